@@ -76,6 +76,13 @@ type (
 		Debug  bool   `envconfig:"DRONE_RPC_DEBUG"`
 		Host   string `envconfig:"DRONE_RPC_HOST"`
 		Proto  string `envconfig:"DRONE_RPC_PROTO"`
+
+		// TLS provides the agent certificate and key used to
+		// authenticate with the server, and the certificate
+		// authority used to verify the server certificate.
+		TLSCA   string `envconfig:"DRONE_RPC_TLS_CA"`
+		TLSCert string `envconfig:"DRONE_RPC_TLS_CERT"`
+		TLSKey  string `envconfig:"DRONE_RPC_TLS_KEY"`
 		// Hosts  map[string]string `envconfig:"DRONE_RPC_EXTRA_HOSTS"`
 	}
 
@@ -102,6 +109,23 @@ type (
 			CPUShares    int64  `envconfig:"DRONE_LIMIT_CPU_SHARES"`
 			CPUSet       string `envconfig:"DRONE_LIMIT_CPU_SET"`
 		}
+		Cache struct {
+			Image     string `envconfig:"DRONE_CACHE_IMAGE"`
+			Backend   string `envconfig:"DRONE_CACHE_BACKEND"`
+			Bucket    string `envconfig:"DRONE_CACHE_BUCKET"`
+			Region    string `envconfig:"DRONE_CACHE_REGION"`
+			Endpoint  string `envconfig:"DRONE_CACHE_ENDPOINT"`
+			AccessKey string `envconfig:"DRONE_CACHE_ACCESS_KEY"`
+			SecretKey string `envconfig:"DRONE_CACHE_SECRET_KEY"`
+			Path      string `envconfig:"DRONE_CACHE_PATH" default:"/var/lib/drone/cache"`
+		}
+		Proxy struct {
+			HTTP    string `envconfig:"DRONE_RUNNER_HTTP_PROXY"`
+			HTTPS   string `envconfig:"DRONE_RUNNER_HTTPS_PROXY"`
+			SOCKS   string `envconfig:"DRONE_RUNNER_SOCKS_PROXY"`
+			NoProxy string `envconfig:"DRONE_RUNNER_NO_PROXY"`
+			CACert  string `envconfig:"DRONE_RUNNER_CA_CERT"`
+		}
 	}
 
 	// Server provides the server configuration.
@@ -6,13 +6,18 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
+	"time"
 
 	"github.com/drone/drone-runtime/engine/docker"
 	"github.com/drone/drone/cmd/drone-agent/config"
+	"github.com/drone/drone/operator/manager"
 	"github.com/drone/drone/operator/manager/rpc"
 	"github.com/drone/drone/operator/runner"
+	"github.com/drone/drone/operator/runner/digest"
 	"github.com/drone/drone/plugin/registry"
 	"github.com/drone/drone/plugin/secret"
+	"github.com/drone/drone/version"
 	"github.com/drone/signal"
 
 	"github.com/sirupsen/logrus"
@@ -20,6 +25,10 @@ import (
 	_ "github.com/joho/godotenv/autoload"
 )
 
+// heartbeatInterval is the interval at which the agent
+// repeats the handshake with the server.
+const heartbeatInterval = time.Hour
+
 func main() {
 	config, err := config.Environ()
 	if err != nil {
@@ -53,15 +62,79 @@ func main() {
 		),
 	)
 
-	manager := rpc.NewClient(
+	client := rpc.NewClient(
 		config.RPC.Proto+"://"+config.RPC.Host,
 		config.RPC.Secret,
 	)
 	if config.RPC.Debug {
-		manager.SetDebug(true)
+		client.SetDebug(true)
 	}
 	if config.Logging.Trace {
-		manager.SetDebug(true)
+		client.SetDebug(true)
+	}
+
+	var tlsconfig *tls.Config
+	if config.RPC.TLSCert != "" {
+		tlsconfig, err = rpc.ClientTLSConfig(
+			config.RPC.TLSCA,
+			config.RPC.TLSCert,
+			config.RPC.TLSKey,
+		)
+		if err != nil {
+			logrus.WithError(err).
+				Fatalln("cannot load the agent certificate")
+		}
+		client.SetTLS(tlsconfig)
+	}
+
+	// negotiate the protocol version and capabilities with
+	// the server. Servers that do not implement the handshake
+	// are assumed to implement protocol version 1.
+	handshake := &rpc.Handshake{
+		Version: version.Version.String(),
+		Machine: config.Runner.Machine,
+		OS:      config.Runner.OS,
+		Arch:    config.Runner.Arch,
+		Variant: config.Runner.Variant,
+		Kernel:  config.Runner.Kernel,
+		Labels:  config.Runner.Labels,
+		Capabilities: []string{
+			rpc.CapabilityLabels,
+			rpc.CapabilityStream,
+			rpc.CapabilityRegistries,
+		},
+	}
+	var manager manager.BuildManager = client
+	res, err := client.Handshake(ctx, handshake)
+	if err != nil {
+		logrus.WithError(err).
+			Warnln("cannot negotiate protocol version")
+	} else {
+		logrus.WithField("version", res.Version).
+			WithField("protocol", res.Protocol).
+			WithField("capabilities", res.Capabilities).
+			Infoln("negotiated protocol version")
+
+		// the handshake is repeated periodically to keep the
+		// agent in the server registry of connected agents.
+		go heartbeat(ctx, client, *handshake)
+	}
+	if err == nil && client.Capable(rpc.CapabilityStream) && res.Stream != "" {
+		stream := rpc.NewStreamClient(
+			config.RPC.Proto+"://"+config.RPC.Host,
+			res.Stream,
+			config.RPC.Secret,
+		)
+		stream.SetDebug(config.RPC.Debug || config.Logging.Trace)
+		if tlsconfig != nil {
+			stream.SetTLS(tlsconfig)
+		}
+		if _, err := stream.Handshake(ctx, handshake); err != nil {
+			logrus.WithError(err).
+				Fatalln("cannot negotiate protocol version")
+		}
+		defer stream.Close()
+		manager = stream
 	}
 
 	engine, err := docker.NewEnv()
@@ -70,6 +143,12 @@ func main() {
 			Fatalln("cannot load the docker engine")
 	}
 
+	digester, err := digest.NewEnv()
+	if err != nil {
+		logrus.WithError(err).
+			Warnln("cannot load the docker image digester")
+	}
+
 	r := &runner.Runner{
 		Platform:   config.Runner.Platform,
 		OS:         config.Runner.OS,
@@ -77,6 +156,7 @@ func main() {
 		Kernel:     config.Runner.Kernel,
 		Variant:    config.Runner.Variant,
 		Engine:     engine,
+		Digester:   digester,
 		Manager:    manager,
 		Registry:   auths,
 		Secrets:    secrets,
@@ -95,6 +175,23 @@ func main() {
 			CPUShares:    config.Runner.Limits.CPUShares,
 			CPUSet:       config.Runner.Limits.CPUSet,
 		},
+		Cache: runner.Cache{
+			Image:     config.Runner.Cache.Image,
+			Backend:   config.Runner.Cache.Backend,
+			Bucket:    config.Runner.Cache.Bucket,
+			Region:    config.Runner.Cache.Region,
+			Endpoint:  config.Runner.Cache.Endpoint,
+			AccessKey: config.Runner.Cache.AccessKey,
+			SecretKey: config.Runner.Cache.SecretKey,
+			Path:      config.Runner.Cache.Path,
+		},
+		Proxy: runner.Proxy{
+			HTTP:    config.Runner.Proxy.HTTP,
+			HTTPS:   config.Runner.Proxy.HTTPS,
+			SOCKS:   config.Runner.Proxy.SOCKS,
+			NoProxy: config.Runner.Proxy.NoProxy,
+			CACert:  config.Runner.Proxy.CACert,
+		},
 	}
 	if err := r.Start(ctx, config.Runner.Capacity); err != nil {
 		logrus.WithError(err).
@@ -102,6 +199,22 @@ func main() {
 	}
 }
 
+// helper function repeats the handshake at a regular
+// interval until the context is canceled.
+func heartbeat(ctx context.Context, client *rpc.Client, handshake rpc.Handshake) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(heartbeatInterval):
+			if err := client.Heartbeat(ctx, &handshake); err != nil {
+				logrus.WithError(err).
+					Debugln("cannot send heartbeat")
+			}
+		}
+	}
+}
+
 // helper funciton configures the logging.
 func initLogging(c config.Config) {
 	if c.Logging.Debug {
@@ -101,6 +101,23 @@ type (
 			CPUShares    int64  `envconfig:"DRONE_LIMIT_CPU_SHARES"`
 			CPUSet       string `envconfig:"DRONE_LIMIT_CPU_SET"`
 		}
+		Cache struct {
+			Image     string `envconfig:"DRONE_CACHE_IMAGE"`
+			Backend   string `envconfig:"DRONE_CACHE_BACKEND"`
+			Bucket    string `envconfig:"DRONE_CACHE_BUCKET"`
+			Region    string `envconfig:"DRONE_CACHE_REGION"`
+			Endpoint  string `envconfig:"DRONE_CACHE_ENDPOINT"`
+			AccessKey string `envconfig:"DRONE_CACHE_ACCESS_KEY"`
+			SecretKey string `envconfig:"DRONE_CACHE_SECRET_KEY"`
+			Path      string `envconfig:"DRONE_CACHE_PATH" default:"/var/lib/drone/cache"`
+		}
+		Proxy struct {
+			HTTP    string `envconfig:"DRONE_RUNNER_HTTP_PROXY"`
+			HTTPS   string `envconfig:"DRONE_RUNNER_HTTPS_PROXY"`
+			SOCKS   string `envconfig:"DRONE_RUNNER_SOCKS_PROXY"`
+			NoProxy string `envconfig:"DRONE_RUNNER_NO_PROXY"`
+			CACert  string `envconfig:"DRONE_RUNNER_CA_CERT"`
+		}
 	}
 
 	// Server provides the server configuration.
@@ -15,6 +15,7 @@ import (
 	"github.com/drone/drone/cmd/drone-controller/config"
 	"github.com/drone/drone/operator/manager/rpc"
 	"github.com/drone/drone/operator/runner"
+	"github.com/drone/drone/operator/runner/digest"
 	"github.com/drone/drone/plugin/registry"
 	"github.com/drone/drone/plugin/secret"
 	"github.com/drone/signal"
@@ -69,6 +70,7 @@ func main() {
 	}
 
 	var engine engine.Engine
+	var digester runner.Digester
 
 	if isKubernetes() {
 		engine, err = kube.NewFile("", "", config.Runner.Machine)
@@ -82,6 +84,11 @@ func main() {
 			logrus.WithError(err).
 				Fatalln("cannot load the docker engine")
 		}
+		digester, err = digest.NewEnv()
+		if err != nil {
+			logrus.WithError(err).
+				Warnln("cannot load the docker image digester")
+		}
 	}
 
 	r := &runner.Runner{
@@ -91,6 +98,7 @@ func main() {
 		Kernel:     config.Runner.Kernel,
 		Variant:    config.Runner.Variant,
 		Engine:     engine,
+		Digester:   digester,
 		Manager:    manager,
 		Registry:   auths,
 		Secrets:    secrets,
@@ -109,6 +117,23 @@ func main() {
 			CPUShares:    config.Runner.Limits.CPUShares,
 			CPUSet:       config.Runner.Limits.CPUSet,
 		},
+		Cache: runner.Cache{
+			Image:     config.Runner.Cache.Image,
+			Backend:   config.Runner.Cache.Backend,
+			Bucket:    config.Runner.Cache.Bucket,
+			Region:    config.Runner.Cache.Region,
+			Endpoint:  config.Runner.Cache.Endpoint,
+			AccessKey: config.Runner.Cache.AccessKey,
+			SecretKey: config.Runner.Cache.SecretKey,
+			Path:      config.Runner.Cache.Path,
+		},
+		Proxy: runner.Proxy{
+			HTTP:    config.Runner.Proxy.HTTP,
+			HTTPS:   config.Runner.Proxy.HTTPS,
+			SOCKS:   config.Runner.Proxy.SOCKS,
+			NoProxy: config.Runner.Proxy.NoProxy,
+			CACert:  config.Runner.Proxy.CACert,
+		},
 	}
 
 	id, err := strconv.ParseInt(os.Getenv("DRONE_STAGE_ID"), 10, 64)
@@ -46,16 +46,27 @@ type (
 	Config struct {
 		License string `envconfig:"DRONE_LICENSE"`
 
-		Authn    Authentication
-		Agent    Agent
-		Cron     Cron
-		Cloning  Cloning
-		Database Database
-		Docker   Docker
-		HTTP     HTTP
-		Logging  Logging
+		Authn     Authentication
+		Agent     Agent
+		Badges    Badges
+		Cron      Cron
+		Cloning   Cloning
+		Comments  Comments
+		Database  Database
+		Docker    Docker
+		Election  Election
+		HTTP      HTTP
+		IDToken   IDToken
+		Logging   Logging
+		LogStream LogStream
+		OIDC      OIDC
 		// Prometheus Prometheus
+		Poller       Poller
 		Proxy        Proxy
+		Provenance   Provenance
+		Pubsub       Pubsub
+		Queue        Queue
+		RateLimit    RateLimit
 		Registration Registration
 		Registries   Registries
 		Repository   Repository
@@ -64,11 +75,16 @@ type (
 		Kube         Kubernetes
 		RPC          RPC
 		S3           S3
+		SCM          SCM
 		Secrets      Secrets
+		Sops         Sops
 		Server       Server
 		Session      Session
+		SMTP         SMTP
 		Status       Status
+		Syncer       Syncer
 		Users        Users
+		Watchdog     Watchdog
 		Webhook      Webhook
 		Yaml         Yaml
 
@@ -81,6 +97,11 @@ type (
 		Stash     Stash
 	}
 
+	// Badges provides the badge configuration.
+	Badges struct {
+		Protected bool `envconfig:"DRONE_BADGES_PROTECTED"`
+	}
+
 	// Cloning provides the cloning configuration.
 	Cloning struct {
 		AlwaysAuth bool   `envconfig:"DRONE_GIT_ALWAYS_AUTH"`
@@ -90,6 +111,12 @@ type (
 		Pull       string `envconfig:"DRONE_GIT_IMAGE_PULL" default:"IfNotExists"`
 	}
 
+	// Comments provides the pull request comment
+	// configuration.
+	Comments struct {
+		Commands []string `envconfig:"DRONE_COMMENT_COMMANDS"`
+	}
+
 	// Cron provides the cron configuration.
 	Cron struct {
 		Disabled bool          `envconfig:"DRONE_CRON_DISABLED"`
@@ -100,7 +127,23 @@ type (
 	Database struct {
 		Driver     string `envconfig:"DRONE_DATABASE_DRIVER"     default:"sqlite3"`
 		Datasource string `envconfig:"DRONE_DATABASE_DATASOURCE" default:"core.sqlite"`
+		Replica    string `envconfig:"DRONE_DATABASE_REPLICA_DATASOURCE"`
 		Secret     string `envconfig:"DRONE_DATABASE_SECRET"`
+
+		// SecretPrevious provides the previous encryption keys,
+		// used to decrypt secrets until the key rotation is
+		// complete.
+		SecretPrevious []string `envconfig:"DRONE_DATABASE_SECRET_PREVIOUS"`
+
+		// SecretPlaintext accepts values stored in plain text
+		// before encryption was enabled, so they can be encrypted
+		// by the key rotation. It should be disabled once the
+		// rotation is complete.
+		SecretPlaintext bool `envconfig:"DRONE_DATABASE_SECRET_PLAINTEXT"`
+
+		MaxOpenConns    int           `envconfig:"DRONE_DATABASE_MAX_CONNECTIONS"`
+		MaxIdleConns    int           `envconfig:"DRONE_DATABASE_MAX_IDLE_CONNECTIONS"`
+		ConnMaxLifetime time.Duration `envconfig:"DRONE_DATABASE_MAX_LIFETIME"`
 	}
 
 	// Docker provides docker configuration
@@ -108,6 +151,12 @@ type (
 		Config string `envconfig:"DRONE_DOCKER_CONFIG"`
 	}
 
+	// Election provides the leader election configuration.
+	Election struct {
+		Driver     string `envconfig:"DRONE_ELECTION_DRIVER"`
+		Datasource string `envconfig:"DRONE_ELECTION_DATASOURCE"`
+	}
+
 	// Kubernetes provides kubernetes configuration
 	Kubernetes struct {
 		Enabled            bool   `envconfig:"DRONE_KUBERNETES_ENABLED"`
@@ -148,9 +197,18 @@ type (
 		Text   bool `envconfig:"DRONE_LOGS_TEXT"`
 	}
 
+	// LogStream provides the live log stream configuration.
+	LogStream struct {
+		Driver     string `envconfig:"DRONE_LOGSTREAM_DRIVER"`
+		Datasource string `envconfig:"DRONE_LOGSTREAM_DATASOURCE"`
+	}
+
 	// Repository provides the repository configuration.
 	Repository struct {
-		Filter []string `envconfig:"DRONE_REPOSITORY_FILTER"`
+		Filter      []string      `envconfig:"DRONE_REPOSITORY_FILTER"`
+		RefsTTL     time.Duration `envconfig:"DRONE_REPOSITORY_REFS_TTL" default:"5m"`
+		HookHistory int           `envconfig:"DRONE_REPOSITORY_HOOK_HISTORY" default:"25"`
+		SignerGrace time.Duration `envconfig:"DRONE_REPOSITORY_SIGNER_GRACE" default:"1h"`
 	}
 
 	// Registries provides the registry configuration.
@@ -167,6 +225,17 @@ type (
 		SkipVerify bool   `envconfig:"DRONE_SECRET_SKIP_VERIFY"`
 	}
 
+	// Sops provides the configuration for secrets files
+	// committed to the repository, encrypted with sops for
+	// the server age identity. Since the identity is shared
+	// by all repositories, a dedicated identity should be
+	// used for each server installation.
+	Sops struct {
+		AgeKey     string `envconfig:"DRONE_SOPS_AGE_KEY"`
+		AgeKeyFile string `envconfig:"DRONE_SOPS_AGE_KEY_FILE"`
+		Path       string `envconfig:"DRONE_SOPS_PATH" default:".drone.secrets.yml"`
+	}
+
 	// RPC provides the rpc configuration.
 	RPC struct {
 		Server string `envconfig:"DRONE_RPC_SERVER"`
@@ -174,11 +243,28 @@ type (
 		Debug  bool   `envconfig:"DRONE_RPC_DEBUG"`
 		Host   string `envconfig:"DRONE_RPC_HOST"`
 		Proto  string `envconfig:"DRONE_RPC_PROTO"`
+		GRPC   string `envconfig:"DRONE_RPC_GRPC_ADDR"`
+		Stream string `envconfig:"DRONE_RPC_GRPC_HOST"`
+
+		// MinProtocol is the minimum rpc protocol version
+		// implemented by remote agents. Agents implementing an
+		// older protocol version are refused.
+		MinProtocol int `envconfig:"DRONE_RPC_MIN_PROTOCOL" default:"1"`
+
+		// TLSCA is the certificate authority used to verify
+		// agent certificates. If TLSRequired is true, agents
+		// must present a certificate in addition to the secret.
+		TLSCA       string `envconfig:"DRONE_RPC_TLS_CA"`
+		TLSRequired bool   `envconfig:"DRONE_RPC_TLS_REQUIRED"`
 		// Hosts  map[string]string `envconfig:"DRONE_RPC_EXTRA_HOSTS"`
 	}
 
 	Agent struct {
 		Enabled bool `envconfig:"DRONE_AGENTS_ENABLED"`
+
+		// PlatformCheck validates pipeline platforms against the
+		// connected agents. The value can be warn, block or none.
+		PlatformCheck string `envconfig:"DRONE_AGENTS_PLATFORM_CHECK" default:"warn"`
 	}
 
 	// Runner provides the runner configuration.
@@ -193,6 +279,7 @@ type (
 		Machine    string            `envconfig:"DRONE_RUNNER_NAME"`
 		Capacity   int               `envconfig:"DRONE_RUNNER_CAPACITY" default:"2"`
 		Labels     map[string]string `envconfig:"DRONE_RUNNER_LABELS"`
+		Pools      Pools             `envconfig:"DRONE_RUNNER_POOLS"`
 		Volumes    []string          `envconfig:"DRONE_RUNNER_VOLUMES"`
 		Networks   []string          `envconfig:"DRONE_RUNNER_NETWORKS"`
 		Devices    []string          `envconfig:"DRONE_RUNNER_DEVICES"`
@@ -206,6 +293,23 @@ type (
 			CPUShares    int64  `envconfig:"DRONE_LIMIT_CPU_SHARES"`
 			CPUSet       string `envconfig:"DRONE_LIMIT_CPU_SET"`
 		}
+		Cache struct {
+			Image     string `envconfig:"DRONE_CACHE_IMAGE"`
+			Backend   string `envconfig:"DRONE_CACHE_BACKEND"`
+			Bucket    string `envconfig:"DRONE_CACHE_BUCKET"`
+			Region    string `envconfig:"DRONE_CACHE_REGION"`
+			Endpoint  string `envconfig:"DRONE_CACHE_ENDPOINT"`
+			AccessKey string `envconfig:"DRONE_CACHE_ACCESS_KEY"`
+			SecretKey string `envconfig:"DRONE_CACHE_SECRET_KEY"`
+			Path      string `envconfig:"DRONE_CACHE_PATH" default:"/var/lib/drone/cache"`
+		}
+		Proxy struct {
+			HTTP    string `envconfig:"DRONE_RUNNER_HTTP_PROXY"`
+			HTTPS   string `envconfig:"DRONE_RUNNER_HTTPS_PROXY"`
+			SOCKS   string `envconfig:"DRONE_RUNNER_SOCKS_PROXY"`
+			NoProxy string `envconfig:"DRONE_RUNNER_NO_PROXY"`
+			CACert  string `envconfig:"DRONE_RUNNER_CA_CERT"`
+		}
 	}
 
 	// Server provides the server configuration.
@@ -219,6 +323,13 @@ type (
 		Key   string `envconfig:"DRONE_TLS_KEY"`
 	}
 
+	// Poller provides the repository polling configuration,
+	// used for repositories that cannot deliver webhooks.
+	Poller struct {
+		Disabled bool          `envconfig:"DRONE_POLLER_DISABLED"`
+		Interval time.Duration `envconfig:"DRONE_POLLER_INTERVAL" default:"1m"`
+	}
+
 	// Proxy provides proxy server configuration.
 	Proxy struct {
 		Addr  string `envconfig:"-"`
@@ -226,6 +337,32 @@ type (
 		Proto string `envconfig:"DRONE_SERVER_PROXY_PROTO"`
 	}
 
+	// Provenance provides the build provenance configuration.
+	// The identity token signing key is used by default.
+	Provenance struct {
+		KeyFile  string `envconfig:"DRONE_PROVENANCE_KEY_FILE"`
+		Endpoint string `envconfig:"DRONE_PROVENANCE_ENDPOINT"`
+		Token    string `envconfig:"DRONE_PROVENANCE_TOKEN"`
+	}
+
+	// Pubsub provides the publish subscribe configuration.
+	Pubsub struct {
+		Driver     string `envconfig:"DRONE_PUBSUB_DRIVER"`
+		Datasource string `envconfig:"DRONE_PUBSUB_DATASOURCE"`
+		Channel    string `envconfig:"DRONE_PUBSUB_CHANNEL" default:"drone"`
+	}
+
+	// Queue provides the queue configuration.
+	Queue struct {
+		Interval time.Duration `envconfig:"DRONE_QUEUE_INTERVAL" default:"1m"`
+	}
+
+	// RateLimit provides the api rate limit configuration.
+	RateLimit struct {
+		Limit int `envconfig:"DRONE_RATE_LIMIT"`
+		Burst int `envconfig:"DRONE_RATE_LIMIT_BURST"`
+	}
+
 	// Registration configuration.
 	Registration struct {
 		Closed bool `envconfig:"DRONE_REGISTRATION_CLOSED"`
@@ -238,9 +375,29 @@ type (
 		SkipVerify bool   `envconfig:"DRONE_AUTHENTICATION_SKIP_VERIFY"`
 	}
 
+	// IDToken provides the build identity token configuration.
+	IDToken struct {
+		KeyFile         string        `envconfig:"DRONE_ID_TOKEN_KEY_FILE"`
+		KeyFilePrevious []string      `envconfig:"DRONE_ID_TOKEN_KEY_FILE_PREVIOUS"`
+		Audience        string        `envconfig:"DRONE_ID_TOKEN_AUDIENCE"`
+		TTL             time.Duration `envconfig:"DRONE_ID_TOKEN_TTL" default:"1h"`
+	}
+
+	// OIDC provides the OpenID Connect single sign-on
+	// configuration.
+	OIDC struct {
+		Issuer       string   `envconfig:"DRONE_OIDC_ISSUER"`
+		ClientID     string   `envconfig:"DRONE_OIDC_CLIENT_ID"`
+		ClientSecret string   `envconfig:"DRONE_OIDC_CLIENT_SECRET"`
+		Scope        []string `envconfig:"DRONE_OIDC_SCOPE" default:"openid,profile,email"`
+		Claim        string   `envconfig:"DRONE_OIDC_CLAIM" default:"preferred_username"`
+		SkipVerify   bool     `envconfig:"DRONE_OIDC_SKIP_VERIFY"`
+	}
+
 	// Session provides the session configuration.
 	Session struct {
 		Timeout time.Duration `envconfig:"DRONE_COOKIE_TIMEOUT" default:"720h"`
+		Idle    time.Duration `envconfig:"DRONE_COOKIE_IDLE_TIMEOUT"`
 		Secret  string        `envconfig:"DRONE_COOKIE_SECRET"`
 	}
 
@@ -248,20 +405,43 @@ type (
 	Status struct {
 		Disabled bool   `envconfig:"DRONE_STATUS_DISABLED"`
 		Name     string `envconfig:"DRONE_STATUS_NAME"`
+		Checks   bool   `envconfig:"DRONE_STATUS_CHECKS"`
+		Stages   bool   `envconfig:"DRONE_STATUS_STAGES"`
+	}
+
+	// Syncer provides the repository synchronization
+	// configuration.
+	Syncer struct {
+		Disabled  bool          `envconfig:"DRONE_SYNC_DISABLED"`
+		Interval  time.Duration `envconfig:"DRONE_SYNC_INTERVAL" default:"1h"`
+		Period    time.Duration `envconfig:"DRONE_SYNC_PERIOD" default:"24h"`
+		Limit     int           `envconfig:"DRONE_SYNC_LIMIT" default:"25"`
+		CacheSize int64         `envconfig:"DRONE_SYNC_CACHE_SIZE" default:"33554432"`
 	}
 
 	// Users provides the user configuration.
 	Users struct {
-		Create UserCreate    `envconfig:"DRONE_USER_CREATE"`
-		Filter []string      `envconfig:"DRONE_USER_FILTER"`
-		MinAge time.Duration `envconfig:"DRONE_MIN_AGE"`
+		Create        UserCreate    `envconfig:"DRONE_USER_CREATE"`
+		Filter        []string      `envconfig:"DRONE_USER_FILTER"`
+		MinAge        time.Duration `envconfig:"DRONE_MIN_AGE"`
+		MembershipTTL time.Duration `envconfig:"DRONE_USER_MEMBERSHIP_TTL" default:"1h"`
+	}
+
+	// Watchdog provides the stage timeout watchdog
+	// configuration.
+	Watchdog struct {
+		Disabled bool          `envconfig:"DRONE_WATCHDOG_DISABLED"`
+		Interval time.Duration `envconfig:"DRONE_WATCHDOG_INTERVAL" default:"5m"`
 	}
 
 	// Webhook provides the webhook configuration.
 	Webhook struct {
-		Endpoint   []string `envconfig:"DRONE_WEBHOOK_ENDPOINT"`
-		Secret     string   `envconfig:"DRONE_WEBHOOK_SECRET"`
-		SkipVerify bool     `envconfig:"DRONE_WEBHOOK_SKIP_VERIFY"`
+		Endpoint      []string      `envconfig:"DRONE_WEBHOOK_ENDPOINT"`
+		Secret        string        `envconfig:"DRONE_WEBHOOK_SECRET"`
+		SkipVerify    bool          `envconfig:"DRONE_WEBHOOK_SKIP_VERIFY"`
+		Events        []string      `envconfig:"DRONE_WEBHOOK_EVENTS"`
+		Repos         []string      `envconfig:"DRONE_WEBHOOK_REPOSITORY_FILTER"`
+		RetryInterval time.Duration `envconfig:"DRONE_WEBHOOK_RETRY_INTERVAL" default:"1m"`
 	}
 
 	// Yaml provides the yaml webhook configuration.
@@ -300,6 +480,9 @@ type (
 		Scope        []string `envconfig:"DRONE_GITHUB_SCOPE" default:"repo,repo:status,user:email,read:org"`
 		RateLimit    int      `envconfig:"DRONE_GITHUB_USER_RATELIMIT"`
 		Debug        bool     `envconfig:"DRONE_GITHUB_DEBUG"`
+		AppID        int64    `envconfig:"DRONE_GITHUB_APP_ID"`
+		AppKeyFile   string   `envconfig:"DRONE_GITHUB_APP_PRIVATE_KEY_FILE"`
+		AppSecret    string   `envconfig:"DRONE_GITHUB_APP_WEBHOOK_SECRET"`
 	}
 
 	// GitLab provides the gitlab client configuration.
@@ -309,6 +492,7 @@ type (
 		ClientSecret string `envconfig:"DRONE_GITLAB_CLIENT_SECRET"`
 		SkipVerify   bool   `envconfig:"DRONE_GITLAB_SKIP_VERIFY"`
 		Debug        bool   `envconfig:"DRONE_GITLAB_DEBUG"`
+		DeployTokens bool   `envconfig:"DRONE_GITLAB_DEPLOY_TOKENS"`
 	}
 
 	// Gogs provides the gogs client configuration.
@@ -328,6 +512,22 @@ type (
 		Debug          bool   `envconfig:"DRONE_STASH_DEBUG"`
 	}
 
+	// SCM provides the configuration for additional source
+	// code management providers.
+	SCM struct {
+		Providers Providers `envconfig:"DRONE_SCM_PROVIDERS"`
+	}
+
+	// SMTP provides the smtp configuration used to send
+	// email notifications.
+	SMTP struct {
+		Host     string `envconfig:"DRONE_SMTP_HOST"`
+		Port     int    `envconfig:"DRONE_SMTP_PORT" default:"587"`
+		Username string `envconfig:"DRONE_SMTP_USERNAME"`
+		Password string `envconfig:"DRONE_SMTP_PASSWORD"`
+		From     string `envconfig:"DRONE_SMTP_FROM"`
+	}
+
 	// S3 provides the storage configuration.
 	S3 struct {
 		Bucket   string `envconfig:"DRONE_S3_BUCKET"`
@@ -399,6 +599,9 @@ func defaultCallback(c *Config) {
 	if c.RPC.Proto == "" {
 		c.RPC.Proto = c.Server.Proto
 	}
+	if c.RPC.Stream == "" && strings.HasPrefix(c.RPC.GRPC, ":") {
+		c.RPC.Stream = strings.Split(c.RPC.Host, ":")[0] + c.RPC.GRPC
+	}
 }
 
 func defaultRunner(c *Config) {
@@ -452,6 +655,86 @@ func (b *Bytes) String() string {
 	return fmt.Sprint(*b)
 }
 
+// Pools stores the named runner pools, where each pool maps
+// a pool name to a set of runner labels.
+type Pools map[string]map[string]string
+
+// Decode implements a decoder that extracts the runner pools
+// from the environment variable string. Pools are separated
+// by semicolons, for example gpu=gpu:nvidia,zone:us-east;arm=arch:arm64
+func (p *Pools) Decode(value string) error {
+	pools := Pools{}
+	for _, pool := range strings.Split(value, ";") {
+		parts := strings.SplitN(pool, "=", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		name := strings.TrimSpace(parts[0])
+		labels := map[string]string{}
+		for _, label := range strings.Split(parts[1], ",") {
+			kv := strings.SplitN(label, ":", 2)
+			if len(kv) != 2 {
+				return fmt.Errorf("invalid runner pool label %q", label)
+			}
+			labels[strings.TrimSpace(kv[0])] = strings.TrimSpace(kv[1])
+		}
+		pools[name] = labels
+	}
+	*p = pools
+	return nil
+}
+
+// Providers stores the additional source code management
+// providers, where each provider maps a provider name to the
+// provider configuration.
+type Providers map[string]Provider
+
+// Provider provides the configuration for an additional source
+// code management provider. The provider authenticates using
+// a service account token.
+type Provider struct {
+	Driver     string
+	Server     string
+	Token      string
+	SkipVerify bool
+}
+
+// Decode implements a decoder that extracts the additional
+// providers from the environment variable string. Providers
+// are separated by semicolons, for example
+// internal=driver:gitlab,server:https://git.company.com,token:abc123
+func (p *Providers) Decode(value string) error {
+	providers := Providers{}
+	for _, provider := range strings.Split(value, ";") {
+		parts := strings.SplitN(provider, "=", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		name := strings.TrimSpace(parts[0])
+		conf := Provider{}
+		for _, param := range strings.Split(parts[1], ",") {
+			kv := strings.SplitN(param, ":", 2)
+			if len(kv) != 2 {
+				return fmt.Errorf("invalid provider parameter %q", param)
+			}
+			val := strings.TrimSpace(kv[1])
+			switch strings.TrimSpace(kv[0]) {
+			case "driver":
+				conf.Driver = val
+			case "server":
+				conf.Server = val
+			case "token":
+				conf.Token = val
+			case "skip_verify":
+				conf.SkipVerify = val == "true"
+			}
+		}
+		providers[name] = conf
+	}
+	*p = providers
+	return nil
+}
+
 // UserCreate stores account information used to bootstrap
 // the admin user account when the system initializes.
 type UserCreate struct {
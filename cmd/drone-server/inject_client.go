@@ -24,6 +24,7 @@ import (
 	"net/http/httputil"
 
 	"github.com/drone/drone/cmd/drone-server/config"
+	"github.com/drone/drone/service/etag"
 	"github.com/drone/go-scm/scm"
 	"github.com/drone/go-scm/scm/driver/bitbucket"
 	"github.com/drone/go-scm/scm/driver/gitea"
@@ -100,7 +101,7 @@ func provideGithubClient(config config.Config) *scm.Client {
 	client.Client = &http.Client{
 		Transport: &oauth2.Transport{
 			Source: oauth2.ContextTokenSource(),
-			Base:   defaultTransport(config.Github.SkipVerify),
+			Base:   cacheTransport(config, config.Github.SkipVerify),
 		},
 	}
 	return client
@@ -121,7 +122,7 @@ func provideGiteaClient(config config.Config) *scm.Client {
 		Transport: &oauth2.Transport{
 			Scheme: oauth2.SchemeToken,
 			Source: oauth2.ContextTokenSource(),
-			Base:   defaultTransport(config.Gitea.SkipVerify),
+			Base:   cacheTransport(config, config.Gitea.SkipVerify),
 		},
 	}
 	return client
@@ -146,7 +147,7 @@ func provideGitlabClient(config config.Config) *scm.Client {
 	client.Client = &http.Client{
 		Transport: &oauth2.Transport{
 			Source: oauth2.ContextTokenSource(),
-			Base:   defaultTransport(config.GitLab.SkipVerify),
+			Base:   cacheTransport(config, config.GitLab.SkipVerify),
 		},
 	}
 	return client
@@ -171,7 +172,7 @@ func provideGogsClient(config config.Config) *scm.Client {
 		Transport: &oauth2.Transport{
 			Scheme: oauth2.SchemeToken,
 			Source: oauth2.ContextTokenSource(),
-			Base:   defaultTransport(config.Gogs.SkipVerify),
+			Base:   cacheTransport(config, config.Gogs.SkipVerify),
 		},
 	}
 	return client
@@ -227,6 +228,18 @@ func defaultTransport(skipverify bool) http.RoundTripper {
 	}
 }
 
+// cacheTransport provides a default http.Transport wrapped
+// with a cache of source control management API responses,
+// revalidated using conditional requests. This reduces the
+// rate limit consumed when synchronizing repositories.
+func cacheTransport(config config.Config, skipverify bool) http.RoundTripper {
+	transport := defaultTransport(skipverify)
+	if config.Syncer.CacheSize <= 0 {
+		return transport
+	}
+	return etag.Transport(transport, config.Syncer.CacheSize)
+}
+
 // parsePrivateKeyFile is a helper function that parses an
 // RSA Private Key file encoded in PEM format.
 func parsePrivateKeyFile(path string) (*rsa.PrivateKey, error) {
@@ -19,6 +19,8 @@ import (
 	"github.com/drone/drone/core"
 	"github.com/drone/drone/plugin/admission"
 	"github.com/drone/drone/plugin/config"
+	"github.com/drone/drone/plugin/platform"
+	"github.com/drone/drone/plugin/pool"
 	"github.com/drone/drone/plugin/registry"
 	"github.com/drone/drone/plugin/secret"
 	"github.com/drone/drone/plugin/webhook"
@@ -31,9 +33,12 @@ import (
 var pluginSet = wire.NewSet(
 	provideAdmissionPlugin,
 	provideConfigPlugin,
+	providePlatformPlugin,
+	providePoolPlugin,
 	provideRegistryPlugin,
 	provideSecretPlugin,
 	provideWebhookPlugin,
+	provideWebhookRetrier,
 )
 
 // provideAdmissionPlugin is a Wire provider function that
@@ -61,6 +66,30 @@ func provideConfigPlugin(client *scm.Client, contents core.FileService, conf spe
 	)
 }
 
+// providePlatformPlugin is a Wire provider function that
+// returns a platform plugin based on the environment
+// configuration. Platforms are only validated when builds
+// are executed by remote agents.
+func providePlatformPlugin(agents core.AgentRegistry, config spec.Config) core.PlatformService {
+	if !config.Agent.Enabled {
+		return platform.Noop()
+	}
+	switch config.Agent.PlatformCheck {
+	case "warn":
+		return platform.Agents(agents, false)
+	case "block":
+		return platform.Agents(agents, true)
+	default:
+		return platform.Noop()
+	}
+}
+
+// providePoolPlugin is a Wire provider function that returns
+// a runner pool plugin based on the environment configuration.
+func providePoolPlugin(config spec.Config) core.PoolService {
+	return pool.Static(config.Runner.Pools)
+}
+
 // provideRegistryPlugin is a Wire provider function that
 // returns a registry plugin based on the environment
 // configuration.
@@ -94,9 +123,24 @@ func provideSecretPlugin(config spec.Config) core.SecretService {
 
 // provideWebhookPlugin is a Wire provider function that returns
 // a webhook plugin based on the environment configuration.
-func provideWebhookPlugin(config spec.Config) core.WebhookSender {
-	return webhook.New(
-		config.Webhook.Endpoint,
+func provideWebhookPlugin(config spec.Config, deliveries core.WebhookDeliveryStore, hooks core.RepoWebhookStore) core.WebhookSender {
+	return webhook.Repository(hooks,
+		webhook.New(webhook.Config{
+			Endpoint: config.Webhook.Endpoint,
+			Secret:   config.Webhook.Secret,
+			Events:   config.Webhook.Events,
+			Repos:    config.Webhook.Repos,
+		}, deliveries),
+		deliveries,
+	)
+}
+
+// provideWebhookRetrier is a Wire provider function that returns
+// a webhook retrier based on the environment configuration.
+func provideWebhookRetrier(config spec.Config, deliveries core.WebhookDeliveryStore, hooks core.RepoWebhookStore) *webhook.Retrier {
+	return webhook.NewRetrier(
 		config.Webhook.Secret,
+		deliveries,
+		hooks,
 	)
 }
@@ -0,0 +1,123 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/drone/drone/cmd/drone-server/config"
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/service/commit"
+	"github.com/drone/drone/service/content"
+	"github.com/drone/drone/service/hook"
+	"github.com/drone/drone/service/hook/parser"
+	"github.com/drone/drone/service/netrc"
+	"github.com/drone/drone/service/provider"
+	"github.com/drone/drone/service/pull"
+	"github.com/drone/drone/service/reference"
+	"github.com/drone/drone/service/repo"
+	"github.com/drone/drone/service/status"
+	"github.com/drone/go-scm/scm"
+	"github.com/drone/go-scm/scm/driver/gitea"
+	"github.com/drone/go-scm/scm/driver/github"
+	"github.com/drone/go-scm/scm/driver/gitlab"
+	"github.com/drone/go-scm/scm/driver/gogs"
+	"github.com/drone/go-scm/scm/transport/oauth2"
+
+	"github.com/google/wire"
+	"github.com/sirupsen/logrus"
+)
+
+// wire set for loading the additional scm providers.
+var providerSet = wire.NewSet(
+	provideProviders,
+	wire.Bind(new(core.ProviderService), new(*provider.Router)),
+)
+
+// provideProviders is a Wire provider function that returns a
+// router for the additional source code management providers
+// based on the environment configuration.
+func provideProviders(config config.Config) (*provider.Router, error) {
+	var providers []*provider.Provider
+	for name, conf := range config.SCM.Providers {
+		logrus.WithField("provider", name).
+			WithField("driver", conf.Driver).
+			WithField("server", conf.Server).
+			Debugln("main: creating the additional provider client")
+
+		client, err := provideProviderClient(conf)
+		if err != nil {
+			return nil, err
+		}
+		renewer := provider.Renewer()
+		providers = append(providers, &provider.Provider{
+			Name:         name,
+			Token:        conf.Token,
+			Commits:      commit.New(client, renewer),
+			Contents:     contents.New(client, renewer),
+			Hooks:        hook.NewProvider(client, config.Proxy.Addr, name, renewer),
+			Netrcs:       netrc.New(client, renewer, config.Cloning.AlwaysAuth, "", "", nil),
+			Parser:       parser.New(client),
+			PullRequests: pull.New(client, renewer),
+			References:   reference.New(client, renewer),
+			Repos:        repo.New(client, renewer),
+			Statuses: status.New(client, renewer, nil, status.Config{
+				Base:     config.Server.Addr,
+				Name:     config.Status.Name,
+				Disabled: config.Status.Disabled,
+				Stages:   config.Status.Stages,
+				Provider: name,
+			}),
+		})
+	}
+	return provider.New(providers...), nil
+}
+
+// provideProviderClient returns a source code management
+// client for an additional provider. The client authenticates
+// using the service account token in the request context.
+func provideProviderClient(conf config.Provider) (*scm.Client, error) {
+	var (
+		client *scm.Client
+		scheme string
+		err    error
+	)
+	switch conf.Driver {
+	case "github":
+		client, err = github.New(conf.Server)
+	case "gitlab":
+		client, err = gitlab.New(conf.Server)
+	case "gitea":
+		client, err = gitea.New(conf.Server)
+		scheme = oauth2.SchemeToken
+	case "gogs":
+		client, err = gogs.New(conf.Server)
+		scheme = oauth2.SchemeToken
+	default:
+		return nil, fmt.Errorf("main: unsupported provider driver %q", conf.Driver)
+	}
+	if err != nil {
+		return nil, err
+	}
+	client.Client = &http.Client{
+		Transport: &oauth2.Transport{
+			Scheme: scheme,
+			Source: oauth2.ContextTokenSource(),
+			Base:   defaultTransport(conf.SkipVerify),
+		},
+	}
+	return client, nil
+}
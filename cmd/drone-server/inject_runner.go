@@ -20,6 +20,8 @@ import (
 	"github.com/drone/drone/core"
 	"github.com/drone/drone/operator/manager"
 	"github.com/drone/drone/operator/runner"
+	"github.com/drone/drone/operator/runner/digest"
+	"github.com/drone/drone/operator/watchdog"
 
 	"github.com/google/wire"
 	"github.com/sirupsen/logrus"
@@ -28,6 +30,7 @@ import (
 // wire set for loading the server.
 var runnerSet = wire.NewSet(
 	provideRunner,
+	watchdog.New,
 )
 
 // provideRunner is a Wire provider function that returns a
@@ -49,6 +52,11 @@ func provideRunner(
 			Fatalln("cannot load the docker engine")
 		return nil
 	}
+	digester, err := digest.NewEnv()
+	if err != nil {
+		logrus.WithError(err).
+			Warnln("cannot load the docker image digester")
+	}
 	return &runner.Runner{
 		Platform:   config.Runner.Platform,
 		OS:         config.Runner.OS,
@@ -56,6 +64,7 @@ func provideRunner(
 		Kernel:     config.Runner.Kernel,
 		Variant:    config.Runner.Variant,
 		Engine:     engine,
+		Digester:   digester,
 		Manager:    manager,
 		Secrets:    secrets,
 		Registry:   registry,
@@ -74,5 +83,22 @@ func provideRunner(
 			CPUShares:    config.Runner.Limits.CPUShares,
 			CPUSet:       config.Runner.Limits.CPUSet,
 		},
+		Cache: runner.Cache{
+			Image:     config.Runner.Cache.Image,
+			Backend:   config.Runner.Cache.Backend,
+			Bucket:    config.Runner.Cache.Bucket,
+			Region:    config.Runner.Cache.Region,
+			Endpoint:  config.Runner.Cache.Endpoint,
+			AccessKey: config.Runner.Cache.AccessKey,
+			SecretKey: config.Runner.Cache.SecretKey,
+			Path:      config.Runner.Cache.Path,
+		},
+		Proxy: runner.Proxy{
+			HTTP:    config.Runner.Proxy.HTTP,
+			HTTPS:   config.Runner.Proxy.HTTPS,
+			SOCKS:   config.Runner.Proxy.SOCKS,
+			NoProxy: config.Runner.Proxy.NoProxy,
+			CACert:  config.Runner.Proxy.CACert,
+		},
 	}
 }
@@ -135,5 +135,5 @@ func provideNomadScheduler(config config.Config) core.Scheduler {
 // docker runner, and by remote agents.
 func provideQueueScheduler(store core.StageStore, config config.Config) core.Scheduler {
 	logrus.Info("main: nomad runtime enabled")
-	return queue.New(store)
+	return queue.New(store, config.Queue.Interval)
 }
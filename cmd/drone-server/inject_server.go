@@ -15,10 +15,13 @@
 package main
 
 import (
+	"crypto/tls"
 	"net/http"
 
 	"github.com/drone/drone/cmd/drone-server/config"
+	"github.com/drone/drone/core"
 	"github.com/drone/drone/handler/api"
+	"github.com/drone/drone/handler/api/ratelimit"
 	"github.com/drone/drone/handler/web"
 	"github.com/drone/drone/metric"
 	"github.com/drone/drone/operator/manager"
@@ -34,17 +37,29 @@ import (
 var serverSet = wire.NewSet(
 	manager.New,
 	metric.NewServer,
+	rpc.NewRegistry,
 	api.New,
 	web.New,
 	provideRouter,
 	provideRPC,
+	provideRPCTLS,
+	provideStreamServer,
 	provideServer,
 	provideServerOptions,
 )
 
 // provideRouter is a Wire provider function that returns a
 // router that is serves the provided handlers.
-func provideRouter(api api.Server, web web.Server, rpc http.Handler, metrics *metric.Server) *chi.Mux {
+func provideRouter(api api.Server, web web.Server, rpc http.Handler, metrics *metric.Server, config config.Config) *chi.Mux {
+	api.ProtectBadges = config.Badges.Protected
+	api.SignerGrace = config.Repository.SignerGrace
+	if config.RateLimit.Limit > 0 {
+		api.Limiter = ratelimit.New(
+			config.RateLimit.Limit,
+			config.RateLimit.Burst,
+		)
+	}
+	metric.HookRejectionCount()
 	r := chi.NewRouter()
 	r.Mount("/metrics", metrics)
 	r.Mount("/api", api.Handler())
@@ -55,13 +70,53 @@ func provideRouter(api api.Server, web web.Server, rpc http.Handler, metrics *me
 
 // provideRPC is a Wire provider function that returns an rpc
 // handler that exposes the build manager to a remote agent.
-func provideRPC(m manager.BuildManager, config config.Config) http.Handler {
-	return rpc.NewServer(m, config.RPC.Secret)
+func provideRPC(m manager.BuildManager, agents core.AgentRegistry, config config.Config) http.Handler {
+	v := rpc.NewServer(m, agents, config.RPC.Secret)
+	v.SetMinProtocol(config.RPC.MinProtocol)
+	v.SetRequireCert(config.RPC.TLSRequired)
+	if config.RPC.GRPC != "" {
+		v.SetStream(config.RPC.Stream)
+	}
+	return v
+}
+
+// provideStreamServer is a Wire provider function that returns
+// an rpc stream server that pushes stages to remote agents.
+func provideStreamServer(m manager.BuildManager, tlsconfig *tls.Config, config config.Config) *rpc.StreamServer {
+	v := rpc.NewStreamServer(m, config.RPC.Secret)
+	if tlsconfig != nil {
+		v.SetTLS(tlsconfig, config.RPC.TLSRequired)
+	}
+	return v
+}
+
+// provideRPCTLS is a Wire provider function that returns the
+// tls configuration used to verify agent certificates. If the
+// certificate authority is not configured, a nil value is
+// returned and agent certificates are not verified.
+func provideRPCTLS(config config.Config) (*tls.Config, error) {
+	if config.RPC.TLSCA == "" {
+		return nil, nil
+	}
+	v, err := rpc.ServerTLSConfig(config.RPC.TLSCA)
+	if err != nil {
+		return nil, err
+	}
+	// the server certificate is loaded for the grpc stream
+	// server, which does not share the http server listener.
+	if config.Server.Cert != "" {
+		cert, err := tls.LoadX509KeyPair(config.Server.Cert, config.Server.Key)
+		if err != nil {
+			return nil, err
+		}
+		v.Certificates = []tls.Certificate{cert}
+	}
+	return v, nil
 }
 
 // provideServer is a Wire provider function that returns an
 // http server that is configured from the environment.
-func provideServer(handler *chi.Mux, config config.Config) *server.Server {
+func provideServer(handler *chi.Mux, tlsconfig *tls.Config, config config.Config) *server.Server {
 	return &server.Server{
 		Acme:    config.Server.Acme,
 		Addr:    config.Server.Port,
@@ -69,6 +124,7 @@ func provideServer(handler *chi.Mux, config config.Config) *server.Server {
 		Key:     config.Server.Key,
 		Host:    config.Server.Host,
 		Handler: handler,
+		TLS:     tlsconfig,
 	}
 }
 
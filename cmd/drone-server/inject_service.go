@@ -15,25 +15,40 @@
 package main
 
 import (
+	"crypto/rsa"
+	"io/ioutil"
+
 	"github.com/drone/drone/cmd/drone-server/config"
 	"github.com/drone/drone/core"
 	"github.com/drone/drone/livelog"
 	"github.com/drone/drone/pubsub"
+	"github.com/drone/drone/service/command"
 	"github.com/drone/drone/service/commit"
 	"github.com/drone/drone/service/content"
 	"github.com/drone/drone/service/content/cache"
+	"github.com/drone/drone/service/health"
 	"github.com/drone/drone/service/hook"
 	"github.com/drone/drone/service/hook/parser"
+	"github.com/drone/drone/service/idtoken"
+	"github.com/drone/drone/service/installation"
 	"github.com/drone/drone/service/netrc"
+	"github.com/drone/drone/service/notify"
 	"github.com/drone/drone/service/org"
+	"github.com/drone/drone/service/provenance"
+	"github.com/drone/drone/service/provider"
+	"github.com/drone/drone/service/pull"
+	"github.com/drone/drone/service/reference"
 	"github.com/drone/drone/service/repo"
+	"github.com/drone/drone/service/sops"
 	"github.com/drone/drone/service/status"
+	"github.com/drone/drone/service/summary"
 	"github.com/drone/drone/service/syncer"
 	"github.com/drone/drone/service/token"
 	"github.com/drone/drone/service/user"
 	"github.com/drone/drone/session"
 	"github.com/drone/drone/trigger"
 	"github.com/drone/drone/trigger/cron"
+	"github.com/drone/drone/trigger/poll"
 	"github.com/drone/drone/version"
 	"github.com/drone/go-scm/scm"
 
@@ -42,71 +57,321 @@ import (
 
 // wire set for loading the services.
 var serviceSet = wire.NewSet(
-	commit.New,
 	cron.New,
-	livelog.New,
-	orgs.New,
-	parser.New,
-	pubsub.New,
+	health.New,
+	poll.New,
 	repo.New,
 	token.Renewer,
 	trigger.New,
 	user.New,
 
+	provideCommandService,
+	provideCommitService,
 	provideContentService,
+	provideHookParser,
 	provideHookService,
+	provideIDTokenService,
+	provideInstallationService,
+	provideLogStream,
 	provideNetrcService,
+	provideNotificationService,
+	provideOrgService,
+	provideProvenanceService,
+	providePubsub,
+	providePullRequestService,
+	provideReferenceService,
+	provideSecretFileService,
 	provideSession,
 	provideStatusService,
+	provideSummaryService,
 	provideSyncer,
+	provideSyncScheduler,
 	provideSystem,
 )
 
+// provideCommandService is a Wire provider function that
+// returns a pull request comment command service based on
+// the environment configuration.
+func provideCommandService(
+	client *scm.Client,
+	builds core.BuildStore,
+	perms core.PermStore,
+	users core.UserStore,
+	renewer core.Renewer,
+	triggerer core.Triggerer,
+	config config.Config,
+) core.CommandService {
+	return command.New(client, builds, perms, users, renewer, triggerer, command.Config{
+		Base:     config.Server.Addr,
+		Commands: config.Comments.Commands,
+	})
+}
+
+// provideCommitService is a Wire provider function that
+// returns a commit service, routing requests for repositories
+// hosted by additional providers.
+func provideCommitService(client *scm.Client, renewer core.Renewer, providers *provider.Router) core.CommitService {
+	return providers.Commits(
+		commit.New(client, renewer),
+	)
+}
+
 // provideContentService is a Wire provider function that
-// returns a contents service wrapped with a simple LRU cache.
-func provideContentService(client *scm.Client, renewer core.Renewer) core.FileService {
-	return cache.Contents(
-		contents.New(client, renewer),
+// returns a contents service wrapped with a simple LRU cache,
+// routing requests for repositories hosted by additional
+// providers.
+func provideContentService(client *scm.Client, renewer core.Renewer, providers *provider.Router) core.FileService {
+	return providers.Contents(
+		cache.Contents(
+			contents.New(client, renewer),
+		),
 	)
 }
 
 // provideHookService is a Wire provider function that returns a
 // hook service based on the environment configuration.
-func provideHookService(client *scm.Client, renewer core.Renewer, config config.Config) core.HookService {
-	return hook.New(client, config.Proxy.Addr, renewer)
+func provideHookService(client *scm.Client, renewer core.Renewer, installations core.InstallationService, providers *provider.Router, config config.Config) core.HookService {
+	if installations != nil {
+		return providers.Hooks(hook.NewApp(client, config.Proxy.Addr, renewer))
+	}
+	return providers.Hooks(hook.New(client, config.Proxy.Addr, renewer))
+}
+
+// provideHookParser is a Wire provider function that returns a
+// hook parser based on the environment configuration. If a
+// GitHub App webhook secret is configured, webhook signatures
+// are verified using the app secret.
+func provideHookParser(client *scm.Client, providers *provider.Router, config config.Config) core.HookParser {
+	if config.Github.AppSecret != "" {
+		return providers.Parser(parser.Secret(parser.New(client), config.Github.AppSecret))
+	}
+	return providers.Parser(parser.New(client))
+}
+
+// provideInstallationService is a Wire provider function that
+// returns a GitHub App installation service based on the
+// environment configuration. If a GitHub App is not configured,
+// a nil service is returned.
+func provideInstallationService(client *scm.Client, config config.Config) (core.InstallationService, error) {
+	if config.Github.ClientID == "" || config.Github.AppID == 0 || config.Github.AppKeyFile == "" {
+		return nil, nil
+	}
+	key, err := idtoken.LoadKey(config.Github.AppKeyFile)
+	if err != nil {
+		return nil, err
+	}
+	return installation.New(client.BaseURL.String(), config.Github.AppID, key, nil), nil
+}
+
+// provideLogStream is a Wire provider function that returns a
+// live log streamer based on the environment configuration.
+// If a remote driver is not configured, logs can only be
+// tailed from this server instance.
+func provideLogStream(config config.Config) (core.LogStream, error) {
+	switch config.LogStream.Driver {
+	case "redis":
+		return livelog.NewRedis(config.LogStream.Datasource)
+	default:
+		return livelog.New(), nil
+	}
 }
 
 // provideNetrcService is a Wire provider function that returns
-// a netrc service based on the environment configuration.
-func provideNetrcService(client *scm.Client, renewer core.Renewer, config config.Config) core.NetrcService {
-	return netrc.New(
-		client,
-		renewer,
-		config.Cloning.AlwaysAuth,
-		config.Cloning.Username,
-		config.Cloning.Password,
+// a netrc service based on the environment configuration. If a
+// GitHub App or GitLab deploy tokens are configured, the service
+// mints repository-scoped, short-lived clone credentials.
+func provideNetrcService(client *scm.Client, renewer core.Renewer, installations core.InstallationService, providers *provider.Router, config config.Config) core.NetrcService {
+	var minter netrc.Minter
+	switch {
+	case installations != nil:
+		minter = netrc.GithubApp(installations)
+	case config.GitLab.DeployTokens && config.GitLab.ClientID != "":
+		minter = netrc.GitlabDeployToken(config.GitLab.Server, nil)
+	}
+	return providers.Netrcs(
+		netrc.New(
+			client,
+			renewer,
+			config.Cloning.AlwaysAuth,
+			config.Cloning.Username,
+			config.Cloning.Password,
+			minter,
+		),
 	)
 }
 
+// provideIDTokenService is a Wire provider function that returns
+// a build identity token service based on the environment
+// configuration. If a signing key is not configured, identity
+// tokens are disabled and a nil service is returned.
+func provideIDTokenService(config config.Config) (core.IDTokenService, error) {
+	if config.IDToken.KeyFile == "" {
+		return nil, nil
+	}
+	var keys []*rsa.PrivateKey
+	for _, path := range append([]string{config.IDToken.KeyFile}, config.IDToken.KeyFilePrevious...) {
+		key, err := idtoken.LoadKey(path)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return idtoken.New(idtoken.Config{
+		Issuer:   config.Server.Addr,
+		Audience: config.IDToken.Audience,
+		TTL:      config.IDToken.TTL,
+	}, keys...), nil
+}
+
+// provideProvenanceService is a Wire provider function that
+// returns a build provenance service based on the environment
+// configuration. If a signing key is not configured, provenance
+// attestations are disabled and a nil service is returned.
+func provideProvenanceService(artifacts core.ArtifactStore, config config.Config) (core.ProvenanceService, error) {
+	path := config.Provenance.KeyFile
+	if path == "" {
+		path = config.IDToken.KeyFile
+	}
+	if path == "" {
+		return nil, nil
+	}
+	key, err := idtoken.LoadKey(path)
+	if err != nil {
+		return nil, err
+	}
+	return provenance.New(artifacts, key, provenance.Config{
+		Builder:  config.Server.Addr,
+		Endpoint: config.Provenance.Endpoint,
+		Token:    config.Provenance.Token,
+	}), nil
+}
+
+// providePubsub is a Wire provider function that returns a
+// publish subscriber based on the environment configuration.
+// If a remote driver is not configured, messages are only
+// published to subscribers in this server instance.
+func providePubsub(config config.Config) (core.Pubsub, error) {
+	switch config.Pubsub.Driver {
+	case "redis":
+		return pubsub.NewRedis(config.Pubsub.Datasource, config.Pubsub.Channel)
+	case "nats":
+		return pubsub.NewNats(config.Pubsub.Datasource, config.Pubsub.Channel)
+	default:
+		return pubsub.New(), nil
+	}
+}
+
+// provideSecretFileService is a Wire provider function that
+// returns a service that decrypts sops secrets files committed
+// to the repository. If an age identity is not configured,
+// secrets files are disabled and a nil service is returned.
+func provideSecretFileService(client *scm.Client, renewer core.Renewer, config config.Config) (core.SecretFileService, error) {
+	key := config.Sops.AgeKey
+	if config.Sops.AgeKeyFile != "" {
+		raw, err := ioutil.ReadFile(config.Sops.AgeKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		key = string(raw)
+	}
+	if key == "" {
+		return nil, nil
+	}
+	identities, err := sops.ParseIdentities(key)
+	if err != nil {
+		return nil, err
+	}
+	return sops.New(client, renewer, identities, sops.Config{
+		Path: config.Sops.Path,
+	}), nil
+}
+
 // provideSession is a Wire provider function that returns a
 // user session based on the environment configuration.
-func provideSession(store core.UserStore, config config.Config) core.Session {
-	return session.New(store, session.NewConfig(
+func provideSession(store core.UserStore, tokens core.TokenStore, sessions core.UserSessionStore, config config.Config) core.Session {
+	return session.New(store, tokens, sessions, session.NewConfig(
 		config.Session.Secret,
-		config.Session.Timeout),
+		config.Session.Timeout,
+		config.Session.Idle),
 	)
 }
 
+// provideNotificationService is a Wire provider function that
+// returns a notification service based on the environment
+// configuration.
+func provideNotificationService(
+	builds core.BuildStore,
+	logs core.LogStore,
+	notifications core.NotificationStore,
+	system *core.System,
+	config config.Config,
+) core.NotificationService {
+	return notify.New(builds, logs, notifications, system, notify.Config{
+		SMTP: notify.SMTP{
+			Host:     config.SMTP.Host,
+			Port:     config.SMTP.Port,
+			Username: config.SMTP.Username,
+			Password: config.SMTP.Password,
+			From:     config.SMTP.From,
+		},
+	})
+}
+
 // provideUserService is a Wire provider function that returns a
 // user service based on the environment configuration.
-func provideStatusService(client *scm.Client, renewer core.Renewer, config config.Config) core.StatusService {
-	return status.New(client, renewer, status.Config{
-		Base:     config.Server.Addr,
-		Name:     config.Status.Name,
-		Disabled: config.Status.Disabled,
+func provideStatusService(client *scm.Client, renewer core.Renewer, installations core.InstallationService, providers *provider.Router, config config.Config) core.StatusService {
+	return providers.Statuses(
+		status.New(client, renewer, installations, status.Config{
+			Base:     config.Server.Addr,
+			Name:     config.Status.Name,
+			Disabled: config.Status.Disabled,
+			Checks:   config.Status.Checks && config.Github.ClientID != "",
+			Stages:   config.Status.Stages,
+		}),
+	)
+}
+
+// provideSummaryService is a Wire provider function that
+// returns a pull request build summary service.
+func provideSummaryService(client *scm.Client, renewer core.Renewer, logs core.LogStore, config config.Config) core.SummaryService {
+	return summary.New(client, renewer, logs, summary.Config{
+		Base: config.Server.Addr,
 	})
 }
 
+// provideOrgService is a Wire provider function that returns
+// an organization service, wrapped with a membership cache.
+func provideOrgService(client *scm.Client, renewer core.Renewer, config config.Config) core.OrganizationService {
+	service := orgs.New(client, renewer)
+	if config.Users.MembershipTTL <= 0 {
+		return service
+	}
+	return orgs.NewCache(service, 10000, config.Users.MembershipTTL)
+}
+
+// providePullRequestService is a Wire provider function that
+// returns a pull request service, routing requests for
+// repositories hosted by additional providers.
+func providePullRequestService(client *scm.Client, renewer core.Renewer, providers *provider.Router) core.PullRequestService {
+	return providers.PullRequests(
+		pull.New(client, renewer),
+	)
+}
+
+// provideReferenceService is a Wire provider function that
+// returns a branch and tag service wrapped with a simple LRU
+// cache, routing requests for repositories hosted by additional
+// providers.
+func provideReferenceService(client *scm.Client, renewer core.Renewer, providers *provider.Router, config config.Config) core.ReferenceService {
+	service := providers.References(
+		reference.New(client, renewer),
+	)
+	if config.Repository.RefsTTL <= 0 {
+		return service
+	}
+	return reference.NewCache(service, 1000, config.Repository.RefsTTL)
+}
+
 // provideSyncer is a Wire provider function that returns a
 // repository synchronizer.
 func provideSyncer(repoz core.RepositoryService,
@@ -124,6 +389,17 @@ func provideSyncer(repoz core.RepositoryService,
 	return sync
 }
 
+// provideSyncScheduler is a Wire provider function that returns
+// a background repository sync scheduler.
+func provideSyncScheduler(sync core.Syncer, users core.UserStore, config config.Config) *syncer.Scheduler {
+	return syncer.NewScheduler(
+		sync,
+		users,
+		config.Syncer.Period,
+		config.Syncer.Limit,
+	)
+}
+
 // provideSyncer is a Wire provider function that returns the
 // system details structure.
 func provideSystem(config config.Config) *core.System {
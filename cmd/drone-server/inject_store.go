@@ -17,19 +17,40 @@ package main
 import (
 	"github.com/drone/drone/cmd/drone-server/config"
 	"github.com/drone/drone/core"
+	"github.com/drone/drone/election"
 	"github.com/drone/drone/metric"
+	"github.com/drone/drone/store/annotation"
+	"github.com/drone/drone/store/artifact"
+	"github.com/drone/drone/store/audit"
 	"github.com/drone/drone/store/batch"
 	"github.com/drone/drone/store/build"
+	"github.com/drone/drone/store/coverage"
 	"github.com/drone/drone/store/cron"
+	"github.com/drone/drone/store/delivery"
+	"github.com/drone/drone/store/deployment"
+	"github.com/drone/drone/store/insight"
+	"github.com/drone/drone/store/lease"
 	"github.com/drone/drone/store/logs"
+	"github.com/drone/drone/store/mapping"
+	"github.com/drone/drone/store/notify"
+	"github.com/drone/drone/store/payload"
 	"github.com/drone/drone/store/perm"
+	"github.com/drone/drone/store/policy"
+	"github.com/drone/drone/store/privileged"
+	"github.com/drone/drone/store/registry"
 	"github.com/drone/drone/store/repos"
 	"github.com/drone/drone/store/secret"
+	"github.com/drone/drone/store/secret/access"
+	"github.com/drone/drone/store/sessions"
 	"github.com/drone/drone/store/shared/db"
 	"github.com/drone/drone/store/shared/encrypt"
 	"github.com/drone/drone/store/stage"
 	"github.com/drone/drone/store/step"
+	"github.com/drone/drone/store/tests"
+	"github.com/drone/drone/store/token"
 	"github.com/drone/drone/store/user"
+	"github.com/drone/drone/store/variable"
+	"github.com/drone/drone/store/webhook"
 
 	"github.com/google/wire"
 )
@@ -38,31 +59,74 @@ import (
 var storeSet = wire.NewSet(
 	provideDatabase,
 	provideEncrypter,
+	provideArtifactStore,
 	provideBuildStore,
+	provideElector,
+	provideHookPayloadStore,
 	provideLogStore,
 	provideRepoStore,
 	provideStageStore,
 	provideUserStore,
+	access.New,
+	annotation.New,
+	audit.New,
 	batch.New,
+	coverage.New,
 	cron.New,
+	delivery.New,
+	deployment.New,
+	insight.New,
+	mapping.New,
+	notify.New,
 	perm.New,
+	policy.New,
+	privileged.New,
+	registry.New,
 	secret.New,
+	sessions.New,
 	step.New,
+	tests.New,
+	token.New,
+	variable.New,
+	webhook.New,
 )
 
 // provideDatabase is a Wire provider function that provides a
 // database connection, configured from the environment.
 func provideDatabase(config config.Config) (*db.DB, error) {
-	return db.Connect(
+	conn, err := db.Connect(
 		config.Database.Driver,
 		config.Database.Datasource,
 	)
+	if err != nil {
+		return nil, err
+	}
+	if config.Database.Replica != "" {
+		err = conn.ConnectReplica(
+			config.Database.Driver,
+			config.Database.Replica,
+		)
+		if err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+	conn.SetPool(db.Pool{
+		MaxOpenConns:    config.Database.MaxOpenConns,
+		MaxIdleConns:    config.Database.MaxIdleConns,
+		ConnMaxLifetime: config.Database.ConnMaxLifetime,
+	})
+	return conn, nil
 }
 
 // provideEncrypter is a Wire provider function that provides a
 // database encrypter, configured from the environment.
 func provideEncrypter(config config.Config) (encrypt.Encrypter, error) {
-	return encrypt.New(config.Database.Secret)
+	return encrypt.NewKeyring(
+		config.Database.Secret,
+		config.Database.SecretPlaintext,
+		config.Database.SecretPrevious...,
+	)
 }
 
 // provideBuildStore is a Wire provider function that provides a
@@ -89,6 +153,46 @@ func provideLogStore(db *db.DB, config config.Config) core.LogStore {
 	)
 }
 
+// provideElector is a Wire provider function that provides a
+// leader elector, configured from the environment. If a driver
+// is not configured, the server instance is always elected
+// leader, which is only safe when running a single instance.
+func provideElector(db *db.DB, config config.Config) (core.Elector, error) {
+	switch config.Election.Driver {
+	case "database":
+		return lease.New(db), nil
+	case "redis":
+		return election.NewRedis(config.Election.Datasource)
+	default:
+		return election.New(), nil
+	}
+}
+
+// provideArtifactStore is a Wire provider function that provides
+// an artifact datastore, configured from the environment.
+func provideArtifactStore(db *db.DB, config config.Config) core.ArtifactStore {
+	if config.S3.Bucket == "" {
+		return artifact.New(db)
+	}
+	return artifact.NewS3Env(
+		db,
+		config.S3.Bucket,
+		config.S3.Prefix,
+		config.S3.Endpoint,
+	)
+}
+
+// provideHookPayloadStore is a Wire provider function that
+// provides a hook payload datastore, configured from the
+// environment.
+func provideHookPayloadStore(db *db.DB, config config.Config) core.HookPayloadStore {
+	limit := config.Repository.HookHistory
+	if limit < 1 {
+		limit = 1
+	}
+	return payload.New(db, limit)
+}
+
 // provideStageStore is a Wire provider function that provides a
 // stage datastore, configured from the environment, with metrics
 // enabled.
@@ -96,6 +200,9 @@ func provideStageStore(db *db.DB) core.StageStore {
 	stages := stage.New(db)
 	metric.PendingJobCount(stages)
 	metric.RunningJobCount(stages)
+	metric.StageConflictCount()
+	metric.StageQueueTime()
+	metric.QueueErrorCount()
 	return stages
 }
 
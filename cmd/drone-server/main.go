@@ -22,9 +22,15 @@ import (
 	"github.com/drone/drone/cmd/drone-server/bootstrap"
 	"github.com/drone/drone/cmd/drone-server/config"
 	"github.com/drone/drone/core"
+	"github.com/drone/drone/election"
+	"github.com/drone/drone/operator/manager/rpc"
 	"github.com/drone/drone/operator/runner"
+	"github.com/drone/drone/operator/watchdog"
+	"github.com/drone/drone/plugin/webhook"
 	"github.com/drone/drone/server"
+	"github.com/drone/drone/service/syncer"
 	"github.com/drone/drone/trigger/cron"
+	"github.com/drone/drone/trigger/poll"
 	"github.com/drone/signal"
 
 	"github.com/joho/godotenv"
@@ -92,16 +98,88 @@ func main() {
 		return app.server.ListenAndServe(ctx)
 	})
 
+	// launches the grpc stream server in a goroutine. If the
+	// stream server address is not configured, the goroutine
+	// exits immediately without error.
+	g.Go(func() error {
+		if config.RPC.GRPC == "" {
+			return nil
+		}
+		logrus.WithField("addr", config.RPC.GRPC).
+			Infoln("main: starting the grpc stream server")
+		return app.stream.ListenAndServe(ctx, config.RPC.GRPC)
+	})
+
 	// launches the cron runner in a goroutine. If the cron
 	// runner is disabled, the goroutine exits immediately
-	// without error.
+	// without error. The cron runner only runs on the server
+	// instance elected leader.
 	g.Go(func() (err error) {
 		if config.Cron.Disabled {
 			return nil
 		}
 		logrus.WithField("interval", config.Cron.Interval.String()).
 			Infoln("starting the cron scheduler")
-		return app.cron.Start(ctx, config.Cron.Interval)
+		return election.Run(ctx, app.elector, "cron", func(ctx context.Context) error {
+			return app.cron.Start(ctx, config.Cron.Interval)
+		})
+	})
+
+	// launches the repository poll scheduler in a goroutine.
+	// If the scheduler is disabled, the goroutine exits
+	// immediately without error. The scheduler only runs on
+	// the server instance elected leader.
+	g.Go(func() (err error) {
+		if config.Poller.Disabled {
+			return nil
+		}
+		logrus.WithField("interval", config.Poller.Interval.String()).
+			Infoln("main: starting the repository poll scheduler")
+		return election.Run(ctx, app.elector, "poller", func(ctx context.Context) error {
+			return app.poll.Start(ctx, config.Poller.Interval)
+		})
+	})
+
+	// launches the background repository sync scheduler in a
+	// goroutine. If the scheduler is disabled, the goroutine
+	// exits immediately without error. The scheduler only runs
+	// on the server instance elected leader.
+	g.Go(func() (err error) {
+		if config.Syncer.Disabled {
+			return nil
+		}
+		logrus.WithField("interval", config.Syncer.Interval.String()).
+			Infoln("main: starting the repository sync scheduler")
+		return election.Run(ctx, app.elector, "syncer", func(ctx context.Context) error {
+			return app.sync.Start(ctx, config.Syncer.Interval)
+		})
+	})
+
+	// launches the stage timeout watchdog in a goroutine. If
+	// the watchdog is disabled, the goroutine exits immediately
+	// without error. The watchdog only runs on the server
+	// instance elected leader.
+	g.Go(func() (err error) {
+		if config.Watchdog.Disabled {
+			return nil
+		}
+		logrus.WithField("interval", config.Watchdog.Interval.String()).
+			Infoln("main: starting the stage timeout watchdog")
+		return election.Run(ctx, app.elector, "watchdog", func(ctx context.Context) error {
+			return app.watch.Start(ctx, config.Watchdog.Interval)
+		})
+	})
+
+	// launches the webhook retrier in a goroutine. The retrier
+	// retries deliveries to the global and repository webhook
+	// endpoints, and only runs on the server instance elected
+	// leader.
+	g.Go(func() (err error) {
+		logrus.WithField("interval", config.Webhook.RetryInterval.String()).
+			Infoln("main: starting the webhook retrier")
+		return election.Run(ctx, app.elector, "webhook", func(ctx context.Context) error {
+			return app.retry.Start(ctx, config.Webhook.RetryInterval)
+		})
 	})
 
 	// launches the build runner in a goroutine. If the local
@@ -116,6 +194,16 @@ func main() {
 		return app.runner.Start(ctx, config.Runner.Capacity)
 	})
 
+	// stops the scheduler when the program receives a
+	// termination signal. Pending requests from agents return
+	// without a stage, and stages that were not yet accepted
+	// remain pending until the server is restarted.
+	g.Go(func() error {
+		<-ctx.Done()
+		logrus.Infoln("main: stopping the scheduler")
+		return app.sched.Shutdown(context.Background())
+	})
+
 	if err := g.Wait(); err != nil {
 		logrus.WithError(err).Fatalln("program terminated")
 	}
@@ -143,22 +231,43 @@ func initLogging(c config.Config) {
 
 // application is the main struct for the Drone server.
 type application struct {
-	cron   *cron.Scheduler
-	runner *runner.Runner
-	server *server.Server
-	users  core.UserStore
+	cron    *cron.Scheduler
+	elector core.Elector
+	poll    *poll.Scheduler
+	retry   *webhook.Retrier
+	runner  *runner.Runner
+	sched   core.Scheduler
+	server  *server.Server
+	stream  *rpc.StreamServer
+	sync    *syncer.Scheduler
+	users   core.UserStore
+	watch   *watchdog.Watchdog
 }
 
 // newApplication creates a new application struct.
 func newApplication(
 	cron *cron.Scheduler,
+	elector core.Elector,
+	poll *poll.Scheduler,
+	retry *webhook.Retrier,
 	runner *runner.Runner,
+	sched core.Scheduler,
 	server *server.Server,
-	users core.UserStore) application {
+	stream *rpc.StreamServer,
+	sync *syncer.Scheduler,
+	users core.UserStore,
+	watch *watchdog.Watchdog) application {
 	return application{
-		users:  users,
-		cron:   cron,
-		server: server,
-		runner: runner,
+		users:   users,
+		cron:    cron,
+		elector: elector,
+		poll:    poll,
+		retry:   retry,
+		sched:   sched,
+		server:  server,
+		stream:  stream,
+		sync:    sync,
+		runner:  runner,
+		watch:   watch,
 	}
 }
@@ -27,6 +27,7 @@ func InitializeApplication(config config.Config) (application, error) {
 		licenseSet,
 		loginSet,
 		pluginSet,
+		providerSet,
 		runnerSet,
 		schedulerSet,
 		serverSet,
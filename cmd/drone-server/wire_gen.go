@@ -9,24 +9,40 @@ import (
 	"github.com/drone/drone/cmd/drone-server/config"
 	"github.com/drone/drone/handler/api"
 	"github.com/drone/drone/handler/web"
-	"github.com/drone/drone/livelog"
 	"github.com/drone/drone/metric"
 	"github.com/drone/drone/operator/manager"
-	"github.com/drone/drone/pubsub"
-	"github.com/drone/drone/service/commit"
-	"github.com/drone/drone/service/hook/parser"
+	"github.com/drone/drone/operator/manager/rpc"
+	"github.com/drone/drone/operator/watchdog"
+	"github.com/drone/drone/service/health"
 	"github.com/drone/drone/service/license"
-	"github.com/drone/drone/service/org"
 	"github.com/drone/drone/service/repo"
 	"github.com/drone/drone/service/token"
 	"github.com/drone/drone/service/user"
+	"github.com/drone/drone/store/annotation"
+	"github.com/drone/drone/store/audit"
 	"github.com/drone/drone/store/batch"
+	"github.com/drone/drone/store/coverage"
 	"github.com/drone/drone/store/cron"
+	"github.com/drone/drone/store/delivery"
+	"github.com/drone/drone/store/deployment"
+	"github.com/drone/drone/store/insight"
+	"github.com/drone/drone/store/mapping"
+	"github.com/drone/drone/store/notify"
 	"github.com/drone/drone/store/perm"
+	"github.com/drone/drone/store/policy"
+	"github.com/drone/drone/store/privileged"
+	registry2 "github.com/drone/drone/store/registry"
 	"github.com/drone/drone/store/secret"
+	"github.com/drone/drone/store/secret/access"
+	"github.com/drone/drone/store/sessions"
 	"github.com/drone/drone/store/step"
+	"github.com/drone/drone/store/tests"
+	"github.com/drone/drone/store/variable"
+	token2 "github.com/drone/drone/store/token"
+	"github.com/drone/drone/store/webhook"
 	"github.com/drone/drone/trigger"
 	cron2 "github.com/drone/drone/trigger/cron"
+	"github.com/drone/drone/trigger/poll"
 )
 
 import (
@@ -46,53 +62,121 @@ func InitializeApplication(config2 config.Config) (application, error) {
 	}
 	userStore := provideUserStore(db)
 	renewer := token.Renewer(refresher, userStore)
-	commitService := commit.New(client, renewer)
+	router, err := provideProviders(config2)
+	if err != nil {
+		return application{}, err
+	}
+	commitService := provideCommitService(client, renewer, router)
 	cronStore := cron.New(db)
 	repositoryStore := provideRepoStore(db)
-	fileService := provideContentService(client, renewer)
+	fileService := provideContentService(client, renewer, router)
 	configService := provideConfigPlugin(client, fileService, config2)
-	statusService := provideStatusService(client, renewer, config2)
+	installationService, err := provideInstallationService(client, config2)
+	if err != nil {
+		return application{}, err
+	}
+	statusService := provideStatusService(client, renewer, installationService, router, config2)
+	artifactStore := provideArtifactStore(db, config2)
 	buildStore := provideBuildStore(db)
 	stageStore := provideStageStore(db)
 	scheduler := provideScheduler(stageStore, config2)
-	webhookSender := provideWebhookPlugin(config2)
-	triggerer := trigger.New(configService, commitService, statusService, buildStore, scheduler, repositoryStore, userStore, webhookSender)
-	cronScheduler := cron2.New(commitService, cronStore, repositoryStore, userStore, triggerer)
-	corePubsub := pubsub.New()
-	logStore := provideLogStore(db, config2)
-	logStream := livelog.New()
-	netrcService := provideNetrcService(client, renewer, config2)
+	webhookDeliveryStore := delivery.New(db)
 	encrypter, err := provideEncrypter(config2)
 	if err != nil {
 		return application{}, err
 	}
+	repoWebhookStore := webhook.New(db, encrypter)
+	webhookSender := provideWebhookPlugin(config2, webhookDeliveryStore, repoWebhookStore)
+	policyStore := policy.New(db)
+	pathMappingStore := mapping.New(db)
+	poolService := providePoolPlugin(config2)
+	agentRegistry := rpc.NewRegistry()
+	platformService := providePlatformPlugin(agentRegistry, config2)
+	pullRequestService := providePullRequestService(client, renewer, router)
+	triggerer := trigger.New(configService, commitService, pullRequestService, statusService, buildStore, scheduler, repositoryStore, policyStore, poolService, platformService, pathMappingStore, userStore, webhookSender)
+	cronScheduler := cron2.New(buildStore, commitService, cronStore, repositoryStore, userStore, triggerer)
+	pollScheduler := poll.New(buildStore, commitService, repositoryStore, userStore, triggerer)
+	corePubsub, err := providePubsub(config2)
+	if err != nil {
+		return application{}, err
+	}
+	logStore := provideLogStore(db, config2)
+	logStream, err := provideLogStream(config2)
+	if err != nil {
+		return application{}, err
+	}
+	netrcService := provideNetrcService(client, renewer, installationService, router, config2)
+	notificationStore := notify.New(db, encrypter)
+	system := provideSystem(config2)
+	notificationService := provideNotificationService(buildStore, logStore, notificationStore, system, config2)
 	secretStore := secret.New(db, encrypter)
 	stepStore := step.New(db)
-	system := provideSystem(config2)
-	buildManager := manager.New(buildStore, configService, corePubsub, logStore, logStream, netrcService, repositoryStore, scheduler, secretStore, statusService, stageStore, stepStore, system, userStore, webhookSender)
+	coverageStore := coverage.New(db)
+	testResultStore := tests.New(db)
+	secretAccessStore := access.New(db)
+	registryStore := registry2.New(db, encrypter)
+	privilegedImageStore := privileged.New(db)
+	variableStore := variable.New(db, encrypter)
+	insightStore := insight.New(db)
+	annotationStore := annotation.New(db)
+	deploymentStore := deployment.New(db)
+	hookPayloadStore := provideHookPayloadStore(db, config2)
+	idTokenService, err := provideIDTokenService(config2)
+	if err != nil {
+		return application{}, err
+	}
+	summaryService := provideSummaryService(client, renewer, logStore, config2)
+	provenanceService, err := provideProvenanceService(artifactStore, config2)
+	if err != nil {
+		return application{}, err
+	}
+	secretFileService, err := provideSecretFileService(client, renewer, config2)
+	if err != nil {
+		return application{}, err
+	}
+	buildManager := manager.New(secretAccessStore, annotationStore, artifactStore, buildStore, configService, coverageStore, deploymentStore, corePubsub, idTokenService, insightStore, logStore, logStream, netrcService, notificationService, privilegedImageStore, provenanceService, registryStore, repositoryStore, scheduler, secretStore, secretFileService, statusService, stageStore, stepStore, summaryService, system, testResultStore, userStore, variableStore, webhookSender)
 	secretService := provideSecretPlugin(config2)
 	registryService := provideRegistryPlugin(config2)
 	runner := provideRunner(buildManager, secretService, registryService, config2)
-	hookService := provideHookService(client, renewer, config2)
+	hookService := provideHookService(client, renewer, installationService, router, config2)
 	coreLicense := provideLicense(client, config2)
 	licenseService := license.NewService(userStore, repositoryStore, buildStore, coreLicense)
 	permStore := perm.New(db)
 	repositoryService := repo.New(client, renewer)
-	session := provideSession(userStore, config2)
+	tokenStore := token2.New(db)
+	userSessionStore := sessions.New(db)
+	session := provideSession(userStore, tokenStore, userSessionStore, config2)
 	batcher := batch.New(db)
 	syncer := provideSyncer(repositoryService, repositoryStore, userStore, batcher, config2)
-	server := api.New(buildStore, cronStore, corePubsub, hookService, logStore, coreLicense, licenseService, permStore, repositoryStore, repositoryService, scheduler, secretStore, stageStore, stepStore, statusService, session, logStream, syncer, system, triggerer, userStore, webhookSender)
-	organizationService := orgs.New(client, renewer)
+	auditStore := audit.New(db)
+	organizationService := provideOrgService(client, renewer, config2)
+	referenceService := provideReferenceService(client, renewer, router, config2)
+	hookParser := provideHookParser(client, router, config2)
+	commandService := provideCommandService(client, buildStore, permStore, userStore, renewer, triggerer, config2)
+	server := api.New(secretAccessStore, agentRegistry, annotationStore, artifactStore, auditStore, buildStore, commitService, commandService, coverageStore, cronStore, webhookDeliveryStore, deploymentStore, corePubsub, hookService, insightStore, logStore, coreLicense, licenseService, pathMappingStore, notificationStore, organizationService, hookParser, hookPayloadStore, permStore, policyStore, privilegedImageStore, provenanceService, router, referenceService, registryStore, repositoryStore, repositoryService, repoWebhookStore, scheduler, secretStore, stageStore, stepStore, statusService, session, userSessionStore, logStream, syncer, system, testResultStore, tokenStore, triggerer, userStore, variableStore, webhookSender)
 	userService := user.New(client)
 	admissionService := provideAdmissionPlugin(client, organizationService, userService, config2)
-	hookParser := parser.New(client)
 	middleware := provideLogin(config2)
 	options := provideServerOptions(config2)
-	webServer := web.New(admissionService, buildStore, client, hookParser, coreLicense, licenseService, middleware, repositoryStore, session, syncer, triggerer, userStore, userService, webhookSender, options, system)
-	handler := provideRPC(buildManager, config2)
+	provider := provideOIDC(config2)
+	healthService := health.New(db, logStore, corePubsub, client)
+	webServer := web.New(admissionService, buildStore, client, commandService, healthService, hookParser, idTokenService, coreLicense, licenseService, middleware, hookPayloadStore, repositoryStore, session, provider, syncer, triggerer, userStore, userService, webhookSender, options, system)
+	handler := provideRPC(buildManager, agentRegistry, config2)
 	metricServer := metric.NewServer(session)
-	mux := provideRouter(server, webServer, handler, metricServer)
-	serverServer := provideServer(mux, config2)
-	mainApplication := newApplication(cronScheduler, runner, serverServer, userStore)
+	mux := provideRouter(server, webServer, handler, metricServer, config2)
+	tlsConfig, err := provideRPCTLS(config2)
+	if err != nil {
+		return application{}, err
+	}
+	serverServer := provideServer(mux, tlsConfig, config2)
+	streamServer := provideStreamServer(buildManager, tlsConfig, config2)
+	watchdogWatchdog := watchdog.New(buildStore, buildManager, repositoryStore, stageStore, webhookSender)
+	retrier := provideWebhookRetrier(config2, webhookDeliveryStore, repoWebhookStore)
+	syncerScheduler := provideSyncScheduler(syncer, userStore, config2)
+	elector, err := provideElector(db, config2)
+	if err != nil {
+		return application{}, err
+	}
+	mainApplication := newApplication(cronScheduler, elector, pollScheduler, retrier, runner, scheduler, serverServer, streamServer, syncerScheduler, userStore, watchdogWatchdog)
 	return mainApplication, nil
 }
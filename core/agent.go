@@ -0,0 +1,48 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import "context"
+
+type (
+	// Agent represents a remote build agent that completed
+	// the rpc handshake.
+	Agent struct {
+		Machine      string            `json:"machine"`
+		Version      string            `json:"version"`
+		Protocol     int               `json:"protocol"`
+		Capabilities []string          `json:"capabilities"`
+		OS           string            `json:"os"`
+		Arch         string            `json:"arch"`
+		Variant      string            `json:"variant,omitempty"`
+		Kernel       string            `json:"kernel,omitempty"`
+		Address      string            `json:"address"`
+		Fingerprint  string            `json:"fingerprint,omitempty"`
+		Labels       map[string]string `json:"labels,omitempty"`
+		Created      int64             `json:"created"`
+		Updated      int64             `json:"updated"`
+	}
+
+	// AgentRegistry tracks the remote build agents that
+	// completed the rpc handshake.
+	AgentRegistry interface {
+		// Register registers the agent, replacing the existing
+		// agent with the same machine name.
+		Register(context.Context, *Agent) error
+
+		// List returns a list of registered agents.
+		List(context.Context) ([]*Agent, error)
+	}
+)
@@ -0,0 +1,88 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"context"
+	"errors"
+	"net/url"
+)
+
+// Annotation kinds.
+const (
+	AnnotationMarkdown = "markdown"
+	AnnotationValue    = "value"
+	AnnotationLink     = "link"
+)
+
+// maximum size of the annotation value.
+const annotationMaxSize = 65535
+
+var (
+	errAnnotationKindInvalid = errors.New("Invalid Annotation Kind")
+	errAnnotationNameInvalid = errors.New("Invalid Annotation Name")
+	errAnnotationTooLarge    = errors.New("Annotation Value Exceeds Maximum Size")
+	errAnnotationLinkInvalid = errors.New("Invalid Annotation Link")
+)
+
+type (
+	// Annotation represents structured output published by
+	// a pipeline step, such as a markdown summary, a key
+	// value result (e.g. an image digest) or a link (e.g.
+	// a deployment url).
+	Annotation struct {
+		ID      int64  `json:"id"`
+		BuildID int64  `json:"build_id"`
+		StageID int64  `json:"stage_id"`
+		StepID  int64  `json:"step_id"`
+		Kind    string `json:"kind"`
+		Name    string `json:"name"`
+		Value   string `json:"value"`
+		Created int64  `json:"created"`
+	}
+
+	// AnnotationStore persists step annotations to storage.
+	AnnotationStore interface {
+		// List returns a list of annotations for the build ID
+		// from the datastore.
+		List(ctx context.Context, build int64) ([]*Annotation, error)
+
+		// Create persists a new annotation to the datastore.
+		Create(ctx context.Context, annotation *Annotation) error
+	}
+)
+
+// Validate validates the annotation fields and returns an
+// error if the annotation is invalid.
+func (a *Annotation) Validate() error {
+	switch {
+	case a.Name == "", len(a.Name) > 250:
+		return errAnnotationNameInvalid
+	case len(a.Value) > annotationMaxSize:
+		return errAnnotationTooLarge
+	}
+	switch a.Kind {
+	case AnnotationMarkdown, AnnotationValue:
+		return nil
+	case AnnotationLink:
+		u, err := url.Parse(a.Value)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			return errAnnotationLinkInvalid
+		}
+		return nil
+	default:
+		return errAnnotationKindInvalid
+	}
+}
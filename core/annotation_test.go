@@ -0,0 +1,52 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package core
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAnnotationValidate(t *testing.T) {
+	tests := []struct {
+		annotation *Annotation
+		error      error
+	}{
+		{
+			annotation: &Annotation{Kind: AnnotationMarkdown, Name: "summary", Value: "# Deployed"},
+			error:      nil,
+		},
+		{
+			annotation: &Annotation{Kind: AnnotationValue, Name: "digest", Value: "sha256:3e8d"},
+			error:      nil,
+		},
+		{
+			annotation: &Annotation{Kind: AnnotationLink, Name: "preview", Value: "https://preview.company.com"},
+			error:      nil,
+		},
+		{
+			annotation: &Annotation{Kind: AnnotationLink, Name: "preview", Value: "javascript:alert(1)"},
+			error:      errAnnotationLinkInvalid,
+		},
+		{
+			annotation: &Annotation{Kind: "html", Name: "summary"},
+			error:      errAnnotationKindInvalid,
+		},
+		{
+			annotation: &Annotation{Kind: AnnotationValue},
+			error:      errAnnotationNameInvalid,
+		},
+		{
+			annotation: &Annotation{Kind: AnnotationMarkdown, Name: "summary", Value: strings.Repeat("a", 65536)},
+			error:      errAnnotationTooLarge,
+		},
+	}
+	for i, test := range tests {
+		got, want := test.annotation.Validate(), test.error
+		if got != want {
+			t.Errorf("Want error %v, got %v at index %d", want, got, i)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"context"
+	"io"
+)
+
+type (
+	// Artifact represents a file produced by a pipeline step
+	// and uploaded to the server.
+	Artifact struct {
+		ID      int64  `json:"id"`
+		BuildID int64  `json:"build_id"`
+		StageID int64  `json:"stage_id"`
+		StepID  int64  `json:"step_id"`
+		Name    string `json:"name"`
+		Size    int64  `json:"size"`
+		Created int64  `json:"created"`
+	}
+
+	// ArtifactStore persists build artifacts to storage.
+	ArtifactStore interface {
+		// List returns a list of artifacts for the build ID
+		// from the datastore.
+		List(ctx context.Context, build int64) ([]*Artifact, error)
+
+		// Find returns an artifact from the datastore.
+		Find(ctx context.Context, id int64) (*Artifact, error)
+
+		// Open returns the artifact contents from the datastore.
+		Open(ctx context.Context, artifact *Artifact) (io.ReadCloser, error)
+
+		// Create copies the artifact contents from Reader r
+		// to the datastore.
+		Create(ctx context.Context, artifact *Artifact, r io.Reader) error
+
+		// Delete purges the artifact from the datastore.
+		Delete(ctx context.Context, artifact *Artifact) error
+	}
+)
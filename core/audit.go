@@ -0,0 +1,93 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import "context"
+
+// Audit actions.
+const (
+	AuditSecretCreate       = "secret:create"
+	AuditSecretUpdate       = "secret:update"
+	AuditSecretDelete       = "secret:delete"
+	AuditRepoEnable         = "repo:enable"
+	AuditRepoDisable        = "repo:disable"
+	AuditBuildCancel        = "build:cancel"
+	AuditUserCreate         = "user:create"
+	AuditUserUpdate         = "user:update"
+	AuditUserDelete         = "user:delete"
+	AuditQueuePause         = "queue:pause"
+	AuditQueueResume        = "queue:resume"
+	AuditSystemRestore      = "system:restore"
+	AuditSecretRotate       = "secret:rotate"
+	AuditWebhookCreate      = "webhook:create"
+	AuditWebhookUpdate      = "webhook:update"
+	AuditWebhookDelete      = "webhook:delete"
+	AuditRegistryCreate     = "registry:create"
+	AuditRegistryUpdate     = "registry:update"
+	AuditRegistryDelete     = "registry:delete"
+	AuditPolicyCreate       = "policy:create"
+	AuditPolicyUpdate       = "policy:update"
+	AuditPolicyDelete       = "policy:delete"
+	AuditStageApprove       = "stage:approve"
+	AuditStageDecline       = "stage:decline"
+	AuditPrivilegedCreate   = "privileged:create"
+	AuditPrivilegedDelete   = "privileged:delete"
+	AuditVariableCreate     = "variable:create"
+	AuditVariableUpdate     = "variable:update"
+	AuditVariableDelete     = "variable:delete"
+	AuditSessionRevoke      = "session:revoke"
+	AuditRepoRegister       = "repo:register"
+	AuditHookReplay         = "hook:replay"
+	AuditSignerRotate       = "signer:rotate"
+	AuditNotificationCreate = "notification:create"
+	AuditNotificationUpdate = "notification:update"
+	AuditNotificationDelete = "notification:delete"
+)
+
+type (
+	// AuditEvent represents a security-relevant action
+	// performed by a user.
+	AuditEvent struct {
+		ID      int64  `json:"id"`
+		RepoID  int64  `json:"repo_id,omitempty"`
+		Actor   string `json:"actor"`
+		Action  string `json:"action"`
+		Target  string `json:"target"`
+		IP      string `json:"ip"`
+		Created int64  `json:"created"`
+	}
+
+	// AuditFilter provides audit event filter parameters.
+	// Zero value fields are ignored.
+	AuditFilter struct {
+		Repo   int64
+		Actor  string
+		Action string
+		Since  int64
+		Limit  int
+		Offset int
+	}
+
+	// AuditStore persists audit events to storage.
+	AuditStore interface {
+		// List returns a list of audit events from the
+		// datastore that match the filter, ordered from
+		// newest to oldest.
+		List(ctx context.Context, filter AuditFilter) ([]*AuditEvent, error)
+
+		// Create persists a new audit event to the datastore.
+		Create(ctx context.Context, event *AuditEvent) error
+	}
+)
@@ -18,38 +18,56 @@ import "context"
 
 // Build represents a build execution.
 type Build struct {
-	ID           int64             `db:"build_id"             json:"id"`
-	RepoID       int64             `db:"build_repo_id"        json:"repo_id"`
-	Trigger      string            `db:"build_trigger"        json:"trigger"`
-	Number       int64             `db:"build_number"         json:"number"`
-	Parent       int64             `db:"build_parent"         json:"parent,omitempty"`
-	Status       string            `db:"build_status"         json:"status"`
-	Error        string            `db:"build_error"          json:"error,omitempty"`
-	Event        string            `db:"build_event"          json:"event"`
-	Action       string            `db:"build_action"         json:"action"`
-	Link         string            `db:"build_link"           json:"link"`
-	Timestamp    int64             `db:"build_timestamp"      json:"timestamp"`
-	Title        string            `db:"build_title"          json:"title,omitempty"`
-	Message      string            `db:"build_message"        json:"message"`
-	Before       string            `db:"build_before"         json:"before"`
-	After        string            `db:"build_after"          json:"after"`
-	Ref          string            `db:"build_ref"            json:"ref"`
-	Fork         string            `db:"build_source_repo"    json:"source_repo"`
-	Source       string            `db:"build_source"         json:"source"`
-	Target       string            `db:"build_target"         json:"target"`
-	Author       string            `db:"build_author"         json:"author_login"`
-	AuthorName   string            `db:"build_author_name"    json:"author_name"`
-	AuthorEmail  string            `db:"build_author_email"   json:"author_email"`
-	AuthorAvatar string            `db:"build_author_avatar"  json:"author_avatar"`
-	Sender       string            `db:"build_sender"         json:"sender"`
-	Params       map[string]string `db:"build_params"         json:"params,omitempty"`
-	Deploy       string            `db:"build_deploy"         json:"deploy_to,omitempty"`
-	Started      int64             `db:"build_started"        json:"started"`
-	Finished     int64             `db:"build_finished"       json:"finished"`
-	Created      int64             `db:"build_created"        json:"created"`
-	Updated      int64             `db:"build_updated"        json:"updated"`
-	Version      int64             `db:"build_version"        json:"version"`
-	Stages       []*Stage          `db:"-"                    json:"stages,omitempty"`
+	ID            int64             `db:"build_id"             json:"id"`
+	RepoID        int64             `db:"build_repo_id"        json:"repo_id"`
+	Trigger       string            `db:"build_trigger"        json:"trigger"`
+	Number        int64             `db:"build_number"         json:"number"`
+	Parent        int64             `db:"build_parent"         json:"parent,omitempty"`
+	Status        string            `db:"build_status"         json:"status"`
+	Error         string            `db:"build_error"          json:"error,omitempty"`
+	Event         string            `db:"build_event"          json:"event"`
+	Action        string            `db:"build_action"         json:"action"`
+	Link          string            `db:"build_link"           json:"link"`
+	Timestamp     int64             `db:"build_timestamp"      json:"timestamp"`
+	Title         string            `db:"build_title"          json:"title,omitempty"`
+	Message       string            `db:"build_message"        json:"message"`
+	Before        string            `db:"build_before"         json:"before"`
+	After         string            `db:"build_after"          json:"after"`
+	Ref           string            `db:"build_ref"            json:"ref"`
+	Fork          string            `db:"build_source_repo"    json:"source_repo"`
+	Source        string            `db:"build_source"         json:"source"`
+	Target        string            `db:"build_target"         json:"target"`
+	Author        string            `db:"build_author"         json:"author_login"`
+	AuthorName    string            `db:"build_author_name"    json:"author_name"`
+	AuthorEmail   string            `db:"build_author_email"   json:"author_email"`
+	AuthorAvatar  string            `db:"build_author_avatar"  json:"author_avatar"`
+	Sender        string            `db:"build_sender"         json:"sender"`
+	Params        map[string]string `db:"build_params"         json:"params,omitempty"`
+	Deploy        string            `db:"build_deploy"         json:"deploy_to,omitempty"`
+	RestartedFrom int64             `db:"build_restarted_from" json:"restarted_from,omitempty"`
+	TriggeredBy   string            `db:"build_triggered_by"   json:"triggered_by,omitempty"`
+	Labels        []string          `db:"build_labels"         json:"labels,omitempty"`
+	ChangedFiles  int64             `db:"build_changed_files"  json:"changed_files,omitempty"`
+	Started       int64             `db:"build_started"        json:"started"`
+	Finished      int64             `db:"build_finished"       json:"finished"`
+	Created       int64             `db:"build_created"        json:"created"`
+	Updated       int64             `db:"build_updated"        json:"updated"`
+	Version       int64             `db:"build_version"        json:"version"`
+	Stages        []*Stage          `db:"-"                    json:"stages,omitempty"`
+}
+
+// BuildFilter provides build filter parameters. Zero value
+// fields are ignored.
+type BuildFilter struct {
+	Status string
+	Event  string
+	Branch string
+	Label  string
+	Since  int64
+	Before int64
+	Sort   string // asc or desc (default)
+	Limit  int
+	Offset int
 }
 
 // BuildStore defines operations for working with builds.
@@ -63,9 +81,17 @@ type BuildStore interface {
 	// FindLast returns the last build from the datastore by ref.
 	FindRef(context.Context, int64, string) (*Build, error)
 
+	// FindCommit returns the last successful build from the
+	// datastore by commit sha and event.
+	FindCommit(context.Context, int64, string, string) (*Build, error)
+
 	// List returns a list of builds from the datastore by repository id.
 	List(context.Context, int64, int, int) ([]*Build, error)
 
+	// ListFilter returns a list of builds from the datastore
+	// by repository id that match the filter.
+	ListFilter(context.Context, int64, BuildFilter) ([]*Build, error)
+
 	// ListRef returns a list of builds from the datastore by ref.
 	ListRef(context.Context, int64, string, int, int) ([]*Build, error)
 
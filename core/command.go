@@ -0,0 +1,69 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"context"
+	"strings"
+)
+
+// Command names.
+const (
+	CommandRetest = "retest"
+	CommandDeploy = "deploy"
+)
+
+type (
+	// Command represents a slash-command posted as a pull
+	// request comment (e.g. /deploy production).
+	Command struct {
+		Name string
+		Args []string
+	}
+
+	// CommandService executes slash-commands posted as pull
+	// request comments.
+	CommandService interface {
+		// Exec executes the command in the pull request
+		// comment and returns the resulting build. A nil
+		// build is returned if the comment does not contain
+		// an enabled command, or if the sender is not
+		// authorized to execute the command.
+		Exec(ctx context.Context, repo *Repository, hook *Hook) (*Build, error)
+	}
+)
+
+// ParseCommand parses the slash-command from the comment
+// body. The first line that begins with a forward slash is
+// parsed, where the first word is the command name and the
+// remaining words are the command arguments. If the body
+// does not contain a command, a nil value is returned.
+func ParseCommand(body string) *Command {
+	for _, line := range strings.Split(body, "\n") {
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, "/") {
+			continue
+		}
+		fields := strings.Fields(line[1:])
+		if len(fields) == 0 {
+			continue
+		}
+		return &Command{
+			Name: strings.ToLower(fields[0]),
+			Args: fields[1:],
+		}
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package core
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseCommand(t *testing.T) {
+	tests := []struct {
+		body string
+		want *Command
+	}{
+		{
+			body: "/retest",
+			want: &Command{Name: "retest", Args: []string{}},
+		},
+		{
+			body: "  /Deploy production  ",
+			want: &Command{Name: "deploy", Args: []string{"production"}},
+		},
+		{
+			body: "looks good to me\n/deploy staging region=eu\n/retest",
+			want: &Command{Name: "deploy", Args: []string{"staging", "region=eu"}},
+		},
+		{
+			body: "looks good to me",
+			want: nil,
+		},
+		{
+			body: "see /retest for details",
+			want: nil,
+		},
+		{
+			body: "/",
+			want: nil,
+		},
+		{
+			body: "",
+			want: nil,
+		},
+	}
+	for _, test := range tests {
+		got := ParseCommand(test.body)
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("Want command %v for body %q, got %v", test.want, test.body, got)
+		}
+	}
+}
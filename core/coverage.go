@@ -0,0 +1,72 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"context"
+	"errors"
+)
+
+var errCoverageInvalid = errors.New("Invalid Coverage Percentage")
+
+type (
+	// Coverage represents a code coverage summary reported
+	// by a pipeline step.
+	Coverage struct {
+		ID      int64           `json:"id"`
+		RepoID  int64           `json:"repo_id"`
+		BuildID int64           `json:"build_id"`
+		StepID  int64           `json:"step_id"`
+		Ref     string          `json:"ref"`
+		Percent float64         `json:"percent"`
+		Files   []*CoverageFile `json:"files,omitempty"`
+		Created int64           `json:"created"`
+	}
+
+	// CoverageFile represents the code coverage for a file.
+	CoverageFile struct {
+		Name    string  `json:"name"`
+		Percent float64 `json:"percent"`
+	}
+
+	// CoverageStore persists code coverage to storage.
+	CoverageStore interface {
+		// List returns a list of coverage reports for the
+		// repository ref from the datastore, ordered from
+		// newest to oldest.
+		List(ctx context.Context, repo int64, ref string, limit int) ([]*Coverage, error)
+
+		// ListBuild returns a list of coverage reports for the
+		// build ID from the datastore.
+		ListBuild(ctx context.Context, build int64) ([]*Coverage, error)
+
+		// Create persists a new coverage report to the datastore.
+		Create(ctx context.Context, coverage *Coverage) error
+	}
+)
+
+// Validate validates the coverage fields and returns an
+// error if any of the percentages are out of range.
+func (c *Coverage) Validate() error {
+	if c.Percent < 0 || c.Percent > 100 {
+		return errCoverageInvalid
+	}
+	for _, file := range c.Files {
+		if file.Percent < 0 || file.Percent > 100 {
+			return errCoverageInvalid
+		}
+	}
+	return nil
+}
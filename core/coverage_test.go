@@ -0,0 +1,41 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package core
+
+import "testing"
+
+func TestCoverageValidate(t *testing.T) {
+	tests := []struct {
+		coverage *Coverage
+		error    error
+	}{
+		{
+			coverage: &Coverage{Percent: 82.5},
+			error:    nil,
+		},
+		{
+			coverage: &Coverage{Percent: 100, Files: []*CoverageFile{{Name: "main.go", Percent: 0}}},
+			error:    nil,
+		},
+		{
+			coverage: &Coverage{Percent: -1},
+			error:    errCoverageInvalid,
+		},
+		{
+			coverage: &Coverage{Percent: 100.1},
+			error:    errCoverageInvalid,
+		},
+		{
+			coverage: &Coverage{Percent: 50, Files: []*CoverageFile{{Name: "main.go", Percent: 101}}},
+			error:    errCoverageInvalid,
+		},
+	}
+	for i, test := range tests {
+		got, want := test.coverage.Validate(), test.error
+		if got != want {
+			t.Errorf("Want error %v, got %v at index %d", want, got, i)
+		}
+	}
+}
@@ -42,6 +42,7 @@ type (
 		Branch   string `json:"branch"`
 		Target   string `json:"target,omitempty"`
 		Disabled bool   `json:"disabled"`
+		Rebuild  bool   `json:"rebuild"`
 		Created  int64  `json:"created"`
 		Updated  int64  `json:"updated"`
 		Version  int64  `json:"version"`
@@ -0,0 +1,68 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"context"
+	"errors"
+)
+
+var errDeploymentEnvironmentInvalid = errors.New("Invalid Deployment Environment")
+
+type (
+	// Deployment represents the deployment of a commit to a
+	// target environment. Deployments are recorded when a
+	// promotion or rollback build completes, or when they are
+	// reported by a pipeline step.
+	Deployment struct {
+		ID          int64  `json:"id"`
+		RepoID      int64  `json:"repo_id"`
+		BuildID     int64  `json:"build_id"`
+		Number      int64  `json:"build_number"`
+		Environment string `json:"environment"`
+		Commit      string `json:"commit"`
+		Ref         string `json:"ref"`
+		Status      string `json:"status"`
+		Deployer    string `json:"deployer"`
+		Created     int64  `json:"created"`
+	}
+
+	// DeploymentStore persists deployment history to storage.
+	DeploymentStore interface {
+		// List returns a list of deployments to the named
+		// environment from the datastore, ordered from newest
+		// to oldest.
+		List(ctx context.Context, repo int64, environment string, limit int) ([]*Deployment, error)
+
+		// ListLatest returns the most recent successful
+		// deployment to each environment for the repository
+		// from the datastore.
+		ListLatest(ctx context.Context, repo int64) ([]*Deployment, error)
+
+		// Create persists a new deployment to the datastore.
+		Create(ctx context.Context, deployment *Deployment) error
+	}
+)
+
+// Validate validates the deployment fields and returns an
+// error if the deployment is invalid.
+func (d *Deployment) Validate() error {
+	switch {
+	case d.Environment == "", len(d.Environment) > 250:
+		return errDeploymentEnvironmentInvalid
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,36 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package core
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDeploymentValidate(t *testing.T) {
+	tests := []struct {
+		deployment *Deployment
+		error      error
+	}{
+		{
+			deployment: &Deployment{Environment: "production"},
+			error:      nil,
+		},
+		{
+			deployment: &Deployment{},
+			error:      errDeploymentEnvironmentInvalid,
+		},
+		{
+			deployment: &Deployment{Environment: strings.Repeat("a", 251)},
+			error:      errDeploymentEnvironmentInvalid,
+		},
+	}
+	for i, test := range tests {
+		got, want := test.deployment.Validate(), test.error
+		if got != want {
+			t.Errorf("Want error %v, got %v at index %d", want, got, i)
+		}
+	}
+}
@@ -0,0 +1,34 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"context"
+	"time"
+)
+
+// Elector elects a single leader among multiple server
+// instances, used to ensure background loops, such as the
+// cron scheduler, run on exactly one server instance.
+type Elector interface {
+	// Acquire acquires or renews the named lease for the
+	// duration, and returns true if this server instance
+	// holds the lease.
+	Acquire(ctx context.Context, name string, ttl time.Duration) (bool, error)
+
+	// Release releases the named lease, if held by this
+	// server instance.
+	Release(ctx context.Context, name string) error
+}
@@ -21,4 +21,5 @@ const (
 	EventTag         = "tag"
 	EventPromote     = "promote"
 	EventRollback    = "rollback"
+	EventCustom      = "custom"
 )
@@ -0,0 +1,60 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import "context"
+
+// Health status values.
+const (
+	HealthOK    = "ok"
+	HealthError = "error"
+)
+
+type (
+	// Health provides the health status of the system and
+	// the individual system components.
+	Health struct {
+		Status     string             `json:"status"`
+		Components []*HealthComponent `json:"components"`
+	}
+
+	// HealthComponent provides the health status of a system
+	// component, such as the database or the source control
+	// management system.
+	HealthComponent struct {
+		Name    string `json:"name"`
+		Status  string `json:"status"`
+		Error   string `json:"error,omitempty"`
+		Latency int64  `json:"latency"`
+	}
+
+	// HealthService checks the health of the system components.
+	HealthService interface {
+		// Live checks the internal components required to
+		// serve requests, including the database, the log
+		// store and the pubsub broker.
+		Live(context.Context) *Health
+
+		// Ready checks the internal components and the
+		// external dependencies, including the source control
+		// management system.
+		Ready(context.Context) *Health
+	}
+)
+
+// OK returns true if all system components are healthy.
+func (h *Health) OK() bool {
+	return h.Status == HealthOK
+}
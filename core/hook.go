@@ -25,6 +25,12 @@ const (
 	ActionCreate = "create"
 	ActionDelete = "delete"
 	ActionSync   = "sync"
+
+	// ActionComment identifies a pull request comment hook,
+	// which may contain a slash-command. Comment hooks are
+	// handled by the CommandService and never trigger a
+	// build directly.
+	ActionComment = "comment"
 )
 
 // Hook represents the payload of a post-commit hook.
@@ -50,6 +56,8 @@ type Hook struct {
 	Deployment   string            `json:"deploy_to"`
 	Sender       string            `json:"sender"`
 	Params       map[string]string `json:"params"`
+	Labels       []string          `json:"labels"`
+	Force        bool              `json:"force"`
 }
 
 // HookService manages post-commit hooks in the external
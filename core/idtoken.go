@@ -0,0 +1,59 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import "context"
+
+type (
+	// IDTokenArgs provides the build details used to issue
+	// an identity token.
+	IDTokenArgs struct {
+		Repo  *Repository
+		Build *Build
+		Stage *Stage
+	}
+
+	// JSONWebKey represents a public signing key.
+	JSONWebKey struct {
+		Kty string `json:"kty"`
+		Use string `json:"use"`
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+		N   string `json:"n"`
+		E   string `json:"e"`
+	}
+
+	// JSONWebKeySet represents a set of public signing keys.
+	JSONWebKeySet struct {
+		Keys []*JSONWebKey `json:"keys"`
+	}
+
+	// IDTokenService issues short-lived identity tokens to
+	// build stages. The token can be exchanged with a cloud
+	// provider for temporary credentials using OpenID Connect
+	// federation.
+	IDTokenService interface {
+		// Issuer returns the token issuer url.
+		Issuer() string
+
+		// Issue returns a signed identity token for the
+		// build stage.
+		Issue(ctx context.Context, args *IDTokenArgs) (string, error)
+
+		// Keys returns the public keys used to verify the
+		// token signature.
+		Keys(ctx context.Context) (*JSONWebKeySet, error)
+	}
+)
@@ -0,0 +1,184 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"context"
+	"math"
+	"time"
+)
+
+// InsightBuckets defines the upper bounds, in seconds, of the
+// build duration histogram used to estimate the percentiles.
+// Builds that exceed the last bound are counted in an overflow
+// bucket.
+var InsightBuckets = []int64{
+	30, 60, 120, 180, 300, 450, 600, 900, 1200, 1800,
+	2700, 3600, 5400, 7200, 10800, 14400, 21600, 43200, 86400,
+}
+
+type (
+	// Insight summarizes the builds of a repository over
+	// a period of time.
+	Insight struct {
+		Since        int64   `json:"since"`
+		Builds       int64   `json:"builds"`
+		Success      int64   `json:"success"`
+		Failure      int64   `json:"failure"`
+		SuccessRate  float64 `json:"success_rate"`
+		DurationMean int64   `json:"duration_mean"`
+		DurationP50  int64   `json:"duration_p50"`
+		DurationP90  int64   `json:"duration_p90"`
+		DurationP95  int64   `json:"duration_p95"`
+		WaitMean     int64   `json:"wait_mean"`
+	}
+
+	// InsightDay summarizes the builds of a repository for
+	// a single day. The day is the unix timestamp of midnight
+	// UTC, and the durations are the total number of seconds.
+	InsightDay struct {
+		Day      int64 `json:"day"`
+		Total    int64 `json:"total"`
+		Success  int64 `json:"success"`
+		Failure  int64 `json:"failure"`
+		Duration int64 `json:"duration"`
+		Wait     int64 `json:"wait"`
+	}
+
+	// InsightBucket counts the builds in a bucket of the build
+	// duration histogram.
+	InsightBucket struct {
+		Bucket int   `json:"bucket"`
+		Count  int64 `json:"count"`
+	}
+
+	// InsightStep summarizes the outcomes of a pipeline step.
+	InsightStep struct {
+		Stage        string  `json:"stage"`
+		Name         string  `json:"name"`
+		Total        int64   `json:"total"`
+		Failure      int64   `json:"failure"`
+		FailureRate  float64 `json:"failure_rate"`
+		DurationMean int64   `json:"duration_mean"`
+	}
+
+	// InsightFlaky summarizes the outcomes of a pipeline step
+	// for the commits that were built more than once. A step
+	// is flaky for a commit if the step both passed and failed,
+	// and the score is the fraction of flaky commits.
+	InsightFlaky struct {
+		Stage   string  `json:"stage"`
+		Name    string  `json:"name"`
+		Commits int64   `json:"commits"`
+		Flaky   int64   `json:"flaky"`
+		Score   float64 `json:"score"`
+	}
+
+	// InsightStore persists build statistics to storage. The
+	// statistics are computed incrementally when a build is
+	// finished, and are stored in summary tables.
+	InsightStore interface {
+		// Record adds the finished build and its stages to the
+		// summary tables.
+		Record(ctx context.Context, build *Build, stages []*Stage) error
+
+		// ListDays returns the daily summaries for the
+		// repository since the given day, ordered from oldest
+		// to newest.
+		ListDays(ctx context.Context, repo, since int64) ([]*InsightDay, error)
+
+		// ListDurations returns the build duration histogram
+		// for the repository since the given day.
+		ListDurations(ctx context.Context, repo, since int64) ([]*InsightBucket, error)
+
+		// ListSteps returns the summaries of the failing steps
+		// for the repository since the given day, ordered by
+		// failure rate from highest to lowest.
+		ListSteps(ctx context.Context, repo, since int64, limit int) ([]*InsightStep, error)
+
+		// ListFlaky returns the summaries of the flaky steps
+		// for the repository since the given day, ordered by
+		// flakiness score from highest to lowest. If the branch
+		// is empty, the steps are summarized across branches.
+		ListFlaky(ctx context.Context, repo int64, branch string, since int64, limit int) ([]*InsightFlaky, error)
+	}
+)
+
+// InsightDayOf returns the unix timestamp of midnight UTC of
+// the day that contains the unix timestamp.
+func InsightDayOf(unix int64) int64 {
+	return time.Unix(unix, 0).UTC().Truncate(24 * time.Hour).Unix()
+}
+
+// InsightBucketOf returns the index of the histogram bucket
+// for the duration in seconds.
+func InsightBucketOf(duration int64) int {
+	for i, bound := range InsightBuckets {
+		if duration <= bound {
+			return i
+		}
+	}
+	return len(InsightBuckets)
+}
+
+// NewInsight returns the summary of the daily summaries and
+// build duration histogram.
+func NewInsight(since int64, days []*InsightDay, buckets []*InsightBucket) *Insight {
+	out := &Insight{Since: since}
+	var duration, wait int64
+	for _, day := range days {
+		out.Builds += day.Total
+		out.Success += day.Success
+		out.Failure += day.Failure
+		duration += day.Duration
+		wait += day.Wait
+	}
+	if out.Builds != 0 {
+		out.SuccessRate = float64(out.Success) / float64(out.Builds)
+		out.DurationMean = duration / out.Builds
+		out.WaitMean = wait / out.Builds
+	}
+	out.DurationP50 = insightPercentile(buckets, 0.50)
+	out.DurationP90 = insightPercentile(buckets, 0.90)
+	out.DurationP95 = insightPercentile(buckets, 0.95)
+	return out
+}
+
+// helper function returns the upper bound of the histogram
+// bucket that contains the percentile. The overflow bucket
+// is reported as the last bound.
+func insightPercentile(buckets []*InsightBucket, p float64) int64 {
+	counts := make([]int64, len(InsightBuckets)+1)
+	var total int64
+	for _, bucket := range buckets {
+		if bucket.Bucket < 0 || bucket.Bucket >= len(counts) {
+			continue
+		}
+		counts[bucket.Bucket] += bucket.Count
+		total += bucket.Count
+	}
+	if total == 0 {
+		return 0
+	}
+	rank := int64(math.Ceil(p * float64(total)))
+	var seen int64
+	for i, count := range counts {
+		seen += count
+		if seen >= rank && i < len(InsightBuckets) {
+			return InsightBuckets[i]
+		}
+	}
+	return InsightBuckets[len(InsightBuckets)-1]
+}
@@ -0,0 +1,77 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package core
+
+import "testing"
+
+func TestInsightDayOf(t *testing.T) {
+	// 2019-03-14 15:09:26 UTC
+	if got, want := InsightDayOf(1552576166), int64(1552521600); got != want {
+		t.Errorf("Want day %d, got %d", want, got)
+	}
+	if got, want := InsightDayOf(1552521600), int64(1552521600); got != want {
+		t.Errorf("Want day %d, got %d", want, got)
+	}
+}
+
+func TestInsightBucketOf(t *testing.T) {
+	tests := []struct {
+		duration int64
+		bucket   int
+	}{
+		{0, 0},
+		{30, 0},
+		{31, 1},
+		{600, 6},
+		{86400, len(InsightBuckets) - 1},
+		{86401, len(InsightBuckets)},
+	}
+	for _, test := range tests {
+		if got, want := InsightBucketOf(test.duration), test.bucket; got != want {
+			t.Errorf("Want duration %d in bucket %d, got %d", test.duration, want, got)
+		}
+	}
+}
+
+func TestNewInsight(t *testing.T) {
+	days := []*InsightDay{
+		{Day: 1552435200, Total: 6, Success: 5, Failure: 1, Duration: 1200, Wait: 60},
+		{Day: 1552521600, Total: 4, Success: 3, Failure: 1, Duration: 800, Wait: 40},
+	}
+	buckets := []*InsightBucket{
+		{Bucket: InsightBucketOf(60), Count: 5},
+		{Bucket: InsightBucketOf(300), Count: 4},
+		{Bucket: InsightBucketOf(900), Count: 1},
+	}
+	insight := NewInsight(1552435200, days, buckets)
+	if got, want := insight.Builds, int64(10); got != want {
+		t.Errorf("Want %d builds, got %d", want, got)
+	}
+	if got, want := insight.SuccessRate, 0.8; got != want {
+		t.Errorf("Want success rate %v, got %v", want, got)
+	}
+	if got, want := insight.DurationMean, int64(200); got != want {
+		t.Errorf("Want mean duration %d, got %d", want, got)
+	}
+	if got, want := insight.WaitMean, int64(10); got != want {
+		t.Errorf("Want mean wait %d, got %d", want, got)
+	}
+	if got, want := insight.DurationP50, int64(60); got != want {
+		t.Errorf("Want p50 duration %d, got %d", want, got)
+	}
+	if got, want := insight.DurationP90, int64(300); got != want {
+		t.Errorf("Want p90 duration %d, got %d", want, got)
+	}
+	if got, want := insight.DurationP95, int64(900); got != want {
+		t.Errorf("Want p95 duration %d, got %d", want, got)
+	}
+}
+
+func TestNewInsight_Empty(t *testing.T) {
+	insight := NewInsight(1552435200, nil, nil)
+	if insight.Builds != 0 || insight.SuccessRate != 0 || insight.DurationP50 != 0 {
+		t.Errorf("Want empty insight")
+	}
+}
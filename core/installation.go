@@ -0,0 +1,36 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import "context"
+
+type (
+	// InstallationToken represents an access token issued to
+	// a source code management app installation.
+	InstallationToken struct {
+		Token   string `json:"token"`
+		Expires int64  `json:"expires"`
+	}
+
+	// InstallationService returns access tokens issued to an
+	// app installation (e.g. a GitHub App) that can be used in
+	// place of a user oauth token.
+	InstallationService interface {
+		// Token returns an access token for the app installation
+		// on the repository, restricted to the repository and
+		// the requested permissions.
+		Token(ctx context.Context, repo *Repository, permissions map[string]string) (*InstallationToken, error)
+	}
+)
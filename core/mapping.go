@@ -0,0 +1,81 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"context"
+	"errors"
+	"path"
+
+	"github.com/bmatcuk/doublestar"
+)
+
+var (
+	errPathMappingPatternInvalid  = errors.New("Invalid Path Pattern")
+	errPathMappingPipelineInvalid = errors.New("Invalid Pipeline Name")
+)
+
+type (
+	// PathMapping routes changed paths to a named pipeline.
+	// If a repository defines path mappings, a mapped pipeline
+	// is only executed when the commit changes a path that
+	// matches one of its patterns. Pipelines without a mapping
+	// are always executed.
+	PathMapping struct {
+		ID       int64  `json:"id"`
+		RepoID   int64  `json:"repo_id"`
+		Pattern  string `json:"pattern"`
+		Pipeline string `json:"pipeline"`
+		Created  int64  `json:"created"`
+	}
+
+	// PathMappingStore manages repository path mappings in
+	// storage.
+	PathMappingStore interface {
+		// List returns the path mappings for the repository.
+		List(context.Context, int64) ([]*PathMapping, error)
+
+		// Find returns a path mapping from the datastore.
+		Find(context.Context, int64) (*PathMapping, error)
+
+		// Create persists a new path mapping to the datastore.
+		Create(context.Context, *PathMapping) error
+
+		// Delete deletes a path mapping from the datastore.
+		Delete(context.Context, *PathMapping) error
+	}
+)
+
+// Validate validates the required fields and formats.
+func (m *PathMapping) Validate() error {
+	switch {
+	case m.Pattern == "":
+		return errPathMappingPatternInvalid
+	case m.Pipeline == "":
+		return errPathMappingPipelineInvalid
+	}
+	if _, err := path.Match(m.Pattern, ""); err != nil {
+		return errPathMappingPatternInvalid
+	}
+	return nil
+}
+
+// Match returns true if the path matches the pattern. The
+// pattern supports the ** wildcard to match any number of
+// directories.
+func (m *PathMapping) Match(name string) bool {
+	ok, _ := doublestar.Match(m.Pattern, name)
+	return ok
+}
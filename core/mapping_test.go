@@ -0,0 +1,54 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package core
+
+import "testing"
+
+func TestPathMappingValidate(t *testing.T) {
+	tests := []struct {
+		mapping *PathMapping
+		err     error
+	}{
+		{
+			mapping: &PathMapping{Pattern: "service-a/**", Pipeline: "service-a"},
+			err:     nil,
+		},
+		{
+			mapping: &PathMapping{Pattern: "", Pipeline: "service-a"},
+			err:     errPathMappingPatternInvalid,
+		},
+		{
+			mapping: &PathMapping{Pattern: "service-a/[", Pipeline: "service-a"},
+			err:     errPathMappingPatternInvalid,
+		},
+		{
+			mapping: &PathMapping{Pattern: "service-a/**", Pipeline: ""},
+			err:     errPathMappingPipelineInvalid,
+		},
+	}
+	for i, test := range tests {
+		if got, want := test.mapping.Validate(), test.err; got != want {
+			t.Errorf("Want error %v, got %v at index %d", want, got, i)
+		}
+	}
+}
+
+func TestPathMappingMatch(t *testing.T) {
+	mapping := &PathMapping{Pattern: "service-a/**"}
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"service-a/main.go", true},
+		{"service-a/cmd/server/main.go", true},
+		{"service-b/main.go", false},
+		{"README.md", false},
+	}
+	for _, test := range tests {
+		if got := mapping.Match(test.path); got != test.want {
+			t.Errorf("Want path %s match %v", test.path, test.want)
+		}
+	}
+}
@@ -27,6 +27,11 @@ type (
 		Machine  string `json:"machine"`
 		Login    string `json:"login"`
 		Password string `json:"password"`
+
+		// Expires is the unix timestamp at which the password
+		// expires. A zero value indicates the password does not
+		// expire, for example, a user oauth token.
+		Expires int64 `json:"expires,omitempty"`
 	}
 
 	// NetrcService returns a valid netrc file that can be used
@@ -0,0 +1,146 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"context"
+	"errors"
+	"net/mail"
+	"net/url"
+)
+
+// Notification service kinds.
+const (
+	NotifySlack   = "slack"
+	NotifyTeams   = "teams"
+	NotifyDiscord = "discord"
+	NotifyEmail   = "email"
+)
+
+// NotifyRecovered is a notification event that matches a
+// successful build when the previous build failed.
+const NotifyRecovered = "recovered"
+
+var (
+	errNotifyKindInvalid     = errors.New("Invalid Notification Kind")
+	errNotifyEndpointInvalid = errors.New("Invalid Notification Endpoint")
+	errNotifyEventInvalid    = errors.New("Invalid Notification Event")
+	errNotifyEmailInvalid    = errors.New("Invalid Notification Email Address")
+	errNotifyEmailRequired   = errors.New("Notification Email Recipients Required")
+)
+
+type (
+	// Notification defines a repository notification that is
+	// sent to a chat service when a build completes.
+	Notification struct {
+		ID         int64    `json:"id,omitempty"`
+		RepoID     int64    `json:"repo_id,omitempty"`
+		Kind       string   `json:"kind"`
+		Endpoint   string   `json:"endpoint,omitempty"`
+		Channel    string   `json:"channel,omitempty"`
+		Events     []string `json:"events,omitempty"`
+		Branches   []string `json:"branches,omitempty"`
+		Recipients []string `json:"recipients,omitempty"`
+		Author     bool     `json:"author,omitempty"`
+		Created    int64    `json:"created,omitempty"`
+		Updated    int64    `json:"updated,omitempty"`
+	}
+
+	// NotificationStore manages repository notifications.
+	NotificationStore interface {
+		// List returns a notification list from the datastore.
+		List(context.Context, int64) ([]*Notification, error)
+
+		// Find returns a notification from the datastore.
+		Find(context.Context, int64) (*Notification, error)
+
+		// Create persists a new notification to the datastore.
+		Create(context.Context, *Notification) error
+
+		// Update persists an updated notification to the
+		// datastore.
+		Update(context.Context, *Notification) error
+
+		// Delete deletes a notification from the datastore.
+		Delete(context.Context, *Notification) error
+
+		// Rotate encrypts all notifications in the datastore
+		// with the current encryption key, and returns the
+		// number of notifications that were encrypted.
+		Rotate(context.Context) (int64, error)
+	}
+
+	// NotifyArgs provides arguments for sending build
+	// notifications.
+	NotifyArgs struct {
+		Repo   *Repository
+		Build  *Build
+		Stages []*Stage
+	}
+
+	// NotificationService sends build notifications.
+	NotificationService interface {
+		// Notify sends the build notification to the
+		// repository notification services.
+		Notify(context.Context, *NotifyArgs) error
+	}
+)
+
+// Validate validates the required fields and formats.
+func (n *Notification) Validate() error {
+	switch n.Kind {
+	case NotifySlack, NotifyTeams, NotifyDiscord:
+		uri, err := url.Parse(n.Endpoint)
+		if err != nil || uri.Scheme != "https" || uri.Host == "" {
+			return errNotifyEndpointInvalid
+		}
+	case NotifyEmail:
+		if len(n.Recipients) == 0 && !n.Author {
+			return errNotifyEmailRequired
+		}
+		for _, recipient := range n.Recipients {
+			if _, err := mail.ParseAddress(recipient); err != nil {
+				return errNotifyEmailInvalid
+			}
+		}
+	default:
+		return errNotifyKindInvalid
+	}
+	for _, event := range n.Events {
+		switch event {
+		case StatusPassing, StatusFailing, StatusError, StatusKilled, NotifyRecovered:
+		default:
+			return errNotifyEventInvalid
+		}
+	}
+	return nil
+}
+
+// Copy makes a copy of the notification without the
+// endpoint, which contains the service credentials.
+func (n *Notification) Copy() *Notification {
+	return &Notification{
+		ID:         n.ID,
+		RepoID:     n.RepoID,
+		Kind:       n.Kind,
+		Channel:    n.Channel,
+		Events:     n.Events,
+		Branches:   n.Branches,
+		Recipients: n.Recipients,
+		Author:     n.Author,
+		Created:    n.Created,
+		Updated:    n.Updated,
+	}
+}
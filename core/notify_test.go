@@ -0,0 +1,61 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package core
+
+import "testing"
+
+func TestNotificationValidate(t *testing.T) {
+	tests := []struct {
+		notify *Notification
+		error  error
+	}{
+		{
+			notify: &Notification{Kind: NotifySlack, Endpoint: "https://hooks.slack.com/services/T0/B0/XXX"},
+			error:  nil,
+		},
+		{
+			notify: &Notification{Kind: NotifyDiscord, Endpoint: "https://discordapp.com/api/webhooks/1/XXX", Events: []string{StatusFailing, StatusError}},
+			error:  nil,
+		},
+		{
+			notify: &Notification{Kind: "irc", Endpoint: "https://company.com/hooks"},
+			error:  errNotifyKindInvalid,
+		},
+		{
+			notify: &Notification{Kind: NotifyTeams, Endpoint: "http://outlook.office.com/webhook/XXX"},
+			error:  errNotifyEndpointInvalid,
+		},
+		{
+			notify: &Notification{Kind: NotifyTeams, Endpoint: ""},
+			error:  errNotifyEndpointInvalid,
+		},
+		{
+			notify: &Notification{Kind: NotifySlack, Endpoint: "https://hooks.slack.com/services/T0/B0/XXX", Events: []string{StatusRunning}},
+			error:  errNotifyEventInvalid,
+		},
+		{
+			notify: &Notification{Kind: NotifyEmail, Recipients: []string{"team@company.com"}, Events: []string{StatusFailing, NotifyRecovered}},
+			error:  nil,
+		},
+		{
+			notify: &Notification{Kind: NotifyEmail, Author: true},
+			error:  nil,
+		},
+		{
+			notify: &Notification{Kind: NotifyEmail},
+			error:  errNotifyEmailRequired,
+		},
+		{
+			notify: &Notification{Kind: NotifyEmail, Recipients: []string{"company.com"}},
+			error:  errNotifyEmailInvalid,
+		},
+	}
+	for i, test := range tests {
+		got, want := test.notify.Validate(), test.error
+		if got != want {
+			t.Errorf("Want error %v, got %v at index %d", want, got, i)
+		}
+	}
+}
@@ -19,13 +19,18 @@ import "context"
 // Organization represents an organization in the source
 // code management system (e.g. GitHub).
 type Organization struct {
-	Name   string
-	Avatar string
+	Name   string `json:"name"`
+	Avatar string `json:"avatar"`
 }
 
 // OrganizationService provides access to organization and
 // team access in the external source code management system
 // (e.g. GitHub).
 type OrganizationService interface {
+	// List returns the organizations the user is a member of.
 	List(context.Context, *User) ([]*Organization, error)
+
+	// Refresh returns the organizations the user is a member
+	// of, bypassing and replacing any cached membership.
+	Refresh(context.Context, *User) ([]*Organization, error)
 }
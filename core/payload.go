@@ -0,0 +1,68 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"context"
+	"net/http"
+)
+
+// Hook payload states.
+const (
+	PayloadSuccess = "success"
+	PayloadIgnored = "ignored"
+	PayloadError   = "error"
+)
+
+type (
+	// HookPayload represents a raw post-commit hook payload
+	// received from the source code management system, and
+	// the result of processing the payload.
+	HookPayload struct {
+		ID      int64       `json:"id"`
+		RepoID  int64       `json:"repo_id"`
+		Event   string      `json:"event,omitempty"`
+		Action  string      `json:"action,omitempty"`
+		Ref     string      `json:"ref,omitempty"`
+		After   string      `json:"after,omitempty"`
+		Status  string      `json:"status"`
+		Message string      `json:"message,omitempty"`
+		Build   int64       `json:"build,omitempty"`
+		Header  http.Header `json:"header,omitempty"`
+		Body    string      `json:"body,omitempty"`
+		Created int64       `json:"created"`
+		Updated int64       `json:"updated"`
+	}
+
+	// HookPayloadStore persists hook payloads to storage.
+	HookPayloadStore interface {
+		// List returns a list of hook payloads from the
+		// datastore by repository id, ordered from newest
+		// to oldest.
+		List(context.Context, int64) ([]*HookPayload, error)
+
+		// Find returns a hook payload from the datastore.
+		Find(context.Context, int64) (*HookPayload, error)
+
+		// Create persists a new hook payload to the datastore,
+		// and purges the oldest payloads for the repository
+		// that exceed the retention limit.
+		Create(context.Context, *HookPayload) error
+
+		// Update persists an updated hook payload to the
+		// datastore.
+		Update(context.Context, *HookPayload) error
+	}
+)
@@ -16,34 +16,43 @@ package core
 
 import "context"
 
+// Repository roles.
+const (
+	RoleRead  = "read"
+	RoleWrite = "write"
+	RoleAdmin = "admin"
+)
+
 type (
 	// Perm represents an individuals repository
 	// permission.
 	Perm struct {
-		UserID  int64  `db:"perm_user_id"  json:"-"`
-		RepoUID string `db:"perm_repo_uid" json:"-"`
-		Read    bool   `db:"perm_read"     json:"read"`
-		Write   bool   `db:"perm_write"    json:"write"`
-		Admin   bool   `db:"perm_admin"    json:"admin"`
-		Synced  int64  `db:"perm_synced"   json:"-"`
-		Created int64  `db:"perm_created"  json:"-"`
-		Updated int64  `db:"perm_updated"  json:"-"`
+		UserID   int64  `db:"perm_user_id"  json:"-"`
+		RepoUID  string `db:"perm_repo_uid" json:"-"`
+		Read     bool   `db:"perm_read"     json:"read"`
+		Write    bool   `db:"perm_write"    json:"write"`
+		Admin    bool   `db:"perm_admin"    json:"admin"`
+		Override string `db:"perm_override" json:"override,omitempty"`
+		Synced   int64  `db:"perm_synced"   json:"-"`
+		Created  int64  `db:"perm_created"  json:"-"`
+		Updated  int64  `db:"perm_updated"  json:"-"`
 	}
 
 	// Collaborator represents a project collaborator,
 	// and provides the account and repository permissions
 	// details.
 	Collaborator struct {
-		UserID  int64  `db:"perm_user_id"  json:"user_id"`
-		RepoUID string `db:"perm_repo_uid" json:"repo_id"`
-		Login   string `db:"user_login"    json:"login"`
-		Avatar  string `db:"user_avatar"   json:"avatar"`
-		Read    bool   `db:"perm_read"     json:"read"`
-		Write   bool   `db:"perm_write"    json:"write"`
-		Admin   bool   `db:"perm_admin"    json:"admin"`
-		Synced  int64  `db:"perm_synced"   json:"synced"`
-		Created int64  `db:"perm_created"  json:"created"`
-		Updated int64  `db:"perm_updated"  json:"updated"`
+		UserID   int64  `db:"perm_user_id"  json:"user_id"`
+		RepoUID  string `db:"perm_repo_uid" json:"repo_id"`
+		Login    string `db:"user_login"    json:"login"`
+		Avatar   string `db:"user_avatar"   json:"avatar"`
+		Read     bool   `db:"perm_read"     json:"read"`
+		Write    bool   `db:"perm_write"    json:"write"`
+		Admin    bool   `db:"perm_admin"    json:"admin"`
+		Override string `db:"perm_override" json:"override,omitempty"`
+		Synced   int64  `db:"perm_synced"   json:"synced"`
+		Created  int64  `db:"perm_created"  json:"created"`
+		Updated  int64  `db:"perm_updated"  json:"updated"`
 	}
 
 	// PermStore defines operations for working with
@@ -57,6 +66,10 @@ type (
 		// datastore.
 		List(ctx context.Context, repoUID string) ([]*Collaborator, error)
 
+		// Create persists a new project member to
+		// the datastore.
+		Create(context.Context, *Perm) error
+
 		// Update persists an updated project member
 		// to the datastore.
 		Update(context.Context, *Perm) error
@@ -66,3 +79,38 @@ type (
 		Delete(context.Context, *Perm) error
 	}
 )
+
+// Role returns the repository role granted by the
+// permissions, or an empty string if no access is granted.
+func (p *Perm) Role() string {
+	switch {
+	case p.Admin:
+		return RoleAdmin
+	case p.Write:
+		return RoleWrite
+	case p.Read:
+		return RoleRead
+	default:
+		return ""
+	}
+}
+
+// Apply sets the permissions granted by the repository
+// role. Admin access implies write access, and write access
+// implies read access.
+func (p *Perm) Apply(role string) {
+	p.Admin = role == RoleAdmin
+	p.Write = p.Admin || role == RoleWrite
+	p.Read = p.Write || role == RoleRead
+}
+
+// ValidRole returns true if the role is a known
+// repository role.
+func ValidRole(role string) bool {
+	switch role {
+	case RoleRead, RoleWrite, RoleAdmin:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,46 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package core
+
+import (
+	"testing"
+)
+
+func TestPermRole(t *testing.T) {
+	tests := []struct {
+		role  string
+		read  bool
+		write bool
+		admin bool
+	}{
+		{role: RoleAdmin, read: true, write: true, admin: true},
+		{role: RoleWrite, read: true, write: true},
+		{role: RoleRead, read: true},
+		{role: ""},
+	}
+	for i, test := range tests {
+		perm := &Perm{Read: true, Write: true, Admin: true}
+		perm.Apply(test.role)
+		if perm.Read != test.read || perm.Write != test.write || perm.Admin != test.admin {
+			t.Errorf("Unexpected permissions for role %q at index %d", test.role, i)
+		}
+		if got, want := perm.Role(), test.role; got != want {
+			t.Errorf("Want role %q, got %q at index %d", want, got, i)
+		}
+	}
+}
+
+func TestValidRole(t *testing.T) {
+	for _, role := range []string{RoleRead, RoleWrite, RoleAdmin} {
+		if !ValidRole(role) {
+			t.Errorf("Expect role %q is valid", role)
+		}
+	}
+	for _, role := range []string{"", "owner", "ADMIN"} {
+		if ValidRole(role) {
+			t.Errorf("Expect role %q is invalid", role)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"context"
+	"errors"
+	"strings"
+)
+
+// ErrPlatformUnavailable is returned when a pipeline targets
+// a platform that no connected agent can serve.
+var ErrPlatformUnavailable = errors.New("No agent available for the platform")
+
+type (
+	// Platform represents the platform and labels targeted by
+	// a pipeline.
+	Platform struct {
+		OS      string            `json:"os"`
+		Arch    string            `json:"arch"`
+		Variant string            `json:"variant,omitempty"`
+		Kernel  string            `json:"kernel,omitempty"`
+		Labels  map[string]string `json:"labels,omitempty"`
+	}
+
+	// PlatformService validates that a pipeline platform can
+	// be served by the build agents.
+	PlatformService interface {
+		// Validate returns an error if the platform cannot be
+		// served by the build agents.
+		Validate(context.Context, *Platform) error
+	}
+)
+
+// String returns the platform in os/arch/variant format.
+func (p *Platform) String() string {
+	parts := []string{p.OS, p.Arch}
+	if p.Variant != "" {
+		parts = append(parts, p.Variant)
+	}
+	return strings.Join(parts, "/")
+}
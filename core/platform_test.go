@@ -0,0 +1,32 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package core
+
+import "testing"
+
+func TestPlatformString(t *testing.T) {
+	tests := []struct {
+		platform *Platform
+		want     string
+	}{
+		{
+			platform: &Platform{OS: "linux", Arch: "amd64"},
+			want:     "linux/amd64",
+		},
+		{
+			platform: &Platform{OS: "linux", Arch: "arm", Variant: "v7"},
+			want:     "linux/arm/v7",
+		},
+		{
+			platform: &Platform{OS: "windows", Arch: "amd64", Kernel: "1809"},
+			want:     "windows/amd64",
+		},
+	}
+	for i, test := range tests {
+		if got, want := test.platform.String(), test.want; got != want {
+			t.Errorf("Want platform %q, got %q at index %d", want, got, i)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"context"
+	"errors"
+)
+
+var (
+	errPolicyNamespaceInvalid  = errors.New("Invalid Policy Namespace")
+	errPolicyCloneDepthInvalid = errors.New("Invalid Policy Clone Depth")
+)
+
+type (
+	// Policy defines the rules that pipelines in an
+	// organization must satisfy. Builds with pipelines that
+	// violate the policy are not executed.
+	Policy struct {
+		ID        int64  `json:"id"`
+		Namespace string `json:"namespace"`
+
+		// AllowRegistries restricts pipeline images to the
+		// listed registry hosts. Images from any registry
+		// are allowed if the list is empty.
+		AllowRegistries []string `json:"allow_registries"`
+
+		// DenyImages lists image name patterns that cannot
+		// be used by pipeline steps or services.
+		DenyImages []string `json:"deny_images"`
+
+		// DenyPrivileged prevents pipeline steps and services
+		// from running in privileged mode.
+		DenyPrivileged bool `json:"deny_privileged"`
+
+		// CloneDepth requires pipelines to clone with a depth
+		// no greater than the configured value.
+		CloneDepth int `json:"clone_depth"`
+
+		Created int64 `json:"created"`
+		Updated int64 `json:"updated"`
+	}
+
+	// PolicyStore persists organization policies to storage.
+	PolicyStore interface {
+		// List returns a list of policies from the datastore.
+		List(context.Context) ([]*Policy, error)
+
+		// Find returns the organization policy from the
+		// datastore.
+		Find(context.Context, string) (*Policy, error)
+
+		// Create persists a new policy to the datastore.
+		Create(context.Context, *Policy) error
+
+		// Update persists an updated policy to the datastore.
+		Update(context.Context, *Policy) error
+
+		// Delete deletes a policy from the datastore.
+		Delete(context.Context, *Policy) error
+	}
+)
+
+// Validate validates the required fields and formats.
+func (p *Policy) Validate() error {
+	switch {
+	case len(p.Namespace) == 0:
+		return errPolicyNamespaceInvalid
+	case p.CloneDepth < 0:
+		return errPolicyCloneDepthInvalid
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,33 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package core
+
+import "testing"
+
+func TestPolicyValidate(t *testing.T) {
+	tests := []struct {
+		policy *Policy
+		error  error
+	}{
+		{
+			policy: &Policy{Namespace: "octocat", CloneDepth: 50},
+			error:  nil,
+		},
+		{
+			policy: &Policy{Namespace: ""},
+			error:  errPolicyNamespaceInvalid,
+		},
+		{
+			policy: &Policy{Namespace: "octocat", CloneDepth: -1},
+			error:  errPolicyCloneDepthInvalid,
+		},
+	}
+	for i, test := range tests {
+		got, want := test.policy.Validate(), test.error
+		if got != want {
+			t.Errorf("Want error %v, got %v at index %d", want, got, i)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrPoolNotFound is returned when a pipeline requests a
+// runner pool that is not defined.
+var ErrPoolNotFound = errors.New("Runner pool not found")
+
+type (
+	// Pool represents a named set of runner labels. Pipelines
+	// request a pool by name instead of listing the labels.
+	Pool struct {
+		Name   string            `json:"name"`
+		Labels map[string]string `json:"labels"`
+	}
+
+	// PoolService provides named runner pools.
+	PoolService interface {
+		// Find returns the named runner pool.
+		Find(context.Context, string) (*Pool, error)
+	}
+)
@@ -0,0 +1,66 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"context"
+	"errors"
+)
+
+var errPrivilegedImageInvalid = errors.New("Invalid Privileged Image")
+
+type (
+	// PrivilegedImage represents an image that is allowed to
+	// run in privileged mode, for example docker-in-docker
+	// plugins. Images with an empty namespace are allowed for
+	// all organizations.
+	PrivilegedImage struct {
+		ID        int64  `json:"id"`
+		Namespace string `json:"namespace,omitempty"`
+		Image     string `json:"image"`
+		Created   int64  `json:"created"`
+	}
+
+	// PrivilegedImageStore manages the privileged image
+	// whitelist in storage.
+	PrivilegedImageStore interface {
+		// List returns the privileged image whitelist from
+		// the datastore.
+		List(context.Context) ([]*PrivilegedImage, error)
+
+		// ListNamespace returns the privileged images stored
+		// for the organization. The global whitelist is
+		// returned for an empty namespace.
+		ListNamespace(context.Context, string) ([]*PrivilegedImage, error)
+
+		// Find returns a privileged image from the datastore.
+		Find(context.Context, int64) (*PrivilegedImage, error)
+
+		// Create persists a new privileged image to the
+		// datastore.
+		Create(context.Context, *PrivilegedImage) error
+
+		// Delete deletes a privileged image from the datastore.
+		Delete(context.Context, *PrivilegedImage) error
+	}
+)
+
+// Validate validates the required fields and formats.
+func (p *PrivilegedImage) Validate() error {
+	if len(p.Image) == 0 {
+		return errPrivilegedImageInvalid
+	}
+	return nil
+}
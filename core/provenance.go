@@ -0,0 +1,55 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import "context"
+
+type (
+	// ProvenanceArgs provides the build details used to
+	// generate a provenance attestation.
+	ProvenanceArgs struct {
+		Repo   *Repository
+		Build  *Build
+		Stages []*Stage
+	}
+
+	// Envelope represents a signed attestation encoded as a
+	// Dead Simple Signing Envelope (DSSE).
+	Envelope struct {
+		PayloadType string               `json:"payloadType"`
+		Payload     string               `json:"payload"`
+		Signatures  []*EnvelopeSignature `json:"signatures"`
+	}
+
+	// EnvelopeSignature represents an envelope signature.
+	EnvelopeSignature struct {
+		KeyID string `json:"keyid"`
+		Sig   string `json:"sig"`
+	}
+
+	// ProvenanceService generates signed SLSA provenance
+	// attestations describing how a build was produced, so
+	// that the build artifacts can be verified.
+	ProvenanceService interface {
+		// Generate returns a signed provenance attestation
+		// for the build.
+		Generate(ctx context.Context, args *ProvenanceArgs) (*Envelope, error)
+
+		// Publish generates a signed provenance attestation
+		// for the build and pushes the attestation to the
+		// attestation store, if configured.
+		Publish(ctx context.Context, args *ProvenanceArgs) error
+	}
+)
@@ -0,0 +1,72 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"context"
+	"strings"
+)
+
+// ProviderSeparator separates the provider name from the
+// namespace of a repository hosted by an additional source
+// code management provider, for example internal~octocat.
+const ProviderSeparator = "~"
+
+// ProviderService provides access to the additional source
+// code management providers configured for the server.
+type ProviderService interface {
+	// List returns the names of the additional providers.
+	List() []string
+
+	// Find returns the named repository from the provider.
+	// The repository namespace is qualified with the
+	// provider name.
+	Find(ctx context.Context, provider, slug string) (*Repository, error)
+}
+
+// QualifyProvider returns the namespace, slug or uid qualified
+// with the provider name. If the provider name is empty, the
+// value is returned unchanged.
+func QualifyProvider(provider, s string) string {
+	if provider == "" || s == "" {
+		return s
+	}
+	return provider + ProviderSeparator + s
+}
+
+// SplitProvider splits the provider name from a qualified
+// namespace, slug or uid. If the value is not qualified, the
+// provider name is empty.
+func SplitProvider(s string) (provider, rest string) {
+	parts := strings.SplitN(s, ProviderSeparator, 2)
+	if len(parts) != 2 {
+		return "", s
+	}
+	return parts[0], parts[1]
+}
+
+// Remote returns a copy of the repository with the provider
+// qualifier removed from the uid, namespace and slug, for use
+// with the source code management provider.
+func (r *Repository) Remote() *Repository {
+	if r.Provider == "" {
+		return r
+	}
+	remote := *r
+	_, remote.UID = SplitProvider(r.UID)
+	_, remote.Namespace = SplitProvider(r.Namespace)
+	_, remote.Slug = SplitProvider(r.Slug)
+	return &remote
+}
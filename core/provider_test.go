@@ -0,0 +1,63 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package core
+
+import "testing"
+
+func TestQualifyProvider(t *testing.T) {
+	if got, want := QualifyProvider("internal", "octocat/hello-world"), "internal~octocat/hello-world"; got != want {
+		t.Errorf("Want qualified slug %q, got %q", want, got)
+	}
+	if got, want := QualifyProvider("", "octocat/hello-world"), "octocat/hello-world"; got != want {
+		t.Errorf("Want unqualified slug %q, got %q", want, got)
+	}
+}
+
+func TestSplitProvider(t *testing.T) {
+	tests := []struct {
+		value    string
+		provider string
+		rest     string
+	}{
+		{"internal~octocat/hello-world", "internal", "octocat/hello-world"},
+		{"octocat/hello-world", "", "octocat/hello-world"},
+		{"internal~42", "internal", "42"},
+	}
+	for _, test := range tests {
+		provider, rest := SplitProvider(test.value)
+		if provider != test.provider || rest != test.rest {
+			t.Errorf("Want %q split into %q and %q, got %q and %q",
+				test.value, test.provider, test.rest, provider, rest)
+		}
+	}
+}
+
+func TestRepositoryRemote(t *testing.T) {
+	repo := &Repository{
+		UID:       "internal~42",
+		Namespace: "internal~octocat",
+		Name:      "hello-world",
+		Slug:      "internal~octocat/hello-world",
+		Provider:  "internal",
+	}
+	remote := repo.Remote()
+	if got, want := remote.Slug, "octocat/hello-world"; got != want {
+		t.Errorf("Want remote slug %q, got %q", want, got)
+	}
+	if got, want := remote.Namespace, "octocat"; got != want {
+		t.Errorf("Want remote namespace %q, got %q", want, got)
+	}
+	if got, want := remote.UID, "42"; got != want {
+		t.Errorf("Want remote uid %q, got %q", want, got)
+	}
+	if got, want := repo.Slug, "internal~octocat/hello-world"; got != want {
+		t.Errorf("Want repository unchanged, got slug %q", got)
+	}
+
+	repo = &Repository{Slug: "octocat/hello-world"}
+	if repo.Remote() != repo {
+		t.Errorf("Want default provider repository returned unchanged")
+	}
+}
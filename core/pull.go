@@ -0,0 +1,36 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import "context"
+
+type (
+	// PullRequest represents a pull request.
+	PullRequest struct {
+		Number int
+		Title  string
+		Labels []string
+	}
+
+	// PullRequestService provides access to pull requests from
+	// the external source code management service (e.g. GitHub).
+	PullRequestService interface {
+		// Find returns the pull request by number.
+		Find(ctx context.Context, user *User, repo string, number int) (*PullRequest, error)
+
+		// ListChanges returns the files changed by the pull request.
+		ListChanges(ctx context.Context, user *User, repo string, number int) ([]*Change, error)
+	}
+)
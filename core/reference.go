@@ -0,0 +1,37 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import "context"
+
+type (
+	// Reference represents a git branch or tag.
+	Reference struct {
+		Name string `json:"name"`
+		Path string `json:"path"`
+		Sha  string `json:"sha"`
+	}
+
+	// ReferenceService provides access to the git branches and
+	// tags from the external source code management service
+	// (e.g. GitHub).
+	ReferenceService interface {
+		// ListBranches returns the repository branches.
+		ListBranches(ctx context.Context, user *User, repo string) ([]*Reference, error)
+
+		// ListTags returns the repository tags.
+		ListTags(ctx context.Context, user *User, repo string) ([]*Reference, error)
+	}
+)
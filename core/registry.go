@@ -16,10 +16,18 @@ package core
 
 import (
 	"context"
+	"errors"
 
 	"github.com/drone/drone-yaml/yaml"
 )
 
+var (
+	errRegistryAddressInvalid  = errors.New("Invalid Registry Address")
+	errRegistryUsernameInvalid = errors.New("Invalid Registry Username")
+	errRegistryPasswordInvalid = errors.New("Invalid Registry Password")
+	errRegistryPolicyInvalid   = errors.New("Invalid Registry Policy")
+)
+
 const (
 	// RegistryPull policy allows pulling from a registry.
 	RegistryPull = "pull"
@@ -36,10 +44,15 @@ const (
 type (
 	// Registry represents a docker registry with credentials.
 	Registry struct {
-		Address  string `json:"address"`
-		Username string `json:"username"`
-		Password string `json:"password"`
-		Policy   string `json:"policy"`
+		ID        int64  `json:"id,omitempty"`
+		RepoID    int64  `json:"repo_id,omitempty"`
+		Namespace string `json:"namespace,omitempty"`
+		Address   string `json:"address"`
+		Username  string `json:"username"`
+		Password  string `json:"password,omitempty"`
+		Policy    string `json:"policy"`
+		Created   int64  `json:"created,omitempty"`
+		Updated   int64  `json:"updated,omitempty"`
 	}
 
 	// RegistryArgs provides arguments for requesting
@@ -50,6 +63,37 @@ type (
 		Conf  *yaml.Manifest `json:"-"`
 	}
 
+	// RegistryStore manages registry credentials stored for a
+	// repository or an organization.
+	RegistryStore interface {
+		// List returns the registry credentials stored for
+		// the repository.
+		List(context.Context, int64) ([]*Registry, error)
+
+		// ListNamespace returns the registry credentials stored
+		// for the organization.
+		ListNamespace(context.Context, string) ([]*Registry, error)
+
+		// Find returns registry credentials from the datastore.
+		Find(context.Context, int64) (*Registry, error)
+
+		// Create persists new registry credentials to the
+		// datastore.
+		Create(context.Context, *Registry) error
+
+		// Update persists updated registry credentials to the
+		// datastore.
+		Update(context.Context, *Registry) error
+
+		// Delete deletes registry credentials from the datastore.
+		Delete(context.Context, *Registry) error
+
+		// Rotate encrypts all registry credentials in the
+		// datastore with the current encryption key, and returns
+		// the number of registry credentials that were encrypted.
+		Rotate(context.Context) (int64, error)
+	}
+
 	// RegistryService provides registry credentials from an
 	// external service.
 	RegistryService interface {
@@ -58,3 +102,35 @@ type (
 		List(context.Context, *RegistryArgs) ([]*Registry, error)
 	}
 )
+
+// Validate validates the required fields and formats.
+func (r *Registry) Validate() error {
+	switch {
+	case len(r.Address) == 0:
+		return errRegistryAddressInvalid
+	case len(r.Username) == 0:
+		return errRegistryUsernameInvalid
+	case len(r.Password) == 0:
+		return errRegistryPasswordInvalid
+	}
+	switch r.Policy {
+	case RegistryPull, RegistryPush, RegistryPushPullRequest:
+		return nil
+	default:
+		return errRegistryPolicyInvalid
+	}
+}
+
+// Copy makes a copy of the registry without the password.
+func (r *Registry) Copy() *Registry {
+	return &Registry{
+		ID:        r.ID,
+		RepoID:    r.RepoID,
+		Namespace: r.Namespace,
+		Address:   r.Address,
+		Username:  r.Username,
+		Policy:    r.Policy,
+		Created:   r.Created,
+		Updated:   r.Updated,
+	}
+}
@@ -0,0 +1,62 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package core
+
+import "testing"
+
+func TestRegistryValidate(t *testing.T) {
+	tests := []struct {
+		registry *Registry
+		error    error
+	}{
+		{
+			registry: &Registry{Address: "docker.io", Username: "octocat", Password: "correct-horse-battery-staple", Policy: RegistryPull},
+			error:    nil,
+		},
+		{
+			registry: &Registry{Address: "", Username: "octocat", Password: "correct-horse-battery-staple", Policy: RegistryPull},
+			error:    errRegistryAddressInvalid,
+		},
+		{
+			registry: &Registry{Address: "docker.io", Username: "", Password: "correct-horse-battery-staple", Policy: RegistryPull},
+			error:    errRegistryUsernameInvalid,
+		},
+		{
+			registry: &Registry{Address: "docker.io", Username: "octocat", Password: "", Policy: RegistryPull},
+			error:    errRegistryPasswordInvalid,
+		},
+		{
+			registry: &Registry{Address: "docker.io", Username: "octocat", Password: "correct-horse-battery-staple", Policy: "pull-all"},
+			error:    errRegistryPolicyInvalid,
+		},
+	}
+	for i, test := range tests {
+		got, want := test.registry.Validate(), test.error
+		if got != want {
+			t.Errorf("Want error %v, got %v at index %d", want, got, i)
+		}
+	}
+}
+
+func TestRegistrySafeCopy(t *testing.T) {
+	before := Registry{
+		ID:       1,
+		RepoID:   2,
+		Address:  "docker.io",
+		Username: "octocat",
+		Password: "correct-horse-battery-staple",
+		Policy:   RegistryPull,
+	}
+	after := before.Copy()
+	if got, want := after.Address, before.Address; got != want {
+		t.Errorf("Want registry Address %s, got %s", want, got)
+	}
+	if got, want := after.Username, before.Username; got != want {
+		t.Errorf("Want registry Username %s, got %s", want, got)
+	}
+	if after.Password != "" {
+		t.Errorf("Expect registry Password removed from the copy")
+	}
+}
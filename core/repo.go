@@ -32,35 +32,72 @@ const (
 type (
 	// Repository represents a source code repository.
 	Repository struct {
-		ID          int64  `json:"id"`
-		UID         string `json:"uid"`
-		UserID      int64  `json:"user_id"`
-		Namespace   string `json:"namespace"`
-		Name        string `json:"name"`
-		Slug        string `json:"slug"`
-		SCM         string `json:"scm"`
-		HTTPURL     string `json:"git_http_url"`
-		SSHURL      string `json:"git_ssh_url"`
-		Link        string `json:"link"`
-		Branch      string `json:"default_branch"`
-		Private     bool   `json:"private"`
-		Visibility  string `json:"visibility"`
-		Active      bool   `json:"active"`
-		Config      string `json:"config_path"`
-		Trusted     bool   `json:"trusted"`
-		Protected   bool   `json:"protected"`
-		IgnoreForks bool   `json:"ignore_forks"`
-		IgnorePulls bool   `json:"ignore_pull_requests"`
-		Timeout     int64  `json:"timeout"`
-		Counter     int64  `json:"counter"`
-		Synced      int64  `json:"synced"`
-		Created     int64  `json:"created"`
-		Updated     int64  `json:"updated"`
-		Version     int64  `json:"version"`
-		Signer      string `json:"-"`
-		Secret      string `json:"-"`
-		Build       *Build `json:"build,omitempty"`
-		Perms       *Perm  `json:"permissions,omitempty"`
+		ID           int64  `json:"id"`
+		UID          string `json:"uid"`
+		UserID       int64  `json:"user_id"`
+		Namespace    string `json:"namespace"`
+		Name         string `json:"name"`
+		Slug         string `json:"slug"`
+		SCM          string `json:"scm"`
+		HTTPURL      string `json:"git_http_url"`
+		SSHURL       string `json:"git_ssh_url"`
+		Link         string `json:"link"`
+		Branch       string `json:"default_branch"`
+		Private      bool   `json:"private"`
+		Visibility   string `json:"visibility"`
+		Active       bool   `json:"active"`
+		Config       string `json:"config_path"`
+		Trusted      bool   `json:"trusted"`
+		Protected    bool   `json:"protected"`
+		IgnoreForks  bool   `json:"ignore_forks"`
+		IgnorePulls  bool   `json:"ignore_pull_requests"`
+		MergePulls   bool   `json:"merge_pull_requests"`
+		IgnoreDupes  bool   `json:"ignore_duplicates"`
+		NoMaskLogs   bool   `json:"no_mask_logs"`
+		SkipPattern  string `json:"skip_pattern"`
+		Summary      bool   `json:"summary"`
+		SummaryLines int64  `json:"summary_lines"`
+		Provider     string `json:"provider,omitempty"`
+		Timeout      int64  `json:"timeout"`
+		Throttle     int64  `json:"throttle"`
+		PollInterval int64  `json:"poll_interval"`
+		PollBranches string `json:"poll_branches"`
+		Polled       int64  `json:"polled"`
+		Counter      int64  `json:"counter"`
+		Synced       int64  `json:"synced"`
+		Created      int64  `json:"created"`
+		Updated      int64  `json:"updated"`
+		Version      int64  `json:"version"`
+		Signer       string `json:"-"`
+		SignerPrev   string `json:"-"`
+		SignerExpiry int64  `json:"signer_expiry,omitempty"`
+		Secret       string `json:"-"`
+		Build        *Build `json:"build,omitempty"`
+		Perms        *Perm  `json:"permissions,omitempty"`
+	}
+
+	// RepositoryFilter provides repository filter parameters.
+	// Zero value fields are ignored.
+	RepositoryFilter struct {
+		Query     string
+		Namespace string
+		Active    bool
+		Limit     int
+		Offset    int
+	}
+
+	// BuildSearch provides build search parameters. Zero
+	// value fields are ignored. If the user is non-zero, the
+	// search is limited to repositories the user can access.
+	BuildSearch struct {
+		User      int64
+		Namespace string
+		Commit    string
+		Author    string
+		Branch    string
+		Message   string
+		Limit     int
+		Offset    int
 	}
 
 	// RepositoryStore defines operations for working with repositories.
@@ -68,6 +105,14 @@ type (
 		// List returns a repository list from the datastore.
 		List(context.Context, int64) ([]*Repository, error)
 
+		// ListFilter returns a repository list from the datastore
+		// that match the filter.
+		ListFilter(context.Context, int64, RepositoryFilter) ([]*Repository, error)
+
+		// ListAll returns a paginated list of all repositories
+		// in the datastore, ordered by identifier.
+		ListAll(context.Context, int, int) ([]*Repository, error)
+
 		// ListLatest returns a unique repository list form
 		// the datastore with the most recent build.
 		ListLatest(context.Context, int64) ([]*Repository, error)
@@ -80,6 +125,14 @@ type (
 		// the datastore with incmoplete builds.
 		ListIncomplete(context.Context) ([]*Repository, error)
 
+		// ListPolling returns a list of active repositories with
+		// polling enabled that are due to be polled.
+		ListPolling(context.Context, int64) ([]*Repository, error)
+
+		// ListSearch returns a non-unique repository list form
+		// the datastore with builds matching the search.
+		ListSearch(context.Context, BuildSearch) ([]*Repository, error)
+
 		// Find returns a repository from the datastore.
 		Find(context.Context, int64) (*Repository, error)
 
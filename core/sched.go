@@ -47,4 +47,24 @@ type Scheduler interface {
 	// Stats provides statistics for underlying scheduler. The
 	// data format is scheduler-specific.
 	Stats(context.Context) (interface{}, error)
+
+	// Pause pauses the scheduler and prevents new pipelines
+	// from being scheduled for execution.
+	Pause(context.Context) error
+
+	// Paused returns true if the scheduler is paused.
+	Paused(context.Context) (bool, error)
+
+	// Resume unpauses the scheduler, allowing new pipelines
+	// to be scheduled for execution.
+	Resume(context.Context) error
+
+	// Shutdown stops the scheduler from dispatching stages.
+	// Stages that have not been accepted remain pending.
+	Shutdown(context.Context) error
+
+	// Signal signals the scheduler to dispatch pending stages
+	// immediately, for example when a stage completes and a
+	// concurrency-limited stage may be eligible to run.
+	Signal(context.Context) error
 }
@@ -67,6 +67,42 @@ type (
 
 		// Delete deletes a secret from the datastore.
 		Delete(context.Context, *Secret) error
+
+		// Rotate encrypts all secrets in the datastore with the
+		// current encryption key, and returns the number of
+		// secrets that were encrypted.
+		Rotate(context.Context) (int64, error)
+	}
+
+	// SecretAccess represents a record of a secret provided
+	// to a build stage for execution.
+	SecretAccess struct {
+		ID          int64 `json:"id"`
+		SecretID    int64 `json:"secret_id"`
+		RepoID      int64 `json:"repo_id"`
+		BuildID     int64 `json:"build_id"`
+		BuildNumber int64 `json:"build_number"`
+		StageID     int64 `json:"stage_id"`
+		Created     int64 `json:"created"`
+	}
+
+	// SecretAccessStore persists secret access records to
+	// storage.
+	SecretAccessStore interface {
+		// List returns a list of access records for the
+		// secret, ordered from newest to oldest.
+		List(context.Context, int64) ([]*SecretAccess, error)
+
+		// Create persists a new access record to the datastore.
+		Create(context.Context, *SecretAccess) error
+	}
+
+	// SecretFileService provides secrets decrypted from an
+	// encrypted secrets file committed to the repository.
+	SecretFileService interface {
+		// List returns the secrets decrypted from the secrets
+		// file at the build commit.
+		List(ctx context.Context, user *User, repo *Repository, build *Build) ([]*Secret, error)
 	}
 
 	// SecretService provides secrets from an external service.
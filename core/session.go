@@ -14,14 +14,18 @@
 
 package core
 
-import "net/http"
+import (
+	"context"
+	"net/http"
+	"time"
+)
 
 // Session provides session management for
 // authenticated users.
 type Session interface {
-	// Create creates a new user session and writes the
-	// session to the http.Response.
-	Create(http.ResponseWriter, *User) error
+	// Create creates a new user session for the http.Request
+	// and writes the session to the http.Response.
+	Create(http.ResponseWriter, *http.Request, *User) error
 
 	// Delete deletes the user session from the http.Response.
 	Delete(http.ResponseWriter) error
@@ -31,3 +35,53 @@ type Session interface {
 	// error is optional, for debugging purposes only.
 	Get(*http.Request) (*User, error)
 }
+
+type (
+	// UserSession represents an active browser session. The
+	// session is valid until it expires or is revoked.
+	UserSession struct {
+		ID       int64  `json:"id"`
+		UserID   int64  `json:"user_id"`
+		Hash     string `json:"-"`
+		Address  string `json:"address"`
+		Agent    string `json:"agent"`
+		Created  int64  `json:"created"`
+		Accessed int64  `json:"accessed"`
+		Expires  int64  `json:"expires"`
+	}
+
+	// UserSessionStore persists user sessions to storage.
+	UserSessionStore interface {
+		// List returns a list of sessions for the user ID
+		// from the datastore.
+		List(ctx context.Context, user int64) ([]*UserSession, error)
+
+		// Find returns a session from the datastore.
+		Find(ctx context.Context, id int64) (*UserSession, error)
+
+		// FindHash returns a session from the datastore by hash.
+		FindHash(ctx context.Context, hash string) (*UserSession, error)
+
+		// Create persists a new session to the datastore.
+		Create(ctx context.Context, session *UserSession) error
+
+		// Touch updates the session last accessed time.
+		Touch(ctx context.Context, session *UserSession) error
+
+		// Delete deletes a session from the datastore.
+		Delete(ctx context.Context, session *UserSession) error
+
+		// DeleteUser deletes all sessions for the user ID
+		// from the datastore.
+		DeleteUser(ctx context.Context, user int64) error
+
+		// DeleteExpired deletes all sessions that expired
+		// before the given unix timestamp.
+		DeleteExpired(ctx context.Context, before int64) error
+	}
+)
+
+// Expired returns true if the session is expired.
+func (s *UserSession) Expired() bool {
+	return s.Expires != 0 && time.Now().Unix() > s.Expires
+}
@@ -19,33 +19,42 @@ import "context"
 type (
 	// Stage represents a stage of build execution.
 	Stage struct {
-		ID        int64             `json:"id"`
-		RepoID    int64             `json:"repo_id"`
-		BuildID   int64             `json:"build_id"`
-		Number    int               `json:"number"`
-		Name      string            `json:"name"`
-		Kind      string            `json:"kind,omitempty"`
-		Type      string            `json:"type,omitempty"`
-		Status    string            `json:"status"`
-		Error     string            `json:"error,omitempty"`
-		ErrIgnore bool              `json:"errignore"`
-		ExitCode  int               `json:"exit_code"`
-		Machine   string            `json:"machine,omitempty"`
-		OS        string            `json:"os"`
-		Arch      string            `json:"arch"`
-		Variant   string            `json:"variant,omitempty"`
-		Kernel    string            `json:"kernel,omitempty"`
-		Limit     int               `json:"limit,omitempty"`
-		Started   int64             `json:"started"`
-		Stopped   int64             `json:"stopped"`
-		Created   int64             `json:"created"`
-		Updated   int64             `json:"updated"`
-		Version   int64             `json:"version"`
-		OnSuccess bool              `json:"on_success"`
-		OnFailure bool              `json:"on_failure"`
-		DependsOn []string          `json:"depends_on,omitempty"`
-		Labels    map[string]string `json:"labels,omitempty"`
-		Steps     []*Step           `json:"steps,omitempty"`
+		ID         int64             `json:"id"`
+		RepoID     int64             `json:"repo_id"`
+		BuildID    int64             `json:"build_id"`
+		Number     int               `json:"number"`
+		Name       string            `json:"name"`
+		Kind       string            `json:"kind,omitempty"`
+		Type       string            `json:"type,omitempty"`
+		Status     string            `json:"status"`
+		Error      string            `json:"error,omitempty"`
+		ErrIgnore  bool              `json:"errignore"`
+		ExitCode   int               `json:"exit_code"`
+		Machine    string            `json:"machine,omitempty"`
+		OS         string            `json:"os"`
+		Arch       string            `json:"arch"`
+		Variant    string            `json:"variant,omitempty"`
+		Kernel     string            `json:"kernel,omitempty"`
+		Limit      int               `json:"limit,omitempty"`
+		LimitRepo  int               `json:"throttle,omitempty"`
+		Timeout    int64             `json:"timeout,omitempty"`
+		Queued     int64             `json:"queued,omitempty"`
+		Dispatched int64             `json:"dispatched,omitempty"`
+		Accepted   int64             `json:"accepted,omitempty"`
+		Started    int64             `json:"started"`
+		Stopped    int64             `json:"stopped"`
+		Created    int64             `json:"created"`
+		Updated    int64             `json:"updated"`
+		Version    int64             `json:"version"`
+		OnSuccess  bool              `json:"on_success"`
+		OnFailure  bool              `json:"on_failure"`
+		DependsOn  []string          `json:"depends_on,omitempty"`
+		Labels     map[string]string `json:"labels,omitempty"`
+		Matrix     map[string]string `json:"matrix,omitempty"`
+		Attempts   int               `json:"attempts,omitempty"`
+		Elevated   bool              `json:"elevated,omitempty"`
+		Abandoned  bool              `json:"abandoned,omitempty"`
+		Steps      []*Step           `json:"steps,omitempty"`
 	}
 
 	// StageStore persists build stage information to storage.
@@ -103,3 +112,14 @@ func (s *Stage) IsFailed() bool {
 		return false
 	}
 }
+
+// QueueTime returns the number of seconds the stage waited in
+// the queue, from the time the stage became eligible for
+// execution until the stage was accepted by an agent. Zero is
+// returned if the stage has not been accepted.
+func (s *Stage) QueueTime() int64 {
+	if s.Queued == 0 || s.Accepted < s.Queued {
+		return 0
+	}
+	return s.Accepted - s.Queued
+}
@@ -69,3 +69,19 @@ func TestStageIsFailed(t *testing.T) {
 		}
 	}
 }
+
+func TestStageQueueTime(t *testing.T) {
+	tests := []struct {
+		stage Stage
+		want  int64
+	}{
+		{Stage{Queued: 100, Accepted: 145}, 45},
+		{Stage{Queued: 100}, 0},
+		{Stage{Accepted: 145}, 0},
+	}
+	for _, test := range tests {
+		if got, want := test.stage.QueueTime(), test.want; got != want {
+			t.Errorf("Want queue time %d, got %d", want, got)
+		}
+	}
+}
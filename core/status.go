@@ -44,6 +44,11 @@ type (
 	StatusInput struct {
 		Repo  *Repository
 		Build *Build
+
+		// Stage is set when the status update is triggered
+		// by a single stage transition. Services that report
+		// status at the build level should ignore these.
+		Stage *Stage
 	}
 
 	// StatusService sends the commit status to an external
@@ -27,6 +27,8 @@ type (
 		Error     string `json:"error,omitempty"`
 		ErrIgnore bool   `json:"errignore,omitempty"`
 		ExitCode  int    `json:"exit_code"`
+		Attempts  int    `json:"attempts,omitempty"`
+		Digest    string `json:"digest,omitempty"`
 		Started   int64  `json:"started,omitempty"`
 		Stopped   int64  `json:"stopped,omitempty"`
 		Version   int64  `json:"version"`
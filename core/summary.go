@@ -0,0 +1,25 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import "context"
+
+// SummaryService posts a pull request comment summarizing
+// the failed stages and steps of a pull request build.
+type SummaryService interface {
+	// Send creates or replaces the build summary comment
+	// for the pull request.
+	Send(ctx context.Context, user *User, args *NotifyArgs) error
+}
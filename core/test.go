@@ -0,0 +1,58 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import "context"
+
+type (
+	// TestResult represents the test results reported by a
+	// pipeline step.
+	TestResult struct {
+		ID       int64       `json:"id"`
+		BuildID  int64       `json:"build_id"`
+		StageID  int64       `json:"stage_id"`
+		StepID   int64       `json:"step_id"`
+		Tests    int         `json:"tests"`
+		Failures int         `json:"failures"`
+		Errors   int         `json:"errors"`
+		Skipped  int         `json:"skipped"`
+		Duration int64       `json:"duration"`
+		Failed   []*TestCase `json:"failed,omitempty"`
+		Created  int64       `json:"created"`
+	}
+
+	// TestCase represents a failed test case.
+	TestCase struct {
+		Suite   string `json:"suite,omitempty"`
+		Class   string `json:"class,omitempty"`
+		Name    string `json:"name"`
+		Message string `json:"message,omitempty"`
+	}
+
+	// TestResultStore persists test results to storage.
+	TestResultStore interface {
+		// List returns a list of test results for the build
+		// ID from the datastore.
+		List(ctx context.Context, build int64) ([]*TestResult, error)
+
+		// Create persists new test results to the datastore.
+		Create(ctx context.Context, result *TestResult) error
+	}
+)
+
+// Passed returns the number of test cases that passed.
+func (r *TestResult) Passed() int {
+	return r.Tests - r.Failures - r.Errors - r.Skipped
+}
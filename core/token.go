@@ -0,0 +1,151 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"time"
+)
+
+// Token scopes.
+const (
+	ScopeReadRepo    = "read:repo"
+	ScopeAdminRepo   = "admin:repo"
+	ScopeReadBuild   = "read:build"
+	ScopeWriteBuild  = "write:build"
+	ScopeReadUser    = "read:user"
+	ScopeWriteUser   = "write:user"
+	ScopeAdminSystem = "admin:system"
+)
+
+// scopeImplies defines the scopes implied by each scope. For
+// example, a token with write access to builds can also read
+// builds, which requires read access to the repository.
+var scopeImplies = map[string][]string{
+	ScopeAdminRepo:  {ScopeReadRepo},
+	ScopeReadBuild:  {ScopeReadRepo},
+	ScopeWriteBuild: {ScopeReadBuild, ScopeReadRepo},
+	ScopeWriteUser:  {ScopeReadUser},
+}
+
+var (
+	errTokenNameLen      = errors.New("Invalid token name length")
+	errTokenScopeMissing = errors.New("Token requires at least one scope")
+	errTokenScopeInvalid = errors.New("Invalid token scope")
+)
+
+type (
+	// Token represents a named personal access token. The
+	// token grants access to the API limited to the token
+	// scopes, until the token expires or is revoked.
+	Token struct {
+		ID      int64    `json:"id"`
+		UserID  int64    `json:"user_id"`
+		Name    string   `json:"name"`
+		Scopes  []string `json:"scopes"`
+		Hash    string   `json:"-"` // digest of the token value
+		Expires int64    `json:"expires,omitempty"`
+		Created int64    `json:"created"`
+	}
+
+	// TokenStore persists personal access tokens to storage.
+	TokenStore interface {
+		// List returns a list of tokens for the user ID
+		// from the datastore.
+		List(ctx context.Context, user int64) ([]*Token, error)
+
+		// Find returns a token from the datastore.
+		Find(ctx context.Context, id int64) (*Token, error)
+
+		// FindHash returns a token from the datastore by hash.
+		FindHash(ctx context.Context, hash string) (*Token, error)
+
+		// Create persists a new token to the datastore.
+		Create(ctx context.Context, token *Token) error
+
+		// Delete deletes a token from the datastore.
+		Delete(ctx context.Context, token *Token) error
+
+		// DeleteUser deletes all tokens for the user ID
+		// from the datastore.
+		DeleteUser(ctx context.Context, user int64) error
+	}
+)
+
+// Validate validates the token fields and returns an error
+// if the validation fails.
+func (t *Token) Validate() error {
+	switch {
+	case len(t.Name) == 0 || len(t.Name) > 100:
+		return errTokenNameLen
+	case len(t.Scopes) == 0:
+		return errTokenScopeMissing
+	}
+	for _, scope := range t.Scopes {
+		if !isScope(scope) {
+			return errTokenScopeInvalid
+		}
+	}
+	return nil
+}
+
+// Expired returns true if the token is expired.
+func (t *Token) Expired() bool {
+	return t.Expires != 0 && time.Now().Unix() > t.Expires
+}
+
+// TokenDigest returns the digest of the token value. The
+// digest is persisted instead of the token value, so that the
+// token cannot be recovered from the datastore.
+func TokenDigest(value string) string {
+	digest := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(digest[:])
+}
+
+// HasScope returns true if the list of scopes grants the
+// named scope, either directly or by implication.
+func HasScope(scopes []string, scope string) bool {
+	for _, s := range scopes {
+		if s == scope {
+			return true
+		}
+		for _, implied := range scopeImplies[s] {
+			if implied == scope {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// helper function returns true if the named scope is a
+// known token scope.
+func isScope(scope string) bool {
+	switch scope {
+	case ScopeReadRepo,
+		ScopeAdminRepo,
+		ScopeReadBuild,
+		ScopeWriteBuild,
+		ScopeReadUser,
+		ScopeWriteUser,
+		ScopeAdminSystem:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,80 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package core
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTokenValidate(t *testing.T) {
+	tests := []struct {
+		token *Token
+		error error
+	}{
+		{
+			token: &Token{Name: "ci", Scopes: []string{ScopeReadBuild}},
+			error: nil,
+		},
+		{
+			token: &Token{Name: "", Scopes: []string{ScopeReadBuild}},
+			error: errTokenNameLen,
+		},
+		{
+			token: &Token{Name: "ci"},
+			error: errTokenScopeMissing,
+		},
+		{
+			token: &Token{Name: "ci", Scopes: []string{"write:everything"}},
+			error: errTokenScopeInvalid,
+		},
+	}
+	for i, test := range tests {
+		got, want := test.token.Validate(), test.error
+		if got != want {
+			t.Errorf("Want error %v, got %v at index %d", want, got, i)
+		}
+	}
+}
+
+func TestTokenExpired(t *testing.T) {
+	now := time.Now().Unix()
+	tests := []struct {
+		expires int64
+		expired bool
+	}{
+		{0, false},
+		{now + 3600, false},
+		{now - 3600, true},
+	}
+	for i, test := range tests {
+		token := &Token{Expires: test.expires}
+		if got, want := token.Expired(), test.expired; got != want {
+			t.Errorf("Want expired %v, got %v at index %d", want, got, i)
+		}
+	}
+}
+
+func TestHasScope(t *testing.T) {
+	tests := []struct {
+		scopes []string
+		scope  string
+		match  bool
+	}{
+		{[]string{ScopeReadBuild}, ScopeReadBuild, true},
+		{[]string{ScopeReadBuild}, ScopeReadRepo, true},
+		{[]string{ScopeReadBuild}, ScopeWriteBuild, false},
+		{[]string{ScopeWriteBuild}, ScopeReadBuild, true},
+		{[]string{ScopeAdminRepo}, ScopeReadRepo, true},
+		{[]string{ScopeAdminRepo}, ScopeWriteBuild, false},
+		{[]string{ScopeReadUser, ScopeAdminSystem}, ScopeAdminSystem, true},
+		{nil, ScopeReadRepo, false},
+	}
+	for i, test := range tests {
+		if got, want := HasScope(test.scopes, test.scope), test.match; got != want {
+			t.Errorf("Want scope match %v, got %v at index %d", want, got, i)
+		}
+	}
+}
@@ -20,6 +20,7 @@ import "context"
 const (
 	TriggerHook = "@hook"
 	TriggerCron = "@cron"
+	TriggerPoll = "@poll"
 )
 
 // Triggerer is responsible for triggering a Build from an
@@ -45,6 +45,12 @@ type (
 		Refresh   string `json:"-"`
 		Expiry    int64  `json:"-"`
 		Hash      string `json:"-"`
+
+		// Scopes limits the access granted to a user that is
+		// authenticated with a personal access token. This
+		// field is not persisted, and is nil when the user is
+		// not authenticated with a personal access token.
+		Scopes []string `json:"-"`
 	}
 
 	// UserStore defines operations for working with users.
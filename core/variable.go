@@ -0,0 +1,99 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"context"
+	"errors"
+	"regexp"
+)
+
+var (
+	errVariableNameInvalid  = errors.New("Invalid Variable Name")
+	errVariableScopeInvalid = errors.New("Invalid Variable Scope")
+)
+
+type (
+	// Variable represents an environment variable that is
+	// provided to every pipeline step. Variables are defined
+	// for the server, an organization, or a repository.
+	Variable struct {
+		ID        int64  `json:"id"`
+		RepoID    int64  `json:"repo_id,omitempty"`
+		Namespace string `json:"namespace,omitempty"`
+		Name      string `json:"name"`
+		Value     string `json:"value,omitempty"`
+		Secret    bool   `json:"secret"`
+		Created   int64  `json:"created"`
+		Updated   int64  `json:"updated"`
+	}
+
+	// VariableStore manages environment variables in storage.
+	VariableStore interface {
+		// List returns all variables from the datastore.
+		List(context.Context) ([]*Variable, error)
+
+		// ListRepo returns the variables defined for the
+		// repository.
+		ListRepo(context.Context, int64) ([]*Variable, error)
+
+		// ListNamespace returns the variables defined for
+		// the organization. The server variables are returned
+		// for an empty namespace.
+		ListNamespace(context.Context, string) ([]*Variable, error)
+
+		// Find returns a variable from the datastore.
+		Find(context.Context, int64) (*Variable, error)
+
+		// Create persists a new variable to the datastore.
+		Create(context.Context, *Variable) error
+
+		// Update persists an updated variable to the datastore.
+		Update(context.Context, *Variable) error
+
+		// Delete deletes a variable from the datastore.
+		Delete(context.Context, *Variable) error
+
+		// Rotate encrypts all variables in the datastore with
+		// the current encryption key, and returns the number
+		// of variables that were encrypted.
+		Rotate(context.Context) (int64, error)
+	}
+)
+
+// Validate validates the required fields and formats.
+func (v *Variable) Validate() error {
+	switch {
+	case !variableRE.MatchString(v.Name):
+		return errVariableNameInvalid
+	case v.RepoID != 0 && v.Namespace != "":
+		return errVariableScopeInvalid
+	default:
+		return nil
+	}
+}
+
+// Copy makes a copy of the variable. The value is removed
+// from the copy if the variable is a secret.
+func (v *Variable) Copy() *Variable {
+	out := *v
+	if out.Secret {
+		out.Value = ""
+	}
+	return &out
+}
+
+// variable name regular expression
+var variableRE = regexp.MustCompile("^[a-zA-Z_][a-zA-Z0-9_]*$")
@@ -0,0 +1,59 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package core
+
+import "testing"
+
+func TestVariableValidate(t *testing.T) {
+	tests := []struct {
+		variable *Variable
+		error    error
+	}{
+		{
+			variable: &Variable{Name: "GOPROXY", Value: "https://proxy.golang.org"},
+			error:    nil,
+		},
+		{
+			variable: &Variable{Namespace: "octocat", Name: "NPM_CONFIG_REGISTRY"},
+			error:    nil,
+		},
+		{
+			variable: &Variable{Name: ""},
+			error:    errVariableNameInvalid,
+		},
+		{
+			variable: &Variable{Name: "1PASSWORD"},
+			error:    errVariableNameInvalid,
+		},
+		{
+			variable: &Variable{Name: "GO PROXY"},
+			error:    errVariableNameInvalid,
+		},
+		{
+			variable: &Variable{RepoID: 1, Namespace: "octocat", Name: "GOPROXY"},
+			error:    errVariableScopeInvalid,
+		},
+	}
+	for i, test := range tests {
+		got, want := test.variable.Validate(), test.error
+		if got != want {
+			t.Errorf("Want error %v, got %v at index %d", want, got, i)
+		}
+	}
+}
+
+func TestVariableCopy(t *testing.T) {
+	v := &Variable{Name: "GOPROXY", Value: "https://proxy.golang.org"}
+	if got, want := v.Copy().Value, v.Value; got != want {
+		t.Errorf("Want variable value %s, got %s", want, got)
+	}
+	v = &Variable{Name: "NPM_TOKEN", Value: "correct-horse-battery-staple", Secret: true}
+	if v.Copy().Value != "" {
+		t.Errorf("Expect secret variable value removed from the copy")
+	}
+	if v.Value == "" {
+		t.Errorf("Expect original variable value unchanged")
+	}
+}
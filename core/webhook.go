@@ -16,8 +16,12 @@ package core
 
 import (
 	"context"
+	"errors"
+	"net/url"
 )
 
+var errWebhookEndpointInvalid = errors.New("Invalid Webhook Endpoint")
+
 // Webhook event types.
 const (
 	WebhookEventBuild = "build"
@@ -25,6 +29,15 @@ const (
 	WebhookEventUser  = "user"
 )
 
+// Webhook delivery states. A delivery that exceeds the
+// maximum number of attempts is marked as failed, and is
+// retained in the dead-letter list.
+const (
+	DeliveryPending = "pending"
+	DeliverySuccess = "success"
+	DeliveryFailed  = "failed"
+)
+
 // Webhook action types.
 const (
 	WebhookActionCreated  = "created"
@@ -56,4 +69,129 @@ type (
 		// Send sends the webhook to the global endpoint.
 		Send(context.Context, *WebhookData) error
 	}
+
+	// WebhookDelivery represents the delivery of a webhook
+	// payload to an endpoint. Deliveries to a repository
+	// webhook reference the webhook, which provides the
+	// secret used to sign the payload.
+	WebhookDelivery struct {
+		ID       int64             `json:"id"`
+		Hook     int64             `json:"hook_id,omitempty"`
+		Endpoint string            `json:"endpoint"`
+		Event    string            `json:"event"`
+		Action   string            `json:"action"`
+		Payload  string            `json:"payload"`
+		Status   string            `json:"status"`
+		Attempts int               `json:"attempts"`
+		History  []*WebhookAttempt `json:"history"`
+		Next     int64             `json:"next_attempt,omitempty"`
+		Created  int64             `json:"created"`
+		Updated  int64             `json:"updated"`
+	}
+
+	// WebhookAttempt represents a single webhook delivery
+	// attempt.
+	WebhookAttempt struct {
+		Code    int    `json:"code,omitempty"`
+		Error   string `json:"error,omitempty"`
+		Created int64  `json:"created"`
+	}
+
+	// WebhookDeliveryFilter provides webhook delivery filter
+	// parameters.
+	WebhookDeliveryFilter struct {
+		Status string
+		Limit  int
+		Offset int
+	}
+
+	// WebhookDeliveryStore persists webhook deliveries to
+	// storage.
+	WebhookDeliveryStore interface {
+		// List returns a list of webhook deliveries from the
+		// datastore, ordered from newest to oldest.
+		List(context.Context, WebhookDeliveryFilter) ([]*WebhookDelivery, error)
+
+		// ListDue returns a list of pending webhook deliveries
+		// that are due for a delivery attempt.
+		ListDue(ctx context.Context, now int64) ([]*WebhookDelivery, error)
+
+		// Find returns a webhook delivery from the datastore.
+		Find(context.Context, int64) (*WebhookDelivery, error)
+
+		// Create persists a new webhook delivery to the datastore.
+		Create(context.Context, *WebhookDelivery) error
+
+		// Update persists an updated webhook delivery to the
+		// datastore.
+		Update(context.Context, *WebhookDelivery) error
+
+		// Purge purges successful webhook deliveries created
+		// before the given time from the datastore.
+		Purge(ctx context.Context, before int64) error
+	}
+
+	// RepoWebhook defines a repository webhook endpoint that
+	// receives build lifecycle payloads.
+	RepoWebhook struct {
+		ID       int64  `json:"id,omitempty"`
+		RepoID   int64  `json:"repo_id,omitempty"`
+		Endpoint string `json:"endpoint,omitempty"`
+		Secret   string `json:"secret,omitempty"`
+		Created  int64  `json:"created,omitempty"`
+		Updated  int64  `json:"updated,omitempty"`
+	}
+
+	// RepoWebhookStore manages repository webhooks.
+	RepoWebhookStore interface {
+		// List returns a repository webhook list from the
+		// datastore.
+		List(context.Context, int64) ([]*RepoWebhook, error)
+
+		// Find returns a repository webhook from the datastore.
+		Find(context.Context, int64) (*RepoWebhook, error)
+
+		// Create persists a new repository webhook to the
+		// datastore.
+		Create(context.Context, *RepoWebhook) error
+
+		// Update persists an updated repository webhook to the
+		// datastore.
+		Update(context.Context, *RepoWebhook) error
+
+		// Delete deletes a repository webhook from the datastore.
+		Delete(context.Context, *RepoWebhook) error
+
+		// Rotate encrypts all repository webhooks in the
+		// datastore with the current encryption key, and returns
+		// the number of webhooks that were encrypted.
+		Rotate(context.Context) (int64, error)
+	}
 )
+
+// Validate validates the required fields and formats.
+func (w *RepoWebhook) Validate() error {
+	uri, err := url.Parse(w.Endpoint)
+	if err != nil {
+		return errWebhookEndpointInvalid
+	}
+	switch {
+	case uri.Scheme != "http" && uri.Scheme != "https":
+		return errWebhookEndpointInvalid
+	case uri.Host == "":
+		return errWebhookEndpointInvalid
+	default:
+		return nil
+	}
+}
+
+// Copy makes a copy of the webhook without the secret.
+func (w *RepoWebhook) Copy() *RepoWebhook {
+	return &RepoWebhook{
+		ID:       w.ID,
+		RepoID:   w.RepoID,
+		Endpoint: w.Endpoint,
+		Created:  w.Created,
+		Updated:  w.Updated,
+	}
+}
@@ -0,0 +1,61 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package core
+
+import "testing"
+
+func TestRepoWebhookValidate(t *testing.T) {
+	tests := []struct {
+		webhook *RepoWebhook
+		error   error
+	}{
+		{
+			webhook: &RepoWebhook{Endpoint: "https://company.com/hooks"},
+			error:   nil,
+		},
+		{
+			webhook: &RepoWebhook{Endpoint: "http://10.0.0.1:8080/hooks"},
+			error:   nil,
+		},
+		{
+			webhook: &RepoWebhook{Endpoint: ""},
+			error:   errWebhookEndpointInvalid,
+		},
+		{
+			webhook: &RepoWebhook{Endpoint: "ftp://company.com/hooks"},
+			error:   errWebhookEndpointInvalid,
+		},
+		{
+			webhook: &RepoWebhook{Endpoint: "https:///hooks"},
+			error:   errWebhookEndpointInvalid,
+		},
+		{
+			webhook: &RepoWebhook{Endpoint: "://company.com"},
+			error:   errWebhookEndpointInvalid,
+		},
+	}
+	for i, test := range tests {
+		got, want := test.webhook.Validate(), test.error
+		if got != want {
+			t.Errorf("Want error %v, got %v at index %d", want, got, i)
+		}
+	}
+}
+
+func TestRepoWebhookCopy(t *testing.T) {
+	webhook := &RepoWebhook{
+		ID:       1,
+		RepoID:   2,
+		Endpoint: "https://company.com/hooks",
+		Secret:   "correct-horse-battery-staple",
+	}
+	copy := webhook.Copy()
+	if copy.Secret != "" {
+		t.Errorf("Expect secret removed from copy")
+	}
+	if copy.Endpoint != webhook.Endpoint {
+		t.Errorf("Expect endpoint copied")
+	}
+}
@@ -0,0 +1,92 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package election
+
+import (
+	"context"
+	"time"
+
+	"github.com/drone/drone/core"
+
+	"github.com/sirupsen/logrus"
+)
+
+// leaseTTL is the duration of the lease. The lease is renewed
+// at a third of this interval, and another server instance is
+// elected leader once the lease expires.
+var leaseTTL = 30 * time.Second
+
+// New returns a new Elector for a single server instance,
+// which always holds the lease.
+func New() core.Elector {
+	return new(local)
+}
+
+type local struct{}
+
+func (local) Acquire(context.Context, string, time.Duration) (bool, error) { return true, nil }
+func (local) Release(context.Context, string) error                        { return nil }
+
+// Run runs the function while this server instance holds the
+// named lease. The function context is cancelled if the lease
+// is lost, and the function is restarted if the lease is
+// acquired again. Run returns when the parent context is
+// cancelled, or when the function returns an error.
+func Run(ctx context.Context, elector core.Elector, name string, fn func(context.Context) error) error {
+	logger := logrus.WithField("lease", name)
+
+	var (
+		cancel context.CancelFunc
+		done   chan error
+	)
+	start := func() {
+		child, cancelFunc := context.WithCancel(ctx)
+		cancel, done = cancelFunc, make(chan error, 1)
+		go func() {
+			done <- fn(child)
+		}()
+	}
+	stop := func() {
+		cancel()
+		<-done
+		cancel, done = nil, nil
+	}
+
+	defer func() {
+		if cancel != nil {
+			stop()
+		}
+		elector.Release(context.Background(), name)
+	}()
+
+	for {
+		leader, err := elector.Acquire(ctx, name, leaseTTL)
+		if err != nil {
+			logger.WithError(err).Errorln("election: cannot acquire lease")
+			leader = false
+		}
+
+		switch {
+		case leader && cancel == nil:
+			logger.Debugln("election: lease acquired")
+			start()
+		case !leader && cancel != nil:
+			logger.Debugln("election: lease lost")
+			stop()
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case err := <-done:
+			cancel()
+			cancel, done = nil, nil
+			if err != nil {
+				return err
+			}
+		case <-time.After(leaseTTL / 3):
+		}
+	}
+}
@@ -0,0 +1,102 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package election
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeElector holds the lease while leader is true.
+type fakeElector struct {
+	sync.Mutex
+	leader   bool
+	released bool
+}
+
+func (e *fakeElector) Acquire(context.Context, string, time.Duration) (bool, error) {
+	e.Lock()
+	defer e.Unlock()
+	return e.leader, nil
+}
+
+func (e *fakeElector) Release(context.Context, string) error {
+	e.Lock()
+	e.released = true
+	e.Unlock()
+	return nil
+}
+
+func (e *fakeElector) setLeader(leader bool) {
+	e.Lock()
+	e.leader = leader
+	e.Unlock()
+}
+
+func TestRun(t *testing.T) {
+	defer func(ttl time.Duration) { leaseTTL = ttl }(leaseTTL)
+	leaseTTL = 30 * time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	elector := &fakeElector{leader: true}
+	started := make(chan struct{}, 10)
+	stopped := make(chan struct{}, 10)
+
+	errc := make(chan error, 1)
+	go func() {
+		errc <- Run(ctx, elector, "cron", func(ctx context.Context) error {
+			started <- struct{}{}
+			<-ctx.Done()
+			stopped <- struct{}{}
+			return ctx.Err()
+		})
+	}()
+
+	// the function is started when the lease is acquired.
+	select {
+	case <-started:
+	case <-time.After(time.Second):
+		t.Fatalf("Want function started when lease acquired")
+	}
+
+	// the function is stopped when the lease is lost.
+	elector.setLeader(false)
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatalf("Want function stopped when lease lost")
+	}
+
+	// the function is restarted when the lease is acquired.
+	elector.setLeader(true)
+	select {
+	case <-started:
+	case <-time.After(time.Second):
+		t.Fatalf("Want function restarted when lease acquired")
+	}
+
+	cancel()
+	if err := <-errc; err != context.Canceled {
+		t.Errorf("Want context canceled error, got %v", err)
+	}
+	if !elector.released {
+		t.Errorf("Want lease released")
+	}
+}
+
+func TestRun_Error(t *testing.T) {
+	want := errors.New("oops")
+	got := Run(context.Background(), New(), "cron", func(context.Context) error {
+		return want
+	})
+	if got != want {
+		t.Errorf("Want error %v, got %v", want, got)
+	}
+}
@@ -0,0 +1,73 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package election
+
+import (
+	"context"
+	"time"
+
+	"github.com/drone/drone/core"
+
+	"github.com/dchest/uniuri"
+	"github.com/go-redis/redis"
+)
+
+// redisPrefix is the prefix of the redis keys that store the
+// lease holder.
+const redisPrefix = "drone:lease:"
+
+// renewScript atomically renews the lease if the lease is
+// held by this instance.
+var renewScript = redis.NewScript(`
+if redis.call("get", KEYS[1]) == ARGV[1] then
+	return redis.call("pexpire", KEYS[1], ARGV[2])
+end
+return 0
+`)
+
+// releaseScript atomically releases the lease if the lease
+// is held by this instance.
+var releaseScript = redis.NewScript(`
+if redis.call("get", KEYS[1]) == ARGV[1] then
+	return redis.call("del", KEYS[1])
+end
+return 0
+`)
+
+// NewRedis returns a new leader Elector backed by Redis. The
+// datasource is the Redis connection url.
+func NewRedis(datasource string) (core.Elector, error) {
+	options, err := redis.ParseURL(datasource)
+	if err != nil {
+		return nil, err
+	}
+	client := redis.NewClient(options)
+	if err := client.Ping().Err(); err != nil {
+		return nil, err
+	}
+	return &redisElector{
+		client: client,
+		holder: uniuri.NewLen(32),
+	}, nil
+}
+
+type redisElector struct {
+	client *redis.Client
+	holder string
+}
+
+func (e *redisElector) Acquire(ctx context.Context, name string, ttl time.Duration) (bool, error) {
+	key := redisPrefix + name
+	ok, err := e.client.SetNX(key, e.holder, ttl).Result()
+	if err != nil || ok {
+		return ok, err
+	}
+	n, err := renewScript.Run(e.client, []string{key}, e.holder, int64(ttl/time.Millisecond)).Int64()
+	return n == 1, err
+}
+
+func (e *redisElector) Release(ctx context.Context, name string) error {
+	return releaseScript.Run(e.client, []string{redisPrefix + name}, e.holder).Err()
+}
@@ -0,0 +1,305 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+// Package expr implements the expression language used to
+// define trigger and when conditions. An expression compares
+// build variables with string values, and combines the
+// comparisons with logical operators.
+//
+//	branch == "master" && event in ["push", "tag"]
+//	ref glob "refs/tags/v1.*" || message =~ "(?i)\[deploy\]"
+//	not (author == "renovate[bot]")
+//
+// The supported comparison operators are ==, !=, <, <=, >, >=,
+// =~ (regular expression), !~ (negated regular expression),
+// glob (glob pattern) and in (list of values). The ordering
+// operators compare numerically if both values are numbers.
+// The logical operators are &&, || and !, or the equivalent
+// and, or and not keywords.
+package expr
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Expr is a parsed expression.
+type Expr struct {
+	root node
+}
+
+// Parse parses the expression.
+func Parse(s string) (*Expr, error) {
+	tokens, err := lex(s)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokenEOF {
+		return nil, fmt.Errorf("expr: unexpected %s", t)
+	}
+	return &Expr{root: root}, nil
+}
+
+// Match returns true if the expression evaluates to true
+// for the variables. Undefined variables are empty.
+func (e *Expr) Match(vars map[string]string) bool {
+	return e.root.eval(vars)
+}
+
+//
+// abstract syntax tree
+//
+
+type node interface {
+	eval(vars map[string]string) bool
+}
+
+type andNode struct{ left, right node }
+
+func (n *andNode) eval(vars map[string]string) bool {
+	return n.left.eval(vars) && n.right.eval(vars)
+}
+
+type orNode struct{ left, right node }
+
+func (n *orNode) eval(vars map[string]string) bool {
+	return n.left.eval(vars) || n.right.eval(vars)
+}
+
+type notNode struct{ node node }
+
+func (n *notNode) eval(vars map[string]string) bool {
+	return !n.node.eval(vars)
+}
+
+type compareNode struct {
+	name   string
+	op     string
+	values []string
+	re     *regexp.Regexp
+}
+
+func (n *compareNode) eval(vars map[string]string) bool {
+	v := vars[n.name]
+	switch n.op {
+	case "==":
+		return v == n.values[0]
+	case "!=":
+		return v != n.values[0]
+	case "=~":
+		return n.re.MatchString(v)
+	case "!~":
+		return !n.re.MatchString(v)
+	case "glob":
+		ok, _ := path.Match(n.values[0], v)
+		return ok
+	case "in":
+		for _, value := range n.values {
+			if v == value {
+				return true
+			}
+		}
+		return false
+	default:
+		c := compare(v, n.values[0])
+		switch n.op {
+		case "<":
+			return c < 0
+		case "<=":
+			return c <= 0
+		case ">":
+			return c > 0
+		default:
+			return c >= 0
+		}
+	}
+}
+
+// helper function compares the values numerically if both
+// values are numbers, and lexically otherwise.
+func compare(a, b string) int {
+	x, errx := strconv.ParseFloat(a, 64)
+	y, erry := strconv.ParseFloat(b, 64)
+	if errx != nil || erry != nil {
+		return strings.Compare(a, b)
+	}
+	switch {
+	case x < y:
+		return -1
+	case x > y:
+		return 1
+	default:
+		return 0
+	}
+}
+
+//
+// parser
+//
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().is(tokenOperator, "||") || p.peek().is(tokenIdent, "or") {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &orNode{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().is(tokenOperator, "&&") || p.peek().is(tokenIdent, "and") {
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &andNode{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseUnary() (node, error) {
+	if p.peek().is(tokenOperator, "!") || p.peek().is(tokenIdent, "not") {
+		p.next()
+		n, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &notNode{node: n}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (node, error) {
+	t := p.next()
+	switch {
+	case t.is(tokenOperator, "("):
+		n, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if t := p.next(); !t.is(tokenOperator, ")") {
+			return nil, fmt.Errorf("expr: expected ) but found %s", t)
+		}
+		return n, nil
+	case t.kind == tokenIdent:
+		return p.parseCompare(t.value)
+	default:
+		return nil, fmt.Errorf("expr: expected variable but found %s", t)
+	}
+}
+
+func (p *parser) parseCompare(name string) (node, error) {
+	if !isVariable(name) {
+		return nil, fmt.Errorf("expr: unknown variable %s", name)
+	}
+	t := p.next()
+	n := &compareNode{name: name, op: t.value}
+	switch {
+	case t.is(tokenIdent, "in"):
+		values, err := p.parseList()
+		if err != nil {
+			return nil, err
+		}
+		n.values = values
+		return n, nil
+	case t.is(tokenIdent, "glob"):
+	case t.kind == tokenOperator && isComparison(t.value):
+	default:
+		return nil, fmt.Errorf("expr: expected operator after %s but found %s", name, t)
+	}
+
+	value, err := p.parseValue()
+	if err != nil {
+		return nil, err
+	}
+	n.values = []string{value}
+	switch n.op {
+	case "=~", "!~":
+		n.re, err = regexp.Compile(value)
+		if err != nil {
+			return nil, fmt.Errorf("expr: invalid regular expression %q", value)
+		}
+	case "glob":
+		if _, err := path.Match(value, ""); err != nil {
+			return nil, fmt.Errorf("expr: invalid glob pattern %q", value)
+		}
+	}
+	return n, nil
+}
+
+func (p *parser) parseList() ([]string, error) {
+	if t := p.next(); !t.is(tokenOperator, "[") {
+		return nil, fmt.Errorf("expr: expected [ but found %s", t)
+	}
+	var values []string
+	for {
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+
+		t := p.next()
+		if t.is(tokenOperator, "]") {
+			return values, nil
+		}
+		if !t.is(tokenOperator, ",") {
+			return nil, fmt.Errorf("expr: expected , or ] but found %s", t)
+		}
+	}
+}
+
+func (p *parser) parseValue() (string, error) {
+	t := p.next()
+	if t.kind != tokenString && t.kind != tokenNumber {
+		return "", fmt.Errorf("expr: expected value but found %s", t)
+	}
+	return t.value, nil
+}
+
+func isComparison(op string) bool {
+	switch op {
+	case "==", "!=", "=~", "!~", "<", "<=", ">", ">=":
+		return true
+	default:
+		return false
+	}
+}
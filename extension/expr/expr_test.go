@@ -0,0 +1,135 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package expr
+
+import (
+	"testing"
+
+	"github.com/drone/drone/core"
+)
+
+func TestMatch(t *testing.T) {
+	vars := map[string]string{
+		"branch":  "feature/login",
+		"event":   "push",
+		"ref":     "refs/heads/feature/login",
+		"message": "fix login [deploy]",
+		"tag":     "",
+		"target":  "10",
+	}
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		{`branch == "feature/login"`, true},
+		{`branch != "master"`, true},
+		{`branch glob "feature/*"`, true},
+		{`branch glob "release/*"`, false},
+		{`event in ["push", "tag"]`, true},
+		{`event in ["pull_request"]`, false},
+		{`message =~ "\[deploy\]$"`, true},
+		{`message !~ "(?i)wip"`, true},
+		{`target > 9`, true},
+		{`target <= "9"`, false},
+		{`branch < "master"`, true},
+		{`target >= 10 && target < 11`, true},
+		{`tag == ""`, true},
+		{`author == ""`, true},
+		{`branch == "master" || event == "push"`, true},
+		{`branch == "master" or event == "tag"`, false},
+		{`branch == "master" || event == "push" && tag != ""`, false},
+		{`(branch == "master" || event == "push") and tag == ""`, true},
+		{`!(event == "push")`, false},
+		{`not event == "tag"`, true},
+		{`event == 'push'`, true},
+		{`message == 'fix login [deploy]'`, true},
+		{`semver_major >= 2`, false},
+	}
+	for _, test := range tests {
+		e, err := Parse(test.expr)
+		if err != nil {
+			t.Errorf("Cannot parse %s: %s", test.expr, err)
+			continue
+		}
+		if got := e.Match(vars); got != test.want {
+			t.Errorf("Want %s to return %v", test.expr, test.want)
+		}
+	}
+}
+
+func TestParse_Error(t *testing.T) {
+	tests := []string{
+		``,
+		`branch`,
+		`branch ==`,
+		`branch = "master"`,
+		`branches == "master"`,
+		`branch == master`,
+		`"master" == branch`,
+		`branch == "master" &&`,
+		`(branch == "master"`,
+		`branch == "master")`,
+		`branch in "master"`,
+		`branch in ["master",]`,
+		`branch in []`,
+		`branch =~ "["`,
+		`branch glob "["`,
+		`branch == "master`,
+		`branch == - `,
+		`branch == "master" $`,
+	}
+	for _, test := range tests {
+		if _, err := Parse(test); err == nil {
+			t.Errorf("Want error parsing %s", test)
+		}
+	}
+}
+
+func TestVars(t *testing.T) {
+	repo := &core.Repository{Slug: "octocat/hello-world"}
+	build := &core.Build{
+		Event:  core.EventTag,
+		Ref:    "refs/tags/v1.0.0",
+		Target: "master",
+		After:  "7fd1a60b01f91b314f59955a4e4d4e80d8edf11d",
+		Deploy: "production",
+	}
+	vars := Vars(repo, build, "drone.company.com")
+	for name, want := range map[string]string{
+		"branch":   "master",
+		"commit":   "7fd1a60b01f91b314f59955a4e4d4e80d8edf11d",
+		"event":    core.EventTag,
+		"instance": "drone.company.com",
+		"ref":      "refs/tags/v1.0.0",
+		"repo":     "octocat/hello-world",
+		"tag":      "v1.0.0",
+		"target":   "production",
+
+		"semver":            "1.0.0",
+		"semver_major":      "1",
+		"semver_minor":      "0",
+		"semver_patch":      "0",
+		"semver_prerelease": "",
+	} {
+		if got := vars[name]; got != want {
+			t.Errorf("Want variable %s %q, got %q", name, want, got)
+		}
+	}
+	for name := range vars {
+		if !isVariable(name) {
+			t.Errorf("Want variable %s defined", name)
+		}
+	}
+
+	build.Ref = "refs/tags/release-2019"
+	if got := Vars(repo, build, "")["semver"]; got != "" {
+		t.Errorf("Want empty semver for invalid version, got %q", got)
+	}
+
+	build.Ref = "refs/heads/master"
+	if got := Vars(repo, build, "")["tag"]; got != "" {
+		t.Errorf("Want empty tag for branch ref, got %q", got)
+	}
+}
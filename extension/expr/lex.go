@@ -0,0 +1,128 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package expr
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenString
+	tokenNumber
+	tokenOperator
+)
+
+type token struct {
+	kind  tokenKind
+	value string
+}
+
+func (t token) is(kind tokenKind, value string) bool {
+	return t.kind == kind && t.value == value
+}
+
+func (t token) String() string {
+	switch t.kind {
+	case tokenEOF:
+		return "end of expression"
+	case tokenString:
+		return fmt.Sprintf("%q", t.value)
+	default:
+		return t.value
+	}
+}
+
+// operators are ordered so that two character operators are
+// matched before their single character prefix.
+var operators = []string{
+	"&&", "||", "==", "!=", "=~", "!~", "<=", ">=",
+	"<", ">", "!", "(", ")", "[", "]", ",",
+}
+
+// helper function splits the expression into tokens.
+func lex(s string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(s); {
+		c := rune(s[i])
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case c == '"' || c == '\'':
+			value, n, err := lexString(s[i:])
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, token{kind: tokenString, value: value})
+			i += n
+		case isDigit(c) || c == '-':
+			j := i + 1
+			for j < len(s) && (isDigit(rune(s[j])) || s[j] == '.') {
+				j++
+			}
+			if s[i:j] == "-" {
+				return nil, fmt.Errorf("expr: unexpected - at position %d", i)
+			}
+			tokens = append(tokens, token{kind: tokenNumber, value: s[i:j]})
+			i = j
+		case isLetter(c):
+			j := i + 1
+			for j < len(s) && (isLetter(rune(s[j])) || isDigit(rune(s[j]))) {
+				j++
+			}
+			tokens = append(tokens, token{kind: tokenIdent, value: s[i:j]})
+			i = j
+		default:
+			op := ""
+			for _, o := range operators {
+				if strings.HasPrefix(s[i:], o) {
+					op = o
+					break
+				}
+			}
+			if op == "" {
+				return nil, fmt.Errorf("expr: unexpected %q at position %d", c, i)
+			}
+			tokens = append(tokens, token{kind: tokenOperator, value: op})
+			i += len(op)
+		}
+	}
+	return append(tokens, token{kind: tokenEOF}), nil
+}
+
+// helper function returns the quoted string at the start of
+// s, and the number of bytes consumed. A backslash escapes
+// the quote character and the backslash itself, and is
+// otherwise retained so regular expressions can be written
+// without double escaping.
+func lexString(s string) (string, int, error) {
+	quote := s[0]
+	var b strings.Builder
+	for i := 1; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == quote:
+			return b.String(), i + 1, nil
+		case c == '\\' && i+1 < len(s) && (s[i+1] == quote || s[i+1] == '\\'):
+			b.WriteByte(s[i+1])
+			i++
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return "", 0, fmt.Errorf("expr: unterminated string %s", s)
+}
+
+func isDigit(c rune) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isLetter(c rune) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
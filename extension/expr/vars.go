@@ -0,0 +1,80 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package expr
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/drone/drone/core"
+
+	"github.com/coreos/go-semver/semver"
+)
+
+// variables defines the build metadata that can be
+// referenced in an expression.
+var variables = map[string]struct{}{
+	"action":   {},
+	"author":   {},
+	"branch":   {},
+	"commit":   {},
+	"event":    {},
+	"instance": {},
+	"message":  {},
+	"ref":      {},
+	"repo":     {},
+	"sender":   {},
+	"semver":   {},
+	"source":   {},
+	"tag":      {},
+	"target":   {},
+
+	"semver_major":      {},
+	"semver_minor":      {},
+	"semver_patch":      {},
+	"semver_prerelease": {},
+}
+
+func isVariable(name string) bool {
+	_, ok := variables[name]
+	return ok
+}
+
+// Vars returns the expression variables for the repository
+// and build. The instance is the server hostname.
+func Vars(repo *core.Repository, build *core.Build, instance string) map[string]string {
+	var tag string
+	if strings.HasPrefix(build.Ref, "refs/tags/") {
+		tag = strings.TrimPrefix(build.Ref, "refs/tags/")
+	}
+	vars := map[string]string{
+		"action":   build.Action,
+		"author":   build.Author,
+		"branch":   build.Target,
+		"commit":   build.After,
+		"event":    build.Event,
+		"instance": instance,
+		"message":  build.Message,
+		"ref":      build.Ref,
+		"repo":     repo.Slug,
+		"sender":   build.Sender,
+		"source":   build.Source,
+		"tag":      tag,
+		"target":   build.Deploy,
+	}
+	// tags that are valid semantic versions expose the
+	// version components, which are compared numerically.
+	if tag == "" {
+		return vars
+	}
+	if v, err := semver.NewVersion(strings.TrimPrefix(tag, "v")); err == nil {
+		vars["semver"] = v.String()
+		vars["semver_major"] = fmt.Sprint(v.Major)
+		vars["semver_minor"] = fmt.Sprint(v.Minor)
+		vars["semver_patch"] = fmt.Sprint(v.Patch)
+		vars["semver_prerelease"] = string(v.PreRelease)
+	}
+	return vars
+}
@@ -0,0 +1,307 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+// Package extension parses pipeline attributes that are not
+// yet supported by the drone-yaml library. The configuration
+// file is parsed a second time, and the extended attributes
+// are merged into the build at compile time.
+package extension
+
+import (
+	"io"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/drone/drone/extension/expr"
+
+	"gopkg.in/yaml.v2"
+)
+
+type (
+	// Manifest is a collection of pipeline extensions.
+	Manifest struct {
+		Pipelines []*Pipeline
+	}
+
+	// Pipeline defines extended pipeline attributes.
+	Pipeline struct {
+		Kind     string            `yaml:"kind"`
+		Name     string            `yaml:"name"`
+		Timeout  time.Duration     `yaml:"timeout"`
+		Failure  string            `yaml:"failure"`
+		Pool     string            `yaml:"pool"`
+		Matrix   map[string]string `yaml:"matrix"`
+		Metadata *Metadata         `yaml:"metadata"`
+		Cache    *Cache            `yaml:"cache"`
+		Clone    *Clone            `yaml:"clone"`
+		Trigger  *Conditions       `yaml:"trigger"`
+		Services []*Service        `yaml:"services"`
+		Steps    []*Step           `yaml:"steps"`
+	}
+
+	// Metadata defines the pipeline metadata. The labels are
+	// added to the build labels, and can be used to filter
+	// the build history (e.g. by team or release train).
+	Metadata struct {
+		Labels map[string]string `yaml:"labels"`
+	}
+
+	// Step defines extended step attributes.
+	Step struct {
+		Name    string        `yaml:"name"`
+		Timeout time.Duration `yaml:"timeout"`
+		Retries Retries       `yaml:"retries"`
+		Failure string        `yaml:"failure"`
+		When    *Conditions   `yaml:"when"`
+	}
+
+	// Service defines extended service attributes.
+	Service struct {
+		Name        string       `yaml:"name"`
+		Healthcheck *Healthcheck `yaml:"healthcheck"`
+	}
+
+	// Healthcheck defines a service health check. The service
+	// is healthy when the command exits successfully, or when
+	// the port accepts connections.
+	Healthcheck struct {
+		Command  string        `yaml:"command"`
+		Port     int           `yaml:"port"`
+		Interval time.Duration `yaml:"interval"`
+		Retries  int           `yaml:"retries"`
+	}
+
+	// Cache defines the pipeline cache. The mounted paths
+	// are restored before the pipeline steps are executed,
+	// and rebuilt when the pipeline steps complete. The cache
+	// key is derived from the checksum of the listed files.
+	Cache struct {
+		Mount    []string `yaml:"mount"`
+		Checksum []string `yaml:"checksum"`
+	}
+
+	// Clone defines extended clone attributes. The clone
+	// depth and disable attributes are supported by the
+	// drone-yaml library.
+	Clone struct {
+		Tags       bool    `yaml:"tags"`
+		Submodules bool    `yaml:"submodules"`
+		Retries    Retries `yaml:"retries"`
+	}
+
+	// Conditions defines extended trigger and when conditions.
+	// The conditions are evaluated in addition to the include
+	// and exclude conditions supported by the drone-yaml
+	// library.
+	Conditions struct {
+		Tag  Condition `yaml:"tag"`
+		Expr string    `yaml:"expr"`
+	}
+
+	// Condition defines include and exclude glob patterns.
+	Condition struct {
+		Include []string
+		Exclude []string
+	}
+
+	// Retries defines the step retry policy.
+	Retries struct {
+		Limit   int           `yaml:"limit"`
+		Backoff time.Duration `yaml:"backoff"`
+	}
+)
+
+// FailureIgnore instructs the system to ignore failures.
+const FailureIgnore = "ignore"
+
+// Lookup returns the named pipeline extensions. If the
+// pipeline does not exist, a nil value is returned.
+func (m *Manifest) Lookup(name string) *Pipeline {
+	for _, pipeline := range m.Pipelines {
+		if pipeline.Name == name {
+			return pipeline
+		}
+	}
+	return nil
+}
+
+// Step returns the named step extensions. If the step does
+// not exist, a nil value is returned.
+func (p *Pipeline) Step(name string) *Step {
+	for _, step := range p.Steps {
+		if step.Name == name {
+			return step
+		}
+	}
+	return nil
+}
+
+// Service returns the named service extensions. If the
+// service does not exist, a nil value is returned.
+func (p *Pipeline) Service(name string) *Service {
+	for _, service := range p.Services {
+		if service.Name == name {
+			return service
+		}
+	}
+	return nil
+}
+
+// Labels returns the pipeline metadata labels formatted as
+// key=value pairs, sorted by key.
+func (p *Pipeline) Labels() []string {
+	if p.Metadata == nil {
+		return nil
+	}
+	var labels []string
+	for key, value := range p.Metadata.Labels {
+		labels = append(labels, key+"="+value)
+	}
+	sort.Strings(labels)
+	return labels
+}
+
+// IgnoreFailure returns true if pipeline failures should not
+// fail the build.
+func (p *Pipeline) IgnoreFailure() bool {
+	return p.Failure == FailureIgnore
+}
+
+// IgnoreFailure returns true if step failures should not
+// fail the pipeline.
+func (s *Step) IgnoreFailure() bool {
+	return s.Failure == FailureIgnore
+}
+
+// Match returns true if the conditions match the variables.
+// The tag condition is matched against the tag name, and
+// does not match builds that are not tags. An empty
+// expression always matches. An error is returned if the
+// expression is invalid.
+func (c *Conditions) Match(vars map[string]string) (bool, error) {
+	if c == nil {
+		return true, nil
+	}
+	if !c.Tag.Match(vars["tag"]) {
+		return false, nil
+	}
+	if strings.TrimSpace(c.Expr) == "" {
+		return true, nil
+	}
+	e, err := expr.Parse(c.Expr)
+	if err != nil {
+		return false, err
+	}
+	return e.Match(vars), nil
+}
+
+// Match returns true if the string matches the include
+// patterns and does not match any of the exclude patterns.
+func (c *Condition) Match(v string) bool {
+	if c.Excludes(v) {
+		return false
+	}
+	if c.Includes(v) {
+		return true
+	}
+	return len(c.Include) == 0
+}
+
+// Includes returns true if the string matches an include
+// pattern.
+func (c *Condition) Includes(v string) bool {
+	for _, pattern := range c.Include {
+		if ok, _ := path.Match(pattern, v); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Excludes returns true if the string matches an exclude
+// pattern.
+func (c *Condition) Excludes(v string) bool {
+	for _, pattern := range c.Exclude {
+		if ok, _ := path.Match(pattern, v); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// UnmarshalYAML implements yaml unmarshalling. The condition
+// is defined as a string, a list of strings, or as include
+// and exclude lists.
+func (c *Condition) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var out1 string
+	var out2 []string
+	var out3 = struct {
+		Include []string
+		Exclude []string
+	}{}
+
+	err := unmarshal(&out1)
+	if err == nil {
+		c.Include = []string{out1}
+		return nil
+	}
+
+	unmarshal(&out2)
+	unmarshal(&out3)
+
+	c.Exclude = out3.Exclude
+	c.Include = append(
+		out3.Include,
+		out2...,
+	)
+	return nil
+}
+
+// Parse parses the pipeline extensions from the io.Reader.
+func Parse(r io.Reader) (*Manifest, error) {
+	manifest := new(Manifest)
+	decoder := yaml.NewDecoder(r)
+	for {
+		raw := map[string]interface{}{}
+		err := decoder.Decode(&raw)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		// documents other than pipelines (secrets, signatures)
+		// are ignored.
+		if raw["kind"] != "pipeline" {
+			continue
+		}
+		// the document is re-encoded and decoded into the
+		// pipeline structure. This is less efficient than
+		// decoding directly, but prevents unrelated documents
+		// from causing type errors.
+		out, err := yaml.Marshal(raw)
+		if err != nil {
+			return nil, err
+		}
+		pipeline := new(Pipeline)
+		err = yaml.Unmarshal(out, pipeline)
+		if err != nil {
+			return nil, err
+		}
+		if pipeline.Name == "" {
+			pipeline.Name = "default"
+		}
+		manifest.Pipelines = append(manifest.Pipelines, pipeline)
+	}
+	return manifest, nil
+}
+
+// ParseString parses the pipeline extensions from a string.
+func ParseString(s string) (*Manifest, error) {
+	return Parse(
+		strings.NewReader(s),
+	)
+}
@@ -0,0 +1,231 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package extension
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"gopkg.in/yaml.v2"
+)
+
+func TestParse(t *testing.T) {
+	manifest, err := ParseString(testManifest)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if got, want := len(manifest.Pipelines), 2; got != want {
+		t.Errorf("Want %d pipelines, got %d", want, got)
+		return
+	}
+
+	pipeline := manifest.Lookup("default")
+	if pipeline == nil {
+		t.Errorf("Expect default pipeline")
+		return
+	}
+	if got, want := pipeline.Timeout, 90*time.Minute; got != want {
+		t.Errorf("Want pipeline timeout %s, got %s", want, got)
+	}
+	if pipeline.Cache == nil {
+		t.Errorf("Expect pipeline cache")
+		return
+	}
+	if got, want := strings.Join(pipeline.Cache.Mount, ","), "node_modules,.npm"; got != want {
+		t.Errorf("Want cache mount %s, got %s", want, got)
+	}
+	if got, want := strings.Join(pipeline.Cache.Checksum, ","), "package-lock.json"; got != want {
+		t.Errorf("Want cache checksum %s, got %s", want, got)
+	}
+	if got, want := pipeline.Pool, "gpu"; got != want {
+		t.Errorf("Want pipeline pool %s, got %s", want, got)
+	}
+	if got, want := pipeline.Matrix["GO_VERSION"], "1.12"; got != want {
+		t.Errorf("Want pipeline matrix value %s, got %s", want, got)
+	}
+	if got, want := strings.Join(pipeline.Labels(), ","), "release=2019.04,team=payments"; got != want {
+		t.Errorf("Want pipeline labels %s, got %s", want, got)
+	}
+	if pipeline.Clone == nil {
+		t.Errorf("Expect pipeline clone")
+		return
+	}
+	if !pipeline.Clone.Tags {
+		t.Errorf("Expect clone fetches tags")
+	}
+	if !pipeline.Clone.Submodules {
+		t.Errorf("Expect clone updates submodules")
+	}
+	if got, want := pipeline.Clone.Retries.Limit, 3; got != want {
+		t.Errorf("Want clone retry limit %d, got %d", want, got)
+	}
+
+	step := pipeline.Step("test")
+	if step == nil {
+		t.Errorf("Expect test step")
+		return
+	}
+	if got, want := step.Timeout, 10*time.Minute; got != want {
+		t.Errorf("Want step timeout %s, got %s", want, got)
+	}
+	if got, want := step.Retries.Limit, 2; got != want {
+		t.Errorf("Want step retry limit %d, got %d", want, got)
+	}
+	if got, want := step.Retries.Backoff, 30*time.Second; got != want {
+		t.Errorf("Want step retry backoff %s, got %s", want, got)
+	}
+	if pipeline.Step("build") != nil {
+		t.Errorf("Expect nil step when name does not exist")
+	}
+	if !pipeline.IgnoreFailure() {
+		t.Errorf("Expect pipeline ignores failure")
+	}
+	if step.IgnoreFailure() {
+		t.Errorf("Expect test step does not ignore failure")
+	}
+	if step = pipeline.Step("lint"); step == nil || !step.IgnoreFailure() {
+		t.Errorf("Expect lint step ignores failure")
+	}
+	if got, want := pipeline.Trigger.Expr, `branch == "master" || tag glob "v*"`; got != want {
+		t.Errorf("Want trigger expression %s, got %s", want, got)
+	}
+	if ok, err := pipeline.Trigger.Match(map[string]string{"tag": "v1.0.0"}); !ok || err != nil {
+		t.Errorf("Expect trigger expression matches tag")
+	}
+	if ok, err := pipeline.Trigger.Match(map[string]string{"tag": "v0.1.0"}); ok || err != nil {
+		t.Errorf("Expect trigger tag condition excludes tag")
+	}
+	if ok, err := step.When.Match(map[string]string{"event": "tag"}); ok || err != nil {
+		t.Errorf("Expect when expression does not match tag")
+	}
+	if ok, err := pipeline.Step("test").When.Match(nil); !ok || err != nil {
+		t.Errorf("Expect empty when expression matches")
+	}
+
+	pipeline = manifest.Lookup("backend")
+	if pipeline == nil {
+		t.Errorf("Expect backend pipeline")
+		return
+	}
+	if got, want := pipeline.Timeout, time.Duration(0); got != want {
+		t.Errorf("Want pipeline timeout %s, got %s", want, got)
+	}
+	if pipeline.IgnoreFailure() {
+		t.Errorf("Expect backend pipeline does not ignore failure")
+	}
+	if labels := pipeline.Labels(); len(labels) != 0 {
+		t.Errorf("Expect backend pipeline has no labels, got %v", labels)
+	}
+
+	if manifest.Lookup("frontend") != nil {
+		t.Errorf("Expect nil pipeline when name does not exist")
+	}
+}
+
+func TestParse_Error(t *testing.T) {
+	_, err := ParseString("kind: pipeline\ntimeout: [ foo ]")
+	if err == nil {
+		t.Errorf("Expect error when timeout is invalid")
+	}
+}
+
+func TestCondition(t *testing.T) {
+	tests := []struct {
+		config string
+		value  string
+		want   bool
+	}{
+		{"", "v1.0.0", true},
+		{"tag: v1.*", "v1.0.0", true},
+		{"tag: v1.*", "v2.0.0", false},
+		{"tag: v1.*", "", false},
+		{"tag: [ v1.*, v2.* ]", "v2.0.0", true},
+		{"tag: { include: [ v* ], exclude: [ '*-rc*' ] }", "v1.0.0-rc1", false},
+		{"tag: { exclude: [ '*-rc*' ] }", "", true},
+	}
+	for i, test := range tests {
+		conditions := new(Conditions)
+		if err := yaml.Unmarshal([]byte(test.config), conditions); err != nil {
+			t.Error(err)
+			continue
+		}
+		if got := conditions.Tag.Match(test.value); got != test.want {
+			t.Errorf("Want test %d to return %v", i, test.want)
+		}
+	}
+}
+
+func TestConditions_Error(t *testing.T) {
+	conditions := &Conditions{Expr: "branch =="}
+	if _, err := conditions.Match(nil); err == nil {
+		t.Errorf("Expect error when expression is invalid")
+	}
+}
+
+var testManifest = `
+kind: pipeline
+timeout: 1h30m
+failure: ignore
+pool: gpu
+
+matrix:
+  GO_VERSION: "1.12"
+
+metadata:
+  labels:
+    team: payments
+    release: "2019.04"
+
+clone:
+  depth: 50
+  tags: true
+  submodules: true
+  retries:
+    limit: 3
+
+cache:
+  mount:
+  - node_modules
+  - .npm
+  checksum:
+  - package-lock.json
+
+steps:
+- name: test
+  image: golang
+  timeout: 10m
+  retries:
+    limit: 2
+    backoff: 30s
+- name: lint
+  image: golang
+  failure: ignore
+  when:
+    branch: [ master ]
+    expr: event != "tag"
+
+trigger:
+  event: [ push, tag ]
+  tag:
+    include: [ v* ]
+    exclude: [ v0.* ]
+  expr: branch == "master" || tag glob "v*"
+
+---
+kind: secret
+type: external
+data:
+  token: path/to/secret
+
+---
+kind: pipeline
+name: backend
+
+steps:
+- name: test
+  image: golang
+`
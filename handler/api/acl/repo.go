@@ -104,8 +104,11 @@ func InjectRepository(
 
 			// because the permissions are synced with the remote
 			// system (e.g. github) they may be stale. If the permissions
-			// are stale they are refreshed below.
-			if perm.Synced == 0 || time.Unix(perm.Synced, 0).Add(time.Hour).Before(time.Now()) {
+			// are stale they are refreshed below. Machine account
+			// permissions, and permissions for repositories hosted
+			// by an additional provider, are managed by drone and
+			// are never synced.
+			if !user.Machine && repo.Provider == "" && (perm.Synced == 0 || time.Unix(perm.Synced, 0).Add(time.Hour).Before(time.Now())) {
 				log.Debugln("api: sync repository permissions")
 
 				permv, err := repoz.FindPerm(ctx, user, repo.Slug)
@@ -121,6 +124,12 @@ func InjectRepository(
 				perm.Write = permv.Write
 				perm.Admin = permv.Admin
 
+				// a locally configured role takes precedence
+				// over the role synced from the remote system.
+				if perm.Override != "" {
+					perm.Apply(perm.Override)
+				}
+
 				err = perms.Update(ctx, perm)
 				if err != nil {
 					log.WithError(err).Debugln("api: cannot cache repository permissions")
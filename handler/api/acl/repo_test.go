@@ -159,6 +159,99 @@ func TestInjectRepository_PermsFound(t *testing.T) {
 	}
 }
 
+// this unit test ensures that the middleware function
+// does not sync machine account permissions with the
+// remote system, since they are managed by drone.
+func TestInjectRepository_PermsMachine(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockUser := &core.User{ID: 1, Machine: true}
+	mockRepo := &core.Repository{UID: "1"}
+	mockPerm := &core.Perm{Read: true}
+
+	repos := mock.NewMockRepositoryStore(controller)
+	repos.EXPECT().FindName(gomock.Any(), "octocat", "hello-world").Return(mockRepo, nil)
+
+	perms := mock.NewMockPermStore(controller)
+	perms.EXPECT().Find(gomock.Any(), mockRepo.UID, mockUser.ID).Return(mockPerm, nil)
+
+	c := new(chi.Context)
+	c.URLParams.Add("owner", "octocat")
+	c.URLParams.Add("name", "hello-world")
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/", nil)
+	r = r.WithContext(
+		context.WithValue(
+			request.WithUser(r.Context(), mockUser),
+			chi.RouteCtxKey, c),
+	)
+
+	invoked := false
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		invoked = true
+		_, ok := request.PermFrom(r.Context())
+		if !ok {
+			t.Errorf("Expect perm from context")
+		}
+	})
+
+	// the repository service is nil, which verifies the
+	// permissions are not synced.
+	InjectRepository(nil, repos, perms)(next).ServeHTTP(w, r)
+	if !invoked {
+		t.Errorf("Expect middleware invoked")
+	}
+}
+
+func TestInjectRepository_PermsOverride(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockUser := &core.User{ID: 1}
+	mockRepo := &core.Repository{UID: "1", Slug: "octocat/hello-world"}
+	mockPerm := &core.Perm{Read: true, Override: core.RoleWrite}
+
+	repos := mock.NewMockRepositoryStore(controller)
+	repos.EXPECT().FindName(gomock.Any(), "octocat", "hello-world").Return(mockRepo, nil)
+
+	repoz := mock.NewMockRepositoryService(controller)
+	repoz.EXPECT().FindPerm(gomock.Any(), mockUser, mockRepo.Slug).Return(&core.Perm{Read: true, Write: true, Admin: true}, nil)
+
+	perms := mock.NewMockPermStore(controller)
+	perms.EXPECT().Find(gomock.Any(), mockRepo.UID, mockUser.ID).Return(mockPerm, nil)
+	perms.EXPECT().Update(gomock.Any(), mockPerm).Return(nil)
+
+	c := new(chi.Context)
+	c.URLParams.Add("owner", "octocat")
+	c.URLParams.Add("name", "hello-world")
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/", nil)
+	r = r.WithContext(
+		context.WithValue(
+			request.WithUser(r.Context(), mockUser),
+			chi.RouteCtxKey, c),
+	)
+
+	invoked := false
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		invoked = true
+		perm, _ := request.PermFrom(r.Context())
+		// the local role override takes precedence over
+		// the admin access granted by the remote system.
+		if perm.Admin || !perm.Write || !perm.Read {
+			t.Errorf("Expect synced permissions replaced by the role override")
+		}
+	})
+
+	InjectRepository(repoz, repos, perms)(next).ServeHTTP(w, r)
+	if !invoked {
+		t.Errorf("Expect middleware invoked")
+	}
+}
+
 // this unit test ensures that the middleware function
 // invokes the next handler even if the permissions are
 // not found. It is the responsibility to downstream
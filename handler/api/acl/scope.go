@@ -0,0 +1,74 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package acl
+
+import (
+	"net/http"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/errors"
+	"github.com/drone/drone/handler/api/render"
+	"github.com/drone/drone/handler/api/request"
+	"github.com/drone/drone/logger"
+)
+
+// CheckScope returns an http.Handler middleware that authorizes
+// users authenticated with a personal access token to proceed to
+// the next handler in the chain only if the token grants the named
+// scope. Users authenticated with a session or a legacy user token
+// are not restricted.
+func CheckScope(scope string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			user, ok := request.UserFrom(r.Context())
+			switch {
+			case !ok:
+				next.ServeHTTP(w, r)
+			case user.Scopes == nil:
+				next.ServeHTTP(w, r)
+			case core.HasScope(user.Scopes, scope):
+				next.ServeHTTP(w, r)
+			default:
+				render.Forbidden(w, errors.ErrForbidden)
+				logger.FromRequest(r).
+					WithField("scope", scope).
+					Debugln("api: token scope required")
+			}
+		})
+	}
+}
+
+// AuthorizeUnscoped returns an http.Handler middleware that
+// authorizes only users that are not authenticated with a personal
+// access token to proceed to the next handler in the chain. This
+// prevents a token from being used to create additional tokens, or
+// to retrieve the legacy user token, which is not restricted.
+func AuthorizeUnscoped(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, ok := request.UserFrom(r.Context())
+		switch {
+		case !ok:
+			render.Unauthorized(w, errors.ErrUnauthorized)
+			logger.FromRequest(r).
+				Debugln("api: authentication required")
+		case user.Scopes != nil:
+			render.Forbidden(w, errors.ErrForbidden)
+			logger.FromRequest(r).
+				Debugln("api: unscoped session required")
+		default:
+			next.ServeHTTP(w, r)
+		}
+	})
+}
@@ -0,0 +1,82 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package acl
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/request"
+)
+
+func TestCheckScope(t *testing.T) {
+	tests := []struct {
+		user   *core.User
+		scope  string
+		status int
+	}{
+		// guest users are not restricted by scope
+		{nil, core.ScopeReadRepo, http.StatusTeapot},
+		// session users are not restricted by scope
+		{&core.User{ID: 1}, core.ScopeAdminRepo, http.StatusTeapot},
+		{&core.User{ID: 1, Scopes: []string{core.ScopeAdminRepo}}, core.ScopeAdminRepo, http.StatusTeapot},
+		{&core.User{ID: 1, Scopes: []string{core.ScopeWriteBuild}}, core.ScopeReadRepo, http.StatusTeapot},
+		{&core.User{ID: 1, Scopes: []string{core.ScopeReadBuild}}, core.ScopeWriteBuild, http.StatusForbidden},
+		{&core.User{ID: 1, Scopes: []string{core.ScopeReadUser}}, core.ScopeAdminSystem, http.StatusForbidden},
+	}
+
+	for i, test := range tests {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("GET", "/", nil)
+		if test.user != nil {
+			r = r.WithContext(
+				request.WithUser(r.Context(), test.user),
+			)
+		}
+
+		CheckScope(test.scope)(
+			http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusTeapot)
+			}),
+		).ServeHTTP(w, r)
+
+		if got, want := w.Code, test.status; got != want {
+			t.Errorf("Want status code %d, got %d at index %d", want, got, i)
+		}
+	}
+}
+
+func TestAuthorizeUnscoped(t *testing.T) {
+	tests := []struct {
+		user   *core.User
+		status int
+	}{
+		{nil, http.StatusUnauthorized},
+		{&core.User{ID: 1}, http.StatusTeapot},
+		{&core.User{ID: 1, Scopes: []string{core.ScopeWriteUser}}, http.StatusForbidden},
+	}
+
+	for i, test := range tests {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("POST", "/", nil)
+		if test.user != nil {
+			r = r.WithContext(
+				request.WithUser(r.Context(), test.user),
+			)
+		}
+
+		AuthorizeUnscoped(
+			http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusTeapot)
+			}),
+		).ServeHTTP(w, r)
+
+		if got, want := w.Code, test.status; got != want {
+			t.Errorf("Want status code %d, got %d at index %d", want, got, i)
+		}
+	}
+}
@@ -6,25 +6,52 @@ package api
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/drone/drone/core"
 	"github.com/drone/drone/handler/api/acl"
+	"github.com/drone/drone/handler/api/audit"
 	"github.com/drone/drone/handler/api/auth"
 	"github.com/drone/drone/handler/api/badge"
 	globalbuilds "github.com/drone/drone/handler/api/builds"
 	"github.com/drone/drone/handler/api/ccmenu"
 	"github.com/drone/drone/handler/api/events"
+	"github.com/drone/drone/handler/api/graphql"
+	"github.com/drone/drone/handler/api/lint"
+	"github.com/drone/drone/handler/api/openapi"
+	"github.com/drone/drone/handler/api/policies"
+	"github.com/drone/drone/handler/api/privileged"
+	"github.com/drone/drone/handler/api/providers"
+	"github.com/drone/drone/handler/api/queue"
+	"github.com/drone/drone/handler/api/ratelimit"
+	orgregistries "github.com/drone/drone/handler/api/registries"
 	"github.com/drone/drone/handler/api/repos"
 	"github.com/drone/drone/handler/api/repos/builds"
+	"github.com/drone/drone/handler/api/repos/builds/artifacts"
 	"github.com/drone/drone/handler/api/repos/builds/logs"
 	"github.com/drone/drone/handler/api/repos/builds/stages"
+	"github.com/drone/drone/handler/api/repos/builds/tests"
 	"github.com/drone/drone/handler/api/repos/collabs"
+	"github.com/drone/drone/handler/api/repos/compile"
+	"github.com/drone/drone/handler/api/repos/coverage"
+	"github.com/drone/drone/handler/api/repos/deployments"
+	"github.com/drone/drone/handler/api/repos/hooks"
 	"github.com/drone/drone/handler/api/repos/crons"
+	"github.com/drone/drone/handler/api/repos/insights"
+	"github.com/drone/drone/handler/api/repos/mappings"
+	"github.com/drone/drone/handler/api/repos/notifications"
+	"github.com/drone/drone/handler/api/repos/refs"
+	"github.com/drone/drone/handler/api/repos/registries"
 	"github.com/drone/drone/handler/api/repos/secrets"
 	"github.com/drone/drone/handler/api/repos/sign"
+	"github.com/drone/drone/handler/api/repos/webhooks"
 	"github.com/drone/drone/handler/api/system"
 	"github.com/drone/drone/handler/api/user"
+	"github.com/drone/drone/handler/api/user/sessions"
+	"github.com/drone/drone/handler/api/user/tokens"
 	"github.com/drone/drone/handler/api/users"
+	"github.com/drone/drone/handler/api/variables"
+	globalwebhooks "github.com/drone/drone/handler/api/webhooks"
 	"github.com/drone/drone/logger"
 
 	"github.com/go-chi/chi"
@@ -42,79 +69,173 @@ var corsOpts = cors.Options{
 }
 
 func New(
+	access core.SecretAccessStore,
+	agents core.AgentRegistry,
+	annotations core.AnnotationStore,
+	artifacts core.ArtifactStore,
+	audit core.AuditStore,
 	builds core.BuildStore,
+	commits core.CommitService,
+	commands core.CommandService,
+	coverage core.CoverageStore,
 	cron core.CronStore,
+	deliveries core.WebhookDeliveryStore,
+	deployments core.DeploymentStore,
 	events core.Pubsub,
 	hooks core.HookService,
+	insights core.InsightStore,
 	logs core.LogStore,
 	license *core.License,
 	licenses core.LicenseService,
+	mappings core.PathMappingStore,
+	notifications core.NotificationStore,
+	orgs core.OrganizationService,
+	parser core.HookParser,
+	payloads core.HookPayloadStore,
 	perms core.PermStore,
+	policies core.PolicyStore,
+	privileged core.PrivilegedImageStore,
+	provenance core.ProvenanceService,
+	providers core.ProviderService,
+	references core.ReferenceService,
+	registries core.RegistryStore,
 	repos core.RepositoryStore,
 	repoz core.RepositoryService,
+	repoWebhooks core.RepoWebhookStore,
 	scheduler core.Scheduler,
 	secrets core.SecretStore,
 	stages core.StageStore,
 	steps core.StepStore,
 	status core.StatusService,
 	session core.Session,
+	sessions core.UserSessionStore,
 	stream core.LogStream,
 	syncer core.Syncer,
 	system *core.System,
+	tests core.TestResultStore,
+	tokens core.TokenStore,
 	triggerer core.Triggerer,
 	users core.UserStore,
+	variables core.VariableStore,
 	webhook core.WebhookSender,
 ) Server {
 	return Server{
-		Builds:    builds,
-		Cron:      cron,
-		Events:    events,
-		Hooks:     hooks,
-		Logs:      logs,
-		License:   license,
-		Licenses:  licenses,
-		Perms:     perms,
-		Repos:     repos,
-		Repoz:     repoz,
-		Scheduler: scheduler,
-		Secrets:   secrets,
-		Stages:    stages,
-		Steps:     steps,
-		Status:    status,
-		Session:   session,
-		Stream:    stream,
-		Syncer:    syncer,
-		System:    system,
-		Triggerer: triggerer,
-		Users:     users,
-		Webhook:   webhook,
+		Access:        access,
+		Agents:        agents,
+		Annotations:   annotations,
+		Artifacts:     artifacts,
+		Audit:         audit,
+		Builds:        builds,
+		Commits:       commits,
+		Commands:      commands,
+		Coverage:      coverage,
+		Cron:          cron,
+		Deliveries:    deliveries,
+		Deployments:   deployments,
+		Events:        events,
+		Hooks:         hooks,
+		Insights:      insights,
+		Logs:          logs,
+		Notifications: notifications,
+		Orgs:          orgs,
+		Parser:        parser,
+		Payloads:      payloads,
+		License:       license,
+		Licenses:      licenses,
+		Mappings:      mappings,
+		Perms:         perms,
+		Policies:      policies,
+		Privileged:    privileged,
+		Provenance:    provenance,
+		Providers:     providers,
+		References:    references,
+		Registries:    registries,
+		Repos:         repos,
+		Repoz:         repoz,
+		RepoWebhooks:  repoWebhooks,
+		Scheduler:     scheduler,
+		Secrets:       secrets,
+		Stages:        stages,
+		Steps:         steps,
+		Status:        status,
+		Session:       session,
+		Sessions:      sessions,
+		Stream:        stream,
+		Syncer:        syncer,
+		System:        system,
+		Tests:         tests,
+		Tokens:        tokens,
+		Triggerer:     triggerer,
+		Users:         users,
+		Variables:     variables,
+		Webhook:       webhook,
 	}
 }
 
 // Server is a http.Handler which exposes drone functionality over HTTP.
 type Server struct {
-	Builds    core.BuildStore
-	Cron      core.CronStore
-	Events    core.Pubsub
-	Hooks     core.HookService
-	Logs      core.LogStore
-	License   *core.License
-	Licenses  core.LicenseService
-	Perms     core.PermStore
-	Repos     core.RepositoryStore
-	Repoz     core.RepositoryService
-	Scheduler core.Scheduler
-	Secrets   core.SecretStore
-	Stages    core.StageStore
-	Steps     core.StepStore
-	Status    core.StatusService
-	Session   core.Session
-	Stream    core.LogStream
-	Syncer    core.Syncer
-	System    *core.System
-	Triggerer core.Triggerer
-	Users     core.UserStore
-	Webhook   core.WebhookSender
+	Access        core.SecretAccessStore
+	Agents        core.AgentRegistry
+	Annotations   core.AnnotationStore
+	Artifacts     core.ArtifactStore
+	Audit         core.AuditStore
+	Builds        core.BuildStore
+	Commits       core.CommitService
+	Commands      core.CommandService
+	Coverage      core.CoverageStore
+	Cron          core.CronStore
+	Deliveries    core.WebhookDeliveryStore
+	Deployments   core.DeploymentStore
+	Events        core.Pubsub
+	Hooks         core.HookService
+	Insights      core.InsightStore
+	Logs          core.LogStore
+	Notifications core.NotificationStore
+	Orgs          core.OrganizationService
+	Parser        core.HookParser
+	Payloads      core.HookPayloadStore
+	License       *core.License
+	Licenses      core.LicenseService
+	Mappings      core.PathMappingStore
+	Perms         core.PermStore
+	Policies      core.PolicyStore
+	Privileged    core.PrivilegedImageStore
+	Provenance    core.ProvenanceService
+	Providers     core.ProviderService
+	References    core.ReferenceService
+	Registries    core.RegistryStore
+	Repos         core.RepositoryStore
+	Repoz         core.RepositoryService
+	RepoWebhooks  core.RepoWebhookStore
+	Scheduler     core.Scheduler
+	Secrets       core.SecretStore
+	Stages        core.StageStore
+	Steps         core.StepStore
+	Status        core.StatusService
+	Session       core.Session
+	Sessions      core.UserSessionStore
+	Stream        core.LogStream
+	Syncer        core.Syncer
+	System        *core.System
+	Tests         core.TestResultStore
+	Tokens        core.TokenStore
+	Triggerer     core.Triggerer
+	Users         core.UserStore
+	Variables     core.VariableStore
+	Webhook       core.WebhookSender
+
+	// ProtectBadges requires read access to serve status
+	// badges for private and internal repositories. By
+	// default badges are served without authentication.
+	ProtectBadges bool
+
+	// Limiter limits the rate of api requests per client.
+	// By default requests are not rate limited.
+	Limiter *ratelimit.Limiter
+
+	// SignerGrace is the period the previous repository
+	// webhook secret remains valid after it is rotated.
+	SignerGrace time.Duration
 }
 
 // Handler returns an http.Handler
@@ -124,48 +245,134 @@ func (s Server) Handler() http.Handler {
 	r.Use(middleware.NoCache)
 	r.Use(logger.Middleware)
 	r.Use(auth.HandleAuthentication(s.Session))
+	if s.Limiter != nil {
+		r.Use(s.Limiter.Handler)
+	}
 
 	cors := cors.New(corsOpts)
 	r.Use(cors.Handler)
 
+	r.With(
+		acl.AuthorizeAdmin,
+		acl.CheckScope(core.ScopeAdminSystem),
+		audit.Record(s.Audit, core.AuditRepoEnable),
+	).Method(http.MethodPost, "/repos/enable", openapi.Op("repos_HandleEnableBulk", openapi.AccessAdmin, repos.HandleEnableBulk(s.Hooks, s.Repos, s.Webhook)))
+	r.With(
+		acl.AuthorizeAdmin,
+		acl.CheckScope(core.ScopeAdminSystem),
+		audit.Record(s.Audit, core.AuditRepoDisable),
+	).Method(http.MethodPost, "/repos/disable", openapi.Op("repos_HandleDisableBulk", openapi.AccessAdmin, repos.HandleDisableBulk(s.Repos, s.Webhook)))
+
 	r.Route("/repos/{owner}/{name}", func(r chi.Router) {
 		r.Use(acl.InjectRepository(s.Repoz, s.Repos, s.Perms))
 		r.Use(acl.CheckReadAccess())
+		r.Use(acl.CheckScope(core.ScopeReadRepo))
 
-		r.Get("/", repos.HandleFind())
+		r.Method(http.MethodGet, "/", openapi.Op("repos_HandleFind", openapi.AccessRead, repos.HandleFind()))
 		r.With(
 			acl.CheckAdminAccess(),
-		).Patch("/", repos.HandleUpdate(s.Repos))
+			acl.CheckScope(core.ScopeAdminRepo),
+		).Method(http.MethodPatch, "/", openapi.Op("repos_HandleUpdate", openapi.AccessRepoAdmin, repos.HandleUpdate(s.Repos)))
 		r.With(
 			acl.CheckAdminAccess(),
-		).Post("/", repos.HandleEnable(s.Hooks, s.Repos, s.Webhook))
+			acl.CheckScope(core.ScopeAdminRepo),
+			audit.Record(s.Audit, core.AuditRepoEnable),
+		).Method(http.MethodPost, "/", openapi.Op("repos_HandleEnable", openapi.AccessRepoAdmin, repos.HandleEnable(s.Hooks, s.Repos, s.Webhook)))
 		r.With(
 			acl.CheckAdminAccess(),
-		).Delete("/", repos.HandleDisable(s.Repos, s.Webhook))
+			acl.CheckScope(core.ScopeAdminRepo),
+			audit.Record(s.Audit, core.AuditRepoDisable),
+		).Method(http.MethodDelete, "/", openapi.Op("repos_HandleDisable", openapi.AccessRepoAdmin, repos.HandleDisable(s.Repos, s.Webhook)))
 		r.With(
 			acl.CheckAdminAccess(),
-		).Post("/chown", repos.HandleChown(s.Repos))
+			acl.CheckScope(core.ScopeAdminRepo),
+		).Method(http.MethodPost, "/chown", openapi.Op("repos_HandleChown", openapi.AccessRepoAdmin, repos.HandleChown(s.Repos)))
+		r.With(
+			acl.CheckAdminAccess(),
+			acl.CheckScope(core.ScopeAdminRepo),
+		).Method(http.MethodPost, "/repair", openapi.Op("repos_HandleRepair", openapi.AccessRepoAdmin, repos.HandleRepair(s.Hooks, s.Repoz, s.Repos, s.Users, s.System.Link)))
+		r.With(
+			acl.CheckAdminAccess(),
+			acl.CheckScope(core.ScopeAdminRepo),
+			audit.Record(s.Audit, core.AuditSignerRotate),
+		).Method(http.MethodPost, "/rotate", openapi.Op("repos_HandleRotate", openapi.AccessRepoAdmin, repos.HandleRotate(s.Hooks, s.Repos, s.Users, s.SignerGrace)))
+		r.With(
+			acl.CheckAdminAccess(),
+			acl.CheckScope(core.ScopeAdminRepo),
+		).Method(http.MethodGet, "/export", openapi.Op("repos_HandleExport", openapi.AccessRepoAdmin, repos.HandleExport(s.Repos, s.Secrets, s.Cron)))
+		r.With(
+			acl.CheckAdminAccess(),
+			acl.CheckScope(core.ScopeAdminRepo),
+		).Method(http.MethodPost, "/import", openapi.Op("repos_HandleImport", openapi.AccessRepoAdmin, repos.HandleImport(s.Repos, s.Secrets, s.Cron)))
+
+		r.Method(http.MethodGet, "/branches", openapi.Op("refs_HandleBranches", openapi.AccessRead, refs.HandleBranches(s.Repos, s.Users, s.References)))
+		r.Method(http.MethodGet, "/tags", openapi.Op("refs_HandleTags", openapi.AccessRead, refs.HandleTags(s.Repos, s.Users, s.References)))
+
+		r.With(
+			acl.CheckScope(core.ScopeReadBuild),
+		).Method(http.MethodGet, "/coverage", openapi.Op("coverage_HandleList", openapi.AccessRead, coverage.HandleList(s.Repos, s.Coverage)))
+
+		r.Route("/deployments", func(r chi.Router) {
+			r.Use(acl.CheckScope(core.ScopeReadBuild))
+			r.Method(http.MethodGet, "/", openapi.Op("deployments_HandleLatest", openapi.AccessRead, deployments.HandleLatest(s.Repos, s.Deployments)))
+			r.Method(http.MethodGet, "/{environment}", openapi.Op("deployments_HandleList", openapi.AccessRead, deployments.HandleList(s.Repos, s.Deployments)))
+		})
+
+		r.Route("/insights", func(r chi.Router) {
+			r.Use(acl.CheckScope(core.ScopeReadBuild))
+			r.Method(http.MethodGet, "/", openapi.Op("insights_HandleFind", openapi.AccessRead, insights.HandleFind(s.Repos, s.Insights)))
+			r.Method(http.MethodGet, "/days", openapi.Op("insights_HandleListDays", openapi.AccessRead, insights.HandleListDays(s.Repos, s.Insights)))
+			r.Method(http.MethodGet, "/steps", openapi.Op("insights_HandleListSteps", openapi.AccessRead, insights.HandleListSteps(s.Repos, s.Insights)))
+			r.Method(http.MethodGet, "/flaky", openapi.Op("insights_HandleListFlaky", openapi.AccessRead, insights.HandleListFlaky(s.Repos, s.Insights)))
+		})
+
 		r.With(
 			acl.CheckAdminAccess(),
-		).Post("/repair", repos.HandleRepair(s.Hooks, s.Repoz, s.Repos, s.Users, s.System.Link))
+			acl.CheckScope(core.ScopeAdminRepo),
+		).Method(http.MethodGet, "/audit", openapi.Op("audit_HandleRepoList", openapi.AccessRepoAdmin, audit.HandleList(s.Audit)))
 
 		r.Route("/builds", func(r chi.Router) {
-			r.Get("/", builds.HandleList(s.Repos, s.Builds))
-			r.Get("/latest", builds.HandleLast(s.Repos, s.Builds, s.Stages))
-			r.Get("/{number}", builds.HandleFind(s.Repos, s.Builds, s.Stages))
-			r.Get("/{number}/logs/{stage}/{step}", logs.HandleFind(s.Repos, s.Builds, s.Stages, s.Steps, s.Logs))
+			r.Use(acl.CheckScope(core.ScopeReadBuild))
+			r.Method(http.MethodGet, "/", openapi.Op("builds_HandleList", openapi.AccessRead, builds.HandleList(s.Repos, s.Builds)))
 
 			r.With(
 				acl.CheckWriteAccess(),
-			).Post("/{number}", builds.HandleRetry(s.Repos, s.Builds, s.Triggerer))
+				acl.CheckScope(core.ScopeWriteBuild),
+			).Method(http.MethodPost, "/", openapi.Op("builds_HandleCreate", openapi.AccessWrite, builds.HandleCreate(s.Users, s.Repos, s.Commits, s.Triggerer)))
+
+			r.Method(http.MethodGet, "/latest", openapi.Op("builds_HandleLast", openapi.AccessRead, builds.HandleLast(s.Repos, s.Builds, s.Stages)))
 
 			r.With(
 				acl.CheckWriteAccess(),
-			).Delete("/{number}", builds.HandleCancel(s.Users, s.Repos, s.Builds, s.Stages, s.Steps, s.Status, s.Scheduler, s.Webhook))
+				acl.CheckScope(core.ScopeWriteBuild),
+			).Method(http.MethodPost, "/latest", openapi.Op("builds_HandleRebuild", openapi.AccessWrite, builds.HandleRebuild(s.Users, s.Repos, s.Builds, s.Commits, s.Triggerer)))
+
+			r.Method(http.MethodGet, "/{number}", openapi.Op("builds_HandleFind", openapi.AccessRead, builds.HandleFind(s.Repos, s.Builds, s.Stages, s.Artifacts, s.Annotations)))
+			r.Method(http.MethodGet, "/{number}/logs/{stage}/{step}", openapi.Op("logs_HandleFind", openapi.AccessRead, logs.HandleFind(s.Repos, s.Builds, s.Stages, s.Steps, s.Logs)))
+			r.Method(http.MethodGet, "/{number}/artifacts", openapi.Op("artifacts_HandleList", openapi.AccessRead, artifacts.HandleList(s.Repos, s.Builds, s.Artifacts)))
+			r.Method(http.MethodGet, "/{number}/artifacts/{artifact}", openapi.Op("artifacts_HandleFind", openapi.AccessRead, artifacts.HandleFind(s.Repos, s.Builds, s.Artifacts)))
+			r.Method(http.MethodGet, "/{number}/tests", openapi.Op("tests_HandleList", openapi.AccessRead, tests.HandleList(s.Repos, s.Builds, s.Tests)))
+			r.Method(http.MethodGet, "/{number}/coverage", openapi.Op("coverage_HandleBuild", openapi.AccessRead, coverage.HandleBuild(s.Repos, s.Builds, s.Coverage)))
+
+			if s.Provenance != nil {
+				r.Method(http.MethodGet, "/{number}/provenance", openapi.Op("builds_HandleProvenance", openapi.AccessRead, builds.HandleProvenance(s.Repos, s.Builds, s.Stages, s.Provenance)))
+			}
+
+			r.With(
+				acl.CheckWriteAccess(),
+				acl.CheckScope(core.ScopeWriteBuild),
+			).Method(http.MethodPost, "/{number}", openapi.Op("builds_HandleRetry", openapi.AccessWrite, builds.HandleRetry(s.Repos, s.Builds, s.Triggerer)))
+
+			r.With(
+				acl.CheckWriteAccess(),
+				acl.CheckScope(core.ScopeWriteBuild),
+				audit.Record(s.Audit, core.AuditBuildCancel),
+			).Method(http.MethodDelete, "/{number}", openapi.Op("builds_HandleCancel", openapi.AccessWrite, builds.HandleCancel(s.Users, s.Repos, s.Builds, s.Stages, s.Steps, s.Status, s.Scheduler, s.Webhook)))
 
 			r.With(
 				acl.CheckAdminAccess(),
-			).Post("/{number}/promote", builds.HandlePromote(s.Repos, s.Builds, s.Triggerer))
+				acl.CheckScope(core.ScopeWriteBuild),
+			).Method(http.MethodPost, "/{number}/promote", openapi.Op("builds_HandlePromote", openapi.AccessRepoAdmin, builds.HandlePromote(s.Repos, s.Builds, s.Triggerer)))
 
 			// r.With(
 			// 	acl.CheckAdminAccess(),
@@ -173,121 +380,410 @@ func (s Server) Handler() http.Handler {
 
 			r.With(
 				acl.CheckAdminAccess(),
-			).Post("/{number}/decline/{stage}", stages.HandleDecline(s.Repos, s.Builds, s.Stages))
+				acl.CheckScope(core.ScopeWriteBuild),
+				audit.Record(s.Audit, core.AuditStageDecline),
+			).Method(http.MethodPost, "/{number}/decline/{stage}", openapi.Op("stages_HandleDecline", openapi.AccessRepoAdmin, stages.HandleDecline(s.Repos, s.Builds, s.Stages)))
 
 			r.With(
 				acl.CheckAdminAccess(),
-			).Post("/{number}/approve/{stage}", stages.HandleApprove(s.Repos, s.Builds, s.Stages, s.Scheduler))
+				acl.CheckScope(core.ScopeWriteBuild),
+				audit.Record(s.Audit, core.AuditStageApprove),
+			).Method(http.MethodPost, "/{number}/approve/{stage}", openapi.Op("stages_HandleApprove", openapi.AccessRepoAdmin, stages.HandleApprove(s.Repos, s.Builds, s.Stages, s.Scheduler)))
 
 			r.With(
 				acl.CheckAdminAccess(),
-			).Delete("/{number}/logs/{stage}/{step}", logs.HandleDelete(s.Repos, s.Builds, s.Stages, s.Steps, s.Logs))
+				acl.CheckScope(core.ScopeAdminRepo),
+			).Method(http.MethodDelete, "/{number}/logs/{stage}/{step}", openapi.Op("logs_HandleDelete", openapi.AccessRepoAdmin, logs.HandleDelete(s.Repos, s.Builds, s.Stages, s.Steps, s.Logs)))
 
 			r.With(
 				acl.CheckAdminAccess(),
-			).Delete("/", builds.HandlePurge(s.Repos, s.Builds))
+				acl.CheckScope(core.ScopeAdminRepo),
+			).Method(http.MethodDelete, "/", openapi.Op("builds_HandlePurge", openapi.AccessRepoAdmin, builds.HandlePurge(s.Repos, s.Builds)))
 
 		})
 
 		r.Route("/secrets", func(r chi.Router) {
-			r.Use(acl.CheckWriteAccess())
-			r.Get("/", secrets.HandleList(s.Repos, s.Secrets))
-			r.Post("/", secrets.HandleCreate(s.Repos, s.Secrets))
-			r.Get("/{secret}", secrets.HandleFind(s.Repos, s.Secrets))
-			r.Patch("/{secret}", secrets.HandleUpdate(s.Repos, s.Secrets))
-			r.Delete("/{secret}", secrets.HandleDelete(s.Repos, s.Secrets))
+			r.Use(acl.CheckAdminAccess())
+			r.Use(acl.CheckScope(core.ScopeAdminRepo))
+			r.Method(http.MethodGet, "/", openapi.Op("secrets_HandleList", openapi.AccessRepoAdmin, secrets.HandleList(s.Repos, s.Secrets)))
+			r.With(
+				audit.Record(s.Audit, core.AuditSecretCreate),
+			).Method(http.MethodPost, "/", openapi.Op("secrets_HandleCreate", openapi.AccessRepoAdmin, secrets.HandleCreate(s.Repos, s.Secrets)))
+			r.Method(http.MethodGet, "/{secret}", openapi.Op("secrets_HandleFind", openapi.AccessRepoAdmin, secrets.HandleFind(s.Repos, s.Secrets)))
+			r.Method(http.MethodGet, "/{secret}/access", openapi.Op("secrets_HandleAccess", openapi.AccessRepoAdmin, secrets.HandleAccess(s.Repos, s.Secrets, s.Access)))
+			r.With(
+				audit.Record(s.Audit, core.AuditSecretUpdate),
+			).Method(http.MethodPatch, "/{secret}", openapi.Op("secrets_HandleUpdate", openapi.AccessRepoAdmin, secrets.HandleUpdate(s.Repos, s.Secrets)))
+			r.With(
+				audit.Record(s.Audit, core.AuditSecretDelete),
+			).Method(http.MethodDelete, "/{secret}", openapi.Op("secrets_HandleDelete", openapi.AccessRepoAdmin, secrets.HandleDelete(s.Repos, s.Secrets)))
 		})
 
+		r.Route("/webhooks", func(r chi.Router) {
+			r.Use(acl.CheckAdminAccess())
+			r.Use(acl.CheckScope(core.ScopeAdminRepo))
+			r.Method(http.MethodGet, "/", openapi.Op("webhooks_HandleList", openapi.AccessRepoAdmin, webhooks.HandleList(s.Repos, s.RepoWebhooks)))
+			r.With(
+				audit.Record(s.Audit, core.AuditWebhookCreate),
+			).Method(http.MethodPost, "/", openapi.Op("webhooks_HandleCreate", openapi.AccessRepoAdmin, webhooks.HandleCreate(s.Repos, s.RepoWebhooks)))
+			r.Method(http.MethodGet, "/{webhook}", openapi.Op("webhooks_HandleFind", openapi.AccessRepoAdmin, webhooks.HandleFind(s.Repos, s.RepoWebhooks)))
+			r.With(
+				audit.Record(s.Audit, core.AuditWebhookUpdate),
+			).Method(http.MethodPatch, "/{webhook}", openapi.Op("webhooks_HandleUpdate", openapi.AccessRepoAdmin, webhooks.HandleUpdate(s.Repos, s.RepoWebhooks)))
+			r.With(
+				audit.Record(s.Audit, core.AuditWebhookDelete),
+			).Method(http.MethodDelete, "/{webhook}", openapi.Op("webhooks_HandleDelete", openapi.AccessRepoAdmin, webhooks.HandleDelete(s.Repos, s.RepoWebhooks)))
+		})
+
+		r.Route("/hooks", func(r chi.Router) {
+			r.Use(acl.CheckAdminAccess())
+			r.Use(acl.CheckScope(core.ScopeAdminRepo))
+			r.Method(http.MethodGet, "/", openapi.Op("hooks_HandleList", openapi.AccessRepoAdmin, hooks.HandleList(s.Repos, s.Payloads)))
+			r.Method(http.MethodGet, "/{hook}", openapi.Op("hooks_HandleFind", openapi.AccessRepoAdmin, hooks.HandleFind(s.Repos, s.Payloads)))
+			r.With(
+				audit.Record(s.Audit, core.AuditHookReplay),
+			).Method(http.MethodPost, "/{hook}", openapi.Op("hooks_HandleReplay", openapi.AccessRepoAdmin, hooks.HandleReplay(s.Repos, s.Payloads, s.Parser, s.Triggerer, s.Commands)))
+		})
+
+		r.Route("/notifications", func(r chi.Router) {
+			r.Use(acl.CheckAdminAccess())
+			r.Use(acl.CheckScope(core.ScopeAdminRepo))
+			r.Method(http.MethodGet, "/", openapi.Op("notifications_HandleList", openapi.AccessRepoAdmin, notifications.HandleList(s.Repos, s.Notifications)))
+			r.With(
+				audit.Record(s.Audit, core.AuditNotificationCreate),
+			).Method(http.MethodPost, "/", openapi.Op("notifications_HandleCreate", openapi.AccessRepoAdmin, notifications.HandleCreate(s.Repos, s.Notifications)))
+			r.Method(http.MethodGet, "/{notification}", openapi.Op("notifications_HandleFind", openapi.AccessRepoAdmin, notifications.HandleFind(s.Repos, s.Notifications)))
+			r.With(
+				audit.Record(s.Audit, core.AuditNotificationUpdate),
+			).Method(http.MethodPatch, "/{notification}", openapi.Op("notifications_HandleUpdate", openapi.AccessRepoAdmin, notifications.HandleUpdate(s.Repos, s.Notifications)))
+			r.With(
+				audit.Record(s.Audit, core.AuditNotificationDelete),
+			).Method(http.MethodDelete, "/{notification}", openapi.Op("notifications_HandleDelete", openapi.AccessRepoAdmin, notifications.HandleDelete(s.Repos, s.Notifications)))
+		})
+
+		r.Route("/registries", func(r chi.Router) {
+			r.Use(acl.CheckAdminAccess())
+			r.Use(acl.CheckScope(core.ScopeAdminRepo))
+			r.Method(http.MethodGet, "/", openapi.Op("registries_HandleList", openapi.AccessRepoAdmin, registries.HandleList(s.Repos, s.Registries)))
+			r.With(
+				audit.Record(s.Audit, core.AuditRegistryCreate),
+			).Method(http.MethodPost, "/", openapi.Op("registries_HandleCreate", openapi.AccessRepoAdmin, registries.HandleCreate(s.Repos, s.Registries)))
+			r.Method(http.MethodGet, "/{registry}", openapi.Op("registries_HandleFind", openapi.AccessRepoAdmin, registries.HandleFind(s.Repos, s.Registries)))
+			r.With(
+				audit.Record(s.Audit, core.AuditRegistryUpdate),
+			).Method(http.MethodPatch, "/{registry}", openapi.Op("registries_HandleUpdate", openapi.AccessRepoAdmin, registries.HandleUpdate(s.Repos, s.Registries)))
+			r.With(
+				audit.Record(s.Audit, core.AuditRegistryDelete),
+			).Method(http.MethodDelete, "/{registry}", openapi.Op("registries_HandleDelete", openapi.AccessRepoAdmin, registries.HandleDelete(s.Repos, s.Registries)))
+		})
+
+		r.Method(http.MethodPost, "/lint", openapi.Op("lint_HandleRepoLint", openapi.AccessRead, lint.HandleLint()))
+		r.With(
+			acl.AuthorizeUser,
+		).Method(http.MethodPost, "/compile", openapi.Op("compile_HandleCompile", openapi.AccessUser, compile.HandleCompile(s.Repos, s.System)))
+
 		r.Route("/sign", func(r chi.Router) {
-			r.Use(acl.CheckWriteAccess())
-			r.Post("/", sign.HandleSign(s.Repos))
+			r.Use(acl.CheckAdminAccess())
+			r.Use(acl.CheckScope(core.ScopeAdminRepo))
+			r.Method(http.MethodPost, "/", openapi.Op("sign_HandleSign", openapi.AccessRepoAdmin, sign.HandleSign(s.Repos)))
 		})
 
 		r.Route("/cron", func(r chi.Router) {
 			r.Use(acl.CheckWriteAccess())
-			r.Post("/", crons.HandleCreate(s.Repos, s.Cron))
-			r.Get("/", crons.HandleList(s.Repos, s.Cron))
-			r.Get("/{cron}", crons.HandleFind(s.Repos, s.Cron))
-			r.Patch("/{cron}", crons.HandleUpdate(s.Repos, s.Cron))
-			r.Delete("/{cron}", crons.HandleDelete(s.Repos, s.Cron))
+			r.Use(acl.CheckScope(core.ScopeAdminRepo))
+			r.Method(http.MethodPost, "/", openapi.Op("crons_HandleCreate", openapi.AccessWrite, crons.HandleCreate(s.Repos, s.Cron)))
+			r.Method(http.MethodGet, "/", openapi.Op("crons_HandleList", openapi.AccessWrite, crons.HandleList(s.Repos, s.Cron)))
+			r.Method(http.MethodGet, "/{cron}", openapi.Op("crons_HandleFind", openapi.AccessWrite, crons.HandleFind(s.Repos, s.Cron)))
+			r.Method(http.MethodPatch, "/{cron}", openapi.Op("crons_HandleUpdate", openapi.AccessWrite, crons.HandleUpdate(s.Repos, s.Cron)))
+			r.Method(http.MethodDelete, "/{cron}", openapi.Op("crons_HandleDelete", openapi.AccessWrite, crons.HandleDelete(s.Repos, s.Cron)))
+		})
+
+		r.Route("/mappings", func(r chi.Router) {
+			r.Use(acl.CheckWriteAccess())
+			r.Use(acl.CheckScope(core.ScopeAdminRepo))
+			r.Method(http.MethodPost, "/", openapi.Op("mappings_HandleCreate", openapi.AccessWrite, mappings.HandleCreate(s.Repos, s.Mappings)))
+			r.Method(http.MethodGet, "/", openapi.Op("mappings_HandleList", openapi.AccessWrite, mappings.HandleList(s.Repos, s.Mappings)))
+			r.Method(http.MethodDelete, "/{mapping}", openapi.Op("mappings_HandleDelete", openapi.AccessWrite, mappings.HandleDelete(s.Repos, s.Mappings)))
 		})
 
 		r.Route("/collaborators", func(r chi.Router) {
-			r.Get("/", collabs.HandleList(s.Repos, s.Perms))
-			r.Get("/{member}", collabs.HandleFind(s.Users, s.Repos, s.Perms))
+			r.Method(http.MethodGet, "/", openapi.Op("collabs_HandleList", openapi.AccessRead, collabs.HandleList(s.Repos, s.Perms)))
+			r.Method(http.MethodGet, "/{member}", openapi.Op("collabs_HandleFind", openapi.AccessRead, collabs.HandleFind(s.Users, s.Repos, s.Perms)))
 			r.With(
 				acl.CheckAdminAccess(),
-			).Delete("/{member}", collabs.HandleDelete(s.Users, s.Repos, s.Perms))
+				acl.CheckScope(core.ScopeAdminRepo),
+			).Method(http.MethodPost, "/{member}", openapi.Op("collabs_HandleUpdate", openapi.AccessRepoAdmin, collabs.HandleUpdate(s.Users, s.Repos, s.Perms)))
+			r.With(
+				acl.CheckAdminAccess(),
+				acl.CheckScope(core.ScopeAdminRepo),
+			).Method(http.MethodDelete, "/{member}", openapi.Op("collabs_HandleDelete", openapi.AccessRepoAdmin, collabs.HandleDelete(s.Users, s.Repos, s.Perms)))
 		})
 	})
 
+	r.With(
+		acl.AuthorizeUser,
+	).Method(http.MethodPost, "/lint", openapi.Op("lint_HandleLint", openapi.AccessUser, lint.HandleLint()))
+
 	r.Route("/badges/{owner}/{name}", func(r chi.Router) {
-		r.Get("/status.svg", badge.Handler(s.Repos, s.Builds))
+		r.Group(func(r chi.Router) {
+			if s.ProtectBadges {
+				r.Use(acl.InjectRepository(s.Repoz, s.Repos, s.Perms))
+				r.Use(acl.CheckReadAccess())
+			}
+			r.Method(http.MethodGet, "/status.svg", openapi.Op("badge_Handler", openapi.AccessNone, badge.Handler(s.Repos, s.Builds)))
+			r.Method(http.MethodGet, "/coverage.svg", openapi.Op("badge_Coverage", openapi.AccessNone, badge.Coverage(s.Repos, s.Coverage)))
+		})
 		r.With(
 			acl.InjectRepository(s.Repoz, s.Repos, s.Perms),
 			acl.CheckReadAccess(),
-		).Get("/cc.xml", ccmenu.Handler(s.Repos, s.Builds, s.System.Link))
+		).Method(http.MethodGet, "/cc.xml", openapi.Op("ccmenu_Handler", openapi.AccessRead, ccmenu.Handler(s.Repos, s.Builds, s.System.Link)))
 	})
 
-	// r.Route("/queue", func(r chi.Router) {
-	// 	r.Use(acl.AuthorizeAdmin)
-	// 	r.Get("/", queue.HandleItems(s.Stages))
-	// 	r.Post("/", queue.HandleResume(s.Queue))
-	// 	r.Delete("/", queue.HandlePause(s.Queue))
-	// })
-
 	r.Route("/user", func(r chi.Router) {
 		r.Use(acl.AuthorizeUser)
-		r.Get("/", user.HandleFind())
-		r.Patch("/", user.HandleUpdate(s.Users))
-		r.Post("/token", user.HandleToken(s.Users))
-		r.Get("/repos", user.HandleRepos(s.Repos))
-		r.Post("/repos", user.HandleSync(s.Syncer, s.Repos))
+		r.Use(acl.CheckScope(core.ScopeReadUser))
+		r.Method(http.MethodGet, "/", openapi.Op("user_HandleFind", openapi.AccessUser, user.HandleFind()))
+		r.With(
+			acl.CheckScope(core.ScopeWriteUser),
+		).Method(http.MethodPatch, "/", openapi.Op("user_HandleUpdate", openapi.AccessUser, user.HandleUpdate(s.Users)))
+		r.With(
+			acl.CheckScope(core.ScopeWriteUser),
+			acl.AuthorizeUnscoped,
+		).Method(http.MethodPost, "/token", openapi.Op("user_HandleToken", openapi.AccessUser, user.HandleToken(s.Users)))
+		r.Method(http.MethodGet, "/repos", openapi.Op("user_HandleRepos", openapi.AccessUser, user.HandleRepos(s.Repos)))
+		r.With(
+			acl.CheckScope(core.ScopeWriteUser),
+		).Method(http.MethodPost, "/repos", openapi.Op("user_HandleSync", openapi.AccessUser, user.HandleSync(s.Syncer, s.Repos)))
+		r.With(
+			acl.CheckScope(core.ScopeWriteUser),
+		).Method(http.MethodPost, "/refresh", openapi.Op("user_HandleRefresh", openapi.AccessUser, user.HandleRefresh(s.Orgs)))
+
+		r.Route("/tokens", func(r chi.Router) {
+			r.Method(http.MethodGet, "/", openapi.Op("tokens_HandleList", openapi.AccessUser, tokens.HandleList(s.Tokens)))
+			r.With(
+				acl.CheckScope(core.ScopeWriteUser),
+				acl.AuthorizeUnscoped,
+			).Method(http.MethodPost, "/", openapi.Op("tokens_HandleCreate", openapi.AccessUser, tokens.HandleCreate(s.Tokens)))
+			r.With(
+				acl.CheckScope(core.ScopeWriteUser),
+			).Method(http.MethodDelete, "/{token}", openapi.Op("tokens_HandleDelete", openapi.AccessUser, tokens.HandleDelete(s.Tokens)))
+			r.With(
+				acl.CheckScope(core.ScopeWriteUser),
+			).Method(http.MethodDelete, "/", openapi.Op("tokens_HandleDeleteAll", openapi.AccessUser, tokens.HandleDeleteAll(s.Tokens)))
+		})
+
+		r.Route("/sessions", func(r chi.Router) {
+			r.Method(http.MethodGet, "/", openapi.Op("sessions_HandleList", openapi.AccessUser, sessions.HandleList(s.Sessions)))
+			r.With(
+				acl.CheckScope(core.ScopeWriteUser),
+			).Method(http.MethodDelete, "/{session}", openapi.Op("sessions_HandleDelete", openapi.AccessUser, sessions.HandleDelete(s.Sessions)))
+			r.With(
+				acl.CheckScope(core.ScopeWriteUser),
+			).Method(http.MethodDelete, "/", openapi.Op("sessions_HandleDeleteAll", openapi.AccessUser, sessions.HandleDeleteAll(s.Sessions)))
+		})
 
 		// TODO(bradrydzewski) finalize the name for this endpoint.
-		r.Get("/builds", user.HandleRecent(s.Repos))
-		r.Get("/builds/recent", user.HandleRecent(s.Repos))
+		r.Method(http.MethodGet, "/builds", openapi.Op("user_HandleBuilds", openapi.AccessUser, user.HandleRecent(s.Repos)))
+		r.Method(http.MethodGet, "/builds/recent", openapi.Op("user_HandleRecent", openapi.AccessUser, user.HandleRecent(s.Repos)))
 	})
 
 	r.Route("/users", func(r chi.Router) {
 		r.Use(acl.AuthorizeAdmin)
-		r.Get("/", users.HandleList(s.Users))
-		r.Post("/", users.HandleCreate(s.Users, s.Webhook))
-		r.Get("/{user}", users.HandleFind(s.Users))
-		r.Patch("/{user}", users.HandleUpdate(s.Users))
-		r.Delete("/{user}", users.HandleDelete(s.Users, s.Webhook))
+		r.Use(acl.CheckScope(core.ScopeAdminSystem))
+		r.Method(http.MethodGet, "/", openapi.Op("users_HandleList", openapi.AccessAdmin, users.HandleList(s.Users)))
+		r.With(
+			audit.Record(s.Audit, core.AuditUserCreate),
+		).Method(http.MethodPost, "/", openapi.Op("users_HandleCreate", openapi.AccessAdmin, users.HandleCreate(s.Users, s.Webhook)))
+		r.Method(http.MethodGet, "/{user}", openapi.Op("users_HandleFind", openapi.AccessAdmin, users.HandleFind(s.Users)))
+		r.With(
+			audit.Record(s.Audit, core.AuditUserUpdate),
+		).Method(http.MethodPatch, "/{user}", openapi.Op("users_HandleUpdate", openapi.AccessAdmin, users.HandleUpdate(s.Users)))
+		r.With(
+			audit.Record(s.Audit, core.AuditUserDelete),
+		).Method(http.MethodDelete, "/{user}", openapi.Op("users_HandleDelete", openapi.AccessAdmin, users.HandleDelete(s.Users, s.Webhook)))
+		r.Method(http.MethodGet, "/{user}/tokens", openapi.Op("tokens_HandleMachineList", openapi.AccessAdmin, tokens.HandleMachineList(s.Users, s.Tokens)))
+		r.With(
+			acl.AuthorizeUnscoped,
+		).Method(http.MethodPost, "/{user}/tokens", openapi.Op("tokens_HandleMachineCreate", openapi.AccessAdmin, tokens.HandleMachineCreate(s.Users, s.Tokens)))
+		r.Method(http.MethodDelete, "/{user}/tokens/{token}", openapi.Op("tokens_HandleMachineDelete", openapi.AccessAdmin, tokens.HandleMachineDelete(s.Users, s.Tokens)))
+		r.Method(http.MethodGet, "/{user}/sessions", openapi.Op("sessions_HandleUserList", openapi.AccessAdmin, sessions.HandleUserList(s.Users, s.Sessions)))
+		r.With(
+			audit.Record(s.Audit, core.AuditSessionRevoke),
+		).Method(http.MethodDelete, "/{user}/sessions", openapi.Op("sessions_HandleUserDeleteAll", openapi.AccessAdmin, sessions.HandleUserDeleteAll(s.Users, s.Sessions)))
+		r.With(
+			audit.Record(s.Audit, core.AuditSessionRevoke),
+		).Method(http.MethodDelete, "/{user}/sessions/{session}", openapi.Op("sessions_HandleUserDelete", openapi.AccessAdmin, sessions.HandleUserDelete(s.Users, s.Sessions)))
+	})
+
+	r.Route("/registries/{namespace}", func(r chi.Router) {
+		r.Use(acl.AuthorizeAdmin)
+		r.Use(acl.CheckScope(core.ScopeAdminSystem))
+		r.Method(http.MethodGet, "/", openapi.Op("orgregistries_HandleList", openapi.AccessAdmin, orgregistries.HandleList(s.Registries)))
+		r.With(
+			audit.Record(s.Audit, core.AuditRegistryCreate),
+		).Method(http.MethodPost, "/", openapi.Op("orgregistries_HandleCreate", openapi.AccessAdmin, orgregistries.HandleCreate(s.Registries)))
+		r.With(
+			audit.Record(s.Audit, core.AuditRegistryUpdate),
+		).Method(http.MethodPatch, "/{registry}", openapi.Op("orgregistries_HandleUpdate", openapi.AccessAdmin, orgregistries.HandleUpdate(s.Registries)))
+		r.With(
+			audit.Record(s.Audit, core.AuditRegistryDelete),
+		).Method(http.MethodDelete, "/{registry}", openapi.Op("orgregistries_HandleDelete", openapi.AccessAdmin, orgregistries.HandleDelete(s.Registries)))
+	})
+
+	r.Route("/policies", func(r chi.Router) {
+		r.Use(acl.AuthorizeAdmin)
+		r.Use(acl.CheckScope(core.ScopeAdminSystem))
+		r.Method(http.MethodGet, "/", openapi.Op("policies_HandleList", openapi.AccessAdmin, policies.HandleList(s.Policies)))
+		r.With(
+			audit.Record(s.Audit, core.AuditPolicyCreate),
+		).Method(http.MethodPost, "/", openapi.Op("policies_HandleCreate", openapi.AccessAdmin, policies.HandleCreate(s.Policies)))
+		r.Method(http.MethodGet, "/{namespace}", openapi.Op("policies_HandleFind", openapi.AccessAdmin, policies.HandleFind(s.Policies)))
+		r.With(
+			audit.Record(s.Audit, core.AuditPolicyUpdate),
+		).Method(http.MethodPatch, "/{namespace}", openapi.Op("policies_HandleUpdate", openapi.AccessAdmin, policies.HandleUpdate(s.Policies)))
+		r.With(
+			audit.Record(s.Audit, core.AuditPolicyDelete),
+		).Method(http.MethodDelete, "/{namespace}", openapi.Op("policies_HandleDelete", openapi.AccessAdmin, policies.HandleDelete(s.Policies)))
+	})
+
+	r.Route("/privileged", func(r chi.Router) {
+		r.Use(acl.AuthorizeAdmin)
+		r.Use(acl.CheckScope(core.ScopeAdminSystem))
+		r.Method(http.MethodGet, "/", openapi.Op("privileged_HandleList", openapi.AccessAdmin, privileged.HandleList(s.Privileged)))
+		r.With(
+			audit.Record(s.Audit, core.AuditPrivilegedCreate),
+		).Method(http.MethodPost, "/", openapi.Op("privileged_HandleCreate", openapi.AccessAdmin, privileged.HandleCreate(s.Privileged)))
+		r.With(
+			audit.Record(s.Audit, core.AuditPrivilegedDelete),
+		).Method(http.MethodDelete, "/{image}", openapi.Op("privileged_HandleDelete", openapi.AccessAdmin, privileged.HandleDelete(s.Privileged)))
+	})
+
+	r.Route("/variables", func(r chi.Router) {
+		r.Use(acl.AuthorizeAdmin)
+		r.Use(acl.CheckScope(core.ScopeAdminSystem))
+		r.Method(http.MethodGet, "/", openapi.Op("variables_HandleList", openapi.AccessAdmin, variables.HandleList(s.Variables)))
+		r.With(
+			audit.Record(s.Audit, core.AuditVariableCreate),
+		).Method(http.MethodPost, "/", openapi.Op("variables_HandleCreate", openapi.AccessAdmin, variables.HandleCreate(s.Repos, s.Variables)))
+		r.With(
+			audit.Record(s.Audit, core.AuditVariableUpdate),
+		).Method(http.MethodPatch, "/{variable}", openapi.Op("variables_HandleUpdate", openapi.AccessAdmin, variables.HandleUpdate(s.Variables)))
+		r.With(
+			audit.Record(s.Audit, core.AuditVariableDelete),
+		).Method(http.MethodDelete, "/{variable}", openapi.Op("variables_HandleDelete", openapi.AccessAdmin, variables.HandleDelete(s.Variables)))
+	})
+
+	r.Route("/providers", func(r chi.Router) {
+		r.Use(acl.AuthorizeAdmin)
+		r.Use(acl.CheckScope(core.ScopeAdminSystem))
+		r.Method(http.MethodGet, "/", openapi.Op("providers_HandleList", openapi.AccessAdmin, providers.HandleList(s.Providers)))
+		r.With(
+			audit.Record(s.Audit, core.AuditRepoRegister),
+		).Method(http.MethodPost, "/{provider}/{owner}/{name}", openapi.Op("providers_HandleRegister", openapi.AccessAdmin, providers.HandleRegister(s.Providers, s.Repos, s.Perms)))
+	})
+
+	r.Route("/audit", func(r chi.Router) {
+		r.Use(acl.AuthorizeAdmin)
+		r.Use(acl.CheckScope(core.ScopeAdminSystem))
+		r.Method(http.MethodGet, "/", openapi.Op("audit_HandleList", openapi.AccessAdmin, audit.HandleList(s.Audit)))
+	})
+
+	r.Route("/webhooks", func(r chi.Router) {
+		r.Use(acl.AuthorizeAdmin)
+		r.Use(acl.CheckScope(core.ScopeAdminSystem))
+		r.Method(http.MethodGet, "/deliveries", openapi.Op("globalwebhooks_HandleList", openapi.AccessAdmin, globalwebhooks.HandleList(s.Deliveries)))
+		r.Method(http.MethodGet, "/deliveries/{delivery}", openapi.Op("globalwebhooks_HandleFind", openapi.AccessAdmin, globalwebhooks.HandleFind(s.Deliveries)))
+	})
+
+	r.Route("/graphql", func(r chi.Router) {
+		r.Use(acl.CheckScope(core.ScopeReadBuild))
+		r.Method(http.MethodGet, "/", openapi.Op("graphql_HandleQuery", openapi.AccessNone, graphql.HandleQuery(s.Repos, s.Builds, s.Stages, s.Logs, s.Perms)))
+		r.Method(http.MethodPost, "/", openapi.Op("graphql_HandleQueryPost", openapi.AccessNone, graphql.HandleQuery(s.Repos, s.Builds, s.Stages, s.Logs, s.Perms)))
 	})
 
 	r.Route("/stream", func(r chi.Router) {
-		r.Get("/", events.HandleGlobal(s.Repos, s.Events))
+		r.Use(acl.CheckScope(core.ScopeReadBuild))
+		r.Method(http.MethodGet, "/", openapi.Op("events_HandleGlobal", openapi.AccessNone, events.HandleGlobal(s.Repos, s.Events)))
 
 		r.Route("/{owner}/{name}", func(r chi.Router) {
 			r.Use(acl.InjectRepository(s.Repoz, s.Repos, s.Perms))
 			r.Use(acl.CheckReadAccess())
 
-			r.Get("/", events.HandleEvents(s.Repos, s.Events))
-			r.Get("/{number}/{stage}/{step}", events.HandleLogStream(s.Repos, s.Builds, s.Stages, s.Steps, s.Stream))
+			r.Method(http.MethodGet, "/", openapi.Op("events_HandleEvents", openapi.AccessRead, events.HandleEvents(s.Repos, s.Events)))
+			r.Method(http.MethodGet, "/{number}/{stage}/{step}", openapi.Op("events_HandleLogStream", openapi.AccessRead, events.HandleLogStream(s.Repos, s.Builds, s.Stages, s.Steps, s.Stream)))
 		})
 	})
 
 	r.Route("/builds", func(r chi.Router) {
-		r.Use(acl.AuthorizeAdmin)
-		r.Get("/incomplete", globalbuilds.HandleIncomplete(s.Repos))
+		r.With(
+			acl.AuthorizeUser,
+			acl.CheckScope(core.ScopeReadBuild),
+		).Method(http.MethodGet, "/search", openapi.Op("globalbuilds_HandleSearch", openapi.AccessUser, globalbuilds.HandleSearch(s.Repos)))
+
+		r.Group(func(r chi.Router) {
+			r.Use(acl.AuthorizeAdmin)
+			r.Use(acl.CheckScope(core.ScopeAdminSystem))
+			r.Method(http.MethodGet, "/incomplete", openapi.Op("globalbuilds_HandleIncomplete", openapi.AccessAdmin, globalbuilds.HandleIncomplete(s.Repos)))
+		})
 	})
 
 	r.Route("/system", func(r chi.Router) {
 		r.Use(acl.AuthorizeAdmin)
+		r.Use(acl.CheckScope(core.ScopeAdminSystem))
 		// r.Get("/license", system.HandleLicense())
 		// r.Get("/limits", system.HandleLimits())
-		r.Get("/stats", system.HandleStats(
+		r.Method(http.MethodGet, "/stats", openapi.Op("system_HandleStats", openapi.AccessAdmin, system.HandleStats(
 			s.Builds,
 			s.Stages,
 			s.Users,
 			s.Repos,
 			s.Events,
 			s.Stream,
-		))
+		)))
+
+		r.Method(http.MethodGet, "/agents", openapi.Op("system_HandleAgents", openapi.AccessAdmin, system.HandleAgents(s.Agents)))
+		r.Method(http.MethodGet, "/activity", openapi.Op("system_HandleActivity", openapi.AccessAdmin, system.HandleActivity(s.Repos, s.Stages)))
+
+		r.Method(http.MethodGet, "/backup", openapi.Op("system_HandleBackup", openapi.AccessAdmin, system.HandleBackup(
+			s.Users,
+			s.Repos,
+			s.Builds,
+			s.Stages,
+			s.Cron,
+		)))
+		r.With(
+			audit.Record(s.Audit, core.AuditSystemRestore),
+		).Method(http.MethodPost, "/restore", openapi.Op("system_HandleRestore", openapi.AccessAdmin, system.HandleRestore(
+			s.Users,
+			s.Repos,
+			s.Builds,
+			s.Steps,
+			s.Cron,
+		)))
+
+		r.With(
+			audit.Record(s.Audit, core.AuditSecretRotate),
+		).Method(http.MethodPost, "/secrets/rotate", openapi.Op("system_HandleRotate", openapi.AccessAdmin, system.HandleRotate(
+			s.Secrets,
+			s.Registries,
+			s.RepoWebhooks,
+			s.Notifications,
+			s.Variables,
+		)))
+
+		r.Route("/queue", func(r chi.Router) {
+			r.Method(http.MethodGet, "/", openapi.Op("queue_HandleStatus", openapi.AccessAdmin, queue.HandleStatus(s.Scheduler)))
+			r.Method(http.MethodGet, "/items", openapi.Op("queue_HandleItems", openapi.AccessAdmin, queue.HandleItems(s.Stages)))
+			r.With(
+				audit.Record(s.Audit, core.AuditQueuePause),
+			).Method(http.MethodPost, "/pause", openapi.Op("queue_HandlePause", openapi.AccessAdmin, queue.HandlePause(s.Scheduler)))
+			r.With(
+				audit.Record(s.Audit, core.AuditQueueResume),
+			).Method(http.MethodPost, "/resume", openapi.Op("queue_HandleResume", openapi.AccessAdmin, queue.HandleResume(s.Scheduler)))
+		})
 	})
 
+	r.Method(http.MethodGet, "/swagger.json", openapi.Op("openapi_HandleSpec", openapi.AccessNone, openapi.HandleSpec(r)))
+
 	return r
 }
@@ -0,0 +1,56 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package api
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/openapi"
+	"github.com/drone/drone/mock"
+
+	"github.com/go-chi/chi"
+	"github.com/golang/mock/gomock"
+)
+
+// this test verifies that every api route is registered with
+// operation metadata, and is included in the api specification.
+func TestHandler_Operations(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	routes := Server{
+		References: mock.NewMockReferenceService(controller),
+		System:     new(core.System),
+	}.Handler().(chi.Routes)
+
+	doc, err := openapi.Generate(routes, "/api")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	ids := map[string]bool{}
+	for _, path := range doc.Paths {
+		for _, op := range path {
+			if ids[op.OperationID] {
+				t.Errorf("Want unique operation id, got duplicate %q", op.OperationID)
+			}
+			ids[op.OperationID] = true
+		}
+	}
+
+	chi.Walk(routes, func(method, pattern string, handler http.Handler, middlewares ...func(http.Handler) http.Handler) error {
+		pattern = strings.Replace(pattern, "/*/", "/", -1)
+		if len(pattern) > 1 {
+			pattern = strings.TrimSuffix(pattern, "/")
+		}
+		if _, ok := doc.Paths[pattern][strings.ToLower(method)]; !ok {
+			t.Errorf("Want operation metadata for %s %s", method, pattern)
+		}
+		return nil
+	})
+}
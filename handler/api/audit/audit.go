@@ -0,0 +1,62 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package audit
+
+import (
+	"net"
+	"net/http"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/request"
+	"github.com/drone/drone/logger"
+
+	"github.com/go-chi/chi/middleware"
+)
+
+// Record returns an http.Handler middleware that records an
+// audit event for the named action when the next handler in
+// the chain completes successfully.
+func Record(events core.AuditStore, action string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			next.ServeHTTP(ww, r)
+
+			// failed requests are not recorded, since the
+			// action was not performed.
+			if status := ww.Status(); status >= 300 {
+				return
+			}
+
+			event := &core.AuditEvent{
+				Action: action,
+				Target: r.URL.Path,
+				IP:     remoteAddr(r),
+			}
+			if user, ok := request.UserFrom(r.Context()); ok {
+				event.Actor = user.Login
+			}
+			if repo, ok := request.RepoFrom(r.Context()); ok {
+				event.RepoID = repo.ID
+			}
+			err := events.Create(r.Context(), event)
+			if err != nil {
+				logger.FromRequest(r).
+					WithError(err).
+					WithField("action", action).
+					Warnln("api: cannot record audit event")
+			}
+		})
+	}
+}
+
+// helper function returns the client ip address.
+func remoteAddr(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
@@ -0,0 +1,88 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package audit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/request"
+	"github.com/drone/drone/mock"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+)
+
+var (
+	mockUser = &core.User{
+		ID:    1,
+		Login: "octocat",
+	}
+
+	mockRepo = &core.Repository{
+		ID:        1,
+		Namespace: "octocat",
+		Name:      "hello-world",
+	}
+)
+
+func TestRecord(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	want := &core.AuditEvent{
+		RepoID: mockRepo.ID,
+		Actor:  mockUser.Login,
+		Action: core.AuditSecretCreate,
+		Target: "/api/repos/octocat/hello-world/secrets",
+		IP:     "192.0.2.1",
+	}
+
+	events := mock.NewMockAuditStore(controller)
+	events.EXPECT().Create(gomock.Any(), gomock.Any()).Do(func(_ interface{}, got *core.AuditEvent) {
+		if diff := cmp.Diff(got, want); diff != "" {
+			t.Errorf(diff)
+		}
+	}).Return(nil)
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/api/repos/octocat/hello-world/secrets", nil)
+	r = r.WithContext(
+		request.WithRepo(
+			request.WithUser(r.Context(), mockUser), mockRepo,
+		),
+	)
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(200)
+	})
+	Record(events, core.AuditSecretCreate)(next).ServeHTTP(w, r)
+	if got, want := w.Code, 200; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+}
+
+func TestRecord_Failure(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	events := mock.NewMockAuditStore(controller)
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("DELETE", "/api/users/octocat", nil)
+	r = r.WithContext(
+		request.WithUser(r.Context(), mockUser),
+	)
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(404)
+	})
+	Record(events, core.AuditUserDelete)(next).ServeHTTP(w, r)
+	if got, want := w.Code, 404; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+}
@@ -0,0 +1,55 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package audit
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/render"
+	"github.com/drone/drone/handler/api/request"
+	"github.com/drone/drone/logger"
+)
+
+// HandleList returns an http.HandlerFunc that writes a json-encoded
+// list of audit events to the response body. If a repository is
+// present in the request context, the events are limited to that
+// repository.
+func HandleList(events core.AuditStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		page, _ := strconv.Atoi(r.FormValue("page"))
+		limit, _ := strconv.Atoi(r.FormValue("per_page"))
+		since, _ := strconv.ParseInt(r.FormValue("since"), 10, 64)
+		if limit < 1 || limit > 100 {
+			limit = 25
+		}
+		offset := 0
+		if page > 1 {
+			offset = (page - 1) * limit
+		}
+
+		filter := core.AuditFilter{
+			Actor:  r.FormValue("actor"),
+			Action: r.FormValue("action"),
+			Since:  since,
+			Limit:  limit,
+			Offset: offset,
+		}
+		if repo, ok := request.RepoFrom(r.Context()); ok {
+			filter.Repo = repo.ID
+		}
+
+		list, err := events.List(r.Context(), filter)
+		if err != nil {
+			render.InternalError(w, err)
+			logger.FromRequest(r).
+				WithError(err).
+				Debugln("api: cannot list audit events")
+		} else {
+			render.JSON(w, list, 200)
+		}
+	}
+}
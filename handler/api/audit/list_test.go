@@ -0,0 +1,101 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package audit
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/request"
+	"github.com/drone/drone/mock"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+)
+
+var mockEvents = []*core.AuditEvent{
+	{
+		ID:      1,
+		RepoID:  1,
+		Actor:   "octocat",
+		Action:  core.AuditRepoEnable,
+		Target:  "/api/repos/octocat/hello-world",
+		IP:      "192.0.2.1",
+		Created: 1524251054,
+	},
+}
+
+func TestHandleList(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	filter := core.AuditFilter{
+		Actor:  "octocat",
+		Action: core.AuditRepoEnable,
+		Since:  1524251000,
+		Limit:  10,
+		Offset: 10,
+	}
+
+	events := mock.NewMockAuditStore(controller)
+	events.EXPECT().List(gomock.Any(), filter).Return(mockEvents, nil)
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/?actor=octocat&action=repo:enable&since=1524251000&page=2&per_page=10", nil)
+
+	HandleList(events)(w, r)
+	if got, want := w.Code, 200; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+
+	got, want := []*core.AuditEvent{}, mockEvents
+	json.NewDecoder(w.Body).Decode(&got)
+	if diff := cmp.Diff(got, want); len(diff) != 0 {
+		t.Errorf(diff)
+	}
+}
+
+func TestHandleList_Repo(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	filter := core.AuditFilter{
+		Repo:  mockRepo.ID,
+		Limit: 25,
+	}
+
+	events := mock.NewMockAuditStore(controller)
+	events.EXPECT().List(gomock.Any(), filter).Return(mockEvents, nil)
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/", nil)
+	r = r.WithContext(
+		request.WithRepo(r.Context(), mockRepo),
+	)
+
+	HandleList(events)(w, r)
+	if got, want := w.Code, 200; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+}
+
+func TestHandleList_Err(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	events := mock.NewMockAuditStore(controller)
+	events.EXPECT().List(gomock.Any(), gomock.Any()).Return(nil, sql.ErrNoRows)
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/", nil)
+
+	HandleList(events)(w, r)
+	if got, want := w.Code, 500; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+}
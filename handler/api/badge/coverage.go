@@ -0,0 +1,84 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package badge
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/drone/drone/core"
+
+	"github.com/go-chi/chi"
+)
+
+var badgeCoverage = `<svg xmlns="http://www.w3.org/2000/svg" width="104" height="20"><linearGradient id="a" x2="0" y2="100%%"><stop offset="0" stop-color="#bbb" stop-opacity=".1"/><stop offset="1" stop-opacity=".1"/></linearGradient><rect rx="3" width="104" height="20" fill="#555"/><rect rx="3" x="61" width="43" height="20" fill="%[1]s"/><path fill="%[1]s" d="M61 0h4v20h-4z"/><rect rx="3" width="104" height="20" fill="url(#a)"/><g fill="#fff" text-anchor="middle" font-family="DejaVu Sans,Verdana,Geneva,sans-serif" font-size="11"><text x="31.5" y="15" fill="#010101" fill-opacity=".3">coverage</text><text x="31.5" y="14">coverage</text><text x="81.5" y="15" fill="#010101" fill-opacity=".3">%[2]s</text><text x="81.5" y="14">%[2]s</text></g></svg>`
+
+// coverage badge colors.
+const (
+	colorHigh   = "#4c1"
+	colorMedium = "#dfb317"
+	colorLow    = "#e05d44"
+	colorNone   = "#9f9f9f"
+)
+
+// Coverage returns an http.HandlerFunc that writes an svg
+// coverage badge to the response.
+func Coverage(
+	repos core.RepositoryStore,
+	coverage core.CoverageStore,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		namespace := chi.URLParam(r, "owner")
+		name := chi.URLParam(r, "name")
+		ref := r.FormValue("ref")
+		branch := r.FormValue("branch")
+		if branch != "" {
+			ref = "refs/heads/" + branch
+		}
+		style := r.FormValue("style")
+
+		// an SVG response is always served, even when error, so
+		// we can go ahead and set the content type appropriately.
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Cache-Control", "no-cache, no-store, max-age=0, must-revalidate, value")
+		w.Header().Set("Expires", "Thu, 01 Jan 1970 00:00:00 GMT")
+		w.Header().Set("Last-Modified", time.Now().UTC().Format(http.TimeFormat))
+		w.Header().Set("Content-Type", "image/svg+xml")
+
+		repo, err := repos.FindName(r.Context(), namespace, name)
+		if err != nil {
+			io.WriteString(w, applyStyle(createCoverageBadge(nil), style))
+			return
+		}
+
+		if ref == "" {
+			ref = fmt.Sprintf("refs/heads/%s", repo.Branch)
+		}
+		list, err := coverage.List(r.Context(), repo.ID, ref, 1)
+		if err != nil || len(list) == 0 {
+			io.WriteString(w, applyStyle(createCoverageBadge(nil), style))
+			return
+		}
+		io.WriteString(w, applyStyle(createCoverageBadge(list[0]), style))
+	}
+}
+
+// helper function returns the coverage badge.
+func createCoverageBadge(coverage *core.Coverage) string {
+	if coverage == nil {
+		return fmt.Sprintf(badgeCoverage, colorNone, "none")
+	}
+	color := colorLow
+	switch {
+	case coverage.Percent >= 80:
+		color = colorHigh
+	case coverage.Percent >= 60:
+		color = colorMedium
+	}
+	return fmt.Sprintf(badgeCoverage, color,
+		fmt.Sprintf("%.0f%%", coverage.Percent))
+}
@@ -0,0 +1,101 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package badge
+
+import (
+	"context"
+	"database/sql"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/mock"
+
+	"github.com/go-chi/chi"
+	"github.com/golang/mock/gomock"
+)
+
+func TestCoverage(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockCoverage := []*core.Coverage{
+		{RepoID: 1, Ref: "refs/heads/develop", Percent: 82.4},
+	}
+
+	repos := mock.NewMockRepositoryStore(controller)
+	repos.EXPECT().FindName(gomock.Any(), gomock.Any(), mockRepo.Name).Return(mockRepo, nil)
+
+	coverage := mock.NewMockCoverageStore(controller)
+	coverage.EXPECT().List(gomock.Any(), mockRepo.ID, "refs/heads/develop", 1).Return(mockCoverage, nil)
+
+	c := new(chi.Context)
+	c.URLParams.Add("owner", "octocat")
+	c.URLParams.Add("name", "hello-world")
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/?branch=develop", nil)
+	r = r.WithContext(
+		context.WithValue(context.Background(), chi.RouteCtxKey, c),
+	)
+
+	Coverage(repos, coverage)(w, r)
+	if got, want := w.Code, 200; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+	if got, want := w.Header().Get("Content-Type"), "image/svg+xml"; got != want {
+		t.Errorf("Want Content-Type %q, got %q", want, got)
+	}
+	if got := w.Body.String(); !strings.Contains(got, ">82%<") {
+		t.Errorf("Want coverage percentage in badge, got %q", got)
+	}
+	if got := w.Body.String(); !strings.Contains(got, colorHigh) {
+		t.Errorf("Want high coverage color in badge, got %q", got)
+	}
+}
+
+func TestCoverage_None(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	repos := mock.NewMockRepositoryStore(controller)
+	repos.EXPECT().FindName(gomock.Any(), gomock.Any(), mockRepo.Name).Return(nil, sql.ErrNoRows)
+
+	c := new(chi.Context)
+	c.URLParams.Add("owner", "octocat")
+	c.URLParams.Add("name", "hello-world")
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/", nil)
+	r = r.WithContext(
+		context.WithValue(context.Background(), chi.RouteCtxKey, c),
+	)
+
+	Coverage(repos, nil)(w, r)
+	if got, want := w.Body.String(), createCoverageBadge(nil); got != want {
+		t.Errorf("Want badge %q, got %q", want, got)
+	}
+}
+
+func TestCreateCoverageBadge(t *testing.T) {
+	tests := []struct {
+		percent float64
+		color   string
+	}{
+		{100, colorHigh},
+		{80, colorHigh},
+		{79.9, colorMedium},
+		{60, colorMedium},
+		{59, colorLow},
+		{0, colorLow},
+	}
+	for _, test := range tests {
+		badge := createCoverageBadge(&core.Coverage{Percent: test.percent})
+		if !strings.Contains(badge, test.color) {
+			t.Errorf("Want color %s for coverage %v", test.color, test.percent)
+		}
+	}
+}
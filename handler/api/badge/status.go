@@ -24,7 +24,8 @@ var (
 )
 
 // Handler returns an http.HandlerFunc that writes an svg status
-// badge to the response.
+// badge to the response. The optional style parameter renders
+// the badge in the flat (default) or flat-square style.
 func Handler(
 	repos core.RepositoryStore,
 	builds core.BuildStore,
@@ -37,6 +38,7 @@ func Handler(
 		if branch != "" {
 			ref = "refs/heads/" + branch
 		}
+		style := r.FormValue("style")
 
 		// an SVG response is always served, even when error, so
 		// we can go ahead and set the content type appropriately.
@@ -48,7 +50,7 @@ func Handler(
 
 		repo, err := repos.FindName(r.Context(), namespace, name)
 		if err != nil {
-			io.WriteString(w, badgeNone)
+			io.WriteString(w, applyStyle(badgeNone, style))
 			return
 		}
 
@@ -57,19 +59,19 @@ func Handler(
 		}
 		build, err := builds.FindRef(r.Context(), repo.ID, ref)
 		if err != nil {
-			io.WriteString(w, badgeNone)
+			io.WriteString(w, applyStyle(badgeNone, style))
 			return
 		}
 
 		switch build.Status {
 		case core.StatusPending, core.StatusRunning, core.StatusBlocked:
-			io.WriteString(w, badgeStarted)
+			io.WriteString(w, applyStyle(badgeStarted, style))
 		case core.StatusPassing:
-			io.WriteString(w, badgeSuccess)
+			io.WriteString(w, applyStyle(badgeSuccess, style))
 		case core.StatusError:
-			io.WriteString(w, badgeError)
+			io.WriteString(w, applyStyle(badgeError, style))
 		default:
-			io.WriteString(w, badgeFailure)
+			io.WriteString(w, applyStyle(badgeFailure, style))
 		}
 	}
 }
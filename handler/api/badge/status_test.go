@@ -228,3 +228,29 @@ func TestHandler_BuildNotFound(t *testing.T) {
 		t.Errorf("Want badge %q, got %q", got, want)
 	}
 }
+
+func TestHandler_Style(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	repos := mock.NewMockRepositoryStore(controller)
+	repos.EXPECT().FindName(gomock.Any(), gomock.Any(), mockRepo.Name).Return(mockRepo, nil)
+
+	builds := mock.NewMockBuildStore(controller)
+	builds.EXPECT().FindRef(gomock.Any(), mockRepo.ID, "refs/heads/develop").Return(mockBuild, nil)
+
+	c := new(chi.Context)
+	c.URLParams.Add("owner", "octocat")
+	c.URLParams.Add("name", "hello-world")
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/?branch=develop&style=flat-square", nil)
+	r = r.WithContext(
+		context.WithValue(context.Background(), chi.RouteCtxKey, c),
+	)
+
+	Handler(repos, builds)(w, r)
+	if got, want := w.Body.String(), applyStyle(badgeSuccess, styleFlatSquare); got != want {
+		t.Errorf("Want badge %q, got %q", got, want)
+	}
+}
@@ -0,0 +1,35 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package badge
+
+import (
+	"regexp"
+	"strings"
+)
+
+// badge styles.
+const (
+	styleFlat       = "flat"
+	styleFlatSquare = "flat-square"
+)
+
+var (
+	reGradient = regexp.MustCompile(`<linearGradient id="a".*?</linearGradient>`)
+	reOverlay  = regexp.MustCompile(`<rect rx="3" width="\d+" height="20" fill="url\(#a\)"/>`)
+)
+
+// helper function renders the badge in the named style. The
+// badges are defined in the flat style, which is the default
+// when the style is empty or unknown.
+func applyStyle(badge, style string) string {
+	switch style {
+	case styleFlatSquare:
+		badge = reGradient.ReplaceAllString(badge, "")
+		badge = reOverlay.ReplaceAllString(badge, "")
+		return strings.Replace(badge, ` rx="3"`, "", -1)
+	default:
+		return badge
+	}
+}
@@ -0,0 +1,32 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package badge
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestApplyStyle(t *testing.T) {
+	for _, style := range []string{"", styleFlat, "unknown"} {
+		if got, want := applyStyle(badgeSuccess, style), badgeSuccess; got != want {
+			t.Errorf("Want flat badge for style %q", style)
+		}
+	}
+
+	got := applyStyle(badgeSuccess, styleFlatSquare)
+	if strings.Contains(got, "linearGradient") {
+		t.Errorf("Expect gradient removed from flat-square badge")
+	}
+	if strings.Contains(got, "url(#a)") {
+		t.Errorf("Expect gradient overlay removed from flat-square badge")
+	}
+	if strings.Contains(got, `rx="3"`) {
+		t.Errorf("Expect rounded corners removed from flat-square badge")
+	}
+	if !strings.Contains(got, ">success</text>") {
+		t.Errorf("Expect badge text preserved")
+	}
+}
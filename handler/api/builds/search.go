@@ -0,0 +1,58 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package builds
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/render"
+	"github.com/drone/drone/handler/api/request"
+	"github.com/drone/drone/logger"
+)
+
+// HandleSearch returns an http.HandlerFunc that writes a
+// json-encoded list of builds matching the commit sha, author,
+// branch or message to the response body. Administrators can
+// search all builds on the server. Other users can only search
+// builds for repositories they can access.
+func HandleSearch(repos core.RepositoryStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		viewer, _ := request.UserFrom(r.Context())
+
+		page, _ := strconv.Atoi(r.FormValue("page"))
+		limit, _ := strconv.Atoi(r.FormValue("per_page"))
+		if limit < 1 || limit > 100 {
+			limit = 25
+		}
+		offset := 0
+		if page > 1 {
+			offset = (page - 1) * limit
+		}
+
+		search := core.BuildSearch{
+			Namespace: r.FormValue("namespace"),
+			Commit:    r.FormValue("commit"),
+			Author:    r.FormValue("author"),
+			Branch:    r.FormValue("branch"),
+			Message:   r.FormValue("message"),
+			Limit:     limit,
+			Offset:    offset,
+		}
+		if !viewer.Admin {
+			search.User = viewer.ID
+		}
+
+		list, err := repos.ListSearch(r.Context(), search)
+		if err != nil {
+			render.InternalError(w, err)
+			logger.FromRequest(r).WithError(err).
+				Debugln("api: cannot search builds")
+		} else {
+			render.JSON(w, list, 200)
+		}
+	}
+}
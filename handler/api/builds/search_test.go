@@ -0,0 +1,99 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package builds
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/errors"
+	"github.com/drone/drone/handler/api/request"
+	"github.com/drone/drone/mock"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestHandleSearch(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	want := []*core.Repository{
+		{ID: 1, Slug: "octocat/hello-world", Build: &core.Build{Number: 1, After: "7fd1a60b01f91b314f59955a4e4d4e80d8edf11d"}},
+	}
+
+	search := core.BuildSearch{
+		User:      1,
+		Namespace: "octocat",
+		Commit:    "7fd1a60",
+		Limit:     25,
+	}
+
+	repos := mock.NewMockRepositoryStore(controller)
+	repos.EXPECT().ListSearch(gomock.Any(), search).Return(want, nil)
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/?namespace=octocat&commit=7fd1a60", nil)
+	r = r.WithContext(
+		request.WithUser(r.Context(), &core.User{ID: 1}),
+	)
+
+	HandleSearch(repos)(w, r)
+	if got, want := w.Code, 200; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+
+	got := []*core.Repository{}
+	json.NewDecoder(w.Body).Decode(&got)
+	if diff := cmp.Diff(got, want); len(diff) != 0 {
+		t.Errorf(diff)
+	}
+}
+
+func TestHandleSearch_Admin(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	search := core.BuildSearch{
+		Author: "octocat",
+		Limit:  10,
+		Offset: 10,
+	}
+
+	repos := mock.NewMockRepositoryStore(controller)
+	repos.EXPECT().ListSearch(gomock.Any(), search).Return([]*core.Repository{}, nil)
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/?author=octocat&page=2&per_page=10", nil)
+	r = r.WithContext(
+		request.WithUser(r.Context(), &core.User{ID: 1, Admin: true}),
+	)
+
+	HandleSearch(repos)(w, r)
+	if got, want := w.Code, 200; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+}
+
+func TestHandleSearch_Error(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	repos := mock.NewMockRepositoryStore(controller)
+	repos.EXPECT().ListSearch(gomock.Any(), gomock.Any()).Return(nil, errors.ErrNotFound)
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/?message=readme", nil)
+	r = r.WithContext(
+		request.WithUser(r.Context(), &core.User{ID: 1}),
+	)
+
+	HandleSearch(repos)(w, r)
+	if got, want := w.Code, 500; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+}
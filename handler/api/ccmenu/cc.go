@@ -39,9 +39,7 @@ func New(r *core.Repository, b *core.Build, link string) *CCProjects {
 
 	// if the build is not currently running then
 	// we can return the latest build status.
-	if b.Status != core.StatusPending &&
-		b.Status != core.StatusRunning &&
-		b.Status != core.StatusBlocked {
+	if !isBuilding(b) {
 		proj.Activity = "Sleeping"
 		proj.LastBuildTime = time.Unix(b.Started, 0).Format(time.RFC3339)
 		proj.LastBuildLabel = fmt.Sprint(b.Number)
@@ -60,3 +58,37 @@ func New(r *core.Repository, b *core.Build, link string) *CCProjects {
 
 	return &CCProjects{Project: proj}
 }
+
+// NewList creates a new CCProject from the Repository and a
+// list of recent builds, ordered by most recent first. If the
+// most recent build is running, the project reports the status
+// of the last completed build, so that build monitors do not
+// lose the last known status while a build is in progress.
+func NewList(r *core.Repository, builds []*core.Build, link string) *CCProjects {
+	projects := New(r, builds[0], link)
+	if !isBuilding(builds[0]) {
+		return projects
+	}
+	for _, build := range builds[1:] {
+		if isBuilding(build) {
+			continue
+		}
+		last := New(r, build, link).Project
+		projects.Project.LastBuildStatus = last.LastBuildStatus
+		projects.Project.LastBuildLabel = last.LastBuildLabel
+		projects.Project.LastBuildTime = last.LastBuildTime
+		break
+	}
+	return projects
+}
+
+// helper function returns true if the build is pending or
+// currently running.
+func isBuilding(b *core.Build) bool {
+	switch b.Status {
+	case core.StatusPending, core.StatusRunning, core.StatusBlocked:
+		return true
+	default:
+		return false
+	}
+}
@@ -7,6 +7,7 @@ package ccmenu
 import (
 	"encoding/xml"
 	"fmt"
+	"io"
 	"net/http"
 
 	"github.com/drone/drone/core"
@@ -14,8 +15,14 @@ import (
 	"github.com/go-chi/chi"
 )
 
-// Handler returns an http.HandlerFunc that writes an svg status
-// badge to the response.
+// recentLimit defines the number of recent builds used to
+// determine the last completed build status.
+const recentLimit = 25
+
+// Handler returns an http.HandlerFunc that writes a cctray
+// xml feed of the repository build status to the response.
+// The optional ref or branch parameter limits the feed to
+// builds for the named git reference.
 func Handler(
 	repos core.RepositoryStore,
 	builds core.BuildStore,
@@ -24,6 +31,11 @@ func Handler(
 	return func(w http.ResponseWriter, r *http.Request) {
 		namespace := chi.URLParam(r, "owner")
 		name := chi.URLParam(r, "name")
+		ref := r.FormValue("ref")
+		branch := r.FormValue("branch")
+		if branch != "" {
+			ref = "refs/heads/" + branch
+		}
 
 		repo, err := repos.FindName(r.Context(), namespace, name)
 		if err != nil {
@@ -31,16 +43,24 @@ func Handler(
 			return
 		}
 
-		build, err := builds.FindNumber(r.Context(), repo.ID, repo.Counter)
-		if err != nil {
+		var list []*core.Build
+		if ref == "" {
+			list, err = builds.List(r.Context(), repo.ID, recentLimit, 0)
+		} else {
+			list, err = builds.ListRef(r.Context(), repo.ID, ref, recentLimit, 0)
+		}
+		if err != nil || len(list) == 0 {
 			w.WriteHeader(404)
 			return
 		}
 
-		project := New(repo, build,
-			fmt.Sprintf("%s/%s/%s/%d", link, namespace, name, build.Number),
+		project := NewList(repo, list,
+			fmt.Sprintf("%s/%s/%s/%d", link, namespace, name, list[0].Number),
 		)
 
+		w.Header().Set("Cache-Control", "no-cache, no-store, max-age=0, must-revalidate, value")
+		w.Header().Set("Content-Type", "application/xml")
+		io.WriteString(w, xml.Header)
 		xml.NewEncoder(w).Encode(project)
 	}
 }
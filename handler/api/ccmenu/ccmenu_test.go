@@ -45,7 +45,7 @@ func TestHandler(t *testing.T) {
 	repos.EXPECT().FindName(gomock.Any(), gomock.Any(), mockRepo.Name).Return(mockRepo, nil)
 
 	builds := mock.NewMockBuildStore(controller)
-	builds.EXPECT().FindNumber(gomock.Any(), mockRepo.ID, mockRepo.Counter).Return(mockBuild, nil)
+	builds.EXPECT().ListRef(gomock.Any(), mockRepo.ID, "refs/heads/develop", recentLimit, 0).Return([]*core.Build{mockBuild}, nil)
 
 	c := new(chi.Context)
 	c.URLParams.Add("owner", "octocat")
@@ -114,7 +114,7 @@ func TestHandler_BuildNotFound(t *testing.T) {
 	repos.EXPECT().FindName(gomock.Any(), gomock.Any(), mockRepo.Name).Return(mockRepo, nil)
 
 	builds := mock.NewMockBuildStore(controller)
-	builds.EXPECT().FindNumber(gomock.Any(), mockRepo.ID, mockRepo.Counter).Return(nil, sql.ErrNoRows)
+	builds.EXPECT().List(gomock.Any(), mockRepo.ID, recentLimit, 0).Return(nil, sql.ErrNoRows)
 
 	c := new(chi.Context)
 	c.URLParams.Add("owner", "octocat")
@@ -131,3 +131,59 @@ func TestHandler_BuildNotFound(t *testing.T) {
 		t.Errorf("Want response code %d, got %d", want, got)
 	}
 }
+
+func TestHandler_Running(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockBuildRunning := &core.Build{
+		ID:     2,
+		RepoID: 1,
+		Number: 2,
+		Status: core.StatusRunning,
+		Ref:    "refs/heads/master",
+	}
+
+	repos := mock.NewMockRepositoryStore(controller)
+	repos.EXPECT().FindName(gomock.Any(), gomock.Any(), mockRepo.Name).Return(mockRepo, nil)
+
+	builds := mock.NewMockBuildStore(controller)
+	builds.EXPECT().List(gomock.Any(), mockRepo.ID, recentLimit, 0).Return([]*core.Build{mockBuildRunning, mockBuild}, nil)
+
+	c := new(chi.Context)
+	c.URLParams.Add("owner", "octocat")
+	c.URLParams.Add("name", "hello-world")
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/", nil)
+	r = r.WithContext(
+		context.WithValue(context.Background(), chi.RouteCtxKey, c),
+	)
+
+	Handler(repos, builds, "https://drone.company.com")(w, r)
+	if got, want := w.Code, 200; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+	if got, want := w.Header().Get("Content-Type"), "application/xml"; got != want {
+		t.Errorf("Want Content-Type %q, got %q", want, got)
+	}
+
+	got, want := &CCProjects{}, &CCProjects{
+		XMLName: xml.Name{
+			Space: "",
+			Local: "Projects",
+		},
+		Project: &CCProject{
+			XMLName:         xml.Name{Space: "", Local: "Project"},
+			Name:            "",
+			Activity:        "Building",
+			LastBuildStatus: "Success",
+			LastBuildLabel:  "1",
+			WebURL:          "https://drone.company.com/octocat/hello-world/2",
+		},
+	}
+	xml.NewDecoder(w.Body).Decode(&got)
+	if diff := cmp.Diff(got, want, ignore); len(diff) != 0 {
+		t.Errorf(diff)
+	}
+}
@@ -0,0 +1,338 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package graphql
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/errors"
+)
+
+type (
+	// object is a resolved object that preserves the order
+	// of the selected fields when encoded to json.
+	object struct {
+		keys   []string
+		values map[string]interface{}
+	}
+
+	// queryError is an error encountered while resolving a
+	// field.
+	queryError struct {
+		Message string        `json:"message"`
+		Path    []interface{} `json:"path,omitempty"`
+	}
+
+	// resolver resolves a field that is not read directly from
+	// the parent struct, typically loading related resources
+	// from the datastore.
+	resolver func(e *executor, parent interface{}, args map[string]interface{}) (interface{}, error)
+)
+
+// query is the root query type.
+type query struct{}
+
+// resolvers maps type and field names to field resolvers.
+var resolvers = map[string]map[string]resolver{
+	"query": {
+		"viewer": resolveViewer,
+		"repo":   resolveRepo,
+		"repos":  resolveRepos,
+	},
+	"Repository": {
+		"build":  resolveBuild,
+		"builds": resolveBuilds,
+	},
+	"Build": {
+		"stages": resolveStages,
+	},
+	"Step": {
+		"logs": resolveLogs,
+	},
+}
+
+func newObject() *object {
+	return &object{values: map[string]interface{}{}}
+}
+
+func (o *object) set(key string, value interface{}) {
+	if _, ok := o.values[key]; !ok {
+		o.keys = append(o.keys, key)
+	}
+	o.values[key] = value
+}
+
+// MarshalJSON encodes the object fields in selection order.
+func (o *object) MarshalJSON() ([]byte, error) {
+	buf := new(bytes.Buffer)
+	buf.WriteByte('{')
+	for i, key := range o.keys {
+		if i != 0 {
+			buf.WriteByte(',')
+		}
+		k, _ := json.Marshal(key)
+		v, err := json.Marshal(o.values[key])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(k)
+		buf.WriteByte(':')
+		buf.Write(v)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// executor resolves a query operation against the datastore.
+type executor struct {
+	ctx    context.Context
+	user   *core.User
+	vars   map[string]interface{}
+	errors []*queryError
+
+	repos  core.RepositoryStore
+	builds core.BuildStore
+	stages core.StageStore
+	logs   core.LogStore
+	perms  core.PermStore
+}
+
+// execute resolves the operation and returns the result.
+func (e *executor) execute(op *operation) *object {
+	for k, v := range op.defaults {
+		if _, ok := e.vars[k]; !ok {
+			e.vars[k] = v
+		}
+	}
+	return e.resolveObject("query", query{}, op.selections, nil)
+}
+
+func (e *executor) resolveObject(typ string, parent interface{}, fields []*field, path []interface{}) *object {
+	out := newObject()
+	for _, f := range fields {
+		fpath := append(path[:len(path):len(path)], f.key())
+		value, err := e.resolveField(typ, parent, f, fpath)
+		if err != nil {
+			e.errors = append(e.errors, &queryError{
+				Message: err.Error(),
+				Path:    fpath,
+			})
+		}
+		out.set(f.key(), value)
+	}
+	return out
+}
+
+func (e *executor) resolveField(typ string, parent interface{}, f *field, path []interface{}) (interface{}, error) {
+	if f.name == "__typename" {
+		return strings.Title(typ), nil
+	}
+	if fn, ok := resolvers[typ][f.name]; ok {
+		value, err := fn(e, parent, e.resolveArgs(f.args))
+		if err != nil {
+			return nil, err
+		}
+		return e.resolveValue(reflect.ValueOf(value), f, path)
+	}
+	value, ok := lookup(reflect.ValueOf(parent), f.name)
+	if !ok {
+		return nil, fmt.Errorf("Cannot query field %q on type %q", f.name, strings.Title(typ))
+	}
+	return e.resolveValue(value, f, path)
+}
+
+// resolveValue resolves the value of the field. Struct values
+// are resolved using the field sub-selections.
+func (e *executor) resolveValue(v reflect.Value, f *field, path []interface{}) (interface{}, error) {
+	if !v.IsValid() {
+		return nil, nil
+	}
+	switch {
+	case v.Kind() == reflect.Ptr && v.Type().Elem().Kind() == reflect.Struct:
+		if v.IsNil() {
+			return nil, nil
+		}
+		if len(f.selections) == 0 {
+			return nil, fmt.Errorf("Field %q of type %q must have a selection of subfields", f.name, v.Type().Elem().Name())
+		}
+		return e.resolveObject(v.Type().Elem().Name(), v.Interface(), f.selections, path), nil
+	case v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Ptr && v.Type().Elem().Elem().Kind() == reflect.Struct:
+		if len(f.selections) == 0 {
+			return nil, fmt.Errorf("Field %q of type %q must have a selection of subfields", f.name, v.Type().Elem().Elem().Name())
+		}
+		list := make([]interface{}, v.Len())
+		for i := range list {
+			list[i], _ = e.resolveValue(v.Index(i), f, append(path[:len(path):len(path)], i))
+		}
+		return list, nil
+	}
+	if len(f.selections) != 0 {
+		return nil, fmt.Errorf("Field %q must not have a selection since it has no subfields", f.name)
+	}
+	return v.Interface(), nil
+}
+
+// resolveArgs replaces variable references with the variable
+// values provided by the request.
+func (e *executor) resolveArgs(args map[string]interface{}) map[string]interface{} {
+	out := map[string]interface{}{}
+	for k, v := range args {
+		switch vv := v.(type) {
+		case variable:
+			out[k] = e.vars[string(vv)]
+		case enum:
+			out[k] = string(vv)
+		default:
+			out[k] = v
+		}
+	}
+	return out
+}
+
+// lookup returns the struct field with the matching json name.
+func lookup(v reflect.Value, name string) (reflect.Value, bool) {
+	if v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return reflect.Value{}, false
+	}
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		tag := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+		if tag == name && tag != "-" {
+			return v.Field(i), true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+// canRead returns true if the user can read the repository.
+// Cached repository permissions are used and are not synced
+// with the remote system.
+func (e *executor) canRead(repo *core.Repository) bool {
+	switch {
+	case repo.Visibility == core.VisibilityPublic:
+		return true
+	case e.user == nil:
+		return false
+	case e.user.Admin:
+		return true
+	case repo.Visibility == core.VisibilityInternal:
+		return true
+	}
+	perm, err := e.perms.Find(e.ctx, repo.UID, e.user.ID)
+	return err == nil && perm.Read
+}
+
+func resolveViewer(e *executor, _ interface{}, _ map[string]interface{}) (interface{}, error) {
+	return e.user, nil
+}
+
+func resolveRepo(e *executor, _ interface{}, args map[string]interface{}) (interface{}, error) {
+	repo, err := e.repos.FindName(e.ctx, argString(args, "owner"), argString(args, "name"))
+	if err != nil || !e.canRead(repo) {
+		return nil, errors.ErrNotFound
+	}
+	return repo, nil
+}
+
+func resolveRepos(e *executor, _ interface{}, args map[string]interface{}) (interface{}, error) {
+	if e.user == nil {
+		return nil, errors.ErrUnauthorized
+	}
+	limit, offset := argPage(args)
+	return e.repos.ListFilter(e.ctx, e.user.ID, core.RepositoryFilter{
+		Query:     argString(args, "query"),
+		Namespace: argString(args, "namespace"),
+		Active:    args["active"] == true,
+		Limit:     limit,
+		Offset:    offset,
+	})
+}
+
+func resolveBuild(e *executor, parent interface{}, args map[string]interface{}) (interface{}, error) {
+	repo := parent.(*core.Repository)
+	if _, ok := args["number"]; !ok {
+		return repo.Build, nil
+	}
+	build, err := e.builds.FindNumber(e.ctx, repo.ID, argInt(args, "number"))
+	if err != nil {
+		return nil, errors.ErrNotFound
+	}
+	return build, nil
+}
+
+func resolveBuilds(e *executor, parent interface{}, args map[string]interface{}) (interface{}, error) {
+	repo := parent.(*core.Repository)
+	limit, offset := argPage(args)
+	return e.builds.ListFilter(e.ctx, repo.ID, core.BuildFilter{
+		Status: argString(args, "status"),
+		Event:  argString(args, "event"),
+		Branch: argString(args, "branch"),
+		Since:  argInt(args, "since"),
+		Before: argInt(args, "before"),
+		Sort:   argString(args, "sort"),
+		Limit:  limit,
+		Offset: offset,
+	})
+}
+
+// resolveStages returns the build stages and steps in a single
+// query.
+func resolveStages(e *executor, parent interface{}, _ map[string]interface{}) (interface{}, error) {
+	build := parent.(*core.Build)
+	if build.Stages != nil {
+		return build.Stages, nil
+	}
+	return e.stages.ListSteps(e.ctx, build.ID)
+}
+
+func resolveLogs(e *executor, parent interface{}, _ map[string]interface{}) (interface{}, error) {
+	step := parent.(*core.Step)
+	rc, err := e.logs.Find(e.ctx, step.ID)
+	if err != nil {
+		return []*core.Line{}, nil
+	}
+	defer rc.Close()
+	lines := []*core.Line{}
+	err = json.NewDecoder(rc).Decode(&lines)
+	return lines, err
+}
+
+func argString(args map[string]interface{}, key string) string {
+	s, _ := args[key].(string)
+	return s
+}
+
+func argInt(args map[string]interface{}, key string) int64 {
+	switch v := args[key].(type) {
+	case int64:
+		return v
+	case float64:
+		return int64(v)
+	}
+	return 0
+}
+
+// argPage returns the limit and offset from the page and
+// per_page arguments.
+func argPage(args map[string]interface{}) (limit, offset int) {
+	page := int(argInt(args, "page"))
+	limit = int(argInt(args, "per_page"))
+	if limit < 1 || limit > 100 {
+		limit = 25
+	}
+	if page > 1 {
+		offset = (page - 1) * limit
+	}
+	return limit, offset
+}
@@ -0,0 +1,111 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package graphql
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/render"
+	"github.com/drone/drone/handler/api/request"
+	"github.com/drone/drone/logger"
+)
+
+type (
+	// params is the graphql request body.
+	params struct {
+		Query         string                 `json:"query"`
+		OperationName string                 `json:"operationName"`
+		Variables     map[string]interface{} `json:"variables"`
+	}
+
+	// result is the graphql response body.
+	result struct {
+		Data   *object       `json:"data,omitempty"`
+		Errors []*queryError `json:"errors,omitempty"`
+	}
+)
+
+// HandleQuery returns an http.HandlerFunc that executes a
+// graphql query for repositories, builds, stages, steps and
+// logs, and writes the json-encoded result to the response
+// body. Only query operations are supported.
+func HandleQuery(
+	repos core.RepositoryStore,
+	builds core.BuildStore,
+	stages core.StageStore,
+	logs core.LogStore,
+	perms core.PermStore,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		in := new(params)
+		if r.Method == http.MethodGet {
+			in.Query = r.FormValue("query")
+			in.OperationName = r.FormValue("operationName")
+			if v := r.FormValue("variables"); v != "" {
+				if err := json.Unmarshal([]byte(v), &in.Variables); err != nil {
+					render.BadRequest(w, err)
+					return
+				}
+			}
+		} else if err := json.NewDecoder(r.Body).Decode(in); err != nil {
+			render.BadRequest(w, err)
+			logger.FromRequest(r).WithError(err).
+				Debugln("api: cannot unmarshal graphql request")
+			return
+		}
+
+		doc, err := parse(in.Query)
+		if err != nil {
+			render.JSON(w, &result{Errors: []*queryError{{Message: err.Error()}}}, 400)
+			logger.FromRequest(r).WithError(err).
+				Debugln("api: cannot parse graphql query")
+			return
+		}
+		op, err := selectOperation(doc, in.OperationName)
+		if err != nil {
+			render.JSON(w, &result{Errors: []*queryError{{Message: err.Error()}}}, 400)
+			return
+		}
+
+		e := &executor{
+			ctx:    r.Context(),
+			vars:   in.Variables,
+			repos:  repos,
+			builds: builds,
+			stages: stages,
+			logs:   logs,
+			perms:  perms,
+		}
+		if e.vars == nil {
+			e.vars = map[string]interface{}{}
+		}
+		if user, ok := request.UserFrom(r.Context()); ok {
+			e.user = user
+		}
+		data := e.execute(op)
+		render.JSON(w, &result{Data: data, Errors: e.errors}, 200)
+	}
+}
+
+// selectOperation returns the named operation from the
+// document. If the name is empty, the document must contain
+// a single operation.
+func selectOperation(doc *document, name string) (*operation, error) {
+	if name == "" {
+		if len(doc.operations) != 1 {
+			return nil, errors.New("graphql: operation name required for documents with multiple operations")
+		}
+		return doc.operations[0], nil
+	}
+	for _, op := range doc.operations {
+		if op.name == name {
+			return op, nil
+		}
+	}
+	return nil, errors.New("graphql: unknown operation name")
+}
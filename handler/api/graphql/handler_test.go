@@ -0,0 +1,161 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package graphql
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"io/ioutil"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/request"
+	"github.com/drone/drone/mock"
+
+	"github.com/golang/mock/gomock"
+	"github.com/sirupsen/logrus"
+)
+
+func init() {
+	logrus.SetOutput(ioutil.Discard)
+}
+
+var (
+	mockUser = &core.User{
+		ID:    1,
+		Login: "octocat",
+	}
+
+	mockRepo = &core.Repository{
+		ID:         1,
+		UID:        "42",
+		Namespace:  "octocat",
+		Name:       "hello-world",
+		Slug:       "octocat/hello-world",
+		Visibility: core.VisibilityPrivate,
+	}
+
+	mockBuild = &core.Build{
+		ID:     1,
+		RepoID: 1,
+		Number: 1,
+		Status: core.StatusPassing,
+	}
+
+	mockStages = []*core.Stage{
+		{
+			ID:     2,
+			Number: 1,
+			Name:   "default",
+			Steps: []*core.Step{
+				{ID: 3, Number: 1, Name: "clone"},
+			},
+		},
+	}
+)
+
+func TestHandleQuery(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	repos := mock.NewMockRepositoryStore(controller)
+	repos.EXPECT().FindName(gomock.Any(), "octocat", "hello-world").Return(mockRepo, nil)
+
+	perms := mock.NewMockPermStore(controller)
+	perms.EXPECT().Find(gomock.Any(), mockRepo.UID, mockUser.ID).Return(&core.Perm{Read: true}, nil)
+
+	builds := mock.NewMockBuildStore(controller)
+	builds.EXPECT().ListFilter(gomock.Any(), mockRepo.ID, core.BuildFilter{Limit: 1}).Return([]*core.Build{mockBuild}, nil)
+
+	stages := mock.NewMockStageStore(controller)
+	stages.EXPECT().ListSteps(gomock.Any(), mockBuild.ID).Return(mockStages, nil)
+
+	logs := mock.NewMockLogStore(controller)
+	logs.EXPECT().Find(gomock.Any(), int64(3)).Return(
+		ioutil.NopCloser(strings.NewReader(`[{"pos":0,"out":"+ git init\n","time":0}]`)), nil,
+	)
+
+	body := `{
+		"query": "query ($name: String!) { repo(owner: \"octocat\", name: $name) { __typename slug builds(per_page: 1) { number status stages { name steps { name logs { out } } } } } }",
+		"variables": { "name": "hello-world" }
+	}`
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/", bytes.NewBufferString(body))
+	r = r.WithContext(
+		request.WithUser(r.Context(), mockUser),
+	)
+
+	HandleQuery(repos, builds, stages, logs, perms)(w, r)
+	if got, want := w.Code, 200; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+
+	want := `{"data":{"repo":{"__typename":"Repository","slug":"octocat/hello-world","builds":[{"number":1,"status":"success","stages":[{"name":"default","steps":[{"name":"clone","logs":[{"out":"+ git init\n"}]}]}]}]}}}`
+	if got := strings.TrimSpace(w.Body.String()); got != want {
+		t.Errorf("Want response body %s, got %s", want, got)
+	}
+}
+
+func TestHandleQuery_NotFound(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	repos := mock.NewMockRepositoryStore(controller)
+	repos.EXPECT().FindName(gomock.Any(), "octocat", "hello-world").Return(mockRepo, nil)
+
+	perms := mock.NewMockPermStore(controller)
+	perms.EXPECT().Find(gomock.Any(), mockRepo.UID, mockUser.ID).Return(nil, sql.ErrNoRows)
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", `/?query={repo(owner:"octocat",name:"hello-world"){slug}}`, nil)
+	r = r.WithContext(
+		request.WithUser(r.Context(), mockUser),
+	)
+
+	HandleQuery(repos, nil, nil, nil, perms)(w, r)
+	if got, want := w.Code, 200; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+
+	got := new(result)
+	json.NewDecoder(w.Body).Decode(got)
+	if len(got.Errors) != 1 {
+		t.Errorf("Want not found error")
+	} else if got, want := got.Errors[0].Message, "Not Found"; got != want {
+		t.Errorf("Want error message %q, got %q", want, got)
+	}
+}
+
+func TestHandleQuery_UnknownField(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/", bytes.NewBufferString(`{"query":"{ viewer { login secret } }"}`))
+	r = r.WithContext(
+		request.WithUser(r.Context(), mockUser),
+	)
+
+	HandleQuery(nil, nil, nil, nil, nil)(w, r)
+	if got, want := w.Code, 200; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+
+	want := `{"data":{"viewer":{"login":"octocat","secret":null}},"errors":[{"message":"Cannot query field \"secret\" on type \"User\"","path":["viewer","secret"]}]}`
+	if got := strings.TrimSpace(w.Body.String()); got != want {
+		t.Errorf("Want response body %s, got %s", want, got)
+	}
+}
+
+func TestHandleQuery_ParseError(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/", bytes.NewBufferString(`{"query":"mutation { repo }"}`))
+
+	HandleQuery(nil, nil, nil, nil, nil)(w, r)
+	if got, want := w.Code, 400; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+}
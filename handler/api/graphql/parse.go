@@ -0,0 +1,399 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package graphql
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// errUnsupported is returned when the query document uses
+// a language feature that is not supported.
+var errUnsupported = errors.New("graphql: only query operations with field selections are supported")
+
+type (
+	// document is a parsed query document.
+	document struct {
+		operations []*operation
+	}
+
+	// operation is a named or anonymous query operation.
+	operation struct {
+		name       string
+		defaults   map[string]interface{}
+		selections []*field
+	}
+
+	// field is a selected field with optional arguments
+	// and sub-selections.
+	field struct {
+		alias      string
+		name       string
+		args       map[string]interface{}
+		selections []*field
+	}
+
+	// variable is a reference to a query variable.
+	variable string
+
+	// enum is an unquoted enum value.
+	enum string
+)
+
+// key returns the response key of the field.
+func (f *field) key() string {
+	if f.alias != "" {
+		return f.alias
+	}
+	return f.name
+}
+
+// token kinds.
+const (
+	tokenEOF = iota
+	tokenPunct
+	tokenName
+	tokenInt
+	tokenFloat
+	tokenString
+)
+
+type token struct {
+	kind  int
+	value string
+}
+
+// parse parses the query document.
+func parse(query string) (*document, error) {
+	tokens, err := lex(query)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+	doc := new(document)
+	for p.peek().kind != tokenEOF {
+		op, err := p.parseOperation()
+		if err != nil {
+			return nil, err
+		}
+		doc.operations = append(doc.operations, op)
+	}
+	if len(doc.operations) == 0 {
+		return nil, errors.New("graphql: empty query document")
+	}
+	return doc, nil
+}
+
+// lex splits the query document into tokens. Commas and
+// comments are ignored.
+func lex(s string) ([]token, error) {
+	var tokens []token
+	r := []rune(s)
+	for i := 0; i < len(r); {
+		c := r[i]
+		switch {
+		case unicode.IsSpace(c) || c == ',' || c == '\ufeff':
+			i++
+		case c == '#':
+			for i < len(r) && r[i] != '\n' {
+				i++
+			}
+		case c == '.':
+			if i+2 < len(r) && r[i+1] == '.' && r[i+2] == '.' {
+				tokens = append(tokens, token{tokenPunct, "..."})
+				i += 3
+			} else {
+				return nil, fmt.Errorf("graphql: unexpected character %q", c)
+			}
+		case strings.ContainsRune("{}()[]:$!=@", c):
+			tokens = append(tokens, token{tokenPunct, string(c)})
+			i++
+		case c == '_' || unicode.IsLetter(c):
+			j := i
+			for j < len(r) && (r[j] == '_' || unicode.IsLetter(r[j]) || unicode.IsDigit(r[j])) {
+				j++
+			}
+			tokens = append(tokens, token{tokenName, string(r[i:j])})
+			i = j
+		case c == '-' || unicode.IsDigit(c):
+			j := i + 1
+			kind := tokenInt
+			for j < len(r) && (unicode.IsDigit(r[j]) || strings.ContainsRune(".eE+-", r[j])) {
+				if !unicode.IsDigit(r[j]) {
+					kind = tokenFloat
+				}
+				j++
+			}
+			tokens = append(tokens, token{kind, string(r[i:j])})
+			i = j
+		case c == '"':
+			var b strings.Builder
+			j := i + 1
+			for ; j < len(r) && r[j] != '"'; j++ {
+				if r[j] == '\n' {
+					return nil, errors.New("graphql: unterminated string")
+				}
+				if r[j] == '\\' && j+1 < len(r) {
+					j++
+					switch r[j] {
+					case 'n':
+						b.WriteRune('\n')
+					case 't':
+						b.WriteRune('\t')
+					case 'r':
+						b.WriteRune('\r')
+					default:
+						b.WriteRune(r[j])
+					}
+					continue
+				}
+				b.WriteRune(r[j])
+			}
+			if j == len(r) {
+				return nil, errors.New("graphql: unterminated string")
+			}
+			tokens = append(tokens, token{tokenString, b.String()})
+			i = j + 1
+		default:
+			return nil, fmt.Errorf("graphql: unexpected character %q", c)
+		}
+	}
+	return append(tokens, token{kind: tokenEOF}), nil
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+// accept consumes the next token if it is the named
+// punctuator.
+func (p *parser) accept(punct string) bool {
+	if t := p.peek(); t.kind == tokenPunct && t.value == punct {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) expect(punct string) error {
+	if !p.accept(punct) {
+		return p.unexpected()
+	}
+	return nil
+}
+
+func (p *parser) expectName() (string, error) {
+	if p.peek().kind != tokenName {
+		return "", p.unexpected()
+	}
+	return p.next().value, nil
+}
+
+func (p *parser) unexpected() error {
+	t := p.peek()
+	if t.kind == tokenEOF {
+		return errors.New("graphql: unexpected end of query document")
+	}
+	return fmt.Errorf("graphql: unexpected token %q", t.value)
+}
+
+func (p *parser) parseOperation() (*operation, error) {
+	op := &operation{defaults: map[string]interface{}{}}
+	if t := p.peek(); t.kind == tokenName {
+		if t.value != "query" {
+			return nil, errUnsupported
+		}
+		p.next()
+		if p.peek().kind == tokenName {
+			op.name = p.next().value
+		}
+		if p.accept("(") {
+			for !p.accept(")") {
+				if err := p.parseVariable(op); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+	selections, err := p.parseSelections()
+	if err != nil {
+		return nil, err
+	}
+	op.selections = selections
+	return op, nil
+}
+
+// parseVariable parses a variable definition. The variable
+// type is parsed but is not enforced.
+func (p *parser) parseVariable(op *operation) error {
+	if err := p.expect("$"); err != nil {
+		return err
+	}
+	name, err := p.expectName()
+	if err != nil {
+		return err
+	}
+	if err := p.expect(":"); err != nil {
+		return err
+	}
+	if err := p.parseType(); err != nil {
+		return err
+	}
+	if p.accept("=") {
+		value, err := p.parseValue()
+		if err != nil {
+			return err
+		}
+		op.defaults[name] = value
+	}
+	return nil
+}
+
+func (p *parser) parseType() error {
+	if p.accept("[") {
+		if err := p.parseType(); err != nil {
+			return err
+		}
+		if err := p.expect("]"); err != nil {
+			return err
+		}
+	} else if _, err := p.expectName(); err != nil {
+		return err
+	}
+	p.accept("!")
+	return nil
+}
+
+func (p *parser) parseSelections() ([]*field, error) {
+	if err := p.expect("{"); err != nil {
+		return nil, err
+	}
+	var fields []*field
+	for !p.accept("}") {
+		if t := p.peek(); t.kind == tokenPunct && (t.value == "..." || t.value == "@") {
+			return nil, errUnsupported
+		}
+		f, err := p.parseField()
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, f)
+	}
+	if len(fields) == 0 {
+		return nil, errors.New("graphql: empty selection set")
+	}
+	return fields, nil
+}
+
+func (p *parser) parseField() (*field, error) {
+	name, err := p.expectName()
+	if err != nil {
+		return nil, err
+	}
+	f := &field{name: name, args: map[string]interface{}{}}
+	if p.accept(":") {
+		f.alias = name
+		if f.name, err = p.expectName(); err != nil {
+			return nil, err
+		}
+	}
+	if p.accept("(") {
+		for !p.accept(")") {
+			arg, err := p.expectName()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect(":"); err != nil {
+				return nil, err
+			}
+			if f.args[arg], err = p.parseValue(); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if t := p.peek(); t.kind == tokenPunct && t.value == "@" {
+		return nil, errUnsupported
+	}
+	if t := p.peek(); t.kind == tokenPunct && t.value == "{" {
+		if f.selections, err = p.parseSelections(); err != nil {
+			return nil, err
+		}
+	}
+	return f, nil
+}
+
+func (p *parser) parseValue() (interface{}, error) {
+	t := p.next()
+	switch t.kind {
+	case tokenInt:
+		return strconv.ParseInt(t.value, 10, 64)
+	case tokenFloat:
+		return strconv.ParseFloat(t.value, 64)
+	case tokenString:
+		return t.value, nil
+	case tokenName:
+		switch t.value {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		case "null":
+			return nil, nil
+		default:
+			return enum(t.value), nil
+		}
+	case tokenPunct:
+		switch t.value {
+		case "$":
+			name, err := p.expectName()
+			return variable(name), err
+		case "[":
+			list := []interface{}{}
+			for !p.accept("]") {
+				value, err := p.parseValue()
+				if err != nil {
+					return nil, err
+				}
+				list = append(list, value)
+			}
+			return list, nil
+		case "{":
+			object := map[string]interface{}{}
+			for !p.accept("}") {
+				name, err := p.expectName()
+				if err != nil {
+					return nil, err
+				}
+				if err := p.expect(":"); err != nil {
+					return nil, err
+				}
+				if object[name], err = p.parseValue(); err != nil {
+					return nil, err
+				}
+			}
+			return object, nil
+		}
+	}
+	if t.kind != tokenEOF {
+		p.pos--
+	}
+	return nil, p.unexpected()
+}
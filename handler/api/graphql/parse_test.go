@@ -0,0 +1,82 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package graphql
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParse(t *testing.T) {
+	doc, err := parse(`
+		# find the repository and latest builds
+		query Repo($name: String! = "hello-world") {
+			repo(owner: "octocat", name: $name) {
+				slug
+				recent: builds(per_page: 5, status: failure, active: true) {
+					number
+				}
+			}
+		}
+	`)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if got, want := len(doc.operations), 1; got != want {
+		t.Errorf("Want %d operations, got %d", want, got)
+		return
+	}
+
+	op := doc.operations[0]
+	if got, want := op.name, "Repo"; got != want {
+		t.Errorf("Want operation name %q, got %q", want, got)
+	}
+	if got, want := op.defaults["name"], "hello-world"; got != want {
+		t.Errorf("Want variable default %q, got %q", want, got)
+	}
+
+	repo := op.selections[0]
+	want := map[string]interface{}{
+		"owner": "octocat",
+		"name":  variable("name"),
+	}
+	if diff := cmp.Diff(repo.args, want); diff != "" {
+		t.Errorf(diff)
+	}
+
+	builds := repo.selections[1]
+	if got, want := builds.key(), "recent"; got != want {
+		t.Errorf("Want field alias %q, got %q", want, got)
+	}
+	want = map[string]interface{}{
+		"per_page": int64(5),
+		"status":   enum("failure"),
+		"active":   true,
+	}
+	if diff := cmp.Diff(builds.args, want); diff != "" {
+		t.Errorf(diff)
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := []string{
+		``,
+		`{}`,
+		`{ repo(`,
+		`{ repo { slug }`,
+		`mutation { repo { slug } }`,
+		`{ repo { ...fields } }`,
+		`{ repo @skip(if: true) { slug } }`,
+		`{ repo(name: "hello) { slug } }`,
+		`{ repo % }`,
+	}
+	for _, query := range tests {
+		if _, err := parse(query); err == nil {
+			t.Errorf("Want error parsing query %q", query)
+		}
+	}
+}
@@ -0,0 +1,349 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package lint
+
+import (
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/extension/expr"
+
+	"gopkg.in/yaml.v2"
+)
+
+type (
+	// document is the raw pipeline document. The document
+	// is parsed a second time, independent of the drone-yaml
+	// library, to report unknown fields and conditions.
+	document struct {
+		Kind      string                 `yaml:"kind"`
+		Name      string                 `yaml:"name"`
+		DependsOn []string               `yaml:"depends_on"`
+		Trigger   map[string]interface{} `yaml:"trigger"`
+		Steps     []*container           `yaml:"steps"`
+		Services  []*container           `yaml:"services"`
+		Extra     map[string]interface{} `yaml:",inline"`
+	}
+
+	// container is the raw step or service.
+	container struct {
+		Name      string                 `yaml:"name"`
+		DependsOn []string               `yaml:"depends_on"`
+		When      map[string]interface{} `yaml:"when"`
+		Extra     map[string]interface{} `yaml:",inline"`
+	}
+)
+
+// pipelineFields is the list of known pipeline fields, in
+// addition to the fields declared by the document.
+var pipelineFields = []string{
+	"type",
+	"version",
+	"platform",
+	"workspace",
+	"clone",
+	"concurrency",
+	"node",
+	"volumes",
+	"image_pull_secrets",
+	"timeout",
+	"failure",
+	"pool",
+	"cache",
+}
+
+// containerFields is the list of known step and service
+// fields, in addition to the fields declared by the container.
+var containerFields = []string{
+	"image",
+	"pull",
+	"build",
+	"command",
+	"commands",
+	"detach",
+	"devices",
+	"dns",
+	"dns_search",
+	"entrypoint",
+	"environment",
+	"extra_hosts",
+	"failure",
+	"network_mode",
+	"privileged",
+	"resources",
+	"settings",
+	"shell",
+	"user",
+	"volumes",
+	"working_dir",
+	"timeout",
+	"retries",
+	"healthcheck",
+}
+
+// conditionFields is the list of known trigger and when
+// conditions.
+var conditionFields = []string{
+	"action",
+	"branch",
+	"cron",
+	"event",
+	"expr",
+	"instance",
+	"paths",
+	"ref",
+	"repo",
+	"status",
+	"tag",
+	"target",
+}
+
+// events is the list of known build events.
+var events = []string{
+	core.EventPush,
+	core.EventPullRequest,
+	core.EventTag,
+	core.EventPromote,
+	core.EventRollback,
+	core.EventCustom,
+}
+
+// statuses is the list of known status conditions.
+var statuses = []string{
+	"success",
+	"failure",
+}
+
+// parseDocuments parses the pipeline documents in the
+// configuration file. Documents of other kinds are ignored.
+func parseDocuments(data string) ([]*document, error) {
+	var out []*document
+	dec := yaml.NewDecoder(strings.NewReader(data))
+	for {
+		doc := new(document)
+		err := dec.Decode(doc)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if doc.Kind != "pipeline" {
+			continue
+		}
+		if doc.Name == "" {
+			doc.Name = "default"
+		}
+		out = append(out, doc)
+	}
+	return out, nil
+}
+
+// check checks the pipeline documents for unknown fields,
+// invalid conditions and invalid dependencies.
+func check(docs []*document, report *report) {
+	names := map[string][]string{}
+	for _, doc := range docs {
+		names[doc.Name] = doc.DependsOn
+	}
+	for _, doc := range docs {
+		for _, key := range unknown(doc.Extra, pipelineFields) {
+			report.warn(doc.Name, "", fmt.Sprintf("unknown pipeline field %s", key))
+		}
+		checkConditions(doc.Trigger, doc.Name, "", "trigger", report)
+
+		for _, dep := range doc.DependsOn {
+			if _, ok := names[dep]; !ok {
+				report.error(doc.Name, "", fmt.Sprintf("pipeline depends on unknown pipeline %s", dep))
+			}
+		}
+
+		steps := map[string][]string{}
+		for _, step := range doc.Steps {
+			steps[step.Name] = step.DependsOn
+		}
+		var containers []*container
+		containers = append(containers, doc.Services...)
+		containers = append(containers, doc.Steps...)
+		for _, step := range containers {
+			for _, key := range unknown(step.Extra, containerFields) {
+				report.warn(doc.Name, step.Name, fmt.Sprintf("unknown step field %s", key))
+			}
+			checkConditions(step.When, doc.Name, step.Name, "when", report)
+		}
+		for _, step := range doc.Steps {
+			for _, dep := range step.DependsOn {
+				if _, ok := steps[dep]; !ok {
+					report.error(doc.Name, step.Name, fmt.Sprintf("step depends on unknown step %s", dep))
+				}
+			}
+		}
+		if name, ok := cycle(steps); ok {
+			report.error(doc.Name, name, "step dependencies contain a cycle, the step is unreachable")
+		}
+	}
+	if name, ok := cycle(names); ok {
+		report.error(name, "", "pipeline dependencies contain a cycle, the pipeline is unreachable")
+	}
+}
+
+// checkConditions checks the trigger or when block for
+// unknown conditions, events and invalid patterns.
+func checkConditions(conditions map[string]interface{}, pipeline, step, block string, report *report) {
+	for _, key := range unknown(conditions, conditionFields) {
+		report.warn(pipeline, step, fmt.Sprintf("unknown %s condition %s", block, key))
+	}
+	var keys []string
+	for key := range conditions {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if key == "expr" {
+			checkExpr(conditions[key], pipeline, step, block, report)
+			continue
+		}
+		values, ok := conditionValues(conditions[key])
+		if !ok {
+			report.error(pipeline, step, fmt.Sprintf("invalid %s condition %s", block, key))
+			continue
+		}
+		for _, v := range values {
+			switch key {
+			case "event":
+				if !contains(events, v) {
+					report.warn(pipeline, step, fmt.Sprintf("unknown %s event %s", block, v))
+				}
+			case "status":
+				if !contains(statuses, v) {
+					report.warn(pipeline, step, fmt.Sprintf("unknown %s status %s", block, v))
+				}
+			case "branch", "ref", "repo", "paths", "tag", "target", "instance":
+				if _, err := path.Match(v, ""); err != nil {
+					report.error(pipeline, step, fmt.Sprintf("invalid %s %s pattern %s", block, key, v))
+				}
+			}
+		}
+	}
+}
+
+// checkExpr checks the trigger or when expression.
+func checkExpr(value interface{}, pipeline, step, block string, report *report) {
+	s, ok := value.(string)
+	if !ok {
+		report.error(pipeline, step, fmt.Sprintf("invalid %s condition expr", block))
+		return
+	}
+	if _, err := expr.Parse(s); err != nil {
+		report.error(pipeline, step, fmt.Sprintf("invalid %s expression: %s", block, err))
+	}
+}
+
+// conditionValues returns the values of a condition, which
+// is defined as a string, a list of strings, or as include
+// and exclude lists.
+func conditionValues(value interface{}) ([]string, bool) {
+	switch v := value.(type) {
+	case string:
+		return []string{v}, true
+	case []interface{}:
+		var out []string
+		for _, item := range v {
+			s, ok := item.(string)
+			if !ok {
+				return nil, false
+			}
+			out = append(out, s)
+		}
+		return out, true
+	case map[interface{}]interface{}:
+		for key := range v {
+			if key != "include" && key != "exclude" {
+				return nil, false
+			}
+		}
+		var out []string
+		for _, key := range []string{"include", "exclude"} {
+			item, ok := v[key]
+			if !ok {
+				continue
+			}
+			values, ok := conditionValues(item)
+			if !ok {
+				return nil, false
+			}
+			out = append(out, values...)
+		}
+		return out, true
+	default:
+		return nil, false
+	}
+}
+
+// cycle returns the name of a node that is part of a
+// dependency cycle, if any. Unknown dependencies are ignored.
+func cycle(deps map[string][]string) (string, bool) {
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	state := map[string]int{}
+	found := ""
+	var visit func(name string) bool
+	visit = func(name string) bool {
+		switch state[name] {
+		case visiting:
+			found = name
+			return true
+		case visited:
+			return false
+		}
+		state[name] = visiting
+		for _, dep := range deps[name] {
+			if _, ok := deps[dep]; ok && visit(dep) {
+				return true
+			}
+		}
+		state[name] = visited
+		return false
+	}
+	var names []string
+	for name := range deps {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if visit(name) {
+			return found, true
+		}
+	}
+	return "", false
+}
+
+// unknown returns the sorted keys that are not in the list
+// of known keys.
+func unknown(fields map[string]interface{}, known []string) []string {
+	var out []string
+	for key := range fields {
+		if !contains(known, key) {
+			out = append(out, key)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
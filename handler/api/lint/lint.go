@@ -0,0 +1,125 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package lint
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/drone/drone-yaml/yaml"
+	"github.com/drone/drone-yaml/yaml/converter"
+	"github.com/drone/drone-yaml/yaml/linter"
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/extension"
+	"github.com/drone/drone/handler/api/render"
+	"github.com/drone/drone/handler/api/request"
+)
+
+type (
+	payload struct {
+		Data string `json:"data"`
+	}
+
+	// issue describes an error or warning found in the
+	// configuration file.
+	issue struct {
+		Pipeline string `json:"pipeline,omitempty"`
+		Step     string `json:"step,omitempty"`
+		Message  string `json:"message"`
+	}
+
+	// report is the result of linting the configuration file.
+	// The configuration is valid if there are no errors.
+	report struct {
+		Valid    bool     `json:"valid"`
+		Errors   []*issue `json:"errors"`
+		Warnings []*issue `json:"warnings"`
+	}
+)
+
+func (r *report) error(pipeline, step, message string) {
+	r.Errors = append(r.Errors, &issue{
+		Pipeline: pipeline,
+		Step:     step,
+		Message:  message,
+	})
+}
+
+func (r *report) warn(pipeline, step, message string) {
+	r.Warnings = append(r.Warnings, &issue{
+		Pipeline: pipeline,
+		Step:     step,
+		Message:  message,
+	})
+}
+
+// HandleLint returns an http.HandlerFunc that processes http
+// requests to lint a pipeline configuration file. If the
+// request is scoped to a repository, the configuration file
+// is linted with the repository settings.
+func HandleLint() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		in := new(payload)
+		err := json.NewDecoder(r.Body).Decode(in)
+		if err != nil {
+			render.BadRequest(w, err)
+			return
+		}
+		repo, ok := request.RepoFrom(r.Context())
+		if !ok {
+			repo = &core.Repository{Config: ".drone.yml"}
+		}
+		render.JSON(w, lint(in.Data, repo), 200)
+	}
+}
+
+// lint lints the configuration file and returns the report.
+func lint(data string, repo *core.Repository) *report {
+	out := &report{
+		Errors:   []*issue{},
+		Warnings: []*issue{},
+	}
+
+	// the legacy yaml configuration file is converted to
+	// the new format, consistent with the build trigger.
+	data, err := converter.ConvertString(data, converter.Metadata{
+		Filename: repo.Config,
+	})
+	if err != nil {
+		out.error("", "", err.Error())
+		return out
+	}
+
+	manifest, err := yaml.ParseString(data)
+	if err != nil {
+		out.error("", "", err.Error())
+		return out
+	}
+
+	// pipelines in untrusted repositories that can only be
+	// linted as trusted are blocked pending approval by a
+	// system administrator when the build is triggered.
+	if err := linter.Manifest(manifest, repo.Trusted); err != nil {
+		if !repo.Trusted && linter.Manifest(manifest, true) == nil {
+			out.warn("", "", "pipeline requires approval by a system administrator")
+		} else {
+			out.error("", "", err.Error())
+		}
+	}
+
+	if _, err := extension.ParseString(data); err != nil {
+		out.error("", "", err.Error())
+	}
+
+	docs, err := parseDocuments(data)
+	if err != nil {
+		out.error("", "", err.Error())
+		return out
+	}
+	check(docs, out)
+
+	out.Valid = len(out.Errors) == 0
+	return out
+}
@@ -0,0 +1,152 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package lint
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestHandleLint(t *testing.T) {
+	in := new(bytes.Buffer)
+	json.NewEncoder(in).Encode(&payload{
+		Data: "kind: pipeline\nname: default\nsteps:\n- name: build\n  image: golang\n  commands: [ go build ]\n",
+	})
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/", in)
+
+	HandleLint().ServeHTTP(w, r)
+	if got, want := w.Code, http.StatusOK; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+
+	got := new(report)
+	json.NewDecoder(w.Body).Decode(got)
+	if !got.Valid {
+		t.Errorf("Want valid configuration, got errors %v", got.Errors)
+	}
+}
+
+func TestHandleLint_BadRequest(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/", bytes.NewBufferString("{"))
+
+	HandleLint().ServeHTTP(w, r)
+	if got, want := w.Code, http.StatusBadRequest; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	docs, err := parseDocuments(dummyConfig)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	got := &report{}
+	check(docs, got)
+
+	want := &report{
+		Errors: []*issue{
+			{Pipeline: "test", Step: "", Message: "invalid trigger branch pattern feature/["},
+			{Pipeline: "test", Step: "", Message: "pipeline depends on unknown pipeline lint"},
+			{Pipeline: "test", Step: "publish", Message: "step depends on unknown step package"},
+			{Pipeline: "deploy", Step: "", Message: "invalid trigger condition event"},
+			{Pipeline: "deploy", Step: "", Message: "invalid trigger expression: expr: expected value but found end of expression"},
+			{Pipeline: "build", Step: "", Message: "pipeline dependencies contain a cycle, the pipeline is unreachable"},
+		},
+		Warnings: []*issue{
+			{Pipeline: "build", Step: "", Message: "unknown pipeline field stages"},
+			{Pipeline: "build", Step: "test", Message: "unknown step field command_line"},
+			{Pipeline: "build", Step: "test", Message: "unknown when condition branches"},
+			{Pipeline: "build", Step: "test", Message: "unknown when status canceled"},
+			{Pipeline: "test", Step: "", Message: "unknown trigger event pull"},
+		},
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestCycle(t *testing.T) {
+	tests := []struct {
+		deps map[string][]string
+		name string
+		ok   bool
+	}{
+		{
+			deps: map[string][]string{"a": nil, "b": {"a"}, "c": {"a", "b"}},
+		},
+		{
+			deps: map[string][]string{"a": {"unknown"}},
+		},
+		{
+			deps: map[string][]string{"a": {"a"}},
+			name: "a",
+			ok:   true,
+		},
+		{
+			deps: map[string][]string{"a": {"b"}, "b": {"c"}, "c": {"b"}},
+			name: "b",
+			ok:   true,
+		},
+	}
+	for i, test := range tests {
+		name, ok := cycle(test.deps)
+		if name != test.name || ok != test.ok {
+			t.Errorf("Want cycle %q %v, got %q %v at index %d", test.name, test.ok, name, ok, i)
+		}
+	}
+}
+
+var dummyConfig = `
+kind: pipeline
+name: build
+depends_on: [ deploy ]
+stages: [ one ]
+steps:
+- name: test
+  image: golang
+  command_line: go test
+  when:
+    branches: [ master ]
+    status: [ success, canceled ]
+
+---
+kind: pipeline
+name: test
+depends_on: [ build, lint ]
+trigger:
+  branch:
+  - master
+  - feature/[
+  event:
+    include: [ push, pull ]
+steps:
+- name: build
+  image: golang
+- name: publish
+  image: plugins/docker
+  depends_on: [ build, package ]
+
+---
+kind: pipeline
+name: deploy
+depends_on: [ test ]
+trigger:
+  event:
+    only: [ push ]
+  expr: branch ==
+
+---
+kind: secret
+name: password
+`
@@ -0,0 +1,273 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+// Package openapi generates an OpenAPI specification from the
+// routes registered with the api router, using the operation
+// metadata registered with each route.
+package openapi
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/drone/drone/handler/api/render"
+	"github.com/drone/drone/logger"
+	"github.com/drone/drone/version"
+
+	"github.com/go-chi/chi"
+)
+
+// Access levels required to invoke an operation.
+const (
+	AccessNone      = ""
+	AccessRead      = "read"
+	AccessUser      = "user"
+	AccessWrite     = "write"
+	AccessRepoAdmin = "repo_admin"
+	AccessAdmin     = "admin"
+)
+
+type (
+	// Document is an OpenAPI document.
+	Document struct {
+		OpenAPI    string                           `json:"openapi"`
+		Info       Info                             `json:"info"`
+		Servers    []Server                         `json:"servers"`
+		Paths      map[string]map[string]*Operation `json:"paths"`
+		Components Components                       `json:"components"`
+	}
+
+	// Info provides metadata about the api.
+	Info struct {
+		Title   string `json:"title"`
+		Version string `json:"version"`
+	}
+
+	// Server provides the api base path.
+	Server struct {
+		URL string `json:"url"`
+	}
+
+	// Operation describes a single api operation on a path.
+	Operation struct {
+		OperationID string                `json:"operationId"`
+		Tags        []string              `json:"tags,omitempty"`
+		Parameters  []*Parameter          `json:"parameters,omitempty"`
+		Security    []map[string][]string `json:"security"`
+		Responses   map[string]*Response  `json:"responses"`
+
+		// Access is the repository or system access level
+		// required to invoke the operation.
+		Access string `json:"x-drone-access,omitempty"`
+	}
+
+	// Parameter describes a single operation parameter.
+	Parameter struct {
+		Name     string `json:"name"`
+		In       string `json:"in"`
+		Required bool   `json:"required"`
+		Schema   Schema `json:"schema"`
+	}
+
+	// Response describes a single response from an operation.
+	Response struct {
+		Description string               `json:"description"`
+		Content     map[string]MediaType `json:"content,omitempty"`
+	}
+
+	// MediaType describes the response body.
+	MediaType struct {
+		Schema Schema `json:"schema"`
+	}
+
+	// Schema describes a data type.
+	Schema struct {
+		Ref        string            `json:"$ref,omitempty"`
+		Type       string            `json:"type,omitempty"`
+		Properties map[string]Schema `json:"properties,omitempty"`
+	}
+
+	// Components holds reusable schemas and security schemes.
+	Components struct {
+		Schemas         map[string]Schema         `json:"schemas"`
+		SecuritySchemes map[string]SecurityScheme `json:"securitySchemes"`
+	}
+
+	// SecurityScheme describes an authentication scheme.
+	SecurityScheme struct {
+		Type   string `json:"type"`
+		Scheme string `json:"scheme,omitempty"`
+		In     string `json:"in,omitempty"`
+		Name   string `json:"name,omitempty"`
+	}
+)
+
+// Op returns an http.Handler that annotates the handler with
+// the operation identifier, and the access level required to
+// invoke the operation, for inclusion in the OpenAPI document.
+func Op(id, access string, handler http.Handler) http.Handler {
+	return &operation{
+		Handler: handler,
+		id:      id,
+		access:  access,
+	}
+}
+
+// operation is an http.Handler annotated with the operation
+// metadata.
+type operation struct {
+	http.Handler
+	id     string
+	access string
+}
+
+// HandleSpec returns an http.HandlerFunc that writes the
+// json-encoded OpenAPI document for the routes to the response
+// body. The routes are expected to be mounted at /api.
+func HandleSpec(routes chi.Routes) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		doc, err := Generate(routes, "/api")
+		if err != nil {
+			render.InternalError(w, err)
+			logger.FromRequest(r).WithError(err).
+				Debugln("api: cannot generate openapi document")
+		} else {
+			render.JSON(w, doc, 200)
+		}
+	}
+}
+
+// Generate returns an OpenAPI document for the routes. Routes
+// that are not annotated with operation metadata are omitted.
+func Generate(routes chi.Routes, base string) (*Document, error) {
+	doc := &Document{
+		OpenAPI: "3.0.0",
+		Info: Info{
+			Title:   "Drone API",
+			Version: version.Version.String(),
+		},
+		Servers: []Server{{URL: base}},
+		Paths:   map[string]map[string]*Operation{},
+		Components: Components{
+			Schemas: map[string]Schema{
+				"Error": {
+					Type: "object",
+					Properties: map[string]Schema{
+						"message": {Type: "string"},
+					},
+				},
+			},
+			SecuritySchemes: map[string]SecurityScheme{
+				"bearer": {Type: "http", Scheme: "bearer"},
+				"token":  {Type: "apiKey", In: "query", Name: "access_token"},
+			},
+		},
+	}
+
+	var ops []*route
+	err := chi.Walk(routes, func(method, pattern string, handler http.Handler, middlewares ...func(http.Handler) http.Handler) error {
+		op, ok := handler.(*operation)
+		if !ok {
+			return nil
+		}
+		ops = append(ops, &route{
+			method:    strings.ToLower(method),
+			pattern:   cleanPattern(pattern),
+			operation: op,
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(ops, func(i, j int) bool {
+		if ops[i].pattern != ops[j].pattern {
+			return ops[i].pattern < ops[j].pattern
+		}
+		return ops[i].method < ops[j].method
+	})
+
+	for _, op := range ops {
+		path, ok := doc.Paths[op.pattern]
+		if !ok {
+			path = map[string]*Operation{}
+			doc.Paths[op.pattern] = path
+		}
+		path[op.method] = &Operation{
+			OperationID: op.id,
+			Tags:        tags(op.pattern),
+			Parameters:  parameters(op.pattern),
+			Security:    security(op.access),
+			Access:      op.access,
+			Responses: map[string]*Response{
+				"200": {Description: "OK"},
+				"default": {
+					Description: "Error",
+					Content: map[string]MediaType{
+						"application/json": {Schema: Schema{Ref: "#/components/schemas/Error"}},
+					},
+				},
+			},
+		}
+	}
+	return doc, nil
+}
+
+// route is a registered route.
+type route struct {
+	*operation
+	method  string
+	pattern string
+}
+
+// security returns the security requirements for the access
+// level. Authentication is optional for read access, since
+// public repositories can be read anonymously.
+func security(level string) []map[string][]string {
+	switch level {
+	case AccessNone:
+		return []map[string][]string{}
+	case AccessRead:
+		return []map[string][]string{{}, {"bearer": {}}, {"token": {}}}
+	default:
+		return []map[string][]string{{"bearer": {}}, {"token": {}}}
+	}
+}
+
+// cleanPattern removes the sub-router wildcards and trailing
+// slash from the route pattern.
+func cleanPattern(pattern string) string {
+	pattern = strings.Replace(pattern, "/*/", "/", -1)
+	if len(pattern) > 1 {
+		pattern = strings.TrimSuffix(pattern, "/")
+	}
+	return pattern
+}
+
+// tags returns the operation tags, derived from the first
+// segment of the route pattern.
+func tags(pattern string) []string {
+	parts := strings.Split(strings.TrimPrefix(pattern, "/"), "/")
+	if parts[0] == "" {
+		return nil
+	}
+	return parts[:1]
+}
+
+// parameters returns the path parameters in the route pattern.
+func parameters(pattern string) []*Parameter {
+	var params []*Parameter
+	for _, part := range strings.Split(pattern, "/") {
+		if strings.HasPrefix(part, "{") && strings.HasSuffix(part, "}") {
+			params = append(params, &Parameter{
+				Name:     strings.Trim(part, "{}"),
+				In:       "path",
+				Required: true,
+				Schema:   Schema{Type: "string"},
+			})
+		}
+	}
+	return params
+}
@@ -0,0 +1,105 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi"
+	"github.com/google/go-cmp/cmp"
+)
+
+var handleNoop = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+
+func TestGenerate(t *testing.T) {
+	r := chi.NewRouter()
+	r.Route("/repos/{owner}/{name}", func(r chi.Router) {
+		r.Method(http.MethodGet, "/", Op("repos_HandleFind", AccessRead, handleNoop))
+		r.Method(http.MethodDelete, "/", Op("repos_HandleDisable", AccessRepoAdmin, handleNoop))
+		r.Method(http.MethodPost, "/builds/{number}", Op("builds_HandleRetry", AccessWrite, handleNoop))
+	})
+	r.Route("/user", func(r chi.Router) {
+		r.Method(http.MethodGet, "/", Op("user_HandleFind", AccessUser, handleNoop))
+	})
+	r.Route("/users", func(r chi.Router) {
+		r.Method(http.MethodGet, "/", Op("users_HandleList", AccessAdmin, handleNoop))
+	})
+	r.Get("/healthz", handleNoop)
+	r.Method(http.MethodGet, "/swagger.json", Op("openapi_HandleSpec", AccessNone, HandleSpec(r)))
+
+	doc, err := Generate(r, "/api")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	tests := []struct {
+		path   string
+		method string
+		id     string
+		access string
+		params []string
+	}{
+		{"/repos/{owner}/{name}", "get", "repos_HandleFind", AccessRead, []string{"owner", "name"}},
+		{"/repos/{owner}/{name}", "delete", "repos_HandleDisable", AccessRepoAdmin, []string{"owner", "name"}},
+		{"/repos/{owner}/{name}/builds/{number}", "post", "builds_HandleRetry", AccessWrite, []string{"owner", "name", "number"}},
+		{"/user", "get", "user_HandleFind", AccessUser, nil},
+		{"/users", "get", "users_HandleList", AccessAdmin, nil},
+		{"/swagger.json", "get", "openapi_HandleSpec", AccessNone, nil},
+	}
+	for _, test := range tests {
+		op, ok := doc.Paths[test.path][test.method]
+		if !ok {
+			t.Errorf("Want operation %s %s", test.method, test.path)
+			continue
+		}
+		if got, want := op.OperationID, test.id; got != want {
+			t.Errorf("Want %s %s operation id %q, got %q", test.method, test.path, want, got)
+		}
+		if got, want := op.Access, test.access; got != want {
+			t.Errorf("Want %s %s access %q, got %q", test.method, test.path, want, got)
+		}
+		var params []string
+		for _, param := range op.Parameters {
+			params = append(params, param.Name)
+		}
+		if diff := cmp.Diff(params, test.params); diff != "" {
+			t.Error(diff)
+		}
+	}
+
+	// routes without operation metadata are omitted.
+	if _, ok := doc.Paths["/healthz"]; ok {
+		t.Errorf("Want route without operation metadata omitted")
+	}
+	if got, want := len(doc.Paths), 5; got != want {
+		t.Errorf("Want %d paths, got %d", want, got)
+	}
+}
+
+func TestHandleSpec(t *testing.T) {
+	r := chi.NewRouter()
+	r.Method(http.MethodGet, "/swagger.json", Op("openapi_HandleSpec", AccessNone, HandleSpec(r)))
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/swagger.json", nil)
+	r.ServeHTTP(w, req)
+
+	if got, want := w.Code, 200; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+
+	doc := new(Document)
+	json.NewDecoder(w.Body).Decode(doc)
+	if got, want := doc.OpenAPI, "3.0.0"; got != want {
+		t.Errorf("Want openapi version %s, got %s", want, got)
+	}
+	if _, ok := doc.Paths["/swagger.json"]["get"]; !ok {
+		t.Errorf("Want swagger.json path in document")
+	}
+}
@@ -0,0 +1,60 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package policies
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/render"
+	"github.com/drone/drone/logger"
+)
+
+type policyInput struct {
+	Namespace       string   `json:"namespace"`
+	AllowRegistries []string `json:"allow_registries"`
+	DenyImages      []string `json:"deny_images"`
+	DenyPrivileged  bool     `json:"deny_privileged"`
+	CloneDepth      int      `json:"clone_depth"`
+}
+
+// HandleCreate returns an http.HandlerFunc that processes http
+// requests to create an organization policy.
+func HandleCreate(policies core.PolicyStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		in := new(policyInput)
+		err := json.NewDecoder(r.Body).Decode(in)
+		if err != nil {
+			render.BadRequest(w, err)
+			return
+		}
+
+		policy := &core.Policy{
+			Namespace:       in.Namespace,
+			AllowRegistries: in.AllowRegistries,
+			DenyImages:      in.DenyImages,
+			DenyPrivileged:  in.DenyPrivileged,
+			CloneDepth:      in.CloneDepth,
+			Created:         time.Now().Unix(),
+			Updated:         time.Now().Unix(),
+		}
+		err = policy.Validate()
+		if err != nil {
+			render.BadRequest(w, err)
+			return
+		}
+
+		err = policies.Create(r.Context(), policy)
+		if err != nil {
+			render.InternalError(w, err)
+			logger.FromRequest(r).WithError(err).
+				Warnln("api: cannot create policy")
+			return
+		}
+		render.JSON(w, policy, 200)
+	}
+}
@@ -0,0 +1,35 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package policies
+
+import (
+	"net/http"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/render"
+	"github.com/drone/drone/logger"
+
+	"github.com/go-chi/chi"
+)
+
+// HandleDelete returns an http.HandlerFunc that processes http
+// requests to delete an organization policy.
+func HandleDelete(policies core.PolicyStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		policy, err := policies.Find(r.Context(), chi.URLParam(r, "namespace"))
+		if err != nil {
+			render.NotFound(w, err)
+			return
+		}
+		err = policies.Delete(r.Context(), policy)
+		if err != nil {
+			render.InternalError(w, err)
+			logger.FromRequest(r).WithError(err).
+				Warnln("api: cannot delete policy")
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,27 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package policies
+
+import (
+	"net/http"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/render"
+
+	"github.com/go-chi/chi"
+)
+
+// HandleFind returns an http.HandlerFunc that writes a json-encoded
+// organization policy to the response body.
+func HandleFind(policies core.PolicyStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		policy, err := policies.Find(r.Context(), chi.URLParam(r, "namespace"))
+		if err != nil {
+			render.NotFound(w, err)
+			return
+		}
+		render.JSON(w, policy, 200)
+	}
+}
@@ -0,0 +1,72 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package policies
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/render"
+	"github.com/drone/drone/logger"
+
+	"github.com/go-chi/chi"
+)
+
+type policyUpdate struct {
+	AllowRegistries []string `json:"allow_registries"`
+	DenyImages      []string `json:"deny_images"`
+	DenyPrivileged  *bool    `json:"deny_privileged"`
+	CloneDepth      *int     `json:"clone_depth"`
+}
+
+// HandleUpdate returns an http.HandlerFunc that processes http
+// requests to update an organization policy.
+func HandleUpdate(policies core.PolicyStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		in := new(policyUpdate)
+		err := json.NewDecoder(r.Body).Decode(in)
+		if err != nil {
+			render.BadRequest(w, err)
+			return
+		}
+
+		policy, err := policies.Find(r.Context(), chi.URLParam(r, "namespace"))
+		if err != nil {
+			render.NotFound(w, err)
+			return
+		}
+
+		if in.AllowRegistries != nil {
+			policy.AllowRegistries = in.AllowRegistries
+		}
+		if in.DenyImages != nil {
+			policy.DenyImages = in.DenyImages
+		}
+		if in.DenyPrivileged != nil {
+			policy.DenyPrivileged = *in.DenyPrivileged
+		}
+		if in.CloneDepth != nil {
+			policy.CloneDepth = *in.CloneDepth
+		}
+		policy.Updated = time.Now().Unix()
+
+		err = policy.Validate()
+		if err != nil {
+			render.BadRequest(w, err)
+			return
+		}
+
+		err = policies.Update(r.Context(), policy)
+		if err != nil {
+			render.InternalError(w, err)
+			logger.FromRequest(r).WithError(err).
+				Warnln("api: cannot update policy")
+			return
+		}
+		render.JSON(w, policy, 200)
+	}
+}
@@ -0,0 +1,90 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package policies
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/mock"
+
+	"github.com/go-chi/chi"
+	"github.com/golang/mock/gomock"
+)
+
+func TestHandleUpdate(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	policy := &core.Policy{
+		ID:              1,
+		Namespace:       "octocat",
+		AllowRegistries: []string{"docker.io"},
+	}
+
+	policies := mock.NewMockPolicyStore(controller)
+	policies.EXPECT().Find(gomock.Any(), "octocat").Return(policy, nil)
+	policies.EXPECT().Update(gomock.Any(), policy).Return(nil)
+
+	c := new(chi.Context)
+	c.URLParams.Add("namespace", "octocat")
+
+	in := new(bytes.Buffer)
+	json.NewEncoder(in).Encode(map[string]interface{}{
+		"deny_privileged": true,
+	})
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("PATCH", "/", in)
+	r = r.WithContext(
+		context.WithValue(context.Background(), chi.RouteCtxKey, c),
+	)
+
+	HandleUpdate(policies).ServeHTTP(w, r)
+	if got, want := w.Code, http.StatusOK; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+
+	got := new(core.Policy)
+	json.NewDecoder(w.Body).Decode(got)
+	if !got.DenyPrivileged {
+		t.Errorf("Want privileged mode denied")
+	}
+	if got, want := len(got.AllowRegistries), 1; got != want {
+		t.Errorf("Want allowed registries unchanged, got %d", got)
+	}
+}
+
+func TestHandleUpdate_ValidationError(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	policies := mock.NewMockPolicyStore(controller)
+	policies.EXPECT().Find(gomock.Any(), "octocat").Return(&core.Policy{Namespace: "octocat"}, nil)
+
+	c := new(chi.Context)
+	c.URLParams.Add("namespace", "octocat")
+
+	in := new(bytes.Buffer)
+	json.NewEncoder(in).Encode(map[string]interface{}{
+		"clone_depth": -1,
+	})
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("PATCH", "/", in)
+	r = r.WithContext(
+		context.WithValue(context.Background(), chi.RouteCtxKey, c),
+	)
+
+	HandleUpdate(policies).ServeHTTP(w, r)
+	if got, want := w.Code, http.StatusBadRequest; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+}
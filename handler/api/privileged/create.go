@@ -0,0 +1,54 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package privileged
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/render"
+	"github.com/drone/drone/logger"
+)
+
+type imageInput struct {
+	Namespace string `json:"namespace"`
+	Image     string `json:"image"`
+}
+
+// HandleCreate returns an http.HandlerFunc that processes http
+// requests to add an image to the privileged image whitelist.
+// Images without a namespace are allowed for all organizations.
+func HandleCreate(images core.PrivilegedImageStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		in := new(imageInput)
+		err := json.NewDecoder(r.Body).Decode(in)
+		if err != nil {
+			render.BadRequest(w, err)
+			return
+		}
+
+		image := &core.PrivilegedImage{
+			Namespace: in.Namespace,
+			Image:     in.Image,
+			Created:   time.Now().Unix(),
+		}
+		err = image.Validate()
+		if err != nil {
+			render.BadRequest(w, err)
+			return
+		}
+
+		err = images.Create(r.Context(), image)
+		if err != nil {
+			render.InternalError(w, err)
+			logger.FromRequest(r).WithError(err).
+				Warnln("api: cannot create privileged image")
+			return
+		}
+		render.JSON(w, image, 200)
+	}
+}
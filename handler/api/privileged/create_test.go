@@ -0,0 +1,68 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package privileged
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/mock"
+
+	"github.com/golang/mock/gomock"
+)
+
+func TestHandleCreate(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	images := mock.NewMockPrivilegedImageStore(controller)
+	images.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+
+	in := new(bytes.Buffer)
+	json.NewEncoder(in).Encode(map[string]interface{}{
+		"namespace": "octocat",
+		"image":     "docker:dind",
+	})
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/", in)
+
+	HandleCreate(images).ServeHTTP(w, r)
+	if got, want := w.Code, http.StatusOK; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+
+	got := new(core.PrivilegedImage)
+	json.NewDecoder(w.Body).Decode(got)
+	if got, want := got.Namespace, "octocat"; got != want {
+		t.Errorf("Want namespace %q, got %q", want, got)
+	}
+	if got, want := got.Image, "docker:dind"; got != want {
+		t.Errorf("Want image %q, got %q", want, got)
+	}
+}
+
+func TestHandleCreate_ValidationError(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	in := new(bytes.Buffer)
+	json.NewEncoder(in).Encode(map[string]interface{}{
+		"namespace": "octocat",
+	})
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/", in)
+
+	images := mock.NewMockPrivilegedImageStore(controller)
+	HandleCreate(images).ServeHTTP(w, r)
+	if got, want := w.Code, http.StatusBadRequest; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+}
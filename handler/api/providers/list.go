@@ -0,0 +1,25 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package providers
+
+import (
+	"net/http"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/render"
+)
+
+// HandleList returns an http.HandlerFunc that writes a json-encoded
+// list of the additional source code management providers to the
+// response body.
+func HandleList(providers core.ProviderService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		names := providers.List()
+		if names == nil {
+			names = []string{}
+		}
+		render.JSON(w, names, 200)
+	}
+}
@@ -0,0 +1,130 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/errors"
+	"github.com/drone/drone/handler/api/request"
+	"github.com/drone/drone/mock"
+
+	"github.com/go-chi/chi"
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+)
+
+var (
+	mockUser = &core.User{
+		ID:    1,
+		Login: "octocat",
+		Admin: true,
+	}
+
+	mockRepo = &core.Repository{
+		UID:       "internal~42",
+		Namespace: "internal~octocat",
+		Name:      "hello-world",
+		Slug:      "internal~octocat/hello-world",
+		Provider:  "internal",
+	}
+)
+
+func TestHandleList(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	providers := mock.NewMockProviderService(controller)
+	providers.EXPECT().List().Return([]string{"internal"})
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/", nil)
+
+	HandleList(providers)(w, r)
+	if got, want := w.Code, 200; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+
+	got, want := []string{}, []string{"internal"}
+	json.NewDecoder(w.Body).Decode(&got)
+	if diff := cmp.Diff(got, want); len(diff) != 0 {
+		t.Errorf(diff)
+	}
+}
+
+func TestHandleRegister(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	remote := *mockRepo
+
+	providers := mock.NewMockProviderService(controller)
+	providers.EXPECT().Find(gomock.Any(), "internal", "octocat/hello-world").Return(&remote, nil)
+
+	repos := mock.NewMockRepositoryStore(controller)
+	repos.EXPECT().FindName(gomock.Any(), "internal~octocat", "hello-world").Return(nil, errors.ErrNotFound)
+	repos.EXPECT().Create(gomock.Any(), &remote).Return(nil)
+
+	perms := mock.NewMockPermStore(controller)
+	perms.EXPECT().Find(gomock.Any(), "internal~42", mockUser.ID).Return(nil, errors.ErrNotFound)
+	perms.EXPECT().Create(gomock.Any(), gomock.Any()).Do(func(_ context.Context, perm *core.Perm) {
+		if !perm.Admin || perm.RepoUID != "internal~42" || perm.UserID != mockUser.ID {
+			t.Errorf("Want admin permissions granted to the user")
+		}
+	}).Return(nil)
+
+	c := new(chi.Context)
+	c.URLParams.Add("provider", "internal")
+	c.URLParams.Add("owner", "octocat")
+	c.URLParams.Add("name", "hello-world")
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/", nil)
+	r = r.WithContext(
+		context.WithValue(request.WithUser(r.Context(), mockUser), chi.RouteCtxKey, c),
+	)
+
+	HandleRegister(providers, repos, perms)(w, r)
+	if got, want := w.Code, 200; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+
+	got := new(core.Repository)
+	json.NewDecoder(w.Body).Decode(got)
+	if got, want := got.Slug, "internal~octocat/hello-world"; got != want {
+		t.Errorf("Want slug %q, got %q", want, got)
+	}
+	if got, want := got.UserID, mockUser.ID; got != want {
+		t.Errorf("Want repository owner %d, got %d", want, got)
+	}
+}
+
+func TestHandleRegister_NotFound(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	providers := mock.NewMockProviderService(controller)
+	providers.EXPECT().Find(gomock.Any(), "internal", "octocat/hello-world").Return(nil, errors.ErrNotFound)
+
+	c := new(chi.Context)
+	c.URLParams.Add("provider", "internal")
+	c.URLParams.Add("owner", "octocat")
+	c.URLParams.Add("name", "hello-world")
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/", nil)
+	r = r.WithContext(
+		context.WithValue(request.WithUser(r.Context(), mockUser), chi.RouteCtxKey, c),
+	)
+
+	HandleRegister(providers, nil, nil)(w, r)
+	if got, want := w.Code, 404; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+}
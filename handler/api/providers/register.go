@@ -0,0 +1,99 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package providers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/render"
+	"github.com/drone/drone/handler/api/request"
+	"github.com/drone/drone/logger"
+	"github.com/drone/go-scm/scm"
+
+	"github.com/go-chi/chi"
+)
+
+// HandleRegister returns an http.HandlerFunc that registers a
+// repository hosted by an additional provider, and grants the
+// user admin access to the repository. The repository can then
+// be enabled like any other repository.
+func HandleRegister(
+	providers core.ProviderService,
+	repos core.RepositoryStore,
+	perms core.PermStore,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var (
+			provider = chi.URLParam(r, "provider")
+			owner    = chi.URLParam(r, "owner")
+			name     = chi.URLParam(r, "name")
+		)
+		user, _ := request.UserFrom(r.Context())
+		log := logger.FromRequest(r).
+			WithField("provider", provider).
+			WithField("namespace", owner).
+			WithField("name", name)
+
+		remote, err := providers.Find(r.Context(), provider, scm.Join(owner, name))
+		if err != nil {
+			render.NotFound(w, err)
+			log.WithError(err).
+				Debugln("api: cannot find provider repository")
+			return
+		}
+
+		repo, err := repos.FindName(r.Context(), remote.Namespace, remote.Name)
+		if err != nil {
+			now := time.Now().Unix()
+			repo = remote
+			repo.UserID = user.ID
+			repo.Synced = now
+			repo.Created = now
+			repo.Updated = now
+			repo.Version = 1
+			err = repos.Create(r.Context(), repo)
+		}
+		if err != nil {
+			render.InternalError(w, err)
+			log.WithError(err).
+				Warnln("api: cannot register provider repository")
+			return
+		}
+
+		// permissions for repositories hosted by an additional
+		// provider are managed by drone and are never synced
+		// with the remote system.
+		perm, err := perms.Find(r.Context(), repo.UID, user.ID)
+		if err == nil {
+			perm.Read = true
+			perm.Write = true
+			perm.Admin = true
+			perm.Updated = time.Now().Unix()
+			err = perms.Update(r.Context(), perm)
+		} else {
+			now := time.Now().Unix()
+			err = perms.Create(r.Context(), &core.Perm{
+				UserID:  user.ID,
+				RepoUID: repo.UID,
+				Read:    true,
+				Write:   true,
+				Admin:   true,
+				Synced:  now,
+				Created: now,
+				Updated: now,
+			})
+		}
+		if err != nil {
+			render.InternalError(w, err)
+			log.WithError(err).
+				Warnln("api: cannot grant provider repository permissions")
+			return
+		}
+
+		render.JSON(w, repo, 200)
+	}
+}
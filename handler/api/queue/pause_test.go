@@ -3,3 +3,46 @@
 // that can be found in the LICENSE file.
 
 package queue
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/drone/drone/mock"
+
+	"github.com/golang/mock/gomock"
+)
+
+func TestHandlePause(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	scheduler := mock.NewMockScheduler(controller)
+	scheduler.EXPECT().Pause(gomock.Any()).Return(nil)
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/", nil)
+
+	HandlePause(scheduler)(w, r)
+	if got, want := w.Code, http.StatusNoContent; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+}
+
+func TestHandlePause_Error(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	scheduler := mock.NewMockScheduler(controller)
+	scheduler.EXPECT().Pause(gomock.Any()).Return(errors.New("not implemented"))
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/", nil)
+
+	HandlePause(scheduler)(w, r)
+	if got, want := w.Code, http.StatusInternalServerError; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+}
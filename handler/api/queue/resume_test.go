@@ -3,3 +3,29 @@
 // that can be found in the LICENSE file.
 
 package queue
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/drone/drone/mock"
+
+	"github.com/golang/mock/gomock"
+)
+
+func TestHandleResume(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	scheduler := mock.NewMockScheduler(controller)
+	scheduler.EXPECT().Resume(gomock.Any()).Return(nil)
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/", nil)
+
+	HandleResume(scheduler)(w, r)
+	if got, want := w.Code, http.StatusNoContent; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+}
@@ -0,0 +1,33 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package queue
+
+import (
+	"net/http"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/render"
+	"github.com/drone/drone/logger"
+)
+
+type status struct {
+	Paused bool `json:"paused"`
+}
+
+// HandleStatus returns an http.HandlerFunc that writes a
+// json-encoded queue status to the response body.
+func HandleStatus(scheduler core.Scheduler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		paused, err := scheduler.Paused(ctx)
+		if err != nil {
+			render.InternalError(w, err)
+			logger.FromRequest(r).WithError(err).
+				Errorln("api: cannot get queue status")
+			return
+		}
+		render.JSON(w, &status{Paused: paused}, 200)
+	}
+}
@@ -0,0 +1,179 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package ratelimit
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/drone/drone/handler/api/errors"
+	"github.com/drone/drone/handler/api/render"
+	"github.com/drone/drone/handler/api/request"
+	"github.com/drone/drone/logger"
+)
+
+var errRateLimited = errors.New("API rate limit exceeded")
+
+// sweepInterval defines the interval at which idle buckets
+// are removed from memory.
+const sweepInterval = time.Minute
+
+// Limiter limits the rate of api requests using a token bucket
+// per client. Authenticated requests are keyed by the access
+// token or user account, and anonymous requests are keyed by the
+// client ip address.
+type Limiter struct {
+	rate  float64 // tokens added per second
+	burst int
+
+	mu      sync.Mutex
+	buckets map[string]*bucket
+	swept   time.Time
+
+	now func() time.Time
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// New returns a new Limiter that allows limit requests per
+// minute, with bursts of up to burst requests. If the burst
+// is zero it defaults to the limit.
+func New(limit, burst int) *Limiter {
+	if burst <= 0 {
+		burst = limit
+	}
+	return &Limiter{
+		rate:    float64(limit) / 60,
+		burst:   burst,
+		buckets: map[string]*bucket{},
+		now:     time.Now,
+	}
+}
+
+// Handler returns an http.Handler middleware that rejects the
+// http.Request with 429 Too Many Requests if the client exceeds
+// the rate limit. The middleware must be installed after the
+// authentication middleware.
+func (l *Limiter) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		allowed, remaining, reset, retry := l.take(keyOf(r))
+
+		h := w.Header()
+		h.Set("X-RateLimit-Limit", strconv.Itoa(l.burst))
+		h.Set("X-RateLimit-Remaining", strconv.Itoa(remaining))
+		h.Set("X-RateLimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+
+		if !allowed {
+			h.Set("Retry-After", strconv.Itoa(int(math.Ceil(retry.Seconds()))))
+			render.TooManyRequests(w, errRateLimited)
+			logger.FromRequest(r).
+				Debugln("api: rate limit exceeded")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// helper function takes a token from the client bucket. It
+// returns true if the request is allowed, the number of
+// remaining requests, the time at which the bucket is full,
+// and the duration until the next request is allowed.
+func (l *Limiter) take(key string) (bool, int, time.Time, time.Duration) {
+	now := l.now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.sweep(now)
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(l.burst), last: now}
+		l.buckets[key] = b
+	}
+	b.tokens = l.fill(b, now)
+	b.last = now
+
+	allowed := b.tokens >= 1
+	if allowed {
+		b.tokens--
+	}
+
+	var retry time.Duration
+	if !allowed {
+		retry = l.duration(1 - b.tokens)
+	}
+	reset := now.Add(l.duration(float64(l.burst) - b.tokens))
+	return allowed, int(b.tokens), reset, retry
+}
+
+// helper function returns the number of tokens in the bucket
+// at the given time.
+func (l *Limiter) fill(b *bucket, now time.Time) float64 {
+	tokens := b.tokens + now.Sub(b.last).Seconds()*l.rate
+	return math.Min(tokens, float64(l.burst))
+}
+
+// helper function returns the time required to accumulate
+// the number of tokens.
+func (l *Limiter) duration(tokens float64) time.Duration {
+	return time.Duration(tokens / l.rate * float64(time.Second))
+}
+
+// helper function removes full buckets, which are equivalent
+// to a new bucket, to prevent unbounded memory growth.
+func (l *Limiter) sweep(now time.Time) {
+	if now.Sub(l.swept) < sweepInterval {
+		return
+	}
+	l.swept = now
+	for key, b := range l.buckets {
+		if l.fill(b, now) >= float64(l.burst) {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+// helper function returns the rate limit key for the request.
+// The token is hashed to avoid holding credentials in memory.
+func keyOf(r *http.Request) string {
+	user, ok := request.UserFrom(r.Context())
+	if !ok {
+		return "ip:" + remoteAddr(r)
+	}
+	if token := extractToken(r); token != "" {
+		return fmt.Sprintf("token:%x", sha256.Sum256([]byte(token)))
+	}
+	return "user:" + strconv.FormatInt(user.ID, 10)
+}
+
+// helper function returns the client ip address.
+func remoteAddr(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// helper function returns the access token from the
+// Authorization header or access_token query parameter.
+func extractToken(r *http.Request) string {
+	bearer := r.Header.Get("Authorization")
+	if bearer == "" {
+		bearer = r.FormValue("access_token")
+	}
+	return strings.TrimPrefix(bearer, "Bearer ")
+}
@@ -0,0 +1,151 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package registries
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/render"
+
+	"github.com/go-chi/chi"
+)
+
+type registryInput struct {
+	Address  *string `json:"address"`
+	Username *string `json:"username"`
+	Password *string `json:"password"`
+	Policy   *string `json:"policy"`
+}
+
+// apply copies the non-nil input fields to the registry.
+func (in *registryInput) apply(registry *core.Registry) {
+	if in.Address != nil {
+		registry.Address = *in.Address
+	}
+	if in.Username != nil {
+		registry.Username = *in.Username
+	}
+	if in.Password != nil {
+		registry.Password = *in.Password
+	}
+	if in.Policy != nil {
+		registry.Policy = *in.Policy
+	}
+}
+
+// HandleList returns an http.HandlerFunc that writes a json-encoded
+// list of organization registry credentials, without the passwords,
+// to the response body.
+func HandleList(registries core.RegistryStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		namespace := chi.URLParam(r, "namespace")
+		list, err := registries.ListNamespace(r.Context(), namespace)
+		if err != nil {
+			render.InternalError(w, err)
+			return
+		}
+		out := []*core.Registry{}
+		for _, registry := range list {
+			out = append(out, registry.Copy())
+		}
+		render.JSON(w, out, 200)
+	}
+}
+
+// HandleCreate returns an http.HandlerFunc that processes http
+// requests to create organization registry credentials.
+func HandleCreate(registries core.RegistryStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		in := new(registryInput)
+		err := json.NewDecoder(r.Body).Decode(in)
+		if err != nil {
+			render.BadRequest(w, err)
+			return
+		}
+
+		registry := &core.Registry{
+			Namespace: chi.URLParam(r, "namespace"),
+			Policy:    core.RegistryPull,
+			Created:   time.Now().Unix(),
+			Updated:   time.Now().Unix(),
+		}
+		in.apply(registry)
+
+		err = registry.Validate()
+		if err != nil {
+			render.BadRequest(w, err)
+			return
+		}
+
+		err = registries.Create(r.Context(), registry)
+		if err != nil {
+			render.InternalError(w, err)
+			return
+		}
+		render.JSON(w, registry.Copy(), 200)
+	}
+}
+
+// HandleUpdate returns an http.HandlerFunc that processes http
+// requests to update organization registry credentials.
+func HandleUpdate(registries core.RegistryStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		in := new(registryInput)
+		err := json.NewDecoder(r.Body).Decode(in)
+		if err != nil {
+			render.BadRequest(w, err)
+			return
+		}
+
+		registry, err := findRegistry(r.Context(), registries,
+			chi.URLParam(r, "namespace"),
+			chi.URLParam(r, "registry"),
+		)
+		if err != nil {
+			render.NotFound(w, err)
+			return
+		}
+		in.apply(registry)
+		registry.Updated = time.Now().Unix()
+
+		err = registry.Validate()
+		if err != nil {
+			render.BadRequest(w, err)
+			return
+		}
+
+		err = registries.Update(r.Context(), registry)
+		if err != nil {
+			render.InternalError(w, err)
+			return
+		}
+		render.JSON(w, registry.Copy(), 200)
+	}
+}
+
+// HandleDelete returns an http.HandlerFunc that processes http
+// requests to delete organization registry credentials.
+func HandleDelete(registries core.RegistryStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		registry, err := findRegistry(r.Context(), registries,
+			chi.URLParam(r, "namespace"),
+			chi.URLParam(r, "registry"),
+		)
+		if err != nil {
+			render.NotFound(w, err)
+			return
+		}
+
+		err = registries.Delete(r.Context(), registry)
+		if err != nil {
+			render.InternalError(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
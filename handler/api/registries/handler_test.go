@@ -0,0 +1,47 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package registries
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/mock"
+
+	"github.com/go-chi/chi"
+	"github.com/golang/mock/gomock"
+)
+
+func TestHandleDelete_RepositoryRegistry(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	// registry credentials that belong to a repository cannot
+	// be managed through the organization endpoints.
+	registries := mock.NewMockRegistryStore(controller)
+	registries.EXPECT().Find(gomock.Any(), int64(2)).Return(&core.Registry{
+		ID:        2,
+		RepoID:    1,
+		Namespace: "octocat",
+	}, nil)
+
+	c := new(chi.Context)
+	c.URLParams.Add("namespace", "octocat")
+	c.URLParams.Add("registry", "2")
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("DELETE", "/", nil)
+	r = r.WithContext(
+		context.WithValue(context.Background(), chi.RouteCtxKey, c),
+	)
+
+	HandleDelete(registries).ServeHTTP(w, r)
+	if got, want := w.Code, http.StatusNotFound; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+}
@@ -0,0 +1,31 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+// Package registries provides http handlers for managing
+// registry credentials shared by all repositories in an
+// organization.
+package registries
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+
+	"github.com/drone/drone/core"
+)
+
+func findRegistry(ctx context.Context, registries core.RegistryStore, namespace, param string) (*core.Registry, error) {
+	id, err := strconv.ParseInt(param, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	registry, err := registries.Find(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if registry.RepoID != 0 || registry.Namespace != namespace {
+		return nil, sql.ErrNoRows
+	}
+	return registry, nil
+}
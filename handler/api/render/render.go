@@ -100,6 +100,12 @@ func BadRequestf(w http.ResponseWriter, format string, a ...interface{}) {
 	ErrorCode(w, fmt.Errorf(format, a...), 400)
 }
 
+// TooManyRequests writes the json-encoded error message to the
+// response with a 429 too many requests status code.
+func TooManyRequests(w http.ResponseWriter, err error) {
+	ErrorCode(w, err, 429)
+}
+
 // JSON writes the json-encoded error message to the response
 // with a 400 bad request status code.
 func JSON(w http.ResponseWriter, v interface{}, status int) {
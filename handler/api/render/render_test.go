@@ -177,6 +177,23 @@ func TestBadRequestf(t *testing.T) {
 	}
 }
 
+func TestWriteTooManyRequests(t *testing.T) {
+	w := httptest.NewRecorder()
+
+	err := errors.New("pc load letter")
+	TooManyRequests(w, err)
+
+	if got, want := w.Code, 429; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+
+	errjson := &errors.Error{}
+	json.NewDecoder(w.Body).Decode(errjson)
+	if got, want := errjson.Message, err.Error(); got != want {
+		t.Errorf("Want error message %s, got %s", want, got)
+	}
+}
+
 func TestWriteJSON(t *testing.T) {
 	// without indent
 	{
@@ -0,0 +1,76 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package artifacts
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"strconv"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/errors"
+	"github.com/drone/drone/handler/api/render"
+
+	"github.com/go-chi/chi"
+)
+
+// HandleFind returns an http.HandlerFunc that writes the
+// artifact contents to the response body.
+func HandleFind(
+	repos core.RepositoryStore,
+	builds core.BuildStore,
+	artifacts core.ArtifactStore,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var (
+			namespace = chi.URLParam(r, "owner")
+			name      = chi.URLParam(r, "name")
+		)
+		number, err := strconv.ParseInt(chi.URLParam(r, "number"), 10, 64)
+		if err != nil {
+			render.BadRequest(w, err)
+			return
+		}
+		id, err := strconv.ParseInt(chi.URLParam(r, "artifact"), 10, 64)
+		if err != nil {
+			render.BadRequest(w, err)
+			return
+		}
+		repo, err := repos.FindName(r.Context(), namespace, name)
+		if err != nil {
+			render.NotFound(w, err)
+			return
+		}
+		build, err := builds.FindNumber(r.Context(), repo.ID, number)
+		if err != nil {
+			render.NotFound(w, err)
+			return
+		}
+		artifact, err := artifacts.Find(r.Context(), id)
+		if err != nil {
+			render.NotFound(w, err)
+			return
+		}
+		// the artifact must belong to the build to prevent
+		// downloading artifacts from another repository.
+		if artifact.BuildID != build.ID {
+			render.NotFound(w, errors.ErrNotFound)
+			return
+		}
+		rc, err := artifacts.Open(r.Context(), artifact)
+		if err != nil {
+			render.NotFound(w, err)
+			return
+		}
+		defer rc.Close()
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.Header().Set("Content-Length", strconv.FormatInt(artifact.Size, 10))
+		w.Header().Set("Content-Disposition",
+			fmt.Sprintf("attachment; filename=%q", path.Base(artifact.Name)))
+		io.Copy(w, rc)
+	}
+}
@@ -168,6 +168,23 @@ func HandleCancel(
 					Debugln("api: cannot update stage status")
 			}
 
+			if user != nil {
+				err := status.Send(r.Context(), user, &core.StatusInput{
+					Repo:  repo,
+					Build: build,
+					Stage: stage,
+				})
+				if err != nil {
+					logger.FromRequest(r).
+						WithError(err).
+						WithField("stage", stage.Number).
+						WithField("build", build.Number).
+						WithField("namespace", namespace).
+						WithField("name", name).
+						Debugln("api: cannot set stage status")
+				}
+			}
+
 			for _, step := range stage.Steps {
 				if step.IsDone() {
 					continue
@@ -52,7 +52,7 @@ func TestCancel(t *testing.T) {
 	steps.EXPECT().Update(gomock.Any(), mockStages[1].Steps[1]).Return(nil)
 
 	statusService := mock.NewMockStatusService(controller)
-	statusService.EXPECT().Send(gomock.Any(), mockUser, gomock.Any()).Return(nil)
+	statusService.EXPECT().Send(gomock.Any(), mockUser, gomock.Any()).Return(nil).Times(2)
 
 	webhook := mock.NewMockWebhookSender(controller)
 	webhook.EXPECT().Send(gomock.Any(), gomock.Any()).Return(nil)
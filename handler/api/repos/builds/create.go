@@ -0,0 +1,137 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package builds
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/render"
+	"github.com/drone/drone/handler/api/request"
+	"github.com/drone/drone/logger"
+
+	"github.com/go-chi/chi"
+)
+
+// HandleCreate returns an http.HandlerFunc that processes http
+// requests to create a build for the specified commit or branch.
+// Additional query parameters are passed to the pipeline as
+// custom build parameters. The label query parameter, which
+// can be repeated, adds labels to the build. The force query
+// parameter creates a new build even if the commit already
+// passed and the repository ignores duplicate builds.
+func HandleCreate(
+	users core.UserStore,
+	repos core.RepositoryStore,
+	commits core.CommitService,
+	triggerer core.Triggerer,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var (
+			ctx       = r.Context()
+			namespace = chi.URLParam(r, "owner")
+			name      = chi.URLParam(r, "name")
+			sha       = r.FormValue("commit")
+			branch    = r.FormValue("branch")
+			force, _  = strconv.ParseBool(r.FormValue("force"))
+			user, _   = request.UserFrom(ctx)
+		)
+
+		repo, err := repos.FindName(ctx, namespace, name)
+		if err != nil {
+			render.NotFound(w, err)
+			logger.FromRequest(r).
+				WithError(err).
+				WithField("namespace", namespace).
+				WithField("name", name).
+				Debugln("api: cannot find repository")
+			return
+		}
+
+		owner, err := users.Find(ctx, repo.UserID)
+		if err != nil {
+			render.NotFound(w, err)
+			logger.FromRequest(r).
+				WithError(err).
+				WithField("namespace", namespace).
+				WithField("name", name).
+				Debugln("api: cannot find repository owner")
+			return
+		}
+
+		// if the user does not provide a branch, assume the
+		// default repository branch.
+		if branch == "" {
+			branch = repo.Branch
+		}
+		branch = strings.TrimPrefix(branch, "refs/heads/")
+		ref := "refs/heads/" + branch
+
+		var commit *core.Commit
+		if sha != "" {
+			commit, err = commits.Find(ctx, owner, repo.Slug, sha)
+		} else {
+			commit, err = commits.FindRef(ctx, owner, repo.Slug, ref)
+		}
+		if err != nil {
+			render.NotFound(w, err)
+			logger.FromRequest(r).
+				WithError(err).
+				WithField("namespace", namespace).
+				WithField("name", name).
+				WithField("ref", ref).
+				WithField("commit", sha).
+				Debugln("api: cannot find commit")
+			return
+		}
+
+		hook := &core.Hook{
+			Trigger: user.Login,
+			Event:   core.EventCustom,
+			Link:    commit.Link,
+			Message: commit.Message,
+			Before:  commit.Sha,
+			After:   commit.Sha,
+			Ref:     ref,
+			Source:  branch,
+			Target:  branch,
+			Sender:  user.Login,
+			Params:  map[string]string{},
+			Force:   force,
+		}
+		if author := commit.Author; author != nil {
+			hook.Timestamp = author.Date
+			hook.Author = author.Login
+			hook.AuthorName = author.Name
+			hook.AuthorEmail = author.Email
+			hook.AuthorAvatar = author.Avatar
+		}
+
+		for key, value := range r.URL.Query() {
+			switch key {
+			case "access_token", "commit", "branch", "force":
+				continue
+			case "label":
+				hook.Labels = append(hook.Labels, value...)
+				continue
+			}
+			if len(value) == 0 {
+				continue
+			}
+			hook.Params[key] = value[0]
+		}
+
+		result, err := triggerer.Trigger(ctx, repo, hook)
+		if err != nil {
+			render.InternalError(w, err)
+		} else if result == nil {
+			render.BadRequestf(w, "Build was skipped")
+		} else {
+			render.JSON(w, result, 200)
+		}
+	}
+}
@@ -30,6 +30,8 @@ func HandleFind(
 	repos core.RepositoryStore,
 	builds core.BuildStore,
 	stages core.StageStore,
+	artifacts core.ArtifactStore,
+	annotations core.AnnotationStore,
 ) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		var (
@@ -56,11 +58,23 @@ func HandleFind(
 			render.InternalError(w, err)
 			return
 		}
-		render.JSON(w, &buildWithStages{build, stages}, 200)
+		list, err := artifacts.List(r.Context(), build.ID)
+		if err != nil {
+			render.InternalError(w, err)
+			return
+		}
+		notes, err := annotations.List(r.Context(), build.ID)
+		if err != nil {
+			render.InternalError(w, err)
+			return
+		}
+		render.JSON(w, &buildWithStages{build, stages, list, notes}, 200)
 	}
 }
 
 type buildWithStages struct {
 	*core.Build
-	Stages []*core.Stage `json:"stages,omitempty"`
+	Stages      []*core.Stage      `json:"stages,omitempty"`
+	Artifacts   []*core.Artifact   `json:"artifacts,omitempty"`
+	Annotations []*core.Annotation `json:"annotations,omitempty"`
 }
@@ -31,6 +31,12 @@ func TestFind(t *testing.T) {
 	stages := mock.NewMockStageStore(controller)
 	stages.EXPECT().ListSteps(gomock.Any(), mockBuild.ID).Return(mockStages, nil)
 
+	artifacts := mock.NewMockArtifactStore(controller)
+	artifacts.EXPECT().List(gomock.Any(), mockBuild.ID).Return(mockArtifacts, nil)
+
+	annotations := mock.NewMockAnnotationStore(controller)
+	annotations.EXPECT().List(gomock.Any(), mockBuild.ID).Return(mockAnnotations, nil)
+
 	c := new(chi.Context)
 	c.URLParams.Add("owner", "octocat")
 	c.URLParams.Add("name", "hello-world")
@@ -42,13 +48,13 @@ func TestFind(t *testing.T) {
 		context.WithValue(context.Background(), chi.RouteCtxKey, c),
 	)
 
-	HandleFind(repos, builds, stages)(w, r)
+	HandleFind(repos, builds, stages, artifacts, annotations)(w, r)
 
 	if got, want := w.Code, 200; want != got {
 		t.Errorf("Want response code %d, got %d", want, got)
 	}
 
-	got, want := &buildWithStages{}, &buildWithStages{mockBuild, mockStages}
+	got, want := &buildWithStages{}, &buildWithStages{mockBuild, mockStages, mockArtifacts, mockAnnotations}
 	json.NewDecoder(w.Body).Decode(got)
 	if diff := cmp.Diff(got, want); len(diff) != 0 {
 		t.Errorf(diff)
@@ -67,7 +73,7 @@ func TestFind_BadRequest(t *testing.T) {
 		context.WithValue(context.Background(), chi.RouteCtxKey, c),
 	)
 
-	HandleFind(nil, nil, nil)(w, r)
+	HandleFind(nil, nil, nil, nil, nil)(w, r)
 
 	if got, want := w.Code, 400; want != got {
 		t.Errorf("Want response code %d, got %d", want, got)
@@ -98,7 +104,7 @@ func TestFind_RepoNotFound(t *testing.T) {
 		context.WithValue(context.Background(), chi.RouteCtxKey, c),
 	)
 
-	HandleFind(repos, nil, nil)(w, r)
+	HandleFind(repos, nil, nil, nil, nil)(w, r)
 
 	if got, want := w.Code, 404; want != got {
 		t.Errorf("Want response code %d, got %d", want, got)
@@ -131,7 +137,7 @@ func TestFind_BuildNotFound(t *testing.T) {
 		context.WithValue(context.Background(), chi.RouteCtxKey, c),
 	)
 
-	HandleFind(repos, builds, nil)(w, r)
+	HandleFind(repos, builds, nil, nil, nil)(w, r)
 
 	if got, want := w.Code, 404; want != got {
 		t.Errorf("Want response code %d, got %d", want, got)
@@ -168,7 +174,7 @@ func TestFind_StagesNotFound(t *testing.T) {
 		context.WithValue(context.Background(), chi.RouteCtxKey, c),
 	)
 
-	HandleFind(repos, builds, stages)(w, r)
+	HandleFind(repos, builds, stages, nil, nil)(w, r)
 	if got, want := w.Code, 500; want != got {
 		t.Errorf("Want response code %d, got %d", want, got)
 	}
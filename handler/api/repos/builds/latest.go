@@ -55,6 +55,6 @@ func HandleLast(
 			render.InternalError(w, err)
 			return
 		}
-		render.JSON(w, &buildWithStages{build, stages}, 200)
+		render.JSON(w, &buildWithStages{Build: build, Stages: stages}, 200)
 	}
 }
@@ -26,7 +26,9 @@ import (
 )
 
 // HandleList returns an http.HandlerFunc that writes a json-encoded
-// list of build history to the response body.
+// list of build history to the response body. The list can be
+// filtered by status, event, branch, label and creation time, and
+// sorted in ascending or descending order.
 func HandleList(
 	repos core.RepositoryStore,
 	builds core.BuildStore,
@@ -49,6 +51,19 @@ func HandleList(
 		default:
 			offset = (offset - 1) * limit
 		}
+		since, _ := strconv.ParseInt(r.FormValue("since"), 10, 64)
+		before, _ := strconv.ParseInt(r.FormValue("before"), 10, 64)
+		filter := core.BuildFilter{
+			Status: r.FormValue("status"),
+			Event:  r.FormValue("event"),
+			Branch: r.FormValue("branch"),
+			Label:  r.FormValue("label"),
+			Since:  since,
+			Before: before,
+			Sort:   r.FormValue("sort"),
+			Limit:  limit,
+			Offset: offset,
+		}
 		repo, err := repos.FindName(r.Context(), namespace, name)
 		if err != nil {
 			render.NotFound(w, err)
@@ -59,7 +74,7 @@ func HandleList(
 				Debugln("api: cannot find repository")
 			return
 		}
-		builds, err := builds.List(r.Context(), repo.ID, limit, offset)
+		builds, err := builds.ListFilter(r.Context(), repo.ID, filter)
 		if err != nil {
 			render.InternalError(w, err)
 			logger.FromRequest(r).
@@ -69,6 +69,27 @@ var (
 		mockStage,
 	}
 
+	mockArtifacts = []*core.Artifact{
+		{
+			ID:      1,
+			BuildID: 1,
+			StepID:  1,
+			Name:    "dist/hello-world.tar.gz",
+			Size:    11,
+		},
+	}
+
+	mockAnnotations = []*core.Annotation{
+		{
+			ID:      1,
+			BuildID: 1,
+			StepID:  1,
+			Kind:    core.AnnotationLink,
+			Name:    "preview",
+			Value:   "https://preview.company.com",
+		},
+	}
+
 	mockUser = &core.User{
 		ID:    1,
 		Login: "octocat",
@@ -83,7 +104,7 @@ func TestList(t *testing.T) {
 	repos.EXPECT().FindName(gomock.Any(), gomock.Any(), mockRepo.Name).Return(mockRepo, nil)
 
 	builds := mock.NewMockBuildStore(controller)
-	builds.EXPECT().List(gomock.Any(), mockRepo.ID, 25, 0).Return(mockBuilds, nil)
+	builds.EXPECT().ListFilter(gomock.Any(), mockRepo.ID, core.BuildFilter{Limit: 25}).Return(mockBuilds, nil)
 
 	c := new(chi.Context)
 	c.URLParams.Add("owner", "octocat")
@@ -107,6 +128,43 @@ func TestList(t *testing.T) {
 	}
 }
 
+func TestList_Filter(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	filter := core.BuildFilter{
+		Status: core.StatusFailing,
+		Event:  core.EventPush,
+		Branch: "master",
+		Since:  1556000000,
+		Before: 1557000000,
+		Sort:   "asc",
+		Limit:  10,
+		Offset: 20,
+	}
+
+	repos := mock.NewMockRepositoryStore(controller)
+	repos.EXPECT().FindName(gomock.Any(), gomock.Any(), mockRepo.Name).Return(mockRepo, nil)
+
+	builds := mock.NewMockBuildStore(controller)
+	builds.EXPECT().ListFilter(gomock.Any(), mockRepo.ID, filter).Return(mockBuilds, nil)
+
+	c := new(chi.Context)
+	c.URLParams.Add("owner", "octocat")
+	c.URLParams.Add("name", "hello-world")
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/?page=3&per_page=10&status=failure&event=push&branch=master&since=1556000000&before=1557000000&sort=asc", nil)
+	r = r.WithContext(
+		context.WithValue(context.Background(), chi.RouteCtxKey, c),
+	)
+
+	HandleList(repos, builds)(w, r)
+	if got, want := w.Code, 200; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+}
+
 func TestList_RepositoryNotFound(t *testing.T) {
 	controller := gomock.NewController(t)
 	defer controller.Finish()
@@ -145,7 +203,7 @@ func TestList_InternalError(t *testing.T) {
 	repos := mock.NewMockRepositoryStore(controller)
 	builds := mock.NewMockBuildStore(controller)
 	repos.EXPECT().FindName(gomock.Any(), gomock.Any(), mockRepo.Name).Return(mockRepo, nil)
-	builds.EXPECT().List(gomock.Any(), mockRepo.ID, 25, 0).Return(nil, errors.ErrNotFound)
+	builds.EXPECT().ListFilter(gomock.Any(), mockRepo.ID, core.BuildFilter{Limit: 25}).Return(nil, errors.ErrNotFound)
 
 	c := new(chi.Context)
 	c.URLParams.Add("owner", "octocat")
@@ -70,6 +70,11 @@ func HandleFind(
 			return
 		}
 		w.Header().Set("Content-Type", "application/json")
+		if step.Attempts != 0 {
+			// the number of attempts is provided in the header
+			// because logs for all attempts are combined.
+			w.Header().Set("X-Drone-Step-Attempts", strconv.Itoa(step.Attempts))
+		}
 		io.Copy(w, rc)
 		rc.Close()
 
@@ -0,0 +1,124 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package builds
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/render"
+	"github.com/drone/drone/handler/api/request"
+	"github.com/drone/drone/logger"
+
+	"github.com/go-chi/chi"
+)
+
+// HandleRebuild returns an http.HandlerFunc that processes http
+// requests to build the latest commit of a branch, preserving the
+// parameters of the most recent build for the branch.
+func HandleRebuild(
+	users core.UserStore,
+	repos core.RepositoryStore,
+	builds core.BuildStore,
+	commits core.CommitService,
+	triggerer core.Triggerer,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var (
+			ctx       = r.Context()
+			namespace = chi.URLParam(r, "owner")
+			name      = chi.URLParam(r, "name")
+			branch    = r.FormValue("branch")
+			user, _   = request.UserFrom(ctx)
+		)
+
+		repo, err := repos.FindName(ctx, namespace, name)
+		if err != nil {
+			render.NotFound(w, err)
+			return
+		}
+
+		if branch == "" {
+			branch = repo.Branch
+		}
+		branch = strings.TrimPrefix(branch, "refs/heads/")
+		ref := "refs/heads/" + branch
+
+		prev, err := builds.FindRef(ctx, repo.ID, ref)
+		if err != nil {
+			render.NotFound(w, err)
+			logger.FromRequest(r).
+				WithError(err).
+				WithField("namespace", namespace).
+				WithField("name", name).
+				WithField("ref", ref).
+				Debugln("api: cannot find latest build")
+			return
+		}
+
+		owner, err := users.Find(ctx, repo.UserID)
+		if err != nil {
+			render.NotFound(w, err)
+			return
+		}
+
+		commit, err := commits.FindRef(ctx, owner, repo.Slug, ref)
+		if err != nil {
+			render.NotFound(w, err)
+			logger.FromRequest(r).
+				WithError(err).
+				WithField("namespace", namespace).
+				WithField("name", name).
+				WithField("ref", ref).
+				Debugln("api: cannot find commit")
+			return
+		}
+
+		hook := &core.Hook{
+			Trigger: user.Login,
+			Event:   prev.Event,
+			Link:    commit.Link,
+			Message: commit.Message,
+			Before:  prev.After,
+			After:   commit.Sha,
+			Ref:     ref,
+			Source:  branch,
+			Target:  branch,
+			Sender:  user.Login,
+			Params:  map[string]string{},
+		}
+		if author := commit.Author; author != nil {
+			hook.Timestamp = author.Date
+			hook.Author = author.Login
+			hook.AuthorName = author.Name
+			hook.AuthorEmail = author.Email
+			hook.AuthorAvatar = author.Avatar
+		}
+
+		for key, value := range prev.Params {
+			hook.Params[key] = value
+		}
+		for key, value := range r.URL.Query() {
+			switch key {
+			case "access_token", "branch":
+				continue
+			}
+			if len(value) == 0 {
+				continue
+			}
+			hook.Params[key] = value[0]
+		}
+
+		result, err := triggerer.Trigger(ctx, repo, hook)
+		if err != nil {
+			render.InternalError(w, err)
+		} else if result == nil {
+			render.BadRequestf(w, "Build was skipped")
+		} else {
+			render.JSON(w, result, 200)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package builds
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/errors"
+	"github.com/drone/drone/handler/api/request"
+	"github.com/drone/drone/mock"
+
+	"github.com/go-chi/chi"
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRebuild(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockPrev := &core.Build{
+		Number: 1,
+		Event:  core.EventPush,
+		After:  "553c2077f0edc3d5dc5d17262f6aa498e69d6f8e",
+		Ref:    "refs/heads/release",
+		Params: map[string]string{"channel": "stable", "version": "1.0"},
+	}
+	mockCommit := &core.Commit{
+		Sha:     "cce10d5c4760d1d6ede99db850ab7e77efe15579",
+		Message: "updated README.md",
+		Author:  &core.Committer{Login: "octocat"},
+	}
+
+	checkBuild := func(_ context.Context, _ *core.Repository, hook *core.Hook) error {
+		if got, want := hook.After, mockCommit.Sha; got != want {
+			t.Errorf("Want hook After %s, got %s", want, got)
+		}
+		if got, want := hook.Before, mockPrev.After; got != want {
+			t.Errorf("Want hook Before %s, got %s", want, got)
+		}
+		if got, want := hook.Ref, "refs/heads/release"; got != want {
+			t.Errorf("Want hook Ref %s, got %s", want, got)
+		}
+		if got, want := hook.Trigger, mockUser.Login; got != want {
+			t.Errorf("Want hook Trigger %s, got %s", want, got)
+		}
+		want := map[string]string{"channel": "stable", "version": "1.1"}
+		if diff := cmp.Diff(hook.Params, want); diff != "" {
+			t.Errorf(diff)
+		}
+		return nil
+	}
+
+	repos := mock.NewMockRepositoryStore(controller)
+	repos.EXPECT().FindName(gomock.Any(), gomock.Any(), mockRepo.Name).Return(mockRepo, nil)
+
+	builds := mock.NewMockBuildStore(controller)
+	builds.EXPECT().FindRef(gomock.Any(), mockRepo.ID, "refs/heads/release").Return(mockPrev, nil)
+
+	users := mock.NewMockUserStore(controller)
+	users.EXPECT().Find(gomock.Any(), mockRepo.UserID).Return(mockUser, nil)
+
+	commits := mock.NewMockCommitService(controller)
+	commits.EXPECT().FindRef(gomock.Any(), mockUser, mockRepo.Slug, "refs/heads/release").Return(mockCommit, nil)
+
+	triggerer := mock.NewMockTriggerer(controller)
+	triggerer.EXPECT().Trigger(gomock.Any(), mockRepo, gomock.Any()).Return(mockBuild, nil).Do(checkBuild)
+
+	c := new(chi.Context)
+	c.URLParams.Add("owner", "octocat")
+	c.URLParams.Add("name", "hello-world")
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/?branch=release&version=1.1", nil)
+	r = r.WithContext(
+		context.WithValue(request.WithUser(r.Context(), mockUser), chi.RouteCtxKey, c),
+	)
+
+	HandleRebuild(users, repos, builds, commits, triggerer)(w, r)
+	if got, want := w.Code, 200; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+}
+
+func TestRebuild_BuildNotFound(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	repos := mock.NewMockRepositoryStore(controller)
+	repos.EXPECT().FindName(gomock.Any(), gomock.Any(), mockRepo.Name).Return(mockRepo, nil)
+
+	builds := mock.NewMockBuildStore(controller)
+	builds.EXPECT().FindRef(gomock.Any(), mockRepo.ID, "refs/heads/master").Return(nil, errors.ErrNotFound)
+
+	c := new(chi.Context)
+	c.URLParams.Add("owner", "octocat")
+	c.URLParams.Add("name", "hello-world")
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/", nil)
+	r = r.WithContext(
+		context.WithValue(request.WithUser(r.Context(), mockUser), chi.RouteCtxKey, c),
+	)
+
+	HandleRebuild(nil, repos, builds, nil, nil)(w, r)
+	if got, want := w.Code, 404; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+}
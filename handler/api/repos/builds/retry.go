@@ -64,6 +64,7 @@ func HandleRetry(
 		}
 
 		hook := &core.Hook{
+			Parent:       prev.Number,
 			Trigger:      user.Login,
 			Event:        prev.Event,
 			Action:       prev.Action,
@@ -82,8 +83,14 @@ func HandleRetry(
 			AuthorAvatar: prev.AuthorAvatar,
 			Sender:       prev.Sender,
 			Params:       map[string]string{},
+			Labels:       prev.Labels,
 		}
 
+		// the restarted build inherits the parameters of the
+		// original build, which can be overridden by the user.
+		for key, value := range prev.Params {
+			hook.Params[key] = value
+		}
 		for key, value := range r.URL.Query() {
 			if key == "access_token" {
 				continue
@@ -67,6 +67,9 @@ func TestRetry(t *testing.T) {
 		if got, want := hook.Sender, mockBuild.Sender; got != want {
 			t.Errorf("Want Build Sender %s, got %s", want, got)
 		}
+		if got, want := hook.Parent, mockBuild.Number; got != want {
+			t.Errorf("Want Build Parent %d, got %d", want, got)
+		}
 		return nil
 	}
 
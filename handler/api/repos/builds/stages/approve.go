@@ -8,8 +8,11 @@ import (
 	"context"
 	"net/http"
 	"strconv"
+	"time"
 
+	"github.com/drone/drone/handler/api/errors"
 	"github.com/drone/drone/handler/api/render"
+	"github.com/drone/drone/handler/api/request"
 	"github.com/drone/drone/core"
 
 	"github.com/go-chi/chi"
@@ -17,6 +20,11 @@ import (
 
 var noContext = context.Background()
 
+// errElevated is returned when a user that is not a system
+// administrator approves a pipeline that requests elevated
+// privileges.
+var errElevated = errors.New("Pipelines that request elevated privileges must be approved by a system administrator")
+
 // HandleApprove returns an http.HandlerFunc that processes http
 // requests to approve a blocked build that is pending review.
 func HandleApprove(
@@ -59,7 +67,38 @@ func HandleApprove(
 			render.BadRequestf(w, "Cannot approve a Pipeline with Status %q", stage.Status)
 			return
 		}
+		// pipelines in untrusted repositories that request
+		// privileged containers or host volumes
+		// can only be approved by a system administrator.
+		if user, _ := request.UserFrom(r.Context()); stage.Elevated && (user == nil || !user.Admin) {
+			render.Forbidden(w, errElevated)
+			return
+		}
+		// pipelines with dependencies are not scheduled until
+		// the pipelines they depend on are complete. The
+		// approved pipeline is returned to the waiting state,
+		// and is scheduled when the last dependency completes.
+		ready := true
+		if len(stage.DependsOn) != 0 {
+			siblings, err := stages.List(r.Context(), build.ID)
+			if err != nil {
+				render.InternalErrorf(w, "There was a problem approving the Pipeline")
+				return
+			}
+			ready = areDepsComplete(stage, siblings)
+		}
+		if !ready {
+			stage.Status = core.StatusWaiting
+			err = stages.Update(r.Context(), stage)
+			if err != nil {
+				render.InternalErrorf(w, "There was a problem approving the Pipeline")
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
 		stage.Status = core.StatusPending
+		stage.Queued = time.Now().Unix()
 		err = stages.Update(r.Context(), stage)
 		if err != nil {
 			render.InternalErrorf(w, "There was a problem approving the Pipeline")
@@ -73,3 +112,21 @@ func HandleApprove(
 		w.WriteHeader(http.StatusNoContent)
 	}
 }
+
+// helper function returns true if the stages that the
+// stage depends on are complete.
+func areDepsComplete(stage *core.Stage, stages []*core.Stage) bool {
+	deps := map[string]struct{}{}
+	for _, dep := range stage.DependsOn {
+		deps[dep] = struct{}{}
+	}
+	for _, sibling := range stages {
+		if _, ok := deps[sibling.Name]; !ok {
+			continue
+		}
+		if !sibling.IsDone() {
+			return false
+		}
+	}
+	return true
+}
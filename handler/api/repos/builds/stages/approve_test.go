@@ -13,6 +13,7 @@ import (
 	"testing"
 
 	"github.com/drone/drone/handler/api/errors"
+	"github.com/drone/drone/handler/api/request"
 	"github.com/drone/drone/mock"
 	"github.com/drone/drone/core"
 
@@ -80,9 +81,200 @@ func TestApprove(t *testing.T) {
 	}
 }
 
+// this test verifies that an approved pipeline is returned
+// to the waiting state, and is not scheduled, if the pipelines
+// it depends on are not complete.
+func TestApprove_WaitingDependencies(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockRepo := &core.Repository{
+		Namespace: "octocat",
+		Name:      "hello-world",
+	}
+	mockBuild := &core.Build{
+		ID:     111,
+		Number: 1,
+		Status: core.StatusPending,
+	}
+	mockStage := &core.Stage{
+		ID:     222,
+		Number: 2,
+		Name:      "deploy",
+		Status:    core.StatusBlocked,
+		DependsOn: []string{"build"},
+	}
+	mockStages := []*core.Stage{
+		{ID: 221, Number: 1, Name: "build", Status: core.StatusRunning},
+		mockStage,
+	}
+
+	checkStage := func(_ context.Context, stage *core.Stage) error {
+		if stage.Status != core.StatusWaiting {
+			t.Errorf("Want stage status changed to Waiting")
+		}
+		return nil
+	}
+
+	repos := mock.NewMockRepositoryStore(controller)
+	repos.EXPECT().FindName(gomock.Any(), mockRepo.Namespace, mockRepo.Name).Return(mockRepo, nil)
+
+	builds := mock.NewMockBuildStore(controller)
+	builds.EXPECT().FindNumber(gomock.Any(), mockRepo.ID, mockBuild.Number).Return(mockBuild, nil)
+
+	stages := mock.NewMockStageStore(controller)
+	stages.EXPECT().FindNumber(gomock.Any(), mockBuild.ID, mockStage.Number).Return(mockStage, nil)
+	stages.EXPECT().List(gomock.Any(), mockBuild.ID).Return(mockStages, nil)
+	stages.EXPECT().Update(gomock.Any(), mockStage).Return(nil).Do(checkStage)
+
+	// the scheduler has no expectations, which verifies the
+	// pipeline is not scheduled before its dependencies are
+	// complete.
+	sched := mock.NewMockScheduler(controller)
+
+	c := new(chi.Context)
+	c.URLParams.Add("owner", "octocat")
+	c.URLParams.Add("name", "hello-world")
+	c.URLParams.Add("number", "1")
+	c.URLParams.Add("stage", "2")
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/", nil)
+	r = r.WithContext(
+		context.WithValue(context.Background(), chi.RouteCtxKey, c),
+	)
+
+	HandleApprove(repos, builds, stages, sched)(w, r)
+	if got, want := w.Code, 204; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+}
+
+// this test verifies that an approved pipeline is scheduled
+// if the pipelines it depends on are complete.
+func TestApprove_CompleteDependencies(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockRepo := &core.Repository{
+		Namespace: "octocat",
+		Name:      "hello-world",
+	}
+	mockBuild := &core.Build{
+		ID:     111,
+		Number: 1,
+		Status: core.StatusPending,
+	}
+	mockStage := &core.Stage{
+		ID:     222,
+		Number: 2,
+		Name:      "deploy",
+		Status:    core.StatusBlocked,
+		DependsOn: []string{"build"},
+	}
+	mockStages := []*core.Stage{
+		{ID: 221, Number: 1, Name: "build", Status: core.StatusPassing},
+		mockStage,
+	}
+
+	checkStage := func(_ context.Context, stage *core.Stage) error {
+		if stage.Status != core.StatusPending {
+			t.Errorf("Want stage status changed to Pending")
+		}
+		return nil
+	}
+
+	repos := mock.NewMockRepositoryStore(controller)
+	repos.EXPECT().FindName(gomock.Any(), mockRepo.Namespace, mockRepo.Name).Return(mockRepo, nil)
+
+	builds := mock.NewMockBuildStore(controller)
+	builds.EXPECT().FindNumber(gomock.Any(), mockRepo.ID, mockBuild.Number).Return(mockBuild, nil)
+
+	stages := mock.NewMockStageStore(controller)
+	stages.EXPECT().FindNumber(gomock.Any(), mockBuild.ID, mockStage.Number).Return(mockStage, nil)
+	stages.EXPECT().List(gomock.Any(), mockBuild.ID).Return(mockStages, nil)
+	stages.EXPECT().Update(gomock.Any(), mockStage).Return(nil).Do(checkStage)
+
+	sched := mock.NewMockScheduler(controller)
+	sched.EXPECT().Schedule(gomock.Any(), mockStage).Return(nil)
+
+	c := new(chi.Context)
+	c.URLParams.Add("owner", "octocat")
+	c.URLParams.Add("name", "hello-world")
+	c.URLParams.Add("number", "1")
+	c.URLParams.Add("stage", "2")
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/", nil)
+	r = r.WithContext(
+		context.WithValue(context.Background(), chi.RouteCtxKey, c),
+	)
+
+	HandleApprove(repos, builds, stages, sched)(w, r)
+	if got, want := w.Code, 204; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+}
+
 // this test verifies that a 400 bad request status is returned
 // from the http.Handler with a human-readable error message if
 // the build status is not Blocked.
+// this test verifies that a pipeline that requests elevated
+// privileges cannot be approved by a repository administrator
+// that is not a system administrator.
+func TestApprove_Elevated(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockRepo := &core.Repository{
+		Namespace: "octocat",
+		Name:      "hello-world",
+	}
+	mockBuild := &core.Build{
+		ID:     111,
+		Number: 1,
+		Status: core.StatusPending,
+	}
+	mockStage := &core.Stage{
+		ID:       222,
+		Number:   2,
+		Status:   core.StatusBlocked,
+		Elevated: true,
+	}
+
+	repos := mock.NewMockRepositoryStore(controller)
+	repos.EXPECT().FindName(gomock.Any(), mockRepo.Namespace, mockRepo.Name).Return(mockRepo, nil)
+
+	builds := mock.NewMockBuildStore(controller)
+	builds.EXPECT().FindNumber(gomock.Any(), mockRepo.ID, mockBuild.Number).Return(mockBuild, nil)
+
+	stages := mock.NewMockStageStore(controller)
+	stages.EXPECT().FindNumber(gomock.Any(), mockBuild.ID, mockStage.Number).Return(mockStage, nil)
+
+	c := new(chi.Context)
+	c.URLParams.Add("owner", "octocat")
+	c.URLParams.Add("name", "hello-world")
+	c.URLParams.Add("number", "1")
+	c.URLParams.Add("stage", "2")
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/", nil)
+	r = r.WithContext(
+		context.WithValue(request.WithUser(context.Background(), &core.User{Login: "octocat"}), chi.RouteCtxKey, c),
+	)
+
+	HandleApprove(repos, builds, stages, nil)(w, r)
+	if got, want := w.Code, 403; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+
+	got, want := new(errors.Error), errElevated
+	json.NewDecoder(w.Body).Decode(got)
+	if diff := cmp.Diff(got, want); len(diff) != 0 {
+		t.Errorf(diff)
+	}
+}
+
 func TestApprove_InvalidStatus(t *testing.T) {
 	controller := gomock.NewController(t)
 	defer controller.Finish()
@@ -0,0 +1,71 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package tests
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/render"
+
+	"github.com/go-chi/chi"
+)
+
+// summary provides the aggregate test results for a build.
+type summary struct {
+	Tests    int                `json:"tests"`
+	Passed   int                `json:"passed"`
+	Failures int                `json:"failures"`
+	Errors   int                `json:"errors"`
+	Skipped  int                `json:"skipped"`
+	Duration int64              `json:"duration"`
+	Results  []*core.TestResult `json:"results"`
+}
+
+// HandleList returns an http.HandlerFunc that writes the
+// json-encoded build test results to the response body.
+func HandleList(
+	repos core.RepositoryStore,
+	builds core.BuildStore,
+	tests core.TestResultStore,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var (
+			namespace = chi.URLParam(r, "owner")
+			name      = chi.URLParam(r, "name")
+		)
+		number, err := strconv.ParseInt(chi.URLParam(r, "number"), 10, 64)
+		if err != nil {
+			render.BadRequest(w, err)
+			return
+		}
+		repo, err := repos.FindName(r.Context(), namespace, name)
+		if err != nil {
+			render.NotFound(w, err)
+			return
+		}
+		build, err := builds.FindNumber(r.Context(), repo.ID, number)
+		if err != nil {
+			render.NotFound(w, err)
+			return
+		}
+		results, err := tests.List(r.Context(), build.ID)
+		if err != nil {
+			render.InternalError(w, err)
+			return
+		}
+		out := &summary{Results: results}
+		for _, result := range results {
+			out.Tests += result.Tests
+			out.Passed += result.Passed()
+			out.Failures += result.Failures
+			out.Errors += result.Errors
+			out.Skipped += result.Skipped
+			out.Duration += result.Duration
+		}
+		render.JSON(w, out, 200)
+	}
+}
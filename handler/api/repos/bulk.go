@@ -0,0 +1,226 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package repos
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/render"
+	"github.com/drone/drone/handler/api/request"
+	"github.com/drone/drone/logger"
+)
+
+// bulkConcurrency is the maximum number of repositories
+// that are enabled or disabled concurrently.
+const bulkConcurrency = 10
+
+type (
+	// bulkRequest selects the repositories to enable or
+	// disable, by namespace or by an explicit list of
+	// repository slugs.
+	bulkRequest struct {
+		Namespace string   `json:"namespace"`
+		Repos     []string `json:"repos"`
+	}
+
+	// bulkResult is the outcome of enabling or disabling
+	// a single repository.
+	bulkResult struct {
+		Slug   string `json:"slug"`
+		Active bool   `json:"active"`
+		Error  string `json:"error,omitempty"`
+	}
+
+	// bulkFunc enables or disables a single repository.
+	bulkFunc func(ctx context.Context, user *core.User, repo *core.Repository) error
+)
+
+// HandleEnableBulk returns an http.HandlerFunc that processes
+// http requests to enable many repositories in the system. The
+// repository webhooks are created in parallel.
+func HandleEnableBulk(
+	hooks core.HookService,
+	repos core.RepositoryStore,
+	sender core.WebhookSender,
+) http.HandlerFunc {
+	return handleBulk(repos, false, func(ctx context.Context, user *core.User, repo *core.Repository) error {
+		repo.Active = true
+		repo.UserID = user.ID
+		setDefaults(repo)
+
+		err := hooks.Create(ctx, user, repo)
+		if err != nil {
+			repo.Active = false
+			return err
+		}
+		err = repos.Activate(ctx, repo)
+		if err != nil {
+			repo.Active = false
+			return err
+		}
+		err = sender.Send(ctx, &core.WebhookData{
+			Event:  core.WebhookEventRepo,
+			Action: core.WebhookActionEnabled,
+			User:   user,
+			Repo:   repo,
+		})
+		if err != nil {
+			logger.FromContext(ctx).
+				WithError(err).
+				WithField("repo", repo.Slug).
+				Warnln("api: cannot send webhook")
+		}
+		return nil
+	})
+}
+
+// HandleDisableBulk returns an http.HandlerFunc that processes
+// http requests to disable many repositories in the system.
+func HandleDisableBulk(
+	repos core.RepositoryStore,
+	sender core.WebhookSender,
+) http.HandlerFunc {
+	return handleBulk(repos, true, func(ctx context.Context, user *core.User, repo *core.Repository) error {
+		repo.Active = false
+		err := repos.Update(ctx, repo)
+		if err != nil {
+			repo.Active = true
+			return err
+		}
+		err = sender.Send(ctx, &core.WebhookData{
+			Event:  core.WebhookEventRepo,
+			Action: core.WebhookActionDisabled,
+			Repo:   repo,
+		})
+		if err != nil {
+			logger.FromContext(ctx).
+				WithError(err).
+				WithField("repo", repo.Slug).
+				Warnln("api: cannot send webhook")
+		}
+		return nil
+	})
+}
+
+// handleBulk returns an http.HandlerFunc that applies the bulk
+// function to the requested repositories with bounded
+// concurrency, and writes the json-encoded results to the
+// response body. If a namespace is requested and activeOnly is
+// true, only active repositories in the namespace are selected.
+func handleBulk(repos core.RepositoryStore, activeOnly bool, fn bulkFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, _ := request.UserFrom(r.Context())
+
+		in := new(bulkRequest)
+		err := json.NewDecoder(r.Body).Decode(in)
+		if err != nil {
+			render.BadRequest(w, err)
+			logger.FromRequest(r).WithError(err).
+				Debugln("api: cannot unmarshal json input")
+			return
+		}
+		if in.Namespace == "" && len(in.Repos) == 0 {
+			render.BadRequestf(w, "A namespace or list of repositories is required")
+			return
+		}
+
+		var list []*core.Repository
+		var results []*bulkResult
+		if in.Namespace != "" {
+			list, err = listNamespace(r.Context(), repos, user, in.Namespace, activeOnly)
+			if err != nil {
+				render.InternalError(w, err)
+				logger.FromRequest(r).WithError(err).
+					WithField("namespace", in.Namespace).
+					Debugln("api: cannot list repositories")
+				return
+			}
+			for _, repo := range list {
+				results = append(results, &bulkResult{Slug: repo.Slug, Active: repo.Active})
+			}
+		}
+		for _, slug := range in.Repos {
+			result := &bulkResult{Slug: slug}
+			results = append(results, result)
+			parts := strings.SplitN(slug, "/", 2)
+			if len(parts) != 2 {
+				result.Error = "Invalid repository slug"
+				list = append(list, nil)
+				continue
+			}
+			repo, err := repos.FindName(r.Context(), parts[0], parts[1])
+			if err != nil {
+				result.Error = "Not Found"
+				list = append(list, nil)
+				continue
+			}
+			result.Active = repo.Active
+			list = append(list, repo)
+		}
+
+		var wg sync.WaitGroup
+		sem := make(chan struct{}, bulkConcurrency)
+		for i, repo := range list {
+			if repo == nil {
+				continue
+			}
+			wg.Add(1)
+			sem <- struct{}{}
+			go func(repo *core.Repository, result *bulkResult) {
+				defer func() {
+					<-sem
+					wg.Done()
+				}()
+				err := fn(r.Context(), user, repo)
+				if err != nil {
+					result.Error = err.Error()
+					logger.FromRequest(r).WithError(err).
+						WithField("repo", repo.Slug).
+						Debugln("api: cannot update repository")
+				}
+				result.Active = repo.Active
+			}(repo, results[i])
+		}
+		wg.Wait()
+
+		render.JSON(w, results, 200)
+	}
+}
+
+// helper function returns all repositories in the namespace
+// that are visible to the user.
+func listNamespace(ctx context.Context, repos core.RepositoryStore, user *core.User, namespace string, activeOnly bool) ([]*core.Repository, error) {
+	var out []*core.Repository
+	for offset := 0; ; offset += 100 {
+		list, err := repos.ListFilter(ctx, user.ID, core.RepositoryFilter{
+			Namespace: namespace,
+			Active:    activeOnly,
+			Limit:     100,
+			Offset:    offset,
+		})
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, list...)
+		if len(list) < 100 {
+			return out, nil
+		}
+	}
+}
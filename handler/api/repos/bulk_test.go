@@ -0,0 +1,134 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package repos
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/request"
+	"github.com/drone/drone/mock"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestEnableBulk(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	user := &core.User{ID: 1, Admin: true}
+	repo := &core.Repository{
+		ID:        1,
+		Namespace: "octocat",
+		Name:      "hello-world",
+		Slug:      "octocat/hello-world",
+	}
+
+	hooks := mock.NewMockHookService(controller)
+	hooks.EXPECT().Create(gomock.Any(), user, repo).Return(nil)
+
+	repos := mock.NewMockRepositoryStore(controller)
+	repos.EXPECT().FindName(gomock.Any(), "octocat", "hello-world").Return(repo, nil)
+	repos.EXPECT().FindName(gomock.Any(), "octocat", "spoon-fork").Return(nil, sql.ErrNoRows)
+	repos.EXPECT().Activate(gomock.Any(), repo).Return(nil)
+
+	webhook := mock.NewMockWebhookSender(controller)
+	webhook.EXPECT().Send(gomock.Any(), gomock.Any()).Return(nil)
+
+	in := new(bytes.Buffer)
+	json.NewEncoder(in).Encode(&bulkRequest{
+		Repos: []string{"octocat/hello-world", "octocat/spoon-fork", "octocat"},
+	})
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/", in)
+	r = r.WithContext(
+		request.WithUser(r.Context(), user),
+	)
+
+	HandleEnableBulk(hooks, repos, webhook)(w, r)
+	if got, want := w.Code, 200; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+
+	got, want := []*bulkResult{}, []*bulkResult{
+		{Slug: "octocat/hello-world", Active: true},
+		{Slug: "octocat/spoon-fork", Error: "Not Found"},
+		{Slug: "octocat", Error: "Invalid repository slug"},
+	}
+	json.NewDecoder(w.Body).Decode(&got)
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf(diff)
+	}
+	if repo.UserID != user.ID {
+		t.Errorf("Want repository owner set to the admin user")
+	}
+}
+
+func TestDisableBulk_Namespace(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	user := &core.User{ID: 1, Admin: true}
+	list := []*core.Repository{
+		{ID: 1, Slug: "octocat/hello-world", Active: true},
+		{ID: 2, Slug: "octocat/spoon-fork", Active: true},
+	}
+
+	filter := core.RepositoryFilter{
+		Namespace: "octocat",
+		Active:    true,
+		Limit:     100,
+	}
+
+	repos := mock.NewMockRepositoryStore(controller)
+	repos.EXPECT().ListFilter(gomock.Any(), user.ID, filter).Return(list, nil)
+	repos.EXPECT().Update(gomock.Any(), list[0]).Return(nil)
+	repos.EXPECT().Update(gomock.Any(), list[1]).Return(sql.ErrConnDone)
+
+	webhook := mock.NewMockWebhookSender(controller)
+	webhook.EXPECT().Send(gomock.Any(), gomock.Any()).Return(nil)
+
+	in := new(bytes.Buffer)
+	json.NewEncoder(in).Encode(&bulkRequest{Namespace: "octocat"})
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/", in)
+	r = r.WithContext(
+		request.WithUser(r.Context(), user),
+	)
+
+	HandleDisableBulk(repos, webhook)(w, r)
+	if got, want := w.Code, 200; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+
+	got, want := []*bulkResult{}, []*bulkResult{
+		{Slug: "octocat/hello-world", Active: false},
+		{Slug: "octocat/spoon-fork", Active: true, Error: sql.ErrConnDone.Error()},
+	}
+	json.NewDecoder(w.Body).Decode(&got)
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf(diff)
+	}
+}
+
+func TestEnableBulk_BadRequest(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/", bytes.NewBufferString("{}"))
+	r = r.WithContext(
+		request.WithUser(r.Context(), &core.User{ID: 1}),
+	)
+
+	HandleEnableBulk(nil, nil, nil)(w, r)
+	if got, want := w.Code, 400; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+}
@@ -0,0 +1,145 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package collabs
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/errors"
+	"github.com/drone/drone/handler/api/render"
+	"github.com/drone/drone/logger"
+
+	"github.com/go-chi/chi"
+)
+
+var (
+	errInvalidRole  = errors.New("Invalid role. Must be one of read, write or admin")
+	errRoleRequired = errors.New("A role is required for accounts that are not machine accounts")
+)
+
+type permInput struct {
+	Role  string `json:"role"`
+	Read  bool   `json:"read"`
+	Write bool   `json:"write"`
+	Admin bool   `json:"admin"`
+}
+
+// HandleUpdate returns an http.HandlerFunc that processes
+// a request to update the role of a repository member. The
+// role of a machine account is managed by drone. The role of
+// other accounts is synchronized with the remote system, and
+// is replaced by the role override, if provided. An empty
+// role removes the role override.
+func HandleUpdate(
+	users core.UserStore,
+	repos core.RepositoryStore,
+	members core.PermStore,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var (
+			login     = chi.URLParam(r, "member")
+			namespace = chi.URLParam(r, "owner")
+			name      = chi.URLParam(r, "name")
+		)
+
+		in := new(permInput)
+		err := json.NewDecoder(r.Body).Decode(in)
+		if err != nil {
+			render.BadRequest(w, err)
+			logger.FromRequest(r).WithError(err).
+				Debugln("api: cannot unmarshal request body")
+			return
+		}
+		if in.Role != "" && !core.ValidRole(in.Role) {
+			render.BadRequest(w, errInvalidRole)
+			logger.FromRequest(r).
+				WithField("role", in.Role).
+				Debugln("api: invalid role")
+			return
+		}
+
+		repo, err := repos.FindName(r.Context(), namespace, name)
+		if err != nil {
+			render.NotFound(w, err)
+			logger.FromRequest(r).
+				WithError(err).
+				WithField("namespace", namespace).
+				WithField("name", name).
+				Debugln("api: repository not found")
+			return
+		}
+		user, err := users.FindLogin(r.Context(), login)
+		if err != nil {
+			render.NotFound(w, err)
+			logger.FromRequest(r).
+				WithError(err).
+				WithField("member", login).
+				WithField("namespace", namespace).
+				WithField("name", name).
+				Debugln("api: user not found")
+			return
+		}
+
+		now := time.Now().Unix()
+		member, err := members.Find(r.Context(), repo.UID, user.ID)
+		create := err != nil
+		if create && !user.Machine && in.Role == "" {
+			render.BadRequest(w, errRoleRequired)
+			logger.FromRequest(r).
+				WithField("member", login).
+				WithField("namespace", namespace).
+				WithField("name", name).
+				Debugln("api: cannot create membership without a role")
+			return
+		}
+		if create {
+			member = &core.Perm{
+				UserID:  user.ID,
+				RepoUID: repo.UID,
+				Created: now,
+			}
+		}
+
+		switch {
+		case user.Machine:
+			role := in.Role
+			if role == "" {
+				role = (&core.Perm{Read: in.Read, Write: in.Write, Admin: in.Admin}).Role()
+			}
+			member.Apply(role)
+			member.Synced = now
+		case in.Role != "":
+			member.Override = in.Role
+			member.Apply(in.Role)
+		default:
+			// the role override is removed, and the permissions
+			// are re-synchronized with the remote system on the
+			// next request.
+			member.Override = ""
+			member.Synced = 0
+		}
+		member.Updated = now
+
+		if create {
+			err = members.Create(r.Context(), member)
+		} else {
+			err = members.Update(r.Context(), member)
+		}
+		if err != nil {
+			render.InternalError(w, err)
+			logger.FromRequest(r).
+				WithError(err).
+				WithField("member", login).
+				WithField("namespace", namespace).
+				WithField("name", name).
+				Debugln("api: cannot update membership")
+		} else {
+			render.JSON(w, member, 200)
+		}
+	}
+}
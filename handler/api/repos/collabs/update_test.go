@@ -0,0 +1,267 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package collabs
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/errors"
+	"github.com/drone/drone/mock"
+
+	"github.com/go-chi/chi"
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+)
+
+var mockMachine = &core.User{
+	ID:      2,
+	Login:   "deploybot",
+	Machine: true,
+}
+
+func TestUpdate(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	users := mock.NewMockUserStore(controller)
+	repos := mock.NewMockRepositoryStore(controller)
+	members := mock.NewMockPermStore(controller)
+	repos.EXPECT().FindName(gomock.Any(), mockRepo.Namespace, mockRepo.Name).Return(mockRepo, nil)
+	users.EXPECT().FindLogin(gomock.Any(), "deploybot").Return(mockMachine, nil)
+	members.EXPECT().Find(gomock.Any(), mockRepo.UID, mockMachine.ID).Return(nil, errors.ErrNotFound)
+	members.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+
+	c := new(chi.Context)
+	c.URLParams.Add("owner", "octocat")
+	c.URLParams.Add("name", "hello-world")
+	c.URLParams.Add("member", "deploybot")
+
+	in := new(bytes.Buffer)
+	json.NewEncoder(in).Encode(&permInput{Write: true})
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/", in)
+	r = r.WithContext(
+		context.WithValue(context.Background(), chi.RouteCtxKey, c),
+	)
+
+	HandleUpdate(users, repos, members)(w, r)
+	if got, want := w.Code, http.StatusOK; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+
+	// write access implies read access.
+	got, want := &core.Perm{}, &core.Perm{Read: true, Write: true}
+	json.NewDecoder(w.Body).Decode(got)
+	if diff := cmp.Diff(got, want); len(diff) != 0 {
+		t.Errorf(diff)
+	}
+}
+
+func TestUpdate_Existing(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	member := &core.Perm{
+		UserID:  mockMachine.ID,
+		RepoUID: mockRepo.UID,
+		Read:    true,
+		Created: 1524251054,
+	}
+
+	users := mock.NewMockUserStore(controller)
+	repos := mock.NewMockRepositoryStore(controller)
+	members := mock.NewMockPermStore(controller)
+	repos.EXPECT().FindName(gomock.Any(), mockRepo.Namespace, mockRepo.Name).Return(mockRepo, nil)
+	users.EXPECT().FindLogin(gomock.Any(), "deploybot").Return(mockMachine, nil)
+	members.EXPECT().Find(gomock.Any(), mockRepo.UID, mockMachine.ID).Return(member, nil)
+	members.EXPECT().Update(gomock.Any(), member).Return(nil)
+
+	c := new(chi.Context)
+	c.URLParams.Add("owner", "octocat")
+	c.URLParams.Add("name", "hello-world")
+	c.URLParams.Add("member", "deploybot")
+
+	in := new(bytes.Buffer)
+	json.NewEncoder(in).Encode(&permInput{Admin: true})
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/", in)
+	r = r.WithContext(
+		context.WithValue(context.Background(), chi.RouteCtxKey, c),
+	)
+
+	HandleUpdate(users, repos, members)(w, r)
+	if got, want := w.Code, http.StatusOK; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+	if !member.Read || !member.Write || !member.Admin {
+		t.Errorf("Expect admin access implies read and write access")
+	}
+}
+
+func TestUpdate_Override(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	member := &core.Perm{
+		UserID:  mockUser.ID,
+		RepoUID: mockRepo.UID,
+		Read:    true,
+		Synced:  1524251054,
+	}
+
+	users := mock.NewMockUserStore(controller)
+	repos := mock.NewMockRepositoryStore(controller)
+	members := mock.NewMockPermStore(controller)
+	repos.EXPECT().FindName(gomock.Any(), mockRepo.Namespace, mockRepo.Name).Return(mockRepo, nil)
+	users.EXPECT().FindLogin(gomock.Any(), "octocat").Return(mockUser, nil)
+	members.EXPECT().Find(gomock.Any(), mockRepo.UID, mockUser.ID).Return(member, nil)
+	members.EXPECT().Update(gomock.Any(), member).Return(nil)
+
+	c := new(chi.Context)
+	c.URLParams.Add("owner", "octocat")
+	c.URLParams.Add("name", "hello-world")
+	c.URLParams.Add("member", "octocat")
+
+	in := new(bytes.Buffer)
+	json.NewEncoder(in).Encode(&permInput{Role: core.RoleWrite})
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/", in)
+	r = r.WithContext(
+		context.WithValue(context.Background(), chi.RouteCtxKey, c),
+	)
+
+	HandleUpdate(users, repos, members)(w, r)
+	if got, want := w.Code, http.StatusOK; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+
+	got, want := &core.Perm{}, &core.Perm{Read: true, Write: true, Override: core.RoleWrite}
+	json.NewDecoder(w.Body).Decode(got)
+	if diff := cmp.Diff(got, want); len(diff) != 0 {
+		t.Errorf(diff)
+	}
+}
+
+func TestUpdate_RemoveOverride(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	member := &core.Perm{
+		UserID:   mockUser.ID,
+		RepoUID:  mockRepo.UID,
+		Read:     true,
+		Override: core.RoleRead,
+		Synced:   1524251054,
+	}
+
+	users := mock.NewMockUserStore(controller)
+	repos := mock.NewMockRepositoryStore(controller)
+	members := mock.NewMockPermStore(controller)
+	repos.EXPECT().FindName(gomock.Any(), mockRepo.Namespace, mockRepo.Name).Return(mockRepo, nil)
+	users.EXPECT().FindLogin(gomock.Any(), "octocat").Return(mockUser, nil)
+	members.EXPECT().Find(gomock.Any(), mockRepo.UID, mockUser.ID).Return(member, nil)
+	members.EXPECT().Update(gomock.Any(), member).Return(nil)
+
+	c := new(chi.Context)
+	c.URLParams.Add("owner", "octocat")
+	c.URLParams.Add("name", "hello-world")
+	c.URLParams.Add("member", "octocat")
+
+	in := new(bytes.Buffer)
+	json.NewEncoder(in).Encode(&permInput{})
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/", in)
+	r = r.WithContext(
+		context.WithValue(context.Background(), chi.RouteCtxKey, c),
+	)
+
+	HandleUpdate(users, repos, members)(w, r)
+	if got, want := w.Code, http.StatusOK; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+	if member.Override != "" {
+		t.Errorf("Expect role override removed")
+	}
+	if member.Synced != 0 {
+		t.Errorf("Expect permissions marked for synchronization")
+	}
+}
+
+func TestUpdate_RoleRequired(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	users := mock.NewMockUserStore(controller)
+	repos := mock.NewMockRepositoryStore(controller)
+	members := mock.NewMockPermStore(controller)
+	repos.EXPECT().FindName(gomock.Any(), mockRepo.Namespace, mockRepo.Name).Return(mockRepo, nil)
+	users.EXPECT().FindLogin(gomock.Any(), "octocat").Return(mockUser, nil)
+	members.EXPECT().Find(gomock.Any(), mockRepo.UID, mockUser.ID).Return(nil, errors.ErrNotFound)
+
+	c := new(chi.Context)
+	c.URLParams.Add("owner", "octocat")
+	c.URLParams.Add("name", "hello-world")
+	c.URLParams.Add("member", "octocat")
+
+	in := new(bytes.Buffer)
+	json.NewEncoder(in).Encode(&permInput{Read: true})
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/", in)
+	r = r.WithContext(
+		context.WithValue(context.Background(), chi.RouteCtxKey, c),
+	)
+
+	HandleUpdate(users, repos, members)(w, r)
+	if got, want := w.Code, http.StatusBadRequest; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+
+	got, want := &errors.Error{}, errRoleRequired
+	json.NewDecoder(w.Body).Decode(got)
+	if diff := cmp.Diff(got, want); len(diff) != 0 {
+		t.Errorf(diff)
+	}
+}
+
+func TestUpdate_InvalidRole(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	c := new(chi.Context)
+	c.URLParams.Add("owner", "octocat")
+	c.URLParams.Add("name", "hello-world")
+	c.URLParams.Add("member", "octocat")
+
+	in := new(bytes.Buffer)
+	json.NewEncoder(in).Encode(&permInput{Role: "owner"})
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/", in)
+	r = r.WithContext(
+		context.WithValue(context.Background(), chi.RouteCtxKey, c),
+	)
+
+	HandleUpdate(nil, nil, nil)(w, r)
+	if got, want := w.Code, http.StatusBadRequest; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+
+	got, want := &errors.Error{}, errInvalidRole
+	json.NewDecoder(w.Body).Decode(got)
+	if diff := cmp.Diff(got, want); len(diff) != 0 {
+		t.Errorf(diff)
+	}
+}
@@ -0,0 +1,91 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package compile
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/render"
+	"github.com/drone/drone/operator/runner"
+
+	"github.com/go-chi/chi"
+)
+
+type payload struct {
+	Data   string            `json:"data"`
+	Event  string            `json:"event"`
+	Action string            `json:"action"`
+	Branch string            `json:"branch"`
+	Ref    string            `json:"ref"`
+	Commit string            `json:"commit"`
+	Target string            `json:"target"`
+	Params map[string]string `json:"params"`
+}
+
+// HandleCompile returns an http.HandlerFunc that processes
+// http requests to compile a pipeline configuration file for
+// a hypothetical build, without executing the build. The
+// event defaults to push and the branch defaults to the
+// repository default branch.
+func HandleCompile(repos core.RepositoryStore, system *core.System) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var (
+			namespace = chi.URLParam(r, "owner")
+			name      = chi.URLParam(r, "name")
+		)
+		repo, err := repos.FindName(r.Context(), namespace, name)
+		if err != nil {
+			render.NotFound(w, err)
+			return
+		}
+
+		in := new(payload)
+		err = json.NewDecoder(r.Body).Decode(in)
+		if err != nil {
+			render.BadRequest(w, err)
+			return
+		}
+
+		if in.Event == "" {
+			in.Event = core.EventPush
+		}
+		if in.Branch == "" {
+			in.Branch = repo.Branch
+		}
+		if in.Ref == "" && in.Event == core.EventTag {
+			render.BadRequestf(w, "A tag ref is required")
+			return
+		}
+		if in.Ref == "" {
+			in.Ref = "refs/heads/" + in.Branch
+		}
+		if in.Event == core.EventPush && strings.HasPrefix(in.Ref, "refs/heads/") {
+			in.Branch = strings.TrimPrefix(in.Ref, "refs/heads/")
+		}
+
+		build := &core.Build{
+			RepoID: repo.ID,
+			Number: repo.Counter + 1,
+			Status: core.StatusPending,
+			Event:  in.Event,
+			Action: in.Action,
+			After:  in.Commit,
+			Ref:    in.Ref,
+			Source: in.Branch,
+			Target: in.Branch,
+			Deploy: in.Target,
+			Params: in.Params,
+		}
+		stages, err := runner.DryRun(repo, build, system, in.Data)
+		if err != nil {
+			render.BadRequest(w, err)
+			return
+		}
+		render.JSON(w, stages, 200)
+	}
+}
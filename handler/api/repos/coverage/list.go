@@ -0,0 +1,100 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package coverage
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/render"
+	"github.com/drone/drone/logger"
+
+	"github.com/go-chi/chi"
+)
+
+// HandleList returns an http.HandlerFunc that writes a json-encoded
+// list of coverage reports for the repository branch, ordered from
+// newest to oldest, which can be used to chart coverage trends.
+func HandleList(
+	repos core.RepositoryStore,
+	coverage core.CoverageStore,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var (
+			namespace = chi.URLParam(r, "owner")
+			name      = chi.URLParam(r, "name")
+			ref       = r.FormValue("ref")
+			branch    = r.FormValue("branch")
+		)
+		limit, _ := strconv.Atoi(r.FormValue("limit"))
+		if limit < 1 || limit > 100 {
+			limit = 25
+		}
+		repo, err := repos.FindName(r.Context(), namespace, name)
+		if err != nil {
+			render.NotFound(w, err)
+			logger.FromRequest(r).
+				WithError(err).
+				WithField("namespace", namespace).
+				WithField("name", name).
+				Debugln("api: cannot find repository")
+			return
+		}
+		if branch != "" {
+			ref = "refs/heads/" + branch
+		}
+		if ref == "" {
+			ref = "refs/heads/" + repo.Branch
+		}
+		list, err := coverage.List(r.Context(), repo.ID, ref, limit)
+		if err != nil {
+			render.InternalError(w, err)
+			logger.FromRequest(r).
+				WithError(err).
+				WithField("namespace", namespace).
+				WithField("name", name).
+				Debugln("api: cannot list coverage")
+			return
+		}
+		render.JSON(w, list, 200)
+	}
+}
+
+// HandleBuild returns an http.HandlerFunc that writes a
+// json-encoded list of coverage reports for the build.
+func HandleBuild(
+	repos core.RepositoryStore,
+	builds core.BuildStore,
+	coverage core.CoverageStore,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var (
+			namespace = chi.URLParam(r, "owner")
+			name      = chi.URLParam(r, "name")
+		)
+		number, err := strconv.ParseInt(chi.URLParam(r, "number"), 10, 64)
+		if err != nil {
+			render.BadRequest(w, err)
+			return
+		}
+		repo, err := repos.FindName(r.Context(), namespace, name)
+		if err != nil {
+			render.NotFound(w, err)
+			return
+		}
+		build, err := builds.FindNumber(r.Context(), repo.ID, number)
+		if err != nil {
+			render.NotFound(w, err)
+			return
+		}
+		list, err := coverage.ListBuild(r.Context(), build.ID)
+		if err != nil {
+			render.InternalError(w, err)
+			return
+		}
+		render.JSON(w, list, 200)
+	}
+}
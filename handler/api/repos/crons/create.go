@@ -39,6 +39,7 @@ func HandleCreate(
 		cronjob := new(core.Cron)
 		cronjob.Event = core.EventPush
 		cronjob.Branch = in.Branch
+		cronjob.Rebuild = in.Rebuild
 		cronjob.RepoID = repo.ID
 		cronjob.SetName(in.Name)
 		err = cronjob.SetExpr(in.Expr)
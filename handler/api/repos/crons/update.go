@@ -18,6 +18,7 @@ type cronUpdate struct {
 	Branch   *string `json:"branch"`
 	Target   *string `json:"target"`
 	Disabled *bool   `json:"disabled"`
+	Rebuild  *bool   `json:"rebuild"`
 }
 
 // HandleUpdate returns an http.HandlerFunc that processes http
@@ -54,6 +55,9 @@ func HandleUpdate(
 		if in.Disabled != nil {
 			cronjob.Disabled = *in.Disabled
 		}
+		if in.Rebuild != nil {
+			cronjob.Rebuild = *in.Rebuild
+		}
 
 		err = crons.Update(r.Context(), cronjob)
 		if err != nil {
@@ -0,0 +1,95 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package deployments
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/render"
+	"github.com/drone/drone/logger"
+
+	"github.com/go-chi/chi"
+)
+
+// HandleLatest returns an http.HandlerFunc that writes a
+// json-encoded list of the most recent successful deployment
+// to each environment, which describes the commit currently
+// deployed to each environment.
+func HandleLatest(
+	repos core.RepositoryStore,
+	deployments core.DeploymentStore,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var (
+			namespace = chi.URLParam(r, "owner")
+			name      = chi.URLParam(r, "name")
+		)
+		repo, err := repos.FindName(r.Context(), namespace, name)
+		if err != nil {
+			render.NotFound(w, err)
+			logger.FromRequest(r).
+				WithError(err).
+				WithField("namespace", namespace).
+				WithField("name", name).
+				Debugln("api: cannot find repository")
+			return
+		}
+		list, err := deployments.ListLatest(r.Context(), repo.ID)
+		if err != nil {
+			render.InternalError(w, err)
+			logger.FromRequest(r).
+				WithError(err).
+				WithField("namespace", namespace).
+				WithField("name", name).
+				Debugln("api: cannot list deployments")
+			return
+		}
+		render.JSON(w, list, 200)
+	}
+}
+
+// HandleList returns an http.HandlerFunc that writes a
+// json-encoded list of deployments to the environment,
+// ordered from newest to oldest.
+func HandleList(
+	repos core.RepositoryStore,
+	deployments core.DeploymentStore,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var (
+			namespace   = chi.URLParam(r, "owner")
+			name        = chi.URLParam(r, "name")
+			environment = chi.URLParam(r, "environment")
+		)
+		limit, _ := strconv.Atoi(r.FormValue("limit"))
+		if limit < 1 || limit > 100 {
+			limit = 25
+		}
+		repo, err := repos.FindName(r.Context(), namespace, name)
+		if err != nil {
+			render.NotFound(w, err)
+			logger.FromRequest(r).
+				WithError(err).
+				WithField("namespace", namespace).
+				WithField("name", name).
+				Debugln("api: cannot find repository")
+			return
+		}
+		list, err := deployments.List(r.Context(), repo.ID, environment, limit)
+		if err != nil {
+			render.InternalError(w, err)
+			logger.FromRequest(r).
+				WithError(err).
+				WithField("namespace", namespace).
+				WithField("name", name).
+				WithField("environment", environment).
+				Debugln("api: cannot list deployments")
+			return
+		}
+		render.JSON(w, list, 200)
+	}
+}
@@ -51,19 +51,7 @@ func HandleEnable(
 		}
 		repo.Active = true
 		repo.UserID = user.ID
-
-		if repo.Config == "" {
-			repo.Config = ".core.yml"
-		}
-		if repo.Signer == "" {
-			repo.Signer = uniuri.NewLen(32)
-		}
-		if repo.Secret == "" {
-			repo.Secret = uniuri.NewLen(32)
-		}
-		if repo.Timeout == 0 {
-			repo.Timeout = 60
-		}
+		setDefaults(repo)
 
 		err = hooks.Create(r.Context(), user, repo)
 		if err != nil {
@@ -113,3 +101,20 @@ func HandleEnable(
 		render.JSON(w, repo, 200)
 	}
 }
+
+// helper function sets the default configuration for a
+// repository that is being enabled.
+func setDefaults(repo *core.Repository) {
+	if repo.Config == "" {
+		repo.Config = ".core.yml"
+	}
+	if repo.Signer == "" {
+		repo.Signer = uniuri.NewLen(32)
+	}
+	if repo.Secret == "" {
+		repo.Secret = uniuri.NewLen(32)
+	}
+	if repo.Timeout == 0 {
+		repo.Timeout = 60
+	}
+}
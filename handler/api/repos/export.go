@@ -0,0 +1,166 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package repos
+
+import (
+	"net/http"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/render"
+	"github.com/drone/drone/logger"
+
+	"github.com/go-chi/chi"
+)
+
+// settingsVersion is the version of the settings document.
+const settingsVersion = 1
+
+type (
+	// settings is a portable document of the repository
+	// settings, used to migrate the settings to another
+	// repository or server.
+	settings struct {
+		Version int               `json:"version"`
+		Slug    string            `json:"slug"`
+		Repo    *settingsRepo     `json:"repo"`
+		Secrets []*settingsSecret `json:"secrets"`
+		Cron    []*settingsCron   `json:"cron"`
+	}
+
+	settingsRepo struct {
+		Visibility   string `json:"visibility"`
+		Config       string `json:"config_path"`
+		Trusted      bool   `json:"trusted"`
+		Protected    bool   `json:"protected"`
+		IgnoreForks  bool   `json:"ignore_forks"`
+		IgnorePulls  bool   `json:"ignore_pull_requests"`
+		MergePulls   bool   `json:"merge_pull_requests"`
+		IgnoreDupes  bool   `json:"ignore_duplicates"`
+		NoMaskLogs   bool   `json:"no_mask_logs"`
+		SkipPattern  string `json:"skip_pattern"`
+		Summary      bool   `json:"summary"`
+		SummaryLines int64  `json:"summary_lines"`
+		Timeout      int64  `json:"timeout"`
+		Throttle     int64  `json:"throttle"`
+		PollInterval int64  `json:"poll_interval"`
+		PollBranches string `json:"poll_branches"`
+	}
+
+	// settingsSecret provides the secret metadata. The secret
+	// value is never exported.
+	settingsSecret struct {
+		Name            string `json:"name"`
+		PullRequest     bool   `json:"pull_request"`
+		PullRequestPush bool   `json:"pull_request_push"`
+	}
+
+	settingsCron struct {
+		Name     string `json:"name"`
+		Expr     string `json:"expr"`
+		Branch   string `json:"branch"`
+		Target   string `json:"target,omitempty"`
+		Disabled bool   `json:"disabled"`
+		Rebuild  bool   `json:"rebuild"`
+	}
+)
+
+// HandleExport returns an http.HandlerFunc that writes the
+// json-encoded repository settings, secret metadata and cron
+// jobs to the response body.
+func HandleExport(
+	repos core.RepositoryStore,
+	secrets core.SecretStore,
+	crons core.CronStore,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var (
+			owner = chi.URLParam(r, "owner")
+			name  = chi.URLParam(r, "name")
+		)
+		repo, err := repos.FindName(r.Context(), owner, name)
+		if err != nil {
+			render.NotFound(w, err)
+			logger.FromRequest(r).
+				WithError(err).
+				WithField("namespace", owner).
+				WithField("name", name).
+				Debugln("api: repository not found")
+			return
+		}
+		secretList, err := secrets.List(r.Context(), repo.ID)
+		if err != nil {
+			render.InternalError(w, err)
+			logger.FromRequest(r).
+				WithError(err).
+				WithField("namespace", owner).
+				WithField("name", name).
+				Debugln("api: cannot list secrets")
+			return
+		}
+		cronList, err := crons.List(r.Context(), repo.ID)
+		if err != nil {
+			render.InternalError(w, err)
+			logger.FromRequest(r).
+				WithError(err).
+				WithField("namespace", owner).
+				WithField("name", name).
+				Debugln("api: cannot list cron jobs")
+			return
+		}
+
+		out := &settings{
+			Version: settingsVersion,
+			Slug:    repo.Slug,
+			Repo: &settingsRepo{
+				Visibility:   repo.Visibility,
+				Config:       repo.Config,
+				Trusted:      repo.Trusted,
+				Protected:    repo.Protected,
+				IgnoreForks:  repo.IgnoreForks,
+				IgnorePulls:  repo.IgnorePulls,
+				MergePulls:   repo.MergePulls,
+				IgnoreDupes:  repo.IgnoreDupes,
+				NoMaskLogs:   repo.NoMaskLogs,
+				SkipPattern:  repo.SkipPattern,
+				Summary:      repo.Summary,
+				SummaryLines: repo.SummaryLines,
+				Timeout:      repo.Timeout,
+				Throttle:     repo.Throttle,
+				PollInterval: repo.PollInterval,
+				PollBranches: repo.PollBranches,
+			},
+			Secrets: []*settingsSecret{},
+			Cron:    []*settingsCron{},
+		}
+		for _, secret := range secretList {
+			out.Secrets = append(out.Secrets, &settingsSecret{
+				Name:            secret.Name,
+				PullRequest:     secret.PullRequest,
+				PullRequestPush: secret.PullRequestPush,
+			})
+		}
+		for _, cron := range cronList {
+			out.Cron = append(out.Cron, &settingsCron{
+				Name:     cron.Name,
+				Expr:     cron.Expr,
+				Branch:   cron.Branch,
+				Target:   cron.Target,
+				Disabled: cron.Disabled,
+				Rebuild:  cron.Rebuild,
+			})
+		}
+		render.JSON(w, out, 200)
+	}
+}
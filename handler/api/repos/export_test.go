@@ -0,0 +1,84 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package repos
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/mock"
+
+	"github.com/go-chi/chi"
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestExport(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	repo := &core.Repository{
+		ID:         1,
+		Namespace:  "octocat",
+		Name:       "hello-world",
+		Slug:       "octocat/hello-world",
+		Visibility: core.VisibilityPrivate,
+		Config:     ".drone.yml",
+		Trusted:    true,
+		Timeout:    90,
+	}
+
+	repos := mock.NewMockRepositoryStore(controller)
+	repos.EXPECT().FindName(gomock.Any(), repo.Namespace, repo.Name).Return(repo, nil)
+
+	secrets := mock.NewMockSecretStore(controller)
+	secrets.EXPECT().List(gomock.Any(), repo.ID).Return([]*core.Secret{
+		{Name: "docker_password", Data: "correct-horse-battery-staple", PullRequest: true},
+	}, nil)
+
+	crons := mock.NewMockCronStore(controller)
+	crons.EXPECT().List(gomock.Any(), repo.ID).Return([]*core.Cron{
+		{Name: "nightly", Expr: "0 0 0 * * *", Branch: "master", Next: 1556000000},
+	}, nil)
+
+	c := new(chi.Context)
+	c.URLParams.Add("owner", "octocat")
+	c.URLParams.Add("name", "hello-world")
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/", nil)
+	r = r.WithContext(
+		context.WithValue(r.Context(), chi.RouteCtxKey, c),
+	)
+
+	HandleExport(repos, secrets, crons)(w, r)
+	if got, want := w.Code, 200; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+
+	got, want := new(settings), &settings{
+		Version: settingsVersion,
+		Slug:    "octocat/hello-world",
+		Repo: &settingsRepo{
+			Visibility: core.VisibilityPrivate,
+			Config:     ".drone.yml",
+			Trusted:    true,
+			Timeout:    90,
+		},
+		Secrets: []*settingsSecret{
+			{Name: "docker_password", PullRequest: true},
+		},
+		Cron: []*settingsCron{
+			{Name: "nightly", Expr: "0 0 0 * * *", Branch: "master"},
+		},
+	}
+	json.NewDecoder(w.Body).Decode(got)
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf(diff)
+	}
+}
@@ -0,0 +1,84 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package hooks
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/mock"
+
+	"github.com/go-chi/chi"
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestHandleFind(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	repos := mock.NewMockRepositoryStore(controller)
+	repos.EXPECT().FindName(gomock.Any(), dummyRepo.Namespace, dummyRepo.Name).Return(dummyRepo, nil)
+
+	payloads := mock.NewMockHookPayloadStore(controller)
+	payloads.EXPECT().Find(gomock.Any(), dummyPayload.ID).Return(dummyPayload, nil)
+
+	c := new(chi.Context)
+	c.URLParams.Add("owner", "octocat")
+	c.URLParams.Add("name", "hello-world")
+	c.URLParams.Add("hook", "2")
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/", nil)
+	r = r.WithContext(
+		context.WithValue(context.Background(), chi.RouteCtxKey, c),
+	)
+
+	HandleFind(repos, payloads).ServeHTTP(w, r)
+	if got, want := w.Code, http.StatusOK; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+
+	got, want := &core.HookPayload{}, dummyPayload
+	json.NewDecoder(w.Body).Decode(got)
+	if diff := cmp.Diff(got, want); len(diff) != 0 {
+		t.Errorf(diff)
+	}
+}
+
+// this test verifies that a payload cannot be accessed
+// using the url of a different repository.
+func TestHandleFind_OtherRepo(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	other := &core.Repository{ID: 3, Namespace: "spaceghost", Name: "hello-world"}
+
+	repos := mock.NewMockRepositoryStore(controller)
+	repos.EXPECT().FindName(gomock.Any(), other.Namespace, other.Name).Return(other, nil)
+
+	payloads := mock.NewMockHookPayloadStore(controller)
+	payloads.EXPECT().Find(gomock.Any(), dummyPayload.ID).Return(dummyPayload, nil)
+
+	c := new(chi.Context)
+	c.URLParams.Add("owner", "spaceghost")
+	c.URLParams.Add("name", "hello-world")
+	c.URLParams.Add("hook", "2")
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/", nil)
+	r = r.WithContext(
+		context.WithValue(context.Background(), chi.RouteCtxKey, c),
+	)
+
+	HandleFind(repos, payloads).ServeHTTP(w, r)
+	if got, want := w.Code, http.StatusNotFound; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+}
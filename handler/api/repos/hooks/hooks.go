@@ -0,0 +1,31 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package hooks
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+
+	"github.com/drone/drone/core"
+)
+
+// helper function returns the hook payload with the given
+// identifier. An error is returned if the payload does not
+// belong to the repository.
+func findPayload(ctx context.Context, payloads core.HookPayloadStore, repo *core.Repository, param string) (*core.HookPayload, error) {
+	id, err := strconv.ParseInt(param, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := payloads.Find(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if payload.RepoID != repo.ID {
+		return nil, sql.ErrNoRows
+	}
+	return payload, nil
+}
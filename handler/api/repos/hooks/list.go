@@ -0,0 +1,44 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package hooks
+
+import (
+	"net/http"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/render"
+
+	"github.com/go-chi/chi"
+)
+
+// HandleList returns an http.HandlerFunc that writes a json-encoded
+// list of hook payloads received for the repository to the response
+// body. The raw payload is excluded from the list.
+func HandleList(
+	repos core.RepositoryStore,
+	payloads core.HookPayloadStore,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var (
+			namespace = chi.URLParam(r, "owner")
+			name      = chi.URLParam(r, "name")
+		)
+		repo, err := repos.FindName(r.Context(), namespace, name)
+		if err != nil {
+			render.NotFound(w, err)
+			return
+		}
+		list, err := payloads.List(r.Context(), repo.ID)
+		if err != nil {
+			render.InternalError(w, err)
+			return
+		}
+		for _, payload := range list {
+			payload.Header = nil
+			payload.Body = ""
+		}
+		render.JSON(w, list, 200)
+	}
+}
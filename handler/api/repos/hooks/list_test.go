@@ -0,0 +1,84 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package hooks
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/mock"
+
+	"github.com/go-chi/chi"
+	"github.com/golang/mock/gomock"
+)
+
+var (
+	dummyRepo = &core.Repository{
+		ID:        1,
+		Namespace: "octocat",
+		Name:      "hello-world",
+		Slug:      "octocat/hello-world",
+		Signer:    "correct-horse-battery-staple",
+		Active:    true,
+	}
+
+	dummyPayload = &core.HookPayload{
+		ID:      2,
+		RepoID:  1,
+		Status:  core.PayloadError,
+		Message: "invalid signature",
+		Header:  http.Header{"X-Github-Event": {"push"}},
+		Body:    `{"ref":"refs/heads/master"}`,
+		Created: 1299283200,
+		Updated: 1299283200,
+	}
+)
+
+func TestHandleList(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	payload := new(core.HookPayload)
+	*payload = *dummyPayload
+
+	repos := mock.NewMockRepositoryStore(controller)
+	repos.EXPECT().FindName(gomock.Any(), dummyRepo.Namespace, dummyRepo.Name).Return(dummyRepo, nil)
+
+	payloads := mock.NewMockHookPayloadStore(controller)
+	payloads.EXPECT().List(gomock.Any(), dummyRepo.ID).Return([]*core.HookPayload{payload}, nil)
+
+	c := new(chi.Context)
+	c.URLParams.Add("owner", "octocat")
+	c.URLParams.Add("name", "hello-world")
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/", nil)
+	r = r.WithContext(
+		context.WithValue(context.Background(), chi.RouteCtxKey, c),
+	)
+
+	HandleList(repos, payloads).ServeHTTP(w, r)
+	if got, want := w.Code, http.StatusOK; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+
+	got := []*core.HookPayload{}
+	json.NewDecoder(w.Body).Decode(&got)
+	if len(got) != 1 {
+		t.Errorf("Want 1 payload, got %d", len(got))
+		return
+	}
+	if got, want := got[0].Status, dummyPayload.Status; got != want {
+		t.Errorf("Want payload status %s, got %s", want, got)
+	}
+	// the raw payload is excluded from the list.
+	if got[0].Body != "" || got[0].Header != nil {
+		t.Errorf("Want raw payload excluded from the list")
+	}
+}
@@ -0,0 +1,144 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package hooks
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/render"
+	"github.com/drone/drone/logger"
+	"github.com/drone/go-scm/scm"
+
+	"github.com/go-chi/chi"
+)
+
+// HandleReplay returns an http.HandlerFunc that processes http
+// requests to re-process a stored hook payload. The payload is
+// verified with the current repository signer, or the previous
+// signer during the rotation grace period, and the updated
+// processing result is written to the response body.
+func HandleReplay(
+	repos core.RepositoryStore,
+	payloads core.HookPayloadStore,
+	parser core.HookParser,
+	triggerer core.Triggerer,
+	commands core.CommandService,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var (
+			namespace = chi.URLParam(r, "owner")
+			name      = chi.URLParam(r, "name")
+		)
+		repo, err := repos.FindName(r.Context(), namespace, name)
+		if err != nil {
+			render.NotFound(w, err)
+			return
+		}
+		payload, err := findPayload(r.Context(), payloads, repo, chi.URLParam(r, "hook"))
+		if err != nil {
+			render.NotFound(w, err)
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute*5)
+		ctx = logger.WithContext(ctx, logger.FromRequest(r))
+		defer cancel()
+
+		payload.Build = 0
+		payload.Status, payload.Message = replay(ctx, repo, payload, parser, triggerer, commands)
+		payload.Updated = time.Now().Unix()
+		err = payloads.Update(r.Context(), payload)
+		if err != nil {
+			render.InternalError(w, err)
+			logger.FromRequest(r).
+				WithError(err).
+				WithField("namespace", namespace).
+				WithField("name", name).
+				Debugln("api: cannot update hook payload")
+			return
+		}
+		render.JSON(w, payload, 200)
+	}
+}
+
+// helper function parses and processes the hook request, and
+// returns the resulting payload status and message.
+func replay(
+	ctx context.Context,
+	repo *core.Repository,
+	payload *core.HookPayload,
+	parser core.HookParser,
+	triggerer core.Triggerer,
+	commands core.CommandService,
+) (string, string) {
+	hook, remote, err := parse(repo.Signer, payload, repo, parser)
+	if err == scm.ErrSignatureInvalid && repo.SignerPrev != "" && repo.SignerExpiry > time.Now().Unix() {
+		hook, remote, err = parse(repo.SignerPrev, payload, repo, parser)
+	}
+	if err != nil {
+		return core.PayloadError, err.Error()
+	}
+	if hook == nil {
+		return core.PayloadIgnored, "webhook ignored"
+	}
+
+	payload.Event = hook.Event
+	payload.Action = hook.Action
+	payload.Ref = hook.Ref
+	payload.After = hook.After
+
+	if !strings.EqualFold(remote.Slug, repo.Slug) {
+		return core.PayloadError, "webhook does not match the repository"
+	}
+	if !repo.Active {
+		return core.PayloadIgnored, "repository inactive"
+	}
+
+	var build *core.Build
+	if hook.Action == core.ActionComment {
+		build, err = commands.Exec(ctx, repo, hook)
+	} else {
+		build, err = triggerer.Trigger(ctx, repo, hook)
+	}
+	switch {
+	case err != nil:
+		return core.PayloadError, err.Error()
+	case build == nil:
+		return core.PayloadIgnored, "no build created"
+	default:
+		payload.Build = build.Number
+		return core.PayloadSuccess, ""
+	}
+}
+
+// helper function re-creates the hook request from the stored
+// payload and parses the request, verifying the signature with
+// the provided secret.
+func parse(
+	secret string,
+	payload *core.HookPayload,
+	repo *core.Repository,
+	parser core.HookParser,
+) (*core.Hook, *core.Repository, error) {
+	target := "/hook"
+	if repo.Provider != "" {
+		target += "?provider=" + url.QueryEscape(repo.Provider)
+	}
+	req, err := http.NewRequest("POST", target, strings.NewReader(payload.Body))
+	if err != nil {
+		return nil, nil, err
+	}
+	for key, value := range payload.Header {
+		req.Header[key] = value
+	}
+	return parser.Parse(req, func(string) string {
+		return secret
+	})
+}
@@ -0,0 +1,250 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package hooks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/mock"
+	"github.com/drone/go-scm/scm"
+
+	"github.com/go-chi/chi"
+	"github.com/golang/mock/gomock"
+)
+
+func TestHandleReplay(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	payload := new(core.HookPayload)
+	*payload = *dummyPayload
+
+	hook := &core.Hook{
+		Event: core.EventPush,
+		Ref:   "refs/heads/master",
+		After: "7fd1a60b01f91b314f59955a4e4d4e80d8edf11d",
+	}
+
+	checkRequest := func(req *http.Request, fn func(string) string) {
+		if got, want := req.Header.Get("X-Github-Event"), "push"; got != want {
+			t.Errorf("Want header %q, got %q", want, got)
+		}
+		if body, _ := ioutil.ReadAll(req.Body); string(body) != dummyPayload.Body {
+			t.Errorf("Want stored payload body, got %q", body)
+		}
+		if got, want := fn(dummyRepo.Slug), dummyRepo.Signer; got != want {
+			t.Errorf("Want repository signer %q, got %q", want, got)
+		}
+	}
+
+	repos := mock.NewMockRepositoryStore(controller)
+	repos.EXPECT().FindName(gomock.Any(), dummyRepo.Namespace, dummyRepo.Name).Return(dummyRepo, nil)
+
+	payloads := mock.NewMockHookPayloadStore(controller)
+	payloads.EXPECT().Find(gomock.Any(), dummyPayload.ID).Return(payload, nil)
+	payloads.EXPECT().Update(gomock.Any(), payload).Return(nil)
+
+	parser := mock.NewMockHookParser(controller)
+	parser.EXPECT().Parse(gomock.Any(), gomock.Any()).Do(checkRequest).Return(hook, dummyRepo, nil)
+
+	triggerer := mock.NewMockTriggerer(controller)
+	triggerer.EXPECT().Trigger(gomock.Any(), dummyRepo, hook).Return(&core.Build{Number: 42}, nil)
+
+	c := new(chi.Context)
+	c.URLParams.Add("owner", "octocat")
+	c.URLParams.Add("name", "hello-world")
+	c.URLParams.Add("hook", "2")
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/", nil)
+	r = r.WithContext(
+		context.WithValue(context.Background(), chi.RouteCtxKey, c),
+	)
+
+	HandleReplay(repos, payloads, parser, triggerer, nil).ServeHTTP(w, r)
+	if got, want := w.Code, http.StatusOK; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+
+	got := new(core.HookPayload)
+	json.NewDecoder(w.Body).Decode(got)
+	if got, want := got.Status, core.PayloadSuccess; got != want {
+		t.Errorf("Want payload status %s, got %s", want, got)
+	}
+	if got, want := got.Build, int64(42); got != want {
+		t.Errorf("Want payload build %d, got %d", want, got)
+	}
+	if got, want := got.Event, core.EventPush; got != want {
+		t.Errorf("Want payload event %s, got %s", want, got)
+	}
+	if got.Message != "" {
+		t.Errorf("Want payload message cleared, got %q", got.Message)
+	}
+}
+
+// this test verifies that the processing error is stored
+// with the payload when the payload cannot be parsed.
+func TestHandleReplay_ParseError(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	payload := new(core.HookPayload)
+	*payload = *dummyPayload
+
+	repos := mock.NewMockRepositoryStore(controller)
+	repos.EXPECT().FindName(gomock.Any(), dummyRepo.Namespace, dummyRepo.Name).Return(dummyRepo, nil)
+
+	payloads := mock.NewMockHookPayloadStore(controller)
+	payloads.EXPECT().Find(gomock.Any(), dummyPayload.ID).Return(payload, nil)
+	payloads.EXPECT().Update(gomock.Any(), payload).Return(nil)
+
+	parser := mock.NewMockHookParser(controller)
+	parser.EXPECT().Parse(gomock.Any(), gomock.Any()).Return(nil, nil, errors.New("invalid signature"))
+
+	c := new(chi.Context)
+	c.URLParams.Add("owner", "octocat")
+	c.URLParams.Add("name", "hello-world")
+	c.URLParams.Add("hook", "2")
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/", nil)
+	r = r.WithContext(
+		context.WithValue(context.Background(), chi.RouteCtxKey, c),
+	)
+
+	HandleReplay(repos, payloads, parser, nil, nil).ServeHTTP(w, r)
+	if got, want := w.Code, http.StatusOK; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+
+	got := new(core.HookPayload)
+	json.NewDecoder(w.Body).Decode(got)
+	if got, want := got.Status, core.PayloadError; got != want {
+		t.Errorf("Want payload status %s, got %s", want, got)
+	}
+	if got, want := got.Message, "invalid signature"; got != want {
+		t.Errorf("Want payload message %q, got %q", want, got)
+	}
+}
+
+// this test verifies that a payload cannot be re-processed
+// for a different repository.
+func TestHandleReplay_OtherRepo(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	payload := new(core.HookPayload)
+	*payload = *dummyPayload
+
+	other := &core.Repository{Namespace: "spaceghost", Name: "hello-world", Slug: "spaceghost/hello-world"}
+
+	repos := mock.NewMockRepositoryStore(controller)
+	repos.EXPECT().FindName(gomock.Any(), dummyRepo.Namespace, dummyRepo.Name).Return(dummyRepo, nil)
+
+	payloads := mock.NewMockHookPayloadStore(controller)
+	payloads.EXPECT().Find(gomock.Any(), dummyPayload.ID).Return(payload, nil)
+	payloads.EXPECT().Update(gomock.Any(), payload).Return(nil)
+
+	parser := mock.NewMockHookParser(controller)
+	parser.EXPECT().Parse(gomock.Any(), gomock.Any()).Return(&core.Hook{Event: core.EventPush}, other, nil)
+
+	c := new(chi.Context)
+	c.URLParams.Add("owner", "octocat")
+	c.URLParams.Add("name", "hello-world")
+	c.URLParams.Add("hook", "2")
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/", nil)
+	r = r.WithContext(
+		context.WithValue(context.Background(), chi.RouteCtxKey, c),
+	)
+
+	HandleReplay(repos, payloads, parser, nil, nil).ServeHTTP(w, r)
+	if got, want := w.Code, http.StatusOK; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+
+	got := new(core.HookPayload)
+	json.NewDecoder(w.Body).Decode(got)
+	if got, want := got.Status, core.PayloadError; got != want {
+		t.Errorf("Want payload status %s, got %s", want, got)
+	}
+}
+
+// this test verifies that the payload is verified with the
+// previous repository signer during the rotation grace
+// period, and is routed to the repository provider.
+func TestHandleReplay_SignerGrace(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	payload := new(core.HookPayload)
+	*payload = *dummyPayload
+
+	repo := new(core.Repository)
+	*repo = *dummyRepo
+	repo.Provider = "internal"
+	repo.SignerPrev = "horse-battery-staple"
+	repo.SignerExpiry = time.Now().Add(time.Hour).Unix()
+
+	hook := &core.Hook{Event: core.EventPush}
+
+	repos := mock.NewMockRepositoryStore(controller)
+	repos.EXPECT().FindName(gomock.Any(), dummyRepo.Namespace, dummyRepo.Name).Return(repo, nil)
+
+	payloads := mock.NewMockHookPayloadStore(controller)
+	payloads.EXPECT().Find(gomock.Any(), dummyPayload.ID).Return(payload, nil)
+	payloads.EXPECT().Update(gomock.Any(), payload).Return(nil)
+
+	parser := mock.NewMockHookParser(controller)
+	gomock.InOrder(
+		parser.EXPECT().Parse(gomock.Any(), gomock.Any()).Return(nil, nil, scm.ErrSignatureInvalid),
+		parser.EXPECT().Parse(gomock.Any(), gomock.Any()).DoAndReturn(func(req *http.Request, fn func(string) string) (*core.Hook, *core.Repository, error) {
+			if got, want := req.URL.Query().Get("provider"), "internal"; got != want {
+				t.Errorf("Want provider %q, got %q", want, got)
+			}
+			if body, _ := ioutil.ReadAll(req.Body); string(body) != dummyPayload.Body {
+				t.Errorf("Want stored payload body, got %q", body)
+			}
+			if got, want := fn(repo.Slug), repo.SignerPrev; got != want {
+				t.Errorf("Want previous signer %q, got %q", want, got)
+			}
+			return hook, repo, nil
+		}),
+	)
+
+	triggerer := mock.NewMockTriggerer(controller)
+	triggerer.EXPECT().Trigger(gomock.Any(), repo, hook).Return(&core.Build{Number: 42}, nil)
+
+	c := new(chi.Context)
+	c.URLParams.Add("owner", "octocat")
+	c.URLParams.Add("name", "hello-world")
+	c.URLParams.Add("hook", "2")
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/", nil)
+	r = r.WithContext(
+		context.WithValue(context.Background(), chi.RouteCtxKey, c),
+	)
+
+	HandleReplay(repos, payloads, parser, triggerer, nil).ServeHTTP(w, r)
+	if got, want := w.Code, http.StatusOK; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+
+	got := new(core.HookPayload)
+	json.NewDecoder(w.Body).Decode(got)
+	if got, want := got.Status, core.PayloadSuccess; got != want {
+		t.Errorf("Want payload status %s, got %s", want, got)
+	}
+}
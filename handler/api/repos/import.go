@@ -0,0 +1,189 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package repos
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/render"
+	"github.com/drone/drone/handler/api/request"
+	"github.com/drone/drone/logger"
+
+	"github.com/go-chi/chi"
+)
+
+// importResult is the result of importing repository settings.
+// Secret values are not exported, so secrets that do not exist
+// in the target repository are reported and must be created.
+type importResult struct {
+	Repo           *core.Repository `json:"repo"`
+	MissingSecrets []string         `json:"missing_secrets"`
+}
+
+// HandleImport returns an http.HandlerFunc that processes http
+// requests to import repository settings and cron jobs that were
+// exported from another repository or server.
+func HandleImport(
+	repos core.RepositoryStore,
+	secrets core.SecretStore,
+	crons core.CronStore,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var (
+			owner = chi.URLParam(r, "owner")
+			name  = chi.URLParam(r, "name")
+		)
+		user, _ := request.UserFrom(r.Context())
+
+		repo, err := repos.FindName(r.Context(), owner, name)
+		if err != nil {
+			render.NotFound(w, err)
+			logger.FromRequest(r).
+				WithError(err).
+				WithField("namespace", owner).
+				WithField("name", name).
+				Debugln("api: repository not found")
+			return
+		}
+
+		in := new(settings)
+		err = json.NewDecoder(r.Body).Decode(in)
+		if err != nil {
+			render.BadRequest(w, err)
+			logger.FromRequest(r).
+				WithError(err).
+				WithField("namespace", owner).
+				WithField("name", name).
+				Debugln("api: cannot unmarshal json input")
+			return
+		}
+		if in.Version != settingsVersion {
+			render.BadRequestf(w, "Unsupported settings version %d", in.Version)
+			return
+		}
+
+		// the cron jobs are validated before any changes
+		// are persisted to the datastore.
+		var cronList []*core.Cron
+		for _, v := range in.Cron {
+			cronjob, err := crons.FindName(r.Context(), repo.ID, v.Name)
+			if err != nil {
+				cronjob = &core.Cron{RepoID: repo.ID, Event: core.EventPush}
+				cronjob.SetName(v.Name)
+			}
+			cronjob.Branch = v.Branch
+			cronjob.Target = v.Target
+			cronjob.Disabled = v.Disabled
+			cronjob.Rebuild = v.Rebuild
+			if err := cronjob.SetExpr(v.Expr); err != nil {
+				render.BadRequest(w, err)
+				return
+			}
+			if err := cronjob.Validate(); err != nil {
+				render.BadRequest(w, err)
+				return
+			}
+			cronList = append(cronList, cronjob)
+		}
+
+		if v := in.Repo; v != nil {
+			if v.Visibility != "" {
+				repo.Visibility = v.Visibility
+			}
+			if v.Config != "" {
+				repo.Config = v.Config
+			}
+			repo.Protected = v.Protected
+			repo.IgnoreForks = v.IgnoreForks
+			repo.IgnorePulls = v.IgnorePulls
+			repo.MergePulls = v.MergePulls
+			repo.IgnoreDupes = v.IgnoreDupes
+			repo.NoMaskLogs = v.NoMaskLogs
+			repo.SkipPattern = v.SkipPattern
+			repo.Summary = v.Summary
+			if v.SummaryLines >= 0 {
+				repo.SummaryLines = v.SummaryLines
+			}
+			if v.Throttle >= 0 {
+				repo.Throttle = v.Throttle
+			}
+			if v.PollInterval == 0 || v.PollInterval >= minPollInterval {
+				repo.PollInterval = v.PollInterval
+			}
+			repo.PollBranches = v.PollBranches
+
+			//
+			// system administrator only
+			//
+			if user != nil && user.Admin {
+				repo.Trusted = v.Trusted
+				repo.Timeout = v.Timeout
+			}
+
+			err = repos.Update(r.Context(), repo)
+			if err != nil {
+				render.InternalError(w, err)
+				logger.FromRequest(r).
+					WithError(err).
+					WithField("namespace", owner).
+					WithField("name", name).
+					Warnln("api: cannot update repository")
+				return
+			}
+		}
+
+		for _, cronjob := range cronList {
+			if cronjob.ID == 0 {
+				err = crons.Create(r.Context(), cronjob)
+			} else {
+				err = crons.Update(r.Context(), cronjob)
+			}
+			if err != nil {
+				render.InternalError(w, err)
+				logger.FromRequest(r).
+					WithError(err).
+					WithField("namespace", owner).
+					WithField("name", name).
+					WithField("cron", cronjob.Name).
+					Warnln("api: cannot import cron job")
+				return
+			}
+		}
+
+		existing, err := secrets.List(r.Context(), repo.ID)
+		if err != nil {
+			render.InternalError(w, err)
+			logger.FromRequest(r).
+				WithError(err).
+				WithField("namespace", owner).
+				WithField("name", name).
+				Debugln("api: cannot list secrets")
+			return
+		}
+		names := map[string]bool{}
+		for _, secret := range existing {
+			names[secret.Name] = true
+		}
+		out := &importResult{Repo: repo, MissingSecrets: []string{}}
+		for _, secret := range in.Secrets {
+			if !names[secret.Name] {
+				out.MissingSecrets = append(out.MissingSecrets, secret.Name)
+			}
+		}
+		render.JSON(w, out, 200)
+	}
+}
@@ -0,0 +1,155 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package repos
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/request"
+	"github.com/drone/drone/mock"
+
+	"github.com/go-chi/chi"
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestImport(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	repo := &core.Repository{
+		ID:        2,
+		Namespace: "octocat",
+		Name:      "spoon-fork",
+		Slug:      "octocat/spoon-fork",
+		Timeout:   60,
+	}
+	existing := &core.Cron{ID: 3, RepoID: 2, Name: "weekly", Expr: "0 0 0 * * 0", Branch: "master"}
+
+	repos := mock.NewMockRepositoryStore(controller)
+	repos.EXPECT().FindName(gomock.Any(), repo.Namespace, repo.Name).Return(repo, nil)
+	repos.EXPECT().Update(gomock.Any(), repo).Return(nil)
+
+	crons := mock.NewMockCronStore(controller)
+	crons.EXPECT().FindName(gomock.Any(), repo.ID, "nightly").Return(nil, sql.ErrNoRows)
+	crons.EXPECT().FindName(gomock.Any(), repo.ID, "weekly").Return(existing, nil)
+	crons.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+	crons.EXPECT().Update(gomock.Any(), existing).Return(nil)
+
+	secrets := mock.NewMockSecretStore(controller)
+	secrets.EXPECT().List(gomock.Any(), repo.ID).Return([]*core.Secret{
+		{Name: "docker_username"},
+	}, nil)
+
+	in := new(bytes.Buffer)
+	json.NewEncoder(in).Encode(&settings{
+		Version: settingsVersion,
+		Slug:    "octocat/hello-world",
+		Repo: &settingsRepo{
+			Visibility: core.VisibilityInternal,
+			Config:     ".drone.yml",
+			Protected:  true,
+			Trusted:    true,
+			Timeout:    90,
+		},
+		Secrets: []*settingsSecret{
+			{Name: "docker_username"},
+			{Name: "docker_password"},
+		},
+		Cron: []*settingsCron{
+			{Name: "nightly", Expr: "0 0 0 * * *", Branch: "master"},
+			{Name: "weekly", Expr: "0 0 0 * * 1", Branch: "develop", Disabled: true},
+		},
+	})
+
+	c := new(chi.Context)
+	c.URLParams.Add("owner", "octocat")
+	c.URLParams.Add("name", "spoon-fork")
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/", in)
+	r = r.WithContext(
+		context.WithValue(request.WithUser(r.Context(), &core.User{ID: 1}), chi.RouteCtxKey, c),
+	)
+
+	HandleImport(repos, secrets, crons)(w, r)
+	if got, want := w.Code, 200; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+
+	got := new(importResult)
+	json.NewDecoder(w.Body).Decode(got)
+	if diff := cmp.Diff(got.MissingSecrets, []string{"docker_password"}); diff != "" {
+		t.Errorf(diff)
+	}
+	if got, want := repo.Visibility, core.VisibilityInternal; got != want {
+		t.Errorf("Want visibility %q, got %q", want, got)
+	}
+	if got, want := repo.Protected, true; got != want {
+		t.Errorf("Want protected %v, got %v", want, got)
+	}
+
+	// trusted and timeout are only imported by system
+	// administrators.
+	if got, want := repo.Trusted, false; got != want {
+		t.Errorf("Want trusted %v, got %v", want, got)
+	}
+	if got, want := repo.Timeout, int64(60); got != want {
+		t.Errorf("Want timeout %d, got %d", want, got)
+	}
+
+	if got, want := existing.Branch, "develop"; got != want {
+		t.Errorf("Want cron branch %q, got %q", want, got)
+	}
+	if got, want := existing.Disabled, true; got != want {
+		t.Errorf("Want cron disabled %v, got %v", want, got)
+	}
+}
+
+func TestImport_InvalidCron(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	repo := &core.Repository{ID: 2, Namespace: "octocat", Name: "spoon-fork"}
+
+	repos := mock.NewMockRepositoryStore(controller)
+	repos.EXPECT().FindName(gomock.Any(), repo.Namespace, repo.Name).Return(repo, nil)
+
+	crons := mock.NewMockCronStore(controller)
+	crons.EXPECT().FindName(gomock.Any(), repo.ID, "nightly").Return(nil, sql.ErrNoRows)
+
+	in := new(bytes.Buffer)
+	json.NewEncoder(in).Encode(&settings{
+		Version: settingsVersion,
+		Repo:    &settingsRepo{Protected: true},
+		Cron: []*settingsCron{
+			{Name: "nightly", Expr: "not a cron expression", Branch: "master"},
+		},
+	})
+
+	c := new(chi.Context)
+	c.URLParams.Add("owner", "octocat")
+	c.URLParams.Add("name", "spoon-fork")
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/", in)
+	r = r.WithContext(
+		context.WithValue(request.WithUser(r.Context(), &core.User{ID: 1}), chi.RouteCtxKey, c),
+	)
+
+	HandleImport(repos, nil, crons)(w, r)
+	if got, want := w.Code, 400; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+	if repo.Protected {
+		t.Errorf("Want repository unchanged when the import is invalid")
+	}
+}
@@ -0,0 +1,60 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package insights
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/render"
+	"github.com/drone/drone/logger"
+
+	"github.com/go-chi/chi"
+)
+
+// HandleListFlaky returns an http.HandlerFunc that writes a
+// json-encoded list of the flaky steps for the repository. A
+// step is flaky when it both passed and failed for the same
+// commit. The optional branch parameter limits the results
+// to builds for the named branch.
+func HandleListFlaky(
+	repos core.RepositoryStore,
+	insights core.InsightStore,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var (
+			namespace = chi.URLParam(r, "owner")
+			name      = chi.URLParam(r, "name")
+			branch    = r.FormValue("branch")
+			since     = parseSince(r)
+		)
+		limit, _ := strconv.Atoi(r.FormValue("limit"))
+		if limit < 1 || limit > 100 {
+			limit = 25
+		}
+		repo, err := repos.FindName(r.Context(), namespace, name)
+		if err != nil {
+			render.NotFound(w, err)
+			logger.FromRequest(r).
+				WithError(err).
+				WithField("namespace", namespace).
+				WithField("name", name).
+				Debugln("api: cannot find repository")
+			return
+		}
+		list, err := insights.ListFlaky(r.Context(), repo.ID, branch, since, limit)
+		if err != nil {
+			render.InternalError(w, err)
+			logger.FromRequest(r).
+				WithError(err).
+				WithField("namespace", namespace).
+				WithField("name", name).
+				Debugln("api: cannot list flaky steps")
+			return
+		}
+		render.JSON(w, list, 200)
+	}
+}
@@ -0,0 +1,77 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package insights
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/render"
+	"github.com/drone/drone/logger"
+
+	"github.com/go-chi/chi"
+)
+
+// HandleFind returns an http.HandlerFunc that writes a
+// json-encoded summary of the repository builds, including
+// the success rate, duration percentiles and mean queue
+// wait time. The days parameter defines the period, and
+// defaults to 30 days.
+func HandleFind(
+	repos core.RepositoryStore,
+	insights core.InsightStore,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var (
+			namespace = chi.URLParam(r, "owner")
+			name      = chi.URLParam(r, "name")
+			since     = parseSince(r)
+		)
+		repo, err := repos.FindName(r.Context(), namespace, name)
+		if err != nil {
+			render.NotFound(w, err)
+			logger.FromRequest(r).
+				WithError(err).
+				WithField("namespace", namespace).
+				WithField("name", name).
+				Debugln("api: cannot find repository")
+			return
+		}
+		days, err := insights.ListDays(r.Context(), repo.ID, since)
+		if err != nil {
+			render.InternalError(w, err)
+			logger.FromRequest(r).
+				WithError(err).
+				WithField("namespace", namespace).
+				WithField("name", name).
+				Debugln("api: cannot list build insights")
+			return
+		}
+		buckets, err := insights.ListDurations(r.Context(), repo.ID, since)
+		if err != nil {
+			render.InternalError(w, err)
+			logger.FromRequest(r).
+				WithError(err).
+				WithField("namespace", namespace).
+				WithField("name", name).
+				Debugln("api: cannot list build durations")
+			return
+		}
+		render.JSON(w, core.NewInsight(since, days, buckets), 200)
+	}
+}
+
+// helper function returns the first day of the period
+// requested with the days parameter.
+func parseSince(r *http.Request) int64 {
+	days, _ := strconv.Atoi(r.FormValue("days"))
+	if days < 1 || days > 365 {
+		days = 30
+	}
+	today := core.InsightDayOf(time.Now().Unix())
+	return today - int64(days-1)*86400
+}
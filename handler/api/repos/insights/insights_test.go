@@ -0,0 +1,205 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package insights
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/errors"
+	"github.com/drone/drone/mock"
+
+	"github.com/go-chi/chi"
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+)
+
+var (
+	mockRepo = &core.Repository{
+		ID:        1,
+		Namespace: "octocat",
+		Name:      "hello-world",
+		Slug:      "octocat/hello-world",
+	}
+
+	mockDays = []*core.InsightDay{
+		{Day: 1552435200, Total: 3, Success: 2, Failure: 1, Duration: 300, Wait: 30},
+		{Day: 1552521600, Total: 1, Success: 1, Failure: 0, Duration: 100, Wait: 10},
+	}
+
+	mockBuckets = []*core.InsightBucket{
+		{Bucket: 0, Count: 1},
+		{Bucket: 2, Count: 3},
+	}
+
+	mockSteps = []*core.InsightStep{
+		{Stage: "default", Name: "test", Total: 4, Failure: 1, FailureRate: 0.25},
+	}
+
+	mockFlaky = []*core.InsightFlaky{
+		{Stage: "default", Name: "test", Commits: 4, Flaky: 1, Score: 0.25},
+	}
+)
+
+func TestFind(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	repos := mock.NewMockRepositoryStore(controller)
+	repos.EXPECT().FindName(gomock.Any(), mockRepo.Namespace, mockRepo.Name).Return(mockRepo, nil)
+
+	insights := mock.NewMockInsightStore(controller)
+	insights.EXPECT().ListDays(gomock.Any(), mockRepo.ID, gomock.Any()).Return(mockDays, nil)
+	insights.EXPECT().ListDurations(gomock.Any(), mockRepo.ID, gomock.Any()).Return(mockBuckets, nil)
+
+	c := new(chi.Context)
+	c.URLParams.Add("owner", "octocat")
+	c.URLParams.Add("name", "hello-world")
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/?days=7", nil)
+	r = r.WithContext(
+		context.WithValue(context.Background(), chi.RouteCtxKey, c),
+	)
+
+	HandleFind(repos, insights)(w, r)
+	if got, want := w.Code, 200; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+
+	insight := new(core.Insight)
+	json.NewDecoder(w.Body).Decode(insight)
+	if got, want := insight.Builds, int64(4); got != want {
+		t.Errorf("Want %d builds, got %d", want, got)
+	}
+	if got, want := insight.SuccessRate, 0.75; got != want {
+		t.Errorf("Want success rate %v, got %v", want, got)
+	}
+	if got, want := insight.DurationP90, core.InsightBuckets[2]; got != want {
+		t.Errorf("Want p90 duration %d, got %d", want, got)
+	}
+}
+
+func TestFind_RepoNotFound(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	repos := mock.NewMockRepositoryStore(controller)
+	repos.EXPECT().FindName(gomock.Any(), mockRepo.Namespace, mockRepo.Name).Return(nil, errors.ErrNotFound)
+
+	c := new(chi.Context)
+	c.URLParams.Add("owner", "octocat")
+	c.URLParams.Add("name", "hello-world")
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/", nil)
+	r = r.WithContext(
+		context.WithValue(context.Background(), chi.RouteCtxKey, c),
+	)
+
+	HandleFind(repos, nil)(w, r)
+	if got, want := w.Code, 404; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+}
+
+func TestListDays(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	repos := mock.NewMockRepositoryStore(controller)
+	repos.EXPECT().FindName(gomock.Any(), mockRepo.Namespace, mockRepo.Name).Return(mockRepo, nil)
+
+	insights := mock.NewMockInsightStore(controller)
+	insights.EXPECT().ListDays(gomock.Any(), mockRepo.ID, gomock.Any()).Return(mockDays, nil)
+
+	c := new(chi.Context)
+	c.URLParams.Add("owner", "octocat")
+	c.URLParams.Add("name", "hello-world")
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/", nil)
+	r = r.WithContext(
+		context.WithValue(context.Background(), chi.RouteCtxKey, c),
+	)
+
+	HandleListDays(repos, insights)(w, r)
+	if got, want := w.Code, 200; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+
+	got, want := []*core.InsightDay{}, mockDays
+	json.NewDecoder(w.Body).Decode(&got)
+	if diff := cmp.Diff(got, want); len(diff) != 0 {
+		t.Errorf(diff)
+	}
+}
+
+func TestListSteps(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	repos := mock.NewMockRepositoryStore(controller)
+	repos.EXPECT().FindName(gomock.Any(), mockRepo.Namespace, mockRepo.Name).Return(mockRepo, nil)
+
+	insights := mock.NewMockInsightStore(controller)
+	insights.EXPECT().ListSteps(gomock.Any(), mockRepo.ID, gomock.Any(), 10).Return(mockSteps, nil)
+
+	c := new(chi.Context)
+	c.URLParams.Add("owner", "octocat")
+	c.URLParams.Add("name", "hello-world")
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/?limit=10", nil)
+	r = r.WithContext(
+		context.WithValue(context.Background(), chi.RouteCtxKey, c),
+	)
+
+	HandleListSteps(repos, insights)(w, r)
+	if got, want := w.Code, 200; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+
+	got, want := []*core.InsightStep{}, mockSteps
+	json.NewDecoder(w.Body).Decode(&got)
+	if diff := cmp.Diff(got, want); len(diff) != 0 {
+		t.Errorf(diff)
+	}
+}
+
+func TestListFlaky(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	repos := mock.NewMockRepositoryStore(controller)
+	repos.EXPECT().FindName(gomock.Any(), mockRepo.Namespace, mockRepo.Name).Return(mockRepo, nil)
+
+	insights := mock.NewMockInsightStore(controller)
+	insights.EXPECT().ListFlaky(gomock.Any(), mockRepo.ID, "master", gomock.Any(), 25).Return(mockFlaky, nil)
+
+	c := new(chi.Context)
+	c.URLParams.Add("owner", "octocat")
+	c.URLParams.Add("name", "hello-world")
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/?branch=master", nil)
+	r = r.WithContext(
+		context.WithValue(context.Background(), chi.RouteCtxKey, c),
+	)
+
+	HandleListFlaky(repos, insights)(w, r)
+	if got, want := w.Code, 200; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+
+	got, want := []*core.InsightFlaky{}, mockFlaky
+	json.NewDecoder(w.Body).Decode(&got)
+	if diff := cmp.Diff(got, want); len(diff) != 0 {
+		t.Errorf(diff)
+	}
+}
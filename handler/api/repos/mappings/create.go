@@ -0,0 +1,68 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package mappings
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/render"
+	"github.com/drone/drone/logger"
+
+	"github.com/go-chi/chi"
+)
+
+type mappingInput struct {
+	Pattern  string `json:"pattern"`
+	Pipeline string `json:"pipeline"`
+}
+
+// HandleCreate returns an http.HandlerFunc that processes http
+// requests to route changed paths to a named pipeline.
+func HandleCreate(
+	repos core.RepositoryStore,
+	mappings core.PathMappingStore,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var (
+			namespace = chi.URLParam(r, "owner")
+			name      = chi.URLParam(r, "name")
+		)
+		repo, err := repos.FindName(r.Context(), namespace, name)
+		if err != nil {
+			render.NotFound(w, err)
+			return
+		}
+		in := new(mappingInput)
+		err = json.NewDecoder(r.Body).Decode(in)
+		if err != nil {
+			render.BadRequest(w, err)
+			return
+		}
+
+		mapping := &core.PathMapping{
+			RepoID:   repo.ID,
+			Pattern:  in.Pattern,
+			Pipeline: in.Pipeline,
+			Created:  time.Now().Unix(),
+		}
+		err = mapping.Validate()
+		if err != nil {
+			render.BadRequest(w, err)
+			return
+		}
+
+		err = mappings.Create(r.Context(), mapping)
+		if err != nil {
+			render.InternalError(w, err)
+			logger.FromRequest(r).WithError(err).
+				Warnln("api: cannot create path mapping")
+			return
+		}
+		render.JSON(w, mapping, 200)
+	}
+}
@@ -0,0 +1,78 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package notifications
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/render"
+
+	"github.com/go-chi/chi"
+)
+
+type notificationInput struct {
+	Kind       string   `json:"kind"`
+	Endpoint   string   `json:"endpoint"`
+	Channel    string   `json:"channel"`
+	Events     []string `json:"events"`
+	Branches   []string `json:"branches"`
+	Recipients []string `json:"recipients"`
+	Author     bool     `json:"author"`
+}
+
+// HandleCreate returns an http.HandlerFunc that processes http
+// requests to create a new repository notification.
+func HandleCreate(
+	repos core.RepositoryStore,
+	notifications core.NotificationStore,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var (
+			namespace = chi.URLParam(r, "owner")
+			name      = chi.URLParam(r, "name")
+		)
+		repo, err := repos.FindName(r.Context(), namespace, name)
+		if err != nil {
+			render.NotFound(w, err)
+			return
+		}
+		in := new(notificationInput)
+		err = json.NewDecoder(r.Body).Decode(in)
+		if err != nil {
+			render.BadRequest(w, err)
+			return
+		}
+
+		notification := &core.Notification{
+			RepoID:     repo.ID,
+			Kind:       in.Kind,
+			Endpoint:   in.Endpoint,
+			Channel:    in.Channel,
+			Events:     in.Events,
+			Branches:   in.Branches,
+			Recipients: in.Recipients,
+			Author:     in.Author,
+			Created:    time.Now().Unix(),
+			Updated:    time.Now().Unix(),
+		}
+
+		err = notification.Validate()
+		if err != nil {
+			render.BadRequest(w, err)
+			return
+		}
+
+		err = notifications.Create(r.Context(), notification)
+		if err != nil {
+			render.InternalError(w, err)
+			return
+		}
+
+		render.JSON(w, notification.Copy(), 200)
+	}
+}
@@ -0,0 +1,39 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package notifications
+
+import (
+	"net/http"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/render"
+
+	"github.com/go-chi/chi"
+)
+
+// HandleFind returns an http.HandlerFunc that writes a json-encoded
+// repository notification to the response body.
+func HandleFind(
+	repos core.RepositoryStore,
+	notifications core.NotificationStore,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var (
+			namespace = chi.URLParam(r, "owner")
+			name      = chi.URLParam(r, "name")
+		)
+		repo, err := repos.FindName(r.Context(), namespace, name)
+		if err != nil {
+			render.NotFound(w, err)
+			return
+		}
+		notification, err := findNotification(r.Context(), notifications, repo, chi.URLParam(r, "notification"))
+		if err != nil {
+			render.NotFound(w, err)
+			return
+		}
+		render.JSON(w, notification.Copy(), 200)
+	}
+}
@@ -0,0 +1,45 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package notifications
+
+import (
+	"net/http"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/render"
+
+	"github.com/go-chi/chi"
+)
+
+// HandleList returns an http.HandlerFunc that writes a json-encoded
+// list of repository notifications to the response body.
+func HandleList(
+	repos core.RepositoryStore,
+	notifications core.NotificationStore,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var (
+			namespace = chi.URLParam(r, "owner")
+			name      = chi.URLParam(r, "name")
+		)
+		repo, err := repos.FindName(r.Context(), namespace, name)
+		if err != nil {
+			render.NotFound(w, err)
+			return
+		}
+		list, err := notifications.List(r.Context(), repo.ID)
+		if err != nil {
+			render.InternalError(w, err)
+			return
+		}
+		// the notification list is copied and the notification
+		// endpoint is removed from the response.
+		notifications := []*core.Notification{}
+		for _, notification := range list {
+			notifications = append(notifications, notification.Copy())
+		}
+		render.JSON(w, notifications, 200)
+	}
+}
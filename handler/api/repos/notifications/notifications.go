@@ -0,0 +1,31 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package notifications
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+
+	"github.com/drone/drone/core"
+)
+
+// helper function returns the repository notification with the
+// given identifier. An error is returned if the notification does
+// not belong to the repository.
+func findNotification(ctx context.Context, notifications core.NotificationStore, repo *core.Repository, param string) (*core.Notification, error) {
+	id, err := strconv.ParseInt(param, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	notification, err := notifications.Find(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if notification.RepoID != repo.ID {
+		return nil, sql.ErrNoRows
+	}
+	return notification, nil
+}
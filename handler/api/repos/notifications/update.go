@@ -0,0 +1,92 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package notifications
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/render"
+
+	"github.com/go-chi/chi"
+)
+
+type notificationUpdate struct {
+	Endpoint   *string  `json:"endpoint"`
+	Channel    *string  `json:"channel"`
+	Events     []string `json:"events"`
+	Branches   []string `json:"branches"`
+	Recipients []string `json:"recipients"`
+	Author     *bool    `json:"author"`
+}
+
+// HandleUpdate returns an http.HandlerFunc that processes http
+// requests to update a repository notification.
+func HandleUpdate(
+	repos core.RepositoryStore,
+	notifications core.NotificationStore,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var (
+			namespace = chi.URLParam(r, "owner")
+			name      = chi.URLParam(r, "name")
+		)
+
+		in := new(notificationUpdate)
+		err := json.NewDecoder(r.Body).Decode(in)
+		if err != nil {
+			render.BadRequest(w, err)
+			return
+		}
+
+		repo, err := repos.FindName(r.Context(), namespace, name)
+		if err != nil {
+			render.NotFound(w, err)
+			return
+		}
+
+		notification, err := findNotification(r.Context(), notifications, repo, chi.URLParam(r, "notification"))
+		if err != nil {
+			render.NotFound(w, err)
+			return
+		}
+
+		if in.Endpoint != nil {
+			notification.Endpoint = *in.Endpoint
+		}
+		if in.Channel != nil {
+			notification.Channel = *in.Channel
+		}
+		if in.Events != nil {
+			notification.Events = in.Events
+		}
+		if in.Branches != nil {
+			notification.Branches = in.Branches
+		}
+		if in.Recipients != nil {
+			notification.Recipients = in.Recipients
+		}
+		if in.Author != nil {
+			notification.Author = *in.Author
+		}
+		notification.Updated = time.Now().Unix()
+
+		err = notification.Validate()
+		if err != nil {
+			render.BadRequest(w, err)
+			return
+		}
+
+		err = notifications.Update(r.Context(), notification)
+		if err != nil {
+			render.InternalError(w, err)
+			return
+		}
+
+		render.JSON(w, notification.Copy(), 200)
+	}
+}
@@ -0,0 +1,96 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/mock"
+
+	"github.com/go-chi/chi"
+	"github.com/golang/mock/gomock"
+)
+
+func TestHandleUpdate(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	notification := new(core.Notification)
+	*notification = *dummyNotification
+
+	repos := mock.NewMockRepositoryStore(controller)
+	repos.EXPECT().FindName(gomock.Any(), dummyRepo.Namespace, dummyRepo.Name).Return(dummyRepo, nil)
+
+	notifications := mock.NewMockNotificationStore(controller)
+	notifications.EXPECT().Find(gomock.Any(), notification.ID).Return(notification, nil)
+	notifications.EXPECT().Update(gomock.Any(), notification).Return(nil)
+
+	c := new(chi.Context)
+	c.URLParams.Add("owner", "octocat")
+	c.URLParams.Add("name", "hello-world")
+	c.URLParams.Add("notification", "2")
+
+	in := new(bytes.Buffer)
+	json.NewEncoder(in).Encode(&notificationUpdate{
+		Branches: []string{"master"},
+	})
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("PATCH", "/", in)
+	r = r.WithContext(
+		context.WithValue(context.Background(), chi.RouteCtxKey, c),
+	)
+
+	HandleUpdate(repos, notifications).ServeHTTP(w, r)
+	if got, want := w.Code, http.StatusOK; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+	if got, want := len(notification.Branches), 1; got != want {
+		t.Errorf("Want %d branches, got %d", want, got)
+	}
+	if got, want := notification.Channel, dummyNotification.Channel; got != want {
+		t.Errorf("Expect notification channel unchanged")
+	}
+}
+
+// this test verifies that a notification cannot be updated
+// using the url of a different repository.
+func TestHandleUpdate_OtherRepo(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	other := &core.Repository{ID: 3, Namespace: "spaceghost", Name: "hello-world"}
+
+	repos := mock.NewMockRepositoryStore(controller)
+	repos.EXPECT().FindName(gomock.Any(), other.Namespace, other.Name).Return(other, nil)
+
+	notifications := mock.NewMockNotificationStore(controller)
+	notifications.EXPECT().Find(gomock.Any(), dummyNotification.ID).Return(dummyNotification, nil)
+
+	c := new(chi.Context)
+	c.URLParams.Add("owner", "spaceghost")
+	c.URLParams.Add("name", "hello-world")
+	c.URLParams.Add("notification", "2")
+
+	in := new(bytes.Buffer)
+	json.NewEncoder(in).Encode(&notificationUpdate{})
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("PATCH", "/", in)
+	r = r.WithContext(
+		context.WithValue(context.Background(), chi.RouteCtxKey, c),
+	)
+
+	HandleUpdate(repos, notifications).ServeHTTP(w, r)
+	if got, want := w.Code, http.StatusNotFound; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+}
@@ -0,0 +1,111 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package refs
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/render"
+	"github.com/drone/drone/logger"
+
+	"github.com/go-chi/chi"
+)
+
+// HandleBranches returns an http.HandlerFunc that writes a
+// json-encoded list of repository branches, optionally
+// filtered by name, which can be used to populate a branch
+// picker in the user interface.
+func HandleBranches(
+	repos core.RepositoryStore,
+	users core.UserStore,
+	refs core.ReferenceService,
+) http.HandlerFunc {
+	return handleList(repos, users, refs.ListBranches)
+}
+
+// HandleTags returns an http.HandlerFunc that writes a
+// json-encoded list of repository tags, optionally filtered
+// by name, which can be used to populate a tag picker in the
+// user interface.
+func HandleTags(
+	repos core.RepositoryStore,
+	users core.UserStore,
+	refs core.ReferenceService,
+) http.HandlerFunc {
+	return handleList(repos, users, refs.ListTags)
+}
+
+type listFunc func(context.Context, *core.User, string) ([]*core.Reference, error)
+
+func handleList(
+	repos core.RepositoryStore,
+	users core.UserStore,
+	list listFunc,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var (
+			ctx       = r.Context()
+			namespace = chi.URLParam(r, "owner")
+			name      = chi.URLParam(r, "name")
+			query     = strings.ToLower(r.FormValue("q"))
+		)
+		limit, _ := strconv.Atoi(r.FormValue("limit"))
+		if limit < 1 || limit > 100 {
+			limit = 25
+		}
+		repo, err := repos.FindName(ctx, namespace, name)
+		if err != nil {
+			render.NotFound(w, err)
+			logger.FromRequest(r).
+				WithError(err).
+				WithField("namespace", namespace).
+				WithField("name", name).
+				Debugln("api: cannot find repository")
+			return
+		}
+
+		// the branches and tags are requested from the source
+		// code management system using the repository owner
+		// credentials. Access to the repository is verified by
+		// the Drone repository permissions, not the permissions
+		// of the user in the source code management system.
+		owner, err := users.Find(ctx, repo.UserID)
+		if err != nil {
+			render.NotFound(w, err)
+			logger.FromRequest(r).
+				WithError(err).
+				WithField("namespace", namespace).
+				WithField("name", name).
+				Debugln("api: cannot find repository owner")
+			return
+		}
+
+		refs, err := list(ctx, owner, repo.Slug)
+		if err != nil {
+			render.InternalError(w, err)
+			logger.FromRequest(r).
+				WithError(err).
+				WithField("namespace", namespace).
+				WithField("name", name).
+				Debugln("api: cannot list repository references")
+			return
+		}
+
+		out := []*core.Reference{}
+		for _, ref := range refs {
+			if len(out) == limit {
+				break
+			}
+			if query == "" || strings.Contains(strings.ToLower(ref.Name), query) {
+				out = append(out, ref)
+			}
+		}
+		render.JSON(w, out, 200)
+	}
+}
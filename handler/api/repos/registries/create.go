@@ -0,0 +1,75 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package registries
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/render"
+
+	"github.com/go-chi/chi"
+)
+
+type registryInput struct {
+	Address  string `json:"address"`
+	Username string `json:"username"`
+	Password string `json:"password"`
+	Policy   string `json:"policy"`
+}
+
+// HandleCreate returns an http.HandlerFunc that processes http
+// requests to create registry credentials for the repository.
+func HandleCreate(
+	repos core.RepositoryStore,
+	registries core.RegistryStore,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var (
+			namespace = chi.URLParam(r, "owner")
+			name      = chi.URLParam(r, "name")
+		)
+		repo, err := repos.FindName(r.Context(), namespace, name)
+		if err != nil {
+			render.NotFound(w, err)
+			return
+		}
+		in := new(registryInput)
+		err = json.NewDecoder(r.Body).Decode(in)
+		if err != nil {
+			render.BadRequest(w, err)
+			return
+		}
+
+		registry := &core.Registry{
+			RepoID:   repo.ID,
+			Address:  in.Address,
+			Username: in.Username,
+			Password: in.Password,
+			Policy:   in.Policy,
+			Created:  time.Now().Unix(),
+			Updated:  time.Now().Unix(),
+		}
+		if registry.Policy == "" {
+			registry.Policy = core.RegistryPull
+		}
+
+		err = registry.Validate()
+		if err != nil {
+			render.BadRequest(w, err)
+			return
+		}
+
+		err = registries.Create(r.Context(), registry)
+		if err != nil {
+			render.InternalError(w, err)
+			return
+		}
+
+		render.JSON(w, registry.Copy(), 200)
+	}
+}
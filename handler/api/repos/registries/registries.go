@@ -0,0 +1,28 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package registries
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+
+	"github.com/drone/drone/core"
+)
+
+func findRegistry(ctx context.Context, registries core.RegistryStore, repo *core.Repository, param string) (*core.Registry, error) {
+	id, err := strconv.ParseInt(param, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	registry, err := registries.Find(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if registry.RepoID != repo.ID {
+		return nil, sql.ErrNoRows
+	}
+	return registry, nil
+}
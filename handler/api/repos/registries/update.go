@@ -0,0 +1,84 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package registries
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/render"
+
+	"github.com/go-chi/chi"
+)
+
+type registryUpdate struct {
+	Address  *string `json:"address"`
+	Username *string `json:"username"`
+	Password *string `json:"password"`
+	Policy   *string `json:"policy"`
+}
+
+// HandleUpdate returns an http.HandlerFunc that processes http
+// requests to update registry credentials for the repository.
+func HandleUpdate(
+	repos core.RepositoryStore,
+	registries core.RegistryStore,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var (
+			namespace = chi.URLParam(r, "owner")
+			name      = chi.URLParam(r, "name")
+		)
+
+		in := new(registryUpdate)
+		err := json.NewDecoder(r.Body).Decode(in)
+		if err != nil {
+			render.BadRequest(w, err)
+			return
+		}
+
+		repo, err := repos.FindName(r.Context(), namespace, name)
+		if err != nil {
+			render.NotFound(w, err)
+			return
+		}
+
+		registry, err := findRegistry(r.Context(), registries, repo, chi.URLParam(r, "registry"))
+		if err != nil {
+			render.NotFound(w, err)
+			return
+		}
+
+		if in.Address != nil {
+			registry.Address = *in.Address
+		}
+		if in.Username != nil {
+			registry.Username = *in.Username
+		}
+		if in.Password != nil {
+			registry.Password = *in.Password
+		}
+		if in.Policy != nil {
+			registry.Policy = *in.Policy
+		}
+		registry.Updated = time.Now().Unix()
+
+		err = registry.Validate()
+		if err != nil {
+			render.BadRequest(w, err)
+			return
+		}
+
+		err = registries.Update(r.Context(), registry)
+		if err != nil {
+			render.InternalError(w, err)
+			return
+		}
+
+		render.JSON(w, registry.Copy(), 200)
+	}
+}
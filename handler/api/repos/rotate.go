@@ -0,0 +1,102 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package repos
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/render"
+	"github.com/drone/drone/logger"
+
+	"github.com/dchest/uniuri"
+	"github.com/go-chi/chi"
+)
+
+// HandleRotate returns an http.HandlerFunc that processes http
+// requests to rotate the repository webhook secret and update
+// the repository hook. The previous secret is accepted until
+// the grace period expires, so that webhooks signed with the
+// previous secret are not rejected during rotation.
+func HandleRotate(
+	hooks core.HookService,
+	repos core.RepositoryStore,
+	users core.UserStore,
+	grace time.Duration,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var (
+			owner = chi.URLParam(r, "owner")
+			name  = chi.URLParam(r, "name")
+		)
+
+		repo, err := repos.FindName(r.Context(), owner, name)
+		if err != nil {
+			render.NotFound(w, err)
+			logger.FromRequest(r).
+				WithError(err).
+				WithField("namespace", owner).
+				WithField("name", name).
+				Debugln("api: repository not found")
+			return
+		}
+
+		user, err := users.Find(r.Context(), repo.UserID)
+		if err != nil {
+			render.NotFound(w, err)
+			logger.FromRequest(r).
+				WithError(err).
+				WithField("namespace", owner).
+				WithField("name", name).
+				Warnln("api: cannot find repository owner")
+			return
+		}
+
+		prev := *repo
+		repo.SignerPrev = repo.Signer
+		repo.SignerExpiry = time.Now().Add(grace).Unix()
+		repo.Signer = uniuri.NewLen(32)
+
+		err = repos.Update(r.Context(), repo)
+		if err != nil {
+			render.InternalError(w, err)
+			logger.FromRequest(r).
+				WithError(err).
+				WithField("namespace", owner).
+				WithField("name", name).
+				Warnln("api: cannot rotate repository secret")
+			return
+		}
+
+		err = hooks.Create(r.Context(), user, repo)
+		if err != nil {
+			render.InternalError(w, err)
+			logger.FromRequest(r).
+				WithError(err).
+				WithField("namespace", owner).
+				WithField("name", name).
+				Debugln("api: cannot create or update hook")
+
+			// the repository hook still uses the previous
+			// secret, so the previous secret is restored to
+			// avoid rejecting webhooks once the grace period
+			// expires.
+			repo.Signer = prev.Signer
+			repo.SignerPrev = prev.SignerPrev
+			repo.SignerExpiry = prev.SignerExpiry
+			if err := repos.Update(r.Context(), repo); err != nil {
+				logger.FromRequest(r).
+					WithError(err).
+					WithField("namespace", owner).
+					WithField("name", name).
+					Warnln("api: cannot restore repository secret")
+			}
+			return
+		}
+
+		render.JSON(w, repo, 200)
+	}
+}
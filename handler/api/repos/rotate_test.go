@@ -0,0 +1,174 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package repos
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/errors"
+	"github.com/drone/drone/mock"
+
+	"github.com/go-chi/chi"
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRotate(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	user := &core.User{
+		ID: 1,
+	}
+	repo := &core.Repository{
+		ID:        1,
+		UserID:    1,
+		Namespace: "octocat",
+		Name:      "hello-world",
+		Slug:      "octocat/hello-world",
+		Signer:    "correct-horse-battery-staple",
+	}
+
+	checkRotate := func(_ context.Context, updated *core.Repository) error {
+		if got, want := updated.SignerPrev, "correct-horse-battery-staple"; got != want {
+			t.Errorf("Want previous signer %q, got %q", want, got)
+		}
+		if updated.Signer == "" || updated.Signer == updated.SignerPrev {
+			t.Errorf("Want new signer generated")
+		}
+		if got := updated.SignerExpiry; got < time.Now().Add(time.Minute*59).Unix() {
+			t.Errorf("Want signer expiry after the grace period, got %d", got)
+		}
+		return nil
+	}
+
+	users := mock.NewMockUserStore(controller)
+	users.EXPECT().Find(gomock.Any(), repo.UserID).Return(user, nil)
+
+	repos := mock.NewMockRepositoryStore(controller)
+	repos.EXPECT().FindName(gomock.Any(), "octocat", "hello-world").Return(repo, nil)
+	repos.EXPECT().Update(gomock.Any(), repo).Return(nil).Do(checkRotate)
+
+	hooks := mock.NewMockHookService(controller)
+	hooks.EXPECT().Create(gomock.Any(), user, repo).Return(nil)
+
+	c := new(chi.Context)
+	c.URLParams.Add("owner", "octocat")
+	c.URLParams.Add("name", "hello-world")
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/", nil)
+	r = r.WithContext(
+		context.WithValue(r.Context(), chi.RouteCtxKey, c),
+	)
+
+	HandleRotate(hooks, repos, users, time.Hour)(w, r)
+	if got, want := w.Code, 200; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+
+	got := new(core.Repository)
+	json.NewDecoder(w.Body).Decode(got)
+	if got, want := got.SignerExpiry, repo.SignerExpiry; got != want {
+		t.Errorf("Want signer expiry %d, got %d", want, got)
+	}
+	if got.Signer != "" || got.SignerPrev != "" {
+		t.Errorf("Want signer omitted from the response")
+	}
+}
+
+// this test verifies that the previous secret is restored
+// when the repository hook cannot be updated.
+func TestRotate_HookError(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	user := &core.User{
+		ID: 1,
+	}
+	repo := &core.Repository{
+		ID:        1,
+		UserID:    1,
+		Namespace: "octocat",
+		Name:      "hello-world",
+		Slug:      "octocat/hello-world",
+		Signer:    "correct-horse-battery-staple",
+	}
+
+	checkRestore := func(_ context.Context, updated *core.Repository) error {
+		if got, want := updated.Signer, "correct-horse-battery-staple"; got != want {
+			t.Errorf("Want signer restored to %q, got %q", want, got)
+		}
+		if updated.SignerPrev != "" || updated.SignerExpiry != 0 {
+			t.Errorf("Want previous signer restored")
+		}
+		return nil
+	}
+
+	users := mock.NewMockUserStore(controller)
+	users.EXPECT().Find(gomock.Any(), repo.UserID).Return(user, nil)
+
+	repos := mock.NewMockRepositoryStore(controller)
+	repos.EXPECT().FindName(gomock.Any(), "octocat", "hello-world").Return(repo, nil)
+	gomock.InOrder(
+		repos.EXPECT().Update(gomock.Any(), repo).Return(nil),
+		repos.EXPECT().Update(gomock.Any(), repo).Return(nil).Do(checkRestore),
+	)
+
+	hooks := mock.NewMockHookService(controller)
+	hooks.EXPECT().Create(gomock.Any(), user, repo).Return(errors.ErrNotFound)
+
+	c := new(chi.Context)
+	c.URLParams.Add("owner", "octocat")
+	c.URLParams.Add("name", "hello-world")
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/", nil)
+	r = r.WithContext(
+		context.WithValue(r.Context(), chi.RouteCtxKey, c),
+	)
+
+	HandleRotate(hooks, repos, users, time.Hour)(w, r)
+	if got, want := w.Code, 500; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+}
+
+// this test verifies that a 404 not found error is returned
+// from the http.Handler if the named repository cannot be
+// found in the local database.
+func TestRotate_RepoNotFound(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	repos := mock.NewMockRepositoryStore(controller)
+	repos.EXPECT().FindName(gomock.Any(), "octocat", "hello-world").Return(nil, errors.ErrNotFound)
+
+	c := new(chi.Context)
+	c.URLParams.Add("owner", "octocat")
+	c.URLParams.Add("name", "hello-world")
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/", nil)
+	r = r.WithContext(
+		context.WithValue(r.Context(), chi.RouteCtxKey, c),
+	)
+
+	HandleRotate(nil, repos, nil, time.Hour)(w, r)
+	if got, want := w.Code, 404; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+
+	got, want := new(errors.Error), errors.ErrNotFound
+	json.NewDecoder(w.Body).Decode(got)
+	if diff := cmp.Diff(got, want); len(diff) != 0 {
+		t.Errorf(diff)
+	}
+}
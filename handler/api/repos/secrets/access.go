@@ -0,0 +1,61 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secrets
+
+import (
+	"net/http"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/render"
+	"github.com/drone/drone/logger"
+
+	"github.com/go-chi/chi"
+)
+
+// HandleAccess returns an http.HandlerFunc that writes a
+// json-encoded list of builds that were provided the secret
+// to the response body.
+func HandleAccess(
+	repos core.RepositoryStore,
+	secrets core.SecretStore,
+	access core.SecretAccessStore,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var (
+			namespace = chi.URLParam(r, "owner")
+			name      = chi.URLParam(r, "name")
+			secret    = chi.URLParam(r, "secret")
+		)
+		repo, err := repos.FindName(r.Context(), namespace, name)
+		if err != nil {
+			render.NotFound(w, err)
+			return
+		}
+		result, err := secrets.FindName(r.Context(), repo.ID, secret)
+		if err != nil {
+			render.NotFound(w, err)
+			return
+		}
+		list, err := access.List(r.Context(), result.ID)
+		if err != nil {
+			render.InternalError(w, err)
+			logger.FromRequest(r).WithError(err).
+				WithField("secret", secret).
+				Warnln("api: cannot list secret access")
+			return
+		}
+		render.JSON(w, list, 200)
+	}
+}
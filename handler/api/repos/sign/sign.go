@@ -40,7 +40,9 @@ func HandleSign(repos core.RepositoryStore) http.HandlerFunc {
 			return
 		}
 
-		k := []byte(repo.Secret)
+		// the key must match the key used to verify the
+		// signature when the build is triggered.
+		k := signer.KeyString(repo.Secret)
 		d := []byte(in.Data)
 		out, err := signer.Sign(d, k)
 		if err != nil {
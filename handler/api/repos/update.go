@@ -17,6 +17,7 @@ package repos
 import (
 	"encoding/json"
 	"net/http"
+	"regexp"
 
 	"github.com/drone/drone/core"
 	"github.com/drone/drone/handler/api/render"
@@ -28,17 +29,30 @@ import (
 
 type (
 	repositoryInput struct {
-		Visibility  *string `json:"visibility"`
-		Config      *string `json:"config_path"`
-		Trusted     *bool   `json:"trusted"`
-		Protected   *bool   `json:"protected"`
-		IgnoreForks *bool   `json:"ignore_forks"`
-		IgnorePulls *bool   `json:"ignore_pull_requests"`
-		Timeout     *int64  `json:"timeout"`
-		Counter     *int64  `json:"counter"`
+		Visibility   *string `json:"visibility"`
+		Config       *string `json:"config_path"`
+		Trusted      *bool   `json:"trusted"`
+		Protected    *bool   `json:"protected"`
+		IgnoreForks  *bool   `json:"ignore_forks"`
+		IgnorePulls  *bool   `json:"ignore_pull_requests"`
+		MergePulls   *bool   `json:"merge_pull_requests"`
+		IgnoreDupes  *bool   `json:"ignore_duplicates"`
+		NoMaskLogs   *bool   `json:"no_mask_logs"`
+		SkipPattern  *string `json:"skip_pattern"`
+		Summary      *bool   `json:"summary"`
+		SummaryLines *int64  `json:"summary_lines"`
+		Timeout      *int64  `json:"timeout"`
+		Throttle     *int64  `json:"throttle"`
+		PollInterval *int64  `json:"poll_interval"`
+		PollBranches *string `json:"poll_branches"`
+		Counter      *int64  `json:"counter"`
 	}
 )
 
+// minimum number of seconds between polling the repository
+// for new commits. A zero value disables polling.
+const minPollInterval = 60
+
 // HandleUpdate returns an http.HandlerFunc that processes http
 // requests to update the repository details.
 func HandleUpdate(repos core.RepositoryStore) http.HandlerFunc {
@@ -86,6 +100,48 @@ func HandleUpdate(repos core.RepositoryStore) http.HandlerFunc {
 		if in.IgnorePulls != nil {
 			repo.IgnorePulls = *in.IgnorePulls
 		}
+		if in.MergePulls != nil {
+			repo.MergePulls = *in.MergePulls
+		}
+		if in.IgnoreDupes != nil {
+			repo.IgnoreDupes = *in.IgnoreDupes
+		}
+		if in.NoMaskLogs != nil {
+			repo.NoMaskLogs = *in.NoMaskLogs
+		}
+		if in.SkipPattern != nil {
+			if _, err := regexp.Compile(*in.SkipPattern); err != nil {
+				render.BadRequestf(w, "Invalid skip pattern: %s", err)
+				logger.FromRequest(r).
+					WithError(err).
+					WithField("repository", slug).
+					Debugln("api: invalid skip pattern")
+				return
+			}
+			repo.SkipPattern = *in.SkipPattern
+		}
+		if in.Summary != nil {
+			repo.Summary = *in.Summary
+		}
+		if in.SummaryLines != nil && *in.SummaryLines >= 0 {
+			repo.SummaryLines = *in.SummaryLines
+		}
+		if in.Throttle != nil && *in.Throttle >= 0 {
+			repo.Throttle = *in.Throttle
+		}
+		if in.PollInterval != nil {
+			if *in.PollInterval != 0 && *in.PollInterval < minPollInterval {
+				render.BadRequestf(w, "Invalid poll interval: must be at least %d seconds", minPollInterval)
+				logger.FromRequest(r).
+					WithField("repository", slug).
+					Debugln("api: invalid poll interval")
+				return
+			}
+			repo.PollInterval = *in.PollInterval
+		}
+		if in.PollBranches != nil {
+			repo.PollBranches = *in.PollBranches
+		}
 
 		//
 		// system administrator only
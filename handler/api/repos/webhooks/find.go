@@ -0,0 +1,39 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package webhooks
+
+import (
+	"net/http"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/render"
+
+	"github.com/go-chi/chi"
+)
+
+// HandleFind returns an http.HandlerFunc that writes a json-encoded
+// repository webhook to the response body.
+func HandleFind(
+	repos core.RepositoryStore,
+	webhooks core.RepoWebhookStore,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var (
+			namespace = chi.URLParam(r, "owner")
+			name      = chi.URLParam(r, "name")
+		)
+		repo, err := repos.FindName(r.Context(), namespace, name)
+		if err != nil {
+			render.NotFound(w, err)
+			return
+		}
+		webhook, err := findWebhook(r.Context(), webhooks, repo, chi.URLParam(r, "webhook"))
+		if err != nil {
+			render.NotFound(w, err)
+			return
+		}
+		render.JSON(w, webhook.Copy(), 200)
+	}
+}
@@ -0,0 +1,61 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/mock"
+
+	"github.com/go-chi/chi"
+	"github.com/golang/mock/gomock"
+)
+
+func TestHandleUpdate(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	webhook := new(core.RepoWebhook)
+	*webhook = *dummyWebhook
+
+	repos := mock.NewMockRepositoryStore(controller)
+	repos.EXPECT().FindName(gomock.Any(), dummyRepo.Namespace, dummyRepo.Name).Return(dummyRepo, nil)
+
+	webhooks := mock.NewMockRepoWebhookStore(controller)
+	webhooks.EXPECT().Find(gomock.Any(), webhook.ID).Return(webhook, nil)
+	webhooks.EXPECT().Update(gomock.Any(), webhook).Return(nil)
+
+	c := new(chi.Context)
+	c.URLParams.Add("owner", "octocat")
+	c.URLParams.Add("name", "hello-world")
+	c.URLParams.Add("webhook", "2")
+
+	endpoint := "https://company.com/hooks/drone"
+	in := new(bytes.Buffer)
+	json.NewEncoder(in).Encode(&webhookUpdate{Endpoint: &endpoint})
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("PATCH", "/", in)
+	r = r.WithContext(
+		context.WithValue(context.Background(), chi.RouteCtxKey, c),
+	)
+
+	HandleUpdate(repos, webhooks).ServeHTTP(w, r)
+	if got, want := w.Code, http.StatusOK; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+	if got, want := webhook.Endpoint, endpoint; got != want {
+		t.Errorf("Want webhook endpoint %s, got %s", want, got)
+	}
+	if got, want := webhook.Secret, dummyWebhook.Secret; got != want {
+		t.Errorf("Expect webhook secret unchanged")
+	}
+}
@@ -0,0 +1,31 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package webhooks
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+
+	"github.com/drone/drone/core"
+)
+
+// helper function returns the repository webhook with the
+// given identifier. An error is returned if the webhook does
+// not belong to the repository.
+func findWebhook(ctx context.Context, webhooks core.RepoWebhookStore, repo *core.Repository, param string) (*core.RepoWebhook, error) {
+	id, err := strconv.ParseInt(param, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	webhook, err := webhooks.Find(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if webhook.RepoID != repo.ID {
+		return nil, sql.ErrNoRows
+	}
+	return webhook, nil
+}
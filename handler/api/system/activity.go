@@ -0,0 +1,131 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package system
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/render"
+	"github.com/drone/drone/logger"
+)
+
+type (
+	activity struct {
+		Running []*runningBuild    `json:"running"`
+		Pending []*pendingPlatform `json:"pending"`
+	}
+
+	runningBuild struct {
+		Repo   string        `json:"repo"`
+		Build  *core.Build   `json:"build"`
+		Stages []*core.Stage `json:"stages"`
+	}
+
+	pendingPlatform struct {
+		OS      string `json:"os"`
+		Arch    string `json:"arch"`
+		Variant string `json:"variant,omitempty"`
+		Kernel  string `json:"kernel,omitempty"`
+		Pending int    `json:"pending"`
+	}
+)
+
+// HandleActivity returns an http.HandlerFunc that writes a
+// json-encoded summary of the server-wide build activity to
+// the response body. The summary includes the running builds
+// and stages across all repositories, the machine executing
+// each stage, and the count of pending stages by platform.
+func HandleActivity(
+	repos core.RepositoryStore,
+	stages core.StageStore,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		builds, err := repos.ListIncomplete(ctx)
+		if err != nil {
+			render.InternalError(w, err)
+			logger.FromRequest(r).WithError(err).
+				Warnln("api: cannot list incomplete builds")
+			return
+		}
+		incomplete, err := stages.ListIncomplete(ctx)
+		if err != nil {
+			render.InternalError(w, err)
+			logger.FromRequest(r).WithError(err).
+				Warnln("api: cannot list incomplete stages")
+			return
+		}
+		render.JSON(w, aggregateActivity(builds, incomplete), 200)
+	}
+}
+
+// helper function groups the running stages by build, and
+// counts the pending stages by os, architecture, variant
+// and kernel version.
+func aggregateActivity(repos []*core.Repository, stages []*core.Stage) *activity {
+	out := &activity{
+		Running: []*runningBuild{},
+		Pending: []*pendingPlatform{},
+	}
+
+	var builds []*runningBuild
+	running := map[int64]*runningBuild{}
+	for _, repo := range repos {
+		if repo.Build == nil {
+			continue
+		}
+		if _, ok := running[repo.Build.ID]; ok {
+			continue
+		}
+		build := &runningBuild{
+			Repo:   repo.Slug,
+			Build:  repo.Build,
+			Stages: []*core.Stage{},
+		}
+		running[repo.Build.ID] = build
+		builds = append(builds, build)
+	}
+
+	pending := map[pendingPlatform]*pendingPlatform{}
+	for _, stage := range stages {
+		switch stage.Status {
+		case core.StatusRunning:
+			build, ok := running[stage.BuildID]
+			if !ok {
+				continue
+			}
+			build.Stages = append(build.Stages, stage)
+		case core.StatusPending:
+			key := pendingPlatform{
+				OS:      stage.OS,
+				Arch:    stage.Arch,
+				Variant: stage.Variant,
+				Kernel:  stage.Kernel,
+			}
+			platform, ok := pending[key]
+			if !ok {
+				platform = &key
+				pending[key] = platform
+				out.Pending = append(out.Pending, platform)
+			}
+			platform.Pending++
+		}
+	}
+
+	// builds with only pending stages are waiting in the
+	// queue, and are excluded from the running builds.
+	for _, build := range builds {
+		if len(build.Stages) != 0 {
+			out.Running = append(out.Running, build)
+		}
+	}
+
+	sort.SliceStable(out.Pending, func(i, j int) bool {
+		return out.Pending[i].Pending > out.Pending[j].Pending
+	})
+	return out
+}
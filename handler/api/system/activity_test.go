@@ -0,0 +1,74 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package system
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/mock"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestHandleActivity(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	incompleteRepos := []*core.Repository{
+		{ID: 1, Slug: "octocat/hello-world", Build: &core.Build{ID: 10, Number: 3, Status: core.StatusRunning}},
+		{ID: 2, Slug: "octocat/spoon-knife", Build: &core.Build{ID: 20, Number: 7, Status: core.StatusPending}},
+	}
+	incompleteStages := []*core.Stage{
+		{ID: 100, BuildID: 10, Number: 1, Status: core.StatusRunning, Machine: "agent-1", OS: "linux", Arch: "amd64"},
+		{ID: 101, BuildID: 10, Number: 2, Status: core.StatusPending, OS: "linux", Arch: "arm64", Variant: "v8"},
+		{ID: 200, BuildID: 20, Number: 1, Status: core.StatusPending, OS: "linux", Arch: "amd64"},
+		{ID: 201, BuildID: 20, Number: 2, Status: core.StatusPending, OS: "linux", Arch: "amd64"},
+	}
+
+	repos := mock.NewMockRepositoryStore(controller)
+	repos.EXPECT().ListIncomplete(gomock.Any()).Return(incompleteRepos, nil)
+
+	stages := mock.NewMockStageStore(controller)
+	stages.EXPECT().ListIncomplete(gomock.Any()).Return(incompleteStages, nil)
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/", nil)
+
+	HandleActivity(repos, stages).ServeHTTP(w, r)
+	if got, want := w.Code, 200; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+
+	got := &activity{}
+	json.NewDecoder(w.Body).Decode(got)
+
+	if got, want := len(got.Running), 1; got != want {
+		t.Errorf("Want %d running builds, got %d", want, got)
+		return
+	}
+	running := got.Running[0]
+	if got, want := running.Repo, "octocat/hello-world"; got != want {
+		t.Errorf("Want running repository %q, got %q", want, got)
+	}
+	if got, want := len(running.Stages), 1; got != want {
+		t.Errorf("Want %d running stages, got %d", want, got)
+		return
+	}
+	if got, want := running.Stages[0].Machine, "agent-1"; got != want {
+		t.Errorf("Want stage machine %q, got %q", want, got)
+	}
+
+	want := []*pendingPlatform{
+		{OS: "linux", Arch: "amd64", Pending: 2},
+		{OS: "linux", Arch: "arm64", Variant: "v8", Pending: 1},
+	}
+	if diff := cmp.Diff(got.Pending, want); len(diff) != 0 {
+		t.Errorf(diff)
+	}
+}
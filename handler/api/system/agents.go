@@ -0,0 +1,29 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package system
+
+import (
+	"net/http"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/render"
+	"github.com/drone/drone/logger"
+)
+
+// HandleAgents returns an http.HandlerFunc that writes a
+// json-encoded list of registered build agents, including
+// the agent version and negotiated capabilities.
+func HandleAgents(agents core.AgentRegistry) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		list, err := agents.List(r.Context())
+		if err != nil {
+			render.InternalError(w, err)
+			logger.FromRequest(r).WithError(err).
+				Warnln("api: cannot list agents")
+			return
+		}
+		render.JSON(w, list, 200)
+	}
+}
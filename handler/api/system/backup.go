@@ -0,0 +1,156 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package system
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/render"
+	"github.com/drone/drone/logger"
+)
+
+// backupVersion is the version of the backup document format.
+const backupVersion = 1
+
+// backupPageSize is the number of repositories and builds
+// loaded from the datastore at a time.
+const backupPageSize = 100
+
+type (
+	// backup is a portable, database-independent snapshot of
+	// the non-secret server data.
+	backup struct {
+		Version int           `json:"version"`
+		Users   []*core.User  `json:"users"`
+		Repos   []*backupRepo `json:"repos"`
+	}
+
+	// backupRepo is a repository with its build history and
+	// cron jobs. Repository secrets are not included.
+	backupRepo struct {
+		*core.Repository
+		Builds []*core.Build `json:"builds"`
+		Cron   []*core.Cron  `json:"cron"`
+	}
+
+	restoreCount struct {
+		Created int `json:"created"`
+		Skipped int `json:"skipped"`
+	}
+
+	restoreResult struct {
+		Users  restoreCount `json:"users"`
+		Repos  restoreCount `json:"repos"`
+		Builds int          `json:"builds"`
+		Cron   int          `json:"cron"`
+	}
+)
+
+// HandleBackup returns an http.HandlerFunc that writes a
+// json-encoded backup of the users, repositories, builds and
+// cron jobs to the response body. Secrets, tokens and logs
+// are not included. Repositories are written one at a time
+// to limit memory usage.
+func HandleBackup(
+	users core.UserStore,
+	repos core.RepositoryStore,
+	builds core.BuildStore,
+	stages core.StageStore,
+	crons core.CronStore,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		userList, err := users.List(ctx)
+		if err != nil {
+			render.InternalError(w, err)
+			logger.FromRequest(r).WithError(err).
+				Warnln("backup: cannot list users")
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Disposition", "attachment; filename=drone-backup.json")
+		w.WriteHeader(200)
+
+		enc := json.NewEncoder(w)
+		fmt.Fprintf(w, `{"version":%d,"users":`, backupVersion)
+		enc.Encode(userList)
+		io.WriteString(w, `,"repos":[`)
+
+		// once the response status is written errors can no
+		// longer be reported to the client. The document is
+		// left incomplete so that it cannot be restored.
+		count := 0
+		for offset := 0; ; offset += backupPageSize {
+			repoList, err := repos.ListAll(ctx, backupPageSize, offset)
+			if err != nil {
+				logger.FromRequest(r).WithError(err).
+					Warnln("backup: cannot list repositories")
+				return
+			}
+			for _, repo := range repoList {
+				out, err := backupRepository(r, repo, builds, stages, crons)
+				if err != nil {
+					logger.FromRequest(r).WithError(err).
+						WithField("repo", repo.Slug).
+						Warnln("backup: cannot backup repository")
+					return
+				}
+				if count != 0 {
+					io.WriteString(w, ",")
+				}
+				enc.Encode(out)
+				count++
+			}
+			if len(repoList) < backupPageSize {
+				break
+			}
+		}
+		io.WriteString(w, "]}\n")
+	}
+}
+
+// backupRepository loads the repository builds, stages, steps
+// and cron jobs.
+func backupRepository(
+	r *http.Request,
+	repo *core.Repository,
+	builds core.BuildStore,
+	stages core.StageStore,
+	crons core.CronStore,
+) (*backupRepo, error) {
+	ctx := r.Context()
+	out := &backupRepo{Repository: repo}
+	for offset := 0; ; offset += backupPageSize {
+		list, err := builds.ListFilter(ctx, repo.ID, core.BuildFilter{
+			Sort:   "asc",
+			Limit:  backupPageSize,
+			Offset: offset,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, build := range list {
+			build.Stages, err = stages.ListSteps(ctx, build.ID)
+			if err != nil {
+				return nil, err
+			}
+		}
+		out.Builds = append(out.Builds, list...)
+		if len(list) < backupPageSize {
+			break
+		}
+	}
+	cronList, err := crons.List(ctx, repo.ID)
+	if err != nil {
+		return nil, err
+	}
+	out.Cron = cronList
+	return out, nil
+}
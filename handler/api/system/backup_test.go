@@ -0,0 +1,177 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package system
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/mock"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestBackup(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockUsers := []*core.User{{ID: 1, Login: "octocat"}}
+	mockRepo := &core.Repository{ID: 1, UserID: 1, Namespace: "octocat", Name: "hello-world", Slug: "octocat/hello-world"}
+	mockBuild := &core.Build{ID: 1, RepoID: 1, Number: 1}
+	mockStages := []*core.Stage{{ID: 1, BuildID: 1, Number: 1, Steps: []*core.Step{{ID: 1, StageID: 1, Number: 1}}}}
+	mockCron := []*core.Cron{{ID: 1, RepoID: 1, Name: "nightly"}}
+
+	users := mock.NewMockUserStore(controller)
+	users.EXPECT().List(gomock.Any()).Return(mockUsers, nil)
+
+	repos := mock.NewMockRepositoryStore(controller)
+	repos.EXPECT().ListAll(gomock.Any(), backupPageSize, 0).Return([]*core.Repository{mockRepo}, nil)
+
+	builds := mock.NewMockBuildStore(controller)
+	builds.EXPECT().ListFilter(gomock.Any(), mockRepo.ID, core.BuildFilter{Sort: "asc", Limit: backupPageSize}).Return([]*core.Build{mockBuild}, nil)
+
+	stages := mock.NewMockStageStore(controller)
+	stages.EXPECT().ListSteps(gomock.Any(), mockBuild.ID).Return(mockStages, nil)
+
+	crons := mock.NewMockCronStore(controller)
+	crons.EXPECT().List(gomock.Any(), mockRepo.ID).Return(mockCron, nil)
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/", nil)
+
+	HandleBackup(users, repos, builds, stages, crons)(w, r)
+	if got, want := w.Code, 200; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+
+	got := new(backup)
+	if err := json.NewDecoder(w.Body).Decode(got); err != nil {
+		t.Error(err)
+		return
+	}
+	mockBuild.Stages = mockStages
+	want := &backup{
+		Version: backupVersion,
+		Users:   mockUsers,
+		Repos: []*backupRepo{
+			{Repository: mockRepo, Builds: []*core.Build{mockBuild}, Cron: mockCron},
+		},
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf(diff)
+	}
+}
+
+func TestRestore(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	in := &backup{
+		Version: backupVersion,
+		Users: []*core.User{
+			{ID: 1, Login: "octocat"},
+			{ID: 2, Login: "spaceghost"},
+		},
+		Repos: []*backupRepo{
+			{
+				Repository: &core.Repository{ID: 5, UserID: 2, Namespace: "spaceghost", Name: "hello-world"},
+				Builds: []*core.Build{
+					{ID: 9, RepoID: 5, Number: 1, Stages: []*core.Stage{
+						{ID: 10, Number: 1, Steps: []*core.Step{{ID: 11, Number: 1}}},
+					}},
+				},
+				Cron: []*core.Cron{{ID: 12, RepoID: 5, Name: "nightly"}},
+			},
+			{
+				Repository: &core.Repository{ID: 6, UserID: 1, Namespace: "octocat", Name: "hello-world"},
+			},
+		},
+	}
+
+	users := mock.NewMockUserStore(controller)
+	users.EXPECT().FindLogin(gomock.Any(), "octocat").Return(&core.User{ID: 1}, nil)
+	users.EXPECT().FindLogin(gomock.Any(), "spaceghost").Return(nil, sql.ErrNoRows)
+	users.EXPECT().Create(gomock.Any(), gomock.Any()).Do(func(_ interface{}, user *core.User) {
+		if user.Hash == "" {
+			t.Errorf("Expect user hash generated")
+		}
+		user.ID = 3
+	}).Return(nil)
+
+	repos := mock.NewMockRepositoryStore(controller)
+	repos.EXPECT().FindName(gomock.Any(), "spaceghost", "hello-world").Return(nil, sql.ErrNoRows)
+	repos.EXPECT().FindName(gomock.Any(), "octocat", "hello-world").Return(&core.Repository{ID: 1}, nil)
+	repos.EXPECT().Create(gomock.Any(), gomock.Any()).Do(func(_ interface{}, repo *core.Repository) {
+		if got, want := repo.UserID, int64(3); got != want {
+			t.Errorf("Want repository owner %d, got %d", want, got)
+		}
+		if repo.Signer == "" || repo.Secret == "" {
+			t.Errorf("Expect repository secrets generated")
+		}
+		repo.ID = 7
+	}).Return(nil)
+
+	builds := mock.NewMockBuildStore(controller)
+	builds.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ interface{}, build *core.Build, stages []*core.Stage) {
+		if got, want := build.RepoID, int64(7); got != want {
+			t.Errorf("Want build repository %d, got %d", want, got)
+		}
+		if got, want := stages[0].RepoID, int64(7); got != want {
+			t.Errorf("Want stage repository %d, got %d", want, got)
+		}
+		stages[0].ID = 8
+	}).Return(nil)
+
+	steps := mock.NewMockStepStore(controller)
+	steps.EXPECT().Create(gomock.Any(), gomock.Any()).Do(func(_ interface{}, step *core.Step) {
+		if got, want := step.StageID, int64(8); got != want {
+			t.Errorf("Want step stage %d, got %d", want, got)
+		}
+	}).Return(nil)
+
+	crons := mock.NewMockCronStore(controller)
+	crons.EXPECT().Create(gomock.Any(), gomock.Any()).Do(func(_ interface{}, cron *core.Cron) {
+		if got, want := cron.RepoID, int64(7); got != want {
+			t.Errorf("Want cron repository %d, got %d", want, got)
+		}
+	}).Return(nil)
+
+	body := new(bytes.Buffer)
+	json.NewEncoder(body).Encode(in)
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/", body)
+
+	HandleRestore(users, repos, builds, steps, crons)(w, r)
+	if got, want := w.Code, 200; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+
+	got, want := new(restoreResult), &restoreResult{
+		Users:  restoreCount{Created: 1, Skipped: 1},
+		Repos:  restoreCount{Created: 1, Skipped: 1},
+		Builds: 1,
+		Cron:   1,
+	}
+	json.NewDecoder(w.Body).Decode(got)
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf(diff)
+	}
+}
+
+func TestRestore_Version(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/", bytes.NewBufferString(`{"version":99}`))
+
+	HandleRestore(nil, nil, nil, nil, nil)(w, r)
+	if got, want := w.Code, 400; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+}
@@ -0,0 +1,165 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package system
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/render"
+	"github.com/drone/drone/logger"
+
+	"github.com/dchest/uniuri"
+)
+
+// HandleRestore returns an http.HandlerFunc that restores a
+// backup created by HandleBackup, enabling migration between
+// database drivers. Users and repositories that already exist
+// are skipped, and builds and cron jobs are only restored for
+// newly created repositories. Records are assigned new
+// identifiers, and new user and repository secrets are
+// generated, since these are not included in the backup.
+// Users must login to restore their remote access tokens.
+func HandleRestore(
+	users core.UserStore,
+	repos core.RepositoryStore,
+	builds core.BuildStore,
+	steps core.StepStore,
+	crons core.CronStore,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		in := new(backup)
+		err := json.NewDecoder(r.Body).Decode(in)
+		if err != nil {
+			render.BadRequest(w, err)
+			logger.FromRequest(r).WithError(err).
+				Debugln("restore: cannot unmarshal backup")
+			return
+		}
+		if in.Version != backupVersion {
+			render.BadRequestf(w, "Unsupported backup version %d", in.Version)
+			return
+		}
+
+		ctx := r.Context()
+		out := new(restoreResult)
+
+		// maps the user identifiers in the backup to the
+		// user identifiers in the datastore.
+		userIDs := map[int64]int64{}
+		for _, user := range in.Users {
+			existing, err := users.FindLogin(ctx, user.Login)
+			if err == nil {
+				userIDs[user.ID] = existing.ID
+				out.Users.Skipped++
+				continue
+			}
+			id := user.ID
+			user.ID = 0
+			user.Hash = uniuri.NewLen(32)
+			err = users.Create(ctx, user)
+			if err != nil {
+				render.InternalError(w, err)
+				logger.FromRequest(r).WithError(err).
+					WithField("user", user.Login).
+					Warnln("restore: cannot create user")
+				return
+			}
+			userIDs[id] = user.ID
+			out.Users.Created++
+		}
+
+		for _, repo := range in.Repos {
+			if repo.Repository == nil {
+				continue
+			}
+			_, err := repos.FindName(ctx, repo.Namespace, repo.Name)
+			if err == nil {
+				out.Repos.Skipped++
+				continue
+			}
+			repo.ID = 0
+			repo.UserID = userIDs[repo.UserID]
+			repo.Signer = uniuri.NewLen(32)
+			repo.Secret = uniuri.NewLen(32)
+			repo.Build = nil
+			repo.Perms = nil
+			err = repos.Create(ctx, repo.Repository)
+			if err != nil {
+				render.InternalError(w, err)
+				logger.FromRequest(r).WithError(err).
+					WithField("repo", repo.Slug).
+					Warnln("restore: cannot create repository")
+				return
+			}
+			out.Repos.Created++
+
+			for _, build := range repo.Builds {
+				err = restoreBuild(r, repo.Repository, build, builds, steps)
+				if err != nil {
+					render.InternalError(w, err)
+					logger.FromRequest(r).WithError(err).
+						WithField("repo", repo.Slug).
+						WithField("build", build.Number).
+						Warnln("restore: cannot create build")
+					return
+				}
+				out.Builds++
+			}
+
+			for _, cron := range repo.Cron {
+				cron.ID = 0
+				cron.RepoID = repo.ID
+				err = crons.Create(ctx, cron)
+				if err != nil {
+					render.InternalError(w, err)
+					logger.FromRequest(r).WithError(err).
+						WithField("repo", repo.Slug).
+						WithField("cron", cron.Name).
+						Warnln("restore: cannot create cron job")
+					return
+				}
+				out.Cron++
+			}
+		}
+
+		render.JSON(w, out, 200)
+	}
+}
+
+// restoreBuild creates the build, stages and steps.
+func restoreBuild(
+	r *http.Request,
+	repo *core.Repository,
+	build *core.Build,
+	builds core.BuildStore,
+	steps core.StepStore,
+) error {
+	ctx := r.Context()
+	stages := build.Stages
+	build.ID = 0
+	build.RepoID = repo.ID
+	build.Stages = nil
+	for _, stage := range stages {
+		stage.ID = 0
+		stage.RepoID = repo.ID
+	}
+	err := builds.Create(ctx, build, stages)
+	if err != nil {
+		return err
+	}
+	for _, stage := range stages {
+		for _, step := range stage.Steps {
+			step.ID = 0
+			step.StageID = stage.ID
+			err := steps.Create(ctx, step)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
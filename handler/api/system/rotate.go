@@ -0,0 +1,59 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package system
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/render"
+	"github.com/drone/drone/logger"
+)
+
+type rotateResult struct {
+	Rotated int64 `json:"rotated"`
+}
+
+// rotator is implemented by datastores that encrypt values
+// with the database encryption key.
+type rotator interface {
+	Rotate(context.Context) (int64, error)
+}
+
+// HandleRotate returns an http.HandlerFunc that encrypts all
+// secrets, registry credentials, repository webhooks,
+// notifications and variables with the current encryption
+// key. This completes a key rotation, after which the previous
+// encryption key can be removed from the server configuration.
+func HandleRotate(
+	secrets core.SecretStore,
+	registries core.RegistryStore,
+	webhooks core.RepoWebhookStore,
+	notifications core.NotificationStore,
+	variables core.VariableStore,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var total int64
+		for _, store := range []rotator{
+			secrets,
+			registries,
+			webhooks,
+			notifications,
+			variables,
+		} {
+			n, err := store.Rotate(r.Context())
+			total += n
+			if err != nil {
+				render.InternalError(w, err)
+				logger.FromRequest(r).WithError(err).
+					WithField("rotated", total).
+					Errorln("api: cannot rotate database encryption key")
+				return
+			}
+		}
+		render.JSON(w, &rotateResult{Rotated: total}, 200)
+	}
+}
@@ -0,0 +1,70 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package system
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/drone/drone/mock"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestHandleRotate(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	secrets := mock.NewMockSecretStore(controller)
+	secrets.EXPECT().Rotate(gomock.Any()).Return(int64(3), nil)
+
+	registries := mock.NewMockRegistryStore(controller)
+	registries.EXPECT().Rotate(gomock.Any()).Return(int64(1), nil)
+
+	webhooks := mock.NewMockRepoWebhookStore(controller)
+	webhooks.EXPECT().Rotate(gomock.Any()).Return(int64(2), nil)
+
+	notifications := mock.NewMockNotificationStore(controller)
+	notifications.EXPECT().Rotate(gomock.Any()).Return(int64(0), nil)
+
+	variables := mock.NewMockVariableStore(controller)
+	variables.EXPECT().Rotate(gomock.Any()).Return(int64(4), nil)
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/", nil)
+
+	HandleRotate(secrets, registries, webhooks, notifications, variables).ServeHTTP(w, r)
+	if got, want := w.Code, 200; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+
+	got, want := &rotateResult{}, &rotateResult{Rotated: 10}
+	json.NewDecoder(w.Body).Decode(got)
+	if diff := cmp.Diff(got, want); len(diff) != 0 {
+		t.Errorf(diff)
+	}
+}
+
+func TestHandleRotate_Error(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	secrets := mock.NewMockSecretStore(controller)
+	secrets.EXPECT().Rotate(gomock.Any()).Return(int64(3), nil)
+
+	registries := mock.NewMockRegistryStore(controller)
+	registries.EXPECT().Rotate(gomock.Any()).Return(int64(0), sql.ErrConnDone)
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/", nil)
+
+	HandleRotate(secrets, registries, nil, nil, nil).ServeHTTP(w, r)
+	if got, want := w.Code, 500; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+}
@@ -0,0 +1,70 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package user
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/request"
+	"github.com/drone/drone/mock"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestHandleRefresh(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	user := &core.User{ID: 1, Login: "octocat"}
+	mockOrgs := []*core.Organization{
+		{Name: "github", Avatar: "https://avatars.githubusercontent.com/u/9919"},
+	}
+
+	orgs := mock.NewMockOrganizationService(controller)
+	orgs.EXPECT().Refresh(gomock.Any(), user).Return(mockOrgs, nil)
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/api/user/refresh", nil)
+	r = r.WithContext(
+		request.WithUser(r.Context(), user),
+	)
+
+	HandleRefresh(orgs)(w, r)
+	if got, want := w.Code, 200; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+
+	got, want := []*core.Organization{}, mockOrgs
+	json.NewDecoder(w.Body).Decode(&got)
+	if diff := cmp.Diff(got, want); len(diff) != 0 {
+		t.Errorf(diff)
+	}
+}
+
+func TestHandleRefresh_Error(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	user := &core.User{ID: 1, Login: "octocat"}
+
+	orgs := mock.NewMockOrganizationService(controller)
+	orgs.EXPECT().Refresh(gomock.Any(), user).Return(nil, errors.New("rate limit exceeded"))
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/api/user/refresh", nil)
+	r = r.WithContext(
+		request.WithUser(r.Context(), user),
+	)
+
+	HandleRefresh(orgs)(w, r)
+	if got, want := w.Code, 500; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+}
@@ -16,6 +16,7 @@ package user
 
 import (
 	"net/http"
+	"strconv"
 
 	"github.com/drone/drone/core"
 	"github.com/drone/drone/handler/api/render"
@@ -24,17 +25,22 @@ import (
 )
 
 // HandleRepos returns an http.HandlerFunc that write a json-encoded
-// list of repositories to the response body.
+// list of repositories to the response body. If search or
+// pagination parameters are provided, the list is filtered and
+// paginated by the server.
 func HandleRepos(repos core.RepositoryStore) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		viewer, _ := request.UserFrom(r.Context())
 
 		var list []*core.Repository
 		var err error
-		if r.FormValue("latest") != "true" {
-			list, err = repos.List(r.Context(), viewer.ID)
-		} else {
+		switch {
+		case r.FormValue("latest") == "true":
 			list, err = repos.ListLatest(r.Context(), viewer.ID)
+		case isFiltered(r):
+			list, err = repos.ListFilter(r.Context(), viewer.ID, createFilter(r))
+		default:
+			list, err = repos.List(r.Context(), viewer.ID)
 		}
 		if err != nil {
 			render.InternalError(w, err)
@@ -45,3 +51,35 @@ func HandleRepos(repos core.RepositoryStore) http.HandlerFunc {
 		}
 	}
 }
+
+// isFiltered returns true if the request includes repository
+// search or pagination parameters.
+func isFiltered(r *http.Request) bool {
+	for _, key := range []string{"query", "namespace", "active", "page", "per_page"} {
+		if r.FormValue(key) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// createFilter creates a repository filter from the request
+// query parameters.
+func createFilter(r *http.Request) core.RepositoryFilter {
+	page, _ := strconv.Atoi(r.FormValue("page"))
+	limit, _ := strconv.Atoi(r.FormValue("per_page"))
+	if limit < 1 || limit > 100 {
+		limit = 25
+	}
+	offset := 0
+	if page > 1 {
+		offset = (page - 1) * limit
+	}
+	return core.RepositoryFilter{
+		Query:     r.FormValue("query"),
+		Namespace: r.FormValue("namespace"),
+		Active:    r.FormValue("active") == "true",
+		Limit:     limit,
+		Offset:    offset,
+	}
+}
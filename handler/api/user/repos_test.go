@@ -63,6 +63,52 @@ func TestResitoryList(t *testing.T) {
 	}
 }
 
+func TestResitoryListFilter(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockUser := &core.User{
+		ID:    1,
+		Login: "octocat",
+	}
+
+	mockRepos := []*core.Repository{
+		{
+			Namespace: "octocat",
+			Name:      "hello-world",
+			Slug:      "octocat/hello-world",
+		},
+	}
+
+	filter := core.RepositoryFilter{
+		Query:     "hello",
+		Namespace: "octocat",
+		Active:    true,
+		Limit:     50,
+		Offset:    50,
+	}
+
+	repos := mock.NewMockRepositoryStore(controller)
+	repos.EXPECT().ListFilter(gomock.Any(), mockUser.ID, filter).Return(mockRepos, nil)
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/?query=hello&namespace=octocat&active=true&page=2&per_page=50", nil)
+	r = r.WithContext(
+		request.WithUser(r.Context(), mockUser),
+	)
+
+	HandleRepos(repos)(w, r)
+	if got, want := w.Code, http.StatusOK; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+
+	got, want := []*core.Repository{}, mockRepos
+	json.NewDecoder(w.Body).Decode(&got)
+	if diff := cmp.Diff(got, want); len(diff) > 0 {
+		t.Errorf(diff)
+	}
+}
+
 func TestResitoryListErr(t *testing.T) {
 	controller := gomock.NewController(t)
 	defer controller.Finish()
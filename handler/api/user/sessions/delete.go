@@ -0,0 +1,88 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sessions
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/errors"
+	"github.com/drone/drone/handler/api/render"
+	"github.com/drone/drone/handler/api/request"
+	"github.com/drone/drone/logger"
+
+	"github.com/go-chi/chi"
+)
+
+// HandleDelete returns an http.HandlerFunc that processes an http.Request
+// to revoke a session of the currently authenticated user.
+func HandleDelete(sessions core.UserSessionStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		viewer, _ := request.UserFrom(r.Context())
+		deleteSession(w, r, sessions, viewer)
+	}
+}
+
+// HandleDeleteAll returns an http.HandlerFunc that processes an
+// http.Request to revoke all sessions of the currently authenticated
+// user, including the current session.
+func HandleDeleteAll(sessions core.UserSessionStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		viewer, _ := request.UserFrom(r.Context())
+		deleteSessions(w, r, sessions, viewer)
+	}
+}
+
+func deleteSession(w http.ResponseWriter, r *http.Request, sessions core.UserSessionStore, user *core.User) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "session"), 10, 64)
+	if err != nil {
+		render.BadRequest(w, err)
+		return
+	}
+	session, err := sessions.Find(r.Context(), id)
+	if err != nil {
+		render.NotFound(w, err)
+		logger.FromRequest(r).WithError(err).
+			Debugln("api: cannot find session")
+		return
+	}
+	// the session can only be revoked by its owner.
+	if session.UserID != user.ID {
+		render.NotFound(w, errors.ErrNotFound)
+		logger.FromRequest(r).
+			Debugln("api: session belongs to another user")
+		return
+	}
+	err = sessions.Delete(r.Context(), session)
+	if err != nil {
+		render.InternalError(w, err)
+		logger.FromRequest(r).WithError(err).
+			Warnln("api: cannot delete session")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func deleteSessions(w http.ResponseWriter, r *http.Request, sessions core.UserSessionStore, user *core.User) {
+	err := sessions.DeleteUser(r.Context(), user.ID)
+	if err != nil {
+		render.InternalError(w, err)
+		logger.FromRequest(r).WithError(err).
+			Warnln("api: cannot delete sessions")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,54 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sessions
+
+import (
+	"net/http"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/render"
+	"github.com/drone/drone/handler/api/request"
+	"github.com/drone/drone/logger"
+)
+
+// HandleList returns an http.HandlerFunc that writes a json-encoded
+// list of active sessions of the currently authenticated user to the
+// response body.
+func HandleList(sessions core.UserSessionStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		viewer, _ := request.UserFrom(r.Context())
+		listSessions(w, r, sessions, viewer)
+	}
+}
+
+// helper function writes the active sessions of the user to
+// the response. Expired sessions are purged on login and are
+// excluded from the results.
+func listSessions(w http.ResponseWriter, r *http.Request, sessions core.UserSessionStore, user *core.User) {
+	list, err := sessions.List(r.Context(), user.ID)
+	if err != nil {
+		render.InternalError(w, err)
+		logger.FromRequest(r).WithError(err).
+			Warnln("api: cannot list sessions")
+		return
+	}
+	active := []*core.UserSession{}
+	for _, session := range list {
+		if !session.Expired() {
+			active = append(active, session)
+		}
+	}
+	render.JSON(w, active, 200)
+}
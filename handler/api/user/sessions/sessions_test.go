@@ -0,0 +1,186 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package sessions
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/request"
+	"github.com/drone/drone/mock"
+
+	"github.com/go-chi/chi"
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+)
+
+var (
+	mockUser = &core.User{
+		ID:    1,
+		Login: "octocat",
+	}
+
+	mockSession = &core.UserSession{
+		ID:       1,
+		UserID:   1,
+		Hash:     "ulSxuA0FKjNiOFIchk18NNvC6ygSxdtKjiOAS",
+		Address:  "127.0.0.1",
+		Agent:    "Mozilla/5.0",
+		Created:  1524251054,
+		Accessed: 1524251054,
+	}
+)
+
+func TestHandleList(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	expired := &core.UserSession{ID: 2, UserID: 1, Expires: time.Now().Add(-time.Hour).Unix()}
+
+	sessions := mock.NewMockUserSessionStore(controller)
+	sessions.EXPECT().List(gomock.Any(), mockUser.ID).Return([]*core.UserSession{mockSession, expired}, nil)
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/", nil)
+	r = r.WithContext(
+		request.WithUser(r.Context(), mockUser),
+	)
+
+	HandleList(sessions)(w, r)
+	if got, want := w.Code, 200; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+
+	got, want := []*core.UserSession{}, []*core.UserSession{
+		{
+			ID:       mockSession.ID,
+			UserID:   mockSession.UserID,
+			Address:  mockSession.Address,
+			Agent:    mockSession.Agent,
+			Created:  mockSession.Created,
+			Accessed: mockSession.Accessed,
+		},
+	}
+	json.NewDecoder(w.Body).Decode(&got)
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf(diff)
+	}
+}
+
+func TestHandleDelete(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	sessions := mock.NewMockUserSessionStore(controller)
+	sessions.EXPECT().Find(gomock.Any(), mockSession.ID).Return(mockSession, nil)
+	sessions.EXPECT().Delete(gomock.Any(), mockSession).Return(nil)
+
+	c := new(chi.Context)
+	c.URLParams.Add("session", "1")
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("DELETE", "/", nil)
+	r = r.WithContext(
+		context.WithValue(request.WithUser(r.Context(), mockUser), chi.RouteCtxKey, c),
+	)
+
+	HandleDelete(sessions)(w, r)
+	if got, want := w.Code, 204; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+}
+
+func TestHandleDelete_NotOwner(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	sessions := mock.NewMockUserSessionStore(controller)
+	sessions.EXPECT().Find(gomock.Any(), mockSession.ID).Return(mockSession, nil)
+
+	c := new(chi.Context)
+	c.URLParams.Add("session", "1")
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("DELETE", "/", nil)
+	r = r.WithContext(
+		context.WithValue(request.WithUser(r.Context(), &core.User{ID: 2}), chi.RouteCtxKey, c),
+	)
+
+	HandleDelete(sessions)(w, r)
+	if got, want := w.Code, 404; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+}
+
+func TestHandleDeleteAll(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	sessions := mock.NewMockUserSessionStore(controller)
+	sessions.EXPECT().DeleteUser(gomock.Any(), mockUser.ID).Return(nil)
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("DELETE", "/", nil)
+	r = r.WithContext(
+		request.WithUser(r.Context(), mockUser),
+	)
+
+	HandleDeleteAll(sessions)(w, r)
+	if got, want := w.Code, 204; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+}
+
+func TestHandleUserDeleteAll(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	users := mock.NewMockUserStore(controller)
+	users.EXPECT().FindLogin(gomock.Any(), mockUser.Login).Return(mockUser, nil)
+
+	sessions := mock.NewMockUserSessionStore(controller)
+	sessions.EXPECT().DeleteUser(gomock.Any(), mockUser.ID).Return(nil)
+
+	c := new(chi.Context)
+	c.URLParams.Add("user", "octocat")
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("DELETE", "/", nil)
+	r = r.WithContext(
+		context.WithValue(context.Background(), chi.RouteCtxKey, c),
+	)
+
+	HandleUserDeleteAll(users, sessions)(w, r)
+	if got, want := w.Code, 204; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+}
+
+func TestHandleUserList_NotFound(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	users := mock.NewMockUserStore(controller)
+	users.EXPECT().FindLogin(gomock.Any(), mockUser.Login).Return(nil, sql.ErrNoRows)
+
+	c := new(chi.Context)
+	c.URLParams.Add("user", "octocat")
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/", nil)
+	r = r.WithContext(
+		context.WithValue(context.Background(), chi.RouteCtxKey, c),
+	)
+
+	HandleUserList(users, nil)(w, r)
+	if got, want := w.Code, 404; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+}
@@ -0,0 +1,72 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sessions
+
+import (
+	"net/http"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/render"
+	"github.com/drone/drone/logger"
+
+	"github.com/go-chi/chi"
+)
+
+// HandleUserList returns an http.HandlerFunc that writes a
+// json-encoded list of active sessions of the named user to
+// the response body.
+func HandleUserList(users core.UserStore, sessions core.UserSessionStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if user, ok := findUser(w, r, users); ok {
+			listSessions(w, r, sessions, user)
+		}
+	}
+}
+
+// HandleUserDelete returns an http.HandlerFunc that processes
+// an http.Request to revoke a session of the named user.
+func HandleUserDelete(users core.UserStore, sessions core.UserSessionStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if user, ok := findUser(w, r, users); ok {
+			deleteSession(w, r, sessions, user)
+		}
+	}
+}
+
+// HandleUserDeleteAll returns an http.HandlerFunc that processes
+// an http.Request to revoke all sessions of the named user.
+func HandleUserDeleteAll(users core.UserStore, sessions core.UserSessionStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if user, ok := findUser(w, r, users); ok {
+			deleteSessions(w, r, sessions, user)
+		}
+	}
+}
+
+// helper function returns the user account identified by the
+// user url parameter. If the account cannot be found an error
+// is written to the response.
+func findUser(w http.ResponseWriter, r *http.Request, users core.UserStore) (*core.User, bool) {
+	login := chi.URLParam(r, "user")
+	user, err := users.FindLogin(r.Context(), login)
+	if err != nil {
+		render.NotFound(w, err)
+		logger.FromRequest(r).WithError(err).
+			WithField("user", login).
+			Debugln("api: cannot find user")
+		return nil, false
+	}
+	return user, true
+}
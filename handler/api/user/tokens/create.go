@@ -0,0 +1,127 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tokens
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/errors"
+	"github.com/drone/drone/handler/api/render"
+	"github.com/drone/drone/handler/api/request"
+	"github.com/drone/drone/logger"
+
+	"github.com/dchest/uniuri"
+)
+
+var errScopeNotHeld = errors.New("Cannot grant a scope you do not hold")
+
+type tokenInput struct {
+	Name      string   `json:"name"`
+	Scopes    []string `json:"scopes"`
+	ExpiresIn int64    `json:"expires_in"`
+}
+
+type tokenWithValue struct {
+	*core.Token
+	Value string `json:"token"`
+}
+
+// HandleCreate returns an http.HandlerFunc that processes an http.Request
+// to create a personal access token for the currently authenticated user.
+// The token value is only included in this response.
+func HandleCreate(tokens core.TokenStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		viewer, _ := request.UserFrom(r.Context())
+		createToken(w, r, tokens, viewer)
+	}
+}
+
+// helper function creates a personal access token for the
+// user and writes the token to the response.
+func createToken(w http.ResponseWriter, r *http.Request, tokens core.TokenStore, user *core.User) {
+	in := new(tokenInput)
+	err := json.NewDecoder(r.Body).Decode(in)
+	if err != nil {
+		render.BadRequest(w, err)
+		logger.FromRequest(r).WithError(err).
+			Debugln("api: cannot unmarshal request body")
+		return
+	}
+
+	// the token cannot grant scopes that are not held by
+	// the authenticated user creating the token.
+	viewer, _ := request.UserFrom(r.Context())
+	for _, scope := range in.Scopes {
+		if !holdsScope(viewer, scope) {
+			render.Forbidden(w, errScopeNotHeld)
+			logger.FromRequest(r).
+				WithField("scope", scope).
+				Debugln("api: cannot grant scope not held")
+			return
+		}
+	}
+
+	// the token value is only returned in the response. The
+	// digest of the value is persisted, and is used to find
+	// the token when the user authenticates.
+	value := uniuri.NewLen(32)
+	token := &core.Token{
+		UserID:  user.ID,
+		Name:    in.Name,
+		Scopes:  in.Scopes,
+		Hash:    core.TokenDigest(value),
+		Created: time.Now().Unix(),
+	}
+	if in.ExpiresIn > 0 {
+		token.Expires = token.Created + in.ExpiresIn
+	}
+
+	err = token.Validate()
+	if err != nil {
+		render.BadRequest(w, err)
+		logger.FromRequest(r).WithError(err).
+			Debugln("api: invalid token input")
+		return
+	}
+
+	err = tokens.Create(r.Context(), token)
+	if err != nil {
+		render.InternalError(w, err)
+		logger.FromRequest(r).WithError(err).
+			Warnln("api: cannot create token")
+		return
+	}
+	render.JSON(w, &tokenWithValue{token, value}, 200)
+}
+
+// helper function returns true if the user holds the named
+// scope. Users that are not authenticated with a personal
+// access token hold all scopes, with the exception of the
+// system scope, which is only held by administrators.
+func holdsScope(user *core.User, scope string) bool {
+	switch {
+	case user == nil:
+		return false
+	case user.Scopes != nil:
+		return core.HasScope(user.Scopes, scope)
+	case scope == core.ScopeAdminSystem:
+		return user.Admin
+	default:
+		return true
+	}
+}
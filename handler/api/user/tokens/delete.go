@@ -0,0 +1,86 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tokens
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/errors"
+	"github.com/drone/drone/handler/api/render"
+	"github.com/drone/drone/handler/api/request"
+	"github.com/drone/drone/logger"
+
+	"github.com/go-chi/chi"
+)
+
+// HandleDelete returns an http.HandlerFunc that processes an http.Request
+// to revoke a personal access token of the currently authenticated user.
+func HandleDelete(tokens core.TokenStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		viewer, _ := request.UserFrom(r.Context())
+		deleteToken(w, r, tokens, viewer)
+	}
+}
+
+// helper function revokes the user personal access token
+// identified by the token url parameter.
+// HandleDeleteAll returns an http.HandlerFunc that processes
+// an http.Request to revoke all personal access tokens of the
+// currently authenticated user.
+func HandleDeleteAll(tokens core.TokenStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		viewer, _ := request.UserFrom(r.Context())
+		err := tokens.DeleteUser(r.Context(), viewer.ID)
+		if err != nil {
+			render.InternalError(w, err)
+			logger.FromRequest(r).WithError(err).
+				Warnln("api: cannot delete tokens")
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func deleteToken(w http.ResponseWriter, r *http.Request, tokens core.TokenStore, user *core.User) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "token"), 10, 64)
+	if err != nil {
+		render.BadRequest(w, err)
+		return
+	}
+	token, err := tokens.Find(r.Context(), id)
+	if err != nil {
+		render.NotFound(w, err)
+		logger.FromRequest(r).WithError(err).
+			Debugln("api: cannot find token")
+		return
+	}
+	// the token can only be revoked by its owner.
+	if token.UserID != user.ID {
+		render.NotFound(w, errors.ErrNotFound)
+		logger.FromRequest(r).
+			Debugln("api: token belongs to another user")
+		return
+	}
+	err = tokens.Delete(r.Context(), token)
+	if err != nil {
+		render.InternalError(w, err)
+		logger.FromRequest(r).WithError(err).
+			Warnln("api: cannot delete token")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,84 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tokens
+
+import (
+	"net/http"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/errors"
+	"github.com/drone/drone/handler/api/render"
+	"github.com/drone/drone/logger"
+
+	"github.com/go-chi/chi"
+)
+
+var errNotMachine = errors.New("Tokens can only be managed for machine accounts")
+
+// HandleMachineList returns an http.HandlerFunc that writes a
+// json-encoded list of personal access tokens for the named
+// machine account to the response body.
+func HandleMachineList(users core.UserStore, tokens core.TokenStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if user, ok := findMachine(w, r, users); ok {
+			listTokens(w, r, tokens, user)
+		}
+	}
+}
+
+// HandleMachineCreate returns an http.HandlerFunc that processes
+// an http.Request to create a personal access token for the named
+// machine account.
+func HandleMachineCreate(users core.UserStore, tokens core.TokenStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if user, ok := findMachine(w, r, users); ok {
+			createToken(w, r, tokens, user)
+		}
+	}
+}
+
+// HandleMachineDelete returns an http.HandlerFunc that processes
+// an http.Request to revoke a personal access token of the named
+// machine account.
+func HandleMachineDelete(users core.UserStore, tokens core.TokenStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if user, ok := findMachine(w, r, users); ok {
+			deleteToken(w, r, tokens, user)
+		}
+	}
+}
+
+// helper function returns the machine account identified by
+// the user url parameter. If the account cannot be found, or
+// is not a machine account, an error is written to the response.
+func findMachine(w http.ResponseWriter, r *http.Request, users core.UserStore) (*core.User, bool) {
+	login := chi.URLParam(r, "user")
+	user, err := users.FindLogin(r.Context(), login)
+	if err != nil {
+		render.NotFound(w, err)
+		logger.FromRequest(r).WithError(err).
+			WithField("user", login).
+			Debugln("api: cannot find user")
+		return nil, false
+	}
+	if !user.Machine {
+		render.BadRequest(w, errNotMachine)
+		logger.FromRequest(r).
+			WithField("user", login).
+			Debugln("api: cannot manage tokens of non-machine account")
+		return nil, false
+	}
+	return user, true
+}
@@ -0,0 +1,339 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package tokens
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/request"
+	"github.com/drone/drone/mock"
+
+	"github.com/go-chi/chi"
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+)
+
+var (
+	mockUser = &core.User{
+		ID:    1,
+		Login: "octocat",
+	}
+
+	mockToken = &core.Token{
+		ID:      1,
+		UserID:  1,
+		Name:    "ci",
+		Scopes:  []string{core.ScopeReadBuild},
+		Hash:    "ulSxuA0FKjNiOFIchk18NNvC6ygSxdtKjiOAS",
+		Created: 1524251054,
+	}
+)
+
+func TestHandleCreate(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	var stored *core.Token
+	tokens := mock.NewMockTokenStore(controller)
+	tokens.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, token *core.Token) error {
+			stored = token
+			return nil
+		},
+	)
+
+	in := new(bytes.Buffer)
+	json.NewEncoder(in).Encode(&tokenInput{
+		Name:      "ci",
+		Scopes:    []string{core.ScopeReadBuild},
+		ExpiresIn: 3600,
+	})
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/", in)
+	r = r.WithContext(
+		request.WithUser(r.Context(), mockUser),
+	)
+
+	HandleCreate(tokens)(w, r)
+	if got, want := w.Code, 200; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+
+	out := new(tokenWithValue)
+	json.NewDecoder(w.Body).Decode(out)
+	if out.Value == "" {
+		t.Errorf("Expect token value returned")
+	}
+	if got, want := out.Token.UserID, mockUser.ID; got != want {
+		t.Errorf("Want token user ID %d, got %d", want, got)
+	}
+	if got, want := out.Token.Expires-out.Token.Created, int64(3600); got != want {
+		t.Errorf("Want token expires in %d seconds, got %d", want, got)
+	}
+	// the token value is never persisted.
+	if got, want := stored.Hash, core.TokenDigest(out.Value); got != want {
+		t.Errorf("Want token digest %q persisted, got %q", want, got)
+	}
+}
+
+func TestHandleCreate_ScopeNotHeld(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	in := new(bytes.Buffer)
+	json.NewEncoder(in).Encode(&tokenInput{
+		Name:   "ci",
+		Scopes: []string{core.ScopeWriteBuild},
+	})
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/", in)
+	r = r.WithContext(
+		request.WithUser(r.Context(), &core.User{
+			ID:     1,
+			Login:  "octocat",
+			Scopes: []string{core.ScopeReadBuild},
+		}),
+	)
+
+	HandleCreate(nil)(w, r)
+	if got, want := w.Code, 403; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+}
+
+func TestHandleCreate_SystemScopeNotAdmin(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	in := new(bytes.Buffer)
+	json.NewEncoder(in).Encode(&tokenInput{
+		Name:   "ci",
+		Scopes: []string{core.ScopeAdminSystem},
+	})
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/", in)
+	r = r.WithContext(
+		request.WithUser(r.Context(), mockUser),
+	)
+
+	HandleCreate(nil)(w, r)
+	if got, want := w.Code, 403; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+}
+
+func TestHandleCreate_InvalidScope(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	in := new(bytes.Buffer)
+	json.NewEncoder(in).Encode(&tokenInput{
+		Name:   "ci",
+		Scopes: []string{"write:everything"},
+	})
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/", in)
+	r = r.WithContext(
+		request.WithUser(r.Context(), mockUser),
+	)
+
+	HandleCreate(nil)(w, r)
+	if got, want := w.Code, 400; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+}
+
+func TestHandleList(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	tokens := mock.NewMockTokenStore(controller)
+	tokens.EXPECT().List(gomock.Any(), mockUser.ID).Return([]*core.Token{mockToken}, nil)
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/", nil)
+	r = r.WithContext(
+		request.WithUser(r.Context(), mockUser),
+	)
+
+	HandleList(tokens)(w, r)
+	if got, want := w.Code, 200; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+
+	// the token hash is never included in the response.
+	got, want := []*core.Token{}, []*core.Token{
+		{
+			ID:      1,
+			UserID:  1,
+			Name:    "ci",
+			Scopes:  []string{core.ScopeReadBuild},
+			Created: 1524251054,
+		},
+	}
+	json.NewDecoder(w.Body).Decode(&got)
+	if diff := cmp.Diff(got, want); len(diff) != 0 {
+		t.Errorf(diff)
+	}
+}
+
+func TestHandleDelete(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	tokens := mock.NewMockTokenStore(controller)
+	tokens.EXPECT().Find(gomock.Any(), mockToken.ID).Return(mockToken, nil)
+	tokens.EXPECT().Delete(gomock.Any(), mockToken).Return(nil)
+
+	c := new(chi.Context)
+	c.URLParams.Add("token", "1")
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("DELETE", "/", nil)
+	r = r.WithContext(
+		context.WithValue(request.WithUser(r.Context(), mockUser), chi.RouteCtxKey, c),
+	)
+
+	HandleDelete(tokens)(w, r)
+	if got, want := w.Code, 204; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+}
+
+func TestHandleDeleteAll(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	tokens := mock.NewMockTokenStore(controller)
+	tokens.EXPECT().DeleteUser(gomock.Any(), mockUser.ID).Return(nil)
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("DELETE", "/", nil)
+	r = r.WithContext(
+		request.WithUser(r.Context(), mockUser),
+	)
+
+	HandleDeleteAll(tokens)(w, r)
+	if got, want := w.Code, 204; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+}
+
+func TestHandleDelete_NotOwner(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	tokens := mock.NewMockTokenStore(controller)
+	tokens.EXPECT().Find(gomock.Any(), mockToken.ID).Return(mockToken, nil)
+
+	c := new(chi.Context)
+	c.URLParams.Add("token", "1")
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("DELETE", "/", nil)
+	r = r.WithContext(
+		context.WithValue(request.WithUser(r.Context(), &core.User{ID: 2}), chi.RouteCtxKey, c),
+	)
+
+	HandleDelete(tokens)(w, r)
+	if got, want := w.Code, 404; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+}
+
+func TestHandleDelete_NotFound(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	tokens := mock.NewMockTokenStore(controller)
+	tokens.EXPECT().Find(gomock.Any(), mockToken.ID).Return(nil, sql.ErrNoRows)
+
+	c := new(chi.Context)
+	c.URLParams.Add("token", "1")
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("DELETE", "/", nil)
+	r = r.WithContext(
+		context.WithValue(request.WithUser(r.Context(), mockUser), chi.RouteCtxKey, c),
+	)
+
+	HandleDelete(tokens)(w, r)
+	if got, want := w.Code, 404; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+}
+
+func TestHandleMachineCreate(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	machine := &core.User{ID: 2, Login: "deploybot", Machine: true}
+
+	users := mock.NewMockUserStore(controller)
+	users.EXPECT().FindLogin(gomock.Any(), machine.Login).Return(machine, nil)
+
+	tokens := mock.NewMockTokenStore(controller)
+	tokens.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+
+	c := new(chi.Context)
+	c.URLParams.Add("user", "deploybot")
+
+	in := new(bytes.Buffer)
+	json.NewEncoder(in).Encode(&tokenInput{
+		Name:   "deploy",
+		Scopes: []string{core.ScopeWriteBuild},
+	})
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/", in)
+	r = r.WithContext(
+		context.WithValue(request.WithUser(r.Context(), mockUser), chi.RouteCtxKey, c),
+	)
+
+	HandleMachineCreate(users, tokens)(w, r)
+	if got, want := w.Code, 200; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+
+	// the token is owned by the machine account, not the
+	// administrator that created the token.
+	out := new(tokenWithValue)
+	json.NewDecoder(w.Body).Decode(out)
+	if got, want := out.Token.UserID, machine.ID; got != want {
+		t.Errorf("Want token user ID %d, got %d", want, got)
+	}
+}
+
+func TestHandleMachineList_NotMachine(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	users := mock.NewMockUserStore(controller)
+	users.EXPECT().FindLogin(gomock.Any(), mockUser.Login).Return(mockUser, nil)
+
+	c := new(chi.Context)
+	c.URLParams.Add("user", "octocat")
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/", nil)
+	r = r.WithContext(
+		context.WithValue(r.Context(), chi.RouteCtxKey, c),
+	)
+
+	HandleMachineList(users, nil)(w, r)
+	if got, want := w.Code, 400; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+}
@@ -0,0 +1,76 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package variables
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/render"
+	"github.com/drone/drone/logger"
+)
+
+type variableInput struct {
+	Namespace string `json:"namespace"`
+	Repo      string `json:"repo"`
+	Name      string `json:"name"`
+	Value     string `json:"value"`
+	Secret    bool   `json:"secret"`
+}
+
+// HandleCreate returns an http.HandlerFunc that processes http
+// requests to create a variable. The variable is defined for the
+// repository if a repository slug is provided, for the organization
+// if a namespace is provided, and for the server otherwise.
+func HandleCreate(repos core.RepositoryStore, variables core.VariableStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		in := new(variableInput)
+		err := json.NewDecoder(r.Body).Decode(in)
+		if err != nil {
+			render.BadRequest(w, err)
+			return
+		}
+
+		variable := &core.Variable{
+			Namespace: in.Namespace,
+			Name:      in.Name,
+			Value:     in.Value,
+			Secret:    in.Secret,
+			Created:   time.Now().Unix(),
+			Updated:   time.Now().Unix(),
+		}
+		if in.Repo != "" {
+			parts := strings.SplitN(in.Repo, "/", 2)
+			if len(parts) != 2 {
+				render.BadRequestf(w, "Invalid repository slug %s", in.Repo)
+				return
+			}
+			repo, err := repos.FindName(r.Context(), parts[0], parts[1])
+			if err != nil {
+				render.NotFound(w, err)
+				return
+			}
+			variable.RepoID = repo.ID
+		}
+
+		err = variable.Validate()
+		if err != nil {
+			render.BadRequest(w, err)
+			return
+		}
+
+		err = variables.Create(r.Context(), variable)
+		if err != nil {
+			render.InternalError(w, err)
+			logger.FromRequest(r).WithError(err).
+				Warnln("api: cannot create variable")
+			return
+		}
+		render.JSON(w, variable.Copy(), 200)
+	}
+}
@@ -0,0 +1,76 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package variables
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/mock"
+
+	"github.com/golang/mock/gomock"
+)
+
+func TestHandleCreate_Repo(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockRepo := &core.Repository{ID: 1, Namespace: "octocat", Name: "hello-world"}
+
+	repos := mock.NewMockRepositoryStore(controller)
+	repos.EXPECT().FindName(gomock.Any(), "octocat", "hello-world").Return(mockRepo, nil)
+
+	variables := mock.NewMockVariableStore(controller)
+	variables.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+
+	in := new(bytes.Buffer)
+	json.NewEncoder(in).Encode(map[string]interface{}{
+		"repo":   "octocat/hello-world",
+		"name":   "NPM_TOKEN",
+		"value":  "correct-horse-battery-staple",
+		"secret": true,
+	})
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/", in)
+
+	HandleCreate(repos, variables).ServeHTTP(w, r)
+	if got, want := w.Code, http.StatusOK; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+
+	got := new(core.Variable)
+	json.NewDecoder(w.Body).Decode(got)
+	if got, want := got.RepoID, mockRepo.ID; got != want {
+		t.Errorf("Want repository ID %d, got %d", want, got)
+	}
+	if got.Value != "" {
+		t.Errorf("Expect secret variable value removed from the response")
+	}
+}
+
+func TestHandleCreate_ValidationError(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	in := new(bytes.Buffer)
+	json.NewEncoder(in).Encode(map[string]interface{}{
+		"name": "GO PROXY",
+	})
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/", in)
+
+	repos := mock.NewMockRepositoryStore(controller)
+	variables := mock.NewMockVariableStore(controller)
+	HandleCreate(repos, variables).ServeHTTP(w, r)
+	if got, want := w.Code, http.StatusBadRequest; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+}
@@ -0,0 +1,73 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package variables
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/render"
+	"github.com/drone/drone/logger"
+
+	"github.com/go-chi/chi"
+)
+
+type variableUpdate struct {
+	Name   *string `json:"name"`
+	Value  *string `json:"value"`
+	Secret *bool   `json:"secret"`
+}
+
+// HandleUpdate returns an http.HandlerFunc that processes http
+// requests to update a variable.
+func HandleUpdate(variables core.VariableStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		in := new(variableUpdate)
+		err := json.NewDecoder(r.Body).Decode(in)
+		if err != nil {
+			render.BadRequest(w, err)
+			return
+		}
+
+		id, err := strconv.ParseInt(chi.URLParam(r, "variable"), 10, 64)
+		if err != nil {
+			render.BadRequest(w, err)
+			return
+		}
+		variable, err := variables.Find(r.Context(), id)
+		if err != nil {
+			render.NotFound(w, err)
+			return
+		}
+		if in.Name != nil {
+			variable.Name = *in.Name
+		}
+		if in.Value != nil {
+			variable.Value = *in.Value
+		}
+		if in.Secret != nil {
+			variable.Secret = *in.Secret
+		}
+		variable.Updated = time.Now().Unix()
+
+		err = variable.Validate()
+		if err != nil {
+			render.BadRequest(w, err)
+			return
+		}
+
+		err = variables.Update(r.Context(), variable)
+		if err != nil {
+			render.InternalError(w, err)
+			logger.FromRequest(r).WithError(err).
+				Warnln("api: cannot update variable")
+			return
+		}
+		render.JSON(w, variable.Copy(), 200)
+	}
+}
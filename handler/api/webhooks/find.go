@@ -0,0 +1,39 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package webhooks
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/render"
+	"github.com/drone/drone/logger"
+
+	"github.com/go-chi/chi"
+)
+
+// HandleFind returns an http.HandlerFunc that writes a json-encoded
+// webhook delivery, including the delivery attempt history, to the
+// response body.
+func HandleFind(deliveries core.WebhookDeliveryStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.ParseInt(chi.URLParam(r, "delivery"), 10, 64)
+		if err != nil {
+			render.BadRequest(w, err)
+			return
+		}
+		delivery, err := deliveries.Find(r.Context(), id)
+		if err != nil {
+			render.NotFound(w, err)
+			logger.FromRequest(r).
+				WithError(err).
+				WithField("delivery", id).
+				Debugln("api: cannot find webhook delivery")
+		} else {
+			render.JSON(w, delivery, 200)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package webhooks
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/render"
+	"github.com/drone/drone/logger"
+)
+
+// HandleList returns an http.HandlerFunc that writes a json-encoded
+// list of webhook deliveries to the response body. The list can be
+// filtered by status, for example to list failed deliveries in the
+// dead-letter list.
+func HandleList(deliveries core.WebhookDeliveryStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		page, _ := strconv.Atoi(r.FormValue("page"))
+		limit, _ := strconv.Atoi(r.FormValue("per_page"))
+		if limit < 1 || limit > 100 {
+			limit = 25
+		}
+		offset := 0
+		if page > 1 {
+			offset = (page - 1) * limit
+		}
+
+		filter := core.WebhookDeliveryFilter{
+			Status: r.FormValue("status"),
+			Limit:  limit,
+			Offset: offset,
+		}
+		list, err := deliveries.List(r.Context(), filter)
+		if err != nil {
+			render.InternalError(w, err)
+			logger.FromRequest(r).
+				WithError(err).
+				Debugln("api: cannot list webhook deliveries")
+		} else {
+			render.JSON(w, list, 200)
+		}
+	}
+}
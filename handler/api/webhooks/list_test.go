@@ -0,0 +1,79 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package webhooks
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/mock"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+)
+
+var mockDeliveries = []*core.WebhookDelivery{
+	{
+		ID:       1,
+		Endpoint: "https://company.com/hooks",
+		Event:    core.WebhookEventUser,
+		Action:   core.WebhookActionCreated,
+		Payload:  `{"action":"created"}`,
+		Status:   core.DeliveryFailed,
+		Attempts: 5,
+		History: []*core.WebhookAttempt{
+			{Code: 502, Error: "webhook: unexpected status code 502", Created: 1524251054},
+		},
+		Created: 1524251054,
+		Updated: 1524251054,
+	},
+}
+
+func TestHandleList(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	filter := core.WebhookDeliveryFilter{
+		Status: core.DeliveryFailed,
+		Limit:  10,
+		Offset: 10,
+	}
+
+	deliveries := mock.NewMockWebhookDeliveryStore(controller)
+	deliveries.EXPECT().List(gomock.Any(), filter).Return(mockDeliveries, nil)
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/?status=failed&page=2&per_page=10", nil)
+
+	HandleList(deliveries)(w, r)
+	if got, want := w.Code, 200; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+
+	got, want := []*core.WebhookDelivery{}, mockDeliveries
+	json.NewDecoder(w.Body).Decode(&got)
+	if diff := cmp.Diff(got, want); len(diff) != 0 {
+		t.Errorf(diff)
+	}
+}
+
+func TestHandleList_Err(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	deliveries := mock.NewMockWebhookDeliveryStore(controller)
+	deliveries.EXPECT().List(gomock.Any(), core.WebhookDeliveryFilter{Limit: 25}).Return(nil, sql.ErrNoRows)
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/", nil)
+
+	HandleList(deliveries)(w, r)
+	if got, want := w.Code, 500; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+}
@@ -5,16 +5,35 @@
 package web
 
 import (
-	"io"
 	"net/http"
+
+	"github.com/drone/drone/core"
 )
 
 // HandleHealthz creates an http.HandlerFunc that performs system
-// healthchecks and returns 500 if the system is in an unhealthy state.
-func HandleHealthz() http.HandlerFunc {
+// healthchecks and returns 503 if the system is in an unhealthy state.
+// The healthchecks are limited to the internal system components.
+func HandleHealthz(health core.HealthService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		writeHealth(w, health.Live(r.Context()))
+	}
+}
+
+// HandleReadyz creates an http.HandlerFunc that performs system
+// healthchecks, including the external dependencies, and returns
+// 503 if the system is not ready to serve traffic.
+func HandleReadyz(health core.HealthService) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(200)
-		w.Header().Set("Content-Type", "text/plain")
-		io.WriteString(w, "OK")
+		writeHealth(w, health.Ready(r.Context()))
+	}
+}
+
+// helper function writes the json-encoded health status to
+// the response with a 503 status code if unhealthy.
+func writeHealth(w http.ResponseWriter, health *core.Health) {
+	if health.OK() {
+		writeJSON(w, health, 200)
+	} else {
+		writeJSON(w, health, 503)
 	}
 }
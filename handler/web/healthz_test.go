@@ -5,17 +5,93 @@
 package web
 
 import (
+	"encoding/json"
 	"net/http/httptest"
 	"testing"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/mock"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+)
+
+var (
+	mockHealthy = &core.Health{
+		Status: core.HealthOK,
+		Components: []*core.HealthComponent{
+			{Name: "database", Status: core.HealthOK},
+		},
+	}
+
+	mockUnhealthy = &core.Health{
+		Status: core.HealthError,
+		Components: []*core.HealthComponent{
+			{Name: "database", Status: core.HealthOK},
+			{Name: "scm", Status: core.HealthError, Error: "connection refused"},
+		},
+	}
 )
 
 func TestHandleHealthz(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	health := mock.NewMockHealthService(controller)
+	health.EXPECT().Live(gomock.Any()).Return(mockHealthy)
+
 	w := httptest.NewRecorder()
 	r := httptest.NewRequest("GET", "/healthz", nil)
 
-	HandleHealthz().ServeHTTP(w, r)
+	HandleHealthz(health).ServeHTTP(w, r)
+
+	if got, want := w.Code, 200; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+
+	got, want := new(core.Health), mockHealthy
+	json.NewDecoder(w.Body).Decode(got)
+	if diff := cmp.Diff(got, want); len(diff) != 0 {
+		t.Errorf(diff)
+	}
+}
+
+func TestHandleReadyz(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	health := mock.NewMockHealthService(controller)
+	health.EXPECT().Ready(gomock.Any()).Return(mockHealthy)
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/readyz", nil)
+
+	HandleReadyz(health).ServeHTTP(w, r)
 
 	if got, want := w.Code, 200; want != got {
 		t.Errorf("Want response code %d, got %d", want, got)
 	}
 }
+
+func TestHandleReadyz_Unhealthy(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	health := mock.NewMockHealthService(controller)
+	health.EXPECT().Ready(gomock.Any()).Return(mockUnhealthy)
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/readyz", nil)
+
+	HandleReadyz(health).ServeHTTP(w, r)
+
+	if got, want := w.Code, 503; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+
+	got, want := new(core.Health), mockUnhealthy
+	json.NewDecoder(w.Body).Decode(got)
+	if diff := cmp.Diff(got, want); len(diff) != 0 {
+		t.Errorf(diff)
+	}
+}
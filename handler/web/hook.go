@@ -5,7 +5,9 @@
 package web
 
 import (
+	"bytes"
 	"context"
+	"io/ioutil"
 	"net/http"
 	"net/http/httputil"
 	"os"
@@ -16,6 +18,7 @@ import (
 
 	"github.com/drone/drone/logger"
 	"github.com/drone/drone/core"
+	"github.com/drone/drone/metric"
 	"github.com/drone/go-scm/scm"
 )
 
@@ -30,12 +33,16 @@ func init() {
 }
 
 // HandleHook returns an http.HandlerFunc that handles webhooks
-// triggered by source code management.
+// triggered by source code management. The raw payload is
+// stored with the processing result, so that it can be
+// inspected and re-processed by a repository administrator.
 func HandleHook(
 	repos core.RepositoryStore,
 	builds core.BuildStore,
 	triggerer core.Triggerer,
+	commands core.CommandService,
 	parser core.HookParser,
+	payloads core.HookPayloadStore,
 ) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 
@@ -46,6 +53,34 @@ func HandleHook(
 			os.Stderr.Write(out)
 		}
 
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			logrus.Debugf("cannot read webhook: %s", err)
+			writeBadRequest(w, err)
+			return
+		}
+		r.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+		// the payload is stored for the repository that is
+		// looked up when the hook signature is verified, so
+		// that payloads that cannot be parsed are retained.
+		var found *core.Repository
+		payload := &core.HookPayload{
+			Header: copyHeader(r.Header),
+			Body:   string(body),
+		}
+		record := func(status, message string) {
+			if found == nil {
+				return
+			}
+			payload.RepoID = found.ID
+			payload.Status = status
+			payload.Message = message
+			if err := payloads.Create(context.Background(), payload); err != nil {
+				logrus.WithError(err).Warnln("cannot store webhook payload")
+			}
+		}
+
 		hook, remote, err := parser.Parse(r, func(slug string) string {
 			namespace, name := scm.Split(slug)
 			repo, err := repos.FindName(r.Context(), namespace, name)
@@ -57,20 +92,43 @@ func HandleHook(
 					}).Debugln("cannot find repository")
 				return ""
 			}
+			found = repo
 			return repo.Signer
 		})
 
+		// the previous secret remains valid for a grace period
+		// after the secret is rotated, so that webhooks already
+		// in flight, or sent before the source control management
+		// system applies the new secret, are not rejected.
+		if err == scm.ErrSignatureInvalid && isSignerGrace(found) {
+			r.Body = ioutil.NopCloser(bytes.NewReader(body))
+			hook, remote, err = parser.Parse(r, func(slug string) string {
+				if slug != found.Slug {
+					return ""
+				}
+				return found.SignerPrev
+			})
+		}
+
 		if err != nil {
 			logrus.Debugf("cannot parse webhook: %s", err)
+			metric.HookRejected(providerName(r), rejectReason(err, found))
+			record(core.PayloadError, err.Error())
 			writeBadRequest(w, err)
 			return
 		}
 
 		if hook == nil {
 			logrus.Debugf("webhook ignored")
+			record(core.PayloadIgnored, "webhook ignored")
 			return
 		}
 
+		payload.Event = hook.Event
+		payload.Action = hook.Action
+		payload.Ref = hook.Ref
+		payload.After = hook.After
+
 		// TODO handle ping requests
 		// TODO consider using scm.Repository in the function callback.
 
@@ -230,9 +288,11 @@ func HandleHook(
 			writeNotFound(w, err)
 			return
 		}
+		found = repo
 
 		if !repo.Active {
 			log.Debugln("ignore webhook, repository inactive")
+			record(core.PayloadIgnored, "repository inactive")
 			w.WriteHeader(200)
 			return
 		}
@@ -241,12 +301,84 @@ func HandleHook(
 		ctx = logger.WithContext(ctx, log)
 		defer cancel()
 
+		// pull request comments never trigger a build directly,
+		// but may contain a command to restart or promote the
+		// most recent pull request build.
+		if hook.Action == core.ActionComment {
+			build, err := commands.Exec(ctx, repo, hook)
+			if err != nil {
+				record(core.PayloadError, err.Error())
+				writeError(w, err)
+				return
+			}
+			if build == nil {
+				record(core.PayloadIgnored, "no build created")
+				w.WriteHeader(200)
+				return
+			}
+			payload.Build = build.Number
+			record(core.PayloadSuccess, "")
+			writeJSON(w, build, 200)
+			return
+		}
+
 		builds, err := triggerer.Trigger(ctx, repo, hook)
 		if err != nil {
+			record(core.PayloadError, err.Error())
 			writeError(w, err)
 			return
 		}
+		if builds == nil {
+			record(core.PayloadIgnored, "no build created")
+		} else {
+			payload.Build = builds.Number
+			record(core.PayloadSuccess, "")
+		}
 
 		writeJSON(w, builds, 200)
 	}
 }
+
+// helper function returns true if the previous repository
+// secret is within the rotation grace period.
+func isSignerGrace(repo *core.Repository) bool {
+	return repo != nil &&
+		repo.SignerPrev != "" &&
+		repo.SignerExpiry > time.Now().Unix()
+}
+
+// helper function returns the name of the provider that
+// delivered the webhook, used to label rejection metrics.
+func providerName(r *http.Request) string {
+	if name := r.URL.Query().Get("provider"); name != "" {
+		return name
+	}
+	return "default"
+}
+
+// helper function returns the reason the webhook was
+// rejected, used to label rejection metrics.
+func rejectReason(err error, repo *core.Repository) string {
+	switch {
+	case err == scm.ErrSignatureInvalid:
+		return "signature"
+	case repo == nil:
+		return "repository"
+	default:
+		return "malformed"
+	}
+}
+
+// helper function returns a copy of the hook headers,
+// excluding credentials that may be added by a proxy.
+func copyHeader(in http.Header) http.Header {
+	out := http.Header{}
+	for key, value := range in {
+		switch http.CanonicalHeaderKey(key) {
+		case "Authorization", "Cookie":
+			continue
+		}
+		out[key] = value
+	}
+	return out
+}
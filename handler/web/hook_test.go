@@ -0,0 +1,129 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package web
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/mock"
+	"github.com/drone/go-scm/scm"
+
+	"github.com/golang/mock/gomock"
+)
+
+// this test verifies that the webhook signature is verified
+// with the previous repository secret during the rotation
+// grace period.
+func TestHandleHook_SignerGrace(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	repo := &core.Repository{
+		ID:           1,
+		Namespace:    "octocat",
+		Name:         "hello-world",
+		Slug:         "octocat/hello-world",
+		Signer:       "correct-horse-battery-staple",
+		SignerPrev:   "horse-battery-staple",
+		SignerExpiry: time.Now().Add(time.Hour).Unix(),
+	}
+	hook := &core.Hook{
+		Event: core.EventPush,
+		After: "7fd1a60b01f91b314f59955a4e4d4e80d8edf11d",
+	}
+
+	repos := mock.NewMockRepositoryStore(controller)
+	repos.EXPECT().FindName(gomock.Any(), "octocat", "hello-world").Return(repo, nil).Times(2)
+
+	parser := mock.NewMockHookParser(controller)
+	gomock.InOrder(
+		parser.EXPECT().Parse(gomock.Any(), gomock.Any()).DoAndReturn(func(_ *http.Request, fn func(string) string) (*core.Hook, *core.Repository, error) {
+			if got, want := fn(repo.Slug), repo.Signer; got != want {
+				t.Errorf("Want signer %q, got %q", want, got)
+			}
+			return nil, nil, scm.ErrSignatureInvalid
+		}),
+		parser.EXPECT().Parse(gomock.Any(), gomock.Any()).DoAndReturn(func(_ *http.Request, fn func(string) string) (*core.Hook, *core.Repository, error) {
+			if got, want := fn(repo.Slug), repo.SignerPrev; got != want {
+				t.Errorf("Want previous signer %q, got %q", want, got)
+			}
+			if got := fn("spaceghost/hello-world"); got != "" {
+				t.Errorf("Want previous signer limited to the repository, got %q", got)
+			}
+			return hook, repo, nil
+		}),
+	)
+
+	payloads := mock.NewMockHookPayloadStore(controller)
+	payloads.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/hook", strings.NewReader("{}"))
+
+	HandleHook(repos, nil, nil, nil, parser, payloads).ServeHTTP(w, r)
+	if got, want := w.Code, 200; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+}
+
+// this test verifies that the previous repository secret is
+// rejected once the rotation grace period expires.
+func TestHandleHook_SignerExpired(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	repo := &core.Repository{
+		ID:           1,
+		Namespace:    "octocat",
+		Name:         "hello-world",
+		Slug:         "octocat/hello-world",
+		Signer:       "correct-horse-battery-staple",
+		SignerPrev:   "horse-battery-staple",
+		SignerExpiry: time.Now().Add(-time.Hour).Unix(),
+	}
+
+	repos := mock.NewMockRepositoryStore(controller)
+	repos.EXPECT().FindName(gomock.Any(), "octocat", "hello-world").Return(repo, nil)
+
+	parser := mock.NewMockHookParser(controller)
+	parser.EXPECT().Parse(gomock.Any(), gomock.Any()).DoAndReturn(func(_ *http.Request, fn func(string) string) (*core.Hook, *core.Repository, error) {
+		fn(repo.Slug)
+		return nil, nil, scm.ErrSignatureInvalid
+	})
+
+	payloads := mock.NewMockHookPayloadStore(controller)
+	payloads.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/hook", strings.NewReader("{}"))
+
+	HandleHook(repos, nil, nil, nil, parser, payloads).ServeHTTP(w, r)
+	if got, want := w.Code, 400; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+}
+
+func Test_rejectReason(t *testing.T) {
+	tests := []struct {
+		err    error
+		repo   *core.Repository
+		reason string
+	}{
+		{scm.ErrSignatureInvalid, &core.Repository{}, "signature"},
+		{scm.ErrSignatureInvalid, nil, "signature"},
+		{errNotFound, nil, "repository"},
+		{errNotFound, &core.Repository{}, "malformed"},
+	}
+	for _, test := range tests {
+		if got, want := rejectReason(test.err, test.repo), test.reason; got != want {
+			t.Errorf("Want reason %q for error %q, got %q", want, test.err, got)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package web
+
+import (
+	"net/http"
+
+	"github.com/drone/drone/core"
+)
+
+type discovery struct {
+	Issuer          string   `json:"issuer"`
+	JWKSURI         string   `json:"jwks_uri"`
+	ResponseTypes   []string `json:"response_types_supported"`
+	SubjectTypes    []string `json:"subject_types_supported"`
+	SigningAlgs     []string `json:"id_token_signing_alg_values_supported"`
+	ScopesSupported []string `json:"scopes_supported"`
+	ClaimsSupported []string `json:"claims_supported"`
+}
+
+// HandleDiscovery creates an http.HandlerFunc that writes the
+// OpenID Connect discovery document for build identity tokens,
+// which cloud providers use to locate the signing keys.
+func HandleDiscovery(idtokens core.IDTokenService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		issuer := idtokens.Issuer()
+		writeJSON(w, &discovery{
+			Issuer:          issuer,
+			JWKSURI:         issuer + "/.well-known/jwks",
+			ResponseTypes:   []string{"id_token"},
+			SubjectTypes:    []string{"public"},
+			SigningAlgs:     []string{"RS256"},
+			ScopesSupported: []string{"openid"},
+			ClaimsSupported: []string{
+				"iss", "sub", "aud", "iat", "nbf", "exp", "jti",
+				"repo", "namespace", "branch", "ref", "event",
+				"commit", "build", "stage", "deploy", "trusted",
+			},
+		}, 200)
+	}
+}
+
+// HandleJWKS creates an http.HandlerFunc that writes the public
+// keys used to verify build identity tokens.
+func HandleJWKS(idtokens core.IDTokenService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		keys, err := idtokens.Keys(r.Context())
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, keys, 200)
+	}
+}
@@ -10,9 +10,11 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/web/oidc"
 	"github.com/drone/drone/logger"
 	"github.com/drone/go-login/login"
 
@@ -60,6 +62,15 @@ func HandleLogin(
 		logger := logrus.WithField("login", account.Login)
 		logger.Debugf("attempting authentication")
 
+		// if single sign-on is enabled, the source code management
+		// account must match the identity authenticated by the
+		// identity provider.
+		if identity, ok := oidc.IdentityFrom(ctx); ok && !matchIdentity(identity, account) {
+			writeLoginErrorStr(w, r, "Account does not match the single sign-on identity")
+			logger.Errorf("cannot match single sign-on identity %s", identity)
+			return
+		}
+
 		user, err := users.FindLogin(ctx, account.Login)
 		if err == sql.ErrNoRows {
 			user = &core.User{
@@ -152,9 +163,15 @@ func HandleLogin(
 			go synchornize(ctx, syncer, user)
 		}
 
+		err = session.Create(w, r, user)
+		if err != nil {
+			writeLoginError(w, r, err)
+			logger.Errorf("cannot create session: %s", err)
+			return
+		}
+
 		logger.Debugf("authentication successful")
 
-		session.Create(w, user)
 		http.Redirect(w, r, "/", 303)
 	}
 }
@@ -174,6 +191,19 @@ func synchornize(ctx context.Context, syncer core.Syncer, user *core.User) {
 	}
 }
 
+// helper function returns true if the identity matches the
+// account username or email address.
+func matchIdentity(identity string, account *core.User) bool {
+	switch {
+	case strings.EqualFold(identity, account.Login):
+		return true
+	case account.Email != "" && strings.EqualFold(identity, account.Email):
+		return true
+	default:
+		return false
+	}
+}
+
 func writeLoginError(w http.ResponseWriter, r *http.Request, err error) {
 	http.Redirect(w, r, "/login/error?message="+err.Error(), 303)
 }
@@ -3,3 +3,28 @@
 // that can be found in the LICENSE file.
 
 package web
+
+import (
+	"testing"
+
+	"github.com/drone/drone/core"
+)
+
+func TestMatchIdentity(t *testing.T) {
+	account := &core.User{Login: "octocat", Email: "octocat@github.com"}
+	tests := []struct {
+		identity string
+		match    bool
+	}{
+		{"octocat", true},
+		{"OctoCat", true},
+		{"octocat@github.com", true},
+		{"spaceghost", false},
+		{"", false},
+	}
+	for _, test := range tests {
+		if got, want := matchIdentity(test.identity, account), test.match; got != want {
+			t.Errorf("Want identity %q match %v, got %v", test.identity, want, got)
+		}
+	}
+}
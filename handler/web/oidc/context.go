@@ -0,0 +1,23 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package oidc
+
+import "context"
+
+type key int
+
+const identityKey key = iota
+
+// WithIdentity returns a parent context with the identity.
+func WithIdentity(parent context.Context, identity string) context.Context {
+	return context.WithValue(parent, identityKey, identity)
+}
+
+// IdentityFrom returns the identity authenticated by the
+// identity provider from the context.
+func IdentityFrom(ctx context.Context) (string, bool) {
+	identity, ok := ctx.Value(identityKey).(string)
+	return identity, ok
+}
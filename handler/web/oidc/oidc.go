@@ -0,0 +1,250 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+// Package oidc provides single sign-on using an OpenID Connect
+// identity provider, such as Okta or Keycloak. The identity
+// provider gates access to the system, and the authenticated
+// identity is mapped to the source code management account,
+// which continues to provide the repository permissions.
+package oidc
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dchest/authcookie"
+	"github.com/dchest/uniuri"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	stateCookie    = "_oidc_state_"
+	identityCookie = "_oidc_identity_"
+	cookiePath     = "/login"
+)
+
+// stateTimeout is the maximum time allowed to authenticate
+// with the identity provider.
+var stateTimeout = time.Minute * 10
+
+// identityTimeout is the maximum time allowed to complete the
+// source code management authentication after the identity
+// provider authentication.
+var identityTimeout = time.Minute * 5
+
+var errInvalidState = errors.New("Invalid or expired authentication state")
+
+// Config configures the OpenID Connect provider.
+type Config struct {
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+	Claim        string
+	Secret       string
+	Client       *http.Client
+}
+
+// Provider authenticates users with an OpenID Connect
+// identity provider.
+type Provider struct {
+	config Config
+
+	mu       sync.Mutex
+	metadata *metadata
+}
+
+// metadata provides the identity provider endpoints, loaded
+// from the discovery document.
+type metadata struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+}
+
+// New returns a new OpenID Connect provider.
+func New(config Config) *Provider {
+	if config.Client == nil {
+		config.Client = http.DefaultClient
+	}
+	if config.Claim == "" {
+		config.Claim = "preferred_username"
+	}
+	return &Provider{config: config}
+}
+
+// Handler returns an http.Handler that requires the user to
+// authenticate with the identity provider before the request
+// is passed to the next handler. The authenticated identity
+// is stored in the request context.
+func (p *Provider) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if cookie, err := r.Cookie(identityCookie); err == nil {
+			identity := authcookie.Login(cookie.Value, []byte(p.config.Secret))
+			if identity != "" {
+				ctx := WithIdentity(r.Context(), identity)
+				next.ServeHTTP(w, r.WithContext(ctx))
+				return
+			}
+		}
+		p.redirect(w, r)
+	})
+}
+
+// HandleCallback returns an http.HandlerFunc that completes the
+// identity provider authentication, and redirects the user to
+// the source code management authentication.
+func (p *Provider) HandleCallback() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if errstr := r.FormValue("error"); errstr != "" {
+			writeError(w, r, errors.New(errstr))
+			return
+		}
+
+		cookie, err := r.Cookie(stateCookie)
+		if err != nil {
+			writeError(w, r, errInvalidState)
+			return
+		}
+		parts := strings.SplitN(
+			authcookie.Login(cookie.Value, []byte(p.config.Secret)), ":", 2)
+		if len(parts) != 2 || !equal(parts[0], r.FormValue("state")) {
+			writeError(w, r, errInvalidState)
+			return
+		}
+		nonce := parts[1]
+
+		meta, err := p.discover(r.Context())
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		token, err := p.exchange(r.Context(), meta, r.FormValue("code"))
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		claims, err := parseClaims(token)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		err = claims.validate(meta.Issuer, p.config.ClientID, nonce, time.Now())
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		identity := claims.lookup(p.config.Claim)
+		if identity == "" {
+			writeError(w, r, fmt.Errorf("Identity token is missing the %s claim", p.config.Claim))
+			return
+		}
+
+		logrus.WithField("identity", identity).
+			Debugln("oidc: authentication successful")
+
+		writeCookie(w, stateCookie, "deleted", -1)
+		writeCookie(w, identityCookie, authcookie.NewSinceNow(
+			identity,
+			identityTimeout,
+			[]byte(p.config.Secret),
+		), int(identityTimeout/time.Second))
+		http.Redirect(w, r, cookiePath, 303)
+	}
+}
+
+// helper function redirects the user to the identity provider
+// authorization endpoint.
+func (p *Provider) redirect(w http.ResponseWriter, r *http.Request) {
+	meta, err := p.discover(r.Context())
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	state := uniuri.NewLen(32)
+	nonce := uniuri.NewLen(32)
+	writeCookie(w, stateCookie, authcookie.NewSinceNow(
+		state+":"+nonce,
+		stateTimeout,
+		[]byte(p.config.Secret),
+	), int(stateTimeout/time.Second))
+
+	params := url.Values{}
+	params.Set("response_type", "code")
+	params.Set("client_id", p.config.ClientID)
+	params.Set("redirect_uri", p.config.RedirectURL)
+	params.Set("scope", strings.Join(p.config.Scopes, " "))
+	params.Set("state", state)
+	params.Set("nonce", nonce)
+
+	target := meta.AuthorizationEndpoint
+	if strings.Contains(target, "?") {
+		target = target + "&" + params.Encode()
+	} else {
+		target = target + "?" + params.Encode()
+	}
+	http.Redirect(w, r, target, 303)
+}
+
+// helper function returns the identity provider metadata
+// from the discovery document. The metadata is cached after
+// it is successfully loaded.
+func (p *Provider) discover(ctx context.Context) (*metadata, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.metadata != nil {
+		return p.metadata, nil
+	}
+
+	endpoint := strings.TrimSuffix(p.config.Issuer, "/") + "/.well-known/openid-configuration"
+	req, err := http.NewRequest("GET", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	res, err := p.config.Client.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != 200 {
+		return nil, fmt.Errorf("Cannot load the identity provider configuration: %s", res.Status)
+	}
+	meta := new(metadata)
+	if err := json.NewDecoder(res.Body).Decode(meta); err != nil {
+		return nil, err
+	}
+	p.metadata = meta
+	return meta, nil
+}
+
+// helper function compares the strings in constant time.
+func equal(a, b string) bool {
+	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+}
+
+func writeCookie(w http.ResponseWriter, name, value string, age int) {
+	cookie := &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     cookiePath,
+		MaxAge:   age,
+		HttpOnly: true,
+	}
+	w.Header().Add("Set-Cookie", cookie.String()+"; SameSite=lax")
+}
+
+func writeError(w http.ResponseWriter, r *http.Request, err error) {
+	logrus.WithError(err).Debugln("oidc: cannot authenticate user")
+	http.Redirect(w, r, "/login/error?message="+url.QueryEscape(err.Error()), 303)
+}
@@ -0,0 +1,208 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package oidc
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dchest/authcookie"
+)
+
+var mockSecret = "0f2f8c0b86f34f"
+
+func TestHandler_Redirect(t *testing.T) {
+	idp := newMockProvider(t, nil)
+	defer idp.Close()
+
+	p := New(Config{
+		Issuer:      idp.URL,
+		ClientID:    "drone",
+		RedirectURL: "https://drone.company.com/login/oidc",
+		Scopes:      []string{"openid", "email"},
+		Secret:      mockSecret,
+	})
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/login", nil)
+	p.Handler(http.NotFoundHandler()).ServeHTTP(w, r)
+
+	if got, want := w.Code, 303; got != want {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+	location, _ := url.Parse(w.Header().Get("Location"))
+	if got, want := location.Path, "/authorize"; got != want {
+		t.Errorf("Want redirect to %s, got %s", want, got)
+	}
+	query := location.Query()
+	if got, want := query.Get("client_id"), "drone"; got != want {
+		t.Errorf("Want client_id %q, got %q", want, got)
+	}
+	if got, want := query.Get("scope"), "openid email"; got != want {
+		t.Errorf("Want scope %q, got %q", want, got)
+	}
+	if query.Get("state") == "" || query.Get("nonce") == "" {
+		t.Errorf("Want state and nonce parameters")
+	}
+	if !strings.Contains(w.Header().Get("Set-Cookie"), stateCookie) {
+		t.Errorf("Want state cookie")
+	}
+}
+
+func TestHandler_Identity(t *testing.T) {
+	p := New(Config{Secret: mockSecret})
+
+	var identity string
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		identity, _ = IdentityFrom(r.Context())
+	})
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/login", nil)
+	r.AddCookie(&http.Cookie{
+		Name:  identityCookie,
+		Value: authcookie.NewSinceNow("octocat", time.Minute, []byte(mockSecret)),
+	})
+	p.Handler(next).ServeHTTP(w, r)
+
+	if got, want := identity, "octocat"; got != want {
+		t.Errorf("Want identity %q, got %q", want, got)
+	}
+}
+
+func TestHandleCallback(t *testing.T) {
+	var idp *httptest.Server
+	idp = newMockProvider(t, func() map[string]interface{} {
+		return map[string]interface{}{
+			"iss":                idp.URL,
+			"aud":                "drone",
+			"exp":                time.Now().Add(time.Hour).Unix(),
+			"nonce":              "n0nc3",
+			"preferred_username": "octocat",
+		}
+	})
+	defer idp.Close()
+
+	p := New(Config{
+		Issuer:       idp.URL,
+		ClientID:     "drone",
+		ClientSecret: "s3cr3t",
+		Secret:       mockSecret,
+	})
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/login/oidc?code=c0d3&state=st4t3", nil)
+	r.AddCookie(&http.Cookie{
+		Name:  stateCookie,
+		Value: authcookie.NewSinceNow("st4t3:n0nc3", time.Minute, []byte(mockSecret)),
+	})
+	p.HandleCallback().ServeHTTP(w, r)
+
+	if got, want := w.Code, 303; got != want {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+	if got, want := w.Header().Get("Location"), "/login"; got != want {
+		t.Errorf("Want redirect to %s, got %s", want, got)
+	}
+
+	var identity string
+	for _, cookie := range (&http.Response{Header: w.Header()}).Cookies() {
+		if cookie.Name == identityCookie {
+			identity = authcookie.Login(cookie.Value, []byte(mockSecret))
+		}
+	}
+	if got, want := identity, "octocat"; got != want {
+		t.Errorf("Want identity %q, got %q", want, got)
+	}
+}
+
+func TestHandleCallback_InvalidState(t *testing.T) {
+	p := New(Config{Secret: mockSecret})
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/login/oidc?code=c0d3&state=f0rg3d", nil)
+	r.AddCookie(&http.Cookie{
+		Name:  stateCookie,
+		Value: authcookie.NewSinceNow("st4t3:n0nc3", time.Minute, []byte(mockSecret)),
+	})
+	p.HandleCallback().ServeHTTP(w, r)
+
+	if got, want := w.Code, 303; got != want {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+	if !strings.HasPrefix(w.Header().Get("Location"), "/login/error") {
+		t.Errorf("Want redirect to the login error page")
+	}
+}
+
+func TestClaimsValidate(t *testing.T) {
+	now := time.Now()
+	valid := func() claims {
+		return claims{
+			"iss":   "https://sso.company.com",
+			"aud":   []interface{}{"drone", "other"},
+			"exp":   float64(now.Add(time.Minute).Unix()),
+			"nonce": "n0nc3",
+		}
+	}
+	if err := valid().validate("https://sso.company.com", "drone", "n0nc3", now); err != nil {
+		t.Errorf("Want valid claims, got %s", err)
+	}
+
+	tests := []struct {
+		name  string
+		claim string
+		value interface{}
+	}{
+		{"issuer", "iss", "https://evil.com"},
+		{"audience", "aud", "other"},
+		{"expiration", "exp", float64(now.Add(-time.Minute).Unix())},
+		{"nonce", "nonce", "r3pl4y"},
+	}
+	for _, test := range tests {
+		c := valid()
+		c[test.claim] = test.value
+		if err := c.validate("https://sso.company.com", "drone", "n0nc3", now); err == nil {
+			t.Errorf("Want invalid %s error", test.name)
+		}
+	}
+}
+
+// helper function returns a mock identity provider. The
+// token endpoint returns an unsigned identity token with
+// the claims returned by the callback function.
+func newMockProvider(t *testing.T, fn func() map[string]interface{}) *httptest.Server {
+	var ts *httptest.Server
+	ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/.well-known/openid-configuration":
+			json.NewEncoder(w).Encode(&metadata{
+				Issuer:                ts.URL,
+				AuthorizationEndpoint: ts.URL + "/authorize",
+				TokenEndpoint:         ts.URL + "/token",
+			})
+		case "/token":
+			id, secret, _ := r.BasicAuth()
+			if id != "drone" || secret != "s3cr3t" || r.FormValue("code") != "c0d3" {
+				w.WriteHeader(401)
+				return
+			}
+			payload, _ := json.Marshal(fn())
+			json.NewEncoder(w).Encode(&token{
+				IDToken: "eyJhbGciOiJub25lIn0." +
+					base64.RawURLEncoding.EncodeToString(payload) + ".",
+			})
+		default:
+			w.WriteHeader(404)
+		}
+	}))
+	return ts
+}
@@ -0,0 +1,130 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package oidc
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// token represents the token endpoint response.
+type token struct {
+	IDToken string `json:"id_token"`
+}
+
+// claims represents the identity token claims.
+type claims map[string]interface{}
+
+// helper function exchanges the authorization code for the
+// identity token.
+func (p *Provider) exchange(ctx context.Context, meta *metadata, code string) (string, error) {
+	if code == "" {
+		return "", errors.New("Missing authorization code")
+	}
+	params := url.Values{}
+	params.Set("grant_type", "authorization_code")
+	params.Set("code", code)
+	params.Set("redirect_uri", p.config.RedirectURL)
+
+	req, err := http.NewRequest("POST", meta.TokenEndpoint, strings.NewReader(params.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(
+		url.QueryEscape(p.config.ClientID),
+		url.QueryEscape(p.config.ClientSecret),
+	)
+
+	res, err := p.config.Client.Do(req.WithContext(ctx))
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != 200 {
+		return "", fmt.Errorf("Cannot exchange the authorization code: %s", res.Status)
+	}
+	out := new(token)
+	if err := json.NewDecoder(res.Body).Decode(out); err != nil {
+		return "", err
+	}
+	if out.IDToken == "" {
+		return "", errors.New("Missing identity token")
+	}
+	return out.IDToken, nil
+}
+
+// helper function parses the claims from the identity token.
+// The token signature is not verified, because the token is
+// received directly from the token endpoint over a secure
+// connection, in which case TLS server validation may be used
+// in place of the signature (OpenID Connect Core, 3.1.3.7).
+func parseClaims(token string) (claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("Malformed identity token")
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(
+		strings.TrimRight(parts[1], "="),
+	)
+	if err != nil {
+		return nil, err
+	}
+	out := claims{}
+	if err := json.Unmarshal(payload, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// helper function validates the issuer, audience, expiration
+// and nonce of the identity token.
+func (c claims) validate(issuer, audience, nonce string, now time.Time) error {
+	if c.lookup("iss") != issuer {
+		return errors.New("Invalid identity token issuer")
+	}
+	if !c.audience(audience) {
+		return errors.New("Invalid identity token audience")
+	}
+	exp, ok := c["exp"].(float64)
+	if !ok || now.Unix() >= int64(exp) {
+		return errors.New("Identity token is expired")
+	}
+	if !equal(c.lookup("nonce"), nonce) {
+		return errors.New("Invalid identity token nonce")
+	}
+	return nil
+}
+
+// helper function returns true if the audience claim contains
+// the client identifier. The audience claim is either a single
+// string, or an array of strings.
+func (c claims) audience(client string) bool {
+	switch aud := c["aud"].(type) {
+	case string:
+		return aud == client
+	case []interface{}:
+		for _, v := range aud {
+			if v == client {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// helper function returns the string value of the claim.
+func (c claims) lookup(name string) string {
+	s, _ := c[name].(string)
+	return s
+}
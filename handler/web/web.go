@@ -10,6 +10,7 @@ import (
 	"github.com/drone/drone-ui/dist"
 	"github.com/drone/drone/core"
 	"github.com/drone/drone/handler/web/landingpage"
+	"github.com/drone/drone/handler/web/oidc"
 	"github.com/drone/drone/logger"
 	"github.com/drone/go-login/login"
 	"github.com/drone/go-scm/scm"
@@ -23,12 +24,17 @@ func New(
 	admitter core.AdmissionService,
 	builds core.BuildStore,
 	client *scm.Client,
+	commands core.CommandService,
+	health core.HealthService,
 	hooks core.HookParser,
+	idtokens core.IDTokenService,
 	license *core.License,
 	licenses core.LicenseService,
 	login login.Middleware,
+	payloads core.HookPayloadStore,
 	repos core.RepositoryStore,
 	session core.Session,
+	sso *oidc.Provider,
 	syncer core.Syncer,
 	triggerer core.Triggerer,
 	users core.UserStore,
@@ -41,12 +47,17 @@ func New(
 		Admitter:  admitter,
 		Builds:    builds,
 		Client:    client,
+		Commands:  commands,
+		Health:    health,
 		Hooks:     hooks,
+		IDTokens:  idtokens,
 		License:   license,
 		Licenses:  licenses,
 		Login:     login,
+		Payloads:  payloads,
 		Repos:     repos,
 		Session:   session,
+		SSO:       sso,
 		Syncer:    syncer,
 		Triggerer: triggerer,
 		Users:     users,
@@ -62,12 +73,17 @@ type Server struct {
 	Admitter  core.AdmissionService
 	Builds    core.BuildStore
 	Client    *scm.Client
+	Commands  core.CommandService
+	Health    core.HealthService
 	Hooks     core.HookParser
+	IDTokens  core.IDTokenService
 	License   *core.License
 	Licenses  core.LicenseService
 	Login     login.Middleware
+	Payloads  core.HookPayloadStore
 	Repos     core.RepositoryStore
 	Session   core.Session
+	SSO       *oidc.Provider
 	Syncer    core.Syncer
 	Triggerer core.Triggerer
 	Users     core.UserStore
@@ -88,27 +104,41 @@ func (s Server) Handler() http.Handler {
 	r.Use(sec.Handler)
 
 	r.Route("/hook", func(r chi.Router) {
-		r.Post("/", HandleHook(s.Repos, s.Builds, s.Triggerer, s.Hooks))
+		r.Post("/", HandleHook(s.Repos, s.Builds, s.Triggerer, s.Commands, s.Hooks, s.Payloads))
 	})
 
 	r.Get("/version", HandleVersion)
-	r.Get("/healthz", HandleHealthz())
+	r.Get("/healthz", HandleHealthz(s.Health))
+	r.Get("/readyz", HandleReadyz(s.Health))
 	r.Get("/varz", HandleVarz(s.Client, s.License))
 
-	r.Handle("/login",
-		s.Login.Handler(
-			http.HandlerFunc(
-				HandleLogin(
-					s.Users,
-					s.Userz,
-					s.Syncer,
-					s.Session,
-					s.Admitter,
-					s.Webhook,
-				),
+	// the discovery endpoints are only available if build
+	// identity tokens are enabled.
+	if s.IDTokens != nil {
+		r.Get("/.well-known/openid-configuration", HandleDiscovery(s.IDTokens))
+		r.Get("/.well-known/jwks", HandleJWKS(s.IDTokens))
+	}
+
+	var auth http.Handler = s.Login.Handler(
+		http.HandlerFunc(
+			HandleLogin(
+				s.Users,
+				s.Userz,
+				s.Syncer,
+				s.Session,
+				s.Admitter,
+				s.Webhook,
 			),
 		),
 	)
+	// if single sign-on is enabled, the user must authenticate
+	// with the identity provider before authenticating with the
+	// source code management system.
+	if s.SSO != nil {
+		auth = s.SSO.Handler(auth)
+		r.Get("/login/oidc", s.SSO.HandleCallback())
+	}
+	r.Handle("/login", auth)
 	r.Get("/logout", HandleLogout())
 
 	h2 := http.FileServer(landingpage.New())
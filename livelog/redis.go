@@ -0,0 +1,198 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package livelog
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/drone/drone/core"
+
+	"github.com/go-redis/redis"
+)
+
+const (
+	// redisKey is the name of the redis set that tracks the
+	// active log streams.
+	redisKey = "drone:livelog"
+
+	// redisBlock is the maximum amount of time a tail blocks
+	// waiting for new lines, before checking if the context
+	// is cancelled.
+	redisBlock = time.Second
+
+	// redisExpire is the amount of time a deleted stream is
+	// retained, so that active subscribers can read the
+	// remaining lines before the stream is removed.
+	redisExpire = time.Minute
+)
+
+type redisStreamer struct {
+	client *redis.Client
+}
+
+// NewRedis returns a new log streamer backed by Redis streams,
+// which can be shared by multiple server instances. The
+// datasource is the Redis connection url.
+func NewRedis(datasource string) (core.LogStream, error) {
+	options, err := redis.ParseURL(datasource)
+	if err != nil {
+		return nil, err
+	}
+	client := redis.NewClient(options)
+	if err := client.Ping().Err(); err != nil {
+		return nil, err
+	}
+	return &redisStreamer{client: client}, nil
+}
+
+func (s *redisStreamer) Create(ctx context.Context, id int64) error {
+	// remove lines from a previous stream with the same
+	// identifier, if one exists.
+	if err := s.client.Del(redisStream(id)).Err(); err != nil {
+		return err
+	}
+	return s.client.SAdd(redisKey, id).Err()
+}
+
+func (s *redisStreamer) Delete(ctx context.Context, id int64) error {
+	n, err := s.client.SRem(redisKey, id).Result()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return errStreamNotFound
+	}
+	// the end of the stream is written to the stream to
+	// notify active subscribers on all server instances.
+	err = s.client.XAdd(&redis.XAddArgs{
+		Stream: redisStream(id),
+		Values: map[string]interface{}{"eof": true},
+	}).Err()
+	if err != nil {
+		return err
+	}
+	return s.client.Expire(redisStream(id), redisExpire).Err()
+}
+
+func (s *redisStreamer) Write(ctx context.Context, id int64, line *core.Line) error {
+	ok, err := s.client.SIsMember(redisKey, id).Result()
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errStreamNotFound
+	}
+	data, err := json.Marshal(line)
+	if err != nil {
+		return err
+	}
+	// the history should not be unbounded. The stream is
+	// capped and lines are removed in a FIFO ordering when
+	// capacity is reached.
+	return s.client.XAdd(&redis.XAddArgs{
+		Stream:       redisStream(id),
+		MaxLenApprox: bufferSize,
+		Values:       map[string]interface{}{"line": data},
+	}).Err()
+}
+
+func (s *redisStreamer) Tail(ctx context.Context, id int64) (<-chan *core.Line, <-chan error) {
+	ok, err := s.client.SIsMember(redisKey, id).Result()
+	if err != nil || !ok {
+		return nil, nil
+	}
+	linec := make(chan *core.Line, bufferSize)
+	errc := make(chan error, 1)
+
+	go func() {
+		defer close(errc)
+		last := "0"
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			default:
+			}
+			streams, err := s.client.XRead(&redis.XReadArgs{
+				Streams: []string{redisStream(id), last},
+				Block:   redisBlock,
+			}).Result()
+			if err == redis.Nil {
+				continue
+			}
+			if err != nil {
+				errc <- err
+				return
+			}
+			for _, stream := range streams {
+				for _, message := range stream.Messages {
+					last = message.ID
+					line, eof := redisDecode(message)
+					if eof {
+						return
+					}
+					if line == nil {
+						continue
+					}
+					select {
+					case linec <- line:
+					default:
+						// lines are sent on a buffered channel. If
+						// there is a slow consumer the buffered
+						// channel will fill and newer lines are
+						// ignored.
+					}
+				}
+			}
+		}
+	}()
+	return linec, errc
+}
+
+func (s *redisStreamer) Info(ctx context.Context) *core.LogStreamInfo {
+	info := &core.LogStreamInfo{
+		Streams: map[int64]int{},
+	}
+	members, err := s.client.SMembers(redisKey).Result()
+	if err != nil {
+		return info
+	}
+	for _, member := range members {
+		id, err := strconv.ParseInt(member, 10, 64)
+		if err != nil {
+			continue
+		}
+		n, _ := s.client.XLen(redisStream(id)).Result()
+		info.Streams[id] = int(n)
+	}
+	return info
+}
+
+// helper function returns the name of the redis stream
+// for the step ID.
+func redisStream(id int64) string {
+	return redisKey + ":" + strconv.FormatInt(id, 10)
+}
+
+// helper function decodes the line from the redis stream
+// message. If the message marks the end of the stream, the
+// eof value is true.
+func redisDecode(message redis.XMessage) (line *core.Line, eof bool) {
+	if _, ok := message.Values["eof"]; ok {
+		return nil, true
+	}
+	data, ok := message.Values["line"].(string)
+	if !ok {
+		return nil, false
+	}
+	line = new(core.Line)
+	if err := json.Unmarshal([]byte(data), line); err != nil {
+		return nil, false
+	}
+	return line, false
+}
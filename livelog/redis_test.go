@@ -0,0 +1,47 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package livelog
+
+import (
+	"testing"
+
+	"github.com/drone/drone/core"
+
+	"github.com/go-redis/redis"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRedisDecode(t *testing.T) {
+	line, eof := redisDecode(redis.XMessage{
+		Values: map[string]interface{}{
+			"line": `{"pos":1,"out":"go test ./...","time":2}`,
+		},
+	})
+	if eof {
+		t.Errorf("Want eof false")
+	}
+	want := &core.Line{Number: 1, Message: "go test ./...", Timestamp: 2}
+	if diff := cmp.Diff(line, want); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestRedisDecode_EOF(t *testing.T) {
+	line, eof := redisDecode(redis.XMessage{
+		Values: map[string]interface{}{"eof": "1"},
+	})
+	if !eof {
+		t.Errorf("Want eof true")
+	}
+	if line != nil {
+		t.Errorf("Want nil line")
+	}
+}
+
+func TestRedisStream(t *testing.T) {
+	if got, want := redisStream(42), "drone:livelog:42"; got != want {
+		t.Errorf("Want stream name %q, got %q", want, got)
+	}
+}
@@ -0,0 +1,29 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package metric
+
+import "github.com/prometheus/client_golang/prometheus"
+
+// hookRejections counts the webhooks that are rejected because
+// the payload cannot be verified.
+var hookRejections = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "drone_webhook_rejections_total",
+	Help: "Total number of rejected webhooks.",
+}, []string{"provider", "reason"})
+
+// HookRejectionCount provides metrics for rejected webhooks,
+// labeled by the provider that delivered the webhook and the
+// reason the webhook was rejected. A high signature count may
+// indicate the repository webhook secret is out of sync with
+// the source control management system.
+func HookRejectionCount() {
+	prometheus.MustRegister(hookRejections)
+}
+
+// HookRejected increments the rejected webhook count for the
+// named provider and reason (e.g. signature, repository).
+func HookRejected(provider, reason string) {
+	hookRejections.WithLabelValues(provider, reason).Inc()
+}
@@ -0,0 +1,45 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package metric
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestHookRejectionCount(t *testing.T) {
+	// restore the default prometheus registerer
+	// when the unit test is complete.
+	snapshot := prometheus.DefaultRegisterer
+	defer func() {
+		prometheus.DefaultRegisterer = snapshot
+	}()
+
+	// creates a blank registry
+	registry := prometheus.NewRegistry()
+	prometheus.DefaultRegisterer = registry
+
+	HookRejectionCount()
+	HookRejected("default", "signature")
+	HookRejected("default", "signature")
+
+	metrics, err := registry.Gather()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if want, got := len(metrics), 1; want != got {
+		t.Errorf("Expect registered metric")
+		return
+	}
+	metric := metrics[0]
+	if want, got := metric.GetName(), "drone_webhook_rejections_total"; want != got {
+		t.Errorf("Expect metric name %s, got %s", want, got)
+	}
+	if want, got := metric.Metric[0].Counter.GetValue(), float64(2); want != got {
+		t.Errorf("Expect metric value %f, got %f", want, got)
+	}
+}
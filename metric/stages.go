@@ -35,3 +35,80 @@ func PendingJobCount(stages core.StageStore) {
 		}),
 	)
 }
+
+// stageConflicts counts the optimistic lock conflicts that
+// occur when multiple agents race for the same stage.
+var stageConflicts = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "drone_stage_conflicts_total",
+	Help: "Total number of stage conflicts between agents.",
+}, []string{"operation"})
+
+// StageConflictCount provides metrics for stage conflict counts,
+// labeled by the operation that detected the conflict. A high
+// count indicates agents are polling more often than stages
+// are being queued.
+func StageConflictCount() {
+	prometheus.MustRegister(stageConflicts)
+}
+
+// StageConflict increments the stage conflict count for the
+// named operation (e.g. accept).
+func StageConflict(operation string) {
+	stageConflicts.WithLabelValues(operation).Inc()
+}
+
+// queueErrors counts the errors that occur when the queue
+// cannot list the pending stages from the datastore.
+var queueErrors = prometheus.NewCounter(prometheus.CounterOpts{
+	Name: "drone_queue_errors_total",
+	Help: "Total number of errors dispatching pending stages.",
+})
+
+// QueueErrorCount provides metrics for queue error counts. A
+// high count indicates the datastore is unavailable and pending
+// stages are not being dispatched to agents.
+func QueueErrorCount() {
+	prometheus.MustRegister(queueErrors)
+}
+
+// QueueError increments the queue error count.
+func QueueError() {
+	queueErrors.Inc()
+}
+
+// stageQueueTime observes the number of seconds a stage waits
+// in the queue before it is accepted by an agent.
+var stageQueueTime = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+	Name:    "drone_stage_queue_seconds",
+	Help:    "Time a stage waits in the queue before it is accepted.",
+	Buckets: []float64{1, 5, 10, 30, 60, 120, 300, 600, 1800, 3600},
+}, []string{"os", "arch"})
+
+// stageAcceptTime observes the number of seconds between the
+// stage being dispatched to an agent and the agent accepting
+// the stage.
+var stageAcceptTime = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+	Name:    "drone_stage_accept_seconds",
+	Help:    "Time between a stage being dispatched and accepted.",
+	Buckets: []float64{0.5, 1, 2, 5, 10, 30, 60},
+}, []string{"os", "arch"})
+
+// StageQueueTime provides metrics for the stage queue latency,
+// labeled by the stage platform, which can be used to plan
+// agent capacity.
+func StageQueueTime() {
+	prometheus.MustRegister(stageQueueTime, stageAcceptTime)
+}
+
+// StageAccepted observes the queue latency of the stage when
+// the stage is accepted by an agent.
+func StageAccepted(stage *core.Stage) {
+	if stage.Queued != 0 {
+		stageQueueTime.WithLabelValues(stage.OS, stage.Arch).
+			Observe(float64(stage.QueueTime()))
+	}
+	if stage.Dispatched != 0 && stage.Accepted >= stage.Dispatched {
+		stageAcceptTime.WithLabelValues(stage.OS, stage.Arch).
+			Observe(float64(stage.Accepted - stage.Dispatched))
+	}
+}
@@ -93,3 +93,111 @@ func TestStageRunningCount(t *testing.T) {
 		t.Errorf("Expect metric value %f, got %f", want, got)
 	}
 }
+
+func TestStageConflictCount(t *testing.T) {
+	// restore the default prometheus registerer
+	// when the unit test is complete.
+	snapshot := prometheus.DefaultRegisterer
+	defer func() {
+		prometheus.DefaultRegisterer = snapshot
+	}()
+
+	// creates a blank registry
+	registry := prometheus.NewRegistry()
+	prometheus.DefaultRegisterer = registry
+
+	StageConflictCount()
+	StageConflict("accept")
+	StageConflict("accept")
+
+	metrics, err := registry.Gather()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if want, got := len(metrics), 1; want != got {
+		t.Errorf("Expect registered metric")
+		return
+	}
+	metric := metrics[0]
+	if want, got := metric.GetName(), "drone_stage_conflicts_total"; want != got {
+		t.Errorf("Expect metric name %s, got %s", want, got)
+	}
+	if want, got := metric.Metric[0].Counter.GetValue(), float64(2); want != got {
+		t.Errorf("Expect metric value %f, got %f", want, got)
+	}
+}
+
+func TestQueueErrorCount(t *testing.T) {
+	// restore the default prometheus registerer
+	// when the unit test is complete.
+	snapshot := prometheus.DefaultRegisterer
+	defer func() {
+		prometheus.DefaultRegisterer = snapshot
+	}()
+
+	// creates a blank registry
+	registry := prometheus.NewRegistry()
+	prometheus.DefaultRegisterer = registry
+
+	QueueErrorCount()
+	QueueError()
+
+	metrics, err := registry.Gather()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if want, got := len(metrics), 1; want != got {
+		t.Errorf("Expect registered metric")
+		return
+	}
+	metric := metrics[0]
+	if want, got := metric.GetName(), "drone_queue_errors_total"; want != got {
+		t.Errorf("Expect metric name %s, got %s", want, got)
+	}
+	if want, got := metric.Metric[0].Counter.GetValue(), float64(1); want != got {
+		t.Errorf("Expect metric value %f, got %f", want, got)
+	}
+}
+
+func TestStageQueueTime(t *testing.T) {
+	// restore the default prometheus registerer
+	// when the unit test is complete.
+	snapshot := prometheus.DefaultRegisterer
+	defer func() {
+		prometheus.DefaultRegisterer = snapshot
+	}()
+
+	// creates a blank registry
+	registry := prometheus.NewRegistry()
+	prometheus.DefaultRegisterer = registry
+
+	StageQueueTime()
+	StageAccepted(&core.Stage{OS: "linux", Arch: "amd64", Queued: 100, Dispatched: 140, Accepted: 142})
+
+	metrics, err := registry.Gather()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if want, got := len(metrics), 2; want != got {
+		t.Errorf("Expect registered metrics")
+		return
+	}
+	for _, metric := range metrics {
+		var want float64
+		switch metric.GetName() {
+		case "drone_stage_accept_seconds":
+			want = 2
+		case "drone_stage_queue_seconds":
+			want = 42
+		default:
+			t.Errorf("Unexpected metric name %s", metric.GetName())
+			continue
+		}
+		if got := metric.Metric[0].Histogram.GetSampleSum(); want != got {
+			t.Errorf("Expect metric %s sum %f, got %f", metric.GetName(), want, got)
+		}
+	}
+}
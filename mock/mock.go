@@ -4,4 +4,4 @@
 
 package mock
 
-//go:generate mockgen -package=mock -destination=mock_gen.go github.com/drone/drone/core NetrcService,Renewer,HookParser,UserService,RepositoryService,CommitService,StatusService,HookService,FileService,Batcher,BuildStore,CronStore,LogStore,PermStore,SecretStore,StageStore,StepStore,RepositoryStore,UserStore,Scheduler,Session,OrganizationService,SecretService,RegistryService,ConfigService,Triggerer,Syncer,LogStream,WebhookSender,LicenseService
+//go:generate mockgen -package=mock -destination=mock_gen.go github.com/drone/drone/core NetrcService,Renewer,HookParser,UserService,RepositoryService,CommitService,StatusService,HookService,FileService,Batcher,ArtifactStore,AuditStore,BuildStore,CoverageStore,CronStore,TestResultStore,TokenStore,LogStore,PermStore,SecretStore,SecretAccessStore,StageStore,StepStore,RepositoryStore,UserStore,Scheduler,Session,UserSessionStore,OrganizationService,SecretService,RegistryService,PoolService,PlatformService,AgentRegistry,RegistryStore,PolicyStore,PrivilegedImageStore,VariableStore,PathMappingStore,InsightStore,ConfigService,Triggerer,Syncer,LogStream,WebhookSender,WebhookDeliveryStore,RepoWebhookStore,NotificationStore,NotificationService,LicenseService,HealthService,IDTokenService,InstallationService,ProviderService,CommandService,SummaryService,Elector,AnnotationStore,ProvenanceService,DeploymentStore,SecretFileService,ReferenceService,PullRequestService,HookPayloadStore
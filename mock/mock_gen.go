@@ -1,5 +1,5 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: github.com/drone/drone/core (interfaces: NetrcService,Renewer,HookParser,UserService,RepositoryService,CommitService,StatusService,HookService,FileService,Batcher,BuildStore,CronStore,LogStore,PermStore,SecretStore,StageStore,StepStore,RepositoryStore,UserStore,Scheduler,Session,OrganizationService,SecretService,RegistryService,ConfigService,Triggerer,Syncer,LogStream,WebhookSender,LicenseService)
+// Source: github.com/drone/drone/core (interfaces: NetrcService,Renewer,HookParser,UserService,RepositoryService,CommitService,StatusService,HookService,FileService,Batcher,ArtifactStore,AuditStore,BuildStore,CoverageStore,CronStore,TestResultStore,TokenStore,LogStore,PermStore,SecretStore,SecretAccessStore,StageStore,StepStore,RepositoryStore,UserStore,Scheduler,Session,UserSessionStore,OrganizationService,SecretService,RegistryService,PoolService,PlatformService,AgentRegistry,RegistryStore,PolicyStore,PrivilegedImageStore,VariableStore,PathMappingStore,InsightStore,ConfigService,Triggerer,Syncer,LogStream,WebhookSender,WebhookDeliveryStore,RepoWebhookStore,NotificationStore,NotificationService,LicenseService,HealthService,IDTokenService,InstallationService,ProviderService,CommandService,SummaryService,Elector,AnnotationStore,ProvenanceService,DeploymentStore,SecretFileService,ReferenceService,PullRequestService,HookPayloadStore)
 
 // Package mock is a generated GoMock package.
 package mock
@@ -11,6 +11,7 @@ import (
 	io "io"
 	http "net/http"
 	reflect "reflect"
+	time "time"
 )
 
 // MockNetrcService is a mock of NetrcService interface
@@ -434,6 +435,140 @@ func (mr *MockBatcherMockRecorder) Batch(arg0, arg1, arg2 interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Batch", reflect.TypeOf((*MockBatcher)(nil).Batch), arg0, arg1, arg2)
 }
 
+// MockArtifactStore is a mock of ArtifactStore interface
+type MockArtifactStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockArtifactStoreMockRecorder
+}
+
+// MockArtifactStoreMockRecorder is the mock recorder for MockArtifactStore
+type MockArtifactStoreMockRecorder struct {
+	mock *MockArtifactStore
+}
+
+// NewMockArtifactStore creates a new mock instance
+func NewMockArtifactStore(ctrl *gomock.Controller) *MockArtifactStore {
+	mock := &MockArtifactStore{ctrl: ctrl}
+	mock.recorder = &MockArtifactStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockArtifactStore) EXPECT() *MockArtifactStoreMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method
+func (m *MockArtifactStore) Create(arg0 context.Context, arg1 *core.Artifact, arg2 io.Reader) error {
+	ret := m.ctrl.Call(m, "Create", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create
+func (mr *MockArtifactStoreMockRecorder) Create(arg0, arg1, arg2 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockArtifactStore)(nil).Create), arg0, arg1, arg2)
+}
+
+// Delete mocks base method
+func (m *MockArtifactStore) Delete(arg0 context.Context, arg1 *core.Artifact) error {
+	ret := m.ctrl.Call(m, "Delete", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete
+func (mr *MockArtifactStoreMockRecorder) Delete(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockArtifactStore)(nil).Delete), arg0, arg1)
+}
+
+// Find mocks base method
+func (m *MockArtifactStore) Find(arg0 context.Context, arg1 int64) (*core.Artifact, error) {
+	ret := m.ctrl.Call(m, "Find", arg0, arg1)
+	ret0, _ := ret[0].(*core.Artifact)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Find indicates an expected call of Find
+func (mr *MockArtifactStoreMockRecorder) Find(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Find", reflect.TypeOf((*MockArtifactStore)(nil).Find), arg0, arg1)
+}
+
+// List mocks base method
+func (m *MockArtifactStore) List(arg0 context.Context, arg1 int64) ([]*core.Artifact, error) {
+	ret := m.ctrl.Call(m, "List", arg0, arg1)
+	ret0, _ := ret[0].([]*core.Artifact)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List
+func (mr *MockArtifactStoreMockRecorder) List(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockArtifactStore)(nil).List), arg0, arg1)
+}
+
+// Open mocks base method
+func (m *MockArtifactStore) Open(arg0 context.Context, arg1 *core.Artifact) (io.ReadCloser, error) {
+	ret := m.ctrl.Call(m, "Open", arg0, arg1)
+	ret0, _ := ret[0].(io.ReadCloser)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Open indicates an expected call of Open
+func (mr *MockArtifactStoreMockRecorder) Open(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Open", reflect.TypeOf((*MockArtifactStore)(nil).Open), arg0, arg1)
+}
+
+// MockAuditStore is a mock of AuditStore interface
+type MockAuditStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuditStoreMockRecorder
+}
+
+// MockAuditStoreMockRecorder is the mock recorder for MockAuditStore
+type MockAuditStoreMockRecorder struct {
+	mock *MockAuditStore
+}
+
+// NewMockAuditStore creates a new mock instance
+func NewMockAuditStore(ctrl *gomock.Controller) *MockAuditStore {
+	mock := &MockAuditStore{ctrl: ctrl}
+	mock.recorder = &MockAuditStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockAuditStore) EXPECT() *MockAuditStoreMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method
+func (m *MockAuditStore) Create(arg0 context.Context, arg1 *core.AuditEvent) error {
+	ret := m.ctrl.Call(m, "Create", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create
+func (mr *MockAuditStoreMockRecorder) Create(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockAuditStore)(nil).Create), arg0, arg1)
+}
+
+// List mocks base method
+func (m *MockAuditStore) List(arg0 context.Context, arg1 core.AuditFilter) ([]*core.AuditEvent, error) {
+	ret := m.ctrl.Call(m, "List", arg0, arg1)
+	ret0, _ := ret[0].([]*core.AuditEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List
+func (mr *MockAuditStoreMockRecorder) List(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockAuditStore)(nil).List), arg0, arg1)
+}
+
 // MockBuildStore is a mock of BuildStore interface
 type MockBuildStore struct {
 	ctrl     *gomock.Controller
@@ -507,6 +642,19 @@ func (mr *MockBuildStoreMockRecorder) Find(arg0, arg1 interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Find", reflect.TypeOf((*MockBuildStore)(nil).Find), arg0, arg1)
 }
 
+// FindCommit mocks base method
+func (m *MockBuildStore) FindCommit(arg0 context.Context, arg1 int64, arg2, arg3 string) (*core.Build, error) {
+	ret := m.ctrl.Call(m, "FindCommit", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*core.Build)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindCommit indicates an expected call of FindCommit
+func (mr *MockBuildStoreMockRecorder) FindCommit(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindCommit", reflect.TypeOf((*MockBuildStore)(nil).FindCommit), arg0, arg1, arg2, arg3)
+}
+
 // FindNumber mocks base method
 func (m *MockBuildStore) FindNumber(arg0 context.Context, arg1, arg2 int64) (*core.Build, error) {
 	ret := m.ctrl.Call(m, "FindNumber", arg0, arg1, arg2)
@@ -546,6 +694,19 @@ func (mr *MockBuildStoreMockRecorder) List(arg0, arg1, arg2, arg3 interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockBuildStore)(nil).List), arg0, arg1, arg2, arg3)
 }
 
+// ListFilter mocks base method
+func (m *MockBuildStore) ListFilter(arg0 context.Context, arg1 int64, arg2 core.BuildFilter) ([]*core.Build, error) {
+	ret := m.ctrl.Call(m, "ListFilter", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]*core.Build)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListFilter indicates an expected call of ListFilter
+func (mr *MockBuildStoreMockRecorder) ListFilter(arg0, arg1, arg2 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListFilter", reflect.TypeOf((*MockBuildStore)(nil).ListFilter), arg0, arg1, arg2)
+}
+
 // ListRef mocks base method
 func (m *MockBuildStore) ListRef(arg0 context.Context, arg1 int64, arg2 string, arg3, arg4 int) ([]*core.Build, error) {
 	ret := m.ctrl.Call(m, "ListRef", arg0, arg1, arg2, arg3, arg4)
@@ -609,6 +770,67 @@ func (mr *MockBuildStoreMockRecorder) Update(arg0, arg1 interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockBuildStore)(nil).Update), arg0, arg1)
 }
 
+// MockCoverageStore is a mock of CoverageStore interface
+type MockCoverageStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockCoverageStoreMockRecorder
+}
+
+// MockCoverageStoreMockRecorder is the mock recorder for MockCoverageStore
+type MockCoverageStoreMockRecorder struct {
+	mock *MockCoverageStore
+}
+
+// NewMockCoverageStore creates a new mock instance
+func NewMockCoverageStore(ctrl *gomock.Controller) *MockCoverageStore {
+	mock := &MockCoverageStore{ctrl: ctrl}
+	mock.recorder = &MockCoverageStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockCoverageStore) EXPECT() *MockCoverageStoreMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method
+func (m *MockCoverageStore) Create(arg0 context.Context, arg1 *core.Coverage) error {
+	ret := m.ctrl.Call(m, "Create", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create
+func (mr *MockCoverageStoreMockRecorder) Create(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockCoverageStore)(nil).Create), arg0, arg1)
+}
+
+// List mocks base method
+func (m *MockCoverageStore) List(arg0 context.Context, arg1 int64, arg2 string, arg3 int) ([]*core.Coverage, error) {
+	ret := m.ctrl.Call(m, "List", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]*core.Coverage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List
+func (mr *MockCoverageStoreMockRecorder) List(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockCoverageStore)(nil).List), arg0, arg1, arg2, arg3)
+}
+
+// ListBuild mocks base method
+func (m *MockCoverageStore) ListBuild(arg0 context.Context, arg1 int64) ([]*core.Coverage, error) {
+	ret := m.ctrl.Call(m, "ListBuild", arg0, arg1)
+	ret0, _ := ret[0].([]*core.Coverage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListBuild indicates an expected call of ListBuild
+func (mr *MockCoverageStoreMockRecorder) ListBuild(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBuild", reflect.TypeOf((*MockCoverageStore)(nil).ListBuild), arg0, arg1)
+}
+
 // MockCronStore is a mock of CronStore interface
 type MockCronStore struct {
 	ctrl     *gomock.Controller
@@ -720,6 +942,152 @@ func (mr *MockCronStoreMockRecorder) Update(arg0, arg1 interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockCronStore)(nil).Update), arg0, arg1)
 }
 
+// MockTestResultStore is a mock of TestResultStore interface
+type MockTestResultStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockTestResultStoreMockRecorder
+}
+
+// MockTestResultStoreMockRecorder is the mock recorder for MockTestResultStore
+type MockTestResultStoreMockRecorder struct {
+	mock *MockTestResultStore
+}
+
+// NewMockTestResultStore creates a new mock instance
+func NewMockTestResultStore(ctrl *gomock.Controller) *MockTestResultStore {
+	mock := &MockTestResultStore{ctrl: ctrl}
+	mock.recorder = &MockTestResultStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockTestResultStore) EXPECT() *MockTestResultStoreMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method
+func (m *MockTestResultStore) Create(arg0 context.Context, arg1 *core.TestResult) error {
+	ret := m.ctrl.Call(m, "Create", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create
+func (mr *MockTestResultStoreMockRecorder) Create(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockTestResultStore)(nil).Create), arg0, arg1)
+}
+
+// List mocks base method
+func (m *MockTestResultStore) List(arg0 context.Context, arg1 int64) ([]*core.TestResult, error) {
+	ret := m.ctrl.Call(m, "List", arg0, arg1)
+	ret0, _ := ret[0].([]*core.TestResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List
+func (mr *MockTestResultStoreMockRecorder) List(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockTestResultStore)(nil).List), arg0, arg1)
+}
+
+// MockTokenStore is a mock of TokenStore interface
+type MockTokenStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockTokenStoreMockRecorder
+}
+
+// MockTokenStoreMockRecorder is the mock recorder for MockTokenStore
+type MockTokenStoreMockRecorder struct {
+	mock *MockTokenStore
+}
+
+// NewMockTokenStore creates a new mock instance
+func NewMockTokenStore(ctrl *gomock.Controller) *MockTokenStore {
+	mock := &MockTokenStore{ctrl: ctrl}
+	mock.recorder = &MockTokenStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockTokenStore) EXPECT() *MockTokenStoreMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method
+func (m *MockTokenStore) Create(arg0 context.Context, arg1 *core.Token) error {
+	ret := m.ctrl.Call(m, "Create", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create
+func (mr *MockTokenStoreMockRecorder) Create(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockTokenStore)(nil).Create), arg0, arg1)
+}
+
+// Delete mocks base method
+func (m *MockTokenStore) Delete(arg0 context.Context, arg1 *core.Token) error {
+	ret := m.ctrl.Call(m, "Delete", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete
+func (mr *MockTokenStoreMockRecorder) Delete(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockTokenStore)(nil).Delete), arg0, arg1)
+}
+
+// DeleteUser mocks base method
+func (m *MockTokenStore) DeleteUser(arg0 context.Context, arg1 int64) error {
+	ret := m.ctrl.Call(m, "DeleteUser", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUser indicates an expected call of DeleteUser
+func (mr *MockTokenStoreMockRecorder) DeleteUser(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUser", reflect.TypeOf((*MockTokenStore)(nil).DeleteUser), arg0, arg1)
+}
+
+// Find mocks base method
+func (m *MockTokenStore) Find(arg0 context.Context, arg1 int64) (*core.Token, error) {
+	ret := m.ctrl.Call(m, "Find", arg0, arg1)
+	ret0, _ := ret[0].(*core.Token)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Find indicates an expected call of Find
+func (mr *MockTokenStoreMockRecorder) Find(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Find", reflect.TypeOf((*MockTokenStore)(nil).Find), arg0, arg1)
+}
+
+// FindHash mocks base method
+func (m *MockTokenStore) FindHash(arg0 context.Context, arg1 string) (*core.Token, error) {
+	ret := m.ctrl.Call(m, "FindHash", arg0, arg1)
+	ret0, _ := ret[0].(*core.Token)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindHash indicates an expected call of FindHash
+func (mr *MockTokenStoreMockRecorder) FindHash(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindHash", reflect.TypeOf((*MockTokenStore)(nil).FindHash), arg0, arg1)
+}
+
+// List mocks base method
+func (m *MockTokenStore) List(arg0 context.Context, arg1 int64) ([]*core.Token, error) {
+	ret := m.ctrl.Call(m, "List", arg0, arg1)
+	ret0, _ := ret[0].([]*core.Token)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List
+func (mr *MockTokenStoreMockRecorder) List(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockTokenStore)(nil).List), arg0, arg1)
+}
+
 // MockLogStore is a mock of LogStore interface
 type MockLogStore struct {
 	ctrl     *gomock.Controller
@@ -815,6 +1183,18 @@ func (m *MockPermStore) EXPECT() *MockPermStoreMockRecorder {
 	return m.recorder
 }
 
+// Create mocks base method
+func (m *MockPermStore) Create(arg0 context.Context, arg1 *core.Perm) error {
+	ret := m.ctrl.Call(m, "Create", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create
+func (mr *MockPermStoreMockRecorder) Create(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockPermStore)(nil).Create), arg0, arg1)
+}
+
 // Delete mocks base method
 func (m *MockPermStore) Delete(arg0 context.Context, arg1 *core.Perm) error {
 	ret := m.ctrl.Call(m, "Delete", arg0, arg1)
@@ -951,6 +1331,19 @@ func (mr *MockSecretStoreMockRecorder) List(arg0, arg1 interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockSecretStore)(nil).List), arg0, arg1)
 }
 
+// Rotate mocks base method
+func (m *MockSecretStore) Rotate(arg0 context.Context) (int64, error) {
+	ret := m.ctrl.Call(m, "Rotate", arg0)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Rotate indicates an expected call of Rotate
+func (mr *MockSecretStoreMockRecorder) Rotate(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rotate", reflect.TypeOf((*MockSecretStore)(nil).Rotate), arg0)
+}
+
 // Update mocks base method
 func (m *MockSecretStore) Update(arg0 context.Context, arg1 *core.Secret) error {
 	ret := m.ctrl.Call(m, "Update", arg0, arg1)
@@ -963,42 +1356,90 @@ func (mr *MockSecretStoreMockRecorder) Update(arg0, arg1 interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockSecretStore)(nil).Update), arg0, arg1)
 }
 
-// MockStageStore is a mock of StageStore interface
-type MockStageStore struct {
+// MockSecretAccessStore is a mock of SecretAccessStore interface
+type MockSecretAccessStore struct {
 	ctrl     *gomock.Controller
-	recorder *MockStageStoreMockRecorder
+	recorder *MockSecretAccessStoreMockRecorder
 }
 
-// MockStageStoreMockRecorder is the mock recorder for MockStageStore
-type MockStageStoreMockRecorder struct {
-	mock *MockStageStore
+// MockSecretAccessStoreMockRecorder is the mock recorder for MockSecretAccessStore
+type MockSecretAccessStoreMockRecorder struct {
+	mock *MockSecretAccessStore
 }
 
-// NewMockStageStore creates a new mock instance
-func NewMockStageStore(ctrl *gomock.Controller) *MockStageStore {
-	mock := &MockStageStore{ctrl: ctrl}
-	mock.recorder = &MockStageStoreMockRecorder{mock}
+// NewMockSecretAccessStore creates a new mock instance
+func NewMockSecretAccessStore(ctrl *gomock.Controller) *MockSecretAccessStore {
+	mock := &MockSecretAccessStore{ctrl: ctrl}
+	mock.recorder = &MockSecretAccessStoreMockRecorder{mock}
 	return mock
 }
 
 // EXPECT returns an object that allows the caller to indicate expected use
-func (m *MockStageStore) EXPECT() *MockStageStoreMockRecorder {
+func (m *MockSecretAccessStore) EXPECT() *MockSecretAccessStoreMockRecorder {
 	return m.recorder
 }
 
 // Create mocks base method
-func (m *MockStageStore) Create(arg0 context.Context, arg1 *core.Stage) error {
+func (m *MockSecretAccessStore) Create(arg0 context.Context, arg1 *core.SecretAccess) error {
 	ret := m.ctrl.Call(m, "Create", arg0, arg1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Create indicates an expected call of Create
-func (mr *MockStageStoreMockRecorder) Create(arg0, arg1 interface{}) *gomock.Call {
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockStageStore)(nil).Create), arg0, arg1)
+func (mr *MockSecretAccessStoreMockRecorder) Create(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockSecretAccessStore)(nil).Create), arg0, arg1)
 }
 
-// Find mocks base method
+// List mocks base method
+func (m *MockSecretAccessStore) List(arg0 context.Context, arg1 int64) ([]*core.SecretAccess, error) {
+	ret := m.ctrl.Call(m, "List", arg0, arg1)
+	ret0, _ := ret[0].([]*core.SecretAccess)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List
+func (mr *MockSecretAccessStoreMockRecorder) List(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockSecretAccessStore)(nil).List), arg0, arg1)
+}
+
+// MockStageStore is a mock of StageStore interface
+type MockStageStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockStageStoreMockRecorder
+}
+
+// MockStageStoreMockRecorder is the mock recorder for MockStageStore
+type MockStageStoreMockRecorder struct {
+	mock *MockStageStore
+}
+
+// NewMockStageStore creates a new mock instance
+func NewMockStageStore(ctrl *gomock.Controller) *MockStageStore {
+	mock := &MockStageStore{ctrl: ctrl}
+	mock.recorder = &MockStageStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockStageStore) EXPECT() *MockStageStoreMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method
+func (m *MockStageStore) Create(arg0 context.Context, arg1 *core.Stage) error {
+	ret := m.ctrl.Call(m, "Create", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create
+func (mr *MockStageStoreMockRecorder) Create(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockStageStore)(nil).Create), arg0, arg1)
+}
+
+// Find mocks base method
 func (m *MockStageStore) Find(arg0 context.Context, arg1 int64) (*core.Stage, error) {
 	ret := m.ctrl.Call(m, "Find", arg0, arg1)
 	ret0, _ := ret[0].(*core.Stage)
@@ -1298,6 +1739,32 @@ func (mr *MockRepositoryStoreMockRecorder) List(arg0, arg1 interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockRepositoryStore)(nil).List), arg0, arg1)
 }
 
+// ListAll mocks base method
+func (m *MockRepositoryStore) ListAll(arg0 context.Context, arg1, arg2 int) ([]*core.Repository, error) {
+	ret := m.ctrl.Call(m, "ListAll", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]*core.Repository)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAll indicates an expected call of ListAll
+func (mr *MockRepositoryStoreMockRecorder) ListAll(arg0, arg1, arg2 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAll", reflect.TypeOf((*MockRepositoryStore)(nil).ListAll), arg0, arg1, arg2)
+}
+
+// ListFilter mocks base method
+func (m *MockRepositoryStore) ListFilter(arg0 context.Context, arg1 int64, arg2 core.RepositoryFilter) ([]*core.Repository, error) {
+	ret := m.ctrl.Call(m, "ListFilter", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]*core.Repository)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListFilter indicates an expected call of ListFilter
+func (mr *MockRepositoryStoreMockRecorder) ListFilter(arg0, arg1, arg2 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListFilter", reflect.TypeOf((*MockRepositoryStore)(nil).ListFilter), arg0, arg1, arg2)
+}
+
 // ListIncomplete mocks base method
 func (m *MockRepositoryStore) ListIncomplete(arg0 context.Context) ([]*core.Repository, error) {
 	ret := m.ctrl.Call(m, "ListIncomplete", arg0)
@@ -1324,6 +1791,19 @@ func (mr *MockRepositoryStoreMockRecorder) ListLatest(arg0, arg1 interface{}) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListLatest", reflect.TypeOf((*MockRepositoryStore)(nil).ListLatest), arg0, arg1)
 }
 
+// ListPolling mocks base method
+func (m *MockRepositoryStore) ListPolling(arg0 context.Context, arg1 int64) ([]*core.Repository, error) {
+	ret := m.ctrl.Call(m, "ListPolling", arg0, arg1)
+	ret0, _ := ret[0].([]*core.Repository)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPolling indicates an expected call of ListPolling
+func (mr *MockRepositoryStoreMockRecorder) ListPolling(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPolling", reflect.TypeOf((*MockRepositoryStore)(nil).ListPolling), arg0, arg1)
+}
+
 // ListRecent mocks base method
 func (m *MockRepositoryStore) ListRecent(arg0 context.Context, arg1 int64) ([]*core.Repository, error) {
 	ret := m.ctrl.Call(m, "ListRecent", arg0, arg1)
@@ -1337,6 +1817,19 @@ func (mr *MockRepositoryStoreMockRecorder) ListRecent(arg0, arg1 interface{}) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRecent", reflect.TypeOf((*MockRepositoryStore)(nil).ListRecent), arg0, arg1)
 }
 
+// ListSearch mocks base method
+func (m *MockRepositoryStore) ListSearch(arg0 context.Context, arg1 core.BuildSearch) ([]*core.Repository, error) {
+	ret := m.ctrl.Call(m, "ListSearch", arg0, arg1)
+	ret0, _ := ret[0].([]*core.Repository)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSearch indicates an expected call of ListSearch
+func (mr *MockRepositoryStoreMockRecorder) ListSearch(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSearch", reflect.TypeOf((*MockRepositoryStore)(nil).ListSearch), arg0, arg1)
+}
+
 // Update mocks base method
 func (m *MockRepositoryStore) Update(arg0 context.Context, arg1 *core.Repository) error {
 	ret := m.ctrl.Call(m, "Update", arg0, arg1)
@@ -1521,6 +2014,31 @@ func (mr *MockSchedulerMockRecorder) Cancelled(arg0, arg1 interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Cancelled", reflect.TypeOf((*MockScheduler)(nil).Cancelled), arg0, arg1)
 }
 
+// Pause mocks base method
+func (m *MockScheduler) Pause(arg0 context.Context) error {
+	ret := m.ctrl.Call(m, "Pause", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Pause indicates an expected call of Pause
+func (mr *MockSchedulerMockRecorder) Pause(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Pause", reflect.TypeOf((*MockScheduler)(nil).Pause), arg0)
+}
+
+// Paused mocks base method
+func (m *MockScheduler) Paused(arg0 context.Context) (bool, error) {
+	ret := m.ctrl.Call(m, "Paused", arg0)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Paused indicates an expected call of Paused
+func (mr *MockSchedulerMockRecorder) Paused(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Paused", reflect.TypeOf((*MockScheduler)(nil).Paused), arg0)
+}
+
 // Request mocks base method
 func (m *MockScheduler) Request(arg0 context.Context, arg1 core.Filter) (*core.Stage, error) {
 	ret := m.ctrl.Call(m, "Request", arg0, arg1)
@@ -1534,6 +2052,18 @@ func (mr *MockSchedulerMockRecorder) Request(arg0, arg1 interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Request", reflect.TypeOf((*MockScheduler)(nil).Request), arg0, arg1)
 }
 
+// Resume mocks base method
+func (m *MockScheduler) Resume(arg0 context.Context) error {
+	ret := m.ctrl.Call(m, "Resume", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Resume indicates an expected call of Resume
+func (mr *MockSchedulerMockRecorder) Resume(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Resume", reflect.TypeOf((*MockScheduler)(nil).Resume), arg0)
+}
+
 // Schedule mocks base method
 func (m *MockScheduler) Schedule(arg0 context.Context, arg1 *core.Stage) error {
 	ret := m.ctrl.Call(m, "Schedule", arg0, arg1)
@@ -1546,6 +2076,30 @@ func (mr *MockSchedulerMockRecorder) Schedule(arg0, arg1 interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Schedule", reflect.TypeOf((*MockScheduler)(nil).Schedule), arg0, arg1)
 }
 
+// Shutdown mocks base method
+func (m *MockScheduler) Shutdown(arg0 context.Context) error {
+	ret := m.ctrl.Call(m, "Shutdown", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Shutdown indicates an expected call of Shutdown
+func (mr *MockSchedulerMockRecorder) Shutdown(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Shutdown", reflect.TypeOf((*MockScheduler)(nil).Shutdown), arg0)
+}
+
+// Signal mocks base method
+func (m *MockScheduler) Signal(arg0 context.Context) error {
+	ret := m.ctrl.Call(m, "Signal", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Signal indicates an expected call of Signal
+func (mr *MockSchedulerMockRecorder) Signal(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Signal", reflect.TypeOf((*MockScheduler)(nil).Signal), arg0)
+}
+
 // Stats mocks base method
 func (m *MockScheduler) Stats(arg0 context.Context) (interface{}, error) {
 	ret := m.ctrl.Call(m, "Stats", arg0)
@@ -1583,15 +2137,15 @@ func (m *MockSession) EXPECT() *MockSessionMockRecorder {
 }
 
 // Create mocks base method
-func (m *MockSession) Create(arg0 http.ResponseWriter, arg1 *core.User) error {
-	ret := m.ctrl.Call(m, "Create", arg0, arg1)
+func (m *MockSession) Create(arg0 http.ResponseWriter, arg1 *http.Request, arg2 *core.User) error {
+	ret := m.ctrl.Call(m, "Create", arg0, arg1, arg2)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Create indicates an expected call of Create
-func (mr *MockSessionMockRecorder) Create(arg0, arg1 interface{}) *gomock.Call {
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockSession)(nil).Create), arg0, arg1)
+func (mr *MockSessionMockRecorder) Create(arg0, arg1, arg2 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockSession)(nil).Create), arg0, arg1, arg2)
 }
 
 // Delete mocks base method
@@ -1619,6 +2173,128 @@ func (mr *MockSessionMockRecorder) Get(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockSession)(nil).Get), arg0)
 }
 
+// MockUserSessionStore is a mock of UserSessionStore interface
+type MockUserSessionStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockUserSessionStoreMockRecorder
+}
+
+// MockUserSessionStoreMockRecorder is the mock recorder for MockUserSessionStore
+type MockUserSessionStoreMockRecorder struct {
+	mock *MockUserSessionStore
+}
+
+// NewMockUserSessionStore creates a new mock instance
+func NewMockUserSessionStore(ctrl *gomock.Controller) *MockUserSessionStore {
+	mock := &MockUserSessionStore{ctrl: ctrl}
+	mock.recorder = &MockUserSessionStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockUserSessionStore) EXPECT() *MockUserSessionStoreMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method
+func (m *MockUserSessionStore) Create(arg0 context.Context, arg1 *core.UserSession) error {
+	ret := m.ctrl.Call(m, "Create", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create
+func (mr *MockUserSessionStoreMockRecorder) Create(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockUserSessionStore)(nil).Create), arg0, arg1)
+}
+
+// Delete mocks base method
+func (m *MockUserSessionStore) Delete(arg0 context.Context, arg1 *core.UserSession) error {
+	ret := m.ctrl.Call(m, "Delete", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete
+func (mr *MockUserSessionStoreMockRecorder) Delete(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockUserSessionStore)(nil).Delete), arg0, arg1)
+}
+
+// DeleteExpired mocks base method
+func (m *MockUserSessionStore) DeleteExpired(arg0 context.Context, arg1 int64) error {
+	ret := m.ctrl.Call(m, "DeleteExpired", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteExpired indicates an expected call of DeleteExpired
+func (mr *MockUserSessionStoreMockRecorder) DeleteExpired(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpired", reflect.TypeOf((*MockUserSessionStore)(nil).DeleteExpired), arg0, arg1)
+}
+
+// DeleteUser mocks base method
+func (m *MockUserSessionStore) DeleteUser(arg0 context.Context, arg1 int64) error {
+	ret := m.ctrl.Call(m, "DeleteUser", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUser indicates an expected call of DeleteUser
+func (mr *MockUserSessionStoreMockRecorder) DeleteUser(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUser", reflect.TypeOf((*MockUserSessionStore)(nil).DeleteUser), arg0, arg1)
+}
+
+// Find mocks base method
+func (m *MockUserSessionStore) Find(arg0 context.Context, arg1 int64) (*core.UserSession, error) {
+	ret := m.ctrl.Call(m, "Find", arg0, arg1)
+	ret0, _ := ret[0].(*core.UserSession)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Find indicates an expected call of Find
+func (mr *MockUserSessionStoreMockRecorder) Find(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Find", reflect.TypeOf((*MockUserSessionStore)(nil).Find), arg0, arg1)
+}
+
+// FindHash mocks base method
+func (m *MockUserSessionStore) FindHash(arg0 context.Context, arg1 string) (*core.UserSession, error) {
+	ret := m.ctrl.Call(m, "FindHash", arg0, arg1)
+	ret0, _ := ret[0].(*core.UserSession)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindHash indicates an expected call of FindHash
+func (mr *MockUserSessionStoreMockRecorder) FindHash(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindHash", reflect.TypeOf((*MockUserSessionStore)(nil).FindHash), arg0, arg1)
+}
+
+// List mocks base method
+func (m *MockUserSessionStore) List(arg0 context.Context, arg1 int64) ([]*core.UserSession, error) {
+	ret := m.ctrl.Call(m, "List", arg0, arg1)
+	ret0, _ := ret[0].([]*core.UserSession)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List
+func (mr *MockUserSessionStoreMockRecorder) List(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockUserSessionStore)(nil).List), arg0, arg1)
+}
+
+// Touch mocks base method
+func (m *MockUserSessionStore) Touch(arg0 context.Context, arg1 *core.UserSession) error {
+	ret := m.ctrl.Call(m, "Touch", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Touch indicates an expected call of Touch
+func (mr *MockUserSessionStoreMockRecorder) Touch(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Touch", reflect.TypeOf((*MockUserSessionStore)(nil).Touch), arg0, arg1)
+}
+
 // MockOrganizationService is a mock of OrganizationService interface
 type MockOrganizationService struct {
 	ctrl     *gomock.Controller
@@ -1655,6 +2331,19 @@ func (mr *MockOrganizationServiceMockRecorder) List(arg0, arg1 interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockOrganizationService)(nil).List), arg0, arg1)
 }
 
+// Refresh mocks base method
+func (m *MockOrganizationService) Refresh(arg0 context.Context, arg1 *core.User) ([]*core.Organization, error) {
+	ret := m.ctrl.Call(m, "Refresh", arg0, arg1)
+	ret0, _ := ret[0].([]*core.Organization)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Refresh indicates an expected call of Refresh
+func (mr *MockOrganizationServiceMockRecorder) Refresh(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Refresh", reflect.TypeOf((*MockOrganizationService)(nil).Refresh), arg0, arg1)
+}
+
 // MockSecretService is a mock of SecretService interface
 type MockSecretService struct {
 	ctrl     *gomock.Controller
@@ -1727,277 +2416,1966 @@ func (mr *MockRegistryServiceMockRecorder) List(arg0, arg1 interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockRegistryService)(nil).List), arg0, arg1)
 }
 
-// MockConfigService is a mock of ConfigService interface
-type MockConfigService struct {
+// MockPoolService is a mock of PoolService interface
+type MockPoolService struct {
 	ctrl     *gomock.Controller
-	recorder *MockConfigServiceMockRecorder
+	recorder *MockPoolServiceMockRecorder
 }
 
-// MockConfigServiceMockRecorder is the mock recorder for MockConfigService
-type MockConfigServiceMockRecorder struct {
-	mock *MockConfigService
+// MockPoolServiceMockRecorder is the mock recorder for MockPoolService
+type MockPoolServiceMockRecorder struct {
+	mock *MockPoolService
 }
 
-// NewMockConfigService creates a new mock instance
-func NewMockConfigService(ctrl *gomock.Controller) *MockConfigService {
-	mock := &MockConfigService{ctrl: ctrl}
-	mock.recorder = &MockConfigServiceMockRecorder{mock}
+// NewMockPoolService creates a new mock instance
+func NewMockPoolService(ctrl *gomock.Controller) *MockPoolService {
+	mock := &MockPoolService{ctrl: ctrl}
+	mock.recorder = &MockPoolServiceMockRecorder{mock}
 	return mock
 }
 
 // EXPECT returns an object that allows the caller to indicate expected use
-func (m *MockConfigService) EXPECT() *MockConfigServiceMockRecorder {
+func (m *MockPoolService) EXPECT() *MockPoolServiceMockRecorder {
 	return m.recorder
 }
 
 // Find mocks base method
-func (m *MockConfigService) Find(arg0 context.Context, arg1 *core.ConfigArgs) (*core.Config, error) {
+func (m *MockPoolService) Find(arg0 context.Context, arg1 string) (*core.Pool, error) {
 	ret := m.ctrl.Call(m, "Find", arg0, arg1)
-	ret0, _ := ret[0].(*core.Config)
+	ret0, _ := ret[0].(*core.Pool)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Find indicates an expected call of Find
-func (mr *MockConfigServiceMockRecorder) Find(arg0, arg1 interface{}) *gomock.Call {
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Find", reflect.TypeOf((*MockConfigService)(nil).Find), arg0, arg1)
+func (mr *MockPoolServiceMockRecorder) Find(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Find", reflect.TypeOf((*MockPoolService)(nil).Find), arg0, arg1)
 }
 
-// MockTriggerer is a mock of Triggerer interface
-type MockTriggerer struct {
+// MockPlatformService is a mock of PlatformService interface
+type MockPlatformService struct {
 	ctrl     *gomock.Controller
-	recorder *MockTriggererMockRecorder
+	recorder *MockPlatformServiceMockRecorder
 }
 
-// MockTriggererMockRecorder is the mock recorder for MockTriggerer
-type MockTriggererMockRecorder struct {
-	mock *MockTriggerer
+// MockPlatformServiceMockRecorder is the mock recorder for MockPlatformService
+type MockPlatformServiceMockRecorder struct {
+	mock *MockPlatformService
 }
 
-// NewMockTriggerer creates a new mock instance
-func NewMockTriggerer(ctrl *gomock.Controller) *MockTriggerer {
-	mock := &MockTriggerer{ctrl: ctrl}
-	mock.recorder = &MockTriggererMockRecorder{mock}
+// NewMockPlatformService creates a new mock instance
+func NewMockPlatformService(ctrl *gomock.Controller) *MockPlatformService {
+	mock := &MockPlatformService{ctrl: ctrl}
+	mock.recorder = &MockPlatformServiceMockRecorder{mock}
 	return mock
 }
 
 // EXPECT returns an object that allows the caller to indicate expected use
-func (m *MockTriggerer) EXPECT() *MockTriggererMockRecorder {
+func (m *MockPlatformService) EXPECT() *MockPlatformServiceMockRecorder {
 	return m.recorder
 }
 
-// Trigger mocks base method
-func (m *MockTriggerer) Trigger(arg0 context.Context, arg1 *core.Repository, arg2 *core.Hook) (*core.Build, error) {
-	ret := m.ctrl.Call(m, "Trigger", arg0, arg1, arg2)
-	ret0, _ := ret[0].(*core.Build)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+// Validate mocks base method
+func (m *MockPlatformService) Validate(arg0 context.Context, arg1 *core.Platform) error {
+	ret := m.ctrl.Call(m, "Validate", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// Trigger indicates an expected call of Trigger
-func (mr *MockTriggererMockRecorder) Trigger(arg0, arg1, arg2 interface{}) *gomock.Call {
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Trigger", reflect.TypeOf((*MockTriggerer)(nil).Trigger), arg0, arg1, arg2)
+// Validate indicates an expected call of Validate
+func (mr *MockPlatformServiceMockRecorder) Validate(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Validate", reflect.TypeOf((*MockPlatformService)(nil).Validate), arg0, arg1)
 }
 
-// MockSyncer is a mock of Syncer interface
-type MockSyncer struct {
+// MockAgentRegistry is a mock of AgentRegistry interface
+type MockAgentRegistry struct {
 	ctrl     *gomock.Controller
-	recorder *MockSyncerMockRecorder
+	recorder *MockAgentRegistryMockRecorder
 }
 
-// MockSyncerMockRecorder is the mock recorder for MockSyncer
-type MockSyncerMockRecorder struct {
-	mock *MockSyncer
+// MockAgentRegistryMockRecorder is the mock recorder for MockAgentRegistry
+type MockAgentRegistryMockRecorder struct {
+	mock *MockAgentRegistry
 }
 
-// NewMockSyncer creates a new mock instance
-func NewMockSyncer(ctrl *gomock.Controller) *MockSyncer {
-	mock := &MockSyncer{ctrl: ctrl}
-	mock.recorder = &MockSyncerMockRecorder{mock}
+// NewMockAgentRegistry creates a new mock instance
+func NewMockAgentRegistry(ctrl *gomock.Controller) *MockAgentRegistry {
+	mock := &MockAgentRegistry{ctrl: ctrl}
+	mock.recorder = &MockAgentRegistryMockRecorder{mock}
 	return mock
 }
 
 // EXPECT returns an object that allows the caller to indicate expected use
-func (m *MockSyncer) EXPECT() *MockSyncerMockRecorder {
+func (m *MockAgentRegistry) EXPECT() *MockAgentRegistryMockRecorder {
 	return m.recorder
 }
 
-// Sync mocks base method
-func (m *MockSyncer) Sync(arg0 context.Context, arg1 *core.User) (*core.Batch, error) {
-	ret := m.ctrl.Call(m, "Sync", arg0, arg1)
-	ret0, _ := ret[0].(*core.Batch)
+// List mocks base method
+func (m *MockAgentRegistry) List(arg0 context.Context) ([]*core.Agent, error) {
+	ret := m.ctrl.Call(m, "List", arg0)
+	ret0, _ := ret[0].([]*core.Agent)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// Sync indicates an expected call of Sync
-func (mr *MockSyncerMockRecorder) Sync(arg0, arg1 interface{}) *gomock.Call {
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Sync", reflect.TypeOf((*MockSyncer)(nil).Sync), arg0, arg1)
+// List indicates an expected call of List
+func (mr *MockAgentRegistryMockRecorder) List(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockAgentRegistry)(nil).List), arg0)
 }
 
-// MockLogStream is a mock of LogStream interface
-type MockLogStream struct {
+// Register mocks base method
+func (m *MockAgentRegistry) Register(arg0 context.Context, arg1 *core.Agent) error {
+	ret := m.ctrl.Call(m, "Register", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Register indicates an expected call of Register
+func (mr *MockAgentRegistryMockRecorder) Register(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Register", reflect.TypeOf((*MockAgentRegistry)(nil).Register), arg0, arg1)
+}
+
+// MockRegistryStore is a mock of RegistryStore interface
+type MockRegistryStore struct {
 	ctrl     *gomock.Controller
-	recorder *MockLogStreamMockRecorder
+	recorder *MockRegistryStoreMockRecorder
 }
 
-// MockLogStreamMockRecorder is the mock recorder for MockLogStream
-type MockLogStreamMockRecorder struct {
-	mock *MockLogStream
+// MockRegistryStoreMockRecorder is the mock recorder for MockRegistryStore
+type MockRegistryStoreMockRecorder struct {
+	mock *MockRegistryStore
 }
 
-// NewMockLogStream creates a new mock instance
-func NewMockLogStream(ctrl *gomock.Controller) *MockLogStream {
-	mock := &MockLogStream{ctrl: ctrl}
-	mock.recorder = &MockLogStreamMockRecorder{mock}
+// NewMockRegistryStore creates a new mock instance
+func NewMockRegistryStore(ctrl *gomock.Controller) *MockRegistryStore {
+	mock := &MockRegistryStore{ctrl: ctrl}
+	mock.recorder = &MockRegistryStoreMockRecorder{mock}
 	return mock
 }
 
 // EXPECT returns an object that allows the caller to indicate expected use
-func (m *MockLogStream) EXPECT() *MockLogStreamMockRecorder {
+func (m *MockRegistryStore) EXPECT() *MockRegistryStoreMockRecorder {
 	return m.recorder
 }
 
 // Create mocks base method
-func (m *MockLogStream) Create(arg0 context.Context, arg1 int64) error {
+func (m *MockRegistryStore) Create(arg0 context.Context, arg1 *core.Registry) error {
 	ret := m.ctrl.Call(m, "Create", arg0, arg1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Create indicates an expected call of Create
-func (mr *MockLogStreamMockRecorder) Create(arg0, arg1 interface{}) *gomock.Call {
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockLogStream)(nil).Create), arg0, arg1)
+func (mr *MockRegistryStoreMockRecorder) Create(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockRegistryStore)(nil).Create), arg0, arg1)
 }
 
 // Delete mocks base method
-func (m *MockLogStream) Delete(arg0 context.Context, arg1 int64) error {
+func (m *MockRegistryStore) Delete(arg0 context.Context, arg1 *core.Registry) error {
 	ret := m.ctrl.Call(m, "Delete", arg0, arg1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Delete indicates an expected call of Delete
-func (mr *MockLogStreamMockRecorder) Delete(arg0, arg1 interface{}) *gomock.Call {
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockLogStream)(nil).Delete), arg0, arg1)
+func (mr *MockRegistryStoreMockRecorder) Delete(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockRegistryStore)(nil).Delete), arg0, arg1)
 }
 
-// Info mocks base method
-func (m *MockLogStream) Info(arg0 context.Context) *core.LogStreamInfo {
-	ret := m.ctrl.Call(m, "Info", arg0)
-	ret0, _ := ret[0].(*core.LogStreamInfo)
+// Find mocks base method
+func (m *MockRegistryStore) Find(arg0 context.Context, arg1 int64) (*core.Registry, error) {
+	ret := m.ctrl.Call(m, "Find", arg0, arg1)
+	ret0, _ := ret[0].(*core.Registry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Find indicates an expected call of Find
+func (mr *MockRegistryStoreMockRecorder) Find(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Find", reflect.TypeOf((*MockRegistryStore)(nil).Find), arg0, arg1)
+}
+
+// List mocks base method
+func (m *MockRegistryStore) List(arg0 context.Context, arg1 int64) ([]*core.Registry, error) {
+	ret := m.ctrl.Call(m, "List", arg0, arg1)
+	ret0, _ := ret[0].([]*core.Registry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List
+func (mr *MockRegistryStoreMockRecorder) List(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockRegistryStore)(nil).List), arg0, arg1)
+}
+
+// ListNamespace mocks base method
+func (m *MockRegistryStore) ListNamespace(arg0 context.Context, arg1 string) ([]*core.Registry, error) {
+	ret := m.ctrl.Call(m, "ListNamespace", arg0, arg1)
+	ret0, _ := ret[0].([]*core.Registry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListNamespace indicates an expected call of ListNamespace
+func (mr *MockRegistryStoreMockRecorder) ListNamespace(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListNamespace", reflect.TypeOf((*MockRegistryStore)(nil).ListNamespace), arg0, arg1)
+}
+
+// Rotate mocks base method
+func (m *MockRegistryStore) Rotate(arg0 context.Context) (int64, error) {
+	ret := m.ctrl.Call(m, "Rotate", arg0)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Rotate indicates an expected call of Rotate
+func (mr *MockRegistryStoreMockRecorder) Rotate(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rotate", reflect.TypeOf((*MockRegistryStore)(nil).Rotate), arg0)
+}
+
+// Update mocks base method
+func (m *MockRegistryStore) Update(arg0 context.Context, arg1 *core.Registry) error {
+	ret := m.ctrl.Call(m, "Update", arg0, arg1)
+	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// Info indicates an expected call of Info
-func (mr *MockLogStreamMockRecorder) Info(arg0 interface{}) *gomock.Call {
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Info", reflect.TypeOf((*MockLogStream)(nil).Info), arg0)
+// Update indicates an expected call of Update
+func (mr *MockRegistryStoreMockRecorder) Update(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockRegistryStore)(nil).Update), arg0, arg1)
 }
 
-// Tail mocks base method
-func (m *MockLogStream) Tail(arg0 context.Context, arg1 int64) (<-chan *core.Line, <-chan error) {
-	ret := m.ctrl.Call(m, "Tail", arg0, arg1)
-	ret0, _ := ret[0].(<-chan *core.Line)
-	ret1, _ := ret[1].(<-chan error)
+// MockPolicyStore is a mock of PolicyStore interface
+type MockPolicyStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockPolicyStoreMockRecorder
+}
+
+// MockPolicyStoreMockRecorder is the mock recorder for MockPolicyStore
+type MockPolicyStoreMockRecorder struct {
+	mock *MockPolicyStore
+}
+
+// NewMockPolicyStore creates a new mock instance
+func NewMockPolicyStore(ctrl *gomock.Controller) *MockPolicyStore {
+	mock := &MockPolicyStore{ctrl: ctrl}
+	mock.recorder = &MockPolicyStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockPolicyStore) EXPECT() *MockPolicyStoreMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method
+func (m *MockPolicyStore) Create(arg0 context.Context, arg1 *core.Policy) error {
+	ret := m.ctrl.Call(m, "Create", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create
+func (mr *MockPolicyStoreMockRecorder) Create(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockPolicyStore)(nil).Create), arg0, arg1)
+}
+
+// Delete mocks base method
+func (m *MockPolicyStore) Delete(arg0 context.Context, arg1 *core.Policy) error {
+	ret := m.ctrl.Call(m, "Delete", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete
+func (mr *MockPolicyStoreMockRecorder) Delete(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockPolicyStore)(nil).Delete), arg0, arg1)
+}
+
+// Find mocks base method
+func (m *MockPolicyStore) Find(arg0 context.Context, arg1 string) (*core.Policy, error) {
+	ret := m.ctrl.Call(m, "Find", arg0, arg1)
+	ret0, _ := ret[0].(*core.Policy)
+	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// Tail indicates an expected call of Tail
-func (mr *MockLogStreamMockRecorder) Tail(arg0, arg1 interface{}) *gomock.Call {
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Tail", reflect.TypeOf((*MockLogStream)(nil).Tail), arg0, arg1)
+// Find indicates an expected call of Find
+func (mr *MockPolicyStoreMockRecorder) Find(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Find", reflect.TypeOf((*MockPolicyStore)(nil).Find), arg0, arg1)
 }
 
-// Write mocks base method
-func (m *MockLogStream) Write(arg0 context.Context, arg1 int64, arg2 *core.Line) error {
-	ret := m.ctrl.Call(m, "Write", arg0, arg1, arg2)
+// List mocks base method
+func (m *MockPolicyStore) List(arg0 context.Context) ([]*core.Policy, error) {
+	ret := m.ctrl.Call(m, "List", arg0)
+	ret0, _ := ret[0].([]*core.Policy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List
+func (mr *MockPolicyStoreMockRecorder) List(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockPolicyStore)(nil).List), arg0)
+}
+
+// Update mocks base method
+func (m *MockPolicyStore) Update(arg0 context.Context, arg1 *core.Policy) error {
+	ret := m.ctrl.Call(m, "Update", arg0, arg1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// Write indicates an expected call of Write
-func (mr *MockLogStreamMockRecorder) Write(arg0, arg1, arg2 interface{}) *gomock.Call {
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Write", reflect.TypeOf((*MockLogStream)(nil).Write), arg0, arg1, arg2)
+// Update indicates an expected call of Update
+func (mr *MockPolicyStoreMockRecorder) Update(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockPolicyStore)(nil).Update), arg0, arg1)
 }
 
-// MockWebhookSender is a mock of WebhookSender interface
-type MockWebhookSender struct {
+// MockPrivilegedImageStore is a mock of PrivilegedImageStore interface
+type MockPrivilegedImageStore struct {
 	ctrl     *gomock.Controller
-	recorder *MockWebhookSenderMockRecorder
+	recorder *MockPrivilegedImageStoreMockRecorder
 }
 
-// MockWebhookSenderMockRecorder is the mock recorder for MockWebhookSender
-type MockWebhookSenderMockRecorder struct {
-	mock *MockWebhookSender
+// MockPrivilegedImageStoreMockRecorder is the mock recorder for MockPrivilegedImageStore
+type MockPrivilegedImageStoreMockRecorder struct {
+	mock *MockPrivilegedImageStore
 }
 
-// NewMockWebhookSender creates a new mock instance
-func NewMockWebhookSender(ctrl *gomock.Controller) *MockWebhookSender {
-	mock := &MockWebhookSender{ctrl: ctrl}
-	mock.recorder = &MockWebhookSenderMockRecorder{mock}
+// NewMockPrivilegedImageStore creates a new mock instance
+func NewMockPrivilegedImageStore(ctrl *gomock.Controller) *MockPrivilegedImageStore {
+	mock := &MockPrivilegedImageStore{ctrl: ctrl}
+	mock.recorder = &MockPrivilegedImageStoreMockRecorder{mock}
 	return mock
 }
 
 // EXPECT returns an object that allows the caller to indicate expected use
-func (m *MockWebhookSender) EXPECT() *MockWebhookSenderMockRecorder {
+func (m *MockPrivilegedImageStore) EXPECT() *MockPrivilegedImageStoreMockRecorder {
 	return m.recorder
 }
 
-// Send mocks base method
-func (m *MockWebhookSender) Send(arg0 context.Context, arg1 *core.WebhookData) error {
-	ret := m.ctrl.Call(m, "Send", arg0, arg1)
+// Create mocks base method
+func (m *MockPrivilegedImageStore) Create(arg0 context.Context, arg1 *core.PrivilegedImage) error {
+	ret := m.ctrl.Call(m, "Create", arg0, arg1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// Send indicates an expected call of Send
-func (mr *MockWebhookSenderMockRecorder) Send(arg0, arg1 interface{}) *gomock.Call {
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Send", reflect.TypeOf((*MockWebhookSender)(nil).Send), arg0, arg1)
+// Create indicates an expected call of Create
+func (mr *MockPrivilegedImageStoreMockRecorder) Create(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockPrivilegedImageStore)(nil).Create), arg0, arg1)
 }
 
-// MockLicenseService is a mock of LicenseService interface
-type MockLicenseService struct {
+// Delete mocks base method
+func (m *MockPrivilegedImageStore) Delete(arg0 context.Context, arg1 *core.PrivilegedImage) error {
+	ret := m.ctrl.Call(m, "Delete", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete
+func (mr *MockPrivilegedImageStoreMockRecorder) Delete(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockPrivilegedImageStore)(nil).Delete), arg0, arg1)
+}
+
+// Find mocks base method
+func (m *MockPrivilegedImageStore) Find(arg0 context.Context, arg1 int64) (*core.PrivilegedImage, error) {
+	ret := m.ctrl.Call(m, "Find", arg0, arg1)
+	ret0, _ := ret[0].(*core.PrivilegedImage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Find indicates an expected call of Find
+func (mr *MockPrivilegedImageStoreMockRecorder) Find(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Find", reflect.TypeOf((*MockPrivilegedImageStore)(nil).Find), arg0, arg1)
+}
+
+// List mocks base method
+func (m *MockPrivilegedImageStore) List(arg0 context.Context) ([]*core.PrivilegedImage, error) {
+	ret := m.ctrl.Call(m, "List", arg0)
+	ret0, _ := ret[0].([]*core.PrivilegedImage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List
+func (mr *MockPrivilegedImageStoreMockRecorder) List(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockPrivilegedImageStore)(nil).List), arg0)
+}
+
+// ListNamespace mocks base method
+func (m *MockPrivilegedImageStore) ListNamespace(arg0 context.Context, arg1 string) ([]*core.PrivilegedImage, error) {
+	ret := m.ctrl.Call(m, "ListNamespace", arg0, arg1)
+	ret0, _ := ret[0].([]*core.PrivilegedImage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListNamespace indicates an expected call of ListNamespace
+func (mr *MockPrivilegedImageStoreMockRecorder) ListNamespace(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListNamespace", reflect.TypeOf((*MockPrivilegedImageStore)(nil).ListNamespace), arg0, arg1)
+}
+
+// MockVariableStore is a mock of VariableStore interface
+type MockVariableStore struct {
 	ctrl     *gomock.Controller
-	recorder *MockLicenseServiceMockRecorder
+	recorder *MockVariableStoreMockRecorder
 }
 
-// MockLicenseServiceMockRecorder is the mock recorder for MockLicenseService
-type MockLicenseServiceMockRecorder struct {
-	mock *MockLicenseService
+// MockVariableStoreMockRecorder is the mock recorder for MockVariableStore
+type MockVariableStoreMockRecorder struct {
+	mock *MockVariableStore
 }
 
-// NewMockLicenseService creates a new mock instance
-func NewMockLicenseService(ctrl *gomock.Controller) *MockLicenseService {
-	mock := &MockLicenseService{ctrl: ctrl}
-	mock.recorder = &MockLicenseServiceMockRecorder{mock}
+// NewMockVariableStore creates a new mock instance
+func NewMockVariableStore(ctrl *gomock.Controller) *MockVariableStore {
+	mock := &MockVariableStore{ctrl: ctrl}
+	mock.recorder = &MockVariableStoreMockRecorder{mock}
 	return mock
 }
 
 // EXPECT returns an object that allows the caller to indicate expected use
-func (m *MockLicenseService) EXPECT() *MockLicenseServiceMockRecorder {
+func (m *MockVariableStore) EXPECT() *MockVariableStoreMockRecorder {
 	return m.recorder
 }
 
-// Exceeded mocks base method
-func (m *MockLicenseService) Exceeded(arg0 context.Context) (bool, error) {
-	ret := m.ctrl.Call(m, "Exceeded", arg0)
-	ret0, _ := ret[0].(bool)
+// Create mocks base method
+func (m *MockVariableStore) Create(arg0 context.Context, arg1 *core.Variable) error {
+	ret := m.ctrl.Call(m, "Create", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create
+func (mr *MockVariableStoreMockRecorder) Create(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockVariableStore)(nil).Create), arg0, arg1)
+}
+
+// Delete mocks base method
+func (m *MockVariableStore) Delete(arg0 context.Context, arg1 *core.Variable) error {
+	ret := m.ctrl.Call(m, "Delete", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete
+func (mr *MockVariableStoreMockRecorder) Delete(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockVariableStore)(nil).Delete), arg0, arg1)
+}
+
+// Find mocks base method
+func (m *MockVariableStore) Find(arg0 context.Context, arg1 int64) (*core.Variable, error) {
+	ret := m.ctrl.Call(m, "Find", arg0, arg1)
+	ret0, _ := ret[0].(*core.Variable)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// Exceeded indicates an expected call of Exceeded
-func (mr *MockLicenseServiceMockRecorder) Exceeded(arg0 interface{}) *gomock.Call {
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Exceeded", reflect.TypeOf((*MockLicenseService)(nil).Exceeded), arg0)
+// Find indicates an expected call of Find
+func (mr *MockVariableStoreMockRecorder) Find(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Find", reflect.TypeOf((*MockVariableStore)(nil).Find), arg0, arg1)
 }
 
-// Expired mocks base method
-func (m *MockLicenseService) Expired(arg0 context.Context) bool {
-	ret := m.ctrl.Call(m, "Expired", arg0)
-	ret0, _ := ret[0].(bool)
+// List mocks base method
+func (m *MockVariableStore) List(arg0 context.Context) ([]*core.Variable, error) {
+	ret := m.ctrl.Call(m, "List", arg0)
+	ret0, _ := ret[0].([]*core.Variable)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List
+func (mr *MockVariableStoreMockRecorder) List(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockVariableStore)(nil).List), arg0)
+}
+
+// ListNamespace mocks base method
+func (m *MockVariableStore) ListNamespace(arg0 context.Context, arg1 string) ([]*core.Variable, error) {
+	ret := m.ctrl.Call(m, "ListNamespace", arg0, arg1)
+	ret0, _ := ret[0].([]*core.Variable)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListNamespace indicates an expected call of ListNamespace
+func (mr *MockVariableStoreMockRecorder) ListNamespace(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListNamespace", reflect.TypeOf((*MockVariableStore)(nil).ListNamespace), arg0, arg1)
+}
+
+// ListRepo mocks base method
+func (m *MockVariableStore) ListRepo(arg0 context.Context, arg1 int64) ([]*core.Variable, error) {
+	ret := m.ctrl.Call(m, "ListRepo", arg0, arg1)
+	ret0, _ := ret[0].([]*core.Variable)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRepo indicates an expected call of ListRepo
+func (mr *MockVariableStoreMockRecorder) ListRepo(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRepo", reflect.TypeOf((*MockVariableStore)(nil).ListRepo), arg0, arg1)
+}
+
+// Rotate mocks base method
+func (m *MockVariableStore) Rotate(arg0 context.Context) (int64, error) {
+	ret := m.ctrl.Call(m, "Rotate", arg0)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Rotate indicates an expected call of Rotate
+func (mr *MockVariableStoreMockRecorder) Rotate(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rotate", reflect.TypeOf((*MockVariableStore)(nil).Rotate), arg0)
+}
+
+// Update mocks base method
+func (m *MockVariableStore) Update(arg0 context.Context, arg1 *core.Variable) error {
+	ret := m.ctrl.Call(m, "Update", arg0, arg1)
+	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// Expired indicates an expected call of Expired
-func (mr *MockLicenseServiceMockRecorder) Expired(arg0 interface{}) *gomock.Call {
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Expired", reflect.TypeOf((*MockLicenseService)(nil).Expired), arg0)
+// Update indicates an expected call of Update
+func (mr *MockVariableStoreMockRecorder) Update(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockVariableStore)(nil).Update), arg0, arg1)
+}
+
+// MockPathMappingStore is a mock of PathMappingStore interface
+type MockPathMappingStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockPathMappingStoreMockRecorder
+}
+
+// MockPathMappingStoreMockRecorder is the mock recorder for MockPathMappingStore
+type MockPathMappingStoreMockRecorder struct {
+	mock *MockPathMappingStore
+}
+
+// NewMockPathMappingStore creates a new mock instance
+func NewMockPathMappingStore(ctrl *gomock.Controller) *MockPathMappingStore {
+	mock := &MockPathMappingStore{ctrl: ctrl}
+	mock.recorder = &MockPathMappingStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockPathMappingStore) EXPECT() *MockPathMappingStoreMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method
+func (m *MockPathMappingStore) Create(arg0 context.Context, arg1 *core.PathMapping) error {
+	ret := m.ctrl.Call(m, "Create", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create
+func (mr *MockPathMappingStoreMockRecorder) Create(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockPathMappingStore)(nil).Create), arg0, arg1)
+}
+
+// Delete mocks base method
+func (m *MockPathMappingStore) Delete(arg0 context.Context, arg1 *core.PathMapping) error {
+	ret := m.ctrl.Call(m, "Delete", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete
+func (mr *MockPathMappingStoreMockRecorder) Delete(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockPathMappingStore)(nil).Delete), arg0, arg1)
+}
+
+// Find mocks base method
+func (m *MockPathMappingStore) Find(arg0 context.Context, arg1 int64) (*core.PathMapping, error) {
+	ret := m.ctrl.Call(m, "Find", arg0, arg1)
+	ret0, _ := ret[0].(*core.PathMapping)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Find indicates an expected call of Find
+func (mr *MockPathMappingStoreMockRecorder) Find(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Find", reflect.TypeOf((*MockPathMappingStore)(nil).Find), arg0, arg1)
+}
+
+// List mocks base method
+func (m *MockPathMappingStore) List(arg0 context.Context, arg1 int64) ([]*core.PathMapping, error) {
+	ret := m.ctrl.Call(m, "List", arg0, arg1)
+	ret0, _ := ret[0].([]*core.PathMapping)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List
+func (mr *MockPathMappingStoreMockRecorder) List(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockPathMappingStore)(nil).List), arg0, arg1)
+}
+
+// MockInsightStore is a mock of InsightStore interface
+type MockInsightStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockInsightStoreMockRecorder
+}
+
+// MockInsightStoreMockRecorder is the mock recorder for MockInsightStore
+type MockInsightStoreMockRecorder struct {
+	mock *MockInsightStore
+}
+
+// NewMockInsightStore creates a new mock instance
+func NewMockInsightStore(ctrl *gomock.Controller) *MockInsightStore {
+	mock := &MockInsightStore{ctrl: ctrl}
+	mock.recorder = &MockInsightStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockInsightStore) EXPECT() *MockInsightStoreMockRecorder {
+	return m.recorder
+}
+
+// ListDays mocks base method
+func (m *MockInsightStore) ListDays(arg0 context.Context, arg1 int64, arg2 int64) ([]*core.InsightDay, error) {
+	ret := m.ctrl.Call(m, "ListDays", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]*core.InsightDay)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDays indicates an expected call of ListDays
+func (mr *MockInsightStoreMockRecorder) ListDays(arg0, arg1, arg2 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDays", reflect.TypeOf((*MockInsightStore)(nil).ListDays), arg0, arg1, arg2)
+}
+
+// ListDurations mocks base method
+func (m *MockInsightStore) ListDurations(arg0 context.Context, arg1 int64, arg2 int64) ([]*core.InsightBucket, error) {
+	ret := m.ctrl.Call(m, "ListDurations", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]*core.InsightBucket)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDurations indicates an expected call of ListDurations
+func (mr *MockInsightStoreMockRecorder) ListDurations(arg0, arg1, arg2 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDurations", reflect.TypeOf((*MockInsightStore)(nil).ListDurations), arg0, arg1, arg2)
+}
+
+// ListFlaky mocks base method
+func (m *MockInsightStore) ListFlaky(arg0 context.Context, arg1 int64, arg2 string, arg3 int64, arg4 int) ([]*core.InsightFlaky, error) {
+	ret := m.ctrl.Call(m, "ListFlaky", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].([]*core.InsightFlaky)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListFlaky indicates an expected call of ListFlaky
+func (mr *MockInsightStoreMockRecorder) ListFlaky(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListFlaky", reflect.TypeOf((*MockInsightStore)(nil).ListFlaky), arg0, arg1, arg2, arg3, arg4)
+}
+
+// ListSteps mocks base method
+func (m *MockInsightStore) ListSteps(arg0 context.Context, arg1 int64, arg2 int64, arg3 int) ([]*core.InsightStep, error) {
+	ret := m.ctrl.Call(m, "ListSteps", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]*core.InsightStep)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSteps indicates an expected call of ListSteps
+func (mr *MockInsightStoreMockRecorder) ListSteps(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSteps", reflect.TypeOf((*MockInsightStore)(nil).ListSteps), arg0, arg1, arg2, arg3)
+}
+
+// Record mocks base method
+func (m *MockInsightStore) Record(arg0 context.Context, arg1 *core.Build, arg2 []*core.Stage) error {
+	ret := m.ctrl.Call(m, "Record", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Record indicates an expected call of Record
+func (mr *MockInsightStoreMockRecorder) Record(arg0, arg1, arg2 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Record", reflect.TypeOf((*MockInsightStore)(nil).Record), arg0, arg1, arg2)
+}
+
+// MockConfigService is a mock of ConfigService interface
+type MockConfigService struct {
+	ctrl     *gomock.Controller
+	recorder *MockConfigServiceMockRecorder
+}
+
+// MockConfigServiceMockRecorder is the mock recorder for MockConfigService
+type MockConfigServiceMockRecorder struct {
+	mock *MockConfigService
+}
+
+// NewMockConfigService creates a new mock instance
+func NewMockConfigService(ctrl *gomock.Controller) *MockConfigService {
+	mock := &MockConfigService{ctrl: ctrl}
+	mock.recorder = &MockConfigServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockConfigService) EXPECT() *MockConfigServiceMockRecorder {
+	return m.recorder
+}
+
+// Find mocks base method
+func (m *MockConfigService) Find(arg0 context.Context, arg1 *core.ConfigArgs) (*core.Config, error) {
+	ret := m.ctrl.Call(m, "Find", arg0, arg1)
+	ret0, _ := ret[0].(*core.Config)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Find indicates an expected call of Find
+func (mr *MockConfigServiceMockRecorder) Find(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Find", reflect.TypeOf((*MockConfigService)(nil).Find), arg0, arg1)
+}
+
+// MockTriggerer is a mock of Triggerer interface
+type MockTriggerer struct {
+	ctrl     *gomock.Controller
+	recorder *MockTriggererMockRecorder
+}
+
+// MockTriggererMockRecorder is the mock recorder for MockTriggerer
+type MockTriggererMockRecorder struct {
+	mock *MockTriggerer
+}
+
+// NewMockTriggerer creates a new mock instance
+func NewMockTriggerer(ctrl *gomock.Controller) *MockTriggerer {
+	mock := &MockTriggerer{ctrl: ctrl}
+	mock.recorder = &MockTriggererMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockTriggerer) EXPECT() *MockTriggererMockRecorder {
+	return m.recorder
+}
+
+// Trigger mocks base method
+func (m *MockTriggerer) Trigger(arg0 context.Context, arg1 *core.Repository, arg2 *core.Hook) (*core.Build, error) {
+	ret := m.ctrl.Call(m, "Trigger", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*core.Build)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Trigger indicates an expected call of Trigger
+func (mr *MockTriggererMockRecorder) Trigger(arg0, arg1, arg2 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Trigger", reflect.TypeOf((*MockTriggerer)(nil).Trigger), arg0, arg1, arg2)
+}
+
+// MockSyncer is a mock of Syncer interface
+type MockSyncer struct {
+	ctrl     *gomock.Controller
+	recorder *MockSyncerMockRecorder
+}
+
+// MockSyncerMockRecorder is the mock recorder for MockSyncer
+type MockSyncerMockRecorder struct {
+	mock *MockSyncer
+}
+
+// NewMockSyncer creates a new mock instance
+func NewMockSyncer(ctrl *gomock.Controller) *MockSyncer {
+	mock := &MockSyncer{ctrl: ctrl}
+	mock.recorder = &MockSyncerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockSyncer) EXPECT() *MockSyncerMockRecorder {
+	return m.recorder
+}
+
+// Sync mocks base method
+func (m *MockSyncer) Sync(arg0 context.Context, arg1 *core.User) (*core.Batch, error) {
+	ret := m.ctrl.Call(m, "Sync", arg0, arg1)
+	ret0, _ := ret[0].(*core.Batch)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Sync indicates an expected call of Sync
+func (mr *MockSyncerMockRecorder) Sync(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Sync", reflect.TypeOf((*MockSyncer)(nil).Sync), arg0, arg1)
+}
+
+// MockLogStream is a mock of LogStream interface
+type MockLogStream struct {
+	ctrl     *gomock.Controller
+	recorder *MockLogStreamMockRecorder
+}
+
+// MockLogStreamMockRecorder is the mock recorder for MockLogStream
+type MockLogStreamMockRecorder struct {
+	mock *MockLogStream
+}
+
+// NewMockLogStream creates a new mock instance
+func NewMockLogStream(ctrl *gomock.Controller) *MockLogStream {
+	mock := &MockLogStream{ctrl: ctrl}
+	mock.recorder = &MockLogStreamMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockLogStream) EXPECT() *MockLogStreamMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method
+func (m *MockLogStream) Create(arg0 context.Context, arg1 int64) error {
+	ret := m.ctrl.Call(m, "Create", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create
+func (mr *MockLogStreamMockRecorder) Create(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockLogStream)(nil).Create), arg0, arg1)
+}
+
+// Delete mocks base method
+func (m *MockLogStream) Delete(arg0 context.Context, arg1 int64) error {
+	ret := m.ctrl.Call(m, "Delete", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete
+func (mr *MockLogStreamMockRecorder) Delete(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockLogStream)(nil).Delete), arg0, arg1)
+}
+
+// Info mocks base method
+func (m *MockLogStream) Info(arg0 context.Context) *core.LogStreamInfo {
+	ret := m.ctrl.Call(m, "Info", arg0)
+	ret0, _ := ret[0].(*core.LogStreamInfo)
+	return ret0
+}
+
+// Info indicates an expected call of Info
+func (mr *MockLogStreamMockRecorder) Info(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Info", reflect.TypeOf((*MockLogStream)(nil).Info), arg0)
+}
+
+// Tail mocks base method
+func (m *MockLogStream) Tail(arg0 context.Context, arg1 int64) (<-chan *core.Line, <-chan error) {
+	ret := m.ctrl.Call(m, "Tail", arg0, arg1)
+	ret0, _ := ret[0].(<-chan *core.Line)
+	ret1, _ := ret[1].(<-chan error)
+	return ret0, ret1
+}
+
+// Tail indicates an expected call of Tail
+func (mr *MockLogStreamMockRecorder) Tail(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Tail", reflect.TypeOf((*MockLogStream)(nil).Tail), arg0, arg1)
+}
+
+// Write mocks base method
+func (m *MockLogStream) Write(arg0 context.Context, arg1 int64, arg2 *core.Line) error {
+	ret := m.ctrl.Call(m, "Write", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Write indicates an expected call of Write
+func (mr *MockLogStreamMockRecorder) Write(arg0, arg1, arg2 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Write", reflect.TypeOf((*MockLogStream)(nil).Write), arg0, arg1, arg2)
+}
+
+// MockWebhookSender is a mock of WebhookSender interface
+type MockWebhookSender struct {
+	ctrl     *gomock.Controller
+	recorder *MockWebhookSenderMockRecorder
+}
+
+// MockWebhookSenderMockRecorder is the mock recorder for MockWebhookSender
+type MockWebhookSenderMockRecorder struct {
+	mock *MockWebhookSender
+}
+
+// NewMockWebhookSender creates a new mock instance
+func NewMockWebhookSender(ctrl *gomock.Controller) *MockWebhookSender {
+	mock := &MockWebhookSender{ctrl: ctrl}
+	mock.recorder = &MockWebhookSenderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockWebhookSender) EXPECT() *MockWebhookSenderMockRecorder {
+	return m.recorder
+}
+
+// Send mocks base method
+func (m *MockWebhookSender) Send(arg0 context.Context, arg1 *core.WebhookData) error {
+	ret := m.ctrl.Call(m, "Send", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Send indicates an expected call of Send
+func (mr *MockWebhookSenderMockRecorder) Send(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Send", reflect.TypeOf((*MockWebhookSender)(nil).Send), arg0, arg1)
+}
+
+// MockWebhookDeliveryStore is a mock of WebhookDeliveryStore interface
+type MockWebhookDeliveryStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockWebhookDeliveryStoreMockRecorder
+}
+
+// MockWebhookDeliveryStoreMockRecorder is the mock recorder for MockWebhookDeliveryStore
+type MockWebhookDeliveryStoreMockRecorder struct {
+	mock *MockWebhookDeliveryStore
+}
+
+// NewMockWebhookDeliveryStore creates a new mock instance
+func NewMockWebhookDeliveryStore(ctrl *gomock.Controller) *MockWebhookDeliveryStore {
+	mock := &MockWebhookDeliveryStore{ctrl: ctrl}
+	mock.recorder = &MockWebhookDeliveryStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockWebhookDeliveryStore) EXPECT() *MockWebhookDeliveryStoreMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method
+func (m *MockWebhookDeliveryStore) Create(arg0 context.Context, arg1 *core.WebhookDelivery) error {
+	ret := m.ctrl.Call(m, "Create", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create
+func (mr *MockWebhookDeliveryStoreMockRecorder) Create(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockWebhookDeliveryStore)(nil).Create), arg0, arg1)
+}
+
+// Find mocks base method
+func (m *MockWebhookDeliveryStore) Find(arg0 context.Context, arg1 int64) (*core.WebhookDelivery, error) {
+	ret := m.ctrl.Call(m, "Find", arg0, arg1)
+	ret0, _ := ret[0].(*core.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Find indicates an expected call of Find
+func (mr *MockWebhookDeliveryStoreMockRecorder) Find(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Find", reflect.TypeOf((*MockWebhookDeliveryStore)(nil).Find), arg0, arg1)
+}
+
+// List mocks base method
+func (m *MockWebhookDeliveryStore) List(arg0 context.Context, arg1 core.WebhookDeliveryFilter) ([]*core.WebhookDelivery, error) {
+	ret := m.ctrl.Call(m, "List", arg0, arg1)
+	ret0, _ := ret[0].([]*core.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List
+func (mr *MockWebhookDeliveryStoreMockRecorder) List(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockWebhookDeliveryStore)(nil).List), arg0, arg1)
+}
+
+// ListDue mocks base method
+func (m *MockWebhookDeliveryStore) ListDue(arg0 context.Context, arg1 int64) ([]*core.WebhookDelivery, error) {
+	ret := m.ctrl.Call(m, "ListDue", arg0, arg1)
+	ret0, _ := ret[0].([]*core.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDue indicates an expected call of ListDue
+func (mr *MockWebhookDeliveryStoreMockRecorder) ListDue(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDue", reflect.TypeOf((*MockWebhookDeliveryStore)(nil).ListDue), arg0, arg1)
+}
+
+// Purge mocks base method
+func (m *MockWebhookDeliveryStore) Purge(arg0 context.Context, arg1 int64) error {
+	ret := m.ctrl.Call(m, "Purge", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Purge indicates an expected call of Purge
+func (mr *MockWebhookDeliveryStoreMockRecorder) Purge(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Purge", reflect.TypeOf((*MockWebhookDeliveryStore)(nil).Purge), arg0, arg1)
+}
+
+// Update mocks base method
+func (m *MockWebhookDeliveryStore) Update(arg0 context.Context, arg1 *core.WebhookDelivery) error {
+	ret := m.ctrl.Call(m, "Update", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update
+func (mr *MockWebhookDeliveryStoreMockRecorder) Update(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockWebhookDeliveryStore)(nil).Update), arg0, arg1)
+}
+
+// MockRepoWebhookStore is a mock of RepoWebhookStore interface
+type MockRepoWebhookStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockRepoWebhookStoreMockRecorder
+}
+
+// MockRepoWebhookStoreMockRecorder is the mock recorder for MockRepoWebhookStore
+type MockRepoWebhookStoreMockRecorder struct {
+	mock *MockRepoWebhookStore
+}
+
+// NewMockRepoWebhookStore creates a new mock instance
+func NewMockRepoWebhookStore(ctrl *gomock.Controller) *MockRepoWebhookStore {
+	mock := &MockRepoWebhookStore{ctrl: ctrl}
+	mock.recorder = &MockRepoWebhookStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockRepoWebhookStore) EXPECT() *MockRepoWebhookStoreMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method
+func (m *MockRepoWebhookStore) Create(arg0 context.Context, arg1 *core.RepoWebhook) error {
+	ret := m.ctrl.Call(m, "Create", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create
+func (mr *MockRepoWebhookStoreMockRecorder) Create(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockRepoWebhookStore)(nil).Create), arg0, arg1)
+}
+
+// Delete mocks base method
+func (m *MockRepoWebhookStore) Delete(arg0 context.Context, arg1 *core.RepoWebhook) error {
+	ret := m.ctrl.Call(m, "Delete", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete
+func (mr *MockRepoWebhookStoreMockRecorder) Delete(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockRepoWebhookStore)(nil).Delete), arg0, arg1)
+}
+
+// Find mocks base method
+func (m *MockRepoWebhookStore) Find(arg0 context.Context, arg1 int64) (*core.RepoWebhook, error) {
+	ret := m.ctrl.Call(m, "Find", arg0, arg1)
+	ret0, _ := ret[0].(*core.RepoWebhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Find indicates an expected call of Find
+func (mr *MockRepoWebhookStoreMockRecorder) Find(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Find", reflect.TypeOf((*MockRepoWebhookStore)(nil).Find), arg0, arg1)
+}
+
+// List mocks base method
+func (m *MockRepoWebhookStore) List(arg0 context.Context, arg1 int64) ([]*core.RepoWebhook, error) {
+	ret := m.ctrl.Call(m, "List", arg0, arg1)
+	ret0, _ := ret[0].([]*core.RepoWebhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List
+func (mr *MockRepoWebhookStoreMockRecorder) List(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockRepoWebhookStore)(nil).List), arg0, arg1)
+}
+
+// Rotate mocks base method
+func (m *MockRepoWebhookStore) Rotate(arg0 context.Context) (int64, error) {
+	ret := m.ctrl.Call(m, "Rotate", arg0)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Rotate indicates an expected call of Rotate
+func (mr *MockRepoWebhookStoreMockRecorder) Rotate(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rotate", reflect.TypeOf((*MockRepoWebhookStore)(nil).Rotate), arg0)
+}
+
+// Update mocks base method
+func (m *MockRepoWebhookStore) Update(arg0 context.Context, arg1 *core.RepoWebhook) error {
+	ret := m.ctrl.Call(m, "Update", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update
+func (mr *MockRepoWebhookStoreMockRecorder) Update(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockRepoWebhookStore)(nil).Update), arg0, arg1)
+}
+
+// MockNotificationStore is a mock of NotificationStore interface
+type MockNotificationStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockNotificationStoreMockRecorder
+}
+
+// MockNotificationStoreMockRecorder is the mock recorder for MockNotificationStore
+type MockNotificationStoreMockRecorder struct {
+	mock *MockNotificationStore
+}
+
+// NewMockNotificationStore creates a new mock instance
+func NewMockNotificationStore(ctrl *gomock.Controller) *MockNotificationStore {
+	mock := &MockNotificationStore{ctrl: ctrl}
+	mock.recorder = &MockNotificationStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockNotificationStore) EXPECT() *MockNotificationStoreMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method
+func (m *MockNotificationStore) Create(arg0 context.Context, arg1 *core.Notification) error {
+	ret := m.ctrl.Call(m, "Create", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create
+func (mr *MockNotificationStoreMockRecorder) Create(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockNotificationStore)(nil).Create), arg0, arg1)
+}
+
+// Delete mocks base method
+func (m *MockNotificationStore) Delete(arg0 context.Context, arg1 *core.Notification) error {
+	ret := m.ctrl.Call(m, "Delete", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete
+func (mr *MockNotificationStoreMockRecorder) Delete(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockNotificationStore)(nil).Delete), arg0, arg1)
+}
+
+// Find mocks base method
+func (m *MockNotificationStore) Find(arg0 context.Context, arg1 int64) (*core.Notification, error) {
+	ret := m.ctrl.Call(m, "Find", arg0, arg1)
+	ret0, _ := ret[0].(*core.Notification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Find indicates an expected call of Find
+func (mr *MockNotificationStoreMockRecorder) Find(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Find", reflect.TypeOf((*MockNotificationStore)(nil).Find), arg0, arg1)
+}
+
+// List mocks base method
+func (m *MockNotificationStore) List(arg0 context.Context, arg1 int64) ([]*core.Notification, error) {
+	ret := m.ctrl.Call(m, "List", arg0, arg1)
+	ret0, _ := ret[0].([]*core.Notification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List
+func (mr *MockNotificationStoreMockRecorder) List(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockNotificationStore)(nil).List), arg0, arg1)
+}
+
+// Rotate mocks base method
+func (m *MockNotificationStore) Rotate(arg0 context.Context) (int64, error) {
+	ret := m.ctrl.Call(m, "Rotate", arg0)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Rotate indicates an expected call of Rotate
+func (mr *MockNotificationStoreMockRecorder) Rotate(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rotate", reflect.TypeOf((*MockNotificationStore)(nil).Rotate), arg0)
+}
+
+// Update mocks base method
+func (m *MockNotificationStore) Update(arg0 context.Context, arg1 *core.Notification) error {
+	ret := m.ctrl.Call(m, "Update", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update
+func (mr *MockNotificationStoreMockRecorder) Update(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockNotificationStore)(nil).Update), arg0, arg1)
+}
+
+// MockNotificationService is a mock of NotificationService interface
+type MockNotificationService struct {
+	ctrl     *gomock.Controller
+	recorder *MockNotificationServiceMockRecorder
+}
+
+// MockNotificationServiceMockRecorder is the mock recorder for MockNotificationService
+type MockNotificationServiceMockRecorder struct {
+	mock *MockNotificationService
+}
+
+// NewMockNotificationService creates a new mock instance
+func NewMockNotificationService(ctrl *gomock.Controller) *MockNotificationService {
+	mock := &MockNotificationService{ctrl: ctrl}
+	mock.recorder = &MockNotificationServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockNotificationService) EXPECT() *MockNotificationServiceMockRecorder {
+	return m.recorder
+}
+
+// Notify mocks base method
+func (m *MockNotificationService) Notify(arg0 context.Context, arg1 *core.NotifyArgs) error {
+	ret := m.ctrl.Call(m, "Notify", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Notify indicates an expected call of Notify
+func (mr *MockNotificationServiceMockRecorder) Notify(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Notify", reflect.TypeOf((*MockNotificationService)(nil).Notify), arg0, arg1)
+}
+
+// MockLicenseService is a mock of LicenseService interface
+type MockLicenseService struct {
+	ctrl     *gomock.Controller
+	recorder *MockLicenseServiceMockRecorder
+}
+
+// MockLicenseServiceMockRecorder is the mock recorder for MockLicenseService
+type MockLicenseServiceMockRecorder struct {
+	mock *MockLicenseService
+}
+
+// NewMockLicenseService creates a new mock instance
+func NewMockLicenseService(ctrl *gomock.Controller) *MockLicenseService {
+	mock := &MockLicenseService{ctrl: ctrl}
+	mock.recorder = &MockLicenseServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockLicenseService) EXPECT() *MockLicenseServiceMockRecorder {
+	return m.recorder
+}
+
+// Exceeded mocks base method
+func (m *MockLicenseService) Exceeded(arg0 context.Context) (bool, error) {
+	ret := m.ctrl.Call(m, "Exceeded", arg0)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Exceeded indicates an expected call of Exceeded
+func (mr *MockLicenseServiceMockRecorder) Exceeded(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Exceeded", reflect.TypeOf((*MockLicenseService)(nil).Exceeded), arg0)
+}
+
+// Expired mocks base method
+func (m *MockLicenseService) Expired(arg0 context.Context) bool {
+	ret := m.ctrl.Call(m, "Expired", arg0)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// Expired indicates an expected call of Expired
+func (mr *MockLicenseServiceMockRecorder) Expired(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Expired", reflect.TypeOf((*MockLicenseService)(nil).Expired), arg0)
+}
+
+// MockHealthService is a mock of HealthService interface
+type MockHealthService struct {
+	ctrl     *gomock.Controller
+	recorder *MockHealthServiceMockRecorder
+}
+
+// MockHealthServiceMockRecorder is the mock recorder for MockHealthService
+type MockHealthServiceMockRecorder struct {
+	mock *MockHealthService
+}
+
+// NewMockHealthService creates a new mock instance
+func NewMockHealthService(ctrl *gomock.Controller) *MockHealthService {
+	mock := &MockHealthService{ctrl: ctrl}
+	mock.recorder = &MockHealthServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockHealthService) EXPECT() *MockHealthServiceMockRecorder {
+	return m.recorder
+}
+
+// Live mocks base method
+func (m *MockHealthService) Live(arg0 context.Context) *core.Health {
+	ret := m.ctrl.Call(m, "Live", arg0)
+	ret0, _ := ret[0].(*core.Health)
+	return ret0
+}
+
+// Live indicates an expected call of Live
+func (mr *MockHealthServiceMockRecorder) Live(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Live", reflect.TypeOf((*MockHealthService)(nil).Live), arg0)
+}
+
+// Ready mocks base method
+func (m *MockHealthService) Ready(arg0 context.Context) *core.Health {
+	ret := m.ctrl.Call(m, "Ready", arg0)
+	ret0, _ := ret[0].(*core.Health)
+	return ret0
+}
+
+// Ready indicates an expected call of Ready
+func (mr *MockHealthServiceMockRecorder) Ready(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Ready", reflect.TypeOf((*MockHealthService)(nil).Ready), arg0)
+}
+
+// MockIDTokenService is a mock of IDTokenService interface
+type MockIDTokenService struct {
+	ctrl     *gomock.Controller
+	recorder *MockIDTokenServiceMockRecorder
+}
+
+// MockIDTokenServiceMockRecorder is the mock recorder for MockIDTokenService
+type MockIDTokenServiceMockRecorder struct {
+	mock *MockIDTokenService
+}
+
+// NewMockIDTokenService creates a new mock instance
+func NewMockIDTokenService(ctrl *gomock.Controller) *MockIDTokenService {
+	mock := &MockIDTokenService{ctrl: ctrl}
+	mock.recorder = &MockIDTokenServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockIDTokenService) EXPECT() *MockIDTokenServiceMockRecorder {
+	return m.recorder
+}
+
+// Issue mocks base method
+func (m *MockIDTokenService) Issue(arg0 context.Context, arg1 *core.IDTokenArgs) (string, error) {
+	ret := m.ctrl.Call(m, "Issue", arg0, arg1)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Issue indicates an expected call of Issue
+func (mr *MockIDTokenServiceMockRecorder) Issue(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Issue", reflect.TypeOf((*MockIDTokenService)(nil).Issue), arg0, arg1)
+}
+
+// Issuer mocks base method
+func (m *MockIDTokenService) Issuer() string {
+	ret := m.ctrl.Call(m, "Issuer")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// Issuer indicates an expected call of Issuer
+func (mr *MockIDTokenServiceMockRecorder) Issuer() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Issuer", reflect.TypeOf((*MockIDTokenService)(nil).Issuer))
+}
+
+// Keys mocks base method
+func (m *MockIDTokenService) Keys(arg0 context.Context) (*core.JSONWebKeySet, error) {
+	ret := m.ctrl.Call(m, "Keys", arg0)
+	ret0, _ := ret[0].(*core.JSONWebKeySet)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Keys indicates an expected call of Keys
+func (mr *MockIDTokenServiceMockRecorder) Keys(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Keys", reflect.TypeOf((*MockIDTokenService)(nil).Keys), arg0)
+}
+
+// MockInstallationService is a mock of InstallationService interface
+type MockInstallationService struct {
+	ctrl     *gomock.Controller
+	recorder *MockInstallationServiceMockRecorder
+}
+
+// MockInstallationServiceMockRecorder is the mock recorder for MockInstallationService
+type MockInstallationServiceMockRecorder struct {
+	mock *MockInstallationService
+}
+
+// NewMockInstallationService creates a new mock instance
+func NewMockInstallationService(ctrl *gomock.Controller) *MockInstallationService {
+	mock := &MockInstallationService{ctrl: ctrl}
+	mock.recorder = &MockInstallationServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockInstallationService) EXPECT() *MockInstallationServiceMockRecorder {
+	return m.recorder
+}
+
+// Token mocks base method
+func (m *MockInstallationService) Token(arg0 context.Context, arg1 *core.Repository, arg2 map[string]string) (*core.InstallationToken, error) {
+	ret := m.ctrl.Call(m, "Token", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*core.InstallationToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Token indicates an expected call of Token
+func (mr *MockInstallationServiceMockRecorder) Token(arg0, arg1, arg2 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Token", reflect.TypeOf((*MockInstallationService)(nil).Token), arg0, arg1, arg2)
+}
+
+// MockProviderService is a mock of ProviderService interface
+type MockProviderService struct {
+	ctrl     *gomock.Controller
+	recorder *MockProviderServiceMockRecorder
+}
+
+// MockProviderServiceMockRecorder is the mock recorder for MockProviderService
+type MockProviderServiceMockRecorder struct {
+	mock *MockProviderService
+}
+
+// NewMockProviderService creates a new mock instance
+func NewMockProviderService(ctrl *gomock.Controller) *MockProviderService {
+	mock := &MockProviderService{ctrl: ctrl}
+	mock.recorder = &MockProviderServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockProviderService) EXPECT() *MockProviderServiceMockRecorder {
+	return m.recorder
+}
+
+// Find mocks base method
+func (m *MockProviderService) Find(arg0 context.Context, arg1 string, arg2 string) (*core.Repository, error) {
+	ret := m.ctrl.Call(m, "Find", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*core.Repository)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Find indicates an expected call of Find
+func (mr *MockProviderServiceMockRecorder) Find(arg0, arg1, arg2 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Find", reflect.TypeOf((*MockProviderService)(nil).Find), arg0, arg1, arg2)
+}
+
+// List mocks base method
+func (m *MockProviderService) List() []string {
+	ret := m.ctrl.Call(m, "List")
+	ret0, _ := ret[0].([]string)
+	return ret0
+}
+
+// List indicates an expected call of List
+func (mr *MockProviderServiceMockRecorder) List() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockProviderService)(nil).List))
+}
+
+// MockCommandService is a mock of CommandService interface
+type MockCommandService struct {
+	ctrl     *gomock.Controller
+	recorder *MockCommandServiceMockRecorder
+}
+
+// MockCommandServiceMockRecorder is the mock recorder for MockCommandService
+type MockCommandServiceMockRecorder struct {
+	mock *MockCommandService
+}
+
+// NewMockCommandService creates a new mock instance
+func NewMockCommandService(ctrl *gomock.Controller) *MockCommandService {
+	mock := &MockCommandService{ctrl: ctrl}
+	mock.recorder = &MockCommandServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockCommandService) EXPECT() *MockCommandServiceMockRecorder {
+	return m.recorder
+}
+
+// Exec mocks base method
+func (m *MockCommandService) Exec(arg0 context.Context, arg1 *core.Repository, arg2 *core.Hook) (*core.Build, error) {
+	ret := m.ctrl.Call(m, "Exec", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*core.Build)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Exec indicates an expected call of Exec
+func (mr *MockCommandServiceMockRecorder) Exec(arg0, arg1, arg2 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Exec", reflect.TypeOf((*MockCommandService)(nil).Exec), arg0, arg1, arg2)
+}
+
+// MockSummaryService is a mock of SummaryService interface
+type MockSummaryService struct {
+	ctrl     *gomock.Controller
+	recorder *MockSummaryServiceMockRecorder
+}
+
+// MockSummaryServiceMockRecorder is the mock recorder for MockSummaryService
+type MockSummaryServiceMockRecorder struct {
+	mock *MockSummaryService
+}
+
+// NewMockSummaryService creates a new mock instance
+func NewMockSummaryService(ctrl *gomock.Controller) *MockSummaryService {
+	mock := &MockSummaryService{ctrl: ctrl}
+	mock.recorder = &MockSummaryServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockSummaryService) EXPECT() *MockSummaryServiceMockRecorder {
+	return m.recorder
+}
+
+// Send mocks base method
+func (m *MockSummaryService) Send(arg0 context.Context, arg1 *core.User, arg2 *core.NotifyArgs) error {
+	ret := m.ctrl.Call(m, "Send", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Send indicates an expected call of Send
+func (mr *MockSummaryServiceMockRecorder) Send(arg0, arg1, arg2 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Send", reflect.TypeOf((*MockSummaryService)(nil).Send), arg0, arg1, arg2)
+}
+
+// MockElector is a mock of Elector interface
+type MockElector struct {
+	ctrl     *gomock.Controller
+	recorder *MockElectorMockRecorder
+}
+
+// MockElectorMockRecorder is the mock recorder for MockElector
+type MockElectorMockRecorder struct {
+	mock *MockElector
+}
+
+// NewMockElector creates a new mock instance
+func NewMockElector(ctrl *gomock.Controller) *MockElector {
+	mock := &MockElector{ctrl: ctrl}
+	mock.recorder = &MockElectorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockElector) EXPECT() *MockElectorMockRecorder {
+	return m.recorder
+}
+
+// Acquire mocks base method
+func (m *MockElector) Acquire(arg0 context.Context, arg1 string, arg2 time.Duration) (bool, error) {
+	ret := m.ctrl.Call(m, "Acquire", arg0, arg1, arg2)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Acquire indicates an expected call of Acquire
+func (mr *MockElectorMockRecorder) Acquire(arg0, arg1, arg2 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Acquire", reflect.TypeOf((*MockElector)(nil).Acquire), arg0, arg1, arg2)
+}
+
+// Release mocks base method
+func (m *MockElector) Release(arg0 context.Context, arg1 string) error {
+	ret := m.ctrl.Call(m, "Release", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Release indicates an expected call of Release
+func (mr *MockElectorMockRecorder) Release(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Release", reflect.TypeOf((*MockElector)(nil).Release), arg0, arg1)
+}
+
+// MockAnnotationStore is a mock of AnnotationStore interface
+type MockAnnotationStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockAnnotationStoreMockRecorder
+}
+
+// MockAnnotationStoreMockRecorder is the mock recorder for MockAnnotationStore
+type MockAnnotationStoreMockRecorder struct {
+	mock *MockAnnotationStore
+}
+
+// NewMockAnnotationStore creates a new mock instance
+func NewMockAnnotationStore(ctrl *gomock.Controller) *MockAnnotationStore {
+	mock := &MockAnnotationStore{ctrl: ctrl}
+	mock.recorder = &MockAnnotationStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockAnnotationStore) EXPECT() *MockAnnotationStoreMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method
+func (m *MockAnnotationStore) Create(arg0 context.Context, arg1 *core.Annotation) error {
+	ret := m.ctrl.Call(m, "Create", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create
+func (mr *MockAnnotationStoreMockRecorder) Create(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockAnnotationStore)(nil).Create), arg0, arg1)
+}
+
+// List mocks base method
+func (m *MockAnnotationStore) List(arg0 context.Context, arg1 int64) ([]*core.Annotation, error) {
+	ret := m.ctrl.Call(m, "List", arg0, arg1)
+	ret0, _ := ret[0].([]*core.Annotation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List
+func (mr *MockAnnotationStoreMockRecorder) List(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockAnnotationStore)(nil).List), arg0, arg1)
+}
+
+// MockProvenanceService is a mock of ProvenanceService interface
+type MockProvenanceService struct {
+	ctrl     *gomock.Controller
+	recorder *MockProvenanceServiceMockRecorder
+}
+
+// MockProvenanceServiceMockRecorder is the mock recorder for MockProvenanceService
+type MockProvenanceServiceMockRecorder struct {
+	mock *MockProvenanceService
+}
+
+// NewMockProvenanceService creates a new mock instance
+func NewMockProvenanceService(ctrl *gomock.Controller) *MockProvenanceService {
+	mock := &MockProvenanceService{ctrl: ctrl}
+	mock.recorder = &MockProvenanceServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockProvenanceService) EXPECT() *MockProvenanceServiceMockRecorder {
+	return m.recorder
+}
+
+// Generate mocks base method
+func (m *MockProvenanceService) Generate(arg0 context.Context, arg1 *core.ProvenanceArgs) (*core.Envelope, error) {
+	ret := m.ctrl.Call(m, "Generate", arg0, arg1)
+	ret0, _ := ret[0].(*core.Envelope)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Generate indicates an expected call of Generate
+func (mr *MockProvenanceServiceMockRecorder) Generate(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Generate", reflect.TypeOf((*MockProvenanceService)(nil).Generate), arg0, arg1)
+}
+
+// Publish mocks base method
+func (m *MockProvenanceService) Publish(arg0 context.Context, arg1 *core.ProvenanceArgs) error {
+	ret := m.ctrl.Call(m, "Publish", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Publish indicates an expected call of Publish
+func (mr *MockProvenanceServiceMockRecorder) Publish(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Publish", reflect.TypeOf((*MockProvenanceService)(nil).Publish), arg0, arg1)
+}
+
+// MockDeploymentStore is a mock of DeploymentStore interface
+type MockDeploymentStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockDeploymentStoreMockRecorder
+}
+
+// MockDeploymentStoreMockRecorder is the mock recorder for MockDeploymentStore
+type MockDeploymentStoreMockRecorder struct {
+	mock *MockDeploymentStore
+}
+
+// NewMockDeploymentStore creates a new mock instance
+func NewMockDeploymentStore(ctrl *gomock.Controller) *MockDeploymentStore {
+	mock := &MockDeploymentStore{ctrl: ctrl}
+	mock.recorder = &MockDeploymentStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockDeploymentStore) EXPECT() *MockDeploymentStoreMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method
+func (m *MockDeploymentStore) Create(arg0 context.Context, arg1 *core.Deployment) error {
+	ret := m.ctrl.Call(m, "Create", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create
+func (mr *MockDeploymentStoreMockRecorder) Create(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockDeploymentStore)(nil).Create), arg0, arg1)
+}
+
+// List mocks base method
+func (m *MockDeploymentStore) List(arg0 context.Context, arg1 int64, arg2 string, arg3 int) ([]*core.Deployment, error) {
+	ret := m.ctrl.Call(m, "List", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]*core.Deployment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List
+func (mr *MockDeploymentStoreMockRecorder) List(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockDeploymentStore)(nil).List), arg0, arg1, arg2, arg3)
+}
+
+// ListLatest mocks base method
+func (m *MockDeploymentStore) ListLatest(arg0 context.Context, arg1 int64) ([]*core.Deployment, error) {
+	ret := m.ctrl.Call(m, "ListLatest", arg0, arg1)
+	ret0, _ := ret[0].([]*core.Deployment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListLatest indicates an expected call of ListLatest
+func (mr *MockDeploymentStoreMockRecorder) ListLatest(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListLatest", reflect.TypeOf((*MockDeploymentStore)(nil).ListLatest), arg0, arg1)
+}
+
+// MockSecretFileService is a mock of SecretFileService interface
+type MockSecretFileService struct {
+	ctrl     *gomock.Controller
+	recorder *MockSecretFileServiceMockRecorder
+}
+
+// MockSecretFileServiceMockRecorder is the mock recorder for MockSecretFileService
+type MockSecretFileServiceMockRecorder struct {
+	mock *MockSecretFileService
+}
+
+// NewMockSecretFileService creates a new mock instance
+func NewMockSecretFileService(ctrl *gomock.Controller) *MockSecretFileService {
+	mock := &MockSecretFileService{ctrl: ctrl}
+	mock.recorder = &MockSecretFileServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockSecretFileService) EXPECT() *MockSecretFileServiceMockRecorder {
+	return m.recorder
+}
+
+// List mocks base method
+func (m *MockSecretFileService) List(arg0 context.Context, arg1 *core.User, arg2 *core.Repository, arg3 *core.Build) ([]*core.Secret, error) {
+	ret := m.ctrl.Call(m, "List", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]*core.Secret)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List
+func (mr *MockSecretFileServiceMockRecorder) List(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockSecretFileService)(nil).List), arg0, arg1, arg2, arg3)
+}
+
+// MockReferenceService is a mock of ReferenceService interface
+type MockReferenceService struct {
+	ctrl     *gomock.Controller
+	recorder *MockReferenceServiceMockRecorder
+}
+
+// MockReferenceServiceMockRecorder is the mock recorder for MockReferenceService
+type MockReferenceServiceMockRecorder struct {
+	mock *MockReferenceService
+}
+
+// NewMockReferenceService creates a new mock instance
+func NewMockReferenceService(ctrl *gomock.Controller) *MockReferenceService {
+	mock := &MockReferenceService{ctrl: ctrl}
+	mock.recorder = &MockReferenceServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockReferenceService) EXPECT() *MockReferenceServiceMockRecorder {
+	return m.recorder
+}
+
+// ListBranches mocks base method
+func (m *MockReferenceService) ListBranches(arg0 context.Context, arg1 *core.User, arg2 string) ([]*core.Reference, error) {
+	ret := m.ctrl.Call(m, "ListBranches", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]*core.Reference)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListBranches indicates an expected call of ListBranches
+func (mr *MockReferenceServiceMockRecorder) ListBranches(arg0, arg1, arg2 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBranches", reflect.TypeOf((*MockReferenceService)(nil).ListBranches), arg0, arg1, arg2)
+}
+
+// ListTags mocks base method
+func (m *MockReferenceService) ListTags(arg0 context.Context, arg1 *core.User, arg2 string) ([]*core.Reference, error) {
+	ret := m.ctrl.Call(m, "ListTags", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]*core.Reference)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTags indicates an expected call of ListTags
+func (mr *MockReferenceServiceMockRecorder) ListTags(arg0, arg1, arg2 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTags", reflect.TypeOf((*MockReferenceService)(nil).ListTags), arg0, arg1, arg2)
+}
+
+// MockPullRequestService is a mock of PullRequestService interface
+type MockPullRequestService struct {
+	ctrl     *gomock.Controller
+	recorder *MockPullRequestServiceMockRecorder
+}
+
+// MockPullRequestServiceMockRecorder is the mock recorder for MockPullRequestService
+type MockPullRequestServiceMockRecorder struct {
+	mock *MockPullRequestService
+}
+
+// NewMockPullRequestService creates a new mock instance
+func NewMockPullRequestService(ctrl *gomock.Controller) *MockPullRequestService {
+	mock := &MockPullRequestService{ctrl: ctrl}
+	mock.recorder = &MockPullRequestServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockPullRequestService) EXPECT() *MockPullRequestServiceMockRecorder {
+	return m.recorder
+}
+
+// Find mocks base method
+func (m *MockPullRequestService) Find(arg0 context.Context, arg1 *core.User, arg2 string, arg3 int) (*core.PullRequest, error) {
+	ret := m.ctrl.Call(m, "Find", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*core.PullRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Find indicates an expected call of Find
+func (mr *MockPullRequestServiceMockRecorder) Find(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Find", reflect.TypeOf((*MockPullRequestService)(nil).Find), arg0, arg1, arg2, arg3)
+}
+
+// ListChanges mocks base method
+func (m *MockPullRequestService) ListChanges(arg0 context.Context, arg1 *core.User, arg2 string, arg3 int) ([]*core.Change, error) {
+	ret := m.ctrl.Call(m, "ListChanges", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]*core.Change)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListChanges indicates an expected call of ListChanges
+func (mr *MockPullRequestServiceMockRecorder) ListChanges(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListChanges", reflect.TypeOf((*MockPullRequestService)(nil).ListChanges), arg0, arg1, arg2, arg3)
+}
+
+// MockHookPayloadStore is a mock of HookPayloadStore interface
+type MockHookPayloadStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockHookPayloadStoreMockRecorder
+}
+
+// MockHookPayloadStoreMockRecorder is the mock recorder for MockHookPayloadStore
+type MockHookPayloadStoreMockRecorder struct {
+	mock *MockHookPayloadStore
+}
+
+// NewMockHookPayloadStore creates a new mock instance
+func NewMockHookPayloadStore(ctrl *gomock.Controller) *MockHookPayloadStore {
+	mock := &MockHookPayloadStore{ctrl: ctrl}
+	mock.recorder = &MockHookPayloadStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockHookPayloadStore) EXPECT() *MockHookPayloadStoreMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method
+func (m *MockHookPayloadStore) Create(arg0 context.Context, arg1 *core.HookPayload) error {
+	ret := m.ctrl.Call(m, "Create", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create
+func (mr *MockHookPayloadStoreMockRecorder) Create(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockHookPayloadStore)(nil).Create), arg0, arg1)
+}
+
+// Find mocks base method
+func (m *MockHookPayloadStore) Find(arg0 context.Context, arg1 int64) (*core.HookPayload, error) {
+	ret := m.ctrl.Call(m, "Find", arg0, arg1)
+	ret0, _ := ret[0].(*core.HookPayload)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Find indicates an expected call of Find
+func (mr *MockHookPayloadStoreMockRecorder) Find(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Find", reflect.TypeOf((*MockHookPayloadStore)(nil).Find), arg0, arg1)
+}
+
+// List mocks base method
+func (m *MockHookPayloadStore) List(arg0 context.Context, arg1 int64) ([]*core.HookPayload, error) {
+	ret := m.ctrl.Call(m, "List", arg0, arg1)
+	ret0, _ := ret[0].([]*core.HookPayload)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List
+func (mr *MockHookPayloadStoreMockRecorder) List(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockHookPayloadStore)(nil).List), arg0, arg1)
+}
+
+// Update mocks base method
+func (m *MockHookPayloadStore) Update(arg0 context.Context, arg1 *core.HookPayload) error {
+	ret := m.ctrl.Call(m, "Update", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update
+func (mr *MockHookPayloadStoreMockRecorder) Update(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockHookPayloadStore)(nil).Update), arg0, arg1)
 }
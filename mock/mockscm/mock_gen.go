@@ -1,5 +1,5 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: github.com/drone/go-scm/scm (interfaces: ContentService,GitService,OrganizationService,PullRequestService,RepositoryService,UserService)
+// Source: github.com/drone/go-scm/scm (interfaces: ContentService,GitService,IssueService,OrganizationService,PullRequestService,RepositoryService,UserService)
 
 // Package mockscm is a generated GoMock package.
 package mockscm
@@ -208,6 +208,165 @@ func (mr *MockGitServiceMockRecorder) ListTags(arg0, arg1, arg2 interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTags", reflect.TypeOf((*MockGitService)(nil).ListTags), arg0, arg1, arg2)
 }
 
+// MockIssueService is a mock of IssueService interface
+type MockIssueService struct {
+	ctrl     *gomock.Controller
+	recorder *MockIssueServiceMockRecorder
+}
+
+// MockIssueServiceMockRecorder is the mock recorder for MockIssueService
+type MockIssueServiceMockRecorder struct {
+	mock *MockIssueService
+}
+
+// NewMockIssueService creates a new mock instance
+func NewMockIssueService(ctrl *gomock.Controller) *MockIssueService {
+	mock := &MockIssueService{ctrl: ctrl}
+	mock.recorder = &MockIssueServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockIssueService) EXPECT() *MockIssueServiceMockRecorder {
+	return m.recorder
+}
+
+// Close mocks base method
+func (m *MockIssueService) Close(arg0 context.Context, arg1 string, arg2 int) (*scm.Response, error) {
+	ret := m.ctrl.Call(m, "Close", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*scm.Response)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Close indicates an expected call of Close
+func (mr *MockIssueServiceMockRecorder) Close(arg0, arg1, arg2 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockIssueService)(nil).Close), arg0, arg1, arg2)
+}
+
+// Create mocks base method
+func (m *MockIssueService) Create(arg0 context.Context, arg1 string, arg2 *scm.IssueInput) (*scm.Issue, *scm.Response, error) {
+	ret := m.ctrl.Call(m, "Create", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*scm.Issue)
+	ret1, _ := ret[1].(*scm.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Create indicates an expected call of Create
+func (mr *MockIssueServiceMockRecorder) Create(arg0, arg1, arg2 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockIssueService)(nil).Create), arg0, arg1, arg2)
+}
+
+// CreateComment mocks base method
+func (m *MockIssueService) CreateComment(arg0 context.Context, arg1 string, arg2 int, arg3 *scm.CommentInput) (*scm.Comment, *scm.Response, error) {
+	ret := m.ctrl.Call(m, "CreateComment", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*scm.Comment)
+	ret1, _ := ret[1].(*scm.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// CreateComment indicates an expected call of CreateComment
+func (mr *MockIssueServiceMockRecorder) CreateComment(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateComment", reflect.TypeOf((*MockIssueService)(nil).CreateComment), arg0, arg1, arg2, arg3)
+}
+
+// DeleteComment mocks base method
+func (m *MockIssueService) DeleteComment(arg0 context.Context, arg1 string, arg2 int, arg3 int) (*scm.Response, error) {
+	ret := m.ctrl.Call(m, "DeleteComment", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*scm.Response)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteComment indicates an expected call of DeleteComment
+func (mr *MockIssueServiceMockRecorder) DeleteComment(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteComment", reflect.TypeOf((*MockIssueService)(nil).DeleteComment), arg0, arg1, arg2, arg3)
+}
+
+// Find mocks base method
+func (m *MockIssueService) Find(arg0 context.Context, arg1 string, arg2 int) (*scm.Issue, *scm.Response, error) {
+	ret := m.ctrl.Call(m, "Find", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*scm.Issue)
+	ret1, _ := ret[1].(*scm.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Find indicates an expected call of Find
+func (mr *MockIssueServiceMockRecorder) Find(arg0, arg1, arg2 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Find", reflect.TypeOf((*MockIssueService)(nil).Find), arg0, arg1, arg2)
+}
+
+// FindComment mocks base method
+func (m *MockIssueService) FindComment(arg0 context.Context, arg1 string, arg2 int, arg3 int) (*scm.Comment, *scm.Response, error) {
+	ret := m.ctrl.Call(m, "FindComment", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*scm.Comment)
+	ret1, _ := ret[1].(*scm.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// FindComment indicates an expected call of FindComment
+func (mr *MockIssueServiceMockRecorder) FindComment(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindComment", reflect.TypeOf((*MockIssueService)(nil).FindComment), arg0, arg1, arg2, arg3)
+}
+
+// List mocks base method
+func (m *MockIssueService) List(arg0 context.Context, arg1 string, arg2 scm.IssueListOptions) ([]*scm.Issue, *scm.Response, error) {
+	ret := m.ctrl.Call(m, "List", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]*scm.Issue)
+	ret1, _ := ret[1].(*scm.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// List indicates an expected call of List
+func (mr *MockIssueServiceMockRecorder) List(arg0, arg1, arg2 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockIssueService)(nil).List), arg0, arg1, arg2)
+}
+
+// ListComments mocks base method
+func (m *MockIssueService) ListComments(arg0 context.Context, arg1 string, arg2 int, arg3 scm.ListOptions) ([]*scm.Comment, *scm.Response, error) {
+	ret := m.ctrl.Call(m, "ListComments", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]*scm.Comment)
+	ret1, _ := ret[1].(*scm.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListComments indicates an expected call of ListComments
+func (mr *MockIssueServiceMockRecorder) ListComments(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListComments", reflect.TypeOf((*MockIssueService)(nil).ListComments), arg0, arg1, arg2, arg3)
+}
+
+// Lock mocks base method
+func (m *MockIssueService) Lock(arg0 context.Context, arg1 string, arg2 int) (*scm.Response, error) {
+	ret := m.ctrl.Call(m, "Lock", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*scm.Response)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Lock indicates an expected call of Lock
+func (mr *MockIssueServiceMockRecorder) Lock(arg0, arg1, arg2 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Lock", reflect.TypeOf((*MockIssueService)(nil).Lock), arg0, arg1, arg2)
+}
+
+// Unlock mocks base method
+func (m *MockIssueService) Unlock(arg0 context.Context, arg1 string, arg2 int) (*scm.Response, error) {
+	ret := m.ctrl.Call(m, "Unlock", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*scm.Response)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Unlock indicates an expected call of Unlock
+func (mr *MockIssueServiceMockRecorder) Unlock(arg0, arg1, arg2 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unlock", reflect.TypeOf((*MockIssueService)(nil).Unlock), arg0, arg1, arg2)
+}
+
 // MockOrganizationService is a mock of OrganizationService interface
 type MockOrganizationService struct {
 	ctrl     *gomock.Controller
@@ -0,0 +1,144 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package manager
+
+import (
+	"encoding/xml"
+	"errors"
+	"io"
+	"strings"
+
+	"github.com/drone/drone/core"
+)
+
+// maximum number of failed test cases stored per report, and
+// the maximum length of the failure message.
+const (
+	maxFailedCases  = 100
+	maxFailedLength = 1000
+)
+
+var errJUnitInvalid = errors.New("manager: invalid junit report")
+
+type (
+	junitSuites struct {
+		Suites []*junitSuite `xml:"testsuite"`
+	}
+
+	junitSuite struct {
+		Name   string        `xml:"name,attr"`
+		Time   float64       `xml:"time,attr"`
+		Suites []*junitSuite `xml:"testsuite"`
+		Cases  []*junitCase  `xml:"testcase"`
+	}
+
+	junitCase struct {
+		Name      string        `xml:"name,attr"`
+		Classname string        `xml:"classname,attr"`
+		Time      float64       `xml:"time,attr"`
+		Failure   *junitFailure `xml:"failure"`
+		Error     *junitFailure `xml:"error"`
+		Skipped   *struct{}     `xml:"skipped"`
+	}
+
+	junitFailure struct {
+		Message string `xml:"message,attr"`
+		Text    string `xml:",chardata"`
+	}
+)
+
+// helper function parses the JUnit or XUnit xml report and
+// returns the test results. The test counts are calculated
+// from the test cases, since the suite attributes are not
+// reliably populated by all test frameworks.
+func parseJUnit(r io.Reader) (*core.TestResult, error) {
+	decoder := xml.NewDecoder(r)
+	var suites []*junitSuite
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return nil, errJUnitInvalid
+		}
+		if err != nil {
+			return nil, err
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		switch start.Name.Local {
+		case "testsuites":
+			out := new(junitSuites)
+			err = decoder.DecodeElement(out, &start)
+			suites = out.Suites
+		case "testsuite":
+			out := new(junitSuite)
+			err = decoder.DecodeElement(out, &start)
+			suites = []*junitSuite{out}
+		default:
+			return nil, errJUnitInvalid
+		}
+		if err != nil {
+			return nil, err
+		}
+		break
+	}
+
+	result := new(core.TestResult)
+	var seconds float64
+	for _, suite := range suites {
+		seconds += collectJUnit(result, suite)
+	}
+	result.Duration = int64(seconds * 1000)
+	return result, nil
+}
+
+// helper function adds the test cases in the suite, and any
+// nested suites, to the test results, and returns the suite
+// duration in seconds.
+func collectJUnit(result *core.TestResult, suite *junitSuite) float64 {
+	var seconds float64
+	for _, nested := range suite.Suites {
+		seconds += collectJUnit(result, nested)
+	}
+	for _, c := range suite.Cases {
+		result.Tests++
+		seconds += c.Time
+
+		var failure *junitFailure
+		switch {
+		case c.Failure != nil:
+			result.Failures++
+			failure = c.Failure
+		case c.Error != nil:
+			result.Errors++
+			failure = c.Error
+		case c.Skipped != nil:
+			result.Skipped++
+		}
+		if failure == nil || len(result.Failed) >= maxFailedCases {
+			continue
+		}
+		message := failure.Message
+		if message == "" {
+			message = strings.TrimSpace(failure.Text)
+		}
+		if len(message) > maxFailedLength {
+			message = message[:maxFailedLength]
+		}
+		result.Failed = append(result.Failed, &core.TestCase{
+			Suite:   suite.Name,
+			Class:   c.Classname,
+			Name:    c.Name,
+			Message: message,
+		})
+	}
+	// prefer the suite duration when provided, since it may
+	// include setup and teardown time.
+	if suite.Time > seconds {
+		return suite.Time
+	}
+	return seconds
+}
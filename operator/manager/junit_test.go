@@ -0,0 +1,98 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package manager
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/drone/drone/core"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseJUnit(t *testing.T) {
+	result, err := parseJUnit(strings.NewReader(testJUnitReport))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	want := &core.TestResult{
+		Tests:    5,
+		Failures: 1,
+		Errors:   1,
+		Skipped:  1,
+		Duration: 3500,
+		Failed: []*core.TestCase{
+			{
+				Suite:   "math",
+				Class:   "math.AddTest",
+				Name:    "TestAddNegative",
+				Message: "want -2, got 2",
+			},
+			{
+				Suite:   "strings",
+				Class:   "strings.SplitTest",
+				Name:    "TestSplit",
+				Message: "panic: index out of range",
+			},
+		},
+	}
+	if diff := cmp.Diff(result, want); diff != "" {
+		t.Errorf(diff)
+	}
+	if got, want := result.Passed(), 2; got != want {
+		t.Errorf("Want %d passed tests, got %d", want, got)
+	}
+}
+
+func TestParseJUnit_Suite(t *testing.T) {
+	result, err := parseJUnit(strings.NewReader(`
+<testsuite name="math" time="0.5">
+  <testcase name="TestAdd" classname="math.AddTest" time="0.1"/>
+</testsuite>`))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if got, want := result.Tests, 1; got != want {
+		t.Errorf("Want %d tests, got %d", want, got)
+	}
+	if got, want := result.Duration, int64(500); got != want {
+		t.Errorf("Want duration %d, got %d", want, got)
+	}
+}
+
+func TestParseJUnit_Invalid(t *testing.T) {
+	_, err := parseJUnit(strings.NewReader(`<html></html>`))
+	if err != errJUnitInvalid {
+		t.Errorf("Want invalid junit error, got %v", err)
+	}
+	_, err = parseJUnit(strings.NewReader(``))
+	if err != errJUnitInvalid {
+		t.Errorf("Want invalid junit error for empty report, got %v", err)
+	}
+}
+
+var testJUnitReport = `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites>
+  <testsuite name="math" tests="3" time="2.0">
+    <testcase name="TestAdd" classname="math.AddTest" time="0.5"/>
+    <testcase name="TestAddNegative" classname="math.AddTest" time="0.5">
+      <failure message="want -2, got 2" type="assert">add_test.go:12</failure>
+    </testcase>
+    <testcase name="TestAddOverflow" classname="math.AddTest">
+      <skipped/>
+    </testcase>
+  </testsuite>
+  <testsuite name="strings">
+    <testcase name="TestJoin" classname="strings.JoinTest" time="0.5"/>
+    <testcase name="TestSplit" classname="strings.SplitTest" time="1.0">
+      <error>
+        panic: index out of range
+      </error>
+    </testcase>
+  </testsuite>
+</testsuites>
+`
@@ -7,10 +7,15 @@ package manager
 import (
 	"bytes"
 	"context"
+	"errors"
 	"io"
+	"io/ioutil"
+	"path"
+	"strings"
 	"time"
 
 	"github.com/drone/drone/core"
+	"github.com/drone/drone/metric"
 	"github.com/drone/drone/store/shared/db"
 
 	"github.com/hashicorp/go-multierror"
@@ -19,18 +24,34 @@ import (
 
 var noContext = context.Background()
 
+var errArtifactName = errors.New("manager: invalid artifact name")
+
+// maxAcceptRetries is the maximum number of times the next
+// eligible stage is accepted on behalf of the agent when the
+// stage was accepted by another agent.
+const maxAcceptRetries = 5
+
+// acceptTimeout is the maximum amount of time spent waiting
+// for the next eligible stage when the stage was accepted by
+// another agent.
+const acceptTimeout = time.Second * 10
+
 var _ BuildManager = (*Manager)(nil)
 
 type (
 	// Context represents the minimum amount of information
 	// required by the runner to execute a build.
 	Context struct {
-		Repo    *core.Repository `json:"repository"`
-		Build   *core.Build      `json:"build"`
-		Stage   *core.Stage      `json:"stage"`
-		Config  *core.File       `json:"config"`
-		Secrets []*core.Secret   `json:"secrets"`
-		System  *core.System     `json:"system"`
+		Repo       *core.Repository  `json:"repository"`
+		Build      *core.Build       `json:"build"`
+		Stage      *core.Stage       `json:"stage"`
+		Config     *core.File        `json:"config"`
+		Secrets    []*core.Secret    `json:"secrets"`
+		Registries []*core.Registry  `json:"registries,omitempty"`
+		Privileged []string          `json:"privileged,omitempty"`
+		Environ    map[string]string `json:"environ,omitempty"`
+		IDToken    string            `json:"id_token,omitempty"`
+		System     *core.System      `json:"system"`
 	}
 
 	// BuildManager encapsulets complex build operations and provides
@@ -39,8 +60,10 @@ type (
 		// Request requests the next available build stage for execution.
 		Request(ctx context.Context, args *Request) (*core.Stage, error)
 
-		// Accept accepts the build stage for execution.
-		Accept(ctx context.Context, stage int64, machine string) error
+		// Accept accepts the build stage for execution. If the
+		// stage was accepted by another agent, the next eligible
+		// stage may be accepted and returned instead.
+		Accept(ctx context.Context, stage int64, machine string) (*core.Stage, error)
 
 		// Netrc returns a valid netrc for execution.
 		Netrc(ctx context.Context, repo int64) (*core.Netrc, error)
@@ -71,11 +94,30 @@ type (
 
 		// UploadBytes uploads the full logs
 		UploadBytes(ctx context.Context, step int64, b []byte) error
+
+		// UploadArtifact uploads a build artifact
+		UploadArtifact(ctx context.Context, step int64, name string, r io.Reader) error
+
+		// UploadTests uploads a junit test report
+		UploadTests(ctx context.Context, step int64, r io.Reader) error
+
+		// UploadCoverage uploads a code coverage summary
+		UploadCoverage(ctx context.Context, step int64, coverage *core.Coverage) error
+
+		// UploadAnnotations uploads step annotations
+		UploadAnnotations(ctx context.Context, step int64, annotations []*core.Annotation) error
+
+		// UploadDeployment uploads a deployment to an environment
+		UploadDeployment(ctx context.Context, step int64, deployment *core.Deployment) error
 	}
 
 	// Request provildes filters when requesting a pending
 	// build from the queue. This allows an agent, for example,
 	// to request a build that matches its architecture and kernel.
+	//
+	// The machine is optional. If provided, the next eligible
+	// stage is accepted on behalf of the agent when the offered
+	// stage is accepted by another agent.
 	Request struct {
 		Kind    string            `json:"kind"`
 		Type    string            `json:"type"`
@@ -84,64 +126,113 @@ type (
 		Variant string            `json:"variant"`
 		Kernel  string            `json:"kernel"`
 		Labels  map[string]string `json:"labels,omitempty"`
+		Machine string            `json:"machine,omitempty"`
 	}
 )
 
 // New returns a new Manager.
 func New(
+	access core.SecretAccessStore,
+	annotations core.AnnotationStore,
+	artifacts core.ArtifactStore,
 	builds core.BuildStore,
 	config core.ConfigService,
+	coverage core.CoverageStore,
+	deployments core.DeploymentStore,
 	events core.Pubsub,
+	idtokens core.IDTokenService,
+	insights core.InsightStore,
 	logs core.LogStore,
 	logz core.LogStream,
 	netrcs core.NetrcService,
+	notify core.NotificationService,
+	privileged core.PrivilegedImageStore,
+	provenance core.ProvenanceService,
+	registries core.RegistryStore,
 	repos core.RepositoryStore,
 	scheduler core.Scheduler,
 	secrets core.SecretStore,
+	secretFiles core.SecretFileService,
 	status core.StatusService,
 	stages core.StageStore,
 	steps core.StepStore,
+	summary core.SummaryService,
 	system *core.System,
+	tests core.TestResultStore,
 	users core.UserStore,
+	variables core.VariableStore,
 	webhook core.WebhookSender,
 ) BuildManager {
 	return &Manager{
-		Builds:    builds,
-		Config:    config,
-		Events:    events,
-		Logs:      logs,
-		Logz:      logz,
-		Netrcs:    netrcs,
-		Repos:     repos,
-		Scheduler: scheduler,
-		Secrets:   secrets,
-		Status:    status,
-		Stages:    stages,
-		Steps:     steps,
-		System:    system,
-		Users:     users,
-		Webhook:   webhook,
+		Access:      access,
+		Annotations: annotations,
+		Artifacts:   artifacts,
+		Builds:      builds,
+		Config:      config,
+		Coverage:    coverage,
+		Deployments: deployments,
+		Events:      events,
+		IDTokens:    idtokens,
+		Insights:    insights,
+		Logs:        logs,
+		Logz:        logz,
+		Netrcs:      netrcs,
+		Notify:      notify,
+		Privileged:  privileged,
+		Provenance:  provenance,
+		Registries:  registries,
+		Repos:       repos,
+		Scheduler:   scheduler,
+		Secrets:     secrets,
+		SecretFiles: secretFiles,
+		Status:      status,
+		Stages:      stages,
+		Steps:       steps,
+		Summary:     summary,
+		System:      system,
+		Tests:       tests,
+		Users:       users,
+		Variables:   variables,
+		Webhook:     webhook,
 	}
 }
 
 // Manager provides a simplified interface to the build runner so that it
 // can more easily interact with the server.
 type Manager struct {
-	Builds    core.BuildStore
-	Config    core.ConfigService
-	Events    core.Pubsub
-	Logs      core.LogStore
-	Logz      core.LogStream
-	Netrcs    core.NetrcService
-	Repos     core.RepositoryStore
-	Scheduler core.Scheduler
-	Secrets   core.SecretStore
-	Status    core.StatusService
-	Stages    core.StageStore
-	Steps     core.StepStore
-	System    *core.System
-	Users     core.UserStore
-	Webhook   core.WebhookSender
+	Access      core.SecretAccessStore
+	Annotations core.AnnotationStore
+	Artifacts   core.ArtifactStore
+	Builds      core.BuildStore
+	Config      core.ConfigService
+	Coverage    core.CoverageStore
+	Deployments core.DeploymentStore
+	Events      core.Pubsub
+	IDTokens    core.IDTokenService
+	Insights    core.InsightStore
+	Logs        core.LogStore
+	Logz        core.LogStream
+	Netrcs      core.NetrcService
+	Notify      core.NotificationService
+	Privileged  core.PrivilegedImageStore
+	Provenance  core.ProvenanceService
+	Registries  core.RegistryStore
+	Repos       core.RepositoryStore
+	Scheduler   core.Scheduler
+	Secrets     core.SecretStore
+	SecretFiles core.SecretFileService
+	Status      core.StatusService
+	Stages      core.StageStore
+	Steps       core.StepStore
+	Summary     core.SummaryService
+	System      *core.System
+	Tests       core.TestResultStore
+	Users       core.UserStore
+	Variables   core.VariableStore
+	Webhook     core.WebhookSender
+
+	masks  maskCache
+	offers offerCache
 }
 
 // Request requests the next available build stage for execution.
@@ -174,6 +265,15 @@ func (m *Manager) Request(ctx context.Context, args *Request) (*core.Stage, erro
 		logger.Warnln("manager: request queue item error")
 		return nil, err
 	}
+	// the offer is recorded in memory, instead of updating
+	// the stage, to avoid a database write for every stage
+	// offered to an agent.
+	if stage != nil {
+		m.offers.set(stage.ID, args.Machine, &offer{
+			args:       args,
+			dispatched: time.Now().Unix(),
+		})
+	}
 	return stage, nil
 }
 
@@ -181,7 +281,49 @@ func (m *Manager) Request(ctx context.Context, args *Request) (*core.Stage, erro
 // agents to pull the same stage from the queue. The system uses optimistic
 // locking at the database-level to prevent multiple agents from executing the
 // same stage.
-func (m *Manager) Accept(ctx context.Context, id int64, machine string) error {
+//
+// If the stage was accepted by another agent, the next eligible stage is
+// requested using the filter provided by the agent, and is accepted on behalf
+// of the agent. This prevents the agent from polling the queue again.
+func (m *Manager) Accept(ctx context.Context, id int64, machine string) (*core.Stage, error) {
+	for i := 0; ; i++ {
+		offer, _ := m.offers.pop(id, machine)
+		stage, err := m.accept(id, machine, offer)
+		if err != db.ErrOptimisticLock || i == maxAcceptRetries {
+			return stage, err
+		}
+		// the next eligible stage can only be requested if
+		// the agent provided its machine name when requesting
+		// the stage, and the request was served by this server.
+		if offer == nil || offer.args.Machine == "" {
+			return nil, err
+		}
+		next, err := m.requestNext(ctx, offer.args)
+		if err != nil || next == nil {
+			return nil, db.ErrOptimisticLock
+		}
+		logrus.WithFields(
+			logrus.Fields{
+				"stage-id":      id,
+				"next-stage-id": next.ID,
+				"machine":       machine,
+			},
+		).Debugln("manager: stage accepted by another agent, accept next stage")
+		id = next.ID
+	}
+}
+
+// helper function requests the next eligible stage for the
+// agent. The request is abandoned if a stage is not available
+// within the timeout, in which case the agent polls the queue.
+func (m *Manager) requestNext(ctx context.Context, args *Request) (*core.Stage, error) {
+	ctx, cancel := context.WithTimeout(ctx, acceptTimeout)
+	defer cancel()
+	return m.Request(ctx, args)
+}
+
+// helper function accepts the build stage for execution.
+func (m *Manager) accept(id int64, machine string, offer *offer) (*core.Stage, error) {
 	logger := logrus.WithFields(
 		logrus.Fields{
 			"stage-id": id,
@@ -194,28 +336,37 @@ func (m *Manager) Accept(ctx context.Context, id int64, machine string) error {
 	if err != nil {
 		logger = logger.WithError(err)
 		logger.Warnln("manager: cannot find stage")
-		return err
+		return nil, err
 	}
 	if stage.Machine != "" {
+		metric.StageConflict("accept")
 		logger.Debugln("manager: stage already assigned. abort.")
-		return db.ErrOptimisticLock
+		return nil, db.ErrOptimisticLock
 	}
 
 	stage.Machine = machine
 	stage.Status = core.StatusPending
+	stage.Attempts++
+	stage.Accepted = time.Now().Unix()
 	stage.Updated = time.Now().Unix()
+	if offer != nil {
+		stage.Dispatched = offer.dispatched
+	}
 
 	err = m.Stages.Update(noContext, stage)
 	if err == db.ErrOptimisticLock {
+		metric.StageConflict("accept")
 		logger = logger.WithError(err)
 		logger.Debugln("manager: stage processed by another agent")
+		return nil, err
 	} else if err != nil {
 		logger = logger.WithError(err)
 		logger.Debugln("manager: cannot update stage")
-	} else {
-		logger.Debugln("manager: stage accepted")
+		return nil, err
 	}
-	return err
+	metric.StageAccepted(stage)
+	logger.Debugln("manager: stage accepted")
+	return stage, nil
 }
 
 // Details fetches build details.
@@ -263,7 +414,6 @@ func (m *Manager) Details(ctx context.Context, id int64) (*Context, error) {
 		logger.Warnln("manager: cannot find configuration")
 		return nil, err
 	}
-	var secrets []*core.Secret
 	tmpSecrets, err := m.Secrets.List(noContext, repo.ID)
 	if err != nil {
 		logger = logger.WithError(err)
@@ -271,22 +421,93 @@ func (m *Manager) Details(ctx context.Context, id int64) (*Context, error) {
 		return nil, err
 	}
 	// TODO(bradrydzewski) can we delegate filtering
-	// secrets to the agent? If not, we should add
-	// unit tests.
-	for _, secret := range tmpSecrets {
-		if secret.PullRequest == false &&
-			build.Event == core.EventPullRequest {
-			continue
+	// secrets to the agent?
+	secrets := filterSecrets(build, tmpSecrets)
+	// record the secrets provided to the build stage, so
+	// that credential usage can be traced after an incident.
+	for _, secret := range secrets {
+		err := m.Access.Create(noContext, &core.SecretAccess{
+			SecretID:    secret.ID,
+			RepoID:      repo.ID,
+			BuildID:     build.ID,
+			BuildNumber: build.Number,
+			StageID:     stage.ID,
+		})
+		if err != nil {
+			logger.WithError(err).
+				WithField("secret", secret.Name).
+				Warnln("manager: cannot record secret access")
+		}
+	}
+	// secrets decrypted from the secrets file committed to
+	// the repository are never provided to pull requests,
+	// since the pull request author controls the pipeline.
+	if m.SecretFiles != nil && build.Event != core.EventPullRequest {
+		fileSecrets, err := m.SecretFiles.List(noContext, user, repo, build)
+		if err != nil {
+			logger.WithError(err).
+				Warnln("manager: cannot decrypt secrets file")
+		}
+		secrets = append(secrets, fileSecrets...)
+	}
+	repoRegistries, err := m.Registries.List(noContext, repo.ID)
+	if err != nil {
+		logger = logger.WithError(err)
+		logger.Warnln("manager: cannot list registries")
+		return nil, err
+	}
+	orgRegistries, err := m.Registries.ListNamespace(noContext, repo.Namespace)
+	if err != nil {
+		logger = logger.WithError(err)
+		logger.Warnln("manager: cannot list organization registries")
+		return nil, err
+	}
+	globalPrivileged, err := m.Privileged.ListNamespace(noContext, "")
+	if err != nil {
+		logger = logger.WithError(err)
+		logger.Warnln("manager: cannot list privileged images")
+		return nil, err
+	}
+	orgPrivileged, err := m.Privileged.ListNamespace(noContext, repo.Namespace)
+	if err != nil {
+		logger = logger.WithError(err)
+		logger.Warnln("manager: cannot list organization privileged images")
+		return nil, err
+	}
+	variables, err := m.variables(repo)
+	if err != nil {
+		logger = logger.WithError(err)
+		logger.Warnln("manager: cannot list variables")
+		return nil, err
+	}
+	// the identity token is optional. If the token cannot be
+	// issued the stage is executed without a token, and steps
+	// that require cloud credentials fail to authenticate.
+	// Tokens are never issued to pull requests from forks,
+	// since the pull request author controls the pipeline.
+	var idtoken string
+	if m.IDTokens != nil && !isFork(repo, build) {
+		idtoken, err = m.IDTokens.Issue(noContext, &core.IDTokenArgs{
+			Repo:  repo,
+			Build: build,
+			Stage: stage,
+		})
+		if err != nil {
+			logger.WithError(err).
+				Warnln("manager: cannot issue identity token")
 		}
-		secrets = append(secrets, secret)
 	}
 	return &Context{
-		Repo:    repo,
-		Build:   build,
-		Stage:   stage,
-		Secrets: secrets,
-		System:  m.System,
-		Config:  &core.File{Data: []byte(config.Data)},
+		Repo:       repo,
+		Build:      build,
+		Stage:      stage,
+		Secrets:    secrets,
+		Registries: mergeRegistries(repoRegistries, orgRegistries),
+		Privileged: privilegedImages(globalPrivileged, orgPrivileged),
+		Environ:    convertVariables(variables),
+		IDToken:    idtoken,
+		System:     m.System,
+		Config:     &core.File{Data: []byte(config.Data)},
 	}, nil
 }
 
@@ -369,15 +590,20 @@ func (m *Manager) BeforeAll(ctx context.Context, stage *core.Stage) error {
 // AfterAll signals the build stage is complete.
 func (m *Manager) AfterAll(ctx context.Context, stage *core.Stage) error {
 	t := &teardown{
-		Builds:    m.Builds,
-		Events:    m.Events,
-		Logs:      m.Logz,
-		Repos:     m.Repos,
-		Scheduler: m.Scheduler,
-		Steps:     m.Steps,
-		Stages:    m.Stages,
-		Status:    m.Status,
-		Users:     m.Users,
+		Builds:      m.Builds,
+		Deployments: m.Deployments,
+		Events:      m.Events,
+		Insights:    m.Insights,
+		Logs:        m.Logz,
+		Notify:      m.Notify,
+		Provenance:  m.Provenance,
+		Repos:       m.Repos,
+		Scheduler:   m.Scheduler,
+		Steps:       m.Steps,
+		Stages:      m.Stages,
+		Status:      m.Status,
+		Summary:     m.Summary,
+		Users:       m.Users,
 	}
 	return t.do(ctx, stage)
 }
@@ -406,6 +632,10 @@ func (m *Manager) Netrc(ctx context.Context, id int64) (*core.Netrc, error) {
 		logger = logger.WithError(err)
 		logger = logger.WithField("repo.name", repo.Slug)
 		logger.Warnln("manager: cannot gernerate netrc")
+	} else if netrc != nil && netrc.Expires != 0 {
+		logger = logger.WithField("repo.name", repo.Slug)
+		logger = logger.WithField("netrc.expires", netrc.Expires)
+		logger.Debugln("manager: generated scoped netrc")
 	}
 	return netrc, err
 }
@@ -432,6 +662,9 @@ func (m *Manager) Watch(ctx context.Context, id int64) (bool, error) {
 
 // Write writes a line to the build logs.
 func (m *Manager) Write(ctx context.Context, step int64, line *core.Line) error {
+	if mask := m.masker(step); mask != nil {
+		line.Message = mask.mask(line.Message)
+	}
 	err := m.Logz.Write(ctx, step, line)
 	if err != nil {
 		logger := logrus.WithError(err)
@@ -443,6 +676,17 @@ func (m *Manager) Write(ctx context.Context, step int64, line *core.Line) error
 
 // Upload uploads the full logs.
 func (m *Manager) Upload(ctx context.Context, step int64, r io.Reader) error {
+	if mask := m.masker(step); mask != nil {
+		data, err := ioutil.ReadAll(r)
+		if err != nil {
+			return err
+		}
+		r = bytes.NewReader(mask.maskLines(data))
+	}
+	// the full logs are uploaded when the step is complete,
+	// after which the masker is no longer needed.
+	m.masks.delete(step)
+
 	err := m.Logs.Create(ctx, step, r)
 	if err != nil {
 		logger := logrus.WithError(err)
@@ -454,6 +698,11 @@ func (m *Manager) Upload(ctx context.Context, step int64, r io.Reader) error {
 
 // UploadBytes uploads the full logs.
 func (m *Manager) UploadBytes(ctx context.Context, step int64, data []byte) error {
+	if mask := m.masker(step); mask != nil {
+		data = mask.maskLines(data)
+	}
+	m.masks.delete(step)
+
 	buf := bytes.NewBuffer(data)
 	err := m.Logs.Create(ctx, step, buf)
 	if err != nil {
@@ -463,3 +712,202 @@ func (m *Manager) UploadBytes(ctx context.Context, step int64, data []byte) erro
 	}
 	return err
 }
+
+// UploadArtifact uploads a build artifact.
+func (m *Manager) UploadArtifact(ctx context.Context, id int64, name string, r io.Reader) error {
+	logger := logrus.WithFields(
+		logrus.Fields{
+			"step-id": id,
+			"name":    name,
+		},
+	)
+
+	// the artifact name is cleaned to prevent a relative
+	// path from escaping the storage prefix.
+	name = strings.TrimPrefix(path.Clean("/"+name), "/")
+	if name == "" {
+		return errArtifactName
+	}
+
+	step, err := m.Steps.Find(noContext, id)
+	if err != nil {
+		logger = logger.WithError(err)
+		logger.Warnln("manager: cannot find step")
+		return err
+	}
+	stage, err := m.Stages.Find(noContext, step.StageID)
+	if err != nil {
+		logger = logger.WithError(err)
+		logger.Warnln("manager: cannot find stage")
+		return err
+	}
+	artifact := &core.Artifact{
+		BuildID: stage.BuildID,
+		StageID: stage.ID,
+		StepID:  step.ID,
+		Name:    name,
+	}
+	err = m.Artifacts.Create(ctx, artifact, r)
+	if err != nil {
+		logger = logger.WithError(err)
+		logger.Warnln("manager: cannot upload artifact")
+	}
+	return err
+}
+
+// UploadTests uploads a junit test report.
+func (m *Manager) UploadTests(ctx context.Context, id int64, r io.Reader) error {
+	logger := logrus.WithField("step-id", id)
+
+	result, err := parseJUnit(r)
+	if err != nil {
+		logger = logger.WithError(err)
+		logger.Warnln("manager: cannot parse test report")
+		return err
+	}
+	step, err := m.Steps.Find(noContext, id)
+	if err != nil {
+		logger = logger.WithError(err)
+		logger.Warnln("manager: cannot find step")
+		return err
+	}
+	stage, err := m.Stages.Find(noContext, step.StageID)
+	if err != nil {
+		logger = logger.WithError(err)
+		logger.Warnln("manager: cannot find stage")
+		return err
+	}
+	result.BuildID = stage.BuildID
+	result.StageID = stage.ID
+	result.StepID = step.ID
+	err = m.Tests.Create(ctx, result)
+	if err != nil {
+		logger = logger.WithError(err)
+		logger.Warnln("manager: cannot create test results")
+	}
+	return err
+}
+
+// UploadCoverage uploads a code coverage summary.
+func (m *Manager) UploadCoverage(ctx context.Context, id int64, coverage *core.Coverage) error {
+	logger := logrus.WithField("step-id", id)
+
+	err := coverage.Validate()
+	if err != nil {
+		logger = logger.WithError(err)
+		logger.Warnln("manager: invalid coverage report")
+		return err
+	}
+	step, err := m.Steps.Find(noContext, id)
+	if err != nil {
+		logger = logger.WithError(err)
+		logger.Warnln("manager: cannot find step")
+		return err
+	}
+	stage, err := m.Stages.Find(noContext, step.StageID)
+	if err != nil {
+		logger = logger.WithError(err)
+		logger.Warnln("manager: cannot find stage")
+		return err
+	}
+	build, err := m.Builds.Find(noContext, stage.BuildID)
+	if err != nil {
+		logger = logger.WithError(err)
+		logger.Warnln("manager: cannot find build")
+		return err
+	}
+	coverage.ID = 0
+	coverage.RepoID = build.RepoID
+	coverage.BuildID = build.ID
+	coverage.StepID = step.ID
+	coverage.Ref = build.Ref
+	err = m.Coverage.Create(ctx, coverage)
+	if err != nil {
+		logger = logger.WithError(err)
+		logger.Warnln("manager: cannot create coverage report")
+	}
+	return err
+}
+
+// UploadAnnotations uploads step annotations.
+func (m *Manager) UploadAnnotations(ctx context.Context, id int64, annotations []*core.Annotation) error {
+	logger := logrus.WithField("step-id", id)
+
+	for _, annotation := range annotations {
+		err := annotation.Validate()
+		if err != nil {
+			logger = logger.WithError(err)
+			logger.Warnln("manager: invalid annotation")
+			return err
+		}
+	}
+	step, err := m.Steps.Find(noContext, id)
+	if err != nil {
+		logger = logger.WithError(err)
+		logger.Warnln("manager: cannot find step")
+		return err
+	}
+	stage, err := m.Stages.Find(noContext, step.StageID)
+	if err != nil {
+		logger = logger.WithError(err)
+		logger.Warnln("manager: cannot find stage")
+		return err
+	}
+	for _, annotation := range annotations {
+		annotation.ID = 0
+		annotation.BuildID = stage.BuildID
+		annotation.StageID = stage.ID
+		annotation.StepID = step.ID
+		err = m.Annotations.Create(ctx, annotation)
+		if err != nil {
+			logger = logger.WithError(err)
+			logger.Warnln("manager: cannot create annotation")
+			return err
+		}
+	}
+	return nil
+}
+
+// UploadDeployment records a deployment reported by the step.
+func (m *Manager) UploadDeployment(ctx context.Context, id int64, deployment *core.Deployment) error {
+	logger := logrus.WithField("step-id", id)
+
+	err := deployment.Validate()
+	if err != nil {
+		logger = logger.WithError(err)
+		logger.Warnln("manager: invalid deployment")
+		return err
+	}
+	step, err := m.Steps.Find(noContext, id)
+	if err != nil {
+		logger = logger.WithError(err)
+		logger.Warnln("manager: cannot find step")
+		return err
+	}
+	stage, err := m.Stages.Find(noContext, step.StageID)
+	if err != nil {
+		logger = logger.WithError(err)
+		logger.Warnln("manager: cannot find stage")
+		return err
+	}
+	build, err := m.Builds.Find(noContext, stage.BuildID)
+	if err != nil {
+		logger = logger.WithError(err)
+		logger.Warnln("manager: cannot find build")
+		return err
+	}
+	deployment.ID = 0
+	deployment.RepoID = build.RepoID
+	deployment.BuildID = build.ID
+	deployment.Number = build.Number
+	deployment.Commit = build.After
+	deployment.Ref = build.Ref
+	deployment.Status = core.StatusPassing
+	deployment.Deployer = build.Trigger
+	err = m.Deployments.Create(ctx, deployment)
+	if err != nil {
+		logger = logger.WithError(err)
+		logger.Warnln("manager: cannot create deployment")
+	}
+	return err
+}
@@ -5,11 +5,522 @@
 package manager
 
 import (
+	"bytes"
 	"io/ioutil"
+	"testing"
 
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/mock"
+	"github.com/drone/drone/store/shared/db"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
 	"github.com/sirupsen/logrus"
 )
 
 func init() {
 	logrus.SetOutput(ioutil.Discard)
 }
+
+func TestUploadArtifact(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockStep := &core.Step{ID: 3, StageID: 2}
+	mockStage := &core.Stage{ID: 2, BuildID: 1}
+	buf := bytes.NewBufferString("hello world")
+
+	want := &core.Artifact{
+		BuildID: 1,
+		StageID: 2,
+		StepID:  3,
+		Name:    "dist/hello-world.tar.gz",
+	}
+
+	steps := mock.NewMockStepStore(controller)
+	steps.EXPECT().Find(gomock.Any(), mockStep.ID).Return(mockStep, nil)
+
+	stages := mock.NewMockStageStore(controller)
+	stages.EXPECT().Find(gomock.Any(), mockStage.ID).Return(mockStage, nil)
+
+	artifacts := mock.NewMockArtifactStore(controller)
+	artifacts.EXPECT().Create(gomock.Any(), gomock.Any(), buf).Do(func(_, got, _ interface{}) {
+		if diff := cmp.Diff(got, want); diff != "" {
+			t.Errorf(diff)
+		}
+	}).Return(nil)
+
+	m := &Manager{
+		Artifacts: artifacts,
+		Stages:    stages,
+		Steps:     steps,
+	}
+	err := m.UploadArtifact(noContext, mockStep.ID, "../dist/hello-world.tar.gz", buf)
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func TestUploadArtifact_InvalidName(t *testing.T) {
+	m := new(Manager)
+	err := m.UploadArtifact(noContext, 1, "/", nil)
+	if err != errArtifactName {
+		t.Errorf("Want invalid artifact name error, got %v", err)
+	}
+}
+
+func TestUploadCoverage(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockStep := &core.Step{ID: 3, StageID: 2}
+	mockStage := &core.Stage{ID: 2, BuildID: 1}
+	mockBuild := &core.Build{ID: 1, RepoID: 4, Ref: "refs/heads/master"}
+
+	steps := mock.NewMockStepStore(controller)
+	steps.EXPECT().Find(gomock.Any(), mockStep.ID).Return(mockStep, nil)
+
+	stages := mock.NewMockStageStore(controller)
+	stages.EXPECT().Find(gomock.Any(), mockStage.ID).Return(mockStage, nil)
+
+	builds := mock.NewMockBuildStore(controller)
+	builds.EXPECT().Find(gomock.Any(), mockBuild.ID).Return(mockBuild, nil)
+
+	want := &core.Coverage{
+		RepoID:  4,
+		BuildID: 1,
+		StepID:  3,
+		Ref:     "refs/heads/master",
+		Percent: 82.5,
+	}
+	coverage := mock.NewMockCoverageStore(controller)
+	coverage.EXPECT().Create(gomock.Any(), want).Return(nil)
+
+	m := &Manager{
+		Builds:   builds,
+		Coverage: coverage,
+		Stages:   stages,
+		Steps:    steps,
+	}
+	err := m.UploadCoverage(noContext, mockStep.ID, &core.Coverage{Percent: 82.5})
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func TestAccept(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockStage := &core.Stage{ID: 1}
+
+	scheduler := mock.NewMockScheduler(controller)
+	scheduler.EXPECT().Request(gomock.Any(), gomock.Any()).Return(mockStage, nil)
+
+	stages := mock.NewMockStageStore(controller)
+	stages.EXPECT().Find(gomock.Any(), mockStage.ID).Return(&core.Stage{ID: 1}, nil)
+	stages.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+
+	m := &Manager{
+		Scheduler: scheduler,
+		Stages:    stages,
+	}
+	_, err := m.Request(noContext, &Request{OS: "linux", Arch: "amd64", Machine: "agent-1"})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	got, err := m.Accept(noContext, mockStage.ID, "agent-1")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if got.Machine != "agent-1" {
+		t.Errorf("Expect stage assigned to the machine")
+	}
+	if got.Dispatched == 0 {
+		t.Errorf("Expect the stage dispatch time recorded")
+	}
+}
+
+func TestAccept_Retry(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	stale := &core.Stage{ID: 1}
+	next := &core.Stage{ID: 2}
+
+	scheduler := mock.NewMockScheduler(controller)
+	gomock.InOrder(
+		scheduler.EXPECT().Request(gomock.Any(), gomock.Any()).Return(stale, nil),
+		scheduler.EXPECT().Request(gomock.Any(), gomock.Any()).Return(next, nil),
+	)
+
+	stages := mock.NewMockStageStore(controller)
+	stages.EXPECT().Find(gomock.Any(), stale.ID).Return(&core.Stage{ID: 1, Machine: "agent-2"}, nil)
+	stages.EXPECT().Find(gomock.Any(), next.ID).Return(&core.Stage{ID: 2}, nil)
+	stages.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+
+	m := &Manager{
+		Scheduler: scheduler,
+		Stages:    stages,
+	}
+	_, err := m.Request(noContext, &Request{OS: "linux", Arch: "amd64", Machine: "agent-1"})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	got, err := m.Accept(noContext, stale.ID, "agent-1")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if got == nil || got.ID != next.ID {
+		t.Errorf("Expect the next eligible stage accepted")
+		return
+	}
+	if got.Machine != "agent-1" {
+		t.Errorf("Expect the next eligible stage assigned to the machine")
+	}
+}
+
+func TestAccept_Conflict(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	stages := mock.NewMockStageStore(controller)
+	stages.EXPECT().Find(gomock.Any(), int64(1)).Return(&core.Stage{ID: 1, Machine: "agent-2"}, nil)
+
+	m := &Manager{
+		Stages: stages,
+	}
+	_, err := m.Accept(noContext, 1, "agent-1")
+	if err != db.ErrOptimisticLock {
+		t.Errorf("Expect optimistic lock error, got %v", err)
+	}
+}
+
+func TestDetails_SecretAccess(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockUser := &core.User{ID: 1}
+	mockRepo := &core.Repository{ID: 2, UserID: 1}
+	mockBuild := &core.Build{ID: 3, RepoID: 2, Number: 4, Event: core.EventPullRequest}
+	mockStage := &core.Stage{ID: 5, BuildID: 3}
+	mockSecrets := []*core.Secret{
+		{ID: 6, RepoID: 2, Name: "docker_password", PullRequest: true},
+		{ID: 7, RepoID: 2, Name: "deploy_token"},
+	}
+
+	stages := mock.NewMockStageStore(controller)
+	stages.EXPECT().Find(gomock.Any(), mockStage.ID).Return(mockStage, nil)
+
+	builds := mock.NewMockBuildStore(controller)
+	builds.EXPECT().Find(gomock.Any(), mockBuild.ID).Return(mockBuild, nil)
+
+	repos := mock.NewMockRepositoryStore(controller)
+	repos.EXPECT().Find(gomock.Any(), mockRepo.ID).Return(mockRepo, nil)
+
+	users := mock.NewMockUserStore(controller)
+	users.EXPECT().Find(gomock.Any(), mockUser.ID).Return(mockUser, nil)
+
+	config := mock.NewMockConfigService(controller)
+	config.EXPECT().Find(gomock.Any(), gomock.Any()).Return(&core.Config{}, nil)
+
+	secrets := mock.NewMockSecretStore(controller)
+	secrets.EXPECT().List(gomock.Any(), mockRepo.ID).Return(mockSecrets, nil)
+
+	// only the secret provided to the pull request is
+	// recorded in the access log.
+	want := &core.SecretAccess{
+		SecretID:    6,
+		RepoID:      2,
+		BuildID:     3,
+		BuildNumber: 4,
+		StageID:     5,
+	}
+	access := mock.NewMockSecretAccessStore(controller)
+	access.EXPECT().Create(gomock.Any(), want).Return(nil)
+
+	registries := mock.NewMockRegistryStore(controller)
+	registries.EXPECT().List(gomock.Any(), mockRepo.ID).Return(nil, nil)
+	registries.EXPECT().ListNamespace(gomock.Any(), mockRepo.Namespace).Return(nil, nil)
+
+	privileged := mock.NewMockPrivilegedImageStore(controller)
+	privileged.EXPECT().ListNamespace(gomock.Any(), "").Return(nil, nil)
+	privileged.EXPECT().ListNamespace(gomock.Any(), mockRepo.Namespace).Return(nil, nil)
+
+	variables := mock.NewMockVariableStore(controller)
+	variables.EXPECT().ListNamespace(gomock.Any(), "").Return(nil, nil)
+	variables.EXPECT().ListNamespace(gomock.Any(), mockRepo.Namespace).Return(nil, nil)
+	variables.EXPECT().ListRepo(gomock.Any(), mockRepo.ID).Return(nil, nil)
+
+	m := &Manager{
+		Access:     access,
+		Builds:     builds,
+		Config:     config,
+		Privileged: privileged,
+		Registries: registries,
+		Repos:      repos,
+		Secrets:    secrets,
+		Stages:     stages,
+		Users:      users,
+		Variables:  variables,
+	}
+	got, err := m.Details(noContext, mockStage.ID)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if len(got.Secrets) != 1 {
+		t.Errorf("Want 1 secret provided to the build, got %d", len(got.Secrets))
+	}
+}
+
+// this test verifies that secrets decrypted from the secrets
+// file are provided to the build, and are not recorded in
+// the access log.
+func TestDetails_SecretFiles(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockUser := &core.User{ID: 1}
+	mockRepo := &core.Repository{ID: 2, UserID: 1}
+	mockBuild := &core.Build{ID: 3, RepoID: 2, Number: 4, Event: core.EventPush}
+	mockStage := &core.Stage{ID: 5, BuildID: 3}
+	mockSecrets := []*core.Secret{
+		{Name: "slack_token", Data: "xoxb-4b6c"},
+	}
+
+	stages := mock.NewMockStageStore(controller)
+	stages.EXPECT().Find(gomock.Any(), mockStage.ID).Return(mockStage, nil)
+
+	builds := mock.NewMockBuildStore(controller)
+	builds.EXPECT().Find(gomock.Any(), mockBuild.ID).Return(mockBuild, nil)
+
+	repos := mock.NewMockRepositoryStore(controller)
+	repos.EXPECT().Find(gomock.Any(), mockRepo.ID).Return(mockRepo, nil)
+
+	users := mock.NewMockUserStore(controller)
+	users.EXPECT().Find(gomock.Any(), mockUser.ID).Return(mockUser, nil)
+
+	config := mock.NewMockConfigService(controller)
+	config.EXPECT().Find(gomock.Any(), gomock.Any()).Return(&core.Config{}, nil)
+
+	secrets := mock.NewMockSecretStore(controller)
+	secrets.EXPECT().List(gomock.Any(), mockRepo.ID).Return(nil, nil)
+
+	secretFiles := mock.NewMockSecretFileService(controller)
+	secretFiles.EXPECT().List(gomock.Any(), mockUser, mockRepo, mockBuild).Return(mockSecrets, nil)
+
+	registries := mock.NewMockRegistryStore(controller)
+	registries.EXPECT().List(gomock.Any(), mockRepo.ID).Return(nil, nil)
+	registries.EXPECT().ListNamespace(gomock.Any(), mockRepo.Namespace).Return(nil, nil)
+
+	privileged := mock.NewMockPrivilegedImageStore(controller)
+	privileged.EXPECT().ListNamespace(gomock.Any(), "").Return(nil, nil)
+	privileged.EXPECT().ListNamespace(gomock.Any(), mockRepo.Namespace).Return(nil, nil)
+
+	variables := mock.NewMockVariableStore(controller)
+	variables.EXPECT().ListNamespace(gomock.Any(), "").Return(nil, nil)
+	variables.EXPECT().ListNamespace(gomock.Any(), mockRepo.Namespace).Return(nil, nil)
+	variables.EXPECT().ListRepo(gomock.Any(), mockRepo.ID).Return(nil, nil)
+
+	m := &Manager{
+		Builds:      builds,
+		Config:      config,
+		Privileged:  privileged,
+		Registries:  registries,
+		Repos:       repos,
+		Secrets:     secrets,
+		SecretFiles: secretFiles,
+		Stages:      stages,
+		Users:       users,
+		Variables:   variables,
+	}
+	got, err := m.Details(noContext, mockStage.ID)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if diff := cmp.Diff(got.Secrets, mockSecrets); diff != "" {
+		t.Errorf(diff)
+	}
+}
+
+// this test verifies that identity tokens are not issued
+// to pull requests from forks.
+func TestDetails_IDTokenFork(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockUser := &core.User{ID: 1}
+	mockRepo := &core.Repository{ID: 2, UserID: 1, Slug: "octocat/hello-world"}
+	mockBuild := &core.Build{ID: 3, RepoID: 2, Number: 4, Event: core.EventPullRequest, Fork: "spaceghost/hello-world"}
+	mockStage := &core.Stage{ID: 5, BuildID: 3}
+
+	stages := mock.NewMockStageStore(controller)
+	stages.EXPECT().Find(gomock.Any(), mockStage.ID).Return(mockStage, nil)
+
+	builds := mock.NewMockBuildStore(controller)
+	builds.EXPECT().Find(gomock.Any(), mockBuild.ID).Return(mockBuild, nil)
+
+	repos := mock.NewMockRepositoryStore(controller)
+	repos.EXPECT().Find(gomock.Any(), mockRepo.ID).Return(mockRepo, nil)
+
+	users := mock.NewMockUserStore(controller)
+	users.EXPECT().Find(gomock.Any(), mockUser.ID).Return(mockUser, nil)
+
+	config := mock.NewMockConfigService(controller)
+	config.EXPECT().Find(gomock.Any(), gomock.Any()).Return(&core.Config{}, nil)
+
+	secrets := mock.NewMockSecretStore(controller)
+	secrets.EXPECT().List(gomock.Any(), mockRepo.ID).Return(nil, nil)
+
+	registries := mock.NewMockRegistryStore(controller)
+	registries.EXPECT().List(gomock.Any(), mockRepo.ID).Return(nil, nil)
+	registries.EXPECT().ListNamespace(gomock.Any(), mockRepo.Namespace).Return(nil, nil)
+
+	privileged := mock.NewMockPrivilegedImageStore(controller)
+	privileged.EXPECT().ListNamespace(gomock.Any(), "").Return(nil, nil)
+	privileged.EXPECT().ListNamespace(gomock.Any(), mockRepo.Namespace).Return(nil, nil)
+
+	variables := mock.NewMockVariableStore(controller)
+	variables.EXPECT().ListNamespace(gomock.Any(), "").Return(nil, nil)
+	variables.EXPECT().ListNamespace(gomock.Any(), mockRepo.Namespace).Return(nil, nil)
+	variables.EXPECT().ListRepo(gomock.Any(), mockRepo.ID).Return(nil, nil)
+
+	// the mock fails the test if a token is issued.
+	idtokens := mock.NewMockIDTokenService(controller)
+
+	m := &Manager{
+		Builds:     builds,
+		Config:     config,
+		IDTokens:   idtokens,
+		Privileged: privileged,
+		Registries: registries,
+		Repos:      repos,
+		Secrets:    secrets,
+		Stages:     stages,
+		Users:      users,
+		Variables:  variables,
+	}
+	got, err := m.Details(noContext, mockStage.ID)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if got.IDToken != "" {
+		t.Errorf("Expect no identity token for pull requests from forks")
+	}
+}
+
+func TestUploadAnnotations(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockStep := &core.Step{ID: 3, StageID: 2}
+	mockStage := &core.Stage{ID: 2, BuildID: 1}
+
+	steps := mock.NewMockStepStore(controller)
+	steps.EXPECT().Find(gomock.Any(), mockStep.ID).Return(mockStep, nil)
+
+	stages := mock.NewMockStageStore(controller)
+	stages.EXPECT().Find(gomock.Any(), mockStage.ID).Return(mockStage, nil)
+
+	want := &core.Annotation{
+		BuildID: 1,
+		StageID: 2,
+		StepID:  3,
+		Kind:    core.AnnotationValue,
+		Name:    "digest",
+		Value:   "sha256:3e8d",
+	}
+	annotations := mock.NewMockAnnotationStore(controller)
+	annotations.EXPECT().Create(gomock.Any(), want).Return(nil)
+
+	m := &Manager{
+		Annotations: annotations,
+		Stages:      stages,
+		Steps:       steps,
+	}
+	err := m.UploadAnnotations(noContext, mockStep.ID, []*core.Annotation{
+		{Kind: core.AnnotationValue, Name: "digest", Value: "sha256:3e8d"},
+	})
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+// this test verifies that no annotations are stored when
+// any of the annotations are invalid.
+func TestUploadAnnotations_Invalid(t *testing.T) {
+	m := new(Manager)
+	err := m.UploadAnnotations(noContext, 3, []*core.Annotation{
+		{Kind: core.AnnotationValue, Name: "digest", Value: "sha256:3e8d"},
+		{Kind: core.AnnotationLink, Name: "preview", Value: "ftp://preview"},
+	})
+	if err == nil {
+		t.Errorf("Expect error when annotation is invalid")
+	}
+}
+
+func TestUploadDeployment(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockStep := &core.Step{ID: 3, StageID: 2}
+	mockStage := &core.Stage{ID: 2, BuildID: 1}
+	mockBuild := &core.Build{
+		ID:      1,
+		RepoID:  4,
+		Number:  5,
+		After:   "7fd1a60b01f91b314f59955a4e4d4e80d8edf11d",
+		Ref:     "refs/heads/master",
+		Trigger: "octocat",
+	}
+
+	steps := mock.NewMockStepStore(controller)
+	steps.EXPECT().Find(gomock.Any(), mockStep.ID).Return(mockStep, nil)
+
+	stages := mock.NewMockStageStore(controller)
+	stages.EXPECT().Find(gomock.Any(), mockStage.ID).Return(mockStage, nil)
+
+	builds := mock.NewMockBuildStore(controller)
+	builds.EXPECT().Find(gomock.Any(), mockBuild.ID).Return(mockBuild, nil)
+
+	want := &core.Deployment{
+		RepoID:      4,
+		BuildID:     1,
+		Number:      5,
+		Environment: "production",
+		Commit:      "7fd1a60b01f91b314f59955a4e4d4e80d8edf11d",
+		Ref:         "refs/heads/master",
+		Status:      core.StatusPassing,
+		Deployer:    "octocat",
+	}
+	deployments := mock.NewMockDeploymentStore(controller)
+	deployments.EXPECT().Create(gomock.Any(), want).Return(nil)
+
+	m := &Manager{
+		Builds:      builds,
+		Deployments: deployments,
+		Stages:      stages,
+		Steps:       steps,
+	}
+	err := m.UploadDeployment(noContext, mockStep.ID, &core.Deployment{Environment: "production"})
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+// this test verifies that the deployment is not stored
+// when the environment is missing.
+func TestUploadDeployment_Invalid(t *testing.T) {
+	m := new(Manager)
+	err := m.UploadDeployment(noContext, 3, &core.Deployment{})
+	if err == nil {
+		t.Errorf("Expect error when deployment is invalid")
+	}
+}
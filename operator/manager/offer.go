@@ -0,0 +1,57 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package manager
+
+import "sync"
+
+// offerCacheSize is the maximum number of stage offers that
+// are cached. The cache is reset when the limit is exceeded.
+const offerCacheSize = 1000
+
+// offer records the request filter provided by the agent
+// and the time the stage was offered to the agent.
+type offer struct {
+	args       *Request
+	dispatched int64
+}
+
+// offerKey identifies a stage offered to an agent. Multiple
+// agents may be offered the same stage.
+type offerKey struct {
+	stage   int64
+	machine string
+}
+
+// offerCache caches the stages offered to agents, so that
+// the next eligible stage can be accepted on behalf of the
+// agent if the offered stage is accepted by another agent.
+type offerCache struct {
+	sync.Mutex
+	items map[offerKey]*offer
+}
+
+func (c *offerCache) set(stage int64, machine string, o *offer) {
+	c.Lock()
+	defer c.Unlock()
+	if c.items == nil || len(c.items) >= offerCacheSize {
+		c.items = map[offerKey]*offer{}
+	}
+	c.items[offerKey{stage, machine}] = o
+}
+
+// pop removes and returns the stage offered to the named
+// machine. Agents that do not provide a machine name when
+// requesting a stage fallback to the anonymous offer.
+func (c *offerCache) pop(stage int64, machine string) (*offer, bool) {
+	c.Lock()
+	defer c.Unlock()
+	for _, key := range []offerKey{{stage, machine}, {stage, ""}} {
+		if o, ok := c.items[key]; ok {
+			delete(c.items, key)
+			return o, true
+		}
+	}
+	return nil, false
+}
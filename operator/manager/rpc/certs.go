@@ -0,0 +1,203 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package rpc
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"net/http"
+	"time"
+)
+
+// certificate validity durations.
+const (
+	caValidity   = time.Hour * 24 * 365 * 10
+	certValidity = time.Hour * 24 * 365
+)
+
+var errInvalidPEM = errors.New("rpc: invalid pem encoded certificate or key")
+
+// Certificate is a pem encoded certificate and private key.
+type Certificate struct {
+	Cert []byte
+	Key  []byte
+}
+
+// NewCA returns a new self-signed certificate authority used
+// to issue server and agent certificates.
+func NewCA(name string) (*Certificate, error) {
+	template, err := newTemplate(name, caValidity)
+	if err != nil {
+		return nil, err
+	}
+	template.IsCA = true
+	template.BasicConstraintsValid = true
+	template.KeyUsage = x509.KeyUsageCertSign | x509.KeyUsageCRLSign | x509.KeyUsageDigitalSignature
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	return encode(template, template, key, key)
+}
+
+// Issue issues a certificate signed by the certificate
+// authority. The hosts are added to the certificate subject
+// alternate names, and are required for server certificates.
+// A certificate issued without hosts is an agent certificate
+// that is only valid for client authentication.
+func Issue(ca *Certificate, name string, hosts ...string) (*Certificate, error) {
+	parent, signer, err := decode(ca)
+	if err != nil {
+		return nil, err
+	}
+	template, err := newTemplate(name, certValidity)
+	if err != nil {
+		return nil, err
+	}
+	template.KeyUsage = x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment
+	template.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}
+	if len(hosts) != 0 {
+		template.ExtKeyUsage = append(template.ExtKeyUsage, x509.ExtKeyUsageServerAuth)
+	}
+	for _, host := range hosts {
+		if ip := net.ParseIP(host); ip != nil {
+			template.IPAddresses = append(template.IPAddresses, ip)
+		} else {
+			template.DNSNames = append(template.DNSNames, host)
+		}
+	}
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	return encode(template, parent, key, signer)
+}
+
+// Fingerprint returns the hex encoded sha256 fingerprint of
+// the certificate.
+func Fingerprint(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.Raw)
+	return hex.EncodeToString(sum[:])
+}
+
+// ServerTLSConfig returns a tls configuration that verifies
+// agent certificates signed by the certificate authority in
+// the pem encoded file. Connections without a certificate are
+// accepted; use SetRequireCert to refuse them.
+func ServerTLSConfig(caFile string) (*tls.Config, error) {
+	pool, err := loadPool(caFile)
+	if err != nil {
+		return nil, err
+	}
+	return &tls.Config{
+		ClientCAs:  pool,
+		ClientAuth: tls.VerifyClientCertIfGiven,
+		MinVersion: tls.VersionTLS12,
+	}, nil
+}
+
+// ClientTLSConfig returns a tls configuration that presents
+// the agent certificate to the server. If the certificate
+// authority file is provided, the server certificate must be
+// signed by the certificate authority.
+func ClientTLSConfig(caFile, certFile, keyFile string) (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, err
+	}
+	config := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+	if caFile != "" {
+		config.RootCAs, err = loadPool(caFile)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return config, nil
+}
+
+// peerFingerprint returns the fingerprint of the verified
+// agent certificate, or an empty string if the agent did not
+// present a certificate.
+func peerFingerprint(r *http.Request) string {
+	if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 {
+		return ""
+	}
+	return Fingerprint(r.TLS.VerifiedChains[0][0])
+}
+
+func newTemplate(name string, validity time.Duration) (*x509.Certificate, error) {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	return &x509.Certificate{
+		SerialNumber: serial,
+		Subject: pkix.Name{
+			CommonName:   name,
+			Organization: []string{"drone"},
+		},
+		NotBefore: now.Add(-time.Minute),
+		NotAfter:  now.Add(validity),
+	}, nil
+}
+
+func encode(template, parent *x509.Certificate, key, signer *ecdsa.PrivateKey) (*Certificate, error) {
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, signer)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+	return &Certificate{
+		Cert: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		Key:  pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: raw}),
+	}, nil
+}
+
+func decode(c *Certificate) (*x509.Certificate, *ecdsa.PrivateKey, error) {
+	certBlock, _ := pem.Decode(c.Cert)
+	keyBlock, _ := pem.Decode(c.Key)
+	if certBlock == nil || keyBlock == nil {
+		return nil, nil, errInvalidPEM
+	}
+	cert, err := x509.ParseCertificate(certBlock.Bytes)
+	if err != nil {
+		return nil, nil, err
+	}
+	key, err := x509.ParseECPrivateKey(keyBlock.Bytes)
+	if err != nil {
+		return nil, nil, err
+	}
+	return cert, key, nil
+}
+
+func loadPool(file string) (*x509.CertPool, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, errInvalidPEM
+	}
+	return pool, nil
+}
@@ -0,0 +1,81 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package rpc
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+)
+
+func TestIssue(t *testing.T) {
+	ca, err := NewCA("drone-ca")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	agent, err := Issue(ca, "agent-1")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM(ca.Cert) {
+		t.Errorf("Want valid certificate authority")
+		return
+	}
+	block, _ := pem.Decode(agent.Cert)
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	_, err = cert.Verify(x509.VerifyOptions{
+		Roots:     roots,
+		KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	})
+	if err != nil {
+		t.Errorf("Want agent certificate signed by the certificate authority, got %s", err)
+	}
+	if got, want := cert.Subject.CommonName, "agent-1"; got != want {
+		t.Errorf("Want common name %s, got %s", want, got)
+	}
+	if got, want := len(Fingerprint(cert)), 64; got != want {
+		t.Errorf("Want fingerprint length %d, got %d", want, got)
+	}
+}
+
+func TestIssue_Server(t *testing.T) {
+	ca, err := NewCA("drone-ca")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	server, err := Issue(ca, "drone", "drone.company.com", "10.0.0.1")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	block, _ := pem.Decode(server.Cert)
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if err := cert.VerifyHostname("drone.company.com"); err != nil {
+		t.Error(err)
+	}
+	if err := cert.VerifyHostname("10.0.0.1"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestIssue_InvalidCA(t *testing.T) {
+	_, err := Issue(&Certificate{}, "agent-1")
+	if err != errInvalidPEM {
+		t.Errorf("Want invalid pem error, got %v", err)
+	}
+}
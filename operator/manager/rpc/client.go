@@ -6,13 +6,16 @@ package rpc
 
 import (
 	"context"
+	"crypto/tls"
 	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"log"
 	"net/http"
+	"net/url"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 
@@ -34,6 +37,10 @@ type Client struct {
 	token  string
 	server string
 	client *retryablehttp.Client
+
+	// capabilities negotiated with the server during
+	// the handshake.
+	capabilities []string
 }
 
 // NewClient returns a new rpc client that is able to
@@ -64,6 +71,47 @@ func (s *Client) SetDebug(debug bool) {
 	}
 }
 
+// SetTLS configures the client to present the agent
+// certificate when connecting to the server.
+func (s *Client) SetTLS(config *tls.Config) {
+	if t, ok := s.client.HTTPClient.Transport.(*http.Transport); ok {
+		t.TLSClientConfig = config
+	}
+}
+
+// Handshake negotiates the protocol version and capabilities
+// with the server. The negotiated capabilities are declared
+// with all subsequent requests.
+func (s *Client) Handshake(ctx context.Context, in *Handshake) (*Handshake, error) {
+	in.Protocol = ProtocolVersion
+	out := &Handshake{}
+	err := s.send(ctx, "/rpc/v1/handshake", in, out)
+	if err != nil {
+		return nil, err
+	}
+	s.capabilities = out.Capabilities
+	return out, nil
+}
+
+// Heartbeat repeats the handshake to keep the agent in the
+// server registry of connected agents. The capabilities
+// negotiated during the initial handshake are not changed.
+func (s *Client) Heartbeat(ctx context.Context, in *Handshake) error {
+	in.Protocol = ProtocolVersion
+	return s.send(ctx, "/rpc/v1/handshake", in, &Handshake{})
+}
+
+// Capable returns true if the capability was negotiated
+// with the server.
+func (s *Client) Capable(capability string) bool {
+	for _, v := range s.capabilities {
+		if v == capability {
+			return true
+		}
+	}
+	return false
+}
+
 // Request requests the next available build stage for execution.
 func (s *Client) Request(ctx context.Context, args *manager.Request) (*core.Stage, error) {
 	timeout, cancel := context.WithTimeout(ctx, time.Minute)
@@ -84,9 +132,18 @@ func (s *Client) Request(ctx context.Context, args *manager.Request) (*core.Stag
 }
 
 // Accept accepts the build stage for execution.
-func (s *Client) Accept(ctx context.Context, stage int64, machine string) error {
+func (s *Client) Accept(ctx context.Context, stage int64, machine string) (*core.Stage, error) {
 	in := &acceptRequest{Stage: stage, Machine: machine}
-	return s.send(noContext, "/rpc/v1/accept", in, nil)
+	out := &core.Stage{}
+	err := s.send(noContext, "/rpc/v1/accept", in, out)
+	if err != nil {
+		return nil, err
+	}
+	// older servers do not return the accepted stage.
+	if out.ID == 0 {
+		return nil, nil
+	}
+	return out, nil
 }
 
 // Netrc returns a valid netrc for execution.
@@ -206,6 +263,31 @@ func (s *Client) UploadBytes(ctx context.Context, step int64, data []byte) error
 	return s.upload(noContext, endpoint, data)
 }
 
+func (s *Client) UploadArtifact(ctx context.Context, step int64, name string, r io.Reader) error {
+	endpoint := "/rpc/v1/artifact?id=" + fmt.Sprint(step) + "&name=" + url.QueryEscape(name)
+	return s.upload(noContext, endpoint, r)
+}
+
+func (s *Client) UploadTests(ctx context.Context, step int64, r io.Reader) error {
+	endpoint := "/rpc/v1/tests?id=" + fmt.Sprint(step)
+	return s.upload(noContext, endpoint, r)
+}
+
+func (s *Client) UploadCoverage(ctx context.Context, step int64, coverage *core.Coverage) error {
+	in := &coverageRequest{Step: step, Coverage: coverage}
+	return s.send(noContext, "/rpc/v1/coverage", in, nil)
+}
+
+func (s *Client) UploadAnnotations(ctx context.Context, step int64, annotations []*core.Annotation) error {
+	in := &annotationsRequest{Step: step, Annotations: annotations}
+	return s.send(noContext, "/rpc/v1/annotations", in, nil)
+}
+
+func (s *Client) UploadDeployment(ctx context.Context, step int64, deployment *core.Deployment) error {
+	in := &deploymentRequest{Step: step, Deployment: deployment}
+	return s.send(noContext, "/rpc/v1/deployment", in, nil)
+}
+
 func (s *Client) send(ctx context.Context, path string, in, out interface{}) error {
 	// Source a buffer from a pool. The agent may generate a
 	// large number of small requests for log entries. This will
@@ -225,6 +307,8 @@ func (s *Client) send(ctx context.Context, path string, in, out interface{}) err
 	}
 	req = req.WithContext(ctx)
 	req.Header.Set("X-Drone-Token", s.token)
+	req.Header.Set(headerProtocol, strconv.Itoa(ProtocolVersion))
+	req.Header.Set(headerCapabilities, strings.Join(s.capabilities, ","))
 
 	res, err := s.client.Do(req)
 	if res != nil {
@@ -274,6 +358,8 @@ func (s *Client) upload(ctx context.Context, path string, body interface{}) erro
 	}
 	req = req.WithContext(ctx)
 	req.Header.Set("X-Drone-Token", s.token)
+	req.Header.Set(headerProtocol, strconv.Itoa(ProtocolVersion))
+	req.Header.Set(headerCapabilities, strings.Join(s.capabilities, ","))
 
 	res, err := s.client.Do(req)
 	if err != nil {
@@ -63,14 +63,19 @@ func TestAccept(t *testing.T) {
 		Post("/rpc/v1/accept").
 		MatchHeader("X-Drone-Token", "correct-horse-battery-staple").
 		BodyString(`{"Stage":1,"Machine":"localhost"}`).
-		Reply(204)
+		Reply(200).
+		Type("application/json").
+		BodyString(`{"id":2}`)
 
 	client := NewClient("http://drone.company.com", "correct-horse-battery-staple")
 	gock.InterceptClient(client.client.HTTPClient)
-	err := client.Accept(noContext, 1, "localhost")
+	stage, err := client.Accept(noContext, 1, "localhost")
 	if err != nil {
 		t.Error(err)
 	}
+	if stage == nil || stage.ID != 2 {
+		t.Errorf("Expect the accepted stage returned")
+	}
 
 	if gock.IsPending() {
 		t.Errorf("Unfinished requests")
@@ -484,6 +489,120 @@ func TestUpload(t *testing.T) {
 	}
 }
 
+func TestUploadArtifact(t *testing.T) {
+	defer gock.Off()
+
+	buf := bytes.NewBufferString("hello world")
+
+	gock.New("http://drone.company.com").
+		Post("/rpc/v1/artifact").
+		MatchParam("id", "1").
+		MatchParam("name", "dist/hello-world.tar.gz").
+		MatchHeader("X-Drone-Token", "correct-horse-battery-staple").
+		BodyString("hello world").
+		Reply(204)
+
+	client := NewClient("http://drone.company.com", "correct-horse-battery-staple")
+	gock.InterceptClient(client.client.HTTPClient)
+	err := client.UploadArtifact(noContext, 1, "dist/hello-world.tar.gz", buf)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if gock.IsPending() {
+		t.Errorf("Unfinished requests")
+	}
+}
+
+func TestUploadTests(t *testing.T) {
+	defer gock.Off()
+
+	buf := bytes.NewBufferString(`<testsuite name="math"></testsuite>`)
+
+	gock.New("http://drone.company.com").
+		Post("/rpc/v1/tests").
+		MatchParam("id", "1").
+		MatchHeader("X-Drone-Token", "correct-horse-battery-staple").
+		BodyString(`<testsuite name="math"></testsuite>`).
+		Reply(204)
+
+	client := NewClient("http://drone.company.com", "correct-horse-battery-staple")
+	gock.InterceptClient(client.client.HTTPClient)
+	err := client.UploadTests(noContext, 1, buf)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if gock.IsPending() {
+		t.Errorf("Unfinished requests")
+	}
+}
+
+func TestUploadCoverage(t *testing.T) {
+	defer gock.Off()
+
+	gock.New("http://drone.company.com").
+		Post("/rpc/v1/coverage").
+		MatchHeader("X-Drone-Token", "correct-horse-battery-staple").
+		BodyString(`{"Step":1,"Coverage":{"id":0,"repo_id":0,"build_id":0,"step_id":0,"ref":"","percent":82.5,"created":0}}`).
+		Reply(204)
+
+	client := NewClient("http://drone.company.com", "correct-horse-battery-staple")
+	gock.InterceptClient(client.client.HTTPClient)
+	err := client.UploadCoverage(noContext, 1, &core.Coverage{Percent: 82.5})
+	if err != nil {
+		t.Error(err)
+	}
+
+	if gock.IsPending() {
+		t.Errorf("Unfinished requests")
+	}
+}
+
+func TestUploadAnnotations(t *testing.T) {
+	defer gock.Off()
+
+	gock.New("http://drone.company.com").
+		Post("/rpc/v1/annotations").
+		MatchHeader("X-Drone-Token", "correct-horse-battery-staple").
+		BodyString(`{"Step":1,"Annotations":[{"id":0,"build_id":0,"stage_id":0,"step_id":0,"kind":"link","name":"preview","value":"https://preview.company.com","created":0}]}`).
+		Reply(204)
+
+	client := NewClient("http://drone.company.com", "correct-horse-battery-staple")
+	gock.InterceptClient(client.client.HTTPClient)
+	err := client.UploadAnnotations(noContext, 1, []*core.Annotation{
+		{Kind: core.AnnotationLink, Name: "preview", Value: "https://preview.company.com"},
+	})
+	if err != nil {
+		t.Error(err)
+	}
+
+	if gock.IsPending() {
+		t.Errorf("Unfinished requests")
+	}
+}
+
+func TestUploadDeployment(t *testing.T) {
+	defer gock.Off()
+
+	gock.New("http://drone.company.com").
+		Post("/rpc/v1/deployment").
+		MatchHeader("X-Drone-Token", "correct-horse-battery-staple").
+		BodyString(`{"Step":1,"Deployment":{"id":0,"repo_id":0,"build_id":0,"build_number":0,"environment":"production","commit":"","ref":"","status":"","deployer":"","created":0}}`).
+		Reply(204)
+
+	client := NewClient("http://drone.company.com", "correct-horse-battery-staple")
+	gock.InterceptClient(client.client.HTTPClient)
+	err := client.UploadDeployment(noContext, 1, &core.Deployment{Environment: "production"})
+	if err != nil {
+		t.Error(err)
+	}
+
+	if gock.IsPending() {
+		t.Errorf("Unfinished requests")
+	}
+}
+
 // func xTestRetrySend(t *testing.T) {
 // 	defer gock.Off()
 
@@ -502,3 +621,76 @@ func TestUpload(t *testing.T) {
 // 		t.Errorf("Unfinished requests")
 // 	}
 // }
+
+func TestHandshake(t *testing.T) {
+	defer gock.Off()
+
+	gock.New("http://drone.company.com").
+		Post("/rpc/v1/handshake").
+		MatchHeader("X-Drone-Token", "correct-horse-battery-staple").
+		MatchHeader("X-Drone-Protocol", "2").
+		BodyString(`{"protocol":2,"version":"1.0.0","machine":"localhost","capabilities":["labels","stream"]}`).
+		Reply(200).
+		Type("application/json").
+		BodyString(`{"protocol":2,"version":"1.0.0","capabilities":["labels"]}`)
+
+	client := NewClient("http://drone.company.com", "correct-horse-battery-staple")
+	gock.InterceptClient(client.client.HTTPClient)
+	out, err := client.Handshake(noContext, &Handshake{
+		Version:      "1.0.0",
+		Machine:      "localhost",
+		Capabilities: []string{CapabilityLabels, CapabilityStream},
+	})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if got, want := out.Protocol, 2; got != want {
+		t.Errorf("Want protocol %d, got %d", want, got)
+	}
+	if !client.Capable(CapabilityLabels) {
+		t.Errorf("Want labels capability negotiated")
+	}
+	if client.Capable(CapabilityStream) {
+		t.Errorf("Want stream capability not negotiated")
+	}
+
+	if gock.IsPending() {
+		t.Errorf("Unfinished requests")
+	}
+}
+
+func TestHeartbeat(t *testing.T) {
+	defer gock.Off()
+
+	gock.New("http://drone.company.com").
+		Post("/rpc/v1/handshake").
+		MatchHeader("X-Drone-Token", "correct-horse-battery-staple").
+		BodyString(`{"protocol":2,"version":"1.0.0","machine":"localhost","os":"linux","arch":"arm64","capabilities":["labels"],"labels":{"gpu":"nvidia"}}`).
+		Reply(200).
+		Type("application/json").
+		BodyString(`{"protocol":2,"version":"1.0.0","capabilities":[]}`)
+
+	client := NewClient("http://drone.company.com", "correct-horse-battery-staple")
+	client.capabilities = []string{CapabilityLabels}
+	gock.InterceptClient(client.client.HTTPClient)
+	err := client.Heartbeat(noContext, &Handshake{
+		Version:      "1.0.0",
+		Machine:      "localhost",
+		OS:           "linux",
+		Arch:         "arm64",
+		Capabilities: []string{CapabilityLabels},
+		Labels:       map[string]string{"gpu": "nvidia"},
+	})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if !client.Capable(CapabilityLabels) {
+		t.Errorf("Want negotiated capabilities unchanged")
+	}
+
+	if gock.IsPending() {
+		t.Errorf("Unfinished requests")
+	}
+}
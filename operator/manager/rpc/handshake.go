@@ -0,0 +1,98 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package rpc
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// ProtocolVersion is the version of the rpc protocol
+// implemented by the server and client. Agents that do not
+// complete the handshake are assumed to implement version 1.
+const ProtocolVersion = 2
+
+// Agent capabilities. Fields that are added to the rpc
+// payloads should only be sent to agents that declare the
+// corresponding capability.
+const (
+	// CapabilityStream indicates the agent can receive
+	// stages using the grpc stream transport.
+	CapabilityStream = "stream"
+
+	// CapabilityLabels indicates the agent can filter stages
+	// by label.
+	CapabilityLabels = "labels"
+
+	// CapabilityRegistries indicates the agent can receive
+	// registry credentials with the build details.
+	CapabilityRegistries = "registries"
+)
+
+// Handshake is exchanged between the agent and the server to
+// negotiate the protocol version and capabilities. The server
+// responds with the capabilities supported by both parties.
+type Handshake struct {
+	Protocol     int      `json:"protocol"`
+	Version      string   `json:"version"`
+	Machine      string   `json:"machine,omitempty"`
+	OS           string   `json:"os,omitempty"`
+	Arch         string   `json:"arch,omitempty"`
+	Variant      string   `json:"variant,omitempty"`
+	Kernel       string   `json:"kernel,omitempty"`
+	Capabilities []string `json:"capabilities"`
+
+	// Labels are the agent labels, used by the server to
+	// verify a pipeline can be served by a connected agent.
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Stream is the grpc stream server address, returned to
+	// agents with the stream capability.
+	Stream string `json:"stream,omitempty"`
+}
+
+// http headers used by the client to declare the protocol
+// version and negotiated capabilities with every request.
+const (
+	headerProtocol     = "X-Drone-Protocol"
+	headerCapabilities = "X-Drone-Capabilities"
+)
+
+// negotiate returns the capabilities supported by both the
+// agent and the server.
+func negotiate(agent, server []string) []string {
+	out := []string{}
+	for _, a := range agent {
+		for _, s := range server {
+			if a == s {
+				out = append(out, a)
+				break
+			}
+		}
+	}
+	return out
+}
+
+// protocol returns the protocol version declared by the
+// agent in the request header.
+func protocol(r *http.Request) int {
+	v, err := strconv.Atoi(r.Header.Get(headerProtocol))
+	if err != nil || v < 1 {
+		return 1
+	}
+	return v
+}
+
+// capable returns true if the agent declared the capability
+// in the request header.
+func capable(r *http.Request, capability string) bool {
+	for _, v := range strings.Split(r.Header.Get(headerCapabilities), ",") {
+		if strings.TrimSpace(v) == capability {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,60 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package rpc
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/drone/drone/core"
+)
+
+// registryTTL is the duration after which agents that have
+// not completed the handshake are removed from the registry.
+const registryTTL = time.Hour * 24
+
+// NewRegistry returns a new in-memory agent registry.
+func NewRegistry() core.AgentRegistry {
+	return &registry{agents: map[string]*core.Agent{}}
+}
+
+type registry struct {
+	sync.Mutex
+	agents map[string]*core.Agent
+}
+
+func (r *registry) Register(ctx context.Context, agent *core.Agent) error {
+	r.Lock()
+	defer r.Unlock()
+	now := time.Now().Unix()
+	agent.Created = now
+	agent.Updated = now
+	if prev, ok := r.agents[agent.Machine]; ok {
+		agent.Created = prev.Created
+	}
+	r.agents[agent.Machine] = agent
+	return nil
+}
+
+func (r *registry) List(ctx context.Context) ([]*core.Agent, error) {
+	r.Lock()
+	defer r.Unlock()
+	expired := time.Now().Add(-registryTTL).Unix()
+	out := []*core.Agent{}
+	for name, agent := range r.agents {
+		if agent.Updated < expired {
+			delete(r.agents, name)
+			continue
+		}
+		copy := *agent
+		out = append(out, &copy)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].Machine < out[j].Machine
+	})
+	return out, nil
+}
@@ -0,0 +1,44 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package rpc
+
+import (
+	"testing"
+
+	"github.com/drone/drone/core"
+)
+
+func TestRegistry(t *testing.T) {
+	r := NewRegistry()
+	r.Register(noContext, &core.Agent{Machine: "b", Version: "1.0.0"})
+	r.Register(noContext, &core.Agent{Machine: "a", Version: "1.0.0"})
+	r.Register(noContext, &core.Agent{Machine: "b", Version: "1.1.0"})
+
+	agents, err := r.List(noContext)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if got, want := len(agents), 2; got != want {
+		t.Errorf("Want %d agents, got %d", want, got)
+		return
+	}
+	if got, want := agents[0].Machine, "a"; got != want {
+		t.Errorf("Want agents sorted by machine, got %s", got)
+	}
+	if got, want := agents[1].Version, "1.1.0"; got != want {
+		t.Errorf("Want agent version %s, got %s", want, got)
+	}
+}
+
+func TestNegotiate(t *testing.T) {
+	got := negotiate(
+		[]string{CapabilityLabels, CapabilityStream, "unknown"},
+		[]string{CapabilityLabels},
+	)
+	if len(got) != 1 || got[0] != CapabilityLabels {
+		t.Errorf("Want negotiated capabilities [labels], got %v", got)
+	}
+}
@@ -7,13 +7,17 @@ package rpc
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
 	"net/http"
 	"strconv"
 	"time"
 
+	"github.com/drone/drone/core"
 	"github.com/drone/drone/operator/manager"
 	"github.com/drone/drone/store/shared/db"
+	"github.com/drone/drone/version"
 )
 
 // default http request timeout
@@ -21,22 +25,65 @@ var defaultTimeout = time.Second * 30
 
 var noContext = context.Background()
 
+var errCoverageMissing = errors.New("rpc: coverage report is missing")
+
+var errAnnotationsMissing = errors.New("rpc: annotations are missing")
+
+var errDeploymentMissing = errors.New("rpc: deployment is missing")
+
 // Server is an rpc handler that enables remote interaction
 // between the server and controller using the http transport.
 type Server struct {
-	manager manager.BuildManager
-	secret  string
+	manager     manager.BuildManager
+	agents      core.AgentRegistry
+	secret      string
+	stream      string
+	minProtocol int
+	requireCert bool
 }
 
 // NewServer returns a new rpc server that enables remote
 // interaction with the build controller using the http transport.
-func NewServer(manager manager.BuildManager, secret string) *Server {
+func NewServer(manager manager.BuildManager, agents core.AgentRegistry, secret string) *Server {
 	return &Server{
-		manager: manager,
-		secret:  secret,
+		manager:     manager,
+		agents:      agents,
+		secret:      secret,
+		minProtocol: 1,
+	}
+}
+
+// SetStream advertises the grpc stream server address to
+// agents with the stream capability.
+func (s *Server) SetStream(addr string) {
+	s.stream = addr
+}
+
+// SetMinProtocol sets the minimum protocol version. Requests
+// from agents that implement an older protocol version are
+// refused.
+func (s *Server) SetMinProtocol(version int) {
+	if version > 0 {
+		s.minProtocol = version
 	}
 }
 
+// SetRequireCert refuses requests from agents that do not
+// present a certificate signed by the certificate authority.
+// The shared secret is required regardless.
+func (s *Server) SetRequireCert(require bool) {
+	s.requireCert = require
+}
+
+// capabilities returns the capabilities supported by the server.
+func (s *Server) capabilities() []string {
+	caps := []string{CapabilityLabels, CapabilityRegistries}
+	if s.stream != "" {
+		caps = append(caps, CapabilityStream)
+	}
+	return caps
+}
+
 func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	if s.secret == "" {
 		w.WriteHeader(401) // not found
@@ -46,6 +93,19 @@ func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(401) // not authorized
 		return
 	}
+	if s.requireCert && peerFingerprint(r) == "" {
+		w.WriteHeader(401) // certificate required
+		return
+	}
+	if r.URL.Path == "/rpc/v1/handshake" {
+		s.handleHandshake(w, r)
+		return
+	}
+	if v := protocol(r); v < s.minProtocol {
+		w.WriteHeader(http.StatusUpgradeRequired)
+		fmt.Fprintf(w, "rpc: agent protocol version %d is not supported, minimum version %d", v, s.minProtocol)
+		return
+	}
 	switch r.URL.Path {
 	case "/rpc/v1/write":
 		s.handleWrite(w, r)
@@ -69,11 +129,59 @@ func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		s.handleWatch(w, r)
 	case "/rpc/v1/upload":
 		s.handleUpload(w, r)
+	case "/rpc/v1/artifact":
+		s.handleArtifact(w, r)
+	case "/rpc/v1/tests":
+		s.handleTests(w, r)
+	case "/rpc/v1/coverage":
+		s.handleCoverage(w, r)
+	case "/rpc/v1/annotations":
+		s.handleAnnotations(w, r)
+	case "/rpc/v1/deployment":
+		s.handleDeployment(w, r)
 	default:
 		w.WriteHeader(404)
 	}
 }
 
+func (s *Server) handleHandshake(w http.ResponseWriter, r *http.Request) {
+	in := &Handshake{}
+	err := json.NewDecoder(r.Body).Decode(in)
+	if err != nil {
+		writeBadRequest(w, err)
+		return
+	}
+	if in.Protocol < s.minProtocol {
+		w.WriteHeader(http.StatusUpgradeRequired)
+		fmt.Fprintf(w, "rpc: agent protocol version %d is not supported, minimum version %d", in.Protocol, s.minProtocol)
+		return
+	}
+	out := &Handshake{
+		Protocol:     ProtocolVersion,
+		Version:      version.Version.String(),
+		Capabilities: negotiate(in.Capabilities, s.capabilities()),
+	}
+	for _, capability := range out.Capabilities {
+		if capability == CapabilityStream {
+			out.Stream = s.stream
+		}
+	}
+	s.agents.Register(r.Context(), &core.Agent{
+		Machine:      in.Machine,
+		Version:      in.Version,
+		Protocol:     in.Protocol,
+		Capabilities: out.Capabilities,
+		OS:           in.OS,
+		Arch:         in.Arch,
+		Variant:      in.Variant,
+		Kernel:       in.Kernel,
+		Address:      r.RemoteAddr,
+		Fingerprint:  peerFingerprint(r),
+		Labels:       in.Labels,
+	})
+	json.NewEncoder(w).Encode(out)
+}
+
 func (s *Server) handleRequest(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
@@ -85,6 +193,11 @@ func (s *Server) handleRequest(w http.ResponseWriter, r *http.Request) {
 		writeBadRequest(w, err)
 		return
 	}
+	// label filtering is only applied to agents that
+	// negotiated the labels capability.
+	if in.Request != nil && !capable(r, CapabilityLabels) {
+		in.Request.Labels = nil
+	}
 	stage, err := s.manager.Request(ctx, in.Request)
 	if err != nil {
 		writeError(w, err)
@@ -101,12 +214,12 @@ func (s *Server) handleAccept(w http.ResponseWriter, r *http.Request) {
 		writeBadRequest(w, err)
 		return
 	}
-	err = s.manager.Accept(ctx, in.Stage, in.Machine)
+	stage, err := s.manager.Accept(ctx, in.Stage, in.Machine)
 	if err != nil {
 		writeError(w, err)
 		return
 	}
-	w.WriteHeader(http.StatusNoContent)
+	json.NewEncoder(w).Encode(stage)
 }
 
 func (s *Server) handleNetrc(w http.ResponseWriter, r *http.Request) {
@@ -138,6 +251,11 @@ func (s *Server) handleDetails(w http.ResponseWriter, r *http.Request) {
 		writeError(w, err)
 		return
 	}
+	// registry credentials are not sent to agents that
+	// cannot use them.
+	if !capable(r, CapabilityRegistries) {
+		build.Registries = nil
+	}
 	out := &buildContextToken{
 		Secret:  build.Repo.Secret,
 		Context: build,
@@ -244,6 +362,99 @@ func (s *Server) handleUpload(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusNoContent)
 }
 
+func (s *Server) handleArtifact(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	in := r.FormValue("id")
+	id, err := strconv.ParseInt(in, 10, 64)
+	if err != nil {
+		writeBadRequest(w, err)
+		return
+	}
+	name := r.FormValue("name")
+	err = s.manager.UploadArtifact(ctx, id, name, r.Body)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handleTests(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	in := r.FormValue("id")
+	id, err := strconv.ParseInt(in, 10, 64)
+	if err != nil {
+		writeBadRequest(w, err)
+		return
+	}
+	err = s.manager.UploadTests(ctx, id, r.Body)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handleCoverage(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	in := &coverageRequest{}
+	err := json.NewDecoder(r.Body).Decode(in)
+	if err != nil {
+		writeBadRequest(w, err)
+		return
+	}
+	if in.Coverage == nil {
+		writeBadRequest(w, errCoverageMissing)
+		return
+	}
+	err = s.manager.UploadCoverage(ctx, in.Step, in.Coverage)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handleAnnotations(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	in := &annotationsRequest{}
+	err := json.NewDecoder(r.Body).Decode(in)
+	if err != nil {
+		writeBadRequest(w, err)
+		return
+	}
+	if len(in.Annotations) == 0 {
+		writeBadRequest(w, errAnnotationsMissing)
+		return
+	}
+	err = s.manager.UploadAnnotations(ctx, in.Step, in.Annotations)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handleDeployment(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	in := &deploymentRequest{}
+	err := json.NewDecoder(r.Body).Decode(in)
+	if err != nil {
+		writeBadRequest(w, err)
+		return
+	}
+	if in.Deployment == nil {
+		writeBadRequest(w, errDeploymentMissing)
+		return
+	}
+	err = s.manager.UploadDeployment(ctx, in.Step, in.Deployment)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
 func (s *Server) handleWatch(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
@@ -0,0 +1,95 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package rpc
+
+import (
+	"encoding/json"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/operator/manager"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/encoding"
+)
+
+// stream message kinds.
+const (
+	// sent by the agent.
+	kindRequest = "request" // request the next stage
+	kindAccept  = "accept"  // accept the stage
+	kindWatch   = "watch"   // watch the build for cancellation
+	kindUnwatch = "unwatch" // stop watching the build
+
+	// sent by the server.
+	kindStage    = "stage"    // stage offered to the agent
+	kindAccepted = "accepted" // result of the accept request
+	kindCancel   = "cancel"   // build cancelled
+	kindConfig   = "config"   // configuration updated
+)
+
+// codecName is the name of the json codec used to encode
+// stream messages.
+const codecName = "json"
+
+// streamMethod is the full name of the stream method.
+const streamMethod = "/drone.Manager/Connect"
+
+// streamMessage is a message sent between the server and
+// agent over the bidirectional stream.
+type streamMessage struct {
+	Kind    string            `json:"kind"`
+	Machine string            `json:"machine,omitempty"`
+	Request *manager.Request  `json:"request,omitempty"`
+	Stage   *core.Stage       `json:"stage,omitempty"`
+	ID      int64             `json:"id,omitempty"`
+	Config  map[string]string `json:"config,omitempty"`
+	Error   string            `json:"error,omitempty"`
+}
+
+// streamService is the interface implemented by the stream
+// server, used by grpc to verify the service registration.
+type streamService interface {
+	connect(grpc.ServerStream) error
+}
+
+// streamDesc describes the grpc service. The service is
+// defined manually and messages are encoded with json, which
+// is consistent with the http transport and does not require
+// generated protobuf code.
+var streamDesc = grpc.ServiceDesc{
+	ServiceName: "drone.Manager",
+	HandlerType: (*streamService)(nil),
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Connect",
+			Handler:       connectHandler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+}
+
+func connectHandler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(streamService).connect(stream)
+}
+
+func init() {
+	encoding.RegisterCodec(jsonCodec{})
+}
+
+// jsonCodec is a grpc codec that encodes messages as json.
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (jsonCodec) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+func (jsonCodec) Name() string {
+	return codecName
+}
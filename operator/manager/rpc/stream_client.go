@@ -0,0 +1,297 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package rpc
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/operator/manager"
+	"github.com/drone/drone/store/shared/db"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+)
+
+var _ manager.BuildManager = (*StreamClient)(nil)
+
+// StreamClient is an rpc client that receives stages, build
+// cancellations and configuration updates from the server
+// using a bidirectional grpc stream. All other operations use
+// the http transport.
+type StreamClient struct {
+	*Client
+
+	addr  string
+	token string
+	opts  []grpc.DialOption
+
+	mu       sync.Mutex
+	conn     *grpc.ClientConn
+	session  *streamSession
+	watches  map[int64]chan struct{}
+	onConfig func(map[string]string)
+
+	acceptMu sync.Mutex
+	stages   chan *streamMessage
+	accepts  chan *streamMessage
+}
+
+// streamSession is an open stream to the server.
+type streamSession struct {
+	stream grpc.ClientStream
+	cancel context.CancelFunc
+	sendMu sync.Mutex
+	done   chan struct{}
+	err    error
+}
+
+// NewStreamClient returns a new rpc client that connects to
+// the stream server at the grpc address, and to the http rpc
+// server for all other operations.
+func NewStreamClient(server, addr, token string) *StreamClient {
+	return &StreamClient{
+		Client:  NewClient(server, token),
+		addr:    addr,
+		token:   token,
+		opts:    []grpc.DialOption{grpc.WithInsecure()},
+		watches: map[int64]chan struct{}{},
+		stages:  make(chan *streamMessage, 1),
+		accepts: make(chan *streamMessage, 1),
+	}
+}
+
+// SetTLS configures the client to present the agent
+// certificate when connecting to the server.
+func (s *StreamClient) SetTLS(config *tls.Config) {
+	s.Client.SetTLS(config)
+	s.opts = []grpc.DialOption{
+		grpc.WithTransportCredentials(credentials.NewTLS(config)),
+	}
+}
+
+// OnConfig registers a function that is invoked when the
+// server pushes a configuration update.
+func (s *StreamClient) OnConfig(fn func(map[string]string)) {
+	s.mu.Lock()
+	s.onConfig = fn
+	s.mu.Unlock()
+}
+
+// Request requests the next available build stage for execution.
+// The server pushes the stage to the agent when available.
+func (s *StreamClient) Request(ctx context.Context, args *manager.Request) (*core.Stage, error) {
+	session, err := s.open()
+	if err != nil {
+		return nil, err
+	}
+	err = session.send(&streamMessage{Kind: kindRequest, Request: args})
+	if err != nil {
+		return nil, err
+	}
+	select {
+	case msg := <-s.stages:
+		if msg.Error != "" {
+			return nil, errors.New(msg.Error)
+		}
+		return msg.Stage, nil
+	case <-session.done:
+		return nil, session.err
+	case <-ctx.Done():
+		// consistent with the http transport, the request
+		// timing out is expected and is not an error.
+		return nil, nil
+	}
+}
+
+// Accept accepts the build stage for execution.
+func (s *StreamClient) Accept(ctx context.Context, stage int64, machine string) (*core.Stage, error) {
+	s.acceptMu.Lock()
+	defer s.acceptMu.Unlock()
+
+	session, err := s.open()
+	if err != nil {
+		return nil, err
+	}
+	err = session.send(&streamMessage{Kind: kindAccept, ID: stage, Machine: machine})
+	if err != nil {
+		return nil, err
+	}
+	for {
+		select {
+		case msg := <-s.accepts:
+			if msg.ID != stage {
+				continue // reply to an abandoned accept
+			}
+			switch msg.Error {
+			case "":
+				return msg.Stage, nil
+			case db.ErrOptimisticLock.Error():
+				return nil, db.ErrOptimisticLock
+			default:
+				return nil, errors.New(msg.Error)
+			}
+		case <-session.done:
+			return nil, session.err
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// Watch watches for build cancellation requests. The server
+// pushes the cancellation to the agent. The build is watched
+// until it is cancelled or the context is done, and is watched
+// again if the agent reconnects.
+func (s *StreamClient) Watch(ctx context.Context, build int64) (bool, error) {
+	cancelled := make(chan struct{})
+	s.mu.Lock()
+	s.watches[build] = cancelled
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		delete(s.watches, build)
+		session := s.session
+		s.mu.Unlock()
+		if session != nil {
+			session.send(&streamMessage{Kind: kindUnwatch, ID: build})
+		}
+	}()
+
+	for {
+		session, err := s.open()
+		if err == nil {
+			err = session.send(&streamMessage{Kind: kindWatch, ID: build})
+		}
+		if err != nil {
+			select {
+			case <-ctx.Done():
+				return false, nil
+			case <-time.After(time.Second):
+				continue
+			}
+		}
+		select {
+		case <-cancelled:
+			return true, nil
+		case <-ctx.Done():
+			return false, nil
+		case <-session.done:
+			// the stream is broken and the build is watched
+			// again when the agent reconnects.
+		}
+	}
+}
+
+// Close closes the grpc connection.
+func (s *StreamClient) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.session != nil {
+		s.session.cancel()
+		s.session = nil
+	}
+	if s.conn != nil {
+		err := s.conn.Close()
+		s.conn = nil
+		return err
+	}
+	return nil
+}
+
+// open returns the open stream to the server, opening a new
+// stream if the stream is not open or is broken.
+func (s *StreamClient) open() (*streamSession, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.session != nil {
+		select {
+		case <-s.session.done:
+		default:
+			return s.session, nil
+		}
+	}
+	if s.conn == nil {
+		conn, err := grpc.Dial(s.addr, s.opts...)
+		if err != nil {
+			return nil, err
+		}
+		s.conn = conn
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	ctx = metadata.AppendToOutgoingContext(ctx, "x-drone-token", s.token)
+	stream, err := s.conn.NewStream(ctx, &streamDesc.Streams[0], streamMethod,
+		grpc.CallContentSubtype(codecName),
+	)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	s.session = &streamSession{
+		stream: stream,
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+	go s.receive(s.session)
+	return s.session, nil
+}
+
+// receive receives messages from the server until the
+// stream is broken.
+func (s *StreamClient) receive(session *streamSession) {
+	for {
+		msg := new(streamMessage)
+		err := session.stream.RecvMsg(msg)
+		if err != nil {
+			session.err = err
+			session.cancel()
+			close(session.done)
+			return
+		}
+		switch msg.Kind {
+		case kindStage:
+			select {
+			case s.stages <- msg:
+			default:
+				// the stage is discarded if the agent is no
+				// longer waiting, and remains in the queue.
+			}
+		case kindAccepted:
+			select {
+			case s.accepts <- msg:
+			default:
+			}
+		case kindCancel:
+			s.mu.Lock()
+			if cancelled, ok := s.watches[msg.ID]; ok {
+				close(cancelled)
+				delete(s.watches, msg.ID)
+			}
+			s.mu.Unlock()
+		case kindConfig:
+			s.mu.Lock()
+			fn := s.onConfig
+			s.mu.Unlock()
+			if fn != nil {
+				fn(msg.Config)
+			}
+		}
+	}
+}
+
+// send sends the message to the server. Messages are sent
+// sequentially since the stream does not support concurrent
+// writes.
+func (s *streamSession) send(msg *streamMessage) error {
+	s.sendMu.Lock()
+	defer s.sendMu.Unlock()
+	return s.stream.SendMsg(msg)
+}
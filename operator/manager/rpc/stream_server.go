@@ -0,0 +1,336 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package rpc
+
+import (
+	"context"
+	"crypto/tls"
+	"io"
+	"net"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/drone/drone/operator/manager"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// Connection provides the state of an agent connected to
+// the stream server.
+type Connection struct {
+	Machine   string `json:"machine"`
+	Address   string `json:"address"`
+	Connected int64  `json:"connected"`
+	Updated   int64  `json:"updated"`
+}
+
+// StreamServer is an rpc server that pushes stages, build
+// cancellations and configuration updates to connected agents
+// using a bidirectional grpc stream, replacing the http long
+// polling request loop.
+type StreamServer struct {
+	manager manager.BuildManager
+	secret  string
+
+	tls         *tls.Config
+	requireCert bool
+
+	mu    sync.Mutex
+	conns map[*streamConn]struct{}
+}
+
+// NewStreamServer returns a new rpc stream server.
+func NewStreamServer(manager manager.BuildManager, secret string) *StreamServer {
+	return &StreamServer{
+		manager: manager,
+		secret:  secret,
+		conns:   map[*streamConn]struct{}{},
+	}
+}
+
+// SetTLS configures the server to accept tls connections
+// using the tls configuration. If require is true, agents
+// must present a certificate signed by the certificate
+// authority.
+func (s *StreamServer) SetTLS(config *tls.Config, require bool) {
+	s.tls = config
+	s.requireCert = require
+}
+
+// ListenAndServe accepts agent connections on the tcp network
+// address until the context is cancelled.
+func (s *StreamServer) ListenAndServe(ctx context.Context, addr string) error {
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	return s.Serve(ctx, lis)
+}
+
+// Serve accepts agent connections on the listener until the
+// context is cancelled.
+func (s *StreamServer) Serve(ctx context.Context, lis net.Listener) error {
+	var opts []grpc.ServerOption
+	if s.tls != nil {
+		opts = append(opts, grpc.Creds(credentials.NewTLS(s.tls)))
+	}
+	srv := grpc.NewServer(opts...)
+	srv.RegisterService(&streamDesc, s)
+	go func() {
+		<-ctx.Done()
+		// streams are long-lived and are therefore closed
+		// immediately instead of gracefully.
+		srv.Stop()
+	}()
+	err := srv.Serve(lis)
+	if ctx.Err() != nil {
+		return nil
+	}
+	return err
+}
+
+// Connections returns the state of the connected agents.
+func (s *StreamServer) Connections() []*Connection {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var out []*Connection
+	for conn := range s.conns {
+		out = append(out, conn.state())
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].Connected < out[j].Connected
+	})
+	return out
+}
+
+// Broadcast pushes the configuration update to all connected
+// agents.
+func (s *StreamServer) Broadcast(config map[string]string) {
+	s.mu.Lock()
+	var conns []*streamConn
+	for conn := range s.conns {
+		conns = append(conns, conn)
+	}
+	s.mu.Unlock()
+	for _, conn := range conns {
+		conn.send(&streamMessage{Kind: kindConfig, Config: config})
+	}
+}
+
+func (s *StreamServer) connect(stream grpc.ServerStream) error {
+	ctx := stream.Context()
+	if !s.authorize(ctx) {
+		return status.Error(codes.Unauthenticated, "rpc: invalid token")
+	}
+
+	conn := newStreamConn(stream)
+	s.mu.Lock()
+	s.conns[conn] = struct{}{}
+	s.mu.Unlock()
+
+	logger := logrus.WithField("address", conn.address)
+	logger.Debugln("rpc: agent connected")
+
+	defer func() {
+		conn.close()
+		s.mu.Lock()
+		delete(s.conns, conn)
+		s.mu.Unlock()
+		logger.Debugln("rpc: agent disconnected")
+	}()
+
+	for {
+		in := new(streamMessage)
+		err := stream.RecvMsg(in)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		conn.touch(in.Machine)
+
+		switch in.Kind {
+		case kindRequest:
+			go s.handleRequest(conn, in)
+		case kindAccept:
+			go s.handleAccept(conn, in)
+		case kindWatch:
+			conn.watch(in.ID, func(ctx context.Context) {
+				s.handleWatch(ctx, conn, in.ID)
+			})
+		case kindUnwatch:
+			conn.unwatch(in.ID)
+		}
+	}
+}
+
+// handleRequest requests the next stage for the agent and
+// pushes the stage to the agent when available. A message
+// without a stage is sent if the queue is stopped.
+func (s *StreamServer) handleRequest(conn *streamConn, in *streamMessage) {
+	if in.Request == nil {
+		in.Request = &manager.Request{}
+	}
+	stage, err := s.manager.Request(conn.ctx, in.Request)
+	if conn.ctx.Err() != nil {
+		return
+	}
+	out := &streamMessage{Kind: kindStage, Stage: stage}
+	if err != nil {
+		out.Error = err.Error()
+	}
+	conn.send(out)
+}
+
+func (s *StreamServer) handleAccept(conn *streamConn, in *streamMessage) {
+	stage, err := s.manager.Accept(conn.ctx, in.ID, in.Machine)
+	out := &streamMessage{Kind: kindAccepted, ID: in.ID, Stage: stage}
+	if err != nil {
+		out.Error = err.Error()
+	}
+	conn.send(out)
+}
+
+// handleWatch pushes a cancel message to the agent if the
+// build is cancelled before the agent stops watching the build.
+func (s *StreamServer) handleWatch(ctx context.Context, conn *streamConn, build int64) {
+	for ctx.Err() == nil {
+		done, _ := s.manager.Watch(ctx, build)
+		if done {
+			conn.send(&streamMessage{Kind: kindCancel, ID: build})
+			return
+		}
+		// the watch may return before the build is cancelled
+		// if the scheduler returns an error, in which case we
+		// wait before watching the build again.
+		select {
+		case <-ctx.Done():
+		case <-time.After(time.Second):
+		}
+	}
+}
+
+func (s *StreamServer) authorize(ctx context.Context) bool {
+	if s.secret == "" {
+		return false
+	}
+	if s.requireCert && !verified(ctx) {
+		return false
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	tokens := md.Get("x-drone-token")
+	return len(tokens) != 0 && tokens[0] == s.secret
+}
+
+// verified returns true if the agent presented a certificate
+// signed by the certificate authority.
+func verified(ctx context.Context) bool {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return false
+	}
+	info, ok := p.AuthInfo.(credentials.TLSInfo)
+	return ok && len(info.State.VerifiedChains) != 0
+}
+
+// streamConn is an agent connected to the stream server.
+type streamConn struct {
+	stream  grpc.ServerStream
+	ctx     context.Context
+	cancel  context.CancelFunc
+	address string
+	sendMu  sync.Mutex
+
+	mu        sync.Mutex
+	machine   string
+	connected int64
+	updated   int64
+	watches   map[int64]context.CancelFunc
+}
+
+func newStreamConn(stream grpc.ServerStream) *streamConn {
+	ctx, cancel := context.WithCancel(stream.Context())
+	now := time.Now().Unix()
+	conn := &streamConn{
+		stream:    stream,
+		ctx:       ctx,
+		cancel:    cancel,
+		connected: now,
+		updated:   now,
+		watches:   map[int64]context.CancelFunc{},
+	}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		conn.address = p.Addr.String()
+	}
+	return conn
+}
+
+// send sends the message to the agent. Messages are sent
+// sequentially since the stream does not support concurrent
+// writes. Errors are ignored since a broken stream is also
+// reported to, and handled by, the receive loop.
+func (c *streamConn) send(msg *streamMessage) {
+	c.sendMu.Lock()
+	defer c.sendMu.Unlock()
+	c.stream.SendMsg(msg)
+}
+
+// touch updates the connection state when a message is
+// received from the agent.
+func (c *streamConn) touch(machine string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if machine != "" {
+		c.machine = machine
+	}
+	c.updated = time.Now().Unix()
+}
+
+func (c *streamConn) state() *Connection {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return &Connection{
+		Machine:   c.machine,
+		Address:   c.address,
+		Connected: c.connected,
+		Updated:   c.updated,
+	}
+}
+
+func (c *streamConn) watch(build int64, fn func(context.Context)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.watches[build]; ok {
+		return
+	}
+	ctx, cancel := context.WithCancel(c.ctx)
+	c.watches[build] = cancel
+	go func() {
+		fn(ctx)
+		c.unwatch(build)
+	}()
+}
+
+func (c *streamConn) unwatch(build int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if cancel, ok := c.watches[build]; ok {
+		cancel()
+		delete(c.watches, build)
+	}
+}
+
+// close cancels the pending requests and watches.
+func (c *streamConn) close() {
+	c.cancel()
+}
@@ -0,0 +1,137 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package rpc
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/operator/manager"
+	"github.com/drone/drone/store/shared/db"
+)
+
+// streamManager is a build manager that implements the
+// operations used by the stream server.
+type streamManager struct {
+	manager.BuildManager
+
+	stage     *core.Stage
+	accept    error
+	cancelled chan struct{}
+}
+
+func (m *streamManager) Request(ctx context.Context, args *manager.Request) (*core.Stage, error) {
+	return m.stage, nil
+}
+
+func (m *streamManager) Accept(ctx context.Context, stage int64, machine string) (*core.Stage, error) {
+	if m.accept != nil {
+		return nil, m.accept
+	}
+	return m.stage, nil
+}
+
+func (m *streamManager) Watch(ctx context.Context, build int64) (bool, error) {
+	select {
+	case <-m.cancelled:
+		return true, nil
+	case <-ctx.Done():
+		return false, ctx.Err()
+	}
+}
+
+func serveStream(t *testing.T, m manager.BuildManager) (*StreamServer, *StreamClient, func()) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	server := NewStreamServer(m, "correct-horse-battery-staple")
+	go server.Serve(ctx, lis)
+
+	client := NewStreamClient("http://drone.company.com", lis.Addr().String(), "correct-horse-battery-staple")
+	return server, client, func() {
+		client.Close()
+		cancel()
+	}
+}
+
+func TestStream_Request(t *testing.T) {
+	m := &streamManager{stage: &core.Stage{ID: 1, BuildID: 2}}
+	server, client, stop := serveStream(t, m)
+	defer stop()
+
+	stage, err := client.Request(context.Background(), &manager.Request{OS: "linux", Arch: "amd64"})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if stage == nil || stage.ID != 1 {
+		t.Errorf("Expect stage pushed to the agent")
+	}
+	if got, want := len(server.Connections()), 1; got != want {
+		t.Errorf("Want %d connections, got %d", want, got)
+	}
+}
+
+func TestStream_AcceptConflict(t *testing.T) {
+	m := &streamManager{accept: db.ErrOptimisticLock}
+	_, client, stop := serveStream(t, m)
+	defer stop()
+
+	_, err := client.Accept(context.Background(), 1, "localhost")
+	if err != db.ErrOptimisticLock {
+		t.Errorf("Expect optimistic lock error, got %v", err)
+	}
+}
+
+func TestStream_AcceptNext(t *testing.T) {
+	m := &streamManager{stage: &core.Stage{ID: 2, BuildID: 3}}
+	_, client, stop := serveStream(t, m)
+	defer stop()
+
+	stage, err := client.Accept(context.Background(), 1, "localhost")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if stage == nil || stage.ID != 2 {
+		t.Errorf("Expect the accepted stage returned to the agent")
+	}
+}
+
+func TestStream_Watch(t *testing.T) {
+	m := &streamManager{cancelled: make(chan struct{})}
+	_, client, stop := serveStream(t, m)
+	defer stop()
+
+	close(m.cancelled)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	done, err := client.Watch(ctx, 2)
+	if err != nil {
+		t.Error(err)
+	}
+	if !done {
+		t.Errorf("Expect cancellation pushed to the agent")
+	}
+}
+
+func TestStream_Unauthorized(t *testing.T) {
+	m := &streamManager{stage: &core.Stage{ID: 1}}
+	_, client, stop := serveStream(t, m)
+	defer stop()
+
+	client.token = "invalid-token"
+	_, err := client.Request(context.Background(), &manager.Request{})
+	if err == nil {
+		t.Errorf("Expect unauthorized error")
+	}
+}
@@ -41,6 +41,21 @@ type writeRequest struct {
 	Line *core.Line
 }
 
+type coverageRequest struct {
+	Step     int64
+	Coverage *core.Coverage
+}
+
+type annotationsRequest struct {
+	Step        int64
+	Annotations []*core.Annotation
+}
+
+type deploymentRequest struct {
+	Step       int64
+	Deployment *core.Deployment
+}
+
 type watchRequest struct {
 	Build int64
 }
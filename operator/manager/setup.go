@@ -111,14 +111,14 @@ func (s *setup) do(ctx context.Context, stage *core.Stage) error {
 		logger.Warnln("manager: cannot publish build event")
 	}
 
-	if updated {
-		user, err := s.Users.Find(noContext, repo.UserID)
-		if err != nil {
-			logger.WithError(err).
-				Warnln("manager: cannot find repository owner")
-			return err
-		}
+	user, err := s.Users.Find(noContext, repo.UserID)
+	if err != nil {
+		logger.WithError(err).
+			Warnln("manager: cannot find repository owner")
+		return err
+	}
 
+	if updated {
 		req := &core.StatusInput{
 			Repo:  repo,
 			Build: build,
@@ -130,6 +130,17 @@ func (s *setup) do(ctx context.Context, stage *core.Stage) error {
 		}
 	}
 
+	req := &core.StatusInput{
+		Repo:  repo,
+		Build: build,
+		Stage: stage,
+	}
+	err = s.Status.Send(noContext, user, req)
+	if err != nil {
+		logger.WithError(err).
+			Warnln("manager: cannot publish stage status")
+	}
+
 	return nil
 }
 
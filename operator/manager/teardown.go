@@ -7,6 +7,7 @@ package manager
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"time"
 
 	"github.com/drone/drone/store/shared/db"
@@ -17,16 +18,26 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
+// maxAttempts is the maximum number of times a stage that
+// fails to start is dispatched to an agent, before the stage
+// is marked as errored.
+const maxAttempts = 3
+
 type teardown struct {
-	Builds    core.BuildStore
-	Events    core.Pubsub
-	Logs      core.LogStream
-	Scheduler core.Scheduler
-	Repos     core.RepositoryStore
-	Steps     core.StepStore
-	Status    core.StatusService
-	Stages    core.StageStore
-	Users     core.UserStore
+	Builds      core.BuildStore
+	Deployments core.DeploymentStore
+	Events      core.Pubsub
+	Insights    core.InsightStore
+	Logs        core.LogStream
+	Notify      core.NotificationService
+	Provenance  core.ProvenanceService
+	Scheduler   core.Scheduler
+	Repos       core.RepositoryStore
+	Steps       core.StepStore
+	Status      core.StatusService
+	Stages      core.StageStore
+	Summary     core.SummaryService
+	Users       core.UserStore
 }
 
 func (t *teardown) do(ctx context.Context, stage *core.Stage) error {
@@ -53,6 +64,19 @@ func (t *teardown) do(ctx context.Context, stage *core.Stage) error {
 		return err
 	}
 
+	// a stage abandoned by the agent, for example because the
+	// agent cannot create the pipeline environment or terminates
+	// unexpectedly, is returned to the queue until the maximum
+	// number of attempts is reached.
+	if isStartError(stage) {
+		if stage.Attempts < maxAttempts {
+			return t.requeue(logger, stage)
+		}
+		logger.WithField("stage.attempts", stage.Attempts).
+			Warnln("manager: stage failed to start")
+		stage.Error = fmt.Sprintf("Stage failed to start after %d attempts: %s", stage.Attempts, stage.Error)
+	}
+
 	for _, step := range stage.Steps {
 		err := t.Steps.Update(noContext, step)
 		if err != nil {
@@ -92,6 +116,18 @@ func (t *teardown) do(ctx context.Context, stage *core.Stage) error {
 		return err
 	}
 
+	// signal the scheduler to dispatch pending stages without
+	// waiting for the polling interval. Stages blocked by
+	// concurrency limits may be eligible now this stage is
+	// complete.
+	err = t.Scheduler.Signal(noContext)
+	if err != nil {
+		logger.WithError(err).
+			Warnln("manager: cannot signal the scheduler")
+	}
+
+	t.sendStageStatus(logger, repo, build, stage)
+
 	if isBuildComplete(stages) == false {
 		logger.Debugln("manager: build pending completion of additional stages")
 		return nil
@@ -99,22 +135,8 @@ func (t *teardown) do(ctx context.Context, stage *core.Stage) error {
 
 	logger.Debugln("manager: build is finished, teardown")
 
-	build.Status = core.StatusPassing
+	build.Status = buildStatus(stages)
 	build.Finished = time.Now().Unix()
-	for _, sibling := range stages {
-		if sibling.Status == core.StatusKilled {
-			build.Status = core.StatusKilled
-			break
-		}
-		if sibling.Status == core.StatusFailing {
-			build.Status = core.StatusFailing
-			break
-		}
-		if sibling.Status == core.StatusError {
-			build.Status = core.StatusError
-			break
-		}
-	}
 
 	err = t.Builds.Update(noContext, build)
 	if err == db.ErrOptimisticLock {
@@ -128,6 +150,31 @@ func (t *teardown) do(ctx context.Context, stage *core.Stage) error {
 		return err
 	}
 
+	err = t.Insights.Record(noContext, build, stages)
+	if err != nil {
+		logger.WithError(err).
+			Warnln("manager: cannot record build insights")
+	}
+
+	// promotion and rollback builds are recorded in the
+	// deployment history of the target environment.
+	if build.Deploy != "" {
+		err = t.Deployments.Create(noContext, &core.Deployment{
+			RepoID:      build.RepoID,
+			BuildID:     build.ID,
+			Number:      build.Number,
+			Environment: build.Deploy,
+			Commit:      build.After,
+			Ref:         build.Ref,
+			Status:      build.Status,
+			Deployer:    build.Trigger,
+		})
+		if err != nil {
+			logger.WithError(err).
+				Warnln("manager: cannot record deployment")
+		}
+	}
+
 	// err = t.Watcher.Complete(noContext, build.ID)
 	// if err != nil {
 	// 	logger.WithError(err).
@@ -147,6 +194,31 @@ func (t *teardown) do(ctx context.Context, stage *core.Stage) error {
 			Warnln("manager: cannot publish build event")
 	}
 
+	err = t.Notify.Notify(noContext, &core.NotifyArgs{
+		Repo:   repo,
+		Build:  build,
+		Stages: stages,
+	})
+	if err != nil {
+		logger.WithError(err).
+			Warnln("manager: cannot send build notifications")
+	}
+
+	// the provenance attestation is only published for
+	// passing builds, since failed builds do not produce
+	// artifacts that should be trusted.
+	if t.Provenance != nil && build.Status == core.StatusPassing {
+		err = t.Provenance.Publish(noContext, &core.ProvenanceArgs{
+			Repo:   repo,
+			Build:  build,
+			Stages: stages,
+		})
+		if err != nil {
+			logger.WithError(err).
+				Warnln("manager: cannot publish build provenance")
+		}
+	}
+
 	user, err := t.Users.Find(noContext, repo.UserID)
 	if err != nil {
 		logger.WithError(err).
@@ -163,9 +235,46 @@ func (t *teardown) do(ctx context.Context, stage *core.Stage) error {
 		logger.WithError(err).
 			Warnln("manager: cannot publish status")
 	}
+
+	err = t.Summary.Send(noContext, user, &core.NotifyArgs{
+		Repo:   repo,
+		Build:  build,
+		Stages: stages,
+	})
+	if err != nil {
+		logger.WithError(err).
+			Warnln("manager: cannot publish build summary")
+	}
 	return nil
 }
 
+// sendStageStatus is a helper function that publishes the
+// status of the completed stage. Failures are logged and
+// do not interrupt teardown.
+func (t *teardown) sendStageStatus(
+	logger logrus.FieldLogger,
+	repo *core.Repository,
+	build *core.Build,
+	stage *core.Stage,
+) {
+	user, err := t.Users.Find(noContext, repo.UserID)
+	if err != nil {
+		logger.WithError(err).
+			Warnln("manager: cannot find repository owner")
+		return
+	}
+	req := &core.StatusInput{
+		Repo:  repo,
+		Build: build,
+		Stage: stage,
+	}
+	err = t.Status.Send(noContext, user, req)
+	if err != nil && err != scm.ErrNotSupported {
+		logger.WithError(err).
+			Warnln("manager: cannot publish stage status")
+	}
+}
+
 // cancelDownstream is a helper function that tests for
 // downstream stages and cancels them based on the overall
 // pipeline state.
@@ -173,12 +282,7 @@ func (t *teardown) cancelDownstream(
 	ctx context.Context,
 	stages []*core.Stage,
 ) error {
-	failed := false
-	for _, s := range stages {
-		if s.IsFailed() {
-			failed = true
-		}
-	}
+	failed := isFailed(stages)
 
 	var errs error
 	for _, s := range stages {
@@ -216,6 +320,45 @@ func (t *teardown) cancelDownstream(
 	return errs
 }
 
+// requeue is a helper function that resets a stage that
+// failed to start, and returns the stage to the queue so it
+// can be dispatched to another agent.
+func (t *teardown) requeue(logger logrus.FieldLogger, stage *core.Stage) error {
+	logger = logger.WithField("stage.attempts", stage.Attempts)
+	logger.WithField("stage.error", stage.Error).
+		Infoln("manager: stage failed to start, requeue")
+
+	for _, step := range stage.Steps {
+		step.Status = core.StatusPending
+		step.Error = ""
+		step.Stopped = 0
+		err := t.Steps.Update(noContext, step)
+		if err != nil {
+			logger.WithError(err).
+				WithField("step.id", step.ID).
+				Warnln("manager: cannot reset the step")
+			return err
+		}
+	}
+
+	stage.Status = core.StatusPending
+	stage.Error = ""
+	stage.ExitCode = 0
+	stage.Abandoned = false
+	stage.Machine = ""
+	stage.Dispatched = 0
+	stage.Accepted = 0
+	stage.Stopped = 0
+	stage.Updated = time.Now().Unix()
+	err := t.Stages.Update(noContext, stage)
+	if err != nil {
+		logger.WithError(err).
+			Warnln("manager: cannot requeue the stage")
+		return err
+	}
+	return t.Scheduler.Schedule(noContext, stage)
+}
+
 // scheduleDownstream is a helper function that tests for
 // downstream stages and schedules stages if all dependencies
 // and execution requirements are met.
@@ -251,6 +394,7 @@ func (t *teardown) scheduleDownstream(
 			logger.Debugln("manager: schedule next stage")
 
 			sibling.Status = core.StatusPending
+			sibling.Queued = time.Now().Unix()
 			sibling.Updated = time.Now().Unix()
 			err := t.Stages.Update(noContext, sibling)
 			if err != nil {
@@ -3,3 +3,69 @@
 // that can be found in the LICENSE file.
 
 package manager
+
+import (
+	"testing"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/mock"
+
+	"github.com/golang/mock/gomock"
+)
+
+// this test verifies that a stage abandoned by the agent is
+// returned to the queue.
+func TestTeardown_Requeue(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	stage := &core.Stage{
+		ID:       3,
+		BuildID:  2,
+		Status:    core.StatusError,
+		Error:     "cannot create network",
+		Machine:   "agent1",
+		Attempts:  1,
+		Accepted:  1,
+		Stopped:   2,
+		Abandoned: true,
+		Steps: []*core.Step{
+			{ID: 4, Status: core.StatusSkipped},
+		},
+	}
+
+	builds := mock.NewMockBuildStore(controller)
+	builds.EXPECT().Find(gomock.Any(), stage.BuildID).Return(&core.Build{ID: 2, RepoID: 1}, nil)
+
+	repos := mock.NewMockRepositoryStore(controller)
+	repos.EXPECT().Find(gomock.Any(), int64(1)).Return(&core.Repository{ID: 1}, nil)
+
+	steps := mock.NewMockStepStore(controller)
+	steps.EXPECT().Update(gomock.Any(), stage.Steps[0]).Return(nil)
+
+	stages := mock.NewMockStageStore(controller)
+	stages.EXPECT().Update(gomock.Any(), stage).Return(nil)
+
+	scheduler := mock.NewMockScheduler(controller)
+	scheduler.EXPECT().Schedule(gomock.Any(), stage).Return(nil)
+
+	td := &teardown{
+		Builds:    builds,
+		Repos:     repos,
+		Scheduler: scheduler,
+		Stages:    stages,
+		Steps:     steps,
+	}
+	if err := td.do(noContext, stage); err != nil {
+		t.Error(err)
+	}
+	if got, want := stage.Status, core.StatusPending; got != want {
+		t.Errorf("Want stage status %s, got %s", want, got)
+	}
+	if stage.Machine != "" || stage.Error != "" {
+		t.Errorf("Want stage machine and error reset")
+	}
+	if got, want := stage.Steps[0].Status, core.StatusPending; got != want {
+		t.Errorf("Want step status %s, got %s", want, got)
+	}
+}
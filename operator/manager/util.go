@@ -5,6 +5,8 @@
 package manager
 
 import (
+	"strings"
+
 	"github.com/drone/drone/core"
 )
 
@@ -22,6 +24,56 @@ func isBuildComplete(stages []*core.Stage) bool {
 	return true
 }
 
+// helper function returns true if the stage could not be
+// started because of an agent or infrastructure failure. The
+// failure is signaled explicitly by the runner or watchdog
+// abandoning the stage.
+func isStartError(stage *core.Stage) bool {
+	return stage.Status == core.StatusError && stage.Abandoned
+}
+
+// helper function returns true if the build is a pull request
+// from a fork. Pull requests are assumed to originate from a
+// fork if the source repository is unknown.
+func isFork(repo *core.Repository, build *core.Build) bool {
+	if build.Event != core.EventPullRequest {
+		return false
+	}
+	return build.Fork == "" || !strings.EqualFold(build.Fork, repo.Slug)
+}
+
+// helper function returns the overall build status from
+// the status of the individual stages. Stages that are
+// configured to ignore failures cannot fail the build.
+func buildStatus(stages []*core.Stage) string {
+	for _, stage := range stages {
+		if stage.Status == core.StatusKilled {
+			return core.StatusKilled
+		}
+		if stage.ErrIgnore {
+			continue
+		}
+		if stage.Status == core.StatusFailing {
+			return core.StatusFailing
+		}
+		if stage.Status == core.StatusError {
+			return core.StatusError
+		}
+	}
+	return core.StatusPassing
+}
+
+// helper function returns true if any stage failed. Stages
+// that are configured to ignore failures are excluded.
+func isFailed(stages []*core.Stage) bool {
+	for _, stage := range stages {
+		if stage.IsFailed() && !stage.ErrIgnore {
+			return true
+		}
+	}
+	return false
+}
+
 func isLastStage(stage *core.Stage, stages []*core.Stage) bool {
 	for _, sibling := range stages {
 		if stage.Number == sibling.Number {
@@ -100,3 +152,55 @@ func depsComplete(stage *core.Stage, siblings []*core.Stage) bool {
 	}
 	return true
 }
+
+// helper function returns the secrets that are provided to
+// the build. Secrets are not provided to pull requests unless
+// explicitly enabled.
+func filterSecrets(build *core.Build, secrets []*core.Secret) []*core.Secret {
+	var out []*core.Secret
+	for _, secret := range secrets {
+		if secret.PullRequest == false &&
+			build.Event == core.EventPullRequest {
+			continue
+		}
+		out = append(out, secret)
+	}
+	return out
+}
+
+// helper function merges the repository and organization
+// registry credentials. Repository credentials take precedence
+// over organization credentials for the same registry address.
+func mergeRegistries(repo, org []*core.Registry) []*core.Registry {
+	out := append([]*core.Registry{}, repo...)
+	for _, registry := range org {
+		if !hasRegistry(repo, registry.Address) {
+			out = append(out, registry)
+		}
+	}
+	return out
+}
+
+func hasRegistry(registries []*core.Registry, address string) bool {
+	for _, registry := range registries {
+		if registry.Address == address {
+			return true
+		}
+	}
+	return false
+}
+
+// helper function returns the names of the privileged images
+// for the organization. Images stored for the organization
+// override the global privileged images.
+func privilegedImages(global, org []*core.PrivilegedImage) []string {
+	images := global
+	if len(org) != 0 {
+		images = org
+	}
+	var out []string
+	for _, image := range images {
+		out = append(out, image.Image)
+	}
+	return out
+}
@@ -3,3 +3,155 @@
 // that can be found in the LICENSE file.
 
 package manager
+
+import (
+	"testing"
+
+	"github.com/drone/drone/core"
+)
+
+func TestBuildStatus(t *testing.T) {
+	tests := []struct {
+		stages []*core.Stage
+		status string
+	}{
+		{
+			stages: []*core.Stage{
+				{Status: core.StatusPassing},
+				{Status: core.StatusPassing},
+			},
+			status: core.StatusPassing,
+		},
+		{
+			stages: []*core.Stage{
+				{Status: core.StatusPassing},
+				{Status: core.StatusFailing},
+			},
+			status: core.StatusFailing,
+		},
+		{
+			stages: []*core.Stage{
+				{Status: core.StatusError},
+				{Status: core.StatusPassing},
+			},
+			status: core.StatusError,
+		},
+		{
+			stages: []*core.Stage{
+				{Status: core.StatusPassing},
+				{Status: core.StatusFailing, ErrIgnore: true},
+			},
+			status: core.StatusPassing,
+		},
+		{
+			stages: []*core.Stage{
+				{Status: core.StatusError, ErrIgnore: true},
+				{Status: core.StatusFailing},
+			},
+			status: core.StatusFailing,
+		},
+		{
+			stages: []*core.Stage{
+				{Status: core.StatusKilled, ErrIgnore: true},
+				{Status: core.StatusPassing},
+			},
+			status: core.StatusKilled,
+		},
+	}
+	for i, test := range tests {
+		if got, want := buildStatus(test.stages), test.status; got != want {
+			t.Errorf("Want build status %s, got %s at index %d", want, got, i)
+		}
+	}
+}
+
+func TestIsFailed(t *testing.T) {
+	stages := []*core.Stage{
+		{Status: core.StatusPassing},
+		{Status: core.StatusFailing, ErrIgnore: true},
+	}
+	if isFailed(stages) {
+		t.Errorf("Expect stages that ignore failures are excluded")
+	}
+	stages = append(stages, &core.Stage{Status: core.StatusError})
+	if !isFailed(stages) {
+		t.Errorf("Expect failed stage")
+	}
+}
+
+func TestIsStartError(t *testing.T) {
+	stage := &core.Stage{
+		Status:    core.StatusError,
+		Abandoned: true,
+		Steps: []*core.Step{
+			{Status: core.StatusSkipped},
+		},
+	}
+	if !isStartError(stage) {
+		t.Errorf("Expect abandoned stage is a start error")
+	}
+	// a stage that errors before any step starts, for example
+	// because of a yaml linting error, is not a start error
+	// unless abandoned by the runner or watchdog.
+	stage.Abandoned = false
+	if isStartError(stage) {
+		t.Errorf("Expect stage that was not abandoned is not a start error")
+	}
+	if isStartError(&core.Stage{Status: core.StatusFailing, Abandoned: true}) {
+		t.Errorf("Expect failing stage is not a start error")
+	}
+}
+
+func TestIsFork(t *testing.T) {
+	repo := &core.Repository{Slug: "octocat/hello-world"}
+	tests := []struct {
+		build *core.Build
+		fork  bool
+	}{
+		{&core.Build{Event: core.EventPush}, false},
+		{&core.Build{Event: core.EventPullRequest, Fork: "octocat/hello-world"}, false},
+		{&core.Build{Event: core.EventPullRequest, Fork: "spaceghost/hello-world"}, true},
+		{&core.Build{Event: core.EventPullRequest}, true},
+	}
+	for i, test := range tests {
+		if got, want := isFork(repo, test.build), test.fork; got != want {
+			t.Errorf("Want fork %v, got %v at index %d", want, got, i)
+		}
+	}
+}
+
+func TestMergeRegistries(t *testing.T) {
+	repo := []*core.Registry{
+		{Address: "docker.io", Username: "octocat"},
+	}
+	org := []*core.Registry{
+		{Address: "docker.io", Username: "octocat-org"},
+		{Address: "registry.company.com", Username: "octocat-org"},
+	}
+	got := mergeRegistries(repo, org)
+	if len(got) != 2 {
+		t.Errorf("Want 2 registries, got %d", len(got))
+		return
+	}
+	if got, want := got[0].Username, "octocat"; got != want {
+		t.Errorf("Want repository registry to take precedence, got %s", got)
+	}
+	if got, want := got[1].Address, "registry.company.com"; got != want {
+		t.Errorf("Want organization registry %s, got %s", want, got)
+	}
+}
+
+func TestPrivilegedImages(t *testing.T) {
+	global := []*core.PrivilegedImage{
+		{Image: "plugins/docker"},
+	}
+	org := []*core.PrivilegedImage{
+		{Namespace: "octocat", Image: "docker:dind"},
+	}
+	if got := privilegedImages(global, nil); len(got) != 1 || got[0] != "plugins/docker" {
+		t.Errorf("Want global privileged images, got %v", got)
+	}
+	if got := privilegedImages(global, org); len(got) != 1 || got[0] != "docker:dind" {
+		t.Errorf("Want organization privileged images to override global images, got %v", got)
+	}
+}
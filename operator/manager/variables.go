@@ -0,0 +1,70 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package manager
+
+import "github.com/drone/drone/core"
+
+// variables returns the server, organization and repository
+// variables for the repository, merged by name.
+func (m *Manager) variables(repo *core.Repository) ([]*core.Variable, error) {
+	server, err := m.Variables.ListNamespace(noContext, "")
+	if err != nil {
+		return nil, err
+	}
+	org, err := m.Variables.ListNamespace(noContext, repo.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	local, err := m.Variables.ListRepo(noContext, repo.ID)
+	if err != nil {
+		return nil, err
+	}
+	return mergeVariables(server, org, local), nil
+}
+
+// helper function merges the variables by name. Variables
+// in later lists take precedence, so that repository
+// variables override organization variables, which override
+// server variables.
+func mergeVariables(lists ...[]*core.Variable) []*core.Variable {
+	var out []*core.Variable
+	index := map[string]int{}
+	for _, list := range lists {
+		for _, variable := range list {
+			if i, ok := index[variable.Name]; ok {
+				out[i] = variable
+				continue
+			}
+			index[variable.Name] = len(out)
+			out = append(out, variable)
+		}
+	}
+	return out
+}
+
+// helper function converts the variables to an environment
+// variable map.
+func convertVariables(variables []*core.Variable) map[string]string {
+	envs := map[string]string{}
+	for _, variable := range variables {
+		envs[variable.Name] = variable.Value
+	}
+	return envs
+}
+
+// helper function returns the secret variables as secrets,
+// so that the values are masked in the build logs.
+func secretVariables(variables []*core.Variable) []*core.Secret {
+	var out []*core.Secret
+	for _, variable := range variables {
+		if variable.Secret {
+			out = append(out, &core.Secret{
+				Name: variable.Name,
+				Data: variable.Value,
+			})
+		}
+	}
+	return out
+}
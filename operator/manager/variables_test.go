@@ -0,0 +1,44 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package manager
+
+import (
+	"testing"
+
+	"github.com/drone/drone/core"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestMergeVariables(t *testing.T) {
+	server := []*core.Variable{
+		{Name: "GOPROXY", Value: "https://proxy.golang.org"},
+		{Name: "GOFLAGS", Value: "-mod=vendor"},
+	}
+	org := []*core.Variable{
+		{Namespace: "octocat", Name: "GOPROXY", Value: "https://goproxy.company.com"},
+	}
+	repo := []*core.Variable{
+		{RepoID: 1, Name: "NPM_TOKEN", Value: "correct-horse-battery-staple", Secret: true},
+		{RepoID: 1, Name: "GOFLAGS", Value: "-mod=mod"},
+	}
+	variables := mergeVariables(server, org, repo)
+
+	want := map[string]string{
+		"GOPROXY":   "https://goproxy.company.com",
+		"GOFLAGS":   "-mod=mod",
+		"NPM_TOKEN": "correct-horse-battery-staple",
+	}
+	if diff := cmp.Diff(convertVariables(variables), want); diff != "" {
+		t.Errorf(diff)
+	}
+
+	secrets := secretVariables(variables)
+	if got, want := len(secrets), 1; got != want {
+		t.Errorf("Want %d secret variables, got %d", want, got)
+	} else if got, want := secrets[0].Data, "correct-horse-battery-staple"; got != want {
+		t.Errorf("Want secret variable value %s, got %s", want, got)
+	}
+}
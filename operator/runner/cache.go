@@ -0,0 +1,208 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package runner
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/drone/drone-runtime/engine"
+	"github.com/drone/drone/extension"
+)
+
+// default cache plugin image.
+const defaultCacheImage = "meltwater/drone-cache:1"
+
+// cache backends.
+const (
+	CacheBackendS3         = "s3"
+	CacheBackendFilesystem = "filesystem"
+)
+
+// names of the generated cache steps.
+const (
+	cacheRestoreName = "restore-cache"
+	cacheRebuildName = "rebuild-cache"
+)
+
+// Cache defines the build cache configuration. The cache is
+// restored before the pipeline steps are executed, and
+// rebuilt when the pipeline steps complete successfully.
+type Cache struct {
+	Image     string
+	Backend   string
+	Bucket    string
+	Region    string
+	Endpoint  string
+	AccessKey string
+	SecretKey string
+	Path      string
+}
+
+// helper function expands the pipeline cache declaration
+// into restore and rebuild steps. The restore step is
+// executed after the clone step, and the rebuild step is
+// executed after all other pipeline steps.
+func applyCache(spec *engine.Spec, ext *extension.Pipeline, cache Cache, prefix string) {
+	if ext == nil || ext.Cache == nil || len(ext.Cache.Mount) == 0 {
+		return
+	}
+	if cache.Backend == "" {
+		return
+	}
+
+	// the workspace volume and working directory are copied
+	// from the first pipeline step, which is typically the
+	// clone step.
+	var (
+		first  *engine.Step
+		steps  []*engine.Step
+		detach []*engine.Step
+		dag    bool
+	)
+	for _, step := range spec.Steps {
+		if len(step.DependsOn) != 0 {
+			dag = true
+		}
+		if step.Detach {
+			detach = append(detach, step)
+			continue
+		}
+		if first == nil {
+			first = step
+		}
+		steps = append(steps, step)
+	}
+	if first == nil {
+		return
+	}
+
+	image := cache.Image
+	if image == "" {
+		image = defaultCacheImage
+	}
+	envs := cacheEnviron(ext.Cache, cache, prefix)
+	restore := createCacheStep(first, cacheRestoreName, image, envs)
+	restore.Envs["PLUGIN_RESTORE"] = "true"
+	rebuild := createCacheStep(first, cacheRebuildName, image, envs)
+	rebuild.Envs["PLUGIN_REBUILD"] = "true"
+
+	if cache.Backend == CacheBackendFilesystem {
+		mountCacheVolume(spec, restore, rebuild, cache.Path)
+	}
+
+	// if the pipeline is executed as a graph, the generated
+	// steps must be inserted into the graph. The restore step
+	// depends on the clone step, and the remaining steps
+	// depend on the restore step.
+	if dag {
+		restore.DependsOn = []string{first.Metadata.Name}
+		rebuild.DependsOn = []string{cacheRestoreName}
+		for _, step := range steps[1:] {
+			if len(step.DependsOn) == 0 || isOnlyDep(step, first.Metadata.Name) {
+				step.DependsOn = []string{cacheRestoreName}
+			}
+			rebuild.DependsOn = append(rebuild.DependsOn, step.Metadata.Name)
+		}
+	}
+
+	spec.Steps = nil
+	spec.Steps = append(spec.Steps, detach...)
+	spec.Steps = append(spec.Steps, first, restore)
+	spec.Steps = append(spec.Steps, steps[1:]...)
+	spec.Steps = append(spec.Steps, rebuild)
+}
+
+// helper function returns the cache plugin environment.
+func cacheEnviron(from *extension.Cache, cache Cache, prefix string) map[string]string {
+	envs := map[string]string{
+		"PLUGIN_BACKEND":   cache.Backend,
+		"PLUGIN_MOUNT":     strings.Join(from.Mount, ","),
+		"PLUGIN_CACHE_KEY": cacheKey(prefix, from.Checksum),
+	}
+	if cache.Backend == CacheBackendS3 {
+		envs["PLUGIN_BUCKET"] = cache.Bucket
+		envs["PLUGIN_REGION"] = cache.Region
+		envs["PLUGIN_ENDPOINT"] = cache.Endpoint
+		envs["PLUGIN_ACCESS_KEY"] = cache.AccessKey
+		envs["PLUGIN_SECRET_KEY"] = cache.SecretKey
+		if cache.Endpoint != "" {
+			envs["PLUGIN_PATH_STYLE"] = "true"
+		}
+	}
+	return envs
+}
+
+// helper function returns the cache key template. The key is
+// derived from the checksum of the named files (for example,
+// lock files) so that the cache is invalidated when the
+// dependencies change.
+func cacheKey(prefix string, files []string) string {
+	parts := []string{prefix}
+	for _, file := range files {
+		parts = append(parts, fmt.Sprintf("{{ checksum %q }}", file))
+	}
+	if len(files) == 0 {
+		parts = append(parts, "{{ .Commit.Branch }}")
+	}
+	return strings.Join(parts, "/")
+}
+
+// helper function creates a cache plugin step that shares
+// the workspace with the source step.
+func createCacheStep(from *engine.Step, name, image string, envs map[string]string) *engine.Step {
+	step := &engine.Step{
+		Metadata: engine.Metadata{
+			UID:       from.Metadata.UID + "-" + name,
+			Namespace: from.Metadata.Namespace,
+			Name:      name,
+			Labels:    from.Metadata.Labels,
+		},
+		Docker: &engine.DockerStep{
+			Image: image,
+		},
+		Envs:       map[string]string{},
+		Volumes:    from.Volumes,
+		WorkingDir: from.WorkingDir,
+	}
+	if from.Docker != nil {
+		step.Docker.Networks = from.Docker.Networks
+		step.Docker.PullPolicy = engine.PullIfNotExists
+	}
+	for k, v := range from.Envs {
+		step.Envs[k] = v
+	}
+	for k, v := range envs {
+		step.Envs[k] = v
+	}
+	return step
+}
+
+// helper function mounts the host cache directory into the
+// cache steps when using the filesystem backend.
+func mountCacheVolume(spec *engine.Spec, restore, rebuild *engine.Step, path string) {
+	if spec.Docker == nil {
+		spec.Docker = new(engine.DockerConfig)
+	}
+	volume := &engine.Volume{
+		Metadata: engine.Metadata{
+			UID:       spec.Metadata.UID + "-cache",
+			Namespace: spec.Metadata.Namespace,
+			Name:      "cache",
+		},
+		HostPath: &engine.VolumeHostPath{Path: path},
+	}
+	spec.Docker.Volumes = append(spec.Docker.Volumes, volume)
+
+	mount := &engine.VolumeMount{Name: "cache", Path: "/tmp/cache"}
+	restore.Volumes = append(restore.Volumes[:len(restore.Volumes):len(restore.Volumes)], mount)
+	rebuild.Volumes = append(rebuild.Volumes[:len(rebuild.Volumes):len(rebuild.Volumes)], mount)
+}
+
+// helper function returns true if the step depends on the
+// named step, and only the named step.
+func isOnlyDep(step *engine.Step, name string) bool {
+	return len(step.DependsOn) == 1 && step.DependsOn[0] == name
+}
@@ -0,0 +1,188 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package runner
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/drone/drone-runtime/engine"
+	"github.com/drone/drone/extension"
+)
+
+func TestApplyCache(t *testing.T) {
+	spec := &engine.Spec{
+		Steps: []*engine.Step{
+			{
+				Metadata:   engine.Metadata{Name: "clone"},
+				Docker:     &engine.DockerStep{Image: "drone/git"},
+				WorkingDir: "/drone/src",
+			},
+			{
+				Metadata: engine.Metadata{Name: "install"},
+				Docker:   &engine.DockerStep{Image: "node"},
+			},
+			{
+				Metadata: engine.Metadata{Name: "test"},
+				Docker:   &engine.DockerStep{Image: "node"},
+			},
+		},
+	}
+	ext := &extension.Pipeline{
+		Cache: &extension.Cache{
+			Mount:    []string{"node_modules", ".npm"},
+			Checksum: []string{"package-lock.json"},
+		},
+	}
+	cache := Cache{
+		Backend: CacheBackendS3,
+		Bucket:  "drone-cache",
+		Region:  "us-east-1",
+	}
+
+	applyCache(spec, ext, cache, "octocat/hello-world/default")
+
+	var names []string
+	for _, step := range spec.Steps {
+		names = append(names, step.Metadata.Name)
+	}
+	got := strings.Join(names, ",")
+	want := "clone,restore-cache,install,test,rebuild-cache"
+	if got != want {
+		t.Errorf("Want step order %s, got %s", want, got)
+	}
+
+	restore := spec.Steps[1]
+	if got, want := restore.Docker.Image, defaultCacheImage; got != want {
+		t.Errorf("Want cache image %s, got %s", want, got)
+	}
+	if got, want := restore.WorkingDir, "/drone/src"; got != want {
+		t.Errorf("Want working dir %s, got %s", want, got)
+	}
+	if restore.Envs["PLUGIN_RESTORE"] != "true" {
+		t.Errorf("Expect restore step restores the cache")
+	}
+	if got, want := restore.Envs["PLUGIN_MOUNT"], "node_modules,.npm"; got != want {
+		t.Errorf("Want cache mount %s, got %s", want, got)
+	}
+	if got, want := restore.Envs["PLUGIN_CACHE_KEY"], `octocat/hello-world/default/{{ checksum "package-lock.json" }}`; got != want {
+		t.Errorf("Want cache key %s, got %s", want, got)
+	}
+	if got, want := restore.Envs["PLUGIN_BUCKET"], "drone-cache"; got != want {
+		t.Errorf("Want cache bucket %s, got %s", want, got)
+	}
+
+	rebuild := spec.Steps[4]
+	if rebuild.Envs["PLUGIN_REBUILD"] != "true" {
+		t.Errorf("Expect rebuild step rebuilds the cache")
+	}
+	if _, ok := rebuild.Envs["PLUGIN_RESTORE"]; ok {
+		t.Errorf("Expect rebuild step does not restore the cache")
+	}
+}
+
+func TestApplyCache_Graph(t *testing.T) {
+	spec := &engine.Spec{
+		Steps: []*engine.Step{
+			{
+				Metadata: engine.Metadata{Name: "clone"},
+			},
+			{
+				Metadata:  engine.Metadata{Name: "install"},
+				DependsOn: []string{"clone"},
+			},
+			{
+				Metadata:  engine.Metadata{Name: "test"},
+				DependsOn: []string{"install"},
+			},
+		},
+	}
+	ext := &extension.Pipeline{
+		Cache: &extension.Cache{
+			Mount: []string{"node_modules"},
+		},
+	}
+
+	applyCache(spec, ext, Cache{Backend: CacheBackendS3}, "octocat/hello-world/default")
+
+	deps := map[string]string{}
+	for _, step := range spec.Steps {
+		deps[step.Metadata.Name] = strings.Join(step.DependsOn, ",")
+	}
+	if got, want := deps["restore-cache"], "clone"; got != want {
+		t.Errorf("Want restore dependencies %s, got %s", want, got)
+	}
+	if got, want := deps["install"], "restore-cache"; got != want {
+		t.Errorf("Want install dependencies %s, got %s", want, got)
+	}
+	if got, want := deps["test"], "install"; got != want {
+		t.Errorf("Want test dependencies %s, got %s", want, got)
+	}
+	if got, want := deps["rebuild-cache"], "restore-cache,install,test"; got != want {
+		t.Errorf("Want rebuild dependencies %s, got %s", want, got)
+	}
+	if got, want := spec.Steps[1].Envs["PLUGIN_CACHE_KEY"], "octocat/hello-world/default/{{ .Commit.Branch }}"; got != want {
+		t.Errorf("Want cache key %s, got %s", want, got)
+	}
+}
+
+func TestApplyCache_Filesystem(t *testing.T) {
+	spec := &engine.Spec{
+		Steps: []*engine.Step{
+			{
+				Metadata: engine.Metadata{Name: "clone"},
+				Volumes: []*engine.VolumeMount{
+					{Name: "workspace", Path: "/drone/src"},
+				},
+			},
+		},
+	}
+	ext := &extension.Pipeline{
+		Cache: &extension.Cache{
+			Mount: []string{"vendor"},
+		},
+	}
+	cache := Cache{
+		Backend: CacheBackendFilesystem,
+		Path:    "/var/lib/drone/cache",
+	}
+
+	applyCache(spec, ext, cache, "octocat/hello-world/default")
+
+	if spec.Docker == nil || len(spec.Docker.Volumes) != 1 {
+		t.Errorf("Expect cache volume added to the spec")
+		return
+	}
+	if got, want := spec.Docker.Volumes[0].HostPath.Path, "/var/lib/drone/cache"; got != want {
+		t.Errorf("Want cache volume path %s, got %s", want, got)
+	}
+	if got, want := len(spec.Steps[1].Volumes), 2; got != want {
+		t.Errorf("Want %d restore volumes, got %d", want, got)
+	}
+	if got, want := len(spec.Steps[0].Volumes), 1; got != want {
+		t.Errorf("Expect clone volumes unchanged, got %d volumes", got)
+	}
+	if _, ok := spec.Steps[1].Envs["PLUGIN_BUCKET"]; ok {
+		t.Errorf("Expect no bucket for the filesystem backend")
+	}
+}
+
+func TestApplyCache_Disabled(t *testing.T) {
+	spec := &engine.Spec{
+		Steps: []*engine.Step{
+			{Metadata: engine.Metadata{Name: "clone"}},
+			{Metadata: engine.Metadata{Name: "test"}},
+		},
+	}
+	ext := &extension.Pipeline{
+		Cache: &extension.Cache{
+			Mount: []string{"vendor"},
+		},
+	}
+	applyCache(spec, ext, Cache{}, "octocat/hello-world/default")
+	if got, want := len(spec.Steps), 2; got != want {
+		t.Errorf("Expect cache steps not added when the backend is not configured")
+	}
+}
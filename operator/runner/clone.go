@@ -0,0 +1,36 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package runner
+
+import (
+	"github.com/drone/drone-runtime/engine"
+	"github.com/drone/drone/extension"
+)
+
+// name of the clone step generated by the compiler.
+const cloneStepName = "clone"
+
+// helper function configures the clone step generated by
+// the compiler with the extended clone attributes. The clone
+// depth is configured by the compiler.
+func applyClone(spec *engine.Spec, ext *extension.Pipeline) {
+	if ext == nil || ext.Clone == nil {
+		return
+	}
+	for _, step := range spec.Steps {
+		if step.Metadata.Name != cloneStepName {
+			continue
+		}
+		if step.Envs == nil {
+			step.Envs = map[string]string{}
+		}
+		if ext.Clone.Tags {
+			step.Envs["PLUGIN_TAGS"] = "true"
+		}
+		if ext.Clone.Submodules {
+			step.Envs["PLUGIN_RECURSIVE"] = "true"
+		}
+	}
+}
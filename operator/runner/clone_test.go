@@ -0,0 +1,64 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package runner
+
+import (
+	"testing"
+
+	"github.com/drone/drone-runtime/engine"
+	"github.com/drone/drone/extension"
+)
+
+func TestApplyClone(t *testing.T) {
+	spec := &engine.Spec{
+		Steps: []*engine.Step{
+			{
+				Metadata: engine.Metadata{Name: "clone"},
+				Envs:     map[string]string{"PLUGIN_DEPTH": "50"},
+			},
+			{
+				Metadata: engine.Metadata{Name: "test"},
+				Envs:     map[string]string{},
+			},
+		},
+	}
+	ext := &extension.Pipeline{
+		Clone: &extension.Clone{
+			Tags:       true,
+			Submodules: true,
+		},
+	}
+
+	applyClone(spec, ext)
+
+	clone := spec.Steps[0]
+	if got, want := clone.Envs["PLUGIN_TAGS"], "true"; got != want {
+		t.Errorf("Want PLUGIN_TAGS %q, got %q", want, got)
+	}
+	if got, want := clone.Envs["PLUGIN_RECURSIVE"], "true"; got != want {
+		t.Errorf("Want PLUGIN_RECURSIVE %q, got %q", want, got)
+	}
+	if got, want := clone.Envs["PLUGIN_DEPTH"], "50"; got != want {
+		t.Errorf("Want PLUGIN_DEPTH %q, got %q", want, got)
+	}
+	if len(spec.Steps[1].Envs) != 0 {
+		t.Errorf("Expect clone attributes not applied to other steps")
+	}
+}
+
+func TestApplyClone_Empty(t *testing.T) {
+	spec := &engine.Spec{
+		Steps: []*engine.Step{
+			{
+				Metadata: engine.Metadata{Name: "clone"},
+			},
+		},
+	}
+	applyClone(spec, nil)
+	applyClone(spec, &extension.Pipeline{})
+	if len(spec.Steps[0].Envs) != 0 {
+		t.Errorf("Expect clone step unchanged")
+	}
+}
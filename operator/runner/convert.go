@@ -9,6 +9,7 @@ import (
 
 	"github.com/drone/drone-runtime/engine"
 	"github.com/drone/drone-runtime/runtime"
+	"github.com/drone/drone-yaml/yaml"
 	"github.com/drone/drone/core"
 )
 
@@ -46,6 +47,19 @@ func convertRegistry(from []*core.Registry) []*engine.DockerAuth {
 	return to
 }
 
+// helper function returns the images used by the pipeline
+// steps and services.
+func pipelineImages(pipeline *yaml.Pipeline) []string {
+	var images []string
+	for _, step := range pipeline.Steps {
+		images = append(images, step.Image)
+	}
+	for _, service := range pipeline.Services {
+		images = append(images, service.Image)
+	}
+	return images
+}
+
 func convertLines(from []*runtime.Line) []*core.Line {
 	var to []*core.Line
 	for _, v := range from {
@@ -0,0 +1,15 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package runner
+
+import "context"
+
+// Digester resolves the digest of the image used to execute
+// a pipeline step, so that the exact image is recorded even
+// when the image tag is later moved.
+type Digester interface {
+	// Digest returns the digest of the named image.
+	Digest(ctx context.Context, image string) (string, error)
+}
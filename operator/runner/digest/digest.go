@@ -0,0 +1,78 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+// Package digest provides a runner.Digester that resolves
+// image digests from the Docker daemon.
+package digest
+
+import (
+	"context"
+	"strings"
+
+	"github.com/drone/drone/operator/runner"
+
+	"docker.io/go-docker"
+)
+
+// NewEnv returns a new Digester using the Docker client
+// configured from the environment.
+func NewEnv() (runner.Digester, error) {
+	cli, err := docker.NewEnvClient()
+	if err != nil {
+		return nil, err
+	}
+	return New(cli), nil
+}
+
+// New returns a new Digester using the Docker API Client.
+func New(client docker.APIClient) runner.Digester {
+	return &digester{client: client}
+}
+
+type digester struct {
+	client docker.APIClient
+}
+
+func (d *digester) Digest(ctx context.Context, image string) (string, error) {
+	// the image is already pinned to a digest.
+	if strings.Contains(image, "@") {
+		return image, nil
+	}
+	info, _, err := d.client.ImageInspectWithRaw(ctx, image)
+	if err != nil {
+		return "", err
+	}
+	// images that were built locally, and were never pushed
+	// to or pulled from a registry, do not have a repository
+	// digest. The local image identifier is used instead.
+	if len(info.RepoDigests) == 0 {
+		return info.ID, nil
+	}
+	return match(image, info.RepoDigests), nil
+}
+
+// helper function returns the repository digest that
+// matches the image repository. If the image is tagged in
+// multiple repositories and no digest matches, the first
+// digest is returned.
+func match(image string, digests []string) string {
+	repo := trimTag(image)
+	for _, digest := range digests {
+		if strings.HasPrefix(digest, repo+"@") {
+			return digest
+		}
+	}
+	return digests[0]
+}
+
+// helper function removes the tag from the image name. The
+// registry host may include a port, so only a colon after
+// the last path separator denotes a tag.
+func trimTag(image string) string {
+	i := strings.LastIndex(image, ":")
+	if i > strings.LastIndex(image, "/") {
+		return image[:i]
+	}
+	return image
+}
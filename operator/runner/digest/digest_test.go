@@ -0,0 +1,58 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package digest
+
+import "testing"
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		image   string
+		digests []string
+		want    string
+	}{
+		{
+			image:   "golang:1.12",
+			digests: []string{"golang@sha256:3e8d"},
+			want:    "golang@sha256:3e8d",
+		},
+		{
+			image:   "golang",
+			digests: []string{"octocat/golang@sha256:9f1a", "golang@sha256:3e8d"},
+			want:    "golang@sha256:3e8d",
+		},
+		{
+			image:   "localhost:5000/octocat/golang:latest",
+			digests: []string{"golang@sha256:3e8d", "localhost:5000/octocat/golang@sha256:9f1a"},
+			want:    "localhost:5000/octocat/golang@sha256:9f1a",
+		},
+		{
+			image:   "octocat/golang:latest",
+			digests: []string{"golang@sha256:3e8d"},
+			want:    "golang@sha256:3e8d",
+		},
+	}
+	for _, test := range tests {
+		if got := match(test.image, test.digests); got != test.want {
+			t.Errorf("Want digest %q for image %q, got %q", test.want, test.image, got)
+		}
+	}
+}
+
+func TestTrimTag(t *testing.T) {
+	tests := []struct {
+		image string
+		want  string
+	}{
+		{"golang", "golang"},
+		{"golang:1.12", "golang"},
+		{"localhost:5000/golang", "localhost:5000/golang"},
+		{"localhost:5000/golang:1.12", "localhost:5000/golang"},
+	}
+	for _, test := range tests {
+		if got := trimTag(test.image); got != test.want {
+			t.Errorf("Want repository %q for image %q, got %q", test.want, test.image, got)
+		}
+	}
+}
@@ -0,0 +1,211 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package runner
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/drone/drone-runtime/engine"
+	"github.com/drone/drone-yaml/yaml"
+	"github.com/drone/drone-yaml/yaml/compiler"
+	"github.com/drone/drone-yaml/yaml/compiler/transform"
+	"github.com/drone/drone-yaml/yaml/converter"
+	"github.com/drone/drone-yaml/yaml/linter"
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/extension"
+	"github.com/drone/drone/extension/expr"
+	"github.com/drone/envsubst"
+)
+
+// dryRunSecret replaces secret values in the compiled
+// pipeline spec.
+const dryRunSecret = "********"
+
+// DryRunStage is a pipeline stage compiled without being
+// executed. The stage is skipped if the pipeline trigger does
+// not match the build, in which case the spec is empty.
+type DryRunStage struct {
+	Name      string       `json:"name"`
+	DependsOn []string     `json:"depends_on,omitempty"`
+	Skipped   bool         `json:"skipped"`
+	Reason    string       `json:"reason,omitempty"`
+	Error     string       `json:"error,omitempty"`
+	Spec      *engine.Spec `json:"spec,omitempty"`
+}
+
+// DryRun compiles the pipelines in the configuration file for
+// the repository and build, without executing the pipelines.
+// Secrets are not resolved, and registry credentials and the
+// netrc file are not included in the compiled spec.
+func DryRun(repo *core.Repository, build *core.Build, system *core.System, data string) ([]*DryRunStage, error) {
+	environ := combineEnviron(
+		buildEnviron(build),
+		repoEnviron(repo),
+		systemEnviron(system),
+		linkEnviron(repo, build, system),
+		build.Params,
+	)
+
+	y, err := envsubst.Eval(data, func(name string) string {
+		env := environ[name]
+		if strings.Contains(env, "\n") {
+			env = fmt.Sprintf("%q", env)
+		}
+		return env
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// the legacy yaml configuration file is converted to the
+	// new format, which expands the legacy build matrix into
+	// multiple pipelines.
+	y, err = converter.ConvertString(y, converter.Metadata{
+		Filename: repo.Config,
+		Ref:      build.Ref,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	manifest, err := yaml.ParseString(y)
+	if err != nil {
+		return nil, err
+	}
+
+	extensions, err := extension.ParseString(y)
+	if err != nil {
+		return nil, err
+	}
+
+	vars := expr.Vars(repo, build, system.Host)
+
+	out := []*DryRunStage{}
+	for _, resource := range manifest.Resources {
+		pipeline, ok := resource.(*yaml.Pipeline)
+		if !ok {
+			continue
+		}
+		stage := &DryRunStage{
+			Name:      pipeline.Name,
+			DependsOn: pipeline.DependsOn,
+		}
+		if stage.Name == "" {
+			stage.Name = "default"
+		}
+		out = append(out, stage)
+
+		if reason := dryRunSkip(pipeline, repo, build); reason != "" {
+			stage.Skipped = true
+			stage.Reason = reason
+			continue
+		}
+
+		ext := extensions.Lookup(stage.Name)
+		if ext != nil && ext.Trigger != nil {
+			if !ext.Trigger.Tag.Match(vars["tag"]) {
+				stage.Skipped = true
+				stage.Reason = "does not match tag"
+				continue
+			}
+			ok, err := ext.Trigger.Match(vars)
+			if err != nil {
+				stage.Error = err.Error()
+				continue
+			}
+			if !ok {
+				stage.Skipped = true
+				stage.Reason = "does not match expression"
+				continue
+			}
+		}
+
+		// stages that request settings restricted to trusted
+		// repositories are compiled once approved by a system
+		// administrator, and are therefore linted as trusted.
+		if err := linter.Lint(pipeline, true); err != nil {
+			stage.Error = err.Error()
+			continue
+		}
+
+		comp := new(compiler.Compiler)
+		comp.PrivilegedFunc = compiler.DindFunc(
+			[]string{
+				"plugins/docker",
+				"plugins/ecr",
+				"plugins/gcr",
+				"plugins/heroku",
+			},
+		)
+		comp.SkipFunc = compiler.SkipFunc(
+			compiler.SkipData{
+				Branch:   build.Target,
+				Event:    build.Event,
+				Instance: system.Host,
+				Ref:      build.Ref,
+				Repo:     repo.Slug,
+				Target:   build.Deploy,
+			},
+		)
+		comp.TransformFunc = transform.Combine(
+			transform.WithEnviron(environ),
+			transform.WithSecretFunc(
+				func(name string) *engine.Secret {
+					return &engine.Secret{
+						Metadata: engine.Metadata{Name: name},
+						Data:     dryRunSecret,
+					}
+				},
+			),
+		)
+		ir := comp.Compile(pipeline)
+
+		if !pipeline.Clone.Disable {
+			applyClone(ir, ext)
+		}
+		if ext != nil {
+			for _, s := range ir.Steps {
+				if step := ext.Step(s.Metadata.Name); step != nil && step.IgnoreFailure() {
+					s.IgnoreErr = true
+				}
+			}
+		}
+		applyHealthchecks(ir, ext)
+		if err := applyConditions(ir, ext, vars); err != nil {
+			stage.Error = err.Error()
+			continue
+		}
+
+		// secrets defined in the configuration file are
+		// replaced, in addition to the resolved secrets.
+		for _, secret := range ir.Secrets {
+			secret.Data = dryRunSecret
+		}
+		stage.Spec = ir
+	}
+	return out, nil
+}
+
+// helper function returns the reason the pipeline trigger
+// does not match the build, or an empty string if the
+// pipeline is executed. The conditions are evaluated in the
+// same order as the build trigger.
+func dryRunSkip(pipeline *yaml.Pipeline, repo *core.Repository, build *core.Build) string {
+	switch {
+	case !pipeline.Trigger.Branch.Match(build.Target):
+		return "does not match branch"
+	case !pipeline.Trigger.Event.Match(build.Event):
+		return "does not match event"
+	case !pipeline.Trigger.Ref.Match(build.Ref):
+		return "does not match ref"
+	case !pipeline.Trigger.Repo.Match(repo.Slug):
+		return "does not match repo"
+	case !pipeline.Trigger.Target.Match(build.Deploy):
+		return "does not match deploy target"
+	default:
+		return ""
+	}
+}
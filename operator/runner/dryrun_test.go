@@ -0,0 +1,140 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package runner
+
+import (
+	"testing"
+
+	"github.com/drone/drone-runtime/engine"
+	"github.com/drone/drone/core"
+)
+
+func TestDryRun(t *testing.T) {
+	repo := &core.Repository{Slug: "octocat/hello-world", Config: ".drone.yml"}
+	build := &core.Build{Event: core.EventPush, Ref: "refs/heads/master", Target: "master"}
+	system := &core.System{Proto: "https", Host: "drone.company.com"}
+
+	stages, err := DryRun(repo, build, system, dummyDryRunConfig)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if got, want := len(stages), 2; got != want {
+		t.Errorf("Want %d stages, got %d", want, got)
+		return
+	}
+
+	stage := stages[0]
+	if stage.Skipped {
+		t.Errorf("Want stage %s executed", stage.Name)
+	}
+	if stage.Spec == nil {
+		t.Errorf("Want stage %s compiled", stage.Name)
+		return
+	}
+	found := false
+	for _, step := range stage.Spec.Steps {
+		if step.Metadata.Name == "test" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Want step test in the compiled spec")
+	}
+	for _, secret := range stage.Spec.Secrets {
+		if got, want := secret.Data, dryRunSecret; got != want {
+			t.Errorf("Want secret %s redacted, got %q", secret.Metadata.Name, got)
+		}
+	}
+
+	stage = stages[1]
+	if !stage.Skipped {
+		t.Errorf("Want stage %s skipped", stage.Name)
+	}
+	if got, want := stage.Reason, "does not match branch"; got != want {
+		t.Errorf("Want skip reason %q, got %q", want, got)
+	}
+	if stage.Spec != nil {
+		t.Errorf("Expect skipped stage not compiled")
+	}
+}
+
+var dummyDryRunConfig = `
+kind: pipeline
+name: test
+steps:
+- name: test
+  image: golang
+  commands: [ go test ]
+  environment:
+    TOKEN:
+      from_secret: token
+
+---
+kind: pipeline
+name: deploy
+steps:
+- name: deploy
+  image: plugins/heroku
+trigger:
+  branch: [ production ]
+`
+
+func TestDryRun_Expr(t *testing.T) {
+	repo := &core.Repository{Slug: "octocat/hello-world", Config: ".drone.yml"}
+	build := &core.Build{Event: core.EventPush, Ref: "refs/heads/master", Target: "master"}
+	system := &core.System{Proto: "https", Host: "drone.company.com"}
+
+	stages, err := DryRun(repo, build, system, dummyDryRunExprConfig)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if got, want := len(stages), 2; got != want {
+		t.Errorf("Want %d stages, got %d", want, got)
+		return
+	}
+
+	stage := stages[0]
+	if stage.Skipped || stage.Spec == nil {
+		t.Errorf("Want stage %s compiled", stage.Name)
+		return
+	}
+	for _, step := range stage.Spec.Steps {
+		if step.Metadata.Name == "publish" && step.RunPolicy != engine.RunNever {
+			t.Errorf("Want step publish skipped")
+		}
+	}
+
+	stage = stages[1]
+	if !stage.Skipped {
+		t.Errorf("Want stage %s skipped", stage.Name)
+	}
+	if got, want := stage.Reason, "does not match expression"; got != want {
+		t.Errorf("Want skip reason %q, got %q", want, got)
+	}
+}
+
+var dummyDryRunExprConfig = `
+kind: pipeline
+name: test
+steps:
+- name: test
+  image: golang
+  commands: [ go test ]
+- name: publish
+  image: plugins/docker
+  when:
+    expr: event == "tag"
+
+---
+kind: pipeline
+name: deploy
+steps:
+- name: deploy
+  image: plugins/heroku
+trigger:
+  expr: branch glob "release/*" || event == "promote"
+`
@@ -10,6 +10,8 @@ import (
 	"strings"
 
 	"github.com/drone/drone/core"
+
+	"github.com/coreos/go-semver/semver"
 )
 
 func systemEnviron(system *core.System) map[string]string {
@@ -89,6 +91,7 @@ func buildEnviron(build *core.Build) map[string]string {
 	}
 	if strings.HasPrefix(build.Ref, "refs/tags/") {
 		env["DRONE_TAG"] = strings.TrimPrefix(build.Ref, "refs/tags/")
+		env = combineEnviron(env, versionEnviron(env["DRONE_TAG"]))
 	}
 	if build.Event == core.EventPullRequest {
 		env["DRONE_PULL_REQUEST"] = re.FindString(build.Ref)
@@ -96,6 +99,28 @@ func buildEnviron(build *core.Build) map[string]string {
 	return env
 }
 
+// helper function parses the tag as a semantic version and
+// returns the version components. The leading v is optional.
+// If the tag is not a valid semantic version, the parsing
+// error is returned instead.
+func versionEnviron(tag string) map[string]string {
+	v, err := semver.NewVersion(strings.TrimPrefix(tag, "v"))
+	if err != nil {
+		return map[string]string{
+			"DRONE_SEMVER_ERROR": err.Error(),
+		}
+	}
+	return map[string]string{
+		"DRONE_SEMVER":            v.String(),
+		"DRONE_SEMVER_MAJOR":      fmt.Sprint(v.Major),
+		"DRONE_SEMVER_MINOR":      fmt.Sprint(v.Minor),
+		"DRONE_SEMVER_PATCH":      fmt.Sprint(v.Patch),
+		"DRONE_SEMVER_PRERELEASE": string(v.PreRelease),
+		"DRONE_SEMVER_BUILD":      v.Metadata,
+		"DRONE_SEMVER_SHORT":      fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch),
+	}
+}
+
 func linkEnviron(repo *core.Repository, build *core.Build, system *core.System) map[string]string {
 	return map[string]string{
 		"DRONE_BUILD_LINK": fmt.Sprintf(
@@ -108,6 +133,18 @@ func linkEnviron(repo *core.Repository, build *core.Build, system *core.System)
 	}
 }
 
+// helper function returns the identity token environment
+// variables. The token is only available if identity tokens
+// are enabled on the server.
+func idtokenEnviron(token string) map[string]string {
+	if token == "" {
+		return nil
+	}
+	return map[string]string{
+		"DRONE_OIDC_TOKEN": token,
+	}
+}
+
 // regular expression to extract the pull request number
 // from the git ref (e.g. refs/pulls/{d}/head)
 var re = regexp.MustCompile("\\d+")
@@ -48,3 +48,65 @@ func Test_runnerEnviron(t *testing.T) {
 		t.Errorf(diff)
 	}
 }
+
+func Test_idtokenEnviron(t *testing.T) {
+	if got := idtokenEnviron(""); got != nil {
+		t.Errorf("Want no environment variables without a token")
+	}
+	got := idtokenEnviron("eyJhbGciOiJSUzI1NiJ9.e30.c2lnbmF0dXJl")
+	want := map[string]string{
+		"DRONE_OIDC_TOKEN": "eyJhbGciOiJSUzI1NiJ9.e30.c2lnbmF0dXJl",
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf(diff)
+	}
+}
+
+func Test_buildEnviron_Semver(t *testing.T) {
+	build := &core.Build{
+		Event: core.EventTag,
+		Ref:   "refs/tags/v1.2.3-alpha.1+001",
+	}
+	got := buildEnviron(build)
+	want := map[string]string{
+		"DRONE_TAG":               "v1.2.3-alpha.1+001",
+		"DRONE_SEMVER":            "1.2.3-alpha.1+001",
+		"DRONE_SEMVER_MAJOR":      "1",
+		"DRONE_SEMVER_MINOR":      "2",
+		"DRONE_SEMVER_PATCH":      "3",
+		"DRONE_SEMVER_PRERELEASE": "alpha.1",
+		"DRONE_SEMVER_BUILD":      "001",
+		"DRONE_SEMVER_SHORT":      "1.2.3",
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("Want %s=%q, got %q", k, v, got[k])
+		}
+	}
+	if _, ok := got["DRONE_SEMVER_ERROR"]; ok {
+		t.Errorf("Expect no semver error for a valid version")
+	}
+}
+
+func Test_buildEnviron_SemverError(t *testing.T) {
+	build := &core.Build{
+		Event: core.EventTag,
+		Ref:   "refs/tags/release-2019",
+	}
+	got := buildEnviron(build)
+	if got["DRONE_SEMVER_ERROR"] == "" {
+		t.Errorf("Expect semver error for an invalid version")
+	}
+	if _, ok := got["DRONE_SEMVER"]; ok {
+		t.Errorf("Expect no semver for an invalid version")
+	}
+
+	build = &core.Build{
+		Event: core.EventPush,
+		Ref:   "refs/heads/master",
+	}
+	got = buildEnviron(build)
+	if _, ok := got["DRONE_SEMVER_ERROR"]; ok {
+		t.Errorf("Expect no semver variables for a branch")
+	}
+}
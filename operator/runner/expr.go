@@ -0,0 +1,37 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package runner
+
+import (
+	"fmt"
+
+	"github.com/drone/drone-runtime/engine"
+	"github.com/drone/drone/extension"
+)
+
+// helper function evaluates the step when expressions, and
+// disables the steps that do not match the build. The
+// expressions are evaluated in addition to the conditions
+// evaluated by the compiler. An error is returned if an
+// expression is invalid.
+func applyConditions(spec *engine.Spec, ext *extension.Pipeline, vars map[string]string) error {
+	if ext == nil {
+		return nil
+	}
+	for _, step := range spec.Steps {
+		conf := ext.Step(step.Metadata.Name)
+		if conf == nil {
+			continue
+		}
+		ok, err := conf.When.Match(vars)
+		if err != nil {
+			return fmt.Errorf("step %s: %s", step.Metadata.Name, err)
+		}
+		if !ok {
+			step.RunPolicy = engine.RunNever
+		}
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package runner
+
+import (
+	"testing"
+
+	"github.com/drone/drone-runtime/engine"
+	"github.com/drone/drone/extension"
+)
+
+func TestApplyConditions(t *testing.T) {
+	spec := &engine.Spec{
+		Steps: []*engine.Step{
+			{Metadata: engine.Metadata{Name: "test"}},
+			{Metadata: engine.Metadata{Name: "publish"}},
+			{Metadata: engine.Metadata{Name: "notify"}},
+		},
+	}
+	ext := &extension.Pipeline{
+		Steps: []*extension.Step{
+			{Name: "test", When: &extension.Conditions{Expr: `branch == "master"`}},
+			{Name: "publish", When: &extension.Conditions{Expr: `event == "tag"`}},
+		},
+	}
+	vars := map[string]string{"branch": "master", "event": "push"}
+
+	if err := applyConditions(spec, ext, vars); err != nil {
+		t.Error(err)
+		return
+	}
+	if spec.Steps[0].RunPolicy == engine.RunNever {
+		t.Errorf("Want step test executed")
+	}
+	if spec.Steps[1].RunPolicy != engine.RunNever {
+		t.Errorf("Want step publish skipped")
+	}
+	if spec.Steps[2].RunPolicy == engine.RunNever {
+		t.Errorf("Want step notify without extensions executed")
+	}
+}
+
+func TestApplyConditions_Invalid(t *testing.T) {
+	spec := &engine.Spec{
+		Steps: []*engine.Step{
+			{Metadata: engine.Metadata{Name: "test"}},
+		},
+	}
+	ext := &extension.Pipeline{
+		Steps: []*extension.Step{
+			{Name: "test", When: &extension.Conditions{Expr: `branch ==`}},
+		},
+	}
+	if err := applyConditions(spec, ext, nil); err == nil {
+		t.Errorf("Want error for invalid expression")
+	}
+}
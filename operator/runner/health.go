@@ -0,0 +1,154 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package runner
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	"github.com/drone/drone-runtime/engine"
+	"github.com/drone/drone/extension"
+)
+
+// probeImage is the image used to probe the service port.
+// The busybox netcat utility supports port scanning.
+var probeImage = "alpine:3.9"
+
+// default health check settings.
+const (
+	defaultProbeInterval = time.Second
+	defaultProbeRetries  = 60
+)
+
+// helper function appends a health check step for each
+// service that defines a health check. Pipeline steps do
+// not start until all health check steps complete, which
+// ensures services are ready before they are used.
+func applyHealthchecks(spec *engine.Spec, ext *extension.Pipeline) {
+	if ext == nil {
+		return
+	}
+
+	var (
+		services []*engine.Step
+		steps    []*engine.Step
+		checks   []*engine.Step
+		names    []string
+		dag      bool
+	)
+
+	for _, step := range spec.Steps {
+		if len(step.DependsOn) != 0 {
+			dag = true
+		}
+		service := ext.Service(step.Metadata.Name)
+		if service == nil || !step.Detach {
+			steps = append(steps, step)
+			continue
+		}
+		services = append(services, step)
+		if service.Healthcheck == nil || step.Docker == nil {
+			continue
+		}
+		check := createHealthcheck(step, service.Healthcheck)
+		checks = append(checks, check)
+		names = append(names, check.Metadata.Name)
+	}
+
+	if len(checks) == 0 {
+		return
+	}
+
+	// if the pipeline is executed as a graph, steps without
+	// dependencies must depend on the health checks.
+	if dag {
+		for _, step := range steps {
+			if len(step.DependsOn) == 0 && !step.Detach {
+				step.DependsOn = append(step.DependsOn, names...)
+			}
+		}
+	}
+
+	spec.Steps = nil
+	spec.Steps = append(spec.Steps, services...)
+	spec.Steps = append(spec.Steps, checks...)
+	spec.Steps = append(spec.Steps, steps...)
+}
+
+// helper function creates a health check step from the
+// service step. The health check runs in the same network
+// as the service, and exits when the service is healthy or
+// the retry limit is exceeded.
+func createHealthcheck(service *engine.Step, check *extension.Healthcheck) *engine.Step {
+	interval := check.Interval
+	if interval <= 0 {
+		interval = defaultProbeInterval
+	}
+	retries := check.Retries
+	if retries <= 0 {
+		retries = defaultProbeRetries
+	}
+
+	docker := new(engine.DockerStep)
+	*docker = *service.Docker
+	docker.Ports = nil
+	docker.Privileged = false
+	docker.Command = []string{"/bin/sh", "-c"}
+
+	// the service image is used to execute the health check
+	// command, which allows the command to use the service
+	// client utilities (e.g. pg_isready, redis-cli).
+	command := check.Command
+	if command == "" {
+		docker.Image = probeImage
+		command = fmt.Sprintf("nc -z %s %d", service.Metadata.Name, check.Port)
+	}
+	docker.Args = []string{
+		createProbeScript(service.Metadata.Name, command, interval, retries),
+	}
+
+	envs := map[string]string{}
+	for k, v := range service.Envs {
+		envs[k] = v
+	}
+
+	return &engine.Step{
+		Metadata: engine.Metadata{
+			UID:       service.Metadata.UID + "-healthcheck",
+			Namespace: service.Metadata.Namespace,
+			Name:      service.Metadata.Name + "-healthcheck",
+			Labels:    service.Metadata.Labels,
+		},
+		Docker:     docker,
+		Envs:       envs,
+		Resources:  service.Resources,
+		Secrets:    service.Secrets,
+		Volumes:    service.Volumes,
+		WorkingDir: service.WorkingDir,
+	}
+}
+
+// helper function returns a shell script that executes the
+// command until it succeeds or the retry limit is exceeded.
+func createProbeScript(name, command string, interval time.Duration, retries int) string {
+	seconds := int(interval / time.Second)
+	if seconds < 1 {
+		seconds = 1
+	}
+	buf := new(bytes.Buffer)
+	fmt.Fprintf(buf, "echo + waiting for service %s\n", name)
+	fmt.Fprintf(buf, "i=0\n")
+	fmt.Fprintf(buf, "until %s; do\n", command)
+	fmt.Fprintf(buf, "  i=$((i+1))\n")
+	fmt.Fprintf(buf, "  if [ $i -ge %d ]; then\n", retries)
+	fmt.Fprintf(buf, "    echo + service %s is not healthy\n", name)
+	fmt.Fprintf(buf, "    exit 1\n")
+	fmt.Fprintf(buf, "  fi\n")
+	fmt.Fprintf(buf, "  sleep %d\n", seconds)
+	fmt.Fprintf(buf, "done\n")
+	fmt.Fprintf(buf, "echo + service %s is healthy\n", name)
+	return buf.String()
+}
@@ -0,0 +1,142 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package runner
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/drone/drone-runtime/engine"
+	"github.com/drone/drone/extension"
+)
+
+func TestApplyHealthchecks(t *testing.T) {
+	spec := &engine.Spec{
+		Steps: []*engine.Step{
+			{
+				Metadata: engine.Metadata{Name: "clone"},
+				Docker:   &engine.DockerStep{Image: "drone/git"},
+			},
+			{
+				Metadata: engine.Metadata{Name: "database"},
+				Detach:   true,
+				Docker:   &engine.DockerStep{Image: "postgres"},
+			},
+			{
+				Metadata: engine.Metadata{Name: "cache"},
+				Detach:   true,
+				Docker:   &engine.DockerStep{Image: "redis"},
+			},
+			{
+				Metadata: engine.Metadata{Name: "test"},
+				Docker:   &engine.DockerStep{Image: "golang"},
+			},
+		},
+	}
+	ext := &extension.Pipeline{
+		Services: []*extension.Service{
+			{
+				Name: "database",
+				Healthcheck: &extension.Healthcheck{
+					Command: "pg_isready -h database",
+				},
+			},
+			{
+				Name: "cache",
+				Healthcheck: &extension.Healthcheck{
+					Port: 6379,
+				},
+			},
+		},
+	}
+
+	applyHealthchecks(spec, ext)
+
+	var names []string
+	for _, step := range spec.Steps {
+		names = append(names, step.Metadata.Name)
+	}
+	got := strings.Join(names, ",")
+	want := "database,cache,database-healthcheck,cache-healthcheck,clone,test"
+	if got != want {
+		t.Errorf("Want step order %s, got %s", want, got)
+	}
+
+	check := spec.Steps[2]
+	if check.Detach {
+		t.Errorf("Expect health check is not detached")
+	}
+	if got, want := check.Docker.Image, "postgres"; got != want {
+		t.Errorf("Want health check image %s, got %s", want, got)
+	}
+	if !strings.Contains(check.Docker.Args[0], "until pg_isready -h database; do") {
+		t.Errorf("Expect health check command in script")
+	}
+
+	check = spec.Steps[3]
+	if got, want := check.Docker.Image, probeImage; got != want {
+		t.Errorf("Want health check image %s, got %s", want, got)
+	}
+	if !strings.Contains(check.Docker.Args[0], "until nc -z cache 6379; do") {
+		t.Errorf("Expect port probe in script")
+	}
+}
+
+func TestApplyHealthchecks_Graph(t *testing.T) {
+	spec := &engine.Spec{
+		Steps: []*engine.Step{
+			{
+				Metadata: engine.Metadata{Name: "database"},
+				Detach:   true,
+				Docker:   &engine.DockerStep{Image: "postgres"},
+			},
+			{
+				Metadata: engine.Metadata{Name: "build"},
+				Docker:   &engine.DockerStep{Image: "golang"},
+			},
+			{
+				Metadata:  engine.Metadata{Name: "test"},
+				DependsOn: []string{"build"},
+				Docker:    &engine.DockerStep{Image: "golang"},
+			},
+		},
+	}
+	ext := &extension.Pipeline{
+		Services: []*extension.Service{
+			{
+				Name:        "database",
+				Healthcheck: &extension.Healthcheck{Port: 5432},
+			},
+		},
+	}
+
+	applyHealthchecks(spec, ext)
+
+	build := spec.Steps[2]
+	if got, want := strings.Join(build.DependsOn, ","), "database-healthcheck"; got != want {
+		t.Errorf("Want dependencies %s, got %s", want, got)
+	}
+	test := spec.Steps[3]
+	if got, want := strings.Join(test.DependsOn, ","), "build"; got != want {
+		t.Errorf("Want dependencies %s, got %s", want, got)
+	}
+}
+
+func TestApplyHealthchecks_None(t *testing.T) {
+	spec := &engine.Spec{
+		Steps: []*engine.Step{
+			{
+				Metadata: engine.Metadata{Name: "database"},
+				Detach:   true,
+				Docker:   &engine.DockerStep{Image: "postgres"},
+			},
+		},
+	}
+	applyHealthchecks(spec, &extension.Pipeline{})
+	applyHealthchecks(spec, nil)
+	if got, want := len(spec.Steps), 1; got != want {
+		t.Errorf("Want %d steps, got %d", want, got)
+	}
+}
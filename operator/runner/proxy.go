@@ -0,0 +1,68 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package runner
+
+// proxyCACertPath is the path at which the certificate
+// authority bundle is mounted in the pipeline containers.
+const proxyCACertPath = "/etc/drone/certs/ca-certificates.crt"
+
+// Proxy defines the proxy and certificate authority
+// configuration injected into every pipeline step, so that
+// installations behind a corporate firewall do not need to
+// configure the proxy in each pipeline.
+type Proxy struct {
+	HTTP    string
+	HTTPS   string
+	SOCKS   string
+	NoProxy string
+
+	// CACert is the host path of a certificate bundle that
+	// is mounted in the pipeline containers. The bundle must
+	// include the public root certificates in addition to
+	// the corporate certificates, since it replaces the
+	// default bundle.
+	CACert string
+}
+
+// helper function returns the proxy environment. Both the
+// upper and lower case variables are set, since tools are
+// inconsistent in which variables they respect.
+func proxyEnviron(proxy Proxy) map[string]string {
+	envs := map[string]string{}
+	set := func(key, value string) {
+		if value != "" {
+			envs[key] = value
+		}
+	}
+	for _, key := range []string{"HTTP_PROXY", "http_proxy"} {
+		set(key, proxy.HTTP)
+	}
+	for _, key := range []string{"HTTPS_PROXY", "https_proxy"} {
+		set(key, proxy.HTTPS)
+	}
+	for _, key := range []string{"ALL_PROXY", "all_proxy"} {
+		set(key, proxy.SOCKS)
+	}
+	for _, key := range []string{"NO_PROXY", "no_proxy"} {
+		set(key, proxy.NoProxy)
+	}
+	if proxy.CACert != "" {
+		envs["SSL_CERT_FILE"] = proxyCACertPath
+		envs["GIT_SSL_CAINFO"] = proxyCACertPath
+		envs["NODE_EXTRA_CA_CERTS"] = proxyCACertPath
+		envs["REQUESTS_CA_BUNDLE"] = proxyCACertPath
+	}
+	return envs
+}
+
+// helper function returns the host volumes that mount the
+// certificate authority bundle in the pipeline containers.
+func proxyVolumes(proxy Proxy) map[string]string {
+	volumes := map[string]string{}
+	if proxy.CACert != "" {
+		volumes[proxy.CACert] = proxyCACertPath
+	}
+	return volumes
+}
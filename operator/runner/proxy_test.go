@@ -0,0 +1,49 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package runner
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestProxyEnviron(t *testing.T) {
+	proxy := Proxy{
+		HTTP:    "http://proxy.company.com:3128",
+		SOCKS:   "socks5://proxy.company.com:1080",
+		NoProxy: "localhost,.company.com",
+		CACert:  "/etc/ssl/company.crt",
+	}
+	want := map[string]string{
+		"HTTP_PROXY":          "http://proxy.company.com:3128",
+		"http_proxy":          "http://proxy.company.com:3128",
+		"ALL_PROXY":           "socks5://proxy.company.com:1080",
+		"all_proxy":           "socks5://proxy.company.com:1080",
+		"NO_PROXY":            "localhost,.company.com",
+		"no_proxy":            "localhost,.company.com",
+		"SSL_CERT_FILE":       proxyCACertPath,
+		"GIT_SSL_CAINFO":      proxyCACertPath,
+		"NODE_EXTRA_CA_CERTS": proxyCACertPath,
+		"REQUESTS_CA_BUNDLE":  proxyCACertPath,
+	}
+	if diff := cmp.Diff(proxyEnviron(proxy), want); diff != "" {
+		t.Errorf(diff)
+	}
+	if got := proxyEnviron(Proxy{}); len(got) != 0 {
+		t.Errorf("Want empty proxy environment, got %v", got)
+	}
+}
+
+func TestProxyVolumes(t *testing.T) {
+	got := proxyVolumes(Proxy{CACert: "/etc/ssl/company.crt"})
+	want := map[string]string{"/etc/ssl/company.crt": proxyCACertPath}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf(diff)
+	}
+	if got := proxyVolumes(Proxy{}); len(got) != 0 {
+		t.Errorf("Want no volumes, got %v", got)
+	}
+}
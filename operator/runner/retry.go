@@ -0,0 +1,151 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package runner
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sync"
+	"time"
+
+	"github.com/drone/drone-runtime/engine"
+	"github.com/drone/drone/extension"
+)
+
+// retryEngine is an engine decorator that re-executes steps
+// that exit with a non-zero exit code, up to the retry limit
+// defined in the yaml. The step container is restarted and the
+// logs for each attempt are appended to the step log stream.
+type retryEngine struct {
+	engine.Engine
+
+	sync.Mutex
+	retries  map[string]extension.Retries
+	streams  map[string]*retryStream
+	attempts map[string]int
+}
+
+// retryStream tracks the log stream of a retried step.
+type retryStream struct {
+	writer  *io.PipeWriter
+	written int64
+	done    chan struct{}
+}
+
+func newRetryEngine(e engine.Engine, retries map[string]extension.Retries) *retryEngine {
+	return &retryEngine{
+		Engine:   e,
+		retries:  retries,
+		streams:  map[string]*retryStream{},
+		attempts: map[string]int{},
+	}
+}
+
+// Tail returns the step logs. If the step can be retried, the
+// logs are proxied so that the logs for all attempts are
+// written to a single stream.
+func (e *retryEngine) Tail(ctx context.Context, spec *engine.Spec, step *engine.Step) (io.ReadCloser, error) {
+	if e.retries[step.Metadata.Name].Limit <= 0 {
+		return e.Engine.Tail(ctx, spec, step)
+	}
+	rc, err := e.Engine.Tail(ctx, spec, step)
+	if err != nil {
+		return nil, err
+	}
+	pr, pw := io.Pipe()
+	stream := &retryStream{writer: pw}
+	stream.copy(rc, 0)
+
+	e.Lock()
+	e.streams[step.Metadata.Name] = stream
+	e.Unlock()
+	return pr, nil
+}
+
+// Wait blocks until the step exits. If the step exits with a
+// non-zero exit code, and the retry limit is not exceeded, the
+// step is restarted after the backoff period.
+func (e *retryEngine) Wait(ctx context.Context, spec *engine.Spec, step *engine.Step) (*engine.State, error) {
+	name := step.Metadata.Name
+	retries := e.retries[name]
+
+	e.Lock()
+	stream := e.streams[name]
+	e.Unlock()
+
+	for attempt := 1; ; attempt++ {
+		e.Lock()
+		e.attempts[name] = attempt
+		e.Unlock()
+
+		state, err := e.Engine.Wait(ctx, spec, step)
+		if err != nil {
+			if stream != nil {
+				stream.writer.Close()
+			}
+			return state, err
+		}
+		if state.ExitCode == 0 || state.OOMKilled ||
+			attempt > retries.Limit || stream == nil {
+			if stream != nil {
+				<-stream.done
+				stream.writer.Close()
+			}
+			return state, nil
+		}
+
+		// wait for the logs from the previous attempt to be
+		// fully written to the stream before restarting.
+		<-stream.done
+
+		fmt.Fprintf(stream.writer, "+ step exited with code %d, retrying in %s (attempt %d of %d)\n",
+			state.ExitCode, retries.Backoff, attempt+1, retries.Limit+1)
+
+		select {
+		case <-ctx.Done():
+			stream.writer.Close()
+			return nil, ctx.Err()
+		case <-time.After(retries.Backoff):
+		}
+
+		err = e.Engine.Start(ctx, spec, step)
+		if err != nil {
+			stream.writer.Close()
+			return nil, err
+		}
+		rc, err := e.Engine.Tail(ctx, spec, step)
+		if err != nil {
+			stream.writer.Close()
+			return nil, err
+		}
+		// the container logs include the output of previous
+		// attempts, which have already been written to the
+		// stream and are therefore skipped.
+		stream.copy(rc, stream.written)
+	}
+}
+
+// Attempts returns the number of times the named step was
+// executed.
+func (e *retryEngine) Attempts(name string) int {
+	e.Lock()
+	defer e.Unlock()
+	return e.attempts[name]
+}
+
+// helper function copies the logs to the stream in a
+// goroutine, skipping the first n bytes.
+func (s *retryStream) copy(rc io.ReadCloser, n int64) {
+	s.done = make(chan struct{})
+	go func() {
+		defer close(s.done)
+		defer rc.Close()
+		skipped, _ := io.CopyN(ioutil.Discard, rc, n)
+		written, _ := io.Copy(s.writer, rc)
+		s.written = skipped + written
+	}()
+}
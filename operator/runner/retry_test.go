@@ -0,0 +1,145 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package runner
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/drone/drone-runtime/engine"
+	"github.com/drone/drone/extension"
+)
+
+func TestRetryEngine(t *testing.T) {
+	step := &engine.Step{
+		Metadata: engine.Metadata{Name: "test"},
+	}
+	flaky := &flakyEngine{failures: 1}
+	e := newRetryEngine(flaky, map[string]extension.Retries{
+		"test": {Limit: 2},
+	})
+
+	rc, err := e.Tail(noContext, nil, step)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	logs := new(bytes.Buffer)
+	done := make(chan struct{})
+	go func() {
+		io.Copy(logs, rc)
+		close(done)
+	}()
+
+	state, err := e.Wait(noContext, nil, step)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	<-done
+
+	if got, want := state.ExitCode, 0; got != want {
+		t.Errorf("Want exit code %d, got %d", want, got)
+	}
+	if got, want := e.Attempts("test"), 2; got != want {
+		t.Errorf("Want %d attempts, got %d", want, got)
+	}
+	if got, want := flaky.started, 1; got != want {
+		t.Errorf("Want step restarted %d times, got %d", want, got)
+	}
+	if got, want := strings.Count(logs.String(), "attempt 1\n"), 1; got != want {
+		t.Errorf("Want logs from previous attempts written once, got %d", got)
+	}
+	if !strings.Contains(logs.String(), "attempt 2\n") {
+		t.Errorf("Want logs from the second attempt")
+	}
+}
+
+func TestRetryEngine_LimitExceeded(t *testing.T) {
+	step := &engine.Step{
+		Metadata: engine.Metadata{Name: "test"},
+	}
+	flaky := &flakyEngine{failures: 5}
+	e := newRetryEngine(flaky, map[string]extension.Retries{
+		"test": {Limit: 2},
+	})
+
+	rc, err := e.Tail(noContext, nil, step)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	go io.Copy(ioutil.Discard, rc)
+
+	state, err := e.Wait(noContext, nil, step)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if got, want := state.ExitCode, 1; got != want {
+		t.Errorf("Want exit code %d, got %d", want, got)
+	}
+	if got, want := e.Attempts("test"), 3; got != want {
+		t.Errorf("Want %d attempts, got %d", want, got)
+	}
+}
+
+func TestRetryEngine_NoRetries(t *testing.T) {
+	step := &engine.Step{
+		Metadata: engine.Metadata{Name: "test"},
+	}
+	flaky := &flakyEngine{failures: 1}
+	e := newRetryEngine(flaky, map[string]extension.Retries{})
+
+	state, err := e.Wait(noContext, nil, step)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if got, want := state.ExitCode, 1; got != want {
+		t.Errorf("Want exit code %d, got %d", want, got)
+	}
+	if got, want := e.Attempts("test"), 1; got != want {
+		t.Errorf("Want %d attempts, got %d", want, got)
+	}
+}
+
+var noContext = context.Background()
+
+// flakyEngine is a stub engine where the step fails until
+// the number of failures is exhausted. Similar to a restarted
+// container, the logs include the output of all attempts.
+type flakyEngine struct {
+	engine.Engine
+	failures int
+	started  int
+	attempts int
+}
+
+func (e *flakyEngine) Start(ctx context.Context, spec *engine.Spec, step *engine.Step) error {
+	e.started++
+	return nil
+}
+
+func (e *flakyEngine) Tail(ctx context.Context, spec *engine.Spec, step *engine.Step) (io.ReadCloser, error) {
+	buf := new(bytes.Buffer)
+	for i := 0; i <= e.started; i++ {
+		fmt.Fprintf(buf, "attempt %d\n", i+1)
+	}
+	return ioutil.NopCloser(buf), nil
+}
+
+func (e *flakyEngine) Wait(ctx context.Context, spec *engine.Spec, step *engine.Step) (*engine.State, error) {
+	e.attempts++
+	if e.attempts <= e.failures {
+		return &engine.State{ExitCode: 1, Exited: true}, nil
+	}
+	return &engine.State{ExitCode: 0, Exited: true}, nil
+}
@@ -9,6 +9,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"path"
 	"runtime/debug"
 	"strconv"
 	"strings"
@@ -23,6 +24,8 @@ import (
 	"github.com/drone/drone-yaml/yaml/converter"
 	"github.com/drone/drone-yaml/yaml/linter"
 	"github.com/drone/drone/core"
+	"github.com/drone/drone/extension"
+	"github.com/drone/drone/extension/expr"
 	"github.com/drone/drone/operator/manager"
 	"github.com/drone/drone/plugin/registry"
 	"github.com/drone/drone/plugin/secret"
@@ -49,10 +52,13 @@ type Runner struct {
 	sync.Mutex
 
 	Engine     engine.Engine
+	Digester   Digester
 	Manager    manager.BuildManager
 	Registry   core.RegistryService
 	Secrets    core.SecretService
 	Limits     Limits
+	Cache      Cache
+	Proxy      Proxy
 	Volumes    []string
 	Networks   []string
 	Devices    []string
@@ -76,15 +82,6 @@ func (r *Runner) handleError(ctx context.Context, stage *core.Stage, err error)
 		core.StatusRunning:
 	default:
 	}
-	for _, step := range stage.Steps {
-		if step.Status == core.StatusPending {
-			step.Status = core.StatusSkipped
-		}
-		if step.Status == core.StatusRunning {
-			step.Status = core.StatusPassing
-			step.Stopped = time.Now().Unix()
-		}
-	}
 	stage.Status = core.StatusError
 	stage.Error = err.Error()
 	stage.Stopped = time.Now().Unix()
@@ -95,6 +92,24 @@ func (r *Runner) handleError(ctx context.Context, stage *core.Stage, err error)
 		stage.ExitCode = v.Code
 	case *runtime.OomError:
 		stage.Error = "OOM kill signaled by host operating system"
+	case *setupError:
+		stage.Abandoned = true
+	}
+	for _, step := range stage.Steps {
+		if step.Status == core.StatusPending {
+			step.Status = core.StatusSkipped
+		}
+		if step.Status == core.StatusRunning {
+			step.Status = core.StatusPassing
+			step.Stopped = stage.Stopped
+			// the step was interrupted by the error (for
+			// example, the image could not be pulled) so the
+			// error details are recorded with the step.
+			if stage.Error != "" {
+				step.Status = core.StatusError
+				step.Error = stage.Error
+			}
+		}
 	}
 	return r.Manager.AfterAll(ctx, stage)
 }
@@ -155,6 +170,13 @@ func (r *Runner) Run(ctx context.Context, id int64) error {
 		return nil
 	}
 
+	// the stage timeout, if defined in the yaml, takes
+	// precedence over the repository timeout.
+	ttl := time.Duration(m.Repo.Timeout) * time.Minute
+	if m.Stage.Timeout != 0 {
+		ttl = time.Duration(m.Stage.Timeout) * time.Minute
+	}
+
 	environ := combineEnviron(
 		agentEnviron(r),
 		buildEnviron(m.Build),
@@ -162,6 +184,7 @@ func (r *Runner) Run(ctx context.Context, id int64) error {
 		systemEnviron(m.System),
 		linkEnviron(m.Repo, m.Build, m.System),
 		m.Build.Params,
+		idtokenEnviron(m.IDToken),
 	)
 
 	//
@@ -218,7 +241,33 @@ func (r *Runner) Run(ctx context.Context, id int64) error {
 
 	logger = logger.WithField("pipeline", pipeline.Name)
 
-	err = linter.Lint(pipeline, m.Repo.Trusted)
+	extensions, err := extension.ParseString(y)
+	if err != nil {
+		logger = logger.WithError(err)
+		logger.Warnln("runner: cannot parse yaml extensions")
+		return r.handleError(ctx, m.Stage, err)
+	}
+
+	timeouts := map[string]time.Duration{}
+	retries := map[string]extension.Retries{}
+	if ext := extensions.Lookup(pipeline.Name); ext != nil {
+		if ext.Clone != nil && !pipeline.Clone.Disable {
+			retries[cloneStepName] = ext.Clone.Retries
+		}
+		for _, step := range ext.Steps {
+			timeouts[step.Name] = step.Timeout
+			retries[step.Name] = step.Retries
+		}
+	}
+	// the step timeout applies to all attempts, and is
+	// therefore enforced outside of the retry policy.
+	retrier := newRetryEngine(r.Engine, retries)
+	eng := newTimeoutEngine(retrier, timeouts)
+
+	// elevated stages request settings restricted to trusted
+	// repositories, and are only executed once approved by a
+	// system administrator.
+	err = linter.Lint(pipeline, m.Repo.Trusted || m.Stage.Elevated)
 	if err != nil {
 		logger = logger.WithError(err)
 		logger.Warnln("runner: yaml lint errors")
@@ -231,13 +280,19 @@ func (r *Runner) Run(ctx context.Context, id int64) error {
 	)
 	registryService := registry.Combine(
 		registry.Static(m.Secrets),
+		registry.Stored(m.Registries),
 		r.Registry,
 	)
 
 	comp := new(compiler.Compiler)
+	// the privileged images configured for the runner are
+	// combined with the privileged images managed at runtime.
+	var privileged []string
+	privileged = append(privileged, r.Privileged...)
+	privileged = append(privileged, m.Privileged...)
 	comp.PrivilegedFunc = compiler.DindFunc(
 		append(
-			r.Privileged,
+			privileged,
 			"plugins/docker",
 			"plugins/ecr",
 			"plugins/gcr",
@@ -269,10 +324,20 @@ func (r *Runner) Run(ctx context.Context, id int64) error {
 				if err != nil {
 					return nil
 				}
-				return convertRegistry(out)
+				// registry credentials are only provided for
+				// registries that host the pipeline images.
+				return convertRegistry(
+					registry.Match(out, pipelineImages(pipeline)),
+				)
 			},
 		),
+		// the server, organization and repository variables
+		// are applied first, so that the build environment
+		// takes precedence.
+		transform.WithEnviron(m.Environ),
 		transform.WithEnviron(environ),
+		transform.WithEnviron(m.Stage.Matrix),
+		transform.WithEnviron(proxyEnviron(r.Proxy)),
 		transform.WithEnviron(r.Environ),
 		transform.WithLables(
 			map[string]string{
@@ -282,8 +347,8 @@ func (r *Runner) Run(ctx context.Context, id int64) error {
 				"io.core.repo.name":      m.Repo.Name,
 				"io.core.stage.name":     m.Stage.Name,
 				"io.core.stage.number":   fmt.Sprint(m.Stage.Number),
-				"io.core.ttl":            fmt.Sprint(time.Duration(m.Repo.Timeout) * time.Minute),
-				"io.core.expires":        fmt.Sprint(time.Now().Add(time.Duration(m.Repo.Timeout)*time.Minute + time.Hour).Unix()),
+				"io.core.ttl":            fmt.Sprint(ttl),
+				"io.core.expires":        fmt.Sprint(time.Now().Add(ttl + time.Hour).Unix()),
 				"io.core.created":        fmt.Sprint(time.Now().Unix()),
 				"io.core.protected":      "false",
 			},
@@ -323,9 +388,48 @@ func (r *Runner) Run(ctx context.Context, id int64) error {
 		transform.WithVolumes(
 			convertVolumes(r.Volumes),
 		),
+		transform.WithVolumes(
+			proxyVolumes(r.Proxy),
+		),
 	)
 	ir := comp.Compile(pipeline)
 
+	// the clone step is configured with the extended clone
+	// attributes, unless the pipeline disables the clone step
+	// and provides its own.
+	if !pipeline.Clone.Disable {
+		applyClone(ir, extensions.Lookup(pipeline.Name))
+	}
+
+	// steps configured to ignore failures do not fail the
+	// pipeline, however, the step is still marked as failed.
+	if ext := extensions.Lookup(pipeline.Name); ext != nil {
+		for _, s := range ir.Steps {
+			if step := ext.Step(s.Metadata.Name); step != nil && step.IgnoreFailure() {
+				s.IgnoreErr = true
+			}
+		}
+	}
+
+	// steps with a when expression that does not match the
+	// build are skipped.
+	err = applyConditions(ir, extensions.Lookup(pipeline.Name),
+		expr.Vars(m.Repo, m.Build, m.System.Host))
+	if err != nil {
+		logger = logger.WithError(err)
+		logger.Warnln("runner: cannot parse when expression")
+		return r.handleError(ctx, m.Stage, err)
+	}
+
+	// the pipeline cache is restored after the clone step and
+	// rebuilt after the pipeline steps complete.
+	applyCache(ir, extensions.Lookup(pipeline.Name), r.Cache,
+		path.Join(m.Repo.Slug, pipeline.Name))
+
+	// services that define a health check must be healthy
+	// before the pipeline steps are started.
+	applyHealthchecks(ir, extensions.Lookup(pipeline.Name))
+
 	steps := map[string]*core.Step{}
 	i := 0
 	for _, s := range ir.Steps {
@@ -395,15 +499,22 @@ func (r *Runner) Run(ctx context.Context, id int64) error {
 		},
 
 		AfterEach: func(s *runtime.State) error {
+			digest := r.digest(ctx, s.Step)
+
 			r.Lock()
 			step, ok := steps[s.Step.Metadata.Name]
 			if ok {
 				step.Status = core.StatusPassing
 				step.Stopped = time.Now().Unix()
+				step.Digest = digest
 				if s.State.ExitCode != 0 {
 					step.ExitCode = s.State.ExitCode
 					step.Status = core.StatusFailing
 				}
+				step.Attempts = retrier.Attempts(step.Name)
+				if eng.Expired(step.Name) {
+					step.Error = fmt.Sprintf("Step exceeded the timeout of %s", timeouts[step.Name])
+				}
 			}
 			stepClone := new(core.Step)
 			*stepClone = *step
@@ -448,7 +559,7 @@ func (r *Runner) Run(ctx context.Context, id int64) error {
 	}
 
 	runner := runtime.New(
-		runtime.WithEngine(r.Engine),
+		runtime.WithEngine(newSetupEngine(eng)),
 		runtime.WithConfig(ir),
 		runtime.WithHooks(hooks),
 	)
@@ -463,7 +574,7 @@ func (r *Runner) Run(ctx context.Context, id int64) error {
 		return r.handleError(ctx, m.Stage, err)
 	}
 
-	timeout, cancel := context.WithTimeout(ctx, time.Duration(m.Repo.Timeout)*time.Minute)
+	timeout, cancel := context.WithTimeout(ctx, ttl)
 	defer cancel()
 
 	logger.Infoln("runner: start execution")
@@ -492,6 +603,22 @@ func (r *Runner) Run(ctx context.Context, id int64) error {
 	return r.Manager.AfterAll(ctx, m.Stage)
 }
 
+// helper function returns the digest of the image used to
+// execute the step. An empty string is returned if the image
+// digest cannot be resolved.
+func (r *Runner) digest(ctx context.Context, step *engine.Step) string {
+	if r.Digester == nil || step.Docker == nil {
+		return ""
+	}
+	digest, err := r.Digester.Digest(ctx, step.Docker.Image)
+	if err != nil {
+		logrus.WithError(err).
+			WithField("image", step.Docker.Image).
+			Debugln("runner: cannot resolve image digest")
+	}
+	return digest
+}
+
 // Start starts N build runner processes. Each process polls
 // the server for pednding builds to execute.
 func (r *Runner) Start(ctx context.Context, n int) error {
@@ -537,6 +664,7 @@ func (r *Runner) poll(ctx context.Context) error {
 		Kernel:  r.Kernel,
 		Variant: r.Variant,
 		Labels:  r.Labels,
+		Machine: r.Machine,
 	})
 	if err != nil {
 		logger = logger.WithError(err)
@@ -550,7 +678,7 @@ func (r *Runner) poll(ctx context.Context) error {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	err = r.Manager.Accept(ctx, p.ID, r.Machine)
+	accepted, err := r.Manager.Accept(ctx, p.ID, r.Machine)
 	if err == db.ErrOptimisticLock {
 		return nil
 	} else if err != nil {
@@ -562,6 +690,11 @@ func (r *Runner) poll(ctx context.Context) error {
 			}).Warnln("runner: cannot ack stage")
 		return err
 	}
+	// the server accepts the next eligible stage if the
+	// stage was accepted by another agent.
+	if accepted != nil {
+		p = accepted
+	}
 
 	go func() {
 		logger.Debugln("runner: watch for cancel signal")
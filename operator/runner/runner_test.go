@@ -5,7 +5,15 @@
 package runner
 
 import (
+	"context"
+	"errors"
 	"io/ioutil"
+	"testing"
+
+	"github.com/drone/drone-runtime/engine"
+	"github.com/drone/drone-runtime/runtime"
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/operator/manager"
 
 	"github.com/sirupsen/logrus"
 )
@@ -13,3 +21,133 @@ import (
 func init() {
 	logrus.SetOutput(ioutil.Discard)
 }
+
+// this test verifies that the error is recorded with the
+// stage and with any steps interrupted by the error.
+func TestHandleError(t *testing.T) {
+	stage := &core.Stage{
+		Status: core.StatusRunning,
+		Steps: []*core.Step{
+			{Name: "clone", Status: core.StatusPassing},
+			{Name: "build", Status: core.StatusRunning},
+			{Name: "test", Status: core.StatusPending},
+		},
+	}
+
+	manager := new(fakeManager)
+	runner := &Runner{Manager: manager}
+	err := runner.handleError(noContext, stage, errors.New("image pull backoff: golang:latest"))
+	if err != nil {
+		t.Error(err)
+	}
+	if manager.stage != stage {
+		t.Errorf("Expect stage passed to AfterAll")
+	}
+	if got, want := stage.Status, core.StatusError; got != want {
+		t.Errorf("Want stage status %s, got %s", want, got)
+	}
+	if got, want := stage.Error, "image pull backoff: golang:latest"; got != want {
+		t.Errorf("Want stage error %q, got %q", want, got)
+	}
+	if got, want := stage.Steps[0].Error, ""; got != want {
+		t.Errorf("Want no error for completed step, got %q", got)
+	}
+	if got, want := stage.Steps[1].Status, core.StatusError; got != want {
+		t.Errorf("Want step status %s, got %s", want, got)
+	}
+	if got, want := stage.Steps[1].Error, stage.Error; got != want {
+		t.Errorf("Want step error %q, got %q", want, got)
+	}
+	if got, want := stage.Steps[2].Status, core.StatusSkipped; got != want {
+		t.Errorf("Want step status %s, got %s", want, got)
+	}
+}
+
+// this test verifies that no error message is recorded when
+// the stage fails with a non-zero exit code.
+func TestHandleError_ExitError(t *testing.T) {
+	stage := &core.Stage{
+		Status: core.StatusRunning,
+		Steps: []*core.Step{
+			{Name: "build", Status: core.StatusRunning},
+		},
+	}
+
+	runner := &Runner{Manager: new(fakeManager)}
+	runner.handleError(noContext, stage, &runtime.ExitError{Name: "build", Code: 2})
+	if got, want := stage.Status, core.StatusFailing; got != want {
+		t.Errorf("Want stage status %s, got %s", want, got)
+	}
+	if got, want := stage.ExitCode, 2; got != want {
+		t.Errorf("Want exit code %d, got %d", want, got)
+	}
+	if stage.Error != "" || stage.Steps[0].Error != "" {
+		t.Errorf("Want no error message for exit errors")
+	}
+}
+
+// this test verifies that the stage is abandoned when the
+// pipeline environment cannot be created.
+func TestHandleError_SetupError(t *testing.T) {
+	stage := &core.Stage{
+		Status: core.StatusRunning,
+		Steps: []*core.Step{
+			{Name: "build", Status: core.StatusPending},
+		},
+	}
+
+	runner := &Runner{Manager: new(fakeManager)}
+	runner.handleError(noContext, stage, &setupError{errors.New("cannot create network")})
+	if got, want := stage.Status, core.StatusError; got != want {
+		t.Errorf("Want stage status %s, got %s", want, got)
+	}
+	if !stage.Abandoned {
+		t.Errorf("Expect stage abandoned")
+	}
+	if got, want := stage.Error, "cannot create network"; got != want {
+		t.Errorf("Want stage error %q, got %q", want, got)
+	}
+}
+
+// fakeManager captures the stage passed to AfterAll.
+type fakeManager struct {
+	manager.BuildManager
+	stage *core.Stage
+}
+
+func (m *fakeManager) AfterAll(_ context.Context, stage *core.Stage) error {
+	m.stage = stage
+	return nil
+}
+
+func TestDigest(t *testing.T) {
+	step := &engine.Step{
+		Docker: &engine.DockerStep{Image: "golang:1.12"},
+	}
+
+	r := new(Runner)
+	if got := r.digest(noContext, step); got != "" {
+		t.Errorf("Want empty digest when digester is not configured, got %q", got)
+	}
+
+	r.Digester = fakeDigester{"golang:1.12": "golang@sha256:3e8d"}
+	if got, want := r.digest(noContext, step), "golang@sha256:3e8d"; got != want {
+		t.Errorf("Want digest %q, got %q", want, got)
+	}
+
+	step.Docker.Image = "alpine:3.9"
+	if got := r.digest(noContext, step); got != "" {
+		t.Errorf("Want empty digest when image cannot be resolved, got %q", got)
+	}
+}
+
+// fakeDigester resolves image digests from a map.
+type fakeDigester map[string]string
+
+func (d fakeDigester) Digest(_ context.Context, image string) (string, error) {
+	digest, ok := d[image]
+	if !ok {
+		return "", errors.New("not found")
+	}
+	return digest, nil
+}
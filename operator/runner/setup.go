@@ -0,0 +1,43 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package runner
+
+import (
+	"context"
+
+	"github.com/drone/drone-runtime/engine"
+)
+
+// setupError is returned when the engine cannot create the
+// pipeline environment, for example because the docker daemon
+// is unavailable. This indicates an agent or infrastructure
+// failure, in which case the stage is abandoned and can be
+// returned to the queue.
+type setupError struct {
+	err error
+}
+
+func (e *setupError) Error() string {
+	return e.err.Error()
+}
+
+// setupEngine is an engine decorator that wraps errors that
+// occur when creating the pipeline environment.
+type setupEngine struct {
+	engine.Engine
+}
+
+func newSetupEngine(e engine.Engine) *setupEngine {
+	return &setupEngine{Engine: e}
+}
+
+// Setup creates the pipeline environment.
+func (e *setupEngine) Setup(ctx context.Context, spec *engine.Spec) error {
+	err := e.Engine.Setup(ctx, spec)
+	if err != nil {
+		return &setupError{err}
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package runner
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/drone/drone-runtime/engine"
+)
+
+func TestSetupEngine(t *testing.T) {
+	e := newSetupEngine(&failingEngine{err: errors.New("cannot connect to the docker daemon")})
+	err := e.Setup(context.Background(), nil)
+	if _, ok := err.(*setupError); !ok {
+		t.Errorf("Expect setup error, got %v", err)
+	}
+	if got, want := err.Error(), "cannot connect to the docker daemon"; got != want {
+		t.Errorf("Want error message %q, got %q", want, got)
+	}
+}
+
+func TestSetupEngine_NoError(t *testing.T) {
+	e := newSetupEngine(new(failingEngine))
+	if err := e.Setup(context.Background(), nil); err != nil {
+		t.Errorf("Expect no error, got %v", err)
+	}
+}
+
+// failingEngine is a stub engine where the environment
+// cannot be created.
+type failingEngine struct {
+	engine.Engine
+	err error
+}
+
+func (e *failingEngine) Setup(ctx context.Context, spec *engine.Spec) error {
+	return e.err
+}
@@ -0,0 +1,104 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package runner
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/drone/drone-runtime/engine"
+)
+
+// timeoutExitCode is the exit code reported for a step that
+// exceeds its timeout. This matches the exit code used by
+// the gnu timeout utility.
+const timeoutExitCode = 124
+
+// timeoutEngine is an engine decorator that enforces the
+// step timeout. When a step exceeds its timeout the step
+// container is destroyed, and the step is reported as exited
+// with a non-zero exit code.
+type timeoutEngine struct {
+	engine.Engine
+
+	sync.Mutex
+	timeouts map[string]time.Duration
+	tails    map[string]io.ReadCloser
+	expired  map[string]bool
+}
+
+func newTimeoutEngine(e engine.Engine, timeouts map[string]time.Duration) *timeoutEngine {
+	return &timeoutEngine{
+		Engine:   e,
+		timeouts: timeouts,
+		tails:    map[string]io.ReadCloser{},
+		expired:  map[string]bool{},
+	}
+}
+
+// Tail returns the step logs. The log stream is tracked so
+// that it can be closed if the step exceeds its timeout.
+func (e *timeoutEngine) Tail(ctx context.Context, spec *engine.Spec, step *engine.Step) (io.ReadCloser, error) {
+	rc, err := e.Engine.Tail(ctx, spec, step)
+	if err != nil {
+		return rc, err
+	}
+	e.Lock()
+	e.tails[step.Metadata.Name] = rc
+	e.Unlock()
+	return rc, nil
+}
+
+// Wait blocks until the step exits or exceeds its timeout.
+func (e *timeoutEngine) Wait(ctx context.Context, spec *engine.Spec, step *engine.Step) (*engine.State, error) {
+	timeout := e.timeouts[step.Metadata.Name]
+	if timeout <= 0 {
+		return e.Engine.Wait(ctx, spec, step)
+	}
+
+	waitctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	state, err := e.Engine.Wait(waitctx, spec, step)
+	// if the parent context is done the pipeline was cancelled
+	// or exceeded the stage timeout, in which case the error is
+	// returned to the caller as-is.
+	if waitctx.Err() != context.DeadlineExceeded || ctx.Err() != nil {
+		return state, err
+	}
+
+	e.Lock()
+	e.expired[step.Metadata.Name] = true
+	if rc, ok := e.tails[step.Metadata.Name]; ok {
+		rc.Close()
+	}
+	e.Unlock()
+
+	// the engine does not provide a method to stop a single
+	// step, so the step is destroyed using a specification
+	// that only includes the expired step. The pipeline
+	// identifier and volumes are omitted so that the pipeline
+	// network and volumes are not removed.
+	e.Engine.Destroy(context.Background(), &engine.Spec{
+		Metadata: engine.Metadata{
+			Namespace: spec.Metadata.Namespace,
+		},
+		Steps: []*engine.Step{step},
+	})
+
+	return &engine.State{
+		ExitCode: timeoutExitCode,
+		Exited:   true,
+	}, nil
+}
+
+// Expired returns true if the named step exceeded its timeout.
+func (e *timeoutEngine) Expired(name string) bool {
+	e.Lock()
+	defer e.Unlock()
+	return e.expired[name]
+}
@@ -0,0 +1,91 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package runner
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/drone/drone-runtime/engine"
+)
+
+func TestTimeoutEngine(t *testing.T) {
+	step := &engine.Step{
+		Metadata: engine.Metadata{Name: "test"},
+	}
+	spec := &engine.Spec{
+		Metadata: engine.Metadata{UID: "uid_1", Namespace: "ns_1"},
+	}
+	hanging := new(hangingEngine)
+	e := newTimeoutEngine(hanging, map[string]time.Duration{
+		"test": time.Millisecond,
+	})
+	state, err := e.Wait(context.Background(), spec, step)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if got, want := state.ExitCode, timeoutExitCode; got != want {
+		t.Errorf("Want exit code %d, got %d", want, got)
+	}
+	if !e.Expired("test") {
+		t.Errorf("Expect step expired")
+	}
+
+	// the expired step is destroyed without destroying
+	// the pipeline network.
+	if hanging.destroyed == nil {
+		t.Errorf("Expect expired step destroyed")
+		return
+	}
+	if got, want := len(hanging.destroyed.Steps), 1; got != want {
+		t.Errorf("Want %d destroyed steps, got %d", want, got)
+	} else if hanging.destroyed.Steps[0] != step {
+		t.Errorf("Expect expired step destroyed")
+	}
+	if hanging.destroyed.Metadata.UID != "" {
+		t.Errorf("Expect pipeline network not destroyed")
+	}
+}
+
+func TestTimeoutEngine_Cancel(t *testing.T) {
+	step := &engine.Step{
+		Metadata: engine.Metadata{Name: "test"},
+	}
+	hanging := new(hangingEngine)
+	e := newTimeoutEngine(hanging, map[string]time.Duration{
+		"test": time.Hour,
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	_, err := e.Wait(ctx, nil, step)
+	if err != context.DeadlineExceeded {
+		t.Errorf("Expect context error, got %v", err)
+	}
+	if e.Expired("test") {
+		t.Errorf("Expect step not expired when the stage is cancelled")
+	}
+	if hanging.destroyed != nil {
+		t.Errorf("Expect step not destroyed when the stage is cancelled")
+	}
+}
+
+// hangingEngine is a stub engine where the step never exits.
+// The destroyed specification is recorded.
+type hangingEngine struct {
+	engine.Engine
+	destroyed *engine.Spec
+}
+
+func (e *hangingEngine) Destroy(ctx context.Context, spec *engine.Spec) error {
+	e.destroyed = spec
+	return nil
+}
+
+func (e *hangingEngine) Wait(ctx context.Context, spec *engine.Spec, step *engine.Step) (*engine.State, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
@@ -0,0 +1,303 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package watchdog
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/operator/manager"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/sirupsen/logrus"
+)
+
+// grace is the additional time a stage is allowed to run
+// beyond its timeout. The agent enforces the timeout and
+// should always report the stage status first; the watchdog
+// only takes action if the agent is unresponsive.
+var grace = time.Minute * 5
+
+// New returns a new Watchdog.
+func New(
+	builds core.BuildStore,
+	manager manager.BuildManager,
+	repos core.RepositoryStore,
+	stages core.StageStore,
+	webhook core.WebhookSender,
+) *Watchdog {
+	return &Watchdog{
+		builds:  builds,
+		manager: manager,
+		repos:   repos,
+		stages:  stages,
+		webhook: webhook,
+	}
+}
+
+// Watchdog enforces the stage timeout on the server. It
+// marks running stages as errored when the timeout is
+// exceeded, which can happen if the agent terminates
+// unexpectedly while executing the stage. It also marks
+// stages that were accepted by an agent, but never started,
+// as errored, which returns the stage to the queue until the
+// maximum number of attempts is reached.
+type Watchdog struct {
+	builds  core.BuildStore
+	manager manager.BuildManager
+	repos   core.RepositoryStore
+	stages  core.StageStore
+	webhook core.WebhookSender
+}
+
+// Start starts the watchdog.
+func (w *Watchdog) Start(ctx context.Context, dur time.Duration) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(dur):
+			w.run(ctx)
+		}
+	}
+}
+
+func (w *Watchdog) run(ctx context.Context) error {
+	var result error
+
+	logrus.Debugln("watchdog: begin processing running stages")
+
+	defer func() {
+		if err := recover(); err != nil {
+			logger := logrus.WithField("error", err)
+			logger.Errorln("watchdog: unexpected panic")
+		}
+	}()
+
+	stages, err := w.stages.ListState(ctx, core.StatusRunning)
+	if err != nil {
+		logger := logrus.WithError(err)
+		logger.Errorln("watchdog: cannot list running stages")
+		return err
+	}
+
+	now := time.Now()
+	repos := map[int64]*core.Repository{}
+	for _, stage := range stages {
+		logger := logrus.WithFields(
+			logrus.Fields{
+				"repo.id":  stage.RepoID,
+				"build.id": stage.BuildID,
+				"stage.id": stage.ID,
+			},
+		)
+
+		repo, ok := repos[stage.RepoID]
+		if !ok {
+			repo, err = w.repos.Find(ctx, stage.RepoID)
+			if err != nil {
+				logger.WithError(err).Warnln("watchdog: cannot find repository")
+				result = multierror.Append(result, err)
+				continue
+			}
+			repos[repo.ID] = repo
+		}
+
+		timeout := timeoutOf(repo, stage)
+		if timeout == 0 || stage.Started == 0 {
+			continue
+		}
+		deadline := time.Unix(stage.Started, 0).Add(timeout + grace)
+		if now.Before(deadline) {
+			continue
+		}
+
+		logger.WithField("timeout", timeout).
+			Infoln("watchdog: stage exceeded timeout")
+
+		err = w.expire(ctx, repo, stage, timeout)
+		if err != nil {
+			logger.WithError(err).Warnln("watchdog: cannot expire stage")
+			result = multierror.Append(result, err)
+		}
+	}
+
+	logrus.Debugln("watchdog: finished processing running stages")
+
+	if err := w.runAccepted(ctx); err != nil {
+		result = multierror.Append(result, err)
+	}
+	return result
+}
+
+// runAccepted processes stages that were accepted by an agent
+// but did not start within the grace period.
+func (w *Watchdog) runAccepted(ctx context.Context) error {
+	var result error
+
+	logrus.Debugln("watchdog: begin processing accepted stages")
+
+	stages, err := w.stages.ListState(ctx, core.StatusPending)
+	if err != nil {
+		logger := logrus.WithError(err)
+		logger.Errorln("watchdog: cannot list pending stages")
+		return err
+	}
+
+	now := time.Now()
+	for _, stage := range stages {
+		if stage.Machine == "" || stage.Accepted == 0 || stage.Started != 0 {
+			continue
+		}
+		deadline := time.Unix(stage.Accepted, 0).Add(grace)
+		if now.Before(deadline) {
+			continue
+		}
+
+		logger := logrus.WithFields(
+			logrus.Fields{
+				"repo.id":  stage.RepoID,
+				"build.id": stage.BuildID,
+				"stage.id": stage.ID,
+				"machine":  stage.Machine,
+			},
+		)
+		logger.Infoln("watchdog: stage accepted but not started")
+
+		repo, err := w.repos.Find(ctx, stage.RepoID)
+		if err != nil {
+			logger.WithError(err).Warnln("watchdog: cannot find repository")
+			result = multierror.Append(result, err)
+			continue
+		}
+		err = w.abandon(ctx, repo, stage)
+		if err != nil {
+			logger.WithError(err).Warnln("watchdog: cannot abandon stage")
+			result = multierror.Append(result, err)
+		}
+	}
+
+	logrus.Debugln("watchdog: finished processing accepted stages")
+	return result
+}
+
+// helper function marks the stage and any incomplete steps
+// as errored, and finalizes the build.
+func (w *Watchdog) expire(ctx context.Context, repo *core.Repository, stage *core.Stage, timeout time.Duration) error {
+	// the stage is re-fetched with its steps included,
+	// which are required to finalize the stage.
+	stages, err := w.stages.ListSteps(ctx, stage.BuildID)
+	if err != nil {
+		return err
+	}
+	for _, s := range stages {
+		if s.ID == stage.ID {
+			stage = s
+			break
+		}
+	}
+	// the stage may have completed since the stages were
+	// listed, in which case no action is required.
+	if stage.Status != core.StatusRunning {
+		return nil
+	}
+
+	now := time.Now().Unix()
+	stage.Status = core.StatusError
+	stage.Error = fmt.Sprintf("Stage exceeded the timeout of %s", timeout)
+	stage.Stopped = now
+	for _, step := range stage.Steps {
+		switch step.Status {
+		case core.StatusPending:
+			step.Status = core.StatusSkipped
+			step.Started = now
+			step.Stopped = now
+		case core.StatusRunning:
+			step.Status = core.StatusError
+			step.Error = stage.Error
+			step.Stopped = now
+		}
+	}
+
+	err = w.manager.AfterAll(ctx, stage)
+	if err != nil {
+		return err
+	}
+	return w.notify(ctx, repo, stage.BuildID)
+}
+
+// helper function marks a stage that was accepted but never
+// started as errored. The stage is returned to the queue, or
+// marked as errored if the maximum number of attempts is
+// reached.
+func (w *Watchdog) abandon(ctx context.Context, repo *core.Repository, stage *core.Stage) error {
+	// the stage is re-fetched with its steps included,
+	// which are required to reset or finalize the stage.
+	stages, err := w.stages.ListSteps(ctx, stage.BuildID)
+	if err != nil {
+		return err
+	}
+	for _, s := range stages {
+		if s.ID == stage.ID {
+			stage = s
+			break
+		}
+	}
+	// the stage may have started since the stages were
+	// listed, in which case no action is required.
+	if stage.Status != core.StatusPending || stage.Started != 0 || stage.Machine == "" {
+		return nil
+	}
+
+	stage.Status = core.StatusError
+	stage.Error = fmt.Sprintf("Stage was accepted by %s but did not start", stage.Machine)
+	stage.Stopped = time.Now().Unix()
+	stage.Abandoned = true
+
+	err = w.manager.AfterAll(ctx, stage)
+	if err != nil {
+		return err
+	}
+	// the stage is returned to the queue if the maximum
+	// number of attempts is not reached, in which case the
+	// build is not updated.
+	if stage.Status == core.StatusPending {
+		return nil
+	}
+	return w.notify(ctx, repo, stage.BuildID)
+}
+
+// helper function sends the build updated webhook.
+func (w *Watchdog) notify(ctx context.Context, repo *core.Repository, id int64) error {
+	build, err := w.builds.Find(ctx, id)
+	if err != nil {
+		return err
+	}
+	stages, err := w.stages.ListSteps(ctx, build.ID)
+	if err != nil {
+		return err
+	}
+
+	repo.Build = build
+	repo.Build.Stages = stages
+	return w.webhook.Send(ctx, &core.WebhookData{
+		Event:  core.WebhookEventBuild,
+		Action: core.WebhookActionUpdated,
+		Repo:   repo,
+		Build:  build,
+	})
+}
+
+// helper function returns the stage timeout. The stage
+// timeout, if defined in the yaml, takes precedence over
+// the repository timeout.
+func timeoutOf(repo *core.Repository, stage *core.Stage) time.Duration {
+	if stage.Timeout != 0 {
+		return time.Duration(stage.Timeout) * time.Minute
+	}
+	return time.Duration(repo.Timeout) * time.Minute
+}
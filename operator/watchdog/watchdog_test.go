@@ -0,0 +1,212 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package watchdog
+
+import (
+	"context"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/mock"
+	"github.com/drone/drone/operator/manager"
+
+	"github.com/golang/mock/gomock"
+	"github.com/sirupsen/logrus"
+)
+
+var noContext = context.Background()
+
+func init() {
+	logrus.SetOutput(ioutil.Discard)
+}
+
+func TestRun(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	started := time.Now().Add(-2 * time.Hour).Unix()
+
+	mockRepo := &core.Repository{ID: 1, Timeout: 60}
+	mockBuild := &core.Build{ID: 2, RepoID: 1, Status: core.StatusError}
+	mockStage := &core.Stage{ID: 3, RepoID: 1, BuildID: 2, Status: core.StatusRunning, Started: started}
+	mockStages := []*core.Stage{
+		{
+			ID:      3,
+			RepoID:  1,
+			BuildID: 2,
+			Status:  core.StatusRunning,
+			Started: started,
+			Steps: []*core.Step{
+				{ID: 4, Status: core.StatusPassing},
+				{ID: 5, Status: core.StatusRunning},
+				{ID: 6, Status: core.StatusPending},
+			},
+		},
+	}
+
+	stages := mock.NewMockStageStore(controller)
+	stages.EXPECT().ListState(gomock.Any(), core.StatusRunning).Return([]*core.Stage{mockStage}, nil)
+	stages.EXPECT().ListState(gomock.Any(), core.StatusPending).Return(nil, nil)
+	stages.EXPECT().ListSteps(gomock.Any(), mockStage.BuildID).Return(mockStages, nil).Times(2)
+
+	repos := mock.NewMockRepositoryStore(controller)
+	repos.EXPECT().Find(gomock.Any(), mockRepo.ID).Return(mockRepo, nil)
+
+	builds := mock.NewMockBuildStore(controller)
+	builds.EXPECT().Find(gomock.Any(), mockBuild.ID).Return(mockBuild, nil)
+
+	webhook := mock.NewMockWebhookSender(controller)
+	webhook.EXPECT().Send(gomock.Any(), gomock.Any()).Return(nil)
+
+	manager := new(mockManager)
+
+	w := New(builds, manager, repos, stages, webhook)
+	err := w.run(noContext)
+	if err != nil {
+		t.Error(err)
+	}
+
+	stage := manager.stage
+	if stage == nil {
+		t.Errorf("Expect stage finalized")
+		return
+	}
+	if got, want := stage.Status, core.StatusError; got != want {
+		t.Errorf("Want stage status %s, got %s", want, got)
+	}
+	if stage.Error == "" {
+		t.Errorf("Expect stage error message")
+	}
+	if got, want := stage.Steps[0].Status, core.StatusPassing; got != want {
+		t.Errorf("Want step status %s, got %s", want, got)
+	}
+	if got, want := stage.Steps[1].Status, core.StatusError; got != want {
+		t.Errorf("Want step status %s, got %s", want, got)
+	}
+	if got, want := stage.Steps[2].Status, core.StatusSkipped; got != want {
+		t.Errorf("Want step status %s, got %s", want, got)
+	}
+}
+
+func TestRun_WithinTimeout(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockRepo := &core.Repository{ID: 1, Timeout: 60}
+	mockStage := &core.Stage{
+		ID:      3,
+		RepoID:  1,
+		BuildID: 2,
+		Status:  core.StatusRunning,
+		Started: time.Now().Add(-30 * time.Minute).Unix(),
+	}
+
+	stages := mock.NewMockStageStore(controller)
+	stages.EXPECT().ListState(gomock.Any(), core.StatusRunning).Return([]*core.Stage{mockStage}, nil)
+	stages.EXPECT().ListState(gomock.Any(), core.StatusPending).Return(nil, nil)
+
+	repos := mock.NewMockRepositoryStore(controller)
+	repos.EXPECT().Find(gomock.Any(), mockRepo.ID).Return(mockRepo, nil)
+
+	manager := new(mockManager)
+
+	w := New(nil, manager, repos, stages, nil)
+	err := w.run(noContext)
+	if err != nil {
+		t.Error(err)
+	}
+	if manager.stage != nil {
+		t.Errorf("Expect stage within timeout is not finalized")
+	}
+}
+
+// this test verifies that a stage accepted by an agent, but
+// not started within the grace period, is marked as errored.
+func TestRun_Accepted(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	accepted := time.Now().Add(-time.Hour).Unix()
+
+	mockRepo := &core.Repository{ID: 1, Timeout: 60}
+	mockBuild := &core.Build{ID: 2, RepoID: 1, Status: core.StatusError}
+	mockStage := &core.Stage{ID: 3, RepoID: 1, BuildID: 2, Status: core.StatusPending, Machine: "agent1", Accepted: accepted}
+	mockStages := []*core.Stage{
+		{
+			ID:       3,
+			RepoID:   1,
+			BuildID:  2,
+			Status:   core.StatusPending,
+			Machine:  "agent1",
+			Accepted: accepted,
+			Steps: []*core.Step{
+				{ID: 4, Status: core.StatusPending},
+			},
+		},
+	}
+
+	stages := mock.NewMockStageStore(controller)
+	stages.EXPECT().ListState(gomock.Any(), core.StatusRunning).Return(nil, nil)
+	stages.EXPECT().ListState(gomock.Any(), core.StatusPending).Return([]*core.Stage{mockStage, {ID: 4, Status: core.StatusPending}}, nil)
+	stages.EXPECT().ListSteps(gomock.Any(), mockStage.BuildID).Return(mockStages, nil).Times(2)
+
+	repos := mock.NewMockRepositoryStore(controller)
+	repos.EXPECT().Find(gomock.Any(), mockRepo.ID).Return(mockRepo, nil)
+
+	builds := mock.NewMockBuildStore(controller)
+	builds.EXPECT().Find(gomock.Any(), mockBuild.ID).Return(mockBuild, nil)
+
+	webhook := mock.NewMockWebhookSender(controller)
+	webhook.EXPECT().Send(gomock.Any(), gomock.Any()).Return(nil)
+
+	manager := new(mockManager)
+
+	w := New(builds, manager, repos, stages, webhook)
+	err := w.run(noContext)
+	if err != nil {
+		t.Error(err)
+	}
+
+	stage := manager.stage
+	if stage == nil {
+		t.Errorf("Expect stage finalized")
+		return
+	}
+	if got, want := stage.Status, core.StatusError; got != want {
+		t.Errorf("Want stage status %s, got %s", want, got)
+	}
+	if got, want := stage.Error, "Stage was accepted by agent1 but did not start"; got != want {
+		t.Errorf("Want stage error %q, got %q", want, got)
+	}
+	if !stage.Abandoned {
+		t.Errorf("Expect stage abandoned")
+	}
+}
+
+func TestTimeoutOf(t *testing.T) {
+	repo := &core.Repository{Timeout: 60}
+	stage := &core.Stage{}
+	if got, want := timeoutOf(repo, stage), time.Hour; got != want {
+		t.Errorf("Want repository timeout %s, got %s", want, got)
+	}
+	stage.Timeout = 90
+	if got, want := timeoutOf(repo, stage), 90*time.Minute; got != want {
+		t.Errorf("Want stage timeout %s, got %s", want, got)
+	}
+}
+
+// mockManager is a stub build manager that captures the
+// finalized stage.
+type mockManager struct {
+	manager.BuildManager
+	stage *core.Stage
+}
+
+func (m *mockManager) AfterAll(ctx context.Context, stage *core.Stage) error {
+	m.stage = stage
+	return nil
+}
@@ -0,0 +1,80 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package platform
+
+import (
+	"context"
+
+	"github.com/drone/drone/core"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Agents returns a platform service that validates the
+// pipeline platform against the platforms and labels of the
+// connected agents. If block is false, pipelines that cannot
+// be served are logged but not rejected.
+func Agents(agents core.AgentRegistry, block bool) core.PlatformService {
+	return &agentPlatforms{agents: agents, block: block}
+}
+
+type agentPlatforms struct {
+	agents core.AgentRegistry
+	block  bool
+}
+
+func (s *agentPlatforms) Validate(ctx context.Context, platform *core.Platform) error {
+	agents, err := s.agents.List(ctx)
+	if err != nil {
+		return err
+	}
+	// the registry is empty until the agents complete the
+	// handshake, for example, after the server is restarted,
+	// in which case the platform cannot be validated.
+	if len(agents) == 0 {
+		return nil
+	}
+	for _, agent := range agents {
+		if match(agent, platform) {
+			return nil
+		}
+	}
+	if !s.block {
+		logrus.WithField("platform", platform.String()).
+			WithField("labels", platform.Labels).
+			Warnln("platform: no agent available for the platform")
+		return nil
+	}
+	return core.ErrPlatformUnavailable
+}
+
+// helper function returns true if the agent can serve the
+// platform. The matching rules mirror the rules used by the
+// scheduler to assign stages to agents.
+func match(agent *core.Agent, platform *core.Platform) bool {
+	// agents that do not report the platform in the
+	// handshake are assumed to serve any platform.
+	if agent.OS == "" || agent.Arch == "" {
+		return true
+	}
+	if agent.OS != platform.OS || agent.Arch != platform.Arch {
+		return false
+	}
+	if platform.Variant != "" && platform.Variant != agent.Variant {
+		return false
+	}
+	if platform.Kernel != "" && platform.Kernel != agent.Kernel {
+		return false
+	}
+	if len(platform.Labels) != len(agent.Labels) {
+		return false
+	}
+	for k, v := range platform.Labels {
+		if w, ok := agent.Labels[k]; !ok || v != w {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,105 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package platform
+
+import (
+	"context"
+	"testing"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/mock"
+
+	"github.com/golang/mock/gomock"
+)
+
+var noContext = context.Background()
+
+var dummyAgents = []*core.Agent{
+	{Machine: "amd64", OS: "linux", Arch: "amd64"},
+	{Machine: "arm64", OS: "linux", Arch: "arm64", Variant: "v8", Labels: map[string]string{"gpu": "nvidia"}},
+	{Machine: "windows", OS: "windows", Arch: "amd64", Kernel: "1809"},
+}
+
+func TestAgents(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	agents := mock.NewMockAgentRegistry(controller)
+	agents.EXPECT().List(gomock.Any()).Return(dummyAgents, nil).AnyTimes()
+
+	tests := []struct {
+		platform *core.Platform
+		error    error
+	}{
+		{
+			platform: &core.Platform{OS: "linux", Arch: "amd64"},
+			error:    nil,
+		},
+		{
+			platform: &core.Platform{OS: "linux", Arch: "arm64", Labels: map[string]string{"gpu": "nvidia"}},
+			error:    nil,
+		},
+		{
+			platform: &core.Platform{OS: "linux", Arch: "arm64", Variant: "v8", Labels: map[string]string{"gpu": "nvidia"}},
+			error:    nil,
+		},
+		{
+			platform: &core.Platform{OS: "windows", Arch: "amd64", Kernel: "1809"},
+			error:    nil,
+		},
+		// the agent labels must match the pipeline labels.
+		{
+			platform: &core.Platform{OS: "linux", Arch: "arm64"},
+			error:    core.ErrPlatformUnavailable,
+		},
+		{
+			platform: &core.Platform{OS: "linux", Arch: "arm64", Variant: "v7", Labels: map[string]string{"gpu": "nvidia"}},
+			error:    core.ErrPlatformUnavailable,
+		},
+		{
+			platform: &core.Platform{OS: "windows", Arch: "amd64", Kernel: "1903"},
+			error:    core.ErrPlatformUnavailable,
+		},
+		{
+			platform: &core.Platform{OS: "darwin", Arch: "amd64"},
+			error:    core.ErrPlatformUnavailable,
+		},
+	}
+
+	service := Agents(agents, true)
+	for i, test := range tests {
+		if got, want := service.Validate(noContext, test.platform), test.error; got != want {
+			t.Errorf("Want error %v, got %v at index %d", want, got, i)
+		}
+	}
+}
+
+func TestAgents_Warn(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	agents := mock.NewMockAgentRegistry(controller)
+	agents.EXPECT().List(gomock.Any()).Return(dummyAgents, nil)
+
+	service := Agents(agents, false)
+	err := service.Validate(noContext, &core.Platform{OS: "darwin", Arch: "amd64"})
+	if err != nil {
+		t.Errorf("Expect unavailable platform ignored when not blocking, got %v", err)
+	}
+}
+
+func TestAgents_NoAgents(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	agents := mock.NewMockAgentRegistry(controller)
+	agents.EXPECT().List(gomock.Any()).Return([]*core.Agent{}, nil)
+
+	service := Agents(agents, true)
+	err := service.Validate(noContext, &core.Platform{OS: "darwin", Arch: "amd64"})
+	if err != nil {
+		t.Errorf("Expect platform not validated without agents, got %v", err)
+	}
+}
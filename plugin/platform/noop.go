@@ -0,0 +1,21 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package platform
+
+import (
+	"context"
+
+	"github.com/drone/drone/core"
+)
+
+// Noop returns a platform service that does not validate
+// the pipeline platform.
+func Noop() core.PlatformService {
+	return new(noop)
+}
+
+type noop struct{}
+
+func (noop) Validate(context.Context, *core.Platform) error { return nil }
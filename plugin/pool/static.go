@@ -0,0 +1,32 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package pool
+
+import (
+	"context"
+
+	"github.com/drone/drone/core"
+)
+
+// Static returns a new static runner pool service, where
+// each pool maps a pool name to a set of runner labels.
+func Static(pools map[string]map[string]string) core.PoolService {
+	return &staticPools{pools: pools}
+}
+
+type staticPools struct {
+	pools map[string]map[string]string
+}
+
+func (s *staticPools) Find(ctx context.Context, name string) (*core.Pool, error) {
+	labels, ok := s.pools[name]
+	if !ok {
+		return nil, core.ErrPoolNotFound
+	}
+	return &core.Pool{
+		Name:   name,
+		Labels: labels,
+	}, nil
+}
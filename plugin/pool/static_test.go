@@ -0,0 +1,39 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package pool
+
+import (
+	"context"
+	"testing"
+
+	"github.com/drone/drone/core"
+)
+
+var noContext = context.Background()
+
+func TestStatic(t *testing.T) {
+	service := Static(map[string]map[string]string{
+		"gpu": {"gpu": "nvidia", "zone": "us-east"},
+	})
+	pool, err := service.Find(noContext, "gpu")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if got, want := pool.Name, "gpu"; got != want {
+		t.Errorf("Want pool name %s, got %s", want, got)
+	}
+	if got, want := pool.Labels["gpu"], "nvidia"; got != want {
+		t.Errorf("Want pool label %s, got %s", want, got)
+	}
+}
+
+func TestStatic_NotFound(t *testing.T) {
+	service := Static(nil)
+	_, err := service.Find(noContext, "gpu")
+	if err != core.ErrPoolNotFound {
+		t.Errorf("Want pool not found error, got %v", err)
+	}
+}
@@ -0,0 +1,68 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package registry
+
+import (
+	"strings"
+
+	"github.com/drone/drone/core"
+)
+
+// dockerHub is the canonical host name of the default
+// docker registry.
+const dockerHub = "docker.io"
+
+// Match returns the registry credentials for registries
+// that host one or more of the images. Credentials for
+// other registries are not provided to the pipeline.
+func Match(registries []*core.Registry, images []string) []*core.Registry {
+	hosts := map[string]struct{}{}
+	for _, image := range images {
+		hosts[ImageHost(image)] = struct{}{}
+	}
+	var out []*core.Registry
+	for _, registry := range registries {
+		if _, ok := hosts[AddressHost(registry.Address)]; ok {
+			out = append(out, registry)
+		}
+	}
+	return out
+}
+
+// ImageHost returns the registry host name from the image
+// name. Images without a host name are pulled from the
+// default docker registry.
+func ImageHost(image string) string {
+	parts := strings.SplitN(image, "/", 2)
+	if len(parts) == 1 {
+		return dockerHub
+	}
+	host := parts[0]
+	if !strings.ContainsAny(host, ".:") && host != "localhost" {
+		return dockerHub
+	}
+	return normalizeHost(host)
+}
+
+// AddressHost returns the registry host name from the
+// registry address, which may include the scheme and path.
+func AddressHost(address string) string {
+	address = strings.TrimPrefix(address, "https://")
+	address = strings.TrimPrefix(address, "http://")
+	if i := strings.Index(address, "/"); i != -1 {
+		address = address[:i]
+	}
+	return normalizeHost(address)
+}
+
+func normalizeHost(host string) string {
+	host = strings.ToLower(host)
+	switch host {
+	case "index.docker.io", "registry-1.docker.io", "registry.hub.docker.com":
+		return dockerHub
+	default:
+		return host
+	}
+}
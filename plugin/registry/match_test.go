@@ -0,0 +1,74 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package registry
+
+import (
+	"testing"
+
+	"github.com/drone/drone/core"
+)
+
+func TestMatch(t *testing.T) {
+	registries := []*core.Registry{
+		{Address: "https://index.docker.io/v1/"},
+		{Address: "gcr.io"},
+		{Address: "registry.company.com:5000"},
+	}
+	images := []string{
+		"golang:1.12",
+		"registry.company.com:5000/octocat/hello-world",
+	}
+	got := Match(registries, images)
+	if len(got) != 2 {
+		t.Errorf("Want 2 registries matched, got %d", len(got))
+		return
+	}
+	if got, want := got[0].Address, "https://index.docker.io/v1/"; got != want {
+		t.Errorf("Want registry %s, got %s", want, got)
+	}
+	if got, want := got[1].Address, "registry.company.com:5000"; got != want {
+		t.Errorf("Want registry %s, got %s", want, got)
+	}
+}
+
+func TestImageHost(t *testing.T) {
+	tests := []struct {
+		image string
+		host  string
+	}{
+		{"golang", "docker.io"},
+		{"golang:1.12", "docker.io"},
+		{"octocat/hello-world", "docker.io"},
+		{"docker.io/octocat/hello-world", "docker.io"},
+		{"index.docker.io/octocat/hello-world", "docker.io"},
+		{"gcr.io/octocat/hello-world", "gcr.io"},
+		{"localhost/hello-world", "localhost"},
+		{"localhost:5000/hello-world", "localhost:5000"},
+		{"Registry.Company.com/hello-world:latest", "registry.company.com"},
+	}
+	for _, test := range tests {
+		if got, want := ImageHost(test.image), test.host; got != want {
+			t.Errorf("Want image %s host %s, got %s", test.image, want, got)
+		}
+	}
+}
+
+func TestAddressHost(t *testing.T) {
+	tests := []struct {
+		address string
+		host    string
+	}{
+		{"https://index.docker.io/v1/", "docker.io"},
+		{"docker.io", "docker.io"},
+		{"https://gcr.io", "gcr.io"},
+		{"http://localhost:5000", "localhost:5000"},
+		{"registry.company.com/v2/", "registry.company.com"},
+	}
+	for _, test := range tests {
+		if got, want := AddressHost(test.address), test.host; got != want {
+			t.Errorf("Want address %s host %s, got %s", test.address, want, got)
+		}
+	}
+}
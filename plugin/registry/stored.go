@@ -0,0 +1,26 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package registry
+
+import (
+	"context"
+
+	"github.com/drone/drone/core"
+)
+
+// Stored returns a new registry credentials controller that
+// provides the repository and organization registry
+// credentials stored in the database.
+func Stored(registries []*core.Registry) core.RegistryService {
+	return &storedController{registries: registries}
+}
+
+type storedController struct {
+	registries []*core.Registry
+}
+
+func (c *storedController) List(ctx context.Context, in *core.RegistryArgs) ([]*core.Registry, error) {
+	return c.registries, nil
+}
@@ -0,0 +1,49 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package webhook
+
+import (
+	"path"
+
+	"github.com/drone/drone/core"
+)
+
+// match returns true if the webhook payload matches the
+// configured event and repository filters.
+func (s *sender) match(payload *core.WebhookData) bool {
+	return matchEvent(s.Events, payload.Event, payload.Action) &&
+		matchRepo(s.Repos, payload.Repo)
+}
+
+// helper function returns true if the event is in the list
+// of allowed events. An allowed event matches all actions
+// (e.g. build), or a single action (e.g. build:created).
+func matchEvent(events []string, event, action string) bool {
+	if len(events) == 0 {
+		return true
+	}
+	for _, pattern := range events {
+		if pattern == event || pattern == event+":"+action {
+			return true
+		}
+	}
+	return false
+}
+
+// helper function returns true if the repository slug
+// matches one of the repository patterns. Events that are
+// not associated with a repository, such as user events,
+// are not subject to the repository filter.
+func matchRepo(patterns []string, repo *core.Repository) bool {
+	if len(patterns) == 0 || repo == nil {
+		return true
+	}
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, repo.Slug); ok {
+			return true
+		}
+	}
+	return false
+}
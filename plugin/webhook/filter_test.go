@@ -0,0 +1,53 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package webhook
+
+import (
+	"testing"
+
+	"github.com/drone/drone/core"
+)
+
+func TestMatchEvent(t *testing.T) {
+	tests := []struct {
+		events []string
+		event  string
+		action string
+		match  bool
+	}{
+		{nil, core.WebhookEventBuild, core.WebhookActionCreated, true},
+		{[]string{"build"}, core.WebhookEventBuild, core.WebhookActionCreated, true},
+		{[]string{"build"}, core.WebhookEventUser, core.WebhookActionCreated, false},
+		{[]string{"repo", "user"}, core.WebhookEventUser, core.WebhookActionDeleted, true},
+		{[]string{"build:updated"}, core.WebhookEventBuild, core.WebhookActionUpdated, true},
+		{[]string{"build:updated"}, core.WebhookEventBuild, core.WebhookActionCreated, false},
+	}
+	for i, test := range tests {
+		if got, want := matchEvent(test.events, test.event, test.action), test.match; got != want {
+			t.Errorf("Want match %v at index %d, got %v", want, i, got)
+		}
+	}
+}
+
+func TestMatchRepo(t *testing.T) {
+	repo := &core.Repository{Slug: "octocat/hello-world"}
+	tests := []struct {
+		patterns []string
+		repo     *core.Repository
+		match    bool
+	}{
+		{nil, repo, true},
+		{[]string{"octocat/*"}, repo, true},
+		{[]string{"octocat/hello-world"}, repo, true},
+		{[]string{"spaceghost/*"}, repo, false},
+		{[]string{"spaceghost/*", "octocat/*"}, repo, true},
+		{[]string{"spaceghost/*"}, nil, true},
+	}
+	for i, test := range tests {
+		if got, want := matchRepo(test.patterns, test.repo), test.match; got != want {
+			t.Errorf("Want match %v at index %d, got %v", want, i, got)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/drone/drone/core"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+// Repository returns a Webhook sender that sends build
+// payloads to the repository webhook endpoints, in addition
+// to sending all payloads to the base sender. If the delivery
+// store is not nil, each delivery is persisted to the
+// datastore and failed deliveries are retried with
+// exponential backoff.
+func Repository(hooks core.RepoWebhookStore, base core.WebhookSender, deliveries core.WebhookDeliveryStore) core.WebhookSender {
+	return &repoSender{
+		base:  base,
+		hooks: hooks,
+		sender: &sender{
+			Deliveries: deliveries,
+			Hooks:      hooks,
+		},
+	}
+}
+
+type repoSender struct {
+	base   core.WebhookSender
+	hooks  core.RepoWebhookStore
+	sender *sender
+}
+
+// Send sends the JSON encoded webhook to the global HTTP
+// endpoints and, for build events, to the repository HTTP
+// endpoints. Each repository endpoint is signed using the
+// endpoint secret.
+func (s *repoSender) Send(ctx context.Context, payload *core.WebhookData) error {
+	var result error
+	if err := s.base.Send(ctx, payload); err != nil {
+		result = multierror.Append(result, err)
+	}
+	if payload.Event != core.WebhookEventBuild || payload.Repo == nil {
+		return result
+	}
+
+	hooks, err := s.hooks.List(ctx, payload.Repo.ID)
+	if err != nil {
+		return multierror.Append(result, err)
+	}
+	if len(hooks) == 0 {
+		return result
+	}
+
+	data, _ := json.Marshal(payload)
+	for _, hook := range hooks {
+		if s.sender.Deliveries != nil {
+			err := s.sender.create(ctx, &core.WebhookDelivery{
+				Hook:     hook.ID,
+				Endpoint: hook.Endpoint,
+				Event:    payload.Event,
+				Action:   payload.Action,
+				Payload:  string(data),
+			})
+			if err != nil {
+				result = multierror.Append(result, err)
+			}
+			continue
+		}
+		_, err := s.sender.send(hook.Endpoint, hook.Secret, payload.Event, data)
+		if err != nil {
+			result = multierror.Append(result, err)
+		}
+	}
+	return result
+}
@@ -0,0 +1,156 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package webhook
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/mock"
+
+	"github.com/99designs/httpsignatures-go"
+	"github.com/golang/mock/gomock"
+	"github.com/h2non/gock"
+)
+
+func TestRepository(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+	defer gock.Off()
+
+	webhook := &core.WebhookData{
+		Event:  core.WebhookEventBuild,
+		Action: core.WebhookActionCreated,
+		Repo:   &core.Repository{ID: 1, Slug: "octocat/hello-world"},
+		Build:  &core.Build{Number: 1},
+	}
+
+	hooks := []*core.RepoWebhook{
+		{
+			ID:       1,
+			RepoID:   1,
+			Endpoint: "https://octocat.com/hooks",
+			Secret:   "correct-horse-battery-staple",
+		},
+	}
+
+	matchSignature := func(r *http.Request, _ *gock.Request) (bool, error) {
+		signature, err := httpsignatures.FromRequest(r)
+		if err != nil {
+			return false, err
+		}
+		return signature.IsValid("correct-horse-battery-staple", r), nil
+	}
+
+	// the signature matcher is assigned to this mock only,
+	// since adding a matcher modifies the default matcher
+	// shared by all mocks.
+	matcher := gock.NewMatcher()
+	matcher.Add(matchSignature)
+
+	gock.New("https://octocat.com").
+		Post("/hooks").
+		SetMatcher(matcher).
+		MatchHeader("X-Drone-Event", "build").
+		Reply(200)
+
+	base := mock.NewMockWebhookSender(controller)
+	base.EXPECT().Send(gomock.Any(), webhook).Return(nil)
+
+	store := mock.NewMockRepoWebhookStore(controller)
+	store.EXPECT().List(gomock.Any(), webhook.Repo.ID).Return(hooks, nil)
+
+	err := Repository(store, base, nil).Send(noContext, webhook)
+	if err != nil {
+		t.Error(err)
+	}
+	if gock.IsPending() {
+		t.Errorf("Unfinished requests")
+	}
+}
+
+func TestRepository_Delivery(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+	defer gock.Off()
+
+	webhook := &core.WebhookData{
+		Event:  core.WebhookEventBuild,
+		Action: core.WebhookActionCreated,
+		Repo:   &core.Repository{ID: 1, Slug: "octocat/hello-world"},
+		Build:  &core.Build{Number: 1},
+	}
+
+	hooks := []*core.RepoWebhook{
+		{
+			ID:       1,
+			RepoID:   1,
+			Endpoint: "https://octocat.com/hooks",
+			Secret:   "correct-horse-battery-staple",
+		},
+	}
+
+	gock.New("https://octocat.com").
+		Post("/hooks").
+		Reply(502)
+
+	base := mock.NewMockWebhookSender(controller)
+	base.EXPECT().Send(gomock.Any(), webhook).Return(nil)
+
+	store := mock.NewMockRepoWebhookStore(controller)
+	store.EXPECT().List(gomock.Any(), webhook.Repo.ID).Return(hooks, nil)
+	store.EXPECT().Find(gomock.Any(), hooks[0].ID).Return(hooks[0], nil)
+
+	var delivery *core.WebhookDelivery
+	deliveries := mock.NewMockWebhookDeliveryStore(controller)
+	deliveries.EXPECT().Create(gomock.Any(), gomock.Any()).Do(func(_ interface{}, in *core.WebhookDelivery) {
+		delivery = in
+	})
+	deliveries.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+
+	err := Repository(store, base, deliveries).Send(noContext, webhook)
+	if err != nil {
+		t.Errorf("Expect failed delivery is not returned as an error, got %s", err)
+		return
+	}
+
+	if got, want := delivery.Hook, hooks[0].ID; got != want {
+		t.Errorf("Want delivery to hook %d, got %d", want, got)
+	}
+	if got, want := delivery.Endpoint, hooks[0].Endpoint; got != want {
+		t.Errorf("Want delivery endpoint %s, got %s", want, got)
+	}
+	if got, want := delivery.Status, core.DeliveryPending; got != want {
+		t.Errorf("Want delivery status %s, got %s", want, got)
+	}
+	if got, want := delivery.Attempts, 1; got != want {
+		t.Errorf("Want %d delivery attempts, got %d", want, got)
+	}
+	if gock.IsPending() {
+		t.Errorf("Unfinished requests")
+	}
+}
+
+func TestRepository_IgnoreUserEvents(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	webhook := &core.WebhookData{
+		Event:  core.WebhookEventUser,
+		Action: core.WebhookActionCreated,
+		User:   &core.User{Login: "octocat"},
+	}
+
+	base := mock.NewMockWebhookSender(controller)
+	base.EXPECT().Send(gomock.Any(), webhook).Return(nil)
+
+	store := mock.NewMockRepoWebhookStore(controller)
+
+	err := Repository(store, base, nil).Send(noContext, webhook)
+	if err != nil {
+		t.Error(err)
+	}
+}
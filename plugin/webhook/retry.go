@@ -0,0 +1,109 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package webhook
+
+import (
+	"context"
+	"time"
+
+	"github.com/drone/drone/core"
+
+	"github.com/sirupsen/logrus"
+)
+
+// maximum number of delivery attempts before a delivery
+// is moved to the dead-letter list.
+const maxAttempts = 5
+
+// base interval used to calculate the exponential backoff.
+const backoffBase = 30 * time.Second
+
+// successful deliveries are purged after this duration.
+const retention = 24 * time.Hour * 7
+
+// backoff returns the duration to wait before the next
+// delivery attempt, which doubles with each attempt.
+func backoff(attempts int) time.Duration {
+	if attempts < 1 {
+		attempts = 1
+	}
+	return backoffBase << uint(attempts-1)
+}
+
+// NewRetrier returns a new Retrier. Deliveries to the global
+// endpoints are signed with the global secret, and deliveries
+// to repository webhooks are signed with the webhook secret.
+func NewRetrier(secret string, deliveries core.WebhookDeliveryStore, hooks core.RepoWebhookStore) *Retrier {
+	return &Retrier{
+		sender: &sender{
+			Secret:     secret,
+			Deliveries: deliveries,
+			Hooks:      hooks,
+		},
+	}
+}
+
+// Retrier retries failed webhook deliveries in the
+// background.
+type Retrier struct {
+	sender *sender
+}
+
+// Start starts the retrier.
+func (r *Retrier) Start(ctx context.Context, dur time.Duration) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(dur):
+			r.run(ctx)
+		}
+	}
+}
+
+func (r *Retrier) run(ctx context.Context) error {
+	defer func() {
+		if err := recover(); err != nil {
+			logger := logrus.WithField("error", err)
+			logger.Errorln("webhook: unexpected panic")
+		}
+	}()
+
+	deliveries, err := r.sender.Deliveries.ListDue(ctx, time.Now().Unix())
+	if err != nil {
+		logger := logrus.WithError(err)
+		logger.Error("webhook: cannot list pending deliveries")
+		return err
+	}
+
+	for _, delivery := range deliveries {
+		r.sender.attempt(ctx, delivery)
+
+		logger := logrus.
+			WithField("delivery", delivery.ID).
+			WithField("endpoint", delivery.Endpoint).
+			WithField("attempts", delivery.Attempts).
+			WithField("status", delivery.Status)
+
+		err := r.sender.Deliveries.Update(ctx, delivery)
+		if err != nil {
+			logger.WithError(err).Warnln("webhook: cannot update delivery")
+			continue
+		}
+		if delivery.Status == core.DeliveryFailed {
+			logger.Warnln("webhook: delivery failed, maximum attempts exceeded")
+		} else {
+			logger.Debugln("webhook: delivery retried")
+		}
+	}
+
+	before := time.Now().Add(-retention).Unix()
+	err = r.sender.Deliveries.Purge(ctx, before)
+	if err != nil {
+		logger := logrus.WithError(err)
+		logger.Warnln("webhook: cannot purge deliveries")
+	}
+	return nil
+}
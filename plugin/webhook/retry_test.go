@@ -0,0 +1,219 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package webhook
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/mock"
+
+	"github.com/99designs/httpsignatures-go"
+	"github.com/golang/mock/gomock"
+	"github.com/h2non/gock"
+)
+
+func TestWebhook_Delivery(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+	defer gock.Off()
+
+	gock.New("https://company.com").
+		Post("/hooks").
+		Reply(502)
+
+	var delivery *core.WebhookDelivery
+	deliveries := mock.NewMockWebhookDeliveryStore(controller)
+	deliveries.EXPECT().Create(gomock.Any(), gomock.Any()).Do(func(_ interface{}, in *core.WebhookDelivery) {
+		delivery = in
+	})
+	deliveries.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+
+	webhook := &core.WebhookData{
+		Event:  core.WebhookEventUser,
+		Action: core.WebhookActionCreated,
+		User:   &core.User{Login: "octocat"},
+	}
+
+	config := Config{
+		Endpoint: []string{"https://company.com/hooks"},
+		Secret:   "GMEuUHQfmrMRsseWxi9YlIeBtn9lm6im",
+	}
+	sender := New(config, deliveries)
+	err := sender.Send(noContext, webhook)
+	if err != nil {
+		t.Errorf("Expect failed delivery is not returned as an error, got %s", err)
+		return
+	}
+
+	if got, want := delivery.Status, core.DeliveryPending; got != want {
+		t.Errorf("Want delivery status %s, got %s", want, got)
+	}
+	if got, want := delivery.Attempts, 1; got != want {
+		t.Errorf("Want %d delivery attempts, got %d", want, got)
+	}
+	if got, want := len(delivery.History), 1; got != want {
+		t.Errorf("Want %d attempts in history, got %d", want, got)
+	} else if got, want := delivery.History[0].Code, 502; got != want {
+		t.Errorf("Want attempt status code %d, got %d", want, got)
+	}
+	if delivery.Next <= delivery.Updated {
+		t.Errorf("Expect next delivery attempt scheduled")
+	}
+	if gock.IsPending() {
+		t.Errorf("Unfinished requests")
+	}
+}
+
+func TestRetrier(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+	defer gock.Off()
+
+	gock.New("https://company.com").
+		Post("/hooks").
+		Reply(200)
+
+	delivery := &core.WebhookDelivery{
+		ID:       1,
+		Endpoint: "https://company.com/hooks",
+		Event:    core.WebhookEventUser,
+		Payload:  `{"action":"created"}`,
+		Status:   core.DeliveryPending,
+		Attempts: 2,
+	}
+
+	deliveries := mock.NewMockWebhookDeliveryStore(controller)
+	deliveries.EXPECT().ListDue(gomock.Any(), gomock.Any()).Return([]*core.WebhookDelivery{delivery}, nil)
+	deliveries.EXPECT().Update(gomock.Any(), delivery).Return(nil)
+	deliveries.EXPECT().Purge(gomock.Any(), gomock.Any()).Return(nil)
+
+	retrier := NewRetrier("GMEuUHQfmrMRsseWxi9YlIeBtn9lm6im", deliveries, nil)
+	retrier.run(noContext)
+
+	if got, want := delivery.Status, core.DeliverySuccess; got != want {
+		t.Errorf("Want delivery status %s, got %s", want, got)
+	}
+	if got, want := delivery.Attempts, 3; got != want {
+		t.Errorf("Want %d delivery attempts, got %d", want, got)
+	}
+	if delivery.Next != 0 {
+		t.Errorf("Expect next delivery attempt cleared")
+	}
+}
+
+func TestRetrier_DeadLetter(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+	defer gock.Off()
+
+	gock.New("https://company.com").
+		Post("/hooks").
+		Reply(500)
+
+	delivery := &core.WebhookDelivery{
+		ID:       1,
+		Endpoint: "https://company.com/hooks",
+		Event:    core.WebhookEventUser,
+		Payload:  `{"action":"created"}`,
+		Status:   core.DeliveryPending,
+		Attempts: maxAttempts - 1,
+	}
+
+	deliveries := mock.NewMockWebhookDeliveryStore(controller)
+	deliveries.EXPECT().ListDue(gomock.Any(), gomock.Any()).Return([]*core.WebhookDelivery{delivery}, nil)
+	deliveries.EXPECT().Update(gomock.Any(), delivery).Return(nil)
+	deliveries.EXPECT().Purge(gomock.Any(), gomock.Any()).Return(nil)
+
+	retrier := NewRetrier("GMEuUHQfmrMRsseWxi9YlIeBtn9lm6im", deliveries, nil)
+	retrier.run(noContext)
+
+	if got, want := delivery.Status, core.DeliveryFailed; got != want {
+		t.Errorf("Want delivery status %s, got %s", want, got)
+	}
+	if delivery.Next != 0 {
+		t.Errorf("Expect no further delivery attempts")
+	}
+}
+
+func TestRetrier_Repository(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+	defer gock.Off()
+
+	matchSignature := func(r *http.Request, _ *gock.Request) (bool, error) {
+		signature, err := httpsignatures.FromRequest(r)
+		if err != nil {
+			return false, err
+		}
+		return signature.IsValid("correct-horse-battery-staple", r), nil
+	}
+
+	// the signature matcher is assigned to this mock only,
+	// since adding a matcher modifies the default matcher
+	// shared by all mocks.
+	matcher := gock.NewMatcher()
+	matcher.Add(matchSignature)
+
+	gock.New("https://octocat.com").
+		Post("/hooks").
+		SetMatcher(matcher).
+		Reply(200)
+
+	delivery := &core.WebhookDelivery{
+		ID:       1,
+		Hook:     1,
+		Endpoint: "https://octocat.com/hooks",
+		Event:    core.WebhookEventBuild,
+		Payload:  `{"action":"created"}`,
+		Status:   core.DeliveryPending,
+		Attempts: 1,
+	}
+
+	hook := &core.RepoWebhook{
+		ID:       1,
+		RepoID:   1,
+		Endpoint: "https://octocat.com/hooks",
+		Secret:   "correct-horse-battery-staple",
+	}
+
+	deliveries := mock.NewMockWebhookDeliveryStore(controller)
+	deliveries.EXPECT().ListDue(gomock.Any(), gomock.Any()).Return([]*core.WebhookDelivery{delivery}, nil)
+	deliveries.EXPECT().Update(gomock.Any(), delivery).Return(nil)
+	deliveries.EXPECT().Purge(gomock.Any(), gomock.Any()).Return(nil)
+
+	hooks := mock.NewMockRepoWebhookStore(controller)
+	hooks.EXPECT().Find(gomock.Any(), delivery.Hook).Return(hook, nil)
+
+	retrier := NewRetrier("GMEuUHQfmrMRsseWxi9YlIeBtn9lm6im", deliveries, hooks)
+	retrier.run(noContext)
+
+	if got, want := delivery.Status, core.DeliverySuccess; got != want {
+		t.Errorf("Want delivery status %s, got %s", want, got)
+	}
+	if gock.IsPending() {
+		t.Errorf("Unfinished requests")
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{0, 30 * time.Second},
+		{1, 30 * time.Second},
+		{2, time.Minute},
+		{3, 2 * time.Minute},
+		{4, 4 * time.Minute},
+	}
+	for _, test := range tests {
+		if got := backoff(test.attempts); got != test.want {
+			t.Errorf("Want backoff %s for %d attempts, got %s", test.want, test.attempts, got)
+		}
+	}
+}
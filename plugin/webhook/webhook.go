@@ -10,6 +10,8 @@ import (
 	"crypto/sha256"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"time"
 
@@ -18,6 +20,11 @@ import (
 	"github.com/99designs/httpsignatures-go"
 )
 
+// errNoHooks is returned when the delivery to a repository
+// webhook cannot be signed because the sender is not
+// configured with the repository webhook store.
+var errNoHooks = errors.New("webhook: repository webhooks unavailable")
+
 // required http headers
 var headers = []string{
 	"date",
@@ -29,18 +36,48 @@ var signer = httpsignatures.NewSigner(
 	headers...,
 )
 
-// New returns a new Webhook sender.
-func New(endpoints []string, secret string) core.WebhookSender {
+// Config provides the webhook configuration.
+type Config struct {
+	// Endpoint provides the list of global webhook endpoints.
+	Endpoint []string
+
+	// Secret provides the secret used to sign the webhook.
+	Secret string
+
+	// Events provides an optional list of events that are sent
+	// to the webhook endpoints. The event can be qualified with
+	// an action (e.g. build:created). If empty, all events are
+	// sent.
+	Events []string
+
+	// Repos provides an optional list of repository name
+	// patterns (e.g. octocat/*). Events for repositories that
+	// do not match are not sent. If empty, events for all
+	// repositories are sent.
+	Repos []string
+}
+
+// New returns a new Webhook sender. If the delivery store
+// is not nil, each delivery is persisted to the datastore
+// and failed deliveries are retried with exponential backoff.
+func New(config Config, deliveries core.WebhookDeliveryStore) core.WebhookSender {
 	return &sender{
-		Endpoints: endpoints,
-		Secret:    secret,
+		Endpoints:  config.Endpoint,
+		Secret:     config.Secret,
+		Events:     config.Events,
+		Repos:      config.Repos,
+		Deliveries: deliveries,
 	}
 }
 
 type sender struct {
-	Client    *http.Client
-	Endpoints []string
-	Secret    string
+	Client     *http.Client
+	Endpoints  []string
+	Secret     string
+	Events     []string
+	Repos      []string
+	Deliveries core.WebhookDeliveryStore
+	Hooks      core.RepoWebhookStore
 }
 
 // Send sends the JSON encoded webhook to the global
@@ -49,10 +86,25 @@ func (s *sender) Send(ctx context.Context, payload *core.WebhookData) error {
 	if len(s.Endpoints) == 0 {
 		return nil
 	}
+	if !s.match(payload) {
+		return nil
+	}
 
 	data, _ := json.Marshal(payload)
 	for _, endpoint := range s.Endpoints {
-		err := s.send(endpoint, s.Secret, payload.Event, data)
+		if s.Deliveries != nil {
+			err := s.create(ctx, &core.WebhookDelivery{
+				Endpoint: endpoint,
+				Event:    payload.Event,
+				Action:   payload.Action,
+				Payload:  string(data),
+			})
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		_, err := s.send(endpoint, s.Secret, payload.Event, data)
 		if err != nil {
 			return err
 		}
@@ -60,7 +112,73 @@ func (s *sender) Send(ctx context.Context, payload *core.WebhookData) error {
 	return nil
 }
 
-func (s *sender) send(endpoint, secret, event string, data []byte) error {
+// create persists the webhook delivery to the datastore and
+// makes the first delivery attempt. Failed attempts are not
+// returned as an error, and are retried in the background.
+func (s *sender) create(ctx context.Context, delivery *core.WebhookDelivery) error {
+	now := time.Now().Unix()
+	delivery.Status = core.DeliveryPending
+	delivery.Created = now
+	delivery.Updated = now
+	err := s.Deliveries.Create(ctx, delivery)
+	if err != nil {
+		return err
+	}
+	s.attempt(ctx, delivery)
+	return s.Deliveries.Update(ctx, delivery)
+}
+
+// attempt attempts delivery of the webhook, and records the
+// result of the attempt in the delivery history.
+func (s *sender) attempt(ctx context.Context, delivery *core.WebhookDelivery) {
+	now := time.Now()
+	var code int
+	secret, err := s.secret(ctx, delivery)
+	if err == nil {
+		code, err = s.send(delivery.Endpoint, secret, delivery.Event, []byte(delivery.Payload))
+	}
+	attempt := &core.WebhookAttempt{
+		Code:    code,
+		Created: now.Unix(),
+	}
+	if err != nil {
+		attempt.Error = err.Error()
+	}
+	delivery.Attempts++
+	delivery.History = append(delivery.History, attempt)
+	delivery.Updated = now.Unix()
+	delivery.Next = 0
+
+	switch {
+	case err == nil:
+		delivery.Status = core.DeliverySuccess
+	case delivery.Attempts >= maxAttempts:
+		delivery.Status = core.DeliveryFailed
+	default:
+		delivery.Status = core.DeliveryPending
+		delivery.Next = now.Add(backoff(delivery.Attempts)).Unix()
+	}
+}
+
+// secret returns the secret used to sign the delivery. The
+// delivery to a repository webhook is signed with the secret
+// of the webhook, and all other deliveries are signed with
+// the global secret.
+func (s *sender) secret(ctx context.Context, delivery *core.WebhookDelivery) (string, error) {
+	if delivery.Hook == 0 {
+		return s.Secret, nil
+	}
+	if s.Hooks == nil {
+		return "", errNoHooks
+	}
+	hook, err := s.Hooks.Find(ctx, delivery.Hook)
+	if err != nil {
+		return "", err
+	}
+	return hook.Secret, nil
+}
+
+func (s *sender) send(endpoint, secret, event string, data []byte) (int, error) {
 	ctx := context.Background()
 	ctx, cancel := context.WithTimeout(ctx, time.Minute)
 	defer cancel()
@@ -68,7 +186,7 @@ func (s *sender) send(endpoint, secret, event string, data []byte) error {
 	buf := bytes.NewBuffer(data)
 	req, err := http.NewRequest("POST", endpoint, buf)
 	if err != nil {
-		return err
+		return 0, err
 	}
 
 	req = req.WithContext(ctx)
@@ -76,12 +194,16 @@ func (s *sender) send(endpoint, secret, event string, data []byte) error {
 	req.Header.Add("Content-Type", "application/json")
 	req.Header.Add("Digest", "SHA-256="+digest(data))
 	req.Header.Add("Date", time.Now().UTC().Format(http.TimeFormat))
-	err = signer.SignRequest("hmac-key", s.Secret, req)
+	err = signer.SignRequest("hmac-key", secret, req)
 	res, err := s.client().Do(req)
-	if res != nil {
-		res.Body.Close()
+	if err != nil {
+		return 0, err
+	}
+	res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return res.StatusCode, fmt.Errorf("webhook: unexpected status code %d", res.StatusCode)
 	}
-	return err
+	return res.StatusCode, nil
 }
 
 func (s *sender) client() *http.Client {
@@ -44,7 +44,11 @@ func TestWebhook(t *testing.T) {
 		Reply(200).
 		Type("application/json")
 
-	sender := New([]string{"https://company.com/hooks"}, "GMEuUHQfmrMRsseWxi9YlIeBtn9lm6im")
+	config := Config{
+		Endpoint: []string{"https://company.com/hooks"},
+		Secret:   "GMEuUHQfmrMRsseWxi9YlIeBtn9lm6im",
+	}
+	sender := New(config, nil)
 	err := sender.Send(noContext, webhook)
 	if err != nil {
 		t.Error(err)
@@ -75,9 +79,44 @@ func TestWebhook_NoEndpoints(t *testing.T) {
 		User:   &core.User{Login: "octocat"},
 	}
 
-	sender := New([]string{}, "correct-horse-battery-staple")
+	sender := New(Config{Secret: "correct-horse-battery-staple"}, nil)
 	err := sender.Send(noContext, webhook)
 	if err != nil {
 		t.Error(err)
 	}
 }
+
+func TestWebhook_Filtered(t *testing.T) {
+	defer gock.Off()
+
+	webhook := &core.WebhookData{
+		Event:  core.WebhookEventBuild,
+		Action: core.WebhookActionCreated,
+		Repo:   &core.Repository{Slug: "octocat/hello-world"},
+		Build:  &core.Build{Number: 1},
+	}
+
+	config := Config{
+		Endpoint: []string{"https://company.com/hooks"},
+		Secret:   "GMEuUHQfmrMRsseWxi9YlIeBtn9lm6im",
+		Events:   []string{"build"},
+		Repos:    []string{"spaceghost/*"},
+	}
+
+	// the request is not sent because the repository does
+	// not match the repository filter. If the request was
+	// sent, gock would return an error for the unmatched
+	// request.
+	gock.New("https://company.com").
+		Post("/hooks").
+		Reply(200)
+
+	sender := New(config, nil)
+	err := sender.Send(noContext, webhook)
+	if err != nil {
+		t.Error(err)
+	}
+	if !gock.IsPending() {
+		t.Errorf("Expect webhook not sent")
+	}
+}
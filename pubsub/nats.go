@@ -0,0 +1,52 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pubsub
+
+import (
+	"github.com/drone/drone/core"
+
+	"github.com/nats-io/nats.go"
+)
+
+// NewNats returns a new publish subscriber that distributes
+// messages to multiple server instances using NATS. The
+// datasource is the NATS server url, and the subject is the
+// name of the NATS subject used to publish messages.
+func NewNats(datasource, subject string) (core.Pubsub, error) {
+	conn, err := nats.Connect(datasource, nats.MaxReconnects(-1))
+	if err != nil {
+		return nil, err
+	}
+	return newRemote(&natsBroker{
+		conn:    conn,
+		subject: subject,
+	})
+}
+
+type natsBroker struct {
+	conn    *nats.Conn
+	subject string
+}
+
+func (b *natsBroker) publish(data []byte) error {
+	return b.conn.Publish(b.subject, data)
+}
+
+func (b *natsBroker) subscribe(handler func(data []byte)) error {
+	_, err := b.conn.Subscribe(b.subject, func(msg *nats.Msg) {
+		handler(msg.Data)
+	})
+	return err
+}
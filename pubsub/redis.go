@@ -0,0 +1,67 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pubsub
+
+import (
+	"github.com/drone/drone/core"
+
+	"github.com/go-redis/redis"
+)
+
+// NewRedis returns a new publish subscriber that distributes
+// messages to multiple server instances using Redis. The
+// datasource is the Redis connection url, and the channel is
+// the name of the Redis channel used to publish messages.
+func NewRedis(datasource, channel string) (core.Pubsub, error) {
+	options, err := redis.ParseURL(datasource)
+	if err != nil {
+		return nil, err
+	}
+	client := redis.NewClient(options)
+	if err := client.Ping().Err(); err != nil {
+		return nil, err
+	}
+	return newRemote(&redisBroker{
+		client:  client,
+		channel: channel,
+	})
+}
+
+type redisBroker struct {
+	client  *redis.Client
+	channel string
+}
+
+func (b *redisBroker) publish(data []byte) error {
+	return b.client.Publish(b.channel, data).Err()
+}
+
+func (b *redisBroker) subscribe(handler func(data []byte)) error {
+	sub := b.client.Subscribe(b.channel)
+	// wait for the subscription to be confirmed before
+	// receiving messages, to surface connection errors.
+	if _, err := sub.Receive(); err != nil {
+		sub.Close()
+		return err
+	}
+	// the channel is closed when the subscription is closed,
+	// and the client reconnects if the connection is lost.
+	go func() {
+		for msg := range sub.Channel() {
+			handler([]byte(msg.Payload))
+		}
+	}()
+	return nil
+}
@@ -0,0 +1,81 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pubsub
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/drone/drone/core"
+)
+
+// broker distributes encoded messages through an external
+// message broker, shared by multiple server instances.
+type broker interface {
+	// publish publishes the encoded message to the broker.
+	publish(data []byte) error
+
+	// subscribe subscribes to the broker and invokes the
+	// handler for each encoded message received.
+	subscribe(handler func(data []byte)) error
+}
+
+// remote is a publish subscriber that distributes messages
+// through an external message broker. Messages received from
+// the broker are published to the local subscribers, which
+// includes messages published by this server instance.
+type remote struct {
+	hub    *hub
+	broker broker
+}
+
+func newRemote(b broker) (core.Pubsub, error) {
+	r := &remote{
+		hub:    New().(*hub),
+		broker: b,
+	}
+	err := b.subscribe(r.receive)
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *remote) Publish(ctx context.Context, e *core.Message) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	return r.broker.publish(data)
+}
+
+func (r *remote) Subscribe(ctx context.Context) (<-chan *core.Message, <-chan error) {
+	return r.hub.Subscribe(ctx)
+}
+
+func (r *remote) Subscribers() int {
+	return r.hub.Subscribers()
+}
+
+// receive decodes the message received from the broker and
+// publishes the message to the local subscribers. Messages
+// that cannot be decoded are ignored.
+func (r *remote) receive(data []byte) {
+	e := new(core.Message)
+	if err := json.Unmarshal(data, e); err != nil {
+		return
+	}
+	r.hub.Publish(context.Background(), e)
+}
@@ -0,0 +1,68 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package pubsub
+
+import (
+	"context"
+	"testing"
+
+	"github.com/drone/drone/core"
+	"github.com/google/go-cmp/cmp"
+)
+
+// fakeBroker is an in-process broker that delivers published
+// messages to all subscribed server instances.
+type fakeBroker struct {
+	handlers []func([]byte)
+}
+
+func (b *fakeBroker) publish(data []byte) error {
+	for _, handler := range b.handlers {
+		handler(data)
+	}
+	return nil
+}
+
+func (b *fakeBroker) subscribe(handler func([]byte)) error {
+	b.handlers = append(b.handlers, handler)
+	return nil
+}
+
+func TestRemote(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	b := new(fakeBroker)
+	p1, err := newRemote(b)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	p2, err := newRemote(b)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	events, _ := p2.Subscribe(ctx)
+	if got, want := p2.Subscribers(), 1; got != want {
+		t.Errorf("Want %d subscribers, got %d", want, got)
+	}
+	if got, want := p1.Subscribers(), 0; got != want {
+		t.Errorf("Want %d subscribers, got %d", want, got)
+	}
+
+	want := &core.Message{
+		Repository: "octocat/hello-world",
+		Visibility: core.VisibilityPrivate,
+		Data:       []byte(`{"number":1}`),
+	}
+	p1.Publish(ctx, want)
+
+	got := <-events
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf(diff)
+	}
+}
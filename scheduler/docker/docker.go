@@ -26,6 +26,10 @@ type Scheduler struct {
 	pending chan int64
 	running map[int64]context.CancelFunc
 	runner  *runner.Runner
+
+	// paused is closed when the scheduler is resumed,
+	// and is nil when the scheduler is not paused.
+	paused chan struct{}
 }
 
 // New returns a new Docker scheduler.
@@ -62,6 +66,9 @@ func (s *Scheduler) start(ctx context.Context) error {
 	for {
 		select {
 		case id := <-s.pending:
+			if err := s.wait(ctx); err != nil {
+				return err
+			}
 			s.run(ctx, id)
 		case <-ctx.Done():
 			return ctx.Err()
@@ -69,6 +76,23 @@ func (s *Scheduler) start(ctx context.Context) error {
 	}
 }
 
+// helper function blocks until the scheduler is resumed
+// or the context is cancelled.
+func (s *Scheduler) wait(ctx context.Context) error {
+	s.Lock()
+	paused := s.paused
+	s.Unlock()
+	if paused == nil {
+		return nil
+	}
+	select {
+	case <-paused:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
 func (s *Scheduler) run(ctx context.Context, id int64) error {
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
@@ -119,6 +143,37 @@ func (s *Scheduler) Stats(_ context.Context) (interface{}, error) {
 	return stats, nil
 }
 
+// Pause pauses the scheduler. Running jobs continue to
+// execute, however, pending jobs are not executed until
+// the scheduler is resumed.
+func (s *Scheduler) Pause(context.Context) error {
+	s.Lock()
+	if s.paused == nil {
+		s.paused = make(chan struct{})
+	}
+	s.Unlock()
+	return nil
+}
+
+// Paused returns true if the scheduler is paused.
+func (s *Scheduler) Paused(context.Context) (bool, error) {
+	s.Lock()
+	paused := s.paused != nil
+	s.Unlock()
+	return paused, nil
+}
+
+// Resume resumes the scheduler.
+func (s *Scheduler) Resume(context.Context) error {
+	s.Lock()
+	if s.paused != nil {
+		close(s.paused)
+		s.paused = nil
+	}
+	s.Unlock()
+	return nil
+}
+
 func (s *Scheduler) Request(context.Context, core.Filter) (*core.Stage, error) {
 	return nil, nil
 }
@@ -127,6 +182,14 @@ func (s *Scheduler) Cancelled(context.Context, int64) (bool, error) {
 	return false, nil
 }
 
+func (s *Scheduler) Shutdown(context.Context) error {
+	return nil
+}
+
+func (s *Scheduler) Signal(context.Context) error {
+	return nil
+}
+
 type status struct {
 	Pending int `json:"pending"`
 	Running int `json:"running"`
@@ -3,3 +3,42 @@
 // that can be found in the LICENSE file.
 
 package docker
+
+import (
+	"context"
+	"testing"
+)
+
+func TestPause(t *testing.T) {
+	s := New()
+	ctx := context.Background()
+
+	s.Pause(ctx)
+	if paused, _ := s.Paused(ctx); !paused {
+		t.Errorf("Expect scheduler paused")
+	}
+
+	done := make(chan error)
+	go func() {
+		done <- s.wait(ctx)
+	}()
+
+	s.Resume(ctx)
+	if err := <-done; err != nil {
+		t.Error(err)
+	}
+	if paused, _ := s.Paused(ctx); paused {
+		t.Errorf("Expect scheduler resumed")
+	}
+}
+
+func TestPause_Cancel(t *testing.T) {
+	s := New()
+	ctx, cancel := context.WithCancel(context.Background())
+
+	s.Pause(ctx)
+	cancel()
+	if err := s.wait(ctx); err != context.Canceled {
+		t.Errorf("Expect wait returns when the context is cancelled")
+	}
+}
@@ -232,6 +232,30 @@ func (s *kubeScheduler) Stats(_ context.Context) (interface{}, error) {
 	return nil, errors.New("not implemented")
 }
 
+func (s *kubeScheduler) Pause(context.Context) error {
+	return errors.New("not implemented")
+}
+
+func (s *kubeScheduler) Paused(context.Context) (bool, error) {
+	return false, nil
+}
+
+func (s *kubeScheduler) Resume(context.Context) error {
+	return errors.New("not implemented")
+}
+
+// Shutdown is a no-op. Stages are dispatched to kubernetes
+// as jobs at the time they are scheduled.
+func (s *kubeScheduler) Shutdown(_ context.Context) error {
+	return nil
+}
+
+// Signal is a no-op. Stages are dispatched to kubernetes
+// as jobs at the time they are scheduled.
+func (s *kubeScheduler) Signal(_ context.Context) error {
+	return nil
+}
+
 func (s *kubeScheduler) namespace() string {
 	namespace := s.config.Namespace
 	if namespace == "" {
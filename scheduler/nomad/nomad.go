@@ -235,6 +235,30 @@ func (s *nomadScheduler) Stats(context.Context) (interface{}, error) {
 	return nil, errors.New("not implemented")
 }
 
+func (s *nomadScheduler) Pause(context.Context) error {
+	return errors.New("not implemented")
+}
+
+func (s *nomadScheduler) Paused(context.Context) (bool, error) {
+	return false, nil
+}
+
+func (s *nomadScheduler) Resume(context.Context) error {
+	return errors.New("not implemented")
+}
+
+// Shutdown is a no-op. Stages are dispatched to nomad as
+// jobs at the time they are scheduled.
+func (s *nomadScheduler) Shutdown(context.Context) error {
+	return nil
+}
+
+// Signal is a no-op. Stages are dispatched to nomad as
+// jobs at the time they are scheduled.
+func (s *nomadScheduler) Signal(context.Context) error {
+	return nil
+}
+
 // stringToPtr returns the pointer to a string
 func stringToPtr(str string) *string {
 	return &str
@@ -6,30 +6,54 @@ package queue
 
 import (
 	"context"
+	"sort"
 	"sync"
 	"time"
 
 	"github.com/drone/drone/core"
+	"github.com/drone/drone/metric"
+
+	"github.com/sirupsen/logrus"
 )
 
+// maxRetries is the maximum number of times the queue retries
+// dispatching pending stages when the datastore is unavailable,
+// before waiting for the next signal or interval.
+const maxRetries = 5
+
 type queue struct {
 	sync.Mutex
 
 	ready    chan struct{}
+	done     chan struct{}
 	paused   bool
+	stopped  bool
 	interval time.Duration
+	backoff  time.Duration
 	store    core.StageStore
 	workers  map[*worker]struct{}
 	ctx      context.Context
+
+	// scheduled tracks the stages that were scheduled since
+	// the last successful dispatch, mapped to the build ID,
+	// so that dispatch errors identify the affected stages.
+	scheduled map[int64]int64
 }
 
 // newQueue returns a new Queue backed by the build datastore.
-func newQueue(store core.StageStore) *queue {
+// The queue polls the datastore for pending stages at the
+// interval, which defaults to one minute.
+func newQueue(store core.StageStore, interval time.Duration) *queue {
+	if interval <= 0 {
+		interval = time.Minute
+	}
 	q := &queue{
 		store:    store,
 		ready:    make(chan struct{}, 1),
+		done:     make(chan struct{}),
 		workers:  map[*worker]struct{}{},
-		interval: time.Minute,
+		interval: interval,
+		backoff:  time.Second,
 		ctx:      context.Background(),
 	}
 	go q.start()
@@ -37,6 +61,24 @@ func newQueue(store core.StageStore) *queue {
 }
 
 func (q *queue) Schedule(ctx context.Context, stage *core.Stage) error {
+	q.Lock()
+	if q.scheduled == nil {
+		q.scheduled = map[int64]int64{}
+	}
+	q.scheduled[stage.ID] = stage.BuildID
+	q.Unlock()
+	return q.Signal(ctx)
+}
+
+// Signal signals the queue to dispatch pending stages to the
+// waiting workers without waiting for the polling interval.
+func (q *queue) Signal(ctx context.Context) error {
+	q.Lock()
+	stopped := q.stopped
+	q.Unlock()
+	if stopped {
+		return nil
+	}
 	select {
 	case q.ready <- struct{}{}:
 	default:
@@ -70,6 +112,25 @@ func (q *queue) Resume(ctx context.Context) error {
 	return nil
 }
 
+// Shutdown stops the queue from dispatching stages. Pending
+// calls to Request return immediately with a nil stage. Stages
+// are not marked as assigned until they are accepted, which
+// means any stage that was not yet accepted by an agent remains
+// pending and is dispatched once the server restarts.
+func (q *queue) Shutdown(ctx context.Context) error {
+	q.Lock()
+	defer q.Unlock()
+	if q.stopped {
+		return nil
+	}
+	q.stopped = true
+	close(q.done)
+	for w := range q.workers {
+		delete(q.workers, w)
+	}
+	return nil
+}
+
 func (q *queue) Request(ctx context.Context, params core.Filter) (*core.Stage, error) {
 	w := &worker{
 		os:      params.OS,
@@ -78,8 +139,13 @@ func (q *queue) Request(ctx context.Context, params core.Filter) (*core.Stage, e
 		variant: params.Variant,
 		labels:  params.Labels,
 		channel: make(chan *core.Stage),
+		done:    ctx.Done(),
 	}
 	q.Lock()
+	if q.stopped {
+		q.Unlock()
+		return nil, nil
+	}
 	q.workers[w] = struct{}{}
 	q.Unlock()
 
@@ -94,6 +160,8 @@ func (q *queue) Request(ctx context.Context, params core.Filter) (*core.Stage, e
 		delete(q.workers, w)
 		q.Unlock()
 		return nil, ctx.Err()
+	case <-q.done:
+		return nil, nil
 	case b := <-w.channel:
 		return b, nil
 	}
@@ -102,7 +170,7 @@ func (q *queue) Request(ctx context.Context, params core.Filter) (*core.Stage, e
 func (q *queue) signal(ctx context.Context) error {
 	q.Lock()
 	count := len(q.workers)
-	pause := q.paused
+	pause := q.paused || q.stopped
 	q.Unlock()
 	if pause {
 		return nil
@@ -117,6 +185,9 @@ func (q *queue) signal(ctx context.Context) error {
 
 	q.Lock()
 	defer q.Unlock()
+	if q.stopped {
+		return nil
+	}
 	for _, item := range items {
 		if item.Status == core.StatusRunning {
 			continue
@@ -171,10 +242,15 @@ func (q *queue) signal(ctx context.Context) error {
 			// 		Msg("cannot update queue item")
 			// 	continue
 			// }
+			// the worker may abandon the request (e.g. the agent
+			// disconnects) while the queue is holding the lock,
+			// in which case the item is offered to the next worker.
 			select {
 			case w.channel <- item:
 				delete(q.workers, w)
 				break loop
+			case <-w.done:
+				delete(q.workers, w)
 			}
 		}
 	}
@@ -186,14 +262,82 @@ func (q *queue) start() error {
 		select {
 		case <-q.ctx.Done():
 			return q.ctx.Err()
+		case <-q.done:
+			return nil
 		case <-q.ready:
-			q.signal(q.ctx)
+			q.dispatch(q.ctx)
 		case <-time.After(q.interval):
-			q.signal(q.ctx)
+			q.dispatch(q.ctx)
 		}
 	}
 }
 
+// dispatch signals the queue to dispatch pending stages to
+// the waiting workers. If the pending stages cannot be listed,
+// for example because the database is temporarily unavailable,
+// the signal is retried with exponential backoff so that
+// dispatching is not stalled until the next interval.
+func (q *queue) dispatch(ctx context.Context) {
+	stages, builds := q.pending()
+	for i := 0; ; i++ {
+		err := q.signal(ctx)
+		if err == nil {
+			q.dispatched(stages)
+			return
+		}
+		metric.QueueError()
+		logger := logrus.WithError(err).
+			WithField("attempt", i+1).
+			WithField("stage.ids", stages).
+			WithField("build.ids", builds)
+		if i+1 >= maxRetries {
+			logger.Errorln("queue: cannot dispatch pending stages")
+			return
+		}
+		logger.Warnln("queue: cannot dispatch pending stages, retrying")
+
+		backoff := q.backoff << uint(i)
+		if backoff > q.interval {
+			backoff = q.interval
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-q.done:
+			return
+		case <-time.After(backoff):
+		}
+	}
+}
+
+// pending returns the IDs of the stages, and their builds,
+// that were scheduled and are waiting to be dispatched.
+func (q *queue) pending() (stages, builds []int64) {
+	q.Lock()
+	defer q.Unlock()
+	seen := map[int64]bool{}
+	for stage, build := range q.scheduled {
+		stages = append(stages, stage)
+		if !seen[build] {
+			seen[build] = true
+			builds = append(builds, build)
+		}
+	}
+	sort.Slice(stages, func(i, j int) bool { return stages[i] < stages[j] })
+	sort.Slice(builds, func(i, j int) bool { return builds[i] < builds[j] })
+	return stages, builds
+}
+
+// dispatched removes the dispatched stages from the list of
+// stages waiting to be dispatched.
+func (q *queue) dispatched(stages []int64) {
+	q.Lock()
+	for _, stage := range stages {
+		delete(q.scheduled, stage)
+	}
+	q.Unlock()
+}
+
 type worker struct {
 	os      string
 	arch    string
@@ -201,6 +345,7 @@ type worker struct {
 	variant string
 	labels  map[string]string
 	channel chan *core.Stage
+	done    <-chan struct{}
 }
 
 type counter struct {
@@ -220,6 +365,9 @@ func checkLabels(a, b map[string]string) bool {
 }
 
 func withinLimits(stage *core.Stage, siblings []*core.Stage) bool {
+	if withinRepoLimits(stage, siblings) == false {
+		return false
+	}
 	if stage.Limit == 0 {
 		return true
 	}
@@ -240,3 +388,23 @@ func withinLimits(stage *core.Stage, siblings []*core.Stage) bool {
 	}
 	return count < stage.Limit
 }
+
+// helper function returns true if the number of builds for
+// the repository, created before the stage build, does not
+// exceed the repository concurrency limit. Stages that belong
+// to the same build do not count towards the limit.
+func withinRepoLimits(stage *core.Stage, siblings []*core.Stage) bool {
+	if stage.LimitRepo == 0 {
+		return true
+	}
+	builds := map[int64]struct{}{}
+	for _, sibling := range siblings {
+		if sibling.RepoID != stage.RepoID {
+			continue
+		}
+		if sibling.BuildID < stage.BuildID {
+			builds[sibling.BuildID] = struct{}{}
+		}
+	}
+	return len(builds) < stage.LimitRepo
+}
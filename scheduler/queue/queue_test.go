@@ -6,6 +6,7 @@ package queue
 
 import (
 	"context"
+	"database/sql"
 	"sync"
 	"testing"
 	"time"
@@ -14,6 +15,7 @@ import (
 	"github.com/drone/drone/mock"
 
 	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
 )
 
 func TestQueue(t *testing.T) {
@@ -32,7 +34,7 @@ func TestQueue(t *testing.T) {
 	store.EXPECT().ListIncomplete(ctx).Return(items[1:], nil).Times(1)
 	store.EXPECT().ListIncomplete(ctx).Return(items[2:], nil).Times(1)
 
-	q := newQueue(store)
+	q := newQueue(store, time.Minute)
 	for _, item := range items {
 		next, err := q.Request(ctx, core.Filter{OS: "linux", Arch: "amd64"})
 		if err != nil {
@@ -53,7 +55,7 @@ func TestQueueCancel(t *testing.T) {
 	store := mock.NewMockStageStore(controller)
 	store.EXPECT().ListIncomplete(ctx).Return(nil, nil)
 
-	q := newQueue(store)
+	q := newQueue(store, time.Minute)
 	q.ctx = ctx
 
 	var wg sync.WaitGroup
@@ -83,6 +85,59 @@ func TestQueueCancel(t *testing.T) {
 	wg.Wait()
 }
 
+func TestQueueShutdown(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	ctx := context.Background()
+	store := mock.NewMockStageStore(controller)
+	store.EXPECT().ListIncomplete(ctx).Return(nil, nil).AnyTimes()
+
+	q := newQueue(store, time.Minute)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+
+	go func() {
+		stage, err := q.Request(ctx, core.Filter{OS: "linux", Arch: "amd64"})
+		if err != nil {
+			t.Errorf("Expect nil error on shutdown, got %s", err)
+		}
+		if stage != nil {
+			t.Errorf("Expect nil stage on shutdown")
+		}
+		wg.Done()
+	}()
+	<-time.After(10 * time.Millisecond)
+
+	if err := q.Shutdown(ctx); err != nil {
+		t.Error(err)
+	}
+	wg.Wait()
+
+	q.Lock()
+	count := len(q.workers)
+	q.Unlock()
+
+	if got, want := count, 0; got != want {
+		t.Errorf("Want %d listener, got %d", want, got)
+	}
+
+	// subsequent requests return immediately.
+	stage, err := q.Request(ctx, core.Filter{OS: "linux", Arch: "amd64"})
+	if err != nil {
+		t.Error(err)
+	}
+	if stage != nil {
+		t.Errorf("Expect nil stage after shutdown")
+	}
+
+	// shutting down a second time is a no-op.
+	if err := q.Shutdown(ctx); err != nil {
+		t.Error(err)
+	}
+}
+
 func TestQueuePush(t *testing.T) {
 	controller := gomock.NewController(t)
 	defer controller.Finish()
@@ -114,6 +169,87 @@ func TestQueuePush(t *testing.T) {
 	}
 }
 
+func TestQueueSignal(t *testing.T) {
+	q := &queue{
+		ready: make(chan struct{}, 1),
+	}
+	q.Signal(context.Background())
+	select {
+	case <-q.ready:
+	case <-time.After(time.Millisecond):
+		t.Errorf("Expect queue signaled")
+	}
+}
+
+func TestQueueInterval(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	store := mock.NewMockStageStore(controller)
+
+	q := newQueue(store, 0)
+	defer q.Shutdown(context.Background())
+	if got, want := q.interval, time.Minute; got != want {
+		t.Errorf("Want default interval %s, got %s", want, got)
+	}
+}
+
+// this test verifies that the queue retries dispatching
+// pending stages when the datastore is temporarily
+// unavailable, instead of waiting for the next interval.
+func TestQueueRetry(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	item := &core.Stage{ID: 1, OS: "linux", Arch: "amd64"}
+
+	ctx := context.Background()
+	store := mock.NewMockStageStore(controller)
+	store.EXPECT().ListIncomplete(ctx).Return(nil, sql.ErrConnDone).Times(2)
+	store.EXPECT().ListIncomplete(ctx).Return([]*core.Stage{item}, nil)
+
+	q := newQueue(store, time.Minute)
+	q.backoff = time.Millisecond
+
+	next, err := q.Request(ctx, core.Filter{OS: "linux", Arch: "amd64"})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if next != item {
+		t.Errorf("Want stage dispatched after retry")
+	}
+}
+
+// this test verifies that the queue tracks the scheduled
+// stages, so that dispatch errors identify the affected
+// stages and builds.
+func TestQueuePending(t *testing.T) {
+	ctx := context.Background()
+
+	// the queue is not started, which verifies the scheduled
+	// stages are tracked until they are dispatched.
+	q := &queue{
+		ready: make(chan struct{}, 1),
+	}
+	q.Schedule(ctx, &core.Stage{ID: 3, BuildID: 2})
+	q.Schedule(ctx, &core.Stage{ID: 2, BuildID: 1})
+	q.Schedule(ctx, &core.Stage{ID: 1, BuildID: 1})
+
+	stages, builds := q.pending()
+	if diff := cmp.Diff(stages, []int64{1, 2, 3}); diff != "" {
+		t.Errorf(diff)
+	}
+	if diff := cmp.Diff(builds, []int64{1, 2}); diff != "" {
+		t.Errorf(diff)
+	}
+
+	q.dispatched(stages)
+	if stages, _ := q.pending(); len(stages) != 0 {
+		t.Errorf("Want dispatched stages removed, got %v", stages)
+	}
+}
+
 func TestWithinLimits(t *testing.T) {
 	tests := []struct {
 		ID     int64
@@ -149,3 +285,35 @@ func TestWithinLimits(t *testing.T) {
 		}
 	}
 }
+
+func TestWithinRepoLimits(t *testing.T) {
+	tests := []struct {
+		ID      int64
+		RepoID  int64
+		BuildID int64
+		Limit   int
+		Want    bool
+	}{
+		{Want: true, ID: 1, RepoID: 1, BuildID: 1, Limit: 2},
+		{Want: true, ID: 2, RepoID: 1, BuildID: 1, Limit: 2},
+		{Want: true, ID: 3, RepoID: 2, BuildID: 2, Limit: 1},
+		{Want: true, ID: 4, RepoID: 1, BuildID: 3, Limit: 2},
+		{Want: false, ID: 5, RepoID: 1, BuildID: 4, Limit: 2},
+		{Want: true, ID: 6, RepoID: 1, BuildID: 5, Limit: 0},
+	}
+	var stages []*core.Stage
+	for _, test := range tests {
+		stages = append(stages, &core.Stage{
+			ID:        test.ID,
+			RepoID:    test.RepoID,
+			BuildID:   test.BuildID,
+			LimitRepo: test.Limit,
+		})
+	}
+	for i, test := range tests {
+		stage := stages[i]
+		if got, want := withinLimits(stage, stages), test.Want; got != want {
+			t.Errorf("Unexpectd results at index %d", i)
+		}
+	}
+}
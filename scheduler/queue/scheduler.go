@@ -7,6 +7,7 @@ package queue
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/drone/drone/core"
 )
@@ -16,10 +17,11 @@ type scheduler struct {
 	*canceller
 }
 
-// New creates a new scheduler.
-func New(store core.StageStore) core.Scheduler {
+// New creates a new scheduler. The scheduler polls the
+// datastore for pending stages at the interval.
+func New(store core.StageStore, interval time.Duration) core.Scheduler {
 	return &scheduler{
-		queue:     newQueue(store),
+		queue:     newQueue(store, interval),
 		canceller: newCanceller(),
 	}
 }
@@ -25,6 +25,11 @@ type Server struct {
 	Key     string
 	Host    string
 	Handler http.Handler
+
+	// TLS provides the tls configuration used to verify
+	// agent certificates signed by the rpc certificate
+	// authority. Optional.
+	TLS *tls.Config
 }
 
 // ListenAndServe initializes a server to respond to HTTP network requests.
@@ -62,8 +67,9 @@ func (s Server) listenAndServeTLS(ctx context.Context) error {
 		Handler: http.HandlerFunc(redirect),
 	}
 	s2 := &http.Server{
-		Addr:    ":https",
-		Handler: s.Handler,
+		Addr:      ":https",
+		Handler:   s.Handler,
+		TLSConfig: s.TLS,
 	}
 	g.Go(func() error {
 		return s1.ListenAndServe()
@@ -107,6 +113,10 @@ func (s Server) listenAndServeAcme(ctx context.Context) error {
 			MinVersion:     tls.VersionTLS12,
 		},
 	}
+	if s.TLS != nil {
+		s2.TLSConfig.ClientCAs = s.TLS.ClientCAs
+		s2.TLSConfig.ClientAuth = s.TLS.ClientAuth
+	}
 	g.Go(func() error {
 		return s1.ListenAndServe()
 	})
@@ -18,6 +18,7 @@ package server
 
 import (
 	"context"
+	"crypto/tls"
 	"net/http"
 
 	"golang.org/x/sync/errgroup"
@@ -31,6 +32,11 @@ type Server struct {
 	Key     string
 	Host    string
 	Handler http.Handler
+
+	// TLS provides the tls configuration used to verify
+	// agent certificates signed by the rpc certificate
+	// authority. Optional.
+	TLS *tls.Config
 }
 
 // ListenAndServe initializes a server to respond to HTTP network requests.
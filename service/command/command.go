@@ -0,0 +1,248 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package command
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/logger"
+	"github.com/drone/go-scm/scm"
+)
+
+// Config configures the Command service.
+type Config struct {
+	// Base is the server address, used to link the
+	// build in the pull request comment.
+	Base string
+
+	// Commands is the list of enabled commands. If the
+	// list is empty, pull request comments are ignored.
+	Commands []string
+}
+
+// New returns a new CommandService.
+func New(
+	client *scm.Client,
+	builds core.BuildStore,
+	perms core.PermStore,
+	users core.UserStore,
+	renew core.Renewer,
+	triggerer core.Triggerer,
+	config Config,
+) core.CommandService {
+	return &service{
+		client:    client,
+		builds:    builds,
+		perms:     perms,
+		users:     users,
+		renew:     renew,
+		triggerer: triggerer,
+		base:      config.Base,
+		commands:  config.Commands,
+	}
+}
+
+type service struct {
+	client    *scm.Client
+	builds    core.BuildStore
+	perms     core.PermStore
+	users     core.UserStore
+	renew     core.Renewer
+	triggerer core.Triggerer
+	base      string
+	commands  []string
+}
+
+func (s *service) Exec(ctx context.Context, repo *core.Repository, hook *core.Hook) (*core.Build, error) {
+	logger := logger.FromContext(ctx)
+
+	command := core.ParseCommand(hook.Message)
+	if command == nil || !s.enabled(command.Name) {
+		logger.Debugln("command: ignore comment, no enabled command")
+		return nil, nil
+	}
+
+	// the sender must be a registered user with write access
+	// to restart a build, or admin access to promote a build.
+	sender, err := s.users.FindLogin(ctx, hook.Sender)
+	if err != nil {
+		logger.WithError(err).
+			WithField("sender", hook.Sender).
+			Debugln("command: ignore comment, cannot find sender")
+		return nil, nil
+	}
+	if !s.authorized(ctx, repo, sender, command) {
+		logger.WithField("sender", hook.Sender).
+			WithField("command", command.Name).
+			Debugln("command: ignore comment, sender not authorized")
+		return nil, nil
+	}
+
+	prev, err := s.builds.FindRef(ctx, repo.ID, hook.Ref)
+	if err != nil {
+		logger.WithError(err).
+			WithField("ref", hook.Ref).
+			Debugln("command: ignore comment, cannot find build")
+		return nil, nil
+	}
+
+	next := &core.Hook{
+		Parent:       prev.Number,
+		Trigger:      sender.Login,
+		Event:        prev.Event,
+		Action:       prev.Action,
+		Link:         prev.Link,
+		Timestamp:    prev.Timestamp,
+		Title:        prev.Title,
+		Message:      prev.Message,
+		Before:       prev.Before,
+		After:        prev.After,
+		Ref:          prev.Ref,
+		Source:       prev.Source,
+		Target:       prev.Target,
+		Author:       prev.Author,
+		AuthorName:   prev.AuthorName,
+		AuthorEmail:  prev.AuthorEmail,
+		AuthorAvatar: prev.AuthorAvatar,
+		Sender:       prev.Sender,
+		Params:       map[string]string{},
+	}
+
+	switch command.Name {
+	case core.CommandRetest:
+		switch prev.Status {
+		case core.StatusBlocked, core.StatusDeclined:
+			logger.WithField("status", prev.Status).
+				Debugln("command: ignore comment, cannot restart build")
+			return nil, nil
+		}
+		for key, value := range prev.Params {
+			next.Params[key] = value
+		}
+	case core.CommandDeploy:
+		if len(command.Args) == 0 {
+			logger.Debugln("command: ignore comment, missing target environment")
+			return nil, nil
+		}
+		next.Event = core.EventPromote
+		next.Deployment = command.Args[0]
+		for _, arg := range command.Args[1:] {
+			parts := strings.SplitN(arg, "=", 2)
+			if len(parts) == 2 {
+				next.Params[parts[0]] = parts[1]
+			}
+		}
+	}
+
+	build, err := s.triggerer.Trigger(ctx, repo, next)
+	if err != nil {
+		return nil, err
+	}
+
+	// failure to post the comment should not fail the
+	// command, since the build has already been created.
+	if err := s.comment(ctx, repo, hook, build); err != nil {
+		logger.WithError(err).
+			Warnln("command: cannot create pull request comment")
+	}
+	return build, nil
+}
+
+// helper function returns true if the named command is
+// enabled.
+func (s *service) enabled(name string) bool {
+	for _, command := range s.commands {
+		if strings.EqualFold(command, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// helper function returns true if the user is authorized
+// to execute the command.
+func (s *service) authorized(ctx context.Context, repo *core.Repository, user *core.User, command *core.Command) bool {
+	if user.Admin {
+		return true
+	}
+	perm, err := s.perms.Find(ctx, repo.UID, user.ID)
+	if err != nil {
+		return false
+	}
+	switch command.Name {
+	case core.CommandRetest:
+		return perm.Write
+	case core.CommandDeploy:
+		return perm.Admin
+	default:
+		return false
+	}
+}
+
+// helper function posts a comment to the pull request with
+// a link to the build, using the repository owner token.
+func (s *service) comment(ctx context.Context, repo *core.Repository, hook *core.Hook, build *core.Build) error {
+	// comments cannot be posted to repositories hosted by
+	// additional providers, since the client is configured
+	// for the default provider.
+	if repo.Provider != "" || build == nil {
+		return nil
+	}
+	number := pullRequestNumber(hook.Ref)
+	if number == 0 {
+		return nil
+	}
+	user, err := s.users.Find(ctx, repo.UserID)
+	if err != nil {
+		return err
+	}
+	err = s.renew.Renew(ctx, user, false)
+	if err != nil {
+		return err
+	}
+	ctx = context.WithValue(ctx, scm.TokenKey{}, &scm.Token{
+		Token:   user.Token,
+		Refresh: user.Refresh,
+	})
+	in := &scm.CommentInput{
+		Body: fmt.Sprintf("Build [#%d](%s/%s/%d) started by @%s.",
+			build.Number,
+			s.base,
+			repo.Slug,
+			build.Number,
+			hook.Sender,
+		),
+	}
+	_, _, err = s.client.PullRequests.CreateComment(ctx, repo.Slug, number, in)
+	if err == scm.ErrNotSupported {
+		return nil
+	}
+	return err
+}
+
+// helper function extracts the pull request number from the
+// pull request reference (e.g. refs/pull/42/head).
+func pullRequestNumber(ref string) int {
+	parts := strings.Split(ref, "/")
+	if len(parts) != 4 || parts[0] != "refs" {
+		return 0
+	}
+	number, _ := strconv.Atoi(parts[2])
+	return number
+}
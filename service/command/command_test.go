@@ -0,0 +1,311 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package command
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/mock"
+	"github.com/drone/drone/mock/mockscm"
+	"github.com/drone/go-scm/scm"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+)
+
+var noContext = context.Background()
+
+var (
+	mockRepo = &core.Repository{
+		ID:     1,
+		UID:    "42",
+		UserID: 2,
+		Slug:   "octocat/hello-world",
+	}
+
+	mockSender = &core.User{
+		ID:    3,
+		Login: "spaceghost",
+	}
+
+	mockOwner = &core.User{
+		ID:    2,
+		Login: "octocat",
+		Token: "755bb80e5b",
+	}
+
+	mockBuild = &core.Build{
+		Number: 41,
+		Status: core.StatusFailing,
+		Event:  core.EventPullRequest,
+		Action: core.ActionOpen,
+		After:  "a6586b3db244fb6b1198f2b25c213ded5b44f9fa",
+		Ref:    "refs/pull/42/head",
+		Source: "feature",
+		Target: "master",
+		Sender: "octocat",
+		Params: map[string]string{"foo": "bar"},
+	}
+
+	mockConfig = Config{
+		Base:     "https://drone.company.com",
+		Commands: []string{core.CommandRetest, core.CommandDeploy},
+	}
+)
+
+func TestExec_Retest(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	hook := &core.Hook{
+		Action:  core.ActionComment,
+		Message: "/retest",
+		Ref:     "refs/pull/42/head",
+		Sender:  "spaceghost",
+	}
+
+	want := &core.Hook{
+		Parent:  mockBuild.Number,
+		Trigger: mockSender.Login,
+		Event:   mockBuild.Event,
+		Action:  mockBuild.Action,
+		After:   mockBuild.After,
+		Ref:     mockBuild.Ref,
+		Source:  mockBuild.Source,
+		Target:  mockBuild.Target,
+		Sender:  mockBuild.Sender,
+		Params:  map[string]string{"foo": "bar"},
+	}
+
+	users := mock.NewMockUserStore(controller)
+	users.EXPECT().FindLogin(gomock.Any(), "spaceghost").Return(mockSender, nil)
+	users.EXPECT().Find(gomock.Any(), mockRepo.UserID).Return(mockOwner, nil)
+
+	perms := mock.NewMockPermStore(controller)
+	perms.EXPECT().Find(gomock.Any(), mockRepo.UID, mockSender.ID).Return(&core.Perm{Read: true, Write: true}, nil)
+
+	builds := mock.NewMockBuildStore(controller)
+	builds.EXPECT().FindRef(gomock.Any(), mockRepo.ID, "refs/pull/42/head").Return(mockBuild, nil)
+
+	triggerer := mock.NewMockTriggerer(controller)
+	triggerer.EXPECT().Trigger(gomock.Any(), mockRepo, gomock.Any()).Do(func(_ context.Context, _ *core.Repository, got *core.Hook) {
+		if diff := cmp.Diff(got, want); diff != "" {
+			t.Errorf(diff)
+		}
+	}).Return(&core.Build{Number: 42}, nil)
+
+	renewer := mock.NewMockRenewer(controller)
+	renewer.EXPECT().Renew(gomock.Any(), mockOwner, false).Return(nil)
+
+	comment := &scm.CommentInput{
+		Body: "Build [#42](https://drone.company.com/octocat/hello-world/42) started by @spaceghost.",
+	}
+	pulls := mockscm.NewMockPullRequestService(controller)
+	pulls.EXPECT().CreateComment(gomock.Any(), "octocat/hello-world", 42, comment).Return(nil, nil, nil)
+
+	client := new(scm.Client)
+	client.PullRequests = pulls
+
+	service := New(client, builds, perms, users, renewer, triggerer, mockConfig)
+	build, err := service.Exec(noContext, mockRepo, hook)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if build == nil || build.Number != 42 {
+		t.Errorf("Want build number 42")
+	}
+}
+
+func TestExec_Deploy(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	hook := &core.Hook{
+		Action:  core.ActionComment,
+		Message: "looks good to me\n/deploy production region=us-east-1",
+		Ref:     "refs/pull/42/head",
+		Sender:  "spaceghost",
+	}
+
+	want := &core.Hook{
+		Parent:     mockBuild.Number,
+		Trigger:    mockSender.Login,
+		Event:      core.EventPromote,
+		Action:     mockBuild.Action,
+		After:      mockBuild.After,
+		Ref:        mockBuild.Ref,
+		Source:     mockBuild.Source,
+		Target:     mockBuild.Target,
+		Sender:     mockBuild.Sender,
+		Deployment: "production",
+		Params:     map[string]string{"region": "us-east-1"},
+	}
+
+	users := mock.NewMockUserStore(controller)
+	users.EXPECT().FindLogin(gomock.Any(), "spaceghost").Return(mockSender, nil)
+	users.EXPECT().Find(gomock.Any(), mockRepo.UserID).Return(mockOwner, nil)
+
+	perms := mock.NewMockPermStore(controller)
+	perms.EXPECT().Find(gomock.Any(), mockRepo.UID, mockSender.ID).Return(&core.Perm{Read: true, Write: true, Admin: true}, nil)
+
+	builds := mock.NewMockBuildStore(controller)
+	builds.EXPECT().FindRef(gomock.Any(), mockRepo.ID, "refs/pull/42/head").Return(mockBuild, nil)
+
+	triggerer := mock.NewMockTriggerer(controller)
+	triggerer.EXPECT().Trigger(gomock.Any(), mockRepo, gomock.Any()).Do(func(_ context.Context, _ *core.Repository, got *core.Hook) {
+		if diff := cmp.Diff(got, want); diff != "" {
+			t.Errorf(diff)
+		}
+	}).Return(&core.Build{Number: 42}, nil)
+
+	renewer := mock.NewMockRenewer(controller)
+	renewer.EXPECT().Renew(gomock.Any(), mockOwner, false).Return(nil)
+
+	pulls := mockscm.NewMockPullRequestService(controller)
+	pulls.EXPECT().CreateComment(gomock.Any(), "octocat/hello-world", 42, gomock.Any()).Return(nil, nil, scm.ErrNotSupported)
+
+	client := new(scm.Client)
+	client.PullRequests = pulls
+
+	service := New(client, builds, perms, users, renewer, triggerer, mockConfig)
+	build, err := service.Exec(noContext, mockRepo, hook)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if build == nil {
+		t.Errorf("Want build")
+	}
+}
+
+// this test verifies that deploy commands require admin
+// access to the repository.
+func TestExec_Unauthorized(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	hook := &core.Hook{
+		Action:  core.ActionComment,
+		Message: "/deploy production",
+		Ref:     "refs/pull/42/head",
+		Sender:  "spaceghost",
+	}
+
+	users := mock.NewMockUserStore(controller)
+	users.EXPECT().FindLogin(gomock.Any(), "spaceghost").Return(mockSender, nil)
+
+	perms := mock.NewMockPermStore(controller)
+	perms.EXPECT().Find(gomock.Any(), mockRepo.UID, mockSender.ID).Return(&core.Perm{Read: true, Write: true}, nil)
+
+	service := New(nil, nil, perms, users, nil, nil, mockConfig)
+	build, err := service.Exec(noContext, mockRepo, hook)
+	if err != nil {
+		t.Error(err)
+	}
+	if build != nil {
+		t.Errorf("Want nil build when sender is not authorized")
+	}
+}
+
+// this test verifies that comments from unknown users
+// are ignored.
+func TestExec_UnknownSender(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	hook := &core.Hook{
+		Action:  core.ActionComment,
+		Message: "/retest",
+		Sender:  "spaceghost",
+	}
+
+	users := mock.NewMockUserStore(controller)
+	users.EXPECT().FindLogin(gomock.Any(), "spaceghost").Return(nil, errors.New("not found"))
+
+	service := New(nil, nil, nil, users, nil, nil, mockConfig)
+	build, err := service.Exec(noContext, mockRepo, hook)
+	if err != nil {
+		t.Error(err)
+	}
+	if build != nil {
+		t.Errorf("Want nil build when sender is unknown")
+	}
+}
+
+// this test verifies that commands are ignored unless
+// they are enabled in the configuration.
+func TestExec_Disabled(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	hook := &core.Hook{
+		Action:  core.ActionComment,
+		Message: "/deploy production",
+		Sender:  "spaceghost",
+	}
+
+	service := New(nil, nil, nil, nil, nil, nil, Config{Commands: []string{core.CommandRetest}})
+	build, err := service.Exec(noContext, mockRepo, hook)
+	if err != nil {
+		t.Error(err)
+	}
+	if build != nil {
+		t.Errorf("Want nil build when command is disabled")
+	}
+}
+
+// this test verifies that the build is returned when the
+// pull request comment cannot be created.
+func TestExec_CommentError(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	hook := &core.Hook{
+		Action:  core.ActionComment,
+		Message: "/retest",
+		Ref:     "refs/pull/42/head",
+		Sender:  "spaceghost",
+	}
+
+	users := mock.NewMockUserStore(controller)
+	users.EXPECT().FindLogin(gomock.Any(), "spaceghost").Return(&core.User{ID: 3, Login: "spaceghost", Admin: true}, nil)
+	users.EXPECT().Find(gomock.Any(), mockRepo.UserID).Return(nil, errors.New("not found"))
+
+	builds := mock.NewMockBuildStore(controller)
+	builds.EXPECT().FindRef(gomock.Any(), mockRepo.ID, "refs/pull/42/head").Return(mockBuild, nil)
+
+	triggerer := mock.NewMockTriggerer(controller)
+	triggerer.EXPECT().Trigger(gomock.Any(), mockRepo, gomock.Any()).Return(&core.Build{Number: 42}, nil)
+
+	service := New(nil, builds, nil, users, nil, triggerer, mockConfig)
+	build, err := service.Exec(noContext, mockRepo, hook)
+	if err != nil {
+		t.Error(err)
+	}
+	if build == nil {
+		t.Errorf("Want build")
+	}
+}
+
+func Test_pullRequestNumber(t *testing.T) {
+	tests := []struct {
+		ref    string
+		number int
+	}{
+		{"refs/pull/42/head", 42},
+		{"refs/merge-requests/42/head", 42},
+		{"refs/heads/master", 0},
+		{"", 0},
+	}
+	for _, test := range tests {
+		if got, want := pullRequestNumber(test.ref), test.number; got != want {
+			t.Errorf("Want pull request number %d for ref %q, got %d", want, test.ref, got)
+		}
+	}
+}
@@ -0,0 +1,175 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package etag provides an http.RoundTripper that caches
+// source control management API responses, and revalidates
+// the cached responses using conditional requests. Unchanged
+// resources are returned from the cache when the server
+// responds with 304 Not Modified, which most providers do
+// not count against the rate limit.
+package etag
+
+import (
+	"bytes"
+	"container/list"
+	"crypto/sha256"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sync"
+)
+
+// Transport returns a new http.RoundTripper that caches up
+// to size bytes of response bodies.
+func Transport(base http.RoundTripper, size int64) http.RoundTripper {
+	return &transport{
+		base:    base,
+		size:    size,
+		list:    list.New(),
+		entries: map[string]*list.Element{},
+	}
+}
+
+type transport struct {
+	sync.Mutex
+
+	base    http.RoundTripper
+	size    int64
+	used    int64
+	list    *list.List
+	entries map[string]*list.Element
+}
+
+type entry struct {
+	key    string
+	etag   string
+	header http.Header
+	body   []byte
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != "GET" || req.Header.Get("If-None-Match") != "" {
+		return t.base.RoundTrip(req)
+	}
+
+	key := cacheKey(req)
+	cached := t.get(key)
+	if cached != nil {
+		req = cloneRequest(req)
+		req.Header.Set("If-None-Match", cached.etag)
+	}
+
+	res, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case res.StatusCode == 304 && cached != nil:
+		res.Body.Close()
+		// the headers of the not modified response, such
+		// as the rate limit headers, replace the headers
+		// of the cached response.
+		header := cloneHeader(cached.header)
+		for k, v := range res.Header {
+			header[k] = v
+		}
+		res.StatusCode = 200
+		res.Status = "200 OK"
+		res.Header = header
+		res.Body = ioutil.NopCloser(bytes.NewReader(cached.body))
+		res.ContentLength = int64(len(cached.body))
+		return res, nil
+	case res.StatusCode == 200 && res.Header.Get("ETag") != "":
+		body, err := ioutil.ReadAll(res.Body)
+		res.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		res.Body = ioutil.NopCloser(bytes.NewReader(body))
+		t.add(&entry{
+			key:    key,
+			etag:   res.Header.Get("ETag"),
+			header: cloneHeader(res.Header),
+			body:   body,
+		})
+		return res, nil
+	default:
+		return res, nil
+	}
+}
+
+// helper function returns the cached response for the key,
+// and marks the cached response as recently used.
+func (t *transport) get(key string) *entry {
+	t.Lock()
+	defer t.Unlock()
+	elem, ok := t.entries[key]
+	if !ok {
+		return nil
+	}
+	t.list.MoveToFront(elem)
+	return elem.Value.(*entry)
+}
+
+// helper function adds the response to the cache, evicting
+// the least recently used responses when the cache exceeds
+// the maximum size.
+func (t *transport) add(e *entry) {
+	t.Lock()
+	defer t.Unlock()
+	if elem, ok := t.entries[e.key]; ok {
+		t.remove(elem)
+	}
+	if int64(len(e.body)) > t.size {
+		return
+	}
+	t.entries[e.key] = t.list.PushFront(e)
+	t.used += int64(len(e.body))
+	for t.used > t.size {
+		t.remove(t.list.Back())
+	}
+}
+
+func (t *transport) remove(elem *list.Element) {
+	e := t.list.Remove(elem).(*entry)
+	delete(t.entries, e.key)
+	t.used -= int64(len(e.body))
+}
+
+// helper function returns the cache key for the request.
+// The key includes a hash of the authorization header, to
+// prevent responses from being shared between users.
+func cacheKey(req *http.Request) string {
+	auth := sha256.Sum256([]byte(req.Header.Get("Authorization")))
+	return fmt.Sprintf("%x/%s", auth, req.URL.String())
+}
+
+// helper function returns a copy of the request with a copy
+// of the request headers, since a RoundTripper must not
+// modify the original request.
+func cloneRequest(req *http.Request) *http.Request {
+	clone := new(http.Request)
+	*clone = *req
+	clone.Header = cloneHeader(req.Header)
+	return clone
+}
+
+func cloneHeader(h http.Header) http.Header {
+	clone := make(http.Header, len(h))
+	for k, v := range h {
+		clone[k] = append([]string(nil), v...)
+	}
+	return clone
+}
@@ -0,0 +1,108 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package etag
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestTransport(t *testing.T) {
+	var requests, revalidated int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("X-RateLimit-Remaining", "4999")
+		if r.Header.Get("If-None-Match") == `"abc"` {
+			revalidated++
+			w.WriteHeader(304)
+			return
+		}
+		w.Header().Set("ETag", `"abc"`)
+		w.Header().Set("Link", `<https://api.github.com/user/repos?page=2>; rel="next"`)
+		w.Write([]byte(`[{"name":"hello-world"}]`))
+	}))
+	defer ts.Close()
+
+	client := &http.Client{Transport: Transport(http.DefaultTransport, 1024)}
+	for i := 0; i < 2; i++ {
+		req, _ := http.NewRequest("GET", ts.URL+"/user/repos", nil)
+		req.Header.Set("Authorization", "Bearer 3da541559")
+		res, err := client.Do(req)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		body, _ := ioutil.ReadAll(res.Body)
+		res.Body.Close()
+
+		if got, want := res.StatusCode, 200; got != want {
+			t.Errorf("Want status code %d, got %d", want, got)
+		}
+		if got, want := string(body), `[{"name":"hello-world"}]`; got != want {
+			t.Errorf("Want body %q, got %q", want, got)
+		}
+		if res.Header.Get("Link") == "" {
+			t.Errorf("Want cached pagination headers")
+		}
+	}
+	if got, want := requests, 2; got != want {
+		t.Errorf("Want %d requests, got %d", want, got)
+	}
+	if got, want := revalidated, 1; got != want {
+		t.Errorf("Want %d conditional requests, got %d", want, got)
+	}
+}
+
+func TestTransport_Authorization(t *testing.T) {
+	var revalidated int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") != "" {
+			revalidated++
+		}
+		w.Header().Set("ETag", `"abc"`)
+		w.Write([]byte(`[]`))
+	}))
+	defer ts.Close()
+
+	// responses must not be shared between users with
+	// different authorization credentials.
+	client := &http.Client{Transport: Transport(http.DefaultTransport, 1024)}
+	for _, token := range []string{"3da541559", "8a4f4a3c2"} {
+		req, _ := http.NewRequest("GET", ts.URL+"/user/repos", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		res, err := client.Do(req)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		res.Body.Close()
+	}
+	if got, want := revalidated, 0; got != want {
+		t.Errorf("Want %d conditional requests, got %d", want, got)
+	}
+}
+
+func TestTransport_Evict(t *testing.T) {
+	tr := Transport(http.DefaultTransport, 10).(*transport)
+	tr.add(&entry{key: "a", body: []byte("12345")})
+	tr.add(&entry{key: "b", body: []byte("12345")})
+	tr.get("a")
+	tr.add(&entry{key: "c", body: []byte("12345")})
+	if tr.get("b") != nil {
+		t.Errorf("Want least recently used entry evicted")
+	}
+	if tr.get("a") == nil || tr.get("c") == nil {
+		t.Errorf("Want recently used entries cached")
+	}
+	if got, want := tr.used, int64(10); got != want {
+		t.Errorf("Want %d bytes used, got %d", want, got)
+	}
+	tr.add(&entry{key: "d", body: []byte("12345678901")})
+	if tr.get("d") != nil {
+		t.Errorf("Want entries larger than the cache size ignored")
+	}
+}
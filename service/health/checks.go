@@ -0,0 +1,78 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package health
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/go-scm/scm"
+)
+
+// pinger is implemented by components that can verify the
+// connectivity to the backing storage.
+type pinger interface {
+	Ping(context.Context) error
+}
+
+// checkLogs returns a check that verifies the log store is
+// reachable. Log stores that cannot be pinged are assumed
+// to be healthy.
+func checkLogs(logs core.LogStore) func(context.Context) error {
+	return func(ctx context.Context) error {
+		if p, ok := logs.(pinger); ok {
+			return p.Ping(ctx)
+		}
+		return nil
+	}
+}
+
+// checkPubsub returns a check that verifies the pubsub
+// broker accepts subscriptions.
+func checkPubsub(pubsub core.Pubsub) func(context.Context) error {
+	return func(ctx context.Context) error {
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		_, errc := pubsub.Subscribe(ctx)
+		select {
+		case err := <-errc:
+			return err
+		default:
+			return nil
+		}
+	}
+}
+
+// checkClient returns a check that verifies the source
+// control management system is reachable. Any response
+// other than a server error is considered healthy, since
+// the request is not authenticated.
+func checkClient(client *scm.Client) func(context.Context) error {
+	return func(ctx context.Context) error {
+		res, err := client.Do(ctx, &scm.Request{
+			Method: "GET",
+			Path:   "/",
+		})
+		if err != nil {
+			return err
+		}
+		res.Body.Close()
+		if res.Status >= 500 {
+			return fmt.Errorf("scm: unexpected status code %d", res.Status)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,120 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package health
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/store/shared/db"
+	"github.com/drone/go-scm/scm"
+)
+
+// timeout defines the maximum time a component check may
+// take before the component is considered unhealthy.
+const timeout = time.Second * 5
+
+// check defines a named component check.
+type check struct {
+	name string
+	fn   func(context.Context) error
+}
+
+// New returns a new HealthService.
+func New(
+	db *db.DB,
+	logs core.LogStore,
+	pubsub core.Pubsub,
+	client *scm.Client,
+) core.HealthService {
+	return &service{
+		live: []check{
+			{name: "database", fn: db.Ping},
+			{name: "logs", fn: checkLogs(logs)},
+			{name: "pubsub", fn: checkPubsub(pubsub)},
+		},
+		ready: []check{
+			{name: "scm", fn: checkClient(client)},
+		},
+		timeout: timeout,
+	}
+}
+
+type service struct {
+	live    []check
+	ready   []check
+	timeout time.Duration
+}
+
+func (s *service) Live(ctx context.Context) *core.Health {
+	return s.run(ctx, s.live)
+}
+
+func (s *service) Ready(ctx context.Context) *core.Health {
+	checks := append([]check{}, s.live...)
+	checks = append(checks, s.ready...)
+	return s.run(ctx, checks)
+}
+
+// helper function runs the component checks concurrently
+// and returns the aggregated health status.
+func (s *service) run(ctx context.Context, checks []check) *core.Health {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	health := &core.Health{
+		Status:     core.HealthOK,
+		Components: make([]*core.HealthComponent, len(checks)),
+	}
+
+	var wg sync.WaitGroup
+	for i, c := range checks {
+		wg.Add(1)
+		go func(i int, c check) {
+			defer wg.Done()
+			health.Components[i] = runCheck(ctx, c)
+		}(i, c)
+	}
+	wg.Wait()
+
+	for _, component := range health.Components {
+		if component.Status != core.HealthOK {
+			health.Status = core.HealthError
+		}
+	}
+	return health
+}
+
+// helper function runs the component check and returns
+// the component status.
+func runCheck(ctx context.Context, c check) *core.HealthComponent {
+	start := time.Now()
+	err := c.fn(ctx)
+	component := &core.HealthComponent{
+		Name:    c.name,
+		Status:  core.HealthOK,
+		Latency: int64(time.Since(start) / time.Millisecond),
+	}
+	if err == nil && ctx.Err() != nil {
+		err = ctx.Err()
+	}
+	if err != nil {
+		component.Status = core.HealthError
+		component.Error = err.Error()
+	}
+	return component
+}
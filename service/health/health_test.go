@@ -0,0 +1,151 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package health
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/mock"
+	"github.com/drone/drone/pubsub"
+	"github.com/drone/go-scm/scm"
+
+	"github.com/golang/mock/gomock"
+)
+
+var noContext = context.Background()
+
+func TestLive(t *testing.T) {
+	s := &service{
+		live: []check{
+			{name: "database", fn: func(context.Context) error { return nil }},
+			{name: "logs", fn: func(context.Context) error { return nil }},
+		},
+		ready: []check{
+			{name: "scm", fn: func(context.Context) error { return errors.New("unreachable") }},
+		},
+		timeout: time.Second,
+	}
+	health := s.Live(noContext)
+	if !health.OK() {
+		t.Errorf("Want healthy status, got %s", health.Status)
+	}
+	if got, want := len(health.Components), 2; got != want {
+		t.Errorf("Want %d components, got %d", want, got)
+	}
+}
+
+func TestReady(t *testing.T) {
+	s := &service{
+		live: []check{
+			{name: "database", fn: func(context.Context) error { return nil }},
+		},
+		ready: []check{
+			{name: "scm", fn: func(context.Context) error { return errors.New("unreachable") }},
+		},
+		timeout: time.Second,
+	}
+	health := s.Ready(noContext)
+	if health.OK() {
+		t.Errorf("Want unhealthy status, got %s", health.Status)
+	}
+	if got, want := len(health.Components), 2; got != want {
+		t.Errorf("Want %d components, got %d", want, got)
+		return
+	}
+	if got, want := health.Components[0].Status, core.HealthOK; got != want {
+		t.Errorf("Want database status %s, got %s", want, got)
+	}
+	if got, want := health.Components[1].Status, core.HealthError; got != want {
+		t.Errorf("Want scm status %s, got %s", want, got)
+	}
+	if got, want := health.Components[1].Error, "unreachable"; got != want {
+		t.Errorf("Want scm error %q, got %q", want, got)
+	}
+}
+
+func TestReady_Timeout(t *testing.T) {
+	s := &service{
+		live: []check{
+			{name: "database", fn: func(ctx context.Context) error {
+				<-ctx.Done()
+				return nil
+			}},
+		},
+		timeout: time.Millisecond,
+	}
+	health := s.Ready(noContext)
+	if health.OK() {
+		t.Errorf("Want unhealthy status when the check times out")
+	}
+}
+
+func TestCheckLogs(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	logs := mock.NewMockLogStore(controller)
+	if err := checkLogs(logs)(noContext); err != nil {
+		t.Errorf("Want log stores without ping support to be healthy")
+	}
+
+	err := checkLogs(&mockPinger{err: errors.New("no such bucket")})(noContext)
+	if err == nil {
+		t.Errorf("Want ping error returned")
+	}
+}
+
+func TestCheckPubsub(t *testing.T) {
+	hub := pubsub.New()
+	if err := checkPubsub(hub)(noContext); err != nil {
+		t.Error(err)
+	}
+	// the check must release its subscription.
+	time.Sleep(10 * time.Millisecond)
+	if got, want := hub.Subscribers(), 0; got != want {
+		t.Errorf("Want %d subscribers, got %d", want, got)
+	}
+}
+
+func TestCheckClient(t *testing.T) {
+	tests := []struct {
+		code int
+		fail bool
+	}{
+		{200, false},
+		{401, false},
+		{404, false},
+		{502, true},
+	}
+	for _, test := range tests {
+		code := test.code
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(code)
+		}))
+		client := new(scm.Client)
+		client.BaseURL, _ = url.Parse(ts.URL)
+
+		err := checkClient(client)(noContext)
+		if got, want := err != nil, test.fail; got != want {
+			t.Errorf("Want status code %d failure %v, got %v", code, want, got)
+		}
+		ts.Close()
+	}
+}
+
+type mockPinger struct {
+	core.LogStore
+	err error
+}
+
+func (m *mockPinger) Ping(context.Context) error {
+	return m.err
+}
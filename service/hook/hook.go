@@ -16,6 +16,7 @@ package hook
 
 import (
 	"context"
+	"net/url"
 	"time"
 
 	"github.com/drone/drone/core"
@@ -24,13 +25,23 @@ import (
 
 // New returns a new HookService.
 func New(client *scm.Client, addr string, renew core.Renewer) core.HookService {
-	return &service{client: client, addr: addr, renew: renew}
+	return &service{client: client, addr: addr, target: addr + "/hook", renew: renew}
+}
+
+// NewProvider returns a new HookService for an additional
+// source code management provider. The webhook target includes
+// the provider name, used to route the webhook to the provider
+// parser.
+func NewProvider(client *scm.Client, addr, provider string, renew core.Renewer) core.HookService {
+	target := addr + "/hook?provider=" + url.QueryEscape(provider)
+	return &service{client: client, addr: addr, target: target, renew: renew}
 }
 
 type service struct {
 	renew  core.Renewer
 	client *scm.Client
 	addr   string
+	target string
 }
 
 func (s *service) Create(ctx context.Context, user *core.User, repo *core.Repository) error {
@@ -45,13 +56,14 @@ func (s *service) Create(ctx context.Context, user *core.User, repo *core.Reposi
 	})
 	hook := &scm.HookInput{
 		Name:   "drone",
-		Target: s.addr + "/hook",
+		Target: s.target,
 		Secret: repo.Signer,
 		Events: scm.HookEvents{
-			Branch:      true,
-			PullRequest: true,
-			Push:        true,
-			Tag:         true,
+			Branch:             true,
+			PullRequest:        true,
+			PullRequestComment: true,
+			Push:               true,
+			Tag:                true,
 		},
 	}
 	return replaceHook(ctx, s.client, repo.Slug, hook)
@@ -69,3 +81,20 @@ func (s *service) Delete(ctx context.Context, user *core.User, repo *core.Reposi
 	})
 	return deleteHook(ctx, s.client, repo.Slug, s.addr)
 }
+
+// NewApp returns a new HookService for use with an app
+// installation (e.g. a GitHub App). The app installation
+// delivers webhooks for all repositories, so repository
+// webhooks are not created, and any webhook previously created
+// by the server is removed to prevent duplicate builds.
+func NewApp(client *scm.Client, addr string, renew core.Renewer) core.HookService {
+	return &appService{&service{client: client, addr: addr, target: addr + "/hook", renew: renew}}
+}
+
+type appService struct {
+	*service
+}
+
+func (s *appService) Create(ctx context.Context, user *core.User, repo *core.Repository) error {
+	return s.service.Delete(ctx, user, repo)
+}
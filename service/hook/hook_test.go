@@ -36,10 +36,11 @@ func TestCreate(t *testing.T) {
 		Target: "https://drone.company.com/hook",
 		Secret: "abc123",
 		Events: scm.HookEvents{
-			Branch:      true,
-			PullRequest: true,
-			Push:        true,
-			Tag:         true,
+			Branch:             true,
+			PullRequest:        true,
+			PullRequestComment: true,
+			Push:               true,
+			Tag:                true,
 		},
 	}
 
@@ -127,3 +128,84 @@ func TestDelete_RenewErr(t *testing.T) {
 		t.Errorf("Want not authorized error, got %v", err)
 	}
 }
+
+func TestCreate_App(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockUser := &core.User{}
+	mockHooks := []*scm.Hook{
+		{
+			ID:     "1",
+			Name:   "drone",
+			Target: "https://drone.company.com/hook",
+		},
+	}
+	mockRepo := &core.Repository{
+		Namespace: "octocat",
+		Name:      "hello-world",
+		Slug:      "octocat/hello-world",
+		Signer:    "abc123",
+	}
+
+	mockRenewer := mock.NewMockRenewer(controller)
+	mockRenewer.EXPECT().Renew(gomock.Any(), mockUser, false).Return(nil)
+
+	// the repository webhook is removed, not created, since
+	// webhooks are delivered by the app installation.
+	mockRepos := mockscm.NewMockRepositoryService(controller)
+	mockRepos.EXPECT().ListHooks(gomock.Any(), "octocat/hello-world", gomock.Any()).Return(mockHooks, nil, nil)
+	mockRepos.EXPECT().DeleteHook(gomock.Any(), "octocat/hello-world", "1").Return(nil, nil)
+
+	client := new(scm.Client)
+	client.Repositories = mockRepos
+
+	service := NewApp(client, "https://drone.company.com", mockRenewer)
+	err := service.Create(noContext, mockUser, mockRepo)
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func TestCreate_Provider(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockUser := &core.User{}
+	mockHooks := []*scm.Hook{}
+	mockRepo := &core.Repository{
+		Namespace: "octocat",
+		Name:      "hello-world",
+		Slug:      "octocat/hello-world",
+		Signer:    "abc123",
+	}
+
+	hook := &scm.HookInput{
+		Name:   "drone",
+		Target: "https://drone.company.com/hook?provider=internal",
+		Secret: "abc123",
+		Events: scm.HookEvents{
+			Branch:             true,
+			PullRequest:        true,
+			PullRequestComment: true,
+			Push:               true,
+			Tag:                true,
+		},
+	}
+
+	mockRenewer := mock.NewMockRenewer(controller)
+	mockRenewer.EXPECT().Renew(gomock.Any(), mockUser, false).Return(nil)
+
+	mockRepos := mockscm.NewMockRepositoryService(controller)
+	mockRepos.EXPECT().ListHooks(gomock.Any(), "octocat/hello-world", gomock.Any()).Return(mockHooks, nil, nil)
+	mockRepos.EXPECT().CreateHook(gomock.Any(), "octocat/hello-world", hook).Return(nil, nil, nil)
+
+	client := new(scm.Client)
+	client.Repositories = mockRepos
+
+	service := NewProvider(client, "https://drone.company.com", "internal", mockRenewer)
+	err := service.Create(noContext, mockUser, mockRepo)
+	if err != nil {
+		t.Error(err)
+	}
+}
@@ -29,7 +29,6 @@ import (
 )
 
 // TODO(bradrydzewski): stash, push hook missing link
-// TODO(bradrydzewski): stash, tag hook missing timestamp
 // TODO(bradrydzewski): stash, tag hook missing commit message
 // TODO(bradrydzewski): stash, tag hook missing link
 // TODO(bradrydzewski): stash, pull request hook missing link
@@ -198,12 +197,12 @@ func (p *parser) Parse(req *http.Request, secretFunc func(string) string) (*core
 			Event:        core.EventTag,
 			Action:       core.ActionCreate,
 			Link:         "",
-			Timestamp:    0,
+			Timestamp:    time.Now().Unix(),
 			Message:      "",
 			After:        v.Ref.Sha,
 			Ref:          v.Ref.Name,
-			Source:       v.Ref.Name,
-			Target:       v.Ref.Name,
+			Source:       strings.TrimPrefix(v.Ref.Name, "refs/tags/"),
+			Target:       strings.TrimPrefix(v.Ref.Name, "refs/tags/"),
 			Author:       v.Sender.Login,
 			AuthorName:   v.Sender.Name,
 			AuthorEmail:  v.Sender.Email,
@@ -230,6 +229,9 @@ func (p *parser) Parse(req *http.Request, secretFunc func(string) string) (*core
 		}
 		return hook, repo, nil
 	case *scm.PullRequestHook:
+		// merged and closed pull requests are ignored. Note
+		// that when a pull request is merged the target branch
+		// receives a push hook, which triggers the build.
 		if v.Action != scm.ActionOpen && v.Action != scm.ActionSync {
 			return nil, nil, nil
 		}
@@ -260,7 +262,7 @@ func (p *parser) Parse(req *http.Request, secretFunc func(string) string) (*core
 			AuthorAvatar: v.PullRequest.Author.Avatar,
 			Sender:       v.Sender.Login,
 		}
-		if v.Action != scm.ActionSync {
+		if v.Action == scm.ActionSync {
 			hook.Action = core.ActionSync
 		}
 		// HACK this is a workaround for github. The pull
@@ -283,6 +285,44 @@ func (p *parser) Parse(req *http.Request, secretFunc func(string) string) (*core
 			hook.AuthorAvatar = v.Sender.Avatar
 		}
 		return hook, repo, nil
+	case *scm.PullRequestCommentHook:
+		// only new comments are parsed for slash-commands.
+		// Edited and deleted comments are ignored, as are
+		// comments on closed pull requests.
+		if v.Action != scm.ActionCreate || v.PullRequest.Closed {
+			return nil, nil, nil
+		}
+		// the comment hook does not include the pull request
+		// sha or reference. The reference is derived from the
+		// pull request number, which matches the reference of
+		// the pull request builds (e.g. refs/pull/42/head).
+		hook = &core.Hook{
+			Trigger:      core.TriggerHook,
+			Event:        core.EventPullRequest,
+			Action:       core.ActionComment,
+			Link:         v.PullRequest.Link,
+			Timestamp:    v.Comment.Created.Unix(),
+			Title:        v.PullRequest.Title,
+			Message:      v.Comment.Body,
+			Ref:          fmt.Sprintf("refs/pull/%d/head", v.PullRequest.Number),
+			Author:       v.Comment.Author.Login,
+			AuthorName:   v.Comment.Author.Name,
+			AuthorEmail:  v.Comment.Author.Email,
+			AuthorAvatar: v.Comment.Author.Avatar,
+			Sender:       v.Sender.Login,
+		}
+		repo = &core.Repository{
+			UID:       v.Repo.ID,
+			Namespace: v.Repo.Namespace,
+			Name:      v.Repo.Name,
+			Slug:      scm.Join(v.Repo.Namespace, v.Repo.Name),
+			Link:      v.Repo.Link,
+			Branch:    v.Repo.Branch,
+			Private:   v.Repo.Private,
+			HTTPURL:   v.Repo.Clone,
+			SSHURL:    v.Repo.CloneSSH,
+		}
+		return hook, repo, nil
 	case *scm.BranchHook:
 		if v.Action != scm.ActionCreate {
 			return nil, nil, nil
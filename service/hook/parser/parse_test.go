@@ -3,3 +3,190 @@
 // that can be found in the LICENSE file.
 
 package parser
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/go-scm/scm"
+)
+
+// mockWebhooks is a webhook service that returns the
+// configured payload.
+type mockWebhooks struct {
+	hook scm.Webhook
+}
+
+func (m *mockWebhooks) Parse(*http.Request, scm.SecretFunc) (scm.Webhook, error) {
+	return m.hook, nil
+}
+
+func TestParse_StashTag(t *testing.T) {
+	client := &scm.Client{Driver: scm.DriverStash}
+	client.Webhooks = &mockWebhooks{
+		hook: &scm.TagHook{
+			Action: scm.ActionCreate,
+			Ref: scm.Reference{
+				Name: "v1.0.0",
+				Sha:  "a6586b3db244fb6b1198f2b25c213ded5b44f9fa",
+			},
+			Repo: scm.Repository{Namespace: "PRJ", Name: "my-repo"},
+		},
+	}
+	hook, repo, err := New(client).Parse(nil, nil)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if got, want := hook.Event, core.EventTag; got != want {
+		t.Errorf("Want event %q, got %q", want, got)
+	}
+	if got, want := hook.Ref, "refs/tags/v1.0.0"; got != want {
+		t.Errorf("Want ref %q, got %q", want, got)
+	}
+	if got, want := hook.Target, "v1.0.0"; got != want {
+		t.Errorf("Want target %q, got %q", want, got)
+	}
+	if hook.Timestamp == 0 {
+		t.Errorf("Want timestamp populated")
+	}
+	if got, want := repo.Slug, "PRJ/my-repo"; got != want {
+		t.Errorf("Want slug %q, got %q", want, got)
+	}
+}
+
+func TestParse_StashTagDelete(t *testing.T) {
+	client := &scm.Client{Driver: scm.DriverStash}
+	client.Webhooks = &mockWebhooks{
+		hook: &scm.TagHook{Action: scm.ActionDelete},
+	}
+	hook, _, err := New(client).Parse(nil, nil)
+	if err != nil {
+		t.Error(err)
+	}
+	if hook != nil {
+		t.Errorf("Expect deleted tags ignored")
+	}
+}
+
+func TestParse_StashPullRequest(t *testing.T) {
+	tests := []struct {
+		action scm.Action
+		want   string
+	}{
+		{scm.ActionOpen, core.ActionCreate},
+		{scm.ActionSync, core.ActionSync},
+	}
+	for _, test := range tests {
+		client := &scm.Client{Driver: scm.DriverStash}
+		client.Webhooks = &mockWebhooks{
+			hook: &scm.PullRequestHook{
+				Action: test.action,
+				PullRequest: scm.PullRequest{
+					Number: 2,
+					Title:  "added LICENSE",
+					Sha:    "208b0a5c05eddadad01f2aed8802fe0c3b3eaf5e",
+					Ref:    "refs/pull-requests/2/from",
+					Source: "develop",
+					Target: "master",
+					Fork:   "PRJ/my-repo",
+				},
+				Repo: scm.Repository{Namespace: "PRJ", Name: "my-repo"},
+			},
+		}
+		hook, _, err := New(client).Parse(nil, nil)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if got, want := hook.Event, core.EventPullRequest; got != want {
+			t.Errorf("Want event %q, got %q", want, got)
+		}
+		if got, want := hook.Action, test.want; got != want {
+			t.Errorf("Want action %q, got %q", want, got)
+		}
+		if got, want := hook.Ref, "refs/pull-requests/2/from"; got != want {
+			t.Errorf("Want ref %q, got %q", want, got)
+		}
+		if got, want := hook.Target, "master"; got != want {
+			t.Errorf("Want target %q, got %q", want, got)
+		}
+	}
+}
+
+func TestParse_StashPullRequestDeclined(t *testing.T) {
+	client := &scm.Client{Driver: scm.DriverStash}
+	client.Webhooks = &mockWebhooks{
+		hook: &scm.PullRequestHook{Action: scm.ActionClose},
+	}
+	hook, _, err := New(client).Parse(nil, nil)
+	if err != nil {
+		t.Error(err)
+	}
+	if hook != nil {
+		t.Errorf("Expect declined pull requests ignored")
+	}
+}
+
+func TestParse_PullRequestComment(t *testing.T) {
+	client := &scm.Client{Driver: scm.DriverGitea}
+	client.Webhooks = &mockWebhooks{
+		hook: &scm.PullRequestCommentHook{
+			Action: scm.ActionCreate,
+			PullRequest: scm.PullRequest{
+				Number: 42,
+				Title:  "added LICENSE",
+				Link:   "https://try.gitea.io/octocat/hello-world/pulls/42",
+			},
+			Comment: scm.Comment{
+				Body:   "/retest",
+				Author: scm.User{Login: "octocat"},
+			},
+			Repo:   scm.Repository{Namespace: "octocat", Name: "hello-world"},
+			Sender: scm.User{Login: "octocat"},
+		},
+	}
+	hook, repo, err := New(client).Parse(nil, nil)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if got, want := hook.Event, core.EventPullRequest; got != want {
+		t.Errorf("Want event %q, got %q", want, got)
+	}
+	if got, want := hook.Action, core.ActionComment; got != want {
+		t.Errorf("Want action %q, got %q", want, got)
+	}
+	if got, want := hook.Ref, "refs/pull/42/head"; got != want {
+		t.Errorf("Want ref %q, got %q", want, got)
+	}
+	if got, want := hook.Message, "/retest"; got != want {
+		t.Errorf("Want message %q, got %q", want, got)
+	}
+	if got, want := hook.Sender, "octocat"; got != want {
+		t.Errorf("Want sender %q, got %q", want, got)
+	}
+	if got, want := repo.Slug, "octocat/hello-world"; got != want {
+		t.Errorf("Want slug %q, got %q", want, got)
+	}
+}
+
+func TestParse_PullRequestCommentIgnored(t *testing.T) {
+	hooks := []*scm.PullRequestCommentHook{
+		{Action: scm.ActionUpdate},
+		{Action: scm.ActionDelete},
+		{Action: scm.ActionCreate, PullRequest: scm.PullRequest{Closed: true}},
+	}
+	for _, v := range hooks {
+		client := &scm.Client{Driver: scm.DriverGitea}
+		client.Webhooks = &mockWebhooks{hook: v}
+		hook, _, err := New(client).Parse(nil, nil)
+		if err != nil {
+			t.Error(err)
+		}
+		if hook != nil {
+			t.Errorf("Expect comment with action %s ignored", v.Action)
+		}
+	}
+}
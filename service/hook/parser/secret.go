@@ -0,0 +1,40 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parser
+
+import (
+	"net/http"
+
+	"github.com/drone/drone/core"
+)
+
+// Secret returns a HookParser that verifies the webhook payload
+// signature using a single global secret, for example the
+// webhook secret of a GitHub App installation, instead of the
+// per-repository secret.
+func Secret(parser core.HookParser, secret string) core.HookParser {
+	return &secretParser{parser, secret}
+}
+
+type secretParser struct {
+	parser core.HookParser
+	secret string
+}
+
+func (p *secretParser) Parse(req *http.Request, _ func(string) string) (*core.Hook, *core.Repository, error) {
+	return p.parser.Parse(req, func(string) string {
+		return p.secret
+	})
+}
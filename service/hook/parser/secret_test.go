@@ -0,0 +1,32 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package parser
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/drone/drone/mock"
+
+	"github.com/golang/mock/gomock"
+)
+
+func TestSecret(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	r := httptest.NewRequest("POST", "/hook", nil)
+
+	mockParser := mock.NewMockHookParser(controller)
+	mockParser.EXPECT().Parse(r, gomock.Any()).Do(func(req *http.Request, fn func(string) string) {
+		if got, want := fn("octocat/hello-world"), "correct-horse-battery-staple"; got != want {
+			t.Errorf("Want app secret %q, got %q", want, got)
+		}
+	})
+
+	repoSecret := func(string) string { return "abc123" }
+	Secret(mockParser, "correct-horse-battery-staple").Parse(r, repoSecret)
+}
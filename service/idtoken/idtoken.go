@@ -0,0 +1,210 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package idtoken
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/drone/drone/core"
+
+	"github.com/dchest/uniuri"
+)
+
+// Config provides the identity token configuration.
+type Config struct {
+	// Issuer is the token issuer url, which must serve
+	// the OpenID Connect discovery document.
+	Issuer string
+
+	// Audience is the intended token audience. If empty,
+	// the audience defaults to the issuer url.
+	Audience string
+
+	// TTL is the token lifetime.
+	TTL time.Duration
+}
+
+// New returns a new IDTokenService that signs tokens with
+// the first key. Additional keys are published but never
+// used for signing, so that tokens signed before a key
+// rotation can still be verified.
+func New(config Config, keys ...*rsa.PrivateKey) core.IDTokenService {
+	audience := config.Audience
+	if audience == "" {
+		audience = config.Issuer
+	}
+	return &service{
+		issuer:   config.Issuer,
+		audience: audience,
+		ttl:      config.TTL,
+		keys:     keys,
+		now:      time.Now,
+	}
+}
+
+type service struct {
+	issuer   string
+	audience string
+	ttl      time.Duration
+	keys     []*rsa.PrivateKey
+	now      func() time.Time
+}
+
+type header struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+	Typ string `json:"typ"`
+}
+
+type claims struct {
+	Issuer    string `json:"iss"`
+	Subject   string `json:"sub"`
+	Audience  string `json:"aud"`
+	IssuedAt  int64  `json:"iat"`
+	NotBefore int64  `json:"nbf"`
+	Expires   int64  `json:"exp"`
+	ID        string `json:"jti"`
+	Repo      string `json:"repo"`
+	Namespace string `json:"namespace"`
+	Branch    string `json:"branch"`
+	Ref       string `json:"ref"`
+	Event     string `json:"event"`
+	Commit    string `json:"commit"`
+	Build     int64  `json:"build"`
+	Stage     string `json:"stage"`
+	Deploy    string `json:"deploy,omitempty"`
+	Trusted   bool   `json:"trusted"`
+}
+
+func (s *service) Issuer() string {
+	return s.issuer
+}
+
+func (s *service) Issue(ctx context.Context, args *core.IDTokenArgs) (string, error) {
+	now := s.now()
+	key := s.keys[0]
+	return Sign(key, &claims{
+		Issuer:    s.issuer,
+		Subject:   subject(args.Repo, args.Build),
+		Audience:  s.audience,
+		IssuedAt:  now.Unix(),
+		NotBefore: now.Unix(),
+		Expires:   now.Add(s.ttl).Unix(),
+		ID:        uniuri.NewLen(32),
+		Repo:      args.Repo.Slug,
+		Namespace: args.Repo.Namespace,
+		Branch:    branch(args.Build),
+		Ref:       args.Build.Ref,
+		Event:     args.Build.Event,
+		Commit:    args.Build.After,
+		Build:     args.Build.Number,
+		Stage:     args.Stage.Name,
+		Deploy:    args.Build.Deploy,
+		Trusted:   args.Repo.Trusted,
+	})
+}
+
+func (s *service) Keys(ctx context.Context) (*core.JSONWebKeySet, error) {
+	set := &core.JSONWebKeySet{}
+	for _, key := range s.keys {
+		set.Keys = append(set.Keys, &core.JSONWebKey{
+			Kty: "RSA",
+			Use: "sig",
+			Alg: "RS256",
+			Kid: KeyID(&key.PublicKey),
+			N:   encodeInt(key.PublicKey.N),
+			E:   encodeInt(big.NewInt(int64(key.PublicKey.E))),
+		})
+	}
+	return set, nil
+}
+
+// helper function returns the token subject. The subject is
+// formatted so that cloud provider trust policies can match
+// on the repository and the branch or deployment target.
+// Pull requests share a single subject, so that trust
+// policies matching a branch never match a pull request.
+func subject(repo *core.Repository, build *core.Build) string {
+	switch {
+	case build.Event == core.EventPullRequest:
+		return fmt.Sprintf("repo:%s:%s", repo.Slug, core.EventPullRequest)
+	case build.Deploy != "":
+		return fmt.Sprintf("repo:%s:deploy:%s", repo.Slug, build.Deploy)
+	default:
+		return fmt.Sprintf("repo:%s:ref:%s", repo.Slug, build.Ref)
+	}
+}
+
+// helper function returns the token branch. The target
+// branch of a pull request is the branch the pull request
+// is merged into, and the source branch is returned instead.
+func branch(build *core.Build) string {
+	if build.Event == core.EventPullRequest {
+		return build.Source
+	}
+	return build.Target
+}
+
+// Sign returns a json web token with the claims, signed
+// using the RS256 algorithm.
+func Sign(key *rsa.PrivateKey, claims interface{}) (string, error) {
+	rawHeader, err := json.Marshal(&header{
+		Alg: "RS256",
+		Kid: KeyID(&key.PublicKey),
+		Typ: "JWT",
+	})
+	if err != nil {
+		return "", err
+	}
+	rawClaims, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	payload := encode(rawHeader) + "." + encode(rawClaims)
+	digest := sha256.Sum256([]byte(payload))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", err
+	}
+	return payload + "." + encode(sig), nil
+}
+
+// KeyID returns the key id, which is the RFC 7638
+// thumbprint of the public key.
+func KeyID(key *rsa.PublicKey) string {
+	thumbprint := fmt.Sprintf(`{"e":"%s","kty":"RSA","n":"%s"}`,
+		encodeInt(big.NewInt(int64(key.E))),
+		encodeInt(key.N),
+	)
+	digest := sha256.Sum256([]byte(thumbprint))
+	return encode(digest[:])
+}
+
+func encodeInt(i *big.Int) string {
+	return encode(i.Bytes())
+}
+
+func encode(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
@@ -0,0 +1,140 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package idtoken
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/drone/drone/core"
+)
+
+var noContext = context.Background()
+
+func TestIssue(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	s := New(Config{Issuer: "https://drone.company.com", TTL: time.Hour}, key).(*service)
+	s.now = func() time.Time { return time.Unix(1552521600, 0) }
+
+	token, err := s.Issue(noContext, &core.IDTokenArgs{
+		Repo:  &core.Repository{Slug: "octocat/hello-world", Namespace: "octocat", Trusted: true},
+		Build: &core.Build{Number: 42, Event: core.EventPush, Ref: "refs/heads/master", Target: "master", After: "7fd1a60b01f91b314f59955a4e4d4e80d8edf11d"},
+		Stage: &core.Stage{Name: "default"},
+	})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		t.Errorf("Want token with 3 parts, got %d", len(parts))
+		return
+	}
+
+	// verify the token signature using the published key.
+	set, _ := s.Keys(noContext)
+	if got, want := len(set.Keys), 1; got != want {
+		t.Errorf("Want %d keys, got %d", want, got)
+		return
+	}
+	pub := &rsa.PublicKey{N: decodeInt(set.Keys[0].N), E: int(decodeInt(set.Keys[0].E).Int64())}
+	sig, _ := base64.RawURLEncoding.DecodeString(parts[2])
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(pub, crypto.SHA256, digest[:], sig); err != nil {
+		t.Errorf("Want valid token signature, got %s", err)
+	}
+
+	h := new(header)
+	raw, _ := base64.RawURLEncoding.DecodeString(parts[0])
+	json.Unmarshal(raw, h)
+	if got, want := h.Kid, set.Keys[0].Kid; got != want {
+		t.Errorf("Want key id %q, got %q", want, got)
+	}
+
+	c := new(claims)
+	raw, _ = base64.RawURLEncoding.DecodeString(parts[1])
+	json.Unmarshal(raw, c)
+	if got, want := c.Subject, "repo:octocat/hello-world:ref:refs/heads/master"; got != want {
+		t.Errorf("Want subject %q, got %q", want, got)
+	}
+	if got, want := c.Audience, "https://drone.company.com"; got != want {
+		t.Errorf("Want audience defaults to issuer %q, got %q", want, got)
+	}
+	if got, want := c.Expires, int64(1552525200); got != want {
+		t.Errorf("Want expiry %d, got %d", want, got)
+	}
+	if got, want := c.Branch, "master"; got != want {
+		t.Errorf("Want branch %q, got %q", want, got)
+	}
+	if got, want := c.Event, core.EventPush; got != want {
+		t.Errorf("Want event %q, got %q", want, got)
+	}
+}
+
+func TestSubject_Deploy(t *testing.T) {
+	repo := &core.Repository{Slug: "octocat/hello-world"}
+	build := &core.Build{Ref: "refs/heads/master", Deploy: "production"}
+	if got, want := subject(repo, build), "repo:octocat/hello-world:deploy:production"; got != want {
+		t.Errorf("Want subject %q, got %q", want, got)
+	}
+}
+
+func TestSubject_PullRequest(t *testing.T) {
+	repo := &core.Repository{Slug: "octocat/hello-world"}
+	build := &core.Build{Event: core.EventPullRequest, Ref: "refs/pull/1/head", Source: "feature", Target: "master"}
+	if got, want := subject(repo, build), "repo:octocat/hello-world:pull_request"; got != want {
+		t.Errorf("Want subject %q, got %q", want, got)
+	}
+	if got, want := branch(build), "feature"; got != want {
+		t.Errorf("Want source branch %q, got %q", want, got)
+	}
+}
+
+func TestParseKey(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	data := pem.EncodeToMemory(&pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(key),
+	})
+	got, err := ParseKey(data)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if got.N.Cmp(key.N) != 0 {
+		t.Errorf("Want parsed key to match")
+	}
+
+	_, err = ParseKey([]byte("not a key"))
+	if err != errInvalidKey {
+		t.Errorf("Want error %v, got %v", errInvalidKey, err)
+	}
+}
+
+func decodeInt(s string) *big.Int {
+	b, _ := base64.RawURLEncoding.DecodeString(s)
+	return new(big.Int).SetBytes(b)
+}
@@ -0,0 +1,157 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package installation
+
+import (
+	"bytes"
+	"context"
+	"crypto/rsa"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/service/idtoken"
+)
+
+// tokens are re-used until they are within this window of
+// expiring, which ensures a cached token handed to a pipeline
+// remains valid for a reasonable amount of time.
+const leeway = time.Minute * 30
+
+// New returns a new InstallationService that issues GitHub App
+// installation access tokens. Tokens are cached per repository
+// and permission set to avoid exhausting the api rate limit.
+func New(server string, id int64, key *rsa.PrivateKey, client *http.Client) core.InstallationService {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &service{
+		server: strings.TrimSuffix(server, "/"),
+		id:     id,
+		key:    key,
+		client: client,
+		cache:  map[string]*core.InstallationToken{},
+	}
+}
+
+type service struct {
+	server string
+	id     int64
+	key    *rsa.PrivateKey
+	client *http.Client
+
+	sync.Mutex
+	cache map[string]*core.InstallationToken
+}
+
+func (s *service) Token(ctx context.Context, repo *core.Repository, permissions map[string]string) (*core.InstallationToken, error) {
+	key := cacheKey(repo, permissions)
+	s.Lock()
+	token, ok := s.cache[key]
+	s.Unlock()
+	if ok && time.Now().Add(leeway).Unix() < token.Expires {
+		return token, nil
+	}
+
+	jwt, err := s.sign()
+	if err != nil {
+		return nil, err
+	}
+
+	installation := new(struct {
+		ID int64 `json:"id"`
+	})
+	path := fmt.Sprintf("%s/repos/%s/%s/installation", s.server, repo.Namespace, repo.Name)
+	if err := s.do(ctx, "GET", path, jwt, nil, installation); err != nil {
+		return nil, err
+	}
+
+	in := map[string]interface{}{
+		"repositories": []string{repo.Name},
+		"permissions":  permissions,
+	}
+	out := new(struct {
+		Token   string    `json:"token"`
+		Expires time.Time `json:"expires_at"`
+	})
+	path = fmt.Sprintf("%s/app/installations/%d/access_tokens", s.server, installation.ID)
+	if err := s.do(ctx, "POST", path, jwt, in, out); err != nil {
+		return nil, err
+	}
+
+	token = &core.InstallationToken{
+		Token:   out.Token,
+		Expires: out.Expires.Unix(),
+	}
+	s.Lock()
+	s.cache[key] = token
+	s.Unlock()
+	return token, nil
+}
+
+// helper function returns a short-lived json web token used to
+// authenticate as the GitHub App.
+func (s *service) sign() (string, error) {
+	now := time.Now()
+	return idtoken.Sign(s.key, map[string]interface{}{
+		"iss": s.id,
+		"iat": now.Add(-time.Minute).Unix(),
+		"exp": now.Add(time.Minute * 9).Unix(),
+	})
+}
+
+// helper function sends a GitHub api request authenticated as
+// the GitHub App and decodes the json response body.
+func (s *service) do(ctx context.Context, method, path, jwt string, in, out interface{}) error {
+	buf := new(bytes.Buffer)
+	if in != nil {
+		if err := json.NewEncoder(buf).Encode(in); err != nil {
+			return err
+		}
+	}
+	req, err := http.NewRequest(method, path, buf)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+jwt)
+	req.Header.Set("Accept", "application/vnd.github.machine-man-preview+json")
+	req.Header.Set("Content-Type", "application/json")
+	res, err := s.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode > 299 {
+		return fmt.Errorf("installation: %s %s: unexpected status %s",
+			method, req.URL.Path, res.Status)
+	}
+	return json.NewDecoder(res.Body).Decode(out)
+}
+
+// helper function returns the cache key for the repository
+// and permission set.
+func cacheKey(repo *core.Repository, permissions map[string]string) string {
+	var keys []string
+	for k, v := range permissions {
+		keys = append(keys, k+"="+v)
+	}
+	sort.Strings(keys)
+	return repo.Slug + ":" + strings.Join(keys, ",")
+}
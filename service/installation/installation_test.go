@@ -0,0 +1,102 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package installation
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/drone/drone/core"
+)
+
+var noContext = context.Background()
+
+func TestToken(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expires := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+
+	var calls int
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/octocat/hello-world/installation", func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.Header.Get("Authorization"), "Bearer ") {
+			t.Errorf("Want app bearer token")
+		}
+		w.Write([]byte(`{"id":42}`))
+	})
+	mux.HandleFunc("/app/installations/42/access_tokens", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if r.Method != "POST" {
+			t.Errorf("Want POST request, got %s", r.Method)
+		}
+		in := struct {
+			Repositories []string          `json:"repositories"`
+			Permissions  map[string]string `json:"permissions"`
+		}{}
+		json.NewDecoder(r.Body).Decode(&in)
+		if len(in.Repositories) != 1 || in.Repositories[0] != "hello-world" {
+			t.Errorf("Want token scoped to repository, got %v", in.Repositories)
+		}
+		if got, want := in.Permissions["contents"], "read"; got != want {
+			t.Errorf("Want contents permission %q, got %q", want, got)
+		}
+		w.WriteHeader(201)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"token":      "ghs_16C7e42F",
+			"expires_at": expires,
+		})
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	service := New(server.URL+"/", 1, key, nil)
+	repo := &core.Repository{Namespace: "octocat", Name: "hello-world", Slug: "octocat/hello-world"}
+	perms := map[string]string{"contents": "read"}
+	got, err := service.Token(noContext, repo, perms)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if got, want := got.Token, "ghs_16C7e42F"; got != want {
+		t.Errorf("Want token %q, got %q", want, got)
+	}
+	if got, want := got.Expires, expires.Unix(); got != want {
+		t.Errorf("Want expires %d, got %d", want, got)
+	}
+
+	// the second request should be served from the cache.
+	if _, err := service.Token(noContext, repo, perms); err != nil {
+		t.Error(err)
+	}
+	if got, want := calls, 1; got != want {
+		t.Errorf("Want %d token requests, got %d", want, got)
+	}
+}
+
+func TestToken_NotInstalled(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	repo := &core.Repository{Namespace: "octocat", Name: "hello-world", Slug: "octocat/hello-world"}
+	_, err = New(server.URL, 1, key, nil).Token(noContext, repo, nil)
+	if err == nil {
+		t.Errorf("Want error when the app is not installed")
+	}
+}
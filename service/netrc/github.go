@@ -0,0 +1,46 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package netrc
+
+import (
+	"context"
+	"time"
+
+	"github.com/drone/drone/core"
+)
+
+// GithubApp returns a Minter that mints GitHub App installation
+// access tokens with read-only access to the repository
+// contents. Installation tokens cannot be issued for less than
+// one hour, so the ttl is not applied.
+func GithubApp(installations core.InstallationService) Minter {
+	return &githubApp{installations: installations}
+}
+
+type githubApp struct {
+	installations core.InstallationService
+}
+
+func (g *githubApp) Mint(ctx context.Context, user *core.User, repo *core.Repository, ttl time.Duration) (*core.Netrc, error) {
+	token, err := g.installations.Token(ctx, repo, map[string]string{"contents": "read"})
+	if err != nil {
+		return nil, err
+	}
+	return &core.Netrc{
+		Login:    "x-access-token",
+		Password: token.Token,
+		Expires:  token.Expires,
+	}, nil
+}
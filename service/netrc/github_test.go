@@ -0,0 +1,40 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package netrc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/mock"
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestGithubApp(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	repo := &core.Repository{Namespace: "octocat", Name: "hello-world"}
+	token := &core.InstallationToken{Token: "ghs_16C7e42F", Expires: 1557932400}
+
+	installations := mock.NewMockInstallationService(controller)
+	installations.EXPECT().Token(gomock.Any(), repo, map[string]string{"contents": "read"}).Return(token, nil)
+
+	got, err := GithubApp(installations).Mint(noContext, &core.User{}, repo, time.Hour)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	want := &core.Netrc{
+		Login:    "x-access-token",
+		Password: "ghs_16C7e42F",
+		Expires:  1557932400,
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf(diff)
+	}
+}
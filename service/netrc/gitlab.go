@@ -0,0 +1,84 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package netrc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/drone/drone/core"
+)
+
+// GitlabDeployToken returns a Minter that mints GitLab project
+// deploy tokens with read-only repository access. The deploy
+// token is created on behalf of the user and expires after the
+// ttl.
+func GitlabDeployToken(server string, client *http.Client) Minter {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &gitlabDeployToken{
+		server: strings.TrimSuffix(server, "/"),
+		client: client,
+	}
+}
+
+type gitlabDeployToken struct {
+	server string
+	client *http.Client
+}
+
+func (g *gitlabDeployToken) Mint(ctx context.Context, user *core.User, repo *core.Repository, ttl time.Duration) (*core.Netrc, error) {
+	now := time.Now().UTC()
+	expires := now.Add(ttl)
+
+	buf := new(bytes.Buffer)
+	err := json.NewEncoder(buf).Encode(map[string]interface{}{
+		"name":       fmt.Sprintf("drone-%d", now.Unix()),
+		"expires_at": expires.Format(time.RFC3339),
+		"scopes":     []string{"read_repository"},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	path := fmt.Sprintf("%s/api/v4/projects/%s/deploy_tokens", g.server,
+		url.PathEscape(repo.Namespace+"/"+repo.Name))
+	req, err := http.NewRequest("POST", path, buf)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+user.Token)
+	req.Header.Set("Content-Type", "application/json")
+
+	out := new(struct {
+		Username string `json:"username"`
+		Token    string `json:"token"`
+	})
+	if err := do(g.client, req.WithContext(ctx), out); err != nil {
+		return nil, err
+	}
+	return &core.Netrc{
+		Login:    out.Username,
+		Password: out.Token,
+		Expires:  expires.Unix(),
+	}, nil
+}
@@ -0,0 +1,57 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package netrc
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/drone/drone/core"
+)
+
+func TestGitlabDeployToken(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got, want := r.URL.EscapedPath(), "/api/v4/projects/octocat%2Fhello-world/deploy_tokens"; got != want {
+			t.Errorf("Want path %q, got %q", want, got)
+		}
+		if got, want := r.Header.Get("Authorization"), "Bearer 755bb80e5b"; got != want {
+			t.Errorf("Want authorization %q, got %q", want, got)
+		}
+		in := struct {
+			Expires time.Time `json:"expires_at"`
+			Scopes  []string  `json:"scopes"`
+		}{}
+		json.NewDecoder(r.Body).Decode(&in)
+		if len(in.Scopes) != 1 || in.Scopes[0] != "read_repository" {
+			t.Errorf("Want read_repository scope, got %v", in.Scopes)
+		}
+		if in.Expires.After(time.Now().Add(time.Hour)) {
+			t.Errorf("Want deploy token to expire within the ttl")
+		}
+		w.WriteHeader(201)
+		w.Write([]byte(`{"id":1,"username":"gitlab+deploy-token-1","token":"jMRvtPNxrn3crTAGukpZ"}`))
+	}))
+	defer server.Close()
+
+	user := &core.User{Token: "755bb80e5b"}
+	repo := &core.Repository{Namespace: "octocat", Name: "hello-world"}
+	got, err := GitlabDeployToken(server.URL, nil).Mint(noContext, user, repo, time.Hour)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if got, want := got.Login, "gitlab+deploy-token-1"; got != want {
+		t.Errorf("Want login %q, got %q", want, got)
+	}
+	if got, want := got.Password, "jMRvtPNxrn3crTAGukpZ"; got != want {
+		t.Errorf("Want password %q, got %q", want, got)
+	}
+	if got.Expires == 0 {
+		t.Errorf("Want expiry recorded")
+	}
+}
@@ -0,0 +1,46 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package netrc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/drone/drone/core"
+)
+
+// Minter mints clone credentials that are scoped to a single
+// repository and expire after a limited duration.
+type Minter interface {
+	Mint(ctx context.Context, user *core.User, repo *core.Repository, ttl time.Duration) (*core.Netrc, error)
+}
+
+// helper function sends the http request and decodes the json
+// response body into out.
+func do(client *http.Client, req *http.Request, out interface{}) error {
+	res, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode > 299 {
+		return fmt.Errorf("netrc: %s %s: unexpected status %s",
+			req.Method, req.URL.Path, res.Status)
+	}
+	return json.NewDecoder(res.Body).Decode(out)
+}
@@ -16,6 +16,7 @@ package netrc
 
 import (
 	"context"
+	"time"
 
 	"github.com/drone/drone/core"
 	"github.com/drone/go-scm/scm"
@@ -30,15 +31,19 @@ type Service struct {
 	private  bool
 	username string
 	password string
+	minter   Minter
 }
 
-// New returns a new Netrc service.
+// New returns a new Netrc service. If the minter is not nil,
+// the service mints repository-scoped, short-lived credentials
+// instead of using the user oauth token.
 func New(
 	client *scm.Client,
 	renewer core.Renewer,
 	private bool,
 	username string,
 	password string,
+	minter Minter,
 ) core.NetrcService {
 	return &Service{
 		client:   client,
@@ -46,6 +51,7 @@ func New(
 		private:  private,
 		username: username,
 		password: password,
+		minter:   minter,
 	}
 }
 
@@ -76,6 +82,20 @@ func (s *Service) Create(ctx context.Context, user *core.User, repo *core.Reposi
 		return nil, err
 	}
 
+	// mint a credential that is scoped to the repository and
+	// expires with the pipeline timeout. If minting fails we
+	// do not fall back to the user token, since this would
+	// silently widen the scope of the credential.
+	if s.minter != nil {
+		ttl := time.Duration(repo.Timeout) * time.Minute
+		scoped, err := s.minter.Mint(ctx, user, repo, ttl)
+		if err != nil {
+			return nil, err
+		}
+		scoped.Machine = netrc.Machine
+		return scoped, nil
+	}
+
 	switch s.client.Driver {
 	case scm.DriverGitlab:
 		netrc.Login = "oauth2"
@@ -84,6 +104,8 @@ func (s *Service) Create(ctx context.Context, user *core.User, repo *core.Reposi
 		netrc.Login = "x-token-auth"
 		netrc.Password = user.Token
 	case scm.DriverGithub, scm.DriverGogs, scm.DriverGitea:
+		// gitea and gogs access tokens do not expire, and
+		// are therefore not refreshed by the renewer.
 		netrc.Password = "x-oauth-basic"
 		netrc.Login = user.Token
 	}
@@ -8,6 +8,7 @@ import (
 	"context"
 	"net/url"
 	"testing"
+	"time"
 
 	"github.com/drone/drone/core"
 	"github.com/drone/drone/mock"
@@ -32,7 +33,7 @@ func TestNetrc(t *testing.T) {
 
 	mockClient := &scm.Client{Driver: scm.DriverGithub}
 
-	s := New(mockClient, mockRenewer, false, "", "")
+	s := New(mockClient, mockRenewer, false, "", "", nil)
 	got, err := s.Create(noContext, mockUser, mockRepo)
 	if err != nil {
 		t.Error(err)
@@ -110,6 +111,37 @@ func TestNetrc_Gogs(t *testing.T) {
 	}
 }
 
+func TestNetrc_Gitea(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockRepo := &core.Repository{Private: true, HTTPURL: "https://try.gitea.io/octocat/hello-world"}
+	mockUser := &core.User{
+		Token:   "755bb80e5b",
+		Refresh: "e08f3fa43e",
+	}
+	mockRenewer := mock.NewMockRenewer(controller)
+	mockRenewer.EXPECT().Renew(gomock.Any(), mockUser, true)
+
+	s := Service{
+		renewer: mockRenewer,
+		client:  &scm.Client{Driver: scm.DriverGitea},
+	}
+	got, err := s.Create(noContext, mockUser, mockRepo)
+	if err != nil {
+		t.Error(err)
+	}
+
+	want := &core.Netrc{
+		Machine:  "try.gitea.io",
+		Login:    "755bb80e5b",
+		Password: "x-oauth-basic",
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf(diff)
+	}
+}
+
 func TestNetrc_Bitbucket(t *testing.T) {
 	controller := gomock.NewController(t)
 	defer controller.Finish()
@@ -203,3 +235,77 @@ func TestNetrc_RenewErr(t *testing.T) {
 		t.Errorf("Want not authorized error, got %v", err)
 	}
 }
+
+func TestNetrc_Minter(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockRepo := &core.Repository{Private: true, Timeout: 60, HTTPURL: "https://github.com/octocat/hello-world"}
+	mockUser := &core.User{
+		Token:   "755bb80e5b",
+		Refresh: "e08f3fa43e",
+	}
+	mockRenewer := mock.NewMockRenewer(controller)
+	mockRenewer.EXPECT().Renew(gomock.Any(), mockUser, true)
+
+	minter := &mockMinter{netrc: &core.Netrc{Login: "x-access-token", Password: "ghs_16C7e42F", Expires: 1557932400}}
+	s := Service{
+		renewer: mockRenewer,
+		client:  &scm.Client{Driver: scm.DriverGithub},
+		minter:  minter,
+	}
+	got, err := s.Create(noContext, mockUser, mockRepo)
+	if err != nil {
+		t.Error(err)
+	}
+
+	want := &core.Netrc{
+		Machine:  "github.com",
+		Login:    "x-access-token",
+		Password: "ghs_16C7e42F",
+		Expires:  1557932400,
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf(diff)
+	}
+	if got, want := minter.ttl, time.Hour; got != want {
+		t.Errorf("Want credential ttl %v, got %v", want, got)
+	}
+}
+
+func TestNetrc_MinterErr(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockRepo := &core.Repository{Private: true, HTTPURL: "https://github.com/octocat/hello-world"}
+	mockUser := &core.User{
+		Token:   "755bb80e5b",
+		Refresh: "e08f3fa43e",
+	}
+	mockRenewer := mock.NewMockRenewer(controller)
+	mockRenewer.EXPECT().Renew(gomock.Any(), mockUser, true)
+
+	s := Service{
+		renewer: mockRenewer,
+		client:  &scm.Client{Driver: scm.DriverGithub},
+		minter:  &mockMinter{err: scm.ErrNotFound},
+	}
+	got, err := s.Create(noContext, mockUser, mockRepo)
+	if err != scm.ErrNotFound {
+		t.Errorf("Want not found error, got %v", err)
+	}
+	if got != nil {
+		t.Errorf("Want nil netrc, do not fall back to the user token")
+	}
+}
+
+type mockMinter struct {
+	netrc *core.Netrc
+	err   error
+	ttl   time.Duration
+}
+
+func (m *mockMinter) Mint(ctx context.Context, user *core.User, repo *core.Repository, ttl time.Duration) (*core.Netrc, error) {
+	m.ttl = ttl
+	return m.netrc, m.err
+}
@@ -0,0 +1,178 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+
+	"github.com/drone/drone/core"
+)
+
+// maximum number of log lines included in the email.
+const excerptLines = 25
+
+var errSMTPNotConfigured = errors.New("notify: smtp server is not configured")
+
+// email sends the build notification email to the configured
+// recipients and, optionally, to the commit author.
+func (s *service) email(ctx context.Context, notification *core.Notification, args *core.NotifyArgs, recovered bool) error {
+	if s.smtp.Host == "" {
+		return errSMTPNotConfigured
+	}
+	to := recipients(notification, args.Build)
+	if len(to) == 0 {
+		return nil
+	}
+
+	data := &emailData{
+		Repo:      args.Repo,
+		Build:     args.Build,
+		Link:      s.link(args),
+		Title:     createTitle(args),
+		Recovered: recovered,
+	}
+	if step := failedStep(args.Stages); step != nil {
+		data.Step = step
+		data.Lines = s.excerpt(ctx, step)
+	}
+
+	body := new(bytes.Buffer)
+	err := emailTemplate.Execute(body, data)
+	if err != nil {
+		return err
+	}
+
+	msg := new(bytes.Buffer)
+	fmt.Fprintf(msg, "From: %s\r\n", s.smtp.From)
+	fmt.Fprintf(msg, "To: %s\r\n", strings.Join(to, ", "))
+	fmt.Fprintf(msg, "Subject: %s\r\n", createSubject(args, recovered))
+	fmt.Fprintf(msg, "MIME-Version: 1.0\r\n")
+	fmt.Fprintf(msg, "Content-Type: text/html; charset=\"UTF-8\"\r\n")
+	fmt.Fprintf(msg, "\r\n")
+	msg.Write(body.Bytes())
+
+	var auth smtp.Auth
+	if s.smtp.Username != "" {
+		auth = smtp.PlainAuth("", s.smtp.Username, s.smtp.Password, s.smtp.Host)
+	}
+	addr := net.JoinHostPort(s.smtp.Host, strconv.Itoa(s.smtp.Port))
+	return s.sendMail(addr, auth, s.smtp.From, to, msg.Bytes())
+}
+
+// excerpt returns the last lines of the step logs.
+func (s *service) excerpt(ctx context.Context, step *core.Step) []*core.Line {
+	rc, err := s.logs.Find(ctx, step.ID)
+	if err != nil {
+		return nil
+	}
+	defer rc.Close()
+	var lines []*core.Line
+	json.NewDecoder(rc).Decode(&lines)
+	if len(lines) > excerptLines {
+		lines = lines[len(lines)-excerptLines:]
+	}
+	return lines
+}
+
+// helper function returns the de-duplicated list of email
+// recipients.
+func recipients(notification *core.Notification, build *core.Build) []string {
+	var to []string
+	seen := map[string]struct{}{}
+	add := func(addr string) {
+		if _, ok := seen[addr]; ok || addr == "" {
+			return
+		}
+		seen[addr] = struct{}{}
+		to = append(to, addr)
+	}
+	for _, addr := range notification.Recipients {
+		add(addr)
+	}
+	if notification.Author {
+		add(build.AuthorEmail)
+	}
+	return to
+}
+
+// helper function returns the first failed step.
+func failedStep(stages []*core.Stage) *core.Step {
+	for _, stage := range stages {
+		for _, step := range stage.Steps {
+			switch step.Status {
+			case core.StatusFailing, core.StatusError:
+				return step
+			}
+		}
+	}
+	return nil
+}
+
+// helper function returns the email subject.
+func createSubject(args *core.NotifyArgs, recovered bool) string {
+	if recovered {
+		return fmt.Sprintf("[%s] Build #%d recovered (%s)",
+			args.Repo.Slug,
+			args.Build.Number,
+			args.Build.Target,
+		)
+	}
+	return fmt.Sprintf("[%s] Build #%d %s (%s)",
+		args.Repo.Slug,
+		args.Build.Number,
+		createVerb(args.Build.Status),
+		args.Build.Target,
+	)
+}
+
+type emailData struct {
+	Repo      *core.Repository
+	Build     *core.Build
+	Step      *core.Step
+	Lines     []*core.Line
+	Link      string
+	Title     string
+	Recovered bool
+}
+
+var emailTemplate = template.Must(template.New("email").Parse(`<!DOCTYPE html>
+<html>
+<body style="font-family: sans-serif;">
+<h2><a href="{{ .Link }}">{{ .Title }}</a></h2>
+{{ if .Recovered }}<p>The build is passing again after the previous build failed.</p>{{ end }}
+<table>
+<tr><td><b>Repository</b></td><td>{{ .Repo.Slug }}</td></tr>
+<tr><td><b>Branch</b></td><td>{{ .Build.Target }}</td></tr>
+<tr><td><b>Commit</b></td><td>{{ .Build.After }}</td></tr>
+<tr><td><b>Author</b></td><td>{{ .Build.Author }}</td></tr>
+<tr><td><b>Message</b></td><td>{{ .Build.Message }}</td></tr>
+<tr><td><b>Status</b></td><td>{{ .Build.Status }}</td></tr>
+</table>
+{{ if .Step }}
+<h3>Step {{ .Step.Name }}</h3>
+<pre style="background: #f5f5f5; padding: 10px;">{{ range .Lines }}{{ .Message }}{{ end }}</pre>
+{{ end }}
+</body>
+</html>
+`))
@@ -0,0 +1,175 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package notify
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/smtp"
+	"strings"
+	"testing"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/mock"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestEmail(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	notifications := []*core.Notification{
+		{
+			Kind:       core.NotifyEmail,
+			Recipients: []string{"team@company.com"},
+			Author:     true,
+		},
+	}
+
+	build := new(core.Build)
+	*build = *mockBuild
+	build.AuthorEmail = "octocat@github.com"
+
+	stages := []*core.Stage{
+		{
+			Steps: []*core.Step{
+				{ID: 1, Name: "clone", Status: core.StatusPassing},
+				{ID: 2, Name: "test", Status: core.StatusFailing},
+			},
+		},
+	}
+
+	store := mock.NewMockNotificationStore(controller)
+	store.EXPECT().List(gomock.Any(), mockRepo.ID).Return(notifications, nil)
+
+	logs := mock.NewMockLogStore(controller)
+	logs.EXPECT().Find(gomock.Any(), int64(2)).Return(
+		ioutil.NopCloser(bytes.NewBufferString(`[{"pos":0,"out":"--- FAIL: TestReadme\n"}]`)), nil,
+	)
+
+	var (
+		gotAddr string
+		gotTo   []string
+		gotMsg  string
+	)
+	service := New(nil, logs, store, mockSystem, Config{
+		SMTP: SMTP{Host: "smtp.company.com", Port: 587, From: "drone@company.com"},
+	}).(*service)
+	service.sendMail = func(addr string, a smtp.Auth, from string, to []string, msg []byte) error {
+		gotAddr, gotTo, gotMsg = addr, to, string(msg)
+		return nil
+	}
+
+	err := service.Notify(noContext, &core.NotifyArgs{Repo: mockRepo, Build: build, Stages: stages})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if got, want := gotAddr, "smtp.company.com:587"; got != want {
+		t.Errorf("Want smtp address %s, got %s", want, got)
+	}
+	if diff := cmp.Diff(gotTo, []string{"team@company.com", "octocat@github.com"}); diff != "" {
+		t.Errorf(diff)
+	}
+	if !strings.Contains(gotMsg, "Subject: [octocat/hello-world] Build #42 failed (master)") {
+		t.Errorf("Expect email subject contains the build summary")
+	}
+	if !strings.Contains(gotMsg, "--- FAIL: TestReadme") {
+		t.Errorf("Expect email body contains the log excerpt")
+	}
+}
+
+func TestEmail_Recovered(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	notifications := []*core.Notification{
+		{
+			Kind:       core.NotifyEmail,
+			Recipients: []string{"team@company.com"},
+		},
+	}
+
+	build := &core.Build{
+		Number: 43,
+		Status: core.StatusPassing,
+		Ref:    "refs/heads/master",
+		Target: "master",
+	}
+	previous := []*core.Build{
+		build,
+		{Number: 42, Status: core.StatusFailing},
+	}
+
+	store := mock.NewMockNotificationStore(controller)
+	store.EXPECT().List(gomock.Any(), mockRepo.ID).Return(notifications, nil)
+
+	builds := mock.NewMockBuildStore(controller)
+	builds.EXPECT().ListRef(gomock.Any(), mockRepo.ID, build.Ref, 25, 0).Return(previous, nil)
+
+	var sent string
+	service := New(builds, nil, store, mockSystem, Config{
+		SMTP: SMTP{Host: "smtp.company.com", Port: 25, From: "drone@company.com"},
+	}).(*service)
+	service.sendMail = func(addr string, a smtp.Auth, from string, to []string, msg []byte) error {
+		sent = string(msg)
+		return nil
+	}
+
+	err := service.Notify(noContext, &core.NotifyArgs{Repo: mockRepo, Build: build})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if !strings.Contains(sent, "Subject: [octocat/hello-world] Build #43 recovered (master)") {
+		t.Errorf("Expect recovery email sent")
+	}
+}
+
+// this test verifies that email notifications are not sent
+// by default for passing builds that did not recover from a
+// failure.
+func TestEmail_Passing(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	notifications := []*core.Notification{
+		{
+			Kind:       core.NotifyEmail,
+			Recipients: []string{"team@company.com"},
+		},
+	}
+
+	build := &core.Build{
+		Number: 43,
+		Status: core.StatusPassing,
+		Ref:    "refs/heads/master",
+	}
+	previous := []*core.Build{
+		build,
+		{Number: 42, Status: core.StatusPassing},
+	}
+
+	store := mock.NewMockNotificationStore(controller)
+	store.EXPECT().List(gomock.Any(), mockRepo.ID).Return(notifications, nil)
+
+	builds := mock.NewMockBuildStore(controller)
+	builds.EXPECT().ListRef(gomock.Any(), mockRepo.ID, build.Ref, 25, 0).Return(previous, nil)
+
+	service := New(builds, nil, store, mockSystem, Config{
+		SMTP: SMTP{Host: "smtp.company.com", Port: 25},
+	}).(*service)
+	service.sendMail = func(addr string, a smtp.Auth, from string, to []string, msg []byte) error {
+		t.Errorf("Expect email not sent")
+		return nil
+	}
+
+	err := service.Notify(noContext, &core.NotifyArgs{Repo: mockRepo, Build: build})
+	if err != nil {
+		t.Error(err)
+	}
+}
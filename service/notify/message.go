@@ -0,0 +1,190 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notify
+
+import (
+	"fmt"
+
+	"github.com/drone/drone/core"
+)
+
+// build status colors.
+const (
+	colorSuccess = "#2eb886"
+	colorFailure = "#a30200"
+	colorDefault = "#daa038"
+)
+
+type (
+	slackMessage struct {
+		Channel     string            `json:"channel,omitempty"`
+		Username    string            `json:"username,omitempty"`
+		Attachments []slackAttachment `json:"attachments"`
+	}
+
+	slackAttachment struct {
+		Fallback  string `json:"fallback"`
+		Color     string `json:"color"`
+		Title     string `json:"title"`
+		TitleLink string `json:"title_link"`
+		Text      string `json:"text"`
+	}
+
+	teamsMessage struct {
+		Type       string         `json:"@type"`
+		Context    string         `json:"@context"`
+		ThemeColor string         `json:"themeColor"`
+		Summary    string         `json:"summary"`
+		Sections   []teamsSection `json:"sections"`
+		Actions    []teamsAction  `json:"potentialAction"`
+	}
+
+	teamsSection struct {
+		ActivityTitle    string `json:"activityTitle"`
+		ActivitySubtitle string `json:"activitySubtitle"`
+		Text             string `json:"text"`
+	}
+
+	teamsAction struct {
+		Type    string        `json:"@type"`
+		Name    string        `json:"name"`
+		Targets []teamsTarget `json:"targets"`
+	}
+
+	teamsTarget struct {
+		OS  string `json:"os"`
+		URI string `json:"uri"`
+	}
+
+	discordMessage struct {
+		Username string         `json:"username,omitempty"`
+		Embeds   []discordEmbed `json:"embeds"`
+	}
+
+	discordEmbed struct {
+		Title       string `json:"title"`
+		URL         string `json:"url"`
+		Description string `json:"description"`
+		Color       int    `json:"color"`
+	}
+)
+
+func createSlack(notification *core.Notification, args *core.NotifyArgs, link string) *slackMessage {
+	return &slackMessage{
+		Channel:  notification.Channel,
+		Username: "drone",
+		Attachments: []slackAttachment{
+			{
+				Fallback:  createTitle(args),
+				Color:     createColor(args.Build.Status),
+				Title:     createTitle(args),
+				TitleLink: link,
+				Text:      createText(args),
+			},
+		},
+	}
+}
+
+func createTeams(args *core.NotifyArgs, link string) *teamsMessage {
+	return &teamsMessage{
+		Type:       "MessageCard",
+		Context:    "https://schema.org/extensions",
+		ThemeColor: createColor(args.Build.Status)[1:],
+		Summary:    createTitle(args),
+		Sections: []teamsSection{
+			{
+				ActivityTitle:    createTitle(args),
+				ActivitySubtitle: args.Repo.Slug,
+				Text:             createText(args),
+			},
+		},
+		Actions: []teamsAction{
+			{
+				Type: "OpenUri",
+				Name: "View Build",
+				Targets: []teamsTarget{
+					{OS: "default", URI: link},
+				},
+			},
+		},
+	}
+}
+
+func createDiscord(args *core.NotifyArgs, link string) *discordMessage {
+	var color int
+	fmt.Sscanf(createColor(args.Build.Status)[1:], "%x", &color)
+	return &discordMessage{
+		Username: "drone",
+		Embeds: []discordEmbed{
+			{
+				Title:       createTitle(args),
+				URL:         link,
+				Description: createText(args),
+				Color:       color,
+			},
+		},
+	}
+}
+
+// helper function returns the message title.
+func createTitle(args *core.NotifyArgs) string {
+	return fmt.Sprintf("Build #%d %s (%s)",
+		args.Build.Number,
+		createVerb(args.Build.Status),
+		args.Repo.Slug,
+	)
+}
+
+// helper function returns the message text.
+func createText(args *core.NotifyArgs) string {
+	after := args.Build.After
+	if len(after) > 8 {
+		after = after[:8]
+	}
+	return fmt.Sprintf("%s pushed %s to %s: %s",
+		args.Build.Author,
+		after,
+		args.Build.Target,
+		args.Build.Message,
+	)
+}
+
+// helper function returns the status verb.
+func createVerb(status string) string {
+	switch status {
+	case core.StatusPassing:
+		return "succeeded"
+	case core.StatusFailing:
+		return "failed"
+	case core.StatusKilled:
+		return "was killed"
+	case core.StatusError:
+		return "errored"
+	default:
+		return status
+	}
+}
+
+// helper function returns the status color.
+func createColor(status string) string {
+	switch status {
+	case core.StatusPassing:
+		return colorSuccess
+	case core.StatusFailing, core.StatusError:
+		return colorFailure
+	default:
+		return colorDefault
+	}
+}
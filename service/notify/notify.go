@@ -0,0 +1,239 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/smtp"
+	"path"
+	"time"
+
+	"github.com/drone/drone/core"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+// Config configures the Notification service.
+type Config struct {
+	SMTP SMTP
+}
+
+// SMTP provides the smtp server configuration used to send
+// email notifications.
+type SMTP struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+}
+
+// New returns a new NotificationService.
+func New(
+	builds core.BuildStore,
+	logs core.LogStore,
+	notifications core.NotificationStore,
+	system *core.System,
+	config Config,
+) core.NotificationService {
+	return &service{
+		builds:        builds,
+		logs:          logs,
+		notifications: notifications,
+		system:        system,
+		smtp:          config.SMTP,
+		sendMail:      smtp.SendMail,
+	}
+}
+
+type service struct {
+	client        *http.Client
+	builds        core.BuildStore
+	logs          core.LogStore
+	notifications core.NotificationStore
+	system        *core.System
+	smtp          SMTP
+	sendMail      func(addr string, a smtp.Auth, from string, to []string, msg []byte) error
+}
+
+// Notify sends the build notification to each repository
+// notification service that matches the build status and
+// branch.
+func (s *service) Notify(ctx context.Context, args *core.NotifyArgs) error {
+	list, err := s.notifications.List(ctx, args.Repo.ID)
+	if err != nil {
+		return err
+	}
+	if len(list) == 0 {
+		return nil
+	}
+
+	// the previous build is only queried if the build passed
+	// and at least one notification subscribes to recovery.
+	var recovered bool
+	if args.Build.Status == core.StatusPassing && wantsRecovered(list) {
+		recovered = s.isRecovered(ctx, args)
+	}
+
+	var result error
+	for _, notification := range list {
+		if !match(notification, args.Build, recovered) {
+			continue
+		}
+		var payload interface{}
+		switch notification.Kind {
+		case core.NotifySlack:
+			payload = createSlack(notification, args, s.link(args))
+		case core.NotifyTeams:
+			payload = createTeams(args, s.link(args))
+		case core.NotifyDiscord:
+			payload = createDiscord(args, s.link(args))
+		case core.NotifyEmail:
+			err := s.email(ctx, notification, args, recovered)
+			if err != nil {
+				result = multierror.Append(result, err)
+			}
+			continue
+		default:
+			continue
+		}
+		err := s.send(ctx, notification.Endpoint, payload)
+		if err != nil {
+			result = multierror.Append(result, err)
+		}
+	}
+	return result
+}
+
+// isRecovered returns true if the previous completed build
+// for the same ref failed.
+func (s *service) isRecovered(ctx context.Context, args *core.NotifyArgs) bool {
+	builds, err := s.builds.ListRef(ctx, args.Repo.ID, args.Build.Ref, 25, 0)
+	if err != nil {
+		return false
+	}
+	for _, build := range builds {
+		if build.Number >= args.Build.Number {
+			continue
+		}
+		switch build.Status {
+		case core.StatusPending, core.StatusRunning, core.StatusBlocked, core.StatusDeclined:
+			continue
+		case core.StatusFailing, core.StatusError:
+			return true
+		default:
+			return false
+		}
+	}
+	return false
+}
+
+func (s *service) send(ctx context.Context, endpoint string, payload interface{}) error {
+	ctx, cancel := context.WithTimeout(ctx, time.Minute)
+	defer cancel()
+
+	buf := new(bytes.Buffer)
+	json.NewEncoder(buf).Encode(payload)
+	req, err := http.NewRequest("POST", endpoint, buf)
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/json")
+	res, err := s.httpClient().Do(req)
+	if err != nil {
+		return err
+	}
+	res.Body.Close()
+	if res.StatusCode > 299 {
+		return fmt.Errorf("notify: unexpected status code %d", res.StatusCode)
+	}
+	return nil
+}
+
+// helper function returns the build link.
+func (s *service) link(args *core.NotifyArgs) string {
+	return fmt.Sprintf("%s/%s/%d", s.system.Link, args.Repo.Slug, args.Build.Number)
+}
+
+func (s *service) httpClient() *http.Client {
+	if s.client == nil {
+		return http.DefaultClient
+	}
+	return s.client
+}
+
+// helper function returns the notification events. Email
+// notifications are sent on failure and recovery by default.
+func events(notification *core.Notification) []string {
+	if len(notification.Events) == 0 && notification.Kind == core.NotifyEmail {
+		return []string{core.StatusFailing, core.StatusError, core.NotifyRecovered}
+	}
+	return notification.Events
+}
+
+// helper function returns true if any notification subscribes
+// to the recovered event.
+func wantsRecovered(list []*core.Notification) bool {
+	for _, notification := range list {
+		for _, event := range events(notification) {
+			if event == core.NotifyRecovered {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// helper function returns true if the notification should be
+// sent for the build. If no events are configured, the
+// notification is sent for all completed builds. If no
+// branches are configured, the notification is sent for all
+// branches.
+func match(notification *core.Notification, build *core.Build, recovered bool) bool {
+	return matchEvent(events(notification), build.Status, recovered) &&
+		matchBranch(notification.Branches, build.Target)
+}
+
+func matchEvent(events []string, status string, recovered bool) bool {
+	if len(events) == 0 {
+		return true
+	}
+	for _, event := range events {
+		if event == status {
+			return true
+		}
+		if event == core.NotifyRecovered && recovered {
+			return true
+		}
+	}
+	return false
+}
+
+func matchBranch(branches []string, branch string) bool {
+	if len(branches) == 0 {
+		return true
+	}
+	for _, pattern := range branches {
+		if ok, _ := path.Match(pattern, branch); ok {
+			return true
+		}
+	}
+	return false
+}
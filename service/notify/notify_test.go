@@ -0,0 +1,154 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package notify
+
+import (
+	"context"
+	"testing"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/mock"
+
+	"github.com/golang/mock/gomock"
+	"github.com/h2non/gock"
+)
+
+var noContext = context.Background()
+
+var (
+	mockRepo = &core.Repository{
+		ID:   1,
+		Slug: "octocat/hello-world",
+	}
+
+	mockBuild = &core.Build{
+		Number:  42,
+		Status:  core.StatusFailing,
+		Target:  "master",
+		After:   "7fd1a60b01f91b314f59955a4e4d4e80d8edf11d",
+		Author:  "octocat",
+		Message: "update the readme",
+	}
+
+	mockSystem = &core.System{
+		Link: "https://drone.company.com",
+	}
+)
+
+func TestNotify(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+	defer gock.Off()
+
+	notifications := []*core.Notification{
+		{
+			Kind:     core.NotifySlack,
+			Endpoint: "https://hooks.slack.com/services/T0/B0/XXX",
+			Channel:  "#builds",
+			Events:   []string{core.StatusFailing},
+		},
+		{
+			Kind:     core.NotifyDiscord,
+			Endpoint: "https://discordapp.com/api/webhooks/1/XXX",
+			Branches: []string{"release/*"},
+		},
+	}
+
+	gock.New("https://hooks.slack.com").
+		Post("/services/T0/B0/XXX").
+		MatchType("json").
+		JSON(&slackMessage{
+			Channel:  "#builds",
+			Username: "drone",
+			Attachments: []slackAttachment{
+				{
+					Fallback:  "Build #42 failed (octocat/hello-world)",
+					Color:     colorFailure,
+					Title:     "Build #42 failed (octocat/hello-world)",
+					TitleLink: "https://drone.company.com/octocat/hello-world/42",
+					Text:      "octocat pushed 7fd1a60b to master: update the readme",
+				},
+			},
+		}).
+		Reply(200)
+
+	store := mock.NewMockNotificationStore(controller)
+	store.EXPECT().List(gomock.Any(), mockRepo.ID).Return(notifications, nil)
+
+	service := New(nil, nil, store, mockSystem, Config{})
+	err := service.Notify(noContext, &core.NotifyArgs{Repo: mockRepo, Build: mockBuild})
+	if err != nil {
+		t.Error(err)
+	}
+	if gock.IsPending() {
+		t.Errorf("Unfinished requests")
+	}
+}
+
+func TestNotify_Error(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+	defer gock.Off()
+
+	notifications := []*core.Notification{
+		{
+			Kind:     core.NotifyTeams,
+			Endpoint: "https://outlook.office.com/webhook/XXX",
+		},
+	}
+
+	gock.New("https://outlook.office.com").
+		Post("/webhook/XXX").
+		Reply(400)
+
+	store := mock.NewMockNotificationStore(controller)
+	store.EXPECT().List(gomock.Any(), mockRepo.ID).Return(notifications, nil)
+
+	service := New(nil, nil, store, mockSystem, Config{})
+	err := service.Notify(noContext, &core.NotifyArgs{Repo: mockRepo, Build: mockBuild})
+	if err == nil {
+		t.Errorf("Expect error when the notification is rejected")
+	}
+}
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		events   []string
+		branches []string
+		status   string
+		branch   string
+		match    bool
+	}{
+		{nil, nil, core.StatusPassing, "master", true},
+		{[]string{core.StatusFailing}, nil, core.StatusPassing, "master", false},
+		{[]string{core.StatusFailing}, nil, core.StatusFailing, "master", true},
+		{nil, []string{"master"}, core.StatusPassing, "develop", false},
+		{nil, []string{"release/*"}, core.StatusPassing, "release/1.0", true},
+		{[]string{core.StatusFailing, core.StatusError}, []string{"master", "develop"}, core.StatusError, "develop", true},
+	}
+	for i, test := range tests {
+		notification := &core.Notification{
+			Events:   test.events,
+			Branches: test.branches,
+		}
+		build := &core.Build{
+			Status: test.status,
+			Target: test.branch,
+		}
+		if got, want := match(notification, build, false), test.match; got != want {
+			t.Errorf("Want match %v at index %d, got %v", want, i, got)
+		}
+	}
+}
+
+func TestCreateDiscord(t *testing.T) {
+	message := createDiscord(&core.NotifyArgs{Repo: mockRepo, Build: mockBuild}, "https://drone.company.com/octocat/hello-world/42")
+	if got, want := message.Embeds[0].Color, 0xa30200; got != want {
+		t.Errorf("Want embed color %d, got %d", want, got)
+	}
+	if got, want := message.Embeds[0].URL, "https://drone.company.com/octocat/hello-world/42"; got != want {
+		t.Errorf("Want embed url %s, got %s", want, got)
+	}
+}
@@ -0,0 +1,70 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package orgs
+
+import (
+	"context"
+	"time"
+
+	"github.com/drone/drone/core"
+
+	"github.com/hashicorp/golang-lru"
+)
+
+// NewCache wraps the service with a simple cache to store
+// the organization membership of each user for the duration
+// of the ttl, reducing the number of requests to the source
+// code management system.
+func NewCache(base core.OrganizationService, size int, ttl time.Duration) core.OrganizationService {
+	cache, _ := lru.New(size)
+	return &cacher{
+		base:  base,
+		cache: cache,
+		ttl:   ttl,
+	}
+}
+
+type cacher struct {
+	base  core.OrganizationService
+	cache *lru.Cache
+	ttl   time.Duration
+}
+
+type item struct {
+	orgs    []*core.Organization
+	expires time.Time
+}
+
+func (c *cacher) List(ctx context.Context, user *core.User) ([]*core.Organization, error) {
+	if cached, ok := c.cache.Get(user.ID); ok {
+		if item := cached.(*item); time.Now().Before(item.expires) {
+			return item.orgs, nil
+		}
+		c.cache.Remove(user.ID)
+	}
+	return c.Refresh(ctx, user)
+}
+
+func (c *cacher) Refresh(ctx context.Context, user *core.User) ([]*core.Organization, error) {
+	orgs, err := c.base.Refresh(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	c.cache.Add(user.ID, &item{
+		orgs:    orgs,
+		expires: time.Now().Add(c.ttl),
+	})
+	return orgs, nil
+}
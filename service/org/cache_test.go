@@ -0,0 +1,82 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package orgs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/mock"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/golang/mock/gomock"
+)
+
+func TestCache(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	user := &core.User{ID: 1}
+	want := []*core.Organization{{Name: "octocat"}}
+
+	base := mock.NewMockOrganizationService(controller)
+	base.EXPECT().Refresh(noContext, user).Return(want, nil).Times(1)
+
+	service := NewCache(base, 10, time.Hour)
+	for i := 0; i < 2; i++ {
+		got, err := service.List(noContext, user)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if diff := cmp.Diff(got, want); diff != "" {
+			t.Errorf(diff)
+		}
+	}
+}
+
+func TestCache_Expired(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	user := &core.User{ID: 1}
+	orgs := []*core.Organization{{Name: "octocat"}}
+
+	base := mock.NewMockOrganizationService(controller)
+	base.EXPECT().Refresh(noContext, user).Return(orgs, nil).Times(2)
+
+	service := NewCache(base, 10, -time.Second)
+	service.List(noContext, user)
+	service.List(noContext, user)
+}
+
+func TestCache_Refresh(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	user := &core.User{ID: 1}
+	before := []*core.Organization{{Name: "octocat"}}
+	after := []*core.Organization{{Name: "octocat"}, {Name: "github"}}
+
+	base := mock.NewMockOrganizationService(controller)
+	gomock.InOrder(
+		base.EXPECT().Refresh(noContext, user).Return(before, nil),
+		base.EXPECT().Refresh(noContext, user).Return(after, nil),
+	)
+
+	service := NewCache(base, 10, time.Hour)
+	service.List(noContext, user)
+	service.Refresh(noContext, user)
+
+	got, err := service.List(noContext, user)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if diff := cmp.Diff(got, after); diff != "" {
+		t.Errorf(diff)
+	}
+}
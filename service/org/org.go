@@ -61,3 +61,7 @@ func (s *service) List(ctx context.Context, user *core.User) ([]*core.Organizati
 	}
 	return orgs, nil
 }
+
+func (s *service) Refresh(ctx context.Context, user *core.User) ([]*core.Organization, error) {
+	return s.List(ctx, user)
+}
@@ -0,0 +1,263 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provenance
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/service/idtoken"
+)
+
+const (
+	payloadType   = "application/vnd.in-toto+json"
+	statementType = "https://in-toto.io/Statement/v0.1"
+	predicateType = "https://slsa.dev/provenance/v0.2"
+	buildType     = "https://drone.io/pipeline@v1"
+)
+
+// Config provides the provenance configuration.
+type Config struct {
+	// Builder is the builder identity, which is the server
+	// address by default.
+	Builder string
+
+	// Endpoint is the attestation store url. If empty, the
+	// attestations are not published.
+	Endpoint string
+
+	// Token is the bearer token used to authenticate with
+	// the attestation store.
+	Token string
+}
+
+// New returns a new ProvenanceService that signs the
+// attestations with the private key.
+func New(artifacts core.ArtifactStore, key *rsa.PrivateKey, config Config) core.ProvenanceService {
+	return &service{
+		artifacts: artifacts,
+		key:       key,
+		builder:   config.Builder,
+		endpoint:  config.Endpoint,
+		token:     config.Token,
+		client:    http.DefaultClient,
+	}
+}
+
+type service struct {
+	artifacts core.ArtifactStore
+	key       *rsa.PrivateKey
+	builder   string
+	endpoint  string
+	token     string
+	client    *http.Client
+}
+
+func (s *service) Generate(ctx context.Context, args *core.ProvenanceArgs) (*core.Envelope, error) {
+	subjects, err := s.subjects(ctx, args.Build)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := json.Marshal(&statement{
+		Type:          statementType,
+		PredicateType: predicateType,
+		Subject:       subjects,
+		Predicate:     s.predicate(args),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return Sign(s.key, payload)
+}
+
+func (s *service) Publish(ctx context.Context, args *core.ProvenanceArgs) error {
+	if s.endpoint == "" {
+		return nil
+	}
+	envelope, err := s.Generate(ctx, args)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(envelope)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(ctx, time.Minute)
+	defer cancel()
+
+	req, err := http.NewRequest("POST", s.endpoint, bytes.NewBuffer(data))
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Add("Content-Type", "application/json")
+	if s.token != "" {
+		req.Header.Add("Authorization", "Bearer "+s.token)
+	}
+	res, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return fmt.Errorf("provenance: unexpected status code %d", res.StatusCode)
+	}
+	return nil
+}
+
+// subjects returns the build artifacts and their digests.
+func (s *service) subjects(ctx context.Context, build *core.Build) ([]*subject, error) {
+	artifacts, err := s.artifacts.List(ctx, build.ID)
+	if err != nil {
+		return nil, err
+	}
+	subjects := []*subject{}
+	for _, artifact := range artifacts {
+		rc, err := s.artifacts.Open(ctx, artifact)
+		if err != nil {
+			return nil, err
+		}
+		h := sha256.New()
+		_, err = io.Copy(h, rc)
+		rc.Close()
+		if err != nil {
+			return nil, err
+		}
+		subjects = append(subjects, &subject{
+			Name:   artifact.Name,
+			Digest: map[string]string{"sha256": hex.EncodeToString(h.Sum(nil))},
+		})
+	}
+	return subjects, nil
+}
+
+// predicate returns the provenance predicate describing the
+// builder, the source and the pipeline steps.
+func (s *service) predicate(args *core.ProvenanceArgs) *predicate {
+	repo, build := args.Repo, args.Build
+	source := &material{
+		URI:    "git+" + repo.HTTPURL + "@" + build.Ref,
+		Digest: map[string]string{"sha1": build.After},
+	}
+	out := &predicate{
+		Builder:   builder{ID: s.builder},
+		BuildType: buildType,
+		Invocation: invocation{
+			ConfigSource: configSource{
+				URI:        source.URI,
+				Digest:     source.Digest,
+				EntryPoint: repo.Config,
+			},
+			Parameters: build.Params,
+		},
+		Metadata: metadata{
+			BuildInvocationID: fmt.Sprintf("%s/%s/%d", s.builder, repo.Slug, build.Number),
+			BuildStartedOn:    timestamp(build.Started),
+			BuildFinishedOn:   timestamp(build.Finished),
+			Completeness: completeness{
+				Parameters: true,
+			},
+		},
+		Materials: []*material{source},
+	}
+	seen := map[string]bool{}
+	for _, stage := range args.Stages {
+		config := &stageConfig{
+			Name:    stage.Name,
+			Machine: stage.Machine,
+			Steps:   []*stepConfig{},
+		}
+		for _, step := range stage.Steps {
+			config.Steps = append(config.Steps, &stepConfig{
+				Name:     step.Name,
+				Status:   step.Status,
+				ExitCode: step.ExitCode,
+				Digest:   step.Digest,
+			})
+			if m := imageMaterial(step.Digest); m != nil && !seen[step.Digest] {
+				seen[step.Digest] = true
+				out.Materials = append(out.Materials, m)
+			}
+		}
+		out.BuildConfig.Stages = append(out.BuildConfig.Stages, config)
+	}
+	return out
+}
+
+// Sign returns a signed envelope for the attestation
+// payload, using the RSASSA-PKCS1-v1_5 signature scheme.
+func Sign(key *rsa.PrivateKey, payload []byte) (*core.Envelope, error) {
+	digest := sha256.Sum256(pae(payloadType, payload))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		return nil, err
+	}
+	return &core.Envelope{
+		PayloadType: payloadType,
+		Payload:     base64.StdEncoding.EncodeToString(payload),
+		Signatures: []*core.EnvelopeSignature{
+			{
+				KeyID: idtoken.KeyID(&key.PublicKey),
+				Sig:   base64.StdEncoding.EncodeToString(sig),
+			},
+		},
+	}, nil
+}
+
+// helper function returns the envelope pre-authentication
+// encoding, which is the signed message.
+func pae(typ string, payload []byte) []byte {
+	return []byte(fmt.Sprintf("DSSEv1 %d %s %d %s", len(typ), typ, len(payload), payload))
+}
+
+// helper function returns the material for the image
+// digest (e.g. golang@sha256:3e8d). Local image identifiers
+// cannot be retrieved from a registry and are ignored.
+func imageMaterial(digest string) *material {
+	parts := strings.SplitN(digest, "@", 2)
+	if len(parts) != 2 {
+		return nil
+	}
+	algo := strings.SplitN(parts[1], ":", 2)
+	if len(algo) != 2 {
+		return nil
+	}
+	return &material{
+		URI:    "pkg:docker/" + parts[0],
+		Digest: map[string]string{algo[0]: algo[1]},
+	}
+}
+
+// helper function returns the unix timestamp formatted
+// as an RFC 3339 string.
+func timestamp(unix int64) string {
+	if unix == 0 {
+		return ""
+	}
+	return time.Unix(unix, 0).UTC().Format(time.RFC3339)
+}
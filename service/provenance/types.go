@@ -0,0 +1,87 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provenance
+
+type statement struct {
+	Type          string     `json:"_type"`
+	PredicateType string     `json:"predicateType"`
+	Subject       []*subject `json:"subject"`
+	Predicate     *predicate `json:"predicate"`
+}
+
+type subject struct {
+	Name   string            `json:"name"`
+	Digest map[string]string `json:"digest"`
+}
+
+type predicate struct {
+	Builder     builder     `json:"builder"`
+	BuildType   string      `json:"buildType"`
+	Invocation  invocation  `json:"invocation"`
+	BuildConfig buildConfig `json:"buildConfig"`
+	Metadata    metadata    `json:"metadata"`
+	Materials   []*material `json:"materials"`
+}
+
+type builder struct {
+	ID string `json:"id"`
+}
+
+type invocation struct {
+	ConfigSource configSource      `json:"configSource"`
+	Parameters   map[string]string `json:"parameters,omitempty"`
+}
+
+type configSource struct {
+	URI        string            `json:"uri"`
+	Digest     map[string]string `json:"digest"`
+	EntryPoint string            `json:"entryPoint"`
+}
+
+type buildConfig struct {
+	Stages []*stageConfig `json:"stages"`
+}
+
+type stageConfig struct {
+	Name    string        `json:"name"`
+	Machine string        `json:"machine,omitempty"`
+	Steps   []*stepConfig `json:"steps"`
+}
+
+type stepConfig struct {
+	Name     string `json:"name"`
+	Status   string `json:"status"`
+	ExitCode int    `json:"exit_code"`
+	Digest   string `json:"digest,omitempty"`
+}
+
+type metadata struct {
+	BuildInvocationID string       `json:"buildInvocationId"`
+	BuildStartedOn    string       `json:"buildStartedOn,omitempty"`
+	BuildFinishedOn   string       `json:"buildFinishedOn,omitempty"`
+	Completeness      completeness `json:"completeness"`
+	Reproducible      bool         `json:"reproducible"`
+}
+
+type completeness struct {
+	Parameters  bool `json:"parameters"`
+	Environment bool `json:"environment"`
+	Materials   bool `json:"materials"`
+}
+
+type material struct {
+	URI    string            `json:"uri"`
+	Digest map[string]string `json:"digest"`
+}
@@ -0,0 +1,128 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package provider routes requests for repositories hosted by
+// additional source code management providers.
+package provider
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"github.com/drone/drone/core"
+)
+
+var _ core.ProviderService = (*Router)(nil)
+
+// errUnknownProvider is returned when the repository is hosted
+// by a provider that is not configured.
+var errUnknownProvider = errors.New("provider: unknown source code management provider")
+
+// Provider is an additional source code management provider.
+// The provider authenticates with a service account token
+// instead of the token of the user that owns the repository.
+type Provider struct {
+	Name         string
+	Token        string
+	Commits      core.CommitService
+	Contents     core.FileService
+	Hooks        core.HookService
+	Netrcs       core.NetrcService
+	Parser       core.HookParser
+	PullRequests core.PullRequestService
+	References   core.ReferenceService
+	Repos        core.RepositoryService
+	Statuses     core.StatusService
+}
+
+// helper function returns the service account user.
+func (p *Provider) user() *core.User {
+	return &core.User{Login: p.Name, Token: p.Token}
+}
+
+// Router routes requests for repositories hosted by additional
+// providers to the provider services, and all other requests
+// to the default provider services.
+type Router struct {
+	providers map[string]*Provider
+}
+
+// New returns a new Router for the additional providers.
+func New(providers ...*Provider) *Router {
+	r := &Router{providers: map[string]*Provider{}}
+	for _, p := range providers {
+		r.providers[p.Name] = p
+	}
+	return r
+}
+
+// List returns the names of the additional providers.
+func (r *Router) List() []string {
+	var names []string
+	for name := range r.providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Find returns the named repository from the provider, with
+// the uid, namespace and slug qualified by the provider name.
+func (r *Router) Find(ctx context.Context, provider, slug string) (*core.Repository, error) {
+	p, ok := r.providers[provider]
+	if !ok {
+		return nil, errUnknownProvider
+	}
+	repo, err := p.Repos.Find(ctx, p.user(), slug)
+	if err != nil {
+		return nil, err
+	}
+	qualify(p.Name, repo)
+	return repo, nil
+}
+
+// helper function returns the provider that hosts the
+// repository with the qualified slug, and the remote slug.
+// If the slug is not qualified, a nil provider is returned.
+func (r *Router) route(slug string) (*Provider, string, error) {
+	name, remote := core.SplitProvider(slug)
+	if name == "" {
+		return nil, slug, nil
+	}
+	p, ok := r.providers[name]
+	if !ok {
+		return nil, slug, errUnknownProvider
+	}
+	return p, remote, nil
+}
+
+// helper function qualifies the repository uid, namespace and
+// slug with the provider name.
+func qualify(provider string, repo *core.Repository) {
+	repo.Provider = provider
+	repo.UID = core.QualifyProvider(provider, repo.UID)
+	repo.Namespace = core.QualifyProvider(provider, repo.Namespace)
+	repo.Slug = core.QualifyProvider(provider, repo.Slug)
+}
+
+// Renewer returns a Renewer for provider service account
+// tokens, which are never renewed.
+func Renewer() core.Renewer {
+	return new(noopRenewer)
+}
+
+type noopRenewer struct{}
+
+func (noopRenewer) Renew(context.Context, *core.User, bool) error { return nil }
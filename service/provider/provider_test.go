@@ -0,0 +1,184 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package provider
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/mock"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+)
+
+var noContext = context.Background()
+
+func TestList(t *testing.T) {
+	router := New(&Provider{Name: "internal"}, &Provider{Name: "external"})
+	if diff := cmp.Diff(router.List(), []string{"external", "internal"}); diff != "" {
+		t.Errorf(diff)
+	}
+}
+
+func TestFind(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	remote := &core.Repository{UID: "42", Namespace: "octocat", Name: "hello-world", Slug: "octocat/hello-world"}
+	repos := mock.NewMockRepositoryService(controller)
+	repos.EXPECT().Find(gomock.Any(), &core.User{Login: "internal", Token: "abc123"}, "octocat/hello-world").Return(remote, nil)
+
+	router := New(&Provider{Name: "internal", Token: "abc123", Repos: repos})
+	got, err := router.Find(noContext, "internal", "octocat/hello-world")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	want := &core.Repository{
+		UID:       "internal~42",
+		Namespace: "internal~octocat",
+		Name:      "hello-world",
+		Slug:      "internal~octocat/hello-world",
+		Provider:  "internal",
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf(diff)
+	}
+
+	if _, err := router.Find(noContext, "unknown", "octocat/hello-world"); err != errUnknownProvider {
+		t.Errorf("Want unknown provider error, got %v", err)
+	}
+}
+
+func TestCommits(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	user := &core.User{Login: "octocat", Token: "755bb80e5b"}
+	commit := &core.Commit{Sha: "7fd1a60b01f91b314f59955a4e4d4e80d8edf11d"}
+
+	base := mock.NewMockCommitService(controller)
+	base.EXPECT().Find(gomock.Any(), user, "octocat/hello-world", commit.Sha).Return(commit, nil)
+
+	internal := mock.NewMockCommitService(controller)
+	internal.EXPECT().Find(gomock.Any(), &core.User{Login: "internal", Token: "abc123"}, "octocat/hello-world", commit.Sha).Return(commit, nil)
+
+	service := New(&Provider{Name: "internal", Token: "abc123", Commits: internal}).Commits(base)
+	if _, err := service.Find(noContext, user, "octocat/hello-world", commit.Sha); err != nil {
+		t.Error(err)
+	}
+	if _, err := service.Find(noContext, user, "internal~octocat/hello-world", commit.Sha); err != nil {
+		t.Error(err)
+	}
+	if _, err := service.Find(noContext, user, "unknown~octocat/hello-world", commit.Sha); err != errUnknownProvider {
+		t.Errorf("Want unknown provider error, got %v", err)
+	}
+}
+
+func TestPullRequests(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	user := &core.User{Login: "octocat", Token: "755bb80e5b"}
+	pr := &core.PullRequest{Number: 42, Title: "Update the README"}
+
+	base := mock.NewMockPullRequestService(controller)
+	base.EXPECT().Find(gomock.Any(), user, "octocat/hello-world", 42).Return(pr, nil)
+
+	internal := mock.NewMockPullRequestService(controller)
+	internal.EXPECT().ListChanges(gomock.Any(), &core.User{Login: "internal", Token: "abc123"}, "octocat/hello-world", 42).Return(nil, nil)
+
+	service := New(&Provider{Name: "internal", Token: "abc123", PullRequests: internal}).PullRequests(base)
+	if _, err := service.Find(noContext, user, "octocat/hello-world", 42); err != nil {
+		t.Error(err)
+	}
+	if _, err := service.ListChanges(noContext, user, "internal~octocat/hello-world", 42); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestReferences(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	user := &core.User{Login: "octocat", Token: "755bb80e5b"}
+	refs := []*core.Reference{{Name: "master", Path: "refs/heads/master"}}
+
+	base := mock.NewMockReferenceService(controller)
+	base.EXPECT().ListBranches(gomock.Any(), user, "octocat/hello-world").Return(refs, nil)
+
+	internal := mock.NewMockReferenceService(controller)
+	internal.EXPECT().ListTags(gomock.Any(), &core.User{Login: "internal", Token: "abc123"}, "octocat/hello-world").Return(refs, nil)
+
+	service := New(&Provider{Name: "internal", Token: "abc123", References: internal}).References(base)
+	if _, err := service.ListBranches(noContext, user, "octocat/hello-world"); err != nil {
+		t.Error(err)
+	}
+	if _, err := service.ListTags(noContext, user, "internal~octocat/hello-world"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestNetrcs(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	repo := &core.Repository{
+		UID:       "internal~42",
+		Namespace: "internal~octocat",
+		Name:      "hello-world",
+		Slug:      "internal~octocat/hello-world",
+		Provider:  "internal",
+	}
+	netrc := &core.Netrc{Machine: "git.company.com", Login: "oauth2", Password: "abc123"}
+
+	internal := mock.NewMockNetrcService(controller)
+	internal.EXPECT().Create(gomock.Any(), &core.User{Login: "internal", Token: "abc123"}, repo.Remote()).Return(netrc, nil)
+
+	service := New(&Provider{Name: "internal", Token: "abc123", Netrcs: internal}).Netrcs(nil)
+	got, err := service.Create(noContext, &core.User{}, repo)
+	if err != nil {
+		t.Error(err)
+	}
+	if got != netrc {
+		t.Errorf("Want netrc from the provider")
+	}
+}
+
+func TestParser(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	r := httptest.NewRequest("POST", "/hook?provider=internal", nil)
+	hook := &core.Hook{Fork: "octocat/hello-world"}
+	remote := &core.Repository{UID: "42", Namespace: "octocat", Name: "hello-world", Slug: "octocat/hello-world"}
+
+	internal := mock.NewMockHookParser(controller)
+	internal.EXPECT().Parse(r, gomock.Any()).DoAndReturn(func(_ interface{}, fn func(string) string) (*core.Hook, *core.Repository, error) {
+		if got, want := fn("octocat/hello-world"), "internal~octocat/hello-world"; got != want {
+			t.Errorf("Want secret requested for qualified slug %q, got %q", want, got)
+		}
+		return hook, remote, nil
+	})
+
+	service := New(&Provider{Name: "internal", Parser: internal}).Parser(nil)
+	gotHook, gotRepo, err := service.Parse(r, func(slug string) string { return slug })
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if got, want := gotRepo.Namespace, "internal~octocat"; got != want {
+		t.Errorf("Want qualified namespace %q, got %q", want, got)
+	}
+	if got, want := gotRepo.Provider, "internal"; got != want {
+		t.Errorf("Want provider %q, got %q", want, got)
+	}
+	if got, want := gotHook.Fork, "internal~octocat/hello-world"; got != want {
+		t.Errorf("Want qualified fork %q, got %q", want, got)
+	}
+}
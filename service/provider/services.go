@@ -0,0 +1,293 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/drone/drone/core"
+)
+
+// Commits returns a CommitService that routes requests for
+// repositories hosted by additional providers.
+func (r *Router) Commits(base core.CommitService) core.CommitService {
+	if len(r.providers) == 0 {
+		return base
+	}
+	return &commits{router: r, base: base}
+}
+
+type commits struct {
+	router *Router
+	base   core.CommitService
+}
+
+func (s *commits) Find(ctx context.Context, user *core.User, repo, sha string) (*core.Commit, error) {
+	p, remote, err := s.router.route(repo)
+	if err != nil {
+		return nil, err
+	}
+	if p == nil {
+		return s.base.Find(ctx, user, repo, sha)
+	}
+	return p.Commits.Find(ctx, p.user(), remote, sha)
+}
+
+func (s *commits) FindRef(ctx context.Context, user *core.User, repo, ref string) (*core.Commit, error) {
+	p, remote, err := s.router.route(repo)
+	if err != nil {
+		return nil, err
+	}
+	if p == nil {
+		return s.base.FindRef(ctx, user, repo, ref)
+	}
+	return p.Commits.FindRef(ctx, p.user(), remote, ref)
+}
+
+func (s *commits) ListChanges(ctx context.Context, user *core.User, repo, sha, ref string) ([]*core.Change, error) {
+	p, remote, err := s.router.route(repo)
+	if err != nil {
+		return nil, err
+	}
+	if p == nil {
+		return s.base.ListChanges(ctx, user, repo, sha, ref)
+	}
+	return p.Commits.ListChanges(ctx, p.user(), remote, sha, ref)
+}
+
+// Contents returns a FileService that routes requests for
+// repositories hosted by additional providers.
+func (r *Router) Contents(base core.FileService) core.FileService {
+	if len(r.providers) == 0 {
+		return base
+	}
+	return &contents{router: r, base: base}
+}
+
+type contents struct {
+	router *Router
+	base   core.FileService
+}
+
+func (s *contents) Find(ctx context.Context, user *core.User, repo, commit, ref, path string) (*core.File, error) {
+	p, remote, err := s.router.route(repo)
+	if err != nil {
+		return nil, err
+	}
+	if p == nil {
+		return s.base.Find(ctx, user, repo, commit, ref, path)
+	}
+	return p.Contents.Find(ctx, p.user(), remote, commit, ref, path)
+}
+
+// Hooks returns a HookService that routes requests for
+// repositories hosted by additional providers.
+func (r *Router) Hooks(base core.HookService) core.HookService {
+	if len(r.providers) == 0 {
+		return base
+	}
+	return &hooks{router: r, base: base}
+}
+
+type hooks struct {
+	router *Router
+	base   core.HookService
+}
+
+func (s *hooks) Create(ctx context.Context, user *core.User, repo *core.Repository) error {
+	p, _, err := s.router.route(repo.Slug)
+	if err != nil {
+		return err
+	}
+	if p == nil {
+		return s.base.Create(ctx, user, repo)
+	}
+	return p.Hooks.Create(ctx, p.user(), repo.Remote())
+}
+
+func (s *hooks) Delete(ctx context.Context, user *core.User, repo *core.Repository) error {
+	p, _, err := s.router.route(repo.Slug)
+	if err != nil {
+		return err
+	}
+	if p == nil {
+		return s.base.Delete(ctx, user, repo)
+	}
+	return p.Hooks.Delete(ctx, p.user(), repo.Remote())
+}
+
+// Netrcs returns a NetrcService that routes requests for
+// repositories hosted by additional providers.
+func (r *Router) Netrcs(base core.NetrcService) core.NetrcService {
+	if len(r.providers) == 0 {
+		return base
+	}
+	return &netrcs{router: r, base: base}
+}
+
+type netrcs struct {
+	router *Router
+	base   core.NetrcService
+}
+
+func (s *netrcs) Create(ctx context.Context, user *core.User, repo *core.Repository) (*core.Netrc, error) {
+	p, _, err := s.router.route(repo.Slug)
+	if err != nil {
+		return nil, err
+	}
+	if p == nil {
+		return s.base.Create(ctx, user, repo)
+	}
+	return p.Netrcs.Create(ctx, p.user(), repo.Remote())
+}
+
+// Parser returns a HookParser that routes webhooks delivered
+// by additional providers, identified by the provider query
+// parameter, to the provider parser.
+func (r *Router) Parser(base core.HookParser) core.HookParser {
+	if len(r.providers) == 0 {
+		return base
+	}
+	return &parser{router: r, base: base}
+}
+
+type parser struct {
+	router *Router
+	base   core.HookParser
+}
+
+func (s *parser) Parse(req *http.Request, secretFunc func(string) string) (*core.Hook, *core.Repository, error) {
+	name := req.URL.Query().Get("provider")
+	if name == "" {
+		return s.base.Parse(req, secretFunc)
+	}
+	p, ok := s.router.providers[name]
+	if !ok {
+		return nil, nil, errUnknownProvider
+	}
+	hook, repo, err := p.Parser.Parse(req, func(slug string) string {
+		return secretFunc(core.QualifyProvider(name, slug))
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	if repo != nil {
+		qualify(name, repo)
+	}
+	if hook != nil {
+		hook.Fork = core.QualifyProvider(name, hook.Fork)
+	}
+	return hook, repo, nil
+}
+
+// PullRequests returns a PullRequestService that routes
+// requests for repositories hosted by additional providers.
+func (r *Router) PullRequests(base core.PullRequestService) core.PullRequestService {
+	if len(r.providers) == 0 {
+		return base
+	}
+	return &pulls{router: r, base: base}
+}
+
+type pulls struct {
+	router *Router
+	base   core.PullRequestService
+}
+
+func (s *pulls) Find(ctx context.Context, user *core.User, repo string, number int) (*core.PullRequest, error) {
+	p, remote, err := s.router.route(repo)
+	if err != nil {
+		return nil, err
+	}
+	if p == nil {
+		return s.base.Find(ctx, user, repo, number)
+	}
+	return p.PullRequests.Find(ctx, p.user(), remote, number)
+}
+
+func (s *pulls) ListChanges(ctx context.Context, user *core.User, repo string, number int) ([]*core.Change, error) {
+	p, remote, err := s.router.route(repo)
+	if err != nil {
+		return nil, err
+	}
+	if p == nil {
+		return s.base.ListChanges(ctx, user, repo, number)
+	}
+	return p.PullRequests.ListChanges(ctx, p.user(), remote, number)
+}
+
+// References returns a ReferenceService that routes requests
+// for repositories hosted by additional providers.
+func (r *Router) References(base core.ReferenceService) core.ReferenceService {
+	if len(r.providers) == 0 {
+		return base
+	}
+	return &references{router: r, base: base}
+}
+
+type references struct {
+	router *Router
+	base   core.ReferenceService
+}
+
+func (s *references) ListBranches(ctx context.Context, user *core.User, repo string) ([]*core.Reference, error) {
+	p, remote, err := s.router.route(repo)
+	if err != nil {
+		return nil, err
+	}
+	if p == nil {
+		return s.base.ListBranches(ctx, user, repo)
+	}
+	return p.References.ListBranches(ctx, p.user(), remote)
+}
+
+func (s *references) ListTags(ctx context.Context, user *core.User, repo string) ([]*core.Reference, error) {
+	p, remote, err := s.router.route(repo)
+	if err != nil {
+		return nil, err
+	}
+	if p == nil {
+		return s.base.ListTags(ctx, user, repo)
+	}
+	return p.References.ListTags(ctx, p.user(), remote)
+}
+
+// Statuses returns a StatusService that routes requests for
+// repositories hosted by additional providers.
+func (r *Router) Statuses(base core.StatusService) core.StatusService {
+	if len(r.providers) == 0 {
+		return base
+	}
+	return &statuses{router: r, base: base}
+}
+
+type statuses struct {
+	router *Router
+	base   core.StatusService
+}
+
+func (s *statuses) Send(ctx context.Context, user *core.User, req *core.StatusInput) error {
+	p, _, err := s.router.route(req.Repo.Slug)
+	if err != nil {
+		return err
+	}
+	if p == nil {
+		return s.base.Send(ctx, user, req)
+	}
+	in := *req
+	in.Repo = req.Repo.Remote()
+	return p.Statuses.Send(ctx, p.user(), &in)
+}
@@ -0,0 +1,105 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pull
+
+import (
+	"context"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/go-scm/scm"
+)
+
+// maximum number of pages requested from the source code
+// management system when listing the changed files.
+const maxPages = 10
+
+// New returns a new PullRequestService.
+func New(client *scm.Client, renew core.Renewer) core.PullRequestService {
+	return &service{
+		client: client,
+		renew:  renew,
+	}
+}
+
+type service struct {
+	renew  core.Renewer
+	client *scm.Client
+}
+
+func (s *service) Find(ctx context.Context, user *core.User, repo string, number int) (*core.PullRequest, error) {
+	ctx, err := s.withToken(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	pr, _, err := s.client.PullRequests.Find(ctx, repo, number)
+	if err != nil {
+		return nil, err
+	}
+	out := &core.PullRequest{
+		Number: pr.Number,
+		Title:  pr.Title,
+	}
+	// the pull request labels are not included in the pull
+	// request resource, however, most providers manage pull
+	// request labels as issue labels. Labels are optional and
+	// are omitted if the issue cannot be found.
+	if s.client.Issues != nil {
+		if issue, _, err := s.client.Issues.Find(ctx, repo, number); err == nil {
+			out.Labels = issue.Labels
+		}
+	}
+	return out, nil
+}
+
+func (s *service) ListChanges(ctx context.Context, user *core.User, repo string, number int) ([]*core.Change, error) {
+	ctx, err := s.withToken(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	var changes []*core.Change
+	opts := scm.ListOptions{Size: 100}
+	for i := 0; i < maxPages; i++ {
+		out, res, err := s.client.PullRequests.ListChanges(ctx, repo, number, opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, change := range out {
+			changes = append(changes, &core.Change{
+				Path:    change.Path,
+				Added:   change.Added,
+				Renamed: change.Renamed,
+				Deleted: change.Deleted,
+			})
+		}
+		if res == nil || res.Page.Next == 0 {
+			break
+		}
+		opts.Page = res.Page.Next
+	}
+	return changes, nil
+}
+
+// helper function renews the user token and returns a
+// context with the token attached.
+func (s *service) withToken(ctx context.Context, user *core.User) (context.Context, error) {
+	err := s.renew.Renew(ctx, user, false)
+	if err != nil {
+		return ctx, err
+	}
+	return context.WithValue(ctx, scm.TokenKey{}, &scm.Token{
+		Token:   user.Token,
+		Refresh: user.Refresh,
+	}), nil
+}
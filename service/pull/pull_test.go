@@ -0,0 +1,163 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package pull
+
+import (
+	"context"
+	"testing"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/mock"
+	"github.com/drone/drone/mock/mockscm"
+	"github.com/drone/go-scm/scm"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+)
+
+var noContext = context.Background()
+
+func TestFind(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockUser := &core.User{}
+
+	mockRenewer := mock.NewMockRenewer(controller)
+	mockRenewer.EXPECT().Renew(gomock.Any(), mockUser, false).Return(nil)
+
+	mockPulls := mockscm.NewMockPullRequestService(controller)
+	mockPulls.EXPECT().Find(gomock.Any(), "octocat/hello-world", 42).Return(&scm.PullRequest{Number: 42, Title: "Update the README"}, nil, nil)
+
+	mockIssues := mockscm.NewMockIssueService(controller)
+	mockIssues.EXPECT().Find(gomock.Any(), "octocat/hello-world", 42).Return(&scm.Issue{Number: 42, Labels: []string{"docs"}}, nil, nil)
+
+	client := new(scm.Client)
+	client.PullRequests = mockPulls
+	client.Issues = mockIssues
+
+	want := &core.PullRequest{
+		Number: 42,
+		Title:  "Update the README",
+		Labels: []string{"docs"},
+	}
+
+	service := New(client, mockRenewer)
+	got, err := service.Find(noContext, mockUser, "octocat/hello-world", 42)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf(diff)
+	}
+}
+
+// this test verifies that the pull request is returned
+// without labels if the labels cannot be retrieved.
+func TestFind_NoLabels(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockUser := &core.User{}
+
+	mockRenewer := mock.NewMockRenewer(controller)
+	mockRenewer.EXPECT().Renew(gomock.Any(), mockUser, false).Return(nil)
+
+	mockPulls := mockscm.NewMockPullRequestService(controller)
+	mockPulls.EXPECT().Find(gomock.Any(), "octocat/hello-world", 42).Return(&scm.PullRequest{Number: 42, Title: "Update the README"}, nil, nil)
+
+	mockIssues := mockscm.NewMockIssueService(controller)
+	mockIssues.EXPECT().Find(gomock.Any(), "octocat/hello-world", 42).Return(nil, nil, scm.ErrNotSupported)
+
+	client := new(scm.Client)
+	client.PullRequests = mockPulls
+	client.Issues = mockIssues
+
+	service := New(client, mockRenewer)
+	got, err := service.Find(noContext, mockUser, "octocat/hello-world", 42)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if got.Labels != nil {
+		t.Errorf("Want nil labels, got %v", got.Labels)
+	}
+}
+
+func TestFind_Err(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockUser := &core.User{}
+
+	mockRenewer := mock.NewMockRenewer(controller)
+	mockRenewer.EXPECT().Renew(gomock.Any(), mockUser, false).Return(nil)
+
+	mockPulls := mockscm.NewMockPullRequestService(controller)
+	mockPulls.EXPECT().Find(gomock.Any(), "octocat/hello-world", 42).Return(nil, nil, scm.ErrNotFound)
+
+	client := new(scm.Client)
+	client.PullRequests = mockPulls
+
+	service := New(client, mockRenewer)
+	_, err := service.Find(noContext, mockUser, "octocat/hello-world", 42)
+	if err != scm.ErrNotFound {
+		t.Errorf("Expect not found error, got %v", err)
+	}
+}
+
+func TestListChanges(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockUser := &core.User{}
+
+	mockRenewer := mock.NewMockRenewer(controller)
+	mockRenewer.EXPECT().Renew(gomock.Any(), mockUser, false).Return(nil)
+
+	res := new(scm.Response)
+	res.Page.Next = 2
+
+	mockPulls := mockscm.NewMockPullRequestService(controller)
+	gomock.InOrder(
+		mockPulls.EXPECT().ListChanges(gomock.Any(), "octocat/hello-world", 42, scm.ListOptions{Size: 100}).Return([]*scm.Change{{Path: "README.md"}}, res, nil),
+		mockPulls.EXPECT().ListChanges(gomock.Any(), "octocat/hello-world", 42, scm.ListOptions{Page: 2, Size: 100}).Return([]*scm.Change{{Path: "main.go", Added: true}}, &scm.Response{}, nil),
+	)
+
+	client := new(scm.Client)
+	client.PullRequests = mockPulls
+
+	want := []*core.Change{
+		{Path: "README.md"},
+		{Path: "main.go", Added: true},
+	}
+
+	service := New(client, mockRenewer)
+	got, err := service.ListChanges(noContext, mockUser, "octocat/hello-world", 42)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf(diff)
+	}
+}
+
+func TestListChanges_RenewalError(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockUser := &core.User{}
+
+	mockRenewer := mock.NewMockRenewer(controller)
+	mockRenewer.EXPECT().Renew(gomock.Any(), mockUser, false).Return(scm.ErrNotAuthorized)
+
+	service := New(nil, mockRenewer)
+	_, err := service.ListChanges(noContext, mockUser, "octocat/hello-world", 42)
+	if err != scm.ErrNotAuthorized {
+		t.Errorf("Expect not authorized error, got %v", err)
+	}
+}
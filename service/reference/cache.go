@@ -0,0 +1,78 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reference
+
+import (
+	"context"
+	"time"
+
+	"github.com/drone/drone/core"
+
+	"github.com/hashicorp/golang-lru"
+)
+
+// NewCache wraps the service with a simple cache to store
+// the branches and tags of each repository for the duration
+// of the ttl, reducing the number of requests to the source
+// code management system.
+func NewCache(base core.ReferenceService, size int, ttl time.Duration) core.ReferenceService {
+	cache, _ := lru.New(size)
+	return &cacher{
+		base:  base,
+		cache: cache,
+		ttl:   ttl,
+	}
+}
+
+type cacher struct {
+	base  core.ReferenceService
+	cache *lru.Cache
+	ttl   time.Duration
+}
+
+type item struct {
+	refs    []*core.Reference
+	expires time.Time
+}
+
+func (c *cacher) ListBranches(ctx context.Context, user *core.User, repo string) ([]*core.Reference, error) {
+	return c.list("branches/"+repo, func() ([]*core.Reference, error) {
+		return c.base.ListBranches(ctx, user, repo)
+	})
+}
+
+func (c *cacher) ListTags(ctx context.Context, user *core.User, repo string) ([]*core.Reference, error) {
+	return c.list("tags/"+repo, func() ([]*core.Reference, error) {
+		return c.base.ListTags(ctx, user, repo)
+	})
+}
+
+func (c *cacher) list(key string, fn func() ([]*core.Reference, error)) ([]*core.Reference, error) {
+	if cached, ok := c.cache.Get(key); ok {
+		if item := cached.(*item); time.Now().Before(item.expires) {
+			return item.refs, nil
+		}
+		c.cache.Remove(key)
+	}
+	refs, err := fn()
+	if err != nil {
+		return nil, err
+	}
+	c.cache.Add(key, &item{
+		refs:    refs,
+		expires: time.Now().Add(c.ttl),
+	})
+	return refs, nil
+}
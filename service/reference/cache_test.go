@@ -0,0 +1,81 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package reference
+
+import (
+	"testing"
+	"time"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/mock"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/golang/mock/gomock"
+)
+
+func TestCache(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	user := &core.User{ID: 1}
+	want := []*core.Reference{{Name: "master", Path: "refs/heads/master"}}
+
+	base := mock.NewMockReferenceService(controller)
+	base.EXPECT().ListBranches(noContext, user, "octocat/hello-world").Return(want, nil).Times(1)
+
+	service := NewCache(base, 10, time.Hour)
+	for i := 0; i < 2; i++ {
+		got, err := service.ListBranches(noContext, user, "octocat/hello-world")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if diff := cmp.Diff(got, want); diff != "" {
+			t.Errorf(diff)
+		}
+	}
+}
+
+func TestCache_Expired(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	user := &core.User{ID: 1}
+	tags := []*core.Reference{{Name: "v1.0.0", Path: "refs/tags/v1.0.0"}}
+
+	base := mock.NewMockReferenceService(controller)
+	base.EXPECT().ListTags(noContext, user, "octocat/hello-world").Return(tags, nil).Times(2)
+
+	service := NewCache(base, 10, -time.Second)
+	service.ListTags(noContext, user, "octocat/hello-world")
+	service.ListTags(noContext, user, "octocat/hello-world")
+}
+
+// this test verifies that branches and tags are cached
+// separately for the same repository.
+func TestCache_Kind(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	user := &core.User{ID: 1}
+	branches := []*core.Reference{{Name: "master", Path: "refs/heads/master"}}
+	tags := []*core.Reference{{Name: "v1.0.0", Path: "refs/tags/v1.0.0"}}
+
+	base := mock.NewMockReferenceService(controller)
+	base.EXPECT().ListBranches(noContext, user, "octocat/hello-world").Return(branches, nil)
+	base.EXPECT().ListTags(noContext, user, "octocat/hello-world").Return(tags, nil)
+
+	service := NewCache(base, 10, time.Hour)
+	service.ListBranches(noContext, user, "octocat/hello-world")
+
+	got, err := service.ListTags(noContext, user, "octocat/hello-world")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if diff := cmp.Diff(got, tags); diff != "" {
+		t.Errorf(diff)
+	}
+}
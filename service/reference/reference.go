@@ -0,0 +1,86 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reference
+
+import (
+	"context"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/go-scm/scm"
+)
+
+// maximum number of pages requested from the source code
+// management system when listing branches or tags.
+const maxPages = 10
+
+// New returns a new ReferenceService.
+func New(client *scm.Client, renew core.Renewer) core.ReferenceService {
+	return &service{
+		client: client,
+		renew:  renew,
+	}
+}
+
+type service struct {
+	renew  core.Renewer
+	client *scm.Client
+}
+
+func (s *service) ListBranches(ctx context.Context, user *core.User, repo string) ([]*core.Reference, error) {
+	return s.list(ctx, user, func(ctx context.Context, opts scm.ListOptions) ([]*scm.Reference, *scm.Response, error) {
+		return s.client.Git.ListBranches(ctx, repo, opts)
+	})
+}
+
+func (s *service) ListTags(ctx context.Context, user *core.User, repo string) ([]*core.Reference, error) {
+	return s.list(ctx, user, func(ctx context.Context, opts scm.ListOptions) ([]*scm.Reference, *scm.Response, error) {
+		return s.client.Git.ListTags(ctx, repo, opts)
+	})
+}
+
+// list pages through the references returned by the list
+// function, up to the maximum number of pages.
+func (s *service) list(ctx context.Context, user *core.User, fn listFunc) ([]*core.Reference, error) {
+	err := s.renew.Renew(ctx, user, false)
+	if err != nil {
+		return nil, err
+	}
+	ctx = context.WithValue(ctx, scm.TokenKey{}, &scm.Token{
+		Token:   user.Token,
+		Refresh: user.Refresh,
+	})
+	refs := []*core.Reference{}
+	opts := scm.ListOptions{Size: 100}
+	for i := 0; i < maxPages; i++ {
+		out, res, err := fn(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, ref := range out {
+			refs = append(refs, &core.Reference{
+				Name: ref.Name,
+				Path: ref.Path,
+				Sha:  ref.Sha,
+			})
+		}
+		if res == nil || res.Page.Next == 0 {
+			break
+		}
+		opts.Page = res.Page.Next
+	}
+	return refs, nil
+}
+
+type listFunc func(context.Context, scm.ListOptions) ([]*scm.Reference, *scm.Response, error)
@@ -0,0 +1,130 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package reference
+
+import (
+	"context"
+	"testing"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/mock"
+	"github.com/drone/drone/mock/mockscm"
+	"github.com/drone/go-scm/scm"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+)
+
+var noContext = context.Background()
+
+func TestListBranches(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockUser := &core.User{}
+
+	mockRenewer := mock.NewMockRenewer(controller)
+	mockRenewer.EXPECT().Renew(gomock.Any(), mockUser, false).Return(nil)
+
+	page1 := []*scm.Reference{{Name: "master", Path: "refs/heads/master", Sha: "a6586b3db244fb6b1198f2b25c213ded5b44f9fa"}}
+	page2 := []*scm.Reference{{Name: "develop", Path: "refs/heads/develop", Sha: "7fd1a60b01f91b314f59955a4e4d4e80d8edf11d"}}
+
+	res := new(scm.Response)
+	res.Page.Next = 2
+
+	mockGit := mockscm.NewMockGitService(controller)
+	gomock.InOrder(
+		mockGit.EXPECT().ListBranches(gomock.Any(), "octocat/hello-world", scm.ListOptions{Size: 100}).Return(page1, res, nil),
+		mockGit.EXPECT().ListBranches(gomock.Any(), "octocat/hello-world", scm.ListOptions{Page: 2, Size: 100}).Return(page2, &scm.Response{}, nil),
+	)
+
+	client := new(scm.Client)
+	client.Git = mockGit
+
+	want := []*core.Reference{
+		{Name: "master", Path: "refs/heads/master", Sha: "a6586b3db244fb6b1198f2b25c213ded5b44f9fa"},
+		{Name: "develop", Path: "refs/heads/develop", Sha: "7fd1a60b01f91b314f59955a4e4d4e80d8edf11d"},
+	}
+
+	service := New(client, mockRenewer)
+	got, err := service.ListBranches(noContext, mockUser, "octocat/hello-world")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf(diff)
+	}
+}
+
+func TestListTags(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockUser := &core.User{}
+
+	mockRenewer := mock.NewMockRenewer(controller)
+	mockRenewer.EXPECT().Renew(gomock.Any(), mockUser, false).Return(nil)
+
+	mockTags := []*scm.Reference{{Name: "v1.0.0", Path: "refs/tags/v1.0.0", Sha: "a6586b3db244fb6b1198f2b25c213ded5b44f9fa"}}
+
+	mockGit := mockscm.NewMockGitService(controller)
+	mockGit.EXPECT().ListTags(gomock.Any(), "octocat/hello-world", scm.ListOptions{Size: 100}).Return(mockTags, &scm.Response{}, nil)
+
+	client := new(scm.Client)
+	client.Git = mockGit
+
+	want := []*core.Reference{
+		{Name: "v1.0.0", Path: "refs/tags/v1.0.0", Sha: "a6586b3db244fb6b1198f2b25c213ded5b44f9fa"},
+	}
+
+	service := New(client, mockRenewer)
+	got, err := service.ListTags(noContext, mockUser, "octocat/hello-world")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf(diff)
+	}
+}
+
+func TestListBranches_Err(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockUser := &core.User{}
+
+	mockRenewer := mock.NewMockRenewer(controller)
+	mockRenewer.EXPECT().Renew(gomock.Any(), mockUser, false).Return(nil)
+
+	mockGit := mockscm.NewMockGitService(controller)
+	mockGit.EXPECT().ListBranches(gomock.Any(), "octocat/hello-world", gomock.Any()).Return(nil, nil, scm.ErrNotFound)
+
+	client := new(scm.Client)
+	client.Git = mockGit
+
+	service := New(client, mockRenewer)
+	_, err := service.ListBranches(noContext, mockUser, "octocat/hello-world")
+	if err != scm.ErrNotFound {
+		t.Errorf("Expect not found error, got %v", err)
+	}
+}
+
+func TestListBranches_RenewalError(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockUser := &core.User{}
+
+	mockRenewer := mock.NewMockRenewer(controller)
+	mockRenewer.EXPECT().Renew(gomock.Any(), mockUser, false).Return(scm.ErrNotAuthorized)
+
+	service := New(nil, mockRenewer)
+	_, err := service.ListBranches(noContext, mockUser, "octocat/hello-world")
+	if err != scm.ErrNotAuthorized {
+		t.Errorf("Expect not authorized error, got %v", err)
+	}
+}
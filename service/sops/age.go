@@ -0,0 +1,47 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sops
+
+import (
+	"errors"
+	"io/ioutil"
+	"strings"
+
+	"filippo.io/age"
+	"filippo.io/age/armor"
+)
+
+var errNoIdentity = errors.New("sops: no identity matched any of the recipients")
+
+// ParseIdentities parses the age identities, one per line,
+// in the format produced by age-keygen. Empty lines and
+// comments are ignored.
+func ParseIdentities(s string) ([]age.Identity, error) {
+	return age.ParseIdentities(strings.NewReader(s))
+}
+
+// ageDecrypt decrypts the armored age file using the first
+// identity that matches a recipient.
+func ageDecrypt(in string, identities []age.Identity) ([]byte, error) {
+	r := armor.NewReader(strings.NewReader(strings.TrimSpace(in)))
+	out, err := age.Decrypt(r, identities...)
+	if _, ok := err.(*age.NoIdentityMatchError); ok {
+		return nil, errNoIdentity
+	}
+	if err != nil {
+		return nil, err
+	}
+	return ioutil.ReadAll(out)
+}
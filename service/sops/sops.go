@@ -0,0 +1,318 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sops
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/go-scm/scm"
+
+	"filippo.io/age"
+	"gopkg.in/yaml.v2"
+)
+
+// the sops metadata key in the secrets file.
+const metadataKey = "sops"
+
+// sops encrypts values using AES-GCM with a 32 byte nonce.
+const nonceSize = 32
+
+var (
+	errMetadataMissing = errors.New("sops: metadata is missing")
+	errValueInvalid    = errors.New("sops: invalid encrypted value")
+	errMacInvalid      = errors.New("sops: message authentication code mismatch")
+)
+
+// encrypted value format, for example
+// ENC[AES256_GCM,data:8Z6s,iv:2l4v...,tag:jD5x...,type:str]
+var valueRE = regexp.MustCompile(`^ENC\[AES256_GCM,data:(.*),iv:(.+),tag:(.+),type:(.+)\]$`)
+
+type (
+	// metadata represents the sops metadata embedded in the
+	// secrets file. Only age key sources are supported.
+	metadata struct {
+		Age       []*ageKeySource `yaml:"age"`
+		KeyGroups []struct {
+			Age []*ageKeySource `yaml:"age"`
+		} `yaml:"key_groups"`
+		LastModified string `yaml:"lastmodified"`
+		Mac          string `yaml:"mac"`
+	}
+
+	// ageKeySource represents the sops data key encrypted
+	// for an age recipient.
+	ageKeySource struct {
+		Recipient string `yaml:"recipient"`
+		Enc       string `yaml:"enc"`
+	}
+)
+
+// Config configures the secrets file service.
+type Config struct {
+	// Path is the path of the secrets file in the repository.
+	Path string
+}
+
+// New returns a new SecretFileService that decrypts sops
+// secrets files encrypted for the age identities.
+func New(client *scm.Client, renew core.Renewer, identities []age.Identity, config Config) core.SecretFileService {
+	return &service{
+		client:     client,
+		renew:      renew,
+		identities: identities,
+		path:       config.Path,
+	}
+}
+
+type service struct {
+	client     *scm.Client
+	renew      core.Renewer
+	identities []age.Identity
+	path       string
+}
+
+func (s *service) List(ctx context.Context, user *core.User, repo *core.Repository, build *core.Build) ([]*core.Secret, error) {
+	// secrets files are not supported for repositories hosted
+	// by additional providers, since the file is fetched with
+	// the default source code management client.
+	if repo.Provider != "" {
+		return nil, nil
+	}
+	err := s.renew.Renew(ctx, user, false)
+	if err != nil {
+		return nil, err
+	}
+	ctx = context.WithValue(ctx, scm.TokenKey{}, &scm.Token{
+		Token:   user.Token,
+		Refresh: user.Refresh,
+	})
+	// the secrets file is optional. Unlike the configuration
+	// file, the request is not retried, since the file does
+	// not exist for most repositories.
+	content, _, err := s.client.Contents.Find(ctx, repo.Slug, s.path, build.After)
+	if err == scm.ErrNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return Decrypt(content.Data, s.identities)
+}
+
+// Decrypt decrypts the sops secrets file, in yaml or json
+// format, and returns the top-level encrypted values as
+// secrets. Unencrypted and nested values are ignored. The
+// document is authenticated with the sops message
+// authentication code before any value is returned. The
+// secrets are never exposed to pull requests.
+func Decrypt(data []byte, identities []age.Identity) ([]*core.Secret, error) {
+	var doc yaml.MapSlice
+	err := yaml.Unmarshal(data, &doc)
+	if err != nil {
+		return nil, err
+	}
+	meta, err := parseMetadata(doc)
+	if err != nil {
+		return nil, err
+	}
+	key, err := dataKey(meta, identities)
+	if err != nil {
+		return nil, err
+	}
+	// sops computes the message authentication code over
+	// every value in the document, in document order, after
+	// the value is decrypted.
+	hash := sha512.New()
+	var secrets []*core.Secret
+	for _, item := range doc {
+		name := fmt.Sprint(item.Key)
+		if name == metadataKey {
+			continue
+		}
+		value, ok := item.Value.(string)
+		if !ok || !valueRE.MatchString(value) {
+			err := walk(item.Value, []string{name}, key, hash)
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+		// sops authenticates each value with the path of the
+		// value in the document, which prevents encrypted
+		// values from being moved between keys.
+		plaintext, err := decryptValue(value, key, name+":")
+		if err != nil {
+			return nil, err
+		}
+		io.WriteString(hash, plaintext)
+		secrets = append(secrets, &core.Secret{
+			Name: name,
+			Data: plaintext,
+		})
+	}
+	if err := verifyMac(meta, key, hash.Sum(nil)); err != nil {
+		return nil, err
+	}
+	return secrets, nil
+}
+
+// parseMetadata returns the sops metadata embedded in the
+// secrets file.
+func parseMetadata(doc yaml.MapSlice) (*metadata, error) {
+	var raw interface{}
+	for _, item := range doc {
+		if item.Key == metadataKey {
+			raw = item.Value
+		}
+	}
+	if raw == nil {
+		return nil, errMetadataMissing
+	}
+	// the metadata is re-encoded to decode it into the
+	// typed structure.
+	out, err := yaml.Marshal(raw)
+	if err != nil {
+		return nil, err
+	}
+	meta := new(metadata)
+	err = yaml.Unmarshal(out, meta)
+	return meta, err
+}
+
+// dataKey returns the sops data key, decrypted with the
+// first age identity that matches a recipient.
+func dataKey(meta *metadata, identities []age.Identity) ([]byte, error) {
+	sources := meta.Age
+	for _, group := range meta.KeyGroups {
+		sources = append(sources, group.Age...)
+	}
+	for _, source := range sources {
+		key, err := ageDecrypt(source.Enc, identities)
+		if err == errNoIdentity {
+			continue
+		}
+		return key, err
+	}
+	return nil, errNoIdentity
+}
+
+// walk decrypts the value, and any nested values, and writes
+// the plaintext to the hash used to compute the message
+// authentication code.
+func walk(in interface{}, path []string, key []byte, hash io.Writer) error {
+	switch v := in.(type) {
+	case yaml.MapSlice:
+		for _, item := range v {
+			err := walk(item.Value, append(path, fmt.Sprint(item.Key)), key, hash)
+			if err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		for _, item := range v {
+			if err := walk(item, path, key, hash); err != nil {
+				return err
+			}
+		}
+	case string:
+		if valueRE.MatchString(v) {
+			plaintext, err := decryptValue(v, key, strings.Join(path, ":")+":")
+			if err != nil {
+				return err
+			}
+			v = plaintext
+		}
+		io.WriteString(hash, v)
+	case bool:
+		// sops encodes boolean values in title case.
+		if v {
+			io.WriteString(hash, "True")
+		} else {
+			io.WriteString(hash, "False")
+		}
+	case float64:
+		io.WriteString(hash, strconv.FormatFloat(v, 'f', -1, 64))
+	case nil:
+	default:
+		fmt.Fprint(hash, v)
+	}
+	return nil
+}
+
+// verifyMac verifies the message authentication code of the
+// document. The code is encrypted with the data key, and is
+// authenticated with the last modified timestamp.
+func verifyMac(meta *metadata, key, sum []byte) error {
+	modified, err := time.Parse(time.RFC3339, meta.LastModified)
+	if err != nil {
+		return errMacInvalid
+	}
+	mac, err := decryptValue(meta.Mac, key, modified.Format(time.RFC3339))
+	if err != nil {
+		return errMacInvalid
+	}
+	want := strings.ToUpper(hex.EncodeToString(sum))
+	if subtle.ConstantTimeCompare([]byte(mac), []byte(want)) != 1 {
+		return errMacInvalid
+	}
+	return nil
+}
+
+// decryptValue decrypts the sops encrypted value.
+func decryptValue(value string, key []byte, path string) (string, error) {
+	match := valueRE.FindStringSubmatch(value)
+	if match == nil {
+		return "", errValueInvalid
+	}
+	data, err := base64.StdEncoding.DecodeString(match[1])
+	if err != nil {
+		return "", errValueInvalid
+	}
+	iv, err := base64.StdEncoding.DecodeString(match[2])
+	if err != nil || len(iv) != nonceSize {
+		return "", errValueInvalid
+	}
+	tag, err := base64.StdEncoding.DecodeString(match[3])
+	if err != nil {
+		return "", errValueInvalid
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return "", err
+	}
+	gcm, err := cipher.NewGCMWithNonceSize(block, nonceSize)
+	if err != nil {
+		return "", err
+	}
+	plaintext, err := gcm.Open(nil, iv, append(data, tag...), []byte(path))
+	if err != nil {
+		return "", errValueInvalid
+	}
+	return string(plaintext), nil
+}
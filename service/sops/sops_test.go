@@ -0,0 +1,217 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package sops
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"testing"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/mock"
+	"github.com/drone/drone/mock/mockscm"
+	"github.com/drone/go-scm/scm"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+)
+
+var noContext = context.Background()
+
+// identity used to encrypt the data key in testdata/secrets.yml
+const mockIdentity = "AGE-SECRET-KEY-16FFN8M867YAUV3PD6R99ZA7RCMSNJTSLWZH3Q43NKZWV584VEYJQU7J7S6"
+
+// identity that is not a recipient of testdata/secrets.yml
+const mockOtherIdentity = "AGE-SECRET-KEY-1YQUDN5NH97RANMM0SDM9PSUGWURFKVE2KN60Q9J4XSQ9ZEGMXS8Q6Y3MPK"
+
+var (
+	mockUser = &core.User{
+		Login: "octocat",
+		Token: "755bb80e5b",
+	}
+
+	mockRepo = &core.Repository{
+		Slug: "octocat/hello-world",
+	}
+
+	mockBuild = &core.Build{
+		After: "7fd1a60b01f91b314f59955a4e4d4e80d8edf11d",
+		Ref:   "refs/heads/master",
+	}
+
+	mockSecrets = []*core.Secret{
+		{Name: "docker_password", Data: "correct-horse-battery-staple"},
+		{Name: "slack_token", Data: "xoxb-4b6c"},
+	}
+)
+
+func TestList(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	data, err := ioutil.ReadFile("testdata/secrets.yml")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	renewer := mock.NewMockRenewer(controller)
+	renewer.EXPECT().Renew(gomock.Any(), mockUser, false).Return(nil)
+
+	contents := mockscm.NewMockContentService(controller)
+	contents.EXPECT().Find(gomock.Any(), mockRepo.Slug, ".drone.secrets.yml", mockBuild.After).Return(&scm.Content{Data: data}, nil, nil)
+
+	client := new(scm.Client)
+	client.Contents = contents
+
+	identities, _ := ParseIdentities(mockIdentity)
+	service := New(client, renewer, identities, Config{Path: ".drone.secrets.yml"})
+	got, err := service.List(noContext, mockUser, mockRepo, mockBuild)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if diff := cmp.Diff(got, mockSecrets); diff != "" {
+		t.Errorf(diff)
+	}
+}
+
+// this test verifies that no secrets are returned, and no
+// error, when the repository does not have a secrets file.
+func TestList_NotFound(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	renewer := mock.NewMockRenewer(controller)
+	renewer.EXPECT().Renew(gomock.Any(), mockUser, false).Return(nil)
+
+	contents := mockscm.NewMockContentService(controller)
+	contents.EXPECT().Find(gomock.Any(), mockRepo.Slug, ".drone.secrets.yml", mockBuild.After).Return(nil, nil, scm.ErrNotFound)
+
+	client := new(scm.Client)
+	client.Contents = contents
+
+	service := New(client, renewer, nil, Config{Path: ".drone.secrets.yml"})
+	got, err := service.List(noContext, mockUser, mockRepo, mockBuild)
+	if err != nil {
+		t.Error(err)
+	}
+	if len(got) != 0 {
+		t.Errorf("Want empty secret list")
+	}
+}
+
+// this test verifies that the secrets file is ignored for
+// repositories hosted by additional providers.
+func TestList_Provider(t *testing.T) {
+	service := New(nil, nil, nil, Config{Path: ".drone.secrets.yml"})
+	got, err := service.List(noContext, mockUser, &core.Repository{Slug: "gitlab/octocat/hello-world", Provider: "gitlab"}, mockBuild)
+	if err != nil {
+		t.Error(err)
+	}
+	if len(got) != 0 {
+		t.Errorf("Want empty secret list")
+	}
+}
+
+// this test verifies that the secrets file cannot be
+// decrypted by an identity that is not a recipient.
+func TestDecrypt_NoIdentity(t *testing.T) {
+	data, err := ioutil.ReadFile("testdata/secrets.yml")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	identities, _ := ParseIdentities(mockOtherIdentity)
+	_, err = Decrypt(data, identities)
+	if err != errNoIdentity {
+		t.Errorf("Want error %s, got %v", errNoIdentity, err)
+	}
+}
+
+// this test verifies that an encrypted value cannot be
+// moved to a different key in the secrets file.
+func TestDecrypt_Moved(t *testing.T) {
+	data, err := ioutil.ReadFile("testdata/secrets.yml")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	data = bytes.Replace(data, []byte("slack_token:"), []byte("github_token:"), 1)
+	identities, _ := ParseIdentities(mockIdentity)
+	_, err = Decrypt(data, identities)
+	if err != errValueInvalid {
+		t.Errorf("Want error %s, got %v", errValueInvalid, err)
+	}
+}
+
+// this test verifies that the secrets file cannot be
+// decrypted when an unencrypted value is modified, or an
+// encrypted value is removed.
+func TestDecrypt_MacMismatch(t *testing.T) {
+	data, err := ioutil.ReadFile("testdata/secrets.yml")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	identities, _ := ParseIdentities(mockIdentity)
+
+	modified := bytes.Replace(data, []byte("us-east-1"), []byte("us-west-2"), 1)
+	_, err = Decrypt(modified, identities)
+	if err != errMacInvalid {
+		t.Errorf("Want error %s, got %v", errMacInvalid, err)
+	}
+
+	lines := bytes.SplitN(data, []byte("\n"), 2)
+	_, err = Decrypt(lines[1], identities)
+	if err != errMacInvalid {
+		t.Errorf("Want error %s, got %v", errMacInvalid, err)
+	}
+
+	removed := bytes.Replace(data, []byte("    mac: ENC"), []byte("    nomac: ENC"), 1)
+	_, err = Decrypt(removed, identities)
+	if err != errMacInvalid {
+		t.Errorf("Want error %s, got %v", errMacInvalid, err)
+	}
+}
+
+func TestDecrypt_MetadataMissing(t *testing.T) {
+	identities, _ := ParseIdentities(mockIdentity)
+	_, err := Decrypt([]byte("slack_token: xoxb-4b6c"), identities)
+	if err != errMetadataMissing {
+		t.Errorf("Want error %s, got %v", errMetadataMissing, err)
+	}
+}
+
+func TestParseIdentities(t *testing.T) {
+	identities, err := ParseIdentities(
+		"# created: 2019-01-01T00:00:00Z\n" +
+			"# public key: age1uhyu2qdnad4vqd9puu54ltpdj94epcx4ehk6mkteq0424llxkgds8x7tag\n" +
+			mockIdentity + "\n\n" +
+			mockOtherIdentity + "\n",
+	)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if got, want := len(identities), 2; got != want {
+		t.Errorf("Want %d identities, got %d", want, got)
+	}
+
+	_, err = ParseIdentities("")
+	if err == nil {
+		t.Errorf("Want error for empty identities")
+	}
+	// the checksum is invalid when a character is modified.
+	_, err = ParseIdentities(mockIdentity[:len(mockIdentity)-1] + "Q")
+	if err == nil {
+		t.Errorf("Want error for invalid checksum")
+	}
+	_, err = ParseIdentities("age1uhyu2qdnad4vqd9puu54ltpdj94epcx4ehk6mkteq0424llxkgds8x7tag")
+	if err == nil {
+		t.Errorf("Want error for recipient")
+	}
+}
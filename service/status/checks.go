@@ -0,0 +1,192 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package status
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/go-scm/scm"
+)
+
+// media type required to access the github checks api.
+const mediaTypeChecks = "application/vnd.github.antiope-preview+json"
+
+type (
+	checkRun struct {
+		ID          int64        `json:"id,omitempty"`
+		Name        string       `json:"name,omitempty"`
+		HeadSHA     string       `json:"head_sha,omitempty"`
+		ExternalID  string       `json:"external_id,omitempty"`
+		DetailsURL  string       `json:"details_url,omitempty"`
+		Status      string       `json:"status,omitempty"`
+		Conclusion  string       `json:"conclusion,omitempty"`
+		StartedAt   string       `json:"started_at,omitempty"`
+		CompletedAt string       `json:"completed_at,omitempty"`
+		Output      *checkOutput `json:"output,omitempty"`
+	}
+
+	checkOutput struct {
+		Title   string `json:"title"`
+		Summary string `json:"summary"`
+	}
+
+	checkRunList struct {
+		CheckRuns []*checkRun `json:"check_runs"`
+	}
+)
+
+// checks is a status service that reports each pipeline
+// stage as a GitHub check run. Build level updates are
+// delegated to the commit status service.
+type checks struct {
+	*service
+}
+
+func newChecks(client *scm.Client, renew core.Renewer, installations core.InstallationService, config Config) *checks {
+	return &checks{
+		service: newStatus(client, renew, installations, config),
+	}
+}
+
+func (s *checks) Send(ctx context.Context, user *core.User, req *core.StatusInput) error {
+	if s.disabled {
+		return nil
+	}
+	if req.Stage == nil {
+		return s.service.Send(ctx, user, req)
+	}
+
+	ctx, err := s.token(ctx, user, req, "checks")
+	if err != nil {
+		return err
+	}
+
+	in := createCheckRun(s.base, s.name, req)
+	id, err := s.findCheckRun(ctx, req.Repo.Slug, in)
+	if err != nil {
+		return err
+	}
+	if id == 0 {
+		path := fmt.Sprintf("repos/%s/check-runs", req.Repo.Slug)
+		return s.do(ctx, "POST", path, in, nil)
+	}
+	path := fmt.Sprintf("repos/%s/check-runs/%d", req.Repo.Slug, id)
+	return s.do(ctx, "PATCH", path, in, nil)
+}
+
+// findCheckRun returns the identifier of the check run
+// previously created for the stage, or zero if no check
+// run exists.
+func (s *checks) findCheckRun(ctx context.Context, slug string, in *checkRun) (int64, error) {
+	path := fmt.Sprintf("repos/%s/commits/%s/check-runs?check_name=%s",
+		slug, in.HeadSHA, url.QueryEscape(in.Name))
+	out := new(checkRunList)
+	err := s.do(ctx, "GET", path, nil, out)
+	if err != nil {
+		return 0, err
+	}
+	for _, run := range out.CheckRuns {
+		if run.ExternalID == in.ExternalID {
+			return run.ID, nil
+		}
+	}
+	return 0, nil
+}
+
+func (s *checks) do(ctx context.Context, method, path string, in, out interface{}) error {
+	req := &scm.Request{
+		Method: method,
+		Path:   path,
+		Header: http.Header{
+			"Accept": {mediaTypeChecks},
+		},
+	}
+	if in != nil {
+		buf := new(bytes.Buffer)
+		json.NewEncoder(buf).Encode(in)
+		req.Header.Set("Content-Type", "application/json")
+		req.Body = buf
+	}
+	res, err := s.client.Do(ctx, req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.Status > 299 {
+		return fmt.Errorf("status: checks api returned status code %d", res.Status)
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(res.Body).Decode(out)
+}
+
+// helper function creates the check run for the stage
+// that triggered the status update.
+func createCheckRun(base, name string, req *core.StatusInput) *checkRun {
+	stage := req.Stage
+	target := fmt.Sprintf("%s/%s/%d", base, req.Repo.Slug, req.Build.Number)
+	run := &checkRun{
+		Name:       fmt.Sprintf("%s/%s", createLabel(name, req.Build.Event), stage.Name),
+		HeadSHA:    req.Build.After,
+		ExternalID: fmt.Sprintf("%d/%d", req.Build.ID, stage.Number),
+		DetailsURL: fmt.Sprintf("%s/%d", target, stage.Number),
+		Status:     convertCheckStatus(stage.Status),
+		Conclusion: convertConclusion(stage.Status),
+		Output: &checkOutput{
+			Title:   createStageDesc(stage.Status),
+			Summary: createSummary(target, stage),
+		},
+	}
+	if stage.Started != 0 {
+		run.StartedAt = formatTime(stage.Started)
+	}
+	if run.Conclusion != "" && stage.Stopped != 0 {
+		run.CompletedAt = formatTime(stage.Stopped)
+	}
+	return run
+}
+
+// helper function creates the check run summary, listing
+// the failed steps with links to the step logs.
+func createSummary(target string, stage *core.Stage) string {
+	var failed []string
+	for _, step := range stage.Steps {
+		if step.ErrIgnore {
+			continue
+		}
+		switch step.Status {
+		case core.StatusFailing, core.StatusError, core.StatusKilled:
+			failed = append(failed, fmt.Sprintf("* [%s](%s/%d/%d) exited with code %d",
+				step.Name, target, stage.Number, step.Number, step.ExitCode))
+		}
+	}
+	if len(failed) == 0 {
+		return createStageDesc(stage.Status)
+	}
+	return "Failed steps:\n\n" + strings.Join(failed, "\n")
+}
+
+func formatTime(unix int64) string {
+	return time.Unix(unix, 0).UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,166 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package status
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/mock"
+	"github.com/drone/go-scm/scm/driver/github"
+
+	"github.com/golang/mock/gomock"
+	"github.com/h2non/gock"
+)
+
+func TestChecks_Create(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+	defer gock.Off()
+
+	mockUser := &core.User{Token: "12345"}
+
+	mockRenewer := mock.NewMockRenewer(controller)
+	mockRenewer.EXPECT().Renew(gomock.Any(), mockUser, false).Return(nil)
+
+	gock.New("https://api.github.com").
+		Get("/repos/octocat/hello-world/commits/a6586b3db244fb6b1198f2b25c213ded5b44f9fa/check-runs").
+		MatchParam("check_name", "continuous-integration/drone/push/default").
+		Reply(200).
+		JSON(map[string]interface{}{"check_runs": []interface{}{}})
+
+	gock.New("https://api.github.com").
+		Post("/repos/octocat/hello-world/check-runs").
+		MatchHeader("Accept", regexp.QuoteMeta(mediaTypeChecks)).
+		JSON(&checkRun{
+			Name:       "continuous-integration/drone/push/default",
+			HeadSHA:    "a6586b3db244fb6b1198f2b25c213ded5b44f9fa",
+			ExternalID: "42/1",
+			DetailsURL: "https://drone.company.com/octocat/hello-world/1/1",
+			Status:     "in_progress",
+			StartedAt:  "2019-01-01T00:00:00Z",
+			Output: &checkOutput{
+				Title:   "Stage is running",
+				Summary: "Stage is running",
+			},
+		}).
+		Reply(201).
+		JSON(map[string]interface{}{"id": 1})
+
+	client, _ := github.New("https://api.github.com")
+	service := New(client, mockRenewer, nil, Config{Base: "https://drone.company.com", Checks: true})
+	err := service.Send(noContext, mockUser, &core.StatusInput{
+		Repo: &core.Repository{Slug: "octocat/hello-world"},
+		Build: &core.Build{
+			ID:     42,
+			Number: 1,
+			Event:  core.EventPush,
+			Status: core.StatusRunning,
+			After:  "a6586b3db244fb6b1198f2b25c213ded5b44f9fa",
+		},
+		Stage: &core.Stage{
+			Number:  1,
+			Name:    "default",
+			Status:  core.StatusRunning,
+			Started: 1546300800,
+		},
+	})
+	if err != nil {
+		t.Error(err)
+	}
+	if !gock.IsDone() {
+		t.Errorf("Expected check run created")
+	}
+}
+
+func TestChecks_Update(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+	defer gock.Off()
+
+	mockUser := &core.User{Token: "12345"}
+
+	mockRenewer := mock.NewMockRenewer(controller)
+	mockRenewer.EXPECT().Renew(gomock.Any(), mockUser, false).Return(nil)
+
+	gock.New("https://api.github.com").
+		Get("/repos/octocat/hello-world/commits/a6586b3db244fb6b1198f2b25c213ded5b44f9fa/check-runs").
+		Reply(200).
+		JSON(map[string]interface{}{
+			"check_runs": []interface{}{
+				map[string]interface{}{"id": 1, "external_id": "41/1"},
+				map[string]interface{}{"id": 2, "external_id": "42/1"},
+			},
+		})
+
+	gock.New("https://api.github.com").
+		Patch("/repos/octocat/hello-world/check-runs/2").
+		JSON(&checkRun{
+			Name:        "continuous-integration/drone/push/default",
+			HeadSHA:     "a6586b3db244fb6b1198f2b25c213ded5b44f9fa",
+			ExternalID:  "42/1",
+			DetailsURL:  "https://drone.company.com/octocat/hello-world/1/1",
+			Status:      "completed",
+			Conclusion:  "failure",
+			StartedAt:   "2019-01-01T00:00:00Z",
+			CompletedAt: "2019-01-01T00:01:00Z",
+			Output: &checkOutput{
+				Title:   "Stage is failing",
+				Summary: "Failed steps:\n\n* [test](https://drone.company.com/octocat/hello-world/1/1/2) exited with code 1",
+			},
+		}).
+		Reply(200).
+		JSON(map[string]interface{}{"id": 2})
+
+	client, _ := github.New("https://api.github.com")
+	service := New(client, mockRenewer, nil, Config{Base: "https://drone.company.com", Checks: true})
+	err := service.Send(noContext, mockUser, &core.StatusInput{
+		Repo: &core.Repository{Slug: "octocat/hello-world"},
+		Build: &core.Build{
+			ID:     42,
+			Number: 1,
+			Event:  core.EventPush,
+			Status: core.StatusRunning,
+			After:  "a6586b3db244fb6b1198f2b25c213ded5b44f9fa",
+		},
+		Stage: &core.Stage{
+			Number:  1,
+			Name:    "default",
+			Status:  core.StatusFailing,
+			Started: 1546300800,
+			Stopped: 1546300860,
+			Steps: []*core.Step{
+				{Number: 1, Name: "clone", Status: core.StatusPassing},
+				{Number: 2, Name: "test", Status: core.StatusFailing, ExitCode: 1},
+				{Number: 3, Name: "lint", Status: core.StatusFailing, ExitCode: 1, ErrIgnore: true},
+			},
+		},
+	})
+	if err != nil {
+		t.Error(err)
+	}
+	if !gock.IsDone() {
+		t.Errorf("Expected check run updated")
+	}
+}
+
+func TestConvertConclusion(t *testing.T) {
+	tests := map[string]string{
+		core.StatusRunning:  "",
+		core.StatusPending:  "",
+		core.StatusPassing:  "success",
+		core.StatusFailing:  "failure",
+		core.StatusError:    "failure",
+		core.StatusKilled:   "cancelled",
+		core.StatusDeclined: "cancelled",
+		core.StatusSkipped:  "neutral",
+	}
+	for status, want := range tests {
+		if got := convertConclusion(status); got != want {
+			t.Errorf("Want conclusion %q for status %q, got %q", want, status, got)
+		}
+	}
+}
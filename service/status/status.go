@@ -17,6 +17,7 @@ package status
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/drone/drone/core"
 	"github.com/drone/go-scm/scm"
@@ -27,25 +28,48 @@ type Config struct {
 	Base     string
 	Name     string
 	Disabled bool
+	Checks   bool
+	Stages   bool
+
+	// Provider is the name of the additional source code
+	// management provider that hosts the repositories, used
+	// to link the status to the qualified repository.
+	Provider string
+}
+
+// New returns a new StatusService. If checks are enabled,
+// the service also reports each stage as a GitHub check run.
+// If the installation service is not nil, statuses are sent
+// using an app installation token instead of the user token.
+func New(client *scm.Client, renew core.Renewer, installations core.InstallationService, config Config) core.StatusService {
+	if config.Checks {
+		return newChecks(client, renew, installations, config)
+	}
+	return newStatus(client, renew, installations, config)
 }
 
-// New returns a new StatusService
-func New(client *scm.Client, renew core.Renewer, config Config) core.StatusService {
+func newStatus(client *scm.Client, renew core.Renewer, installations core.InstallationService, config Config) *service {
 	return &service{
-		client:   client,
-		renew:    renew,
-		base:     config.Base,
-		name:     config.Name,
-		disabled: config.Disabled,
+		client:        client,
+		renew:         renew,
+		installations: installations,
+		base:          config.Base,
+		name:          config.Name,
+		disabled:      config.Disabled,
+		stages:        config.Stages,
+		provider:      config.Provider,
 	}
 }
 
 type service struct {
-	renew    core.Renewer
-	client   *scm.Client
-	base     string
-	name     string
-	disabled bool
+	renew         core.Renewer
+	installations core.InstallationService
+	client        *scm.Client
+	base          string
+	name          string
+	disabled      bool
+	stages        bool
+	provider      string
 }
 
 func (s *service) Send(ctx context.Context, user *core.User, req *core.StatusInput) error {
@@ -53,24 +77,60 @@ func (s *service) Send(ctx context.Context, user *core.User, req *core.StatusInp
 		return nil
 	}
 
-	err := s.renew.Renew(ctx, user, false)
+	ctx, err := s.token(ctx, user, req, "statuses")
 	if err != nil {
 		return err
 	}
 
-	ctx = context.WithValue(ctx, scm.TokenKey{}, &scm.Token{
-		Token:   user.Token,
-		Refresh: user.Refresh,
-	})
+	// stage transitions are only reported as commit
+	// statuses when per-stage statuses are enabled.
+	if req.Stage != nil && !s.stages {
+		return nil
+	}
 
-	_, _, err = s.client.Repositories.CreateStatus(ctx, req.Repo.Slug, req.Build.After, &scm.StatusInput{
+	in := &scm.StatusInput{
 		Desc:   createDesc(req.Build.Status),
-		Label:  createLabel(s.name, req.Build.Event),
+		Label:  createBuildLabel(s.client.Driver, s.name, req.Build),
 		State:  convertStatus(req.Build.Status),
-		Target: fmt.Sprintf("%s/%s/%d", s.base, req.Repo.Slug, req.Build.Number),
-	})
+		Target: fmt.Sprintf("%s/%s/%d", s.base, core.QualifyProvider(s.provider, req.Repo.Slug), req.Build.Number),
+	}
+	if stage := req.Stage; stage != nil {
+		in.Desc = createStageDesc(stage.Status)
+		in.Label = fmt.Sprintf("%s/%s", in.Label, stage.Name)
+		in.State = convertStatus(stage.Status)
+		in.Target = fmt.Sprintf("%s/%d", in.Target, stage.Number)
+	}
+
+	_, _, err = s.client.Repositories.CreateStatus(ctx, req.Repo.Slug, req.Build.After, in)
 	if err == scm.ErrNotSupported {
 		return nil
 	}
 	return err
 }
+
+// helper function returns a context with the token used to
+// authenticate with the source code management system. If an
+// app installation is configured, the installation token with
+// write access to the named permission is used, otherwise the
+// user token is used.
+func (s *service) token(ctx context.Context, user *core.User, req *core.StatusInput, permission string) (context.Context, error) {
+	if s.installations != nil {
+		token, err := s.installations.Token(ctx, req.Repo, map[string]string{permission: "write"})
+		if err != nil {
+			return ctx, err
+		}
+		return context.WithValue(ctx, scm.TokenKey{}, &scm.Token{
+			Token:   token.Token,
+			Expires: time.Unix(token.Expires, 0),
+		}), nil
+	}
+
+	err := s.renew.Renew(ctx, user, false)
+	if err != nil {
+		return ctx, err
+	}
+	return context.WithValue(ctx, scm.TokenKey{}, &scm.Token{
+		Token:   user.Token,
+		Refresh: user.Refresh,
+	}), nil
+}
@@ -40,7 +40,7 @@ func TestStatus(t *testing.T) {
 	client := new(scm.Client)
 	client.Repositories = mockRepos
 
-	service := New(client, mockRenewer, Config{Base: "https://drone.company.com"})
+	service := New(client, mockRenewer, nil, Config{Base: "https://drone.company.com"})
 	err := service.Send(noContext, mockUser, &core.StatusInput{
 		Repo: &core.Repository{Slug: "octocat/hello-world"},
 		Build: &core.Build{
@@ -77,7 +77,7 @@ func TestStatus_ErrNotSupported(t *testing.T) {
 	client := new(scm.Client)
 	client.Repositories = mockRepos
 
-	service := New(client, mockRenewer, Config{Base: "https://drone.company.com"})
+	service := New(client, mockRenewer, nil, Config{Base: "https://drone.company.com"})
 	err := service.Send(noContext, mockUser, &core.StatusInput{
 		Repo: &core.Repository{Slug: "octocat/hello-world"},
 		Build: &core.Build{
@@ -101,7 +101,7 @@ func TestStatus_RenewalError(t *testing.T) {
 	mockRenewer := mock.NewMockRenewer(controller)
 	mockRenewer.EXPECT().Renew(gomock.Any(), mockUser, false).Return(scm.ErrNotAuthorized)
 
-	service := New(nil, mockRenewer, Config{Base: "https://drone.company.com"})
+	service := New(nil, mockRenewer, nil, Config{Base: "https://drone.company.com"})
 	err := service.Send(noContext, mockUser, nil)
 	if err == nil {
 		t.Errorf("Expect error refreshing token")
@@ -109,9 +109,159 @@ func TestStatus_RenewalError(t *testing.T) {
 }
 
 func TestStatus_Disabled(t *testing.T) {
-	service := New(nil, nil, Config{Disabled: true})
+	service := New(nil, nil, nil, Config{Disabled: true})
 	err := service.Send(noContext, nil, nil)
 	if err != nil {
 		t.Error(err)
 	}
 }
+
+func TestStatus_IgnoreStage(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockUser := &core.User{}
+
+	mockRenewer := mock.NewMockRenewer(controller)
+	mockRenewer.EXPECT().Renew(gomock.Any(), mockUser, false).Return(nil)
+
+	service := New(nil, mockRenewer, nil, Config{Base: "https://drone.company.com"})
+	err := service.Send(noContext, mockUser, &core.StatusInput{
+		Repo:  &core.Repository{Slug: "octocat/hello-world"},
+		Build: &core.Build{Number: 1, Status: core.StatusRunning},
+		Stage: &core.Stage{Number: 1, Status: core.StatusRunning},
+	})
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func TestStatus_Stage(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockUser := &core.User{}
+
+	mockRenewer := mock.NewMockRenewer(controller)
+	mockRenewer.EXPECT().Renew(gomock.Any(), mockUser, false).Return(nil)
+
+	statusInput := &scm.StatusInput{
+		State:  scm.StateFailure,
+		Label:  "continuous-integration/drone/push/backend",
+		Desc:   "Stage is failing",
+		Target: "https://drone.company.com/octocat/hello-world/1/2",
+	}
+
+	mockRepos := mockscm.NewMockRepositoryService(controller)
+	mockRepos.EXPECT().CreateStatus(gomock.Any(), "octocat/hello-world", "a6586b3db244fb6b1198f2b25c213ded5b44f9fa", statusInput).Return(nil, nil, nil)
+
+	client := new(scm.Client)
+	client.Repositories = mockRepos
+
+	service := New(client, mockRenewer, nil, Config{Base: "https://drone.company.com", Stages: true})
+	err := service.Send(noContext, mockUser, &core.StatusInput{
+		Repo: &core.Repository{Slug: "octocat/hello-world"},
+		Build: &core.Build{
+			Number: 1,
+			Event:  core.EventPush,
+			Status: core.StatusRunning,
+			After:  "a6586b3db244fb6b1198f2b25c213ded5b44f9fa",
+		},
+		Stage: &core.Stage{
+			Number: 2,
+			Name:   "backend",
+			Status: core.StatusFailing,
+		},
+	})
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func TestStatus_Deployment(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockUser := &core.User{}
+
+	mockRenewer := mock.NewMockRenewer(controller)
+	mockRenewer.EXPECT().Renew(gomock.Any(), mockUser, false).Return(nil)
+
+	statusInput := &scm.StatusInput{
+		State:  scm.StateSuccess,
+		Label:  "continuous-integration/drone/promote/production",
+		Desc:   "Build is passing",
+		Target: "https://drone.company.com/octocat/hello-world/2",
+	}
+
+	mockRepos := mockscm.NewMockRepositoryService(controller)
+	mockRepos.EXPECT().CreateStatus(gomock.Any(), "octocat/hello-world", "a6586b3db244fb6b1198f2b25c213ded5b44f9fa", statusInput).Return(nil, nil, nil)
+
+	client := &scm.Client{Driver: scm.DriverGitea}
+	client.Repositories = mockRepos
+
+	service := New(client, mockRenewer, nil, Config{Base: "https://drone.company.com"})
+	err := service.Send(noContext, mockUser, &core.StatusInput{
+		Repo: &core.Repository{Slug: "octocat/hello-world"},
+		Build: &core.Build{
+			Number: 2,
+			Event:  core.EventPromote,
+			Deploy: "production",
+			Status: core.StatusPassing,
+			After:  "a6586b3db244fb6b1198f2b25c213ded5b44f9fa",
+		},
+	})
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func TestStatus_Installation(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockUser := &core.User{}
+	mockRepo := &core.Repository{Slug: "octocat/hello-world"}
+	mockToken := &core.InstallationToken{Token: "ghs_16C7e42F", Expires: 1557932400}
+
+	// the user token must not be renewed when statuses are
+	// sent using the app installation token.
+	mockRenewer := mock.NewMockRenewer(controller)
+
+	mockInstallations := mock.NewMockInstallationService(controller)
+	mockInstallations.EXPECT().Token(gomock.Any(), mockRepo, map[string]string{"statuses": "write"}).Return(mockToken, nil)
+
+	statusInput := &scm.StatusInput{
+		State:  scm.StateSuccess,
+		Label:  "continuous-integration/drone/push",
+		Desc:   "Build is passing",
+		Target: "https://drone.company.com/octocat/hello-world/1",
+	}
+
+	mockRepos := mockscm.NewMockRepositoryService(controller)
+	mockRepos.EXPECT().CreateStatus(gomock.Any(), "octocat/hello-world", "a6586b3db244fb6b1198f2b25c213ded5b44f9fa", statusInput).
+		Do(func(ctx context.Context, repo, ref string, in *scm.StatusInput) {
+			token := ctx.Value(scm.TokenKey{}).(*scm.Token)
+			if got, want := token.Token, mockToken.Token; got != want {
+				t.Errorf("Want installation token %q, got %q", want, got)
+			}
+		}).
+		Return(nil, nil, nil)
+
+	client := new(scm.Client)
+	client.Repositories = mockRepos
+
+	service := New(client, mockRenewer, mockInstallations, Config{Base: "https://drone.company.com"})
+	err := service.Send(noContext, mockUser, &core.StatusInput{
+		Repo: mockRepo,
+		Build: &core.Build{
+			Number: 1,
+			Event:  core.EventPush,
+			Status: core.StatusPassing,
+			After:  "a6586b3db244fb6b1198f2b25c213ded5b44f9fa",
+		},
+	})
+	if err != nil {
+		t.Error(err)
+	}
+}
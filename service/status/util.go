@@ -37,6 +37,28 @@ func createLabel(name, event string) string {
 	}
 }
 
+// helper function creates the status label for the build.
+// Gitea and Gogs do not support deployment statuses, so
+// deployments are reported as commit statuses labeled with
+// the event and target environment, so that each environment
+// reports an independent status. The labels used by other
+// providers are unchanged, since branch protection rules are
+// keyed on the label.
+func createBuildLabel(driver scm.Driver, name string, build *core.Build) string {
+	label := createLabel(name, build.Event)
+	if driver != scm.DriverGitea && driver != scm.DriverGogs {
+		return label
+	}
+	switch build.Event {
+	case core.EventPromote, core.EventRollback:
+		label = fmt.Sprintf("%s/%s", label, build.Event)
+		if build.Deploy != "" {
+			label = fmt.Sprintf("%s/%s", label, build.Deploy)
+		}
+	}
+	return label
+}
+
 func createDesc(state string) string {
 	switch state {
 	case core.StatusBlocked:
@@ -80,6 +102,8 @@ func convertStatus(state string) scm.State {
 		return scm.StateSuccess
 	case core.StatusPending:
 		return scm.StatePending
+	case core.StatusWaiting:
+		return scm.StatePending
 	case core.StatusRunning:
 		return scm.StatePending
 	case core.StatusSkipped:
@@ -88,3 +112,63 @@ func convertStatus(state string) scm.State {
 		return scm.StateUnknown
 	}
 }
+
+func createStageDesc(state string) string {
+	switch state {
+	case core.StatusBlocked:
+		return "Stage is pending approval"
+	case core.StatusDeclined:
+		return "Stage was declined"
+	case core.StatusError:
+		return "Stage encountered an error"
+	case core.StatusFailing:
+		return "Stage is failing"
+	case core.StatusKilled:
+		return "Stage was killed"
+	case core.StatusPassing:
+		return "Stage is passing"
+	case core.StatusWaiting:
+		return "Stage is pending"
+	case core.StatusPending:
+		return "Stage is pending"
+	case core.StatusRunning:
+		return "Stage is running"
+	case core.StatusSkipped:
+		return "Stage was skipped"
+	default:
+		return "Stage is in an unknown state"
+	}
+}
+
+func convertCheckStatus(state string) string {
+	switch state {
+	case core.StatusBlocked,
+		core.StatusWaiting,
+		core.StatusPending:
+		return "queued"
+	case core.StatusRunning:
+		return "in_progress"
+	default:
+		return "completed"
+	}
+}
+
+func convertConclusion(state string) string {
+	switch state {
+	case core.StatusBlocked,
+		core.StatusWaiting,
+		core.StatusPending,
+		core.StatusRunning:
+		return ""
+	case core.StatusPassing:
+		return "success"
+	case core.StatusFailing,
+		core.StatusError:
+		return "failure"
+	case core.StatusKilled,
+		core.StatusDeclined:
+		return "cancelled"
+	default:
+		return "neutral"
+	}
+}
@@ -33,6 +33,10 @@ func TestCreateLabel(t *testing.T) {
 			event: "unknown",
 			label: "continuous-integration/drone",
 		},
+		{
+			event: core.EventPromote,
+			label: "continuous-integration/drone",
+		},
 		{
 			name:  "drone",
 			event: core.EventPush,
@@ -46,6 +50,51 @@ func TestCreateLabel(t *testing.T) {
 	}
 }
 
+func TestCreateBuildLabel(t *testing.T) {
+	tests := []struct {
+		driver scm.Driver
+		build  *core.Build
+		label  string
+	}{
+		{
+			driver: scm.DriverGitea,
+			build:  &core.Build{Event: core.EventPush, Deploy: "production"},
+			label:  "continuous-integration/drone/push",
+		},
+		{
+			driver: scm.DriverGitea,
+			build:  &core.Build{Event: core.EventPromote, Deploy: "production"},
+			label:  "continuous-integration/drone/promote/production",
+		},
+		{
+			driver: scm.DriverGogs,
+			build:  &core.Build{Event: core.EventRollback, Deploy: "staging"},
+			label:  "continuous-integration/drone/rollback/staging",
+		},
+		{
+			driver: scm.DriverGitea,
+			build:  &core.Build{Event: core.EventPromote},
+			label:  "continuous-integration/drone/promote",
+		},
+		// the labels used by other providers are unchanged.
+		{
+			driver: scm.DriverGithub,
+			build:  &core.Build{Event: core.EventPromote, Deploy: "production"},
+			label:  "continuous-integration/drone",
+		},
+		{
+			driver: scm.DriverGitlab,
+			build:  &core.Build{Event: core.EventRollback, Deploy: "staging"},
+			label:  "continuous-integration/drone",
+		},
+	}
+	for _, test := range tests {
+		if got, want := createBuildLabel(test.driver, "", test.build), test.label; got != want {
+			t.Errorf("Want label %q, got %q", want, got)
+		}
+	}
+}
+
 func TestCreateDesc(t *testing.T) {
 	tests := []struct {
 		status string
@@ -138,6 +187,10 @@ func TestConvertStatus(t *testing.T) {
 			from: core.StatusPending,
 			to:   scm.StatePending,
 		},
+		{
+			from: core.StatusWaiting,
+			to:   scm.StatePending,
+		},
 		{
 			from: core.StatusRunning,
 			to:   scm.StatePending,
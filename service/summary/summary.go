@@ -0,0 +1,231 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package summary
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/go-scm/scm"
+)
+
+// maximum number of log lines included in the summary.
+const maxLines = 100
+
+// marker identifies the build summary comment, and is used
+// to find and replace the comment for subsequent builds.
+const marker = "<!-- drone:summary -->"
+
+// Config configures the Summary service.
+type Config struct {
+	Base string
+}
+
+// New returns a new SummaryService.
+func New(client *scm.Client, renew core.Renewer, logs core.LogStore, config Config) core.SummaryService {
+	return &service{
+		client: client,
+		renew:  renew,
+		logs:   logs,
+		base:   config.Base,
+	}
+}
+
+type service struct {
+	client *scm.Client
+	renew  core.Renewer
+	logs   core.LogStore
+	base   string
+}
+
+func (s *service) Send(ctx context.Context, user *core.User, args *core.NotifyArgs) error {
+	repo, build := args.Repo, args.Build
+	if !repo.Summary || repo.Provider != "" || build.Event != core.EventPullRequest {
+		return nil
+	}
+	number := pullRequestNumber(build.Ref)
+	if number == 0 {
+		return nil
+	}
+
+	err := s.renew.Renew(ctx, user, false)
+	if err != nil {
+		return err
+	}
+	ctx = context.WithValue(ctx, scm.TokenKey{}, &scm.Token{
+		Token:   user.Token,
+		Refresh: user.Refresh,
+	})
+
+	// the source code management client cannot edit existing
+	// comments, so the summary is updated by deleting the
+	// previous summary comment and creating a new comment.
+	// The summary is removed once the pull request passes.
+	err = s.delete(ctx, repo.Slug, number)
+	if err == scm.ErrNotSupported {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if !isFailed(build.Status) {
+		return nil
+	}
+
+	in := &scm.CommentInput{
+		Body: s.createBody(ctx, args),
+	}
+	_, _, err = s.client.PullRequests.CreateComment(ctx, repo.Slug, number, in)
+	if err == scm.ErrNotSupported {
+		return nil
+	}
+	return err
+}
+
+// delete deletes the previous summary comments from the
+// pull request.
+func (s *service) delete(ctx context.Context, slug string, number int) error {
+	var ids []int
+	opts := scm.ListOptions{Size: 100}
+	for {
+		comments, res, err := s.client.PullRequests.ListComments(ctx, slug, number, opts)
+		if err != nil {
+			return err
+		}
+		for _, comment := range comments {
+			if strings.HasPrefix(comment.Body, marker) {
+				ids = append(ids, comment.ID)
+			}
+		}
+		if res == nil || res.Page.Next == 0 {
+			break
+		}
+		opts.Page = res.Page.Next
+	}
+	for _, id := range ids {
+		_, err := s.client.PullRequests.DeleteComment(ctx, slug, number, id)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// createBody returns the markdown comment body summarizing
+// the failed stages and steps, including the last lines of
+// the first failed step.
+func (s *service) createBody(ctx context.Context, args *core.NotifyArgs) string {
+	link := fmt.Sprintf("%s/%s/%d", s.base, args.Repo.Slug, args.Build.Number)
+
+	buf := new(bytes.Buffer)
+	fmt.Fprintln(buf, marker)
+	fmt.Fprintf(buf, "Build [#%d](%s) failed.\n\n", args.Build.Number, link)
+	fmt.Fprintln(buf, "| Stage | Step | Status |")
+	fmt.Fprintln(buf, "| --- | --- | --- |")
+
+	var failed *core.Step
+	for _, stage := range args.Stages {
+		if !isFailed(stage.Status) {
+			continue
+		}
+		var found bool
+		for _, step := range stage.Steps {
+			if !isFailed(step.Status) {
+				continue
+			}
+			if failed == nil {
+				failed = step
+			}
+			found = true
+			fmt.Fprintf(buf, "| %s | [%s](%s/%d/%d) | %s |\n",
+				stage.Name,
+				step.Name,
+				link,
+				stage.Number,
+				step.Number,
+				step.Status,
+			)
+		}
+		if !found {
+			fmt.Fprintf(buf, "| [%s](%s/%d) | | %s |\n",
+				stage.Name,
+				link,
+				stage.Number,
+				stage.Status,
+			)
+		}
+	}
+
+	if failed == nil || args.Repo.SummaryLines <= 0 {
+		return buf.String()
+	}
+	lines := s.excerpt(ctx, failed, args.Repo.SummaryLines)
+	if len(lines) == 0 {
+		return buf.String()
+	}
+	fmt.Fprintf(buf, "\n<details><summary>Last %d lines of %s</summary>\n\n", len(lines), failed.Name)
+	fmt.Fprintln(buf, "```")
+	for _, line := range lines {
+		fmt.Fprintln(buf, strings.TrimRight(line.Message, "\r\n"))
+	}
+	fmt.Fprintln(buf, "```")
+	fmt.Fprintln(buf, "</details>")
+	return buf.String()
+}
+
+// excerpt returns the last lines of the step logs.
+func (s *service) excerpt(ctx context.Context, step *core.Step, limit int64) []*core.Line {
+	if limit > maxLines {
+		limit = maxLines
+	}
+	rc, err := s.logs.Find(ctx, step.ID)
+	if err != nil {
+		return nil
+	}
+	defer rc.Close()
+	var lines []*core.Line
+	json.NewDecoder(rc).Decode(&lines)
+	if int64(len(lines)) > limit {
+		lines = lines[int64(len(lines))-limit:]
+	}
+	return lines
+}
+
+// helper function returns true if the status is a failure.
+func isFailed(status string) bool {
+	switch status {
+	case core.StatusFailing, core.StatusError:
+		return true
+	default:
+		return false
+	}
+}
+
+// helper function extracts the pull request number from the
+// pull request reference (e.g. refs/pull/42/head).
+func pullRequestNumber(ref string) int {
+	parts := strings.Split(ref, "/")
+	if len(parts) != 4 || parts[0] != "refs" {
+		return 0
+	}
+	number, _ := strconv.Atoi(parts[2])
+	return number
+}
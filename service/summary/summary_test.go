@@ -0,0 +1,203 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package summary
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"testing"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/mock"
+	"github.com/drone/drone/mock/mockscm"
+	"github.com/drone/go-scm/scm"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+)
+
+var noContext = context.Background()
+
+var (
+	mockUser = &core.User{
+		Login: "octocat",
+		Token: "755bb80e5b",
+	}
+
+	mockRepo = &core.Repository{
+		Slug:         "octocat/hello-world",
+		Summary:      true,
+		SummaryLines: 2,
+	}
+
+	mockBuild = &core.Build{
+		Number: 1,
+		Event:  core.EventPullRequest,
+		Status: core.StatusFailing,
+		Ref:    "refs/pull/42/head",
+	}
+
+	mockStages = []*core.Stage{
+		{
+			Number: 1,
+			Name:   "default",
+			Status: core.StatusFailing,
+			Steps: []*core.Step{
+				{ID: 1, Number: 1, Name: "clone", Status: core.StatusPassing},
+				{ID: 2, Number: 2, Name: "test", Status: core.StatusFailing},
+			},
+		},
+		{
+			Number: 2,
+			Name:   "docs",
+			Status: core.StatusPassing,
+		},
+		{
+			Number: 3,
+			Name:   "deploy",
+			Status: core.StatusError,
+		},
+	}
+
+	mockLogs = `[{"pos":0,"out":"go test ./...\n"},{"pos":1,"out":"--- FAIL: TestFoo\n"},{"pos":2,"out":"FAIL\n"}]`
+)
+
+func TestSend(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	renewer := mock.NewMockRenewer(controller)
+	renewer.EXPECT().Renew(gomock.Any(), mockUser, false).Return(nil)
+
+	logs := mock.NewMockLogStore(controller)
+	logs.EXPECT().Find(gomock.Any(), int64(2)).Return(ioutil.NopCloser(bytes.NewBufferString(mockLogs)), nil)
+
+	comments := []*scm.Comment{
+		{ID: 1, Body: "lgtm"},
+		{ID: 2, Body: marker + "\nBuild #0 failed."},
+	}
+
+	want := &scm.CommentInput{
+		Body: marker + "\n" +
+			"Build [#1](https://drone.company.com/octocat/hello-world/1) failed.\n\n" +
+			"| Stage | Step | Status |\n" +
+			"| --- | --- | --- |\n" +
+			"| default | [test](https://drone.company.com/octocat/hello-world/1/1/2) | failure |\n" +
+			"| [deploy](https://drone.company.com/octocat/hello-world/1/3) | | error |\n" +
+			"\n<details><summary>Last 2 lines of test</summary>\n\n" +
+			"```\n--- FAIL: TestFoo\nFAIL\n```\n" +
+			"</details>\n",
+	}
+
+	pulls := mockscm.NewMockPullRequestService(controller)
+	pulls.EXPECT().ListComments(gomock.Any(), "octocat/hello-world", 42, gomock.Any()).Return(comments, &scm.Response{}, nil)
+	pulls.EXPECT().DeleteComment(gomock.Any(), "octocat/hello-world", 42, 2).Return(nil, nil)
+	pulls.EXPECT().CreateComment(gomock.Any(), "octocat/hello-world", 42, gomock.Any()).Do(func(_ context.Context, _ string, _ int, got *scm.CommentInput) {
+		if diff := cmp.Diff(got, want); diff != "" {
+			t.Errorf(diff)
+		}
+	}).Return(nil, nil, nil)
+
+	client := new(scm.Client)
+	client.PullRequests = pulls
+
+	service := New(client, renewer, logs, Config{Base: "https://drone.company.com"})
+	err := service.Send(noContext, mockUser, &core.NotifyArgs{
+		Repo:   mockRepo,
+		Build:  mockBuild,
+		Stages: mockStages,
+	})
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+// this test verifies that the previous summary comment is
+// removed, and no comment is created, when the build passes.
+func TestSend_Passing(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	renewer := mock.NewMockRenewer(controller)
+	renewer.EXPECT().Renew(gomock.Any(), mockUser, false).Return(nil)
+
+	comments := []*scm.Comment{
+		{ID: 2, Body: marker + "\nBuild #0 failed."},
+	}
+
+	pulls := mockscm.NewMockPullRequestService(controller)
+	pulls.EXPECT().ListComments(gomock.Any(), "octocat/hello-world", 42, gomock.Any()).Return(comments, &scm.Response{}, nil)
+	pulls.EXPECT().DeleteComment(gomock.Any(), "octocat/hello-world", 42, 2).Return(nil, nil)
+
+	client := new(scm.Client)
+	client.PullRequests = pulls
+
+	build := new(core.Build)
+	*build = *mockBuild
+	build.Status = core.StatusPassing
+
+	service := New(client, renewer, nil, Config{Base: "https://drone.company.com"})
+	err := service.Send(noContext, mockUser, &core.NotifyArgs{
+		Repo:   mockRepo,
+		Build:  build,
+		Stages: mockStages,
+	})
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+// this test verifies that no comment is created when the
+// repository summary is disabled.
+func TestSend_Disabled(t *testing.T) {
+	service := New(nil, nil, nil, Config{})
+	err := service.Send(noContext, mockUser, &core.NotifyArgs{
+		Repo:  &core.Repository{Slug: "octocat/hello-world"},
+		Build: mockBuild,
+	})
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+// this test verifies that no comment is created for builds
+// that are not pull request builds.
+func TestSend_Push(t *testing.T) {
+	service := New(nil, nil, nil, Config{})
+	err := service.Send(noContext, mockUser, &core.NotifyArgs{
+		Repo:  mockRepo,
+		Build: &core.Build{Event: core.EventPush, Ref: "refs/heads/master"},
+	})
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+// this test verifies that the summary is skipped if the
+// source code management system does not support comments.
+func TestSend_ErrNotSupported(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	renewer := mock.NewMockRenewer(controller)
+	renewer.EXPECT().Renew(gomock.Any(), mockUser, false).Return(nil)
+
+	pulls := mockscm.NewMockPullRequestService(controller)
+	pulls.EXPECT().ListComments(gomock.Any(), "octocat/hello-world", 42, gomock.Any()).Return(nil, nil, scm.ErrNotSupported)
+
+	client := new(scm.Client)
+	client.PullRequests = pulls
+
+	service := New(client, renewer, nil, Config{})
+	err := service.Send(noContext, mockUser, &core.NotifyArgs{
+		Repo:   mockRepo,
+		Build:  mockBuild,
+		Stages: mockStages,
+	})
+	if err != nil {
+		t.Error(err)
+	}
+}
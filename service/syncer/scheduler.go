@@ -0,0 +1,122 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syncer
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/drone/drone/core"
+
+	"github.com/sirupsen/logrus"
+)
+
+// NewScheduler returns a new Scheduler that synchronizes the
+// user repository lists in the background. A user account is
+// synchronized at most once per period, and at most limit
+// accounts are synchronized per interval, to avoid exceeding
+// the source code management system rate limits.
+func NewScheduler(
+	syncer core.Syncer,
+	users core.UserStore,
+	period time.Duration,
+	limit int,
+) *Scheduler {
+	return &Scheduler{
+		syncer: syncer,
+		users:  users,
+		period: period,
+		limit:  limit,
+	}
+}
+
+// Scheduler defines a background repository sync scheduler.
+type Scheduler struct {
+	syncer core.Syncer
+	users  core.UserStore
+	period time.Duration
+	limit  int
+}
+
+// Start starts the sync scheduler.
+func (s *Scheduler) Start(ctx context.Context, dur time.Duration) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(dur):
+			s.run(ctx)
+		}
+	}
+}
+
+func (s *Scheduler) run(ctx context.Context) error {
+	logrus.Debugln("syncer: begin scheduled repository sync")
+
+	defer func() {
+		if err := recover(); err != nil {
+			logger := logrus.WithField("error", err)
+			logger.Errorln("syncer: unexpected panic")
+		}
+	}()
+
+	users, err := s.users.List(ctx)
+	if err != nil {
+		logger := logrus.WithError(err)
+		logger.Errorln("syncer: cannot list users")
+		return err
+	}
+
+	for _, user := range s.pending(users, time.Now()) {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		logger := logrus.WithField("login", user.Login)
+		_, err := s.syncer.Sync(ctx, user)
+		if err != nil {
+			logger.WithError(err).
+				Warnln("syncer: cannot synchronize user")
+		}
+	}
+
+	logrus.Debugln("syncer: finished scheduled repository sync")
+	return nil
+}
+
+// helper function returns the user accounts that are due to
+// be synchronized, ordered from the least recently synchronized
+// account, and capped at the per-interval limit.
+func (s *Scheduler) pending(users []*core.User, now time.Time) []*core.User {
+	var due []*core.User
+	for _, user := range users {
+		switch {
+		case user.Machine, !user.Active, user.Syncing:
+			continue
+		case user.Token == "":
+			continue
+		case time.Unix(user.Synced, 0).Add(s.period).After(now):
+			continue
+		}
+		due = append(due, user)
+	}
+	sort.SliceStable(due, func(i, j int) bool {
+		return due[i].Synced < due[j].Synced
+	})
+	if s.limit > 0 && len(due) > s.limit {
+		due = due[:s.limit]
+	}
+	return due
+}
@@ -0,0 +1,89 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package syncer
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/mock"
+
+	"github.com/golang/mock/gomock"
+)
+
+func TestSchedulerRun(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	stale := time.Now().Add(-48 * time.Hour).Unix()
+	users := []*core.User{
+		{ID: 1, Login: "octocat", Active: true, Token: "3da541559", Synced: stale},
+		{ID: 2, Login: "spaceghost", Active: true, Token: "8a4f4a3c2", Synced: time.Now().Unix()},
+	}
+
+	userStore := mock.NewMockUserStore(controller)
+	userStore.EXPECT().List(gomock.Any()).Return(users, nil)
+
+	syncer := mock.NewMockSyncer(controller)
+	syncer.EXPECT().Sync(gomock.Any(), users[0]).Return(&core.Batch{}, nil)
+
+	s := NewScheduler(syncer, userStore, 24*time.Hour, 10)
+	if err := s.run(noContext); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestSchedulerRun_SyncError(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	users := []*core.User{
+		{ID: 1, Login: "octocat", Active: true, Token: "3da541559"},
+		{ID: 2, Login: "spaceghost", Active: true, Token: "8a4f4a3c2"},
+	}
+
+	userStore := mock.NewMockUserStore(controller)
+	userStore.EXPECT().List(gomock.Any()).Return(users, nil)
+
+	// a failure to synchronize one account must not prevent
+	// the remaining accounts from being synchronized.
+	syncer := mock.NewMockSyncer(controller)
+	syncer.EXPECT().Sync(gomock.Any(), users[0]).Return(nil, errors.New("rate limit exceeded"))
+	syncer.EXPECT().Sync(gomock.Any(), users[1]).Return(&core.Batch{}, nil)
+
+	s := NewScheduler(syncer, userStore, 24*time.Hour, 10)
+	if err := s.run(noContext); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestSchedulerPending(t *testing.T) {
+	now := time.Now()
+	users := []*core.User{
+		{ID: 1, Active: true, Token: "a", Synced: now.Add(-time.Hour).Unix()},
+		{ID: 2, Active: true, Token: "b", Synced: now.Add(-72 * time.Hour).Unix()},
+		{ID: 3, Active: true, Token: "c", Synced: now.Add(-48 * time.Hour).Unix()},
+		{ID: 4, Active: true, Token: "d", Synced: 0},
+		{ID: 5, Active: true, Token: "e", Synced: 0, Machine: true},
+		{ID: 6, Active: false, Token: "f", Synced: 0},
+		{ID: 7, Active: true, Token: "g", Synced: 0, Syncing: true},
+		{ID: 8, Active: true, Token: "", Synced: 0},
+	}
+
+	s := NewScheduler(nil, nil, 24*time.Hour, 2)
+	due := s.pending(users, now)
+	if got, want := len(due), 2; got != want {
+		t.Errorf("Want %d users due for sync, got %d", want, got)
+		return
+	}
+	if got, want := due[0].ID, int64(4); got != want {
+		t.Errorf("Want user %d synchronized first, got %d", want, got)
+	}
+	if got, want := due[1].ID, int64(2); got != want {
+		t.Errorf("Want user %d synchronized second, got %d", want, got)
+	}
+}
@@ -122,6 +122,11 @@ func (s *Synchronizer) Sync(ctx context.Context, user *core.User) (*core.Batch,
 		}
 
 		for _, repo := range repos {
+			// repositories hosted by an additional provider
+			// are not synchronized with the default provider.
+			if repo.Provider != "" {
+				continue
+			}
 			local[repo.UID] = repo
 		}
 	}
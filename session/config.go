@@ -20,12 +20,14 @@ import "time"
 type Config struct {
 	Secret  string
 	Timeout time.Duration
+	Idle    time.Duration
 }
 
 // NewConfig returns a new session configuration.
-func NewConfig(secret string, timeout time.Duration) Config {
+func NewConfig(secret string, timeout, idle time.Duration) Config {
 	return Config{
 		Secret:  secret,
 		Timeout: timeout,
+		Idle:    idle,
 	}
 }
@@ -15,6 +15,8 @@
 package session
 
 import (
+	"errors"
+	"net"
 	"net/http"
 	"strings"
 	"time"
@@ -22,35 +24,77 @@ import (
 	"github.com/drone/drone/core"
 
 	"github.com/dchest/authcookie"
+	"github.com/dchest/uniuri"
 )
 
-// New returns a new cookie-based session management.
-func New(users core.UserStore, config Config) core.Session {
+var (
+	errTokenExpired   = errors.New("Token is expired")
+	errSessionExpired = errors.New("Session is expired")
+)
+
+// touchInterval defines the minimum interval between updates
+// to the session last accessed time.
+const touchInterval = time.Minute
+
+// New returns a new cookie-based session management. If the
+// session store is nil, the session cookie is bound to the user
+// login and cannot be revoked.
+func New(users core.UserStore, tokens core.TokenStore, sessions core.UserSessionStore, config Config) core.Session {
 	return &session{
-		secret:  []byte(config.Secret),
-		timeout: config.Timeout,
-		users:   users,
+		secret:   []byte(config.Secret),
+		timeout:  config.Timeout,
+		idle:     config.Idle,
+		users:    users,
+		tokens:   tokens,
+		sessions: sessions,
 	}
 }
 
 type session struct {
-	users   core.UserStore
-	secret  []byte
-	timeout time.Duration
+	users    core.UserStore
+	tokens   core.TokenStore
+	sessions core.UserSessionStore
+	secret   []byte
+	timeout  time.Duration
+	idle     time.Duration
 
 	administrator string // administrator account
 	prometheus    string // prometheus account
 	autoscaler    string // autoscaler account
 }
 
-func (s *session) Create(w http.ResponseWriter, user *core.User) error {
+func (s *session) Create(w http.ResponseWriter, r *http.Request, user *core.User) error {
+	value := user.Login
+	if s.sessions != nil {
+		ctx := r.Context()
+		now := time.Now()
+
+		// purge expired sessions to prevent the table from
+		// growing unbounded. This is considered a non-fatal
+		// error.
+		s.sessions.DeleteExpired(ctx, now.Unix())
+
+		record := &core.UserSession{
+			UserID:   user.ID,
+			Hash:     uniuri.NewLen(32),
+			Address:  remoteAddr(r),
+			Agent:    r.UserAgent(),
+			Created:  now.Unix(),
+			Accessed: now.Unix(),
+			Expires:  now.Add(s.timeout).Unix(),
+		}
+		if err := s.sessions.Create(ctx, record); err != nil {
+			return err
+		}
+		value = record.Hash
+	}
 	cookie := &http.Cookie{
 		Name:     "_session_",
 		Path:     "/",
 		MaxAge:   2147483647,
 		HttpOnly: true,
 		Value: authcookie.NewSinceNow(
-			user.Login,
+			value,
 			s.timeout,
 			s.secret,
 		),
@@ -84,13 +128,64 @@ func (s *session) fromSession(r *http.Request) (*core.User, error) {
 	if login == "" {
 		return nil, nil
 	}
-	return s.users.FindLogin(r.Context(), login)
+	if s.sessions == nil {
+		return s.users.FindLogin(r.Context(), login)
+	}
+	// the cookie holds the session hash, which is used to
+	// lookup the session record. A revoked session is removed
+	// from the datastore and no longer authenticates the user.
+	return s.fromRecord(r, login)
+}
+
+func (s *session) fromRecord(r *http.Request, hash string) (*core.User, error) {
+	ctx := r.Context()
+	record, err := s.sessions.FindHash(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().Unix()
+	if record.Expired() {
+		return nil, errSessionExpired
+	}
+	if s.idle > 0 && now-record.Accessed > int64(s.idle.Seconds()) {
+		return nil, errSessionExpired
+	}
+	user, err := s.users.Find(ctx, record.UserID)
+	if err != nil {
+		return nil, err
+	}
+	// the last accessed time is updated at most once per
+	// interval to limit writes to the datastore.
+	if now-record.Accessed >= int64(touchInterval.Seconds()) {
+		record.Accessed = now
+		s.sessions.Touch(ctx, record)
+	}
+	return user, nil
 }
 
 func (s *session) fromToken(r *http.Request) (*core.User, error) {
-	return s.users.FindToken(r.Context(),
-		extractToken(r),
-	)
+	hash := extractToken(r)
+	if s.tokens != nil {
+		token, err := s.tokens.FindHash(r.Context(), core.TokenDigest(hash))
+		if err == nil {
+			return s.fromAccessToken(r, token)
+		}
+	}
+	// fallback to the legacy user token, which grants
+	// unrestricted access to the user account.
+	return s.users.FindToken(r.Context(), hash)
+}
+
+func (s *session) fromAccessToken(r *http.Request, token *core.Token) (*core.User, error) {
+	if token.Expired() {
+		return nil, errTokenExpired
+	}
+	user, err := s.users.Find(r.Context(), token.UserID)
+	if err != nil {
+		return nil, err
+	}
+	user.Scopes = token.Scopes
+	return user, nil
 }
 
 func isAuthorizationToken(r *http.Request) bool {
@@ -101,6 +196,15 @@ func isAuthorizationParameter(r *http.Request) bool {
 	return r.FormValue("access_token") != ""
 }
 
+// helper function returns the client ip address.
+func remoteAddr(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
 func extractToken(r *http.Request) string {
 	bearer := r.Header.Get("Authorization")
 	if bearer == "" {
@@ -0,0 +1,129 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package annotation
+
+import (
+	"context"
+	"time"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/store/shared/db"
+)
+
+// New returns a new AnnotationStore.
+func New(db *db.DB) core.AnnotationStore {
+	return &annotationStore{db}
+}
+
+type annotationStore struct {
+	db *db.DB
+}
+
+func (s *annotationStore) List(ctx context.Context, id int64) ([]*core.Annotation, error) {
+	var out []*core.Annotation
+	err := s.db.View(func(queryer db.Queryer, binder db.Binder) error {
+		params := map[string]interface{}{"annotation_build_id": id}
+		stmt, args, err := binder.BindNamed(queryBuild, params)
+		if err != nil {
+			return err
+		}
+		rows, err := queryer.Query(stmt, args...)
+		if err != nil {
+			return err
+		}
+		out, err = scanRows(rows)
+		return err
+	})
+	return out, err
+}
+
+func (s *annotationStore) Create(ctx context.Context, annotation *core.Annotation) error {
+	if annotation.Created == 0 {
+		annotation.Created = time.Now().Unix()
+	}
+	if s.db.Driver() == db.Postgres {
+		return s.createPostgres(ctx, annotation)
+	}
+	return s.create(ctx, annotation)
+}
+
+func (s *annotationStore) create(ctx context.Context, annotation *core.Annotation) error {
+	return s.db.Lock(func(execer db.Execer, binder db.Binder) error {
+		params := toParams(annotation)
+		stmt, args, err := binder.BindNamed(stmtInsert, params)
+		if err != nil {
+			return err
+		}
+		res, err := execer.Exec(stmt, args...)
+		if err != nil {
+			return err
+		}
+		annotation.ID, err = res.LastInsertId()
+		return err
+	})
+}
+
+func (s *annotationStore) createPostgres(ctx context.Context, annotation *core.Annotation) error {
+	return s.db.Lock(func(execer db.Execer, binder db.Binder) error {
+		params := toParams(annotation)
+		stmt, args, err := binder.BindNamed(stmtInsertPg, params)
+		if err != nil {
+			return err
+		}
+		return execer.QueryRow(stmt, args...).Scan(&annotation.ID)
+	})
+}
+
+const queryBase = `
+SELECT
+ annotation_id
+,annotation_build_id
+,annotation_stage_id
+,annotation_step_id
+,annotation_kind
+,annotation_name
+,annotation_value
+,annotation_created
+`
+
+const queryBuild = queryBase + `
+FROM annotations
+WHERE annotation_build_id = :annotation_build_id
+ORDER BY annotation_step_id, annotation_id
+`
+
+const stmtInsert = `
+INSERT INTO annotations (
+ annotation_build_id
+,annotation_stage_id
+,annotation_step_id
+,annotation_kind
+,annotation_name
+,annotation_value
+,annotation_created
+) VALUES (
+ :annotation_build_id
+,:annotation_stage_id
+,:annotation_step_id
+,:annotation_kind
+,:annotation_name
+,:annotation_value
+,:annotation_created
+)
+`
+
+const stmtInsertPg = stmtInsert + `
+RETURNING annotation_id
+`
@@ -0,0 +1,95 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package annotation
+
+import (
+	"context"
+	"testing"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/store/build"
+	"github.com/drone/drone/store/repos"
+	"github.com/drone/drone/store/shared/db/dbtest"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+var noContext = context.TODO()
+
+func TestAnnotation(t *testing.T) {
+	conn, err := dbtest.Connect()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer func() {
+		dbtest.Reset(conn)
+		dbtest.Disconnect(conn)
+	}()
+
+	// seed with a dummy repository
+	arepo := &core.Repository{UID: "1", Slug: "octocat/hello-world"}
+	repos := repos.New(conn)
+	repos.Create(noContext, arepo)
+
+	// seed with a dummy build
+	abuild := &core.Build{Number: 1, RepoID: arepo.ID}
+	builds := build.New(conn)
+	builds.Create(noContext, abuild, []*core.Stage{{Number: 1}})
+
+	annotation := &core.Annotation{
+		BuildID: abuild.ID,
+		StageID: 1,
+		StepID:  1,
+		Kind:    core.AnnotationLink,
+		Name:    "preview",
+		Value:   "https://preview.company.com",
+	}
+
+	store := New(conn).(*annotationStore)
+	t.Run("Create", testAnnotationCreate(store, annotation))
+	t.Run("List", testAnnotationList(store, annotation))
+}
+
+func testAnnotationCreate(store *annotationStore, annotation *core.Annotation) func(t *testing.T) {
+	return func(t *testing.T) {
+		err := store.Create(noContext, annotation)
+		if err != nil {
+			t.Error(err)
+		}
+		if annotation.ID == 0 {
+			t.Errorf("Want annotation ID assigned, got %d", annotation.ID)
+		}
+		if annotation.Created == 0 {
+			t.Errorf("Want annotation created timestamp assigned")
+		}
+	}
+}
+
+func testAnnotationList(store *annotationStore, annotation *core.Annotation) func(t *testing.T) {
+	return func(t *testing.T) {
+		list, err := store.List(noContext, annotation.BuildID)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if got, want := len(list), 1; got != want {
+			t.Errorf("Want %d annotations, got %d", want, got)
+			return
+		}
+		if diff := cmp.Diff(list[0], annotation); diff != "" {
+			t.Errorf(diff)
+		}
+
+		list, err = store.List(noContext, annotation.BuildID+1)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if got, want := len(list), 0; got != want {
+			t.Errorf("Want %d annotations for other builds, got %d", want, got)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package annotation
+
+import (
+	"database/sql"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/store/shared/db"
+)
+
+// helper function converts the Annotation structure to a set
+// of named query parameters.
+func toParams(annotation *core.Annotation) map[string]interface{} {
+	return map[string]interface{}{
+		"annotation_id":       annotation.ID,
+		"annotation_build_id": annotation.BuildID,
+		"annotation_stage_id": annotation.StageID,
+		"annotation_step_id":  annotation.StepID,
+		"annotation_kind":     annotation.Kind,
+		"annotation_name":     annotation.Name,
+		"annotation_value":    annotation.Value,
+		"annotation_created":  annotation.Created,
+	}
+}
+
+// helper function scans the sql.Row and copies the column
+// values to the destination object.
+func scanRow(scanner db.Scanner, dest *core.Annotation) error {
+	return scanner.Scan(
+		&dest.ID,
+		&dest.BuildID,
+		&dest.StageID,
+		&dest.StepID,
+		&dest.Kind,
+		&dest.Name,
+		&dest.Value,
+		&dest.Created,
+	)
+}
+
+// helper function scans the sql.Row and copies the column
+// values to the destination object.
+func scanRows(rows *sql.Rows) ([]*core.Annotation, error) {
+	defer rows.Close()
+
+	list := []*core.Annotation{}
+	for rows.Next() {
+		annotation := new(core.Annotation)
+		err := scanRow(rows, annotation)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, annotation)
+	}
+	return list, nil
+}
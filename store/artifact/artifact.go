@@ -0,0 +1,196 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package artifact
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"time"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/store/shared/db"
+)
+
+// New returns a new ArtifactStore.
+func New(db *db.DB) core.ArtifactStore {
+	return &artifactStore{db}
+}
+
+type artifactStore struct {
+	db *db.DB
+}
+
+func (s *artifactStore) List(ctx context.Context, id int64) ([]*core.Artifact, error) {
+	var out []*core.Artifact
+	err := s.db.View(func(queryer db.Queryer, binder db.Binder) error {
+		params := map[string]interface{}{"artifact_build_id": id}
+		stmt, args, err := binder.BindNamed(queryBuild, params)
+		if err != nil {
+			return err
+		}
+		rows, err := queryer.Query(stmt, args...)
+		if err != nil {
+			return err
+		}
+		out, err = scanRows(rows)
+		return err
+	})
+	return out, err
+}
+
+func (s *artifactStore) Find(ctx context.Context, id int64) (*core.Artifact, error) {
+	out := &core.Artifact{ID: id}
+	err := s.db.View(func(queryer db.Queryer, binder db.Binder) error {
+		params := toParams(out)
+		query, args, err := binder.BindNamed(queryKey, params)
+		if err != nil {
+			return err
+		}
+		row := queryer.QueryRow(query, args...)
+		return scanRow(row, out)
+	})
+	return out, err
+}
+
+func (s *artifactStore) Open(ctx context.Context, artifact *core.Artifact) (io.ReadCloser, error) {
+	var data []byte
+	err := s.db.View(func(queryer db.Queryer, binder db.Binder) error {
+		params := toParams(artifact)
+		query, args, err := binder.BindNamed(queryData, params)
+		if err != nil {
+			return err
+		}
+		row := queryer.QueryRow(query, args...)
+		return row.Scan(&data)
+	})
+	return ioutil.NopCloser(
+		bytes.NewBuffer(data),
+	), err
+}
+
+func (s *artifactStore) Create(ctx context.Context, artifact *core.Artifact, r io.Reader) error {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	artifact.Size = int64(len(data))
+	return s.create(ctx, artifact, data)
+}
+
+func (s *artifactStore) create(ctx context.Context, artifact *core.Artifact, data []byte) error {
+	if artifact.Created == 0 {
+		artifact.Created = time.Now().Unix()
+	}
+	if s.db.Driver() == db.Postgres {
+		return s.createPostgres(ctx, artifact, data)
+	}
+	return s.db.Lock(func(execer db.Execer, binder db.Binder) error {
+		params := toParams(artifact)
+		params["artifact_data"] = data
+		stmt, args, err := binder.BindNamed(stmtInsert, params)
+		if err != nil {
+			return err
+		}
+		res, err := execer.Exec(stmt, args...)
+		if err != nil {
+			return err
+		}
+		artifact.ID, err = res.LastInsertId()
+		return err
+	})
+}
+
+func (s *artifactStore) createPostgres(ctx context.Context, artifact *core.Artifact, data []byte) error {
+	return s.db.Lock(func(execer db.Execer, binder db.Binder) error {
+		params := toParams(artifact)
+		params["artifact_data"] = data
+		stmt, args, err := binder.BindNamed(stmtInsertPg, params)
+		if err != nil {
+			return err
+		}
+		return execer.QueryRow(stmt, args...).Scan(&artifact.ID)
+	})
+}
+
+func (s *artifactStore) Delete(ctx context.Context, artifact *core.Artifact) error {
+	return s.db.Lock(func(execer db.Execer, binder db.Binder) error {
+		params := toParams(artifact)
+		stmt, args, err := binder.BindNamed(stmtDelete, params)
+		if err != nil {
+			return err
+		}
+		_, err = execer.Exec(stmt, args...)
+		return err
+	})
+}
+
+const queryBase = `
+SELECT
+ artifact_id
+,artifact_build_id
+,artifact_stage_id
+,artifact_step_id
+,artifact_name
+,artifact_size
+,artifact_created
+`
+
+const queryKey = queryBase + `
+FROM artifacts
+WHERE artifact_id = :artifact_id
+`
+
+const queryBuild = queryBase + `
+FROM artifacts
+WHERE artifact_build_id = :artifact_build_id
+ORDER BY artifact_step_id, artifact_name
+`
+
+const queryData = `
+SELECT artifact_data
+FROM artifacts
+WHERE artifact_id = :artifact_id
+`
+
+const stmtDelete = `
+DELETE FROM artifacts
+WHERE artifact_id = :artifact_id
+`
+
+const stmtInsert = `
+INSERT INTO artifacts (
+ artifact_build_id
+,artifact_stage_id
+,artifact_step_id
+,artifact_name
+,artifact_size
+,artifact_created
+,artifact_data
+) VALUES (
+ :artifact_build_id
+,:artifact_stage_id
+,:artifact_step_id
+,:artifact_name
+,:artifact_size
+,:artifact_created
+,:artifact_data
+)
+`
+
+const stmtInsertPg = stmtInsert + `
+RETURNING artifact_id
+`
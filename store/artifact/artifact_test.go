@@ -0,0 +1,154 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package artifact
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"io/ioutil"
+	"testing"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/store/build"
+	"github.com/drone/drone/store/repos"
+	"github.com/drone/drone/store/shared/db/dbtest"
+	"github.com/drone/drone/store/step"
+)
+
+var noContext = context.TODO()
+
+func TestArtifacts(t *testing.T) {
+	conn, err := dbtest.Connect()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer func() {
+		dbtest.Reset(conn)
+		dbtest.Disconnect(conn)
+	}()
+
+	// seed with a dummy repository
+	arepo := &core.Repository{UID: "1", Slug: "octocat/hello-world"}
+	repos := repos.New(conn)
+	repos.Create(noContext, arepo)
+
+	// seed with a dummy stage
+	stage := &core.Stage{Number: 1}
+	stages := []*core.Stage{stage}
+
+	// seed with a dummy build
+	abuild := &core.Build{Number: 1, RepoID: arepo.ID}
+	builds := build.New(conn)
+	builds.Create(noContext, abuild, stages)
+
+	// seed with a dummy step
+	astep := &core.Step{Number: 1, StageID: stage.ID}
+	steps := step.New(conn)
+	steps.Create(noContext, astep)
+
+	artifact := &core.Artifact{
+		BuildID: abuild.ID,
+		StageID: stage.ID,
+		StepID:  astep.ID,
+		Name:    "dist/hello-world.tar.gz",
+	}
+
+	store := New(conn).(*artifactStore)
+	t.Run("Create", testArtifactCreate(store, artifact))
+	t.Run("Find", testArtifactFind(store, artifact))
+	t.Run("List", testArtifactList(store, artifact))
+	t.Run("Open", testArtifactOpen(store, artifact))
+	t.Run("Delete", testArtifactDelete(store, artifact))
+}
+
+func testArtifactCreate(store *artifactStore, artifact *core.Artifact) func(t *testing.T) {
+	return func(t *testing.T) {
+		buf := bytes.NewBufferString("hello world")
+		err := store.Create(noContext, artifact, buf)
+		if err != nil {
+			t.Error(err)
+		}
+		if artifact.ID == 0 {
+			t.Errorf("Want artifact ID assigned, got %d", artifact.ID)
+		}
+		if got, want := artifact.Size, int64(11); got != want {
+			t.Errorf("Want artifact size %d, got %d", want, got)
+		}
+		if artifact.Created == 0 {
+			t.Errorf("Want artifact created timestamp assigned")
+		}
+	}
+}
+
+func testArtifactFind(store *artifactStore, artifact *core.Artifact) func(t *testing.T) {
+	return func(t *testing.T) {
+		result, err := store.Find(noContext, artifact.ID)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if got, want := result.Name, artifact.Name; got != want {
+			t.Errorf("Want artifact name %q, got %q", want, got)
+		}
+		if got, want := result.StepID, artifact.StepID; got != want {
+			t.Errorf("Want artifact step id %d, got %d", want, got)
+		}
+		if got, want := result.Size, artifact.Size; got != want {
+			t.Errorf("Want artifact size %d, got %d", want, got)
+		}
+	}
+}
+
+func testArtifactList(store *artifactStore, artifact *core.Artifact) func(t *testing.T) {
+	return func(t *testing.T) {
+		list, err := store.List(noContext, artifact.BuildID)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if got, want := len(list), 1; got != want {
+			t.Errorf("Want %d artifacts, got %d", want, got)
+			return
+		}
+		if got, want := list[0].ID, artifact.ID; got != want {
+			t.Errorf("Want artifact id %d, got %d", want, got)
+		}
+	}
+}
+
+func testArtifactOpen(store *artifactStore, artifact *core.Artifact) func(t *testing.T) {
+	return func(t *testing.T) {
+		r, err := store.Open(noContext, artifact)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		data, err := ioutil.ReadAll(r)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if got, want := string(data), "hello world"; got != want {
+			t.Errorf("Want artifact contents %q, got %q", want, got)
+		}
+	}
+}
+
+func testArtifactDelete(store *artifactStore, artifact *core.Artifact) func(t *testing.T) {
+	return func(t *testing.T) {
+		err := store.Delete(noContext, artifact)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		_, err = store.Find(noContext, artifact.ID)
+		if got, want := sql.ErrNoRows, err; got != want {
+			t.Errorf("Want sql.ErrNoRows, got %v", got)
+			return
+		}
+	}
+}
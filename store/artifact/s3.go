@@ -0,0 +1,114 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package artifact
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/store/shared/db"
+)
+
+// NewS3Env returns a new S3 artifact store. The artifact
+// metadata is stored in the database, and the artifact
+// contents are stored in the S3 bucket.
+func NewS3Env(db *db.DB, bucket, prefix, endpoint string) core.ArtifactStore {
+	return &s3store{
+		artifactStore: &artifactStore{db},
+		bucket:        bucket,
+		prefix:        prefix,
+		session: session.Must(
+			session.NewSession(&aws.Config{
+				Endpoint: aws.String(endpoint),
+			}),
+		),
+	}
+}
+
+// NewS3 returns a new S3 artifact store.
+func NewS3(db *db.DB, session *session.Session, bucket, prefix string) core.ArtifactStore {
+	return &s3store{
+		artifactStore: &artifactStore{db},
+		bucket:        bucket,
+		prefix:        prefix,
+		session:       session,
+	}
+}
+
+type s3store struct {
+	*artifactStore
+	bucket  string
+	prefix  string
+	session *session.Session
+}
+
+func (s *s3store) Open(ctx context.Context, artifact *core.Artifact) (io.ReadCloser, error) {
+	svc := s3.New(s.session)
+	out, err := svc.GetObject(&s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(s.key(artifact)),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out.Body, nil
+}
+
+func (s *s3store) Create(ctx context.Context, artifact *core.Artifact, r io.Reader) error {
+	counter := &countingReader{r: r}
+	uploader := s3manager.NewUploader(s.session)
+	input := &s3manager.UploadInput{
+		ACL:    aws.String("private"),
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(s.key(artifact)),
+		Body:   counter,
+	}
+	_, err := uploader.Upload(input)
+	if err != nil {
+		return err
+	}
+	artifact.Size = counter.n
+	return s.artifactStore.create(ctx, artifact, nil)
+}
+
+func (s *s3store) Delete(ctx context.Context, artifact *core.Artifact) error {
+	svc := s3.New(s.session)
+	_, err := svc.DeleteObject(&s3.DeleteObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(s.key(artifact)),
+	})
+	if err != nil {
+		return err
+	}
+	return s.artifactStore.Delete(ctx, artifact)
+}
+
+// helper function returns the object key. The key is derived
+// from the step and name, which are unique, since the artifact
+// identifier is not known until the metadata is created.
+func (s *s3store) key(artifact *core.Artifact) string {
+	return path.Join("/", s.prefix, "artifacts",
+		fmt.Sprint(artifact.StepID), artifact.Name)
+}
+
+// countingReader counts the number of bytes read.
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,40 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package artifact
+
+import (
+	"testing"
+
+	"github.com/drone/drone/core"
+)
+
+func TestKey(t *testing.T) {
+	tests := []struct {
+		bucket string
+		prefix string
+		result string
+	}{
+		{
+			bucket: "test-bucket",
+			prefix: "drone",
+			result: "/drone/artifacts/1/dist/hello-world.tar.gz",
+		},
+		{
+			bucket: "test-bucket",
+			prefix: "/drone",
+			result: "/drone/artifacts/1/dist/hello-world.tar.gz",
+		},
+	}
+	artifact := &core.Artifact{StepID: 1, Name: "dist/hello-world.tar.gz"}
+	for _, test := range tests {
+		s := &s3store{
+			bucket: test.bucket,
+			prefix: test.prefix,
+		}
+		if got, want := s.key(artifact), test.result; got != want {
+			t.Errorf("Want key %s, got %s", want, got)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit
+
+import (
+	"context"
+	"time"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/store/shared/db"
+)
+
+// New returns a new AuditStore.
+func New(db *db.DB) core.AuditStore {
+	return &auditStore{db}
+}
+
+type auditStore struct {
+	db *db.DB
+}
+
+func (s *auditStore) List(ctx context.Context, filter core.AuditFilter) ([]*core.AuditEvent, error) {
+	var out []*core.AuditEvent
+	err := s.db.View(func(queryer db.Queryer, binder db.Binder) error {
+		params := map[string]interface{}{
+			"audit_repo_id": filter.Repo,
+			"audit_actor":   filter.Actor,
+			"audit_action":  filter.Action,
+			"audit_created": filter.Since,
+			"limit":         filter.Limit,
+			"offset":        filter.Offset,
+		}
+		stmt, args, err := binder.BindNamed(queryFilter, params)
+		if err != nil {
+			return err
+		}
+		rows, err := queryer.Query(stmt, args...)
+		if err != nil {
+			return err
+		}
+		out, err = scanRows(rows)
+		return err
+	})
+	return out, err
+}
+
+func (s *auditStore) Create(ctx context.Context, event *core.AuditEvent) error {
+	if event.Created == 0 {
+		event.Created = time.Now().Unix()
+	}
+	if s.db.Driver() == db.Postgres {
+		return s.createPostgres(ctx, event)
+	}
+	return s.create(ctx, event)
+}
+
+func (s *auditStore) create(ctx context.Context, event *core.AuditEvent) error {
+	return s.db.Lock(func(execer db.Execer, binder db.Binder) error {
+		params := toParams(event)
+		stmt, args, err := binder.BindNamed(stmtInsert, params)
+		if err != nil {
+			return err
+		}
+		res, err := execer.Exec(stmt, args...)
+		if err != nil {
+			return err
+		}
+		event.ID, err = res.LastInsertId()
+		return err
+	})
+}
+
+func (s *auditStore) createPostgres(ctx context.Context, event *core.AuditEvent) error {
+	return s.db.Lock(func(execer db.Execer, binder db.Binder) error {
+		params := toParams(event)
+		stmt, args, err := binder.BindNamed(stmtInsertPg, params)
+		if err != nil {
+			return err
+		}
+		return execer.QueryRow(stmt, args...).Scan(&event.ID)
+	})
+}
+
+// zero value filter parameters are ignored.
+const queryFilter = `
+SELECT
+ audit_id
+,audit_repo_id
+,audit_actor
+,audit_action
+,audit_target
+,audit_ip
+,audit_created
+FROM audit_events
+WHERE (:audit_repo_id = 0 OR audit_repo_id = :audit_repo_id)
+  AND (:audit_actor = '' OR audit_actor = :audit_actor)
+  AND (:audit_action = '' OR audit_action = :audit_action)
+  AND audit_created >= :audit_created
+ORDER BY audit_id DESC
+LIMIT :limit OFFSET :offset
+`
+
+const stmtInsert = `
+INSERT INTO audit_events (
+ audit_repo_id
+,audit_actor
+,audit_action
+,audit_target
+,audit_ip
+,audit_created
+) VALUES (
+ :audit_repo_id
+,:audit_actor
+,:audit_action
+,:audit_target
+,:audit_ip
+,:audit_created
+)
+`
+
+const stmtInsertPg = stmtInsert + `
+RETURNING audit_id
+`
@@ -0,0 +1,131 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package audit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/store/shared/db/dbtest"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+var noContext = context.TODO()
+
+func TestAudit(t *testing.T) {
+	conn, err := dbtest.Connect()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer func() {
+		dbtest.Reset(conn)
+		dbtest.Disconnect(conn)
+	}()
+
+	events := []*core.AuditEvent{
+		{
+			RepoID:  1,
+			Actor:   "octocat",
+			Action:  core.AuditSecretCreate,
+			Target:  "/api/repos/octocat/hello-world/secrets",
+			IP:      "127.0.0.1",
+			Created: 1000,
+		},
+		{
+			Actor:   "spaceghost",
+			Action:  core.AuditUserDelete,
+			Target:  "/api/users/octocat",
+			IP:      "127.0.0.1",
+			Created: 2000,
+		},
+	}
+
+	store := New(conn).(*auditStore)
+	t.Run("Create", testAuditCreate(store, events))
+	t.Run("List", testAuditList(store, events))
+	t.Run("Filter", testAuditFilter(store, events))
+}
+
+func testAuditCreate(store *auditStore, events []*core.AuditEvent) func(t *testing.T) {
+	return func(t *testing.T) {
+		for _, event := range events {
+			err := store.Create(noContext, event)
+			if err != nil {
+				t.Error(err)
+			}
+			if event.ID == 0 {
+				t.Errorf("Want audit event ID assigned, got %d", event.ID)
+			}
+		}
+	}
+}
+
+func testAuditList(store *auditStore, events []*core.AuditEvent) func(t *testing.T) {
+	return func(t *testing.T) {
+		list, err := store.List(noContext, core.AuditFilter{Limit: 10})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if got, want := len(list), 2; got != want {
+			t.Errorf("Want %d audit events, got %d", want, got)
+			return
+		}
+		// events are ordered from newest to oldest.
+		if diff := cmp.Diff(list[0], events[1]); diff != "" {
+			t.Errorf(diff)
+		}
+		if diff := cmp.Diff(list[1], events[0]); diff != "" {
+			t.Errorf(diff)
+		}
+
+		list, err = store.List(noContext, core.AuditFilter{Limit: 1, Offset: 1})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if got, want := len(list), 1; got != want {
+			t.Errorf("Want %d audit events, got %d", want, got)
+		} else if got, want := list[0].ID, events[0].ID; got != want {
+			t.Errorf("Want audit event ID %d, got %d", want, got)
+		}
+	}
+}
+
+func testAuditFilter(store *auditStore, events []*core.AuditEvent) func(t *testing.T) {
+	return func(t *testing.T) {
+		filters := []core.AuditFilter{
+			{Repo: 1, Limit: 10},
+			{Actor: "octocat", Limit: 10},
+			{Action: core.AuditSecretCreate, Limit: 10},
+		}
+		for _, filter := range filters {
+			list, err := store.List(noContext, filter)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if got, want := len(list), 1; got != want {
+				t.Errorf("Want %d audit events, got %d", want, got)
+			} else if got, want := list[0].ID, events[0].ID; got != want {
+				t.Errorf("Want audit event ID %d, got %d", want, got)
+			}
+		}
+
+		list, err := store.List(noContext, core.AuditFilter{Since: 1500, Limit: 10})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if got, want := len(list), 1; got != want {
+			t.Errorf("Want %d audit events, got %d", want, got)
+		} else if got, want := list[0].ID, events[1].ID; got != want {
+			t.Errorf("Want audit event ID %d, got %d", want, got)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit
+
+import (
+	"database/sql"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/store/shared/db"
+)
+
+// helper function converts the AuditEvent structure to a set
+// of named query parameters.
+func toParams(event *core.AuditEvent) map[string]interface{} {
+	return map[string]interface{}{
+		"audit_id":      event.ID,
+		"audit_repo_id": event.RepoID,
+		"audit_actor":   event.Actor,
+		"audit_action":  event.Action,
+		"audit_target":  event.Target,
+		"audit_ip":      event.IP,
+		"audit_created": event.Created,
+	}
+}
+
+// helper function scans the sql.Row and copies the column
+// values to the destination object.
+func scanRow(scanner db.Scanner, dest *core.AuditEvent) error {
+	return scanner.Scan(
+		&dest.ID,
+		&dest.RepoID,
+		&dest.Actor,
+		&dest.Action,
+		&dest.Target,
+		&dest.IP,
+		&dest.Created,
+	)
+}
+
+// helper function scans the sql.Row and copies the column
+// values to the destination object.
+func scanRows(rows *sql.Rows) ([]*core.AuditEvent, error) {
+	defer rows.Close()
+
+	list := []*core.AuditEvent{}
+	for rows.Next() {
+		event := new(core.AuditEvent)
+		err := scanRow(rows, event)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, event)
+	}
+	return list, nil
+}
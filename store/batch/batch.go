@@ -186,15 +186,27 @@ const stmtInsertBase = `
 ,repo_counter
 ,repo_config
 ,repo_timeout
+,repo_throttle
+,repo_poll_interval
+,repo_poll_branches
+,repo_polled
 ,repo_trusted
 ,repo_protected
 ,repo_no_forks
 ,repo_no_pulls
+,repo_merge_pulls
+,repo_no_dupes
+,repo_no_mask
+,repo_skip_pattern
+,repo_summary
+,repo_summary_lines
 ,repo_synced
 ,repo_created
 ,repo_updated
 ,repo_version
 ,repo_signer
+,repo_signer_prev
+,repo_signer_expiry
 ,repo_secret
 ) VALUES (
  :repo_uid
@@ -213,15 +225,27 @@ const stmtInsertBase = `
 ,:repo_counter
 ,:repo_config
 ,:repo_timeout
+,:repo_throttle
+,:repo_poll_interval
+,:repo_poll_branches
+,:repo_polled
 ,:repo_trusted
 ,:repo_protected
 ,:repo_no_forks
 ,:repo_no_pulls
+,:repo_merge_pulls
+,:repo_no_dupes
+,:repo_no_mask
+,:repo_skip_pattern
+,:repo_summary
+,:repo_summary_lines
 ,:repo_synced
 ,:repo_created
 ,:repo_updated
 ,:repo_version
 ,:repo_signer
+,:repo_signer_prev
+,:repo_signer_expiry
 ,:repo_secret
 )
 `
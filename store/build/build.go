@@ -76,10 +76,26 @@ func (s *buildStore) FindRef(ctx context.Context, repo int64, ref string) (*core
 	return out, err
 }
 
+// FindCommit returns the last successful build from the
+// datastore by commit sha and event.
+func (s *buildStore) FindCommit(ctx context.Context, repo int64, sha, event string) (*core.Build, error) {
+	out := &core.Build{RepoID: repo, After: sha, Event: event, Status: core.StatusPassing}
+	err := s.db.View(func(queryer db.Queryer, binder db.Binder) error {
+		params := toParams(out)
+		query, args, err := binder.BindNamed(queryRowCommit, params)
+		if err != nil {
+			return err
+		}
+		row := queryer.QueryRow(query, args...)
+		return scanRow(row, out)
+	})
+	return out, err
+}
+
 // List returns a list of builds from the datastore by repository id.
 func (s *buildStore) List(ctx context.Context, repo int64, limit, offset int) ([]*core.Build, error) {
 	var out []*core.Build
-	err := s.db.View(func(queryer db.Queryer, binder db.Binder) error {
+	err := s.db.ViewReplica(func(queryer db.Queryer, binder db.Binder) error {
 		params := map[string]interface{}{
 			"build_repo_id": repo,
 			"limit":         limit,
@@ -99,10 +115,45 @@ func (s *buildStore) List(ctx context.Context, repo int64, limit, offset int) ([
 	return out, err
 }
 
+// ListFilter returns a list of builds from the datastore by
+// repository id that match the filter.
+func (s *buildStore) ListFilter(ctx context.Context, repo int64, filter core.BuildFilter) ([]*core.Build, error) {
+	var out []*core.Build
+	err := s.db.ViewReplica(func(queryer db.Queryer, binder db.Binder) error {
+		params := map[string]interface{}{
+			"build_repo_id": repo,
+			"build_status":  filter.Status,
+			"build_event":   filter.Event,
+			"build_target":  filter.Branch,
+			"build_label":   filter.Label,
+			"label_pattern": labelPattern(filter.Label),
+			"since":         filter.Since,
+			"before":        filter.Before,
+			"limit":         filter.Limit,
+			"offset":        filter.Offset,
+		}
+		query := queryFilter + queryFilterDesc
+		if filter.Sort == "asc" {
+			query = queryFilter + queryFilterAsc
+		}
+		stmt, args, err := binder.BindNamed(query, params)
+		if err != nil {
+			return err
+		}
+		rows, err := queryer.Query(stmt, args...)
+		if err != nil {
+			return err
+		}
+		out, err = scanRows(rows)
+		return err
+	})
+	return out, err
+}
+
 // ListRef returns a list of builds from the datastore by ref.
 func (s *buildStore) ListRef(ctx context.Context, repo int64, ref string, limit, offset int) ([]*core.Build, error) {
 	var out []*core.Build
-	err := s.db.View(func(queryer db.Queryer, binder db.Binder) error {
+	err := s.db.ViewReplica(func(queryer db.Queryer, binder db.Binder) error {
 		params := map[string]interface{}{
 			"build_repo_id": repo,
 			"build_ref":     ref,
@@ -327,6 +378,10 @@ SELECT
 ,build_sender
 ,build_params
 ,build_deploy
+,build_restarted_from
+,build_triggered_by
+,build_labels
+,build_changed_files
 ,build_started
 ,build_finished
 ,build_created
@@ -361,6 +416,16 @@ ORDER BY build_id DESC
 LIMIT 1
 `
 
+const queryRowCommit = queryBase + `
+FROM builds
+WHERE build_repo_id = :build_repo_id
+  AND build_after = :build_after
+  AND build_event = :build_event
+  AND build_status = :build_status
+ORDER BY build_id DESC
+LIMIT 1
+`
+
 const queryRepo = queryBase + `
 FROM builds
 WHERE build_repo_id = :build_repo_id
@@ -368,6 +433,28 @@ ORDER BY build_id DESC
 LIMIT :limit OFFSET :offset
 `
 
+// zero value filter parameters are ignored.
+const queryFilter = queryBase + `
+FROM builds
+WHERE build_repo_id = :build_repo_id
+  AND (:build_status = '' OR build_status = :build_status)
+  AND (:build_event = '' OR build_event = :build_event)
+  AND (:build_target = '' OR build_target = :build_target)
+  AND (:build_label = '' OR build_labels LIKE :label_pattern ESCAPE '!')
+  AND (:since = 0 OR build_created >= :since)
+  AND (:before = 0 OR build_created < :before)
+`
+
+const queryFilterDesc = `
+ORDER BY build_id DESC
+LIMIT :limit OFFSET :offset
+`
+
+const queryFilterAsc = `
+ORDER BY build_id ASC
+LIMIT :limit OFFSET :offset
+`
+
 const queryPending = queryBase + `
 FROM builds
 WHERE EXISTS (
@@ -426,6 +513,10 @@ UPDATE builds SET
 ,build_sender = :build_sender
 ,build_params = :build_params
 ,build_deploy = :build_deploy
+,build_restarted_from = :build_restarted_from
+,build_triggered_by = :build_triggered_by
+,build_labels = :build_labels
+,build_changed_files = :build_changed_files
 ,build_started = :build_started
 ,build_finished = :build_finished
 ,build_updated = :build_updated
@@ -461,6 +552,10 @@ INSERT INTO builds (
 ,build_sender
 ,build_params
 ,build_deploy
+,build_restarted_from
+,build_triggered_by
+,build_labels
+,build_changed_files
 ,build_started
 ,build_finished
 ,build_created
@@ -492,6 +587,10 @@ INSERT INTO builds (
 ,:build_sender
 ,:build_params
 ,:build_deploy
+,:build_restarted_from
+,:build_triggered_by
+,:build_labels
+,:build_changed_files
 ,:build_started
 ,:build_finished
 ,:build_created
@@ -517,6 +616,11 @@ INSERT INTO stages (
 ,stage_errignore
 ,stage_exit_code
 ,stage_limit
+,stage_limit_repo
+,stage_timeout
+,stage_queued
+,stage_dispatched
+,stage_accepted
 ,stage_os
 ,stage_arch
 ,stage_variant
@@ -531,6 +635,9 @@ INSERT INTO stages (
 ,stage_on_failure
 ,stage_depends_on
 ,stage_labels
+,stage_elevated
+,stage_matrix
+,stage_attempts
 ) VALUES (
  :stage_repo_id
 ,:stage_build_id
@@ -543,6 +650,11 @@ INSERT INTO stages (
 ,:stage_errignore
 ,:stage_exit_code
 ,:stage_limit
+,:stage_limit_repo
+,:stage_timeout
+,:stage_queued
+,:stage_dispatched
+,:stage_accepted
 ,:stage_os
 ,:stage_arch
 ,:stage_variant
@@ -557,6 +669,9 @@ INSERT INTO stages (
 ,:stage_on_failure
 ,:stage_depends_on
 ,:stage_labels
+,:stage_elevated
+,:stage_matrix
+,:stage_attempts
 )
 `
 
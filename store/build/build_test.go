@@ -7,6 +7,7 @@ package build
 import (
 	"context"
 	"database/sql"
+	"reflect"
 	"testing"
 
 	"github.com/drone/drone/store/shared/db"
@@ -31,6 +32,7 @@ func TestBuild(t *testing.T) {
 	store := New(conn).(*buildStore)
 	t.Run("Create", testBuildCreate(store))
 	t.Run("Purge", testBuildPurge(store))
+	t.Run("FindCommit", testBuildFindCommit(store))
 	t.Run("Count", testBuildCount(store))
 	t.Run("Pending", testBuildPending(store))
 	t.Run("Running", testBuildRunning(store))
@@ -39,9 +41,11 @@ func TestBuild(t *testing.T) {
 func testBuildCreate(store *buildStore) func(t *testing.T) {
 	return func(t *testing.T) {
 		build := &core.Build{
-			RepoID: 1,
-			Number: 99,
-			Ref:    "refs/heads/master",
+			RepoID:       1,
+			Number:       99,
+			Ref:          "refs/heads/master",
+			Labels:       []string{"bug", "team=payments"},
+			ChangedFiles: 3,
 		}
 		stage := &core.Stage{
 			RepoID: 42,
@@ -62,6 +66,7 @@ func testBuildCreate(store *buildStore) func(t *testing.T) {
 		t.Run("FindRef", testBuildFindRef(store, build))
 		t.Run("List", testBuildList(store, build))
 		t.Run("ListRef", testBuildListRef(store, build))
+		t.Run("ListFilter", testBuildListFilter(store, build))
 		t.Run("Update", testBuildUpdate(store, build))
 		t.Run("Locking", testBuildLocking(store, build))
 		t.Run("Delete", testBuildDelete(store, build))
@@ -116,6 +121,48 @@ func testBuildList(store *buildStore, build *core.Build) func(t *testing.T) {
 	}
 }
 
+func testBuildListFilter(store *buildStore, build *core.Build) func(t *testing.T) {
+	return func(t *testing.T) {
+		list, err := store.ListFilter(noContext, build.RepoID, core.BuildFilter{Sort: "asc", Limit: 10})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if got, want := len(list), 1; got != want {
+			t.Errorf("Want list count %d, got %d", want, got)
+		} else {
+			t.Run("Fields", testBuild(list[0]))
+		}
+
+		list, err = store.ListFilter(noContext, build.RepoID, core.BuildFilter{Branch: "develop", Limit: 10})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if got, want := len(list), 0; got != want {
+			t.Errorf("Want filtered list count %d, got %d", want, got)
+		}
+
+		// the label filter matches the entire label, and like
+		// wildcards in the label are matched literally.
+		for label, want := range map[string]int{
+			"team=payments": 1,
+			"team":          0,
+			"team=pay_ents": 0,
+			"team=%":        0,
+		} {
+			list, err = store.ListFilter(noContext, build.RepoID, core.BuildFilter{Label: label, Limit: 10})
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if got := len(list); got != want {
+				t.Errorf("Want list count %d filtered by label %q, got %d", want, label, got)
+			}
+		}
+	}
+}
+
 func testBuildListRef(store *buildStore, build *core.Build) func(t *testing.T) {
 	return func(t *testing.T) {
 		list, err := store.ListRef(noContext, build.RepoID, build.Ref, 10, 0)
@@ -233,6 +280,34 @@ func testBuildPurge(store *buildStore) func(t *testing.T) {
 	}
 }
 
+func testBuildFindCommit(store *buildStore) func(t *testing.T) {
+	return func(t *testing.T) {
+		store.db.Update(func(execer db.Execer, binder db.Binder) error {
+			_, err := execer.Exec("DELETE FROM builds")
+			return err
+		})
+		store.Create(noContext, &core.Build{RepoID: 1, Number: 1, Event: core.EventPush, After: "7fd1a60", Status: core.StatusPassing}, nil)
+		store.Create(noContext, &core.Build{RepoID: 1, Number: 2, Event: core.EventPush, After: "7fd1a60", Status: core.StatusFailing}, nil)
+		store.Create(noContext, &core.Build{RepoID: 1, Number: 3, Event: core.EventPullRequest, After: "553c207", Status: core.StatusFailing}, nil)
+
+		build, err := store.FindCommit(noContext, 1, "7fd1a60", core.EventPush)
+		if err != nil {
+			t.Error(err)
+		} else if got, want := build.Number, int64(1); got != want {
+			t.Errorf("Want build number %d, got %d", want, got)
+		}
+
+		_, err = store.FindCommit(noContext, 1, "553c207", core.EventPullRequest)
+		if err != sql.ErrNoRows {
+			t.Errorf("Want sql.ErrNoRows for commit without successful build, got %v", err)
+		}
+		_, err = store.FindCommit(noContext, 1, "7fd1a60", core.EventCustom)
+		if err != sql.ErrNoRows {
+			t.Errorf("Want sql.ErrNoRows for event without successful build, got %v", err)
+		}
+	}
+}
+
 func testBuildCount(store *buildStore) func(t *testing.T) {
 	return func(t *testing.T) {
 		store.db.Update(func(execer db.Execer, binder db.Binder) error {
@@ -318,5 +393,11 @@ func testBuild(item *core.Build) func(t *testing.T) {
 		if got, want := item.Ref, "refs/heads/master"; got != want {
 			t.Errorf("Want build ref %q, got %q", want, got)
 		}
+		if got, want := item.Labels, []string{"bug", "team=payments"}; !reflect.DeepEqual(got, want) {
+			t.Errorf("Want build labels %v, got %v", want, got)
+		}
+		if got, want := item.ChangedFiles, int64(3); got != want {
+			t.Errorf("Want build changed files %d, got %d", want, got)
+		}
 	}
 }
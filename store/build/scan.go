@@ -18,6 +18,7 @@ package build
 import (
 	"database/sql"
 	"encoding/json"
+	"strings"
 
 	"github.com/drone/drone/core"
 	"github.com/drone/drone/store/shared/db"
@@ -29,37 +30,41 @@ import (
 // of named query parameters.
 func toParams(build *core.Build) map[string]interface{} {
 	return map[string]interface{}{
-		"build_id":            build.ID,
-		"build_repo_id":       build.RepoID,
-		"build_trigger":       build.Trigger,
-		"build_number":        build.Number,
-		"build_parent":        build.Parent,
-		"build_status":        build.Status,
-		"build_error":         build.Error,
-		"build_event":         build.Event,
-		"build_action":        build.Action,
-		"build_link":          build.Link,
-		"build_timestamp":     build.Timestamp,
-		"build_title":         build.Title,
-		"build_message":       build.Message,
-		"build_before":        build.Before,
-		"build_after":         build.After,
-		"build_ref":           build.Ref,
-		"build_source_repo":   build.Fork,
-		"build_source":        build.Source,
-		"build_target":        build.Target,
-		"build_author":        build.Author,
-		"build_author_name":   build.AuthorName,
-		"build_author_email":  build.AuthorEmail,
-		"build_author_avatar": build.AuthorAvatar,
-		"build_sender":        build.Sender,
-		"build_params":        encodeParams(build.Params),
-		"build_deploy":        build.Deploy,
-		"build_started":       build.Started,
-		"build_finished":      build.Finished,
-		"build_created":       build.Created,
-		"build_updated":       build.Updated,
-		"build_version":       build.Version,
+		"build_id":             build.ID,
+		"build_repo_id":        build.RepoID,
+		"build_trigger":        build.Trigger,
+		"build_number":         build.Number,
+		"build_parent":         build.Parent,
+		"build_status":         build.Status,
+		"build_error":          build.Error,
+		"build_event":          build.Event,
+		"build_action":         build.Action,
+		"build_link":           build.Link,
+		"build_timestamp":      build.Timestamp,
+		"build_title":          build.Title,
+		"build_message":        build.Message,
+		"build_before":         build.Before,
+		"build_after":          build.After,
+		"build_ref":            build.Ref,
+		"build_source_repo":    build.Fork,
+		"build_source":         build.Source,
+		"build_target":         build.Target,
+		"build_author":         build.Author,
+		"build_author_name":    build.AuthorName,
+		"build_author_email":   build.AuthorEmail,
+		"build_author_avatar":  build.AuthorAvatar,
+		"build_sender":         build.Sender,
+		"build_params":         encodeParams(build.Params),
+		"build_deploy":         build.Deploy,
+		"build_restarted_from": build.RestartedFrom,
+		"build_triggered_by":   build.TriggeredBy,
+		"build_labels":         encodeSlice(build.Labels),
+		"build_changed_files":  build.ChangedFiles,
+		"build_started":        build.Started,
+		"build_finished":       build.Finished,
+		"build_created":        build.Created,
+		"build_updated":        build.Updated,
+		"build_version":        build.Version,
 	}
 }
 
@@ -79,6 +84,11 @@ func toStageParams(stage *core.Stage) map[string]interface{} {
 		"stage_errignore":  stage.ErrIgnore,
 		"stage_exit_code":  stage.ExitCode,
 		"stage_limit":      stage.Limit,
+		"stage_limit_repo": stage.LimitRepo,
+		"stage_timeout":    stage.Timeout,
+		"stage_queued":     stage.Queued,
+		"stage_dispatched": stage.Dispatched,
+		"stage_accepted":   stage.Accepted,
 		"stage_os":         stage.OS,
 		"stage_arch":       stage.Arch,
 		"stage_variant":    stage.Variant,
@@ -93,6 +103,9 @@ func toStageParams(stage *core.Stage) map[string]interface{} {
 		"stage_on_failure": stage.OnFailure,
 		"stage_depends_on": encodeSlice(stage.DependsOn),
 		"stage_labels":     encodeParams(stage.Labels),
+		"stage_elevated":   stage.Elevated,
+		"stage_matrix":     encodeParams(stage.Matrix),
+		"stage_attempts":   stage.Attempts,
 	}
 }
 
@@ -106,10 +119,21 @@ func encodeSlice(v []string) types.JSONText {
 	return types.JSONText(raw)
 }
 
+// helper function returns the pattern that matches the
+// json-encoded build labels containing the label. The like
+// wildcards are escaped with the ! character.
+func labelPattern(label string) string {
+	raw, _ := json.Marshal(label)
+	return "%" + likeEscaper.Replace(string(raw)) + "%"
+}
+
+var likeEscaper = strings.NewReplacer("!", "!!", "%", "!%", "_", "!_")
+
 // helper function scans the sql.Row and copies the column
 // values to the destination object.
 func scanRow(scanner db.Scanner, dest *core.Build) error {
 	paramsJSON := types.JSONText{}
+	labelsJSON := types.JSONText{}
 	err := scanner.Scan(
 		&dest.ID,
 		&dest.RepoID,
@@ -137,6 +161,10 @@ func scanRow(scanner db.Scanner, dest *core.Build) error {
 		&dest.Sender,
 		&paramsJSON,
 		&dest.Deploy,
+		&dest.RestartedFrom,
+		&dest.TriggeredBy,
+		&labelsJSON,
+		&dest.ChangedFiles,
 		&dest.Started,
 		&dest.Finished,
 		&dest.Created,
@@ -145,6 +173,7 @@ func scanRow(scanner db.Scanner, dest *core.Build) error {
 	)
 	dest.Params = map[string]string{}
 	json.Unmarshal(paramsJSON, &dest.Params)
+	json.Unmarshal(labelsJSON, &dest.Labels)
 	return err
 }
 
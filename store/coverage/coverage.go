@@ -0,0 +1,159 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package coverage
+
+import (
+	"context"
+	"time"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/store/shared/db"
+)
+
+// New returns a new CoverageStore.
+func New(db *db.DB) core.CoverageStore {
+	return &coverageStore{db}
+}
+
+type coverageStore struct {
+	db *db.DB
+}
+
+func (s *coverageStore) List(ctx context.Context, repo int64, ref string, limit int) ([]*core.Coverage, error) {
+	var out []*core.Coverage
+	err := s.db.View(func(queryer db.Queryer, binder db.Binder) error {
+		params := map[string]interface{}{
+			"coverage_repo_id": repo,
+			"coverage_ref":     ref,
+			"limit":            limit,
+		}
+		stmt, args, err := binder.BindNamed(queryRef, params)
+		if err != nil {
+			return err
+		}
+		rows, err := queryer.Query(stmt, args...)
+		if err != nil {
+			return err
+		}
+		out, err = scanRows(rows)
+		return err
+	})
+	return out, err
+}
+
+func (s *coverageStore) ListBuild(ctx context.Context, id int64) ([]*core.Coverage, error) {
+	var out []*core.Coverage
+	err := s.db.View(func(queryer db.Queryer, binder db.Binder) error {
+		params := map[string]interface{}{"coverage_build_id": id}
+		stmt, args, err := binder.BindNamed(queryBuild, params)
+		if err != nil {
+			return err
+		}
+		rows, err := queryer.Query(stmt, args...)
+		if err != nil {
+			return err
+		}
+		out, err = scanRows(rows)
+		return err
+	})
+	return out, err
+}
+
+func (s *coverageStore) Create(ctx context.Context, coverage *core.Coverage) error {
+	if coverage.Created == 0 {
+		coverage.Created = time.Now().Unix()
+	}
+	if s.db.Driver() == db.Postgres {
+		return s.createPostgres(ctx, coverage)
+	}
+	return s.create(ctx, coverage)
+}
+
+func (s *coverageStore) create(ctx context.Context, coverage *core.Coverage) error {
+	return s.db.Lock(func(execer db.Execer, binder db.Binder) error {
+		params := toParams(coverage)
+		stmt, args, err := binder.BindNamed(stmtInsert, params)
+		if err != nil {
+			return err
+		}
+		res, err := execer.Exec(stmt, args...)
+		if err != nil {
+			return err
+		}
+		coverage.ID, err = res.LastInsertId()
+		return err
+	})
+}
+
+func (s *coverageStore) createPostgres(ctx context.Context, coverage *core.Coverage) error {
+	return s.db.Lock(func(execer db.Execer, binder db.Binder) error {
+		params := toParams(coverage)
+		stmt, args, err := binder.BindNamed(stmtInsertPg, params)
+		if err != nil {
+			return err
+		}
+		return execer.QueryRow(stmt, args...).Scan(&coverage.ID)
+	})
+}
+
+const queryBase = `
+SELECT
+ coverage_id
+,coverage_repo_id
+,coverage_build_id
+,coverage_step_id
+,coverage_ref
+,coverage_percent
+,coverage_files
+,coverage_created
+`
+
+const queryRef = queryBase + `
+FROM coverage
+WHERE coverage_repo_id = :coverage_repo_id
+  AND coverage_ref = :coverage_ref
+ORDER BY coverage_id DESC
+LIMIT :limit
+`
+
+const queryBuild = queryBase + `
+FROM coverage
+WHERE coverage_build_id = :coverage_build_id
+ORDER BY coverage_id
+`
+
+const stmtInsert = `
+INSERT INTO coverage (
+ coverage_repo_id
+,coverage_build_id
+,coverage_step_id
+,coverage_ref
+,coverage_percent
+,coverage_files
+,coverage_created
+) VALUES (
+ :coverage_repo_id
+,:coverage_build_id
+,:coverage_step_id
+,:coverage_ref
+,:coverage_percent
+,:coverage_files
+,:coverage_created
+)
+`
+
+const stmtInsertPg = stmtInsert + `
+RETURNING coverage_id
+`
@@ -0,0 +1,108 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package coverage
+
+import (
+	"context"
+	"testing"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/store/build"
+	"github.com/drone/drone/store/repos"
+	"github.com/drone/drone/store/shared/db/dbtest"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+var noContext = context.TODO()
+
+func TestCoverage(t *testing.T) {
+	conn, err := dbtest.Connect()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer func() {
+		dbtest.Reset(conn)
+		dbtest.Disconnect(conn)
+	}()
+
+	// seed with a dummy repository
+	arepo := &core.Repository{UID: "1", Slug: "octocat/hello-world"}
+	repos := repos.New(conn)
+	repos.Create(noContext, arepo)
+
+	// seed with a dummy build
+	abuild := &core.Build{Number: 1, RepoID: arepo.ID}
+	builds := build.New(conn)
+	builds.Create(noContext, abuild, []*core.Stage{{Number: 1}})
+
+	coverage := &core.Coverage{
+		RepoID:  arepo.ID,
+		BuildID: abuild.ID,
+		StepID:  1,
+		Ref:     "refs/heads/master",
+		Percent: 82.5,
+		Files: []*core.CoverageFile{
+			{Name: "main.go", Percent: 75},
+		},
+	}
+
+	store := New(conn).(*coverageStore)
+	t.Run("Create", testCoverageCreate(store, coverage))
+	t.Run("List", testCoverageList(store, coverage))
+	t.Run("ListBuild", testCoverageListBuild(store, coverage))
+}
+
+func testCoverageCreate(store *coverageStore, coverage *core.Coverage) func(t *testing.T) {
+	return func(t *testing.T) {
+		err := store.Create(noContext, coverage)
+		if err != nil {
+			t.Error(err)
+		}
+		if coverage.ID == 0 {
+			t.Errorf("Want coverage ID assigned, got %d", coverage.ID)
+		}
+	}
+}
+
+func testCoverageList(store *coverageStore, coverage *core.Coverage) func(t *testing.T) {
+	return func(t *testing.T) {
+		list, err := store.List(noContext, coverage.RepoID, coverage.Ref, 10)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if got, want := len(list), 1; got != want {
+			t.Errorf("Want %d coverage reports, got %d", want, got)
+			return
+		}
+		if diff := cmp.Diff(list[0], coverage); diff != "" {
+			t.Errorf(diff)
+		}
+
+		list, err = store.List(noContext, coverage.RepoID, "refs/heads/develop", 10)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if got, want := len(list), 0; got != want {
+			t.Errorf("Want %d coverage reports for other refs, got %d", want, got)
+		}
+	}
+}
+
+func testCoverageListBuild(store *coverageStore, coverage *core.Coverage) func(t *testing.T) {
+	return func(t *testing.T) {
+		list, err := store.ListBuild(noContext, coverage.BuildID)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if got, want := len(list), 1; got != want {
+			t.Errorf("Want %d coverage reports, got %d", want, got)
+		}
+	}
+}
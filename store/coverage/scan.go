@@ -0,0 +1,80 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package coverage
+
+import (
+	"database/sql"
+	"encoding/json"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/store/shared/db"
+
+	"github.com/jmoiron/sqlx/types"
+)
+
+// helper function converts the Coverage structure to a set
+// of named query parameters.
+func toParams(coverage *core.Coverage) map[string]interface{} {
+	return map[string]interface{}{
+		"coverage_id":       coverage.ID,
+		"coverage_repo_id":  coverage.RepoID,
+		"coverage_build_id": coverage.BuildID,
+		"coverage_step_id":  coverage.StepID,
+		"coverage_ref":      coverage.Ref,
+		"coverage_percent":  coverage.Percent,
+		"coverage_files":    encodeFiles(coverage.Files),
+		"coverage_created":  coverage.Created,
+	}
+}
+
+func encodeFiles(v []*core.CoverageFile) types.JSONText {
+	raw, _ := json.Marshal(v)
+	return types.JSONText(raw)
+}
+
+// helper function scans the sql.Row and copies the column
+// values to the destination object.
+func scanRow(scanner db.Scanner, dest *core.Coverage) error {
+	filesJSON := types.JSONText{}
+	err := scanner.Scan(
+		&dest.ID,
+		&dest.RepoID,
+		&dest.BuildID,
+		&dest.StepID,
+		&dest.Ref,
+		&dest.Percent,
+		&filesJSON,
+		&dest.Created,
+	)
+	json.Unmarshal(filesJSON, &dest.Files)
+	return err
+}
+
+// helper function scans the sql.Row and copies the column
+// values to the destination object.
+func scanRows(rows *sql.Rows) ([]*core.Coverage, error) {
+	defer rows.Close()
+
+	list := []*core.Coverage{}
+	for rows.Next() {
+		coverage := new(core.Coverage)
+		err := scanRow(rows, coverage)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, coverage)
+	}
+	return list, nil
+}
@@ -155,6 +155,7 @@ SELECT
 ,cron_branch
 ,cron_target
 ,cron_disabled
+,cron_rebuild
 ,cron_created
 ,cron_updated
 ,cron_version
@@ -196,6 +197,7 @@ UPDATE cron SET
 ,cron_branch = :cron_branch
 ,cron_target = :cron_target
 ,cron_disabled = :cron_disabled
+,cron_rebuild = :cron_rebuild
 ,cron_created = :cron_created
 ,cron_updated = :cron_updated
 ,cron_version = :cron_version
@@ -218,6 +220,7 @@ INSERT INTO cron (
 ,cron_branch
 ,cron_target
 ,cron_disabled
+,cron_rebuild
 ,cron_created
 ,cron_updated
 ,cron_version
@@ -231,6 +234,7 @@ INSERT INTO cron (
 ,:cron_branch
 ,:cron_target
 ,:cron_disabled
+,:cron_rebuild
 ,:cron_created
 ,:cron_updated
 ,:cron_version
@@ -7,8 +7,8 @@ package cron
 import (
 	"database/sql"
 
-	"github.com/drone/drone/store/shared/db"
 	"github.com/drone/drone/core"
+	"github.com/drone/drone/store/shared/db"
 )
 
 // helper function converts the User structure to a set
@@ -25,6 +25,7 @@ func toParams(cron *core.Cron) map[string]interface{} {
 		"cron_branch":   cron.Branch,
 		"cron_target":   cron.Target,
 		"cron_disabled": cron.Disabled,
+		"cron_rebuild":  cron.Rebuild,
 		"cron_created":  cron.Created,
 		"cron_updated":  cron.Updated,
 		"cron_version":  cron.Version,
@@ -45,6 +46,7 @@ func scanRow(scanner db.Scanner, dst *core.Cron) error {
 		&dst.Branch,
 		&dst.Target,
 		&dst.Disabled,
+		&dst.Rebuild,
 		&dst.Created,
 		&dst.Updated,
 		&dst.Version,
@@ -0,0 +1,232 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package delivery
+
+import (
+	"context"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/store/shared/db"
+)
+
+// New returns a new WebhookDeliveryStore.
+func New(db *db.DB) core.WebhookDeliveryStore {
+	return &deliveryStore{db}
+}
+
+type deliveryStore struct {
+	db *db.DB
+}
+
+func (s *deliveryStore) List(ctx context.Context, filter core.WebhookDeliveryFilter) ([]*core.WebhookDelivery, error) {
+	var out []*core.WebhookDelivery
+	err := s.db.View(func(queryer db.Queryer, binder db.Binder) error {
+		params := map[string]interface{}{
+			"delivery_status": filter.Status,
+			"limit":           filter.Limit,
+			"offset":          filter.Offset,
+		}
+		stmt, args, err := binder.BindNamed(queryFilter, params)
+		if err != nil {
+			return err
+		}
+		rows, err := queryer.Query(stmt, args...)
+		if err != nil {
+			return err
+		}
+		out, err = scanRows(rows)
+		return err
+	})
+	return out, err
+}
+
+func (s *deliveryStore) ListDue(ctx context.Context, now int64) ([]*core.WebhookDelivery, error) {
+	var out []*core.WebhookDelivery
+	err := s.db.View(func(queryer db.Queryer, binder db.Binder) error {
+		params := map[string]interface{}{
+			"delivery_status": core.DeliveryPending,
+			"delivery_next":   now,
+		}
+		stmt, args, err := binder.BindNamed(queryDue, params)
+		if err != nil {
+			return err
+		}
+		rows, err := queryer.Query(stmt, args...)
+		if err != nil {
+			return err
+		}
+		out, err = scanRows(rows)
+		return err
+	})
+	return out, err
+}
+
+func (s *deliveryStore) Find(ctx context.Context, id int64) (*core.WebhookDelivery, error) {
+	out := &core.WebhookDelivery{ID: id}
+	err := s.db.View(func(queryer db.Queryer, binder db.Binder) error {
+		params := toParams(out)
+		query, args, err := binder.BindNamed(queryKey, params)
+		if err != nil {
+			return err
+		}
+		row := queryer.QueryRow(query, args...)
+		return scanRow(row, out)
+	})
+	return out, err
+}
+
+func (s *deliveryStore) Create(ctx context.Context, delivery *core.WebhookDelivery) error {
+	if s.db.Driver() == db.Postgres {
+		return s.createPostgres(ctx, delivery)
+	}
+	return s.create(ctx, delivery)
+}
+
+func (s *deliveryStore) create(ctx context.Context, delivery *core.WebhookDelivery) error {
+	return s.db.Lock(func(execer db.Execer, binder db.Binder) error {
+		params := toParams(delivery)
+		stmt, args, err := binder.BindNamed(stmtInsert, params)
+		if err != nil {
+			return err
+		}
+		res, err := execer.Exec(stmt, args...)
+		if err != nil {
+			return err
+		}
+		delivery.ID, err = res.LastInsertId()
+		return err
+	})
+}
+
+func (s *deliveryStore) createPostgres(ctx context.Context, delivery *core.WebhookDelivery) error {
+	return s.db.Lock(func(execer db.Execer, binder db.Binder) error {
+		params := toParams(delivery)
+		stmt, args, err := binder.BindNamed(stmtInsertPg, params)
+		if err != nil {
+			return err
+		}
+		return execer.QueryRow(stmt, args...).Scan(&delivery.ID)
+	})
+}
+
+func (s *deliveryStore) Update(ctx context.Context, delivery *core.WebhookDelivery) error {
+	return s.db.Lock(func(execer db.Execer, binder db.Binder) error {
+		params := toParams(delivery)
+		stmt, args, err := binder.BindNamed(stmtUpdate, params)
+		if err != nil {
+			return err
+		}
+		_, err = execer.Exec(stmt, args...)
+		return err
+	})
+}
+
+func (s *deliveryStore) Purge(ctx context.Context, before int64) error {
+	return s.db.Lock(func(execer db.Execer, binder db.Binder) error {
+		params := map[string]interface{}{
+			"delivery_status":  core.DeliverySuccess,
+			"delivery_created": before,
+		}
+		stmt, args, err := binder.BindNamed(stmtPurge, params)
+		if err != nil {
+			return err
+		}
+		_, err = execer.Exec(stmt, args...)
+		return err
+	})
+}
+
+const queryBase = `
+SELECT
+ delivery_id
+,delivery_hook_id
+,delivery_endpoint
+,delivery_event
+,delivery_action
+,delivery_payload
+,delivery_status
+,delivery_attempts
+,delivery_history
+,delivery_next
+,delivery_created
+,delivery_updated
+FROM webhook_deliveries
+`
+
+const queryKey = queryBase + `
+WHERE delivery_id = :delivery_id
+`
+
+// zero value filter parameters are ignored.
+const queryFilter = queryBase + `
+WHERE (:delivery_status = '' OR delivery_status = :delivery_status)
+ORDER BY delivery_id DESC
+LIMIT :limit OFFSET :offset
+`
+
+const queryDue = queryBase + `
+WHERE delivery_status = :delivery_status
+  AND delivery_next <= :delivery_next
+ORDER BY delivery_id ASC
+`
+
+const stmtInsert = `
+INSERT INTO webhook_deliveries (
+ delivery_hook_id
+,delivery_endpoint
+,delivery_event
+,delivery_action
+,delivery_payload
+,delivery_status
+,delivery_attempts
+,delivery_history
+,delivery_next
+,delivery_created
+,delivery_updated
+) VALUES (
+ :delivery_hook_id
+,:delivery_endpoint
+,:delivery_event
+,:delivery_action
+,:delivery_payload
+,:delivery_status
+,:delivery_attempts
+,:delivery_history
+,:delivery_next
+,:delivery_created
+,:delivery_updated
+)
+`
+
+const stmtInsertPg = stmtInsert + `
+RETURNING delivery_id
+`
+
+const stmtUpdate = `
+UPDATE webhook_deliveries
+SET
+ delivery_status = :delivery_status
+,delivery_attempts = :delivery_attempts
+,delivery_history = :delivery_history
+,delivery_next = :delivery_next
+,delivery_updated = :delivery_updated
+WHERE delivery_id = :delivery_id
+`
+
+const stmtPurge = `
+DELETE FROM webhook_deliveries
+WHERE delivery_status = :delivery_status
+  AND delivery_created < :delivery_created
+`
@@ -0,0 +1,193 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package delivery
+
+import (
+	"context"
+	"testing"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/store/shared/db/dbtest"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+var noContext = context.TODO()
+
+func TestDelivery(t *testing.T) {
+	conn, err := dbtest.Connect()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer func() {
+		dbtest.Reset(conn)
+		dbtest.Disconnect(conn)
+	}()
+
+	deliveries := []*core.WebhookDelivery{
+		{
+			Endpoint: "https://company.com/hooks",
+			Event:    core.WebhookEventBuild,
+			Action:   core.WebhookActionCreated,
+			Payload:  `{"action":"created"}`,
+			Status:   core.DeliverySuccess,
+			Attempts: 1,
+			History: []*core.WebhookAttempt{
+				{Code: 200, Created: 1000},
+			},
+			Created: 1000,
+			Updated: 1000,
+		},
+		{
+			Hook:     1,
+			Endpoint: "https://company.com/hooks",
+			Event:    core.WebhookEventUser,
+			Action:   core.WebhookActionDeleted,
+			Payload:  `{"action":"deleted"}`,
+			Status:   core.DeliveryPending,
+			Attempts: 1,
+			History: []*core.WebhookAttempt{
+				{Code: 502, Error: "Bad Gateway", Created: 2000},
+			},
+			Next:    2030,
+			Created: 2000,
+			Updated: 2000,
+		},
+	}
+
+	store := New(conn).(*deliveryStore)
+	t.Run("Create", testDeliveryCreate(store, deliveries))
+	t.Run("Find", testDeliveryFind(store, deliveries[1]))
+	t.Run("List", testDeliveryList(store, deliveries))
+	t.Run("ListDue", testDeliveryListDue(store, deliveries[1]))
+	t.Run("Update", testDeliveryUpdate(store, deliveries[1]))
+	t.Run("Purge", testDeliveryPurge(store, deliveries))
+}
+
+func testDeliveryCreate(store *deliveryStore, deliveries []*core.WebhookDelivery) func(t *testing.T) {
+	return func(t *testing.T) {
+		for _, delivery := range deliveries {
+			err := store.Create(noContext, delivery)
+			if err != nil {
+				t.Error(err)
+			}
+			if delivery.ID == 0 {
+				t.Errorf("Want delivery ID assigned, got %d", delivery.ID)
+			}
+		}
+	}
+}
+
+func testDeliveryFind(store *deliveryStore, delivery *core.WebhookDelivery) func(t *testing.T) {
+	return func(t *testing.T) {
+		result, err := store.Find(noContext, delivery.ID)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if diff := cmp.Diff(result, delivery); diff != "" {
+			t.Errorf(diff)
+		}
+	}
+}
+
+func testDeliveryList(store *deliveryStore, deliveries []*core.WebhookDelivery) func(t *testing.T) {
+	return func(t *testing.T) {
+		list, err := store.List(noContext, core.WebhookDeliveryFilter{Limit: 10})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if got, want := len(list), 2; got != want {
+			t.Errorf("Want %d deliveries, got %d", want, got)
+			return
+		}
+		// deliveries are ordered from newest to oldest.
+		if got, want := list[0].ID, deliveries[1].ID; got != want {
+			t.Errorf("Want delivery ID %d, got %d", want, got)
+		}
+
+		list, err = store.List(noContext, core.WebhookDeliveryFilter{Status: core.DeliverySuccess, Limit: 10})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if got, want := len(list), 1; got != want {
+			t.Errorf("Want %d deliveries, got %d", want, got)
+		} else if got, want := list[0].ID, deliveries[0].ID; got != want {
+			t.Errorf("Want delivery ID %d, got %d", want, got)
+		}
+	}
+}
+
+func testDeliveryListDue(store *deliveryStore, delivery *core.WebhookDelivery) func(t *testing.T) {
+	return func(t *testing.T) {
+		list, err := store.ListDue(noContext, 2029)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if got, want := len(list), 0; got != want {
+			t.Errorf("Want %d due deliveries, got %d", want, got)
+		}
+
+		list, err = store.ListDue(noContext, 2030)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if got, want := len(list), 1; got != want {
+			t.Errorf("Want %d due deliveries, got %d", want, got)
+		} else if got, want := list[0].ID, delivery.ID; got != want {
+			t.Errorf("Want delivery ID %d, got %d", want, got)
+		}
+	}
+}
+
+func testDeliveryUpdate(store *deliveryStore, delivery *core.WebhookDelivery) func(t *testing.T) {
+	return func(t *testing.T) {
+		delivery.Status = core.DeliveryFailed
+		delivery.Attempts = 2
+		delivery.History = append(delivery.History, &core.WebhookAttempt{Error: "connection refused", Created: 2030})
+		delivery.Next = 0
+		delivery.Updated = 2030
+		err := store.Update(noContext, delivery)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		result, err := store.Find(noContext, delivery.ID)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if diff := cmp.Diff(result, delivery); diff != "" {
+			t.Errorf(diff)
+		}
+	}
+}
+
+func testDeliveryPurge(store *deliveryStore, deliveries []*core.WebhookDelivery) func(t *testing.T) {
+	return func(t *testing.T) {
+		err := store.Purge(noContext, 3000)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		// failed deliveries are retained in the dead-letter
+		// list and are never purged.
+		list, err := store.List(noContext, core.WebhookDeliveryFilter{Limit: 10})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if got, want := len(list), 1; got != want {
+			t.Errorf("Want %d deliveries, got %d", want, got)
+		} else if got, want := list[0].ID, deliveries[1].ID; got != want {
+			t.Errorf("Want delivery ID %d, got %d", want, got)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package delivery
+
+import (
+	"database/sql"
+	"encoding/json"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/store/shared/db"
+
+	"github.com/jmoiron/sqlx/types"
+)
+
+// helper function converts the WebhookDelivery structure
+// to a set of named query parameters.
+func toParams(delivery *core.WebhookDelivery) map[string]interface{} {
+	return map[string]interface{}{
+		"delivery_id":       delivery.ID,
+		"delivery_hook_id":  delivery.Hook,
+		"delivery_endpoint": delivery.Endpoint,
+		"delivery_event":    delivery.Event,
+		"delivery_action":   delivery.Action,
+		"delivery_payload":  delivery.Payload,
+		"delivery_status":   delivery.Status,
+		"delivery_attempts": delivery.Attempts,
+		"delivery_history":  encodeHistory(delivery.History),
+		"delivery_next":     delivery.Next,
+		"delivery_created":  delivery.Created,
+		"delivery_updated":  delivery.Updated,
+	}
+}
+
+func encodeHistory(v []*core.WebhookAttempt) types.JSONText {
+	raw, _ := json.Marshal(v)
+	return types.JSONText(raw)
+}
+
+// helper function scans the sql.Row and copies the column
+// values to the destination object.
+func scanRow(scanner db.Scanner, dest *core.WebhookDelivery) error {
+	historyJSON := types.JSONText{}
+	err := scanner.Scan(
+		&dest.ID,
+		&dest.Hook,
+		&dest.Endpoint,
+		&dest.Event,
+		&dest.Action,
+		&dest.Payload,
+		&dest.Status,
+		&dest.Attempts,
+		&historyJSON,
+		&dest.Next,
+		&dest.Created,
+		&dest.Updated,
+	)
+	json.Unmarshal(historyJSON, &dest.History)
+	return err
+}
+
+// helper function scans the sql.Row and copies the column
+// values to the destination object.
+func scanRows(rows *sql.Rows) ([]*core.WebhookDelivery, error) {
+	defer rows.Close()
+
+	list := []*core.WebhookDelivery{}
+	for rows.Next() {
+		delivery := new(core.WebhookDelivery)
+		err := scanRow(rows, delivery)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, delivery)
+	}
+	return list, nil
+}
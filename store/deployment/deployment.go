@@ -0,0 +1,176 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deployment
+
+import (
+	"context"
+	"time"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/store/shared/db"
+)
+
+// New returns a new DeploymentStore.
+func New(db *db.DB) core.DeploymentStore {
+	return &deploymentStore{db}
+}
+
+type deploymentStore struct {
+	db *db.DB
+}
+
+func (s *deploymentStore) List(ctx context.Context, repo int64, environment string, limit int) ([]*core.Deployment, error) {
+	var out []*core.Deployment
+	err := s.db.View(func(queryer db.Queryer, binder db.Binder) error {
+		params := map[string]interface{}{
+			"deployment_repo_id":     repo,
+			"deployment_environment": environment,
+			"limit":                  limit,
+		}
+		stmt, args, err := binder.BindNamed(queryEnvironment, params)
+		if err != nil {
+			return err
+		}
+		rows, err := queryer.Query(stmt, args...)
+		if err != nil {
+			return err
+		}
+		out, err = scanRows(rows)
+		return err
+	})
+	return out, err
+}
+
+func (s *deploymentStore) ListLatest(ctx context.Context, repo int64) ([]*core.Deployment, error) {
+	var out []*core.Deployment
+	err := s.db.View(func(queryer db.Queryer, binder db.Binder) error {
+		params := map[string]interface{}{
+			"deployment_repo_id": repo,
+			"deployment_status":  core.StatusPassing,
+		}
+		stmt, args, err := binder.BindNamed(queryLatest, params)
+		if err != nil {
+			return err
+		}
+		rows, err := queryer.Query(stmt, args...)
+		if err != nil {
+			return err
+		}
+		out, err = scanRows(rows)
+		return err
+	})
+	return out, err
+}
+
+func (s *deploymentStore) Create(ctx context.Context, deployment *core.Deployment) error {
+	if deployment.Created == 0 {
+		deployment.Created = time.Now().Unix()
+	}
+	if s.db.Driver() == db.Postgres {
+		return s.createPostgres(ctx, deployment)
+	}
+	return s.create(ctx, deployment)
+}
+
+func (s *deploymentStore) create(ctx context.Context, deployment *core.Deployment) error {
+	return s.db.Lock(func(execer db.Execer, binder db.Binder) error {
+		params := toParams(deployment)
+		stmt, args, err := binder.BindNamed(stmtInsert, params)
+		if err != nil {
+			return err
+		}
+		res, err := execer.Exec(stmt, args...)
+		if err != nil {
+			return err
+		}
+		deployment.ID, err = res.LastInsertId()
+		return err
+	})
+}
+
+func (s *deploymentStore) createPostgres(ctx context.Context, deployment *core.Deployment) error {
+	return s.db.Lock(func(execer db.Execer, binder db.Binder) error {
+		params := toParams(deployment)
+		stmt, args, err := binder.BindNamed(stmtInsertPg, params)
+		if err != nil {
+			return err
+		}
+		return execer.QueryRow(stmt, args...).Scan(&deployment.ID)
+	})
+}
+
+const queryBase = `
+SELECT
+ deployment_id
+,deployment_repo_id
+,deployment_build_id
+,deployment_number
+,deployment_environment
+,deployment_commit
+,deployment_ref
+,deployment_status
+,deployment_deployer
+,deployment_created
+`
+
+const queryEnvironment = queryBase + `
+FROM deployments
+WHERE deployment_repo_id = :deployment_repo_id
+  AND deployment_environment = :deployment_environment
+ORDER BY deployment_id DESC
+LIMIT :limit
+`
+
+// the latest deployment to each environment is the
+// successful deployment with the highest identifier.
+const queryLatest = queryBase + `
+FROM deployments
+WHERE deployment_id IN (
+  SELECT MAX(deployment_id)
+  FROM deployments
+  WHERE deployment_repo_id = :deployment_repo_id
+    AND deployment_status = :deployment_status
+  GROUP BY deployment_environment
+)
+ORDER BY deployment_environment
+`
+
+const stmtInsert = `
+INSERT INTO deployments (
+ deployment_repo_id
+,deployment_build_id
+,deployment_number
+,deployment_environment
+,deployment_commit
+,deployment_ref
+,deployment_status
+,deployment_deployer
+,deployment_created
+) VALUES (
+ :deployment_repo_id
+,:deployment_build_id
+,:deployment_number
+,:deployment_environment
+,:deployment_commit
+,:deployment_ref
+,:deployment_status
+,:deployment_deployer
+,:deployment_created
+)
+`
+
+const stmtInsertPg = stmtInsert + `
+RETURNING deployment_id
+`
@@ -0,0 +1,111 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package deployment
+
+import (
+	"context"
+	"testing"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/store/repos"
+	"github.com/drone/drone/store/shared/db/dbtest"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+var noContext = context.TODO()
+
+func TestDeployment(t *testing.T) {
+	conn, err := dbtest.Connect()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer func() {
+		dbtest.Reset(conn)
+		dbtest.Disconnect(conn)
+	}()
+
+	// seed with a dummy repository
+	arepo := &core.Repository{UID: "1", Slug: "octocat/hello-world"}
+	repos := repos.New(conn)
+	repos.Create(noContext, arepo)
+
+	store := New(conn).(*deploymentStore)
+	t.Run("Create", testDeploymentCreate(store, arepo))
+	t.Run("List", testDeploymentList(store, arepo))
+	t.Run("ListLatest", testDeploymentListLatest(store, arepo))
+}
+
+func testDeploymentCreate(store *deploymentStore, repo *core.Repository) func(t *testing.T) {
+	return func(t *testing.T) {
+		deployments := []*core.Deployment{
+			{RepoID: repo.ID, BuildID: 1, Number: 1, Environment: "production", Commit: "7fd1a60", Status: core.StatusPassing},
+			{RepoID: repo.ID, BuildID: 2, Number: 2, Environment: "staging", Commit: "553c2077", Status: core.StatusPassing},
+			{RepoID: repo.ID, BuildID: 3, Number: 3, Environment: "production", Commit: "553c2077", Status: core.StatusFailing},
+		}
+		for _, deployment := range deployments {
+			err := store.Create(noContext, deployment)
+			if err != nil {
+				t.Error(err)
+			}
+			if deployment.ID == 0 {
+				t.Errorf("Want deployment ID assigned, got %d", deployment.ID)
+			}
+			if deployment.Created == 0 {
+				t.Errorf("Want deployment created timestamp assigned")
+			}
+		}
+	}
+}
+
+func testDeploymentList(store *deploymentStore, repo *core.Repository) func(t *testing.T) {
+	return func(t *testing.T) {
+		list, err := store.List(noContext, repo.ID, "production", 25)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if got, want := len(list), 2; got != want {
+			t.Errorf("Want %d deployments, got %d", want, got)
+			return
+		}
+		if got, want := list[0].Number, int64(3); got != want {
+			t.Errorf("Want newest deployment first, got build %d", got)
+		}
+
+		list, err = store.List(noContext, repo.ID, "production", 1)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if got, want := len(list), 1; got != want {
+			t.Errorf("Want %d deployments with limit, got %d", want, got)
+		}
+	}
+}
+
+func testDeploymentListLatest(store *deploymentStore, repo *core.Repository) func(t *testing.T) {
+	return func(t *testing.T) {
+		list, err := store.ListLatest(noContext, repo.ID)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		got := map[string]int64{}
+		for _, deployment := range list {
+			got[deployment.Environment] = deployment.Number
+		}
+		// the failed deployment to production is excluded,
+		// since it did not replace the running version.
+		want := map[string]int64{
+			"production": 1,
+			"staging":    2,
+		}
+		if diff := cmp.Diff(got, want); diff != "" {
+			t.Errorf(diff)
+		}
+	}
+}
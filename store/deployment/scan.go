@@ -0,0 +1,73 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deployment
+
+import (
+	"database/sql"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/store/shared/db"
+)
+
+// helper function converts the Deployment structure to a set
+// of named query parameters.
+func toParams(deployment *core.Deployment) map[string]interface{} {
+	return map[string]interface{}{
+		"deployment_id":          deployment.ID,
+		"deployment_repo_id":     deployment.RepoID,
+		"deployment_build_id":    deployment.BuildID,
+		"deployment_number":      deployment.Number,
+		"deployment_environment": deployment.Environment,
+		"deployment_commit":      deployment.Commit,
+		"deployment_ref":         deployment.Ref,
+		"deployment_status":      deployment.Status,
+		"deployment_deployer":    deployment.Deployer,
+		"deployment_created":     deployment.Created,
+	}
+}
+
+// helper function scans the sql.Row and copies the column
+// values to the destination object.
+func scanRow(scanner db.Scanner, dest *core.Deployment) error {
+	return scanner.Scan(
+		&dest.ID,
+		&dest.RepoID,
+		&dest.BuildID,
+		&dest.Number,
+		&dest.Environment,
+		&dest.Commit,
+		&dest.Ref,
+		&dest.Status,
+		&dest.Deployer,
+		&dest.Created,
+	)
+}
+
+// helper function scans the sql.Row and copies the column
+// values to the destination object.
+func scanRows(rows *sql.Rows) ([]*core.Deployment, error) {
+	defer rows.Close()
+
+	list := []*core.Deployment{}
+	for rows.Next() {
+		deployment := new(core.Deployment)
+		err := scanRow(rows, deployment)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, deployment)
+	}
+	return list, nil
+}
@@ -0,0 +1,385 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package insight
+
+import (
+	"context"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/store/shared/db"
+)
+
+// New returns a new InsightStore.
+func New(db *db.DB) core.InsightStore {
+	return &insightStore{db}
+}
+
+type insightStore struct {
+	db *db.DB
+}
+
+func (s *insightStore) Record(ctx context.Context, build *core.Build, stages []*core.Stage) error {
+	day := core.InsightDayOf(build.Created)
+	return s.db.Update(func(execer db.Execer, binder db.Binder) error {
+		params := toBuildParams(build, day)
+		err := upsert(execer, binder, stmtUpdateBuild, stmtInsertBuild, params)
+		if err != nil {
+			return err
+		}
+		params = toDurationParams(build, day)
+		err = upsert(execer, binder, stmtUpdateDuration, stmtInsertDuration, params)
+		if err != nil {
+			return err
+		}
+		for _, stage := range stages {
+			for _, step := range stage.Steps {
+				if !isRecorded(step.Status) {
+					continue
+				}
+				params = toStepParams(build, stage, step, day)
+				err = upsert(execer, binder, stmtUpdateStep, stmtInsertStep, params)
+				if err != nil {
+					return err
+				}
+				// the step outcomes are tracked per commit to
+				// detect steps that pass and fail when the same
+				// commit is built more than once.
+				if build.After == "" {
+					continue
+				}
+				params = toCommitParams(build, stage, step, day)
+				err = upsert(execer, binder, stmtUpdateCommit, stmtInsertCommit, params)
+				if err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+func (s *insightStore) ListDays(ctx context.Context, repo, since int64) ([]*core.InsightDay, error) {
+	var out []*core.InsightDay
+	err := s.db.ViewReplica(func(queryer db.Queryer, binder db.Binder) error {
+		params := map[string]interface{}{
+			"insight_repo_id": repo,
+			"insight_day":     since,
+		}
+		stmt, args, err := binder.BindNamed(queryDays, params)
+		if err != nil {
+			return err
+		}
+		rows, err := queryer.Query(stmt, args...)
+		if err != nil {
+			return err
+		}
+		out, err = scanDays(rows)
+		return err
+	})
+	return out, err
+}
+
+func (s *insightStore) ListDurations(ctx context.Context, repo, since int64) ([]*core.InsightBucket, error) {
+	var out []*core.InsightBucket
+	err := s.db.ViewReplica(func(queryer db.Queryer, binder db.Binder) error {
+		params := map[string]interface{}{
+			"insight_repo_id": repo,
+			"insight_day":     since,
+		}
+		stmt, args, err := binder.BindNamed(queryDurations, params)
+		if err != nil {
+			return err
+		}
+		rows, err := queryer.Query(stmt, args...)
+		if err != nil {
+			return err
+		}
+		out, err = scanBuckets(rows)
+		return err
+	})
+	return out, err
+}
+
+func (s *insightStore) ListSteps(ctx context.Context, repo, since int64, limit int) ([]*core.InsightStep, error) {
+	var out []*core.InsightStep
+	err := s.db.ViewReplica(func(queryer db.Queryer, binder db.Binder) error {
+		params := map[string]interface{}{
+			"insight_repo_id": repo,
+			"insight_day":     since,
+			"limit":           limit,
+		}
+		stmt, args, err := binder.BindNamed(querySteps, params)
+		if err != nil {
+			return err
+		}
+		rows, err := queryer.Query(stmt, args...)
+		if err != nil {
+			return err
+		}
+		out, err = scanSteps(rows)
+		return err
+	})
+	return out, err
+}
+
+func (s *insightStore) ListFlaky(ctx context.Context, repo int64, branch string, since int64, limit int) ([]*core.InsightFlaky, error) {
+	var out []*core.InsightFlaky
+	err := s.db.ViewReplica(func(queryer db.Queryer, binder db.Binder) error {
+		params := map[string]interface{}{
+			"insight_repo_id": repo,
+			"insight_branch":  branch,
+			"insight_day":     since,
+			"limit":           limit,
+		}
+		query := queryFlaky
+		if branch != "" {
+			query = queryFlakyBranch
+		}
+		stmt, args, err := binder.BindNamed(query, params)
+		if err != nil {
+			return err
+		}
+		rows, err := queryer.Query(stmt, args...)
+		if err != nil {
+			return err
+		}
+		out, err = scanFlaky(rows)
+		return err
+	})
+	return out, err
+}
+
+// helper function increments the summary row, and inserts
+// the row if it does not exist. The statements are executed
+// inside a transaction.
+func upsert(execer db.Execer, binder db.Binder, update, insert string, params map[string]interface{}) error {
+	stmt, args, err := binder.BindNamed(update, params)
+	if err != nil {
+		return err
+	}
+	res, err := execer.Exec(stmt, args...)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil || n != 0 {
+		return err
+	}
+	stmt, args, err = binder.BindNamed(insert, params)
+	if err != nil {
+		return err
+	}
+	_, err = execer.Exec(stmt, args...)
+	return err
+}
+
+// helper function returns true if the step outcome is
+// included in the step summaries. Skipped and cancelled
+// steps are excluded.
+func isRecorded(status string) bool {
+	switch status {
+	case core.StatusPassing, core.StatusFailing, core.StatusError:
+		return true
+	default:
+		return false
+	}
+}
+
+const queryDays = `
+SELECT
+ insight_day
+,insight_total
+,insight_success
+,insight_failure
+,insight_duration
+,insight_wait
+FROM insight_builds
+WHERE insight_repo_id = :insight_repo_id
+  AND insight_day >= :insight_day
+ORDER BY insight_day ASC
+`
+
+const queryDurations = `
+SELECT
+ insight_bucket
+,SUM(insight_count)
+FROM insight_durations
+WHERE insight_repo_id = :insight_repo_id
+  AND insight_day >= :insight_day
+GROUP BY insight_bucket
+ORDER BY insight_bucket ASC
+`
+
+const querySteps = `
+SELECT
+ insight_stage
+,insight_name
+,SUM(insight_total)
+,SUM(insight_failure)
+,SUM(insight_duration)
+FROM insight_steps
+WHERE insight_repo_id = :insight_repo_id
+  AND insight_day >= :insight_day
+GROUP BY insight_stage, insight_name
+HAVING SUM(insight_failure) > 0
+ORDER BY SUM(insight_failure) * 1.0 / SUM(insight_total) DESC, SUM(insight_total) DESC
+LIMIT :limit
+`
+
+const queryFlakyBase = `
+SELECT
+ insight_stage
+,insight_name
+,COUNT(*)
+,SUM(CASE WHEN insight_success > 0 AND insight_failure > 0 THEN 1 ELSE 0 END)
+FROM insight_commits
+WHERE insight_repo_id = :insight_repo_id
+  AND insight_day >= :insight_day
+  AND insight_success + insight_failure > 1
+`
+
+const queryFlakyGroup = `
+GROUP BY insight_stage, insight_name
+HAVING SUM(CASE WHEN insight_success > 0 AND insight_failure > 0 THEN 1 ELSE 0 END) > 0
+ORDER BY SUM(CASE WHEN insight_success > 0 AND insight_failure > 0 THEN 1 ELSE 0 END) * 1.0 / COUNT(*) DESC, COUNT(*) DESC
+LIMIT :limit
+`
+
+const queryFlaky = queryFlakyBase + queryFlakyGroup
+
+const queryFlakyBranch = queryFlakyBase + `
+  AND insight_branch = :insight_branch
+` + queryFlakyGroup
+
+const stmtUpdateBuild = `
+UPDATE insight_builds
+SET
+ insight_total    = insight_total + :insight_total
+,insight_success  = insight_success + :insight_success
+,insight_failure  = insight_failure + :insight_failure
+,insight_duration = insight_duration + :insight_duration
+,insight_wait     = insight_wait + :insight_wait
+WHERE insight_repo_id = :insight_repo_id
+  AND insight_day = :insight_day
+`
+
+const stmtInsertBuild = `
+INSERT INTO insight_builds (
+ insight_repo_id
+,insight_day
+,insight_total
+,insight_success
+,insight_failure
+,insight_duration
+,insight_wait
+) VALUES (
+ :insight_repo_id
+,:insight_day
+,:insight_total
+,:insight_success
+,:insight_failure
+,:insight_duration
+,:insight_wait
+)
+`
+
+const stmtUpdateDuration = `
+UPDATE insight_durations
+SET
+ insight_count = insight_count + :insight_count
+WHERE insight_repo_id = :insight_repo_id
+  AND insight_day = :insight_day
+  AND insight_bucket = :insight_bucket
+`
+
+const stmtInsertDuration = `
+INSERT INTO insight_durations (
+ insight_repo_id
+,insight_day
+,insight_bucket
+,insight_count
+) VALUES (
+ :insight_repo_id
+,:insight_day
+,:insight_bucket
+,:insight_count
+)
+`
+
+const stmtUpdateStep = `
+UPDATE insight_steps
+SET
+ insight_total    = insight_total + :insight_total
+,insight_failure  = insight_failure + :insight_failure
+,insight_duration = insight_duration + :insight_duration
+WHERE insight_repo_id = :insight_repo_id
+  AND insight_day = :insight_day
+  AND insight_stage = :insight_stage
+  AND insight_name = :insight_name
+`
+
+const stmtInsertStep = `
+INSERT INTO insight_steps (
+ insight_repo_id
+,insight_day
+,insight_stage
+,insight_name
+,insight_total
+,insight_failure
+,insight_duration
+) VALUES (
+ :insight_repo_id
+,:insight_day
+,:insight_stage
+,:insight_name
+,:insight_total
+,:insight_failure
+,:insight_duration
+)
+`
+
+const stmtUpdateCommit = `
+UPDATE insight_commits
+SET
+ insight_day     = :insight_day
+,insight_success = insight_success + :insight_success
+,insight_failure = insight_failure + :insight_failure
+WHERE insight_repo_id = :insight_repo_id
+  AND insight_branch = :insight_branch
+  AND insight_commit = :insight_commit
+  AND insight_stage = :insight_stage
+  AND insight_name = :insight_name
+`
+
+const stmtInsertCommit = `
+INSERT INTO insight_commits (
+ insight_repo_id
+,insight_branch
+,insight_commit
+,insight_stage
+,insight_name
+,insight_day
+,insight_success
+,insight_failure
+) VALUES (
+ :insight_repo_id
+,:insight_branch
+,:insight_commit
+,:insight_stage
+,:insight_name
+,:insight_day
+,:insight_success
+,:insight_failure
+)
+`
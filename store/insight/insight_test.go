@@ -0,0 +1,194 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package insight
+
+import (
+	"context"
+	"testing"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/store/repos"
+	"github.com/drone/drone/store/shared/db/dbtest"
+)
+
+var noContext = context.TODO()
+
+// 2019-03-14 00:00:00 UTC
+const dummyDay = 1552521600
+
+const dummyCommit = "7fd1a60b01f91b314f59955a4e4d4e80d8edf11d"
+
+func TestInsight(t *testing.T) {
+	conn, err := dbtest.Connect()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer func() {
+		dbtest.Reset(conn)
+		dbtest.Disconnect(conn)
+	}()
+
+	// seeds the database with a dummy repository.
+	repo := &core.Repository{UID: "1", Slug: "octocat/hello-world", Namespace: "octocat"}
+	repos := repos.New(conn)
+	if err := repos.Create(noContext, repo); err != nil {
+		t.Error(err)
+	}
+
+	store := New(conn).(*insightStore)
+	t.Run("Record", testInsightRecord(store, repo))
+}
+
+func testInsightRecord(store *insightStore, repo *core.Repository) func(t *testing.T) {
+	return func(t *testing.T) {
+		builds := []*core.Build{
+			{RepoID: repo.ID, Status: core.StatusPassing, Target: "master", After: dummyCommit, Created: dummyDay + 100, Started: dummyDay + 110, Finished: dummyDay + 170},
+			{RepoID: repo.ID, Status: core.StatusFailing, Target: "master", After: dummyCommit, Created: dummyDay + 200, Started: dummyDay + 230, Finished: dummyDay + 530},
+		}
+		stages := [][]*core.Stage{
+			{{Name: "default", Steps: []*core.Step{
+				{Name: "test", Status: core.StatusPassing, Started: 10, Stopped: 40},
+				{Name: "deploy", Status: core.StatusSkipped},
+			}}},
+			{{Name: "default", Steps: []*core.Step{
+				{Name: "test", Status: core.StatusFailing, Started: 10, Stopped: 30},
+			}}},
+		}
+		for i, build := range builds {
+			if err := store.Record(noContext, build, stages[i]); err != nil {
+				t.Error(err)
+				return
+			}
+		}
+
+		t.Run("ListDays", testInsightListDays(store, repo))
+		t.Run("ListDurations", testInsightListDurations(store, repo))
+		t.Run("ListSteps", testInsightListSteps(store, repo))
+		t.Run("ListFlaky", testInsightListFlaky(store, repo))
+	}
+}
+
+func testInsightListDays(store *insightStore, repo *core.Repository) func(t *testing.T) {
+	return func(t *testing.T) {
+		list, err := store.ListDays(noContext, repo.ID, dummyDay)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if got, want := len(list), 1; got != want {
+			t.Errorf("Want %d days, got %d", want, got)
+			return
+		}
+		day := list[0]
+		if got, want := day.Day, int64(dummyDay); got != want {
+			t.Errorf("Want day %d, got %d", want, got)
+		}
+		if got, want := day.Total, int64(2); got != want {
+			t.Errorf("Want %d builds, got %d", want, got)
+		}
+		if got, want := day.Success, int64(1); got != want {
+			t.Errorf("Want %d successful builds, got %d", want, got)
+		}
+		if got, want := day.Failure, int64(1); got != want {
+			t.Errorf("Want %d failed builds, got %d", want, got)
+		}
+		if got, want := day.Duration, int64(360); got != want {
+			t.Errorf("Want duration %d, got %d", want, got)
+		}
+		if got, want := day.Wait, int64(40); got != want {
+			t.Errorf("Want wait %d, got %d", want, got)
+		}
+
+		list, err = store.ListDays(noContext, repo.ID, dummyDay+86400)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if got, want := len(list), 0; got != want {
+			t.Errorf("Want %d days, got %d", want, got)
+		}
+	}
+}
+
+func testInsightListDurations(store *insightStore, repo *core.Repository) func(t *testing.T) {
+	return func(t *testing.T) {
+		list, err := store.ListDurations(noContext, repo.ID, dummyDay)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if got, want := len(list), 2; got != want {
+			t.Errorf("Want %d buckets, got %d", want, got)
+			return
+		}
+		if got, want := list[0].Bucket, core.InsightBucketOf(60); got != want {
+			t.Errorf("Want bucket %d, got %d", want, got)
+		}
+		if got, want := list[1].Bucket, core.InsightBucketOf(300); got != want {
+			t.Errorf("Want bucket %d, got %d", want, got)
+		}
+	}
+}
+
+func testInsightListSteps(store *insightStore, repo *core.Repository) func(t *testing.T) {
+	return func(t *testing.T) {
+		list, err := store.ListSteps(noContext, repo.ID, dummyDay, 10)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if got, want := len(list), 1; got != want {
+			t.Errorf("Want %d steps, got %d", want, got)
+			return
+		}
+		step := list[0]
+		if got, want := step.Name, "test"; got != want {
+			t.Errorf("Want step name %q, got %q", want, got)
+		}
+		if got, want := step.Total, int64(2); got != want {
+			t.Errorf("Want %d step executions, got %d", want, got)
+		}
+		if got, want := step.FailureRate, 0.5; got != want {
+			t.Errorf("Want failure rate %v, got %v", want, got)
+		}
+		if got, want := step.DurationMean, int64(25); got != want {
+			t.Errorf("Want mean duration %d, got %d", want, got)
+		}
+	}
+}
+
+func testInsightListFlaky(store *insightStore, repo *core.Repository) func(t *testing.T) {
+	return func(t *testing.T) {
+		list, err := store.ListFlaky(noContext, repo.ID, "master", dummyDay, 10)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if got, want := len(list), 1; got != want {
+			t.Errorf("Want %d flaky steps, got %d", want, got)
+			return
+		}
+		flaky := list[0]
+		if got, want := flaky.Name, "test"; got != want {
+			t.Errorf("Want step name %q, got %q", want, got)
+		}
+		if got, want := flaky.Commits, int64(1); got != want {
+			t.Errorf("Want %d commits, got %d", want, got)
+		}
+		if got, want := flaky.Score, 1.0; got != want {
+			t.Errorf("Want flakiness score %v, got %v", want, got)
+		}
+
+		list, err = store.ListFlaky(noContext, repo.ID, "develop", dummyDay, 10)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if got, want := len(list), 0; got != want {
+			t.Errorf("Want %d flaky steps, got %d", want, got)
+		}
+	}
+}
@@ -0,0 +1,201 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package insight
+
+import (
+	"database/sql"
+
+	"github.com/drone/drone/core"
+)
+
+// helper function converts the finished build to a set of
+// named query parameters that increment the daily summary.
+func toBuildParams(build *core.Build, day int64) map[string]interface{} {
+	params := map[string]interface{}{
+		"insight_repo_id":  build.RepoID,
+		"insight_day":      day,
+		"insight_total":    1,
+		"insight_success":  0,
+		"insight_failure":  0,
+		"insight_duration": duration(build.Started, build.Finished),
+		"insight_wait":     duration(build.Created, build.Started),
+	}
+	switch build.Status {
+	case core.StatusPassing:
+		params["insight_success"] = 1
+	case core.StatusFailing, core.StatusError:
+		params["insight_failure"] = 1
+	}
+	return params
+}
+
+// helper function converts the finished build to a set of
+// named query parameters that increment the duration
+// histogram.
+func toDurationParams(build *core.Build, day int64) map[string]interface{} {
+	return map[string]interface{}{
+		"insight_repo_id": build.RepoID,
+		"insight_day":     day,
+		"insight_bucket":  core.InsightBucketOf(duration(build.Started, build.Finished)),
+		"insight_count":   1,
+	}
+}
+
+// helper function converts the finished step to a set of
+// named query parameters that increment the step summary.
+func toStepParams(build *core.Build, stage *core.Stage, step *core.Step, day int64) map[string]interface{} {
+	params := map[string]interface{}{
+		"insight_repo_id":  build.RepoID,
+		"insight_day":      day,
+		"insight_stage":    stage.Name,
+		"insight_name":     step.Name,
+		"insight_total":    1,
+		"insight_failure":  0,
+		"insight_duration": duration(step.Started, step.Stopped),
+	}
+	if step.Status != core.StatusPassing {
+		params["insight_failure"] = 1
+	}
+	return params
+}
+
+// helper function converts the finished step to a set of
+// named query parameters that increment the step outcomes
+// for the build commit.
+func toCommitParams(build *core.Build, stage *core.Stage, step *core.Step, day int64) map[string]interface{} {
+	params := map[string]interface{}{
+		"insight_repo_id": build.RepoID,
+		"insight_branch":  build.Target,
+		"insight_commit":  build.After,
+		"insight_stage":   stage.Name,
+		"insight_name":    step.Name,
+		"insight_day":     day,
+		"insight_success": 1,
+		"insight_failure": 0,
+	}
+	if step.Status != core.StatusPassing {
+		params["insight_success"] = 0
+		params["insight_failure"] = 1
+	}
+	return params
+}
+
+// helper function returns the number of seconds between the
+// start and end timestamps, or zero if either timestamp is
+// not set.
+func duration(start, end int64) int64 {
+	if start == 0 || end < start {
+		return 0
+	}
+	return end - start
+}
+
+// helper function scans the sql.Rows and returns the daily
+// summaries.
+func scanDays(rows *sql.Rows) ([]*core.InsightDay, error) {
+	defer rows.Close()
+
+	list := []*core.InsightDay{}
+	for rows.Next() {
+		day := new(core.InsightDay)
+		err := rows.Scan(
+			&day.Day,
+			&day.Total,
+			&day.Success,
+			&day.Failure,
+			&day.Duration,
+			&day.Wait,
+		)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, day)
+	}
+	return list, nil
+}
+
+// helper function scans the sql.Rows and returns the
+// duration histogram buckets.
+func scanBuckets(rows *sql.Rows) ([]*core.InsightBucket, error) {
+	defer rows.Close()
+
+	list := []*core.InsightBucket{}
+	for rows.Next() {
+		bucket := new(core.InsightBucket)
+		err := rows.Scan(
+			&bucket.Bucket,
+			&bucket.Count,
+		)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, bucket)
+	}
+	return list, nil
+}
+
+// helper function scans the sql.Rows and returns the step
+// summaries. The failure rate and mean duration are computed
+// from the totals.
+func scanSteps(rows *sql.Rows) ([]*core.InsightStep, error) {
+	defer rows.Close()
+
+	list := []*core.InsightStep{}
+	for rows.Next() {
+		var seconds int64
+		step := new(core.InsightStep)
+		err := rows.Scan(
+			&step.Stage,
+			&step.Name,
+			&step.Total,
+			&step.Failure,
+			&seconds,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if step.Total != 0 {
+			step.FailureRate = float64(step.Failure) / float64(step.Total)
+			step.DurationMean = seconds / step.Total
+		}
+		list = append(list, step)
+	}
+	return list, nil
+}
+
+// helper function scans the sql.Rows and returns the flaky
+// step summaries. The score is computed from the totals.
+func scanFlaky(rows *sql.Rows) ([]*core.InsightFlaky, error) {
+	defer rows.Close()
+
+	list := []*core.InsightFlaky{}
+	for rows.Next() {
+		flaky := new(core.InsightFlaky)
+		err := rows.Scan(
+			&flaky.Stage,
+			&flaky.Name,
+			&flaky.Commits,
+			&flaky.Flaky,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if flaky.Commits != 0 {
+			flaky.Score = float64(flaky.Flaky) / float64(flaky.Commits)
+		}
+		list = append(list, flaky)
+	}
+	return list, nil
+}
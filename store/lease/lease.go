@@ -0,0 +1,124 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package lease
+
+import (
+	"context"
+	"time"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/store/shared/db"
+
+	"github.com/dchest/uniuri"
+)
+
+// New returns a new leader Elector backed by the database.
+// Each elector is identified by a random holder name, and
+// holds a lease until the lease expires or is released.
+func New(db *db.DB) core.Elector {
+	return &leaseStore{
+		db:     db,
+		holder: uniuri.NewLen(32),
+	}
+}
+
+type leaseStore struct {
+	db     *db.DB
+	holder string
+}
+
+func (s *leaseStore) Acquire(ctx context.Context, name string, ttl time.Duration) (bool, error) {
+	var acquired bool
+	err := s.db.Lock(func(execer db.Execer, binder db.Binder) error {
+		now := time.Now()
+		params := map[string]interface{}{
+			"lease_name":    name,
+			"lease_holder":  s.holder,
+			"lease_expires": now.Add(ttl).Unix(),
+			"lease_now":     now.Unix(),
+		}
+		// renew the lease if held by this instance, or take
+		// over the lease if expired.
+		stmt, args, err := binder.BindNamed(stmtUpdate, params)
+		if err != nil {
+			return err
+		}
+		res, err := execer.Exec(stmt, args...)
+		if err != nil {
+			return err
+		}
+		if n, _ := res.RowsAffected(); n > 0 {
+			acquired = true
+			return nil
+		}
+		// create the lease if it does not exist. If another
+		// instance created the lease first, the insert fails
+		// with a unique constraint violation, which is
+		// verified below.
+		stmt, args, err = binder.BindNamed(stmtInsert, params)
+		if err != nil {
+			return err
+		}
+		if _, err := execer.Exec(stmt, args...); err == nil {
+			acquired = true
+			return nil
+		}
+		stmt, args, err = binder.BindNamed(queryKey, params)
+		if err != nil {
+			return err
+		}
+		var holder string
+		return execer.QueryRow(stmt, args...).Scan(&holder)
+	})
+	return acquired, err
+}
+
+func (s *leaseStore) Release(ctx context.Context, name string) error {
+	return s.db.Lock(func(execer db.Execer, binder db.Binder) error {
+		params := map[string]interface{}{
+			"lease_name":   name,
+			"lease_holder": s.holder,
+		}
+		stmt, args, err := binder.BindNamed(stmtDelete, params)
+		if err != nil {
+			return err
+		}
+		_, err = execer.Exec(stmt, args...)
+		return err
+	})
+}
+
+const queryKey = `
+SELECT lease_holder
+FROM leases
+WHERE lease_name = :lease_name
+`
+
+const stmtInsert = `
+INSERT INTO leases (
+ lease_name
+,lease_holder
+,lease_expires
+) VALUES (
+ :lease_name
+,:lease_holder
+,:lease_expires
+)
+`
+
+const stmtUpdate = `
+UPDATE leases
+SET
+ lease_holder  = :lease_holder
+,lease_expires = :lease_expires
+WHERE lease_name = :lease_name
+  AND (lease_holder = :lease_holder OR lease_expires < :lease_now)
+`
+
+const stmtDelete = `
+DELETE FROM leases
+WHERE lease_name = :lease_name
+  AND lease_holder = :lease_holder
+`
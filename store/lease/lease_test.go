@@ -0,0 +1,99 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package lease
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/drone/drone/store/shared/db/dbtest"
+)
+
+var noContext = context.TODO()
+
+func TestLease(t *testing.T) {
+	conn, err := dbtest.Connect()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer func() {
+		dbtest.Reset(conn)
+		dbtest.Disconnect(conn)
+	}()
+
+	leader := New(conn)
+	follower := New(conn)
+
+	ok, err := leader.Acquire(noContext, "cron", time.Minute)
+	if err != nil {
+		t.Error(err)
+	}
+	if !ok {
+		t.Errorf("Want lease acquired")
+	}
+
+	ok, err = follower.Acquire(noContext, "cron", time.Minute)
+	if err != nil {
+		t.Error(err)
+	}
+	if ok {
+		t.Errorf("Want lease held by another instance")
+	}
+
+	// the lease is renewed by the instance holding the lease.
+	ok, err = leader.Acquire(noContext, "cron", time.Minute)
+	if err != nil {
+		t.Error(err)
+	}
+	if !ok {
+		t.Errorf("Want lease renewed")
+	}
+
+	// the lease can be acquired by another instance once
+	// the lease is released.
+	if err := leader.Release(noContext, "cron"); err != nil {
+		t.Error(err)
+	}
+	ok, err = follower.Acquire(noContext, "cron", time.Minute)
+	if err != nil {
+		t.Error(err)
+	}
+	if !ok {
+		t.Errorf("Want lease acquired after release")
+	}
+}
+
+func TestLease_Expired(t *testing.T) {
+	conn, err := dbtest.Connect()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer func() {
+		dbtest.Reset(conn)
+		dbtest.Disconnect(conn)
+	}()
+
+	leader := New(conn)
+	follower := New(conn)
+
+	ok, err := leader.Acquire(noContext, "cron", -time.Minute)
+	if err != nil {
+		t.Error(err)
+	}
+	if !ok {
+		t.Errorf("Want lease acquired")
+	}
+
+	ok, err = follower.Acquire(noContext, "cron", time.Minute)
+	if err != nil {
+		t.Error(err)
+	}
+	if !ok {
+		t.Errorf("Want expired lease acquired by another instance")
+	}
+}
@@ -34,9 +34,14 @@ type logStore struct {
 	db *db.DB
 }
 
+// Ping verifies the database storing the logs is reachable.
+func (s *logStore) Ping(ctx context.Context) error {
+	return s.db.Ping(ctx)
+}
+
 func (s *logStore) Find(ctx context.Context, step int64) (io.ReadCloser, error) {
 	out := &logs{ID: step}
-	err := s.db.View(func(queryer db.Queryer, binder db.Binder) error {
+	err := s.db.ViewReplica(func(queryer db.Queryer, binder db.Binder) error {
 		query, args, err := binder.BindNamed(queryKey, out)
 		if err != nil {
 			return err
@@ -86,6 +86,15 @@ func (s *s3store) Delete(ctx context.Context, step int64) error {
 	return err
 }
 
+// Ping verifies the bucket storing the logs is reachable.
+func (s *s3store) Ping(ctx context.Context) error {
+	svc := s3.New(s.session)
+	_, err := svc.HeadBucketWithContext(ctx, &s3.HeadBucketInput{
+		Bucket: aws.String(s.bucket),
+	})
+	return err
+}
+
 func (s *s3store) key(step int64) string {
 	return path.Join("/", s.prefix, fmt.Sprint(step))
 }
@@ -0,0 +1,153 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mapping
+
+import (
+	"context"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/store/shared/db"
+)
+
+// New returns a new PathMapping database store.
+func New(db *db.DB) core.PathMappingStore {
+	return &mappingStore{db}
+}
+
+type mappingStore struct {
+	db *db.DB
+}
+
+func (s *mappingStore) List(ctx context.Context, id int64) ([]*core.PathMapping, error) {
+	var out []*core.PathMapping
+	err := s.db.View(func(queryer db.Queryer, binder db.Binder) error {
+		params := map[string]interface{}{"mapping_repo_id": id}
+		stmt, args, err := binder.BindNamed(queryRepo, params)
+		if err != nil {
+			return err
+		}
+		rows, err := queryer.Query(stmt, args...)
+		if err != nil {
+			return err
+		}
+		out, err = scanRows(rows)
+		return err
+	})
+	return out, err
+}
+
+func (s *mappingStore) Find(ctx context.Context, id int64) (*core.PathMapping, error) {
+	out := &core.PathMapping{ID: id}
+	err := s.db.View(func(queryer db.Queryer, binder db.Binder) error {
+		params := toParams(out)
+		query, args, err := binder.BindNamed(queryKey, params)
+		if err != nil {
+			return err
+		}
+		row := queryer.QueryRow(query, args...)
+		return scanRow(row, out)
+	})
+	return out, err
+}
+
+func (s *mappingStore) Create(ctx context.Context, mapping *core.PathMapping) error {
+	if s.db.Driver() == db.Postgres {
+		return s.createPostgres(ctx, mapping)
+	}
+	return s.create(ctx, mapping)
+}
+
+func (s *mappingStore) create(ctx context.Context, mapping *core.PathMapping) error {
+	return s.db.Lock(func(execer db.Execer, binder db.Binder) error {
+		params := toParams(mapping)
+		stmt, args, err := binder.BindNamed(stmtInsert, params)
+		if err != nil {
+			return err
+		}
+		res, err := execer.Exec(stmt, args...)
+		if err != nil {
+			return err
+		}
+		mapping.ID, err = res.LastInsertId()
+		return err
+	})
+}
+
+func (s *mappingStore) createPostgres(ctx context.Context, mapping *core.PathMapping) error {
+	return s.db.Lock(func(execer db.Execer, binder db.Binder) error {
+		params := toParams(mapping)
+		stmt, args, err := binder.BindNamed(stmtInsertPg, params)
+		if err != nil {
+			return err
+		}
+		return execer.QueryRow(stmt, args...).Scan(&mapping.ID)
+	})
+}
+
+func (s *mappingStore) Delete(ctx context.Context, mapping *core.PathMapping) error {
+	return s.db.Lock(func(execer db.Execer, binder db.Binder) error {
+		params := toParams(mapping)
+		stmt, args, err := binder.BindNamed(stmtDelete, params)
+		if err != nil {
+			return err
+		}
+		_, err = execer.Exec(stmt, args...)
+		return err
+	})
+}
+
+const queryBase = `
+SELECT
+ mapping_id
+,mapping_repo_id
+,mapping_pattern
+,mapping_pipeline
+,mapping_created
+`
+
+const queryRepo = queryBase + `
+FROM path_mappings
+WHERE mapping_repo_id = :mapping_repo_id
+ORDER BY mapping_pipeline, mapping_pattern
+`
+
+const queryKey = queryBase + `
+FROM path_mappings
+WHERE mapping_id = :mapping_id
+LIMIT 1
+`
+
+const stmtDelete = `
+DELETE FROM path_mappings
+WHERE mapping_id = :mapping_id
+`
+
+const stmtInsert = `
+INSERT INTO path_mappings (
+ mapping_repo_id
+,mapping_pattern
+,mapping_pipeline
+,mapping_created
+) VALUES (
+ :mapping_repo_id
+,:mapping_pattern
+,:mapping_pipeline
+,:mapping_created
+)
+`
+
+const stmtInsertPg = stmtInsert + `
+RETURNING mapping_id
+`
@@ -0,0 +1,104 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package mapping
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/store/repos"
+	"github.com/drone/drone/store/shared/db/dbtest"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+var noContext = context.TODO()
+
+func TestMapping(t *testing.T) {
+	conn, err := dbtest.Connect()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer func() {
+		dbtest.Reset(conn)
+		dbtest.Disconnect(conn)
+	}()
+
+	// seeds the database with a dummy repository.
+	repo := &core.Repository{UID: "1", Slug: "octocat/hello-world", Namespace: "octocat"}
+	repos := repos.New(conn)
+	if err := repos.Create(noContext, repo); err != nil {
+		t.Error(err)
+	}
+
+	store := New(conn).(*mappingStore)
+	t.Run("Create", testMappingCreate(store, repo))
+}
+
+func testMappingCreate(store *mappingStore, repo *core.Repository) func(t *testing.T) {
+	return func(t *testing.T) {
+		item := &core.PathMapping{
+			RepoID:   repo.ID,
+			Pattern:  "service-a/**",
+			Pipeline: "service-a",
+			Created:  1,
+		}
+		if err := store.Create(noContext, item); err != nil {
+			t.Error(err)
+		}
+		if item.ID == 0 {
+			t.Errorf("Want path mapping ID assigned, got %d", item.ID)
+		}
+
+		t.Run("Find", testMappingFind(store, item))
+		t.Run("List", testMappingList(store, item))
+		t.Run("Delete", testMappingDelete(store, item))
+	}
+}
+
+func testMappingFind(store *mappingStore, mapping *core.PathMapping) func(t *testing.T) {
+	return func(t *testing.T) {
+		item, err := store.Find(noContext, mapping.ID)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if diff := cmp.Diff(item, mapping); diff != "" {
+			t.Errorf(diff)
+		}
+	}
+}
+
+func testMappingList(store *mappingStore, mapping *core.PathMapping) func(t *testing.T) {
+	return func(t *testing.T) {
+		list, err := store.List(noContext, mapping.RepoID)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if got, want := len(list), 1; got != want {
+			t.Errorf("Want count %d, got %d", want, got)
+		} else if diff := cmp.Diff(list[0], mapping); diff != "" {
+			t.Errorf(diff)
+		}
+	}
+}
+
+func testMappingDelete(store *mappingStore, mapping *core.PathMapping) func(t *testing.T) {
+	return func(t *testing.T) {
+		err := store.Delete(noContext, mapping)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		_, err = store.Find(noContext, mapping.ID)
+		if got, want := sql.ErrNoRows, err; got != want {
+			t.Errorf("Want sql.ErrNoRows, got %v", got)
+		}
+	}
+}
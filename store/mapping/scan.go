@@ -0,0 +1,63 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mapping
+
+import (
+	"database/sql"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/store/shared/db"
+)
+
+// helper function converts the PathMapping structure to a
+// set of named query parameters.
+func toParams(mapping *core.PathMapping) map[string]interface{} {
+	return map[string]interface{}{
+		"mapping_id":       mapping.ID,
+		"mapping_repo_id":  mapping.RepoID,
+		"mapping_pattern":  mapping.Pattern,
+		"mapping_pipeline": mapping.Pipeline,
+		"mapping_created":  mapping.Created,
+	}
+}
+
+// helper function scans the sql.Row and copies the column
+// values to the destination object.
+func scanRow(scanner db.Scanner, dst *core.PathMapping) error {
+	return scanner.Scan(
+		&dst.ID,
+		&dst.RepoID,
+		&dst.Pattern,
+		&dst.Pipeline,
+		&dst.Created,
+	)
+}
+
+// helper function scans the sql.Row and copies the column
+// values to the destination object.
+func scanRows(rows *sql.Rows) ([]*core.PathMapping, error) {
+	defer rows.Close()
+
+	mappings := []*core.PathMapping{}
+	for rows.Next() {
+		mapping := new(core.PathMapping)
+		err := scanRow(rows, mapping)
+		if err != nil {
+			return nil, err
+		}
+		mappings = append(mappings, mapping)
+	}
+	return mappings, nil
+}
@@ -0,0 +1,243 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notify
+
+import (
+	"context"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/store/shared/db"
+	"github.com/drone/drone/store/shared/encrypt"
+)
+
+// New returns a new Notification database store.
+func New(db *db.DB, enc encrypt.Encrypter) core.NotificationStore {
+	return &notifyStore{
+		db:  db,
+		enc: enc,
+	}
+}
+
+type notifyStore struct {
+	db  *db.DB
+	enc encrypt.Encrypter
+}
+
+func (s *notifyStore) List(ctx context.Context, id int64) ([]*core.Notification, error) {
+	var out []*core.Notification
+	err := s.db.View(func(queryer db.Queryer, binder db.Binder) error {
+		params := map[string]interface{}{"notify_repo_id": id}
+		stmt, args, err := binder.BindNamed(queryRepo, params)
+		if err != nil {
+			return err
+		}
+		rows, err := queryer.Query(stmt, args...)
+		if err != nil {
+			return err
+		}
+		out, err = scanRows(s.enc, rows)
+		return err
+	})
+	return out, err
+}
+
+func (s *notifyStore) Find(ctx context.Context, id int64) (*core.Notification, error) {
+	out := &core.Notification{ID: id}
+	err := s.db.View(func(queryer db.Queryer, binder db.Binder) error {
+		params, err := toParams(s.enc, out)
+		if err != nil {
+			return err
+		}
+		query, args, err := binder.BindNamed(queryKey, params)
+		if err != nil {
+			return err
+		}
+		row := queryer.QueryRow(query, args...)
+		_, err = scanRow(s.enc, row, out)
+		return err
+	})
+	return out, err
+}
+
+func (s *notifyStore) Create(ctx context.Context, notify *core.Notification) error {
+	if s.db.Driver() == db.Postgres {
+		return s.createPostgres(ctx, notify)
+	}
+	return s.create(ctx, notify)
+}
+
+func (s *notifyStore) create(ctx context.Context, notify *core.Notification) error {
+	return s.db.Lock(func(execer db.Execer, binder db.Binder) error {
+		params, err := toParams(s.enc, notify)
+		if err != nil {
+			return err
+		}
+		stmt, args, err := binder.BindNamed(stmtInsert, params)
+		if err != nil {
+			return err
+		}
+		res, err := execer.Exec(stmt, args...)
+		if err != nil {
+			return err
+		}
+		notify.ID, err = res.LastInsertId()
+		return err
+	})
+}
+
+func (s *notifyStore) createPostgres(ctx context.Context, notify *core.Notification) error {
+	return s.db.Lock(func(execer db.Execer, binder db.Binder) error {
+		params, err := toParams(s.enc, notify)
+		if err != nil {
+			return err
+		}
+		stmt, args, err := binder.BindNamed(stmtInsertPg, params)
+		if err != nil {
+			return err
+		}
+		return execer.QueryRow(stmt, args...).Scan(&notify.ID)
+	})
+}
+
+func (s *notifyStore) Update(ctx context.Context, notify *core.Notification) error {
+	return s.db.Lock(func(execer db.Execer, binder db.Binder) error {
+		params, err := toParams(s.enc, notify)
+		if err != nil {
+			return err
+		}
+		stmt, args, err := binder.BindNamed(stmtUpdate, params)
+		if err != nil {
+			return err
+		}
+		_, err = execer.Exec(stmt, args...)
+		return err
+	})
+}
+
+func (s *notifyStore) Delete(ctx context.Context, notify *core.Notification) error {
+	return s.db.Lock(func(execer db.Execer, binder db.Binder) error {
+		params, err := toParams(s.enc, notify)
+		if err != nil {
+			return err
+		}
+		stmt, args, err := binder.BindNamed(stmtDelete, params)
+		if err != nil {
+			return err
+		}
+		_, err = execer.Exec(stmt, args...)
+		return err
+	})
+}
+
+func (s *notifyStore) Rotate(ctx context.Context) (int64, error) {
+	var stale []*core.Notification
+	err := s.db.View(func(queryer db.Queryer, binder db.Binder) error {
+		rows, err := queryer.Query(queryAll)
+		if err != nil {
+			return err
+		}
+		stale, err = scanStale(s.enc, rows)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	for i, notify := range stale {
+		if err := s.Update(ctx, notify); err != nil {
+			return int64(i), err
+		}
+	}
+	return int64(len(stale)), nil
+}
+
+const queryBase = `
+SELECT
+ notify_id
+,notify_repo_id
+,notify_kind
+,notify_endpoint
+,notify_channel
+,notify_events
+,notify_branches
+,notify_recipients
+,notify_author
+,notify_created
+,notify_updated
+`
+
+const queryAll = queryBase + `
+FROM notifications
+ORDER BY notify_id
+`
+
+const queryKey = queryBase + `
+FROM notifications
+WHERE notify_id = :notify_id
+LIMIT 1
+`
+
+const queryRepo = queryBase + `
+FROM notifications
+WHERE notify_repo_id = :notify_repo_id
+ORDER BY notify_id
+`
+
+const stmtUpdate = `
+UPDATE notifications SET
+ notify_kind = :notify_kind
+,notify_endpoint = :notify_endpoint
+,notify_channel = :notify_channel
+,notify_events = :notify_events
+,notify_branches = :notify_branches
+,notify_recipients = :notify_recipients
+,notify_author = :notify_author
+,notify_updated = :notify_updated
+WHERE notify_id = :notify_id
+`
+
+const stmtDelete = `
+DELETE FROM notifications
+WHERE notify_id = :notify_id
+`
+
+const stmtInsert = `
+INSERT INTO notifications (
+ notify_repo_id
+,notify_kind
+,notify_endpoint
+,notify_channel
+,notify_events
+,notify_branches
+,notify_recipients
+,notify_author
+,notify_created
+,notify_updated
+) VALUES (
+ :notify_repo_id
+,:notify_kind
+,:notify_endpoint
+,:notify_channel
+,:notify_events
+,:notify_branches
+,:notify_recipients
+,:notify_author
+,:notify_created
+,:notify_updated
+)
+`
+
+const stmtInsertPg = stmtInsert + `
+RETURNING notify_id
+`
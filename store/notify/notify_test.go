@@ -0,0 +1,240 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package notify
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/store/repos"
+	"github.com/drone/drone/store/shared/db/dbtest"
+	"github.com/drone/drone/store/shared/encrypt"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+var noContext = context.TODO()
+
+func TestNotify(t *testing.T) {
+	conn, err := dbtest.Connect()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer func() {
+		dbtest.Reset(conn)
+		dbtest.Disconnect(conn)
+	}()
+
+	// seeds the database with a dummy repository.
+	repo := &core.Repository{UID: "1", Slug: "octocat/hello-world"}
+	repos := repos.New(conn)
+	if err := repos.Create(noContext, repo); err != nil {
+		t.Error(err)
+	}
+
+	store := New(conn, nil).(*notifyStore)
+	store.enc, _ = encrypt.New("fb4b4d6267c8a5ce8231f8b186dbca92")
+	t.Run("Create", testNotifyCreate(store, repos, repo))
+	t.Run("Email", testNotifyEmail(store))
+}
+
+func testNotifyEmail(store *notifyStore) func(t *testing.T) {
+	return func(t *testing.T) {
+		// seeds the database with a second repository, since
+		// the first repository is deleted by the foreign key
+		// test.
+		repo := &core.Repository{UID: "2", Slug: "octocat/hello-world-2"}
+		if err := repos.New(store.db).Create(noContext, repo); err != nil {
+			t.Error(err)
+			return
+		}
+		item := &core.Notification{
+			RepoID:     repo.ID,
+			Kind:       core.NotifyEmail,
+			Recipients: []string{"team@company.com"},
+			Author:     true,
+			Events:     []string{core.StatusFailing, core.NotifyRecovered},
+		}
+		err := store.Create(noContext, item)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		result, err := store.Find(noContext, item.ID)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if diff := cmp.Diff(result, item); diff != "" {
+			t.Errorf(diff)
+		}
+	}
+}
+
+func testNotifyCreate(store *notifyStore, repos core.RepositoryStore, repo *core.Repository) func(t *testing.T) {
+	return func(t *testing.T) {
+		item := &core.Notification{
+			RepoID:   repo.ID,
+			Kind:     core.NotifySlack,
+			Endpoint: "https://hooks.slack.com/services/T0/B0/XXX",
+			Channel:  "#builds",
+			Events:   []string{core.StatusFailing, core.StatusError},
+			Branches: []string{"master", "release/*"},
+			Created:  1524251054,
+			Updated:  1524251054,
+		}
+		err := store.Create(noContext, item)
+		if err != nil {
+			t.Error(err)
+		}
+		if item.ID == 0 {
+			t.Errorf("Want notification ID assigned, got %d", item.ID)
+		}
+
+		t.Run("Find", testNotifyFind(store, item))
+		t.Run("List", testNotifyList(store, repo, item))
+		t.Run("Update", testNotifyUpdate(store, item))
+		t.Run("Rotate", testNotifyRotate(store, item))
+		t.Run("Delete", testNotifyDelete(store, item))
+		t.Run("Fkey", testNotifyForeignKey(store, repos, repo))
+	}
+}
+
+func testNotifyFind(store *notifyStore, notify *core.Notification) func(t *testing.T) {
+	return func(t *testing.T) {
+		item, err := store.Find(noContext, notify.ID)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if diff := cmp.Diff(item, notify); diff != "" {
+			t.Errorf(diff)
+		}
+	}
+}
+
+func testNotifyList(store *notifyStore, repo *core.Repository, notify *core.Notification) func(t *testing.T) {
+	return func(t *testing.T) {
+		list, err := store.List(noContext, repo.ID)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if got, want := len(list), 1; got != want {
+			t.Errorf("Want count %d, got %d", want, got)
+		} else if diff := cmp.Diff(list[0], notify); diff != "" {
+			t.Errorf(diff)
+		}
+	}
+}
+
+func testNotifyUpdate(store *notifyStore, notify *core.Notification) func(t *testing.T) {
+	return func(t *testing.T) {
+		before, err := store.Find(noContext, notify.ID)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		before.Channel = "#releases"
+		before.Events = nil
+		before.Updated = 1524251055
+		err = store.Update(noContext, before)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		after, err := store.Find(noContext, before.ID)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if diff := cmp.Diff(after, before); diff != "" {
+			t.Errorf(diff)
+		}
+	}
+}
+
+func testNotifyRotate(store *notifyStore, notify *core.Notification) func(t *testing.T) {
+	return func(t *testing.T) {
+		before, err := store.Find(noContext, notify.ID)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		store.enc, _ = encrypt.NewKeyring(
+			"0a8f63b5d2e84a5f9c7e1b3d6f2a4c8e",
+			false,
+			"fb4b4d6267c8a5ce8231f8b186dbca92",
+		)
+		n, err := store.Rotate(noContext)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if n == 0 {
+			t.Errorf("Want notifications rotated")
+		}
+		n, _ = store.Rotate(noContext)
+		if got, want := n, int64(0); got != want {
+			t.Errorf("Want %d notifications rotated, got %d", want, got)
+		}
+
+		// the previous key is no longer required once the
+		// rotation is complete.
+		store.enc, _ = encrypt.NewKeyring("0a8f63b5d2e84a5f9c7e1b3d6f2a4c8e", false)
+		after, err := store.Find(noContext, notify.ID)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if got, want := after.Endpoint, before.Endpoint; got != want {
+			t.Errorf("Want endpoint %q, got %q", want, got)
+		}
+	}
+}
+
+func testNotifyDelete(store *notifyStore, notify *core.Notification) func(t *testing.T) {
+	return func(t *testing.T) {
+		err := store.Delete(noContext, notify)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		_, err = store.Find(noContext, notify.ID)
+		if got, want := sql.ErrNoRows, err; got != want {
+			t.Errorf("Want sql.ErrNoRows, got %v", got)
+			return
+		}
+	}
+}
+
+func testNotifyForeignKey(store *notifyStore, repos core.RepositoryStore, repo *core.Repository) func(t *testing.T) {
+	return func(t *testing.T) {
+		item := &core.Notification{
+			RepoID:   repo.ID,
+			Kind:     core.NotifyDiscord,
+			Endpoint: "https://discordapp.com/api/webhooks/1/XXX",
+		}
+		store.Create(noContext, item)
+		before, _ := store.List(noContext, repo.ID)
+		if len(before) == 0 {
+			t.Errorf("Want non-empty notification list")
+			return
+		}
+
+		err := repos.Delete(noContext, repo)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		after, _ := store.List(noContext, repo.ID)
+		if len(after) != 0 {
+			t.Errorf("Want empty notification list")
+		}
+	}
+}
@@ -0,0 +1,125 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notify
+
+import (
+	"database/sql"
+	"encoding/json"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/store/shared/db"
+	"github.com/drone/drone/store/shared/encrypt"
+
+	"github.com/jmoiron/sqlx/types"
+)
+
+// helper function converts the Notification structure to a
+// set of named query parameters.
+func toParams(encrypt encrypt.Encrypter, notify *core.Notification) (map[string]interface{}, error) {
+	ciphertext, err := encrypt.Encrypt(notify.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"notify_id":         notify.ID,
+		"notify_repo_id":    notify.RepoID,
+		"notify_kind":       notify.Kind,
+		"notify_endpoint":   ciphertext,
+		"notify_channel":    notify.Channel,
+		"notify_events":     encodeSlice(notify.Events),
+		"notify_branches":   encodeSlice(notify.Branches),
+		"notify_recipients": encodeSlice(notify.Recipients),
+		"notify_author":     notify.Author,
+		"notify_created":    notify.Created,
+		"notify_updated":    notify.Updated,
+	}, nil
+}
+
+func encodeSlice(v []string) types.JSONText {
+	raw, _ := json.Marshal(v)
+	return types.JSONText(raw)
+}
+
+// helper function scans the sql.Row and copies the column
+// values to the destination object. It returns true if the
+// value is not encrypted with the current encryption key.
+func scanRow(enc encrypt.Encrypter, scanner db.Scanner, dst *core.Notification) (bool, error) {
+	var ciphertext []byte
+	eventsJSON := types.JSONText{}
+	branchesJSON := types.JSONText{}
+	recipientsJSON := types.JSONText{}
+	err := scanner.Scan(
+		&dst.ID,
+		&dst.RepoID,
+		&dst.Kind,
+		&ciphertext,
+		&dst.Channel,
+		&eventsJSON,
+		&branchesJSON,
+		&recipientsJSON,
+		&dst.Author,
+		&dst.Created,
+		&dst.Updated,
+	)
+	if err != nil {
+		return false, err
+	}
+	json.Unmarshal(eventsJSON, &dst.Events)
+	json.Unmarshal(branchesJSON, &dst.Branches)
+	json.Unmarshal(recipientsJSON, &dst.Recipients)
+	plaintext, err := enc.Decrypt(ciphertext)
+	if err != nil {
+		return false, err
+	}
+	dst.Endpoint = plaintext
+	return encrypt.Stale(enc, ciphertext), nil
+}
+
+// helper function scans the sql.Row and copies the column
+// values to the destination object.
+func scanRows(encrypt encrypt.Encrypter, rows *sql.Rows) ([]*core.Notification, error) {
+	defer rows.Close()
+
+	list := []*core.Notification{}
+	for rows.Next() {
+		notify := new(core.Notification)
+		_, err := scanRow(encrypt, rows, notify)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, notify)
+	}
+	return list, nil
+}
+
+// helper function scans the sql.Rows and returns the list of
+// notifications that are not encrypted with the current encryption
+// key.
+func scanStale(enc encrypt.Encrypter, rows *sql.Rows) ([]*core.Notification, error) {
+	defer rows.Close()
+
+	stale := []*core.Notification{}
+	for rows.Next() {
+		notify := new(core.Notification)
+		ok, err := scanRow(enc, rows, notify)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			stale = append(stale, notify)
+		}
+	}
+	return stale, nil
+}
@@ -0,0 +1,244 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package payload
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/store/shared/db"
+)
+
+// New returns a new HookPayloadStore. The store retains the
+// most recent limit payloads for each repository.
+func New(db *db.DB, limit int) core.HookPayloadStore {
+	return &payloadStore{db: db, limit: limit}
+}
+
+type payloadStore struct {
+	db    *db.DB
+	limit int
+}
+
+func (s *payloadStore) List(ctx context.Context, repo int64) ([]*core.HookPayload, error) {
+	var out []*core.HookPayload
+	err := s.db.View(func(queryer db.Queryer, binder db.Binder) error {
+		params := map[string]interface{}{
+			"payload_repo_id": repo,
+		}
+		stmt, args, err := binder.BindNamed(queryRepo, params)
+		if err != nil {
+			return err
+		}
+		rows, err := queryer.Query(stmt, args...)
+		if err != nil {
+			return err
+		}
+		out, err = scanRows(rows)
+		return err
+	})
+	return out, err
+}
+
+func (s *payloadStore) Find(ctx context.Context, id int64) (*core.HookPayload, error) {
+	out := &core.HookPayload{ID: id}
+	err := s.db.View(func(queryer db.Queryer, binder db.Binder) error {
+		params := toParams(out)
+		query, args, err := binder.BindNamed(queryKey, params)
+		if err != nil {
+			return err
+		}
+		row := queryer.QueryRow(query, args...)
+		return scanRow(row, out)
+	})
+	return out, err
+}
+
+func (s *payloadStore) Create(ctx context.Context, payload *core.HookPayload) error {
+	if payload.Created == 0 {
+		payload.Created = time.Now().Unix()
+	}
+	if payload.Updated == 0 {
+		payload.Updated = payload.Created
+	}
+	return s.db.Lock(func(execer db.Execer, binder db.Binder) error {
+		var err error
+		if s.db.Driver() == db.Postgres {
+			err = s.createPostgres(execer, binder, payload)
+		} else {
+			err = s.create(execer, binder, payload)
+		}
+		if err != nil {
+			return err
+		}
+		return s.purge(execer, binder, payload.RepoID)
+	})
+}
+
+func (s *payloadStore) create(execer db.Execer, binder db.Binder, payload *core.HookPayload) error {
+	params := toParams(payload)
+	stmt, args, err := binder.BindNamed(stmtInsert, params)
+	if err != nil {
+		return err
+	}
+	res, err := execer.Exec(stmt, args...)
+	if err != nil {
+		return err
+	}
+	payload.ID, err = res.LastInsertId()
+	return err
+}
+
+func (s *payloadStore) createPostgres(execer db.Execer, binder db.Binder, payload *core.HookPayload) error {
+	params := toParams(payload)
+	stmt, args, err := binder.BindNamed(stmtInsertPg, params)
+	if err != nil {
+		return err
+	}
+	return execer.QueryRow(stmt, args...).Scan(&payload.ID)
+}
+
+// purge deletes the oldest payloads for the repository that
+// exceed the retention limit. The cutoff is queried before
+// the delete statement because mysql cannot delete from a
+// table that is referenced in a subquery.
+func (s *payloadStore) purge(execer db.Execer, binder db.Binder, repo int64) error {
+	params := map[string]interface{}{
+		"payload_repo_id": repo,
+		"offset":          s.limit,
+	}
+	stmt, args, err := binder.BindNamed(queryCutoff, params)
+	if err != nil {
+		return err
+	}
+	var cutoff int64
+	err = execer.QueryRow(stmt, args...).Scan(&cutoff)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	params["payload_id"] = cutoff
+	stmt, args, err = binder.BindNamed(stmtPurge, params)
+	if err != nil {
+		return err
+	}
+	_, err = execer.Exec(stmt, args...)
+	return err
+}
+
+func (s *payloadStore) Update(ctx context.Context, payload *core.HookPayload) error {
+	return s.db.Lock(func(execer db.Execer, binder db.Binder) error {
+		params := toParams(payload)
+		stmt, args, err := binder.BindNamed(stmtUpdate, params)
+		if err != nil {
+			return err
+		}
+		_, err = execer.Exec(stmt, args...)
+		return err
+	})
+}
+
+const queryBase = `
+SELECT
+ payload_id
+,payload_repo_id
+,payload_event
+,payload_action
+,payload_ref
+,payload_after
+,payload_status
+,payload_message
+,payload_build
+,payload_header
+,payload_body
+,payload_created
+,payload_updated
+FROM hook_payloads
+`
+
+const queryKey = queryBase + `
+WHERE payload_id = :payload_id
+`
+
+const queryRepo = queryBase + `
+WHERE payload_repo_id = :payload_repo_id
+ORDER BY payload_id DESC
+`
+
+const queryCutoff = `
+SELECT payload_id
+FROM hook_payloads
+WHERE payload_repo_id = :payload_repo_id
+ORDER BY payload_id DESC
+LIMIT 1 OFFSET :offset
+`
+
+const stmtInsert = `
+INSERT INTO hook_payloads (
+ payload_repo_id
+,payload_event
+,payload_action
+,payload_ref
+,payload_after
+,payload_status
+,payload_message
+,payload_build
+,payload_header
+,payload_body
+,payload_created
+,payload_updated
+) VALUES (
+ :payload_repo_id
+,:payload_event
+,:payload_action
+,:payload_ref
+,:payload_after
+,:payload_status
+,:payload_message
+,:payload_build
+,:payload_header
+,:payload_body
+,:payload_created
+,:payload_updated
+)
+`
+
+const stmtInsertPg = stmtInsert + `
+RETURNING payload_id
+`
+
+const stmtUpdate = `
+UPDATE hook_payloads
+SET
+ payload_event = :payload_event
+,payload_action = :payload_action
+,payload_ref = :payload_ref
+,payload_after = :payload_after
+,payload_status = :payload_status
+,payload_message = :payload_message
+,payload_build = :payload_build
+,payload_updated = :payload_updated
+WHERE payload_id = :payload_id
+`
+
+const stmtPurge = `
+DELETE FROM hook_payloads
+WHERE payload_repo_id = :payload_repo_id
+  AND payload_id <= :payload_id
+`
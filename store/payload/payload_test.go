@@ -0,0 +1,148 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package payload
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/store/repos"
+	"github.com/drone/drone/store/shared/db/dbtest"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+var noContext = context.TODO()
+
+func TestPayload(t *testing.T) {
+	conn, err := dbtest.Connect()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer func() {
+		dbtest.Reset(conn)
+		dbtest.Disconnect(conn)
+	}()
+
+	// seed with a dummy repository
+	arepo := &core.Repository{UID: "1", Slug: "octocat/hello-world"}
+	repos := repos.New(conn)
+	repos.Create(noContext, arepo)
+
+	payloads := []*core.HookPayload{
+		{
+			RepoID:  arepo.ID,
+			Status:  core.PayloadError,
+			Message: "signature mismatch",
+			Header:  http.Header{"X-Github-Event": {"push"}},
+			Body:    `{"ref":"refs/heads/master"}`,
+		},
+		{
+			RepoID: arepo.ID,
+			Event:  core.EventPush,
+			Ref:    "refs/heads/master",
+			After:  "7fd1a60b01f91b314f59955a4e4d4e80d8edf11d",
+			Status: core.PayloadSuccess,
+			Build:  1,
+			Header: http.Header{"X-Github-Event": {"push"}},
+			Body:   `{"ref":"refs/heads/master"}`,
+		},
+		{
+			RepoID:  arepo.ID,
+			Event:   core.EventPullRequest,
+			Action:  core.ActionSync,
+			Ref:     "refs/pull/42/head",
+			After:   "553c2077f0edc3d5dc5d17262f6aa498e69d6f8e",
+			Status:  core.PayloadIgnored,
+			Message: "no build created",
+			Header:  http.Header{"X-Github-Event": {"pull_request"}},
+			Body:    `{"number":42}`,
+		},
+	}
+
+	store := New(conn, 2).(*payloadStore)
+	t.Run("Create", testPayloadCreate(store, payloads))
+	t.Run("Find", testPayloadFind(store, payloads[2]))
+	t.Run("List", testPayloadList(store, arepo, payloads))
+	t.Run("Update", testPayloadUpdate(store, payloads[2]))
+}
+
+func testPayloadCreate(store *payloadStore, payloads []*core.HookPayload) func(t *testing.T) {
+	return func(t *testing.T) {
+		for _, payload := range payloads {
+			err := store.Create(noContext, payload)
+			if err != nil {
+				t.Error(err)
+			}
+			if payload.ID == 0 {
+				t.Errorf("Want payload ID assigned, got %d", payload.ID)
+			}
+			if payload.Created == 0 {
+				t.Errorf("Want payload created timestamp assigned")
+			}
+		}
+	}
+}
+
+func testPayloadFind(store *payloadStore, payload *core.HookPayload) func(t *testing.T) {
+	return func(t *testing.T) {
+		result, err := store.Find(noContext, payload.ID)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if diff := cmp.Diff(result, payload); diff != "" {
+			t.Errorf(diff)
+		}
+	}
+}
+
+func testPayloadList(store *payloadStore, repo *core.Repository, payloads []*core.HookPayload) func(t *testing.T) {
+	return func(t *testing.T) {
+		list, err := store.List(noContext, repo.ID)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		// the oldest payload exceeds the retention limit
+		// and is purged when the newest payload is created.
+		if got, want := len(list), 2; got != want {
+			t.Errorf("Want %d payloads, got %d", want, got)
+			return
+		}
+		// payloads are ordered from newest to oldest.
+		if got, want := list[0].ID, payloads[2].ID; got != want {
+			t.Errorf("Want payload ID %d, got %d", want, got)
+		}
+		if got, want := list[1].ID, payloads[1].ID; got != want {
+			t.Errorf("Want payload ID %d, got %d", want, got)
+		}
+	}
+}
+
+func testPayloadUpdate(store *payloadStore, payload *core.HookPayload) func(t *testing.T) {
+	return func(t *testing.T) {
+		payload.Status = core.PayloadSuccess
+		payload.Message = ""
+		payload.Build = 2
+		payload.Updated = payload.Created + 60
+		err := store.Update(noContext, payload)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		result, err := store.Find(noContext, payload.ID)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if diff := cmp.Diff(result, payload); diff != "" {
+			t.Errorf(diff)
+		}
+	}
+}
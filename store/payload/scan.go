@@ -0,0 +1,90 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package payload
+
+import (
+	"database/sql"
+	"encoding/json"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/store/shared/db"
+
+	"github.com/jmoiron/sqlx/types"
+)
+
+// helper function converts the HookPayload structure to a
+// set of named query parameters.
+func toParams(payload *core.HookPayload) map[string]interface{} {
+	return map[string]interface{}{
+		"payload_id":      payload.ID,
+		"payload_repo_id": payload.RepoID,
+		"payload_event":   payload.Event,
+		"payload_action":  payload.Action,
+		"payload_ref":     payload.Ref,
+		"payload_after":   payload.After,
+		"payload_status":  payload.Status,
+		"payload_message": payload.Message,
+		"payload_build":   payload.Build,
+		"payload_header":  encodeHeader(payload.Header),
+		"payload_body":    payload.Body,
+		"payload_created": payload.Created,
+		"payload_updated": payload.Updated,
+	}
+}
+
+func encodeHeader(v map[string][]string) types.JSONText {
+	raw, _ := json.Marshal(v)
+	return types.JSONText(raw)
+}
+
+// helper function scans the sql.Row and copies the column
+// values to the destination object.
+func scanRow(scanner db.Scanner, dest *core.HookPayload) error {
+	headerJSON := types.JSONText{}
+	err := scanner.Scan(
+		&dest.ID,
+		&dest.RepoID,
+		&dest.Event,
+		&dest.Action,
+		&dest.Ref,
+		&dest.After,
+		&dest.Status,
+		&dest.Message,
+		&dest.Build,
+		&headerJSON,
+		&dest.Body,
+		&dest.Created,
+		&dest.Updated,
+	)
+	json.Unmarshal(headerJSON, &dest.Header)
+	return err
+}
+
+// helper function scans the sql.Row and copies the column
+// values to the destination object.
+func scanRows(rows *sql.Rows) ([]*core.HookPayload, error) {
+	defer rows.Close()
+
+	list := []*core.HookPayload{}
+	for rows.Next() {
+		payload := new(core.HookPayload)
+		err := scanRow(rows, payload)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, payload)
+	}
+	return list, nil
+}
@@ -111,6 +111,7 @@ SELECT
 ,perm_read
 ,perm_write
 ,perm_admin
+,perm_override
 ,perm_synced
 ,perm_created
 ,perm_updated
@@ -128,6 +129,7 @@ SELECT
 ,perm_read
 ,perm_write
 ,perm_admin
+,perm_override
 ,perm_synced
 ,perm_created
 ,perm_updated
@@ -149,6 +151,7 @@ SET
  perm_read = :perm_read
 ,perm_write = :perm_write
 ,perm_admin = :perm_admin
+,perm_override = :perm_override
 ,perm_synced = :perm_synced
 ,perm_updated = :perm_updated
 WHERE perm_user_id = :perm_user_id
@@ -162,6 +165,7 @@ INSERT INTO perms (
 ,perm_read
 ,perm_write
 ,perm_admin
+,perm_override
 ,perm_synced
 ,perm_created
 ,perm_updated
@@ -171,6 +175,7 @@ INSERT INTO perms (
 ,:perm_read
 ,:perm_write
 ,:perm_admin
+,:perm_override
 ,:perm_synced
 ,:perm_created
 ,:perm_updated
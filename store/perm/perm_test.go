@@ -109,11 +109,12 @@ func testPermList(store *permStore, user *core.User, repo *core.Repository) func
 func testPermUpdate(store *permStore, user *core.User, repo *core.Repository) func(t *testing.T) {
 	return func(t *testing.T) {
 		before := &core.Perm{
-			UserID:  user.ID,
-			RepoUID: repo.UID,
-			Read:    true,
-			Write:   true,
-			Admin:   true,
+			UserID:   user.ID,
+			RepoUID:  repo.UID,
+			Read:     true,
+			Write:    true,
+			Admin:    true,
+			Override: core.RoleAdmin,
 		}
 		err := store.Update(noContext, before)
 		if err != nil {
@@ -128,6 +129,9 @@ func testPermUpdate(store *permStore, user *core.User, repo *core.Repository) fu
 		if got, want := after.Admin, before.Admin; got != want {
 			t.Errorf("Want updated Admin %v, got %v", want, got)
 		}
+		if got, want := after.Override, before.Override; got != want {
+			t.Errorf("Want updated Override %q, got %q", want, got)
+		}
 	}
 }
 
@@ -31,6 +31,7 @@ func toParams(perm *core.Perm) map[string]interface{} {
 		"perm_read":     perm.Read,
 		"perm_write":    perm.Write,
 		"perm_admin":    perm.Admin,
+		"perm_override": perm.Override,
 		"perm_synced":   perm.Synced,
 		"perm_created":  perm.Created,
 		"perm_updated":  perm.Updated,
@@ -46,6 +47,7 @@ func scanRow(scanner db.Scanner, dst *core.Perm) error {
 		&dst.Read,
 		&dst.Write,
 		&dst.Admin,
+		&dst.Override,
 		&dst.Synced,
 		&dst.Created,
 		&dst.Updated,
@@ -63,6 +65,7 @@ func scanCollabRow(scanner db.Scanner, dst *core.Collaborator) error {
 		&dst.Read,
 		&dst.Write,
 		&dst.Admin,
+		&dst.Override,
 		&dst.Synced,
 		&dst.Created,
 		&dst.Updated,
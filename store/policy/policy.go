@@ -0,0 +1,178 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package policy
+
+import (
+	"context"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/store/shared/db"
+)
+
+// New returns a new Policy database store.
+func New(db *db.DB) core.PolicyStore {
+	return &policyStore{db}
+}
+
+type policyStore struct {
+	db *db.DB
+}
+
+func (s *policyStore) List(ctx context.Context) ([]*core.Policy, error) {
+	var out []*core.Policy
+	err := s.db.View(func(queryer db.Queryer, binder db.Binder) error {
+		rows, err := queryer.Query(queryAll)
+		if err != nil {
+			return err
+		}
+		out, err = scanRows(rows)
+		return err
+	})
+	return out, err
+}
+
+func (s *policyStore) Find(ctx context.Context, namespace string) (*core.Policy, error) {
+	out := &core.Policy{Namespace: namespace}
+	err := s.db.View(func(queryer db.Queryer, binder db.Binder) error {
+		params := toParams(out)
+		query, args, err := binder.BindNamed(queryNamespace, params)
+		if err != nil {
+			return err
+		}
+		row := queryer.QueryRow(query, args...)
+		return scanRow(row, out)
+	})
+	return out, err
+}
+
+func (s *policyStore) Create(ctx context.Context, policy *core.Policy) error {
+	if s.db.Driver() == db.Postgres {
+		return s.createPostgres(ctx, policy)
+	}
+	return s.create(ctx, policy)
+}
+
+func (s *policyStore) create(ctx context.Context, policy *core.Policy) error {
+	return s.db.Lock(func(execer db.Execer, binder db.Binder) error {
+		params := toParams(policy)
+		stmt, args, err := binder.BindNamed(stmtInsert, params)
+		if err != nil {
+			return err
+		}
+		res, err := execer.Exec(stmt, args...)
+		if err != nil {
+			return err
+		}
+		policy.ID, err = res.LastInsertId()
+		return err
+	})
+}
+
+func (s *policyStore) createPostgres(ctx context.Context, policy *core.Policy) error {
+	return s.db.Lock(func(execer db.Execer, binder db.Binder) error {
+		params := toParams(policy)
+		stmt, args, err := binder.BindNamed(stmtInsertPg, params)
+		if err != nil {
+			return err
+		}
+		return execer.QueryRow(stmt, args...).Scan(&policy.ID)
+	})
+}
+
+func (s *policyStore) Update(ctx context.Context, policy *core.Policy) error {
+	return s.db.Lock(func(execer db.Execer, binder db.Binder) error {
+		params := toParams(policy)
+		stmt, args, err := binder.BindNamed(stmtUpdate, params)
+		if err != nil {
+			return err
+		}
+		_, err = execer.Exec(stmt, args...)
+		return err
+	})
+}
+
+func (s *policyStore) Delete(ctx context.Context, policy *core.Policy) error {
+	return s.db.Lock(func(execer db.Execer, binder db.Binder) error {
+		params := toParams(policy)
+		stmt, args, err := binder.BindNamed(stmtDelete, params)
+		if err != nil {
+			return err
+		}
+		_, err = execer.Exec(stmt, args...)
+		return err
+	})
+}
+
+const queryBase = `
+SELECT
+ policy_id
+,policy_namespace
+,policy_allow_registries
+,policy_deny_images
+,policy_deny_privileged
+,policy_clone_depth
+,policy_created
+,policy_updated
+`
+
+const queryAll = queryBase + `
+FROM policies
+ORDER BY policy_namespace
+`
+
+const queryNamespace = queryBase + `
+FROM policies
+WHERE policy_namespace = :policy_namespace
+LIMIT 1
+`
+
+const stmtUpdate = `
+UPDATE policies SET
+ policy_allow_registries = :policy_allow_registries
+,policy_deny_images = :policy_deny_images
+,policy_deny_privileged = :policy_deny_privileged
+,policy_clone_depth = :policy_clone_depth
+,policy_updated = :policy_updated
+WHERE policy_id = :policy_id
+`
+
+const stmtDelete = `
+DELETE FROM policies
+WHERE policy_id = :policy_id
+`
+
+const stmtInsert = `
+INSERT INTO policies (
+ policy_namespace
+,policy_allow_registries
+,policy_deny_images
+,policy_deny_privileged
+,policy_clone_depth
+,policy_created
+,policy_updated
+) VALUES (
+ :policy_namespace
+,:policy_allow_registries
+,:policy_deny_images
+,:policy_deny_privileged
+,:policy_clone_depth
+,:policy_created
+,:policy_updated
+)
+`
+
+const stmtInsertPg = stmtInsert + `
+RETURNING policy_id
+`
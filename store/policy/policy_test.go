@@ -0,0 +1,124 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package policy
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/store/shared/db/dbtest"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+var noContext = context.TODO()
+
+func TestPolicy(t *testing.T) {
+	conn, err := dbtest.Connect()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer func() {
+		dbtest.Reset(conn)
+		dbtest.Disconnect(conn)
+	}()
+
+	store := New(conn).(*policyStore)
+	t.Run("Create", testPolicyCreate(store))
+}
+
+func testPolicyCreate(store *policyStore) func(t *testing.T) {
+	return func(t *testing.T) {
+		item := &core.Policy{
+			Namespace:       "octocat",
+			AllowRegistries: []string{"docker.io", "gcr.io"},
+			DenyImages:      []string{"*:latest"},
+			DenyPrivileged:  true,
+			CloneDepth:      50,
+		}
+		err := store.Create(noContext, item)
+		if err != nil {
+			t.Error(err)
+		}
+		if item.ID == 0 {
+			t.Errorf("Want policy ID assigned, got %d", item.ID)
+		}
+
+		t.Run("Find", testPolicyFind(store, item))
+		t.Run("List", testPolicyList(store, item))
+		t.Run("Update", testPolicyUpdate(store, item))
+		t.Run("Delete", testPolicyDelete(store, item))
+	}
+}
+
+func testPolicyFind(store *policyStore, policy *core.Policy) func(t *testing.T) {
+	return func(t *testing.T) {
+		item, err := store.Find(noContext, policy.Namespace)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if diff := cmp.Diff(item, policy); diff != "" {
+			t.Errorf(diff)
+		}
+	}
+}
+
+func testPolicyList(store *policyStore, policy *core.Policy) func(t *testing.T) {
+	return func(t *testing.T) {
+		list, err := store.List(noContext)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if got, want := len(list), 1; got != want {
+			t.Errorf("Want count %d, got %d", want, got)
+		} else if diff := cmp.Diff(list[0], policy); diff != "" {
+			t.Errorf(diff)
+		}
+	}
+}
+
+func testPolicyUpdate(store *policyStore, policy *core.Policy) func(t *testing.T) {
+	return func(t *testing.T) {
+		before, err := store.Find(noContext, policy.Namespace)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		before.DenyPrivileged = false
+		before.CloneDepth = 0
+		err = store.Update(noContext, before)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		after, err := store.Find(noContext, policy.Namespace)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if diff := cmp.Diff(after, before); diff != "" {
+			t.Errorf(diff)
+		}
+	}
+}
+
+func testPolicyDelete(store *policyStore, policy *core.Policy) func(t *testing.T) {
+	return func(t *testing.T) {
+		err := store.Delete(noContext, policy)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		_, err = store.Find(noContext, policy.Namespace)
+		if got, want := sql.ErrNoRows, err; got != want {
+			t.Errorf("Want sql.ErrNoRows, got %v", got)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package policy
+
+import (
+	"database/sql"
+	"encoding/json"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/store/shared/db"
+
+	"github.com/jmoiron/sqlx/types"
+)
+
+// helper function converts the Policy structure to a set
+// of named query parameters.
+func toParams(policy *core.Policy) map[string]interface{} {
+	return map[string]interface{}{
+		"policy_id":               policy.ID,
+		"policy_namespace":        policy.Namespace,
+		"policy_allow_registries": encodeSlice(policy.AllowRegistries),
+		"policy_deny_images":      encodeSlice(policy.DenyImages),
+		"policy_deny_privileged":  policy.DenyPrivileged,
+		"policy_clone_depth":      policy.CloneDepth,
+		"policy_created":          policy.Created,
+		"policy_updated":          policy.Updated,
+	}
+}
+
+func encodeSlice(v []string) types.JSONText {
+	raw, _ := json.Marshal(v)
+	return types.JSONText(raw)
+}
+
+// helper function scans the sql.Row and copies the column
+// values to the destination object.
+func scanRow(scanner db.Scanner, dst *core.Policy) error {
+	registriesJSON := types.JSONText{}
+	imagesJSON := types.JSONText{}
+	err := scanner.Scan(
+		&dst.ID,
+		&dst.Namespace,
+		&registriesJSON,
+		&imagesJSON,
+		&dst.DenyPrivileged,
+		&dst.CloneDepth,
+		&dst.Created,
+		&dst.Updated,
+	)
+	json.Unmarshal(registriesJSON, &dst.AllowRegistries)
+	json.Unmarshal(imagesJSON, &dst.DenyImages)
+	return err
+}
+
+// helper function scans the sql.Row and copies the column
+// values to the destination object.
+func scanRows(rows *sql.Rows) ([]*core.Policy, error) {
+	defer rows.Close()
+
+	policies := []*core.Policy{}
+	for rows.Next() {
+		policy := new(core.Policy)
+		err := scanRow(rows, policy)
+		if err != nil {
+			return nil, err
+		}
+		policies = append(policies, policy)
+	}
+	return policies, nil
+}
@@ -0,0 +1,168 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package privileged
+
+import (
+	"context"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/store/shared/db"
+)
+
+// New returns a new PrivilegedImage database store.
+func New(db *db.DB) core.PrivilegedImageStore {
+	return &privilegedStore{db}
+}
+
+type privilegedStore struct {
+	db *db.DB
+}
+
+func (s *privilegedStore) List(ctx context.Context) ([]*core.PrivilegedImage, error) {
+	var out []*core.PrivilegedImage
+	err := s.db.View(func(queryer db.Queryer, binder db.Binder) error {
+		rows, err := queryer.Query(queryAll)
+		if err != nil {
+			return err
+		}
+		out, err = scanRows(rows)
+		return err
+	})
+	return out, err
+}
+
+func (s *privilegedStore) ListNamespace(ctx context.Context, namespace string) ([]*core.PrivilegedImage, error) {
+	var out []*core.PrivilegedImage
+	err := s.db.View(func(queryer db.Queryer, binder db.Binder) error {
+		params := map[string]interface{}{"privileged_namespace": namespace}
+		stmt, args, err := binder.BindNamed(queryNamespace, params)
+		if err != nil {
+			return err
+		}
+		rows, err := queryer.Query(stmt, args...)
+		if err != nil {
+			return err
+		}
+		out, err = scanRows(rows)
+		return err
+	})
+	return out, err
+}
+
+func (s *privilegedStore) Find(ctx context.Context, id int64) (*core.PrivilegedImage, error) {
+	out := &core.PrivilegedImage{ID: id}
+	err := s.db.View(func(queryer db.Queryer, binder db.Binder) error {
+		params := toParams(out)
+		query, args, err := binder.BindNamed(queryKey, params)
+		if err != nil {
+			return err
+		}
+		row := queryer.QueryRow(query, args...)
+		return scanRow(row, out)
+	})
+	return out, err
+}
+
+func (s *privilegedStore) Create(ctx context.Context, image *core.PrivilegedImage) error {
+	if s.db.Driver() == db.Postgres {
+		return s.createPostgres(ctx, image)
+	}
+	return s.create(ctx, image)
+}
+
+func (s *privilegedStore) create(ctx context.Context, image *core.PrivilegedImage) error {
+	return s.db.Lock(func(execer db.Execer, binder db.Binder) error {
+		params := toParams(image)
+		stmt, args, err := binder.BindNamed(stmtInsert, params)
+		if err != nil {
+			return err
+		}
+		res, err := execer.Exec(stmt, args...)
+		if err != nil {
+			return err
+		}
+		image.ID, err = res.LastInsertId()
+		return err
+	})
+}
+
+func (s *privilegedStore) createPostgres(ctx context.Context, image *core.PrivilegedImage) error {
+	return s.db.Lock(func(execer db.Execer, binder db.Binder) error {
+		params := toParams(image)
+		stmt, args, err := binder.BindNamed(stmtInsertPg, params)
+		if err != nil {
+			return err
+		}
+		return execer.QueryRow(stmt, args...).Scan(&image.ID)
+	})
+}
+
+func (s *privilegedStore) Delete(ctx context.Context, image *core.PrivilegedImage) error {
+	return s.db.Lock(func(execer db.Execer, binder db.Binder) error {
+		params := toParams(image)
+		stmt, args, err := binder.BindNamed(stmtDelete, params)
+		if err != nil {
+			return err
+		}
+		_, err = execer.Exec(stmt, args...)
+		return err
+	})
+}
+
+const queryBase = `
+SELECT
+ privileged_id
+,privileged_namespace
+,privileged_image
+,privileged_created
+`
+
+const queryAll = queryBase + `
+FROM privileged_images
+ORDER BY privileged_namespace, privileged_image
+`
+
+const queryNamespace = queryBase + `
+FROM privileged_images
+WHERE privileged_namespace = :privileged_namespace
+ORDER BY privileged_image
+`
+
+const queryKey = queryBase + `
+FROM privileged_images
+WHERE privileged_id = :privileged_id
+LIMIT 1
+`
+
+const stmtDelete = `
+DELETE FROM privileged_images
+WHERE privileged_id = :privileged_id
+`
+
+const stmtInsert = `
+INSERT INTO privileged_images (
+ privileged_namespace
+,privileged_image
+,privileged_created
+) VALUES (
+ :privileged_namespace
+,:privileged_image
+,:privileged_created
+)
+`
+
+const stmtInsertPg = stmtInsert + `
+RETURNING privileged_id
+`
@@ -0,0 +1,120 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package privileged
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/store/shared/db/dbtest"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+var noContext = context.TODO()
+
+func TestPrivileged(t *testing.T) {
+	conn, err := dbtest.Connect()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer func() {
+		dbtest.Reset(conn)
+		dbtest.Disconnect(conn)
+	}()
+
+	store := New(conn).(*privilegedStore)
+	t.Run("Create", testPrivilegedCreate(store))
+}
+
+func testPrivilegedCreate(store *privilegedStore) func(t *testing.T) {
+	return func(t *testing.T) {
+		global := &core.PrivilegedImage{Image: "plugins/docker", Created: 1}
+		if err := store.Create(noContext, global); err != nil {
+			t.Error(err)
+		}
+		item := &core.PrivilegedImage{Namespace: "octocat", Image: "docker:dind", Created: 1}
+		if err := store.Create(noContext, item); err != nil {
+			t.Error(err)
+		}
+		if item.ID == 0 {
+			t.Errorf("Want privileged image ID assigned, got %d", item.ID)
+		}
+
+		t.Run("Find", testPrivilegedFind(store, item))
+		t.Run("List", testPrivilegedList(store))
+		t.Run("ListNamespace", testPrivilegedListNamespace(store, global, item))
+		t.Run("Delete", testPrivilegedDelete(store, item))
+	}
+}
+
+func testPrivilegedFind(store *privilegedStore, image *core.PrivilegedImage) func(t *testing.T) {
+	return func(t *testing.T) {
+		item, err := store.Find(noContext, image.ID)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if diff := cmp.Diff(item, image); diff != "" {
+			t.Errorf(diff)
+		}
+	}
+}
+
+func testPrivilegedList(store *privilegedStore) func(t *testing.T) {
+	return func(t *testing.T) {
+		list, err := store.List(noContext)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if got, want := len(list), 2; got != want {
+			t.Errorf("Want count %d, got %d", want, got)
+		}
+	}
+}
+
+func testPrivilegedListNamespace(store *privilegedStore, global, image *core.PrivilegedImage) func(t *testing.T) {
+	return func(t *testing.T) {
+		list, err := store.ListNamespace(noContext, "octocat")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if got, want := len(list), 1; got != want {
+			t.Errorf("Want count %d, got %d", want, got)
+		} else if diff := cmp.Diff(list[0], image); diff != "" {
+			t.Errorf(diff)
+		}
+
+		list, err = store.ListNamespace(noContext, "")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if got, want := len(list), 1; got != want {
+			t.Errorf("Want count %d, got %d", want, got)
+		} else if diff := cmp.Diff(list[0], global); diff != "" {
+			t.Errorf(diff)
+		}
+	}
+}
+
+func testPrivilegedDelete(store *privilegedStore, image *core.PrivilegedImage) func(t *testing.T) {
+	return func(t *testing.T) {
+		err := store.Delete(noContext, image)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		_, err = store.Find(noContext, image.ID)
+		if got, want := sql.ErrNoRows, err; got != want {
+			t.Errorf("Want sql.ErrNoRows, got %v", got)
+		}
+	}
+}
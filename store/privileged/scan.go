@@ -0,0 +1,61 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package privileged
+
+import (
+	"database/sql"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/store/shared/db"
+)
+
+// helper function converts the PrivilegedImage structure to
+// a set of named query parameters.
+func toParams(image *core.PrivilegedImage) map[string]interface{} {
+	return map[string]interface{}{
+		"privileged_id":        image.ID,
+		"privileged_namespace": image.Namespace,
+		"privileged_image":     image.Image,
+		"privileged_created":   image.Created,
+	}
+}
+
+// helper function scans the sql.Row and copies the column
+// values to the destination object.
+func scanRow(scanner db.Scanner, dst *core.PrivilegedImage) error {
+	return scanner.Scan(
+		&dst.ID,
+		&dst.Namespace,
+		&dst.Image,
+		&dst.Created,
+	)
+}
+
+// helper function scans the sql.Row and copies the column
+// values to the destination object.
+func scanRows(rows *sql.Rows) ([]*core.PrivilegedImage, error) {
+	defer rows.Close()
+
+	images := []*core.PrivilegedImage{}
+	for rows.Next() {
+		image := new(core.PrivilegedImage)
+		err := scanRow(rows, image)
+		if err != nil {
+			return nil, err
+		}
+		images = append(images, image)
+	}
+	return images, nil
+}
@@ -0,0 +1,259 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"context"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/store/shared/db"
+	"github.com/drone/drone/store/shared/encrypt"
+)
+
+// New returns a new Registry database store.
+func New(db *db.DB, enc encrypt.Encrypter) core.RegistryStore {
+	return &registryStore{
+		db:  db,
+		enc: enc,
+	}
+}
+
+type registryStore struct {
+	db  *db.DB
+	enc encrypt.Encrypter
+}
+
+func (s *registryStore) List(ctx context.Context, id int64) ([]*core.Registry, error) {
+	var out []*core.Registry
+	err := s.db.View(func(queryer db.Queryer, binder db.Binder) error {
+		params := map[string]interface{}{"registry_repo_id": id}
+		stmt, args, err := binder.BindNamed(queryRepo, params)
+		if err != nil {
+			return err
+		}
+		rows, err := queryer.Query(stmt, args...)
+		if err != nil {
+			return err
+		}
+		out, err = scanRows(s.enc, rows)
+		return err
+	})
+	return out, err
+}
+
+func (s *registryStore) ListNamespace(ctx context.Context, namespace string) ([]*core.Registry, error) {
+	var out []*core.Registry
+	err := s.db.View(func(queryer db.Queryer, binder db.Binder) error {
+		params := map[string]interface{}{"registry_namespace": namespace}
+		stmt, args, err := binder.BindNamed(queryNamespace, params)
+		if err != nil {
+			return err
+		}
+		rows, err := queryer.Query(stmt, args...)
+		if err != nil {
+			return err
+		}
+		out, err = scanRows(s.enc, rows)
+		return err
+	})
+	return out, err
+}
+
+func (s *registryStore) Find(ctx context.Context, id int64) (*core.Registry, error) {
+	out := &core.Registry{ID: id}
+	err := s.db.View(func(queryer db.Queryer, binder db.Binder) error {
+		params, err := toParams(s.enc, out)
+		if err != nil {
+			return err
+		}
+		query, args, err := binder.BindNamed(queryKey, params)
+		if err != nil {
+			return err
+		}
+		row := queryer.QueryRow(query, args...)
+		_, err = scanRow(s.enc, row, out)
+		return err
+	})
+	return out, err
+}
+
+func (s *registryStore) Create(ctx context.Context, registry *core.Registry) error {
+	if s.db.Driver() == db.Postgres {
+		return s.createPostgres(ctx, registry)
+	}
+	return s.create(ctx, registry)
+}
+
+func (s *registryStore) create(ctx context.Context, registry *core.Registry) error {
+	return s.db.Lock(func(execer db.Execer, binder db.Binder) error {
+		params, err := toParams(s.enc, registry)
+		if err != nil {
+			return err
+		}
+		stmt, args, err := binder.BindNamed(stmtInsert, params)
+		if err != nil {
+			return err
+		}
+		res, err := execer.Exec(stmt, args...)
+		if err != nil {
+			return err
+		}
+		registry.ID, err = res.LastInsertId()
+		return err
+	})
+}
+
+func (s *registryStore) createPostgres(ctx context.Context, registry *core.Registry) error {
+	return s.db.Lock(func(execer db.Execer, binder db.Binder) error {
+		params, err := toParams(s.enc, registry)
+		if err != nil {
+			return err
+		}
+		stmt, args, err := binder.BindNamed(stmtInsertPg, params)
+		if err != nil {
+			return err
+		}
+		return execer.QueryRow(stmt, args...).Scan(&registry.ID)
+	})
+}
+
+func (s *registryStore) Update(ctx context.Context, registry *core.Registry) error {
+	return s.db.Lock(func(execer db.Execer, binder db.Binder) error {
+		params, err := toParams(s.enc, registry)
+		if err != nil {
+			return err
+		}
+		stmt, args, err := binder.BindNamed(stmtUpdate, params)
+		if err != nil {
+			return err
+		}
+		_, err = execer.Exec(stmt, args...)
+		return err
+	})
+}
+
+func (s *registryStore) Delete(ctx context.Context, registry *core.Registry) error {
+	return s.db.Lock(func(execer db.Execer, binder db.Binder) error {
+		params, err := toParams(s.enc, registry)
+		if err != nil {
+			return err
+		}
+		stmt, args, err := binder.BindNamed(stmtDelete, params)
+		if err != nil {
+			return err
+		}
+		_, err = execer.Exec(stmt, args...)
+		return err
+	})
+}
+
+func (s *registryStore) Rotate(ctx context.Context) (int64, error) {
+	var stale []*core.Registry
+	err := s.db.View(func(queryer db.Queryer, binder db.Binder) error {
+		rows, err := queryer.Query(queryAll)
+		if err != nil {
+			return err
+		}
+		stale, err = scanStale(s.enc, rows)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	for i, registry := range stale {
+		if err := s.Update(ctx, registry); err != nil {
+			return int64(i), err
+		}
+	}
+	return int64(len(stale)), nil
+}
+
+const queryBase = `
+SELECT
+ registry_id
+,registry_repo_id
+,registry_namespace
+,registry_address
+,registry_username
+,registry_password
+,registry_policy
+,registry_created
+,registry_updated
+`
+
+const queryAll = queryBase + `
+FROM registries
+ORDER BY registry_id
+`
+
+const queryKey = queryBase + `
+FROM registries
+WHERE registry_id = :registry_id
+LIMIT 1
+`
+
+const queryRepo = queryBase + `
+FROM registries
+WHERE registry_repo_id = :registry_repo_id
+ORDER BY registry_id
+`
+
+const queryNamespace = queryBase + `
+FROM registries
+WHERE registry_namespace = :registry_namespace
+  AND registry_repo_id IS NULL
+ORDER BY registry_id
+`
+
+const stmtUpdate = `
+UPDATE registries SET
+ registry_address = :registry_address
+,registry_username = :registry_username
+,registry_password = :registry_password
+,registry_policy = :registry_policy
+,registry_updated = :registry_updated
+WHERE registry_id = :registry_id
+`
+
+const stmtDelete = `
+DELETE FROM registries
+WHERE registry_id = :registry_id
+`
+
+const stmtInsert = `
+INSERT INTO registries (
+ registry_repo_id
+,registry_namespace
+,registry_address
+,registry_username
+,registry_password
+,registry_policy
+,registry_created
+,registry_updated
+) VALUES (
+ :registry_repo_id
+,:registry_namespace
+,:registry_address
+,:registry_username
+,:registry_password
+,:registry_policy
+,:registry_created
+,:registry_updated
+)
+`
+
+const stmtInsertPg = stmtInsert + `
+RETURNING registry_id
+`
@@ -0,0 +1,244 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package registry
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/store/repos"
+	"github.com/drone/drone/store/shared/db/dbtest"
+	"github.com/drone/drone/store/shared/encrypt"
+)
+
+var noContext = context.TODO()
+
+func TestRegistry(t *testing.T) {
+	conn, err := dbtest.Connect()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer func() {
+		dbtest.Reset(conn)
+		dbtest.Disconnect(conn)
+	}()
+
+	// seeds the database with a dummy repository.
+	repo := &core.Repository{UID: "1", Slug: "octocat/hello-world", Namespace: "octocat"}
+	repos := repos.New(conn)
+	if err := repos.Create(noContext, repo); err != nil {
+		t.Error(err)
+	}
+
+	store := New(conn, nil).(*registryStore)
+	store.enc, _ = encrypt.New("fb4b4d6267c8a5ce8231f8b186dbca92")
+	t.Run("Create", testRegistryCreate(store, repos, repo))
+	t.Run("Namespace", testRegistryNamespace(store))
+}
+
+func testRegistryCreate(store *registryStore, repos core.RepositoryStore, repo *core.Repository) func(t *testing.T) {
+	return func(t *testing.T) {
+		item := &core.Registry{
+			RepoID:   repo.ID,
+			Address:  "docker.io",
+			Username: "octocat",
+			Password: "correct-horse-battery-staple",
+			Policy:   core.RegistryPull,
+		}
+		err := store.Create(noContext, item)
+		if err != nil {
+			t.Error(err)
+		}
+		if item.ID == 0 {
+			t.Errorf("Want registry ID assigned, got %d", item.ID)
+		}
+
+		t.Run("Find", testRegistryFind(store, item))
+		t.Run("List", testRegistryList(store, repo))
+		t.Run("Update", testRegistryUpdate(store, item))
+		t.Run("Rotate", testRegistryRotate(store, item))
+		t.Run("Delete", testRegistryDelete(store, item))
+		t.Run("Fkey", testRegistryForeignKey(store, repos, repo))
+	}
+}
+
+func testRegistryFind(store *registryStore, registry *core.Registry) func(t *testing.T) {
+	return func(t *testing.T) {
+		item, err := store.Find(noContext, registry.ID)
+		if err != nil {
+			t.Error(err)
+		} else {
+			t.Run("Fields", testRegistry(item))
+		}
+	}
+}
+
+func testRegistryList(store *registryStore, repo *core.Repository) func(t *testing.T) {
+	return func(t *testing.T) {
+		list, err := store.List(noContext, repo.ID)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if got, want := len(list), 1; got != want {
+			t.Errorf("Want count %d, got %d", want, got)
+		} else {
+			t.Run("Fields", testRegistry(list[0]))
+		}
+	}
+}
+
+func testRegistryUpdate(store *registryStore, registry *core.Registry) func(t *testing.T) {
+	return func(t *testing.T) {
+		before, err := store.Find(noContext, registry.ID)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		before.Policy = core.RegistryPush
+		err = store.Update(noContext, before)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		after, err := store.Find(noContext, before.ID)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if got, want := after.Policy, core.RegistryPush; got != want {
+			t.Errorf("Want registry policy %q, got %q", want, got)
+		}
+	}
+}
+
+func testRegistryRotate(store *registryStore, registry *core.Registry) func(t *testing.T) {
+	return func(t *testing.T) {
+		before, err := store.Find(noContext, registry.ID)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		store.enc, _ = encrypt.NewKeyring(
+			"0a8f63b5d2e84a5f9c7e1b3d6f2a4c8e",
+			false,
+			"fb4b4d6267c8a5ce8231f8b186dbca92",
+		)
+		n, err := store.Rotate(noContext)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if n == 0 {
+			t.Errorf("Want registry credentials rotated")
+		}
+		n, _ = store.Rotate(noContext)
+		if got, want := n, int64(0); got != want {
+			t.Errorf("Want %d registry credentials rotated, got %d", want, got)
+		}
+
+		// the previous key is no longer required once the
+		// rotation is complete.
+		store.enc, _ = encrypt.NewKeyring("0a8f63b5d2e84a5f9c7e1b3d6f2a4c8e", false)
+		after, err := store.Find(noContext, registry.ID)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if got, want := after.Password, before.Password; got != want {
+			t.Errorf("Want password %q, got %q", want, got)
+		}
+	}
+}
+
+func testRegistryDelete(store *registryStore, registry *core.Registry) func(t *testing.T) {
+	return func(t *testing.T) {
+		err := store.Delete(noContext, registry)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		_, err = store.Find(noContext, registry.ID)
+		if got, want := sql.ErrNoRows, err; got != want {
+			t.Errorf("Want sql.ErrNoRows, got %v", got)
+			return
+		}
+	}
+}
+
+func testRegistryForeignKey(store *registryStore, repos core.RepositoryStore, repo *core.Repository) func(t *testing.T) {
+	return func(t *testing.T) {
+		item := &core.Registry{
+			RepoID:   repo.ID,
+			Address:  "docker.io",
+			Username: "octocat",
+			Password: "correct-horse-battery-staple",
+			Policy:   core.RegistryPull,
+		}
+		store.Create(noContext, item)
+		before, _ := store.List(noContext, repo.ID)
+		if len(before) == 0 {
+			t.Errorf("Want non-empty registry list")
+			return
+		}
+
+		err := repos.Delete(noContext, repo)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		after, _ := store.List(noContext, repo.ID)
+		if len(after) != 0 {
+			t.Errorf("Want empty registry list")
+		}
+	}
+}
+
+func testRegistryNamespace(store *registryStore) func(t *testing.T) {
+	return func(t *testing.T) {
+		item := &core.Registry{
+			Namespace: "octocat",
+			Address:   "docker.io",
+			Username:  "octocat",
+			Password:  "correct-horse-battery-staple",
+			Policy:    core.RegistryPull,
+		}
+		err := store.Create(noContext, item)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		list, err := store.ListNamespace(noContext, "octocat")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if got, want := len(list), 1; got != want {
+			t.Errorf("Want count %d, got %d", want, got)
+			return
+		}
+		if got := list[0].RepoID; got != 0 {
+			t.Errorf("Want organization registry without repository, got %d", got)
+		}
+		t.Run("Fields", testRegistry(list[0]))
+	}
+}
+
+func testRegistry(item *core.Registry) func(t *testing.T) {
+	return func(t *testing.T) {
+		if got, want := item.Address, "docker.io"; got != want {
+			t.Errorf("Want registry address %q, got %q", want, got)
+		}
+		if got, want := item.Username, "octocat"; got != want {
+			t.Errorf("Want registry username %q, got %q", want, got)
+		}
+		if got, want := item.Password, "correct-horse-battery-staple"; got != want {
+			t.Errorf("Want registry password %q, got %q", want, got)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"database/sql"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/store/shared/db"
+	"github.com/drone/drone/store/shared/encrypt"
+)
+
+// helper function converts the Registry structure to a set
+// of named query parameters.
+func toParams(encrypt encrypt.Encrypter, registry *core.Registry) (map[string]interface{}, error) {
+	ciphertext, err := encrypt.Encrypt(registry.Password)
+	if err != nil {
+		return nil, err
+	}
+	// organization registry credentials are not associated
+	// with a repository, and are stored with a null repository
+	// identifier to satisfy the foreign key constraint.
+	repoID := sql.NullInt64{
+		Int64: registry.RepoID,
+		Valid: registry.RepoID != 0,
+	}
+	return map[string]interface{}{
+		"registry_id":        registry.ID,
+		"registry_repo_id":   repoID,
+		"registry_namespace": registry.Namespace,
+		"registry_address":   registry.Address,
+		"registry_username":  registry.Username,
+		"registry_password":  ciphertext,
+		"registry_policy":    registry.Policy,
+		"registry_created":   registry.Created,
+		"registry_updated":   registry.Updated,
+	}, nil
+}
+
+// helper function scans the sql.Row and copies the column
+// values to the destination object. It returns true if the
+// value is not encrypted with the current encryption key.
+func scanRow(enc encrypt.Encrypter, scanner db.Scanner, dst *core.Registry) (bool, error) {
+	var ciphertext []byte
+	var repoID sql.NullInt64
+	err := scanner.Scan(
+		&dst.ID,
+		&repoID,
+		&dst.Namespace,
+		&dst.Address,
+		&dst.Username,
+		&ciphertext,
+		&dst.Policy,
+		&dst.Created,
+		&dst.Updated,
+	)
+	if err != nil {
+		return false, err
+	}
+	dst.RepoID = repoID.Int64
+	plaintext, err := enc.Decrypt(ciphertext)
+	if err != nil {
+		return false, err
+	}
+	dst.Password = plaintext
+	return encrypt.Stale(enc, ciphertext), nil
+}
+
+// helper function scans the sql.Row and copies the column
+// values to the destination object.
+func scanRows(encrypt encrypt.Encrypter, rows *sql.Rows) ([]*core.Registry, error) {
+	defer rows.Close()
+
+	list := []*core.Registry{}
+	for rows.Next() {
+		registry := new(core.Registry)
+		_, err := scanRow(encrypt, rows, registry)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, registry)
+	}
+	return list, nil
+}
+
+// helper function scans the sql.Rows and returns the list of
+// registry credentials that are not encrypted with the current encryption
+// key.
+func scanStale(enc encrypt.Encrypter, rows *sql.Rows) ([]*core.Registry, error) {
+	defer rows.Close()
+
+	stale := []*core.Registry{}
+	for rows.Next() {
+		registry := new(core.Registry)
+		ok, err := scanRow(enc, rows, registry)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			stale = append(stale, registry)
+		}
+	}
+	return stale, nil
+}
@@ -17,6 +17,7 @@ package repos
 
 import (
 	"context"
+	"strings"
 
 	"github.com/drone/drone/core"
 	"github.com/drone/drone/store/shared/db"
@@ -49,6 +50,56 @@ func (s *repoStore) List(ctx context.Context, id int64) ([]*core.Repository, err
 	return out, err
 }
 
+func (s *repoStore) ListFilter(ctx context.Context, id int64, filter core.RepositoryFilter) ([]*core.Repository, error) {
+	var out []*core.Repository
+	err := s.db.View(func(queryer db.Queryer, binder db.Binder) error {
+		params := map[string]interface{}{
+			"user_id":        id,
+			"repo_query":     "",
+			"repo_namespace": filter.Namespace,
+			"repo_active":    true,
+			"repo_inactive":  !filter.Active,
+			"limit":          filter.Limit,
+			"offset":         filter.Offset,
+		}
+		if filter.Query != "" {
+			params["repo_query"] = "%" + strings.ToLower(filter.Query) + "%"
+		}
+		query, args, err := binder.BindNamed(queryPermsFilter, params)
+		if err != nil {
+			return err
+		}
+		rows, err := queryer.Query(query, args...)
+		if err != nil {
+			return err
+		}
+		out, err = scanRows(rows)
+		return err
+	})
+	return out, err
+}
+
+func (s *repoStore) ListAll(ctx context.Context, limit, offset int) ([]*core.Repository, error) {
+	var out []*core.Repository
+	err := s.db.View(func(queryer db.Queryer, binder db.Binder) error {
+		params := map[string]interface{}{
+			"limit":  limit,
+			"offset": offset,
+		}
+		query, args, err := binder.BindNamed(queryAll, params)
+		if err != nil {
+			return err
+		}
+		rows, err := queryer.Query(query, args...)
+		if err != nil {
+			return err
+		}
+		out, err = scanRows(rows)
+		return err
+	})
+	return out, err
+}
+
 func (s *repoStore) ListLatest(ctx context.Context, id int64) ([]*core.Repository, error) {
 	var out []*core.Repository
 	err := s.db.View(func(queryer db.Queryer, binder db.Binder) error {
@@ -101,6 +152,60 @@ func (s *repoStore) ListIncomplete(ctx context.Context) ([]*core.Repository, err
 	return out, err
 }
 
+func (s *repoStore) ListPolling(ctx context.Context, now int64) ([]*core.Repository, error) {
+	var out []*core.Repository
+	err := s.db.View(func(queryer db.Queryer, binder db.Binder) error {
+		params := map[string]interface{}{
+			"repo_active": true,
+			"now":         now,
+		}
+		query, args, err := binder.BindNamed(queryPolling, params)
+		if err != nil {
+			return err
+		}
+		rows, err := queryer.Query(query, args...)
+		if err != nil {
+			return err
+		}
+		out, err = scanRows(rows)
+		return err
+	})
+	return out, err
+}
+
+func (s *repoStore) ListSearch(ctx context.Context, search core.BuildSearch) ([]*core.Repository, error) {
+	var out []*core.Repository
+	err := s.db.View(func(queryer db.Queryer, binder db.Binder) error {
+		params := map[string]interface{}{
+			"user_id":        search.User,
+			"repo_namespace": search.Namespace,
+			"build_after":    "",
+			"build_author":   search.Author,
+			"build_target":   search.Branch,
+			"build_message":  "",
+			"limit":          search.Limit,
+			"offset":         search.Offset,
+		}
+		if search.Commit != "" {
+			params["build_after"] = search.Commit + "%"
+		}
+		if search.Message != "" {
+			params["build_message"] = "%" + strings.ToLower(search.Message) + "%"
+		}
+		query, args, err := binder.BindNamed(queryRepoWithBuildSearch, params)
+		if err != nil {
+			return err
+		}
+		rows, err := queryer.Query(query, args...)
+		if err != nil {
+			return err
+		}
+		out, err = scanRowsBuild(rows)
+		return err
+	})
+	return out, err
+}
+
 func (s *repoStore) Find(ctx context.Context, id int64) (*core.Repository, error) {
 	out := &core.Repository{ID: id}
 	err := s.db.View(func(queryer db.Queryer, binder db.Binder) error {
@@ -259,15 +364,28 @@ SELECT
 ,repo_counter
 ,repo_config
 ,repo_timeout
+,repo_throttle
+,repo_poll_interval
+,repo_poll_branches
+,repo_polled
 ,repo_trusted
 ,repo_protected
 ,repo_no_forks
 ,repo_no_pulls
+,repo_merge_pulls
+,repo_no_dupes
+,repo_no_mask
+,repo_skip_pattern
+,repo_summary
+,repo_summary_lines
+,repo_provider
 ,repo_synced
 ,repo_created
 ,repo_updated
 ,repo_version
 ,repo_signer
+,repo_signer_prev
+,repo_signer_expiry
 ,repo_secret
 `
 
@@ -298,6 +416,10 @@ const queryColsBulds = queryCols + `
 ,build_sender
 ,build_params
 ,build_deploy
+,build_restarted_from
+,build_triggered_by
+,build_labels
+,build_changed_files
 ,build_started
 ,build_finished
 ,build_created
@@ -315,6 +437,20 @@ FROM repos
 WHERE repo_slug = :repo_slug
 `
 
+const queryAll = queryCols + `
+FROM repos
+ORDER BY repo_id ASC
+LIMIT :limit OFFSET :offset
+`
+
+const queryPolling = queryCols + `
+FROM repos
+WHERE repo_active = :repo_active
+  AND repo_poll_interval > 0
+  AND repo_polled + repo_poll_interval <= :now
+ORDER BY repo_id ASC
+`
+
 const queryPerms = queryCols + `
 FROM repos
 INNER JOIN perms ON perms.perm_repo_uid = repos.repo_uid
@@ -322,6 +458,19 @@ WHERE perms.perm_user_id = :user_id
 ORDER BY repo_slug ASC
 `
 
+// zero value filter parameters are ignored. Inactive repositories
+// are only matched when the repo_inactive parameter is true.
+const queryPermsFilter = queryCols + `
+FROM repos
+INNER JOIN perms ON perms.perm_repo_uid = repos.repo_uid
+WHERE perms.perm_user_id = :user_id
+  AND (:repo_query = '' OR LOWER(repo_slug) LIKE :repo_query)
+  AND (:repo_namespace = '' OR repo_namespace = :repo_namespace)
+  AND (repo_active = :repo_active OR repo_active = :repo_inactive)
+ORDER BY repo_slug ASC
+LIMIT :limit OFFSET :offset
+`
+
 const stmtDelete = `
 DELETE FROM repos WHERE repo_id = :repo_id
 `
@@ -344,15 +493,28 @@ INSERT INTO repos (
 ,repo_counter
 ,repo_config
 ,repo_timeout
+,repo_throttle
+,repo_poll_interval
+,repo_poll_branches
+,repo_polled
 ,repo_trusted
 ,repo_protected
 ,repo_no_forks
 ,repo_no_pulls
+,repo_merge_pulls
+,repo_no_dupes
+,repo_no_mask
+,repo_skip_pattern
+,repo_summary
+,repo_summary_lines
+,repo_provider
 ,repo_synced
 ,repo_created
 ,repo_updated
 ,repo_version
 ,repo_signer
+,repo_signer_prev
+,repo_signer_expiry
 ,repo_secret
 ) VALUES (
  :repo_uid
@@ -371,15 +533,28 @@ INSERT INTO repos (
 ,:repo_counter
 ,:repo_config
 ,:repo_timeout
+,:repo_throttle
+,:repo_poll_interval
+,:repo_poll_branches
+,:repo_polled
 ,:repo_trusted
 ,:repo_protected
 ,:repo_no_forks
 ,:repo_no_pulls
+,:repo_merge_pulls
+,:repo_no_dupes
+,:repo_no_mask
+,:repo_skip_pattern
+,:repo_summary
+,:repo_summary_lines
+,:repo_provider
 ,:repo_synced
 ,:repo_created
 ,:repo_updated
 ,:repo_version
 ,:repo_signer
+,:repo_signer_prev
+,:repo_signer_expiry
 ,:repo_secret
 )
 `
@@ -389,7 +564,16 @@ RETURNING repo_id
 `
 
 const stmtPermInsert = `
-INSERT INTO perms VALUES (
+INSERT INTO perms (
+ perm_user_id
+,perm_repo_uid
+,perm_read
+,perm_write
+,perm_admin
+,perm_synced
+,perm_created
+,perm_updated
+) VALUES (
  :perm_user_id
 ,:perm_repo_uid
 ,:perm_read
@@ -420,13 +604,25 @@ UPDATE repos SET
 ,repo_protected = :repo_protected
 ,repo_no_forks = :repo_no_forks
 ,repo_no_pulls = :repo_no_pulls
+,repo_merge_pulls = :repo_merge_pulls
+,repo_no_dupes = :repo_no_dupes
+,repo_no_mask = :repo_no_mask
+,repo_skip_pattern = :repo_skip_pattern
+,repo_summary = :repo_summary
+,repo_summary_lines = :repo_summary_lines
 ,repo_timeout = :repo_timeout
+,repo_throttle = :repo_throttle
+,repo_poll_interval = :repo_poll_interval
+,repo_poll_branches = :repo_poll_branches
+,repo_polled = :repo_polled
 ,repo_counter = :repo_counter
 ,repo_synced = :repo_synced
 ,repo_created = :repo_created
 ,repo_updated = :repo_updated
 ,repo_version = :repo_version_new
 ,repo_signer = :repo_signer
+,repo_signer_prev = :repo_signer_prev
+,repo_signer_expiry = :repo_signer_expiry
 ,repo_secret = :repo_secret
 WHERE repo_id = :repo_id
   AND repo_version = :repo_version_old
@@ -467,6 +663,26 @@ ORDER BY build_id DESC
 LIMIT 50;
 `
 
+// zero value search parameters are ignored. The commit
+// parameter matches the commit sha prefix.
+const queryRepoWithBuildSearch = queryColsBulds + `
+FROM repos
+INNER JOIN builds ON builds.build_repo_id = repos.repo_id
+WHERE (:user_id = 0 OR EXISTS (
+    SELECT perm_repo_uid
+    FROM perms
+    WHERE perms.perm_repo_uid = repos.repo_uid
+    AND perms.perm_user_id = :user_id
+))
+  AND (:repo_namespace = '' OR repo_namespace = :repo_namespace)
+  AND (:build_after = '' OR build_after LIKE :build_after)
+  AND (:build_author = '' OR build_author = :build_author)
+  AND (:build_target = '' OR build_target = :build_target)
+  AND (:build_message = '' OR LOWER(build_message) LIKE :build_message)
+ORDER BY build_id DESC
+LIMIT :limit OFFSET :offset
+`
+
 // const queryRepoWithBuildIncompleteOld = queryColsBulds + `
 // FROM repos
 // INNER JOIN perms  ON perms.perm_repo_uid = repos.repo_uid
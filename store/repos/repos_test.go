@@ -37,12 +37,17 @@ func TestRepo(t *testing.T) {
 	t.Run("Find", testRepoFind(store))
 	t.Run("FindName", testRepoFindName(store))
 	t.Run("List", testRepoList(store))
+	t.Run("ListFilter", testRepoListFilter(store))
+	t.Run("ListAll", testRepoListAll(store))
 	t.Run("ListLatest", testRepoListLatest(store))
+	t.Run("ListSearch", testRepoListSearch(store))
 	t.Run("Update", testRepoUpdate(store))
 	t.Run("Activate", testRepoActivate(store))
+	t.Run("ListPolling", testRepoListPolling(store))
 	t.Run("Locking", testRepoLocking(store))
 	t.Run("Increment", testRepoIncrement(store))
 	t.Run("Delete", testRepoDelete(store))
+	t.Run("Provider", testRepoProvider(store))
 }
 
 func testRepoCreate(repos *repoStore) func(t *testing.T) {
@@ -196,6 +201,56 @@ func testRepoList(repos *repoStore) func(t *testing.T) {
 	}
 }
 
+func testRepoListFilter(repos *repoStore) func(t *testing.T) {
+	return func(t *testing.T) {
+		tests := []struct {
+			filter core.RepositoryFilter
+			count  int
+		}{
+			{core.RepositoryFilter{Limit: 10}, 1},
+			{core.RepositoryFilter{Query: "HELLO", Limit: 10}, 1},
+			{core.RepositoryFilter{Query: "goodbye", Limit: 10}, 0},
+			{core.RepositoryFilter{Namespace: "octocat", Limit: 10}, 1},
+			{core.RepositoryFilter{Namespace: "spaceghost", Limit: 10}, 0},
+			{core.RepositoryFilter{Limit: 10, Offset: 1}, 0},
+		}
+		for _, test := range tests {
+			list, err := repos.ListFilter(noContext, 1, test.filter)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if got, want := len(list), test.count; got != want {
+				t.Errorf("Want Repo count %d for filter %+v, got %d", want, test.filter, got)
+			}
+		}
+	}
+}
+
+func testRepoListAll(repos *repoStore) func(t *testing.T) {
+	return func(t *testing.T) {
+		list, err := repos.ListAll(noContext, 10, 0)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if got, want := len(list), 1; got != want {
+			t.Errorf("Want Repo count %d, got %d", want, got)
+			return
+		}
+		t.Run("Fields", testRepo(list[0]))
+
+		list, err = repos.ListAll(noContext, 10, 1)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if got, want := len(list), 0; got != want {
+			t.Errorf("Want Repo count %d with offset, got %d", want, got)
+		}
+	}
+}
+
 func testRepoListLatest(repos *repoStore) func(t *testing.T) {
 	return func(t *testing.T) {
 		repos, err := repos.ListLatest(noContext, 1)
@@ -213,6 +268,28 @@ func testRepoListLatest(repos *repoStore) func(t *testing.T) {
 	}
 }
 
+func testRepoListSearch(repos *repoStore) func(t *testing.T) {
+	return func(t *testing.T) {
+		search := core.BuildSearch{
+			User:      1,
+			Namespace: "octocat",
+			Commit:    "7fd1a60",
+			Author:    "octocat",
+			Branch:    "master",
+			Message:   "Update README",
+			Limit:     25,
+		}
+		repos, err := repos.ListSearch(noContext, search)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if got, want := len(repos), 0; got != want {
+			t.Errorf("Want Repo count %d, got %d", want, got)
+		}
+	}
+}
+
 func testRepoUpdate(repos *repoStore) func(t *testing.T) {
 	return func(t *testing.T) {
 		before, err := repos.FindName(noContext, "octocat", "hello-world")
@@ -223,6 +300,10 @@ func testRepoUpdate(repos *repoStore) func(t *testing.T) {
 
 		version := before.Version
 		before.Private = true
+		before.Summary = true
+		before.SummaryLines = 25
+		before.SignerPrev = "correct-horse-battery-staple"
+		before.SignerExpiry = 1583366400
 		err = repos.Update(noContext, before)
 		if err != nil {
 			t.Error(err)
@@ -239,6 +320,18 @@ func testRepoUpdate(repos *repoStore) func(t *testing.T) {
 		if got, want := before.Private, after.Private; got != want {
 			t.Errorf("Want updated Repo private %v, got %v", want, got)
 		}
+		if got, want := after.Summary, true; got != want {
+			t.Errorf("Want updated Repo summary %v, got %v", want, got)
+		}
+		if got, want := after.SummaryLines, int64(25); got != want {
+			t.Errorf("Want updated Repo summary lines %d, got %d", want, got)
+		}
+		if got, want := after.SignerPrev, before.SignerPrev; got != want {
+			t.Errorf("Want updated Repo previous signer %q, got %q", want, got)
+		}
+		if got, want := after.SignerExpiry, before.SignerExpiry; got != want {
+			t.Errorf("Want updated Repo signer expiry %d, got %d", want, got)
+		}
 	}
 }
 
@@ -266,6 +359,44 @@ func testRepoActivate(repos *repoStore) func(t *testing.T) {
 	}
 }
 
+func testRepoListPolling(repos *repoStore) func(t *testing.T) {
+	return func(t *testing.T) {
+		repo, err := repos.FindName(noContext, "octocat", "hello-world")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		repo.PollInterval = 300
+		repo.PollBranches = "master,develop"
+		repo.Polled = 1583366400
+		err = repos.Update(noContext, repo)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		list, err := repos.ListPolling(noContext, repo.Polled+299)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if got, want := len(list), 0; got != want {
+			t.Errorf("Want %d repositories before the poll interval, got %d", want, got)
+		}
+		list, err = repos.ListPolling(noContext, repo.Polled+300)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if got, want := len(list), 1; got != want {
+			t.Errorf("Want %d repositories after the poll interval, got %d", want, got)
+			return
+		}
+		if got, want := list[0].PollBranches, repo.PollBranches; got != want {
+			t.Errorf("Want poll branches %q, got %q", want, got)
+		}
+	}
+}
+
 func testRepoLocking(repos *repoStore) func(t *testing.T) {
 	return func(t *testing.T) {
 		repo, err := repos.FindName(noContext, "octocat", "hello-world")
@@ -329,6 +460,32 @@ func testRepoDelete(repos *repoStore) func(t *testing.T) {
 	}
 }
 
+func testRepoProvider(repos *repoStore) func(t *testing.T) {
+	return func(t *testing.T) {
+		repo := &core.Repository{
+			UID:       "internal~42",
+			UserID:    1,
+			Namespace: "internal~octocat",
+			Name:      "hello-world",
+			Slug:      "internal~octocat/hello-world",
+			Provider:  "internal",
+		}
+		err := repos.Create(noContext, repo)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		result, err := repos.FindName(noContext, "internal~octocat", "hello-world")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if got, want := result.Provider, "internal"; got != want {
+			t.Errorf("Want Provider %q, got %q", want, got)
+		}
+	}
+}
+
 func testRepo(repo *core.Repository) func(t *testing.T) {
 	return func(t *testing.T) {
 		if got, want := repo.UserID, int64(1); got != want {
@@ -26,33 +26,46 @@ import (
 // of named query parameters.
 func ToParams(v *core.Repository) map[string]interface{} {
 	return map[string]interface{}{
-		"repo_id":         v.ID,
-		"repo_uid":        v.UID,
-		"repo_user_id":    v.UserID,
-		"repo_namespace":  v.Namespace,
-		"repo_name":       v.Name,
-		"repo_slug":       v.Slug,
-		"repo_scm":        v.SCM,
-		"repo_clone_url":  v.HTTPURL,
-		"repo_ssh_url":    v.SSHURL,
-		"repo_html_url":   v.Link,
-		"repo_branch":     v.Branch,
-		"repo_private":    v.Private,
-		"repo_visibility": v.Visibility,
-		"repo_active":     v.Active,
-		"repo_config":     v.Config,
-		"repo_trusted":    v.Trusted,
-		"repo_protected":  v.Protected,
-		"repo_no_forks":   v.IgnoreForks,
-		"repo_no_pulls":   v.IgnorePulls,
-		"repo_timeout":    v.Timeout,
-		"repo_counter":    v.Counter,
-		"repo_synced":     v.Synced,
-		"repo_created":    v.Created,
-		"repo_updated":    v.Updated,
-		"repo_version":    v.Version,
-		"repo_signer":     v.Signer,
-		"repo_secret":     v.Secret,
+		"repo_id":            v.ID,
+		"repo_uid":           v.UID,
+		"repo_user_id":       v.UserID,
+		"repo_namespace":     v.Namespace,
+		"repo_name":          v.Name,
+		"repo_slug":          v.Slug,
+		"repo_scm":           v.SCM,
+		"repo_clone_url":     v.HTTPURL,
+		"repo_ssh_url":       v.SSHURL,
+		"repo_html_url":      v.Link,
+		"repo_branch":        v.Branch,
+		"repo_private":       v.Private,
+		"repo_visibility":    v.Visibility,
+		"repo_active":        v.Active,
+		"repo_config":        v.Config,
+		"repo_trusted":       v.Trusted,
+		"repo_protected":     v.Protected,
+		"repo_no_forks":      v.IgnoreForks,
+		"repo_no_pulls":      v.IgnorePulls,
+		"repo_merge_pulls":   v.MergePulls,
+		"repo_no_dupes":      v.IgnoreDupes,
+		"repo_no_mask":       v.NoMaskLogs,
+		"repo_skip_pattern":  v.SkipPattern,
+		"repo_summary":       v.Summary,
+		"repo_summary_lines": v.SummaryLines,
+		"repo_provider":      v.Provider,
+		"repo_timeout":       v.Timeout,
+		"repo_throttle":      v.Throttle,
+		"repo_poll_interval": v.PollInterval,
+		"repo_poll_branches": v.PollBranches,
+		"repo_polled":        v.Polled,
+		"repo_counter":       v.Counter,
+		"repo_synced":        v.Synced,
+		"repo_created":       v.Created,
+		"repo_updated":       v.Updated,
+		"repo_version":       v.Version,
+		"repo_signer":        v.Signer,
+		"repo_signer_prev":   v.SignerPrev,
+		"repo_signer_expiry": v.SignerExpiry,
+		"repo_secret":        v.Secret,
 	}
 }
 
@@ -77,15 +90,28 @@ func scanRow(scanner db.Scanner, dest *core.Repository) error {
 		&dest.Counter,
 		&dest.Config,
 		&dest.Timeout,
+		&dest.Throttle,
+		&dest.PollInterval,
+		&dest.PollBranches,
+		&dest.Polled,
 		&dest.Trusted,
 		&dest.Protected,
 		&dest.IgnoreForks,
 		&dest.IgnorePulls,
+		&dest.MergePulls,
+		&dest.IgnoreDupes,
+		&dest.NoMaskLogs,
+		&dest.SkipPattern,
+		&dest.Summary,
+		&dest.SummaryLines,
+		&dest.Provider,
 		&dest.Synced,
 		&dest.Created,
 		&dest.Updated,
 		&dest.Version,
 		&dest.Signer,
+		&dest.SignerPrev,
+		&dest.SignerExpiry,
 		&dest.Secret,
 	)
 }
@@ -129,15 +155,28 @@ func scanRowBuild(scanner db.Scanner, dest *core.Repository) error {
 		&dest.Counter,
 		&dest.Config,
 		&dest.Timeout,
+		&dest.Throttle,
+		&dest.PollInterval,
+		&dest.PollBranches,
+		&dest.Polled,
 		&dest.Trusted,
 		&dest.Protected,
 		&dest.IgnoreForks,
 		&dest.IgnorePulls,
+		&dest.MergePulls,
+		&dest.IgnoreDupes,
+		&dest.NoMaskLogs,
+		&dest.SkipPattern,
+		&dest.Summary,
+		&dest.SummaryLines,
+		&dest.Provider,
 		&dest.Synced,
 		&dest.Created,
 		&dest.Updated,
 		&dest.Version,
 		&dest.Signer,
+		&dest.SignerPrev,
+		&dest.SignerExpiry,
 		&dest.Secret,
 		// build parameters
 		&build.ID,
@@ -166,6 +205,10 @@ func scanRowBuild(scanner db.Scanner, dest *core.Repository) error {
 		&build.Sender,
 		&build.Params,
 		&build.Deploy,
+		&build.RestartedFrom,
+		&build.TriggeredBy,
+		&build.Labels,
+		&build.ChangedFiles,
 		&build.Started,
 		&build.Finished,
 		&build.Created,
@@ -25,76 +25,87 @@ import (
 )
 
 type nullBuild struct {
-	ID           sql.NullInt64
-	RepoID       sql.NullInt64
-	ConfigID     sql.NullInt64
-	Trigger      sql.NullString
-	Number       sql.NullInt64
-	Parent       sql.NullInt64
-	Status       sql.NullString
-	Error        sql.NullString
-	Event        sql.NullString
-	Action       sql.NullString
-	Link         sql.NullString
-	Timestamp    sql.NullInt64
-	Title        sql.NullString
-	Message      sql.NullString
-	Before       sql.NullString
-	After        sql.NullString
-	Ref          sql.NullString
-	Fork         sql.NullString
-	Source       sql.NullString
-	Target       sql.NullString
-	Author       sql.NullString
-	AuthorName   sql.NullString
-	AuthorEmail  sql.NullString
-	AuthorAvatar sql.NullString
-	Sender       sql.NullString
-	Params       types.JSONText
-	Deploy       sql.NullString
-	Started      sql.NullInt64
-	Finished     sql.NullInt64
-	Created      sql.NullInt64
-	Updated      sql.NullInt64
-	Version      sql.NullInt64
+	ID            sql.NullInt64
+	RepoID        sql.NullInt64
+	ConfigID      sql.NullInt64
+	Trigger       sql.NullString
+	Number        sql.NullInt64
+	Parent        sql.NullInt64
+	Status        sql.NullString
+	Error         sql.NullString
+	Event         sql.NullString
+	Action        sql.NullString
+	Link          sql.NullString
+	Timestamp     sql.NullInt64
+	Title         sql.NullString
+	Message       sql.NullString
+	Before        sql.NullString
+	After         sql.NullString
+	Ref           sql.NullString
+	Fork          sql.NullString
+	Source        sql.NullString
+	Target        sql.NullString
+	Author        sql.NullString
+	AuthorName    sql.NullString
+	AuthorEmail   sql.NullString
+	AuthorAvatar  sql.NullString
+	Sender        sql.NullString
+	Params        types.JSONText
+	Deploy        sql.NullString
+	RestartedFrom sql.NullInt64
+	TriggeredBy   sql.NullString
+	Labels        types.JSONText
+	ChangedFiles  sql.NullInt64
+	Started       sql.NullInt64
+	Finished      sql.NullInt64
+	Created       sql.NullInt64
+	Updated       sql.NullInt64
+	Version       sql.NullInt64
 }
 
 func (b *nullBuild) value() *core.Build {
 	params := map[string]string{}
 	json.Unmarshal(b.Params, &params)
 
+	var labels []string
+	json.Unmarshal(b.Labels, &labels)
+
 	build := &core.Build{
-		ID:           b.ID.Int64,
-		RepoID:       b.RepoID.Int64,
-		Trigger:      b.Trigger.String,
-		Number:       b.Number.Int64,
-		Parent:       b.Parent.Int64,
-		Status:       b.Status.String,
-		Error:        b.Error.String,
-		Event:        b.Event.String,
-		Action:       b.Action.String,
-		Link:         b.Link.String,
-		Timestamp:    b.Timestamp.Int64,
-		Title:        b.Title.String,
-		Message:      b.Message.String,
-		Before:       b.Before.String,
-		After:        b.After.String,
-		Ref:          b.Ref.String,
-		Fork:         b.Fork.String,
-		Source:       b.Source.String,
-		Target:       b.Target.String,
-		Author:       b.Author.String,
-		AuthorName:   b.AuthorName.String,
-		AuthorEmail:  b.AuthorEmail.String,
-		AuthorAvatar: b.AuthorAvatar.String,
-		Sender:       b.Sender.String,
-		Params:       params,
-		Deploy:       b.Deploy.String,
-		Started:      b.Started.Int64,
-		Finished:     b.Finished.Int64,
-		Created:      b.Created.Int64,
-		Updated:      b.Updated.Int64,
-		Version:      b.Version.Int64,
+		ID:            b.ID.Int64,
+		RepoID:        b.RepoID.Int64,
+		Trigger:       b.Trigger.String,
+		Number:        b.Number.Int64,
+		Parent:        b.Parent.Int64,
+		Status:        b.Status.String,
+		Error:         b.Error.String,
+		Event:         b.Event.String,
+		Action:        b.Action.String,
+		Link:          b.Link.String,
+		Timestamp:     b.Timestamp.Int64,
+		Title:         b.Title.String,
+		Message:       b.Message.String,
+		Before:        b.Before.String,
+		After:         b.After.String,
+		Ref:           b.Ref.String,
+		Fork:          b.Fork.String,
+		Source:        b.Source.String,
+		Target:        b.Target.String,
+		Author:        b.Author.String,
+		AuthorName:    b.AuthorName.String,
+		AuthorEmail:   b.AuthorEmail.String,
+		AuthorAvatar:  b.AuthorAvatar.String,
+		Sender:        b.Sender.String,
+		Params:        params,
+		Deploy:        b.Deploy.String,
+		RestartedFrom: b.RestartedFrom.Int64,
+		TriggeredBy:   b.TriggeredBy.String,
+		Labels:        labels,
+		ChangedFiles:  b.ChangedFiles.Int64,
+		Started:       b.Started.Int64,
+		Finished:      b.Finished.Int64,
+		Created:       b.Created.Int64,
+		Updated:       b.Updated.Int64,
+		Version:       b.Version.Int64,
 	}
 	return build
 }
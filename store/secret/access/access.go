@@ -0,0 +1,126 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package access
+
+import (
+	"context"
+	"time"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/store/shared/db"
+)
+
+// New returns a new SecretAccessStore.
+func New(db *db.DB) core.SecretAccessStore {
+	return &accessStore{db}
+}
+
+type accessStore struct {
+	db *db.DB
+}
+
+func (s *accessStore) List(ctx context.Context, id int64) ([]*core.SecretAccess, error) {
+	var out []*core.SecretAccess
+	err := s.db.View(func(queryer db.Queryer, binder db.Binder) error {
+		params := map[string]interface{}{"access_secret_id": id}
+		stmt, args, err := binder.BindNamed(querySecret, params)
+		if err != nil {
+			return err
+		}
+		rows, err := queryer.Query(stmt, args...)
+		if err != nil {
+			return err
+		}
+		out, err = scanRows(rows)
+		return err
+	})
+	return out, err
+}
+
+func (s *accessStore) Create(ctx context.Context, access *core.SecretAccess) error {
+	if access.Created == 0 {
+		access.Created = time.Now().Unix()
+	}
+	if s.db.Driver() == db.Postgres {
+		return s.createPostgres(ctx, access)
+	}
+	return s.create(ctx, access)
+}
+
+func (s *accessStore) create(ctx context.Context, access *core.SecretAccess) error {
+	return s.db.Lock(func(execer db.Execer, binder db.Binder) error {
+		params := toParams(access)
+		stmt, args, err := binder.BindNamed(stmtInsert, params)
+		if err != nil {
+			return err
+		}
+		res, err := execer.Exec(stmt, args...)
+		if err != nil {
+			return err
+		}
+		access.ID, err = res.LastInsertId()
+		return err
+	})
+}
+
+func (s *accessStore) createPostgres(ctx context.Context, access *core.SecretAccess) error {
+	return s.db.Lock(func(execer db.Execer, binder db.Binder) error {
+		params := toParams(access)
+		stmt, args, err := binder.BindNamed(stmtInsertPg, params)
+		if err != nil {
+			return err
+		}
+		return execer.QueryRow(stmt, args...).Scan(&access.ID)
+	})
+}
+
+// the number of access records returned is limited to
+// the most recent records.
+const querySecret = `
+SELECT
+ access_id
+,access_secret_id
+,access_repo_id
+,access_build_id
+,access_build_number
+,access_stage_id
+,access_created
+FROM secret_access
+WHERE access_secret_id = :access_secret_id
+ORDER BY access_id DESC
+LIMIT 100
+`
+
+const stmtInsert = `
+INSERT INTO secret_access (
+ access_secret_id
+,access_repo_id
+,access_build_id
+,access_build_number
+,access_stage_id
+,access_created
+) VALUES (
+ :access_secret_id
+,:access_repo_id
+,:access_build_id
+,:access_build_number
+,:access_stage_id
+,:access_created
+)
+`
+
+const stmtInsertPg = stmtInsert + `
+RETURNING access_id
+`
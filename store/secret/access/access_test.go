@@ -0,0 +1,67 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package access
+
+import (
+	"context"
+	"testing"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/store/shared/db/dbtest"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+var noContext = context.TODO()
+
+func TestAccess(t *testing.T) {
+	conn, err := dbtest.Connect()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer func() {
+		dbtest.Reset(conn)
+		dbtest.Disconnect(conn)
+	}()
+
+	records := []*core.SecretAccess{
+		{SecretID: 1, RepoID: 1, BuildID: 1, BuildNumber: 1, StageID: 1, Created: 1000},
+		{SecretID: 2, RepoID: 1, BuildID: 1, BuildNumber: 1, StageID: 1, Created: 1000},
+		{SecretID: 1, RepoID: 1, BuildID: 2, BuildNumber: 2, StageID: 2, Created: 2000},
+	}
+
+	store := New(conn).(*accessStore)
+	t.Run("Create", testAccessCreate(store, records))
+	t.Run("List", testAccessList(store, records))
+}
+
+func testAccessCreate(store *accessStore, records []*core.SecretAccess) func(t *testing.T) {
+	return func(t *testing.T) {
+		for _, record := range records {
+			err := store.Create(noContext, record)
+			if err != nil {
+				t.Error(err)
+			}
+			if record.ID == 0 {
+				t.Errorf("Want access ID assigned, got %d", record.ID)
+			}
+		}
+	}
+}
+
+func testAccessList(store *accessStore, records []*core.SecretAccess) func(t *testing.T) {
+	return func(t *testing.T) {
+		list, err := store.List(noContext, 1)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		want := []*core.SecretAccess{records[2], records[0]}
+		if diff := cmp.Diff(want, list); diff != "" {
+			t.Errorf(diff)
+		}
+	}
+}
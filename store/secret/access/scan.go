@@ -0,0 +1,67 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package access
+
+import (
+	"database/sql"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/store/shared/db"
+)
+
+// helper function converts the SecretAccess structure to a
+// set of named query parameters.
+func toParams(access *core.SecretAccess) map[string]interface{} {
+	return map[string]interface{}{
+		"access_id":           access.ID,
+		"access_secret_id":    access.SecretID,
+		"access_repo_id":      access.RepoID,
+		"access_build_id":     access.BuildID,
+		"access_build_number": access.BuildNumber,
+		"access_stage_id":     access.StageID,
+		"access_created":      access.Created,
+	}
+}
+
+// helper function scans the sql.Row and copies the column
+// values to the destination object.
+func scanRow(scanner db.Scanner, dest *core.SecretAccess) error {
+	return scanner.Scan(
+		&dest.ID,
+		&dest.SecretID,
+		&dest.RepoID,
+		&dest.BuildID,
+		&dest.BuildNumber,
+		&dest.StageID,
+		&dest.Created,
+	)
+}
+
+// helper function scans the sql.Row and copies the column
+// values to the destination object.
+func scanRows(rows *sql.Rows) ([]*core.SecretAccess, error) {
+	defer rows.Close()
+
+	list := []*core.SecretAccess{}
+	for rows.Next() {
+		access := new(core.SecretAccess)
+		err := scanRow(rows, access)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, access)
+	}
+	return list, nil
+}
@@ -41,8 +41,9 @@ func toParams(encrypt encrypt.Encrypter, secret *core.Secret) (map[string]interf
 }
 
 // helper function scans the sql.Row and copies the column
-// values to the destination object.
-func scanRow(encrypt encrypt.Encrypter, scanner db.Scanner, dst *core.Secret) error {
+// values to the destination object. It returns true if the
+// secret is not encrypted with the current encryption key.
+func scanRow(enc encrypt.Encrypter, scanner db.Scanner, dst *core.Secret) (bool, error) {
 	var ciphertext []byte
 	err := scanner.Scan(
 		&dst.ID,
@@ -53,29 +54,35 @@ func scanRow(encrypt encrypt.Encrypter, scanner db.Scanner, dst *core.Secret) er
 		&dst.PullRequestPush,
 	)
 	if err != nil {
-		return err
+		return false, err
 	}
-	plaintext, err := encrypt.Decrypt(ciphertext)
+	plaintext, err := enc.Decrypt(ciphertext)
 	if err != nil {
-		return err
+		return false, err
 	}
 	dst.Data = plaintext
-	return nil
+	return encrypt.Stale(enc, ciphertext), nil
 }
 
 // helper function scans the sql.Row and copies the column
-// values to the destination object.
-func scanRows(encrypt encrypt.Encrypter, rows *sql.Rows) ([]*core.Secret, error) {
+// values to the destination object. It returns the list of
+// secrets that are not encrypted with the current encryption
+// key.
+func scanRows(encrypt encrypt.Encrypter, rows *sql.Rows) ([]*core.Secret, []*core.Secret, error) {
 	defer rows.Close()
 
 	secrets := []*core.Secret{}
+	stale := []*core.Secret{}
 	for rows.Next() {
 		sec := new(core.Secret)
-		err := scanRow(encrypt, rows, sec)
+		ok, err := scanRow(encrypt, rows, sec)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
+		}
+		if ok {
+			stale = append(stale, sec)
 		}
 		secrets = append(secrets, sec)
 	}
-	return secrets, nil
+	return secrets, stale, nil
 }
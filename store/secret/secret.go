@@ -37,7 +37,7 @@ type secretStore struct {
 }
 
 func (s *secretStore) List(ctx context.Context, id int64) ([]*core.Secret, error) {
-	var out []*core.Secret
+	var out, stale []*core.Secret
 	err := s.db.View(func(queryer db.Queryer, binder db.Binder) error {
 		params := map[string]interface{}{"secret_repo_id": id}
 		stmt, args, err := binder.BindNamed(queryRepo, params)
@@ -48,14 +48,18 @@ func (s *secretStore) List(ctx context.Context, id int64) ([]*core.Secret, error
 		if err != nil {
 			return err
 		}
-		out, err = scanRows(s.enc, rows)
+		out, stale, err = scanRows(s.enc, rows)
 		return err
 	})
+	if err == nil {
+		s.migrate(ctx, stale...)
+	}
 	return out, err
 }
 
 func (s *secretStore) Find(ctx context.Context, id int64) (*core.Secret, error) {
 	out := &core.Secret{ID: id}
+	stale := false
 	err := s.db.View(func(queryer db.Queryer, binder db.Binder) error {
 		params, err := toParams(s.enc, out)
 		if err != nil {
@@ -66,13 +70,18 @@ func (s *secretStore) Find(ctx context.Context, id int64) (*core.Secret, error)
 			return err
 		}
 		row := queryer.QueryRow(query, args...)
-		return scanRow(s.enc, row, out)
+		stale, err = scanRow(s.enc, row, out)
+		return err
 	})
+	if err == nil && stale {
+		s.migrate(ctx, out)
+	}
 	return out, err
 }
 
 func (s *secretStore) FindName(ctx context.Context, id int64, name string) (*core.Secret, error) {
 	out := &core.Secret{Name: name, RepoID: id}
+	stale := false
 	err := s.db.View(func(queryer db.Queryer, binder db.Binder) error {
 		params, err := toParams(s.enc, out)
 		if err != nil {
@@ -83,8 +92,12 @@ func (s *secretStore) FindName(ctx context.Context, id int64, name string) (*cor
 			return err
 		}
 		row := queryer.QueryRow(query, args...)
-		return scanRow(s.enc, row, out)
+		stale, err = scanRow(s.enc, row, out)
+		return err
 	})
+	if err == nil && stale {
+		s.migrate(ctx, out)
+	}
 	return out, err
 }
 
@@ -158,6 +171,38 @@ func (s *secretStore) Delete(ctx context.Context, secret *core.Secret) error {
 	})
 }
 
+func (s *secretStore) Rotate(ctx context.Context) (int64, error) {
+	var stale []*core.Secret
+	err := s.db.View(func(queryer db.Queryer, binder db.Binder) error {
+		rows, err := queryer.Query(queryAll)
+		if err != nil {
+			return err
+		}
+		_, stale, err = scanRows(s.enc, rows)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	for i, secret := range stale {
+		if err := s.Update(ctx, secret); err != nil {
+			return int64(i), err
+		}
+	}
+	return int64(len(stale)), nil
+}
+
+// helper function encrypts the secrets with the current
+// encryption key. Secrets encrypted with a previous key, or
+// stored in plain text while the plain text migration is
+// enabled, are migrated lazily when read. Errors
+// are ignored, and the migration is retried on the next read.
+func (s *secretStore) migrate(ctx context.Context, secrets ...*core.Secret) {
+	for _, secret := range secrets {
+		s.Update(ctx, secret)
+	}
+}
+
 const queryBase = `
 SELECT
  secret_id
@@ -168,6 +213,11 @@ SELECT
 ,secret_pull_request_push
 `
 
+const queryAll = queryBase + `
+FROM secrets
+ORDER BY secret_id
+`
+
 const queryKey = queryBase + `
 FROM secrets
 WHERE secret_id = :secret_id
@@ -59,6 +59,7 @@ func testSecretCreate(store *secretStore, repos core.RepositoryStore, repo *core
 		t.Run("FindName", testSecretFindName(store, repo))
 		t.Run("List", testSecretList(store, repo))
 		t.Run("Update", testSecretUpdate(store, repo))
+		t.Run("Rotate", testSecretRotate(store, repo))
 		t.Run("Delete", testSecretDelete(store, repo))
 		t.Run("Fkey", testSecretForeignKey(store, repos, repo))
 	}
@@ -124,6 +125,40 @@ func testSecretUpdate(store *secretStore, repo *core.Repository) func(t *testing
 	}
 }
 
+func testSecretRotate(store *secretStore, repo *core.Repository) func(t *testing.T) {
+	return func(t *testing.T) {
+		store.enc, _ = encrypt.NewKeyring(
+			"0a8f63b5d2e84a5f9c7e1b3d6f2a4c8e",
+			false,
+			"fb4b4d6267c8a5ce8231f8b186dbca92",
+		)
+		n, err := store.Rotate(noContext)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if got, want := n, int64(1); got != want {
+			t.Errorf("Want %d secrets rotated, got %d", want, got)
+		}
+		n, _ = store.Rotate(noContext)
+		if got, want := n, int64(0); got != want {
+			t.Errorf("Want %d secrets rotated, got %d", want, got)
+		}
+
+		// the previous key is no longer required once the
+		// rotation is complete.
+		store.enc, _ = encrypt.NewKeyring("0a8f63b5d2e84a5f9c7e1b3d6f2a4c8e", false)
+		item, err := store.FindName(noContext, repo.ID, "password")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if got, want := item.Data, "correct-horse-battery-staple"; got != want {
+			t.Errorf("Want secret data %s, got %s", want, got)
+		}
+	}
+}
+
 func testSecretDelete(store *secretStore, repo *core.Repository) func(t *testing.T) {
 	return func(t *testing.T) {
 		secret, err := store.FindName(noContext, repo.ID, "password")
@@ -0,0 +1,69 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sessions
+
+import (
+	"database/sql"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/store/shared/db"
+)
+
+// helper function converts the UserSession structure to a
+// set of named query parameters.
+func toParams(session *core.UserSession) map[string]interface{} {
+	return map[string]interface{}{
+		"session_id":       session.ID,
+		"session_user_id":  session.UserID,
+		"session_hash":     session.Hash,
+		"session_address":  session.Address,
+		"session_agent":    session.Agent,
+		"session_created":  session.Created,
+		"session_accessed": session.Accessed,
+		"session_expires":  session.Expires,
+	}
+}
+
+// helper function scans the sql.Row and copies the column
+// values to the destination object.
+func scanRow(scanner db.Scanner, dest *core.UserSession) error {
+	return scanner.Scan(
+		&dest.ID,
+		&dest.UserID,
+		&dest.Hash,
+		&dest.Address,
+		&dest.Agent,
+		&dest.Created,
+		&dest.Accessed,
+		&dest.Expires,
+	)
+}
+
+// helper function scans the sql.Row and copies the column
+// values to the destination object.
+func scanRows(rows *sql.Rows) ([]*core.UserSession, error) {
+	defer rows.Close()
+
+	list := []*core.UserSession{}
+	for rows.Next() {
+		session := new(core.UserSession)
+		err := scanRow(rows, session)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, session)
+	}
+	return list, nil
+}
@@ -0,0 +1,204 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package sessions
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/store/shared/db/dbtest"
+	"github.com/drone/drone/store/user"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+var noContext = context.TODO()
+
+func TestSession(t *testing.T) {
+	conn, err := dbtest.Connect()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer func() {
+		dbtest.Reset(conn)
+		dbtest.Disconnect(conn)
+	}()
+
+	// seed with a dummy user
+	auser := &core.User{Login: "octocat"}
+	users := user.New(conn)
+	users.Create(noContext, auser)
+
+	session := &core.UserSession{
+		UserID:  auser.ID,
+		Hash:    "ulSxuA0FKjNiOFIchk18NNvC6ygSxdtKjiOAS",
+		Address: "127.0.0.1",
+		Agent:   "Mozilla/5.0",
+		Expires: time.Now().Add(time.Hour).Unix(),
+	}
+
+	store := New(conn).(*sessionStore)
+	t.Run("Create", testSessionCreate(store, session))
+	t.Run("Find", testSessionFind(store, session))
+	t.Run("FindHash", testSessionFindHash(store, session))
+	t.Run("List", testSessionList(store, session))
+	t.Run("Touch", testSessionTouch(store, session))
+	t.Run("Delete", testSessionDelete(store, session))
+	t.Run("DeleteUser", testSessionDeleteUser(store, auser))
+	t.Run("DeleteExpired", testSessionDeleteExpired(store, auser))
+}
+
+func testSessionCreate(store *sessionStore, session *core.UserSession) func(t *testing.T) {
+	return func(t *testing.T) {
+		err := store.Create(noContext, session)
+		if err != nil {
+			t.Error(err)
+		}
+		if session.ID == 0 {
+			t.Errorf("Want session ID assigned, got %d", session.ID)
+		}
+		if session.Created == 0 {
+			t.Errorf("Want session created timestamp assigned")
+		}
+		if got, want := session.Accessed, session.Created; got != want {
+			t.Errorf("Want session accessed timestamp %d, got %d", want, got)
+		}
+	}
+}
+
+func testSessionFind(store *sessionStore, session *core.UserSession) func(t *testing.T) {
+	return func(t *testing.T) {
+		got, err := store.Find(noContext, session.ID)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if diff := cmp.Diff(got, session); diff != "" {
+			t.Errorf(diff)
+		}
+	}
+}
+
+func testSessionFindHash(store *sessionStore, session *core.UserSession) func(t *testing.T) {
+	return func(t *testing.T) {
+		got, err := store.FindHash(noContext, session.Hash)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if diff := cmp.Diff(got, session); diff != "" {
+			t.Errorf(diff)
+		}
+	}
+}
+
+func testSessionList(store *sessionStore, session *core.UserSession) func(t *testing.T) {
+	return func(t *testing.T) {
+		list, err := store.List(noContext, session.UserID)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if got, want := len(list), 1; got != want {
+			t.Errorf("Want %d sessions, got %d", want, got)
+			return
+		}
+		if diff := cmp.Diff(list[0], session); diff != "" {
+			t.Errorf(diff)
+		}
+	}
+}
+
+func testSessionTouch(store *sessionStore, session *core.UserSession) func(t *testing.T) {
+	return func(t *testing.T) {
+		session.Accessed = session.Accessed + 60
+		err := store.Touch(noContext, session)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		got, err := store.Find(noContext, session.ID)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if diff := cmp.Diff(got, session); diff != "" {
+			t.Errorf(diff)
+		}
+	}
+}
+
+func testSessionDelete(store *sessionStore, session *core.UserSession) func(t *testing.T) {
+	return func(t *testing.T) {
+		err := store.Delete(noContext, session)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		_, err = store.Find(noContext, session.ID)
+		if got, want := err, sql.ErrNoRows; got != want {
+			t.Errorf("Want sql.ErrNoRows, got %v", got)
+		}
+	}
+}
+
+func testSessionDeleteUser(store *sessionStore, user *core.User) func(t *testing.T) {
+	return func(t *testing.T) {
+		for _, hash := range []string{"a", "b"} {
+			session := &core.UserSession{UserID: user.ID, Hash: hash}
+			if err := store.Create(noContext, session); err != nil {
+				t.Error(err)
+				return
+			}
+		}
+		err := store.DeleteUser(noContext, user.ID)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		list, err := store.List(noContext, user.ID)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if got, want := len(list), 0; got != want {
+			t.Errorf("Want %d sessions, got %d", want, got)
+		}
+	}
+}
+
+func testSessionDeleteExpired(store *sessionStore, user *core.User) func(t *testing.T) {
+	return func(t *testing.T) {
+		now := time.Now().Unix()
+		sessions := []*core.UserSession{
+			{UserID: user.ID, Hash: "expired", Expires: now - 60},
+			{UserID: user.ID, Hash: "active", Expires: now + 60},
+			{UserID: user.ID, Hash: "forever"},
+		}
+		for _, session := range sessions {
+			if err := store.Create(noContext, session); err != nil {
+				t.Error(err)
+				return
+			}
+		}
+		err := store.DeleteExpired(noContext, now)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		list, err := store.List(noContext, user.ID)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if got, want := len(list), 2; got != want {
+			t.Errorf("Want %d sessions, got %d", want, got)
+		}
+	}
+}
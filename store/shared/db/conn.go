@@ -11,6 +11,7 @@ import (
 
 	"github.com/jmoiron/sqlx"
 
+	"github.com/drone/drone/store/shared/migrate/cockroach"
 	"github.com/drone/drone/store/shared/migrate/mysql"
 	"github.com/drone/drone/store/shared/migrate/postgres"
 	"github.com/drone/drone/store/shared/migrate/sqlite"
@@ -18,7 +19,7 @@ import (
 
 // Connect to a database and verify with a ping.
 func Connect(driver, datasource string) (*DB, error) {
-	db, err := sql.Open(driver, datasource)
+	db, err := sql.Open(driverName(driver), datasource)
 	if err != nil {
 		return nil, err
 	}
@@ -35,6 +36,7 @@ func Connect(driver, datasource string) (*DB, error) {
 
 	var engine Driver
 	var locker Locker
+	var retries int
 	switch driver {
 	case "mysql":
 		engine = Mysql
@@ -42,18 +44,59 @@ func Connect(driver, datasource string) (*DB, error) {
 	case "postgres":
 		engine = Postgres
 		locker = &nopLocker{}
+	case "cockroach":
+		// cockroach implements the postgres dialect, and is
+		// therefore treated as postgres by the stores, with
+		// the exception of serialization errors which must
+		// be retried by the client.
+		engine = Postgres
+		locker = &nopLocker{}
+		retries = maxRetries
 	default:
 		engine = Sqlite
 		locker = &sync.RWMutex{}
 	}
 
 	return &DB{
-		conn:   sqlx.NewDb(db, driver),
-		lock:   locker,
-		driver: engine,
+		conn:    sqlx.NewDb(db, driverName(driver)),
+		lock:    locker,
+		driver:  engine,
+		retries: retries,
 	}, nil
 }
 
+// ConnectReplica connects to a read replica of the database and
+// verifies with a ping. The replica is used to execute heavy reads,
+// and is not migrated.
+func (db *DB) ConnectReplica(driver, datasource string) error {
+	conn, err := sql.Open(driverName(driver), datasource)
+	if err != nil {
+		return err
+	}
+	switch driver {
+	case "mysql":
+		conn.SetMaxIdleConns(0)
+	}
+	if err := pingDatabase(conn); err != nil {
+		conn.Close()
+		return err
+	}
+	db.replica = sqlx.NewDb(conn, driverName(driver))
+	return nil
+}
+
+// helper function returns the name of the registered sql
+// driver. Cockroach uses the postgres wire protocol and is
+// accessed using the postgres driver.
+func driverName(driver string) string {
+	switch driver {
+	case "cockroach":
+		return "postgres"
+	default:
+		return driver
+	}
+}
+
 // helper function to ping the database with backoff to ensure
 // a connection can be established before we proceed with the
 // database setup and migration.
@@ -76,6 +119,8 @@ func setupDatabase(db *sql.DB, driver string) error {
 		return mysql.Migrate(db)
 	case "postgres":
 		return postgres.Migrate(db)
+	case "cockroach":
+		return cockroach.Migrate(db)
 	default:
 		return sqlite.Migrate(db)
 	}
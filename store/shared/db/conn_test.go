@@ -3,3 +3,21 @@
 // that can be found in the LICENSE file.
 
 package db
+
+import "testing"
+
+func TestDriverName(t *testing.T) {
+	tests := []struct {
+		driver, name string
+	}{
+		{"sqlite3", "sqlite3"},
+		{"mysql", "mysql"},
+		{"postgres", "postgres"},
+		{"cockroach", "postgres"},
+	}
+	for _, test := range tests {
+		if got, want := driverName(test.driver), test.name; got != want {
+			t.Errorf("Want driver name %q for %q, got %q", want, test.driver, got)
+		}
+	}
+}
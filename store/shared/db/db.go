@@ -5,11 +5,21 @@
 package db
 
 import (
+	"context"
 	"database/sql"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 )
 
+// maxRetries is the maximum number of times a transaction is
+// retried after a serialization error.
+const maxRetries = 5
+
+// retryBackoff is the delay before a transaction is retried,
+// multiplied by the number of attempts.
+const retryBackoff = 10 * time.Millisecond
+
 // Driver defines the database driver.
 type Driver int
 
@@ -57,9 +67,19 @@ type (
 	// DB is a pool of zero or more underlying connections to
 	// the drone database.
 	DB struct {
-		conn   *sqlx.DB
-		lock   Locker
-		driver Driver
+		conn    *sqlx.DB
+		replica *sqlx.DB
+		lock    Locker
+		driver  Driver
+		retries int
+	}
+
+	// Pool defines the database connection pool settings.
+	// Zero value fields are ignored.
+	Pool struct {
+		MaxOpenConns    int
+		MaxIdleConns    int
+		ConnMaxLifetime time.Duration
 	}
 )
 
@@ -73,6 +93,21 @@ func (db *DB) View(fn func(Queryer, Binder) error) error {
 	return err
 }
 
+// ViewReplica executes a function within the context of a managed
+// read-only transaction using the read replica, if configured, or the
+// primary database. It should be used for heavy reads that tolerate
+// replication lag. Any error that is returned from the function is
+// returned from the ViewReplica() method.
+func (db *DB) ViewReplica(fn func(Queryer, Binder) error) error {
+	if db.replica == nil {
+		return db.View(fn)
+	}
+	db.lock.RLock()
+	err := fn(db.replica, db.replica)
+	db.lock.RUnlock()
+	return err
+}
+
 // Lock obtains a write lock to the database (sqlite only) and executes
 // a function. Any error that is returned from the function is returned
 // from the Lock() method.
@@ -88,10 +123,24 @@ func (db *DB) Lock(fn func(Execer, Binder) error) error {
 // transaction is committed. If an error is returned then the entire
 // transaction is rolled back. Any error that is returned from the function
 // or returned from the commit is returned from the Update() method.
+//
+// If the database reports a serialization error (cockroach only) the
+// transaction is retried, and the function may be invoked more than once.
 func (db *DB) Update(fn func(Execer, Binder) error) error {
 	db.lock.Lock()
 	defer db.lock.Unlock()
 
+	for i := 0; ; i++ {
+		err := db.update(fn)
+		if i < db.retries && isRetryable(err) {
+			time.Sleep(retryBackoff * time.Duration(i+1))
+			continue
+		}
+		return err
+	}
+}
+
+func (db *DB) update(fn func(Execer, Binder) error) error {
 	tx, err := db.conn.Begin()
 	if err != nil {
 		return err
@@ -111,7 +160,35 @@ func (db *DB) Driver() Driver {
 	return db.driver
 }
 
+// SetPool configures the connection pool of the primary database
+// and the read replica.
+func (db *DB) SetPool(pool Pool) {
+	for _, conn := range []*sqlx.DB{db.conn, db.replica} {
+		if conn == nil {
+			continue
+		}
+		if pool.MaxOpenConns != 0 {
+			conn.SetMaxOpenConns(pool.MaxOpenConns)
+		}
+		if pool.MaxIdleConns != 0 {
+			conn.SetMaxIdleConns(pool.MaxIdleConns)
+		}
+		if pool.ConnMaxLifetime != 0 {
+			conn.SetConnMaxLifetime(pool.ConnMaxLifetime)
+		}
+	}
+}
+
+// Ping verifies the connection to the primary database
+// is still alive.
+func (db *DB) Ping(ctx context.Context) error {
+	return db.conn.PingContext(ctx)
+}
+
 // Close cloes the database connection.
 func (db *DB) Close() error {
+	if db.replica != nil {
+		db.replica.Close()
+	}
 	return db.conn.Close()
 }
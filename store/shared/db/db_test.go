@@ -3,3 +3,27 @@
 // that can be found in the LICENSE file.
 
 package db
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/lib/pq"
+)
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{nil, false},
+		{errors.New("serialization failure"), false},
+		{&pq.Error{Code: "23505"}, false},
+		{&pq.Error{Code: "40001"}, true},
+	}
+	for _, test := range tests {
+		if got := isRetryable(test.err); got != test.want {
+			t.Errorf("Want retryable %v for error %v, got %v", test.want, test.err, got)
+		}
+	}
+}
@@ -33,6 +33,29 @@ func Connect() (*db.DB, error) {
 // Reset resets the database state.
 func Reset(d *db.DB) {
 	d.Lock(func(tx db.Execer, _ db.Binder) error {
+		tx.Exec("DELETE FROM leases")
+		tx.Exec("DELETE FROM deployments")
+		tx.Exec("DELETE FROM annotations")
+		tx.Exec("DELETE FROM artifacts")
+		tx.Exec("DELETE FROM coverage")
+		tx.Exec("DELETE FROM test_results")
+		tx.Exec("DELETE FROM audit_events")
+		tx.Exec("DELETE FROM secret_access")
+		tx.Exec("DELETE FROM tokens")
+		tx.Exec("DELETE FROM sessions")
+		tx.Exec("DELETE FROM hook_payloads")
+		tx.Exec("DELETE FROM webhook_deliveries")
+		tx.Exec("DELETE FROM repo_webhooks")
+		tx.Exec("DELETE FROM notifications")
+		tx.Exec("DELETE FROM registries")
+		tx.Exec("DELETE FROM policies")
+		tx.Exec("DELETE FROM privileged_images")
+		tx.Exec("DELETE FROM variables")
+		tx.Exec("DELETE FROM path_mappings")
+		tx.Exec("DELETE FROM insight_builds")
+		tx.Exec("DELETE FROM insight_durations")
+		tx.Exec("DELETE FROM insight_steps")
+		tx.Exec("DELETE FROM insight_commits")
 		tx.Exec("DELETE FROM cron")
 		tx.Exec("DELETE FROM logs")
 		tx.Exec("DELETE FROM steps")
@@ -4,9 +4,21 @@
 
 package db
 
-import "errors"
+import (
+	"errors"
+
+	"github.com/lib/pq"
+)
 
 // ErrOptimisticLock is returned by if the struct being
 // modified has a Version field and the value is not equal
 // to the current value in the database
 var ErrOptimisticLock = errors.New("Optimistic Lock Error")
+
+// isRetryable returns true if the error is a transaction
+// serialization error, reported by cockroach when concurrent
+// transactions conflict, and the transaction should be retried.
+func isRetryable(err error) bool {
+	pqerr, ok := err.(*pq.Error)
+	return ok && pqerr.Code == "40001"
+}
@@ -0,0 +1,126 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package encrypt
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+)
+
+// prefix identifies values encrypted by the keyring. The
+// prefix is followed by the key identifier and a separator.
+const prefix = "$aesgcm$"
+
+// indicates the value was encrypted with an unknown key.
+var errUnknownKey = errors.New("encryption key not found")
+
+// indicates the value could not be decrypted with any key,
+// and plain text values are not accepted.
+var errPlaintext = errors.New("cannot decrypt value")
+
+// NewKeyring provides a new database field encrypter that
+// encrypts values with the primary key, and decrypts values
+// encrypted with the primary key or any previous key. If
+// plaintext is true, values stored before encryption was
+// enabled are returned as-is, so that rows can be migrated;
+// otherwise values that cannot be decrypted are rejected.
+func NewKeyring(key string, plaintext bool, previous ...string) (Encrypter, error) {
+	if key == "" {
+		return &none{}, nil
+	}
+	primary, err := newKey(key)
+	if err != nil {
+		return nil, err
+	}
+	keys := []*keyringKey{primary}
+	for _, v := range previous {
+		if v == "" || v == key {
+			continue
+		}
+		k, err := newKey(v)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, k)
+	}
+	return &keyring{keys: keys, plaintext: plaintext}, nil
+}
+
+// Stale returns true if the ciphertext is not encrypted with
+// the primary key of the encrypter, and should be encrypted
+// again to complete the migration or key rotation.
+func Stale(enc Encrypter, ciphertext []byte) bool {
+	if k, ok := enc.(*keyring); ok {
+		return !bytes.HasPrefix(ciphertext, k.keys[0].prefix)
+	}
+	return false
+}
+
+type keyring struct {
+	keys      []*keyringKey
+	plaintext bool
+}
+
+type keyringKey struct {
+	prefix []byte
+	aesgcm *aesgcm
+}
+
+func newKey(key string) (*keyringKey, error) {
+	if len(key) != 32 {
+		return nil, errKeySize
+	}
+	block, err := aes.NewCipher([]byte(key))
+	if err != nil {
+		return nil, err
+	}
+	// the key identifier is derived from the key so that the
+	// key used to encrypt a value can be identified without
+	// revealing the key.
+	sum := sha256.Sum256([]byte(key))
+	id := hex.EncodeToString(sum[:4])
+	return &keyringKey{
+		prefix: []byte(prefix + id + "$"),
+		aesgcm: &aesgcm{block: block},
+	}, nil
+}
+
+func (e *keyring) Encrypt(plaintext string) ([]byte, error) {
+	k := e.keys[0]
+	ciphertext, err := k.aesgcm.Encrypt(plaintext)
+	if err != nil {
+		return nil, err
+	}
+	return append(append([]byte{}, k.prefix...), ciphertext...), nil
+}
+
+func (e *keyring) Decrypt(ciphertext []byte) (string, error) {
+	if bytes.HasPrefix(ciphertext, []byte(prefix)) {
+		for _, k := range e.keys {
+			if bytes.HasPrefix(ciphertext, k.prefix) {
+				return k.aesgcm.Decrypt(ciphertext[len(k.prefix):])
+			}
+		}
+		return "", errUnknownKey
+	}
+	// values encrypted before the keyring was introduced do
+	// not include the key identifier, and are decrypted with
+	// each key until authentication succeeds.
+	for _, k := range e.keys {
+		if plaintext, err := k.aesgcm.Decrypt(ciphertext); err == nil {
+			return plaintext, nil
+		}
+	}
+	// values stored before encryption was enabled are
+	// stored in plain text, and are only accepted while
+	// the migration is explicitly enabled.
+	if e.plaintext {
+		return string(ciphertext), nil
+	}
+	return "", errPlaintext
+}
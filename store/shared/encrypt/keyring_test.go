@@ -0,0 +1,101 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package encrypt
+
+import "testing"
+
+const (
+	testKeyOld = "fb4b4d6267c8a5ce8231f8b186dbca92"
+	testKeyNew = "0a8f63b5d2e84a5f9c7e1b3d6f2a4c8e"
+)
+
+func TestKeyring(t *testing.T) {
+	s := "correct-horse-batter-staple"
+	n, _ := NewKeyring(testKeyNew, false)
+	ciphertext, err := n.Encrypt(s)
+	if err != nil {
+		t.Error(err)
+	}
+	plaintext, err := n.Decrypt(ciphertext)
+	if err != nil {
+		t.Error(err)
+	}
+	if want, got := plaintext, s; got != want {
+		t.Errorf("Want plaintext %q, got %q", want, got)
+	}
+	if Stale(n, ciphertext) {
+		t.Errorf("Want ciphertext encrypted with primary key")
+	}
+}
+
+func TestKeyring_Rotate(t *testing.T) {
+	s := "correct-horse-batter-staple"
+	before, _ := NewKeyring(testKeyOld, false)
+	ciphertext, _ := before.Encrypt(s)
+
+	after, _ := NewKeyring(testKeyNew, false, testKeyOld)
+	if !Stale(after, ciphertext) {
+		t.Errorf("Want ciphertext encrypted with previous key stale")
+	}
+	plaintext, err := after.Decrypt(ciphertext)
+	if err != nil {
+		t.Error(err)
+	}
+	if want, got := plaintext, s; got != want {
+		t.Errorf("Want plaintext %q, got %q", want, got)
+	}
+
+	removed, _ := NewKeyring(testKeyNew, false)
+	if _, err := removed.Decrypt(ciphertext); err != errUnknownKey {
+		t.Errorf("Want unknown key error, got %v", err)
+	}
+}
+
+func TestKeyring_Legacy(t *testing.T) {
+	s := "correct-horse-batter-staple"
+	legacy, _ := New(testKeyOld)
+	ciphertext, _ := legacy.Encrypt(s)
+
+	n, _ := NewKeyring(testKeyNew, false, testKeyOld)
+	if !Stale(n, ciphertext) {
+		t.Errorf("Want legacy ciphertext stale")
+	}
+	plaintext, err := n.Decrypt(ciphertext)
+	if err != nil {
+		t.Error(err)
+	}
+	if want, got := plaintext, s; got != want {
+		t.Errorf("Want plaintext %q, got %q", want, got)
+	}
+}
+
+func TestKeyring_Plaintext(t *testing.T) {
+	s := "correct-horse-batter-staple"
+	n, _ := NewKeyring(testKeyNew, true)
+	if !Stale(n, []byte(s)) {
+		t.Errorf("Want plaintext stale")
+	}
+	plaintext, err := n.Decrypt([]byte(s))
+	if err != nil {
+		t.Error(err)
+	}
+	if want, got := plaintext, s; got != want {
+		t.Errorf("Want plaintext %q, got %q", want, got)
+	}
+}
+
+func TestKeyring_PlaintextDisabled(t *testing.T) {
+	n, _ := NewKeyring(testKeyNew, false)
+	if _, err := n.Decrypt([]byte("correct-horse-batter-staple")); err != errPlaintext {
+		t.Errorf("Want plaintext error, got %v", err)
+	}
+}
+
+func TestKeyring_None(t *testing.T) {
+	n, _ := NewKeyring("", false)
+	if Stale(n, []byte("correct-horse-batter-staple")) {
+		t.Errorf("Want plaintext not stale when encryption is disabled")
+	}
+}
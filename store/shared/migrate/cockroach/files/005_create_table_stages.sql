@@ -0,0 +1,40 @@
+-- name: create-table-stages
+
+CREATE TABLE IF NOT EXISTS stages (
+ stage_id          INT8 DEFAULT unique_rowid() PRIMARY KEY
+,stage_repo_id     INTEGER
+,stage_build_id    INTEGER
+,stage_number      INTEGER
+,stage_name        VARCHAR(50)
+,stage_kind        VARCHAR(50)
+,stage_type        VARCHAR(50)
+,stage_status      VARCHAR(50)
+,stage_error       VARCHAR(500)
+,stage_errignore   BOOLEAN
+,stage_exit_code   INTEGER
+,stage_limit       INTEGER
+,stage_os          VARCHAR(50)
+,stage_arch        VARCHAR(50)
+,stage_variant     VARCHAR(10)
+,stage_kernel      VARCHAR(50)
+,stage_machine     VARCHAR(500)
+,stage_started     INTEGER
+,stage_stopped     INTEGER
+,stage_created     INTEGER
+,stage_updated     INTEGER
+,stage_version     INTEGER
+,stage_on_success  BOOLEAN
+,stage_on_failure  BOOLEAN
+,stage_depends_on  TEXT
+,stage_labels      TEXT
+,UNIQUE(stage_build_id, stage_number)
+);
+
+-- name: create-index-stages-build
+
+CREATE INDEX IF NOT EXISTS ix_stages_build ON stages (stage_build_id);
+
+-- name: create-index-stages-status
+
+CREATE INDEX IF NOT EXISTS ix_build_in_progress ON stages (stage_status)
+WHERE stage_status IN ('pending', 'running');
@@ -0,0 +1,6 @@
+-- name: create-table-logs
+
+CREATE TABLE IF NOT EXISTS logs (
+ log_id    INT8 DEFAULT unique_rowid() PRIMARY KEY
+,log_data  BYTEA
+);
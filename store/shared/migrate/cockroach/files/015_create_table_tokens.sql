@@ -0,0 +1,14 @@
+-- name: create-table-tokens
+
+CREATE TABLE IF NOT EXISTS tokens (
+ token_id        INT8 DEFAULT unique_rowid() PRIMARY KEY
+,token_user_id   INTEGER
+,token_name      VARCHAR(100)
+,token_scopes    TEXT
+,token_hash      VARCHAR(100)
+,token_expires   INTEGER
+,token_created   INTEGER
+,UNIQUE(token_hash)
+,UNIQUE(token_user_id, token_name)
+,FOREIGN KEY(token_user_id) REFERENCES users(user_id) ON DELETE CASCADE
+);
@@ -0,0 +1,3 @@
+-- name: alter-table-perms-add-column-override
+
+ALTER TABLE perms ADD COLUMN perm_override VARCHAR(50) NOT NULL DEFAULT '';
@@ -0,0 +1,18 @@
+-- name: create-table-notifications
+
+CREATE TABLE IF NOT EXISTS notifications (
+ notify_id        INT8 DEFAULT unique_rowid() PRIMARY KEY
+,notify_repo_id   INTEGER
+,notify_kind      VARCHAR(50)
+,notify_endpoint  BYTEA
+,notify_channel   VARCHAR(500)
+,notify_events    VARCHAR(500)
+,notify_branches  VARCHAR(500)
+,notify_created   INTEGER
+,notify_updated   INTEGER
+,FOREIGN KEY(notify_repo_id) REFERENCES repos(repo_id) ON DELETE CASCADE
+);
+
+-- name: create-index-notifications-repo
+
+CREATE INDEX IF NOT EXISTS ix_notify_repo ON notifications (notify_repo_id);
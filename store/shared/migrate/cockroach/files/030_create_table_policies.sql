@@ -0,0 +1,13 @@
+-- name: create-table-policies
+
+CREATE TABLE IF NOT EXISTS policies (
+ policy_id               INT8 DEFAULT unique_rowid() PRIMARY KEY
+,policy_namespace        VARCHAR(250)
+,policy_allow_registries VARCHAR(2000)
+,policy_deny_images      VARCHAR(2000)
+,policy_deny_privileged  BOOLEAN
+,policy_clone_depth      INTEGER
+,policy_created          INTEGER
+,policy_updated          INTEGER
+,UNIQUE(policy_namespace)
+);
@@ -0,0 +1,38 @@
+-- name: create-table-insight-builds
+
+CREATE TABLE IF NOT EXISTS insight_builds (
+ insight_repo_id   INTEGER
+,insight_day       INTEGER
+,insight_total     INTEGER
+,insight_success   INTEGER
+,insight_failure   INTEGER
+,insight_duration  INTEGER
+,insight_wait      INTEGER
+,PRIMARY KEY(insight_repo_id, insight_day)
+,FOREIGN KEY(insight_repo_id) REFERENCES repos(repo_id) ON DELETE CASCADE
+);
+
+-- name: create-table-insight-durations
+
+CREATE TABLE IF NOT EXISTS insight_durations (
+ insight_repo_id   INTEGER
+,insight_day       INTEGER
+,insight_bucket    INTEGER
+,insight_count     INTEGER
+,PRIMARY KEY(insight_repo_id, insight_day, insight_bucket)
+,FOREIGN KEY(insight_repo_id) REFERENCES repos(repo_id) ON DELETE CASCADE
+);
+
+-- name: create-table-insight-steps
+
+CREATE TABLE IF NOT EXISTS insight_steps (
+ insight_repo_id   INTEGER
+,insight_day       INTEGER
+,insight_stage     VARCHAR(250)
+,insight_name      VARCHAR(250)
+,insight_total     INTEGER
+,insight_failure   INTEGER
+,insight_duration  INTEGER
+,PRIMARY KEY(insight_repo_id, insight_day, insight_stage, insight_name)
+,FOREIGN KEY(insight_repo_id) REFERENCES repos(repo_id) ON DELETE CASCADE
+);
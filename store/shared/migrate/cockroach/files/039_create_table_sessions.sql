@@ -0,0 +1,18 @@
+-- name: create-table-sessions
+
+CREATE TABLE IF NOT EXISTS sessions (
+ session_id        INT8 DEFAULT unique_rowid() PRIMARY KEY
+,session_user_id   INTEGER
+,session_hash      VARCHAR(100)
+,session_address   VARCHAR(100)
+,session_agent     VARCHAR(500)
+,session_created   INTEGER
+,session_accessed  INTEGER
+,session_expires   INTEGER
+,UNIQUE(session_hash)
+,FOREIGN KEY(session_user_id) REFERENCES users(user_id) ON DELETE CASCADE
+);
+
+-- name: create-index-sessions-user
+
+CREATE INDEX IF NOT EXISTS ix_sessions_user ON sessions (session_user_id);
@@ -0,0 +1,17 @@
+-- name: create-table-annotations
+
+CREATE TABLE IF NOT EXISTS annotations (
+ annotation_id        INT8 DEFAULT unique_rowid() PRIMARY KEY
+,annotation_build_id  INTEGER
+,annotation_stage_id  INTEGER
+,annotation_step_id   INTEGER
+,annotation_kind      VARCHAR(50)
+,annotation_name      VARCHAR(250)
+,annotation_value     TEXT
+,annotation_created   INTEGER
+,FOREIGN KEY(annotation_build_id) REFERENCES builds(build_id) ON DELETE CASCADE
+);
+
+-- name: create-index-annotations-build
+
+CREATE INDEX IF NOT EXISTS ix_annotations_build ON annotations (annotation_build_id);
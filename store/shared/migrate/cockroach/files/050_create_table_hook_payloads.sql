@@ -0,0 +1,22 @@
+-- name: create-table-hook-payloads
+
+CREATE TABLE IF NOT EXISTS hook_payloads (
+ payload_id       INT8 DEFAULT unique_rowid() PRIMARY KEY
+,payload_repo_id  INTEGER
+,payload_event    VARCHAR(50)
+,payload_action   VARCHAR(50)
+,payload_ref      VARCHAR(500)
+,payload_after    VARCHAR(250)
+,payload_status   VARCHAR(50)
+,payload_message  TEXT
+,payload_build    INTEGER
+,payload_header   TEXT
+,payload_body     TEXT
+,payload_created  INTEGER
+,payload_updated  INTEGER
+,FOREIGN KEY(payload_repo_id) REFERENCES repos(repo_id) ON DELETE CASCADE
+);
+
+-- name: create-index-hook-payloads-repo
+
+CREATE INDEX IF NOT EXISTS ix_hook_payloads_repo ON hook_payloads (payload_repo_id);
@@ -0,0 +1,3 @@
+-- name: alter-table-stages-add-column-timeout
+
+ALTER TABLE stages ADD COLUMN stage_timeout INTEGER NOT NULL DEFAULT 0;
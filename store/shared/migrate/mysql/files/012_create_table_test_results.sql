@@ -0,0 +1,20 @@
+-- name: create-table-test-results
+
+CREATE TABLE IF NOT EXISTS test_results (
+ test_id        INTEGER PRIMARY KEY AUTO_INCREMENT
+,test_build_id  INTEGER
+,test_stage_id  INTEGER
+,test_step_id   INTEGER
+,test_tests     INTEGER
+,test_failures  INTEGER
+,test_errors    INTEGER
+,test_skipped   INTEGER
+,test_duration  INTEGER
+,test_failed    MEDIUMTEXT
+,test_created   INTEGER
+,FOREIGN KEY(test_build_id) REFERENCES builds(build_id) ON DELETE CASCADE
+);
+
+-- name: create-index-test-results-build
+
+CREATE INDEX ix_test_results_build ON test_results (test_build_id);
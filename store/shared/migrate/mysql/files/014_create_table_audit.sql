@@ -0,0 +1,19 @@
+-- name: create-table-audit
+
+CREATE TABLE IF NOT EXISTS audit_events (
+ audit_id        INTEGER PRIMARY KEY AUTO_INCREMENT
+,audit_repo_id   INTEGER
+,audit_actor     VARCHAR(250)
+,audit_action    VARCHAR(50)
+,audit_target    VARCHAR(500)
+,audit_ip        VARCHAR(50)
+,audit_created   INTEGER
+);
+
+-- name: create-index-audit-repo
+
+CREATE INDEX ix_audit_repo ON audit_events (audit_repo_id);
+
+-- name: create-index-audit-actor
+
+CREATE INDEX ix_audit_actor ON audit_events (audit_actor);
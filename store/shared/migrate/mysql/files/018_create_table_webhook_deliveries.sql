@@ -0,0 +1,19 @@
+-- name: create-table-webhook-deliveries
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+ delivery_id        INTEGER PRIMARY KEY AUTO_INCREMENT
+,delivery_endpoint  VARCHAR(500)
+,delivery_event     VARCHAR(50)
+,delivery_action    VARCHAR(50)
+,delivery_payload   MEDIUMTEXT
+,delivery_status    VARCHAR(50)
+,delivery_attempts  INTEGER
+,delivery_history   MEDIUMTEXT
+,delivery_next      INTEGER
+,delivery_created   INTEGER
+,delivery_updated   INTEGER
+);
+
+-- name: create-index-webhook-deliveries-status
+
+CREATE INDEX ix_delivery_status ON webhook_deliveries (delivery_status, delivery_next);
@@ -0,0 +1,15 @@
+-- name: create-table-repo-webhooks
+
+CREATE TABLE IF NOT EXISTS repo_webhooks (
+ webhook_id        INTEGER PRIMARY KEY AUTO_INCREMENT
+,webhook_repo_id   INTEGER
+,webhook_endpoint  VARCHAR(500)
+,webhook_secret    BLOB
+,webhook_created   INTEGER
+,webhook_updated   INTEGER
+,FOREIGN KEY(webhook_repo_id) REFERENCES repos(repo_id) ON DELETE CASCADE
+);
+
+-- name: create-index-repo-webhooks-repo
+
+CREATE INDEX ix_webhook_repo ON repo_webhooks (webhook_repo_id);
@@ -0,0 +1,18 @@
+-- name: create-table-notifications
+
+CREATE TABLE IF NOT EXISTS notifications (
+ notify_id        INTEGER PRIMARY KEY AUTO_INCREMENT
+,notify_repo_id   INTEGER
+,notify_kind      VARCHAR(50)
+,notify_endpoint  BLOB
+,notify_channel   VARCHAR(500)
+,notify_events    VARCHAR(500)
+,notify_branches  VARCHAR(500)
+,notify_created   INTEGER
+,notify_updated   INTEGER
+,FOREIGN KEY(notify_repo_id) REFERENCES repos(repo_id) ON DELETE CASCADE
+);
+
+-- name: create-index-notifications-repo
+
+CREATE INDEX ix_notify_repo ON notifications (notify_repo_id);
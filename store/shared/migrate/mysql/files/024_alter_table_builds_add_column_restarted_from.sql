@@ -0,0 +1,7 @@
+-- name: alter-table-builds-add-column-restarted-from
+
+ALTER TABLE builds ADD COLUMN build_restarted_from INTEGER NOT NULL DEFAULT 0;
+
+-- name: alter-table-builds-add-column-triggered-by
+
+ALTER TABLE builds ADD COLUMN build_triggered_by VARCHAR(250) NOT NULL DEFAULT '';
@@ -0,0 +1,7 @@
+-- name: create-index-builds-after
+
+CREATE INDEX ix_build_after ON builds (build_after);
+
+-- name: create-index-builds-target
+
+CREATE INDEX ix_build_target ON builds (build_target);
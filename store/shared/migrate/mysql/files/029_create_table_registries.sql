@@ -0,0 +1,22 @@
+-- name: create-table-registries
+
+CREATE TABLE IF NOT EXISTS registries (
+ registry_id        INTEGER PRIMARY KEY AUTO_INCREMENT
+,registry_repo_id   INTEGER
+,registry_namespace VARCHAR(500)
+,registry_address   VARCHAR(500)
+,registry_username  VARCHAR(500)
+,registry_password  BLOB
+,registry_policy    VARCHAR(500)
+,registry_created   INTEGER
+,registry_updated   INTEGER
+,FOREIGN KEY(registry_repo_id) REFERENCES repos(repo_id) ON DELETE CASCADE
+);
+
+-- name: create-index-registries-repo
+
+CREATE INDEX ix_registry_repo ON registries (registry_repo_id);
+
+-- name: create-index-registries-namespace
+
+CREATE INDEX ix_registry_namespace ON registries (registry_namespace);
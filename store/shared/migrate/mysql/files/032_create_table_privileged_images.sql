@@ -0,0 +1,9 @@
+-- name: create-table-privileged-images
+
+CREATE TABLE IF NOT EXISTS privileged_images (
+ privileged_id        INTEGER PRIMARY KEY AUTO_INCREMENT
+,privileged_namespace VARCHAR(250)
+,privileged_image     VARCHAR(250)
+,privileged_created   INTEGER
+,UNIQUE(privileged_namespace, privileged_image)
+);
@@ -0,0 +1,3 @@
+-- name: alter-table-repos-add-column-skip-pattern
+
+ALTER TABLE repos ADD COLUMN repo_skip_pattern VARCHAR(500) NOT NULL DEFAULT '';
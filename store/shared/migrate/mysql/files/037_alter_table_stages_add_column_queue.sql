@@ -0,0 +1,11 @@
+-- name: alter-table-stages-add-column-queued
+
+ALTER TABLE stages ADD COLUMN stage_queued INTEGER NOT NULL DEFAULT 0;
+
+-- name: alter-table-stages-add-column-dispatched
+
+ALTER TABLE stages ADD COLUMN stage_dispatched INTEGER NOT NULL DEFAULT 0;
+
+-- name: alter-table-stages-add-column-accepted
+
+ALTER TABLE stages ADD COLUMN stage_accepted INTEGER NOT NULL DEFAULT 0;
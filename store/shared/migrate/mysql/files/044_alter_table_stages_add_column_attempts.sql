@@ -0,0 +1,3 @@
+-- name: alter-table-stages-add-column-attempts
+
+ALTER TABLE stages ADD COLUMN stage_attempts INTEGER NOT NULL DEFAULT 0;
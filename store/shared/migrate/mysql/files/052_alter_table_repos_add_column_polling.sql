@@ -0,0 +1,11 @@
+-- name: alter-table-repos-add-column-poll-interval
+
+ALTER TABLE repos ADD COLUMN repo_poll_interval INTEGER NOT NULL DEFAULT 0;
+
+-- name: alter-table-repos-add-column-poll-branches
+
+ALTER TABLE repos ADD COLUMN repo_poll_branches VARCHAR(500) NOT NULL DEFAULT '';
+
+-- name: alter-table-repos-add-column-polled
+
+ALTER TABLE repos ADD COLUMN repo_polled INTEGER NOT NULL DEFAULT 0;
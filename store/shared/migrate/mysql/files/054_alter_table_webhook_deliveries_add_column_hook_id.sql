@@ -0,0 +1,3 @@
+-- name: alter-table-webhook-deliveries-add-column-hook-id
+
+ALTER TABLE webhook_deliveries ADD COLUMN delivery_hook_id INTEGER NOT NULL DEFAULT 0;
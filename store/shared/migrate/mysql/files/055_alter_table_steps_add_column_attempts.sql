@@ -0,0 +1,3 @@
+-- name: alter-table-steps-add-column-attempts
+
+ALTER TABLE steps ADD COLUMN step_attempts INTEGER NOT NULL DEFAULT 0;
@@ -112,6 +112,326 @@ var migrations = []struct {
 		name: "create-table-nodes",
 		stmt: createTableNodes,
 	},
+	{
+		name: "create-table-artifacts",
+		stmt: createTableArtifacts,
+	},
+	{
+		name: "create-index-artifacts-build",
+		stmt: createIndexArtifactsBuild,
+	},
+	{
+		name: "create-table-test-results",
+		stmt: createTableTestResults,
+	},
+	{
+		name: "create-index-test-results-build",
+		stmt: createIndexTestResultsBuild,
+	},
+	{
+		name: "create-table-coverage",
+		stmt: createTableCoverage,
+	},
+	{
+		name: "create-index-coverage-build",
+		stmt: createIndexCoverageBuild,
+	},
+	{
+		name: "create-index-coverage-repo-ref",
+		stmt: createIndexCoverageRepoRef,
+	},
+	{
+		name: "create-table-audit",
+		stmt: createTableAudit,
+	},
+	{
+		name: "create-index-audit-repo",
+		stmt: createIndexAuditRepo,
+	},
+	{
+		name: "create-index-audit-actor",
+		stmt: createIndexAuditActor,
+	},
+	{
+		name: "create-table-tokens",
+		stmt: createTableTokens,
+	},
+	{
+		name: "alter-table-perms-add-column-override",
+		stmt: alterTablePermsAddColumnOverride,
+	},
+	{
+		name: "alter-table-repos-add-column-throttle",
+		stmt: alterTableReposAddColumnThrottle,
+	},
+	{
+		name: "alter-table-stages-add-column-limit-repo",
+		stmt: alterTableStagesAddColumnLimitRepo,
+	},
+	{
+		name: "create-table-webhook-deliveries",
+		stmt: createTableWebhookDeliveries,
+	},
+	{
+		name: "create-index-webhook-deliveries-status",
+		stmt: createIndexWebhookDeliveriesStatus,
+	},
+	{
+		name: "create-table-repo-webhooks",
+		stmt: createTableRepoWebhooks,
+	},
+	{
+		name: "create-index-repo-webhooks-repo",
+		stmt: createIndexRepoWebhooksRepo,
+	},
+	{
+		name: "create-table-notifications",
+		stmt: createTableNotifications,
+	},
+	{
+		name: "create-index-notifications-repo",
+		stmt: createIndexNotificationsRepo,
+	},
+	{
+		name: "alter-table-notifications-add-column-recipients",
+		stmt: alterTableNotificationsAddColumnRecipients,
+	},
+	{
+		name: "alter-table-notifications-add-column-author",
+		stmt: alterTableNotificationsAddColumnAuthor,
+	},
+	{
+		name: "alter-table-repos-add-column-merge-pulls",
+		stmt: alterTableReposAddColumnMergePulls,
+	},
+	{
+		name: "alter-table-cron-add-column-rebuild",
+		stmt: alterTableCronAddColumnRebuild,
+	},
+	{
+		name: "alter-table-builds-add-column-restarted-from",
+		stmt: alterTableBuildsAddColumnRestartedFrom,
+	},
+	{
+		name: "alter-table-builds-add-column-triggered-by",
+		stmt: alterTableBuildsAddColumnTriggeredBy,
+	},
+	{
+		name: "create-index-builds-repo-status",
+		stmt: createIndexBuildsRepoStatus,
+	},
+	{
+		name: "create-index-builds-repo-event",
+		stmt: createIndexBuildsRepoEvent,
+	},
+	{
+		name: "create-index-builds-repo-target",
+		stmt: createIndexBuildsRepoTarget,
+	},
+	{
+		name: "create-index-builds-repo-created",
+		stmt: createIndexBuildsRepoCreated,
+	},
+	{
+		name: "create-index-builds-after",
+		stmt: createIndexBuildsAfter,
+	},
+	{
+		name: "create-index-builds-target",
+		stmt: createIndexBuildsTarget,
+	},
+	{
+		name: "create-table-secret-access",
+		stmt: createTableSecretAccess,
+	},
+	{
+		name: "create-index-secret-access-secret",
+		stmt: createIndexSecretAccessSecret,
+	},
+	{
+		name: "alter-table-repos-add-column-no-mask",
+		stmt: alterTableReposAddColumnNoMask,
+	},
+	{
+		name: "create-table-registries",
+		stmt: createTableRegistries,
+	},
+	{
+		name: "create-index-registries-repo",
+		stmt: createIndexRegistriesRepo,
+	},
+	{
+		name: "create-index-registries-namespace",
+		stmt: createIndexRegistriesNamespace,
+	},
+	{
+		name: "create-table-policies",
+		stmt: createTablePolicies,
+	},
+	{
+		name: "alter-table-stages-add-column-elevated",
+		stmt: alterTableStagesAddColumnElevated,
+	},
+	{
+		name: "create-table-privileged-images",
+		stmt: createTablePrivilegedImages,
+	},
+	{
+		name: "create-table-variables",
+		stmt: createTableVariables,
+	},
+	{
+		name: "create-index-variables-repo",
+		stmt: createIndexVariablesRepo,
+	},
+	{
+		name: "create-index-variables-namespace",
+		stmt: createIndexVariablesNamespace,
+	},
+	{
+		name: "alter-table-repos-add-column-skip-pattern",
+		stmt: alterTableReposAddColumnSkipPattern,
+	},
+	{
+		name: "create-table-path-mappings",
+		stmt: createTablePathMappings,
+	},
+	{
+		name: "create-table-insight-builds",
+		stmt: createTableInsightBuilds,
+	},
+	{
+		name: "create-table-insight-durations",
+		stmt: createTableInsightDurations,
+	},
+	{
+		name: "create-table-insight-steps",
+		stmt: createTableInsightSteps,
+	},
+	{
+		name: "alter-table-stages-add-column-queued",
+		stmt: alterTableStagesAddColumnQueued,
+	},
+	{
+		name: "alter-table-stages-add-column-dispatched",
+		stmt: alterTableStagesAddColumnDispatched,
+	},
+	{
+		name: "alter-table-stages-add-column-accepted",
+		stmt: alterTableStagesAddColumnAccepted,
+	},
+	{
+		name: "create-table-insight-commits",
+		stmt: createTableInsightCommits,
+	},
+	{
+		name: "create-index-insight-commits-day",
+		stmt: createIndexInsightCommitsDay,
+	},
+	{
+		name: "create-table-sessions",
+		stmt: createTableSessions,
+	},
+	{
+		name: "create-index-sessions-user",
+		stmt: createIndexSessionsUser,
+	},
+	{
+		name: "alter-table-repos-add-column-provider",
+		stmt: alterTableReposAddColumnProvider,
+	},
+	{
+		name: "alter-table-repos-add-column-summary",
+		stmt: alterTableReposAddColumnSummary,
+	},
+	{
+		name: "alter-table-repos-add-column-summary-lines",
+		stmt: alterTableReposAddColumnSummaryLines,
+	},
+	{
+		name: "alter-table-stages-add-column-matrix",
+		stmt: alterTableStagesAddColumnMatrix,
+	},
+	{
+		name: "create-table-leases",
+		stmt: createTableLeases,
+	},
+	{
+		name: "alter-table-stages-add-column-attempts",
+		stmt: alterTableStagesAddColumnAttempts,
+	},
+	{
+		name: "create-table-annotations",
+		stmt: createTableAnnotations,
+	},
+	{
+		name: "create-index-annotations-build",
+		stmt: createIndexAnnotationsBuild,
+	},
+	{
+		name: "alter-table-steps-add-column-digest",
+		stmt: alterTableStepsAddColumnDigest,
+	},
+	{
+		name: "create-table-deployments",
+		stmt: createTableDeployments,
+	},
+	{
+		name: "create-index-deployments-repo-environment",
+		stmt: createIndexDeploymentsRepoEnvironment,
+	},
+	{
+		name: "alter-table-builds-add-column-labels",
+		stmt: alterTableBuildsAddColumnLabels,
+	},
+	{
+		name: "alter-table-builds-add-column-changed-files",
+		stmt: alterTableBuildsAddColumnChangedFiles,
+	},
+	{
+		name: "alter-table-repos-add-column-no-dupes",
+		stmt: alterTableReposAddColumnNoDupes,
+	},
+	{
+		name: "create-table-hook-payloads",
+		stmt: createTableHookPayloads,
+	},
+	{
+		name: "create-index-hook-payloads-repo",
+		stmt: createIndexHookPayloadsRepo,
+	},
+	{
+		name: "alter-table-repos-add-column-signer-prev",
+		stmt: alterTableReposAddColumnSignerPrev,
+	},
+	{
+		name: "alter-table-repos-add-column-signer-expiry",
+		stmt: alterTableReposAddColumnSignerExpiry,
+	},
+	{
+		name: "alter-table-repos-add-column-poll-interval",
+		stmt: alterTableReposAddColumnPollInterval,
+	},
+	{
+		name: "alter-table-repos-add-column-poll-branches",
+		stmt: alterTableReposAddColumnPollBranches,
+	},
+	{
+		name: "alter-table-repos-add-column-polled",
+		stmt: alterTableReposAddColumnPolled,
+	},
+	{
+		name: "alter-table-stages-add-column-timeout",
+		stmt: alterTableStagesAddColumnTimeout,
+	},
+	{
+		name: "alter-table-webhook-deliveries-add-column-hook-id",
+		stmt: alterTableWebhookDeliveriesAddColumnHookId,
+	},
+	{
+		name: "alter-table-steps-add-column-attempts",
+		stmt: alterTableStepsAddColumnAttempts,
+	},
 }
 
 // Migrate performs the database migration. If the migration fails
@@ -525,3 +845,730 @@ CREATE TABLE IF NOT EXISTS nodes (
 ,UNIQUE(node_name)
 );
 `
+
+//
+// 011_create_table_artifacts.sql
+//
+
+var createTableArtifacts = `
+CREATE TABLE IF NOT EXISTS artifacts (
+ artifact_id       SERIAL PRIMARY KEY
+,artifact_build_id INTEGER
+,artifact_stage_id INTEGER
+,artifact_step_id  INTEGER
+,artifact_name     VARCHAR(250)
+,artifact_size     INTEGER
+,artifact_created  INTEGER
+,artifact_data     BYTEA
+,UNIQUE(artifact_step_id, artifact_name)
+,FOREIGN KEY(artifact_build_id) REFERENCES builds(build_id) ON DELETE CASCADE
+);
+`
+
+var createIndexArtifactsBuild = `
+CREATE INDEX IF NOT EXISTS ix_artifacts_build ON artifacts (artifact_build_id);
+`
+
+//
+// 012_create_table_test_results.sql
+//
+
+var createTableTestResults = `
+CREATE TABLE IF NOT EXISTS test_results (
+ test_id        SERIAL PRIMARY KEY
+,test_build_id  INTEGER
+,test_stage_id  INTEGER
+,test_step_id   INTEGER
+,test_tests     INTEGER
+,test_failures  INTEGER
+,test_errors    INTEGER
+,test_skipped   INTEGER
+,test_duration  INTEGER
+,test_failed    TEXT
+,test_created   INTEGER
+,FOREIGN KEY(test_build_id) REFERENCES builds(build_id) ON DELETE CASCADE
+);
+`
+
+var createIndexTestResultsBuild = `
+CREATE INDEX IF NOT EXISTS ix_test_results_build ON test_results (test_build_id);
+`
+
+//
+// 013_create_table_coverage.sql
+//
+
+var createTableCoverage = `
+CREATE TABLE IF NOT EXISTS coverage (
+ coverage_id        SERIAL PRIMARY KEY
+,coverage_repo_id   INTEGER
+,coverage_build_id  INTEGER
+,coverage_step_id   INTEGER
+,coverage_ref       VARCHAR(500)
+,coverage_percent   REAL
+,coverage_files     TEXT
+,coverage_created   INTEGER
+,FOREIGN KEY(coverage_build_id) REFERENCES builds(build_id) ON DELETE CASCADE
+);
+`
+
+var createIndexCoverageBuild = `
+CREATE INDEX IF NOT EXISTS ix_coverage_build ON coverage (coverage_build_id);
+`
+
+var createIndexCoverageRepoRef = `
+CREATE INDEX IF NOT EXISTS ix_coverage_repo_ref ON coverage (coverage_repo_id, coverage_ref);
+`
+
+//
+// 014_create_table_audit.sql
+//
+
+var createTableAudit = `
+CREATE TABLE IF NOT EXISTS audit_events (
+ audit_id        SERIAL PRIMARY KEY
+,audit_repo_id   INTEGER
+,audit_actor     VARCHAR(250)
+,audit_action    VARCHAR(50)
+,audit_target    VARCHAR(500)
+,audit_ip        VARCHAR(50)
+,audit_created   INTEGER
+);
+`
+
+var createIndexAuditRepo = `
+CREATE INDEX IF NOT EXISTS ix_audit_repo ON audit_events (audit_repo_id);
+`
+
+var createIndexAuditActor = `
+CREATE INDEX IF NOT EXISTS ix_audit_actor ON audit_events (audit_actor);
+`
+
+//
+// 015_create_table_tokens.sql
+//
+
+var createTableTokens = `
+CREATE TABLE IF NOT EXISTS tokens (
+ token_id        SERIAL PRIMARY KEY
+,token_user_id   INTEGER
+,token_name      VARCHAR(100)
+,token_scopes    TEXT
+,token_hash      VARCHAR(100)
+,token_expires   INTEGER
+,token_created   INTEGER
+,UNIQUE(token_hash)
+,UNIQUE(token_user_id, token_name)
+,FOREIGN KEY(token_user_id) REFERENCES users(user_id) ON DELETE CASCADE
+);
+`
+
+//
+// 016_alter_table_perms.sql
+//
+
+var alterTablePermsAddColumnOverride = `
+ALTER TABLE perms ADD COLUMN perm_override VARCHAR(50) NOT NULL DEFAULT '';
+`
+
+//
+// 017_alter_table_repos_throttle.sql
+//
+
+var alterTableReposAddColumnThrottle = `
+ALTER TABLE repos ADD COLUMN repo_throttle INTEGER NOT NULL DEFAULT 0;
+`
+
+var alterTableStagesAddColumnLimitRepo = `
+ALTER TABLE stages ADD COLUMN stage_limit_repo INTEGER NOT NULL DEFAULT 0;
+`
+
+//
+// 018_create_table_webhook_deliveries.sql
+//
+
+var createTableWebhookDeliveries = `
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+ delivery_id        SERIAL PRIMARY KEY
+,delivery_endpoint  VARCHAR(500)
+,delivery_event     VARCHAR(50)
+,delivery_action    VARCHAR(50)
+,delivery_payload   TEXT
+,delivery_status    VARCHAR(50)
+,delivery_attempts  INTEGER
+,delivery_history   TEXT
+,delivery_next      INTEGER
+,delivery_created   INTEGER
+,delivery_updated   INTEGER
+);
+`
+
+var createIndexWebhookDeliveriesStatus = `
+CREATE INDEX IF NOT EXISTS ix_delivery_status ON webhook_deliveries (delivery_status, delivery_next);
+`
+
+//
+// 019_create_table_repo_webhooks.sql
+//
+
+var createTableRepoWebhooks = `
+CREATE TABLE IF NOT EXISTS repo_webhooks (
+ webhook_id        SERIAL PRIMARY KEY
+,webhook_repo_id   INTEGER
+,webhook_endpoint  VARCHAR(500)
+,webhook_secret    BYTEA
+,webhook_created   INTEGER
+,webhook_updated   INTEGER
+,FOREIGN KEY(webhook_repo_id) REFERENCES repos(repo_id) ON DELETE CASCADE
+);
+`
+
+var createIndexRepoWebhooksRepo = `
+CREATE INDEX IF NOT EXISTS ix_webhook_repo ON repo_webhooks (webhook_repo_id);
+`
+
+//
+// 020_create_table_notifications.sql
+//
+
+var createTableNotifications = `
+CREATE TABLE IF NOT EXISTS notifications (
+ notify_id        SERIAL PRIMARY KEY
+,notify_repo_id   INTEGER
+,notify_kind      VARCHAR(50)
+,notify_endpoint  BYTEA
+,notify_channel   VARCHAR(500)
+,notify_events    VARCHAR(500)
+,notify_branches  VARCHAR(500)
+,notify_created   INTEGER
+,notify_updated   INTEGER
+,FOREIGN KEY(notify_repo_id) REFERENCES repos(repo_id) ON DELETE CASCADE
+);
+`
+
+var createIndexNotificationsRepo = `
+CREATE INDEX IF NOT EXISTS ix_notify_repo ON notifications (notify_repo_id);
+`
+
+//
+// 021_alter_table_notifications_add_column_recipients.sql
+//
+
+var alterTableNotificationsAddColumnRecipients = `
+ALTER TABLE notifications ADD COLUMN notify_recipients TEXT;
+`
+
+var alterTableNotificationsAddColumnAuthor = `
+ALTER TABLE notifications ADD COLUMN notify_author BOOLEAN NOT NULL DEFAULT false;
+`
+
+//
+// 022_alter_table_repos_add_column_merge_pulls.sql
+//
+
+var alterTableReposAddColumnMergePulls = `
+ALTER TABLE repos ADD COLUMN repo_merge_pulls BOOLEAN NOT NULL DEFAULT false;
+`
+
+//
+// 023_alter_table_cron_add_column_rebuild.sql
+//
+
+var alterTableCronAddColumnRebuild = `
+ALTER TABLE cron ADD COLUMN cron_rebuild BOOLEAN NOT NULL DEFAULT false;
+`
+
+//
+// 024_alter_table_builds_add_column_restarted_from.sql
+//
+
+var alterTableBuildsAddColumnRestartedFrom = `
+ALTER TABLE builds ADD COLUMN build_restarted_from INTEGER NOT NULL DEFAULT 0;
+`
+
+var alterTableBuildsAddColumnTriggeredBy = `
+ALTER TABLE builds ADD COLUMN build_triggered_by VARCHAR(250) NOT NULL DEFAULT '';
+`
+
+//
+// 025_create_index_builds_filter.sql
+//
+
+var createIndexBuildsRepoStatus = `
+CREATE INDEX IF NOT EXISTS ix_build_repo_status ON builds (build_repo_id, build_status);
+`
+
+var createIndexBuildsRepoEvent = `
+CREATE INDEX IF NOT EXISTS ix_build_repo_event ON builds (build_repo_id, build_event);
+`
+
+var createIndexBuildsRepoTarget = `
+CREATE INDEX IF NOT EXISTS ix_build_repo_target ON builds (build_repo_id, build_target);
+`
+
+var createIndexBuildsRepoCreated = `
+CREATE INDEX IF NOT EXISTS ix_build_repo_created ON builds (build_repo_id, build_created);
+`
+
+//
+// 026_create_index_builds_search.sql
+//
+
+var createIndexBuildsAfter = `
+CREATE INDEX IF NOT EXISTS ix_build_after ON builds (build_after);
+`
+
+var createIndexBuildsTarget = `
+CREATE INDEX IF NOT EXISTS ix_build_target ON builds (build_target);
+`
+
+//
+// 027_create_table_secret_access.sql
+//
+
+var createTableSecretAccess = `
+CREATE TABLE IF NOT EXISTS secret_access (
+ access_id           SERIAL PRIMARY KEY
+,access_secret_id    INTEGER
+,access_repo_id      INTEGER
+,access_build_id     INTEGER
+,access_build_number INTEGER
+,access_stage_id     INTEGER
+,access_created      INTEGER
+);
+`
+
+var createIndexSecretAccessSecret = `
+CREATE INDEX IF NOT EXISTS ix_secret_access_secret ON secret_access (access_secret_id);
+`
+
+//
+// 028_alter_table_repos_add_column_no_mask.sql
+//
+
+var alterTableReposAddColumnNoMask = `
+ALTER TABLE repos ADD COLUMN repo_no_mask BOOLEAN NOT NULL DEFAULT false;
+`
+
+//
+// 029_create_table_registries.sql
+//
+
+var createTableRegistries = `
+CREATE TABLE IF NOT EXISTS registries (
+ registry_id        SERIAL PRIMARY KEY
+,registry_repo_id   INTEGER
+,registry_namespace VARCHAR(500)
+,registry_address   VARCHAR(500)
+,registry_username  VARCHAR(500)
+,registry_password  BYTEA
+,registry_policy    VARCHAR(500)
+,registry_created   INTEGER
+,registry_updated   INTEGER
+,FOREIGN KEY(registry_repo_id) REFERENCES repos(repo_id) ON DELETE CASCADE
+);
+`
+
+var createIndexRegistriesRepo = `
+CREATE INDEX IF NOT EXISTS ix_registry_repo ON registries (registry_repo_id);
+`
+
+var createIndexRegistriesNamespace = `
+CREATE INDEX IF NOT EXISTS ix_registry_namespace ON registries (registry_namespace);
+`
+
+//
+// 030_create_table_policies.sql
+//
+
+var createTablePolicies = `
+CREATE TABLE IF NOT EXISTS policies (
+ policy_id               SERIAL PRIMARY KEY
+,policy_namespace        VARCHAR(250)
+,policy_allow_registries VARCHAR(2000)
+,policy_deny_images      VARCHAR(2000)
+,policy_deny_privileged  BOOLEAN
+,policy_clone_depth      INTEGER
+,policy_created          INTEGER
+,policy_updated          INTEGER
+,UNIQUE(policy_namespace)
+);
+`
+
+//
+// 031_alter_table_stages_add_column_elevated.sql
+//
+
+var alterTableStagesAddColumnElevated = `
+ALTER TABLE stages ADD COLUMN stage_elevated BOOLEAN NOT NULL DEFAULT false;
+`
+
+//
+// 032_create_table_privileged_images.sql
+//
+
+var createTablePrivilegedImages = `
+CREATE TABLE IF NOT EXISTS privileged_images (
+ privileged_id        SERIAL PRIMARY KEY
+,privileged_namespace VARCHAR(250)
+,privileged_image     VARCHAR(250)
+,privileged_created   INTEGER
+,UNIQUE(privileged_namespace, privileged_image)
+);
+`
+
+//
+// 033_create_table_variables.sql
+//
+
+var createTableVariables = `
+CREATE TABLE IF NOT EXISTS variables (
+ variable_id        SERIAL PRIMARY KEY
+,variable_repo_id   INTEGER
+,variable_namespace VARCHAR(500)
+,variable_name      VARCHAR(500)
+,variable_value     BYTEA
+,variable_secret    BOOLEAN
+,variable_created   INTEGER
+,variable_updated   INTEGER
+,FOREIGN KEY(variable_repo_id) REFERENCES repos(repo_id) ON DELETE CASCADE
+);
+`
+
+var createIndexVariablesRepo = `
+CREATE INDEX IF NOT EXISTS ix_variable_repo ON variables (variable_repo_id);
+`
+
+var createIndexVariablesNamespace = `
+CREATE INDEX IF NOT EXISTS ix_variable_namespace ON variables (variable_namespace);
+`
+
+//
+// 034_alter_table_repos_add_column_skip_pattern.sql
+//
+
+var alterTableReposAddColumnSkipPattern = `
+ALTER TABLE repos ADD COLUMN repo_skip_pattern VARCHAR(500) NOT NULL DEFAULT '';
+`
+
+//
+// 035_create_table_path_mappings.sql
+//
+
+var createTablePathMappings = `
+CREATE TABLE IF NOT EXISTS path_mappings (
+ mapping_id        SERIAL PRIMARY KEY
+,mapping_repo_id   INTEGER
+,mapping_pattern   VARCHAR(250)
+,mapping_pipeline  VARCHAR(250)
+,mapping_created   INTEGER
+,UNIQUE(mapping_repo_id, mapping_pattern, mapping_pipeline)
+,FOREIGN KEY(mapping_repo_id) REFERENCES repos(repo_id) ON DELETE CASCADE
+);
+`
+
+//
+// 036_create_table_insights.sql
+//
+
+var createTableInsightBuilds = `
+CREATE TABLE IF NOT EXISTS insight_builds (
+ insight_repo_id   INTEGER
+,insight_day       INTEGER
+,insight_total     INTEGER
+,insight_success   INTEGER
+,insight_failure   INTEGER
+,insight_duration  INTEGER
+,insight_wait      INTEGER
+,PRIMARY KEY(insight_repo_id, insight_day)
+,FOREIGN KEY(insight_repo_id) REFERENCES repos(repo_id) ON DELETE CASCADE
+);
+`
+
+var createTableInsightDurations = `
+CREATE TABLE IF NOT EXISTS insight_durations (
+ insight_repo_id   INTEGER
+,insight_day       INTEGER
+,insight_bucket    INTEGER
+,insight_count     INTEGER
+,PRIMARY KEY(insight_repo_id, insight_day, insight_bucket)
+,FOREIGN KEY(insight_repo_id) REFERENCES repos(repo_id) ON DELETE CASCADE
+);
+`
+
+var createTableInsightSteps = `
+CREATE TABLE IF NOT EXISTS insight_steps (
+ insight_repo_id   INTEGER
+,insight_day       INTEGER
+,insight_stage     VARCHAR(250)
+,insight_name      VARCHAR(250)
+,insight_total     INTEGER
+,insight_failure   INTEGER
+,insight_duration  INTEGER
+,PRIMARY KEY(insight_repo_id, insight_day, insight_stage, insight_name)
+,FOREIGN KEY(insight_repo_id) REFERENCES repos(repo_id) ON DELETE CASCADE
+);
+`
+
+//
+// 037_alter_table_stages_add_column_queue.sql
+//
+
+var alterTableStagesAddColumnQueued = `
+ALTER TABLE stages ADD COLUMN stage_queued INTEGER NOT NULL DEFAULT 0;
+`
+
+var alterTableStagesAddColumnDispatched = `
+ALTER TABLE stages ADD COLUMN stage_dispatched INTEGER NOT NULL DEFAULT 0;
+`
+
+var alterTableStagesAddColumnAccepted = `
+ALTER TABLE stages ADD COLUMN stage_accepted INTEGER NOT NULL DEFAULT 0;
+`
+
+//
+// 038_create_table_insight_commits.sql
+//
+
+var createTableInsightCommits = `
+CREATE TABLE IF NOT EXISTS insight_commits (
+ insight_repo_id   INTEGER
+,insight_branch    VARCHAR(250)
+,insight_commit    VARCHAR(50)
+,insight_stage     VARCHAR(100)
+,insight_name      VARCHAR(100)
+,insight_day       INTEGER
+,insight_success   INTEGER
+,insight_failure   INTEGER
+,PRIMARY KEY(insight_repo_id, insight_branch, insight_commit, insight_stage, insight_name)
+,FOREIGN KEY(insight_repo_id) REFERENCES repos(repo_id) ON DELETE CASCADE
+);
+`
+
+var createIndexInsightCommitsDay = `
+CREATE INDEX IF NOT EXISTS ix_insight_commits_day ON insight_commits (insight_repo_id, insight_day);
+`
+
+//
+// 039_create_table_sessions.sql
+//
+
+var createTableSessions = `
+CREATE TABLE IF NOT EXISTS sessions (
+ session_id        SERIAL PRIMARY KEY
+,session_user_id   INTEGER
+,session_hash      VARCHAR(100)
+,session_address   VARCHAR(100)
+,session_agent     VARCHAR(500)
+,session_created   INTEGER
+,session_accessed  INTEGER
+,session_expires   INTEGER
+,UNIQUE(session_hash)
+,FOREIGN KEY(session_user_id) REFERENCES users(user_id) ON DELETE CASCADE
+);
+`
+
+var createIndexSessionsUser = `
+CREATE INDEX IF NOT EXISTS ix_sessions_user ON sessions (session_user_id);
+`
+
+//
+// 040_alter_table_repos_add_column_provider.sql
+//
+
+var alterTableReposAddColumnProvider = `
+ALTER TABLE repos ADD COLUMN repo_provider VARCHAR(50) NOT NULL DEFAULT '';
+`
+
+//
+// 041_alter_table_repos_add_column_summary.sql
+//
+
+var alterTableReposAddColumnSummary = `
+ALTER TABLE repos ADD COLUMN repo_summary BOOLEAN NOT NULL DEFAULT false;
+`
+
+var alterTableReposAddColumnSummaryLines = `
+ALTER TABLE repos ADD COLUMN repo_summary_lines INTEGER NOT NULL DEFAULT 0;
+`
+
+//
+// 042_alter_table_stages_add_column_matrix.sql
+//
+
+var alterTableStagesAddColumnMatrix = `
+ALTER TABLE stages ADD COLUMN stage_matrix TEXT;
+`
+
+//
+// 043_create_table_leases.sql
+//
+
+var createTableLeases = `
+CREATE TABLE IF NOT EXISTS leases (
+ lease_name     VARCHAR(250) PRIMARY KEY
+,lease_holder   VARCHAR(250)
+,lease_expires  INTEGER
+);
+`
+
+//
+// 044_alter_table_stages_add_column_attempts.sql
+//
+
+var alterTableStagesAddColumnAttempts = `
+ALTER TABLE stages ADD COLUMN stage_attempts INTEGER NOT NULL DEFAULT 0;
+`
+
+//
+// 045_create_table_annotations.sql
+//
+
+var createTableAnnotations = `
+CREATE TABLE IF NOT EXISTS annotations (
+ annotation_id        SERIAL PRIMARY KEY
+,annotation_build_id  INTEGER
+,annotation_stage_id  INTEGER
+,annotation_step_id   INTEGER
+,annotation_kind      VARCHAR(50)
+,annotation_name      VARCHAR(250)
+,annotation_value     TEXT
+,annotation_created   INTEGER
+,FOREIGN KEY(annotation_build_id) REFERENCES builds(build_id) ON DELETE CASCADE
+);
+`
+
+var createIndexAnnotationsBuild = `
+CREATE INDEX IF NOT EXISTS ix_annotations_build ON annotations (annotation_build_id);
+`
+
+//
+// 046_alter_table_steps_add_column_digest.sql
+//
+
+var alterTableStepsAddColumnDigest = `
+ALTER TABLE steps ADD COLUMN step_digest VARCHAR(500) NOT NULL DEFAULT '';
+`
+
+//
+// 047_create_table_deployments.sql
+//
+
+var createTableDeployments = `
+CREATE TABLE IF NOT EXISTS deployments (
+ deployment_id          SERIAL PRIMARY KEY
+,deployment_repo_id     INTEGER
+,deployment_build_id    INTEGER
+,deployment_number      INTEGER
+,deployment_environment VARCHAR(250)
+,deployment_commit      VARCHAR(250)
+,deployment_ref         VARCHAR(500)
+,deployment_status      VARCHAR(50)
+,deployment_deployer    VARCHAR(250)
+,deployment_created     INTEGER
+,FOREIGN KEY(deployment_repo_id) REFERENCES repos(repo_id) ON DELETE CASCADE
+);
+`
+
+var createIndexDeploymentsRepoEnvironment = `
+CREATE INDEX IF NOT EXISTS ix_deployments_repo_environment ON deployments (deployment_repo_id, deployment_environment);
+`
+
+//
+// 048_alter_table_builds_add_column_labels.sql
+//
+
+var alterTableBuildsAddColumnLabels = `
+ALTER TABLE builds ADD COLUMN build_labels TEXT;
+`
+
+var alterTableBuildsAddColumnChangedFiles = `
+ALTER TABLE builds ADD COLUMN build_changed_files INTEGER NOT NULL DEFAULT 0;
+`
+
+//
+// 049_alter_table_repos_add_column_no_dupes.sql
+//
+
+var alterTableReposAddColumnNoDupes = `
+ALTER TABLE repos ADD COLUMN repo_no_dupes BOOLEAN NOT NULL DEFAULT false;
+`
+
+//
+// 050_create_table_hook_payloads.sql
+//
+
+var createTableHookPayloads = `
+CREATE TABLE IF NOT EXISTS hook_payloads (
+ payload_id       SERIAL PRIMARY KEY
+,payload_repo_id  INTEGER
+,payload_event    VARCHAR(50)
+,payload_action   VARCHAR(50)
+,payload_ref      VARCHAR(500)
+,payload_after    VARCHAR(250)
+,payload_status   VARCHAR(50)
+,payload_message  TEXT
+,payload_build    INTEGER
+,payload_header   TEXT
+,payload_body     TEXT
+,payload_created  INTEGER
+,payload_updated  INTEGER
+,FOREIGN KEY(payload_repo_id) REFERENCES repos(repo_id) ON DELETE CASCADE
+);
+`
+
+var createIndexHookPayloadsRepo = `
+CREATE INDEX IF NOT EXISTS ix_hook_payloads_repo ON hook_payloads (payload_repo_id);
+`
+
+//
+// 051_alter_table_repos_add_column_signer_prev.sql
+//
+
+var alterTableReposAddColumnSignerPrev = `
+ALTER TABLE repos ADD COLUMN repo_signer_prev VARCHAR(50) NOT NULL DEFAULT '';
+`
+
+var alterTableReposAddColumnSignerExpiry = `
+ALTER TABLE repos ADD COLUMN repo_signer_expiry INTEGER NOT NULL DEFAULT 0;
+`
+
+//
+// 052_alter_table_repos_add_column_polling.sql
+//
+
+var alterTableReposAddColumnPollInterval = `
+ALTER TABLE repos ADD COLUMN repo_poll_interval INTEGER NOT NULL DEFAULT 0;
+`
+
+var alterTableReposAddColumnPollBranches = `
+ALTER TABLE repos ADD COLUMN repo_poll_branches VARCHAR(500) NOT NULL DEFAULT '';
+`
+
+var alterTableReposAddColumnPolled = `
+ALTER TABLE repos ADD COLUMN repo_polled INTEGER NOT NULL DEFAULT 0;
+`
+
+//
+// 053_alter_table_stages_add_column_timeout.sql
+//
+
+var alterTableStagesAddColumnTimeout = `
+ALTER TABLE stages ADD COLUMN stage_timeout INTEGER NOT NULL DEFAULT 0;
+`
+
+//
+// 054_alter_table_webhook_deliveries_add_column_hook_id.sql
+//
+
+var alterTableWebhookDeliveriesAddColumnHookId = `
+ALTER TABLE webhook_deliveries ADD COLUMN delivery_hook_id INTEGER NOT NULL DEFAULT 0;
+`
+
+//
+// 055_alter_table_steps_add_column_attempts.sql
+//
+
+var alterTableStepsAddColumnAttempts = `
+ALTER TABLE steps ADD COLUMN step_attempts INTEGER NOT NULL DEFAULT 0;
+`
@@ -0,0 +1,21 @@
+-- name: create-table-coverage
+
+CREATE TABLE IF NOT EXISTS coverage (
+ coverage_id        SERIAL PRIMARY KEY
+,coverage_repo_id   INTEGER
+,coverage_build_id  INTEGER
+,coverage_step_id   INTEGER
+,coverage_ref       VARCHAR(500)
+,coverage_percent   REAL
+,coverage_files     TEXT
+,coverage_created   INTEGER
+,FOREIGN KEY(coverage_build_id) REFERENCES builds(build_id) ON DELETE CASCADE
+);
+
+-- name: create-index-coverage-build
+
+CREATE INDEX IF NOT EXISTS ix_coverage_build ON coverage (coverage_build_id);
+
+-- name: create-index-coverage-repo-ref
+
+CREATE INDEX IF NOT EXISTS ix_coverage_repo_ref ON coverage (coverage_repo_id, coverage_ref);
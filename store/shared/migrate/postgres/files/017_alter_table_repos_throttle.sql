@@ -0,0 +1,7 @@
+-- name: alter-table-repos-add-column-throttle
+
+ALTER TABLE repos ADD COLUMN repo_throttle INTEGER NOT NULL DEFAULT 0;
+
+-- name: alter-table-stages-add-column-limit-repo
+
+ALTER TABLE stages ADD COLUMN stage_limit_repo INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,19 @@
+-- name: create-table-webhook-deliveries
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+ delivery_id        SERIAL PRIMARY KEY
+,delivery_endpoint  VARCHAR(500)
+,delivery_event     VARCHAR(50)
+,delivery_action    VARCHAR(50)
+,delivery_payload   TEXT
+,delivery_status    VARCHAR(50)
+,delivery_attempts  INTEGER
+,delivery_history   TEXT
+,delivery_next      INTEGER
+,delivery_created   INTEGER
+,delivery_updated   INTEGER
+);
+
+-- name: create-index-webhook-deliveries-status
+
+CREATE INDEX IF NOT EXISTS ix_delivery_status ON webhook_deliveries (delivery_status, delivery_next);
@@ -0,0 +1,15 @@
+-- name: create-table-repo-webhooks
+
+CREATE TABLE IF NOT EXISTS repo_webhooks (
+ webhook_id        SERIAL PRIMARY KEY
+,webhook_repo_id   INTEGER
+,webhook_endpoint  VARCHAR(500)
+,webhook_secret    BYTEA
+,webhook_created   INTEGER
+,webhook_updated   INTEGER
+,FOREIGN KEY(webhook_repo_id) REFERENCES repos(repo_id) ON DELETE CASCADE
+);
+
+-- name: create-index-repo-webhooks-repo
+
+CREATE INDEX IF NOT EXISTS ix_webhook_repo ON repo_webhooks (webhook_repo_id);
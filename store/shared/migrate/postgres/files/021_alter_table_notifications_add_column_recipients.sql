@@ -0,0 +1,7 @@
+-- name: alter-table-notifications-add-column-recipients
+
+ALTER TABLE notifications ADD COLUMN notify_recipients TEXT;
+
+-- name: alter-table-notifications-add-column-author
+
+ALTER TABLE notifications ADD COLUMN notify_author BOOLEAN NOT NULL DEFAULT false;
@@ -0,0 +1,3 @@
+-- name: alter-table-cron-add-column-rebuild
+
+ALTER TABLE cron ADD COLUMN cron_rebuild BOOLEAN NOT NULL DEFAULT false;
@@ -0,0 +1,3 @@
+-- name: alter-table-stages-add-column-elevated
+
+ALTER TABLE stages ADD COLUMN stage_elevated BOOLEAN NOT NULL DEFAULT false;
@@ -0,0 +1,11 @@
+-- name: create-table-path-mappings
+
+CREATE TABLE IF NOT EXISTS path_mappings (
+ mapping_id        SERIAL PRIMARY KEY
+,mapping_repo_id   INTEGER
+,mapping_pattern   VARCHAR(250)
+,mapping_pipeline  VARCHAR(250)
+,mapping_created   INTEGER
+,UNIQUE(mapping_repo_id, mapping_pattern, mapping_pipeline)
+,FOREIGN KEY(mapping_repo_id) REFERENCES repos(repo_id) ON DELETE CASCADE
+);
@@ -0,0 +1,3 @@
+-- name: alter-table-stages-add-column-matrix
+
+ALTER TABLE stages ADD COLUMN stage_matrix TEXT;
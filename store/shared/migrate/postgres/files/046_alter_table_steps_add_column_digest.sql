@@ -0,0 +1,3 @@
+-- name: alter-table-steps-add-column-digest
+
+ALTER TABLE steps ADD COLUMN step_digest VARCHAR(500) NOT NULL DEFAULT '';
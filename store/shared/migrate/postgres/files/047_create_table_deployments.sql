@@ -0,0 +1,19 @@
+-- name: create-table-deployments
+
+CREATE TABLE IF NOT EXISTS deployments (
+ deployment_id          SERIAL PRIMARY KEY
+,deployment_repo_id     INTEGER
+,deployment_build_id    INTEGER
+,deployment_number      INTEGER
+,deployment_environment VARCHAR(250)
+,deployment_commit      VARCHAR(250)
+,deployment_ref         VARCHAR(500)
+,deployment_status      VARCHAR(50)
+,deployment_deployer    VARCHAR(250)
+,deployment_created     INTEGER
+,FOREIGN KEY(deployment_repo_id) REFERENCES repos(repo_id) ON DELETE CASCADE
+);
+
+-- name: create-index-deployments-repo-environment
+
+CREATE INDEX IF NOT EXISTS ix_deployments_repo_environment ON deployments (deployment_repo_id, deployment_environment);
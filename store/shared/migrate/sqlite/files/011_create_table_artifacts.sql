@@ -0,0 +1,18 @@
+-- name: create-table-artifacts
+
+CREATE TABLE IF NOT EXISTS artifacts (
+ artifact_id       INTEGER PRIMARY KEY AUTOINCREMENT
+,artifact_build_id INTEGER
+,artifact_stage_id INTEGER
+,artifact_step_id  INTEGER
+,artifact_name     TEXT
+,artifact_size     INTEGER
+,artifact_created  INTEGER
+,artifact_data     BLOB
+,UNIQUE(artifact_step_id, artifact_name)
+,FOREIGN KEY(artifact_build_id) REFERENCES builds(build_id) ON DELETE CASCADE
+);
+
+-- name: create-index-artifacts-build
+
+CREATE INDEX IF NOT EXISTS ix_artifacts_build ON artifacts (artifact_build_id);
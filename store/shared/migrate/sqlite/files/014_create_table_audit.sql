@@ -0,0 +1,19 @@
+-- name: create-table-audit
+
+CREATE TABLE IF NOT EXISTS audit_events (
+ audit_id        INTEGER PRIMARY KEY AUTOINCREMENT
+,audit_repo_id   INTEGER
+,audit_actor     TEXT
+,audit_action    TEXT
+,audit_target    TEXT
+,audit_ip        TEXT
+,audit_created   INTEGER
+);
+
+-- name: create-index-audit-repo
+
+CREATE INDEX IF NOT EXISTS ix_audit_repo ON audit_events (audit_repo_id);
+
+-- name: create-index-audit-actor
+
+CREATE INDEX IF NOT EXISTS ix_audit_actor ON audit_events (audit_actor);
@@ -0,0 +1,19 @@
+-- name: create-table-webhook-deliveries
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+ delivery_id        INTEGER PRIMARY KEY AUTOINCREMENT
+,delivery_endpoint  TEXT
+,delivery_event     TEXT
+,delivery_action    TEXT
+,delivery_payload   TEXT
+,delivery_status    TEXT
+,delivery_attempts  INTEGER
+,delivery_history   TEXT
+,delivery_next      INTEGER
+,delivery_created   INTEGER
+,delivery_updated   INTEGER
+);
+
+-- name: create-index-webhook-deliveries-status
+
+CREATE INDEX IF NOT EXISTS ix_delivery_status ON webhook_deliveries (delivery_status, delivery_next);
@@ -0,0 +1,18 @@
+-- name: create-table-notifications
+
+CREATE TABLE IF NOT EXISTS notifications (
+ notify_id        INTEGER PRIMARY KEY AUTOINCREMENT
+,notify_repo_id   INTEGER
+,notify_kind      TEXT
+,notify_endpoint  BLOB
+,notify_channel   TEXT
+,notify_events    TEXT
+,notify_branches  TEXT
+,notify_created   INTEGER
+,notify_updated   INTEGER
+,FOREIGN KEY(notify_repo_id) REFERENCES repos(repo_id) ON DELETE CASCADE
+);
+
+-- name: create-index-notifications-repo
+
+CREATE INDEX IF NOT EXISTS ix_notify_repo ON notifications (notify_repo_id);
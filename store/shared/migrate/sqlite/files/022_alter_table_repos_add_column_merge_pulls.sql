@@ -0,0 +1,3 @@
+-- name: alter-table-repos-add-column-merge-pulls
+
+ALTER TABLE repos ADD COLUMN repo_merge_pulls BOOLEAN NOT NULL DEFAULT 0;
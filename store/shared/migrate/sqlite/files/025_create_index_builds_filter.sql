@@ -0,0 +1,15 @@
+-- name: create-index-builds-repo-status
+
+CREATE INDEX IF NOT EXISTS ix_build_repo_status ON builds (build_repo_id, build_status);
+
+-- name: create-index-builds-repo-event
+
+CREATE INDEX IF NOT EXISTS ix_build_repo_event ON builds (build_repo_id, build_event);
+
+-- name: create-index-builds-repo-target
+
+CREATE INDEX IF NOT EXISTS ix_build_repo_target ON builds (build_repo_id, build_target);
+
+-- name: create-index-builds-repo-created
+
+CREATE INDEX IF NOT EXISTS ix_build_repo_created ON builds (build_repo_id, build_created);
@@ -0,0 +1,15 @@
+-- name: create-table-secret-access
+
+CREATE TABLE IF NOT EXISTS secret_access (
+ access_id           INTEGER PRIMARY KEY AUTOINCREMENT
+,access_secret_id    INTEGER
+,access_repo_id      INTEGER
+,access_build_id     INTEGER
+,access_build_number INTEGER
+,access_stage_id     INTEGER
+,access_created      INTEGER
+);
+
+-- name: create-index-secret-access-secret
+
+CREATE INDEX IF NOT EXISTS ix_secret_access_secret ON secret_access (access_secret_id);
@@ -0,0 +1,3 @@
+-- name: alter-table-repos-add-column-no-mask
+
+ALTER TABLE repos ADD COLUMN repo_no_mask BOOLEAN NOT NULL DEFAULT 0;
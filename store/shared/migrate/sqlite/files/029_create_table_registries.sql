@@ -0,0 +1,22 @@
+-- name: create-table-registries
+
+CREATE TABLE IF NOT EXISTS registries (
+ registry_id        INTEGER PRIMARY KEY AUTOINCREMENT
+,registry_repo_id   INTEGER
+,registry_namespace TEXT
+,registry_address   TEXT
+,registry_username  TEXT
+,registry_password  BLOB
+,registry_policy    TEXT
+,registry_created   INTEGER
+,registry_updated   INTEGER
+,FOREIGN KEY(registry_repo_id) REFERENCES repos(repo_id) ON DELETE CASCADE
+);
+
+-- name: create-index-registries-repo
+
+CREATE INDEX IF NOT EXISTS ix_registry_repo ON registries (registry_repo_id);
+
+-- name: create-index-registries-namespace
+
+CREATE INDEX IF NOT EXISTS ix_registry_namespace ON registries (registry_namespace);
@@ -0,0 +1,13 @@
+-- name: create-table-policies
+
+CREATE TABLE IF NOT EXISTS policies (
+ policy_id               INTEGER PRIMARY KEY AUTOINCREMENT
+,policy_namespace        TEXT
+,policy_allow_registries TEXT
+,policy_deny_images      TEXT
+,policy_deny_privileged  BOOLEAN
+,policy_clone_depth      INTEGER
+,policy_created          INTEGER
+,policy_updated          INTEGER
+,UNIQUE(policy_namespace)
+);
@@ -0,0 +1,9 @@
+-- name: create-table-privileged-images
+
+CREATE TABLE IF NOT EXISTS privileged_images (
+ privileged_id        INTEGER PRIMARY KEY AUTOINCREMENT
+,privileged_namespace TEXT
+,privileged_image     TEXT
+,privileged_created   INTEGER
+,UNIQUE(privileged_namespace, privileged_image)
+);
@@ -0,0 +1,21 @@
+-- name: create-table-variables
+
+CREATE TABLE IF NOT EXISTS variables (
+ variable_id        INTEGER PRIMARY KEY AUTOINCREMENT
+,variable_repo_id   INTEGER
+,variable_namespace TEXT
+,variable_name      TEXT
+,variable_value     BLOB
+,variable_secret    BOOLEAN
+,variable_created   INTEGER
+,variable_updated   INTEGER
+,FOREIGN KEY(variable_repo_id) REFERENCES repos(repo_id) ON DELETE CASCADE
+);
+
+-- name: create-index-variables-repo
+
+CREATE INDEX IF NOT EXISTS ix_variable_repo ON variables (variable_repo_id);
+
+-- name: create-index-variables-namespace
+
+CREATE INDEX IF NOT EXISTS ix_variable_namespace ON variables (variable_namespace);
@@ -0,0 +1,3 @@
+-- name: alter-table-repos-add-column-skip-pattern
+
+ALTER TABLE repos ADD COLUMN repo_skip_pattern TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,11 @@
+-- name: create-table-path-mappings
+
+CREATE TABLE IF NOT EXISTS path_mappings (
+ mapping_id        INTEGER PRIMARY KEY AUTOINCREMENT
+,mapping_repo_id   INTEGER
+,mapping_pattern   TEXT
+,mapping_pipeline  TEXT
+,mapping_created   INTEGER
+,UNIQUE(mapping_repo_id, mapping_pattern, mapping_pipeline)
+,FOREIGN KEY(mapping_repo_id) REFERENCES repos(repo_id) ON DELETE CASCADE
+);
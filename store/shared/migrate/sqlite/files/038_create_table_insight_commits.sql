@@ -0,0 +1,18 @@
+-- name: create-table-insight-commits
+
+CREATE TABLE IF NOT EXISTS insight_commits (
+ insight_repo_id   INTEGER
+,insight_branch    TEXT
+,insight_commit    TEXT
+,insight_stage     TEXT
+,insight_name      TEXT
+,insight_day       INTEGER
+,insight_success   INTEGER
+,insight_failure   INTEGER
+,PRIMARY KEY(insight_repo_id, insight_branch, insight_commit, insight_stage, insight_name)
+,FOREIGN KEY(insight_repo_id) REFERENCES repos(repo_id) ON DELETE CASCADE
+);
+
+-- name: create-index-insight-commits-day
+
+CREATE INDEX IF NOT EXISTS ix_insight_commits_day ON insight_commits (insight_repo_id, insight_day);
@@ -0,0 +1,18 @@
+-- name: create-table-sessions
+
+CREATE TABLE IF NOT EXISTS sessions (
+ session_id        INTEGER PRIMARY KEY AUTOINCREMENT
+,session_user_id   INTEGER
+,session_hash      TEXT
+,session_address   TEXT
+,session_agent     TEXT
+,session_created   INTEGER
+,session_accessed  INTEGER
+,session_expires   INTEGER
+,UNIQUE(session_hash)
+,FOREIGN KEY(session_user_id) REFERENCES users(user_id) ON DELETE CASCADE
+);
+
+-- name: create-index-sessions-user
+
+CREATE INDEX IF NOT EXISTS ix_sessions_user ON sessions (session_user_id);
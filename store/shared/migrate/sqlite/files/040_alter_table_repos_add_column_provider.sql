@@ -0,0 +1,3 @@
+-- name: alter-table-repos-add-column-provider
+
+ALTER TABLE repos ADD COLUMN repo_provider TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,7 @@
+-- name: alter-table-repos-add-column-summary
+
+ALTER TABLE repos ADD COLUMN repo_summary BOOLEAN NOT NULL DEFAULT 0;
+
+-- name: alter-table-repos-add-column-summary-lines
+
+ALTER TABLE repos ADD COLUMN repo_summary_lines INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,19 @@
+-- name: create-table-deployments
+
+CREATE TABLE IF NOT EXISTS deployments (
+ deployment_id          INTEGER PRIMARY KEY AUTOINCREMENT
+,deployment_repo_id     INTEGER
+,deployment_build_id    INTEGER
+,deployment_number      INTEGER
+,deployment_environment TEXT
+,deployment_commit      TEXT
+,deployment_ref         TEXT
+,deployment_status      TEXT
+,deployment_deployer    TEXT
+,deployment_created     INTEGER
+,FOREIGN KEY(deployment_repo_id) REFERENCES repos(repo_id) ON DELETE CASCADE
+);
+
+-- name: create-index-deployments-repo-environment
+
+CREATE INDEX IF NOT EXISTS ix_deployments_repo_environment ON deployments (deployment_repo_id, deployment_environment);
@@ -0,0 +1,7 @@
+-- name: alter-table-builds-add-column-labels
+
+ALTER TABLE builds ADD COLUMN build_labels TEXT;
+
+-- name: alter-table-builds-add-column-changed-files
+
+ALTER TABLE builds ADD COLUMN build_changed_files INTEGER NOT NULL DEFAULT 0;
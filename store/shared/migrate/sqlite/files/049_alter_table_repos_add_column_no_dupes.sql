@@ -0,0 +1,3 @@
+-- name: alter-table-repos-add-column-no-dupes
+
+ALTER TABLE repos ADD COLUMN repo_no_dupes BOOLEAN NOT NULL DEFAULT 0;
@@ -0,0 +1,22 @@
+-- name: create-table-hook-payloads
+
+CREATE TABLE IF NOT EXISTS hook_payloads (
+ payload_id       INTEGER PRIMARY KEY AUTOINCREMENT
+,payload_repo_id  INTEGER
+,payload_event    TEXT
+,payload_action   TEXT
+,payload_ref      TEXT
+,payload_after    TEXT
+,payload_status   TEXT
+,payload_message  TEXT
+,payload_build    INTEGER
+,payload_header   TEXT
+,payload_body     TEXT
+,payload_created  INTEGER
+,payload_updated  INTEGER
+,FOREIGN KEY(payload_repo_id) REFERENCES repos(repo_id) ON DELETE CASCADE
+);
+
+-- name: create-index-hook-payloads-repo
+
+CREATE INDEX IF NOT EXISTS ix_hook_payloads_repo ON hook_payloads (payload_repo_id);
@@ -0,0 +1,7 @@
+-- name: alter-table-repos-add-column-signer-prev
+
+ALTER TABLE repos ADD COLUMN repo_signer_prev TEXT NOT NULL DEFAULT '';
+
+-- name: alter-table-repos-add-column-signer-expiry
+
+ALTER TABLE repos ADD COLUMN repo_signer_expiry INTEGER NOT NULL DEFAULT 0;
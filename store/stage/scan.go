@@ -41,6 +41,11 @@ func toParams(stage *core.Stage) map[string]interface{} {
 		"stage_errignore":  stage.ErrIgnore,
 		"stage_exit_code":  stage.ExitCode,
 		"stage_limit":      stage.Limit,
+		"stage_limit_repo": stage.LimitRepo,
+		"stage_timeout":    stage.Timeout,
+		"stage_queued":     stage.Queued,
+		"stage_dispatched": stage.Dispatched,
+		"stage_accepted":   stage.Accepted,
 		"stage_os":         stage.OS,
 		"stage_arch":       stage.Arch,
 		"stage_variant":    stage.Variant,
@@ -55,6 +60,9 @@ func toParams(stage *core.Stage) map[string]interface{} {
 		"stage_on_failure": stage.OnFailure,
 		"stage_depends_on": encodeSlice(stage.DependsOn),
 		"stage_labels":     encodeParams(stage.Labels),
+		"stage_elevated":   stage.Elevated,
+		"stage_matrix":     encodeParams(stage.Matrix),
+		"stage_attempts":   stage.Attempts,
 	}
 }
 
@@ -73,6 +81,7 @@ func encodeParams(v map[string]string) types.JSONText {
 func scanRow(scanner db.Scanner, dest *core.Stage) error {
 	depJSON := types.JSONText{}
 	labJSON := types.JSONText{}
+	matJSON := types.JSONText{}
 	err := scanner.Scan(
 		&dest.ID,
 		&dest.RepoID,
@@ -86,6 +95,11 @@ func scanRow(scanner db.Scanner, dest *core.Stage) error {
 		&dest.ErrIgnore,
 		&dest.ExitCode,
 		&dest.Limit,
+		&dest.LimitRepo,
+		&dest.Timeout,
+		&dest.Queued,
+		&dest.Dispatched,
+		&dest.Accepted,
 		&dest.OS,
 		&dest.Arch,
 		&dest.Variant,
@@ -100,9 +114,13 @@ func scanRow(scanner db.Scanner, dest *core.Stage) error {
 		&dest.OnFailure,
 		&depJSON,
 		&labJSON,
+		&dest.Elevated,
+		&matJSON,
+		&dest.Attempts,
 	)
 	json.Unmarshal(depJSON, &dest.DependsOn)
 	json.Unmarshal(labJSON, &dest.Labels)
+	json.Unmarshal(matJSON, &dest.Matrix)
 	return err
 }
 
@@ -111,6 +129,7 @@ func scanRow(scanner db.Scanner, dest *core.Stage) error {
 func scanRowStep(scanner db.Scanner, stage *core.Stage, step *nullStep) error {
 	depJSON := types.JSONText{}
 	labJSON := types.JSONText{}
+	matJSON := types.JSONText{}
 	err := scanner.Scan(
 		&stage.ID,
 		&stage.RepoID,
@@ -124,6 +143,11 @@ func scanRowStep(scanner db.Scanner, stage *core.Stage, step *nullStep) error {
 		&stage.ErrIgnore,
 		&stage.ExitCode,
 		&stage.Limit,
+		&stage.LimitRepo,
+		&stage.Timeout,
+		&stage.Queued,
+		&stage.Dispatched,
+		&stage.Accepted,
 		&stage.OS,
 		&stage.Arch,
 		&stage.Variant,
@@ -138,6 +162,9 @@ func scanRowStep(scanner db.Scanner, stage *core.Stage, step *nullStep) error {
 		&stage.OnFailure,
 		&depJSON,
 		&labJSON,
+		&stage.Elevated,
+		&matJSON,
+		&stage.Attempts,
 		&step.ID,
 		&step.StageID,
 		&step.Number,
@@ -146,12 +173,15 @@ func scanRowStep(scanner db.Scanner, stage *core.Stage, step *nullStep) error {
 		&step.Error,
 		&step.ErrIgnore,
 		&step.ExitCode,
+		&step.Attempts,
+		&step.Digest,
 		&step.Started,
 		&step.Stopped,
 		&step.Version,
 	)
 	json.Unmarshal(depJSON, &stage.DependsOn)
 	json.Unmarshal(labJSON, &stage.Labels)
+	json.Unmarshal(matJSON, &stage.Matrix)
 	return err
 }
 
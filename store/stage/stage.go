@@ -230,6 +230,11 @@ SELECT
 ,stage_errignore
 ,stage_exit_code
 ,stage_limit
+,stage_limit_repo
+,stage_timeout
+,stage_queued
+,stage_dispatched
+,stage_accepted
 ,stage_os
 ,stage_arch
 ,stage_variant
@@ -244,6 +249,9 @@ SELECT
 ,stage_on_failure
 ,stage_depends_on
 ,stage_labels
+,stage_elevated
+,stage_matrix
+,stage_attempts
 FROM stages
 `
 
@@ -297,6 +305,11 @@ SELECT
 ,stage_errignore
 ,stage_exit_code
 ,stage_limit
+,stage_limit_repo
+,stage_timeout
+,stage_queued
+,stage_dispatched
+,stage_accepted
 ,stage_os
 ,stage_arch
 ,stage_variant
@@ -311,6 +324,9 @@ SELECT
 ,stage_on_failure
 ,stage_depends_on
 ,stage_labels
+,stage_elevated
+,stage_matrix
+,stage_attempts
 ,step_id
 ,step_stage_id
 ,step_number
@@ -319,6 +335,8 @@ SELECT
 ,step_error
 ,step_errignore
 ,step_exit_code
+,step_attempts
+,step_digest
 ,step_started
 ,step_stopped
 ,step_version
@@ -343,6 +361,9 @@ SET
 ,stage_variant = :stage_variant
 ,stage_kernel = :stage_kernel
 ,stage_machine = :stage_machine
+,stage_queued = :stage_queued
+,stage_dispatched = :stage_dispatched
+,stage_accepted = :stage_accepted
 ,stage_started = :stage_started
 ,stage_stopped = :stage_stopped
 ,stage_created = :stage_created
@@ -352,6 +373,9 @@ SET
 ,stage_on_failure = :stage_on_failure
 ,stage_depends_on = :stage_depends_on
 ,stage_labels = :stage_labels
+,stage_elevated = :stage_elevated
+,stage_matrix = :stage_matrix
+,stage_attempts = :stage_attempts
 WHERE stage_id = :stage_id
   AND stage_version = :stage_version_old
 `
@@ -369,6 +393,11 @@ INSERT INTO stages (
 ,stage_errignore
 ,stage_exit_code
 ,stage_limit
+,stage_limit_repo
+,stage_timeout
+,stage_queued
+,stage_dispatched
+,stage_accepted
 ,stage_os
 ,stage_arch
 ,stage_variant
@@ -383,6 +412,9 @@ INSERT INTO stages (
 ,stage_on_failure
 ,stage_depends_on
 ,stage_labels
+,stage_elevated
+,stage_matrix
+,stage_attempts
 ) VALUES (
  :stage_repo_id
 ,:stage_build_id
@@ -395,6 +427,11 @@ INSERT INTO stages (
 ,:stage_errignore
 ,:stage_exit_code
 ,:stage_limit
+,:stage_limit_repo
+,:stage_timeout
+,:stage_queued
+,:stage_dispatched
+,:stage_accepted
 ,:stage_os
 ,:stage_arch
 ,:stage_variant
@@ -409,6 +446,9 @@ INSERT INTO stages (
 ,:stage_on_failure
 ,:stage_depends_on
 ,:stage_labels
+,:stage_elevated
+,:stage_matrix
+,:stage_attempts
 )
 `
 
@@ -425,6 +465,8 @@ INSERT INTO steps (
 ,step_error
 ,step_errignore
 ,step_exit_code
+,step_attempts
+,step_digest
 ,step_started
 ,step_stopped
 ,step_version
@@ -436,6 +478,8 @@ INSERT INTO steps (
 ,:step_error
 ,:step_errignore
 ,:step_exit_code
+,:step_attempts
+,:step_digest
 ,:step_started
 ,:step_stopped
 ,:step_version
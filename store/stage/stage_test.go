@@ -54,6 +54,8 @@ func testStageCreate(store *stageStore, build *core.Build) func(t *testing.T) {
 			ExitCode: 0,
 			Started:  1522878684,
 			Stopped:  0,
+			Matrix:   map[string]string{"GO_VERSION": "1.12"},
+			Attempts: 1,
 		}
 		err := store.Create(noContext, item)
 		if err != nil {
@@ -244,5 +246,11 @@ func testStage(item *core.Stage) func(t *testing.T) {
 		if got, want := item.RepoID, int64(42); got != want {
 			t.Errorf("Want RepoID %d, got %d", want, got)
 		}
+		if got, want := item.Matrix["GO_VERSION"], "1.12"; got != want {
+			t.Errorf("Want Matrix value %q, got %q", want, got)
+		}
+		if got, want := item.Attempts, 1; got != want {
+			t.Errorf("Want Attempts %d, got %d", want, got)
+		}
 	}
 }
@@ -30,6 +30,8 @@ type nullStep struct {
 	Error     sql.NullString
 	ErrIgnore sql.NullBool
 	ExitCode  sql.NullInt64
+	Attempts  sql.NullInt64
+	Digest    sql.NullString
 	Started   sql.NullInt64
 	Stopped   sql.NullInt64
 	Version   sql.NullInt64
@@ -45,6 +47,8 @@ func (s *nullStep) value() *core.Step {
 		Error:     s.Error.String,
 		ErrIgnore: s.ErrIgnore.Bool,
 		ExitCode:  int(s.ExitCode.Int64),
+		Attempts:  int(s.Attempts.Int64),
+		Digest:    s.Digest.String,
 		Started:   s.Started.Int64,
 		Stopped:   s.Stopped.Int64,
 		Version:   s.Version.Int64,
@@ -34,6 +34,8 @@ func toParams(from *core.Step) map[string]interface{} {
 		"step_error":     from.Error,
 		"step_errignore": from.ErrIgnore,
 		"step_exit_code": from.ExitCode,
+		"step_attempts":  from.Attempts,
+		"step_digest":    from.Digest,
 		"step_started":   from.Started,
 		"step_stopped":   from.Stopped,
 		"step_version":   from.Version,
@@ -52,6 +54,8 @@ func scanRow(scanner db.Scanner, dest *core.Step) error {
 		&dest.Error,
 		&dest.ErrIgnore,
 		&dest.ExitCode,
+		&dest.Attempts,
+		&dest.Digest,
 		&dest.Started,
 		&dest.Stopped,
 		&dest.Version,
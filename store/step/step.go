@@ -154,6 +154,8 @@ SELECT
 ,step_error
 ,step_errignore
 ,step_exit_code
+,step_attempts
+,step_digest
 ,step_started
 ,step_stopped
 ,step_version
@@ -183,6 +185,8 @@ SET
 ,step_error = :step_error
 ,step_errignore = :step_errignore
 ,step_exit_code = :step_exit_code
+,step_attempts = :step_attempts
+,step_digest = :step_digest
 ,step_started = :step_started
 ,step_stopped = :step_stopped
 ,step_version = :step_version_new
@@ -199,6 +203,8 @@ INSERT INTO steps (
 ,step_error
 ,step_errignore
 ,step_exit_code
+,step_attempts
+,step_digest
 ,step_started
 ,step_stopped
 ,step_version
@@ -210,6 +216,8 @@ INSERT INTO steps (
 ,:step_error
 ,:step_errignore
 ,:step_exit_code
+,:step_attempts
+,:step_digest
 ,:step_started
 ,:step_stopped
 ,:step_version
@@ -124,6 +124,7 @@ func testStepUpdate(store *stepStore, step *core.Step) func(t *testing.T) {
 			Started:  1522878684,
 			Stopped:  1522878690,
 			Status:   core.StatusFailing,
+			Digest:   "golang@sha256:3e8d8f8a",
 			Version:  step.Version,
 		}
 		err := store.Update(noContext, before)
@@ -151,6 +152,9 @@ func testStepUpdate(store *stepStore, step *core.Step) func(t *testing.T) {
 		if got, want := after.Stopped, before.Stopped; got != want {
 			t.Errorf("Want updated Stopped %v, got %v", want, got)
 		}
+		if got, want := after.Digest, before.Digest; got != want {
+			t.Errorf("Want updated Digest %v, got %v", want, got)
+		}
 	}
 }
 
@@ -0,0 +1,86 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tests
+
+import (
+	"database/sql"
+	"encoding/json"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/store/shared/db"
+
+	"github.com/jmoiron/sqlx/types"
+)
+
+// helper function converts the TestResult structure to a set
+// of named query parameters.
+func toParams(result *core.TestResult) map[string]interface{} {
+	return map[string]interface{}{
+		"test_id":       result.ID,
+		"test_build_id": result.BuildID,
+		"test_stage_id": result.StageID,
+		"test_step_id":  result.StepID,
+		"test_tests":    result.Tests,
+		"test_failures": result.Failures,
+		"test_errors":   result.Errors,
+		"test_skipped":  result.Skipped,
+		"test_duration": result.Duration,
+		"test_failed":   encodeCases(result.Failed),
+		"test_created":  result.Created,
+	}
+}
+
+func encodeCases(v []*core.TestCase) types.JSONText {
+	raw, _ := json.Marshal(v)
+	return types.JSONText(raw)
+}
+
+// helper function scans the sql.Row and copies the column
+// values to the destination object.
+func scanRow(scanner db.Scanner, dest *core.TestResult) error {
+	failedJSON := types.JSONText{}
+	err := scanner.Scan(
+		&dest.ID,
+		&dest.BuildID,
+		&dest.StageID,
+		&dest.StepID,
+		&dest.Tests,
+		&dest.Failures,
+		&dest.Errors,
+		&dest.Skipped,
+		&dest.Duration,
+		&failedJSON,
+		&dest.Created,
+	)
+	json.Unmarshal(failedJSON, &dest.Failed)
+	return err
+}
+
+// helper function scans the sql.Row and copies the column
+// values to the destination object.
+func scanRows(rows *sql.Rows) ([]*core.TestResult, error) {
+	defer rows.Close()
+
+	results := []*core.TestResult{}
+	for rows.Next() {
+		result := new(core.TestResult)
+		err := scanRow(rows, result)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
@@ -0,0 +1,138 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tests
+
+import (
+	"context"
+	"time"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/store/shared/db"
+)
+
+// New returns a new TestResultStore.
+func New(db *db.DB) core.TestResultStore {
+	return &testStore{db}
+}
+
+type testStore struct {
+	db *db.DB
+}
+
+func (s *testStore) List(ctx context.Context, id int64) ([]*core.TestResult, error) {
+	var out []*core.TestResult
+	err := s.db.View(func(queryer db.Queryer, binder db.Binder) error {
+		params := map[string]interface{}{"test_build_id": id}
+		stmt, args, err := binder.BindNamed(queryBuild, params)
+		if err != nil {
+			return err
+		}
+		rows, err := queryer.Query(stmt, args...)
+		if err != nil {
+			return err
+		}
+		out, err = scanRows(rows)
+		return err
+	})
+	return out, err
+}
+
+func (s *testStore) Create(ctx context.Context, result *core.TestResult) error {
+	if result.Created == 0 {
+		result.Created = time.Now().Unix()
+	}
+	if s.db.Driver() == db.Postgres {
+		return s.createPostgres(ctx, result)
+	}
+	return s.create(ctx, result)
+}
+
+func (s *testStore) create(ctx context.Context, result *core.TestResult) error {
+	return s.db.Lock(func(execer db.Execer, binder db.Binder) error {
+		params := toParams(result)
+		stmt, args, err := binder.BindNamed(stmtInsert, params)
+		if err != nil {
+			return err
+		}
+		res, err := execer.Exec(stmt, args...)
+		if err != nil {
+			return err
+		}
+		result.ID, err = res.LastInsertId()
+		return err
+	})
+}
+
+func (s *testStore) createPostgres(ctx context.Context, result *core.TestResult) error {
+	return s.db.Lock(func(execer db.Execer, binder db.Binder) error {
+		params := toParams(result)
+		stmt, args, err := binder.BindNamed(stmtInsertPg, params)
+		if err != nil {
+			return err
+		}
+		return execer.QueryRow(stmt, args...).Scan(&result.ID)
+	})
+}
+
+const queryBase = `
+SELECT
+ test_id
+,test_build_id
+,test_stage_id
+,test_step_id
+,test_tests
+,test_failures
+,test_errors
+,test_skipped
+,test_duration
+,test_failed
+,test_created
+`
+
+const queryBuild = queryBase + `
+FROM test_results
+WHERE test_build_id = :test_build_id
+ORDER BY test_step_id, test_id
+`
+
+const stmtInsert = `
+INSERT INTO test_results (
+ test_build_id
+,test_stage_id
+,test_step_id
+,test_tests
+,test_failures
+,test_errors
+,test_skipped
+,test_duration
+,test_failed
+,test_created
+) VALUES (
+ :test_build_id
+,:test_stage_id
+,:test_step_id
+,:test_tests
+,:test_failures
+,:test_errors
+,:test_skipped
+,:test_duration
+,:test_failed
+,:test_created
+)
+`
+
+const stmtInsertPg = stmtInsert + `
+RETURNING test_id
+`
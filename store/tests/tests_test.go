@@ -0,0 +1,104 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package tests
+
+import (
+	"context"
+	"testing"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/store/build"
+	"github.com/drone/drone/store/repos"
+	"github.com/drone/drone/store/shared/db/dbtest"
+	"github.com/drone/drone/store/step"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+var noContext = context.TODO()
+
+func TestTests(t *testing.T) {
+	conn, err := dbtest.Connect()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer func() {
+		dbtest.Reset(conn)
+		dbtest.Disconnect(conn)
+	}()
+
+	// seed with a dummy repository
+	arepo := &core.Repository{UID: "1", Slug: "octocat/hello-world"}
+	repos := repos.New(conn)
+	repos.Create(noContext, arepo)
+
+	// seed with a dummy stage
+	stage := &core.Stage{Number: 1}
+	stages := []*core.Stage{stage}
+
+	// seed with a dummy build
+	abuild := &core.Build{Number: 1, RepoID: arepo.ID}
+	builds := build.New(conn)
+	builds.Create(noContext, abuild, stages)
+
+	// seed with a dummy step
+	astep := &core.Step{Number: 1, StageID: stage.ID}
+	steps := step.New(conn)
+	steps.Create(noContext, astep)
+
+	result := &core.TestResult{
+		BuildID:  abuild.ID,
+		StageID:  stage.ID,
+		StepID:   astep.ID,
+		Tests:    10,
+		Failures: 1,
+		Skipped:  2,
+		Duration: 1500,
+		Failed: []*core.TestCase{
+			{
+				Suite:   "github.com/octocat/hello-world",
+				Name:    "TestHello",
+				Message: "want hello, got goodbye",
+			},
+		},
+	}
+
+	store := New(conn).(*testStore)
+	t.Run("Create", testResultCreate(store, result))
+	t.Run("List", testResultList(store, result))
+}
+
+func testResultCreate(store *testStore, result *core.TestResult) func(t *testing.T) {
+	return func(t *testing.T) {
+		err := store.Create(noContext, result)
+		if err != nil {
+			t.Error(err)
+		}
+		if result.ID == 0 {
+			t.Errorf("Want test result ID assigned, got %d", result.ID)
+		}
+		if result.Created == 0 {
+			t.Errorf("Want test result created timestamp assigned")
+		}
+	}
+}
+
+func testResultList(store *testStore, result *core.TestResult) func(t *testing.T) {
+	return func(t *testing.T) {
+		list, err := store.List(noContext, result.BuildID)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if got, want := len(list), 1; got != want {
+			t.Errorf("Want %d test results, got %d", want, got)
+			return
+		}
+		if diff := cmp.Diff(list[0], result); diff != "" {
+			t.Errorf(diff)
+		}
+	}
+}
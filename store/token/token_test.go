@@ -0,0 +1,149 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package token
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/store/shared/db/dbtest"
+	"github.com/drone/drone/store/user"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+var noContext = context.TODO()
+
+func TestToken(t *testing.T) {
+	conn, err := dbtest.Connect()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer func() {
+		dbtest.Reset(conn)
+		dbtest.Disconnect(conn)
+	}()
+
+	// seed with a dummy user
+	auser := &core.User{Login: "octocat"}
+	users := user.New(conn)
+	users.Create(noContext, auser)
+
+	token := &core.Token{
+		UserID:  auser.ID,
+		Name:    "ci",
+		Scopes:  []string{core.ScopeReadBuild, core.ScopeWriteBuild},
+		Hash:    "ulSxuA0FKjNiOFIchk18NNvC6ygSxdtKjiOAS",
+		Expires: 1524251054,
+	}
+
+	store := New(conn).(*tokenStore)
+	t.Run("Create", testTokenCreate(store, token))
+	t.Run("Find", testTokenFind(store, token))
+	t.Run("FindHash", testTokenFindHash(store, token))
+	t.Run("List", testTokenList(store, token))
+	t.Run("Delete", testTokenDelete(store, token))
+	t.Run("DeleteUser", testTokenDeleteUser(store, auser))
+}
+
+func testTokenCreate(store *tokenStore, token *core.Token) func(t *testing.T) {
+	return func(t *testing.T) {
+		err := store.Create(noContext, token)
+		if err != nil {
+			t.Error(err)
+		}
+		if token.ID == 0 {
+			t.Errorf("Want token ID assigned, got %d", token.ID)
+		}
+		if token.Created == 0 {
+			t.Errorf("Want token created timestamp assigned")
+		}
+	}
+}
+
+func testTokenFind(store *tokenStore, token *core.Token) func(t *testing.T) {
+	return func(t *testing.T) {
+		got, err := store.Find(noContext, token.ID)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if diff := cmp.Diff(got, token); diff != "" {
+			t.Errorf(diff)
+		}
+	}
+}
+
+func testTokenFindHash(store *tokenStore, token *core.Token) func(t *testing.T) {
+	return func(t *testing.T) {
+		got, err := store.FindHash(noContext, token.Hash)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if diff := cmp.Diff(got, token); diff != "" {
+			t.Errorf(diff)
+		}
+	}
+}
+
+func testTokenList(store *tokenStore, token *core.Token) func(t *testing.T) {
+	return func(t *testing.T) {
+		list, err := store.List(noContext, token.UserID)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if got, want := len(list), 1; got != want {
+			t.Errorf("Want %d tokens, got %d", want, got)
+			return
+		}
+		if diff := cmp.Diff(list[0], token); diff != "" {
+			t.Errorf(diff)
+		}
+	}
+}
+
+func testTokenDelete(store *tokenStore, token *core.Token) func(t *testing.T) {
+	return func(t *testing.T) {
+		err := store.Delete(noContext, token)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		_, err = store.Find(noContext, token.ID)
+		if got, want := err, sql.ErrNoRows; got != want {
+			t.Errorf("Want sql.ErrNoRows, got %v", got)
+		}
+	}
+}
+
+func testTokenDeleteUser(store *tokenStore, user *core.User) func(t *testing.T) {
+	return func(t *testing.T) {
+		for _, name := range []string{"ci", "cli"} {
+			token := &core.Token{UserID: user.ID, Name: name, Hash: name}
+			if err := store.Create(noContext, token); err != nil {
+				t.Error(err)
+				return
+			}
+		}
+		err := store.DeleteUser(noContext, user.ID)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		list, err := store.List(noContext, user.ID)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if got, want := len(list), 0; got != want {
+			t.Errorf("Want %d tokens, got %d", want, got)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package variable
+
+import (
+	"database/sql"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/store/shared/db"
+	"github.com/drone/drone/store/shared/encrypt"
+)
+
+// helper function converts the Variable structure to a set
+// of named query parameters.
+func toParams(encrypt encrypt.Encrypter, variable *core.Variable) (map[string]interface{}, error) {
+	ciphertext, err := encrypt.Encrypt(variable.Value)
+	if err != nil {
+		return nil, err
+	}
+	// server and organization variables are not associated
+	// with a repository, and are stored with a null repository
+	// identifier to satisfy the foreign key constraint.
+	repoID := sql.NullInt64{
+		Int64: variable.RepoID,
+		Valid: variable.RepoID != 0,
+	}
+	return map[string]interface{}{
+		"variable_id":        variable.ID,
+		"variable_repo_id":   repoID,
+		"variable_namespace": variable.Namespace,
+		"variable_name":      variable.Name,
+		"variable_value":     ciphertext,
+		"variable_secret":    variable.Secret,
+		"variable_created":   variable.Created,
+		"variable_updated":   variable.Updated,
+	}, nil
+}
+
+// helper function scans the sql.Row and copies the column
+// values to the destination object. It returns true if the
+// value is not encrypted with the current encryption key.
+func scanRow(enc encrypt.Encrypter, scanner db.Scanner, dst *core.Variable) (bool, error) {
+	var ciphertext []byte
+	var repoID sql.NullInt64
+	err := scanner.Scan(
+		&dst.ID,
+		&repoID,
+		&dst.Namespace,
+		&dst.Name,
+		&ciphertext,
+		&dst.Secret,
+		&dst.Created,
+		&dst.Updated,
+	)
+	if err != nil {
+		return false, err
+	}
+	dst.RepoID = repoID.Int64
+	plaintext, err := enc.Decrypt(ciphertext)
+	if err != nil {
+		return false, err
+	}
+	dst.Value = plaintext
+	return encrypt.Stale(enc, ciphertext), nil
+}
+
+// helper function scans the sql.Row and copies the column
+// values to the destination object.
+func scanRows(encrypt encrypt.Encrypter, rows *sql.Rows) ([]*core.Variable, error) {
+	defer rows.Close()
+
+	list := []*core.Variable{}
+	for rows.Next() {
+		variable := new(core.Variable)
+		_, err := scanRow(encrypt, rows, variable)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, variable)
+	}
+	return list, nil
+}
+
+// helper function scans the sql.Rows and returns the list of
+// variables that are not encrypted with the current encryption
+// key.
+func scanStale(enc encrypt.Encrypter, rows *sql.Rows) ([]*core.Variable, error) {
+	defer rows.Close()
+
+	stale := []*core.Variable{}
+	for rows.Next() {
+		variable := new(core.Variable)
+		ok, err := scanRow(enc, rows, variable)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			stale = append(stale, variable)
+		}
+	}
+	return stale, nil
+}
@@ -0,0 +1,268 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package variable
+
+import (
+	"context"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/store/shared/db"
+	"github.com/drone/drone/store/shared/encrypt"
+)
+
+// New returns a new Variable database store.
+func New(db *db.DB, enc encrypt.Encrypter) core.VariableStore {
+	return &variableStore{
+		db:  db,
+		enc: enc,
+	}
+}
+
+type variableStore struct {
+	db  *db.DB
+	enc encrypt.Encrypter
+}
+
+func (s *variableStore) List(ctx context.Context) ([]*core.Variable, error) {
+	var out []*core.Variable
+	err := s.db.View(func(queryer db.Queryer, binder db.Binder) error {
+		rows, err := queryer.Query(queryAll)
+		if err != nil {
+			return err
+		}
+		out, err = scanRows(s.enc, rows)
+		return err
+	})
+	return out, err
+}
+
+func (s *variableStore) ListRepo(ctx context.Context, id int64) ([]*core.Variable, error) {
+	var out []*core.Variable
+	err := s.db.View(func(queryer db.Queryer, binder db.Binder) error {
+		params := map[string]interface{}{"variable_repo_id": id}
+		stmt, args, err := binder.BindNamed(queryRepo, params)
+		if err != nil {
+			return err
+		}
+		rows, err := queryer.Query(stmt, args...)
+		if err != nil {
+			return err
+		}
+		out, err = scanRows(s.enc, rows)
+		return err
+	})
+	return out, err
+}
+
+func (s *variableStore) ListNamespace(ctx context.Context, namespace string) ([]*core.Variable, error) {
+	var out []*core.Variable
+	err := s.db.View(func(queryer db.Queryer, binder db.Binder) error {
+		params := map[string]interface{}{"variable_namespace": namespace}
+		stmt, args, err := binder.BindNamed(queryNamespace, params)
+		if err != nil {
+			return err
+		}
+		rows, err := queryer.Query(stmt, args...)
+		if err != nil {
+			return err
+		}
+		out, err = scanRows(s.enc, rows)
+		return err
+	})
+	return out, err
+}
+
+func (s *variableStore) Find(ctx context.Context, id int64) (*core.Variable, error) {
+	out := &core.Variable{ID: id}
+	err := s.db.View(func(queryer db.Queryer, binder db.Binder) error {
+		params, err := toParams(s.enc, out)
+		if err != nil {
+			return err
+		}
+		query, args, err := binder.BindNamed(queryKey, params)
+		if err != nil {
+			return err
+		}
+		row := queryer.QueryRow(query, args...)
+		_, err = scanRow(s.enc, row, out)
+		return err
+	})
+	return out, err
+}
+
+func (s *variableStore) Create(ctx context.Context, variable *core.Variable) error {
+	if s.db.Driver() == db.Postgres {
+		return s.createPostgres(ctx, variable)
+	}
+	return s.create(ctx, variable)
+}
+
+func (s *variableStore) create(ctx context.Context, variable *core.Variable) error {
+	return s.db.Lock(func(execer db.Execer, binder db.Binder) error {
+		params, err := toParams(s.enc, variable)
+		if err != nil {
+			return err
+		}
+		stmt, args, err := binder.BindNamed(stmtInsert, params)
+		if err != nil {
+			return err
+		}
+		res, err := execer.Exec(stmt, args...)
+		if err != nil {
+			return err
+		}
+		variable.ID, err = res.LastInsertId()
+		return err
+	})
+}
+
+func (s *variableStore) createPostgres(ctx context.Context, variable *core.Variable) error {
+	return s.db.Lock(func(execer db.Execer, binder db.Binder) error {
+		params, err := toParams(s.enc, variable)
+		if err != nil {
+			return err
+		}
+		stmt, args, err := binder.BindNamed(stmtInsertPg, params)
+		if err != nil {
+			return err
+		}
+		return execer.QueryRow(stmt, args...).Scan(&variable.ID)
+	})
+}
+
+func (s *variableStore) Update(ctx context.Context, variable *core.Variable) error {
+	return s.db.Lock(func(execer db.Execer, binder db.Binder) error {
+		params, err := toParams(s.enc, variable)
+		if err != nil {
+			return err
+		}
+		stmt, args, err := binder.BindNamed(stmtUpdate, params)
+		if err != nil {
+			return err
+		}
+		_, err = execer.Exec(stmt, args...)
+		return err
+	})
+}
+
+func (s *variableStore) Delete(ctx context.Context, variable *core.Variable) error {
+	return s.db.Lock(func(execer db.Execer, binder db.Binder) error {
+		params, err := toParams(s.enc, variable)
+		if err != nil {
+			return err
+		}
+		stmt, args, err := binder.BindNamed(stmtDelete, params)
+		if err != nil {
+			return err
+		}
+		_, err = execer.Exec(stmt, args...)
+		return err
+	})
+}
+
+func (s *variableStore) Rotate(ctx context.Context) (int64, error) {
+	var stale []*core.Variable
+	err := s.db.View(func(queryer db.Queryer, binder db.Binder) error {
+		rows, err := queryer.Query(queryAll)
+		if err != nil {
+			return err
+		}
+		stale, err = scanStale(s.enc, rows)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	for i, variable := range stale {
+		if err := s.Update(ctx, variable); err != nil {
+			return int64(i), err
+		}
+	}
+	return int64(len(stale)), nil
+}
+
+const queryBase = `
+SELECT
+ variable_id
+,variable_repo_id
+,variable_namespace
+,variable_name
+,variable_value
+,variable_secret
+,variable_created
+,variable_updated
+`
+
+const queryAll = queryBase + `
+FROM variables
+ORDER BY variable_id
+`
+
+const queryKey = queryBase + `
+FROM variables
+WHERE variable_id = :variable_id
+LIMIT 1
+`
+
+const queryRepo = queryBase + `
+FROM variables
+WHERE variable_repo_id = :variable_repo_id
+ORDER BY variable_id
+`
+
+const queryNamespace = queryBase + `
+FROM variables
+WHERE variable_namespace = :variable_namespace
+  AND variable_repo_id IS NULL
+ORDER BY variable_id
+`
+
+const stmtUpdate = `
+UPDATE variables SET
+ variable_name = :variable_name
+,variable_value = :variable_value
+,variable_secret = :variable_secret
+,variable_updated = :variable_updated
+WHERE variable_id = :variable_id
+`
+
+const stmtDelete = `
+DELETE FROM variables
+WHERE variable_id = :variable_id
+`
+
+const stmtInsert = `
+INSERT INTO variables (
+ variable_repo_id
+,variable_namespace
+,variable_name
+,variable_value
+,variable_secret
+,variable_created
+,variable_updated
+) VALUES (
+ :variable_repo_id
+,:variable_namespace
+,:variable_name
+,:variable_value
+,:variable_secret
+,:variable_created
+,:variable_updated
+)
+`
+
+const stmtInsertPg = stmtInsert + `
+RETURNING variable_id
+`
@@ -0,0 +1,241 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package variable
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/store/repos"
+	"github.com/drone/drone/store/shared/db/dbtest"
+	"github.com/drone/drone/store/shared/encrypt"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+var noContext = context.TODO()
+
+func TestVariable(t *testing.T) {
+	conn, err := dbtest.Connect()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer func() {
+		dbtest.Reset(conn)
+		dbtest.Disconnect(conn)
+	}()
+
+	// seeds the database with a dummy repository.
+	repo := &core.Repository{UID: "1", Slug: "octocat/hello-world", Namespace: "octocat"}
+	repos := repos.New(conn)
+	if err := repos.Create(noContext, repo); err != nil {
+		t.Error(err)
+	}
+
+	store := New(conn, nil).(*variableStore)
+	store.enc, _ = encrypt.New("fb4b4d6267c8a5ce8231f8b186dbca92")
+	t.Run("Create", testVariableCreate(store, repos, repo))
+	t.Run("Namespace", testVariableNamespace(store))
+}
+
+func testVariableCreate(store *variableStore, repos core.RepositoryStore, repo *core.Repository) func(t *testing.T) {
+	return func(t *testing.T) {
+		item := &core.Variable{
+			RepoID: repo.ID,
+			Name:   "NPM_TOKEN",
+			Value:  "correct-horse-battery-staple",
+			Secret: true,
+		}
+		err := store.Create(noContext, item)
+		if err != nil {
+			t.Error(err)
+		}
+		if item.ID == 0 {
+			t.Errorf("Want variable ID assigned, got %d", item.ID)
+		}
+
+		t.Run("Find", testVariableFind(store, item))
+		t.Run("ListRepo", testVariableListRepo(store, repo, item))
+		t.Run("Update", testVariableUpdate(store, item))
+		t.Run("Rotate", testVariableRotate(store, item))
+		t.Run("Delete", testVariableDelete(store, item))
+		t.Run("Fkey", testVariableForeignKey(store, repos, repo))
+	}
+}
+
+func testVariableFind(store *variableStore, variable *core.Variable) func(t *testing.T) {
+	return func(t *testing.T) {
+		item, err := store.Find(noContext, variable.ID)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if diff := cmp.Diff(item, variable); diff != "" {
+			t.Errorf(diff)
+		}
+	}
+}
+
+func testVariableListRepo(store *variableStore, repo *core.Repository, variable *core.Variable) func(t *testing.T) {
+	return func(t *testing.T) {
+		list, err := store.ListRepo(noContext, repo.ID)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if got, want := len(list), 1; got != want {
+			t.Errorf("Want count %d, got %d", want, got)
+		} else if diff := cmp.Diff(list[0], variable); diff != "" {
+			t.Errorf(diff)
+		}
+	}
+}
+
+func testVariableUpdate(store *variableStore, variable *core.Variable) func(t *testing.T) {
+	return func(t *testing.T) {
+		before, err := store.Find(noContext, variable.ID)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		before.Value = "hunter2"
+		before.Secret = false
+		err = store.Update(noContext, before)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		after, err := store.Find(noContext, variable.ID)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if diff := cmp.Diff(after, before); diff != "" {
+			t.Errorf(diff)
+		}
+	}
+}
+
+func testVariableRotate(store *variableStore, variable *core.Variable) func(t *testing.T) {
+	return func(t *testing.T) {
+		before, err := store.Find(noContext, variable.ID)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		store.enc, _ = encrypt.NewKeyring(
+			"0a8f63b5d2e84a5f9c7e1b3d6f2a4c8e",
+			false,
+			"fb4b4d6267c8a5ce8231f8b186dbca92",
+		)
+		n, err := store.Rotate(noContext)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if n == 0 {
+			t.Errorf("Want variables rotated")
+		}
+		n, _ = store.Rotate(noContext)
+		if got, want := n, int64(0); got != want {
+			t.Errorf("Want %d variables rotated, got %d", want, got)
+		}
+
+		// the previous key is no longer required once the
+		// rotation is complete.
+		store.enc, _ = encrypt.NewKeyring("0a8f63b5d2e84a5f9c7e1b3d6f2a4c8e", false)
+		after, err := store.Find(noContext, variable.ID)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if got, want := after.Value, before.Value; got != want {
+			t.Errorf("Want value %q, got %q", want, got)
+		}
+	}
+}
+
+func testVariableDelete(store *variableStore, variable *core.Variable) func(t *testing.T) {
+	return func(t *testing.T) {
+		err := store.Delete(noContext, variable)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		_, err = store.Find(noContext, variable.ID)
+		if got, want := sql.ErrNoRows, err; got != want {
+			t.Errorf("Want sql.ErrNoRows, got %v", got)
+		}
+	}
+}
+
+func testVariableForeignKey(store *variableStore, repos core.RepositoryStore, repo *core.Repository) func(t *testing.T) {
+	return func(t *testing.T) {
+		item := &core.Variable{
+			RepoID: repo.ID,
+			Name:   "GOPROXY",
+			Value:  "https://proxy.golang.org",
+		}
+		store.Create(noContext, item)
+		before, _ := store.ListRepo(noContext, repo.ID)
+		if len(before) == 0 {
+			t.Errorf("Want non-empty variable list")
+			return
+		}
+
+		err := repos.Delete(noContext, repo)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		after, _ := store.ListRepo(noContext, repo.ID)
+		if len(after) != 0 {
+			t.Errorf("Want empty variable list")
+		}
+	}
+}
+
+func testVariableNamespace(store *variableStore) func(t *testing.T) {
+	return func(t *testing.T) {
+		global := &core.Variable{Name: "GOPROXY", Value: "https://proxy.golang.org"}
+		if err := store.Create(noContext, global); err != nil {
+			t.Error(err)
+			return
+		}
+		org := &core.Variable{Namespace: "octocat", Name: "GOPRIVATE", Value: "github.com/octocat"}
+		if err := store.Create(noContext, org); err != nil {
+			t.Error(err)
+			return
+		}
+
+		list, err := store.ListNamespace(noContext, "")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if got, want := len(list), 1; got != want {
+			t.Errorf("Want %d server variables, got %d", want, got)
+		}
+		list, err = store.ListNamespace(noContext, "octocat")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if got, want := len(list), 1; got != want {
+			t.Errorf("Want %d organization variables, got %d", want, got)
+		}
+		list, err = store.List(noContext)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if got, want := len(list), 2; got != want {
+			t.Errorf("Want %d variables, got %d", want, got)
+		}
+	}
+}
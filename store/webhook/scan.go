@@ -0,0 +1,101 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"database/sql"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/store/shared/db"
+	"github.com/drone/drone/store/shared/encrypt"
+)
+
+// helper function converts the Webhook structure to a set
+// of named query parameters.
+func toParams(encrypt encrypt.Encrypter, webhook *core.RepoWebhook) (map[string]interface{}, error) {
+	ciphertext, err := encrypt.Encrypt(webhook.Secret)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"webhook_id":       webhook.ID,
+		"webhook_repo_id":  webhook.RepoID,
+		"webhook_endpoint": webhook.Endpoint,
+		"webhook_secret":   ciphertext,
+		"webhook_created":  webhook.Created,
+		"webhook_updated":  webhook.Updated,
+	}, nil
+}
+
+// helper function scans the sql.Row and copies the column
+// values to the destination object. It returns true if the
+// value is not encrypted with the current encryption key.
+func scanRow(enc encrypt.Encrypter, scanner db.Scanner, dst *core.RepoWebhook) (bool, error) {
+	var ciphertext []byte
+	err := scanner.Scan(
+		&dst.ID,
+		&dst.RepoID,
+		&dst.Endpoint,
+		&ciphertext,
+		&dst.Created,
+		&dst.Updated,
+	)
+	if err != nil {
+		return false, err
+	}
+	plaintext, err := enc.Decrypt(ciphertext)
+	if err != nil {
+		return false, err
+	}
+	dst.Secret = plaintext
+	return encrypt.Stale(enc, ciphertext), nil
+}
+
+// helper function scans the sql.Row and copies the column
+// values to the destination object.
+func scanRows(encrypt encrypt.Encrypter, rows *sql.Rows) ([]*core.RepoWebhook, error) {
+	defer rows.Close()
+
+	webhooks := []*core.RepoWebhook{}
+	for rows.Next() {
+		webhook := new(core.RepoWebhook)
+		_, err := scanRow(encrypt, rows, webhook)
+		if err != nil {
+			return nil, err
+		}
+		webhooks = append(webhooks, webhook)
+	}
+	return webhooks, nil
+}
+
+// helper function scans the sql.Rows and returns the list of
+// webhooks that are not encrypted with the current encryption
+// key.
+func scanStale(enc encrypt.Encrypter, rows *sql.Rows) ([]*core.RepoWebhook, error) {
+	defer rows.Close()
+
+	stale := []*core.RepoWebhook{}
+	for rows.Next() {
+		webhook := new(core.RepoWebhook)
+		ok, err := scanRow(enc, rows, webhook)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			stale = append(stale, webhook)
+		}
+	}
+	return stale, nil
+}
@@ -0,0 +1,223 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"context"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/store/shared/db"
+	"github.com/drone/drone/store/shared/encrypt"
+)
+
+// New returns a new repository Webhook database store.
+func New(db *db.DB, enc encrypt.Encrypter) core.RepoWebhookStore {
+	return &webhookStore{
+		db:  db,
+		enc: enc,
+	}
+}
+
+type webhookStore struct {
+	db  *db.DB
+	enc encrypt.Encrypter
+}
+
+func (s *webhookStore) List(ctx context.Context, id int64) ([]*core.RepoWebhook, error) {
+	var out []*core.RepoWebhook
+	err := s.db.View(func(queryer db.Queryer, binder db.Binder) error {
+		params := map[string]interface{}{"webhook_repo_id": id}
+		stmt, args, err := binder.BindNamed(queryRepo, params)
+		if err != nil {
+			return err
+		}
+		rows, err := queryer.Query(stmt, args...)
+		if err != nil {
+			return err
+		}
+		out, err = scanRows(s.enc, rows)
+		return err
+	})
+	return out, err
+}
+
+func (s *webhookStore) Find(ctx context.Context, id int64) (*core.RepoWebhook, error) {
+	out := &core.RepoWebhook{ID: id}
+	err := s.db.View(func(queryer db.Queryer, binder db.Binder) error {
+		params, err := toParams(s.enc, out)
+		if err != nil {
+			return err
+		}
+		query, args, err := binder.BindNamed(queryKey, params)
+		if err != nil {
+			return err
+		}
+		row := queryer.QueryRow(query, args...)
+		_, err = scanRow(s.enc, row, out)
+		return err
+	})
+	return out, err
+}
+
+func (s *webhookStore) Create(ctx context.Context, webhook *core.RepoWebhook) error {
+	if s.db.Driver() == db.Postgres {
+		return s.createPostgres(ctx, webhook)
+	}
+	return s.create(ctx, webhook)
+}
+
+func (s *webhookStore) create(ctx context.Context, webhook *core.RepoWebhook) error {
+	return s.db.Lock(func(execer db.Execer, binder db.Binder) error {
+		params, err := toParams(s.enc, webhook)
+		if err != nil {
+			return err
+		}
+		stmt, args, err := binder.BindNamed(stmtInsert, params)
+		if err != nil {
+			return err
+		}
+		res, err := execer.Exec(stmt, args...)
+		if err != nil {
+			return err
+		}
+		webhook.ID, err = res.LastInsertId()
+		return err
+	})
+}
+
+func (s *webhookStore) createPostgres(ctx context.Context, webhook *core.RepoWebhook) error {
+	return s.db.Lock(func(execer db.Execer, binder db.Binder) error {
+		params, err := toParams(s.enc, webhook)
+		if err != nil {
+			return err
+		}
+		stmt, args, err := binder.BindNamed(stmtInsertPg, params)
+		if err != nil {
+			return err
+		}
+		return execer.QueryRow(stmt, args...).Scan(&webhook.ID)
+	})
+}
+
+func (s *webhookStore) Update(ctx context.Context, webhook *core.RepoWebhook) error {
+	return s.db.Lock(func(execer db.Execer, binder db.Binder) error {
+		params, err := toParams(s.enc, webhook)
+		if err != nil {
+			return err
+		}
+		stmt, args, err := binder.BindNamed(stmtUpdate, params)
+		if err != nil {
+			return err
+		}
+		_, err = execer.Exec(stmt, args...)
+		return err
+	})
+}
+
+func (s *webhookStore) Delete(ctx context.Context, webhook *core.RepoWebhook) error {
+	return s.db.Lock(func(execer db.Execer, binder db.Binder) error {
+		params, err := toParams(s.enc, webhook)
+		if err != nil {
+			return err
+		}
+		stmt, args, err := binder.BindNamed(stmtDelete, params)
+		if err != nil {
+			return err
+		}
+		_, err = execer.Exec(stmt, args...)
+		return err
+	})
+}
+
+func (s *webhookStore) Rotate(ctx context.Context) (int64, error) {
+	var stale []*core.RepoWebhook
+	err := s.db.View(func(queryer db.Queryer, binder db.Binder) error {
+		rows, err := queryer.Query(queryAll)
+		if err != nil {
+			return err
+		}
+		stale, err = scanStale(s.enc, rows)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	for i, webhook := range stale {
+		if err := s.Update(ctx, webhook); err != nil {
+			return int64(i), err
+		}
+	}
+	return int64(len(stale)), nil
+}
+
+const queryBase = `
+SELECT
+ webhook_id
+,webhook_repo_id
+,webhook_endpoint
+,webhook_secret
+,webhook_created
+,webhook_updated
+`
+
+const queryAll = queryBase + `
+FROM repo_webhooks
+ORDER BY webhook_id
+`
+
+const queryKey = queryBase + `
+FROM repo_webhooks
+WHERE webhook_id = :webhook_id
+LIMIT 1
+`
+
+const queryRepo = queryBase + `
+FROM repo_webhooks
+WHERE webhook_repo_id = :webhook_repo_id
+ORDER BY webhook_id
+`
+
+const stmtUpdate = `
+UPDATE repo_webhooks SET
+ webhook_endpoint = :webhook_endpoint
+,webhook_secret = :webhook_secret
+,webhook_updated = :webhook_updated
+WHERE webhook_id = :webhook_id
+`
+
+const stmtDelete = `
+DELETE FROM repo_webhooks
+WHERE webhook_id = :webhook_id
+`
+
+const stmtInsert = `
+INSERT INTO repo_webhooks (
+ webhook_repo_id
+,webhook_endpoint
+,webhook_secret
+,webhook_created
+,webhook_updated
+) VALUES (
+ :webhook_repo_id
+,:webhook_endpoint
+,:webhook_secret
+,:webhook_created
+,:webhook_updated
+)
+`
+
+const stmtInsertPg = stmtInsert + `
+RETURNING webhook_id
+`
@@ -0,0 +1,212 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package webhook
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/store/repos"
+	"github.com/drone/drone/store/shared/db/dbtest"
+	"github.com/drone/drone/store/shared/encrypt"
+)
+
+var noContext = context.TODO()
+
+func TestWebhook(t *testing.T) {
+	conn, err := dbtest.Connect()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer func() {
+		dbtest.Reset(conn)
+		dbtest.Disconnect(conn)
+	}()
+
+	// seeds the database with a dummy repository.
+	repo := &core.Repository{UID: "1", Slug: "octocat/hello-world"}
+	repos := repos.New(conn)
+	if err := repos.Create(noContext, repo); err != nil {
+		t.Error(err)
+	}
+
+	store := New(conn, nil).(*webhookStore)
+	store.enc, _ = encrypt.New("fb4b4d6267c8a5ce8231f8b186dbca92")
+	t.Run("Create", testWebhookCreate(store, repos, repo))
+}
+
+func testWebhookCreate(store *webhookStore, repos core.RepositoryStore, repo *core.Repository) func(t *testing.T) {
+	return func(t *testing.T) {
+		item := &core.RepoWebhook{
+			RepoID:   repo.ID,
+			Endpoint: "https://company.com/hooks",
+			Secret:   "correct-horse-battery-staple",
+			Created:  1524251054,
+			Updated:  1524251054,
+		}
+		err := store.Create(noContext, item)
+		if err != nil {
+			t.Error(err)
+		}
+		if item.ID == 0 {
+			t.Errorf("Want webhook ID assigned, got %d", item.ID)
+		}
+
+		t.Run("Find", testWebhookFind(store, item))
+		t.Run("List", testWebhookList(store, repo))
+		t.Run("Update", testWebhookUpdate(store, item))
+		t.Run("Rotate", testWebhookRotate(store, item))
+		t.Run("Delete", testWebhookDelete(store, item))
+		t.Run("Fkey", testWebhookForeignKey(store, repos, repo))
+	}
+}
+
+func testWebhookFind(store *webhookStore, webhook *core.RepoWebhook) func(t *testing.T) {
+	return func(t *testing.T) {
+		item, err := store.Find(noContext, webhook.ID)
+		if err != nil {
+			t.Error(err)
+		} else {
+			t.Run("Fields", testWebhookFields(item))
+		}
+	}
+}
+
+func testWebhookList(store *webhookStore, repo *core.Repository) func(t *testing.T) {
+	return func(t *testing.T) {
+		list, err := store.List(noContext, repo.ID)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if got, want := len(list), 1; got != want {
+			t.Errorf("Want count %d, got %d", want, got)
+		} else {
+			t.Run("Fields", testWebhookFields(list[0]))
+		}
+	}
+}
+
+func testWebhookUpdate(store *webhookStore, webhook *core.RepoWebhook) func(t *testing.T) {
+	return func(t *testing.T) {
+		before, err := store.Find(noContext, webhook.ID)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		before.Endpoint = "https://company.com/hooks/drone"
+		before.Updated = 1524251055
+		err = store.Update(noContext, before)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		after, err := store.Find(noContext, before.ID)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if got, want := after.Endpoint, before.Endpoint; got != want {
+			t.Errorf("Want webhook endpoint %q, got %q", want, got)
+		}
+		if got, want := after.Secret, "correct-horse-battery-staple"; got != want {
+			t.Errorf("Want webhook secret %q, got %q", want, got)
+		}
+	}
+}
+
+func testWebhookRotate(store *webhookStore, webhook *core.RepoWebhook) func(t *testing.T) {
+	return func(t *testing.T) {
+		before, err := store.Find(noContext, webhook.ID)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		store.enc, _ = encrypt.NewKeyring(
+			"0a8f63b5d2e84a5f9c7e1b3d6f2a4c8e",
+			false,
+			"fb4b4d6267c8a5ce8231f8b186dbca92",
+		)
+		n, err := store.Rotate(noContext)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if n == 0 {
+			t.Errorf("Want webhooks rotated")
+		}
+		n, _ = store.Rotate(noContext)
+		if got, want := n, int64(0); got != want {
+			t.Errorf("Want %d webhooks rotated, got %d", want, got)
+		}
+
+		// the previous key is no longer required once the
+		// rotation is complete.
+		store.enc, _ = encrypt.NewKeyring("0a8f63b5d2e84a5f9c7e1b3d6f2a4c8e", false)
+		after, err := store.Find(noContext, webhook.ID)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if got, want := after.Secret, before.Secret; got != want {
+			t.Errorf("Want secret %q, got %q", want, got)
+		}
+	}
+}
+
+func testWebhookDelete(store *webhookStore, webhook *core.RepoWebhook) func(t *testing.T) {
+	return func(t *testing.T) {
+		err := store.Delete(noContext, webhook)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		_, err = store.Find(noContext, webhook.ID)
+		if got, want := sql.ErrNoRows, err; got != want {
+			t.Errorf("Want sql.ErrNoRows, got %v", got)
+			return
+		}
+	}
+}
+
+func testWebhookForeignKey(store *webhookStore, repos core.RepositoryStore, repo *core.Repository) func(t *testing.T) {
+	return func(t *testing.T) {
+		item := &core.RepoWebhook{
+			RepoID:   repo.ID,
+			Endpoint: "https://company.com/hooks",
+			Secret:   "correct-horse-battery-staple",
+		}
+		store.Create(noContext, item)
+		before, _ := store.List(noContext, repo.ID)
+		if len(before) == 0 {
+			t.Errorf("Want non-empty webhook list")
+			return
+		}
+
+		err := repos.Delete(noContext, repo)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		after, _ := store.List(noContext, repo.ID)
+		if len(after) != 0 {
+			t.Errorf("Want empty webhook list")
+		}
+	}
+}
+
+func testWebhookFields(item *core.RepoWebhook) func(t *testing.T) {
+	return func(t *testing.T) {
+		if got, want := item.Endpoint, "https://company.com/hooks"; got != want {
+			t.Errorf("Want webhook endpoint %q, got %q", want, got)
+		}
+		if got, want := item.Secret, "correct-horse-battery-staple"; got != want {
+			t.Errorf("Want webhook secret %q, got %q", want, got)
+		}
+	}
+}
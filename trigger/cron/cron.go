@@ -18,6 +18,7 @@ import (
 
 // New returns a new Cron scheduler.
 func New(
+	builds core.BuildStore,
 	commits core.CommitService,
 	cron core.CronStore,
 	repos core.RepositoryStore,
@@ -25,6 +26,7 @@ func New(
 	trigger core.Triggerer,
 ) *Scheduler {
 	return &Scheduler{
+		builds:  builds,
 		commits: commits,
 		cron:    cron,
 		repos:   repos,
@@ -35,6 +37,7 @@ func New(
 
 // Scheduler defines a cron scheduler.
 type Scheduler struct {
+	builds  core.BuildStore
 	commits core.CommitService
 	cron    core.CronStore
 	repos   core.RepositoryStore
@@ -156,6 +159,15 @@ func (s *Scheduler) run(ctx context.Context) error {
 			Sender:       commit.Author.Login,
 		}
 
+		// rebuild jobs preserve the parameters of the most
+		// recent build for the branch.
+		if job.Rebuild {
+			prev, err := s.builds.FindRef(ctx, repo.ID, hook.Ref)
+			if err == nil {
+				hook.Params = prev.Params
+			}
+		}
+
 		_, err = s.trigger.Trigger(ctx, repo, hook)
 		if err != nil {
 			logger.WithFields(
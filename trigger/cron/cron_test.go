@@ -80,6 +80,61 @@ func TestCron(t *testing.T) {
 	}
 }
 
+func TestCron_Rebuild(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	job := &core.Cron{
+		RepoID:  dummyRepo.ID,
+		Name:    "nightly",
+		Expr:    "0 0 * * *",
+		Next:    2000000000,
+		Branch:  "master",
+		Rebuild: true,
+	}
+
+	checkBuild := func(_ context.Context, _ *core.Repository, hook *core.Hook) {
+		if diff := cmp.Diff(hook.Params, map[string]string{"release": "nightly"}); diff != "" {
+			t.Errorf(diff)
+		}
+	}
+
+	mockTriggerer := mock.NewMockTriggerer(controller)
+	mockTriggerer.EXPECT().Trigger(gomock.Any(), dummyRepo, gomock.Any()).Do(checkBuild)
+
+	mockRepos := mock.NewMockRepositoryStore(controller)
+	mockRepos.EXPECT().Find(gomock.Any(), job.RepoID).Return(dummyRepo, nil)
+
+	mockCrons := mock.NewMockCronStore(controller)
+	mockCrons.EXPECT().Ready(gomock.Any(), gomock.Any()).Return([]*core.Cron{job}, nil)
+	mockCrons.EXPECT().Update(gomock.Any(), job)
+
+	mockUsers := mock.NewMockUserStore(controller)
+	mockUsers.EXPECT().Find(gomock.Any(), dummyRepo.UserID).Return(dummyUser, nil)
+
+	mockCommits := mock.NewMockCommitService(controller)
+	mockCommits.EXPECT().FindRef(gomock.Any(), dummyUser, dummyRepo.Slug, dummyRepo.Branch).Return(dummyCommit, nil)
+
+	mockBuilds := mock.NewMockBuildStore(controller)
+	mockBuilds.EXPECT().FindRef(gomock.Any(), dummyRepo.ID, "refs/heads/master").Return(&core.Build{
+		Params: map[string]string{"release": "nightly"},
+	}, nil)
+
+	s := Scheduler{
+		builds:  mockBuilds,
+		commits: mockCommits,
+		cron:    mockCrons,
+		repos:   mockRepos,
+		users:   mockUsers,
+		trigger: mockTriggerer,
+	}
+
+	err := s.run(noContext)
+	if err != nil {
+		t.Error(err)
+	}
+}
+
 func TestCron_Cancel(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	cancel()
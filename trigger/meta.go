@@ -0,0 +1,122 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package trigger
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/drone/drone-yaml/yaml"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/extension"
+
+	"github.com/sirupsen/logrus"
+)
+
+// triggeredBy returns the actor that triggered the build.
+// Builds triggered by a webhook are attributed to the user
+// that sent the webhook.
+func triggeredBy(base *core.Hook) string {
+	switch base.Trigger {
+	case "", core.TriggerHook:
+		return base.Sender
+	default:
+		return base.Trigger
+	}
+}
+
+// restartedFrom returns the number of the build that was
+// restarted, or zero if the hook does not restart a build.
+// Promotions and rollbacks reference a parent build but
+// are not considered restarts.
+func restartedFrom(base *core.Hook) int64 {
+	switch base.Event {
+	case core.EventPromote, core.EventRollback:
+		return 0
+	default:
+		return base.Parent
+	}
+}
+
+// findPull returns the pull request for pull request hooks,
+// or nil if the hook is not a pull request hook or the pull
+// request cannot be found.
+func (t *triggerer) findPull(ctx context.Context, user *core.User, repo *core.Repository, base *core.Hook) *core.PullRequest {
+	if base.Event != core.EventPullRequest {
+		return nil
+	}
+	number := pullRequestNumber(base.Ref)
+	if number == 0 {
+		return nil
+	}
+	pr, err := t.pulls.Find(ctx, user, repo.Slug, number)
+	if err != nil {
+		logrus.WithError(err).
+			WithField("repo", repo.Slug).
+			WithField("number", number).
+			Warnln("trigger: cannot find pull request")
+		return nil
+	}
+	return pr
+}
+
+// pullRequestNumber extracts the pull request number from
+// the pull request reference (e.g. refs/pull/42/head).
+func pullRequestNumber(ref string) int {
+	parts := strings.Split(ref, "/")
+	if len(parts) != 4 || parts[0] != "refs" {
+		return 0
+	}
+	number, _ := strconv.Atoi(parts[2])
+	return number
+}
+
+// maximum number of build labels, and the maximum length of
+// each build label.
+const (
+	maxLabels      = 50
+	maxLabelLength = 250
+)
+
+// buildLabels merges the labels provided by the hook, the
+// pull request and the pipeline metadata. Empty and duplicate
+// labels are removed, and labels that exceed the maximum
+// length or number of labels are ignored.
+func buildLabels(lists ...[]string) []string {
+	var labels []string
+	seen := map[string]bool{}
+	for _, list := range lists {
+		for _, label := range list {
+			label = strings.TrimSpace(label)
+			if label == "" || len(label) > maxLabelLength || seen[label] {
+				continue
+			}
+			if len(labels) == maxLabels {
+				return labels
+			}
+			seen[label] = true
+			labels = append(labels, label)
+		}
+	}
+	return labels
+}
+
+// pipelineLabels returns the metadata labels of the matched
+// pipelines.
+func pipelineLabels(matched []*yaml.Pipeline, extensions *extension.Manifest) []string {
+	var labels []string
+	for _, pipeline := range matched {
+		name := pipeline.Name
+		if name == "" {
+			name = "default"
+		}
+		if ext := extensions.Lookup(name); ext != nil {
+			labels = append(labels, ext.Labels()...)
+		}
+	}
+	return labels
+}
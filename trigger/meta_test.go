@@ -0,0 +1,89 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package trigger
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/drone/drone/core"
+)
+
+func Test_triggeredBy(t *testing.T) {
+	tests := []struct {
+		hook *core.Hook
+		want string
+	}{
+		{&core.Hook{Trigger: core.TriggerHook, Sender: "octocat"}, "octocat"},
+		{&core.Hook{Sender: "octocat"}, "octocat"},
+		{&core.Hook{Trigger: core.TriggerCron, Sender: "octocat"}, core.TriggerCron},
+		{&core.Hook{Trigger: "spaceghost", Sender: "octocat"}, "spaceghost"},
+	}
+	for _, test := range tests {
+		if got := triggeredBy(test.hook); got != test.want {
+			t.Errorf("Want triggered by %q, got %q", test.want, got)
+		}
+	}
+}
+
+func Test_restartedFrom(t *testing.T) {
+	tests := []struct {
+		hook *core.Hook
+		want int64
+	}{
+		{&core.Hook{Event: core.EventPush}, 0},
+		{&core.Hook{Event: core.EventPush, Parent: 41}, 41},
+		{&core.Hook{Event: core.EventPullRequest, Parent: 41}, 41},
+		{&core.Hook{Event: core.EventPromote, Parent: 41}, 0},
+		{&core.Hook{Event: core.EventRollback, Parent: 41}, 0},
+	}
+	for _, test := range tests {
+		if got := restartedFrom(test.hook); got != test.want {
+			t.Errorf("Want restarted from %d, got %d", test.want, got)
+		}
+	}
+}
+
+func Test_pullRequestNumber(t *testing.T) {
+	tests := []struct {
+		ref    string
+		number int
+	}{
+		{"refs/pull/42/head", 42},
+		{"refs/pull/42/merge", 42},
+		{"refs/merge-requests/42/merge", 42},
+		{"refs/heads/master", 0},
+		{"", 0},
+	}
+	for _, test := range tests {
+		if got, want := pullRequestNumber(test.ref), test.number; got != want {
+			t.Errorf("Want pull request number %d for ref %q, got %d", want, test.ref, got)
+		}
+	}
+}
+
+func Test_buildLabels(t *testing.T) {
+	long := strings.Repeat("a", maxLabelLength+1)
+	got := buildLabels(
+		[]string{"team=payments", " ", long},
+		[]string{"docs", "team=payments"},
+		nil,
+		[]string{" release=2019.04 "},
+	)
+	want := []string{"team=payments", "docs", "release=2019.04"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Want labels %v, got %v", want, got)
+	}
+
+	var many []string
+	for i := 0; i < maxLabels+10; i++ {
+		many = append(many, strconv.Itoa(i))
+	}
+	if got := buildLabels(many); len(got) != maxLabels {
+		t.Errorf("Want %d labels, got %d", maxLabels, len(got))
+	}
+}
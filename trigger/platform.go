@@ -0,0 +1,29 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package trigger
+
+import (
+	"github.com/drone/drone-yaml/yaml"
+	"github.com/drone/drone/core"
+)
+
+// helper function returns the platform targeted by the
+// pipeline. The platform defaults to linux/amd64.
+func toPlatform(pipeline *yaml.Pipeline, labels map[string]string) *core.Platform {
+	platform := &core.Platform{
+		OS:      pipeline.Platform.OS,
+		Arch:    pipeline.Platform.Arch,
+		Variant: pipeline.Platform.Variant,
+		Kernel:  pipeline.Platform.Version,
+		Labels:  labels,
+	}
+	if platform.OS == "" {
+		platform.OS = "linux"
+	}
+	if platform.Arch == "" {
+		platform.Arch = "amd64"
+	}
+	return platform
+}
@@ -0,0 +1,88 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package trigger
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/drone/drone-yaml/yaml"
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/plugin/registry"
+)
+
+// helper function validates the pipeline against the
+// organization policy. An error describing the violation is
+// returned if the pipeline does not satisfy the policy.
+func checkPolicy(policy *core.Policy, pipeline *yaml.Pipeline) error {
+	if policy.CloneDepth > 0 && !pipeline.Clone.Disable {
+		if pipeline.Clone.Depth == 0 || pipeline.Clone.Depth > policy.CloneDepth {
+			return fmt.Errorf("policy: pipeline %s must set a clone depth no greater than %d",
+				pipeline.Name, policy.CloneDepth)
+		}
+	}
+	var containers []*yaml.Container
+	containers = append(containers, pipeline.Services...)
+	containers = append(containers, pipeline.Steps...)
+	for _, container := range containers {
+		if policy.DenyPrivileged && container.Privileged {
+			return fmt.Errorf("policy: step %s cannot run in privileged mode",
+				container.Name)
+		}
+		if !allowRegistry(policy.AllowRegistries, container.Image) {
+			return fmt.Errorf("policy: step %s image %s is not from an allowed registry",
+				container.Name, container.Image)
+		}
+		if denyImage(policy.DenyImages, container.Image) {
+			return fmt.Errorf("policy: step %s image %s is not allowed",
+				container.Name, container.Image)
+		}
+	}
+	return nil
+}
+
+// helper function returns true if the image is hosted by an
+// allowed registry. All registries are allowed if the list
+// is empty.
+func allowRegistry(registries []string, image string) bool {
+	if len(registries) == 0 {
+		return true
+	}
+	host := registry.ImageHost(image)
+	for _, address := range registries {
+		if registry.AddressHost(address) == host {
+			return true
+		}
+	}
+	return false
+}
+
+// helper function returns true if the image matches a
+// denied image pattern. Patterns without a tag also match
+// tagged images.
+func denyImage(patterns []string, image string) bool {
+	name := trimTag(image)
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, image); ok {
+			return true
+		}
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// helper function removes the tag or digest from the image.
+func trimTag(image string) string {
+	if i := strings.Index(image, "@"); i != -1 {
+		image = image[:i]
+	}
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		image = image[:i]
+	}
+	return image
+}
@@ -0,0 +1,109 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package trigger
+
+import (
+	"testing"
+
+	"github.com/drone/drone-yaml/yaml"
+	"github.com/drone/drone/core"
+)
+
+func TestCheckPolicy(t *testing.T) {
+	tests := []struct {
+		policy   *core.Policy
+		pipeline *yaml.Pipeline
+		violated bool
+	}{
+		// empty policy
+		{
+			policy: &core.Policy{},
+			pipeline: &yaml.Pipeline{
+				Steps: []*yaml.Container{{Name: "build", Image: "golang", Privileged: true}},
+			},
+			violated: false,
+		},
+		// allowed registries
+		{
+			policy: &core.Policy{AllowRegistries: []string{"docker.io"}},
+			pipeline: &yaml.Pipeline{
+				Steps: []*yaml.Container{{Name: "build", Image: "golang:1.12"}},
+			},
+			violated: false,
+		},
+		{
+			policy: &core.Policy{AllowRegistries: []string{"docker.io"}},
+			pipeline: &yaml.Pipeline{
+				Services: []*yaml.Container{{Name: "cache", Image: "gcr.io/octocat/redis"}},
+			},
+			violated: true,
+		},
+		// denied images
+		{
+			policy: &core.Policy{DenyImages: []string{"octocat/*"}},
+			pipeline: &yaml.Pipeline{
+				Steps: []*yaml.Container{{Name: "build", Image: "octocat/hello-world:latest"}},
+			},
+			violated: true,
+		},
+		{
+			policy: &core.Policy{DenyImages: []string{"*:latest"}},
+			pipeline: &yaml.Pipeline{
+				Steps: []*yaml.Container{{Name: "build", Image: "golang:1.12"}},
+			},
+			violated: false,
+		},
+		// privileged mode
+		{
+			policy: &core.Policy{DenyPrivileged: true},
+			pipeline: &yaml.Pipeline{
+				Steps: []*yaml.Container{{Name: "build", Image: "docker:dind", Privileged: true}},
+			},
+			violated: true,
+		},
+		// clone depth
+		{
+			policy:   &core.Policy{CloneDepth: 50},
+			pipeline: &yaml.Pipeline{},
+			violated: true,
+		},
+		{
+			policy:   &core.Policy{CloneDepth: 50},
+			pipeline: &yaml.Pipeline{Clone: yaml.Clone{Depth: 100}},
+			violated: true,
+		},
+		{
+			policy:   &core.Policy{CloneDepth: 50},
+			pipeline: &yaml.Pipeline{Clone: yaml.Clone{Depth: 1}},
+			violated: false,
+		},
+		{
+			policy:   &core.Policy{CloneDepth: 50},
+			pipeline: &yaml.Pipeline{Clone: yaml.Clone{Disable: true}},
+			violated: false,
+		},
+	}
+	for i, test := range tests {
+		err := checkPolicy(test.policy, test.pipeline)
+		if got, want := err != nil, test.violated; got != want {
+			t.Errorf("Want policy violation %v, got %v at index %d", want, err, i)
+		}
+	}
+}
+
+func TestTrimTag(t *testing.T) {
+	tests := map[string]string{
+		"golang":                         "golang",
+		"golang:1.12":                    "golang",
+		"localhost:5000/golang":          "localhost:5000/golang",
+		"localhost:5000/golang:1.12":     "localhost:5000/golang",
+		"golang@sha256:0123456789abcdef": "golang",
+	}
+	for image, want := range tests {
+		if got := trimTag(image); got != want {
+			t.Errorf("Want image %s trimmed to %s, got %s", image, want, got)
+		}
+	}
+}
@@ -0,0 +1,173 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package poll
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/drone/drone/core"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/sirupsen/logrus"
+)
+
+// New returns a new Poll scheduler.
+func New(
+	builds core.BuildStore,
+	commits core.CommitService,
+	repos core.RepositoryStore,
+	users core.UserStore,
+	trigger core.Triggerer,
+) *Scheduler {
+	return &Scheduler{
+		builds:  builds,
+		commits: commits,
+		repos:   repos,
+		users:   users,
+		trigger: trigger,
+	}
+}
+
+// Scheduler defines a poll scheduler that checks repository
+// branches for new commits, for repositories hosted behind a
+// firewall where webhooks cannot be delivered to the server.
+type Scheduler struct {
+	builds  core.BuildStore
+	commits core.CommitService
+	repos   core.RepositoryStore
+	users   core.UserStore
+	trigger core.Triggerer
+}
+
+// Start starts the poll scheduler.
+func (s *Scheduler) Start(ctx context.Context, dur time.Duration) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(dur):
+			s.run(ctx)
+		}
+	}
+}
+
+func (s *Scheduler) run(ctx context.Context) error {
+	var result error
+
+	logrus.Debugln("poll: begin polling repositories")
+
+	defer func() {
+		if err := recover(); err != nil {
+			logger := logrus.WithField("error", err)
+			logger.Errorln("poll: unexpected panic")
+		}
+	}()
+
+	now := time.Now().Unix()
+	repos, err := s.repos.ListPolling(ctx, now)
+	if err != nil {
+		logger := logrus.WithError(err)
+		logger.Error("poll: cannot list repositories")
+		return err
+	}
+
+	for _, repo := range repos {
+		logger := logrus.WithField("repo", repo.Slug)
+
+		// the poll time is updated before the repository is
+		// polled, so that a repository that cannot be polled is
+		// retried at the next interval. The update fails with an
+		// optimistic lock error if the repository was modified
+		// since it was listed, in which case it is skipped.
+		repo.Polled = now
+		err := s.repos.Update(ctx, repo)
+		if err != nil {
+			logger.WithError(err).Warnln("poll: cannot update repository")
+			result = multierror.Append(result, err)
+			continue
+		}
+
+		user, err := s.users.Find(ctx, repo.UserID)
+		if err != nil {
+			logger.WithError(err).Warnln("poll: cannot find repository owner")
+			result = multierror.Append(result, err)
+			continue
+		}
+
+		for _, branch := range branches(repo) {
+			err := s.poll(ctx, user, repo, branch)
+			if err != nil {
+				logger.WithError(err).
+					WithField("branch", branch).
+					Warnln("poll: cannot poll branch")
+				result = multierror.Append(result, err)
+			}
+		}
+	}
+
+	logrus.Debugf("poll: finished polling repositories")
+	return result
+}
+
+// poll checks the branch for a new commit, and triggers a
+// build if the most recent branch build is for a different
+// commit.
+func (s *Scheduler) poll(ctx context.Context, user *core.User, repo *core.Repository, branch string) error {
+	commit, err := s.commits.FindRef(ctx, user, repo.Slug, branch)
+	if err != nil {
+		return err
+	}
+
+	ref := fmt.Sprintf("refs/heads/%s", branch)
+	prev, err := s.builds.FindRef(ctx, repo.ID, ref)
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+	if err == nil && prev.After == commit.Sha {
+		return nil
+	}
+
+	hook := &core.Hook{
+		Trigger:      core.TriggerPoll,
+		Event:        core.EventPush,
+		Link:         commit.Link,
+		Timestamp:    commit.Author.Date,
+		Message:      commit.Message,
+		After:        commit.Sha,
+		Ref:          ref,
+		Source:       branch,
+		Target:       branch,
+		Author:       commit.Author.Login,
+		AuthorName:   commit.Author.Name,
+		AuthorEmail:  commit.Author.Email,
+		AuthorAvatar: commit.Author.Avatar,
+		Sender:       commit.Author.Login,
+	}
+	if err == nil {
+		hook.Before = prev.After
+	}
+	_, err = s.trigger.Trigger(ctx, repo, hook)
+	return err
+}
+
+// helper function returns the branches polled for new
+// commits. If no branches are configured, the default
+// branch is polled.
+func branches(repo *core.Repository) []string {
+	var out []string
+	for _, branch := range strings.Split(repo.PollBranches, ",") {
+		if branch = strings.TrimSpace(branch); branch != "" {
+			out = append(out, branch)
+		}
+	}
+	if len(out) == 0 && repo.Branch != "" {
+		out = append(out, repo.Branch)
+	}
+	return out
+}
@@ -0,0 +1,226 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package poll
+
+import (
+	"context"
+	"database/sql"
+	"io/ioutil"
+	"testing"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/mock"
+	"github.com/drone/drone/store/shared/db"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+	"github.com/sirupsen/logrus"
+)
+
+func init() {
+	logrus.SetOutput(ioutil.Discard)
+}
+
+var noContext = context.Background()
+
+func TestPoll(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	repo := new(core.Repository)
+	*repo = *dummyRepo
+
+	checkHook := func(_ context.Context, _ *core.Repository, hook *core.Hook) {
+		if diff := cmp.Diff(hook, dummyHook); diff != "" {
+			t.Errorf(diff)
+		}
+	}
+
+	checkRepo := func(_ context.Context, repo *core.Repository) {
+		if repo.Polled == 0 {
+			t.Errorf("Expect repository poll time updated")
+		}
+	}
+
+	mockRepos := mock.NewMockRepositoryStore(controller)
+	mockRepos.EXPECT().ListPolling(gomock.Any(), gomock.Any()).Return([]*core.Repository{repo}, nil)
+	mockRepos.EXPECT().Update(gomock.Any(), repo).Do(checkRepo).Return(nil)
+
+	mockUsers := mock.NewMockUserStore(controller)
+	mockUsers.EXPECT().Find(gomock.Any(), repo.UserID).Return(dummyUser, nil)
+
+	mockCommits := mock.NewMockCommitService(controller)
+	mockCommits.EXPECT().FindRef(gomock.Any(), dummyUser, repo.Slug, "master").Return(dummyCommit, nil)
+
+	mockBuilds := mock.NewMockBuildStore(controller)
+	mockBuilds.EXPECT().FindRef(gomock.Any(), repo.ID, "refs/heads/master").Return(&core.Build{After: dummyHook.Before}, nil)
+
+	mockTriggerer := mock.NewMockTriggerer(controller)
+	mockTriggerer.EXPECT().Trigger(gomock.Any(), repo, gomock.Any()).Do(checkHook)
+
+	s := New(mockBuilds, mockCommits, mockRepos, mockUsers, mockTriggerer)
+	err := s.run(noContext)
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+// this test verifies that a build is not created if the most
+// recent branch build is for the current commit.
+func TestPoll_Unchanged(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	repo := new(core.Repository)
+	*repo = *dummyRepo
+
+	mockRepos := mock.NewMockRepositoryStore(controller)
+	mockRepos.EXPECT().ListPolling(gomock.Any(), gomock.Any()).Return([]*core.Repository{repo}, nil)
+	mockRepos.EXPECT().Update(gomock.Any(), repo).Return(nil)
+
+	mockUsers := mock.NewMockUserStore(controller)
+	mockUsers.EXPECT().Find(gomock.Any(), repo.UserID).Return(dummyUser, nil)
+
+	mockCommits := mock.NewMockCommitService(controller)
+	mockCommits.EXPECT().FindRef(gomock.Any(), dummyUser, repo.Slug, "master").Return(dummyCommit, nil)
+
+	mockBuilds := mock.NewMockBuildStore(controller)
+	mockBuilds.EXPECT().FindRef(gomock.Any(), repo.ID, "refs/heads/master").Return(&core.Build{After: dummyCommit.Sha}, nil)
+
+	s := New(mockBuilds, mockCommits, mockRepos, mockUsers, nil)
+	err := s.run(noContext)
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+// this test verifies that a build is created for the
+// configured branches when the branch has no builds.
+func TestPoll_Branches(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	repo := new(core.Repository)
+	*repo = *dummyRepo
+	repo.PollBranches = "develop"
+
+	checkHook := func(_ context.Context, _ *core.Repository, hook *core.Hook) {
+		if got, want := hook.Ref, "refs/heads/develop"; got != want {
+			t.Errorf("Want hook ref %q, got %q", want, got)
+		}
+		if got, want := hook.Before, ""; got != want {
+			t.Errorf("Want empty hook before sha, got %q", got)
+		}
+	}
+
+	mockRepos := mock.NewMockRepositoryStore(controller)
+	mockRepos.EXPECT().ListPolling(gomock.Any(), gomock.Any()).Return([]*core.Repository{repo}, nil)
+	mockRepos.EXPECT().Update(gomock.Any(), repo).Return(nil)
+
+	mockUsers := mock.NewMockUserStore(controller)
+	mockUsers.EXPECT().Find(gomock.Any(), repo.UserID).Return(dummyUser, nil)
+
+	mockCommits := mock.NewMockCommitService(controller)
+	mockCommits.EXPECT().FindRef(gomock.Any(), dummyUser, repo.Slug, "develop").Return(dummyCommit, nil)
+
+	mockBuilds := mock.NewMockBuildStore(controller)
+	mockBuilds.EXPECT().FindRef(gomock.Any(), repo.ID, "refs/heads/develop").Return(nil, sql.ErrNoRows)
+
+	mockTriggerer := mock.NewMockTriggerer(controller)
+	mockTriggerer.EXPECT().Trigger(gomock.Any(), repo, gomock.Any()).Do(checkHook)
+
+	s := New(mockBuilds, mockCommits, mockRepos, mockUsers, mockTriggerer)
+	err := s.run(noContext)
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+// this test verifies that the repository is skipped if the
+// repository is modified, or polled by another server, after
+// it is listed.
+func TestPoll_Conflict(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	repo := new(core.Repository)
+	*repo = *dummyRepo
+
+	mockRepos := mock.NewMockRepositoryStore(controller)
+	mockRepos.EXPECT().ListPolling(gomock.Any(), gomock.Any()).Return([]*core.Repository{repo}, nil)
+	mockRepos.EXPECT().Update(gomock.Any(), repo).Return(db.ErrOptimisticLock)
+
+	s := New(nil, nil, mockRepos, nil, nil)
+	err := s.run(noContext)
+	if err == nil {
+		t.Errorf("Expect optimistic lock error")
+	}
+}
+
+func Test_branches(t *testing.T) {
+	tests := []struct {
+		repo *core.Repository
+		want []string
+	}{
+		{&core.Repository{Branch: "master"}, []string{"master"}},
+		{&core.Repository{Branch: "master", PollBranches: "develop"}, []string{"develop"}},
+		{&core.Repository{Branch: "master", PollBranches: " master, develop ,"}, []string{"master", "develop"}},
+		{&core.Repository{}, nil},
+	}
+	for _, test := range tests {
+		if diff := cmp.Diff(branches(test.repo), test.want); diff != "" {
+			t.Errorf(diff)
+		}
+	}
+}
+
+var (
+	dummyUser = &core.User{
+		Login: "octocat",
+	}
+
+	dummyRepo = &core.Repository{
+		ID:           1,
+		UID:          "1296269",
+		UserID:       2,
+		Namespace:    "octocat",
+		Name:         "Hello-World",
+		Slug:         "octocat/Hello-World",
+		Branch:       "master",
+		Active:       true,
+		PollInterval: 300,
+	}
+
+	dummyHook = &core.Hook{
+		Trigger:      core.TriggerPoll,
+		Event:        core.EventPush,
+		Link:         "https://github.com/octocat/Hello-World/commit/7fd1a60b01f91b314f59955a4e4d4e80d8edf11d",
+		Timestamp:    1299283200,
+		Message:      "first commit",
+		Before:       "553c2077f0edc3d5dc5d17262f6aa498e69d6f8e",
+		After:        "7fd1a60b01f91b314f59955a4e4d4e80d8edf11d",
+		Ref:          "refs/heads/master",
+		Source:       "master",
+		Target:       "master",
+		Author:       "octocat",
+		AuthorName:   "The Octocat",
+		AuthorEmail:  "octocat@hello-world.com",
+		AuthorAvatar: "https://avatars3.githubusercontent.com/u/583231",
+		Sender:       "octocat",
+	}
+
+	dummyCommit = &core.Commit{
+		Sha:     dummyHook.After,
+		Message: dummyHook.Message,
+		Link:    dummyHook.Link,
+		Author: &core.Committer{
+			Name:   dummyHook.AuthorName,
+			Email:  dummyHook.AuthorEmail,
+			Login:  dummyHook.Author,
+			Avatar: dummyHook.AuthorAvatar,
+			Date:   dummyHook.Timestamp,
+		},
+	}
+)
@@ -0,0 +1,18 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package trigger
+
+// helper function merges the runner pool labels with the
+// pipeline node labels. The node labels take precedence.
+func mergeLabels(pool, node map[string]string) map[string]string {
+	out := map[string]string{}
+	for k, v := range pool {
+		out[k] = v
+	}
+	for k, v := range node {
+		out[k] = v
+	}
+	return out
+}
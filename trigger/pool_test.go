@@ -0,0 +1,23 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package trigger
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestMergeLabels(t *testing.T) {
+	pool := map[string]string{"gpu": "nvidia", "zone": "us-east"}
+	node := map[string]string{"zone": "us-west"}
+	want := map[string]string{"gpu": "nvidia", "zone": "us-west"}
+	if diff := cmp.Diff(mergeLabels(pool, node), want); diff != "" {
+		t.Errorf(diff)
+	}
+	if got := mergeLabels(nil, nil); len(got) != 0 {
+		t.Errorf("Want empty labels, got %v", got)
+	}
+}
@@ -0,0 +1,23 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package trigger
+
+import "strings"
+
+// mergeRef returns the gitlab merge request ref that points
+// to the result of merging the source branch into the target
+// branch. Refs that do not reference a merge request head are
+// returned unchanged.
+//
+// Note that the commit sha is not changed. The build status
+// is still reported against the head commit of the source
+// branch, which is where gitlab expects to find it.
+func mergeRef(ref string) string {
+	if strings.HasPrefix(ref, "refs/merge-requests/") &&
+		strings.HasSuffix(ref, "/head") {
+		return strings.TrimSuffix(ref, "/head") + "/merge"
+	}
+	return ref
+}
@@ -0,0 +1,24 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package trigger
+
+import "testing"
+
+func TestMergeRef(t *testing.T) {
+	tests := []struct {
+		ref  string
+		want string
+	}{
+		{"refs/merge-requests/42/head", "refs/merge-requests/42/merge"},
+		{"refs/merge-requests/42/merge", "refs/merge-requests/42/merge"},
+		{"refs/pull/42/head", "refs/pull/42/head"},
+		{"refs/heads/master", "refs/heads/master"},
+	}
+	for _, test := range tests {
+		if got := mergeRef(test.ref); got != test.want {
+			t.Errorf("Want ref %q, got %q", test.want, got)
+		}
+	}
+}
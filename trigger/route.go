@@ -0,0 +1,126 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package trigger
+
+import (
+	"context"
+
+	"github.com/drone/drone/core"
+
+	"github.com/sirupsen/logrus"
+)
+
+// helper function returns the names of the pipelines that are
+// skipped because the build does not change any of the paths
+// mapped to the pipeline. The changeset is only available for
+// push and pull request events, otherwise all pipelines are
+// executed.
+func (t *triggerer) route(ctx context.Context, repo *core.Repository, changes []*core.Change) map[string]bool {
+	if len(changes) == 0 {
+		return nil
+	}
+
+	mappings, err := t.mappings.List(ctx, repo.ID)
+	if err != nil {
+		logrus.WithError(err).
+			WithField("repo", repo.Slug).
+			Warnln("trigger: cannot list path mappings")
+		return nil
+	}
+	if len(mappings) == 0 {
+		return nil
+	}
+
+	var paths []string
+	for _, change := range changes {
+		paths = append(paths, change.Path)
+	}
+	return skipRoutes(mappings, paths)
+}
+
+// helper function returns the files changed by the build.
+// The changeset is only available for push and pull request
+// events. The pull request changeset includes the changes of
+// all commits in the pull request.
+func (t *triggerer) listChanges(ctx context.Context, user *core.User, repo *core.Repository, base *core.Hook) []*core.Change {
+	if base.After == "" {
+		return nil
+	}
+
+	var changes []*core.Change
+	var err error
+	switch base.Event {
+	case core.EventPush:
+		changes, err = t.commits.ListChanges(ctx, user, repo.Slug, base.After, base.Ref)
+	case core.EventPullRequest:
+		number := pullRequestNumber(base.Ref)
+		if number == 0 {
+			return nil
+		}
+		changes, err = t.pulls.ListChanges(ctx, user, repo.Slug, number)
+	default:
+		return nil
+	}
+	if err != nil {
+		logrus.WithError(err).
+			WithField("repo", repo.Slug).
+			Warnln("trigger: cannot list changed paths")
+		return nil
+	}
+	return changes
+}
+
+// helper function returns the names of the mapped pipelines
+// that none of the changed paths are routed to. Pipelines
+// without a path mapping are never skipped. If the changeset
+// is empty all pipelines are executed.
+func skipRoutes(mappings []*core.PathMapping, paths []string) map[string]bool {
+	if len(paths) == 0 {
+		return nil
+	}
+	skipped := map[string]bool{}
+	for _, mapping := range mappings {
+		skipped[mapping.Pipeline] = true
+	}
+	for _, mapping := range mappings {
+		for _, path := range paths {
+			if mapping.Match(path) {
+				skipped[mapping.Pipeline] = false
+				break
+			}
+		}
+	}
+	for name, skip := range skipped {
+		if !skip {
+			delete(skipped, name)
+		}
+	}
+	return skipped
+}
+
+// helper function returns true if the stage dependencies
+// are all skipped, in which case the stage is not waiting
+// for any other stage and can be scheduled immediately.
+func depsSkipped(deps []string, skipped map[string]bool) bool {
+	if len(deps) == 0 {
+		return false
+	}
+	for _, dep := range deps {
+		if !skipped[dep] {
+			return false
+		}
+	}
+	return true
+}
+
+// helper function returns true if all stages are skipped.
+func allSkipped(stages []*core.Stage) bool {
+	for _, stage := range stages {
+		if stage.Status != core.StatusSkipped {
+			return false
+		}
+	}
+	return true
+}
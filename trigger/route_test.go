@@ -0,0 +1,71 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package trigger
+
+import (
+	"testing"
+
+	"github.com/drone/drone/core"
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_skipRoutes(t *testing.T) {
+	mappings := []*core.PathMapping{
+		{Pattern: "service-a/**", Pipeline: "service-a"},
+		{Pattern: "service-b/**", Pipeline: "service-b"},
+		{Pattern: "shared/**", Pipeline: "service-a"},
+		{Pattern: "shared/**", Pipeline: "service-b"},
+	}
+	tests := []struct {
+		paths []string
+		want  map[string]bool
+	}{
+		{
+			paths: nil,
+			want:  nil,
+		},
+		{
+			paths: []string{"service-a/main.go"},
+			want:  map[string]bool{"service-b": true},
+		},
+		{
+			paths: []string{"service-b/cmd/main.go"},
+			want:  map[string]bool{"service-a": true},
+		},
+		{
+			paths: []string{"shared/util.go"},
+			want:  map[string]bool{},
+		},
+		{
+			paths: []string{"README.md"},
+			want:  map[string]bool{"service-a": true, "service-b": true},
+		},
+	}
+	for _, test := range tests {
+		got := skipRoutes(mappings, test.paths)
+		if diff := cmp.Diff(test.want, got); diff != "" {
+			t.Errorf("Unexpected skipped pipelines for paths %v", test.paths)
+			t.Log(diff)
+		}
+	}
+}
+
+func Test_depsSkipped(t *testing.T) {
+	skipped := map[string]bool{"service-a": true, "service-b": true}
+	tests := []struct {
+		deps []string
+		want bool
+	}{
+		{nil, false},
+		{[]string{"service-a"}, true},
+		{[]string{"service-a", "service-b"}, true},
+		{[]string{"service-a", "service-c"}, false},
+	}
+	for _, test := range tests {
+		if got, want := depsSkipped(test.deps, skipped), test.want; got != want {
+			t.Errorf("Want deps %v skipped %v, got %v", test.deps, want, got)
+		}
+	}
+}
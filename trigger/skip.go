@@ -5,10 +5,12 @@
 package trigger
 
 import (
+	"regexp"
 	"strings"
 
 	"github.com/drone/drone-yaml/yaml"
 	"github.com/drone/drone/core"
+	"github.com/drone/drone/extension"
 )
 
 func skipBranch(document *yaml.Pipeline, branch string) bool {
@@ -35,14 +37,67 @@ func skipRepo(document *yaml.Pipeline, repo string) bool {
 	return !document.Trigger.Repo.Match(repo)
 }
 
-func skipMessage(hook *core.Hook) bool {
+// helper function returns true if the pipeline tag trigger
+// does not match the tag.
+func skipTag(ext *extension.Pipeline, tag string) bool {
+	if ext == nil || ext.Trigger == nil {
+		return false
+	}
+	return !ext.Trigger.Tag.Match(tag)
+}
+
+// helper function returns true if the pipeline trigger
+// expression does not match the build. An error is returned
+// if the expression is invalid.
+func skipExpr(ext *extension.Pipeline, vars map[string]string) (bool, error) {
+	if ext == nil {
+		return false, nil
+	}
+	ok, err := ext.Trigger.Match(vars)
+	return !ok, err
+}
+
+func skipMessage(repo *core.Repository, hook *core.Hook) bool {
 	switch {
 	case hook.Event == core.EventTag:
 		return false
+	case hook.Event == core.EventCustom:
+		return false
 	case skipMessageEval(hook.Message):
 		return true
 	case skipMessageEval(hook.Title):
 		return true
+	case skipPatternEval(repo.SkipPattern, hook.Message):
+		return true
+	case skipPatternEval(repo.SkipPattern, hook.Title):
+		return true
+	default:
+		return false
+	}
+}
+
+// helper function returns true if the hook may be skipped
+// in favor of an existing build for the same commit. Tag and
+// cron builds are always executed, as are builds that restart
+// or promote an existing build.
+func skipDuplicate(repo *core.Repository, hook *core.Hook) bool {
+	switch {
+	case !repo.IgnoreDupes:
+		return false
+	case hook.Force:
+		return false
+	case hook.Parent != 0:
+		return false
+	case hook.Trigger == core.TriggerCron:
+		return false
+	case hook.After == "":
+		return false
+	}
+	switch hook.Event {
+	case core.EventPush,
+		core.EventPullRequest,
+		core.EventCustom:
+		return true
 	default:
 		return false
 	}
@@ -60,6 +115,20 @@ func skipMessageEval(str string) bool {
 	}
 }
 
+// helper function returns true if the string matches the
+// repository skip pattern. The pattern is a case-insensitive
+// regular expression. Invalid patterns never match.
+func skipPatternEval(pattern, str string) bool {
+	if pattern == "" || str == "" {
+		return false
+	}
+	re, err := regexp.Compile("(?i)" + pattern)
+	if err != nil {
+		return false
+	}
+	return re.MatchString(str)
+}
+
 // func skipPaths(document *config.Config, paths []string) bool {
 // 	switch {
 // 	// changed files are only returned for push and pull request
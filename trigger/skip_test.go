@@ -9,6 +9,7 @@ import (
 
 	"github.com/drone/drone-yaml/yaml"
 	"github.com/drone/drone/core"
+	"github.com/drone/drone/extension"
 )
 
 func Test_skipBranch(t *testing.T) {
@@ -81,6 +82,95 @@ func Test_skipEvent(t *testing.T) {
 	}
 }
 
+func Test_skipTag(t *testing.T) {
+	tests := []struct {
+		config string
+		tag    string
+		want   bool
+	}{
+		{
+			config: "kind: pipeline\ntrigger: { }",
+			tag:    "",
+			want:   false,
+		},
+		{
+			config: "kind: pipeline\ntrigger: { tag: v1.* }",
+			tag:    "v1.2.0",
+			want:   false,
+		},
+		{
+			config: "kind: pipeline\ntrigger: { tag: [ v1.*, v2.* ] }",
+			tag:    "v3.0.0",
+			want:   true,
+		},
+		{
+			config: "kind: pipeline\ntrigger: { tag: v1.* }",
+			tag:    "",
+			want:   true,
+		},
+		{
+			config: "kind: pipeline\ntrigger: { tag: { exclude: [ '*-rc*' ] } }",
+			tag:    "v1.0.0-rc1",
+			want:   true,
+		},
+	}
+	for i, test := range tests {
+		manifest, err := extension.ParseString(test.config)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		got, want := skipTag(manifest.Lookup("default"), test.tag), test.want
+		if got != want {
+			t.Errorf("Want test %d to return %v", i, want)
+		}
+	}
+}
+
+func Test_skipExpr(t *testing.T) {
+	tests := []struct {
+		config string
+		want   bool
+		err    bool
+	}{
+		{
+			config: "kind: pipeline\ntrigger: { branch: [ master ] }",
+			want:   false,
+		},
+		{
+			config: "kind: pipeline\ntrigger: { expr: 'branch == \"master\" && event == \"push\"' }",
+			want:   false,
+		},
+		{
+			config: "kind: pipeline\ntrigger: { expr: 'branch == \"develop\"' }",
+			want:   true,
+		},
+		{
+			config: "kind: pipeline\ntrigger: { expr: 'branch ==' }",
+			want:   true,
+			err:    true,
+		},
+	}
+	vars := map[string]string{"branch": "master", "event": "push"}
+	for i, test := range tests {
+		manifest, err := extension.ParseString(test.config)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		got, err := skipExpr(manifest.Lookup("default"), vars)
+		if test.err != (err != nil) {
+			t.Errorf("Want test %d error %v, got %v", i, test.err, err)
+		}
+		if got != test.want {
+			t.Errorf("Want test %d to return %v", i, test.want)
+		}
+	}
+	if skip, _ := skipExpr(nil, vars); skip {
+		t.Errorf("Want pipeline without extensions executed")
+	}
+}
+
 // func Test_skipPath(t *testing.T) {
 // 	tests := []struct {
 // 		config string
@@ -178,6 +268,12 @@ func Test_skipMessage(t *testing.T) {
 			title: "update readme [CI SKIP]",
 			want:  false,
 		},
+		// ignore [CI SKIP] when event is custom
+		{
+			event:   "custom",
+			message: "update readme [CI SKIP]",
+			want:    false,
+		},
 	}
 	for _, test := range tests {
 		hook := &core.Hook{
@@ -185,7 +281,7 @@ func Test_skipMessage(t *testing.T) {
 			Title:   test.title,
 			Event:   test.event,
 		}
-		got, want := skipMessage(hook), test.want
+		got, want := skipMessage(&core.Repository{}, hook), test.want
 		if got != want {
 			t.Errorf("Want { event: %q, message: %q, title: %q } to return %v",
 				test.event, test.message, test.title, want)
@@ -193,6 +289,104 @@ func Test_skipMessage(t *testing.T) {
 	}
 }
 
+func Test_skipMessagePattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		event   string
+		message string
+		title   string
+		want    bool
+	}{
+		{
+			pattern: `\[(wip|draft)\]`,
+			event:   "push",
+			message: "update readme [WIP]",
+			want:    true,
+		},
+		{
+			pattern: `\[(wip|draft)\]`,
+			event:   "pull_request",
+			title:   "[draft] update readme",
+			want:    true,
+		},
+		{
+			pattern: `\[(wip|draft)\]`,
+			event:   "push",
+			message: "update readme",
+			want:    false,
+		},
+		// ignore pattern when event is tag
+		{
+			pattern: `\[(wip|draft)\]`,
+			event:   "tag",
+			message: "update readme [WIP]",
+			want:    false,
+		},
+		// ignore invalid pattern
+		{
+			pattern: `[wip`,
+			event:   "push",
+			message: "update readme [wip",
+			want:    false,
+		},
+		// default directives are always honored
+		{
+			pattern: `\[(wip|draft)\]`,
+			event:   "push",
+			message: "update readme [skip ci]",
+			want:    true,
+		},
+	}
+	for _, test := range tests {
+		repo := &core.Repository{SkipPattern: test.pattern}
+		hook := &core.Hook{
+			Message: test.message,
+			Title:   test.title,
+			Event:   test.event,
+		}
+		got, want := skipMessage(repo, hook), test.want
+		if got != want {
+			t.Errorf("Want { pattern: %q, event: %q, message: %q, title: %q } to return %v",
+				test.pattern, test.event, test.message, test.title, want)
+		}
+	}
+}
+
+func Test_skipDuplicate(t *testing.T) {
+	tests := []struct {
+		dupes   bool
+		force   bool
+		parent  int64
+		trigger string
+		event   string
+		after   string
+		want    bool
+	}{
+		{true, false, 0, core.TriggerHook, core.EventPush, "7fd1a60", true},
+		{true, false, 0, core.TriggerHook, core.EventPullRequest, "7fd1a60", true},
+		{true, false, 0, "octocat", core.EventCustom, "7fd1a60", true},
+		{true, false, 0, core.TriggerHook, core.EventTag, "7fd1a60", false},
+		{true, false, 0, core.TriggerCron, core.EventPush, "7fd1a60", false},
+		{true, false, 0, core.TriggerHook, core.EventPush, "", false},
+		{true, false, 1, "octocat", core.EventPush, "7fd1a60", false},
+		{true, true, 0, "octocat", core.EventCustom, "7fd1a60", false},
+		{false, false, 0, core.TriggerHook, core.EventPush, "7fd1a60", false},
+	}
+	for i, test := range tests {
+		repo := &core.Repository{IgnoreDupes: test.dupes}
+		hook := &core.Hook{
+			Force:   test.force,
+			Parent:  test.parent,
+			Trigger: test.trigger,
+			Event:   test.event,
+			After:   test.after,
+		}
+		if got, want := skipDuplicate(repo, hook), test.want; got != want {
+			t.Errorf("Want test %d to return %v", i, want)
+		}
+	}
+}
+
 func Test_skipMessageEval(t *testing.T) {
 	tests := []struct {
 		eval string
@@ -6,6 +6,8 @@ package trigger
 
 import (
 	"context"
+	"database/sql"
+	"fmt"
 	"runtime/debug"
 	"strings"
 	"time"
@@ -13,44 +15,60 @@ import (
 	"github.com/drone/drone-yaml/yaml"
 	"github.com/drone/drone-yaml/yaml/converter"
 	"github.com/drone/drone-yaml/yaml/linter"
-	"github.com/drone/drone-yaml/yaml/signer"
 
 	"github.com/drone/drone/core"
+	"github.com/drone/drone/extension"
+	"github.com/drone/drone/extension/expr"
 
 	"github.com/sirupsen/logrus"
 )
 
 type triggerer struct {
-	config  core.ConfigService
-	commits core.CommitService
-	status  core.StatusService
-	builds  core.BuildStore
-	sched   core.Scheduler
-	repos   core.RepositoryStore
-	users   core.UserStore
-	hooks   core.WebhookSender
+	config    core.ConfigService
+	commits   core.CommitService
+	pulls     core.PullRequestService
+	status    core.StatusService
+	builds    core.BuildStore
+	sched     core.Scheduler
+	repos     core.RepositoryStore
+	policies  core.PolicyStore
+	pools     core.PoolService
+	platforms core.PlatformService
+	mappings  core.PathMappingStore
+	users     core.UserStore
+	hooks     core.WebhookSender
 }
 
 // New returns a new build triggerer.
 func New(
 	config core.ConfigService,
 	commits core.CommitService,
+	pulls core.PullRequestService,
 	status core.StatusService,
 	builds core.BuildStore,
 	sched core.Scheduler,
 	repos core.RepositoryStore,
+	policies core.PolicyStore,
+	pools core.PoolService,
+	platforms core.PlatformService,
+	mappings core.PathMappingStore,
 	users core.UserStore,
 	hooks core.WebhookSender,
 ) core.Triggerer {
 	return &triggerer{
-		config:  config,
-		commits: commits,
-		status:  status,
-		builds:  builds,
-		sched:   sched,
-		repos:   repos,
-		users:   users,
-		hooks:   hooks,
+		config:    config,
+		commits:   commits,
+		pulls:     pulls,
+		status:    status,
+		builds:    builds,
+		sched:     sched,
+		repos:     repos,
+		policies:  policies,
+		pools:     pools,
+		platforms: platforms,
+		mappings:  mappings,
+		users:     users,
+		hooks:     hooks,
 	}
 }
 
@@ -74,10 +92,6 @@ func (t *triggerer) Trigger(ctx context.Context, repo *core.Repository, base *co
 		}
 	}()
 
-	if skipMessage(base) {
-		logger.Infoln("trigger: skipping hook. found skip directive")
-		return nil, nil
-	}
 	if base.Event == core.EventPullRequest {
 		if repo.IgnorePulls {
 			logger.Infoln("trigger: skipping hook. project ignores pull requests")
@@ -87,6 +101,30 @@ func (t *triggerer) Trigger(ctx context.Context, repo *core.Repository, base *co
 			logger.Infoln("trigger: skipping hook. project ignores forks")
 			return nil, nil
 		}
+		// gitlab exposes the result of merging the merge
+		// request into the target branch as a separate ref,
+		// which is optionally built instead of the source.
+		if repo.MergePulls {
+			base.Ref = mergeRef(base.Ref)
+		}
+	}
+
+	// commits that already passed are not executed again if
+	// the project ignores duplicate builds (e.g. branch fast
+	// forwards or re-delivered hooks). The existing build is
+	// returned instead.
+	if skipDuplicate(repo, base) {
+		build, err := t.builds.FindCommit(ctx, repo.ID, base.After, base.Event)
+		if err == nil {
+			logger = logger.WithField("build", build.Number)
+			logger.Infoln("trigger: skipping hook. commit already passed")
+			return build, nil
+		}
+		if err != sql.ErrNoRows {
+			logger = logger.WithError(err)
+			logger.Warnln("trigger: cannot find build for commit")
+			return nil, err
+		}
 	}
 
 	user, err := t.users.Find(ctx, repo.UserID)
@@ -101,13 +139,23 @@ func (t *triggerer) Trigger(ctx context.Context, repo *core.Repository, base *co
 		return nil, nil
 	}
 
-	// if the commit message is not included we should
-	// make an optional API call to the version control
-	// system to augment the available information.
-	if base.Message == "" && base.After != "" {
+	// commits with a skip directive are not executed, however,
+	// a skipped build is created to keep the history complete.
+	// Hooks that are ignored above do not create a build.
+	if skipMessage(repo, base) {
+		logger.Infoln("trigger: skipping hook. found skip directive")
+		return t.createBuildSkipped(ctx, repo, base)
+	}
+
+	// if the commit message or author avatar is not included
+	// we should make an optional API call to the version
+	// control system to augment the available information.
+	if (base.Message == "" || base.AuthorAvatar == "") && base.After != "" {
 		commit, err := t.commits.Find(ctx, user, repo.Slug, base.After)
 		if err == nil && commit != nil {
-			base.Message = commit.Message
+			if base.Message == "" {
+				base.Message = commit.Message
+			}
 			if base.AuthorEmail == "" {
 				base.AuthorEmail = commit.Author.Email
 			}
@@ -201,18 +249,43 @@ func (t *triggerer) Trigger(ctx context.Context, repo *core.Repository, base *co
 		return t.createBuildError(ctx, repo, base, err.Error())
 	}
 
-	err = linter.Manifest(manifest, repo.Trusted)
+	// pipelines in untrusted repositories that request
+	// privileged containers or host volumes
+	// are blocked pending approval by a system administrator,
+	// instead of failing the linter.
+	elevated := false
+	if !repo.Trusted {
+		for _, resource := range manifest.Resources {
+			if pipeline, ok := resource.(*yaml.Pipeline); ok && requiresTrust(pipeline) {
+				elevated = true
+			}
+		}
+	}
+
+	err = linter.Manifest(manifest, repo.Trusted || elevated)
 	if err != nil {
 		logger = logger.WithError(err)
 		logger.Warnln("trigger: yaml linting error")
 		return t.createBuildError(ctx, repo, base, err.Error())
 	}
 
+	extensions, err := extension.ParseString(raw.Data)
+	if err != nil {
+		logger = logger.WithError(err)
+		logger.Warnln("trigger: cannot parse yaml extensions")
+		return t.createBuildError(ctx, repo, base, err.Error())
+	}
+
+	// if the repository is protected the configuration must
+	// be signed. Unsigned or tampered configurations are
+	// blocked pending approval by a repository administrator.
 	verified := true
-	if repo.Protected && base.Trigger == core.TriggerHook {
-		key := signer.KeyString(repo.Secret)
-		val := []byte(raw.Data)
-		verified, _ = signer.Verify(val, key)
+	if repo.Protected {
+		if err := verifySignature(manifest, raw.Data, repo.Secret); err != nil {
+			logger.WithError(err).
+				Infoln("trigger: cannot verify yaml signature, blocking build")
+			verified = false
+		}
 	}
 
 	// var paths []string
@@ -222,6 +295,10 @@ func (t *triggerer) Trigger(ctx context.Context, repo *core.Repository, base *co
 	// 		Msg("cannot fetch changeset")
 	// }
 
+	// the trigger expressions are evaluated against the
+	// build metadata, in addition to the trigger conditions.
+	vars := expr.Vars(repo, req.Build, "")
+
 	var matched []*yaml.Pipeline
 	for _, document := range manifest.Resources {
 		pipeline, ok := document.(*yaml.Pipeline)
@@ -258,9 +335,29 @@ func (t *triggerer) Trigger(ctx context.Context, repo *core.Repository, base *co
 			logger = logger.WithField("pipeline", pipeline.Name)
 			logger.Infoln("trigger: skipping pipeline, does not match deploy target")
 			continue
-		} else {
-			matched = append(matched, pipeline)
 		}
+
+		name := pipeline.Name
+		if name == "" {
+			name = "default"
+		}
+		if skipTag(extensions.Lookup(name), vars["tag"]) {
+			logger = logger.WithField("pipeline", pipeline.Name)
+			logger.Infoln("trigger: skipping pipeline, does not match tag")
+			continue
+		}
+		skip, err := skipExpr(extensions.Lookup(name), vars)
+		if err != nil {
+			logger = logger.WithError(err).WithField("pipeline", pipeline.Name)
+			logger.Warnln("trigger: cannot parse trigger expression")
+			return t.createBuildError(ctx, repo, base, err.Error())
+		}
+		if skip {
+			logger = logger.WithField("pipeline", pipeline.Name)
+			logger.Infoln("trigger: skipping pipeline, does not match expression")
+			continue
+		}
+		matched = append(matched, pipeline)
 	}
 
 	if len(matched) == 0 {
@@ -268,6 +365,96 @@ func (t *triggerer) Trigger(ctx context.Context, repo *core.Repository, base *co
 		return nil, nil
 	}
 
+	// the pipelines are validated against the organization
+	// policy. Builds that violate the policy are not executed,
+	// and the violation is stored with the build.
+	policy, err := t.policies.Find(ctx, repo.Namespace)
+	if err != nil && err != sql.ErrNoRows {
+		logger = logger.WithError(err)
+		logger.Errorln("trigger: cannot find organization policy")
+		return nil, err
+	}
+	if err == nil {
+		for _, pipeline := range matched {
+			if err := checkPolicy(policy, pipeline); err != nil {
+				logger = logger.WithError(err)
+				logger.Infoln("trigger: pipeline violates organization policy")
+				return t.createBuildError(ctx, repo, base, err.Error())
+			}
+		}
+	}
+
+	// pipelines that request a runner pool are executed on
+	// runners with the pool labels. Builds that request an
+	// unknown pool are not executed.
+	labels := map[*yaml.Pipeline]map[string]string{}
+	for _, pipeline := range matched {
+		labels[pipeline] = pipeline.Node
+		name := pipeline.Name
+		if name == "" {
+			name = "default"
+		}
+		ext := extensions.Lookup(name)
+		if ext == nil || ext.Pool == "" {
+			continue
+		}
+		pool, err := t.pools.Find(ctx, ext.Pool)
+		if err == core.ErrPoolNotFound {
+			logger = logger.WithField("pool", ext.Pool)
+			logger.Infoln("trigger: pipeline requests unknown runner pool")
+			return t.createBuildError(ctx, repo, base,
+				fmt.Sprintf("pool: cannot find runner pool %s", ext.Pool))
+		}
+		if err != nil {
+			logger = logger.WithError(err)
+			logger.Errorln("trigger: cannot find runner pool")
+			return nil, err
+		}
+		labels[pipeline] = mergeLabels(pool.Labels, pipeline.Node)
+	}
+
+	// the pipelines are validated against the platforms of
+	// the connected agents. Builds that target a platform no
+	// agent can serve would otherwise remain pending forever.
+	for _, pipeline := range matched {
+		name := pipeline.Name
+		if name == "" {
+			name = "default"
+		}
+		platform := toPlatform(pipeline, labels[pipeline])
+		err := t.platforms.Validate(ctx, platform)
+		if err == core.ErrPlatformUnavailable {
+			logger = logger.WithField("platform", platform.String())
+			logger.Infoln("trigger: no agent available for the pipeline platform")
+			return t.createBuildError(ctx, repo, base,
+				fmt.Sprintf("platform: no agent available for pipeline %s (%s)", name, platform))
+		}
+		if err != nil {
+			logger = logger.WithError(err)
+			logger.Errorln("trigger: cannot validate pipeline platform")
+			return nil, err
+		}
+	}
+
+	// the pull request labels and the number of changed files
+	// are stored with the build, so that clients can render
+	// the build without making an API call to the version
+	// control system.
+	var pullLabels []string
+	if pr := t.findPull(ctx, user, repo, base); pr != nil {
+		pullLabels = pr.Labels
+		if base.Title == "" {
+			base.Title = pr.Title
+		}
+	}
+	changes := t.listChanges(ctx, user, repo, base)
+
+	// pipelines mapped to repository paths are skipped if
+	// the build does not change any of the mapped paths. The
+	// stages are created in the skipped state to keep the
+	// history complete.
+	skipped := t.route(ctx, repo, changes)
+
 	repo, err = t.repos.Increment(ctx, repo)
 	if err != nil {
 		logger = logger.WithError(err)
@@ -285,23 +472,27 @@ func (t *triggerer) Trigger(ctx context.Context, repo *core.Repository, base *co
 		Action:  base.Action,
 		Link:    base.Link,
 		// Timestamp:    base.Timestamp,
-		Title:        trunc(base.Title, 2000),
-		Message:      trunc(base.Message, 2000),
-		Before:       base.Before,
-		After:        base.After,
-		Ref:          base.Ref,
-		Fork:         base.Fork,
-		Source:       base.Source,
-		Target:       base.Target,
-		Author:       base.Author,
-		AuthorName:   base.AuthorName,
-		AuthorEmail:  base.AuthorEmail,
-		AuthorAvatar: base.AuthorAvatar,
-		Params:       base.Params,
-		Deploy:       base.Deployment,
-		Sender:       base.Sender,
-		Created:      time.Now().Unix(),
-		Updated:      time.Now().Unix(),
+		Title:         trunc(base.Title, 2000),
+		Message:       trunc(base.Message, 2000),
+		Before:        base.Before,
+		After:         base.After,
+		Ref:           base.Ref,
+		Fork:          base.Fork,
+		Source:        base.Source,
+		Target:        base.Target,
+		Author:        base.Author,
+		AuthorName:    base.AuthorName,
+		AuthorEmail:   base.AuthorEmail,
+		AuthorAvatar:  base.AuthorAvatar,
+		Params:        base.Params,
+		Deploy:        base.Deployment,
+		RestartedFrom: restartedFrom(base),
+		TriggeredBy:   triggeredBy(base),
+		Labels:        buildLabels(base.Labels, pullLabels, pipelineLabels(matched, extensions)),
+		ChangedFiles:  int64(len(changes)),
+		Sender:        base.Sender,
+		Created:       time.Now().Unix(),
+		Updated:       time.Now().Unix(),
 	}
 
 	stages := make([]*core.Stage, len(matched))
@@ -323,11 +514,13 @@ func (t *triggerer) Trigger(ctx context.Context, repo *core.Repository, base *co
 			Variant:   match.Platform.Variant,
 			Kernel:    match.Platform.Version,
 			Limit:     match.Concurrency.Limit,
+			LimitRepo: int(repo.Throttle),
 			Status:    core.StatusWaiting,
 			DependsOn: match.DependsOn,
 			OnSuccess: onSuccess,
 			OnFailure: onFailure,
-			Labels:    match.Node,
+			Labels:    labels[match],
+			Elevated:  !repo.Trusted && requiresTrust(match),
 			Created:   time.Now().Unix(),
 			Updated:   time.Now().Unix(),
 		}
@@ -342,14 +535,48 @@ func (t *triggerer) Trigger(ctx context.Context, repo *core.Repository, base *co
 		if stage.Name == "" {
 			stage.Name = "default"
 		}
-		if verified == false {
+		if ext := extensions.Lookup(stage.Name); ext != nil {
+			stage.Timeout = toMinutes(ext.Timeout)
+			stage.ErrIgnore = ext.IgnoreFailure()
+			stage.Matrix = ext.Matrix
+		}
+		switch {
+		case skipped[stage.Name]:
+			logger = logger.WithField("pipeline", stage.Name)
+			logger.Infoln("trigger: skipping pipeline, does not match mapped paths")
+			stage.Status = core.StatusSkipped
+			stage.Started = time.Now().Unix()
+			stage.Stopped = time.Now().Unix()
+		case verified == false:
+			// every stage of an unverified configuration is
+			// blocked, including stages with dependencies, so
+			// that each stage requires approval.
+			stage.Status = core.StatusBlocked
+		case stage.Elevated:
 			stage.Status = core.StatusBlocked
-		} else if len(stage.DependsOn) == 0 {
+		case len(stage.DependsOn) != 0 && !depsSkipped(stage.DependsOn, skipped):
+			// stages with dependencies remain in the waiting
+			// state, and are scheduled when the stages they
+			// depend on are approved and complete.
+		default:
 			stage.Status = core.StatusPending
 		}
+		// the queue time is recorded when the stage becomes
+		// eligible for execution.
+		if stage.Status == core.StatusPending {
+			stage.Queued = stage.Created
+		}
 		stages[i] = stage
 	}
 
+	// if every pipeline is skipped the build is finished
+	// without scheduling any stages.
+	if len(skipped) != 0 && allSkipped(stages) {
+		build.Status = core.StatusSkipped
+		build.Started = time.Now().Unix()
+		build.Finished = time.Now().Unix()
+	}
+
 	err = t.builds.Create(ctx, build, stages)
 	if err != nil {
 		logger = logger.WithError(err)
@@ -367,10 +594,7 @@ func (t *triggerer) Trigger(ctx context.Context, repo *core.Repository, base *co
 	}
 
 	for _, stage := range stages {
-		if len(stage.DependsOn) != 0 {
-			continue
-		}
-		if stage.Status == core.StatusBlocked {
+		if stage.Status != core.StatusPending {
 			continue
 		}
 		err = t.sched.Schedule(ctx, stage)
@@ -414,6 +638,12 @@ func (t *triggerer) Trigger(ctx context.Context, repo *core.Repository, base *co
 	return build, nil
 }
 
+// helper function converts the duration to minutes, rounding
+// up to the nearest minute.
+func toMinutes(d time.Duration) int64 {
+	return int64((d + time.Minute - 1) / time.Minute)
+}
+
 func trunc(s string, i int) string {
 	runes := []rune(s)
 	if len(runes) > i {
@@ -423,6 +653,16 @@ func trunc(s string, i int) string {
 }
 
 func (t *triggerer) createBuildError(ctx context.Context, repo *core.Repository, base *core.Hook, message string) (*core.Build, error) {
+	return t.createBuildFinished(ctx, repo, base, core.StatusError, message)
+}
+
+func (t *triggerer) createBuildSkipped(ctx context.Context, repo *core.Repository, base *core.Hook) (*core.Build, error) {
+	return t.createBuildFinished(ctx, repo, base, core.StatusSkipped, "")
+}
+
+// helper function creates a build without stages that is
+// finished with the given status.
+func (t *triggerer) createBuildFinished(ctx context.Context, repo *core.Repository, base *core.Hook, status, message string) (*core.Build, error) {
 	repo, err := t.repos.Increment(ctx, repo)
 	if err != nil {
 		return nil, err
@@ -432,29 +672,32 @@ func (t *triggerer) createBuildError(ctx context.Context, repo *core.Repository,
 		RepoID: repo.ID,
 		Number: repo.Counter,
 		Parent: base.Parent,
-		Status: core.StatusError,
+		Status: status,
 		Error:  message,
 		Event:  base.Event,
 		Action: base.Action,
 		Link:   base.Link,
 		// Timestamp:    base.Timestamp,
-		Title:        base.Title,
-		Message:      base.Message,
-		Before:       base.Before,
-		After:        base.After,
-		Ref:          base.Ref,
-		Fork:         base.Fork,
-		Source:       base.Source,
-		Target:       base.Target,
-		Author:       base.Author,
-		AuthorName:   base.AuthorName,
-		AuthorEmail:  base.AuthorEmail,
-		AuthorAvatar: base.AuthorAvatar,
-		Deploy:       base.Deployment,
-		Sender:       base.Sender,
-		Created:      time.Now().Unix(),
-		Updated:      time.Now().Unix(),
-		Finished:     time.Now().Unix(),
+		Title:         base.Title,
+		Message:       base.Message,
+		Before:        base.Before,
+		After:         base.After,
+		Ref:           base.Ref,
+		Fork:          base.Fork,
+		Source:        base.Source,
+		Target:        base.Target,
+		Author:        base.Author,
+		AuthorName:    base.AuthorName,
+		AuthorEmail:   base.AuthorEmail,
+		AuthorAvatar:  base.AuthorAvatar,
+		Deploy:        base.Deployment,
+		RestartedFrom: restartedFrom(base),
+		TriggeredBy:   triggeredBy(base),
+		Labels:        buildLabels(base.Labels),
+		Sender:        base.Sender,
+		Created:       time.Now().Unix(),
+		Updated:       time.Now().Unix(),
+		Finished:      time.Now().Unix(),
 	}
 
 	err = t.builds.Create(ctx, build, nil)
@@ -9,6 +9,7 @@ import (
 	"database/sql"
 	"io"
 	"io/ioutil"
+	"strings"
 	"testing"
 
 	"github.com/drone/drone/core"
@@ -70,164 +71,1048 @@ func TestTrigger(t *testing.T) {
 	mockWebhooks := mock.NewMockWebhookSender(controller)
 	mockWebhooks.EXPECT().Send(gomock.Any(), gomock.Any()).Return(nil)
 
+	mockPolicies := mock.NewMockPolicyStore(controller)
+	mockPolicies.EXPECT().Find(gomock.Any(), dummyRepo.Namespace).Return(nil, sql.ErrNoRows)
+
+	mockPlatforms := mock.NewMockPlatformService(controller)
+	mockPlatforms.EXPECT().Validate(gomock.Any(), gomock.Any()).Return(nil)
+
+	mockCommits := mock.NewMockCommitService(controller)
+	mockCommits.EXPECT().ListChanges(gomock.Any(), dummyUser, dummyRepo.Slug, dummyHook.After, dummyHook.Ref).Return(dummyChanges, nil)
+
+	mockMappings := mock.NewMockPathMappingStore(controller)
+	mockMappings.EXPECT().List(gomock.Any(), dummyRepo.ID).Return(nil, nil)
+
+	triggerer := New(
+		mockConfigService,
+		mockCommits,
+		nil,
+		mockStatus,
+		mockBuilds,
+		mockQueue,
+		mockRepos,
+		mockPolicies,
+		nil,
+		mockPlatforms,
+		mockMappings,
+		mockUsers,
+		mockWebhooks,
+	)
+
+	build, err := triggerer.Trigger(noContext, dummyRepo, dummyHook)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if diff := cmp.Diff(build, dummyBuild, ignoreBuildFileds); diff != "" {
+		t.Errorf(diff)
+	}
+}
+
+// this test verifies that the stage attributes defined in
+// the yaml extensions are copied to the stage.
+func TestTrigger_StageExtensions(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	checkBuild := func(_ context.Context, build *core.Build, stages []*core.Stage) {
+		if got, want := stages[0].Timeout, int64(91); got != want {
+			t.Errorf("Want stage timeout %d, got %d", want, got)
+		}
+		if !stages[0].ErrIgnore {
+			t.Errorf("Expect stage ignores failure")
+		}
+		if stages[0].Queued == 0 {
+			t.Errorf("Expect stage queue time recorded")
+		}
+	}
+
+	mockUsers := mock.NewMockUserStore(controller)
+	mockUsers.EXPECT().Find(gomock.Any(), dummyRepo.UserID).Return(dummyUser, nil)
+
+	mockRepos := mock.NewMockRepositoryStore(controller)
+	mockRepos.EXPECT().Increment(gomock.Any(), dummyRepo).Return(dummyRepo, nil)
+
+	mockConfigService := mock.NewMockConfigService(controller)
+	mockConfigService.EXPECT().Find(gomock.Any(), gomock.Any()).Return(dummyYamlExtensions, nil)
+
+	mockStatus := mock.NewMockStatusService(controller)
+	mockStatus.EXPECT().Send(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+
+	mockQueue := mock.NewMockScheduler(controller)
+	mockQueue.EXPECT().Schedule(gomock.Any(), gomock.Any()).Return(nil)
+
+	mockBuilds := mock.NewMockBuildStore(controller)
+	mockBuilds.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any()).Do(checkBuild).Return(nil)
+
+	mockWebhooks := mock.NewMockWebhookSender(controller)
+	mockWebhooks.EXPECT().Send(gomock.Any(), gomock.Any()).Return(nil)
+
+	mockPolicies := mock.NewMockPolicyStore(controller)
+	mockPolicies.EXPECT().Find(gomock.Any(), dummyRepo.Namespace).Return(nil, sql.ErrNoRows)
+
+	mockPlatforms := mock.NewMockPlatformService(controller)
+	mockPlatforms.EXPECT().Validate(gomock.Any(), gomock.Any()).Return(nil)
+
+	mockCommits := mock.NewMockCommitService(controller)
+	mockCommits.EXPECT().ListChanges(gomock.Any(), dummyUser, dummyRepo.Slug, dummyHook.After, dummyHook.Ref).Return(dummyChanges, nil)
+
+	mockMappings := mock.NewMockPathMappingStore(controller)
+	mockMappings.EXPECT().List(gomock.Any(), dummyRepo.ID).Return(nil, nil)
+
+	triggerer := New(
+		mockConfigService,
+		mockCommits,
+		nil,
+		mockStatus,
+		mockBuilds,
+		mockQueue,
+		mockRepos,
+		mockPolicies,
+		nil,
+		mockPlatforms,
+		mockMappings,
+		mockUsers,
+		mockWebhooks,
+	)
+
+	_, err := triggerer.Trigger(noContext, dummyRepo, dummyHook)
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+// this test verifies that the build is blocked if the
+// repository is protected and the yaml is not signed.
+func TestTrigger_Unsigned(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	protected := new(core.Repository)
+	*protected = *dummyRepo
+	protected.Protected = true
+
+	checkBuild := func(_ context.Context, build *core.Build, stages []*core.Stage) {
+		if got, want := stages[0].Status, core.StatusBlocked; got != want {
+			t.Errorf("Want stage status %q, got %q", want, got)
+		}
+		if stages[0].Queued != 0 {
+			t.Errorf("Expect blocked stage not queued")
+		}
+	}
+
+	mockUsers := mock.NewMockUserStore(controller)
+	mockUsers.EXPECT().Find(gomock.Any(), dummyRepo.UserID).Return(dummyUser, nil)
+
+	mockRepos := mock.NewMockRepositoryStore(controller)
+	mockRepos.EXPECT().Increment(gomock.Any(), protected).Return(protected, nil)
+
+	mockConfigService := mock.NewMockConfigService(controller)
+	mockConfigService.EXPECT().Find(gomock.Any(), gomock.Any()).Return(dummyYaml, nil)
+
+	mockStatus := mock.NewMockStatusService(controller)
+	mockStatus.EXPECT().Send(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+
+	mockBuilds := mock.NewMockBuildStore(controller)
+	mockBuilds.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any()).Do(checkBuild).Return(nil)
+
+	mockWebhooks := mock.NewMockWebhookSender(controller)
+	mockWebhooks.EXPECT().Send(gomock.Any(), gomock.Any()).Return(nil)
+
+	// the scheduler has no expectations, which verifies
+	// the blocked stage is not scheduled.
+	mockQueue := mock.NewMockScheduler(controller)
+
+	mockPolicies := mock.NewMockPolicyStore(controller)
+	mockPolicies.EXPECT().Find(gomock.Any(), dummyRepo.Namespace).Return(nil, sql.ErrNoRows)
+
+	mockPlatforms := mock.NewMockPlatformService(controller)
+	mockPlatforms.EXPECT().Validate(gomock.Any(), gomock.Any()).Return(nil)
+
+	mockCommits := mock.NewMockCommitService(controller)
+	mockCommits.EXPECT().ListChanges(gomock.Any(), dummyUser, dummyRepo.Slug, dummyHook.After, dummyHook.Ref).Return(dummyChanges, nil)
+
+	mockMappings := mock.NewMockPathMappingStore(controller)
+	mockMappings.EXPECT().List(gomock.Any(), dummyRepo.ID).Return(nil, nil)
+
+	triggerer := New(
+		mockConfigService,
+		mockCommits,
+		nil,
+		mockStatus,
+		mockBuilds,
+		mockQueue,
+		mockRepos,
+		mockPolicies,
+		nil,
+		mockPlatforms,
+		mockMappings,
+		mockUsers,
+		mockWebhooks,
+	)
+
+	_, err := triggerer.Trigger(noContext, protected, dummyHook)
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+// this test verifies that every stage of an unsigned yaml
+// is blocked, including stages with dependencies, so that
+// approving the first stage does not release the build.
+func TestTrigger_UnsignedDependencies(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	protected := new(core.Repository)
+	*protected = *dummyRepo
+	protected.Protected = true
+
+	checkBuild := func(_ context.Context, build *core.Build, stages []*core.Stage) {
+		if got, want := len(stages), 3; got != want {
+			t.Errorf("Want %d stages, got %d", want, got)
+			return
+		}
+		for _, stage := range stages {
+			if got, want := stage.Status, core.StatusBlocked; got != want {
+				t.Errorf("Want stage %s status %q, got %q", stage.Name, want, got)
+			}
+		}
+	}
+
+	mockUsers := mock.NewMockUserStore(controller)
+	mockUsers.EXPECT().Find(gomock.Any(), dummyRepo.UserID).Return(dummyUser, nil)
+
+	mockRepos := mock.NewMockRepositoryStore(controller)
+	mockRepos.EXPECT().Increment(gomock.Any(), protected).Return(protected, nil)
+
+	mockConfigService := mock.NewMockConfigService(controller)
+	mockConfigService.EXPECT().Find(gomock.Any(), gomock.Any()).Return(dummyYamlMapping, nil)
+
+	mockStatus := mock.NewMockStatusService(controller)
+	mockStatus.EXPECT().Send(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+
+	mockBuilds := mock.NewMockBuildStore(controller)
+	mockBuilds.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any()).Do(checkBuild).Return(nil)
+
+	mockWebhooks := mock.NewMockWebhookSender(controller)
+	mockWebhooks.EXPECT().Send(gomock.Any(), gomock.Any()).Return(nil)
+
+	// the scheduler has no expectations, which verifies
+	// the blocked stages are not scheduled.
+	mockQueue := mock.NewMockScheduler(controller)
+
+	mockPolicies := mock.NewMockPolicyStore(controller)
+	mockPolicies.EXPECT().Find(gomock.Any(), dummyRepo.Namespace).Return(nil, sql.ErrNoRows)
+
+	mockPlatforms := mock.NewMockPlatformService(controller)
+	mockPlatforms.EXPECT().Validate(gomock.Any(), gomock.Any()).Return(nil).Times(3)
+
+	mockCommits := mock.NewMockCommitService(controller)
+	mockCommits.EXPECT().ListChanges(gomock.Any(), dummyUser, dummyRepo.Slug, dummyHook.After, dummyHook.Ref).Return(dummyChanges, nil)
+
+	mockMappings := mock.NewMockPathMappingStore(controller)
+	mockMappings.EXPECT().List(gomock.Any(), dummyRepo.ID).Return(nil, nil)
+
+	triggerer := New(
+		mockConfigService,
+		mockCommits,
+		nil,
+		mockStatus,
+		mockBuilds,
+		mockQueue,
+		mockRepos,
+		mockPolicies,
+		nil,
+		mockPlatforms,
+		mockMappings,
+		mockUsers,
+		mockWebhooks,
+	)
+
+	_, err := triggerer.Trigger(noContext, protected, dummyHook)
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+// this test verifies that hook is not executed if the commit
+// message includes the [CI SKIP] keyword, and that a skipped
+// build is created.
+func TestTrigger_SkipCI(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	checkBuild := func(_ context.Context, build *core.Build, stages []*core.Stage) {
+		if got, want := build.Status, core.StatusSkipped; got != want {
+			t.Errorf("Want build status %s, got %s", want, got)
+		}
+		if len(stages) != 0 {
+			t.Errorf("Expect skipped build created without stages")
+		}
+	}
+
+	mockUsers := mock.NewMockUserStore(controller)
+	mockUsers.EXPECT().Find(gomock.Any(), dummyRepo.UserID).Return(dummyUser, nil)
+
+	mockRepos := mock.NewMockRepositoryStore(controller)
+	mockRepos.EXPECT().Increment(gomock.Any(), dummyRepo).Return(dummyRepo, nil)
+
+	mockBuilds := mock.NewMockBuildStore(controller)
+	mockBuilds.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any()).Do(checkBuild).Return(nil)
+
+	triggerer := New(
+		nil,
+		nil,
+		nil,
+		nil,
+		mockBuilds,
+		nil,
+		mockRepos,
+		nil,
+		nil,
+		nil,
+		nil,
+		mockUsers,
+		nil,
+	)
+	dummyHookSkip := *dummyHook
+	dummyHookSkip.Message = "foo [CI SKIP] bar"
+	build, err := triggerer.Trigger(noContext, dummyRepo, &dummyHookSkip)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if build == nil {
+		t.Errorf("Expect skipped build returned")
+	}
+}
+
+// this test verifies that a skipped build is not created if
+// the commit message includes the [CI SKIP] keyword, and the
+// project ignores pull requests.
+func TestTrigger_SkipCIIgnorePulls(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	dummyRepoIgnore := *dummyRepo
+	dummyRepoIgnore.IgnorePulls = true
+
+	// the build and repository stores have no expectations,
+	// which verifies a skipped build is not created.
+	mockRepos := mock.NewMockRepositoryStore(controller)
+	mockBuilds := mock.NewMockBuildStore(controller)
+
+	triggerer := New(
+		nil,
+		nil,
+		nil,
+		nil,
+		mockBuilds,
+		nil,
+		mockRepos,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+	)
+	dummyHookSkip := *dummyHook
+	dummyHookSkip.Event = core.EventPullRequest
+	dummyHookSkip.Message = "foo [CI SKIP] bar"
+	build, err := triggerer.Trigger(noContext, &dummyRepoIgnore, &dummyHookSkip)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if build != nil {
+		t.Errorf("Expect no build created")
+	}
+}
+
+// this test verifies that hook is not executed if the commit
+// message matches the repository skip pattern.
+func TestTrigger_SkipPattern(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	dummyRepoSkip := *dummyRepo
+	dummyRepoSkip.SkipPattern = `\[(wip|draft)\]`
+
+	mockUsers := mock.NewMockUserStore(controller)
+	mockUsers.EXPECT().Find(gomock.Any(), dummyRepo.UserID).Return(dummyUser, nil)
+
+	mockRepos := mock.NewMockRepositoryStore(controller)
+	mockRepos.EXPECT().Increment(gomock.Any(), &dummyRepoSkip).Return(&dummyRepoSkip, nil)
+
+	mockBuilds := mock.NewMockBuildStore(controller)
+	mockBuilds.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+
+	triggerer := New(
+		nil,
+		nil,
+		nil,
+		nil,
+		mockBuilds,
+		nil,
+		mockRepos,
+		nil,
+		nil,
+		nil,
+		nil,
+		mockUsers,
+		nil,
+	)
+	dummyHookSkip := *dummyHook
+	dummyHookSkip.Message = "foo [WIP] bar"
+	build, err := triggerer.Trigger(noContext, &dummyRepoSkip, &dummyHookSkip)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if got, want := build.Status, core.StatusSkipped; got != want {
+		t.Errorf("Want build status %s, got %s", want, got)
+	}
+}
+
+// this test verifies that the existing build is returned,
+// and no build is created, if the commit already passed and
+// the repository ignores duplicate builds.
+func TestTrigger_SkipDuplicate(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	dummyRepoDupes := *dummyRepo
+	dummyRepoDupes.IgnoreDupes = true
+
+	mockBuilds := mock.NewMockBuildStore(controller)
+	mockBuilds.EXPECT().FindCommit(gomock.Any(), dummyRepo.ID, dummyHook.After, dummyHook.Event).Return(dummyBuild, nil)
+
+	triggerer := New(
+		nil,
+		nil,
+		nil,
+		nil,
+		mockBuilds,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+	)
+	build, err := triggerer.Trigger(noContext, &dummyRepoDupes, dummyHook)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if build != dummyBuild {
+		t.Errorf("Expect existing build returned")
+	}
+}
+
+// this test verifies that if the system cannot determine
+// the repository owner, the function must exit with an error.
+// The owner is required because we need an oauth token
+// when fetching the configuration file.
+func TestTrigger_NoOwner(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockUsers := mock.NewMockUserStore(controller)
+	mockUsers.EXPECT().Find(noContext, dummyRepo.UserID).Return(nil, sql.ErrNoRows)
+
+	triggerer := New(
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		mockUsers,
+		nil,
+	)
+
+	_, err := triggerer.Trigger(noContext, dummyRepo, dummyHook)
+	if err != sql.ErrNoRows {
+		t.Errorf("Expect error when yaml not found")
+	}
+}
+
+// this test verifies that if the system cannot fetch the yaml
+// configuration file, the function must exit with an error.
+func TestTrigger_MissingYaml(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockUsers := mock.NewMockUserStore(controller)
+	mockUsers.EXPECT().Find(noContext, dummyRepo.UserID).Return(dummyUser, nil)
+
+	mockConfigService := mock.NewMockConfigService(controller)
+	mockConfigService.EXPECT().Find(gomock.Any(), gomock.Any()).Return(nil, io.EOF)
+
+	triggerer := New(
+		mockConfigService,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		mockUsers,
+		nil,
+	)
+
+	_, err := triggerer.Trigger(noContext, dummyRepo, dummyHook)
+	if err == nil {
+		t.Errorf("Expect error when yaml not found")
+	}
+}
+
+// this test verifies that if the system cannot parse the yaml
+// configuration file, the function must exit with an error.
+func TestTrigger_ErrorYaml(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockUsers := mock.NewMockUserStore(controller)
+	mockUsers.EXPECT().Find(noContext, dummyRepo.UserID).Return(dummyUser, nil)
+
+	mockConfigService := mock.NewMockConfigService(controller)
+	mockConfigService.EXPECT().Find(gomock.Any(), gomock.Any()).Return(dummyYamlInvalid, nil)
+
+	mockRepos := mock.NewMockRepositoryStore(controller)
+	mockRepos.EXPECT().Increment(gomock.Any(), dummyRepo).Return(dummyRepo, nil)
+
+	mockBuilds := mock.NewMockBuildStore(controller)
+	mockBuilds.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil) // .Do(checkBuild).Return(nil)
+
+	triggerer := New(
+		mockConfigService,
+		nil,
+		nil,
+		nil,
+		mockBuilds,
+		nil,
+		mockRepos,
+		nil,
+		nil,
+		nil,
+		nil,
+		mockUsers,
+		nil,
+	)
+
+	build, err := triggerer.Trigger(noContext, dummyRepo, dummyHook)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if got, want := build.Status, core.StatusError; got != want {
+		t.Errorf("Want status %s, got %s", want, got)
+	}
+	if got, want := build.Error, "yaml: found unknown directive name"; got != want {
+		t.Errorf("Want error %s, got %s", want, got)
+	}
+	if build.Finished == 0 {
+		t.Errorf("Want non-zero finished time")
+	}
+}
+
+// this test verifies that no build should be scheduled if the
+// hook branch does not match the branches defined in the yaml.
+func TestTrigger_SkipBranch(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockUsers := mock.NewMockUserStore(controller)
+	mockUsers.EXPECT().Find(noContext, dummyRepo.UserID).Return(dummyUser, nil)
+
+	mockConfigService := mock.NewMockConfigService(controller)
+	mockConfigService.EXPECT().Find(gomock.Any(), gomock.Any()).Return(dummyYamlSkipBranch, nil)
+
+	triggerer := New(
+		mockConfigService,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		mockUsers,
+		nil,
+	)
+
+	_, err := triggerer.Trigger(noContext, dummyRepo, dummyHook)
+	if err != nil {
+		t.Errorf("Expect build silenty skipped if branch does not match")
+	}
+}
+
+// this test verifies that no build should be scheduled if the
+// hook event does not match the events defined in the yaml.
+func TestTrigger_SkipEvent(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockUsers := mock.NewMockUserStore(controller)
+	mockUsers.EXPECT().Find(noContext, dummyRepo.UserID).Return(dummyUser, nil)
+
+	mockConfigService := mock.NewMockConfigService(controller)
+	mockConfigService.EXPECT().Find(gomock.Any(), gomock.Any()).Return(dummyYamlSkipEvent, nil)
+
+	triggerer := New(
+		mockConfigService,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		mockUsers,
+		nil,
+	)
+
+	_, err := triggerer.Trigger(noContext, dummyRepo, dummyHook)
+	if err != nil {
+		t.Errorf("Expect build silenty skipped if event does not match")
+	}
+}
+
+// this test verifies that a build is created with an error
+// status, and is not scheduled, if the pipeline violates the
+// organization policy.
+func TestTrigger_PolicyViolation(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	checkBuild := func(_ context.Context, build *core.Build, stages []*core.Stage) {
+		if got, want := build.Status, core.StatusError; got != want {
+			t.Errorf("Want build status %s, got %s", want, got)
+		}
+		if !strings.HasPrefix(build.Error, "policy:") {
+			t.Errorf("Want policy violation stored with the build, got %q", build.Error)
+		}
+	}
+
+	mockUsers := mock.NewMockUserStore(controller)
+	mockUsers.EXPECT().Find(noContext, dummyRepo.UserID).Return(dummyUser, nil)
+
+	mockConfigService := mock.NewMockConfigService(controller)
+	mockConfigService.EXPECT().Find(gomock.Any(), gomock.Any()).Return(dummyYaml, nil)
+
+	mockPolicies := mock.NewMockPolicyStore(controller)
+	mockPolicies.EXPECT().Find(gomock.Any(), dummyRepo.Namespace).Return(&core.Policy{CloneDepth: 50}, nil)
+
+	mockRepos := mock.NewMockRepositoryStore(controller)
+	mockRepos.EXPECT().Increment(gomock.Any(), dummyRepo).Return(dummyRepo, nil)
+
+	mockBuilds := mock.NewMockBuildStore(controller)
+	mockBuilds.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any()).Do(checkBuild).Return(nil)
+
+	triggerer := New(
+		mockConfigService,
+		nil,
+		nil,
+		nil,
+		mockBuilds,
+		nil,
+		mockRepos,
+		mockPolicies,
+		nil,
+		nil,
+		nil,
+		mockUsers,
+		nil,
+	)
+
+	_, err := triggerer.Trigger(noContext, dummyRepo, dummyHook)
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+// this test verifies that the runner pool labels requested
+// in the yaml are merged with the pipeline node labels.
+func TestTrigger_Pool(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	checkBuild := func(_ context.Context, build *core.Build, stages []*core.Stage) {
+		want := map[string]string{"gpu": "nvidia", "zone": "us-west"}
+		if diff := cmp.Diff(stages[0].Labels, want); diff != "" {
+			t.Errorf(diff)
+		}
+	}
+
+	mockUsers := mock.NewMockUserStore(controller)
+	mockUsers.EXPECT().Find(gomock.Any(), dummyRepo.UserID).Return(dummyUser, nil)
+
+	mockRepos := mock.NewMockRepositoryStore(controller)
+	mockRepos.EXPECT().Increment(gomock.Any(), dummyRepo).Return(dummyRepo, nil)
+
+	mockConfigService := mock.NewMockConfigService(controller)
+	mockConfigService.EXPECT().Find(gomock.Any(), gomock.Any()).Return(dummyYamlPool, nil)
+
+	mockStatus := mock.NewMockStatusService(controller)
+	mockStatus.EXPECT().Send(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+
+	mockQueue := mock.NewMockScheduler(controller)
+	mockQueue.EXPECT().Schedule(gomock.Any(), gomock.Any()).Return(nil)
+
+	mockBuilds := mock.NewMockBuildStore(controller)
+	mockBuilds.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any()).Do(checkBuild).Return(nil)
+
+	mockWebhooks := mock.NewMockWebhookSender(controller)
+	mockWebhooks.EXPECT().Send(gomock.Any(), gomock.Any()).Return(nil)
+
+	mockPolicies := mock.NewMockPolicyStore(controller)
+	mockPolicies.EXPECT().Find(gomock.Any(), dummyRepo.Namespace).Return(nil, sql.ErrNoRows)
+
+	mockPools := mock.NewMockPoolService(controller)
+	mockPools.EXPECT().Find(gomock.Any(), "gpu").Return(&core.Pool{
+		Name:   "gpu",
+		Labels: map[string]string{"gpu": "nvidia", "zone": "us-east"},
+	}, nil)
+
+	mockPlatforms := mock.NewMockPlatformService(controller)
+	mockPlatforms.EXPECT().Validate(gomock.Any(), gomock.Any()).Return(nil)
+
+	mockCommits := mock.NewMockCommitService(controller)
+	mockCommits.EXPECT().ListChanges(gomock.Any(), dummyUser, dummyRepo.Slug, dummyHook.After, dummyHook.Ref).Return(dummyChanges, nil)
+
+	mockMappings := mock.NewMockPathMappingStore(controller)
+	mockMappings.EXPECT().List(gomock.Any(), dummyRepo.ID).Return(nil, nil)
+
 	triggerer := New(
 		mockConfigService,
+		mockCommits,
 		nil,
 		mockStatus,
 		mockBuilds,
 		mockQueue,
 		mockRepos,
+		mockPolicies,
+		mockPools,
+		mockPlatforms,
+		mockMappings,
 		mockUsers,
 		mockWebhooks,
 	)
 
-	build, err := triggerer.Trigger(noContext, dummyRepo, dummyHook)
+	_, err := triggerer.Trigger(noContext, dummyRepo, dummyHook)
 	if err != nil {
 		t.Error(err)
-		return
-	}
-	if diff := cmp.Diff(build, dummyBuild, ignoreBuildFileds); diff != "" {
-		t.Errorf(diff)
 	}
 }
 
-// this test verifies that hook is ignored if the commit
-// message includes the [CI SKIP] keyword.
-func TestTrigger_SkipCI(t *testing.T) {
-	triggerer := New(
-		nil,
-		nil,
-		nil,
-		nil,
-		nil,
-		nil,
-		nil,
-		nil,
-	)
-	dummyHookSkip := *dummyHook
-	dummyHookSkip.Message = "foo [CI SKIP] bar"
-	triggerer.Trigger(noContext, dummyRepo, &dummyHookSkip)
-}
-
-// this test verifies that if the system cannot determine
-// the repository owner, the function must exit with an error.
-// The owner is required because we need an oauth token
-// when fetching the configuration file.
-func TestTrigger_NoOwner(t *testing.T) {
+// this test verifies that a build that requests an unknown
+// runner pool is not executed, and the error is stored with
+// the build.
+// this test verifies that pipelines mapped to paths that
+// are not changed by the build are created in the skipped
+// state, and that stages depending on skipped stages are
+// scheduled.
+func TestTrigger_PathMapping(t *testing.T) {
 	controller := gomock.NewController(t)
 	defer controller.Finish()
 
+	checkBuild := func(_ context.Context, build *core.Build, stages []*core.Stage) {
+		if got, want := build.Status, core.StatusPending; got != want {
+			t.Errorf("Want build status %q, got %q", want, got)
+		}
+		if got, want := len(stages), 3; got != want {
+			t.Errorf("Want %d stages, got %d", want, got)
+			return
+		}
+		if got, want := stages[0].Status, core.StatusPending; got != want {
+			t.Errorf("Want stage %s status %q, got %q", stages[0].Name, want, got)
+		}
+		if got, want := stages[1].Status, core.StatusSkipped; got != want {
+			t.Errorf("Want stage %s status %q, got %q", stages[1].Name, want, got)
+		}
+		if got, want := stages[2].Status, core.StatusPending; got != want {
+			t.Errorf("Want stage %s status %q, got %q", stages[2].Name, want, got)
+		}
+	}
+
 	mockUsers := mock.NewMockUserStore(controller)
-	mockUsers.EXPECT().Find(noContext, dummyRepo.UserID).Return(nil, sql.ErrNoRows)
+	mockUsers.EXPECT().Find(gomock.Any(), dummyRepo.UserID).Return(dummyUser, nil)
+
+	mockRepos := mock.NewMockRepositoryStore(controller)
+	mockRepos.EXPECT().Increment(gomock.Any(), dummyRepo).Return(dummyRepo, nil)
+
+	mockConfigService := mock.NewMockConfigService(controller)
+	mockConfigService.EXPECT().Find(gomock.Any(), gomock.Any()).Return(dummyYamlMapping, nil)
+
+	mockCommits := mock.NewMockCommitService(controller)
+	mockCommits.EXPECT().ListChanges(gomock.Any(), dummyUser, dummyRepo.Slug, dummyHook.After, dummyHook.Ref).Return(dummyChanges, nil)
+
+	mockStatus := mock.NewMockStatusService(controller)
+	mockStatus.EXPECT().Send(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+
+	mockQueue := mock.NewMockScheduler(controller)
+	mockQueue.EXPECT().Schedule(gomock.Any(), gomock.Any()).Return(nil).Times(2)
+
+	mockBuilds := mock.NewMockBuildStore(controller)
+	mockBuilds.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any()).Do(checkBuild).Return(nil)
+
+	mockWebhooks := mock.NewMockWebhookSender(controller)
+	mockWebhooks.EXPECT().Send(gomock.Any(), gomock.Any()).Return(nil)
+
+	mockPolicies := mock.NewMockPolicyStore(controller)
+	mockPolicies.EXPECT().Find(gomock.Any(), dummyRepo.Namespace).Return(nil, sql.ErrNoRows)
+
+	mockPlatforms := mock.NewMockPlatformService(controller)
+	mockPlatforms.EXPECT().Validate(gomock.Any(), gomock.Any()).Return(nil).Times(3)
+
+	mockMappings := mock.NewMockPathMappingStore(controller)
+	mockMappings.EXPECT().List(gomock.Any(), dummyRepo.ID).Return(dummyMappings, nil)
 
 	triggerer := New(
+		mockConfigService,
+		mockCommits,
 		nil,
+		mockStatus,
+		mockBuilds,
+		mockQueue,
+		mockRepos,
+		mockPolicies,
 		nil,
-		nil,
-		nil,
-		nil,
-		nil,
+		mockPlatforms,
+		mockMappings,
 		mockUsers,
-		nil,
+		mockWebhooks,
 	)
 
 	_, err := triggerer.Trigger(noContext, dummyRepo, dummyHook)
-	if err != sql.ErrNoRows {
-		t.Errorf("Expect error when yaml not found")
+	if err != nil {
+		t.Error(err)
 	}
 }
 
-// this test verifies that if the system cannot fetch the yaml
-// configuration file, the function must exit with an error.
-func TestTrigger_MissingYaml(t *testing.T) {
+// this test verifies that the hook and pull request labels,
+// the pull request title and the number of changed files are
+// stored with the build.
+func TestTrigger_PullRequestMetadata(t *testing.T) {
 	controller := gomock.NewController(t)
 	defer controller.Finish()
 
+	hook := new(core.Hook)
+	*hook = *dummyHook
+	hook.Event = core.EventPullRequest
+	hook.Ref = "refs/pull/42/head"
+	hook.Labels = []string{"ticket=DRONE-42", "docs"}
+
+	checkBuild := func(_ context.Context, build *core.Build, stages []*core.Stage) {
+		if got, want := build.Title, "Update the README"; got != want {
+			t.Errorf("Want build title %q, got %q", want, got)
+		}
+		if diff := cmp.Diff(build.Labels, []string{"ticket=DRONE-42", "docs"}); diff != "" {
+			t.Errorf(diff)
+		}
+		if got, want := build.ChangedFiles, int64(1); got != want {
+			t.Errorf("Want %d changed files, got %d", want, got)
+		}
+	}
+
 	mockUsers := mock.NewMockUserStore(controller)
-	mockUsers.EXPECT().Find(noContext, dummyRepo.UserID).Return(dummyUser, nil)
+	mockUsers.EXPECT().Find(gomock.Any(), dummyRepo.UserID).Return(dummyUser, nil)
+
+	mockRepos := mock.NewMockRepositoryStore(controller)
+	mockRepos.EXPECT().Increment(gomock.Any(), dummyRepo).Return(dummyRepo, nil)
 
 	mockConfigService := mock.NewMockConfigService(controller)
-	mockConfigService.EXPECT().Find(gomock.Any(), gomock.Any()).Return(nil, io.EOF)
+	mockConfigService.EXPECT().Find(gomock.Any(), gomock.Any()).Return(dummyYaml, nil)
+
+	mockPulls := mock.NewMockPullRequestService(controller)
+	mockPulls.EXPECT().Find(gomock.Any(), dummyUser, dummyRepo.Slug, 42).Return(&core.PullRequest{Number: 42, Title: "Update the README", Labels: []string{"docs"}}, nil)
+	mockPulls.EXPECT().ListChanges(gomock.Any(), dummyUser, dummyRepo.Slug, 42).Return(dummyChanges, nil)
+
+	mockStatus := mock.NewMockStatusService(controller)
+	mockStatus.EXPECT().Send(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+
+	mockQueue := mock.NewMockScheduler(controller)
+	mockQueue.EXPECT().Schedule(gomock.Any(), gomock.Any()).Return(nil)
+
+	mockBuilds := mock.NewMockBuildStore(controller)
+	mockBuilds.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any()).Do(checkBuild).Return(nil)
+
+	mockWebhooks := mock.NewMockWebhookSender(controller)
+	mockWebhooks.EXPECT().Send(gomock.Any(), gomock.Any()).Return(nil)
+
+	mockPolicies := mock.NewMockPolicyStore(controller)
+	mockPolicies.EXPECT().Find(gomock.Any(), dummyRepo.Namespace).Return(nil, sql.ErrNoRows)
+
+	mockPlatforms := mock.NewMockPlatformService(controller)
+	mockPlatforms.EXPECT().Validate(gomock.Any(), gomock.Any()).Return(nil)
+
+	mockMappings := mock.NewMockPathMappingStore(controller)
+	mockMappings.EXPECT().List(gomock.Any(), dummyRepo.ID).Return(nil, nil)
 
 	triggerer := New(
 		mockConfigService,
 		nil,
+		mockPulls,
+		mockStatus,
+		mockBuilds,
+		mockQueue,
+		mockRepos,
+		mockPolicies,
 		nil,
-		nil,
-		nil,
-		nil,
+		mockPlatforms,
+		mockMappings,
 		mockUsers,
-		nil,
+		mockWebhooks,
 	)
 
-	_, err := triggerer.Trigger(noContext, dummyRepo, dummyHook)
-	if err == nil {
-		t.Errorf("Expect error when yaml not found")
+	_, err := triggerer.Trigger(noContext, dummyRepo, hook)
+	if err != nil {
+		t.Error(err)
 	}
 }
 
-// this test verifies that if the system cannot parse the yaml
-// configuration file, the function must exit with an error.
-func TestTrigger_ErrorYaml(t *testing.T) {
+// this test verifies that a build is skipped if the build
+// does not change a path mapped to any of the pipelines.
+func TestTrigger_PathMappingSkipped(t *testing.T) {
 	controller := gomock.NewController(t)
 	defer controller.Finish()
 
-	mockUsers := mock.NewMockUserStore(controller)
-	mockUsers.EXPECT().Find(noContext, dummyRepo.UserID).Return(dummyUser, nil)
+	checkBuild := func(_ context.Context, build *core.Build, stages []*core.Stage) {
+		if got, want := build.Status, core.StatusSkipped; got != want {
+			t.Errorf("Want build status %q, got %q", want, got)
+		}
+		if build.Finished == 0 {
+			t.Errorf("Want build finished")
+		}
+		for _, stage := range stages {
+			if got, want := stage.Status, core.StatusSkipped; got != want {
+				t.Errorf("Want stage %s status %q, got %q", stage.Name, want, got)
+			}
+		}
+	}
 
-	mockConfigService := mock.NewMockConfigService(controller)
-	mockConfigService.EXPECT().Find(gomock.Any(), gomock.Any()).Return(dummyYamlInvalid, nil)
+	mappings := []*core.PathMapping{
+		{Pattern: "service-b/**", Pipeline: "default"},
+	}
+
+	mockUsers := mock.NewMockUserStore(controller)
+	mockUsers.EXPECT().Find(gomock.Any(), dummyRepo.UserID).Return(dummyUser, nil)
 
 	mockRepos := mock.NewMockRepositoryStore(controller)
 	mockRepos.EXPECT().Increment(gomock.Any(), dummyRepo).Return(dummyRepo, nil)
 
+	mockConfigService := mock.NewMockConfigService(controller)
+	mockConfigService.EXPECT().Find(gomock.Any(), gomock.Any()).Return(dummyYaml, nil)
+
+	mockCommits := mock.NewMockCommitService(controller)
+	mockCommits.EXPECT().ListChanges(gomock.Any(), dummyUser, dummyRepo.Slug, dummyHook.After, dummyHook.Ref).Return(dummyChanges, nil)
+
+	mockStatus := mock.NewMockStatusService(controller)
+	mockStatus.EXPECT().Send(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+
 	mockBuilds := mock.NewMockBuildStore(controller)
-	mockBuilds.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil) // .Do(checkBuild).Return(nil)
+	mockBuilds.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any()).Do(checkBuild).Return(nil)
+
+	mockWebhooks := mock.NewMockWebhookSender(controller)
+	mockWebhooks.EXPECT().Send(gomock.Any(), gomock.Any()).Return(nil)
+
+	mockPolicies := mock.NewMockPolicyStore(controller)
+	mockPolicies.EXPECT().Find(gomock.Any(), dummyRepo.Namespace).Return(nil, sql.ErrNoRows)
+
+	mockPlatforms := mock.NewMockPlatformService(controller)
+	mockPlatforms.EXPECT().Validate(gomock.Any(), gomock.Any()).Return(nil)
+
+	mockMappings := mock.NewMockPathMappingStore(controller)
+	mockMappings.EXPECT().List(gomock.Any(), dummyRepo.ID).Return(mappings, nil)
+
+	// the scheduler has no expectations, which verifies
+	// skipped stages are not scheduled.
+	mockQueue := mock.NewMockScheduler(controller)
 
 	triggerer := New(
 		mockConfigService,
+		mockCommits,
 		nil,
-		nil,
+		mockStatus,
 		mockBuilds,
-		nil,
+		mockQueue,
 		mockRepos,
-		mockUsers,
+		mockPolicies,
 		nil,
+		mockPlatforms,
+		mockMappings,
+		mockUsers,
+		mockWebhooks,
 	)
 
-	build, err := triggerer.Trigger(noContext, dummyRepo, dummyHook)
+	_, err := triggerer.Trigger(noContext, dummyRepo, dummyHook)
 	if err != nil {
 		t.Error(err)
 	}
-
-	if got, want := build.Status, core.StatusError; got != want {
-		t.Errorf("Want status %s, got %s", want, got)
-	}
-	if got, want := build.Error, "yaml: found unknown directive name"; got != want {
-		t.Errorf("Want error %s, got %s", want, got)
-	}
-	if build.Finished == 0 {
-		t.Errorf("Want non-zero finished time")
-	}
 }
 
-// this test verifies that no build should be scheduled if the
-// hook branch does not match the branches defined in the yaml.
-func TestTrigger_SkipBranch(t *testing.T) {
+func TestTrigger_PoolNotFound(t *testing.T) {
 	controller := gomock.NewController(t)
 	defer controller.Finish()
 
+	checkBuild := func(_ context.Context, build *core.Build, stages []*core.Stage) {
+		if got, want := build.Status, core.StatusError; got != want {
+			t.Errorf("Want build status %s, got %s", want, got)
+		}
+		if !strings.HasPrefix(build.Error, "pool:") {
+			t.Errorf("Want pool error stored with the build, got %q", build.Error)
+		}
+	}
+
 	mockUsers := mock.NewMockUserStore(controller)
 	mockUsers.EXPECT().Find(noContext, dummyRepo.UserID).Return(dummyUser, nil)
 
 	mockConfigService := mock.NewMockConfigService(controller)
-	mockConfigService.EXPECT().Find(gomock.Any(), gomock.Any()).Return(dummyYamlSkipBranch, nil)
+	mockConfigService.EXPECT().Find(gomock.Any(), gomock.Any()).Return(dummyYamlPool, nil)
+
+	mockPolicies := mock.NewMockPolicyStore(controller)
+	mockPolicies.EXPECT().Find(gomock.Any(), dummyRepo.Namespace).Return(nil, sql.ErrNoRows)
+
+	mockPools := mock.NewMockPoolService(controller)
+	mockPools.EXPECT().Find(gomock.Any(), "gpu").Return(nil, core.ErrPoolNotFound)
+
+	mockRepos := mock.NewMockRepositoryStore(controller)
+	mockRepos.EXPECT().Increment(gomock.Any(), dummyRepo).Return(dummyRepo, nil)
+
+	mockBuilds := mock.NewMockBuildStore(controller)
+	mockBuilds.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any()).Do(checkBuild).Return(nil)
 
 	triggerer := New(
 		mockConfigService,
 		nil,
 		nil,
 		nil,
+		mockBuilds,
+		nil,
+		mockRepos,
+		mockPolicies,
+		mockPools,
 		nil,
 		nil,
 		mockUsers,
@@ -236,28 +1121,55 @@ func TestTrigger_SkipBranch(t *testing.T) {
 
 	_, err := triggerer.Trigger(noContext, dummyRepo, dummyHook)
 	if err != nil {
-		t.Errorf("Expect build silenty skipped if branch does not match")
+		t.Error(err)
 	}
 }
 
-// this test verifies that no build should be scheduled if the
-// hook event does not match the events defined in the yaml.
-func TestTrigger_SkipEvent(t *testing.T) {
+// this test verifies that a build that targets a platform
+// that no agent can serve is not executed, and the error is
+// stored with the build.
+func TestTrigger_PlatformUnavailable(t *testing.T) {
 	controller := gomock.NewController(t)
 	defer controller.Finish()
 
+	checkBuild := func(_ context.Context, build *core.Build, stages []*core.Stage) {
+		if got, want := build.Status, core.StatusError; got != want {
+			t.Errorf("Want build status %s, got %s", want, got)
+		}
+		if got, want := build.Error, "platform: no agent available for pipeline default (linux/amd64)"; got != want {
+			t.Errorf("Want platform error %q stored with the build, got %q", want, got)
+		}
+	}
+
 	mockUsers := mock.NewMockUserStore(controller)
 	mockUsers.EXPECT().Find(noContext, dummyRepo.UserID).Return(dummyUser, nil)
 
 	mockConfigService := mock.NewMockConfigService(controller)
-	mockConfigService.EXPECT().Find(gomock.Any(), gomock.Any()).Return(dummyYamlSkipEvent, nil)
+	mockConfigService.EXPECT().Find(gomock.Any(), gomock.Any()).Return(dummyYaml, nil)
+
+	mockPolicies := mock.NewMockPolicyStore(controller)
+	mockPolicies.EXPECT().Find(gomock.Any(), dummyRepo.Namespace).Return(nil, sql.ErrNoRows)
+
+	mockPlatforms := mock.NewMockPlatformService(controller)
+	mockPlatforms.EXPECT().Validate(gomock.Any(), gomock.Any()).Return(core.ErrPlatformUnavailable)
+
+	mockRepos := mock.NewMockRepositoryStore(controller)
+	mockRepos.EXPECT().Increment(gomock.Any(), dummyRepo).Return(dummyRepo, nil)
+
+	mockBuilds := mock.NewMockBuildStore(controller)
+	mockBuilds.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any()).Do(checkBuild).Return(nil)
 
 	triggerer := New(
 		mockConfigService,
 		nil,
 		nil,
 		nil,
+		mockBuilds,
+		nil,
+		mockRepos,
+		mockPolicies,
 		nil,
+		mockPlatforms,
 		nil,
 		mockUsers,
 		nil,
@@ -265,7 +1177,7 @@ func TestTrigger_SkipEvent(t *testing.T) {
 
 	_, err := triggerer.Trigger(noContext, dummyRepo, dummyHook)
 	if err != nil {
-		t.Errorf("Expect build silenty skipped if event does not match")
+		t.Error(err)
 	}
 }
 
@@ -285,13 +1197,30 @@ func TestTrigger_ErrorIncrement(t *testing.T) {
 	mockConfigService := mock.NewMockConfigService(controller)
 	mockConfigService.EXPECT().Find(gomock.Any(), gomock.Any()).Return(dummyYaml, nil)
 
+	mockPolicies := mock.NewMockPolicyStore(controller)
+	mockPolicies.EXPECT().Find(gomock.Any(), dummyRepo.Namespace).Return(nil, sql.ErrNoRows)
+
+	mockPlatforms := mock.NewMockPlatformService(controller)
+	mockPlatforms.EXPECT().Validate(gomock.Any(), gomock.Any()).Return(nil)
+
+	mockCommits := mock.NewMockCommitService(controller)
+	mockCommits.EXPECT().ListChanges(gomock.Any(), dummyUser, dummyRepo.Slug, dummyHook.After, dummyHook.Ref).Return(dummyChanges, nil)
+
+	mockMappings := mock.NewMockPathMappingStore(controller)
+	mockMappings.EXPECT().List(gomock.Any(), dummyRepo.ID).Return(nil, nil)
+
 	triggerer := New(
 		mockConfigService,
+		mockCommits,
 		nil,
 		nil,
 		nil,
 		nil,
 		mockRepos,
+		mockPolicies,
+		nil,
+		mockPlatforms,
+		mockMappings,
 		mockUsers,
 		nil,
 	)
@@ -425,6 +1354,8 @@ var (
 		AuthorEmail:  "octocat@hello-world.com",
 		AuthorAvatar: "https://avatars3.githubusercontent.com/u/583231",
 		Sender:       "octocat",
+		TriggeredBy:  "octocat",
+		ChangedFiles: 1,
 	}
 
 	dummyRepo = &core.Repository{
@@ -477,6 +1408,27 @@ var (
 		Data: "%ERROR",
 	}
 
+	dummyYamlExtensions = &core.Config{
+		Data: "kind: pipeline\ntimeout: 90m30s\nfailure: ignore\nsteps: [ ]",
+	}
+
+	dummyYamlPool = &core.Config{
+		Data: "kind: pipeline\npool: gpu\nnode: { zone: us-west }\nsteps: [ ]",
+	}
+
+	dummyYamlMapping = &core.Config{
+		Data: "kind: pipeline\nname: service-a\nsteps: [ ]\n---\nkind: pipeline\nname: service-b\nsteps: [ ]\n---\nkind: pipeline\nname: notify\ndepends_on: [ service-b ]\nsteps: [ ]",
+	}
+
+	dummyMappings = []*core.PathMapping{
+		{Pattern: "service-a/**", Pipeline: "service-a"},
+		{Pattern: "service-b/**", Pipeline: "service-b"},
+	}
+
+	dummyChanges = []*core.Change{
+		{Path: "service-a/main.go"},
+	}
+
 	dummyYamlSkipBranch = &core.Config{
 		Data: "kind: pipeline\ntrigger: { branch: { exclude: master } }",
 	}
@@ -489,5 +1441,5 @@ var (
 		"Created", "Updated")
 
 	ignoreStageFileds = cmpopts.IgnoreFields(core.Stage{},
-		"Created", "Updated")
+		"Created", "Updated", "Queued")
 )
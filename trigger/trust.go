@@ -0,0 +1,27 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package trigger
+
+import "github.com/drone/drone-yaml/yaml"
+
+// helper function returns true if the pipeline requests
+// privileged containers or host volumes, which are
+// restricted to trusted repositories.
+func requiresTrust(pipeline *yaml.Pipeline) bool {
+	for _, volume := range pipeline.Volumes {
+		if volume.HostPath != nil {
+			return true
+		}
+	}
+	var containers []*yaml.Container
+	containers = append(containers, pipeline.Services...)
+	containers = append(containers, pipeline.Steps...)
+	for _, container := range containers {
+		if container.Privileged {
+			return true
+		}
+	}
+	return false
+}
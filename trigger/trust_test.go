@@ -0,0 +1,54 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package trigger
+
+import (
+	"testing"
+
+	"github.com/drone/drone-yaml/yaml"
+)
+
+func TestRequiresTrust(t *testing.T) {
+	tests := []struct {
+		pipeline *yaml.Pipeline
+		trust    bool
+	}{
+		{
+			pipeline: &yaml.Pipeline{
+				Steps: []*yaml.Container{{Image: "golang"}},
+			},
+			trust: false,
+		},
+		{
+			pipeline: &yaml.Pipeline{
+				Steps: []*yaml.Container{{Image: "docker:dind", Privileged: true}},
+			},
+			trust: true,
+		},
+		{
+			pipeline: &yaml.Pipeline{
+				Services: []*yaml.Container{{Image: "redis", Privileged: true}},
+			},
+			trust: true,
+		},
+		{
+			pipeline: &yaml.Pipeline{
+				Volumes: []*yaml.Volume{{Name: "cache", EmptyDir: &yaml.VolumeEmptyDir{}}},
+			},
+			trust: false,
+		},
+		{
+			pipeline: &yaml.Pipeline{
+				Volumes: []*yaml.Volume{{Name: "docker", HostPath: &yaml.VolumeHostPath{Path: "/var/run/docker.sock"}}},
+			},
+			trust: true,
+		},
+	}
+	for i, test := range tests {
+		if got, want := requiresTrust(test.pipeline), test.trust; got != want {
+			t.Errorf("Want requires trust %v, got %v at index %d", want, got, i)
+		}
+	}
+}
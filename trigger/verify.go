@@ -0,0 +1,39 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package trigger
+
+import (
+	"errors"
+
+	"github.com/drone/drone-yaml/yaml"
+	"github.com/drone/drone-yaml/yaml/signer"
+)
+
+var (
+	errSignatureMissing = errors.New("yaml: configuration is not signed")
+	errSignatureInvalid = errors.New("yaml: configuration signature is invalid")
+)
+
+// helper function verifies the configuration includes a
+// signature resource, and that the hmac signature matches
+// the configuration contents. An error is returned if the
+// signature is missing or cannot be verified.
+func verifySignature(manifest *yaml.Manifest, data, secret string) error {
+	signed := false
+	for _, resource := range manifest.Resources {
+		if _, ok := resource.(*yaml.Signature); ok {
+			signed = true
+			break
+		}
+	}
+	if !signed {
+		return errSignatureMissing
+	}
+	ok, err := signer.Verify([]byte(data), signer.KeyString(secret))
+	if err != nil || !ok {
+		return errSignatureInvalid
+	}
+	return nil
+}